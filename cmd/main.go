@@ -21,64 +21,747 @@ package main
 // @description Type "Bearer" followed by a space and JWT token.
 
 import (
+	"context"
+	"crypto/tls"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"flag"
 	"fmt"
+	"io/fs"
 	"log"
 	"net/http"
+	"os"
+	"os/signal"
+	"strconv"
+	"strings"
+	"syscall"
 
 	"github.com/limistah/wallet-service/docs"
+	docsv2 "github.com/limistah/wallet-service/docs/v2"
 	"github.com/limistah/wallet-service/internal/auth"
+	"github.com/limistah/wallet-service/internal/awsauth"
+	"github.com/limistah/wallet-service/internal/breaker"
+	"github.com/limistah/wallet-service/internal/compliance"
 	"github.com/limistah/wallet-service/internal/config"
 	"github.com/limistah/wallet-service/internal/database"
+	"github.com/limistah/wallet-service/internal/events"
+	"github.com/limistah/wallet-service/internal/fraud"
+	"github.com/limistah/wallet-service/internal/fx"
+	"github.com/limistah/wallet-service/internal/handlers"
+	"github.com/limistah/wallet-service/internal/jobs"
+	"github.com/limistah/wallet-service/internal/metrics"
+	"github.com/limistah/wallet-service/internal/middleware"
+	"github.com/limistah/wallet-service/internal/models"
+	"github.com/limistah/wallet-service/internal/notifications"
+	"github.com/limistah/wallet-service/internal/oauth"
+	"github.com/limistah/wallet-service/internal/openapi"
+	"github.com/limistah/wallet-service/internal/passwords"
+	"github.com/limistah/wallet-service/internal/payments"
+	"github.com/limistah/wallet-service/internal/queue"
 	"github.com/limistah/wallet-service/internal/repositories"
+	"github.com/limistah/wallet-service/internal/repositories/memory"
 	"github.com/limistah/wallet-service/internal/routes"
+	"github.com/limistah/wallet-service/internal/rpc"
+	"github.com/limistah/wallet-service/internal/secrets"
+	"github.com/limistah/wallet-service/internal/server"
+	"github.com/limistah/wallet-service/internal/storage"
 	"github.com/limistah/wallet-service/internal/usecases"
+	"github.com/limistah/wallet-service/internal/webhooks"
 
 	"github.com/gin-gonic/gin"
-	"github.com/joho/godotenv"
 	swaggerFiles "github.com/swaggo/files"
 	ginSwagger "github.com/swaggo/gin-swagger"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
 )
 
 func main() {
+	seedDefaults := database.DefaultSeedOptions()
+	seed := flag.Bool("seed", false, "Populate the database with development data and exit")
+	seedUsers := flag.Int("seed-users", seedDefaults.Users, "Number of users to create with --seed")
+	seedTransactions := flag.Int("seed-transactions", seedDefaults.TransactionsPerWallet, "Transactions per wallet to create with --seed")
+	seedMismatches := flag.Int("seed-mismatches", seedDefaults.Mismatches, "Wallets to leave with a reconciliation mismatch with --seed")
+	flag.Parse()
+
 	// Load environment variables
-	if err := godotenv.Load(); err != nil {
+	if err := config.LoadEnvFile(envFile); err != nil {
 		log.Println("No .env file found, using environment variables")
 	}
 	cfg := config.LoadConfig()
-	if cfg.App.Environment == "production" {
+	secretProvider := newSecretProvider(cfg.Secrets)
+	if secretProvider != nil {
+		if _, err := secrets.Apply(secretProvider); err != nil {
+			log.Fatal("Failed to load secrets:", err)
+		}
+		cfg = config.LoadConfig()
+	}
+	if err := cfg.Validate(); err != nil {
+		if cfg.IsProduction() {
+			log.Fatalf("Invalid configuration:\n%v", err)
+		}
+		log.Printf("Configuration problems, refused in production:\n%v", err)
+	}
+	log.Printf("Effective configuration:\n%s", cfg.Summary())
+	if cfg.IsProduction() {
 		gin.SetMode(gin.ReleaseMode)
 	}
 
-	db, err := database.Initialize()
-	if err != nil {
-		log.Fatal("Failed to connect to database:", err)
+	var repos *repositories.Repositories
+	// sqlDB stays nil without a database, which the readiness check reports as always ready
+	var sqlDB *sql.DB
+	if cfg.Database.Driver == "memory" {
+		if *seed {
+			log.Fatal("Nothing to seed: the memory driver keeps no data across restarts")
+		}
+		var err error
+		repos, err = memory.Open(context.Background(), cfg.App)
+		if err != nil {
+			log.Fatal("Failed to set up in-memory repositories:", err)
+		}
+	} else {
+		db, err := database.Initialize()
+		if err != nil {
+			log.Fatal("Failed to connect to database:", err)
+		}
+
+		if *seed {
+			if cfg.App.Environment == "production" {
+				log.Fatal("Refusing to seed a production database")
+			}
+			seedDefaults.Users = *seedUsers
+			seedDefaults.TransactionsPerWallet = *seedTransactions
+			seedDefaults.Mismatches = *seedMismatches
+			if err := database.Seed(db, seedDefaults); err != nil {
+				log.Fatal("Failed to seed database:", err)
+			}
+			return
+		}
+
+		repos = repositories.NewRepositories(db)
+		repos.TransactionRetry = repositories.RetryPolicy{
+			MaxAttempts:    cfg.Database.RetryMaxAttempts,
+			InitialBackoff: cfg.Database.RetryInitialBackoff,
+			MaxBackoff:     cfg.Database.RetryMaxBackoff,
+		}
+		if sqlDB, err = db.DB(); err != nil {
+			log.Fatal("Failed to get database instance:", err)
+		}
+	}
+
+	eventBus := events.NewBus(cfg.Notification.QueueSize)
+	defer eventBus.Close()
+
+	// Email delivery can be switched on and off on reload, so the SMTP sender is built whenever a server is configured
+	var smtpSender notifications.EmailSender = notifications.LogSender{}
+	if cfg.Notification.SMTPHost != "" {
+		smtpSender = notifications.NewSMTPSender(
+			cfg.Notification.SMTPHost,
+			cfg.Notification.SMTPPort,
+			cfg.Notification.SMTPUsername,
+			cfg.Notification.SMTPPassword,
+			cfg.Notification.EmailFrom,
+		)
+	}
+	emailSwitch := notifications.NewSwitchableEmailSender(smtpSender, cfg.Notification.EmailEnabled)
+	var emailSender notifications.EmailSender = emailSwitch
+	var smsSender notifications.SMSSender = notifications.LogSMSSender{}
+	switch cfg.Notification.SMSProvider {
+	case "twilio":
+		smsSender = notifications.NewTwilioSender(
+			cfg.Notification.TwilioAccountSID,
+			cfg.Notification.TwilioAuthToken,
+			cfg.Notification.TwilioFrom,
+		)
+	case "termii":
+		smsSender = notifications.NewTermiiSender(
+			cfg.Notification.TermiiAPIKey,
+			cfg.Notification.TermiiSenderID,
+			cfg.Notification.TermiiBaseURL,
+		)
+	}
+
+	var pushSender notifications.PushSender = notifications.LogPushSender{}
+	if cfg.Notification.PushProvider == "fcm" {
+		fcmSender, err := notifications.NewFCMSender(cfg.Notification.FCMCredentialsFile)
+		if err != nil {
+			log.Fatal("Failed to configure FCM:", err)
+		}
+		pushSender = fcmSender
+	}
+
+	// A failing provider trips its circuit so calls fail fast; notification jobs and payouts are retried from the job queue
+	breakers := breaker.NewRegistry(breaker.Config{
+		FailureThreshold: cfg.Breaker.FailureThreshold,
+		OpenTimeout:      cfg.Breaker.OpenTimeout,
+		HalfOpenProbes:   cfg.Breaker.HalfOpenProbes,
+	})
+	emailSender = notifications.NewBreakerEmailSender(emailSender, breakers.Get("email"))
+	smsSender = notifications.NewBreakerSMSSender(smsSender, breakers.Get("sms"))
+	pushSender = notifications.NewBreakerPushSender(pushSender, breakers.Get("push"))
+
+	var slackSender notifications.SlackSender
+	if cfg.Digest.SlackWebhookURL != "" {
+		slackSender = notifications.NewBreakerSlackSender(notifications.NewSlackWebhookSender(cfg.Digest.SlackWebhookURL), breakers.Get("slack"))
+	}
+
+	jobQueue := queue.New(repos.Job, queue.Config{
+		Workers:        cfg.JobQueue.Workers,
+		PollInterval:   cfg.JobQueue.PollInterval,
+		MaxAttempts:    cfg.JobQueue.MaxAttempts,
+		InitialBackoff: cfg.JobQueue.RetryInitial,
+		MaxBackoff:     cfg.JobQueue.RetryMax,
+	})
+
+	// Events are turned into delivery jobs so notifications survive restarts and failing senders are retried
+	notifier := notifications.NewNotifier(repos, emailSender, smsSender, slackSender)
+	pushDispatcher := notifications.NewPushDispatcher(repos, pushSender)
+	notifications.RegisterJobs(jobQueue, notifier, pushDispatcher)
+	eventBus.Subscribe(notifications.EnqueueDeliveries(jobQueue))
+
+	// Events also go to SNS or SQS for services on AWS; the bus dispatches them one at a time, so each wallet's
+	// events reach FIFO topics and queues in order. The bus is closed first so its last events are still published
+	if cfg.Events.SNSTopicARN != "" || cfg.Events.SQSQueueURL != "" {
+		awsPublisher, err := events.NewAWSPublisher(events.AWSConfig{
+			Region: cfg.Secrets.AWSRegion,
+			Credentials: awsauth.Credentials{
+				AccessKeyID:     cfg.Secrets.AWSAccessKeyID,
+				SecretAccessKey: cfg.Secrets.AWSSecretAccessKey,
+				SessionToken:    cfg.Secrets.AWSSessionToken,
+			},
+			TopicARN:    cfg.Events.SNSTopicARN,
+			QueueURL:    cfg.Events.SQSQueueURL,
+			QueueSize:   cfg.Events.QueueSize,
+			MaxAttempts: cfg.Events.MaxAttempts,
+		})
+		if err != nil {
+			log.Fatal("Failed to configure AWS event publishing:", err)
+		}
+		eventBus.Subscribe(awsPublisher.Publish)
+		defer func() {
+			eventBus.Close()
+			awsPublisher.Close()
+		}()
+	}
+
+	metricsRegistry := metrics.NewRegistry()
+	if sqlDB != nil {
+		database.RegisterPoolMetrics(metricsRegistry, sqlDB)
+	}
+	liveSettings := usecases.NewLiveSettings(runtimeSettings(cfg))
+
+	useCaseOptions := []usecases.Option{
+		usecases.WithReconciliationMetrics(usecases.NewReconciliationMetrics(metricsRegistry, repos)),
+		usecases.WithEventPublisher(eventBus),
+		usecases.WithJobQueue(jobQueue),
+		usecases.WithSMSSender(smsSender),
+		usecases.WithSandbox(cfg.App.SandboxEnabled),
+		usecases.WithStandingOrderRetry(usecases.RetryPolicy{
+			InitialBackoff: cfg.Scheduler.StandingOrderRetryInitial,
+			MaxBackoff:     cfg.Scheduler.StandingOrderRetryMax,
+			GracePeriod:    cfg.Scheduler.StandingOrderGracePeriod,
+		}),
+		usecases.WithSubscriptionDunning(usecases.DunningPolicy{
+			RetryInterval: cfg.Scheduler.SubscriptionRetryInterval,
+			MaxAttempts:   cfg.Scheduler.SubscriptionMaxAttempts,
+		}),
+		usecases.WithWebhookSender(webhooks.NewHTTPSender(cfg.Webhook.Timeout)),
+		usecases.WithWebhookRetry(usecases.WebhookRetryPolicy{
+			MaxAttempts:    cfg.Webhook.MaxAttempts,
+			InitialBackoff: cfg.Webhook.RetryInitial,
+			MaxBackoff:     cfg.Webhook.RetryMax,
+		}),
+		usecases.WithLiveSettings(liveSettings),
+		usecases.WithImpersonationTTL(cfg.Admin.ImpersonationTTL),
+		usecases.WithPasswordPolicy(passwords.Policy{
+			MinLength:     cfg.Password.MinLength,
+			RequireUpper:  cfg.Password.RequireUpper,
+			RequireLower:  cfg.Password.RequireLower,
+			RequireDigit:  cfg.Password.RequireDigit,
+			RequireSymbol: cfg.Password.RequireSymbol,
+			HistorySize:   cfg.Password.HistorySize,
+		}),
+	}
+	if cfg.Password.BreachCheckEnabled {
+		useCaseOptions = append(useCaseOptions, usecases.WithBreachChecker(passwords.NewPwnedPasswordsChecker(cfg.Password.BreachCheckURL)))
+	}
+
+	adminAllowlist := make([]string, len(cfg.Admin.IPAllowlist))
+	for i, value := range cfg.Admin.IPAllowlist {
+		cidr, err := models.NormalizeCIDR(value)
+		if err != nil {
+			log.Fatal("Failed to configure admin IP allowlist:", err)
+		}
+		adminAllowlist[i] = cidr
+	}
+	useCaseOptions = append(useCaseOptions, usecases.WithIPAllowlist(adminAllowlist, cfg.Admin.IPAllowlistRefresh))
+
+	if cfg.Fraud.Enabled {
+		fraudEngine, err := newFraudEngine(cfg.Fraud)
+		if err != nil {
+			log.Fatal("Failed to configure fraud rules:", err)
+		}
+		useCaseOptions = append(useCaseOptions, usecases.WithFraudEngine(fraudEngine))
+	}
+
+	if cfg.AML.Enabled {
+		useCaseOptions = append(useCaseOptions, usecases.WithAMLScreener(compliance.ManualReviewScreener{}, cfg.AML.Threshold))
+	}
+
+	if cfg.Sanctions.Enabled {
+		watchlist, err := compliance.LoadWatchlist(cfg.Sanctions.ListFile)
+		if err != nil {
+			log.Fatal("Failed to configure sanctions screening:", err)
+		}
+		checker := compliance.NewCachedSanctionsChecker(watchlist, cfg.Sanctions.CacheTTL)
+		useCaseOptions = append(useCaseOptions, usecases.WithSanctionsChecker(checker))
+	}
+
+	webhookVerifiers := payments.NewRegistry()
+	for provider, secret := range cfg.Payment.WebhookSecrets {
+		webhookVerifiers.Register(payments.NewHMACWebhookVerifier(provider, secret))
+	}
+	if cfg.Payment.StripeSecretKey != "" {
+		stripeClient := payments.NewStripeClient(cfg.Payment.StripeSecretKey, cfg.Payment.StripeWebhookSecret)
+		webhookVerifiers.Register(stripeClient)
+		cardProvider := payments.NewBreakerCardPaymentProvider(stripeClient, breakers.Get(stripeClient.Name()))
+		useCaseOptions = append(useCaseOptions, usecases.WithCardPaymentProvider(cardProvider))
+	}
+
+	var paymentProvider payments.PaymentProvider
+	switch cfg.Payment.Provider {
+	case payments.PaystackProviderName:
+		paymentProvider = payments.NewPaystackClient(cfg.Payment.PaystackSecretKey)
+	case payments.FlutterwaveProviderName:
+		paymentProvider = payments.NewFlutterwaveClient(cfg.Payment.FlutterwaveSecretKey, cfg.Payment.FlutterwaveWebhookSecret)
+	case "":
+		// Hosted checkout funding is disabled
+	default:
+		log.Fatalf("Unsupported payment provider: %s", cfg.Payment.Provider)
+	}
+	if paymentProvider != nil {
+		webhookVerifiers.Register(paymentProvider)
+		paymentProvider = payments.NewBreakerPaymentProvider(paymentProvider, breakers.Get(paymentProvider.Name()))
+		useCaseOptions = append(useCaseOptions, usecases.WithPaymentProvider(paymentProvider, cfg.Payment.CheckoutCallbackURL))
+	}
+
+	var payoutProvider payments.PayoutProvider
+	switch cfg.Payment.PayoutProvider {
+	case payments.PaystackProviderName:
+		payoutProvider = payments.NewPaystackClient(cfg.Payment.PaystackSecretKey)
+	case payments.FlutterwaveProviderName:
+		payoutProvider = payments.NewFlutterwaveClient(cfg.Payment.FlutterwaveSecretKey, cfg.Payment.FlutterwaveWebhookSecret)
+	case "":
+		// Withdrawals complete instantly
+	default:
+		log.Fatalf("Unsupported payout provider: %s", cfg.Payment.PayoutProvider)
+	}
+	if payoutProvider != nil {
+		webhookVerifiers.RegisterPayoutProvider(payoutProvider)
+		payoutProvider = payments.NewBreakerPayoutProvider(payoutProvider, breakers.Get(payoutProvider.Name()))
+		useCaseOptions = append(useCaseOptions, usecases.WithPayoutProvider(payoutProvider))
+	}
+
+	statementSecret := cfg.Statement.SigningSecret
+	if statementSecret == "" {
+		statementSecret = cfg.App.JWTSecret
+	}
+	useCaseOptions = append(useCaseOptions, usecases.WithStatementStorage(
+		storage.NewLocalStore(cfg.Statement.StorageDir),
+		storage.NewURLSigner(statementSecret, cfg.Statement.PublicBaseURL),
+		cfg.Statement.URLTTL,
+	))
+	useCaseOptions = append(useCaseOptions, usecases.WithAvatarStorage(
+		storage.NewLocalStore(cfg.Avatar.StorageDir),
+		cfg.Avatar.MaxBytes,
+	))
+
+	useCaseOptions = append(useCaseOptions, usecases.WithTransferQuoteTTL(cfg.FX.QuoteTTL))
+	if len(cfg.FX.Rates) > 0 {
+		rates, err := fx.ParseRates(cfg.FX.Rates)
+		if err != nil {
+			log.Fatal("Failed to configure exchange rates:", err)
+		}
+		useCaseOptions = append(useCaseOptions, usecases.WithExchangeRates(rates))
+	}
+
+	useCases := usecases.NewUseCases(repos, useCaseOptions...)
+	usecases.RegisterJobs(jobQueue, useCases)
+	eventBus.Subscribe(func(event events.Event) {
+		useCases.Webhook.EnqueueEvent(context.Background(), event)
+	})
+
+	stopJobQueue := jobQueue.Start()
+	defer stopJobQueue()
+
+	stopReloads := reloadOnHangup(liveSettings, emailSwitch, useCases.Audit)
+	defer stopReloads()
+
+	stopStandingOrders := jobs.StartStandingOrders(useCases.StandingOrder, cfg.Scheduler.StandingOrderInterval)
+	defer stopStandingOrders()
+
+	stopPendingExpiry := jobs.StartPendingExpiry(useCases.Wallet, cfg.Scheduler.PendingExpiryInterval, cfg.Scheduler.PendingTransactionTTL)
+	defer stopPendingExpiry()
+
+	stopSettlementBatches := jobs.StartSettlementBatches(useCases.Settlement, cfg.Scheduler.SettlementBatchInterval)
+	defer stopSettlementBatches()
+
+	stopBudgetAlerts := jobs.StartBudgetAlerts(useCases.Budget, cfg.Scheduler.BudgetAlertInterval)
+	defer stopBudgetAlerts()
+
+	stopEscrowExpiry := jobs.StartEscrowExpiry(useCases.Escrow, cfg.Scheduler.EscrowExpiryInterval)
+	defer stopEscrowExpiry()
+
+	stopSubscriptionBilling := jobs.StartSubscriptionBilling(useCases.Subscription, cfg.Scheduler.SubscriptionBillingInterval)
+	defer stopSubscriptionBilling()
+
+	stopAccountDeletion := jobs.StartAccountDeletion(useCases.User, cfg.Scheduler.AccountDeletionInterval, cfg.Scheduler.AccountDeletionGracePeriod)
+	defer stopAccountDeletion()
+
+	stopBalanceCheckpoints := jobs.StartBalanceCheckpoints(useCases.Reconciliation, cfg.Scheduler.BalanceCheckpointInterval, cfg.Scheduler.BalanceCheckpointLag)
+	defer stopBalanceCheckpoints()
+
+	if cfg.Scheduler.TransactionRetention > 0 {
+		stopArchive := jobs.StartTransactionArchive(useCases.Archive, cfg.Scheduler.ArchiveInterval, cfg.Scheduler.TransactionRetention)
+		defer stopArchive()
+	}
+
+	if cfg.Digest.Enabled {
+		stopDigest := jobs.StartReconciliationDigest(useCases.Reconciliation, notifier, cfg.Digest.Recipients, cfg.Digest.Interval)
+		defer stopDigest()
 	}
 
-	repos := repositories.NewRepositories(db)
+	var jwtOptions []auth.JWTOption
+	if len(cfg.App.JWTSigningKeys) > 0 {
+		signingKeys, err := auth.LoadSigningKeys(cfg.App.JWTSigningKeys)
+		if err != nil {
+			log.Fatal("Failed to load JWT signing keys:", err)
+		}
+		keyStore, err := auth.NewKeyStore(signingKeys...)
+		if err != nil {
+			log.Fatal("Failed to load JWT signing keys:", err)
+		}
+		jwtOptions = append(jwtOptions, auth.WithKeyStore(keyStore))
+	}
+	jwtService := auth.NewJWTService(cfg.App.JWTSecret, "wallet-service", jwtOptions...)
 
-	useCases := usecases.NewUseCases(repos)
+	if secretProvider != nil && cfg.Secrets.RotationInterval > 0 {
+		stopSecretRotation := secrets.Watch(secretProvider, cfg.Secrets.RotationInterval, func(changed []string) {
+			rotateSecrets(changed, jwtService)
+		})
+		defer stopSecretRotation()
+	}
 
-	jwtService := auth.NewJWTService(cfg.App.JWTSecret, "wallet-service")
+	oauthProviders := oauth.NewRegistry(oauth.NewSessionCodec(cfg.App.JWTSecret, cfg.OAuth.SessionTTL))
+	oauthCallbackURL := func(provider string) string {
+		return strings.TrimSuffix(cfg.OAuth.CallbackBaseURL, "/") + "/api/v1/auth/oauth/" + provider + "/callback"
+	}
+	if cfg.OAuth.GoogleClientID != "" {
+		oauthProviders.Register(oauth.NewGoogleProvider(
+			cfg.OAuth.GoogleClientID,
+			cfg.OAuth.GoogleClientSecret,
+			oauthCallbackURL(oauth.GoogleProviderName),
+		))
+	}
+	if cfg.OAuth.OIDCIssuer != "" {
+		oauthProviders.Register(oauth.NewProvider(oauth.Config{
+			Name:         cfg.OAuth.OIDCName,
+			Issuer:       cfg.OAuth.OIDCIssuer,
+			ClientID:     cfg.OAuth.OIDCClientID,
+			ClientSecret: cfg.OAuth.OIDCClientSecret,
+			RedirectURL:  oauthCallbackURL(cfg.OAuth.OIDCName),
+			Scopes:       cfg.OAuth.OIDCScopes,
+		}))
+	}
 
-	router := gin.Default()
+	v1Deprecation := middleware.Deprecation{
+		DeprecatedAt: cfg.API.V1DeprecatedAt,
+		Sunset:       cfg.API.V1Sunset,
+	}
+	// Each API version has its own document; v2 is served under /swagger/v2/
 	docs.SwaggerInfo.BasePath = "/api/v1"
-	router.GET("/swagger/*any", ginSwagger.WrapHandler(swaggerFiles.Handler))
+	v1Docs := ginSwagger.WrapHandler(swaggerFiles.Handler)
+	v2Docs := ginSwagger.WrapHandler(swaggerFiles.Handler, ginSwagger.InstanceName(docsv2.SwaggerInfov2.InstanceName()))
+	var requestValidator *openapi.Validator
+	if cfg.Server.RequestValidation {
+		validator, err := openapi.Load([]byte(docs.SwaggerInfo.ReadDoc()), []byte(docsv2.SwaggerInfov2.ReadDoc()))
+		if err != nil {
+			log.Fatal("Failed to load the API documents:", err)
+		}
+		requestValidator = validator
+	}
+	// The rate limit is shared by every router, so a client has one window across tenants
+	rateLimit := middleware.RateLimit(cfg.Server.RateLimit, cfg.Server.RateLimitWindow)
+	newRouter := func(useCases *usecases.UseCases) *gin.Engine {
+		router := gin.Default()
+		if err := router.SetTrustedProxies(cfg.Server.TrustedProxies); err != nil {
+			log.Fatal("Invalid trusted proxies:", err)
+		}
+		router.Use(middleware.SecurityHeaders(cfg.Server.HSTSMaxAge))
+		if cfg.Server.CompressionEnabled {
+			router.Use(middleware.Compress(cfg.Server.CompressionMinBytes))
+		}
+		router.GET("/swagger/*any", func(c *gin.Context) {
+			if strings.HasPrefix(c.Param("any"), "/v2/") {
+				v2Docs(c)
+				return
+			}
+			v1Docs(c)
+		})
+		router.Use(middleware.Locale())
+		router.Use(middleware.ImpersonationAudit(useCases.Audit))
+		router.Use(middleware.ErrorHandler())
+		router.Use(rateLimit)
+		router.Use(middleware.BodyLimit(cfg.Server.MaxBodyBytes))
+		if requestValidator != nil {
+			router.Use(middleware.ValidateRequests(requestValidator))
+		}
+		router.Use(middleware.RequestOrigin(cfg.Fraud.CountryHeader))
+		routes.SetupRoutes(router, useCases, jwtService, webhookVerifiers, breakers, oauthProviders, v1Deprecation)
+		return router
+	}
+
+	// The deployment-wide router serves webhooks and other requests not made for a tenant; every
+	// tenant gets a router of its own over repositories scoped to it
+	deploymentRouter := newRouter(useCases)
+	deploymentRouter.GET("/metrics", gin.WrapH(metricsRegistry.Handler(cfg.Metrics.Token)))
+	deploymentRouter.GET("/ready", handlers.NewReadinessHandler(sqlDB).Ready)
+	router := routes.NewTenantRouter(deploymentRouter, func(tenantID uint) http.Handler {
+		return newRouter(usecases.NewUseCases(repos.ForTenant(tenantID), useCaseOptions...))
+	}, jwtService, useCases.Tenant)
 
-	routes.SetupRoutes(router, useCases, jwtService)
+	tlsConfig, redirectHandler, err := server.NewTLS(cfg.Server)
+	if err != nil {
+		log.Fatal("Failed to configure TLS:", err)
+	}
+
+	// With an admin listener the admin endpoints are only reachable there
+	var publicHandler http.Handler = router
+	if cfg.Server.AdminAddress != "" {
+		publicHandler = server.WithoutAdmin(router)
+	}
+	listeners := []server.Listener{{Address: fmt.Sprintf("%s:%s", cfg.Server.Host, cfg.Server.Port), Handler: publicHandler}}
+	for _, address := range cfg.Server.ExtraAddresses {
+		listeners = append(listeners, server.Listener{Address: address, Handler: publicHandler})
+	}
+	if cfg.Server.UnixSocket != "" {
+		listeners = append(listeners, server.Listener{Address: "unix:" + cfg.Server.UnixSocket, Handler: publicHandler})
+	}
+	if cfg.Server.AdminAddress != "" {
+		listeners = append(listeners, server.Listener{Address: cfg.Server.AdminAddress, Handler: server.AdminOnly(router)})
+	}
+	scheme := "http"
+	if tlsConfig != nil {
+		scheme = "https"
+		if cfg.Server.HTTPRedirectPort != "" {
+			listeners = append(listeners, server.Listener{
+				Address: fmt.Sprintf("%s:%s", cfg.Server.Host, cfg.Server.HTTPRedirectPort),
+				Handler: redirectHandler,
+				Plain:   true,
+			})
+		}
+	}
 
-	server := &http.Server{
-		Addr:         fmt.Sprintf("%s:%s", cfg.Server.Host, cfg.Server.Port),
-		Handler:      router,
-		ReadTimeout:  cfg.Server.ReadTimeout,
-		WriteTimeout: cfg.Server.WriteTimeout,
+	if cfg.Server.GRPCAddress != "" {
+		if err := serveGRPC(cfg.Server.GRPCAddress, tlsConfig, useCases, jwtService); err != nil {
+			log.Fatal("Failed to start the gRPC API:", err)
+		}
 	}
 
-	log.Printf("Server starting on %s:%s in %s mode",
-		cfg.Server.Host, cfg.Server.Port, cfg.App.Environment)
-	log.Printf("Swagger UI available at: http://%s:%s/swagger/index.html",
-		cfg.Server.Host, cfg.Server.Port)
+	for _, listener := range listeners {
+		log.Printf("Server listening on %s in %s mode", listener.Address, cfg.App.Environment)
+	}
+	log.Printf("Swagger UI available at: %s://%s:%s/swagger/index.html (v2: /swagger/v2/index.html)",
+		scheme, cfg.Server.Host, cfg.Server.Port)
 
-	if err := server.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+	if err := server.Serve(listeners, tlsConfig, cfg.Server.ReadTimeout, cfg.Server.WriteTimeout); err != nil && !errors.Is(err, http.ErrServerClosed) {
 		log.Fatal("Failed to start server:", err)
 	}
 }
+
+// envFile holds environment variables for development; it is read again on reload
+const envFile = ".env"
+
+// runtimeSettings are the settings of cfg that are reloaded on SIGHUP
+func runtimeSettings(cfg *config.Config) usecases.RuntimeSettings {
+	settings := usecases.RuntimeSettings{
+		Login: usecases.LoginPolicy{
+			MaxFailures:   cfg.Login.MaxFailures,
+			Cooldown:      cfg.Login.LockoutCooldown,
+			IPMaxFailures: cfg.Login.IPMaxFailures,
+			IPWindow:      cfg.Login.IPWindow,
+		},
+		PIN: usecases.PINPolicy{
+			Threshold:   cfg.PIN.Threshold,
+			MaxAttempts: cfg.PIN.MaxAttempts,
+			Cooldown:    cfg.PIN.LockoutCooldown,
+		},
+		AMLThreshold: cfg.AML.Threshold,
+	}
+	if cfg.StepUp.Enabled {
+		settings.TransferChallenge = &usecases.TransferChallengePolicy{
+			Threshold: cfg.StepUp.Threshold,
+			TTL:       cfg.StepUp.CodeTTL,
+		}
+	}
+	return settings
+}
+
+// reloadOnHangup reloads the runtime settings and the email switch on SIGHUP until stopped
+func reloadOnHangup(settings *usecases.LiveSettings, email *notifications.SwitchableEmailSender, audit usecases.AuditUseCase) func() {
+	hangups := make(chan os.Signal, 1)
+	signal.Notify(hangups, syscall.SIGHUP)
+	done := make(chan struct{})
+	go func() {
+		for {
+			select {
+			case <-hangups:
+				reloadSettings(settings, email, audit)
+			case <-done:
+				return
+			}
+		}
+	}()
+	return func() {
+		signal.Stop(hangups)
+		close(done)
+	}
+}
+
+// reloadSettings reads the env file and the environment again and applies the runtime settings,
+// recording the ones that changed in the audit log
+func reloadSettings(settings *usecases.LiveSettings, email *notifications.SwitchableEmailSender, audit usecases.AuditUseCase) {
+	if err := config.LoadEnvFile(envFile); err != nil && !errors.Is(err, fs.ErrNotExist) {
+		log.Printf("Failed to reload configuration: %v", err)
+		return
+	}
+	cfg := config.LoadConfig()
+	if err := cfg.Validate(); err != nil && cfg.IsProduction() {
+		log.Printf("Configuration not reloaded, it is invalid:\n%v", err)
+		return
+	}
+
+	next := runtimeSettings(cfg)
+	changes := next.Changes(settings.Store(next))
+	if wasEnabled := email.SetEnabled(cfg.Notification.EmailEnabled); wasEnabled != cfg.Notification.EmailEnabled {
+		changes["notification.email_enabled"] = usecases.SettingChange{
+			From: strconv.FormatBool(wasEnabled),
+			To:   strconv.FormatBool(cfg.Notification.EmailEnabled),
+		}
+	}
+	if len(changes) == 0 {
+		log.Println("Configuration reloaded, nothing changed")
+		return
+	}
+	log.Printf("Configuration reloaded, %d settings changed", len(changes))
+
+	metadata, _ := json.Marshal(map[string]interface{}{"changes": changes})
+	entry := &models.AuditLog{
+		ActorRole:    "system",
+		Action:       "config.reloaded",
+		ResourceType: "config",
+		Metadata:     string(metadata),
+	}
+	if err := audit.Record(context.Background(), entry); err != nil {
+		log.Printf("Failed to record configuration reload audit log: %v", err)
+	}
+}
+
+// serveGRPC serves the gRPC API on address in the background, over TLS when tlsConfig is set and the
+// address is not a unix socket
+func serveGRPC(address string, tlsConfig *tls.Config, useCases *usecases.UseCases, jwtService *auth.JWTService) error {
+	ln, err := server.Listen(address)
+	if err != nil {
+		return err
+	}
+	var opts []grpc.ServerOption
+	if tlsConfig != nil && !strings.HasPrefix(address, "unix:") {
+		opts = append(opts, grpc.Creds(credentials.NewTLS(tlsConfig)))
+	}
+	grpcServer := rpc.NewServer(useCases, jwtService, opts...)
+	go func() {
+		if err := grpcServer.Serve(ln); err != nil {
+			log.Fatal("gRPC API stopped:", err)
+		}
+	}()
+	log.Printf("gRPC API listening on %s", address)
+	return nil
+}
+
+// newSecretProvider returns the secrets backend of cfg, or nil when secrets stay in the environment
+func newSecretProvider(cfg config.SecretsConfig) secrets.Provider {
+	switch cfg.Provider {
+	case secrets.VaultProviderName:
+		return secrets.NewVaultProvider(cfg.VaultAddr, cfg.VaultToken, cfg.VaultPath)
+	case secrets.AWSProviderName:
+		return secrets.NewAWSProvider(cfg.AWSRegion, cfg.AWSSecretID, secrets.AWSCredentials{
+			AccessKeyID:     cfg.AWSAccessKeyID,
+			SecretAccessKey: cfg.AWSSecretAccessKey,
+			SessionToken:    cfg.AWSSessionToken,
+		})
+	case "":
+		return nil
+	default:
+		log.Fatalf("Unsupported secrets provider: %s", cfg.Provider)
+		return nil
+	}
+}
+
+// rotateSecrets applies secrets rotated in the backend: the JWT secret and the database password take
+// effect straight away, the provider keys on the next restart
+func rotateSecrets(changed []string, jwtService *auth.JWTService) {
+	var pending []string
+	for _, name := range changed {
+		switch name {
+		case "JWT_SECRET":
+			jwtService.RotateSecret(os.Getenv(name))
+		case "DB_PASSWORD":
+			// Read again whenever a database connection is opened
+		default:
+			pending = append(pending, name)
+		}
+	}
+	log.Printf("Secrets rotated: %s", strings.Join(changed, ", "))
+	if len(pending) > 0 {
+		log.Printf("Restart to apply the rotated %s", strings.Join(pending, ", "))
+	}
+}
+
+// newFraudEngine builds the rules engine from configuration, leaving out disabled rules
+func newFraudEngine(cfg config.FraudConfig) (*fraud.Engine, error) {
+	actions := make(map[string]fraud.Action)
+	for name, value := range map[string]string{
+		"velocity":      cfg.VelocityAction,
+		"new_recipient": cfg.NewRecipientAction,
+		"origin":        cfg.OriginAction,
+		"structuring":   cfg.StructuringAction,
+	} {
+		action, err := fraud.ParseAction(value)
+		if err != nil {
+			return nil, fmt.Errorf("%s rule: %w", name, err)
+		}
+		actions[name] = action
+	}
+
+	var rules []fraud.Rule
+	if cfg.VelocityLimit > 0 {
+		rules = append(rules, fraud.VelocityRule{Limit: cfg.VelocityLimit, Window: cfg.VelocityWindow, Action: actions["velocity"]})
+	}
+	if cfg.NewRecipientThreshold.IsPositive() {
+		rules = append(rules, fraud.NewRecipientRule{Threshold: cfg.NewRecipientThreshold, Action: actions["new_recipient"]})
+	}
+	if cfg.OriginThreshold.IsPositive() {
+		rules = append(rules, fraud.OriginAnomalyRule{Threshold: cfg.OriginThreshold, Action: actions["origin"]})
+	}
+	if cfg.StructuringThreshold.IsPositive() && cfg.StructuringCount > 0 {
+		rules = append(rules, fraud.StructuringRule{
+			Threshold: cfg.StructuringThreshold,
+			Margin:    cfg.StructuringMargin,
+			MinCount:  cfg.StructuringCount,
+			Window:    cfg.StructuringWindow,
+			Action:    actions["structuring"],
+		})
+	}
+
+	return fraud.NewEngine(rules...), nil
+}
@@ -0,0 +1,443 @@
+package apperrors
+
+// Error codes returned in the code field of error responses. Codes are part of the API: clients
+// branch on them, so existing codes must not be renamed
+const (
+	CodeValidation         = "VALIDATION_FAILED"
+	CodeUnauthenticated    = "UNAUTHENTICATED"
+	CodeInvalidToken       = "INVALID_TOKEN"
+	CodeForbidden          = "FORBIDDEN"
+	CodeServiceUnavailable = "SERVICE_UNAVAILABLE"
+	CodeRequestTooLarge    = "REQUEST_TOO_LARGE"
+	CodeRateLimited        = "RATE_LIMITED"
+
+	CodeUserNotFound = "USER_NOT_FOUND"
+	CodeEmailTaken   = "EMAIL_TAKEN"
+
+	CodeWalletNotFound             = "WALLET_NOT_FOUND"
+	CodeWalletInactive             = "WALLET_INACTIVE"
+	CodeWalletExists               = "WALLET_EXISTS"
+	CodeCounterpartyWalletNotFound = "COUNTERPARTY_WALLET_NOT_FOUND"
+	CodeCounterpartyWalletInactive = "COUNTERPARTY_WALLET_INACTIVE"
+	CodeSandboxDisabled            = "SANDBOX_DISABLED"
+	CodeSandboxTokenRequired       = "SANDBOX_TOKEN_REQUIRED"
+	CodeSandboxProviderForbidden   = "SANDBOX_PROVIDER_FORBIDDEN"
+
+	CodeInvalidAmount           = "INVALID_AMOUNT"
+	CodeAmountRequired          = "AMOUNT_REQUIRED"
+	CodeAmountMismatch          = "AMOUNT_MISMATCH"
+	CodeCurrencyMismatch        = "CURRENCY_MISMATCH"
+	CodeDuplicateReference      = "DUPLICATE_REFERENCE"
+	CodeInsufficientFunds       = "INSUFFICIENT_FUNDS"
+	CodeSelfPayment             = "SELF_PAYMENT"
+	CodeBankAccountRequired     = "BANK_ACCOUNT_REQUIRED"
+	CodeTierLimitExceeded       = "TIER_LIMIT_EXCEEDED"
+	CodeTierFeatureUnavailable  = "TIER_FEATURE_UNAVAILABLE"
+	CodeBalanceMismatch         = "BALANCE_MISMATCH"
+	CodeReconciliationFailed    = "RECONCILIATION_FAILED"
+	CodeConcurrentModification  = "CONCURRENT_MODIFICATION"
+	CodeInvalidCursor           = "INVALID_CURSOR"
+	CodeInvalidQRCode           = "INVALID_QR_CODE"
+	CodePaymentLinkNotFound     = "PAYMENT_LINK_NOT_FOUND"
+	CodePaymentLinkInactive     = "PAYMENT_LINK_INACTIVE"
+	CodeMoneyRequestNotFound    = "MONEY_REQUEST_NOT_FOUND"
+	CodeMoneyRequestNotPending  = "MONEY_REQUEST_NOT_PENDING"
+	CodeStandingOrderNotFound   = "STANDING_ORDER_NOT_FOUND"
+	CodeStandingOrderInactive   = "STANDING_ORDER_INACTIVE"
+	CodeWalletTierNotFound      = "WALLET_TIER_NOT_FOUND"
+	CodeFraudBlocked            = "FRAUD_BLOCKED"
+	CodeComplianceBlocked       = "COMPLIANCE_BLOCKED"
+	CodeAMLDenied               = "AML_DENIED"
+	CodeSanctionsMatch          = "SANCTIONS_MATCH"
+	CodeFraudReviewNotFound     = "FRAUD_REVIEW_NOT_FOUND"
+	CodeFraudReviewResolved     = "FRAUD_REVIEW_RESOLVED"
+	CodeAMLCaseNotFound         = "AML_CASE_NOT_FOUND"
+	CodeAMLCaseResolved         = "AML_CASE_RESOLVED"
+	CodeBlocklistEntryNotFound  = "BLOCKLIST_ENTRY_NOT_FOUND"
+	CodeAlreadyBlocklisted      = "ALREADY_BLOCKLISTED"
+	CodeFundingNotConfigured    = "FUNDING_NOT_CONFIGURED"
+	CodePaymentProviderFailed   = "PAYMENT_PROVIDER_FAILED"
+	CodePayoutFailed            = "PAYOUT_FAILED"
+	CodeDepositNotFound         = "DEPOSIT_NOT_FOUND"
+	CodeDepositMismatch         = "DEPOSIT_MISMATCH"
+	CodePayoutNotFound          = "PAYOUT_NOT_FOUND"
+	CodePaymentProviderNotFound = "PAYMENT_PROVIDER_NOT_FOUND"
+	CodeInvalidWebhookSignature = "INVALID_WEBHOOK_SIGNATURE"
+	CodeInvalidPhoneNumber      = "INVALID_PHONE_NUMBER"
+	CodeVerificationNotPending  = "VERIFICATION_NOT_PENDING"
+	CodeVerificationExpired     = "VERIFICATION_EXPIRED"
+	CodeInvalidVerificationCode = "INVALID_VERIFICATION_CODE"
+	CodeDeliveryFailed          = "DELIVERY_FAILED"
+	CodeDeviceNotFound          = "DEVICE_NOT_FOUND"
+	CodeJobNotFound             = "JOB_NOT_FOUND"
+	CodeJobNotDead              = "JOB_NOT_DEAD"
+
+	CodeIPNotAllowed             = "IP_NOT_ALLOWED"
+	CodeIPAllowlistEntryNotFound = "IP_ALLOWLIST_ENTRY_NOT_FOUND"
+	CodeAlreadyAllowlisted       = "ALREADY_ALLOWLISTED"
+	CodeIPAllowlistLockout       = "IP_ALLOWLIST_LOCKOUT"
+
+	CodeTokenRevoked      = "TOKEN_REVOKED"
+	CodeIncorrectPassword = "INCORRECT_PASSWORD"
+
+	CodeOAuthProviderNotFound = "OAUTH_PROVIDER_NOT_FOUND"
+	CodeOAuthFailed           = "OAUTH_FAILED"
+	CodeOAuthEmailUnverified  = "OAUTH_EMAIL_UNVERIFIED"
+	CodeOAuthUnavailable      = "OAUTH_UNAVAILABLE"
+
+	CodeInvalidCredentials   = "INVALID_CREDENTIALS"
+	CodeAccountLocked        = "ACCOUNT_LOCKED"
+	CodeTooManyLoginAttempts = "TOO_MANY_LOGIN_ATTEMPTS"
+
+	CodeWeakPassword     = "WEAK_PASSWORD"
+	CodePasswordBreached = "PASSWORD_BREACHED"
+	CodePasswordReused   = "PASSWORD_REUSED"
+
+	CodeSessionNotFound = "SESSION_NOT_FOUND"
+
+	CodeImpersonationForbidden = "IMPERSONATION_FORBIDDEN"
+	CodeImpersonationReadOnly  = "IMPERSONATION_READ_ONLY"
+
+	CodePINRequired   = "PIN_REQUIRED"
+	CodeInvalidPIN    = "INVALID_PIN"
+	CodePINLocked     = "PIN_LOCKED"
+	CodePINAlreadySet = "PIN_ALREADY_SET"
+	CodePINNotSet     = "PIN_NOT_SET"
+
+	CodeChallengeNotFound    = "CHALLENGE_NOT_FOUND"
+	CodeChallengeExpired     = "CHALLENGE_EXPIRED"
+	CodeInvalidChallengeCode = "INVALID_CHALLENGE_CODE"
+	CodeStepUpPhoneRequired  = "STEP_UP_PHONE_REQUIRED"
+
+	CodeLedgerAccountNotFound  = "LEDGER_ACCOUNT_NOT_FOUND"
+	CodeJournalEntryNotFound   = "JOURNAL_ENTRY_NOT_FOUND"
+	CodeUnbalancedJournalEntry = "UNBALANCED_JOURNAL_ENTRY"
+
+	CodeSuspenseItemNotFound = "SUSPENSE_ITEM_NOT_FOUND"
+	CodeSuspenseItemResolved = "SUSPENSE_ITEM_RESOLVED"
+
+	CodeSettlementBatchNotFound = "SETTLEMENT_BATCH_NOT_FOUND"
+
+	CodeStatementNotFound       = "STATEMENT_NOT_FOUND"
+	CodeStatementLineNotFound   = "STATEMENT_LINE_NOT_FOUND"
+	CodeDuplicateStatement      = "DUPLICATE_STATEMENT"
+	CodeStatementLineNotPending = "STATEMENT_LINE_NOT_PENDING"
+
+	CodeAccountStatementNotFound = "ACCOUNT_STATEMENT_NOT_FOUND"
+	CodeStatementsNotConfigured  = "STATEMENTS_NOT_CONFIGURED"
+	CodeInvalidDownloadLink      = "INVALID_DOWNLOAD_LINK"
+
+	CodeTransactionNotFound = "TRANSACTION_NOT_FOUND"
+
+	CodeQuoteNotFound = "QUOTE_NOT_FOUND"
+	CodeQuoteExpired  = "QUOTE_EXPIRED"
+	CodeQuoteMismatch = "QUOTE_MISMATCH"
+
+	CodeBudgetNotFound = "BUDGET_NOT_FOUND"
+	CodeBudgetExists   = "BUDGET_EXISTS"
+
+	CodeTenantNotFound = "TENANT_NOT_FOUND"
+	CodeTenantExists   = "TENANT_EXISTS"
+
+	CodeBusinessAccountNotFound = "BUSINESS_ACCOUNT_NOT_FOUND"
+	CodeBusinessMemberExists    = "BUSINESS_MEMBER_EXISTS"
+	CodeBusinessMemberNotFound  = "BUSINESS_MEMBER_NOT_FOUND"
+	CodeBusinessRoleForbidden   = "BUSINESS_ROLE_FORBIDDEN"
+	CodeApprovalNotFound        = "APPROVAL_NOT_FOUND"
+	CodeApprovalResolved        = "APPROVAL_RESOLVED"
+
+	CodeEscrowNotFound  = "ESCROW_NOT_FOUND"
+	CodeEscrowSettled   = "ESCROW_SETTLED"
+	CodeEscrowPayerOnly = "ESCROW_PAYER_ONLY"
+
+	CodeSubscriptionPlanNotFound = "SUBSCRIPTION_PLAN_NOT_FOUND"
+	CodeSubscriptionPlanArchived = "SUBSCRIPTION_PLAN_ARCHIVED"
+	CodeSubscriptionMerchantOnly = "SUBSCRIPTION_MERCHANT_ONLY"
+	CodeSubscriptionNotFound     = "SUBSCRIPTION_NOT_FOUND"
+	CodeSubscriptionExists       = "SUBSCRIPTION_EXISTS"
+	CodeSubscriptionCancelled    = "SUBSCRIPTION_CANCELLED"
+	CodeSubscriptionNotSuspended = "SUBSCRIPTION_NOT_SUSPENDED"
+
+	CodeWebhookSubscriptionNotFound = "WEBHOOK_SUBSCRIPTION_NOT_FOUND"
+	CodeWebhookSubscriptionDisabled = "WEBHOOK_SUBSCRIPTION_DISABLED"
+	CodeWebhookDeliveryNotFound     = "WEBHOOK_DELIVERY_NOT_FOUND"
+	CodeWebhookDeliveryNotDead      = "WEBHOOK_DELIVERY_NOT_DEAD"
+
+	CodeInvalidAvatar        = "INVALID_AVATAR"
+	CodeAvatarNotFound       = "AVATAR_NOT_FOUND"
+	CodeAvatarsNotConfigured = "AVATARS_NOT_CONFIGURED"
+
+	CodeAccountDeleted    = "ACCOUNT_DELETED"
+	CodeAccountHasBalance = "ACCOUNT_HAS_BALANCE"
+
+	CodeReconciliationRunNotFound = "RECONCILIATION_RUN_NOT_FOUND"
+
+	CodeReconciliationReportNotFound = "RECONCILIATION_REPORT_NOT_FOUND"
+	CodeReconciliationReportResolved = "RECONCILIATION_REPORT_RESOLVED"
+	CodeReconciliationReportNoIssue  = "RECONCILIATION_REPORT_NO_ISSUE"
+
+	CodeAdjustmentNotFound     = "ADJUSTMENT_NOT_FOUND"
+	CodeAdjustmentDecided      = "ADJUSTMENT_DECIDED"
+	CodeAdjustmentSelfApproval = "ADJUSTMENT_SELF_APPROVAL"
+
+	CodeTransactionPurposeNotFound = "TRANSACTION_PURPOSE_NOT_FOUND"
+	CodeTransactionPurposeExists   = "TRANSACTION_PURPOSE_EXISTS"
+	CodeTransactionPurposeInUse    = "TRANSACTION_PURPOSE_IN_USE"
+
+	CodeHandleNotFound = "HANDLE_NOT_FOUND"
+	CodeHandleTaken    = "HANDLE_TAKEN"
+)
+
+// Sentinel errors of the domain. Return them as they are, or use Withf or Wrap for a more specific
+// message; callers match them with errors.Is
+var (
+	// ErrValidation covers business rule violations that need no code of their own; always use Withf
+	ErrValidation      = New(KindInvalid, CodeValidation, "validation failed")
+	ErrUnauthenticated = New(KindUnauthenticated, CodeUnauthenticated, "user not authenticated")
+	// ErrInvalidToken refuses bearer tokens that are malformed, expired or not signed by the service
+	ErrInvalidToken = New(KindUnauthenticated, CodeInvalidToken, "invalid or expired token")
+	// ErrForbidden refuses callers whose role or tenant may not use an endpoint
+	ErrForbidden       = New(KindForbidden, CodeForbidden, "insufficient permissions")
+	ErrRequestTooLarge = New(KindTooLarge, CodeRequestTooLarge, "request body too large")
+	// ErrRateLimited is returned to clients that sent more requests than the rate limit allows; the
+	// Retry-After header says when to try again
+	ErrRateLimited = New(KindRateLimited, CodeRateLimited, "rate limit exceeded")
+
+	ErrUserNotFound = New(KindNotFound, CodeUserNotFound, "user not found")
+	ErrEmailTaken   = New(KindConflict, CodeEmailTaken, "user with this email already exists")
+
+	ErrWalletNotFound = New(KindNotFound, CodeWalletNotFound, "wallet not found")
+	ErrWalletInactive = New(KindInvalid, CodeWalletInactive, "wallet is not active")
+	ErrWalletExists   = New(KindConflict, CodeWalletExists, "user already has a wallet")
+	// ErrCounterpartyWalletNotFound is the wallet on the other side of a transfer, payment or request
+	ErrCounterpartyWalletNotFound = New(KindNotFound, CodeCounterpartyWalletNotFound, "destination wallet not found")
+	ErrCounterpartyWalletInactive = New(KindInvalid, CodeCounterpartyWalletInactive, "destination wallet is not active")
+	ErrSandboxDisabled            = New(KindForbidden, CodeSandboxDisabled, "sandbox mode is disabled")
+	ErrSandboxTokenRequired       = New(KindForbidden, CodeSandboxTokenRequired, "not a sandbox token")
+	// ErrSandboxProviderForbidden keeps sandbox tokens away from real payment providers
+	ErrSandboxProviderForbidden = New(KindForbidden, CodeSandboxProviderForbidden, "sandbox token cannot use payment providers")
+
+	ErrInvalidAmount          = New(KindInvalid, CodeInvalidAmount, "amount must be greater than zero")
+	ErrAmountRequired         = New(KindInvalid, CodeAmountRequired, "amount is required")
+	ErrAmountMismatch         = New(KindInvalid, CodeAmountMismatch, "amount does not match")
+	ErrCurrencyMismatch       = New(KindInvalid, CodeCurrencyMismatch, "currency does not match")
+	ErrDuplicateReference     = New(KindConflict, CodeDuplicateReference, "duplicate reference")
+	ErrInsufficientFunds      = New(KindConflict, CodeInsufficientFunds, "insufficient funds")
+	ErrSelfPayment            = New(KindInvalid, CodeSelfPayment, "cannot transfer to the same wallet")
+	ErrBankAccountRequired    = New(KindInvalid, CodeBankAccountRequired, "bank account is required for withdrawals")
+	ErrTierLimitExceeded      = New(KindInvalid, CodeTierLimitExceeded, "amount exceeds the limits of the wallet tier")
+	ErrTierFeatureUnavailable = New(KindForbidden, CodeTierFeatureUnavailable, "feature is not available on this wallet tier")
+	ErrBalanceMismatch        = New(KindConflict, CodeBalanceMismatch, "wallet balance mismatch detected")
+	ErrReconciliationFailed   = New(KindUnavailable, CodeReconciliationFailed, "reconciliation check failed")
+	ErrConcurrentModification = New(KindConflict, CodeConcurrentModification, "wallet version mismatch - concurrent modification detected")
+	ErrInvalidCursor          = New(KindInvalid, CodeInvalidCursor, "invalid cursor")
+
+	ErrPaymentLinkNotFound    = New(KindNotFound, CodePaymentLinkNotFound, "payment link not found")
+	ErrPaymentLinkInactive    = New(KindConflict, CodePaymentLinkInactive, "payment link is no longer active")
+	ErrMoneyRequestNotFound   = New(KindNotFound, CodeMoneyRequestNotFound, "money request not found")
+	ErrMoneyRequestNotPending = New(KindConflict, CodeMoneyRequestNotPending, "money request is no longer pending")
+	ErrStandingOrderNotFound  = New(KindNotFound, CodeStandingOrderNotFound, "standing order not found")
+	ErrStandingOrderInactive  = New(KindConflict, CodeStandingOrderInactive, "standing order is not active")
+	ErrWalletTierNotFound     = New(KindNotFound, CodeWalletTierNotFound, "wallet tier not found")
+
+	// ErrFraudBlocked carries the rule and reason in its message; use Withf
+	ErrFraudBlocked = New(KindForbidden, CodeFraudBlocked, "transaction blocked by fraud rule")
+	// ErrComplianceBlocked is returned for debits involving a blocklisted party; the matching entry is
+	// only recorded in the compliance log
+	ErrComplianceBlocked = New(KindForbidden, CodeComplianceBlocked, "transaction blocked for compliance reasons")
+	// ErrAMLDenied is returned for debits denied by AML screening; the reason is only recorded in the
+	// compliance log so the customer is not tipped off
+	ErrAMLDenied = New(KindForbidden, CodeAMLDenied, "transaction denied by AML screening")
+	// ErrSanctionsMatch is returned when a new user or transfer recipient matches a sanctions watchlist
+	ErrSanctionsMatch      = New(KindForbidden, CodeSanctionsMatch, "sanctions screening match")
+	ErrFraudReviewNotFound = New(KindNotFound, CodeFraudReviewNotFound, "fraud review not found")
+	// ErrFraudReviewResolved signals that a concurrent decision resolved the review first
+	ErrFraudReviewResolved = New(KindConflict, CodeFraudReviewResolved, "fraud review already resolved")
+	ErrAMLCaseNotFound     = New(KindNotFound, CodeAMLCaseNotFound, "AML case not found")
+	// ErrAMLCaseResolved signals that a concurrent decision resolved the case first
+	ErrAMLCaseResolved        = New(KindConflict, CodeAMLCaseResolved, "AML case already resolved")
+	ErrBlocklistEntryNotFound = New(KindNotFound, CodeBlocklistEntryNotFound, "blocklist entry not found")
+	ErrAlreadyBlocklisted     = New(KindConflict, CodeAlreadyBlocklisted, "value is already blocklisted")
+
+	ErrFundingNotConfigured  = New(KindUnavailable, CodeFundingNotConfigured, "funding is not configured")
+	ErrPaymentProviderFailed = New(KindUpstream, CodePaymentProviderFailed, "payment provider request failed")
+	ErrPayoutFailed          = New(KindUpstream, CodePayoutFailed, "payout failed")
+	ErrDepositNotFound       = New(KindNotFound, CodeDepositNotFound, "deposit not found")
+	ErrDepositMismatch       = New(KindInvalid, CodeDepositMismatch, "deposit does not match")
+	ErrPayoutNotFound        = New(KindNotFound, CodePayoutNotFound, "payout not found")
+	// ErrPaymentProviderNotFound is returned for webhooks of providers that are not configured
+	ErrPaymentProviderNotFound = New(KindNotFound, CodePaymentProviderNotFound, "payment provider not found")
+	ErrInvalidWebhookSignature = New(KindUnauthenticated, CodeInvalidWebhookSignature, "invalid webhook signature")
+
+	ErrInvalidPhoneNumber      = New(KindInvalid, CodeInvalidPhoneNumber, "phone number must be in E.164 format")
+	ErrVerificationNotPending  = New(KindInvalid, CodeVerificationNotPending, "no pending phone verification")
+	ErrVerificationExpired     = New(KindInvalid, CodeVerificationExpired, "verification code expired")
+	ErrInvalidVerificationCode = New(KindInvalid, CodeInvalidVerificationCode, "invalid verification code")
+	ErrDeliveryFailed          = New(KindUpstream, CodeDeliveryFailed, "failed to send")
+	ErrDeviceNotFound          = New(KindNotFound, CodeDeviceNotFound, "device not found")
+
+	ErrJobNotFound = New(KindNotFound, CodeJobNotFound, "job not found")
+	ErrJobNotDead  = New(KindConflict, CodeJobNotDead, "only dead jobs can be retried")
+
+	ErrIPNotAllowed             = New(KindForbidden, CodeIPNotAllowed, "IP address is not allowed")
+	ErrIPAllowlistEntryNotFound = New(KindNotFound, CodeIPAllowlistEntryNotFound, "IP allowlist entry not found")
+	ErrAlreadyAllowlisted       = New(KindConflict, CodeAlreadyAllowlisted, "CIDR range is already allowlisted")
+	// ErrIPAllowlistLockout refuses allowlist changes that would shut out the admin making them
+	ErrIPAllowlistLockout = New(KindConflict, CodeIPAllowlistLockout, "change would block your own IP address")
+
+	// ErrTokenRevoked is returned for tokens signed out with logout or a password change
+	ErrTokenRevoked      = New(KindUnauthenticated, CodeTokenRevoked, "token has been revoked")
+	ErrIncorrectPassword = New(KindInvalid, CodeIncorrectPassword, "invalid current password")
+
+	ErrOAuthProviderNotFound = New(KindNotFound, CodeOAuthProviderNotFound, "sign in provider not found")
+	// ErrOAuthFailed covers callbacks that cannot be trusted or redeemed: a forged or expired session,
+	// a denied consent or a failed code exchange
+	ErrOAuthFailed = New(KindUnauthenticated, CodeOAuthFailed, "sign in with provider failed")
+	// ErrOAuthEmailUnverified refuses provider accounts whose email the provider has not verified, as
+	// they could claim an existing account
+	ErrOAuthEmailUnverified = New(KindForbidden, CodeOAuthEmailUnverified, "email is not verified by the provider")
+	// ErrOAuthUnavailable is returned when the endpoints of a generic provider cannot be discovered
+	ErrOAuthUnavailable = New(KindUpstream, CodeOAuthUnavailable, "sign in provider is unavailable")
+
+	ErrInvalidCredentials = New(KindUnauthenticated, CodeInvalidCredentials, "email or password is incorrect")
+	// ErrAccountLocked is returned while an account is locked after too many failed logins; correct
+	// passwords are refused as well until the cooldown ends or an admin unlocks it
+	ErrAccountLocked = New(KindForbidden, CodeAccountLocked, "account is locked after too many failed logins")
+	// ErrTooManyLoginAttempts throttles clients whose IP address failed too many logins recently
+	ErrTooManyLoginAttempts = New(KindRateLimited, CodeTooManyLoginAttempts, "too many failed login attempts")
+
+	// ErrWeakPassword is returned for passwords breaking the password policy; its message lists the rules
+	ErrWeakPassword     = New(KindInvalid, CodeWeakPassword, "password does not meet the password policy")
+	ErrPasswordBreached = New(KindInvalid, CodePasswordBreached, "password has appeared in a data breach, choose another one")
+	ErrPasswordReused   = New(KindInvalid, CodePasswordReused, "password was used recently, choose another one")
+
+	ErrSessionNotFound = New(KindNotFound, CodeSessionNotFound, "session not found")
+
+	// ErrImpersonationForbidden refuses impersonations of staff and system accounts, write access for
+	// support staff and actions impersonation tokens may never take
+	ErrImpersonationForbidden = New(KindForbidden, CodeImpersonationForbidden, "impersonation is not allowed")
+	// ErrImpersonationReadOnly refuses requests that change state made with a read-only impersonation token
+	ErrImpersonationReadOnly = New(KindForbidden, CodeImpersonationReadOnly, "impersonation token is read-only")
+
+	// ErrPINRequired is returned for debits above the PIN threshold sent without the user's transaction PIN
+	ErrPINRequired = New(KindForbidden, CodePINRequired, "transaction PIN is required")
+	ErrInvalidPIN  = New(KindForbidden, CodeInvalidPIN, "transaction PIN is incorrect")
+	// ErrPINLocked is returned while the PIN is locked after too many wrong attempts; resetting the PIN
+	// with the account password unlocks it
+	ErrPINLocked     = New(KindForbidden, CodePINLocked, "transaction PIN is locked after too many wrong attempts")
+	ErrPINAlreadySet = New(KindConflict, CodePINAlreadySet, "transaction PIN is already set")
+	ErrPINNotSet     = New(KindInvalid, CodePINNotSet, "no transaction PIN is set")
+
+	ErrChallengeNotFound = New(KindNotFound, CodeChallengeNotFound, "transfer challenge not found")
+	// ErrChallengeExpired is returned for challenges that expired, were confirmed already or had too
+	// many wrong codes; the transfer has to be started again
+	ErrChallengeExpired     = New(KindInvalid, CodeChallengeExpired, "transfer challenge expired")
+	ErrInvalidChallengeCode = New(KindInvalid, CodeInvalidChallengeCode, "invalid confirmation code")
+	// ErrStepUpPhoneRequired refuses transfers above the step-up threshold from users without a verified
+	// phone to send the confirmation code to
+	ErrStepUpPhoneRequired = New(KindForbidden, CodeStepUpPhoneRequired, "a verified phone number is required to confirm large transfers")
+
+	ErrLedgerAccountNotFound = New(KindNotFound, CodeLedgerAccountNotFound, "ledger account not found")
+	ErrJournalEntryNotFound  = New(KindNotFound, CodeJournalEntryNotFound, "journal entry not found")
+	// ErrUnbalancedJournalEntry refuses journal entries whose debits and credits differ or that have
+	// fewer than two legs
+	ErrUnbalancedJournalEntry = New(KindInvalid, CodeUnbalancedJournalEntry, "journal entry debits and credits do not balance")
+
+	ErrSuspenseItemNotFound = New(KindNotFound, CodeSuspenseItemNotFound, "suspense item not found")
+	// ErrSuspenseItemResolved signals that the funds already left the suspense account
+	ErrSuspenseItemResolved = New(KindConflict, CodeSuspenseItemResolved, "suspense item already resolved")
+
+	ErrSettlementBatchNotFound = New(KindNotFound, CodeSettlementBatchNotFound, "settlement batch not found")
+
+	ErrStatementNotFound     = New(KindNotFound, CodeStatementNotFound, "provider statement not found")
+	ErrStatementLineNotFound = New(KindNotFound, CodeStatementLineNotFound, "statement line not found")
+	// ErrDuplicateStatement refuses a statement file already imported for the provider
+	ErrDuplicateStatement      = New(KindConflict, CodeDuplicateStatement, "statement file already imported")
+	ErrStatementLineNotPending = New(KindConflict, CodeStatementLineNotPending, "statement line is not an open exception")
+
+	ErrAccountStatementNotFound = New(KindNotFound, CodeAccountStatementNotFound, "account statement not found")
+	ErrStatementsNotConfigured  = New(KindUnavailable, CodeStatementsNotConfigured, "statement generation is not configured")
+	// ErrInvalidDownloadLink refuses download links that were tampered with or have expired
+	ErrInvalidDownloadLink = New(KindForbidden, CodeInvalidDownloadLink, "download link is invalid or has expired")
+
+	ErrTransactionNotFound = New(KindNotFound, CodeTransactionNotFound, "transaction not found")
+
+	ErrQuoteNotFound = New(KindNotFound, CodeQuoteNotFound, "transfer quote not found")
+	// ErrQuoteExpired is returned for quotes that expired or were used already
+	ErrQuoteExpired = New(KindInvalid, CodeQuoteExpired, "transfer quote expired")
+	// ErrQuoteMismatch refuses a transfer whose recipient or amount differs from its quote
+	ErrQuoteMismatch = New(KindInvalid, CodeQuoteMismatch, "transfer does not match its quote")
+
+	ErrBudgetNotFound = New(KindNotFound, CodeBudgetNotFound, "budget not found")
+	// ErrBudgetExists refuses a second budget for a category the user already budgets
+	ErrBudgetExists = New(KindConflict, CodeBudgetExists, "a budget already exists for this category")
+
+	// ErrTenantNotFound is returned for unknown and deactivated tenants
+	ErrTenantNotFound = New(KindNotFound, CodeTenantNotFound, "tenant not found")
+	ErrTenantExists   = New(KindConflict, CodeTenantExists, "a tenant already uses this slug")
+
+	// ErrBusinessAccountNotFound is also returned to users who are not members of the account
+	ErrBusinessAccountNotFound = New(KindNotFound, CodeBusinessAccountNotFound, "business account not found")
+	ErrBusinessMemberExists    = New(KindConflict, CodeBusinessMemberExists, "user is already a member of the business account")
+	ErrBusinessMemberNotFound  = New(KindNotFound, CodeBusinessMemberNotFound, "business account member not found")
+	// ErrBusinessRoleForbidden refuses actions the member's role does not allow, and approvals of
+	// a member's own payment requests
+	ErrBusinessRoleForbidden = New(KindForbidden, CodeBusinessRoleForbidden, "your role on the business account does not allow this")
+	ErrApprovalNotFound      = New(KindNotFound, CodeApprovalNotFound, "payment approval not found")
+	ErrApprovalResolved      = New(KindConflict, CodeApprovalResolved, "payment approval was already decided")
+
+	// ErrEscrowNotFound is also returned to users who are neither the payer nor the payee
+	ErrEscrowNotFound  = New(KindNotFound, CodeEscrowNotFound, "escrow not found")
+	ErrEscrowSettled   = New(KindConflict, CodeEscrowSettled, "escrow was already released, cancelled or expired")
+	ErrEscrowPayerOnly = New(KindForbidden, CodeEscrowPayerOnly, "only the payer can release an escrow")
+
+	ErrSubscriptionPlanNotFound = New(KindNotFound, CodeSubscriptionPlanNotFound, "subscription plan not found")
+	ErrSubscriptionPlanArchived = New(KindConflict, CodeSubscriptionPlanArchived, "subscription plan no longer takes subscribers")
+	ErrSubscriptionMerchantOnly = New(KindForbidden, CodeSubscriptionMerchantOnly, "only the merchant of the plan can do this")
+	// ErrSubscriptionNotFound is also returned to users who are neither the subscriber nor the merchant
+	ErrSubscriptionNotFound = New(KindNotFound, CodeSubscriptionNotFound, "subscription not found")
+	// ErrSubscriptionExists refuses a second subscription to a plan the user did not cancel
+	ErrSubscriptionExists       = New(KindConflict, CodeSubscriptionExists, "you are already subscribed to this plan")
+	ErrSubscriptionCancelled    = New(KindConflict, CodeSubscriptionCancelled, "subscription was cancelled")
+	ErrSubscriptionNotSuspended = New(KindConflict, CodeSubscriptionNotSuspended, "only suspended subscriptions can be resumed")
+
+	// ErrWebhookSubscriptionNotFound is also returned to users who do not own the subscription
+	ErrWebhookSubscriptionNotFound = New(KindNotFound, CodeWebhookSubscriptionNotFound, "webhook subscription not found")
+	ErrWebhookSubscriptionDisabled = New(KindConflict, CodeWebhookSubscriptionDisabled, "webhook subscription was disabled")
+	ErrWebhookDeliveryNotFound     = New(KindNotFound, CodeWebhookDeliveryNotFound, "webhook delivery not found")
+	ErrWebhookDeliveryNotDead      = New(KindConflict, CodeWebhookDeliveryNotDead, "only dead webhook deliveries can be redelivered")
+
+	// ErrInvalidAvatar refuses profile pictures that are not PNG, JPEG, GIF or WebP images
+	ErrInvalidAvatar        = New(KindInvalid, CodeInvalidAvatar, "avatar must be a PNG, JPEG, GIF or WebP image")
+	ErrAvatarNotFound       = New(KindNotFound, CodeAvatarNotFound, "no avatar uploaded")
+	ErrAvatarsNotConfigured = New(KindUnavailable, CodeAvatarsNotConfigured, "avatar storage is not configured")
+
+	// ErrAccountDeleted refuses logins to accounts whose user asked for them to be erased
+	ErrAccountDeleted = New(KindForbidden, CodeAccountDeleted, "account is scheduled for deletion")
+	// ErrAccountHasBalance refuses to erase an account whose wallet still holds money; the user has to
+	// withdraw it first
+	ErrAccountHasBalance = New(KindConflict, CodeAccountHasBalance, "withdraw the wallet balance before deleting the account")
+
+	ErrReconciliationRunNotFound = New(KindNotFound, CodeReconciliationRunNotFound, "reconciliation run not found")
+
+	ErrReconciliationReportNotFound = New(KindNotFound, CodeReconciliationReportNotFound, "reconciliation report not found")
+	// ErrReconciliationReportResolved signals that operators already settled the issue of the report
+	ErrReconciliationReportResolved = New(KindConflict, CodeReconciliationReportResolved, "reconciliation report already resolved")
+	// ErrReconciliationReportNoIssue refuses to work a report whose balances matched
+	ErrReconciliationReportNoIssue = New(KindConflict, CodeReconciliationReportNoIssue, "reconciliation report has no issue to resolve")
+
+	ErrAdjustmentNotFound = New(KindNotFound, CodeAdjustmentNotFound, "balance adjustment not found")
+	// ErrAdjustmentDecided signals that another admin approved or rejected the adjustment first
+	ErrAdjustmentDecided = New(KindConflict, CodeAdjustmentDecided, "balance adjustment was already decided")
+	// ErrAdjustmentSelfApproval refuses decisions on an adjustment by the admin who requested it
+	ErrAdjustmentSelfApproval = New(KindForbidden, CodeAdjustmentSelfApproval, "a balance adjustment must be decided by another admin")
+
+	ErrTransactionPurposeNotFound = New(KindNotFound, CodeTransactionPurposeNotFound, "transaction purpose not found")
+	ErrTransactionPurposeExists   = New(KindConflict, CodeTransactionPurposeExists, "transaction purpose already exists")
+	// ErrTransactionPurposeInUse refuses deleting a purpose the service posts or transactions were recorded with
+	ErrTransactionPurposeInUse = New(KindConflict, CodeTransactionPurposeInUse, "transaction purpose is in use")
+
+	// ErrHandleNotFound signals that no user goes by a handle, or that they cannot be paid
+	ErrHandleNotFound = New(KindNotFound, CodeHandleNotFound, "handle not found")
+	ErrHandleTaken    = New(KindConflict, CodeHandleTaken, "handle is already taken")
+)
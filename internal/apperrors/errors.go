@@ -0,0 +1,130 @@
+package apperrors
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/limistah/wallet-service/internal/breaker"
+)
+
+// Kind classifies a domain error by who has to act on it, which decides its HTTP status
+type Kind int
+
+const (
+	KindInternal        Kind = iota // A fault of the service
+	KindInvalid                     // The request is malformed or breaks a business rule
+	KindUnauthenticated             // The caller is not signed in
+	KindForbidden                   // The caller may not perform the operation
+	KindNotFound                    // A resource the operation needs does not exist
+	KindConflict                    // The operation clashes with the current state of a resource
+	KindUpstream                    // A payment or delivery provider rejected or failed the operation
+	KindUnavailable                 // The operation cannot run right now and may succeed later
+	KindTooLarge                    // The request body exceeds the size limit
+	KindRateLimited                 // The caller made too many attempts and must wait
+)
+
+// Error is a failure clients are expected to handle, identified by a stable machine-readable code.
+// Errors with the same code match each other with errors.Is, so the sentinels in codes.go also match
+// errors created from them with Withf or Wrap
+type Error struct {
+	Kind    Kind
+	Code    string
+	Message string
+	Err     error // Cause, if any
+}
+
+// New creates a domain error
+func New(kind Kind, code, message string) *Error {
+	return &Error{Kind: kind, Code: code, Message: message}
+}
+
+func (e *Error) Error() string { return e.Message }
+
+func (e *Error) Unwrap() error { return e.Err }
+
+// Is reports whether target is a domain error with the same code
+func (e *Error) Is(target error) bool {
+	t, ok := target.(*Error)
+	return ok && t.Code == e.Code
+}
+
+// Withf returns an error with the same kind and code and a more specific message; a %w verb records
+// the cause
+func (e *Error) Withf(format string, args ...interface{}) *Error {
+	err := fmt.Errorf(format, args...)
+	return &Error{Kind: e.Kind, Code: e.Code, Message: err.Error(), Err: errors.Unwrap(err)}
+}
+
+// Wrap returns an error with the same kind and code caused by err, with the message of e as prefix
+func (e *Error) Wrap(err error) *Error {
+	return e.Withf("%s: %w", e.Message, err)
+}
+
+// From returns the outermost domain error in the chain of err, or nil when there is none
+func From(err error) *Error {
+	var domainErr *Error
+	if errors.As(err, &domainErr) {
+		return domainErr
+	}
+	return nil
+}
+
+// CodeOf returns the code of err, or an empty string for errors that are not domain errors
+func CodeOf(err error) string {
+	if errors.Is(err, breaker.ErrOpen) {
+		return CodeServiceUnavailable
+	}
+	if isBodyTooLarge(err) {
+		return CodeRequestTooLarge
+	}
+	if domainErr := From(err); domainErr != nil {
+		return domainErr.Code
+	}
+	return ""
+}
+
+// HTTPStatus maps err to the status code of the response reporting it. Calls refused by an open
+// circuit breaker are reported as 503 and bodies cut off at the size limit as 413 whatever wraps them;
+// errors that are not domain errors are 500
+func HTTPStatus(err error) int {
+	if errors.Is(err, breaker.ErrOpen) {
+		return http.StatusServiceUnavailable
+	}
+	if isBodyTooLarge(err) {
+		return http.StatusRequestEntityTooLarge
+	}
+	domainErr := From(err)
+	if domainErr == nil {
+		return http.StatusInternalServerError
+	}
+
+	switch domainErr.Kind {
+	case KindInvalid:
+		return http.StatusBadRequest
+	case KindUnauthenticated:
+		return http.StatusUnauthorized
+	case KindForbidden:
+		return http.StatusForbidden
+	case KindNotFound:
+		return http.StatusNotFound
+	case KindConflict:
+		return http.StatusConflict
+	case KindUpstream:
+		return http.StatusBadGateway
+	case KindUnavailable:
+		return http.StatusServiceUnavailable
+	case KindTooLarge:
+		return http.StatusRequestEntityTooLarge
+	case KindRateLimited:
+		return http.StatusTooManyRequests
+	default:
+		return http.StatusInternalServerError
+	}
+}
+
+// isBodyTooLarge reports whether err is ErrRequestTooLarge or a request body read past its limit
+func isBodyTooLarge(err error) bool {
+	var maxBytesErr *http.MaxBytesError
+	return errors.Is(err, ErrRequestTooLarge) || errors.As(err, &maxBytesErr)
+}
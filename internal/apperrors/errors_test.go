@@ -0,0 +1,74 @@
+package apperrors
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/limistah/wallet-service/internal/breaker"
+)
+
+func TestError_MatchesByCode(t *testing.T) {
+	err := ErrInsufficientFunds.Withf("insufficient funds: available=%.2f, requested=%.2f", 10.0, 25.0)
+
+	if !errors.Is(err, ErrInsufficientFunds) {
+		t.Error("Expected an error created with Withf to match its sentinel")
+	}
+	if errors.Is(err, ErrDuplicateReference) {
+		t.Error("Expected errors with different codes not to match")
+	}
+	if err.Error() != "insufficient funds: available=10.00, requested=25.00" {
+		t.Errorf("Expected the specific message, got %q", err.Error())
+	}
+
+	wrapped := fmt.Errorf("pre-transaction reconciliation failed: %w", ErrBalanceMismatch.Withf("wallet balance mismatch detected"))
+	if !errors.Is(wrapped, ErrBalanceMismatch) || CodeOf(wrapped) != CodeBalanceMismatch {
+		t.Errorf("Expected a wrapped domain error to keep its code, got %q", CodeOf(wrapped))
+	}
+}
+
+func TestError_WrapKeepsCause(t *testing.T) {
+	cause := errors.New("provider rejected the account")
+	err := ErrPayoutFailed.Wrap(cause)
+
+	if err.Error() != "payout failed: provider rejected the account" {
+		t.Errorf("Expected the cause to follow the message, got %q", err.Error())
+	}
+	if !errors.Is(err, cause) || !errors.Is(err, ErrPayoutFailed) {
+		t.Error("Expected the error to match both its cause and its sentinel")
+	}
+}
+
+func TestHTTPStatus(t *testing.T) {
+	tests := []struct {
+		name   string
+		err    error
+		status int
+		code   string
+	}{
+		{"invalid", ErrInvalidAmount, http.StatusBadRequest, CodeInvalidAmount},
+		{"unauthenticated", ErrUnauthenticated, http.StatusUnauthorized, CodeUnauthenticated},
+		{"forbidden", ErrFraudBlocked.Withf("transaction blocked by fraud rule velocity: too many debits"), http.StatusForbidden, CodeFraudBlocked},
+		{"not found", ErrWalletNotFound, http.StatusNotFound, CodeWalletNotFound},
+		{"conflict", ErrDuplicateReference, http.StatusConflict, CodeDuplicateReference},
+		{"upstream", ErrPaymentProviderFailed.Withf("failed to create payment intent: %w", errors.New("timeout")), http.StatusBadGateway, CodePaymentProviderFailed},
+		{"unavailable", ErrReconciliationFailed.Wrap(errors.New("connection reset")), http.StatusServiceUnavailable, CodeReconciliationFailed},
+		{"open circuit behind a provider failure", ErrPaymentProviderFailed.Withf("failed to create payment intent: %w", breaker.ErrOpen), http.StatusServiceUnavailable, CodeServiceUnavailable},
+		{"rate limited", ErrTooManyLoginAttempts, http.StatusTooManyRequests, CodeTooManyLoginAttempts},
+		{"too large", ErrRequestTooLarge, http.StatusRequestEntityTooLarge, CodeRequestTooLarge},
+		{"body cut off while binding", ErrValidation.Withf("invalid request data: %w", &http.MaxBytesError{Limit: 1024}), http.StatusRequestEntityTooLarge, CodeRequestTooLarge},
+		{"not a domain error", errors.New("database is down"), http.StatusInternalServerError, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if status := HTTPStatus(tt.err); status != tt.status {
+				t.Errorf("Expected status %d, got %d", tt.status, status)
+			}
+			if code := CodeOf(tt.err); code != tt.code {
+				t.Errorf("Expected code %q, got %q", tt.code, code)
+			}
+		})
+	}
+}
@@ -1,56 +1,173 @@
 package auth
 
 import (
+	"crypto/rand"
+	"encoding/hex"
 	"errors"
+	"sync"
 	"time"
 
 	"github.com/golang-jwt/jwt/v4"
 )
 
 type JWTService struct {
+	mu        sync.RWMutex
 	secretKey []byte
-	issuer    string
+	// previousKey still verifies the tokens signed before the secret was last rotated
+	previousKey []byte
+	issuer      string
+	// keys signs tokens with an asymmetric key when set; HS256 tokens are still accepted so those
+	// issued before the switch stay valid until they expire
+	keys *KeyStore
+}
+
+// JWTOption configures a JWTService
+type JWTOption func(*JWTService)
+
+// WithKeyStore signs tokens with the active key of the store (RS256 or EdDSA) instead of the shared
+// secret, so other services can verify them with the published JWKS
+func WithKeyStore(keys *KeyStore) JWTOption {
+	return func(j *JWTService) {
+		j.keys = keys
+	}
 }
 
 type Claims struct {
-	UserID uint   `json:"user_id"`
-	Email  string `json:"email"`
+	UserID uint `json:"user_id"`
+	// TenantID is the tenant of the user; requests made with the token only reach its data
+	TenantID uint   `json:"tenant_id,omitempty"`
+	Email    string `json:"email"`
+	Role     string `json:"role"`
+	// Sandbox routes every wallet operation of the token to the user's sandbox wallet
+	Sandbox bool `json:"sandbox,omitempty"`
+	// Impersonator is set on tokens an admin minted to act as the user
+	Impersonator *Impersonator `json:"impersonator,omitempty"`
 	jwt.RegisteredClaims
 }
 
-func NewJWTService(secretKey, issuer string) *JWTService {
-	return &JWTService{
+// Impersonator identifies the admin acting as the user of an impersonation token
+type Impersonator struct {
+	ID     uint   `json:"id"`
+	Email  string `json:"email"`
+	Role   string `json:"role"`
+	Reason string `json:"reason"`
+	// ReadOnly restricts the token to requests that change nothing
+	ReadOnly bool `json:"read_only"`
+}
+
+func NewJWTService(secretKey, issuer string, opts ...JWTOption) *JWTService {
+	j := &JWTService{
 		secretKey: []byte(secretKey),
 		issuer:    issuer,
 	}
+	for _, opt := range opts {
+		opt(j)
+	}
+	return j
+}
+
+// JWKS returns the public keys tokens are verified with; it is empty when tokens are signed with
+// the shared secret
+func (j *JWTService) JWKS() JWKS {
+	if j.keys == nil {
+		return JWKS{Keys: []JWK{}}
+	}
+	return j.keys.JWKS()
 }
 
-// GenerateToken generates a new JWT token for a user
-func (j *JWTService) GenerateToken(userID uint, email string) (string, error) {
+// GenerateToken generates a new JWT token for a user. Every token gets a unique ID (jti) so it can be
+// revoked on its own
+func (j *JWTService) GenerateToken(userID uint, email, role string, sandbox bool) (string, error) {
+	token, _, err := j.IssueToken(userID, 0, email, role, sandbox)
+	return token, err
+}
+
+// IssueToken generates a token for a user of the tenant like GenerateToken and returns its claims
+// along with it
+func (j *JWTService) IssueToken(userID, tenantID uint, email, role string, sandbox bool) (string, *Claims, error) {
+	return j.issue(&Claims{
+		UserID:   userID,
+		TenantID: tenantID,
+		Email:    email,
+		Role:     role,
+		Sandbox:  sandbox,
+	}, 24*time.Hour) // Token expires in 24 hours
+}
+
+// IssueImpersonationToken generates a token letting an admin act as a user for ttl. The impersonator
+// is part of the claims, so every request made with the token can be told apart and audited
+func (j *JWTService) IssueImpersonationToken(userID, tenantID uint, email, role string, impersonator Impersonator, ttl time.Duration) (string, *Claims, error) {
+	return j.issue(&Claims{
+		UserID:       userID,
+		TenantID:     tenantID,
+		Email:        email,
+		Role:         role,
+		Impersonator: &impersonator,
+	}, ttl)
+}
+
+// issue signs claims with a new token ID, valid from now for ttl
+func (j *JWTService) issue(claims *Claims, ttl time.Duration) (string, *Claims, error) {
+	tokenID, err := newTokenID()
+	if err != nil {
+		return "", nil, err
+	}
+
 	now := time.Now()
-	claims := &Claims{
-		UserID: userID,
-		Email:  email,
-		RegisteredClaims: jwt.RegisteredClaims{
-			ExpiresAt: jwt.NewNumericDate(now.Add(24 * time.Hour)), // Token expires in 24 hours
-			IssuedAt:  jwt.NewNumericDate(now),
-			NotBefore: jwt.NewNumericDate(now),
-			Issuer:    j.issuer,
-		},
+	claims.RegisteredClaims = jwt.RegisteredClaims{
+		ExpiresAt: jwt.NewNumericDate(now.Add(ttl)),
+		IssuedAt:  jwt.NewNumericDate(now),
+		NotBefore: jwt.NewNumericDate(now),
+		Issuer:    j.issuer,
+		ID:        tokenID,
+	}
+
+	token, err := j.sign(claims)
+	if err != nil {
+		return "", nil, err
 	}
+	return token, claims, nil
+}
+
+// RotateSecret signs new tokens with secret; tokens signed with the replaced secret stay valid
+// until they expire or the secret is rotated again
+func (j *JWTService) RotateSecret(secret string) {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	if secret == string(j.secretKey) {
+		return
+	}
+	j.previousKey = j.secretKey
+	j.secretKey = []byte(secret)
+}
+
+// secrets returns the shared secret and the one it replaced, if any
+func (j *JWTService) secrets() (current, previous []byte) {
+	j.mu.RLock()
+	defer j.mu.RUnlock()
+	return j.secretKey, j.previousKey
+}
 
-	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
-	return token.SignedString(j.secretKey)
+// sign signs claims with the active key of the key store, or the shared secret without one
+func (j *JWTService) sign(claims *Claims) (string, error) {
+	if j.keys == nil {
+		secret, _ := j.secrets()
+		return jwt.NewWithClaims(jwt.SigningMethodHS256, claims).SignedString(secret)
+	}
+	key := j.keys.Active()
+	token := jwt.NewWithClaims(key.Method, claims)
+	token.Header["kid"] = key.ID
+	return token.SignedString(key.private)
 }
 
 // ValidateToken validates and parses a JWT token
 func (j *JWTService) ValidateToken(tokenString string) (*Claims, error) {
-	token, err := jwt.ParseWithClaims(tokenString, &Claims{}, func(token *jwt.Token) (interface{}, error) {
-		if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
-			return nil, errors.New("unexpected signing method")
-		}
-		return j.secretKey, nil
-	})
+	token, err := jwt.ParseWithClaims(tokenString, &Claims{}, j.verificationKey)
+	if _, previous := j.secrets(); err != nil && previous != nil && isHMAC(tokenString) {
+		token, err = jwt.ParseWithClaims(tokenString, &Claims{}, func(*jwt.Token) (interface{}, error) {
+			return previous, nil
+		})
+	}
 
 	if err != nil {
 		return nil, err
@@ -63,6 +180,38 @@ func (j *JWTService) ValidateToken(tokenString string) (*Claims, error) {
 	return nil, errors.New("invalid token")
 }
 
+// verificationKey returns the key a token is verified with: the shared secret for HS256 tokens, or
+// the public key named by the kid header for asymmetric ones
+func (j *JWTService) verificationKey(token *jwt.Token) (interface{}, error) {
+	switch token.Method.(type) {
+	case *jwt.SigningMethodHMAC:
+		secret, _ := j.secrets()
+		return secret, nil
+	case *jwt.SigningMethodRSA, *jwt.SigningMethodEd25519:
+		if j.keys == nil {
+			return nil, errors.New("unexpected signing method")
+		}
+		kid, _ := token.Header["kid"].(string)
+		key, ok := j.keys.Lookup(kid)
+		if !ok || key.Method.Alg() != token.Method.Alg() {
+			return nil, errors.New("unknown signing key")
+		}
+		return key.Public(), nil
+	default:
+		return nil, errors.New("unexpected signing method")
+	}
+}
+
+// isHMAC reports whether a token is signed with a shared secret
+func isHMAC(tokenString string) bool {
+	token, _, err := jwt.NewParser().ParseUnverified(tokenString, &Claims{})
+	if err != nil {
+		return false
+	}
+	_, ok := token.Method.(*jwt.SigningMethodHMAC)
+	return ok
+}
+
 // RefreshToken generates a new token from an existing valid token
 func (j *JWTService) RefreshToken(tokenString string) (string, error) {
 	claims, err := j.ValidateToken(tokenString)
@@ -71,5 +220,14 @@ func (j *JWTService) RefreshToken(tokenString string) (string, error) {
 	}
 
 	// Generate new token with same user data but extended expiry
-	return j.GenerateToken(claims.UserID, claims.Email)
+	return j.GenerateToken(claims.UserID, claims.Email, claims.Role, claims.Sandbox)
+}
+
+// newTokenID returns a random 128-bit token ID encoded as hex
+func newTokenID() (string, error) {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(b), nil
 }
@@ -0,0 +1,166 @@
+package auth
+
+import (
+	"crypto"
+	"crypto/ed25519"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"math/big"
+	"os"
+	"strings"
+
+	"github.com/golang-jwt/jwt/v4"
+)
+
+// SigningKey is an asymmetric key tokens are signed with. Its ID is sent as the kid header so
+// verifiers can pick the matching public key from the JWKS
+type SigningKey struct {
+	ID      string
+	Method  jwt.SigningMethod
+	private crypto.Signer
+}
+
+// ParseSigningKey reads an RSA (RS256) or Ed25519 (EdDSA) private key from PEM, in PKCS#8 or, for
+// RSA, PKCS#1 form
+func ParseSigningKey(id string, pemBytes []byte) (*SigningKey, error) {
+	if id == "" {
+		return nil, errors.New("signing key id is required")
+	}
+	block, _ := pem.Decode(pemBytes)
+	if block == nil {
+		return nil, fmt.Errorf("signing key %q is not PEM encoded", id)
+	}
+
+	var parsed any
+	var err error
+	if block.Type == "RSA PRIVATE KEY" {
+		parsed, err = x509.ParsePKCS1PrivateKey(block.Bytes)
+	} else {
+		parsed, err = x509.ParsePKCS8PrivateKey(block.Bytes)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("invalid signing key %q: %w", id, err)
+	}
+
+	switch key := parsed.(type) {
+	case *rsa.PrivateKey:
+		if key.N.BitLen() < 2048 {
+			return nil, fmt.Errorf("signing key %q: RSA keys must be at least 2048 bits", id)
+		}
+		return &SigningKey{ID: id, Method: jwt.SigningMethodRS256, private: key}, nil
+	case ed25519.PrivateKey:
+		return &SigningKey{ID: id, Method: jwt.SigningMethodEdDSA, private: key}, nil
+	default:
+		return nil, fmt.Errorf("signing key %q: only RSA and Ed25519 keys are supported", id)
+	}
+}
+
+// LoadSigningKeys reads keys written as ID=PATH, for example "2024-06=/keys/current.pem"
+func LoadSigningKeys(entries []string) ([]*SigningKey, error) {
+	keys := make([]*SigningKey, 0, len(entries))
+	for _, entry := range entries {
+		id, path, ok := strings.Cut(strings.TrimSpace(entry), "=")
+		if !ok || id == "" || path == "" {
+			return nil, fmt.Errorf("invalid signing key %q: use ID=PATH", entry)
+		}
+		pemBytes, err := os.ReadFile(path)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read signing key %q: %w", id, err)
+		}
+		key, err := ParseSigningKey(id, pemBytes)
+		if err != nil {
+			return nil, err
+		}
+		keys = append(keys, key)
+	}
+	return keys, nil
+}
+
+// Public returns the public half of the key
+func (k *SigningKey) Public() crypto.PublicKey {
+	return k.private.Public()
+}
+
+// KeyStore holds the key new tokens are signed with and the keys it replaced. Retired keys still
+// verify the tokens they signed and stay in the JWKS until those tokens expire, so rotating is a
+// matter of putting the new key first and dropping the oldest once its tokens are gone
+type KeyStore struct {
+	active *SigningKey
+	keys   map[string]*SigningKey
+	order  []string
+}
+
+// NewKeyStore creates a key store that signs with the first key and verifies with all of them
+func NewKeyStore(keys ...*SigningKey) (*KeyStore, error) {
+	if len(keys) == 0 {
+		return nil, errors.New("at least one signing key is required")
+	}
+	s := &KeyStore{active: keys[0], keys: make(map[string]*SigningKey, len(keys))}
+	for _, key := range keys {
+		if _, exists := s.keys[key.ID]; exists {
+			return nil, fmt.Errorf("duplicate signing key id %q", key.ID)
+		}
+		s.keys[key.ID] = key
+		s.order = append(s.order, key.ID)
+	}
+	return s, nil
+}
+
+// Active returns the key new tokens are signed with
+func (s *KeyStore) Active() *SigningKey {
+	return s.active
+}
+
+// Lookup returns the key with the given ID
+func (s *KeyStore) Lookup(id string) (*SigningKey, bool) {
+	key, ok := s.keys[id]
+	return key, ok
+}
+
+// JWK is a public key in JSON Web Key form (RFC 7517)
+type JWK struct {
+	KeyType   string `json:"kty"`
+	KeyID     string `json:"kid"`
+	Use       string `json:"use"`
+	Algorithm string `json:"alg"`
+	// RSA keys
+	Modulus  string `json:"n,omitempty"`
+	Exponent string `json:"e,omitempty"`
+	// Ed25519 keys
+	Curve string `json:"crv,omitempty"`
+	X     string `json:"x,omitempty"`
+}
+
+// JWKS is the public key set published at /.well-known/jwks.json
+type JWKS struct {
+	Keys []JWK `json:"keys"`
+}
+
+// JWKS returns the public keys of the store, active key first
+func (s *KeyStore) JWKS() JWKS {
+	set := JWKS{Keys: make([]JWK, 0, len(s.order))}
+	for _, id := range s.order {
+		set.Keys = append(set.Keys, s.keys[id].JWK())
+	}
+	return set
+}
+
+// JWK returns the public key in JSON Web Key form
+func (k *SigningKey) JWK() JWK {
+	jwk := JWK{KeyID: k.ID, Use: "sig", Algorithm: k.Method.Alg()}
+	switch public := k.Public().(type) {
+	case *rsa.PublicKey:
+		jwk.KeyType = "RSA"
+		jwk.Modulus = base64.RawURLEncoding.EncodeToString(public.N.Bytes())
+		jwk.Exponent = base64.RawURLEncoding.EncodeToString(big.NewInt(int64(public.E)).Bytes())
+	case ed25519.PublicKey:
+		jwk.KeyType = "OKP"
+		jwk.Curve = "Ed25519"
+		jwk.X = base64.RawURLEncoding.EncodeToString(public)
+	}
+	return jwk
+}
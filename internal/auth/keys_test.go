@@ -0,0 +1,127 @@
+package auth
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/pem"
+	"testing"
+)
+
+func rsaKeyPEM(t *testing.T) []byte {
+	t.Helper()
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatalf("Failed to generate RSA key: %v", err)
+	}
+	return pem.EncodeToMemory(&pem.Block{Type: "RSA PRIVATE KEY", Bytes: x509.MarshalPKCS1PrivateKey(key)})
+}
+
+func ed25519KeyPEM(t *testing.T) []byte {
+	t.Helper()
+	_, key, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatalf("Failed to generate Ed25519 key: %v", err)
+	}
+	der, err := x509.MarshalPKCS8PrivateKey(key)
+	if err != nil {
+		t.Fatalf("Failed to encode Ed25519 key: %v", err)
+	}
+	return pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: der})
+}
+
+func TestJWTService_SignsWithActiveKeyAndVerifiesRotatedKeys(t *testing.T) {
+	oldKey, err := ParseSigningKey("2024-01", rsaKeyPEM(t))
+	if err != nil {
+		t.Fatalf("Unexpected error parsing RSA key: %v", err)
+	}
+	newKey, err := ParseSigningKey("2024-06", ed25519KeyPEM(t))
+	if err != nil {
+		t.Fatalf("Unexpected error parsing Ed25519 key: %v", err)
+	}
+
+	oldStore, _ := NewKeyStore(oldKey)
+	oldToken, err := NewJWTService("secret", "wallet-service", WithKeyStore(oldStore)).GenerateToken(1, "a@example.com", "user", false)
+	if err != nil {
+		t.Fatalf("Unexpected error signing with RS256: %v", err)
+	}
+	hmacToken, _ := NewJWTService("secret", "wallet-service").GenerateToken(2, "b@example.com", "user", false)
+
+	// Rotate: the Ed25519 key signs from now on, the RSA key only verifies
+	store, err := NewKeyStore(newKey, oldKey)
+	if err != nil {
+		t.Fatalf("Unexpected error creating key store: %v", err)
+	}
+	service := NewJWTService("secret", "wallet-service", WithKeyStore(store))
+	newToken, err := service.GenerateToken(3, "c@example.com", "user", false)
+	if err != nil {
+		t.Fatalf("Unexpected error signing with EdDSA: %v", err)
+	}
+
+	for userID, token := range map[uint]string{1: oldToken, 2: hmacToken, 3: newToken} {
+		claims, err := service.ValidateToken(token)
+		if err != nil {
+			t.Errorf("Expected the token of user %d to verify, got: %v", userID, err)
+		} else if claims.UserID != userID {
+			t.Errorf("Expected user %d, got %d", userID, claims.UserID)
+		}
+	}
+
+	// A token signed with a key the store does not know is refused
+	stranger, _ := ParseSigningKey("2024-01", rsaKeyPEM(t))
+	strangerStore, _ := NewKeyStore(stranger)
+	forged, _ := NewJWTService("secret", "wallet-service", WithKeyStore(strangerStore)).GenerateToken(1, "a@example.com", "admin", false)
+	if _, err := service.ValidateToken(forged); err == nil {
+		t.Error("Expected a token signed with an unknown key to be refused")
+	}
+
+	jwks := service.JWKS()
+	if len(jwks.Keys) != 2 {
+		t.Fatalf("Expected both keys in the JWKS, got %d", len(jwks.Keys))
+	}
+	if jwk := jwks.Keys[0]; jwk.KeyID != "2024-06" || jwk.KeyType != "OKP" || jwk.Algorithm != "EdDSA" || jwk.X == "" {
+		t.Errorf("Expected the active Ed25519 key first, got: %+v", jwk)
+	}
+	if jwk := jwks.Keys[1]; jwk.KeyType != "RSA" || jwk.Algorithm != "RS256" || jwk.Modulus == "" || jwk.Exponent != "AQAB" {
+		t.Errorf("Expected the retired RSA key, got: %+v", jwk)
+	}
+}
+
+func TestParseSigningKey_Rejects(t *testing.T) {
+	if _, err := ParseSigningKey("", ed25519KeyPEM(t)); err == nil {
+		t.Error("Expected a key without an id to be rejected")
+	}
+	if _, err := ParseSigningKey("k1", []byte("not a key")); err == nil {
+		t.Error("Expected a value that is not PEM to be rejected")
+	}
+	key, _ := ParseSigningKey("k1", ed25519KeyPEM(t))
+	if _, err := NewKeyStore(key, key); err == nil {
+		t.Error("Expected duplicate key ids to be rejected")
+	}
+}
+
+func TestJWTService_RotateSecret(t *testing.T) {
+	service := NewJWTService("first-secret", "wallet-service")
+	before, err := service.GenerateToken(1, "user@example.com", "user", false)
+	if err != nil {
+		t.Fatalf("Failed to generate token: %v", err)
+	}
+
+	service.RotateSecret("second-secret")
+	after, err := service.GenerateToken(1, "user@example.com", "user", false)
+	if err != nil {
+		t.Fatalf("Failed to generate token: %v", err)
+	}
+	if _, err := NewJWTService("second-secret", "wallet-service").ValidateToken(after); err != nil {
+		t.Errorf("Expected new tokens to be signed with the rotated secret, got %v", err)
+	}
+	if _, err := service.ValidateToken(before); err != nil {
+		t.Errorf("Expected tokens signed before the rotation to stay valid, got %v", err)
+	}
+
+	service.RotateSecret("third-secret")
+	if _, err := service.ValidateToken(before); err == nil {
+		t.Error("Expected tokens signed two secrets ago to be refused")
+	}
+}
@@ -0,0 +1,216 @@
+package breaker
+
+import (
+	"errors"
+	"sort"
+	"sync"
+	"time"
+)
+
+// ErrOpen is returned without calling the dependency while its circuit is open
+var ErrOpen = errors.New("circuit breaker is open")
+
+// State is the position of a circuit
+type State string
+
+const (
+	StateClosed   State = "CLOSED"    // Calls go through
+	StateOpen     State = "OPEN"      // Calls fail fast with ErrOpen until OpenTimeout passes
+	StateHalfOpen State = "HALF_OPEN" // A few probe calls decide whether to close or open again
+)
+
+// Config controls when a circuit opens and how it recovers
+type Config struct {
+	// FailureThreshold is how many consecutive failures open the circuit
+	FailureThreshold int
+	// OpenTimeout is how long the circuit stays open before probing the dependency again
+	OpenTimeout time.Duration
+	// HalfOpenProbes is how many probe calls may run at once while half-open; that many consecutive
+	// successes close the circuit and any failure opens it again
+	HalfOpenProbes int
+}
+
+// Stats are the counters of a circuit since it was created
+type Stats struct {
+	Name                string
+	State               State
+	ConsecutiveFailures int
+	Successes           int64
+	Failures            int64
+	Rejections          int64 // Calls refused with ErrOpen
+	Opens               int64
+	OpenedAt            *time.Time // Last time the circuit opened
+}
+
+// Breaker stops calling a dependency after repeated failures so callers fail fast instead of
+// waiting on timeouts, then lets a few probe calls through to detect recovery
+type Breaker struct {
+	name   string
+	config Config
+	now    func() time.Time
+
+	mu                  sync.Mutex
+	state               State
+	consecutiveFailures int
+	probesInFlight      int
+	probeSuccesses      int
+	openedAt            time.Time
+	stats               Stats
+}
+
+// New creates a closed circuit
+func New(name string, config Config) *Breaker {
+	if config.FailureThreshold <= 0 {
+		config.FailureThreshold = 1
+	}
+	if config.HalfOpenProbes <= 0 {
+		config.HalfOpenProbes = 1
+	}
+	return &Breaker{
+		name:   name,
+		config: config,
+		now:    time.Now,
+		state:  StateClosed,
+	}
+}
+
+// Name returns the dependency the circuit protects
+func (b *Breaker) Name() string {
+	return b.name
+}
+
+// Execute calls fn unless the circuit is open; an error from fn counts as a failure of the dependency
+func (b *Breaker) Execute(fn func() error) error {
+	probe, err := b.allow()
+	if err != nil {
+		return err
+	}
+
+	err = fn()
+	b.record(probe, err == nil)
+	return err
+}
+
+// allow decides whether a call may go through and whether it is a half-open probe
+func (b *Breaker) allow() (bool, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.state == StateOpen && !b.now().Before(b.openedAt.Add(b.config.OpenTimeout)) {
+		b.state = StateHalfOpen
+		b.probesInFlight = 0
+		b.probeSuccesses = 0
+	}
+
+	switch b.state {
+	case StateOpen:
+		b.stats.Rejections++
+		return false, ErrOpen
+	case StateHalfOpen:
+		if b.probesInFlight >= b.config.HalfOpenProbes {
+			b.stats.Rejections++
+			return false, ErrOpen
+		}
+		b.probesInFlight++
+		return true, nil
+	default:
+		return false, nil
+	}
+}
+
+func (b *Breaker) record(probe, success bool) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if probe {
+		b.probesInFlight--
+	}
+
+	if success {
+		b.stats.Successes++
+		b.consecutiveFailures = 0
+		if probe && b.state == StateHalfOpen {
+			b.probeSuccesses++
+			if b.probeSuccesses >= b.config.HalfOpenProbes {
+				b.state = StateClosed
+			}
+		}
+		return
+	}
+
+	b.stats.Failures++
+	b.consecutiveFailures++
+	// A failed probe reopens right away; a call that started before the circuit opened changes nothing
+	if (probe && b.state == StateHalfOpen) ||
+		(b.state == StateClosed && b.consecutiveFailures >= b.config.FailureThreshold) {
+		b.state = StateOpen
+		b.openedAt = b.now()
+		b.stats.Opens++
+	}
+}
+
+// Stats returns a snapshot of the counters of the circuit
+func (b *Breaker) Stats() Stats {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	stats := b.stats
+	stats.Name = b.name
+	stats.State = b.state
+	if b.state == StateOpen && !b.now().Before(b.openedAt.Add(b.config.OpenTimeout)) {
+		// The next call will probe
+		stats.State = StateHalfOpen
+	}
+	stats.ConsecutiveFailures = b.consecutiveFailures
+	if !b.openedAt.IsZero() {
+		openedAt := b.openedAt
+		stats.OpenedAt = &openedAt
+	}
+	return stats
+}
+
+// Registry creates the circuits of a process with a shared configuration and reports on them
+type Registry struct {
+	config Config
+
+	mu       sync.Mutex
+	breakers map[string]*Breaker
+}
+
+// NewRegistry creates a registry whose circuits use config
+func NewRegistry(config Config) *Registry {
+	return &Registry{
+		config:   config,
+		breakers: make(map[string]*Breaker),
+	}
+}
+
+// Get returns the circuit for a dependency, creating it on first use
+func (r *Registry) Get(name string) *Breaker {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if b, ok := r.breakers[name]; ok {
+		return b
+	}
+	b := New(name, r.config)
+	r.breakers[name] = b
+	return b
+}
+
+// Stats returns a snapshot of every circuit ordered by name
+func (r *Registry) Stats() []Stats {
+	r.mu.Lock()
+	breakers := make([]*Breaker, 0, len(r.breakers))
+	for _, b := range r.breakers {
+		breakers = append(breakers, b)
+	}
+	r.mu.Unlock()
+
+	stats := make([]Stats, len(breakers))
+	for i, b := range breakers {
+		stats[i] = b.Stats()
+	}
+	sort.Slice(stats, func(i, j int) bool { return stats[i].Name < stats[j].Name })
+	return stats
+}
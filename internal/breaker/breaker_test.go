@@ -0,0 +1,101 @@
+package breaker
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+var errUnavailable = errors.New("provider unavailable")
+
+func newTestBreaker(clock *time.Time) *Breaker {
+	b := New("paystack", Config{FailureThreshold: 3, OpenTimeout: 30 * time.Second, HalfOpenProbes: 1})
+	b.now = func() time.Time { return *clock }
+	return b
+}
+
+func fail() error    { return errUnavailable }
+func succeed() error { return nil }
+
+func TestBreaker_OpensAfterConsecutiveFailures(t *testing.T) {
+	clock := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	b := newTestBreaker(&clock)
+
+	b.Execute(fail)
+	b.Execute(fail)
+	b.Execute(succeed) // Resets the streak
+	b.Execute(fail)
+	b.Execute(fail)
+	if b.Stats().State != StateClosed {
+		t.Fatal("Expected the circuit to stay closed below the threshold")
+	}
+
+	b.Execute(fail)
+	if b.Stats().State != StateOpen {
+		t.Fatal("Expected the circuit to open after 3 consecutive failures")
+	}
+
+	called := false
+	err := b.Execute(func() error {
+		called = true
+		return nil
+	})
+	if err != ErrOpen || called {
+		t.Fatalf("Expected an open circuit to fail fast, got %v (called: %v)", err, called)
+	}
+
+	stats := b.Stats()
+	if stats.Failures != 5 || stats.Successes != 1 || stats.Rejections != 1 || stats.Opens != 1 {
+		t.Errorf("Unexpected stats: %+v", stats)
+	}
+}
+
+func TestBreaker_HalfOpenProbes(t *testing.T) {
+	clock := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	b := newTestBreaker(&clock)
+	for i := 0; i < 3; i++ {
+		b.Execute(fail)
+	}
+
+	clock = clock.Add(30 * time.Second)
+	if b.Stats().State != StateHalfOpen {
+		t.Fatalf("Expected the circuit to be ready to probe, got %s", b.Stats().State)
+	}
+
+	// A failed probe opens the circuit for another timeout
+	if err := b.Execute(fail); err != errUnavailable {
+		t.Fatalf("Expected the probe to reach the dependency, got %v", err)
+	}
+	if err := b.Execute(succeed); err != ErrOpen {
+		t.Fatalf("Expected the circuit to reopen after a failed probe, got %v", err)
+	}
+
+	clock = clock.Add(30 * time.Second)
+	// Only one probe runs at a time
+	err := b.Execute(func() error {
+		if err := b.Execute(succeed); err != ErrOpen {
+			t.Errorf("Expected a second concurrent probe to be rejected, got %v", err)
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("Expected the probe to succeed, got %v", err)
+	}
+	if b.Stats().State != StateClosed {
+		t.Errorf("Expected a successful probe to close the circuit, got %s", b.Stats().State)
+	}
+}
+
+func TestRegistry_SharesCircuitsByName(t *testing.T) {
+	registry := NewRegistry(Config{FailureThreshold: 1, OpenTimeout: time.Minute})
+	registry.Get("twilio").Execute(fail)
+
+	if registry.Get("twilio").Execute(succeed) != ErrOpen {
+		t.Error("Expected the same circuit to be returned for a name")
+	}
+
+	stats := registry.Stats()
+	if len(stats) != 1 || stats[0].Name != "twilio" || stats[0].State != StateOpen {
+		t.Errorf("Unexpected stats: %+v", stats)
+	}
+}
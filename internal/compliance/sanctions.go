@@ -0,0 +1,152 @@
+package compliance
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+	"unicode"
+)
+
+// SanctionsSubject is a person screened against a sanctions watchlist
+type SanctionsSubject struct {
+	Name  string
+	Email string
+}
+
+// SanctionsMatch is the watchlist entry a subject matched
+type SanctionsMatch struct {
+	List    string
+	EntryID string
+	Name    string
+}
+
+// SanctionsChecker screens people against a sanctions watchlist, typically through an external provider
+type SanctionsChecker interface {
+	Name() string
+	// Check returns the matching watchlist entry, or nil when the subject is clear
+	Check(subject SanctionsSubject) (*SanctionsMatch, error)
+}
+
+// normalizeName lowercases a name and sorts its words so "DOE, John" and "john doe" compare equal
+func normalizeName(name string) string {
+	words := strings.FieldsFunc(strings.ToLower(name), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+	sort.Strings(words)
+	return strings.Join(words, " ")
+}
+
+// WatchlistChecker matches names against a local watchlist, ignoring case, punctuation and word order
+type WatchlistChecker struct {
+	list    string
+	entries map[string]SanctionsMatch
+}
+
+// NewWatchlistChecker creates a checker for the given names; entry IDs are their positions in the list
+func NewWatchlistChecker(list string, names []string) *WatchlistChecker {
+	c := &WatchlistChecker{list: list, entries: make(map[string]SanctionsMatch)}
+	for i, name := range names {
+		key := normalizeName(name)
+		if key == "" {
+			continue
+		}
+		if _, exists := c.entries[key]; !exists {
+			c.entries[key] = SanctionsMatch{List: list, EntryID: strconv.Itoa(i + 1), Name: strings.TrimSpace(name)}
+		}
+	}
+	return c
+}
+
+// LoadWatchlist reads a watchlist file with one name per line; blank lines and lines starting with #
+// are skipped, and entries are numbered by line
+func LoadWatchlist(path string) (*WatchlistChecker, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open sanctions watchlist: %w", err)
+	}
+	defer file.Close()
+
+	var names []string
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if strings.HasPrefix(line, "#") {
+			line = ""
+		}
+		names = append(names, line)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read sanctions watchlist: %w", err)
+	}
+
+	return NewWatchlistChecker(path, names), nil
+}
+
+func (c *WatchlistChecker) Name() string { return "watchlist" }
+
+func (c *WatchlistChecker) Check(subject SanctionsSubject) (*SanctionsMatch, error) {
+	if match, ok := c.entries[normalizeName(subject.Name)]; ok {
+		return &match, nil
+	}
+	return nil, nil
+}
+
+type cachedResult struct {
+	match     *SanctionsMatch
+	expiresAt time.Time
+}
+
+// CachedSanctionsChecker remembers the results of another checker for a while so repeat screenings
+// of the same person do not reach the provider; failed checks are not cached
+type CachedSanctionsChecker struct {
+	checker SanctionsChecker
+	ttl     time.Duration
+	now     func() time.Time
+
+	mu      sync.Mutex
+	results map[string]cachedResult
+}
+
+// NewCachedSanctionsChecker caches the results of checker for ttl
+func NewCachedSanctionsChecker(checker SanctionsChecker, ttl time.Duration) *CachedSanctionsChecker {
+	return &CachedSanctionsChecker{
+		checker: checker,
+		ttl:     ttl,
+		now:     time.Now,
+		results: make(map[string]cachedResult),
+	}
+}
+
+func (c *CachedSanctionsChecker) Name() string { return c.checker.Name() }
+
+func (c *CachedSanctionsChecker) Check(subject SanctionsSubject) (*SanctionsMatch, error) {
+	key := normalizeName(subject.Name) + "|" + strings.ToLower(strings.TrimSpace(subject.Email))
+	now := c.now()
+
+	c.mu.Lock()
+	cached, ok := c.results[key]
+	if ok && now.After(cached.expiresAt) {
+		delete(c.results, key)
+		ok = false
+	}
+	c.mu.Unlock()
+	if ok {
+		return cached.match, nil
+	}
+
+	match, err := c.checker.Check(subject)
+	if err != nil {
+		return nil, err
+	}
+
+	c.mu.Lock()
+	c.results[key] = cachedResult{match: match, expiresAt: now.Add(c.ttl)}
+	c.mu.Unlock()
+
+	return match, nil
+}
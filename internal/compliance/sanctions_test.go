@@ -0,0 +1,91 @@
+package compliance
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+// countingChecker counts the checks that reach it
+type countingChecker struct {
+	SanctionsChecker
+	calls int
+}
+
+func (c *countingChecker) Check(subject SanctionsSubject) (*SanctionsMatch, error) {
+	c.calls++
+	return c.SanctionsChecker.Check(subject)
+}
+
+func TestWatchlistChecker_MatchesNormalizedNames(t *testing.T) {
+	checker := NewWatchlistChecker("test", []string{"", "DOE, John", "Jane Roe"})
+
+	tests := []struct {
+		name    string
+		entryID string
+	}{
+		{"john doe", "2"},
+		{"  Doe   JOHN ", "2"},
+		{"Jane-Roe", "3"},
+		{"John Smith", ""},
+		{"", ""},
+	}
+	for _, tt := range tests {
+		match, err := checker.Check(SanctionsSubject{Name: tt.name})
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		if tt.entryID == "" && match != nil {
+			t.Errorf("Expected %q to be clear, got %+v", tt.name, match)
+		}
+		if tt.entryID != "" && (match == nil || match.EntryID != tt.entryID) {
+			t.Errorf("Expected %q to match entry %s, got %+v", tt.name, tt.entryID, match)
+		}
+	}
+}
+
+func TestLoadWatchlist(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "watchlist.txt")
+	if err := os.WriteFile(path, []byte("# Sanctioned persons\nJohn Doe\n\nJane Roe\n"), 0o600); err != nil {
+		t.Fatalf("Failed to write watchlist: %v", err)
+	}
+
+	checker, err := LoadWatchlist(path)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	match, _ := checker.Check(SanctionsSubject{Name: "Roe Jane"})
+	if match == nil || match.EntryID != "4" || match.Name != "Jane Roe" {
+		t.Errorf("Expected a match on line 4, got %+v", match)
+	}
+
+	if _, err := LoadWatchlist(filepath.Join(t.TempDir(), "missing.txt")); err == nil {
+		t.Error("Expected an error for a missing watchlist")
+	}
+}
+
+func TestCachedSanctionsChecker_CachesUntilExpiry(t *testing.T) {
+	inner := &countingChecker{SanctionsChecker: NewWatchlistChecker("test", []string{"John Doe"})}
+	checker := NewCachedSanctionsChecker(inner, time.Hour)
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	checker.now = func() time.Time { return now }
+
+	for i := 0; i < 3; i++ {
+		match, _ := checker.Check(SanctionsSubject{Name: "John Doe", Email: "john@example.com"})
+		if match == nil {
+			t.Fatal("Expected a cached match")
+		}
+	}
+	checker.Check(SanctionsSubject{Name: "Ada Clear", Email: "ada@example.com"})
+	checker.Check(SanctionsSubject{Name: "ada clear", Email: "ADA@example.com"})
+	if inner.calls != 2 {
+		t.Errorf("Expected 2 checks to reach the provider, got %d", inner.calls)
+	}
+
+	now = now.Add(2 * time.Hour)
+	checker.Check(SanctionsSubject{Name: "John Doe", Email: "john@example.com"})
+	if inner.calls != 3 {
+		t.Errorf("Expected an expired result to be checked again, got %d checks", inner.calls)
+	}
+}
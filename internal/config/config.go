@@ -3,13 +3,41 @@ package config
 import (
 	"os"
 	"strconv"
+	"strings"
+	"sync"
 	"time"
+
+	"github.com/joho/godotenv"
+	"github.com/shopspring/decimal"
 )
 
 type Config struct {
-	Server   ServerConfig
-	Database DatabaseConfig
-	App      AppConfig
+	Server       ServerConfig
+	Database     DatabaseConfig
+	App          AppConfig
+	Notification NotificationConfig
+	Payment      PaymentConfig
+	Scheduler    SchedulerConfig
+	Fraud        FraudConfig
+	AML          AMLConfig
+	Sanctions    SanctionsConfig
+	JobQueue     JobQueueConfig
+	Breaker      BreakerConfig
+	API          APIConfig
+	Admin        AdminConfig
+	OAuth        OAuthConfig
+	Login        LoginConfig
+	Password     PasswordConfig
+	PIN          PINConfig
+	StepUp       StepUpConfig
+	Digest       DigestConfig
+	Statement    StatementConfig
+	Avatar       AvatarConfig
+	FX           FXConfig
+	Webhook      WebhookConfig
+	Metrics      MetricsConfig
+	Secrets      SecretsConfig
+	Events       EventsConfig
 }
 
 type ServerConfig struct {
@@ -17,6 +45,46 @@ type ServerConfig struct {
 	Port         string
 	ReadTimeout  time.Duration
 	WriteTimeout time.Duration
+	// MaxBodyBytes is the largest request body accepted; larger requests are refused with 413
+	MaxBodyBytes int64
+	// HSTSMaxAge is announced in Strict-Transport-Security; zero leaves the header out
+	HSTSMaxAge time.Duration
+	// RequestValidation refuses requests to documented operations that break the Swagger documents
+	// generated under docs/ before they reach the handlers
+	RequestValidation bool
+	// CompressionEnabled gzips JSON and text responses of at least CompressionMinBytes for clients
+	// that accept it
+	CompressionEnabled  bool
+	CompressionMinBytes int
+	// RateLimit is how many requests a client address may send per RateLimitWindow; zero turns the
+	// limit off
+	RateLimit       int
+	RateLimitWindow time.Duration
+	// TrustedProxies are the addresses or CIDR ranges of the load balancers in front of the service;
+	// client addresses are only read from X-Forwarded-For on requests coming through them
+	TrustedProxies []string
+
+	// TLSCertFile and TLSKeyFile serve HTTPS with a PEM certificate and key. TLSAutocertDomains
+	// instead obtains certificates for those domains from Let's Encrypt, kept in TLSAutocertCacheDir
+	TLSCertFile         string
+	TLSKeyFile          string
+	TLSAutocertDomains  []string
+	TLSAutocertCacheDir string
+	TLSAutocertEmail    string
+	// HTTPRedirectPort, with TLS on, listens for plain HTTP on this port and redirects to HTTPS;
+	// autocert answers its challenges there. Empty disables the listener
+	HTTPRedirectPort string
+
+	// UnixSocket also serves the API on a unix domain socket at this path, for a sidecar proxy, and
+	// ExtraAddresses on more host:port addresses
+	UnixSocket     string
+	ExtraAddresses []string
+	// AdminAddress, as host:port or unix:PATH, serves the admin endpoints and /metrics, which the
+	// other listeners then refuse; empty serves them with the rest of the API
+	AdminAddress string
+	// GRPCAddress, as host:port or unix:PATH, serves the gRPC API for operator tools; empty leaves it
+	// off
+	GRPCAddress string
 }
 
 type DatabaseConfig struct {
@@ -24,47 +92,631 @@ type DatabaseConfig struct {
 	Host            string
 	Port            string
 	Username        string
-	Password        string
+	Password        string `secret:"true"`
 	DBName          string
 	SSLMode         string
 	MaxIdleConns    int
 	MaxOpenConns    int
 	ConnMaxLifetime time.Duration
+	// RetryMaxAttempts is how many times a transaction aborted by a deadlock, lock wait timeout or
+	// dropped connection is run before the error is returned
+	RetryMaxAttempts    int
+	RetryInitialBackoff time.Duration
+	RetryMaxBackoff     time.Duration
+	// QueryTimeout bounds every statement; BalanceQueryTimeout is shorter for balance reads on the
+	// payment path and ReconciliationQueryTimeout longer for reconciliation aggregates. Zero disables
+	QueryTimeout               time.Duration
+	BalanceQueryTimeout        time.Duration
+	ReconciliationQueryTimeout time.Duration
+	// SQLiteWAL, SQLiteBusyTimeout and SQLiteForeignKeys set the journal_mode=WAL, busy_timeout and
+	// foreign_keys pragmas on every SQLite connection. SQLiteSingleWriter runs the transactions of the
+	// process one at a time, so concurrent transfers queue instead of failing with "database is locked"
+	SQLiteWAL          bool
+	SQLiteBusyTimeout  time.Duration
+	SQLiteForeignKeys  bool
+	SQLiteSingleWriter bool
 }
 
 type AppConfig struct {
 	Environment string
 	LogLevel    string
-	JWTSecret   string
+	JWTSecret   string `secret:"true"`
+	// JWTSigningKeys are RSA or Ed25519 private keys written as ID=PATH; the first signs tokens and
+	// the rest, kept while rotating, only verify them. Tokens are signed with JWTSecret without keys
+	JWTSigningKeys []string
+	// AdminEmail and AdminPassword seed an administrator account on startup when both are set
+	AdminEmail    string
+	AdminPassword string `secret:"true"`
+	// SandboxEnabled lets clients log in with sandbox tokens that operate isolated test wallets
+	SandboxEnabled bool
+}
+
+type SchedulerConfig struct {
+	// StandingOrderInterval is how often due standing orders and retries are processed
+	StandingOrderInterval time.Duration
+	// StandingOrderRetryInitial and StandingOrderRetryMax bound the backoff between attempts on
+	// insufficient funds; StandingOrderGracePeriod is how long an occurrence is retried before failing
+	StandingOrderRetryInitial time.Duration
+	StandingOrderRetryMax     time.Duration
+	StandingOrderGracePeriod  time.Duration
+
+	// PendingExpiryInterval is how often withdrawals and transfers left PENDING for longer than
+	// PendingTransactionTTL are cancelled and refunded
+	PendingExpiryInterval time.Duration
+	PendingTransactionTTL time.Duration
+
+	// SettlementBatchInterval is how often the previous UTC day's completed deposits and payouts are
+	// grouped into provider settlement batches
+	SettlementBatchInterval time.Duration
+
+	// TransactionRetention is how long settled transactions stay in the live table before they are
+	// moved to the archive; zero turns archiving off
+	TransactionRetention time.Duration
+	// ArchiveInterval is how often old transactions are archived
+	ArchiveInterval time.Duration
+
+	// BalanceCheckpointInterval is how often wallet balance checkpoints are moved forward, and
+	// BalanceCheckpointLag how old settled transactions must be before a checkpoint covers them
+	BalanceCheckpointInterval time.Duration
+	BalanceCheckpointLag      time.Duration
+
+	// BudgetAlertInterval is how often spending is compared with users' budgets
+	BudgetAlertInterval time.Duration
+
+	// EscrowExpiryInterval is how often escrows past their expiry are returned to their payers
+	EscrowExpiryInterval time.Duration
+
+	// SubscriptionBillingInterval is how often due subscriptions are charged. A failed charge is
+	// retried every SubscriptionRetryInterval, and SubscriptionMaxAttempts failed attempts at a
+	// period suspend the subscription
+	SubscriptionBillingInterval time.Duration
+	SubscriptionRetryInterval   time.Duration
+	SubscriptionMaxAttempts     int
+
+	// AccountDeletionInterval is how often accounts whose users asked for deletion more than
+	// AccountDeletionGracePeriod ago are anonymized
+	AccountDeletionInterval    time.Duration
+	AccountDeletionGracePeriod time.Duration
+}
+
+type FraudConfig struct {
+	// Enabled screens withdrawals and transfers with the rules below; actions are FLAG or BLOCK
+	Enabled bool
+	// CountryHeader is the request header carrying the client's ISO country code, set by a CDN or proxy
+	CountryHeader string
+
+	// More than VelocityLimit debits within VelocityWindow; a limit of 0 disables the rule
+	VelocityLimit  int
+	VelocityWindow time.Duration
+	VelocityAction string
+
+	// Transfers of at least NewRecipientThreshold to a wallet never paid before; 0 disables the rule
+	NewRecipientThreshold decimal.Decimal
+	NewRecipientAction    string
+
+	// Debits of at least OriginThreshold from an IP address and country not seen on the wallet before
+	OriginThreshold decimal.Decimal
+	OriginAction    string
+
+	// StructuringCount debits within StructuringWindow that each fall within StructuringMargin percent
+	// below StructuringThreshold; a threshold of 0 disables the rule
+	StructuringThreshold decimal.Decimal
+	StructuringMargin    decimal.Decimal
+	StructuringCount     int
+	StructuringWindow    time.Duration
+	StructuringAction    string
+}
+
+type AMLConfig struct {
+	// Enabled sends withdrawals and transfers above Threshold to manual AML review
+	Enabled   bool
+	Threshold decimal.Decimal
+}
+
+type SanctionsConfig struct {
+	// Enabled screens new users and new transfer recipients against the watchlist in ListFile,
+	// a file with one name per line
+	Enabled  bool
+	ListFile string
+	// CacheTTL is how long a screening result is reused for the same person
+	CacheTTL time.Duration
+}
+
+type JobQueueConfig struct {
+	// Workers is how many post-transaction audits and notification deliveries run at once
+	Workers      int
+	PollInterval time.Duration
+	// MaxAttempts is how many times a failing job runs before it moves to the dead letter queue;
+	// RetryInitial and RetryMax bound the backoff between attempts
+	MaxAttempts  int
+	RetryInitial time.Duration
+	RetryMax     time.Duration
+}
+
+type BreakerConfig struct {
+	// FailureThreshold consecutive failures of a payment provider or email, SMS or push sender open its
+	// circuit for OpenTimeout; then HalfOpenProbes trial calls decide whether it closes again
+	FailureThreshold int
+	OpenTimeout      time.Duration
+	HalfOpenProbes   int
+}
+
+type APIConfig struct {
+	// V1DeprecatedAt marks the v1 endpoints that have a v2 successor as deprecated from that time on;
+	// V1Sunset is announced as the date they stop responding. Zero times announce nothing
+	V1DeprecatedAt time.Time
+	V1Sunset       time.Time
+}
+
+type AdminConfig struct {
+	// IPAllowlist restricts the admin endpoints to these IP addresses and CIDR ranges, on top of the
+	// ranges added at runtime, which are reloaded every IPAllowlistRefresh. With no range at all every
+	// address is allowed
+	IPAllowlist        []string
+	IPAllowlistRefresh time.Duration
+	// ImpersonationTTL is how long the tokens staff get to act as a user stay valid
+	ImpersonationTTL time.Duration
+}
+
+type OAuthConfig struct {
+	// CallbackBaseURL is the public URL of the service; providers redirect to
+	// <CallbackBaseURL>/api/v1/auth/oauth/<provider>/callback, which must be registered with them
+	CallbackBaseURL string
+	// SessionTTL is how long a user has to finish signing in at the provider
+	SessionTTL time.Duration
+
+	// Google sign in is enabled when a client ID is set
+	GoogleClientID     string
+	GoogleClientSecret string `secret:"true"`
+
+	// A generic OpenID Connect provider is enabled under OIDCName when an issuer is set; its endpoints
+	// are discovered from <OIDCIssuer>/.well-known/openid-configuration
+	OIDCName         string
+	OIDCIssuer       string
+	OIDCClientID     string
+	OIDCClientSecret string `secret:"true"`
+	OIDCScopes       []string
+}
+
+type LoginConfig struct {
+	// MaxFailures wrong passwords in a row lock an account for LockoutCooldown; 0 disables lockout
+	MaxFailures     int
+	LockoutCooldown time.Duration
+	// IPMaxFailures failed logins from one IP address within IPWindow throttle its further attempts;
+	// 0 disables throttling
+	IPMaxFailures int
+	IPWindow      time.Duration
+}
+
+type PasswordConfig struct {
+	MinLength     int
+	RequireUpper  bool
+	RequireLower  bool
+	RequireDigit  bool
+	RequireSymbol bool
+	// HistorySize is how many of the latest passwords of a user cannot be chosen again; 0 allows reuse
+	HistorySize int
+	// BreachCheckEnabled refuses passwords found in known data breaches, looked up in the range API at
+	// BreachCheckURL; only the first 5 characters of the password's SHA-1 hash are sent
+	BreachCheckEnabled bool
+	BreachCheckURL     string
+}
+
+type PINConfig struct {
+	// Threshold is the amount above which withdrawals and transfers need the transaction PIN of users
+	// who set one; 0 asks for it on every debit
+	Threshold decimal.Decimal
+	// MaxAttempts wrong PINs in a row lock the PIN for LockoutCooldown; 0 disables the lock
+	MaxAttempts     int
+	LockoutCooldown time.Duration
+}
+
+type StepUpConfig struct {
+	// Enabled holds transfers above Threshold until the user confirms them with a one-time code sent
+	// to their verified phone, valid for CodeTTL
+	Enabled   bool
+	Threshold decimal.Decimal
+	CodeTTL   time.Duration
+}
+
+type DigestConfig struct {
+	// Enabled summarises the previous UTC day's reconciliation reports, checking every Interval until
+	// the summary is delivered, and sends it to Recipients by email and to the Slack incoming webhook
+	Enabled         bool
+	Interval        time.Duration
+	Recipients      []string
+	SlackWebhookURL string `secret:"true"`
+}
+
+type StatementConfig struct {
+	// StorageDir is where rendered account statements are kept
+	StorageDir string
+	// URLTTL is how long a statement download link stays valid
+	URLTTL time.Duration
+	// SigningSecret signs download links; the JWT secret is used when it is empty
+	SigningSecret string `secret:"true"`
+	// PublicBaseURL is the public URL of the service that download links point at
+	PublicBaseURL string
+}
+
+type AvatarConfig struct {
+	// StorageDir is where uploaded profile pictures are kept
+	StorageDir string
+	// MaxBytes is the largest profile picture accepted
+	MaxBytes int
+}
+
+type FXConfig struct {
+	// Rates convert transfers between wallets of different currencies, written as FROM/TO=RATE; the
+	// reverse pair uses the inverse rate. Without rates such transfers are refused
+	Rates []string
+	// QuoteTTL is how long a transfer quote keeps its fee and exchange rate
+	QuoteTTL time.Duration
+}
+
+type MetricsConfig struct {
+	// Token, when set, must be sent by scrapers of /metrics as a bearer token
+	Token string `secret:"true"`
+}
+
+type SecretsConfig struct {
+	// Provider loads the secrets from "vault" or "aws" Secrets Manager on startup, overriding the
+	// environment, and again every RotationInterval; empty keeps them in the environment
+	Provider         string
+	RotationInterval time.Duration
+
+	// VaultPath is the key/value secret read from the Vault server at VaultAddr
+	VaultAddr  string
+	VaultToken string `secret:"true"`
+	VaultPath  string
+
+	// AWSSecretID is the name or ARN of the Secrets Manager secret in AWSRegion
+	AWSRegion          string
+	AWSSecretID        string
+	AWSAccessKeyID     string
+	AWSSecretAccessKey string `secret:"true"`
+	AWSSessionToken    string `secret:"true"`
+}
+
+type EventsConfig struct {
+	// SNSTopicARN publishes every domain event to an SNS topic, SQSQueueURL sends it to an SQS queue.
+	// FIFO topics and queues get the events of each wallet in order. Requests are signed with the
+	// AWS_REGION and AWS credentials of SecretsConfig
+	SNSTopicARN string
+	SQSQueueURL string
+	// QueueSize bounds the events waiting to be published; MaxAttempts is how often publishing an
+	// event is tried before it is dropped
+	QueueSize   int
+	MaxAttempts int
+}
+
+type WebhookConfig struct {
+	// Timeout fails a delivery attempt whose endpoint does not answer in time
+	Timeout time.Duration
+	// MaxAttempts is how many times a delivery is sent before it moves to the dead letter queue;
+	// RetryInitial and RetryMax bound the backoff between attempts
+	MaxAttempts  int
+	RetryInitial time.Duration
+	RetryMax     time.Duration
+}
+
+type NotificationConfig struct {
+	EmailEnabled bool
+	SMTPHost     string
+	SMTPPort     string
+	SMTPUsername string
+	SMTPPassword string `secret:"true"`
+	EmailFrom    string
+	QueueSize    int
+
+	// SMSProvider selects the SMS sender: "twilio", "termii" or "log"
+	SMSProvider      string
+	TwilioAccountSID string
+	TwilioAuthToken  string `secret:"true"`
+	TwilioFrom       string
+	TermiiAPIKey     string `secret:"true"`
+	TermiiSenderID   string
+	TermiiBaseURL    string
+
+	// PushProvider selects the push sender: "fcm" or "log"
+	PushProvider       string
+	FCMCredentialsFile string
+}
+
+type PaymentConfig struct {
+	// WebhookSecrets maps a provider name to the shared secret used to sign its webhooks
+	WebhookSecrets map[string]string `secret:"true"`
+
+	StripeSecretKey     string `secret:"true"`
+	StripeWebhookSecret string `secret:"true"`
+
+	// Provider selects the hosted checkout provider: "paystack", "flutterwave" or empty to disable
+	Provider                 string
+	CheckoutCallbackURL      string
+	PaystackSecretKey        string `secret:"true"`
+	FlutterwaveSecretKey     string `secret:"true"`
+	FlutterwaveWebhookSecret string `secret:"true"`
+
+	// PayoutProvider selects the bank payout provider for withdrawals: "paystack", "flutterwave" or empty for instant withdrawals
+	PayoutProvider string
 }
 
 // LoadConfig loads configuration from environment variables
 func LoadConfig() *Config {
 	return &Config{
 		Server: ServerConfig{
-			Host:         getEnv("SERVER_HOST", "localhost"),
-			Port:         getEnv("SERVER_PORT", "8080"),
-			ReadTimeout:  getDurationEnv("SERVER_READ_TIMEOUT", 30*time.Second),
-			WriteTimeout: getDurationEnv("SERVER_WRITE_TIMEOUT", 30*time.Second),
+			Host:                getEnv("SERVER_HOST", "localhost"),
+			Port:                getEnv("SERVER_PORT", "8080"),
+			ReadTimeout:         getDurationEnv("SERVER_READ_TIMEOUT", 30*time.Second),
+			WriteTimeout:        getDurationEnv("SERVER_WRITE_TIMEOUT", 30*time.Second),
+			MaxBodyBytes:        int64(getIntEnv("SERVER_MAX_BODY_BYTES", 1<<20)),
+			HSTSMaxAge:          getDurationEnv("SERVER_HSTS_MAX_AGE", 365*24*time.Hour),
+			RequestValidation:   getBoolEnv("SERVER_REQUEST_VALIDATION", true),
+			CompressionEnabled:  getBoolEnv("SERVER_COMPRESSION_ENABLED", true),
+			CompressionMinBytes: getIntEnv("SERVER_COMPRESSION_MIN_BYTES", 1024),
+			RateLimit:           getIntEnv("SERVER_RATE_LIMIT", 600),
+			RateLimitWindow:     getDurationEnv("SERVER_RATE_LIMIT_WINDOW", time.Minute),
+			TrustedProxies:      getListEnv("SERVER_TRUSTED_PROXIES"),
+
+			TLSCertFile:         getEnv("SERVER_TLS_CERT_FILE", ""),
+			TLSKeyFile:          getEnv("SERVER_TLS_KEY_FILE", ""),
+			TLSAutocertDomains:  getListEnv("SERVER_TLS_AUTOCERT_DOMAINS"),
+			TLSAutocertCacheDir: getEnv("SERVER_TLS_AUTOCERT_CACHE_DIR", "./data/autocert"),
+			TLSAutocertEmail:    getEnv("SERVER_TLS_AUTOCERT_EMAIL", ""),
+			HTTPRedirectPort:    getEnv("SERVER_HTTP_REDIRECT_PORT", ""),
+			UnixSocket:          getEnv("SERVER_UNIX_SOCKET", ""),
+			ExtraAddresses:      getListEnv("SERVER_EXTRA_ADDRESSES"),
+			AdminAddress:        getEnv("SERVER_ADMIN_ADDRESS", ""),
+			GRPCAddress:         getEnv("SERVER_GRPC_ADDRESS", ""),
 		},
 		Database: DatabaseConfig{
-			Driver:          getEnv("DB_DRIVER", "mysql"),
-			Host:            getEnv("DB_HOST", "localhost"),
-			Port:            getEnv("DB_PORT", "3306"),
-			Username:        getEnv("DB_USERNAME", "root"),
-			Password:        getEnv("DB_PASSWORD", ""),
-			DBName:          getEnv("DB_NAME", "wallet_service"),
-			SSLMode:         getEnv("DB_SSL_MODE", "disable"),
-			MaxIdleConns:    getIntEnv("DB_MAX_IDLE_CONNS", 10),
-			MaxOpenConns:    getIntEnv("DB_MAX_OPEN_CONNS", 100),
-			ConnMaxLifetime: getDurationEnv("DB_CONN_MAX_LIFETIME", time.Hour),
+			Driver:                     getEnv("DB_DRIVER", "mysql"),
+			Host:                       getEnv("DB_HOST", "localhost"),
+			Port:                       getEnv("DB_PORT", "3306"),
+			Username:                   getEnv("DB_USERNAME", "root"),
+			Password:                   getEnv("DB_PASSWORD", ""),
+			DBName:                     getEnv("DB_NAME", "wallet_service"),
+			SSLMode:                    getEnv("DB_SSL_MODE", "disable"),
+			MaxIdleConns:               getIntEnv("DB_MAX_IDLE_CONNS", 10),
+			MaxOpenConns:               getIntEnv("DB_MAX_OPEN_CONNS", 100),
+			ConnMaxLifetime:            getDurationEnv("DB_CONN_MAX_LIFETIME", time.Hour),
+			RetryMaxAttempts:           getIntEnv("DB_RETRY_MAX_ATTEMPTS", 3),
+			RetryInitialBackoff:        getDurationEnv("DB_RETRY_INITIAL_BACKOFF", 20*time.Millisecond),
+			RetryMaxBackoff:            getDurationEnv("DB_RETRY_MAX_BACKOFF", time.Second),
+			QueryTimeout:               getDurationEnv("DB_QUERY_TIMEOUT", 30*time.Second),
+			BalanceQueryTimeout:        getDurationEnv("DB_BALANCE_QUERY_TIMEOUT", 2*time.Second),
+			ReconciliationQueryTimeout: getDurationEnv("DB_RECONCILIATION_QUERY_TIMEOUT", 5*time.Minute),
+			SQLiteWAL:                  getBoolEnv("DB_SQLITE_WAL", true),
+			SQLiteBusyTimeout:          getDurationEnv("DB_SQLITE_BUSY_TIMEOUT", 5*time.Second),
+			SQLiteForeignKeys:          getBoolEnv("DB_SQLITE_FOREIGN_KEYS", true),
+			SQLiteSingleWriter:         getBoolEnv("DB_SQLITE_SINGLE_WRITER", false),
 		},
 		App: AppConfig{
-			Environment: getEnv("APP_ENV", "development"),
-			LogLevel:    getEnv("LOG_LEVEL", "info"),
-			JWTSecret:   getEnv("JWT_SECRET", "your-secret-key"),
+			Environment:    getEnv("APP_ENV", "development"),
+			LogLevel:       getEnv("LOG_LEVEL", "info"),
+			JWTSecret:      getEnv("JWT_SECRET", "your-secret-key"),
+			JWTSigningKeys: getListEnv("JWT_SIGNING_KEYS"),
+			AdminEmail:     getEnv("ADMIN_EMAIL", ""),
+			AdminPassword:  getEnv("ADMIN_PASSWORD", ""),
+			SandboxEnabled: getBoolEnv("SANDBOX_ENABLED", false),
+		},
+		Scheduler: SchedulerConfig{
+			StandingOrderInterval:       getDurationEnv("STANDING_ORDER_INTERVAL", time.Minute),
+			StandingOrderRetryInitial:   getDurationEnv("STANDING_ORDER_RETRY_INITIAL", 30*time.Minute),
+			StandingOrderRetryMax:       getDurationEnv("STANDING_ORDER_RETRY_MAX", 6*time.Hour),
+			StandingOrderGracePeriod:    getDurationEnv("STANDING_ORDER_GRACE_PERIOD", 72*time.Hour),
+			PendingExpiryInterval:       getDurationEnv("PENDING_EXPIRY_INTERVAL", 5*time.Minute),
+			PendingTransactionTTL:       getDurationEnv("PENDING_TRANSACTION_TTL", 72*time.Hour),
+			SettlementBatchInterval:     getDurationEnv("SETTLEMENT_BATCH_INTERVAL", time.Hour),
+			TransactionRetention:        getDurationEnv("TRANSACTION_RETENTION", 365*24*time.Hour),
+			ArchiveInterval:             getDurationEnv("ARCHIVE_INTERVAL", 24*time.Hour),
+			BalanceCheckpointInterval:   getDurationEnv("BALANCE_CHECKPOINT_INTERVAL", time.Hour),
+			BalanceCheckpointLag:        getDurationEnv("BALANCE_CHECKPOINT_LAG", 24*time.Hour),
+			BudgetAlertInterval:         getDurationEnv("BUDGET_ALERT_INTERVAL", time.Hour),
+			EscrowExpiryInterval:        getDurationEnv("ESCROW_EXPIRY_INTERVAL", 5*time.Minute),
+			SubscriptionBillingInterval: getDurationEnv("SUBSCRIPTION_BILLING_INTERVAL", 5*time.Minute),
+			SubscriptionRetryInterval:   getDurationEnv("SUBSCRIPTION_RETRY_INTERVAL", 24*time.Hour),
+			SubscriptionMaxAttempts:     getIntEnv("SUBSCRIPTION_MAX_ATTEMPTS", 4),
+			AccountDeletionInterval:     getDurationEnv("ACCOUNT_DELETION_INTERVAL", time.Hour),
+			AccountDeletionGracePeriod:  getDurationEnv("ACCOUNT_DELETION_GRACE_PERIOD", 30*24*time.Hour),
+		},
+		Fraud: FraudConfig{
+			Enabled:               getBoolEnv("FRAUD_RULES_ENABLED", false),
+			CountryHeader:         getEnv("FRAUD_COUNTRY_HEADER", "CF-IPCountry"),
+			VelocityLimit:         getIntEnv("FRAUD_VELOCITY_LIMIT", 5),
+			VelocityWindow:        getDurationEnv("FRAUD_VELOCITY_WINDOW", time.Minute),
+			VelocityAction:        getEnv("FRAUD_VELOCITY_ACTION", "BLOCK"),
+			NewRecipientThreshold: getDecimalEnv("FRAUD_NEW_RECIPIENT_THRESHOLD", decimal.NewFromInt(1000)),
+			NewRecipientAction:    getEnv("FRAUD_NEW_RECIPIENT_ACTION", "FLAG"),
+			OriginThreshold:       getDecimalEnv("FRAUD_ORIGIN_THRESHOLD", decimal.NewFromInt(500)),
+			OriginAction:          getEnv("FRAUD_ORIGIN_ACTION", "FLAG"),
+			StructuringThreshold:  getDecimalEnv("FRAUD_STRUCTURING_THRESHOLD", decimal.NewFromInt(10000)),
+			StructuringMargin:     getDecimalEnv("FRAUD_STRUCTURING_MARGIN", decimal.NewFromInt(10)),
+			StructuringCount:      getIntEnv("FRAUD_STRUCTURING_COUNT", 3),
+			StructuringWindow:     getDurationEnv("FRAUD_STRUCTURING_WINDOW", 24*time.Hour),
+			StructuringAction:     getEnv("FRAUD_STRUCTURING_ACTION", "FLAG"),
+		},
+		AML: AMLConfig{
+			Enabled:   getBoolEnv("AML_SCREENING_ENABLED", false),
+			Threshold: getDecimalEnv("AML_SCREENING_THRESHOLD", decimal.NewFromInt(10000)),
+		},
+		Sanctions: SanctionsConfig{
+			Enabled:  getBoolEnv("SANCTIONS_SCREENING_ENABLED", false),
+			ListFile: getEnv("SANCTIONS_LIST_FILE", ""),
+			CacheTTL: getDurationEnv("SANCTIONS_CACHE_TTL", 24*time.Hour),
+		},
+		JobQueue: JobQueueConfig{
+			Workers:      getIntEnv("JOB_QUEUE_WORKERS", 4),
+			PollInterval: getDurationEnv("JOB_QUEUE_POLL_INTERVAL", time.Second),
+			MaxAttempts:  getIntEnv("JOB_QUEUE_MAX_ATTEMPTS", 8),
+			RetryInitial: getDurationEnv("JOB_QUEUE_RETRY_INITIAL", 10*time.Second),
+			RetryMax:     getDurationEnv("JOB_QUEUE_RETRY_MAX", 30*time.Minute),
+		},
+		Breaker: BreakerConfig{
+			FailureThreshold: getIntEnv("CIRCUIT_BREAKER_FAILURE_THRESHOLD", 5),
+			OpenTimeout:      getDurationEnv("CIRCUIT_BREAKER_OPEN_TIMEOUT", 30*time.Second),
+			HalfOpenProbes:   getIntEnv("CIRCUIT_BREAKER_HALF_OPEN_PROBES", 1),
+		},
+		API: APIConfig{
+			V1DeprecatedAt: getTimeEnv("API_V1_DEPRECATED_AT"),
+			V1Sunset:       getTimeEnv("API_V1_SUNSET"),
+		},
+		Admin: AdminConfig{
+			IPAllowlist:        getListEnv("ADMIN_IP_ALLOWLIST"),
+			IPAllowlistRefresh: getDurationEnv("ADMIN_IP_ALLOWLIST_REFRESH", time.Minute),
+			ImpersonationTTL:   getDurationEnv("ADMIN_IMPERSONATION_TTL", 15*time.Minute),
+		},
+		OAuth: OAuthConfig{
+			CallbackBaseURL:    getEnv("OAUTH_CALLBACK_BASE_URL", "http://localhost:8080"),
+			SessionTTL:         getDurationEnv("OAUTH_SESSION_TTL", 10*time.Minute),
+			GoogleClientID:     getEnv("OAUTH_GOOGLE_CLIENT_ID", ""),
+			GoogleClientSecret: getEnv("OAUTH_GOOGLE_CLIENT_SECRET", ""),
+			OIDCName:           getEnv("OAUTH_OIDC_NAME", "oidc"),
+			OIDCIssuer:         getEnv("OAUTH_OIDC_ISSUER", ""),
+			OIDCClientID:       getEnv("OAUTH_OIDC_CLIENT_ID", ""),
+			OIDCClientSecret:   getEnv("OAUTH_OIDC_CLIENT_SECRET", ""),
+			OIDCScopes:         getListEnv("OAUTH_OIDC_SCOPES"),
 		},
+		Login: LoginConfig{
+			MaxFailures:     getIntEnv("LOGIN_MAX_FAILURES", 5),
+			LockoutCooldown: getDurationEnv("LOGIN_LOCKOUT_COOLDOWN", 15*time.Minute),
+			IPMaxFailures:   getIntEnv("LOGIN_IP_MAX_FAILURES", 20),
+			IPWindow:        getDurationEnv("LOGIN_IP_WINDOW", 15*time.Minute),
+		},
+		Password: PasswordConfig{
+			MinLength:          getIntEnv("PASSWORD_MIN_LENGTH", 8),
+			RequireUpper:       getBoolEnv("PASSWORD_REQUIRE_UPPER", true),
+			RequireLower:       getBoolEnv("PASSWORD_REQUIRE_LOWER", true),
+			RequireDigit:       getBoolEnv("PASSWORD_REQUIRE_DIGIT", true),
+			RequireSymbol:      getBoolEnv("PASSWORD_REQUIRE_SYMBOL", false),
+			HistorySize:        getIntEnv("PASSWORD_HISTORY_SIZE", 5),
+			BreachCheckEnabled: getBoolEnv("PASSWORD_BREACH_CHECK_ENABLED", false),
+			BreachCheckURL:     getEnv("PASSWORD_BREACH_CHECK_URL", "https://api.pwnedpasswords.com"),
+		},
+		PIN: PINConfig{
+			Threshold:       getDecimalEnv("PIN_THRESHOLD", decimal.Zero),
+			MaxAttempts:     getIntEnv("PIN_MAX_ATTEMPTS", 5),
+			LockoutCooldown: getDurationEnv("PIN_LOCKOUT_COOLDOWN", 30*time.Minute),
+		},
+		StepUp: StepUpConfig{
+			Enabled:   getBoolEnv("STEP_UP_ENABLED", false),
+			Threshold: getDecimalEnv("STEP_UP_THRESHOLD", decimal.NewFromInt(5000)),
+			CodeTTL:   getDurationEnv("STEP_UP_CODE_TTL", 5*time.Minute),
+		},
+		Digest: DigestConfig{
+			Enabled:         getBoolEnv("RECONCILIATION_DIGEST_ENABLED", false),
+			Interval:        getDurationEnv("RECONCILIATION_DIGEST_INTERVAL", time.Hour),
+			Recipients:      getListEnv("RECONCILIATION_DIGEST_RECIPIENTS"),
+			SlackWebhookURL: getEnv("RECONCILIATION_DIGEST_SLACK_WEBHOOK_URL", ""),
+		},
+		Statement: StatementConfig{
+			StorageDir:    getEnv("STATEMENT_STORAGE_DIR", "./data/statements"),
+			URLTTL:        getDurationEnv("STATEMENT_URL_TTL", 15*time.Minute),
+			SigningSecret: getEnv("STATEMENT_SIGNING_SECRET", ""),
+			PublicBaseURL: getEnv("STATEMENT_PUBLIC_BASE_URL", "http://localhost:8080"),
+		},
+		Avatar: AvatarConfig{
+			StorageDir: getEnv("AVATAR_STORAGE_DIR", "./data/avatars"),
+			MaxBytes:   getIntEnv("AVATAR_MAX_BYTES", 512<<10),
+		},
+		FX: FXConfig{
+			Rates:    getListEnv("FX_RATES"),
+			QuoteTTL: getDurationEnv("TRANSFER_QUOTE_TTL", time.Minute),
+		},
+		Webhook: WebhookConfig{
+			Timeout:      getDurationEnv("WEBHOOK_TIMEOUT", 10*time.Second),
+			MaxAttempts:  getIntEnv("WEBHOOK_MAX_ATTEMPTS", 8),
+			RetryInitial: getDurationEnv("WEBHOOK_RETRY_INITIAL", time.Minute),
+			RetryMax:     getDurationEnv("WEBHOOK_RETRY_MAX", 6*time.Hour),
+		},
+		Metrics: MetricsConfig{
+			Token: getEnv("METRICS_TOKEN", ""),
+		},
+		Secrets: SecretsConfig{
+			Provider:           getEnv("SECRETS_PROVIDER", ""),
+			RotationInterval:   getDurationEnv("SECRETS_ROTATION_INTERVAL", 5*time.Minute),
+			VaultAddr:          getEnv("VAULT_ADDR", ""),
+			VaultToken:         getEnv("VAULT_TOKEN", ""),
+			VaultPath:          getEnv("VAULT_SECRET_PATH", ""),
+			AWSRegion:          getEnv("AWS_REGION", ""),
+			AWSSecretID:        getEnv("AWS_SECRET_ID", ""),
+			AWSAccessKeyID:     getEnv("AWS_ACCESS_KEY_ID", ""),
+			AWSSecretAccessKey: getEnv("AWS_SECRET_ACCESS_KEY", ""),
+			AWSSessionToken:    getEnv("AWS_SESSION_TOKEN", ""),
+		},
+		Events: EventsConfig{
+			SNSTopicARN: getEnv("EVENTS_SNS_TOPIC_ARN", ""),
+			SQSQueueURL: getEnv("EVENTS_SQS_QUEUE_URL", ""),
+			QueueSize:   getIntEnv("EVENTS_AWS_QUEUE_SIZE", 10000),
+			MaxAttempts: getIntEnv("EVENTS_AWS_MAX_ATTEMPTS", 5),
+		},
+		Notification: NotificationConfig{
+			EmailEnabled:       getBoolEnv("NOTIFICATION_EMAIL_ENABLED", false),
+			SMTPHost:           getEnv("SMTP_HOST", ""),
+			SMTPPort:           getEnv("SMTP_PORT", "587"),
+			SMTPUsername:       getEnv("SMTP_USERNAME", ""),
+			SMTPPassword:       getEnv("SMTP_PASSWORD", ""),
+			EmailFrom:          getEnv("EMAIL_FROM", "no-reply@walletservice.com"),
+			QueueSize:          getIntEnv("NOTIFICATION_QUEUE_SIZE", 1000),
+			SMSProvider:        getEnv("SMS_PROVIDER", "log"),
+			TwilioAccountSID:   getEnv("TWILIO_ACCOUNT_SID", ""),
+			TwilioAuthToken:    getEnv("TWILIO_AUTH_TOKEN", ""),
+			TwilioFrom:         getEnv("TWILIO_FROM", ""),
+			TermiiAPIKey:       getEnv("TERMII_API_KEY", ""),
+			TermiiSenderID:     getEnv("TERMII_SENDER_ID", ""),
+			TermiiBaseURL:      getEnv("TERMII_BASE_URL", ""),
+			PushProvider:       getEnv("PUSH_PROVIDER", "log"),
+			FCMCredentialsFile: getEnv("FCM_CREDENTIALS_FILE", ""),
+		},
+		Payment: PaymentConfig{
+			WebhookSecrets:           getMapEnv("PAYMENT_WEBHOOK_SECRETS"),
+			StripeSecretKey:          getEnv("STRIPE_SECRET_KEY", ""),
+			StripeWebhookSecret:      getEnv("STRIPE_WEBHOOK_SECRET", ""),
+			Provider:                 getEnv("PAYMENT_PROVIDER", ""),
+			CheckoutCallbackURL:      getEnv("PAYMENT_CALLBACK_URL", ""),
+			PaystackSecretKey:        getEnv("PAYSTACK_SECRET_KEY", ""),
+			FlutterwaveSecretKey:     getEnv("FLUTTERWAVE_SECRET_KEY", ""),
+			FlutterwaveWebhookSecret: getEnv("FLUTTERWAVE_WEBHOOK_HASH", ""),
+			PayoutProvider:           getEnv("PAYOUT_PROVIDER", ""),
+		},
+	}
+}
+
+var (
+	envFileMu sync.Mutex
+	// envFileValues are the variables set from the env file, with the value it gave them
+	envFileValues = make(map[string]string)
+)
+
+// LoadEnvFile sets the variables of the env file at path that the process environment does not set.
+// Loading it again picks up edits to the file, so a reload sees the new values; variables set by the
+// process environment or changed since, such as secrets from a secrets backend, always win
+func LoadEnvFile(path string) error {
+	values, err := godotenv.Read(path)
+	if err != nil {
+		return err
+	}
+
+	envFileMu.Lock()
+	defer envFileMu.Unlock()
+	for key, loaded := range envFileValues {
+		if os.Getenv(key) != loaded {
+			delete(envFileValues, key)
+		} else if _, ok := values[key]; !ok {
+			os.Unsetenv(key)
+			delete(envFileValues, key)
+		}
+	}
+	for key, value := range values {
+		if _, fromFile := envFileValues[key]; !fromFile {
+			if _, set := os.LookupEnv(key); set {
+				continue
+			}
+		}
+		os.Setenv(key, value)
+		envFileValues[key] = value
 	}
+	return nil
 }
 
 // Helper functions
@@ -84,6 +736,15 @@ func getIntEnv(key string, defaultValue int) int {
 	return defaultValue
 }
 
+func getBoolEnv(key string, defaultValue bool) bool {
+	if value := os.Getenv(key); value != "" {
+		if boolValue, err := strconv.ParseBool(value); err == nil {
+			return boolValue
+		}
+	}
+	return defaultValue
+}
+
 func getDurationEnv(key string, defaultValue time.Duration) time.Duration {
 	if value := os.Getenv(key); value != "" {
 		if duration, err := time.ParseDuration(value); err == nil {
@@ -92,3 +753,47 @@ func getDurationEnv(key string, defaultValue time.Duration) time.Duration {
 	}
 	return defaultValue
 }
+
+func getDecimalEnv(key string, defaultValue decimal.Decimal) decimal.Decimal {
+	if value := os.Getenv(key); value != "" {
+		if amount, err := decimal.NewFromString(value); err == nil {
+			return amount
+		}
+	}
+	return defaultValue
+}
+
+// getTimeEnv parses an RFC 3339 timestamp or a date such as 2026-12-31; unset or invalid values give
+// the zero time
+func getTimeEnv(key string) time.Time {
+	value := os.Getenv(key)
+	for _, layout := range []string{time.RFC3339, time.DateOnly} {
+		if t, err := time.Parse(layout, value); err == nil {
+			return t
+		}
+	}
+	return time.Time{}
+}
+
+// getListEnv parses a comma separated list, skipping empty items
+func getListEnv(key string) []string {
+	var values []string
+	for _, value := range strings.Split(os.Getenv(key), ",") {
+		if value = strings.TrimSpace(value); value != "" {
+			values = append(values, value)
+		}
+	}
+	return values
+}
+
+// getMapEnv parses a comma separated list of key:value pairs
+func getMapEnv(key string) map[string]string {
+	values := make(map[string]string)
+	for _, pair := range strings.Split(os.Getenv(key), ",") {
+		name, value, found := strings.Cut(strings.TrimSpace(pair), ":")
+		if found && name != "" && value != "" {
+			values[strings.ToLower(name)] = value
+		}
+	}
+	return values
+}
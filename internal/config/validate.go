@@ -0,0 +1,203 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+// defaultJWTSecret is the JWT secret used when JWT_SECRET is not set; it is public, so production
+// refuses it
+const defaultJWTSecret = "your-secret-key"
+
+// minSecretLength is the shortest JWT secret accepted in production
+const minSecretLength = 32
+
+// redacted replaces the value of secrets in the summary
+const redacted = "[redacted]"
+
+// IsProduction reports whether the service runs in production, where Validate problems are fatal
+func (c *Config) IsProduction() bool {
+	return c.App.Environment == "production"
+}
+
+// Validate reports settings that are missing, weak or inconsistent, each naming the variable to fix.
+// The secrets are only required in production; the other problems are reported everywhere
+func (c *Config) Validate() error {
+	var problems []string
+	problem := func(format string, args ...interface{}) {
+		problems = append(problems, fmt.Sprintf(format, args...))
+	}
+
+	if c.IsProduction() {
+		switch {
+		case c.App.JWTSecret == defaultJWTSecret:
+			problem("JWT_SECRET is not set; set a random secret of at least %d characters", minSecretLength)
+		case len(c.App.JWTSecret) < minSecretLength:
+			problem("JWT_SECRET is %d characters long; use a random secret of at least %d", len(c.App.JWTSecret), minSecretLength)
+		}
+		if c.Database.Driver != "sqlite" && c.Database.Driver != "memory" && c.Database.Password == "" {
+			problem("DB_PASSWORD is empty")
+		}
+		if c.App.AdminEmail != "" && len(c.App.AdminPassword) < c.Password.MinLength {
+			problem("ADMIN_PASSWORD is shorter than PASSWORD_MIN_LENGTH (%d)", c.Password.MinLength)
+		}
+		if c.StepUp.Enabled && c.Notification.SMSProvider == "log" {
+			problem("STEP_UP_ENABLED needs an SMS_PROVIDER; with \"log\" confirmation codes only reach the log")
+		}
+	}
+
+	if (c.Server.TLSCertFile == "") != (c.Server.TLSKeyFile == "") {
+		problem("SERVER_TLS_CERT_FILE and SERVER_TLS_KEY_FILE must be set together")
+	}
+	if c.Server.TLSCertFile != "" && len(c.Server.TLSAutocertDomains) > 0 {
+		problem("SERVER_TLS_AUTOCERT_DOMAINS cannot be used with SERVER_TLS_CERT_FILE; choose one")
+	}
+	if c.Server.HTTPRedirectPort != "" && c.Server.TLSCertFile == "" && len(c.Server.TLSAutocertDomains) == 0 {
+		problem("SERVER_HTTP_REDIRECT_PORT is set without TLS")
+	}
+	if c.Server.RateLimit > 0 && c.Server.RateLimitWindow <= 0 {
+		problem("SERVER_RATE_LIMIT_WINDOW must be positive when SERVER_RATE_LIMIT is set")
+	}
+	if c.Notification.EmailEnabled && c.Notification.SMTPHost == "" {
+		problem("NOTIFICATION_EMAIL_ENABLED is set without SMTP_HOST")
+	}
+	switch c.Notification.SMSProvider {
+	case "twilio":
+		requireSet(problem, "SMS_PROVIDER=twilio", map[string]string{
+			"TWILIO_ACCOUNT_SID": c.Notification.TwilioAccountSID,
+			"TWILIO_AUTH_TOKEN":  c.Notification.TwilioAuthToken,
+			"TWILIO_FROM":        c.Notification.TwilioFrom,
+		})
+	case "termii":
+		requireSet(problem, "SMS_PROVIDER=termii", map[string]string{"TERMII_API_KEY": c.Notification.TermiiAPIKey})
+	case "log":
+	default:
+		problem("SMS_PROVIDER %q is not supported; use twilio, termii or log", c.Notification.SMSProvider)
+	}
+	switch c.Notification.PushProvider {
+	case "fcm":
+		requireSet(problem, "PUSH_PROVIDER=fcm", map[string]string{"FCM_CREDENTIALS_FILE": c.Notification.FCMCredentialsFile})
+	case "log":
+	default:
+		problem("PUSH_PROVIDER %q is not supported; use fcm or log", c.Notification.PushProvider)
+	}
+	for name, provider := range map[string]string{"PAYMENT_PROVIDER": c.Payment.Provider, "PAYOUT_PROVIDER": c.Payment.PayoutProvider} {
+		switch provider {
+		case "paystack":
+			requireSet(problem, name+"=paystack", map[string]string{"PAYSTACK_SECRET_KEY": c.Payment.PaystackSecretKey})
+		case "flutterwave":
+			requireSet(problem, name+"=flutterwave", map[string]string{
+				"FLUTTERWAVE_SECRET_KEY":   c.Payment.FlutterwaveSecretKey,
+				"FLUTTERWAVE_WEBHOOK_HASH": c.Payment.FlutterwaveWebhookSecret,
+			})
+		case "":
+		default:
+			problem("%s %q is not supported; use paystack, flutterwave or leave it empty", name, provider)
+		}
+	}
+	if c.Sanctions.Enabled && c.Sanctions.ListFile == "" {
+		problem("SANCTIONS_SCREENING_ENABLED is set without SANCTIONS_LIST_FILE")
+	}
+	if c.Digest.Enabled && len(c.Digest.Recipients) == 0 && c.Digest.SlackWebhookURL == "" {
+		problem("RECONCILIATION_DIGEST_ENABLED is set without RECONCILIATION_DIGEST_RECIPIENTS or RECONCILIATION_DIGEST_SLACK_WEBHOOK_URL")
+	}
+	switch c.Secrets.Provider {
+	case "vault":
+		requireSet(problem, "SECRETS_PROVIDER=vault", map[string]string{
+			"VAULT_ADDR":        c.Secrets.VaultAddr,
+			"VAULT_TOKEN":       c.Secrets.VaultToken,
+			"VAULT_SECRET_PATH": c.Secrets.VaultPath,
+		})
+	case "aws":
+		requireSet(problem, "SECRETS_PROVIDER=aws", map[string]string{
+			"AWS_REGION":            c.Secrets.AWSRegion,
+			"AWS_SECRET_ID":         c.Secrets.AWSSecretID,
+			"AWS_ACCESS_KEY_ID":     c.Secrets.AWSAccessKeyID,
+			"AWS_SECRET_ACCESS_KEY": c.Secrets.AWSSecretAccessKey,
+		})
+	case "":
+	default:
+		problem("SECRETS_PROVIDER %q is not supported; use vault, aws or leave it empty", c.Secrets.Provider)
+	}
+	if c.Events.SNSTopicARN != "" || c.Events.SQSQueueURL != "" {
+		requireSet(problem, "EVENTS_SNS_TOPIC_ARN or EVENTS_SQS_QUEUE_URL", map[string]string{
+			"AWS_REGION":            c.Secrets.AWSRegion,
+			"AWS_ACCESS_KEY_ID":     c.Secrets.AWSAccessKeyID,
+			"AWS_SECRET_ACCESS_KEY": c.Secrets.AWSSecretAccessKey,
+		})
+	}
+	if c.JobQueue.Workers < 1 {
+		problem("JOB_QUEUE_WORKERS must be at least 1")
+	}
+
+	if len(problems) == 0 {
+		return nil
+	}
+	sort.Strings(problems)
+	return errors.New(strings.Join(problems, "\n"))
+}
+
+// requireSet reports the variables of values that feature needs but are empty
+func requireSet(problem func(string, ...interface{}), feature string, values map[string]string) {
+	var missing []string
+	for name, value := range values {
+		if value == "" {
+			missing = append(missing, name)
+		}
+	}
+	if len(missing) > 0 {
+		sort.Strings(missing)
+		problem("%s needs %s", feature, strings.Join(missing, ", "))
+	}
+}
+
+// Summary renders the effective configuration one section per line, for the startup log. Fields
+// tagged secret show whether they are set but never their value
+func (c *Config) Summary() string {
+	config := reflect.ValueOf(*c)
+	lines := make([]string, 0, config.NumField())
+	for i := 0; i < config.NumField(); i++ {
+		section := config.Field(i)
+		fields := make([]string, 0, section.NumField())
+		for j := 0; j < section.NumField(); j++ {
+			field := section.Type().Field(j)
+			value := summaryValue(section.Field(j))
+			if field.Tag.Get("secret") == "true" && value != "" {
+				value = redacted
+			}
+			fields = append(fields, field.Name+"="+value)
+		}
+		lines = append(lines, config.Type().Field(i).Name+": "+strings.Join(fields, " "))
+	}
+	return strings.Join(lines, "\n")
+}
+
+// summaryValue renders a setting; maps only show their keys
+func summaryValue(value reflect.Value) string {
+	switch v := value.Interface().(type) {
+	case time.Time:
+		if v.IsZero() {
+			return ""
+		}
+		return v.Format(time.RFC3339)
+	case decimal.Decimal:
+		return v.String()
+	case []string:
+		return strings.Join(v, ",")
+	case map[string]string:
+		keys := make([]string, 0, len(v))
+		for key := range v {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+		return strings.Join(keys, ",")
+	default:
+		return fmt.Sprint(v)
+	}
+}
@@ -0,0 +1,64 @@
+package config
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestConfig_ValidateProduction(t *testing.T) {
+	t.Setenv("APP_ENV", "production")
+	t.Setenv("JWT_SECRET", "")
+	t.Setenv("DB_PASSWORD", "")
+	t.Setenv("SMS_PROVIDER", "twilio")
+	t.Setenv("TWILIO_ACCOUNT_SID", "AC123")
+	t.Setenv("PAYOUT_PROVIDER", "monzo")
+
+	err := LoadConfig().Validate()
+	if err == nil {
+		t.Fatal("Expected the defaults to be refused in production")
+	}
+	for _, want := range []string{
+		"JWT_SECRET is not set",
+		"DB_PASSWORD is empty",
+		"SMS_PROVIDER=twilio needs TWILIO_AUTH_TOKEN, TWILIO_FROM",
+		`PAYOUT_PROVIDER "monzo" is not supported`,
+	} {
+		if !strings.Contains(err.Error(), want) {
+			t.Errorf("Expected %q among the problems, got:\n%v", want, err)
+		}
+	}
+
+	t.Setenv("JWT_SECRET", "short")
+	if err := LoadConfig().Validate(); err == nil || !strings.Contains(err.Error(), "JWT_SECRET is 5 characters long") {
+		t.Errorf("Expected a short JWT secret to be refused, got %v", err)
+	}
+}
+
+func TestConfig_ValidateDevelopment(t *testing.T) {
+	t.Setenv("APP_ENV", "development")
+	t.Setenv("JWT_SECRET", "")
+	t.Setenv("DB_PASSWORD", "")
+	t.Setenv("SMS_PROVIDER", "")
+
+	if err := LoadConfig().Validate(); err != nil {
+		t.Errorf("Expected the defaults to be accepted outside production, got %v", err)
+	}
+}
+
+func TestConfig_SummaryRedactsSecrets(t *testing.T) {
+	t.Setenv("JWT_SECRET", "super-secret-signing-value")
+	t.Setenv("SMTP_PASSWORD", "")
+	t.Setenv("PAYMENT_WEBHOOK_SECRETS", "paystack:whsec_123")
+
+	summary := LoadConfig().Summary()
+	for _, secret := range []string{"super-secret-signing-value", "whsec_123"} {
+		if strings.Contains(summary, secret) {
+			t.Errorf("Expected %q to be redacted from the summary:\n%s", secret, summary)
+		}
+	}
+	for _, want := range []string{"JWTSecret=" + redacted, "SMTPPassword= ", "ReadTimeout=30s"} {
+		if !strings.Contains(summary, want) {
+			t.Errorf("Expected %q in the summary:\n%s", want, summary)
+		}
+	}
+}
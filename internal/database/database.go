@@ -1,16 +1,23 @@
 package database
 
 import (
+	"context"
+	"database/sql"
 	"fmt"
 	"log"
+	"net"
 	"os"
+	"time"
 
+	gomysql "github.com/go-sql-driver/mysql"
 	"github.com/limistah/wallet-service/internal/config"
 	"github.com/limistah/wallet-service/internal/models"
+	"github.com/limistah/wallet-service/internal/repositories"
 	"github.com/shopspring/decimal"
 	"gorm.io/driver/mysql"
 	"gorm.io/driver/sqlite"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 	"gorm.io/gorm/logger"
 )
 
@@ -37,19 +44,20 @@ func Initialize() (*gorm.DB, error) {
 
 	switch cfg.Database.Driver {
 	case "mysql":
-		dsn := fmt.Sprintf("%s:%s@tcp(%s:%s)/%s?charset=utf8mb4&parseTime=True&loc=Local",
-			cfg.Database.Username,
-			cfg.Database.Password,
-			cfg.Database.Host,
-			cfg.Database.Port,
-			cfg.Database.DBName,
-		)
-		db, err = gorm.Open(mysql.Open(dsn), gormConfig)
+		dialector, err := mysqlDialector(cfg.Database)
+		if err != nil {
+			return nil, fmt.Errorf("failed to configure MySQL database: %v", err)
+		}
+		db, err = gorm.Open(dialector, gormConfig)
 		if err != nil {
 			return nil, fmt.Errorf("failed to connect to MySQL database: %v", err)
 		}
 	case "sqlite":
-		db, err = gorm.Open(sqlite.Open(dbPath), gormConfig)
+		dialector, err := sqliteDialector(dbPath, cfg.Database)
+		if err != nil {
+			return nil, fmt.Errorf("failed to configure SQLite database: %v", err)
+		}
+		db, err = gorm.Open(dialector, gormConfig)
 		if err != nil {
 			return nil, fmt.Errorf("failed to connect to SQLite database: %v", err)
 		}
@@ -57,42 +65,96 @@ func Initialize() (*gorm.DB, error) {
 		return nil, fmt.Errorf("unsupported database driver: %s", cfg.Database.Driver)
 	}
 
-	if cfg.Database.Driver == "mysql" {
-		sqlDB, err := db.DB()
-		if err != nil {
-			return nil, fmt.Errorf("failed to get database instance: %v", err)
-		}
-
-		sqlDB.SetMaxIdleConns(cfg.Database.MaxIdleConns)
-		sqlDB.SetMaxOpenConns(cfg.Database.MaxOpenConns)
-		sqlDB.SetConnMaxLifetime(cfg.Database.ConnMaxLifetime)
-
-		if err := sqlDB.Ping(); err != nil {
-			return nil, fmt.Errorf("failed to ping database: %v", err)
-		}
+	sqlDB, err := db.DB()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get database instance: %v", err)
+	}
+	configurePool(sqlDB, cfg.Database)
+	if err := sqlDB.Ping(); err != nil {
+		return nil, fmt.Errorf("failed to ping database: %v", err)
 	}
 
 	log.Printf("Successfully connected to %s database", cfg.Database.Driver)
 
-	err = db.AutoMigrate(
-		&models.User{},
-		&models.Wallet{},
-		&models.Transaction{},
-		&models.ReconciliationReport{},
-	)
+	if err := repositories.RegisterTenantScoping(db); err != nil {
+		return nil, fmt.Errorf("failed to register tenant scoping: %v", err)
+	}
+
+	err = migrate(db)
 	if err != nil {
 		return nil, fmt.Errorf("failed to migrate database: %v", err)
 	}
 
+	err = bootstrapDefaultTenant(db)
+	if err != nil {
+		return nil, fmt.Errorf("failed to bootstrap default tenant: %v", err)
+	}
+
 	err = bootstrapSystemAccount(db)
 	if err != nil {
 		return nil, fmt.Errorf("failed to bootstrap system account: %v", err)
 	}
 
+	err = bootstrapDefaultWalletTier(db)
+	if err != nil {
+		return nil, fmt.Errorf("failed to bootstrap default wallet tier: %v", err)
+	}
+
+	err = bootstrapSandboxAccount(db, cfg)
+	if err != nil {
+		return nil, fmt.Errorf("failed to bootstrap sandbox account: %v", err)
+	}
+
+	err = bootstrapAdminAccount(db, cfg)
+	if err != nil {
+		return nil, fmt.Errorf("failed to bootstrap admin account: %v", err)
+	}
+
+	// Registered after migrating so schema changes on large tables are not cut short
+	if err := repositories.RegisterQueryTimeouts(db, queryTimeouts(cfg.Database)); err != nil {
+		return nil, fmt.Errorf("failed to register query timeouts: %v", err)
+	}
+
 	log.Println("Database connected and migrated successfully")
 	return db, nil
 }
 
+// configurePool applies the connection pool settings, whichever the driver
+func configurePool(sqlDB *sql.DB, cfg config.DatabaseConfig) {
+	sqlDB.SetMaxIdleConns(cfg.MaxIdleConns)
+	sqlDB.SetMaxOpenConns(cfg.MaxOpenConns)
+	sqlDB.SetConnMaxLifetime(cfg.ConnMaxLifetime)
+}
+
+// mysqlDialector reads the database password from the environment each time a connection is opened,
+// so a password rotated by the secrets backend applies to new connections without a restart
+func mysqlDialector(cfg config.DatabaseConfig) (gorm.Dialector, error) {
+	dsn := gomysql.NewConfig()
+	dsn.User = cfg.Username
+	dsn.Passwd = cfg.Password
+	dsn.Net = "tcp"
+	dsn.Addr = net.JoinHostPort(cfg.Host, cfg.Port)
+	dsn.DBName = cfg.DBName
+	dsn.ParseTime = true
+	dsn.Loc = time.Local
+	dsn.Params = map[string]string{"charset": "utf8mb4"}
+	err := dsn.Apply(gomysql.BeforeConnect(func(_ context.Context, c *gomysql.Config) error {
+		if password, set := os.LookupEnv("DB_PASSWORD"); set {
+			c.Passwd = password
+		}
+		return nil
+	}))
+	if err != nil {
+		return nil, err
+	}
+
+	connector, err := gomysql.NewConnector(dsn)
+	if err != nil {
+		return nil, err
+	}
+	return mysql.New(mysql.Config{Conn: sql.OpenDB(connector)}), nil
+}
+
 // InitWithConfig initializes database with provided config (useful for testing)
 func InitWithConfig(cfg *config.Config) (*gorm.DB, error) {
 	var db *gorm.DB
@@ -127,28 +189,217 @@ func InitWithConfig(cfg *config.Config) (*gorm.DB, error) {
 		return nil, fmt.Errorf("failed to connect to %s database: %v", cfg.Database.Driver, err)
 	}
 
+	if err := repositories.RegisterTenantScoping(db); err != nil {
+		return nil, fmt.Errorf("failed to register tenant scoping: %v", err)
+	}
+
 	// Auto migrate models
-	err = db.AutoMigrate(
+	err = migrate(db)
+	if err != nil {
+		return nil, fmt.Errorf("failed to migrate database: %v", err)
+	}
+
+	if err := repositories.RegisterQueryTimeouts(db, queryTimeouts(cfg.Database)); err != nil {
+		return nil, fmt.Errorf("failed to register query timeouts: %v", err)
+	}
+
+	return db, nil
+}
+
+func queryTimeouts(cfg config.DatabaseConfig) repositories.QueryTimeouts {
+	return repositories.QueryTimeouts{
+		Default:        cfg.QueryTimeout,
+		Balance:        cfg.BalanceQueryTimeout,
+		Reconciliation: cfg.ReconciliationQueryTimeout,
+	}
+}
+
+// migrate runs auto migrations for every persisted model. The transaction types and purposes are seeded
+// first since transactions reference them
+func migrate(db *gorm.DB) error {
+	if err := db.AutoMigrate(&models.TransactionTypeDefinition{}, &models.TransactionPurposeDefinition{}); err != nil {
+		return err
+	}
+	if err := seedTransactionTypes(db); err != nil {
+		return err
+	}
+	if err := seedTransactionPurposes(db); err != nil {
+		return err
+	}
+
+	err := db.AutoMigrate(
+		&models.Tenant{},
 		&models.User{},
+		&models.WalletTier{},
 		&models.Wallet{},
 		&models.Transaction{},
 		&models.ReconciliationReport{},
+		&models.AuditLog{},
+		&models.NotificationPreference{},
+		&models.PhoneVerification{},
+		&models.DeviceToken{},
+		&models.Deposit{},
+		&models.Payout{},
+		&models.MoneyRequest{},
+		&models.PaymentLink{},
+		&models.StandingOrder{},
+		&models.StandingOrderRun{},
+		&models.FraudReview{},
+		&models.BlocklistEntry{},
+		&models.ComplianceLog{},
+		&models.AMLCase{},
+		&models.Job{},
+		&models.IPAllowlistEntry{},
+		&models.RevokedToken{},
+		&models.UserIdentity{},
+		&models.LoginAttempt{},
+		&models.PasswordHistory{},
+		&models.Session{},
+		&models.TransferChallenge{},
+		&models.TransferQuote{},
+		&models.ReconciliationSummary{},
+		&models.ReconciliationRun{},
+		&models.LedgerAccount{},
+		&models.JournalEntry{},
+		&models.SuspenseItem{},
+		&models.BalanceAdjustment{},
+		&models.SettlementBatch{},
+		&models.ProviderStatement{},
+		&models.StatementLine{},
+		&models.ArchivedTransaction{},
+		&models.BalanceCheckpoint{},
+		&models.AccountStatement{},
+		&models.TransactionStatusChange{},
+		&models.Budget{},
+		&models.BusinessAccount{},
+		&models.BusinessMember{},
+		&models.PaymentApproval{},
+		&models.Escrow{},
+		&models.SubscriptionPlan{},
+		&models.Subscription{},
+		&models.SubscriptionCharge{},
+		&models.WebhookSubscription{},
+		&models.WebhookDelivery{},
+		&models.WebhookDeliveryAttempt{},
+		&models.DomainEvent{},
 	)
 	if err != nil {
-		return nil, fmt.Errorf("failed to migrate database: %v", err)
+		return err
+	}
+	if err := dropLegacyLedgerAccountIndex(db); err != nil {
+		return err
 	}
+	return dropLegacyPurposeChecks(db)
+}
 
-	return db, nil
+// bootstrapDefaultTenant creates the tenant that users and wallets belong to unless they are created
+// for another one
+func bootstrapDefaultTenant(db *gorm.DB) error {
+	tenant := &models.Tenant{ID: models.DefaultTenantID, Name: "Default", Slug: models.DefaultTenantSlug, Active: true}
+	if err := db.Clauses(clause.OnConflict{DoNothing: true}).Create(tenant).Error; err != nil {
+		return fmt.Errorf("failed to create default tenant: %v", err)
+	}
+	return nil
 }
 
 // bootstrapSystemAccount creates the system account and wallet for double-entry bookkeeping
 func bootstrapSystemAccount(db *gorm.DB) error {
+	return ensureSystemAccount(db, models.CreateSystemUser(), false)
+}
+
+// seedTransactionTypes adds the default transaction types missing from the lookup table
+func seedTransactionTypes(db *gorm.DB) error {
+	types := models.DefaultTransactionTypes()
+	if err := db.Clauses(clause.OnConflict{DoNothing: true}).Create(&types).Error; err != nil {
+		return fmt.Errorf("failed to seed transaction types: %v", err)
+	}
+	return nil
+}
+
+// seedTransactionPurposes adds the default transaction purposes missing from the lookup table; purposes
+// an admin has since changed are left as they are
+func seedTransactionPurposes(db *gorm.DB) error {
+	purposes := models.DefaultTransactionPurposes()
+	if err := db.Clauses(clause.OnConflict{DoNothing: true}).Create(&purposes).Error; err != nil {
+		return fmt.Errorf("failed to seed transaction purposes: %v", err)
+	}
+	return nil
+}
+
+// dropLegacyPurposeChecks drops the CHECK constraints that limited transaction purposes to a fixed list
+// before they moved to the transaction_purposes lookup table
+func dropLegacyPurposeChecks(db *gorm.DB) error {
+	legacy := []struct {
+		model interface{}
+		name  string
+	}{
+		{&models.Transaction{}, "chk_transactions_transaction_purpose"},
+		{&models.ArchivedTransaction{}, "chk_archived_transactions_transaction_purpose"},
+	}
+	for _, check := range legacy {
+		if !db.Migrator().HasConstraint(check.model, check.name) {
+			continue
+		}
+		if err := db.Migrator().DropConstraint(check.model, check.name); err != nil {
+			return fmt.Errorf("failed to drop %s: %v", check.name, err)
+		}
+	}
+	return nil
+}
+
+// dropLegacyLedgerAccountIndex drops the unique index that allowed one chart of ledger accounts per
+// deployment, before each tenant got a chart of its own
+func dropLegacyLedgerAccountIndex(db *gorm.DB) error {
+	const legacy = "idx_ledger_account_code"
+	if !db.Migrator().HasIndex(&models.LedgerAccount{}, legacy) {
+		return nil
+	}
+	if err := db.Migrator().DropIndex(&models.LedgerAccount{}, legacy); err != nil {
+		return fmt.Errorf("failed to drop %s: %v", legacy, err)
+	}
+	return nil
+}
+
+// bootstrapDefaultWalletTier creates the fee-free, unlimited tier used by wallets without an assigned tier
+func bootstrapDefaultWalletTier(db *gorm.DB) error {
+	var count int64
+	if err := db.Model(&models.WalletTier{}).Where("is_default = ?", true).Count(&count).Error; err != nil {
+		return fmt.Errorf("failed to check for default wallet tier: %v", err)
+	}
+	if count > 0 {
+		return nil
+	}
+
+	tier := &models.WalletTier{
+		Name:                  models.DefaultWalletTierName,
+		Description:           "Default plan with no fees or limits",
+		IsDefault:             true,
+		PaymentLinksEnabled:   true,
+		QRPaymentsEnabled:     true,
+		StandingOrdersEnabled: true,
+	}
+	if err := db.Create(tier).Error; err != nil {
+		return fmt.Errorf("failed to create default wallet tier: %v", err)
+	}
+
+	log.Printf("Default wallet tier %q created with ID: %d", tier.Name, tier.ID)
+	return nil
+}
+
+// bootstrapSandboxAccount creates the system account whose wallet backs sandbox test money
+func bootstrapSandboxAccount(db *gorm.DB, cfg *config.Config) error {
+	if !cfg.App.SandboxEnabled {
+		return nil
+	}
+	return ensureSystemAccount(db, models.CreateSandboxSystemUser(), true)
+}
+
+// ensureSystemAccount creates the given system user and its wallet unless they already exist
+func ensureSystemAccount(db *gorm.DB, systemUser *models.User, sandbox bool) error {
 	// Check if system account already exists
 	var existingUser models.User
-	if err := db.Where("email = ? AND is_system = ?", models.SystemAccountEmail, true).First(&existingUser).Error; err != nil {
+	if err := db.Where("tenant_id = ? AND email = ? AND is_system = ?", models.DefaultTenantID, systemUser.Email, true).First(&existingUser).Error; err != nil {
 		if err == gorm.ErrRecordNotFound {
-			systemUser := models.CreateSystemUser()
-
 			if err := systemUser.HashPassword(systemUser.Password); err != nil {
 				return fmt.Errorf("failed to hash system account password: %v", err)
 			}
@@ -159,22 +410,105 @@ func bootstrapSystemAccount(db *gorm.DB) error {
 
 			systemWallet := &models.Wallet{
 				UserID:   systemUser.ID,
-				Balance:  decimal.NewFromInt(1000000000), // 1 billion as initial system balance
+				Balance:  models.SystemWalletOpeningBalance,
 				Currency: "USD",
 				Status:   models.WalletStatusActive,
+				Sandbox:  sandbox,
 			}
 
 			if err := db.Create(systemWallet).Error; err != nil {
 				return fmt.Errorf("failed to create system wallet: %v", err)
 			}
 
-			log.Printf("System account and wallet created successfully with ID: %d", systemUser.ID)
+			log.Printf("System account %s and wallet created successfully with ID: %d", systemUser.Email, systemUser.ID)
 		} else {
 			return fmt.Errorf("failed to check for existing system account: %v", err)
 		}
+		existingUser = *systemUser
 	} else {
-		log.Printf("System account already exists with ID: %d", existingUser.ID)
+		log.Printf("System account %s already exists with ID: %d", existingUser.Email, existingUser.ID)
+	}
+
+	return ensureLedgerAccounts(db, &existingUser, sandbox)
+}
+
+// ensureLedgerAccounts creates the missing accounts of the chart of ledger accounts. Cash is the
+// system account's first wallet; every other account gets a wallet of its own, starting empty
+func ensureLedgerAccounts(db *gorm.DB, systemUser *models.User, sandbox bool) error {
+	for _, account := range models.DefaultLedgerAccounts() {
+		var count int64
+		if err := db.Model(&models.LedgerAccount{}).
+			Where("tenant_id = ? AND code = ? AND sandbox = ?", models.DefaultTenantID, account.Code, sandbox).
+			Count(&count).Error; err != nil {
+			return fmt.Errorf("failed to check for ledger account %s: %v", account.Code, err)
+		}
+		if count > 0 {
+			continue
+		}
+
+		var wallet models.Wallet
+		if account.Code == models.LedgerAccountCash {
+			if err := db.Where("user_id = ? AND sandbox = ?", systemUser.ID, sandbox).Order("id ASC").First(&wallet).Error; err != nil {
+				return fmt.Errorf("failed to load system wallet: %v", err)
+			}
+		} else {
+			wallet = models.Wallet{
+				UserID:   systemUser.ID,
+				Balance:  decimal.Zero,
+				Currency: "USD",
+				Status:   models.WalletStatusActive,
+				Sandbox:  sandbox,
+			}
+			if err := db.Create(&wallet).Error; err != nil {
+				return fmt.Errorf("failed to create wallet of ledger account %s: %v", account.Code, err)
+			}
+		}
+
+		account.TenantID = models.DefaultTenantID
+		account.Sandbox = sandbox
+		account.WalletID = wallet.ID
+		if err := db.Create(&account).Error; err != nil {
+			return fmt.Errorf("failed to create ledger account %s: %v", account.Code, err)
+		}
+		log.Printf("Ledger account %s created with wallet ID: %d", account.Code, wallet.ID)
+	}
+	return nil
+}
+
+// bootstrapAdminAccount creates or promotes the configured administrator account
+func bootstrapAdminAccount(db *gorm.DB, cfg *config.Config) error {
+	if cfg.App.AdminEmail == "" || cfg.App.AdminPassword == "" {
+		return nil
+	}
+
+	var existingUser models.User
+	err := db.Where("tenant_id = ? AND email = ?", models.DefaultTenantID, cfg.App.AdminEmail).First(&existingUser).Error
+	if err == nil {
+		if existingUser.IsAdmin() {
+			return nil
+		}
+		if err := db.Model(&existingUser).Update("role", models.UserRoleAdmin).Error; err != nil {
+			return fmt.Errorf("failed to promote admin user: %v", err)
+		}
+		log.Printf("User %d promoted to admin", existingUser.ID)
+		return nil
+	}
+	if err != gorm.ErrRecordNotFound {
+		return fmt.Errorf("failed to check for existing admin account: %v", err)
+	}
+
+	adminUser := &models.User{
+		Name:  "Administrator",
+		Email: cfg.App.AdminEmail,
+		Role:  models.UserRoleAdmin,
+	}
+	if err := adminUser.HashPassword(cfg.App.AdminPassword); err != nil {
+		return fmt.Errorf("failed to hash admin password: %v", err)
+	}
+	if err := db.Create(adminUser).Error; err != nil {
+		return fmt.Errorf("failed to create admin user: %v", err)
 	}
 
+	log.Printf("Admin account created successfully with ID: %d", adminUser.ID)
 	return nil
 }
@@ -0,0 +1,39 @@
+package database
+
+import (
+	"database/sql"
+
+	"github.com/limistah/wallet-service/internal/metrics"
+)
+
+// RegisterPoolMetrics exports the state of the connection pool of sqlDB, read from its stats on every
+// scrape, so an exhausted pool shows up as connections in use at the limit and a growing wait count
+func RegisterPoolMetrics(registry *metrics.Registry, sqlDB *sql.DB) {
+	stat := func(value func(sql.DBStats) float64) func() ([]metrics.Sample, error) {
+		return func() ([]metrics.Sample, error) {
+			return []metrics.Sample{{Value: value(sqlDB.Stats())}}, nil
+		}
+	}
+
+	registry.NewGaugeFunc("wallet_db_connections_max_open", "Most connections the pool may open; 0 is unlimited", nil,
+		stat(func(s sql.DBStats) float64 { return float64(s.MaxOpenConnections) }))
+	registry.NewGaugeFunc("wallet_db_connections_open", "Connections open, in use or idle", nil,
+		stat(func(s sql.DBStats) float64 { return float64(s.OpenConnections) }))
+	registry.NewGaugeFunc("wallet_db_connections_in_use", "Connections running a statement or transaction", nil,
+		stat(func(s sql.DBStats) float64 { return float64(s.InUse) }))
+	registry.NewGaugeFunc("wallet_db_connections_idle", "Connections open and waiting to be used", nil,
+		stat(func(s sql.DBStats) float64 { return float64(s.Idle) }))
+	registry.NewCounterFunc("wallet_db_connection_waits_total", "Times a statement waited for a free connection", nil,
+		stat(func(s sql.DBStats) float64 { return float64(s.WaitCount) }))
+	registry.NewCounterFunc("wallet_db_connection_wait_seconds_total", "Time spent waiting for a free connection", nil,
+		stat(func(s sql.DBStats) float64 { return s.WaitDuration.Seconds() }))
+	registry.NewCounterFunc("wallet_db_connections_closed_total", "Connections closed for being idle or too old", []string{"reason"},
+		func() ([]metrics.Sample, error) {
+			stats := sqlDB.Stats()
+			return []metrics.Sample{
+				{Labels: []string{"max_idle"}, Value: float64(stats.MaxIdleClosed)},
+				{Labels: []string{"max_idle_time"}, Value: float64(stats.MaxIdleTimeClosed)},
+				{Labels: []string{"max_lifetime"}, Value: float64(stats.MaxLifetimeClosed)},
+			}, nil
+		})
+}
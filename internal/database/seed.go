@@ -0,0 +1,216 @@
+package database
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"math/rand"
+	"time"
+
+	"github.com/limistah/wallet-service/internal/models"
+	"github.com/shopspring/decimal"
+	"gorm.io/gorm"
+)
+
+// SeedPassword is the password set on every seeded user
+const SeedPassword = "password123"
+
+// seedCurrencies are the wallet currencies seeded users are spread across
+var seedCurrencies = []string{"USD", "EUR", "GBP", "NGN"}
+
+// SeedOptions controls the size and shape of the development dataset
+type SeedOptions struct {
+	Users                 int
+	TransactionsPerWallet int
+	Mismatches            int
+	RandSeed              int64
+}
+
+// DefaultSeedOptions returns a dataset large enough for local development and demos
+func DefaultSeedOptions() SeedOptions {
+	return SeedOptions{
+		Users:                 50,
+		TransactionsPerWallet: 60,
+		Mismatches:            3,
+		RandSeed:              time.Now().UnixNano(),
+	}
+}
+
+// seeder keeps the running balances of the wallets it writes so every ledger entry stays consistent
+type seeder struct {
+	tx     *gorm.DB
+	rnd    *rand.Rand
+	runID  int64
+	seq    int
+	system *models.Wallet
+}
+
+// Seed creates users, wallets across currencies and double-entry transaction history with a mix of
+// statuses, then corrupts a few stored balances so reconciliation has mismatches to report
+func Seed(db *gorm.DB, opts SeedOptions) error {
+	if opts.Users <= 0 {
+		return errors.New("seed requires at least one user")
+	}
+	if opts.Mismatches > opts.Users {
+		opts.Mismatches = opts.Users
+	}
+
+	var systemUser models.User
+	if err := db.Where("tenant_id = ? AND email = ? AND is_system = ?", models.DefaultTenantID, models.SystemAccountEmail, true).First(&systemUser).Error; err != nil {
+		return fmt.Errorf("system account not found: %v", err)
+	}
+	var systemWallet models.Wallet
+	if err := db.Where("user_id = ? AND sandbox = ?", systemUser.ID, false).First(&systemWallet).Error; err != nil {
+		return fmt.Errorf("system wallet not found: %v", err)
+	}
+
+	// Hashing once keeps large seeds fast; every seeded user shares the same password
+	template := &models.User{}
+	if err := template.HashPassword(SeedPassword); err != nil {
+		return fmt.Errorf("failed to hash seed password: %v", err)
+	}
+
+	rnd := rand.New(rand.NewSource(opts.RandSeed))
+	runID := time.Now().Unix()
+
+	return db.Transaction(func(tx *gorm.DB) error {
+		s := &seeder{tx: tx, rnd: rnd, runID: runID, system: &systemWallet}
+
+		wallets := make([]*models.Wallet, 0, opts.Users)
+		for i := 1; i <= opts.Users; i++ {
+			user := &models.User{
+				Name:     fmt.Sprintf("Seed User %d", i),
+				Email:    fmt.Sprintf("seed-%d-%d@example.com", runID, i),
+				Password: template.Password,
+				Role:     models.UserRoleUser,
+			}
+			if err := tx.Create(user).Error; err != nil {
+				return fmt.Errorf("failed to create seed user: %v", err)
+			}
+
+			wallet := &models.Wallet{
+				UserID:   user.ID,
+				Balance:  decimal.Zero,
+				Currency: seedCurrencies[rnd.Intn(len(seedCurrencies))],
+				Status:   models.WalletStatusActive,
+			}
+			if err := tx.Create(wallet).Error; err != nil {
+				return fmt.Errorf("failed to create seed wallet: %v", err)
+			}
+			wallets = append(wallets, wallet)
+		}
+
+		for _, wallet := range wallets {
+			for n := 0; n < opts.TransactionsPerWallet; n++ {
+				if err := s.randomOperation(wallet, wallets); err != nil {
+					return err
+				}
+			}
+		}
+
+		for _, wallet := range append(wallets, s.system) {
+			if err := tx.Model(&models.Wallet{}).Where("id = ?", wallet.ID).Update("balance", wallet.Balance).Error; err != nil {
+				return fmt.Errorf("failed to store seed wallet balance: %v", err)
+			}
+		}
+
+		// Nudge stored balances away from the ledger without a transaction to explain the difference
+		for _, i := range rnd.Perm(len(wallets))[:opts.Mismatches] {
+			drift := decimal.NewFromInt(int64(rnd.Intn(5000) + 1)).Div(decimal.NewFromInt(100))
+			if err := tx.Model(&models.Wallet{}).Where("id = ?", wallets[i].ID).Update("balance", wallets[i].Balance.Add(drift)).Error; err != nil {
+				return fmt.Errorf("failed to create reconciliation mismatch: %v", err)
+			}
+			log.Printf("Seeded reconciliation mismatch on wallet %d (+%s)", wallets[i].ID, drift.StringFixed(2))
+		}
+
+		log.Printf("Seeded %d users and wallets with %d transactions each (password %q)", opts.Users, opts.TransactionsPerWallet, SeedPassword)
+		return nil
+	})
+}
+
+// randomOperation records one top-up, withdrawal or transfer for the wallet
+func (s *seeder) randomOperation(wallet *models.Wallet, wallets []*models.Wallet) error {
+	amount := decimal.NewFromInt(int64(s.rnd.Intn(50000) + 100)).Div(decimal.NewFromInt(100))
+
+	switch roll := s.rnd.Intn(10); {
+	case roll < 4 || wallet.Balance.LessThan(amount):
+		status := models.TransactionStatusCompleted
+		if s.rnd.Intn(10) == 0 {
+			status = models.TransactionStatusFailed
+		}
+		return s.record(s.system, wallet, amount, models.TransactionPurposeWalletTopUp, status)
+	case roll < 7:
+		status := models.TransactionStatusCompleted
+		switch s.rnd.Intn(10) {
+		case 0:
+			status = models.TransactionStatusPending
+		case 1:
+			status = models.TransactionStatusFailed
+		}
+		return s.record(wallet, s.system, amount, models.TransactionPurposeWithdrawal, status)
+	default:
+		recipient := wallets[s.rnd.Intn(len(wallets))]
+		if recipient.ID == wallet.ID || recipient.Currency != wallet.Currency {
+			return s.record(s.system, wallet, amount, models.TransactionPurposeWalletTopUp, models.TransactionStatusCompleted)
+		}
+		return s.record(wallet, recipient, amount, models.TransactionPurposeTransfer, models.TransactionStatusCompleted)
+	}
+}
+
+// record writes a debit/credit pair and applies it to the running balances; pending debits hold funds
+// like the payout flow does, failed pairs move nothing
+func (s *seeder) record(from, to *models.Wallet, amount decimal.Decimal, purpose models.TransactionPurpose, status models.TransactionStatus) error {
+	s.seq++
+	reference := fmt.Sprintf("SEED-%d-%06d", s.runID, s.seq)
+	createdAt := time.Now().Add(-time.Duration(s.rnd.Intn(90*24*60)) * time.Minute)
+
+	fromAfter, toAfter := from.Balance, to.Balance
+	switch status {
+	case models.TransactionStatusCompleted:
+		fromAfter = from.Balance.Sub(amount)
+		toAfter = to.Balance.Add(amount)
+	case models.TransactionStatusPending:
+		fromAfter = from.Balance.Sub(amount)
+	}
+
+	debit := &models.Transaction{
+		CreatedAt:          createdAt,
+		Reference:          reference + "_debit",
+		WalletID:           from.ID,
+		TransactionPurpose: purpose,
+		TransactionType:    models.TransactionTypeDebit,
+		Amount:             amount,
+		BalanceBefore:      from.Balance,
+		BalanceAfter:       fromAfter,
+		Description:        "Seeded " + string(purpose),
+		Metadata:           `{"source": "seed"}`,
+		Status:             status,
+	}
+	if err := s.tx.Create(debit).Error; err != nil {
+		return fmt.Errorf("failed to create seed transaction: %v", err)
+	}
+
+	credit := &models.Transaction{
+		CreatedAt:            createdAt,
+		Reference:            reference,
+		WalletID:             to.ID,
+		TransactionPurpose:   purpose,
+		TransactionType:      models.TransactionTypeCredit,
+		Amount:               amount,
+		BalanceBefore:        to.Balance,
+		BalanceAfter:         toAfter,
+		Description:          "Seeded " + string(purpose),
+		Metadata:             `{"source": "seed"}`,
+		Status:               status,
+		RelatedTransactionID: &debit.ID,
+	}
+	if err := s.tx.Create(credit).Error; err != nil {
+		return fmt.Errorf("failed to create seed transaction: %v", err)
+	}
+	if err := s.tx.Model(debit).Update("related_transaction_id", credit.ID).Error; err != nil {
+		return fmt.Errorf("failed to link seed transactions: %v", err)
+	}
+
+	from.Balance, to.Balance = fromAfter, toAfter
+	return nil
+}
@@ -0,0 +1,106 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"net/url"
+	"strings"
+	"sync"
+
+	"github.com/limistah/wallet-service/internal/config"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+)
+
+// sqliteDialector opens the SQLite database at path with the pragmas of cfg set on every connection.
+// With SQLiteSingleWriter the transactions of the process take turns: two transactions that read
+// and then write would otherwise both hold a snapshot, and the second to write fails with "database
+// is locked" however long the busy timeout is
+func sqliteDialector(path string, cfg config.DatabaseConfig) (gorm.Dialector, error) {
+	dsn := sqliteDSN(path, cfg)
+	if !cfg.SQLiteSingleWriter {
+		return sqlite.Open(dsn), nil
+	}
+
+	sqlDB, err := sql.Open(sqlite.DriverName, dsn)
+	if err != nil {
+		return nil, err
+	}
+	return sqlite.New(sqlite.Config{DSN: dsn, Conn: newSingleWriterPool(sqlDB)}), nil
+}
+
+// sqliteDSN adds the pragmas of cfg to path as the connection parameters of the driver
+func sqliteDSN(path string, cfg config.DatabaseConfig) string {
+	params := url.Values{}
+	if cfg.SQLiteWAL {
+		params.Set("_journal_mode", "WAL")
+	}
+	if cfg.SQLiteBusyTimeout > 0 {
+		params.Set("_busy_timeout", fmt.Sprint(cfg.SQLiteBusyTimeout.Milliseconds()))
+	}
+	if cfg.SQLiteForeignKeys {
+		params.Set("_foreign_keys", "1")
+	}
+	if len(params) == 0 {
+		return path
+	}
+
+	separator := "?"
+	if strings.Contains(path, "?") {
+		separator = "&"
+	}
+	return path + separator + params.Encode()
+}
+
+// singleWriterPool lets one transaction of the process run at a time; statements outside transactions
+// are left to the busy timeout. A transaction waiting for its turn gives up when its context is done
+type singleWriterPool struct {
+	*sql.DB
+	turn chan struct{}
+}
+
+func newSingleWriterPool(db *sql.DB) *singleWriterPool {
+	return &singleWriterPool{DB: db, turn: make(chan struct{}, 1)}
+}
+
+func (p *singleWriterPool) BeginTx(ctx context.Context, opts *sql.TxOptions) (gorm.ConnPool, error) {
+	select {
+	case p.turn <- struct{}{}:
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+	release := sync.OnceFunc(func() { <-p.turn })
+
+	tx, err := p.DB.BeginTx(ctx, opts)
+	if err != nil {
+		release()
+		return nil, err
+	}
+	return &singleWriterTx{Tx: tx, db: p.DB, release: release}, nil
+}
+
+func (p *singleWriterPool) GetDBConn() (*sql.DB, error) {
+	return p.DB, nil
+}
+
+// singleWriterTx hands the turn to the next transaction once it commits or rolls back
+type singleWriterTx struct {
+	*sql.Tx
+	db      *sql.DB
+	release func()
+}
+
+func (t *singleWriterTx) Commit() error {
+	defer t.release()
+	return t.Tx.Commit()
+}
+
+func (t *singleWriterTx) Rollback() error {
+	defer t.release()
+	return t.Tx.Rollback()
+}
+
+func (t *singleWriterTx) GetDBConn() (*sql.DB, error) {
+	return t.db, nil
+}
@@ -0,0 +1,78 @@
+package database
+
+import (
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/limistah/wallet-service/internal/config"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+func TestSQLiteDSN(t *testing.T) {
+	cfg := config.DatabaseConfig{SQLiteWAL: true, SQLiteBusyTimeout: 5 * time.Second, SQLiteForeignKeys: true}
+	if got, want := sqliteDSN("app.db", cfg), "app.db?_busy_timeout=5000&_foreign_keys=1&_journal_mode=WAL"; got != want {
+		t.Errorf("sqliteDSN() = %q, want %q", got, want)
+	}
+	if got, want := sqliteDSN("file:app.db?cache=shared", config.DatabaseConfig{SQLiteForeignKeys: true}), "file:app.db?cache=shared&_foreign_keys=1"; got != want {
+		t.Errorf("sqliteDSN() = %q, want %q", got, want)
+	}
+	if got := sqliteDSN("app.db", config.DatabaseConfig{}); got != "app.db" {
+		t.Errorf("Expected no parameters without pragmas, got %q", got)
+	}
+}
+
+func TestSQLiteSingleWriter_ConcurrentTransactions(t *testing.T) {
+	cfg := config.DatabaseConfig{SQLiteWAL: true, SQLiteBusyTimeout: 100 * time.Millisecond, SQLiteSingleWriter: true}
+	dialector, err := sqliteDialector(filepath.Join(t.TempDir(), "wallet.db"), cfg)
+	if err != nil {
+		t.Fatalf("sqliteDialector() error = %v", err)
+	}
+	db, err := gorm.Open(dialector, &gorm.Config{Logger: logger.Default.LogMode(logger.Silent)})
+	if err != nil {
+		t.Fatalf("Failed to open database: %v", err)
+	}
+	if err := db.Exec("CREATE TABLE balances (id INTEGER PRIMARY KEY, amount INTEGER NOT NULL)").Error; err != nil {
+		t.Fatalf("Failed to create table: %v", err)
+	}
+	if err := db.Exec("INSERT INTO balances (id, amount) VALUES (1, 0)").Error; err != nil {
+		t.Fatalf("Failed to insert balance: %v", err)
+	}
+
+	// Each transfer reads the balance before writing it, which fails with "database is locked" when
+	// two transactions interleave
+	const transfers = 20
+	var wg sync.WaitGroup
+	errs := make(chan error, transfers)
+	for i := 0; i < transfers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			errs <- db.Transaction(func(tx *gorm.DB) error {
+				var amount int
+				if err := tx.Raw("SELECT amount FROM balances WHERE id = 1").Scan(&amount).Error; err != nil {
+					return err
+				}
+				time.Sleep(time.Millisecond)
+				return tx.Exec("UPDATE balances SET amount = ? WHERE id = 1", amount+1).Error
+			})
+		}()
+	}
+	wg.Wait()
+	close(errs)
+	for err := range errs {
+		if err != nil {
+			t.Fatalf("Transaction error = %v", err)
+		}
+	}
+
+	var amount int
+	if err := db.Raw("SELECT amount FROM balances WHERE id = 1").Scan(&amount).Error; err != nil || amount != transfers {
+		t.Errorf("Expected every transfer to apply, got %d, %v", amount, err)
+	}
+	if sqlDB, err := db.DB(); err != nil || sqlDB == nil {
+		t.Errorf("Expected the pool to expose its *sql.DB, got %v", err)
+	}
+}
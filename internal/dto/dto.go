@@ -1,27 +1,82 @@
 package dto
 
 import (
+	"strconv"
+	"strings"
 	"time"
 
+	"github.com/limistah/wallet-service/internal/breaker"
+	"github.com/limistah/wallet-service/internal/fx"
 	"github.com/limistah/wallet-service/internal/models"
 	"github.com/shopspring/decimal"
 )
 
 // UserResponse represents user response data
 type UserResponse struct {
-	ID        uint      `json:"id" example:"1"`
-	CreatedAt time.Time `json:"created_at" example:"2023-01-01T00:00:00Z"`
-	UpdatedAt time.Time `json:"updated_at" example:"2023-01-01T00:00:00Z"`
-	Name      string    `json:"name" example:"John Doe"`
-	Email     string    `json:"email" example:"john.doe@example.com"`
-	Age       int       `json:"age" example:"30"`
+	ID            uint      `json:"id" example:"1"`
+	CreatedAt     time.Time `json:"created_at" example:"2023-01-01T00:00:00Z"`
+	UpdatedAt     time.Time `json:"updated_at" example:"2023-01-01T00:00:00Z"`
+	Name          string    `json:"name" example:"John Doe"`
+	Email         string    `json:"email" example:"john.doe@example.com"`
+	Age           int       `json:"age" example:"30"`
+	PhoneNumber   string    `json:"phone_number,omitempty" example:"+2348012345678"`
+	PhoneVerified bool      `json:"phone_verified" example:"true"`
+	Handle        string    `json:"handle,omitempty" example:"alice"`
+	// DateOfBirth is a YYYY-MM-DD date
+	DateOfBirth string          `json:"date_of_birth,omitempty" example:"1990-04-21"`
+	Address     *AddressPayload `json:"address,omitempty"`
+	// DisplayCurrency is the currency balances and spending are also shown in
+	DisplayCurrency string `json:"display_currency,omitempty" example:"NGN"`
+	// AvatarURL serves the profile picture; it changes with every upload
+	AvatarURL string `json:"avatar_url,omitempty" example:"/api/v1/users/me/avatar?v=1672531200"`
 } //@name UserResponse
 
+// AddressPayload represents a postal address; country is an ISO 3166-1 alpha-2 code
+type AddressPayload struct {
+	Line1      string `json:"line1" example:"12 Marina Road"`
+	Line2      string `json:"line2,omitempty" example:"Flat 4"`
+	City       string `json:"city" example:"Lagos"`
+	State      string `json:"state,omitempty" example:"Lagos"`
+	PostalCode string `json:"postal_code,omitempty" example:"101241"`
+	Country    string `json:"country" example:"NG"`
+} //@name AddressPayload
+
+// UpdateProfileRequest represents a profile update; fields left out are not changed
+type UpdateProfileRequest struct {
+	Name *string `json:"name,omitempty" example:"John Doe"`
+	// PhoneNumber is E.164; a changed number has to be verified again, and an empty one removes it
+	PhoneNumber *string `json:"phone_number,omitempty" example:"+2348012345678"`
+	DateOfBirth *string `json:"date_of_birth,omitempty" example:"1990-04-21"`
+	// Address replaces the whole address; an empty object removes it
+	Address *AddressPayload `json:"address,omitempty"`
+	// DisplayCurrency shows balances and spending also in this currency at indicative rates; an empty
+	// string turns it off
+	DisplayCurrency *string `json:"display_currency,omitempty" example:"NGN"`
+} //@name UpdateProfileRequest
+
+// SetHandleRequest represents a request to choose the handle other users pay the authenticated user by
+type SetHandleRequest struct {
+	Handle string `json:"handle" binding:"max=31" example:"alice"` // Written with or without its @; empty removes it
+} //@name SetHandleRequest
+
+// HandleResponse is the user going by a handle and the wallet payments to them land in
+type HandleResponse struct {
+	Handle   string `json:"handle" example:"alice"`
+	Name     string `json:"name" example:"Alice Johnson"`
+	WalletID uint   `json:"wallet_id" example:"2"`
+	Currency string `json:"currency" example:"USD"`
+} //@name HandleResponse
+
+// DeleteAccountRequest confirms the deletion of the authenticated user's account with their password
+type DeleteAccountRequest struct {
+	Password string `json:"password" binding:"required" example:"password123"`
+} //@name DeleteAccountRequest
+
 // CreateUserRequest represents user creation request
 type CreateUserRequest struct {
 	Name     string `json:"name" binding:"required" example:"John Doe"`
 	Email    string `json:"email" binding:"required,email" example:"john.doe@example.com"`
-	Password string `json:"password" binding:"required,min=6" example:"password123"`
+	Password string `json:"password" binding:"required" example:"Password123"` // Must follow the password policy
 	Age      int    `json:"age" example:"30"`
 } //@name CreateUserRequest
 
@@ -35,6 +90,8 @@ type UpdateUserRequest struct {
 type LoginRequest struct {
 	Email    string `json:"email" binding:"required,email" example:"john.doe@example.com"`
 	Password string `json:"password" binding:"required" example:"password123"`
+	// Sandbox issues a token whose wallet operations use isolated test money
+	Sandbox bool `json:"sandbox,omitempty" example:"false"`
 } //@name LoginRequest
 
 // LoginResponse represents user login response
@@ -43,22 +100,127 @@ type LoginResponse struct {
 	Token string       `json:"token" example:"eyJhbGciOiJIUzI1NiIsInR5cCI6IkpXVCJ9..."`
 } //@name LoginResponse
 
+// LoginAttemptResponse represents a login to the account, successful or not
+type LoginAttemptResponse struct {
+	ID            uint      `json:"id" example:"1"`
+	CreatedAt     time.Time `json:"created_at" example:"2023-01-01T00:00:00Z"`
+	IPAddress     string    `json:"ip_address" example:"203.0.113.7"`
+	UserAgent     string    `json:"user_agent" example:"Mozilla/5.0 (iPhone; CPU iPhone OS 17_0 like Mac OS X)"`
+	Method        string    `json:"method" example:"password"`
+	Succeeded     bool      `json:"succeeded" example:"true"`
+	TwoFactorUsed bool      `json:"two_factor_used" example:"false"`
+} //@name LoginAttemptResponse
+
+// SessionResponse represents a device signed in to the account
+type SessionResponse struct {
+	ID        uint      `json:"id" example:"1"`
+	CreatedAt time.Time `json:"created_at" example:"2023-01-01T00:00:00Z"`
+	IPAddress string    `json:"ip_address" example:"203.0.113.7"`
+	UserAgent string    `json:"user_agent" example:"WalletApp/2.1 (iPhone; iOS 17.0)"`
+	IssuedAt  time.Time `json:"issued_at" example:"2023-01-01T00:00:00Z"` // When the latest token of the device was issued
+	ExpiresAt time.Time `json:"expires_at" example:"2023-01-02T00:00:00Z"`
+	Current   bool      `json:"current" example:"true"` // The session of the token used for the request
+} //@name SessionResponse
+
+// TransferChallengeResponse tells the client a transfer is held until confirmed with a one-time code
+type TransferChallengeResponse struct {
+	ChallengeID uint      `json:"challenge_id" example:"12"`
+	ExpiresAt   time.Time `json:"expires_at" example:"2023-01-01T00:05:00Z"`
+	Channel     string    `json:"channel" example:"sms"`
+} //@name TransferChallengeResponse
+
+// ConfirmTransferRequest represents the confirmation of a held transfer
+type ConfirmTransferRequest struct {
+	ChallengeID uint   `json:"challenge_id" binding:"required" example:"12"`
+	Code        string `json:"code" binding:"required" example:"482913"`
+} //@name ConfirmTransferRequest
+
+// SetPINRequest represents a request to set the first transaction PIN
+type SetPINRequest struct {
+	PIN string `json:"pin" binding:"required" example:"4821"`
+} //@name SetPINRequest
+
+// ChangePINRequest represents a request to replace the transaction PIN
+type ChangePINRequest struct {
+	CurrentPIN string `json:"current_pin" binding:"required" example:"4821"`
+	NewPIN     string `json:"new_pin" binding:"required" example:"7305"`
+} //@name ChangePINRequest
+
+// ResetPINRequest represents a request to replace a forgotten transaction PIN with the account password
+type ResetPINRequest struct {
+	Password string `json:"password" binding:"required" example:"Password123"`
+	NewPIN   string `json:"new_pin" binding:"required" example:"7305"`
+} //@name ResetPINRequest
+
+// ImpersonationRequest represents a staff request to act as a user
+type ImpersonationRequest struct {
+	Reason      string `json:"reason" binding:"required,max=500" example:"Ticket #4521: user cannot see a deposit"`
+	WriteAccess bool   `json:"write_access" example:"false"` // Admins only; impersonations are read-only otherwise
+} //@name ImpersonationRequest
+
+// ImpersonationResponse carries a short-lived token acting as the user
+type ImpersonationResponse struct {
+	User      UserResponse `json:"user"`
+	Token     string       `json:"token" example:"eyJhbGciOiJIUzI1NiIsInR5cCI6IkpXVCJ9..."`
+	ExpiresAt time.Time    `json:"expires_at" example:"2023-01-01T00:15:00Z"`
+	ReadOnly  bool         `json:"read_only" example:"true"`
+} //@name ImpersonationResponse
+
 // ChangePasswordRequest represents password change request
 type ChangePasswordRequest struct {
 	CurrentPassword string `json:"current_password" binding:"required" example:"oldpassword123"`
-	NewPassword     string `json:"new_password" binding:"required,min=6" example:"newpassword123"`
+	NewPassword     string `json:"new_password" binding:"required" example:"NewPassword123"` // Must follow the password policy and differ from recent passwords
 } //@name ChangePasswordRequest
 
 // WalletResponse represents wallet response data
 type WalletResponse struct {
-	ID       uint            `json:"id" example:"1"`
-	UserID   uint            `json:"user_id" example:"1"`
-	Balance  decimal.Decimal `json:"balance" example:"1000.50"`
-	Currency string          `json:"currency" example:"USD"`
-	Status   string          `json:"status" example:"ACTIVE"`
-	Version  uint            `json:"version" example:"1"`
+	ID        uint            `json:"id" example:"1"`
+	UserID    uint            `json:"user_id" example:"1"`
+	Balance   decimal.Decimal `json:"balance" example:"1000.50"`
+	Currency  string          `json:"currency" example:"USD"`
+	Status    string          `json:"status" example:"ACTIVE"`
+	Version   uint            `json:"version" example:"1"`
+	Sandbox   bool            `json:"sandbox,omitempty" example:"false"`
+	TierID    *uint           `json:"tier_id,omitempty" example:"1"`
+	Nickname  string          `json:"nickname,omitempty" example:"Rent"`
+	IsDefault bool            `json:"is_default" example:"true"` // Payments addressed to the user and fundings land in the default wallet
+	// DisplayBalance is the balance in the display currency of the user, when they chose one
+	DisplayBalance *DisplayValueResponse `json:"display_balance,omitempty"`
 } //@name WalletResponse
 
+// CreateWalletRequest represents a request to open another wallet for the authenticated user
+type CreateWalletRequest struct {
+	Currency string `json:"currency" binding:"required,len=3" example:"EUR"`
+	Nickname string `json:"nickname" binding:"max=50" example:"Travel"`
+} //@name CreateWalletRequest
+
+// WalletNicknameRequest represents a request to label a wallet of the authenticated user
+type WalletNicknameRequest struct {
+	Nickname string `json:"nickname" binding:"max=50" example:"Rent"` // Empty clears the nickname
+} //@name WalletNicknameRequest
+
+// DisplayValueResponse is an amount converted to the display currency of the user at the current
+// exchange rate. It is indicative only: money moves at the rate quoted when it moves
+type DisplayValueResponse struct {
+	Amount     decimal.Decimal `json:"amount" example:"1550775.00"`
+	Currency   string          `json:"currency" example:"NGN"`
+	Rate       decimal.Decimal `json:"rate" example:"1550"`
+	Indicative bool            `json:"indicative" example:"true"`
+} //@name DisplayValueResponse
+
+// ToDisplayValueResponse converts amount for display, or returns nil without a conversion
+func ToDisplayValueResponse(conversion *fx.Conversion, amount decimal.Decimal) *DisplayValueResponse {
+	if conversion == nil {
+		return nil
+	}
+	return &DisplayValueResponse{
+		Amount:     conversion.Convert(amount),
+		Currency:   conversion.Currency,
+		Rate:       conversion.Rate,
+		Indicative: true,
+	}
+}
+
 // FundWalletRequest represents fund wallet request
 type FundWalletRequest struct {
 	Amount      decimal.Decimal `json:"amount" binding:"required" example:"100.50"`
@@ -68,19 +230,80 @@ type FundWalletRequest struct {
 
 // WithdrawRequest represents withdraw request
 type WithdrawRequest struct {
-	Amount      decimal.Decimal `json:"amount" binding:"required" example:"50.25"`
-	Reference   string          `json:"reference" binding:"required" example:"WTH123456"`
-	Description string          `json:"description" example:"ATM withdrawal"`
+	Amount      decimal.Decimal     `json:"amount" binding:"required" example:"50.25"`
+	Reference   string              `json:"reference" binding:"required" example:"WTH123456"`
+	Description string              `json:"description" example:"ATM withdrawal"`
+	BankAccount *BankAccountRequest `json:"bank_account,omitempty"`
+	PIN         string              `json:"pin,omitempty" example:"4821"` // Transaction PIN, when set and the amount is above the PIN threshold
 } //@name WithdrawRequest
 
+// BankAccountRequest represents the bank account a withdrawal is paid out to
+type BankAccountRequest struct {
+	BankCode      string `json:"bank_code" binding:"required" example:"058"`
+	AccountNumber string `json:"account_number" binding:"required" example:"0123456789"`
+	AccountName   string `json:"account_name" example:"Jane Doe"`
+} //@name BankAccountRequest
+
 // TransferRequest represents transfer request
 type TransferRequest struct {
-	ToWalletID  uint            `json:"to_wallet_id" binding:"required" example:"2"`
+	ToWalletID  uint            `json:"to_wallet_id" binding:"required_without=ToHandle" example:"2"`
+	ToHandle    string          `json:"to_handle,omitempty" binding:"required_without=ToWalletID,max=31" example:"@alice"` // Pays the default wallet of the user going by the handle
 	Amount      decimal.Decimal `json:"amount" binding:"required" example:"75.00"`
 	Reference   string          `json:"reference" binding:"required" example:"TRF123456"`
 	Description string          `json:"description" example:"Payment to friend"`
+	PIN         string          `json:"pin,omitempty" example:"4821"`    // Transaction PIN, when set and the amount is above the PIN threshold
+	QuoteID     *uint           `json:"quote_id,omitempty" example:"31"` // Quote whose fee and exchange rate the transfer is made with
 } //@name TransferRequest
 
+// TransferQuoteRequest represents a request to preview a transfer
+type TransferQuoteRequest struct {
+	ToWalletID uint            `json:"to_wallet_id" binding:"required" example:"2"`
+	Amount     decimal.Decimal `json:"amount" binding:"required" example:"75.00"`
+} //@name TransferQuoteRequest
+
+// TransferQuoteResponse is the preview of a transfer. Sending quote_id with the transfer before
+// expires_at makes it with this fee and exchange rate
+type TransferQuoteResponse struct {
+	QuoteID        uint            `json:"quote_id" example:"31"`
+	ToWalletID     uint            `json:"to_wallet_id" example:"2"`
+	Amount         decimal.Decimal `json:"amount" example:"75.00"`
+	Fee            decimal.Decimal `json:"fee" example:"0.75"`
+	TotalDebit     decimal.Decimal `json:"total_debit" example:"75.75"`
+	SourceCurrency string          `json:"source_currency" example:"USD"`
+	TargetCurrency string          `json:"target_currency" example:"NGN"`
+	ExchangeRate   decimal.Decimal `json:"exchange_rate" example:"1550"`
+	CreditAmount   decimal.Decimal `json:"credit_amount" example:"116250.00"`
+	BalanceBefore  decimal.Decimal `json:"balance_before" example:"1000.00"`
+	BalanceAfter   decimal.Decimal `json:"balance_after" example:"924.25"`
+	ExpiresAt      time.Time       `json:"expires_at" example:"2023-01-01T00:01:00Z"`
+	WalletNickname string          `json:"wallet_nickname,omitempty" example:"Travel"` // Nickname of the sender's wallet
+} //@name TransferQuoteResponse
+
+// SplitPaymentRequest represents a request to pay several wallets from the authenticated user's wallet
+type SplitPaymentRequest struct {
+	Amount      decimal.Decimal         `json:"amount" binding:"required" example:"100.00"`
+	Reference   string                  `json:"reference" binding:"required" example:"ORDER-1042"`
+	Description string                  `json:"description" example:"Marketplace order 1042"`
+	Recipients  []SplitRecipientRequest `json:"recipients" binding:"required,min=2,max=50,dive"`
+	PIN         string                  `json:"pin,omitempty" example:"4821"` // Transaction PIN, when set and the amount is above the PIN threshold
+} //@name SplitPaymentRequest
+
+// SplitRecipientRequest is a wallet credited by a split payment. Either amount or percentage is set;
+// percentages share what the fixed amounts leave of the payment and must add up to 100
+type SplitRecipientRequest struct {
+	WalletID   uint            `json:"wallet_id" binding:"required" example:"2"`
+	Amount     decimal.Decimal `json:"amount" example:"10.00"`
+	Percentage decimal.Decimal `json:"percentage" example:"0"`
+} //@name SplitRecipientRequest
+
+// SplitPaymentResponse is a split payment: the debit of the paying wallet and a credit per recipient,
+// all posted under one journal entry named by the reference
+type SplitPaymentResponse struct {
+	Reference string                `json:"reference" example:"ORDER-1042"`
+	Debit     TransactionResponse   `json:"debit"`
+	Credits   []TransactionResponse `json:"credits"`
+} //@name SplitPaymentResponse
+
 // TransactionResponse represents transaction response data
 type TransactionResponse struct {
 	ID                 uint            `json:"id" example:"1"`
@@ -93,9 +316,80 @@ type TransactionResponse struct {
 	BalanceBefore      decimal.Decimal `json:"balance_before" example:"900.00"`
 	BalanceAfter       decimal.Decimal `json:"balance_after" example:"1000.50"`
 	Description        string          `json:"description" example:"Deposit from bank"`
+	Category           string          `json:"category,omitempty" example:"groceries"`
 	Status             string          `json:"status" example:"COMPLETED"`
+	// WalletNickname is the nickname the user gave the wallet, on the legs of transfers made from it
+	WalletNickname string `json:"wallet_nickname,omitempty" example:"Travel"`
 } //@name TransactionResponse
 
+// TransactionLegResponse is the other side of a user's transaction, without the counterparty's balances
+type TransactionLegResponse struct {
+	ID                 uint            `json:"id" example:"2"`
+	CreatedAt          time.Time       `json:"created_at" example:"2023-01-01T00:00:00Z"`
+	Reference          string          `json:"reference" example:"TRF123456-IN"`
+	WalletID           uint            `json:"wallet_id" example:"2"`
+	TransactionType    string          `json:"transaction_type" example:"CREDIT"`
+	TransactionPurpose string          `json:"transaction_purpose" example:"TRANSFER"`
+	Amount             decimal.Decimal `json:"amount" example:"75.00"`
+	Status             string          `json:"status" example:"COMPLETED"`
+} //@name TransactionLegResponse
+
+// TransactionLookupResponse is a transaction of the user's wallet found by reference, with its
+// double-entry counter leg
+type TransactionLookupResponse struct {
+	Transaction TransactionResponse     `json:"transaction"`
+	CounterLeg  *TransactionLegResponse `json:"counter_leg,omitempty"`
+} //@name TransactionLookupResponse
+
+// TransactionStatusEntry is a status a transaction held and when it moved to it
+type TransactionStatusEntry struct {
+	Status    string    `json:"status" example:"COMPLETED"`
+	Reason    string    `json:"reason,omitempty" example:"approved after review"`
+	ChangedAt time.Time `json:"changed_at" example:"2023-01-01T00:05:00Z"`
+} //@name TransactionStatusEntry
+
+// TransactionDetailResponse is a transaction of the user's wallet with its metadata, double-entry
+// counter leg and status history, oldest status first
+type TransactionDetailResponse struct {
+	TransactionResponse
+	Metadata             string                   `json:"metadata" example:"{\"source\": \"funding\"}"`
+	RelatedTransactionID *uint                    `json:"related_transaction_id,omitempty" example:"2"`
+	RelatedTransaction   *TransactionLegResponse  `json:"related_transaction,omitempty"`
+	StatusHistory        []TransactionStatusEntry `json:"status_history"`
+} //@name TransactionDetailResponse
+
+// TransactionTypeResponse represents a transaction type transactions can be recorded with
+type TransactionTypeResponse struct {
+	Name        string `json:"name" example:"CREDIT"`
+	Description string `json:"description" example:"Money into the wallet"`
+} //@name TransactionTypeResponse
+
+// TransactionPurposeRequest sets the description of a transaction purpose and the hooks that tune how
+// its transactions are handled
+type TransactionPurposeRequest struct {
+	Description          string `json:"description" binding:"required,max=255" example:"Interest paid on a balance"`
+	ChargesFees          bool   `json:"charges_fees" example:"false"`                                                             // Apply the wallet tier's fees to its withdrawals and transfers
+	CountsTowardLimits   bool   `json:"counts_toward_limits" example:"false"`                                                     // Count its debits against the wallet tier's limits
+	NotificationTemplate string `json:"notification_template,omitempty" binding:"max=255" example:"Interest paid to your wallet"` // Subject of the emails about its transactions
+} //@name TransactionPurposeRequest
+
+// CreateTransactionPurposeRequest defines a new transaction purpose
+type CreateTransactionPurposeRequest struct {
+	Name string `json:"name" binding:"required,max=20" example:"CASHBACK"`
+	TransactionPurposeRequest
+} //@name CreateTransactionPurposeRequest
+
+// TransactionPurposeResponse represents a purpose transactions can be recorded with and its hooks
+type TransactionPurposeResponse struct {
+	Name                 string    `json:"name" example:"INTEREST"`
+	Description          string    `json:"description" example:"Interest paid on a balance"`
+	ChargesFees          bool      `json:"charges_fees" example:"false"`
+	CountsTowardLimits   bool      `json:"counts_toward_limits" example:"false"`
+	NotificationTemplate string    `json:"notification_template,omitempty" example:"Interest paid to your wallet"`
+	BuiltIn              bool      `json:"built_in" example:"true"` // Posted by the service itself, so it cannot be deleted
+	UpdatedAt            time.Time `json:"updated_at" example:"2023-01-01T00:00:00Z"`
+} //@name TransactionPurposeResponse
+
 // TransactionHistoryResponse represents cursor-paginated transaction history
 type TransactionHistoryResponse struct {
 	Transactions []TransactionResponse `json:"transactions"`
@@ -110,10 +404,79 @@ type ReconciliationReportResponse struct {
 	StoredBalance     decimal.Decimal `json:"stored_balance" example:"1000.50"`
 	CalculatedBalance decimal.Decimal `json:"calculated_balance" example:"1000.50"`
 	Difference        decimal.Decimal `json:"difference" example:"0.00"`
-	Status            string          `json:"status" example:"MATCH"`
-	Notes             string          `json:"notes" example:"Balance matches"`
+	Status            string          `json:"status" example:"MISMATCH"`
+	BrokenPairs       int             `json:"broken_pairs" example:"0"`
+	Notes             string          `json:"notes" example:"Balance mismatch detected. Difference: 25.00"`
+	// The follow-up of reports with an issue; left out of matching reports
+	ResolutionStatus   string     `json:"resolution_status,omitempty" example:"ACKNOWLEDGED"`
+	AssigneeID         *uint      `json:"assignee_id,omitempty" example:"3"`
+	AcknowledgedBy     *uint      `json:"acknowledged_by,omitempty" example:"3"`
+	AcknowledgedAt     *time.Time `json:"acknowledged_at,omitempty" example:"2023-01-01T09:00:00Z"`
+	InvestigationNotes string     `json:"investigation_notes,omitempty" example:"[2023-01-01 09:00] admin 3: Fee leg missing from the journal"`
+	ResolutionType     string     `json:"resolution_type,omitempty" example:"DATA_FIX"`
+	ResolvedBy         *uint      `json:"resolved_by,omitempty" example:"3"`
+	ResolvedAt         *time.Time `json:"resolved_at,omitempty" example:"2023-01-01T12:00:00Z"`
 } //@name ReconciliationReportResponse
 
+// ReconciliationReportNoteRequest records an operator note on a reconciliation report
+type ReconciliationReportNoteRequest struct {
+	Note string `json:"note,omitempty" binding:"max=1000" example:"Fee leg missing from the journal"`
+} //@name ReconciliationReportNoteRequest
+
+// AssignReconciliationReportRequest hands a reconciliation report to an admin or support operator
+type AssignReconciliationReportRequest struct {
+	AssigneeID uint `json:"assignee_id" binding:"required" example:"3"`
+} //@name AssignReconciliationReportRequest
+
+// ResolveReconciliationReportRequest closes a reconciliation report with how its issue was settled
+type ResolveReconciliationReportRequest struct {
+	ResolutionType string `json:"resolution_type" binding:"required,oneof=ADJUSTED FALSE_POSITIVE DATA_FIX" example:"DATA_FIX"`
+	Note           string `json:"note,omitempty" binding:"max=1000" example:"Replayed the missing fee leg"`
+} //@name ResolveReconciliationReportRequest
+
+// ReconciliationSummaryResponse represents the daily summary of reconciliation reports
+type ReconciliationSummaryResponse struct {
+	ID                 uint                              `json:"id" example:"1"`
+	Date               string                            `json:"date" example:"2023-01-01"`
+	Checks             int                               `json:"checks" example:"120"`
+	WalletsChecked     int                               `json:"wallets_checked" example:"100"`
+	Matches            int                               `json:"matches" example:"98"`
+	Mismatches         int                               `json:"mismatches" example:"2"`
+	DoubleEntryErrors  int                               `json:"double_entry_errors" example:"0"`
+	LargestDifferences []models.ReconciliationDifference `json:"largest_differences"`
+	DeliveredAt        *time.Time                        `json:"delivered_at,omitempty" example:"2023-01-02T01:00:00Z"`
+} //@name ReconciliationSummaryResponse
+
+// StartReconciliationRunRequest narrows a reconciliation run to a cohort of wallets; every criterion
+// is optional and an empty request reconciles every wallet
+type StartReconciliationRunRequest struct {
+	Currency   string     `json:"currency,omitempty" example:"NGN"`
+	Status     string     `json:"status,omitempty" binding:"omitempty,oneof=ACTIVE SUSPENDED CLOSED" example:"ACTIVE"`
+	ActiveFrom *time.Time `json:"active_from,omitempty" example:"2024-01-02T09:00:00Z"` // Wallets last changed at or after
+	ActiveTo   *time.Time `json:"active_to,omitempty" example:"2024-01-02T12:00:00Z"`   // Wallets last changed before
+	WalletIDs  []uint     `json:"wallet_ids,omitempty" binding:"omitempty,max=1000" example:"12,57"`
+} //@name StartReconciliationRunRequest
+
+// ReconciliationRunResponse represents a bulk reconciliation run and how far it has got. Failures lists
+// the first wallets that could not be reconciled; failed_wallets counts all of them
+type ReconciliationRunResponse struct {
+	ID                uint                              `json:"id" example:"1"`
+	CreatedAt         time.Time                         `json:"created_at" example:"2024-01-02T09:00:00Z"`
+	RequestedBy       uint                              `json:"requested_by" example:"1"`
+	Status            string                            `json:"status" example:"RUNNING"`
+	Progress          int                               `json:"progress" example:"40"` // Percent of the wallets processed
+	TotalWallets      int                               `json:"total_wallets" example:"1000"`
+	ProcessedWallets  int                               `json:"processed_wallets" example:"400"`
+	Matches           int                               `json:"matches" example:"396"`
+	Mismatches        int                               `json:"mismatches" example:"2"`
+	DoubleEntryErrors int                               `json:"double_entry_errors" example:"1"`
+	FailedWallets     int                               `json:"failed_wallets" example:"1"`
+	Failures          []models.ReconciliationRunFailure `json:"failures"`
+	Scope             models.ReconciliationScope        `json:"scope"`
+	StartedAt         *time.Time                        `json:"started_at,omitempty" example:"2024-01-02T09:00:01Z"`
+	CompletedAt       *time.Time                        `json:"completed_at,omitempty" example:"2024-01-02T09:04:30Z"`
+} //@name ReconciliationRunResponse
+
 // PaginationMeta represents pagination metadata
 type PaginationMeta struct {
 	Page      int `json:"page" example:"1"`
@@ -137,40 +500,1161 @@ type APIResponse struct {
 	Error   string      `json:"error,omitempty" example:""`
 } //@name APIResponse
 
-// ErrorResponse represents an error response
+// ErrorResponse represents an error response; Code is the machine-readable code of domain errors
+// (see the apperrors package) and is omitted for unexpected failures
 type ErrorResponse struct {
 	Success bool   `json:"success" example:"false"`
 	Message string `json:"message" example:"Operation failed"`
 	Error   string `json:"error" example:"Validation error"`
+	Code    string `json:"code,omitempty" example:"INSUFFICIENT_FUNDS"`
+	// Violations lists every constraint of the API documentation a request breaks, when it was
+	// refused for them
+	Violations []ViolationResponse `json:"violations,omitempty"`
 } //@name ErrorResponse
 
+// ViolationResponse is a constraint of the API documentation a request breaks
+type ViolationResponse struct {
+	In      string `json:"in" example:"body"` // body, query, path or header
+	Field   string `json:"field" example:"bank_account.account_number"`
+	Rule    string `json:"rule" example:"required"`
+	Message string `json:"message" example:"bank_account.account_number is required"`
+} //@name ViolationResponse
+
 // BalanceResponse represents wallet balance response
 type BalanceResponse struct {
 	WalletID uint            `json:"wallet_id" example:"1"`
 	Balance  decimal.Decimal `json:"balance" example:"1000.50"`
 	Currency string          `json:"currency" example:"USD"`
+	// DisplayBalance is the balance in the display currency of the user, when they chose one
+	DisplayBalance *DisplayValueResponse `json:"display_balance,omitempty"`
 } //@name BalanceResponse
 
+// DebitAllowanceResponse represents a periodic debit limit and how much of it is left
+type DebitAllowanceResponse struct {
+	Limit     decimal.Decimal `json:"limit" example:"50000.00"`
+	Used      decimal.Decimal `json:"used" example:"12000.00"`
+	Remaining decimal.Decimal `json:"remaining" example:"38000.00"`
+	ResetsAt  time.Time       `json:"resets_at" example:"2023-01-02T00:00:00Z"`
+} //@name DebitAllowanceResponse
+
+// WalletLimitsResponse represents the limits of a wallet's tier and how much each operation may
+// move right now. Omitted limits and amounts are unlimited
+type WalletLimitsResponse struct {
+	WalletID             uint                    `json:"wallet_id" example:"1"`
+	Currency             string                  `json:"currency" example:"NGN"`
+	Tier                 string                  `json:"tier" example:"basic"`
+	MaxTransactionAmount *decimal.Decimal        `json:"max_transaction_amount,omitempty" example:"20000.00"`
+	MaxBalance           *decimal.Decimal        `json:"max_balance,omitempty" example:"200000.00"`
+	Daily                *DebitAllowanceResponse `json:"daily,omitempty"`
+	Monthly              *DebitAllowanceResponse `json:"monthly,omitempty"`
+	Remaining            OperationLimitsResponse `json:"remaining"`
+} //@name WalletLimitsResponse
+
+// OperationLimitsResponse represents the largest amount allowed for each operation type; fees are
+// charged on top of transfers and withdrawals
+type OperationLimitsResponse struct {
+	Transfer   *decimal.Decimal `json:"transfer,omitempty" example:"20000.00"`
+	Withdrawal *decimal.Decimal `json:"withdrawal,omitempty" example:"20000.00"`
+	Funding    *decimal.Decimal `json:"funding,omitempty" example:"150000.00"`
+} //@name OperationLimitsResponse
+
+// AdminWalletResponse represents wallet data with owner details for admin views
+type AdminWalletResponse struct {
+	ID        uint            `json:"id" example:"1"`
+	CreatedAt time.Time       `json:"created_at" example:"2023-01-01T00:00:00Z"`
+	UpdatedAt time.Time       `json:"updated_at" example:"2023-01-01T00:00:00Z"`
+	UserID    uint            `json:"user_id" example:"1"`
+	UserName  string          `json:"user_name" example:"John Doe"`
+	UserEmail string          `json:"user_email" example:"john.doe@example.com"`
+	Balance   decimal.Decimal `json:"balance" example:"1000.50"`
+	Currency  string          `json:"currency" example:"USD"`
+	Status    string          `json:"status" example:"ACTIVE"`
+	Version   uint            `json:"version" example:"1"`
+	Sandbox   bool            `json:"sandbox" example:"false"`
+	TierID    *uint           `json:"tier_id,omitempty" example:"1"`
+	Nickname  string          `json:"nickname,omitempty" example:"Rent"`
+	IsDefault bool            `json:"is_default" example:"true"`
+} //@name AdminWalletResponse
+
+// AdminWalletListResponse represents a paginated list of wallets for admin views
+type AdminWalletListResponse struct {
+	Wallets    []AdminWalletResponse `json:"wallets"`
+	Pagination PaginationMeta        `json:"pagination"`
+} //@name AdminWalletListResponse
+
+// AdminWalletDetailResponse represents a single wallet with its reconciliation history
+type AdminWalletDetailResponse struct {
+	Wallet                AdminWalletResponse            `json:"wallet"`
+	ReconciliationReports []ReconciliationReportResponse `json:"reconciliation_reports"`
+	TransactionsURL       string                         `json:"transactions_url" example:"/api/v1/admin/wallets/1/transactions"`
+} //@name AdminWalletDetailResponse
+
+// AdminTransactionResponse represents a transaction with its double-entry counterpart
+type AdminTransactionResponse struct {
+	TransactionResponse
+	Metadata             string               `json:"metadata" example:"{\"source\": \"funding\"}"`
+	RelatedTransactionID *uint                `json:"related_transaction_id,omitempty" example:"2"`
+	RelatedTransaction   *TransactionResponse `json:"related_transaction,omitempty"`
+	JournalEntryID       *uint                `json:"journal_entry_id,omitempty" example:"1"` // Set on the legs of a multi-leg journal entry
+	OriginIP             string               `json:"origin_ip,omitempty" example:"203.0.113.7"`
+	OriginCountry        string               `json:"origin_country,omitempty" example:"NG"`
+} //@name AdminTransactionResponse
+
+// AdminTransactionHistoryResponse represents cursor-paginated ledger entries for admin views
+type AdminTransactionHistoryResponse struct {
+	Transactions []AdminTransactionResponse `json:"transactions"`
+	Pagination   CursorPaginationMeta       `json:"pagination"`
+} //@name AdminTransactionHistoryResponse
+
+// AuditLogResponse represents an audit log entry
+type AuditLogResponse struct {
+	ID           uint      `json:"id" example:"1"`
+	CreatedAt    time.Time `json:"created_at" example:"2023-01-01T00:00:00Z"`
+	ActorID      uint      `json:"actor_id" example:"1"`
+	ActorEmail   string    `json:"actor_email" example:"admin@example.com"`
+	ActorRole    string    `json:"actor_role" example:"ADMIN"`
+	Action       string    `json:"action" example:"GET /api/v1/admin/wallets/:id"`
+	ResourceType string    `json:"resource_type" example:"wallets"`
+	ResourceID   string    `json:"resource_id" example:"1"`
+	Path         string    `json:"path" example:"/api/v1/admin/wallets/1"`
+	StatusCode   int       `json:"status_code" example:"200"`
+	IPAddress    string    `json:"ip_address" example:"127.0.0.1"`
+} //@name AuditLogResponse
+
+// NotificationPreferenceResponse represents a user's notification preferences
+type NotificationPreferenceResponse struct {
+	EmailEnabled        bool            `json:"email_enabled" example:"true"`
+	CreditReceived      bool            `json:"credit_received" example:"true"`
+	WithdrawalCompleted bool            `json:"withdrawal_completed" example:"true"`
+	TransactionFailed   bool            `json:"transaction_failed" example:"true"`
+	SuspiciousActivity  bool            `json:"suspicious_activity" example:"true"`
+	SMSEnabled          bool            `json:"sms_enabled" example:"true"`
+	SMSDebitThreshold   decimal.Decimal `json:"sms_debit_threshold" example:"1000.00"`
+	PushEnabled         bool            `json:"push_enabled" example:"true"`
+} //@name NotificationPreferenceResponse
+
+// UpdateNotificationPreferenceRequest represents a partial notification preference update
+type UpdateNotificationPreferenceRequest struct {
+	EmailEnabled        *bool            `json:"email_enabled,omitempty" example:"true"`
+	CreditReceived      *bool            `json:"credit_received,omitempty" example:"true"`
+	WithdrawalCompleted *bool            `json:"withdrawal_completed,omitempty" example:"false"`
+	TransactionFailed   *bool            `json:"transaction_failed,omitempty" example:"true"`
+	SuspiciousActivity  *bool            `json:"suspicious_activity,omitempty" example:"true"`
+	SMSEnabled          *bool            `json:"sms_enabled,omitempty" example:"true"`
+	SMSDebitThreshold   *decimal.Decimal `json:"sms_debit_threshold,omitempty" example:"500.00"`
+	PushEnabled         *bool            `json:"push_enabled,omitempty" example:"true"`
+} //@name UpdateNotificationPreferenceRequest
+
+// PhoneVerificationRequest represents a request to verify a phone number
+type PhoneVerificationRequest struct {
+	PhoneNumber string `json:"phone_number" binding:"required" example:"+2348012345678"`
+} //@name PhoneVerificationRequest
+
+// VerifyPhoneRequest represents a phone verification code submission
+type VerifyPhoneRequest struct {
+	Code string `json:"code" binding:"required,len=6" example:"123456"`
+} //@name VerifyPhoneRequest
+
+// PhoneVerificationResponse represents a pending phone verification
+type PhoneVerificationResponse struct {
+	PhoneNumber string    `json:"phone_number" example:"+2348012345678"`
+	ExpiresAt   time.Time `json:"expires_at" example:"2023-01-01T00:10:00Z"`
+} //@name PhoneVerificationResponse
+
+// RegisterDeviceRequest represents a request to register a device for push notifications
+type RegisterDeviceRequest struct {
+	Token      string `json:"token" binding:"required,max=512" example:"fcm-registration-token"`
+	Platform   string `json:"platform" binding:"required" example:"ANDROID"`
+	DeviceName string `json:"device_name,omitempty" binding:"max=255" example:"Pixel 8"`
+} //@name RegisterDeviceRequest
+
+// DeviceResponse represents a registered push notification device
+type DeviceResponse struct {
+	ID         uint       `json:"id" example:"1"`
+	CreatedAt  time.Time  `json:"created_at" example:"2023-01-01T00:00:00Z"`
+	Platform   string     `json:"platform" example:"ANDROID"`
+	DeviceName string     `json:"device_name" example:"Pixel 8"`
+	LastUsedAt *time.Time `json:"last_used_at,omitempty" example:"2023-01-01T00:00:00Z"`
+} //@name DeviceResponse
+
+// CardFundingRequest represents a request to fund a wallet by card
+type CardFundingRequest struct {
+	Amount decimal.Decimal `json:"amount" binding:"required" example:"100.50"`
+} //@name CardFundingRequest
+
+// CardFundingResponse carries the pending deposit and the secret the client uses to confirm the card payment
+type CardFundingResponse struct {
+	Deposit      DepositResponse `json:"deposit"`
+	ClientSecret string          `json:"client_secret" example:"pi_123_secret_456"`
+} //@name CardFundingResponse
+
+// CheckoutFundingResponse carries the pending deposit and the hosted checkout page for the customer
+type CheckoutFundingResponse struct {
+	Deposit          DepositResponse `json:"deposit"`
+	AuthorizationURL string          `json:"authorization_url" example:"https://checkout.paystack.com/abc123"`
+} //@name CheckoutFundingResponse
+
+// DepositResponse represents a provider funded deposit
+type DepositResponse struct {
+	ID                uint            `json:"id" example:"1"`
+	CreatedAt         time.Time       `json:"created_at" example:"2023-01-01T00:00:00Z"`
+	WalletID          uint            `json:"wallet_id" example:"1"`
+	Provider          string          `json:"provider" example:"paystack"`
+	ExternalReference string          `json:"external_reference" example:"PSK_123456"`
+	Reference         string          `json:"reference" example:"TXN-1672531200-ABCD1234"`
+	Amount            decimal.Decimal `json:"amount" example:"100.50"`
+	Currency          string          `json:"currency" example:"NGN"`
+	Status            string          `json:"status" example:"COMPLETED"`
+	TransactionID     *uint           `json:"transaction_id,omitempty" example:"10"`
+	FailureReason     string          `json:"failure_reason,omitempty" example:"card declined"`
+	CompletedAt       *time.Time      `json:"completed_at,omitempty" example:"2023-01-01T00:05:00Z"`
+} //@name DepositResponse
+
+// PayoutResponse represents a bank payout
+type PayoutResponse struct {
+	ID                uint            `json:"id" example:"1"`
+	CreatedAt         time.Time       `json:"created_at" example:"2023-01-01T00:00:00Z"`
+	WalletID          uint            `json:"wallet_id" example:"1"`
+	TransactionID     uint            `json:"transaction_id" example:"10"`
+	Reference         string          `json:"reference" example:"WTH123456"`
+	Provider          string          `json:"provider" example:"flutterwave"`
+	ProviderReference string          `json:"provider_reference,omitempty" example:"190626"`
+	Amount            decimal.Decimal `json:"amount" example:"50.25"`
+	Currency          string          `json:"currency" example:"NGN"`
+	Status            string          `json:"status" example:"COMPLETED"`
+	FailureReason     string          `json:"failure_reason,omitempty" example:"insufficient provider balance"`
+	CompletedAt       *time.Time      `json:"completed_at,omitempty" example:"2023-01-01T00:05:00Z"`
+} //@name PayoutResponse
+
+// MintSandboxFundsRequest represents a request to mint test money into a sandbox wallet
+type MintSandboxFundsRequest struct {
+	Amount decimal.Decimal `json:"amount" binding:"required" example:"1000.00"`
+} //@name MintSandboxFundsRequest
+
+// CreateMoneyRequestRequest represents a request asking another user for funds
+type CreateMoneyRequestRequest struct {
+	PayerEmail string          `json:"payer_email" binding:"required,email" example:"jane@example.com"`
+	Amount     decimal.Decimal `json:"amount" binding:"required" example:"25.00"`
+	Note       string          `json:"note,omitempty" binding:"max=255" example:"Dinner on Friday"`
+} //@name CreateMoneyRequestRequest
+
+// AcceptMoneyRequestRequest carries the transaction PIN that authorizes paying a money request
+type AcceptMoneyRequestRequest struct {
+	PIN string `json:"pin,omitempty" example:"4821"` // Transaction PIN, when set and the amount is above the PIN threshold
+} //@name AcceptMoneyRequestRequest
+
+// MoneyRequestResponse represents a money request between two users
+type MoneyRequestResponse struct {
+	ID             uint            `json:"id" example:"1"`
+	CreatedAt      time.Time       `json:"created_at" example:"2023-01-01T00:00:00Z"`
+	RequesterID    uint            `json:"requester_id" example:"1"`
+	RequesterName  string          `json:"requester_name" example:"John Doe"`
+	RequesterEmail string          `json:"requester_email" example:"john@example.com"`
+	PayerID        uint            `json:"payer_id" example:"2"`
+	PayerName      string          `json:"payer_name" example:"Jane Doe"`
+	PayerEmail     string          `json:"payer_email" example:"jane@example.com"`
+	Amount         decimal.Decimal `json:"amount" example:"25.00"`
+	Currency       string          `json:"currency" example:"USD"`
+	Note           string          `json:"note,omitempty" example:"Dinner on Friday"`
+	Status         string          `json:"status" example:"PENDING"`
+	TransactionID  *uint           `json:"transaction_id,omitempty" example:"10"`
+	RespondedAt    *time.Time      `json:"responded_at,omitempty" example:"2023-01-01T00:05:00Z"`
+} //@name MoneyRequestResponse
+
+// CreatePaymentLinkRequest represents a request to create a shareable payment link
+type CreatePaymentLinkRequest struct {
+	Amount      *decimal.Decimal `json:"amount,omitempty" example:"50.00"`
+	Description string           `json:"description,omitempty" binding:"max=255" example:"Concert ticket"`
+	ExpiresAt   *time.Time       `json:"expires_at,omitempty" example:"2023-02-01T00:00:00Z"`
+	SingleUse   bool             `json:"single_use" example:"true"`
+} //@name CreatePaymentLinkRequest
+
+// PayPaymentLinkRequest represents a payment made through a payment link; amount is only needed for open links
+type PayPaymentLinkRequest struct {
+	Amount *decimal.Decimal `json:"amount,omitempty" example:"50.00"`
+	PIN    string           `json:"pin,omitempty" example:"4821"` // Transaction PIN, when set and the amount is above the PIN threshold
+} //@name PayPaymentLinkRequest
+
+// PaymentLinkResponse represents a payment link with its analytics, as seen by its creator
+type PaymentLinkResponse struct {
+	ID             uint             `json:"id" example:"1"`
+	CreatedAt      time.Time        `json:"created_at" example:"2023-01-01T00:00:00Z"`
+	Token          string           `json:"token" example:"q3Xv9LmP2rT8wYz1aB4cD6eF"`
+	Amount         *decimal.Decimal `json:"amount,omitempty" example:"50.00"`
+	Currency       string           `json:"currency" example:"USD"`
+	Description    string           `json:"description,omitempty" example:"Concert ticket"`
+	SingleUse      bool             `json:"single_use" example:"true"`
+	ExpiresAt      *time.Time       `json:"expires_at,omitempty" example:"2023-02-01T00:00:00Z"`
+	Status         string           `json:"status" example:"ACTIVE"`
+	ViewCount      uint             `json:"view_count" example:"12"`
+	PaymentCount   uint             `json:"payment_count" example:"3"`
+	TotalCollected decimal.Decimal  `json:"total_collected" example:"150.00"`
+	LastPaidAt     *time.Time       `json:"last_paid_at,omitempty" example:"2023-01-05T00:00:00Z"`
+} //@name PaymentLinkResponse
+
+// PublicPaymentLinkResponse represents the details of a payment link shown to anyone holding the token
+type PublicPaymentLinkResponse struct {
+	Token         string           `json:"token" example:"q3Xv9LmP2rT8wYz1aB4cD6eF"`
+	RecipientName string           `json:"recipient_name" example:"John Doe"`
+	Amount        *decimal.Decimal `json:"amount,omitempty" example:"50.00"`
+	Currency      string           `json:"currency" example:"USD"`
+	Description   string           `json:"description,omitempty" example:"Concert ticket"`
+	ExpiresAt     *time.Time       `json:"expires_at,omitempty" example:"2023-02-01T00:00:00Z"`
+	Payable       bool             `json:"payable" example:"true"`
+} //@name PublicPaymentLinkResponse
+
+// QRCodeResponse carries a payment QR payload and its rendered image
+type QRCodeResponse struct {
+	Payload  string           `json:"payload" example:"walletpay://pay?currency=USD&wallet=1"`
+	WalletID uint             `json:"wallet_id" example:"1"`
+	Currency string           `json:"currency" example:"USD"`
+	Amount   *decimal.Decimal `json:"amount,omitempty" example:"12.50"`
+	Note     string           `json:"note,omitempty" example:"Coffee"`
+	PNG      string           `json:"png" example:"iVBORw0KGgoAAAANSUhEUg..."` // Base64 encoded PNG image
+} //@name QRCodeResponse
+
+// PayQRCodeRequest represents a scan-and-pay request; amount is only needed for codes without a fixed amount
+type PayQRCodeRequest struct {
+	Payload string           `json:"payload" binding:"required,max=512" example:"walletpay://pay?amount=12.50&currency=USD&wallet=1"`
+	Amount  *decimal.Decimal `json:"amount,omitempty" example:"12.50"`
+	PIN     string           `json:"pin,omitempty" example:"4821"` // Transaction PIN, when set and the amount is above the PIN threshold
+} //@name PayQRCodeRequest
+
+// CreateStandingOrderRequest represents a request to schedule a recurring transfer
+type CreateStandingOrderRequest struct {
+	DestinationWalletID uint            `json:"destination_wallet_id" binding:"required" example:"2"`
+	Amount              decimal.Decimal `json:"amount" binding:"required" example:"100.00"`
+	Frequency           string          `json:"frequency" binding:"required,oneof=DAILY WEEKLY MONTHLY" example:"MONTHLY"`
+	StartAt             *time.Time      `json:"start_at,omitempty" example:"2023-02-01T09:00:00Z"`
+	EndsAt              *time.Time      `json:"ends_at,omitempty" example:"2023-12-31T00:00:00Z"`
+	Description         string          `json:"description,omitempty" binding:"max=255" example:"Rent"`
+	PIN                 string          `json:"pin,omitempty" example:"4821"` // Transaction PIN, when set and the amount is above the PIN threshold
+} //@name CreateStandingOrderRequest
+
+// CategorizeTransactionRequest represents a request to tag a transaction with a spending category
+type CategorizeTransactionRequest struct {
+	Category string `json:"category" binding:"max=50" example:"groceries"` // Empty clears the category
+} //@name CategorizeTransactionRequest
+
+// CreateBudgetRequest represents a request to set a monthly budget for a spending category
+type CreateBudgetRequest struct {
+	Category string          `json:"category" binding:"required,max=50" example:"groceries"`
+	Amount   decimal.Decimal `json:"amount" binding:"required" example:"500.00"`
+} //@name CreateBudgetRequest
+
+// UpdateBudgetRequest represents a request to change the amount of a budget
+type UpdateBudgetRequest struct {
+	Amount decimal.Decimal `json:"amount" binding:"required" example:"650.00"`
+} //@name UpdateBudgetRequest
+
+// BudgetResponse represents a monthly budget with the spending in its category this month
+type BudgetResponse struct {
+	ID          uint            `json:"id" example:"1"`
+	CreatedAt   time.Time       `json:"created_at" example:"2023-01-01T00:00:00Z"`
+	Category    string          `json:"category" example:"groceries"`
+	Amount      decimal.Decimal `json:"amount" example:"500.00"`
+	Currency    string          `json:"currency" example:"USD"`
+	Spent       decimal.Decimal `json:"spent" example:"410.00"`
+	Remaining   decimal.Decimal `json:"remaining" example:"90.00"`
+	PercentUsed decimal.Decimal `json:"percent_used" example:"82"`
+	// DisplaySpent and DisplayRemaining are in the display currency of the user, when they chose one
+	DisplaySpent     *DisplayValueResponse `json:"display_spent,omitempty"`
+	DisplayRemaining *DisplayValueResponse `json:"display_remaining,omitempty"`
+} //@name BudgetResponse
+
+// CreateTenantRequest represents a business added to the deployment by an operator
+type CreateTenantRequest struct {
+	Name string `json:"name" binding:"required,max=255" example:"Acme Payments"`
+	Slug string `json:"slug" binding:"required,max=50" example:"acme"` // Sent as X-Tenant-ID by clients of the tenant
+} //@name CreateTenantRequest
+
+// TenantResponse represents a business sharing the deployment
+type TenantResponse struct {
+	ID        uint      `json:"id" example:"2"`
+	CreatedAt time.Time `json:"created_at" example:"2023-01-01T00:00:00Z"`
+	Name      string    `json:"name" example:"Acme Payments"`
+	Slug      string    `json:"slug" example:"acme"`
+	Active    bool      `json:"active" example:"true"`
+} //@name TenantResponse
+
+// CreateBusinessAccountRequest represents a request to open a business wallet shared with members
+type CreateBusinessAccountRequest struct {
+	Name string `json:"name" binding:"required,max=255" example:"Acme Ltd"`
+} //@name CreateBusinessAccountRequest
+
+// BusinessMemberRequest represents a user given a role on a business account
+type BusinessMemberRequest struct {
+	Email string `json:"email" binding:"required,email" example:"jane@example.com"`
+	Role  string `json:"role" binding:"required,oneof=VIEWER INITIATOR APPROVER" example:"INITIATOR"`
+} //@name BusinessMemberRequest
+
+// UpdateBusinessMemberRequest represents a new role for a member of a business account
+type UpdateBusinessMemberRequest struct {
+	Role string `json:"role" binding:"required,oneof=VIEWER INITIATOR APPROVER" example:"APPROVER"`
+} //@name UpdateBusinessMemberRequest
+
+// BusinessPaymentRequest represents a transfer to a wallet or, with a bank account, a withdrawal
+// from a business wallet
+type BusinessPaymentRequest struct {
+	ToWalletID  uint                `json:"to_wallet_id,omitempty" example:"2"`
+	BankAccount *BankAccountRequest `json:"bank_account,omitempty"`
+	Amount      decimal.Decimal     `json:"amount" binding:"required" example:"1200.00"`
+	Reference   string              `json:"reference" binding:"required,max=200" example:"INV-2024-001"`
+	Description string              `json:"description" example:"Supplier invoice"`
+	PIN         string              `json:"pin,omitempty" example:"4821"` // Transaction PIN of an approver, whose payments are made at once
+} //@name BusinessPaymentRequest
+
+// PaymentDecisionRequest records an approver's decision on a payment request
+type PaymentDecisionRequest struct {
+	Note string `json:"note,omitempty" binding:"max=1000" example:"Matches the purchase order"`
+	PIN  string `json:"pin,omitempty" example:"4821"` // Transaction PIN of the approver, when approving
+} //@name PaymentDecisionRequest
+
+// BusinessMemberResponse represents a member of a business account
+type BusinessMemberResponse struct {
+	UserID uint   `json:"user_id" example:"3"`
+	Name   string `json:"name,omitempty" example:"Jane Doe"`
+	Email  string `json:"email,omitempty" example:"jane@example.com"`
+	Role   string `json:"role" example:"INITIATOR"`
+} //@name BusinessMemberResponse
+
+// BusinessAccountResponse represents a business account with its wallet and the caller's role
+type BusinessAccountResponse struct {
+	ID        uint                     `json:"id" example:"1"`
+	CreatedAt time.Time                `json:"created_at" example:"2023-01-01T00:00:00Z"`
+	Name      string                   `json:"name" example:"Acme Ltd"`
+	OwnerID   uint                     `json:"owner_id" example:"1"`
+	Role      string                   `json:"role,omitempty" example:"APPROVER"`
+	Wallet    WalletResponse           `json:"wallet"`
+	Members   []BusinessMemberResponse `json:"members,omitempty"`
+} //@name BusinessAccountResponse
+
+// PaymentApprovalResponse represents a payment from a business wallet and the decision on it
+type PaymentApprovalResponse struct {
+	ID                   uint                `json:"id" example:"1"`
+	CreatedAt            time.Time           `json:"created_at" example:"2023-01-01T00:00:00Z"`
+	BusinessAccountID    uint                `json:"business_account_id" example:"1"`
+	Purpose              string              `json:"purpose" example:"TRANSFER"`
+	CounterpartyWalletID *uint               `json:"counterparty_wallet_id,omitempty" example:"2"`
+	BankAccount          *BankAccountRequest `json:"bank_account,omitempty"`
+	Amount               decimal.Decimal     `json:"amount" example:"1200.00"`
+	Currency             string              `json:"currency" example:"USD"`
+	Reference            string              `json:"reference" example:"INV-2024-001"`
+	Description          string              `json:"description,omitempty" example:"Supplier invoice"`
+	InitiatorID          uint                `json:"initiator_id" example:"3"`
+	Status               string              `json:"status" example:"PENDING"`
+	ApproverID           *uint               `json:"approver_id,omitempty" example:"1"`
+	DecisionNote         string              `json:"decision_note,omitempty" example:"Matches the purchase order"`
+	DecidedAt            *time.Time          `json:"decided_at,omitempty" example:"2023-01-01T01:00:00Z"`
+	TransactionID        *uint               `json:"transaction_id,omitempty" example:"10"`
+	FailureReason        string              `json:"failure_reason,omitempty" example:"insufficient funds"`
+} //@name PaymentApprovalResponse
+
+// CreateEscrowRequest represents a request to hold funds in escrow for another user
+type CreateEscrowRequest struct {
+	PayeeEmail string          `json:"payee_email" binding:"required,email" example:"jane@example.com"`
+	Amount     decimal.Decimal `json:"amount" binding:"required" example:"250.00"`
+	Condition  string          `json:"condition,omitempty" binding:"max=1000" example:"Released once the laptop is delivered"`
+	ExpiresAt  time.Time       `json:"expires_at" binding:"required" example:"2023-02-01T00:00:00Z"`
+	PIN        string          `json:"pin,omitempty" example:"4821"` // Transaction PIN, when set and the amount is above the PIN threshold
+} //@name CreateEscrowRequest
+
+// EscrowResponse represents funds held in escrow between a payer and a payee
+type EscrowResponse struct {
+	ID                       uint            `json:"id" example:"1"`
+	CreatedAt                time.Time       `json:"created_at" example:"2023-01-01T00:00:00Z"`
+	Reference                string          `json:"reference" example:"ESCROW-TXN-1672531200-ab12cd34"`
+	PayerID                  uint            `json:"payer_id" example:"1"`
+	PayerName                string          `json:"payer_name" example:"John Doe"`
+	PayerEmail               string          `json:"payer_email" example:"john@example.com"`
+	PayeeID                  uint            `json:"payee_id" example:"2"`
+	PayeeName                string          `json:"payee_name" example:"Jane Doe"`
+	PayeeEmail               string          `json:"payee_email" example:"jane@example.com"`
+	Amount                   decimal.Decimal `json:"amount" example:"250.00"`
+	Currency                 string          `json:"currency" example:"USD"`
+	Condition                string          `json:"condition,omitempty" example:"Released once the laptop is delivered"`
+	ExpiresAt                time.Time       `json:"expires_at" example:"2023-02-01T00:00:00Z"`
+	Status                   string          `json:"status" example:"HELD"`
+	FundingJournalEntryID    uint            `json:"funding_journal_entry_id" example:"5"`
+	SettlementJournalEntryID *uint           `json:"settlement_journal_entry_id,omitempty" example:"6"`
+	SettledAt                *time.Time      `json:"settled_at,omitempty" example:"2023-01-10T00:00:00Z"`
+} //@name EscrowResponse
+
+// CreateSubscriptionPlanRequest represents a request by a merchant to define a subscription plan
+type CreateSubscriptionPlanRequest struct {
+	Name        string          `json:"name" binding:"required,max=100" example:"Pro"`
+	Description string          `json:"description,omitempty" binding:"max=1000" example:"Unlimited projects"`
+	Amount      decimal.Decimal `json:"amount" binding:"required" example:"9.99"`
+	Interval    string          `json:"interval" binding:"required,oneof=WEEKLY MONTHLY YEARLY" example:"MONTHLY"`
+} //@name CreateSubscriptionPlanRequest
+
+// SubscribeRequest represents a request to subscribe to a plan
+type SubscribeRequest struct {
+	PlanID uint `json:"plan_id" binding:"required" example:"1"`
+} //@name SubscribeRequest
+
+// SubscriptionPlanResponse represents a plan a merchant charges subscribers every interval
+type SubscriptionPlanResponse struct {
+	ID           uint            `json:"id" example:"1"`
+	CreatedAt    time.Time       `json:"created_at" example:"2023-01-01T00:00:00Z"`
+	MerchantID   uint            `json:"merchant_id" example:"2"`
+	MerchantName string          `json:"merchant_name,omitempty" example:"Acme Ltd"`
+	Name         string          `json:"name" example:"Pro"`
+	Description  string          `json:"description,omitempty" example:"Unlimited projects"`
+	Amount       decimal.Decimal `json:"amount" example:"9.99"`
+	Currency     string          `json:"currency" example:"USD"`
+	Interval     string          `json:"interval" example:"MONTHLY"`
+	Active       bool            `json:"active" example:"true"`
+} //@name SubscriptionPlanResponse
+
+// SubscriptionResponse represents a user's subscription to a plan and its billing state
+type SubscriptionResponse struct {
+	ID             uint            `json:"id" example:"1"`
+	CreatedAt      time.Time       `json:"created_at" example:"2023-01-01T00:00:00Z"`
+	Reference      string          `json:"reference" example:"SUB-TXN-1672531200-ab12cd34"`
+	PlanID         uint            `json:"plan_id" example:"1"`
+	PlanName       string          `json:"plan_name" example:"Pro"`
+	UserID         uint            `json:"user_id" example:"3"`
+	Amount         decimal.Decimal `json:"amount" example:"9.99"`
+	Currency       string          `json:"currency" example:"USD"`
+	Interval       string          `json:"interval" example:"MONTHLY"`
+	Status         string          `json:"status" example:"PAST_DUE"`
+	PaidPeriods    int             `json:"paid_periods" example:"3"`
+	PaidUntil      time.Time       `json:"paid_until" example:"2023-04-01T00:00:00Z"`
+	NextBillingAt  time.Time       `json:"next_billing_at" example:"2023-04-02T00:00:00Z"`
+	FailedAttempts int             `json:"failed_attempts" example:"1"`
+	LastError      string          `json:"last_error,omitempty" example:"insufficient funds: available=2.00, required=9.99"`
+	SuspendedAt    *time.Time      `json:"suspended_at,omitempty" example:"2023-04-04T00:00:00Z"`
+	CancelledAt    *time.Time      `json:"cancelled_at,omitempty" example:"2023-04-10T00:00:00Z"`
+} //@name SubscriptionResponse
+
+// SubscriptionChargeResponse represents one attempt to charge a billing period of a subscription
+type SubscriptionChargeResponse struct {
+	ID            uint            `json:"id" example:"1"`
+	CreatedAt     time.Time       `json:"created_at" example:"2023-04-01T00:00:00Z"`
+	Reference     string          `json:"reference" example:"SUB-TXN-1672531200-ab12cd34-4"`
+	PeriodStart   time.Time       `json:"period_start" example:"2023-04-01T00:00:00Z"`
+	PeriodEnd     time.Time       `json:"period_end" example:"2023-05-01T00:00:00Z"`
+	Amount        decimal.Decimal `json:"amount" example:"9.99"`
+	Currency      string          `json:"currency" example:"USD"`
+	Attempt       int             `json:"attempt" example:"1"`
+	Status        string          `json:"status" example:"FAILED"`
+	Error         string          `json:"error,omitempty" example:"insufficient funds: available=2.00, required=9.99"`
+	TransactionID *uint           `json:"transaction_id,omitempty" example:"10"`
+} //@name SubscriptionChargeResponse
+
+// CreateWebhookSubscriptionRequest represents a request to receive the events of the user's wallets at
+// an endpoint; no event types means every event
+type CreateWebhookSubscriptionRequest struct {
+	URL        string   `json:"url" binding:"required,url,max=2048" example:"https://example.com/hooks/wallet"`
+	EventTypes []string `json:"event_types,omitempty" binding:"max=20" example:"wallet.credit_received,wallet.transfer_sent"`
+} //@name CreateWebhookSubscriptionRequest
+
+// ReplayWebhookEventsRequest represents a request to deliver stored events again to a webhook endpoint:
+// the events of a wallet, those from a time on, or both. Without to, the range is open-ended
+type ReplayWebhookEventsRequest struct {
+	WalletID uint      `json:"wallet_id,omitempty" example:"2"`
+	From     time.Time `json:"from,omitempty" example:"2023-01-01T00:00:00Z"`
+	To       time.Time `json:"to,omitempty" example:"2023-01-02T00:00:00Z"`
+} //@name ReplayWebhookEventsRequest
+
+// ReplayWebhookEventsResponse represents the outcome of a replay
+type ReplayWebhookEventsResponse struct {
+	Queued int `json:"queued" example:"42"`
+} //@name ReplayWebhookEventsResponse
+
+// WebhookSubscriptionResponse represents an endpoint receiving events; the secret is only returned when
+// the subscription is created
+type WebhookSubscriptionResponse struct {
+	ID         uint      `json:"id" example:"1"`
+	CreatedAt  time.Time `json:"created_at" example:"2023-01-01T00:00:00Z"`
+	URL        string    `json:"url" example:"https://example.com/hooks/wallet"`
+	EventTypes []string  `json:"event_types,omitempty" example:"wallet.credit_received,wallet.transfer_sent"`
+	Active     bool      `json:"active" example:"true"`
+	Secret     string    `json:"secret,omitempty" example:"whsec_8fJ2kQ9xLmN4pR7tV1wY3zA5bC6dE0gH"`
+} //@name WebhookSubscriptionResponse
+
+// WebhookDeliveryResponse represents an event sent, or to be sent, to a webhook endpoint
+type WebhookDeliveryResponse struct {
+	ID             uint                             `json:"id" example:"1"`
+	CreatedAt      time.Time                        `json:"created_at" example:"2023-01-01T00:00:00Z"`
+	SubscriptionID uint                             `json:"subscription_id" example:"1"`
+	EventID        uint                             `json:"event_id,omitempty" example:"120"`
+	EventType      string                           `json:"event_type" example:"wallet.credit_received"`
+	Replay         bool                             `json:"replay" example:"false"`
+	Payload        string                           `json:"payload" example:"{\"type\":\"wallet.credit_received\",\"wallet_id\":2}"`
+	Status         string                           `json:"status" example:"DEAD"`
+	Attempts       int                              `json:"attempts" example:"8"`
+	NextAttemptAt  *time.Time                       `json:"next_attempt_at,omitempty" example:"2023-01-01T00:01:00Z"`
+	LastStatusCode int                              `json:"last_status_code,omitempty" example:"503"`
+	LastDurationMS int64                            `json:"last_duration_ms,omitempty" example:"120"`
+	LastError      string                           `json:"last_error,omitempty" example:"webhook endpoint returned status 503: try later"`
+	DeliveredAt    *time.Time                       `json:"delivered_at,omitempty" example:"2023-01-01T00:00:01Z"`
+	DeadAt         *time.Time                       `json:"dead_at,omitempty" example:"2023-01-02T12:00:00Z"`
+	AttemptHistory []WebhookDeliveryAttemptResponse `json:"attempt_history,omitempty"`
+} //@name WebhookDeliveryResponse
+
+// WebhookDeliveryAttemptResponse represents one request made to deliver an event and how the endpoint
+// answered
+type WebhookDeliveryAttemptResponse struct {
+	CreatedAt  time.Time `json:"created_at" example:"2023-01-01T00:00:00Z"`
+	Attempt    int       `json:"attempt" example:"1"`
+	StatusCode int       `json:"status_code,omitempty" example:"503"`
+	Error      string    `json:"error,omitempty" example:"webhook endpoint returned status 503: try later"`
+	DurationMS int64     `json:"duration_ms" example:"120"`
+} //@name WebhookDeliveryAttemptResponse
+
+// StandingOrderResponse represents a recurring transfer
+type StandingOrderResponse struct {
+	ID                  uint            `json:"id" example:"1"`
+	CreatedAt           time.Time       `json:"created_at" example:"2023-01-01T00:00:00Z"`
+	WalletID            uint            `json:"wallet_id" example:"1"`
+	DestinationWalletID uint            `json:"destination_wallet_id" example:"2"`
+	Amount              decimal.Decimal `json:"amount" example:"100.00"`
+	Currency            string          `json:"currency" example:"USD"`
+	Description         string          `json:"description,omitempty" example:"Rent"`
+	Frequency           string          `json:"frequency" example:"MONTHLY"`
+	NextRunAt           time.Time       `json:"next_run_at" example:"2023-02-01T09:00:00Z"`
+	EndsAt              *time.Time      `json:"ends_at,omitempty" example:"2023-12-31T00:00:00Z"`
+	Status              string          `json:"status" example:"ACTIVE"`
+} //@name StandingOrderResponse
+
+// StandingOrderRunResponse represents one scheduled occurrence of a standing order and its attempts
+type StandingOrderRunResponse struct {
+	ID            uint       `json:"id" example:"1"`
+	ScheduledFor  time.Time  `json:"scheduled_for" example:"2023-02-01T09:00:00Z"`
+	Status        string     `json:"status" example:"RETRYING"`
+	Attempts      int        `json:"attempts" example:"2"`
+	NextAttemptAt *time.Time `json:"next_attempt_at,omitempty" example:"2023-02-01T10:00:00Z"`
+	LastError     string     `json:"last_error,omitempty" example:"insufficient funds: available=20.00, required=100.00"`
+	TransactionID *uint      `json:"transaction_id,omitempty" example:"10"`
+	CompletedAt   *time.Time `json:"completed_at,omitempty" example:"2023-02-01T11:00:00Z"`
+} //@name StandingOrderRunResponse
+
+// WalletTierRequest represents a wallet tier created or replaced by an admin; omitted limits are unlimited
+type WalletTierRequest struct {
+	Name                  string           `json:"name" binding:"required,max=50" example:"premium"`
+	Description           string           `json:"description,omitempty" example:"Higher limits and free transfers"`
+	IsDefault             bool             `json:"is_default" example:"false"`
+	TransferFeeFlat       decimal.Decimal  `json:"transfer_fee_flat" example:"0.00"`
+	TransferFeePercent    decimal.Decimal  `json:"transfer_fee_percent" example:"0.50"`
+	WithdrawalFeeFlat     decimal.Decimal  `json:"withdrawal_fee_flat" example:"1.00"`
+	WithdrawalFeePercent  decimal.Decimal  `json:"withdrawal_fee_percent" example:"0.00"`
+	MaxTransactionAmount  *decimal.Decimal `json:"max_transaction_amount,omitempty" example:"10000.00"`
+	DailyDebitLimit       *decimal.Decimal `json:"daily_debit_limit,omitempty" example:"50000.00"`
+	MonthlyDebitLimit     *decimal.Decimal `json:"monthly_debit_limit,omitempty" example:"500000.00"`
+	MaxBalance            *decimal.Decimal `json:"max_balance,omitempty" example:"1000000.00"`
+	PaymentLinksEnabled   bool             `json:"payment_links_enabled" example:"true"`
+	QRPaymentsEnabled     bool             `json:"qr_payments_enabled" example:"true"`
+	StandingOrdersEnabled bool             `json:"standing_orders_enabled" example:"true"`
+} //@name WalletTierRequest
+
+// WalletTierResponse represents a wallet tier with its fees, limits and features
+type WalletTierResponse struct {
+	ID                    uint             `json:"id" example:"2"`
+	Name                  string           `json:"name" example:"premium"`
+	Description           string           `json:"description,omitempty" example:"Higher limits and free transfers"`
+	IsDefault             bool             `json:"is_default" example:"false"`
+	TransferFeeFlat       decimal.Decimal  `json:"transfer_fee_flat" example:"0.00"`
+	TransferFeePercent    decimal.Decimal  `json:"transfer_fee_percent" example:"0.50"`
+	WithdrawalFeeFlat     decimal.Decimal  `json:"withdrawal_fee_flat" example:"1.00"`
+	WithdrawalFeePercent  decimal.Decimal  `json:"withdrawal_fee_percent" example:"0.00"`
+	MaxTransactionAmount  *decimal.Decimal `json:"max_transaction_amount,omitempty" example:"10000.00"`
+	DailyDebitLimit       *decimal.Decimal `json:"daily_debit_limit,omitempty" example:"50000.00"`
+	MonthlyDebitLimit     *decimal.Decimal `json:"monthly_debit_limit,omitempty" example:"500000.00"`
+	MaxBalance            *decimal.Decimal `json:"max_balance,omitempty" example:"1000000.00"`
+	PaymentLinksEnabled   bool             `json:"payment_links_enabled" example:"true"`
+	QRPaymentsEnabled     bool             `json:"qr_payments_enabled" example:"true"`
+	StandingOrdersEnabled bool             `json:"standing_orders_enabled" example:"true"`
+} //@name WalletTierResponse
+
+// AssignWalletTierRequest assigns a tier to a wallet; omit tier_id to return the wallet to the default tier
+type AssignWalletTierRequest struct {
+	TierID *uint `json:"tier_id" example:"2"`
+} //@name AssignWalletTierRequest
+
+// ResolveFraudReviewRequest records an admin decision on a held debit
+type ResolveFraudReviewRequest struct {
+	Note string `json:"note,omitempty" binding:"max=1000" example:"Confirmed with the customer by phone"`
+} //@name ResolveFraudReviewRequest
+
+// FraudReviewResponse represents a debit held by a fraud rule and the admin decision on it
+type FraudReviewResponse struct {
+	ID                   uint                `json:"id" example:"1"`
+	CreatedAt            time.Time           `json:"created_at" example:"2023-01-01T00:00:00Z"`
+	TransactionID        uint                `json:"transaction_id" example:"10"`
+	WalletID             uint                `json:"wallet_id" example:"1"`
+	Purpose              string              `json:"purpose" example:"TRANSFER"`
+	CounterpartyWalletID *uint               `json:"counterparty_wallet_id,omitempty" example:"2"`
+	Amount               decimal.Decimal     `json:"amount" example:"4990.00"`
+	Fee                  decimal.Decimal     `json:"fee" example:"10.00"`
+	Currency             string              `json:"currency" example:"USD"`
+	Rule                 string              `json:"rule" example:"new_recipient"`
+	Reason               string              `json:"reason" example:"transfer of 4990.00 to a new recipient"`
+	BankAccount          *BankAccountRequest `json:"bank_account,omitempty"`
+	Status               string              `json:"status" example:"PENDING"`
+	ReviewerID           *uint               `json:"reviewer_id,omitempty" example:"3"`
+	ReviewNote           string              `json:"review_note,omitempty" example:"Confirmed with the customer by phone"`
+	ReviewedAt           *time.Time          `json:"reviewed_at,omitempty" example:"2023-01-01T01:00:00Z"`
+} //@name FraudReviewResponse
+
+// BlocklistEntryRequest blocklists a user email, wallet ID or bank account written as BANKCODE:ACCOUNTNUMBER
+type BlocklistEntryRequest struct {
+	Type   string `json:"type" binding:"required,oneof=EMAIL WALLET BANK_ACCOUNT" example:"EMAIL"`
+	Value  string `json:"value" binding:"required,max=255" example:"mule@example.com"`
+	Reason string `json:"reason,omitempty" binding:"max=1000" example:"Reported by partner bank"`
+} //@name BlocklistEntryRequest
+
+// BlocklistEntryResponse represents a blocklisted email, wallet or bank account
+type BlocklistEntryResponse struct {
+	ID          uint      `json:"id" example:"1"`
+	CreatedAt   time.Time `json:"created_at" example:"2023-01-01T00:00:00Z"`
+	Type        string    `json:"type" example:"EMAIL"`
+	Value       string    `json:"value" example:"mule@example.com"`
+	Reason      string    `json:"reason,omitempty" example:"Reported by partner bank"`
+	CreatedByID uint      `json:"created_by_id" example:"3"`
+} //@name BlocklistEntryResponse
+
+// IPAllowlistEntryRequest allows a CIDR range, or a single IP address, to reach the admin endpoints
+type IPAllowlistEntryRequest struct {
+	CIDR        string `json:"cidr" binding:"required,max=64" example:"203.0.113.0/24"`
+	Description string `json:"description,omitempty" binding:"max=255" example:"Head office VPN"`
+} //@name IPAllowlistEntryRequest
+
+// IPAllowlistEntryResponse represents a CIDR range allowed at runtime
+type IPAllowlistEntryResponse struct {
+	ID          uint      `json:"id" example:"1"`
+	CreatedAt   time.Time `json:"created_at" example:"2023-01-01T00:00:00Z"`
+	CIDR        string    `json:"cidr" example:"203.0.113.0/24"`
+	Description string    `json:"description,omitempty" example:"Head office VPN"`
+	CreatedByID uint      `json:"created_by_id" example:"3"`
+} //@name IPAllowlistEntryResponse
+
+// IPAllowlistResponse lists the ranges allowed to reach the admin endpoints; ranges from configuration
+// cannot be removed through the API
+type IPAllowlistResponse struct {
+	ConfiguredCIDRs []string                   `json:"configured_cidrs" example:"10.0.0.0/8"`
+	Entries         []IPAllowlistEntryResponse `json:"entries"`
+} //@name IPAllowlistResponse
+
+// ComplianceLogResponse represents an operation refused by a compliance control
+type ComplianceLogResponse struct {
+	ID                   uint            `json:"id" example:"1"`
+	CreatedAt            time.Time       `json:"created_at" example:"2023-01-01T00:00:00Z"`
+	Check                string          `json:"check" example:"BLOCKLIST"`
+	UserID               *uint           `json:"user_id,omitempty" example:"2"`
+	WalletID             *uint           `json:"wallet_id,omitempty" example:"1"`
+	CounterpartyWalletID *uint           `json:"counterparty_wallet_id,omitempty" example:"5"`
+	Purpose              string          `json:"purpose,omitempty" example:"TRANSFER"`
+	Amount               decimal.Decimal `json:"amount" example:"250.00"`
+	Reference            string          `json:"reference,omitempty" example:"TXN123456"`
+	Subject              string          `json:"subject" example:"EMAIL mule@example.com"`
+	Reason               string          `json:"reason,omitempty" example:"Reported by partner bank"`
+} //@name ComplianceLogResponse
+
+// ResolveAMLCaseRequest records a compliance officer's decision on a debit parked by AML screening
+type ResolveAMLCaseRequest struct {
+	Note string `json:"note,omitempty" binding:"max=1000" example:"Source of funds documents verified"`
+} //@name ResolveAMLCaseRequest
+
+// AMLCaseResponse represents a debit parked by AML screening and the decision on it
+type AMLCaseResponse struct {
+	ID                   uint                `json:"id" example:"1"`
+	CreatedAt            time.Time           `json:"created_at" example:"2023-01-01T00:00:00Z"`
+	TransactionID        uint                `json:"transaction_id" example:"10"`
+	WalletID             uint                `json:"wallet_id" example:"1"`
+	Purpose              string              `json:"purpose" example:"TRANSFER"`
+	CounterpartyWalletID *uint               `json:"counterparty_wallet_id,omitempty" example:"2"`
+	Amount               decimal.Decimal     `json:"amount" example:"25000.00"`
+	Fee                  decimal.Decimal     `json:"fee" example:"10.00"`
+	Currency             string              `json:"currency" example:"USD"`
+	Screener             string              `json:"screener" example:"manual"`
+	CaseReference        string              `json:"case_reference,omitempty" example:"AML-2023-0001"`
+	Reason               string              `json:"reason" example:"amount 25000.00 is above the AML screening threshold"`
+	BankAccount          *BankAccountRequest `json:"bank_account,omitempty"`
+	Status               string              `json:"status" example:"PENDING"`
+	ReviewerID           *uint               `json:"reviewer_id,omitempty" example:"3"`
+	ReviewNote           string              `json:"review_note,omitempty" example:"Source of funds documents verified"`
+	ReviewedAt           *time.Time          `json:"reviewed_at,omitempty" example:"2023-01-01T01:00:00Z"`
+} //@name AMLCaseResponse
+
+// JobResponse represents a background job waiting to run, running, or in the dead letter queue
+type JobResponse struct {
+	ID          uint       `json:"id" example:"1"`
+	CreatedAt   time.Time  `json:"created_at" example:"2023-01-01T00:00:00Z"`
+	Type        string     `json:"type" example:"notifications.email"`
+	Payload     string     `json:"payload" example:"{\"wallet_id\":1}"`
+	Status      string     `json:"status" example:"DEAD"`
+	Attempts    int        `json:"attempts" example:"8"`
+	MaxAttempts int        `json:"max_attempts" example:"8"`
+	RunAt       time.Time  `json:"run_at" example:"2023-01-01T00:00:00Z"`
+	LockedUntil *time.Time `json:"locked_until,omitempty" example:"2023-01-01T00:05:00Z"`
+	LastError   string     `json:"last_error,omitempty" example:"smtp: connection refused"`
+} //@name JobResponse
+
+// CircuitBreakerResponse represents the state and call counters of the circuit around a provider
+type CircuitBreakerResponse struct {
+	Name                string     `json:"name" example:"paystack"`
+	State               string     `json:"state" example:"OPEN"`
+	ConsecutiveFailures int        `json:"consecutive_failures" example:"5"`
+	Successes           int64      `json:"successes" example:"1200"`
+	Failures            int64      `json:"failures" example:"7"`
+	Rejections          int64      `json:"rejections" example:"14"`
+	Opens               int64      `json:"opens" example:"1"`
+	OpenedAt            *time.Time `json:"opened_at,omitempty" example:"2023-01-01T00:00:00Z"`
+} //@name CircuitBreakerResponse
+
+// LedgerAccountResponse represents an account of the chart of ledger accounts with the balance of its wallet
+type LedgerAccountResponse struct {
+	ID       uint            `json:"id" example:"2"`
+	Code     string          `json:"code" example:"FEES"`
+	Name     string          `json:"name" example:"Fee income"`
+	Type     string          `json:"type" example:"INCOME"`
+	Sandbox  bool            `json:"sandbox" example:"false"`
+	WalletID uint            `json:"wallet_id" example:"3"`
+	Balance  decimal.Decimal `json:"balance" example:"1520.00"`
+	Currency string          `json:"currency" example:"USD"`
+} //@name LedgerAccountResponse
+
+// JournalLegRequest represents one leg of a manual journal entry
+type JournalLegRequest struct {
+	Account string          `json:"account" binding:"required,oneof=CASH FEES INTEREST SUSPENSE ADJUSTMENTS" example:"INTEREST"`
+	Type    string          `json:"type" binding:"required,oneof=DEBIT CREDIT" example:"CREDIT"`
+	Amount  decimal.Decimal `json:"amount" binding:"required" example:"500.00"`
+} //@name JournalLegRequest
+
+// JournalEntryRequest represents a balanced journal entry posted by an admin between ledger accounts
+type JournalEntryRequest struct {
+	Reference   string              `json:"reference" binding:"required,max=255" example:"JE-2023-0001"`
+	Description string              `json:"description" binding:"max=1000" example:"Fund interest payouts for January"`
+	Sandbox     bool                `json:"sandbox" example:"false"`
+	Purpose     string              `json:"purpose,omitempty" binding:"max=20" example:"INTEREST"` // Transaction purpose of the legs; ADJUSTMENT when omitted
+	Legs        []JournalLegRequest `json:"legs" binding:"required,min=2,dive"`
+} //@name JournalEntryRequest
+
+// JournalEntryResponse represents a journal entry with its legs
+type JournalEntryResponse struct {
+	ID          uint                  `json:"id" example:"1"`
+	CreatedAt   time.Time             `json:"created_at" example:"2023-01-01T00:00:00Z"`
+	Reference   string                `json:"reference" example:"JE-2023-0001"`
+	Description string                `json:"description" example:"Fund interest payouts for January"`
+	PostedBy    *uint                 `json:"posted_by,omitempty" example:"1"`
+	Legs        []TransactionResponse `json:"legs"`
+} //@name JournalEntryResponse
+
+// SuspenseNoteRequest records an admin note on a suspense item
+type SuspenseNoteRequest struct {
+	Note string `json:"note,omitempty" binding:"max=1000" example:"Payer named in the bank narration; contacting support"`
+} //@name SuspenseNoteRequest
+
+// ReallocateSuspenseItemRequest credits the funds of a suspense item to the wallet they belong to
+type ReallocateSuspenseItemRequest struct {
+	WalletID uint   `json:"wallet_id" binding:"required" example:"12"`
+	Note     string `json:"note,omitempty" binding:"max=1000" example:"Customer confirmed the transfer by email"`
+} //@name ReallocateSuspenseItemRequest
+
+// SuspenseItemResponse represents an inbound credit parked in the suspense account
+type SuspenseItemResponse struct {
+	ID                       uint            `json:"id" example:"1"`
+	CreatedAt                time.Time       `json:"created_at" example:"2023-01-01T00:00:00Z"`
+	Provider                 string          `json:"provider" example:"paystack"`
+	ExternalReference        string          `json:"external_reference" example:"PSK-8812"`
+	Amount                   decimal.Decimal `json:"amount" example:"250.00"`
+	Currency                 string          `json:"currency" example:"USD"`
+	Reason                   string          `json:"reason" example:"UNKNOWN_REFERENCE"`
+	DepositID                *uint           `json:"deposit_id,omitempty" example:"7"`
+	WalletID                 *uint           `json:"wallet_id,omitempty" example:"12"`
+	Status                   string          `json:"status" example:"OPEN"`
+	JournalEntryID           uint            `json:"journal_entry_id" example:"4"`
+	Notes                    string          `json:"notes,omitempty" example:"[2023-01-01 10:00] admin 3: Payer named in the bank narration"`
+	InvestigatorID           *uint           `json:"investigator_id,omitempty" example:"3"`
+	ReallocatedWalletID      *uint           `json:"reallocated_wallet_id,omitempty" example:"12"`
+	ResolutionJournalEntryID *uint           `json:"resolution_journal_entry_id,omitempty" example:"5"`
+	ResolvedBy               *uint           `json:"resolved_by,omitempty" example:"3"`
+	ResolvedAt               *time.Time      `json:"resolved_at,omitempty" example:"2023-01-02T00:00:00Z"`
+} //@name SuspenseItemResponse
+
+// BalanceAdjustmentRequest asks for a manual credit or debit of a wallet; a second admin approves it
+// before it is posted
+type BalanceAdjustmentRequest struct {
+	WalletID uint            `json:"wallet_id" binding:"required" example:"12"`
+	Type     string          `json:"type" binding:"required,oneof=CREDIT DEBIT" example:"CREDIT"`
+	Amount   decimal.Decimal `json:"amount" binding:"required" example:"25.00"`
+	Reason   string          `json:"reason" binding:"required,max=1000" example:"Reversal fee charged twice, see reconciliation report 41"`
+} //@name BalanceAdjustmentRequest
+
+// AdjustmentDecisionRequest records an optional note with the approval or rejection of an adjustment
+type AdjustmentDecisionRequest struct {
+	Note string `json:"note,omitempty" binding:"max=1000" example:"Checked against the provider statement"`
+} //@name AdjustmentDecisionRequest
+
+// BalanceAdjustmentResponse represents a manual wallet adjustment and its approval
+type BalanceAdjustmentResponse struct {
+	ID             uint            `json:"id" example:"1"`
+	CreatedAt      time.Time       `json:"created_at" example:"2023-01-01T00:00:00Z"`
+	WalletID       uint            `json:"wallet_id" example:"12"`
+	Type           string          `json:"type" example:"CREDIT"`
+	Amount         decimal.Decimal `json:"amount" example:"25.00"`
+	Currency       string          `json:"currency" example:"USD"`
+	Reason         string          `json:"reason" example:"Reversal fee charged twice, see reconciliation report 41"`
+	RequestedBy    uint            `json:"requested_by" example:"3"`
+	Status         string          `json:"status" example:"APPROVED"`
+	ApproverID     *uint           `json:"approver_id,omitempty" example:"4"`
+	DecisionNote   string          `json:"decision_note,omitempty" example:"Checked against the provider statement"`
+	DecidedAt      *time.Time      `json:"decided_at,omitempty" example:"2023-01-01T10:00:00Z"`
+	JournalEntryID *uint           `json:"journal_entry_id,omitempty" example:"9"`
+} //@name BalanceAdjustmentResponse
+
+// CloseSettlementDayRequest batches the completed deposits and payouts of a UTC day
+type CloseSettlementDayRequest struct {
+	Date string `json:"date" binding:"required" example:"2023-01-01"`
+} //@name CloseSettlementDayRequest
+
+// RecordSettlementRequest records the net a provider settled for a batch, from its statement; a
+// negative net is a settlement paid to the provider
+type RecordSettlementRequest struct {
+	ReceivedNet *decimal.Decimal `json:"received_net" binding:"required" example:"1200.00"`
+	Note        string           `json:"note,omitempty" binding:"max=1000" example:"Statement line 2023-01-02 ref STL-8812"`
+} //@name RecordSettlementRequest
+
+// SettlementBatchResponse represents the expected and received settlement of a provider for a day
+type SettlementBatchResponse struct {
+	ID           uint             `json:"id" example:"1"`
+	CreatedAt    time.Time        `json:"created_at" example:"2023-01-02T01:00:00Z"`
+	Provider     string           `json:"provider" example:"paystack"`
+	Currency     string           `json:"currency" example:"NGN"`
+	Date         string           `json:"date" example:"2023-01-01"`
+	DepositCount int              `json:"deposit_count" example:"14"`
+	DepositTotal decimal.Decimal  `json:"deposit_total" example:"1500.00"`
+	PayoutCount  int              `json:"payout_count" example:"3"`
+	PayoutTotal  decimal.Decimal  `json:"payout_total" example:"300.00"`
+	ExpectedNet  decimal.Decimal  `json:"expected_net" example:"1200.00"`
+	ReceivedNet  *decimal.Decimal `json:"received_net,omitempty" example:"1200.00"`
+	Difference   *decimal.Decimal `json:"difference,omitempty" example:"0.00"`
+	Status       string           `json:"status" example:"MATCHED"`
+	Notes        string           `json:"notes,omitempty" example:"Statement line 2023-01-02 ref STL-8812"`
+	ReconciledBy *uint            `json:"reconciled_by,omitempty" example:"3"`
+	ReconciledAt *time.Time       `json:"reconciled_at,omitempty" example:"2023-01-02T09:00:00Z"`
+} //@name SettlementBatchResponse
+
+// SettlementBatchReportResponse is a settlement batch with the deposits and payouts it holds
+type SettlementBatchReportResponse struct {
+	SettlementBatchResponse
+	Deposits []DepositResponse `json:"deposits"`
+	Payouts  []PayoutResponse  `json:"payouts"`
+} //@name SettlementBatchReportResponse
+
+// ProviderStatementResponse represents an imported provider settlement file and how its lines matched
+type ProviderStatementResponse struct {
+	ID             uint      `json:"id" example:"1"`
+	CreatedAt      time.Time `json:"created_at" example:"2023-01-02T09:00:00Z"`
+	Provider       string    `json:"provider" example:"paystack"`
+	FileName       string    `json:"file_name" example:"paystack-settlement-2023-01-01.csv"`
+	UploadedBy     uint      `json:"uploaded_by" example:"3"`
+	LineCount      int       `json:"line_count" example:"17"`
+	MatchedCount   int       `json:"matched_count" example:"15"`
+	ExceptionCount int       `json:"exception_count" example:"2"`
+} //@name ProviderStatementResponse
+
+// StatementLineResponse represents a statement line and the deposit or payout it matched
+type StatementLineResponse struct {
+	ID          uint            `json:"id" example:"1"`
+	StatementID uint            `json:"statement_id" example:"1"`
+	Provider    string          `json:"provider" example:"paystack"`
+	LineNumber  int             `json:"line_number" example:"2"`
+	Reference   string          `json:"reference" example:"PSK_123456"`
+	Type        string          `json:"type,omitempty" example:"CREDIT"`
+	Amount      decimal.Decimal `json:"amount" example:"100.50"`
+	Currency    string          `json:"currency,omitempty" example:"NGN"`
+	ValueDate   *time.Time      `json:"value_date,omitempty" example:"2023-01-01T00:00:00Z"`
+	Status      string          `json:"status" example:"EXCEPTION"`
+	Reason      string          `json:"reason,omitempty" example:"AMOUNT_MISMATCH"`
+	DepositID   *uint           `json:"deposit_id,omitempty" example:"7"`
+	PayoutID    *uint           `json:"payout_id,omitempty" example:"4"`
+	Notes       string          `json:"notes,omitempty" example:"Provider fee deducted from the settled amount"`
+	ResolvedBy  *uint           `json:"resolved_by,omitempty" example:"3"`
+	ResolvedAt  *time.Time      `json:"resolved_at,omitempty" example:"2023-01-02T10:00:00Z"`
+} //@name StatementLineResponse
+
+// ProviderStatementReportResponse is a provider statement with its lines in file order
+type ProviderStatementReportResponse struct {
+	ProviderStatementResponse
+	Lines []StatementLineResponse `json:"lines"`
+} //@name ProviderStatementReportResponse
+
+// ResolveStatementLineRequest closes a reviewed statement exception
+type ResolveStatementLineRequest struct {
+	Note string `json:"note" binding:"required,max=1000" example:"Provider fee deducted from the settled amount"`
+} //@name ResolveStatementLineRequest
+
+// RequestAccountStatementRequest asks for a statement of the user's wallet over a period of days
+type RequestAccountStatementRequest struct {
+	From   string `json:"from" binding:"required" example:"2023-01-01"`
+	To     string `json:"to" binding:"required" example:"2023-12-31"`
+	Format string `json:"format,omitempty" binding:"omitempty,oneof=CSV PDF csv pdf" example:"PDF"` // CSV when empty
+} //@name RequestAccountStatementRequest
+
+// AccountStatementResponse represents a requested account statement; the download link is only set
+// once it is ready
+type AccountStatementResponse struct {
+	ID                uint       `json:"id" example:"1"`
+	CreatedAt         time.Time  `json:"created_at" example:"2024-01-02T09:00:00Z"`
+	WalletID          uint       `json:"wallet_id" example:"1"`
+	From              string     `json:"from" example:"2023-01-01"`
+	To                string     `json:"to" example:"2023-12-31"`
+	Format            string     `json:"format" example:"PDF"`
+	Status            string     `json:"status" example:"READY"`
+	TransactionCount  int        `json:"transaction_count" example:"412"`
+	FileSize          int64      `json:"file_size,omitempty" example:"48211"`
+	FailureReason     string     `json:"failure_reason,omitempty" example:""`
+	CompletedAt       *time.Time `json:"completed_at,omitempty" example:"2024-01-02T09:00:05Z"`
+	DownloadURL       string     `json:"download_url,omitempty" example:"https://api.walletservice.com/api/v1/downloads?expires=1704186900&key=statements%2F1%2F1%2Fstatement-20230101-20231231.pdf&signature=9f86d0"`
+	DownloadExpiresAt *time.Time `json:"download_expires_at,omitempty" example:"2024-01-02T09:15:00Z"`
+} //@name AccountStatementResponse
+
 // Helper functions to convert models to DTOs
 func ToUserResponse(user *models.User) UserResponse {
-	return UserResponse{
-		ID:        user.ID,
-		CreatedAt: user.CreatedAt,
-		UpdatedAt: user.UpdatedAt,
-		Name:      user.Name,
-		Email:     user.Email,
-		Age:       user.Age,
+	response := UserResponse{
+		ID:            user.ID,
+		CreatedAt:     user.CreatedAt,
+		UpdatedAt:     user.UpdatedAt,
+		Name:          user.Name,
+		Email:         user.Email,
+		Age:           user.Age,
+		PhoneNumber:   user.PhoneNumber,
+		PhoneVerified: user.HasVerifiedPhone(),
+
+		DisplayCurrency: user.DisplayCurrency,
+	}
+	if user.Handle != nil {
+		response.Handle = *user.Handle
+	}
+	if user.DateOfBirth != nil {
+		response.DateOfBirth = user.DateOfBirth.Format(time.DateOnly)
+	}
+	if user.AddressLine1 != "" {
+		response.Address = &AddressPayload{
+			Line1:      user.AddressLine1,
+			Line2:      user.AddressLine2,
+			City:       user.City,
+			State:      user.State,
+			PostalCode: user.PostalCode,
+			Country:    user.Country,
+		}
+	}
+	if user.HasAvatar() && user.AvatarUpdatedAt != nil {
+		response.AvatarURL = "/api/v1/users/me/avatar?v=" + strconv.FormatInt(user.AvatarUpdatedAt.Unix(), 10)
+	}
+	return response
+}
+
+func ToLoginAttemptResponse(attempt *models.LoginAttempt) LoginAttemptResponse {
+	return LoginAttemptResponse{
+		ID:            attempt.ID,
+		CreatedAt:     attempt.CreatedAt,
+		IPAddress:     attempt.IPAddress,
+		UserAgent:     attempt.UserAgent,
+		Method:        attempt.Method,
+		Succeeded:     attempt.Succeeded,
+		TwoFactorUsed: attempt.TwoFactorUsed,
+	}
+}
+
+func ToHandleResponse(wallet *models.Wallet) HandleResponse {
+	handle := ""
+	if wallet.User.Handle != nil {
+		handle = *wallet.User.Handle
+	}
+	return HandleResponse{
+		Handle:   handle,
+		Name:     wallet.User.Name,
+		WalletID: wallet.ID,
+		Currency: wallet.Currency,
+	}
+}
+
+func ToTransferChallengeResponse(challenge *models.TransferChallenge) TransferChallengeResponse {
+	return TransferChallengeResponse{
+		ChallengeID: challenge.ID,
+		ExpiresAt:   challenge.ExpiresAt,
+		Channel:     "sms",
+	}
+}
+
+// ToTransferQuoteResponse shows the balances of the sender's wallet as it was when quoted
+func ToTransferQuoteResponse(quote *models.TransferQuote, wallet *models.Wallet) TransferQuoteResponse {
+	return TransferQuoteResponse{
+		QuoteID:        quote.ID,
+		ToWalletID:     quote.ToWalletID,
+		Amount:         quote.Amount,
+		Fee:            quote.Fee,
+		TotalDebit:     quote.TotalDebit(),
+		SourceCurrency: quote.SourceCurrency,
+		TargetCurrency: quote.TargetCurrency,
+		ExchangeRate:   quote.ExchangeRate,
+		CreditAmount:   quote.CreditAmount,
+		BalanceBefore:  wallet.Balance,
+		BalanceAfter:   wallet.Balance.Sub(quote.TotalDebit()),
+		ExpiresAt:      quote.ExpiresAt,
+		WalletNickname: wallet.Nickname,
+	}
+}
+
+func ToSplitPaymentResponse(reference string, debit *models.Transaction, credits []models.Transaction) SplitPaymentResponse {
+	response := SplitPaymentResponse{
+		Reference: reference,
+		Debit:     ToTransactionResponse(debit),
+		Credits:   make([]TransactionResponse, len(credits)),
+	}
+	for i := range credits {
+		response.Credits[i] = ToTransactionResponse(&credits[i])
+	}
+	return response
+}
+
+func ToSessionResponse(session *models.Session, currentTokenID string) SessionResponse {
+	return SessionResponse{
+		ID:        session.ID,
+		CreatedAt: session.CreatedAt,
+		IPAddress: session.IPAddress,
+		UserAgent: session.UserAgent,
+		IssuedAt:  session.IssuedAt,
+		ExpiresAt: session.ExpiresAt,
+		Current:   currentTokenID != "" && session.TokenID == currentTokenID,
 	}
 }
 
 func ToWalletResponse(wallet *models.Wallet) WalletResponse {
 	return WalletResponse{
-		ID:       wallet.ID,
-		UserID:   wallet.UserID,
-		Balance:  wallet.Balance,
-		Currency: wallet.Currency,
-		Status:   string(wallet.Status),
-		Version:  wallet.Version,
+		ID:        wallet.ID,
+		UserID:    wallet.UserID,
+		Balance:   wallet.Balance,
+		Currency:  wallet.Currency,
+		Status:    string(wallet.Status),
+		Version:   wallet.Version,
+		Sandbox:   wallet.Sandbox,
+		TierID:    wallet.TierID,
+		Nickname:  wallet.Nickname,
+		IsDefault: wallet.IsDefault,
+	}
+}
+
+func ToAdminWalletResponse(wallet *models.Wallet) AdminWalletResponse {
+	return AdminWalletResponse{
+		ID:        wallet.ID,
+		CreatedAt: wallet.CreatedAt,
+		UpdatedAt: wallet.UpdatedAt,
+		UserID:    wallet.UserID,
+		UserName:  wallet.User.Name,
+		UserEmail: wallet.User.Email,
+		Balance:   wallet.Balance,
+		Currency:  wallet.Currency,
+		Status:    string(wallet.Status),
+		Version:   wallet.Version,
+		Sandbox:   wallet.Sandbox,
+		TierID:    wallet.TierID,
+		Nickname:  wallet.Nickname,
+		IsDefault: wallet.IsDefault,
 	}
 }
 
@@ -186,12 +1670,453 @@ func ToTransactionResponse(transaction *models.Transaction) TransactionResponse
 		BalanceBefore:      transaction.BalanceBefore,
 		BalanceAfter:       transaction.BalanceAfter,
 		Description:        transaction.Description,
+		Category:           transaction.Category,
 		Status:             string(transaction.Status),
 	}
 }
 
+func ToTransactionLegResponse(transaction *models.Transaction) TransactionLegResponse {
+	return TransactionLegResponse{
+		ID:                 transaction.ID,
+		CreatedAt:          transaction.CreatedAt,
+		Reference:          transaction.Reference,
+		WalletID:           transaction.WalletID,
+		TransactionType:    string(transaction.TransactionType),
+		TransactionPurpose: string(transaction.TransactionPurpose),
+		Amount:             transaction.Amount,
+		Status:             string(transaction.Status),
+	}
+}
+
+func ToTransactionLookupResponse(transaction, counter *models.Transaction) TransactionLookupResponse {
+	response := TransactionLookupResponse{Transaction: ToTransactionResponse(transaction)}
+	if counter != nil {
+		leg := ToTransactionLegResponse(counter)
+		response.CounterLeg = &leg
+	}
+	return response
+}
+
+// ToTransactionDetailResponse starts the status history with the status the transaction was created
+// with, which is the first change's starting status
+func ToTransactionDetailResponse(transaction, related *models.Transaction, changes []models.TransactionStatusChange) TransactionDetailResponse {
+	initial := transaction.Status
+	if len(changes) > 0 {
+		initial = changes[0].FromStatus
+	}
+	response := TransactionDetailResponse{
+		TransactionResponse:  ToTransactionResponse(transaction),
+		Metadata:             transaction.Metadata,
+		RelatedTransactionID: transaction.RelatedTransactionID,
+		StatusHistory:        []TransactionStatusEntry{{Status: string(initial), ChangedAt: transaction.CreatedAt}},
+	}
+	if related != nil {
+		leg := ToTransactionLegResponse(related)
+		response.RelatedTransaction = &leg
+	}
+	for _, change := range changes {
+		response.StatusHistory = append(response.StatusHistory, TransactionStatusEntry{
+			Status:    string(change.ToStatus),
+			Reason:    change.Reason,
+			ChangedAt: change.CreatedAt,
+		})
+	}
+	return response
+}
+
+func ToAdminTransactionResponse(transaction *models.Transaction) AdminTransactionResponse {
+	response := AdminTransactionResponse{
+		TransactionResponse:  ToTransactionResponse(transaction),
+		Metadata:             transaction.Metadata,
+		RelatedTransactionID: transaction.RelatedTransactionID,
+		JournalEntryID:       transaction.JournalEntryID,
+		OriginIP:             transaction.OriginIP,
+		OriginCountry:        transaction.OriginCountry,
+	}
+	if transaction.RelatedTransaction != nil {
+		related := ToTransactionResponse(transaction.RelatedTransaction)
+		response.RelatedTransaction = &related
+	}
+	return response
+}
+
+func ToAuditLogResponse(entry *models.AuditLog) AuditLogResponse {
+	return AuditLogResponse{
+		ID:           entry.ID,
+		CreatedAt:    entry.CreatedAt,
+		ActorID:      entry.ActorID,
+		ActorEmail:   entry.ActorEmail,
+		ActorRole:    entry.ActorRole,
+		Action:       entry.Action,
+		ResourceType: entry.ResourceType,
+		ResourceID:   entry.ResourceID,
+		Path:         entry.Path,
+		StatusCode:   entry.StatusCode,
+		IPAddress:    entry.IPAddress,
+	}
+}
+
+func ToNotificationPreferenceResponse(preference *models.NotificationPreference) NotificationPreferenceResponse {
+	return NotificationPreferenceResponse{
+		EmailEnabled:        preference.EmailEnabled,
+		CreditReceived:      preference.CreditReceived,
+		WithdrawalCompleted: preference.WithdrawalCompleted,
+		TransactionFailed:   preference.TransactionFailed,
+		SuspiciousActivity:  preference.SuspiciousActivity,
+		SMSEnabled:          preference.SMSEnabled,
+		SMSDebitThreshold:   preference.SMSDebitThreshold,
+		PushEnabled:         preference.PushEnabled,
+	}
+}
+
+func ToDepositResponse(deposit *models.Deposit) DepositResponse {
+	return DepositResponse{
+		ID:                deposit.ID,
+		CreatedAt:         deposit.CreatedAt,
+		WalletID:          deposit.WalletID,
+		Provider:          deposit.Provider,
+		ExternalReference: deposit.ExternalReference,
+		Reference:         deposit.Reference,
+		Amount:            deposit.Amount,
+		Currency:          deposit.Currency,
+		Status:            string(deposit.Status),
+		TransactionID:     deposit.TransactionID,
+		FailureReason:     deposit.FailureReason,
+		CompletedAt:       deposit.CompletedAt,
+	}
+}
+
+func ToPayoutResponse(payout *models.Payout) PayoutResponse {
+	return PayoutResponse{
+		ID:                payout.ID,
+		CreatedAt:         payout.CreatedAt,
+		WalletID:          payout.WalletID,
+		TransactionID:     payout.TransactionID,
+		Reference:         payout.Reference,
+		Provider:          payout.Provider,
+		ProviderReference: payout.ProviderReference,
+		Amount:            payout.Amount,
+		Currency:          payout.Currency,
+		Status:            string(payout.Status),
+		FailureReason:     payout.FailureReason,
+		CompletedAt:       payout.CompletedAt,
+	}
+}
+
+func ToDeviceResponse(device *models.DeviceToken) DeviceResponse {
+	return DeviceResponse{
+		ID:         device.ID,
+		CreatedAt:  device.CreatedAt,
+		Platform:   string(device.Platform),
+		DeviceName: device.DeviceName,
+		LastUsedAt: device.LastUsedAt,
+	}
+}
+
+func ToMoneyRequestResponse(request *models.MoneyRequest) MoneyRequestResponse {
+	return MoneyRequestResponse{
+		ID:             request.ID,
+		CreatedAt:      request.CreatedAt,
+		RequesterID:    request.RequesterID,
+		RequesterName:  request.Requester.Name,
+		RequesterEmail: request.Requester.Email,
+		PayerID:        request.PayerID,
+		PayerName:      request.Payer.Name,
+		PayerEmail:     request.Payer.Email,
+		Amount:         request.Amount,
+		Currency:       request.Currency,
+		Note:           request.Note,
+		Status:         string(request.Status),
+		TransactionID:  request.TransactionID,
+		RespondedAt:    request.RespondedAt,
+	}
+}
+
+func ToPaymentLinkResponse(link *models.PaymentLink) PaymentLinkResponse {
+	return PaymentLinkResponse{
+		ID:             link.ID,
+		CreatedAt:      link.CreatedAt,
+		Token:          link.Token,
+		Amount:         link.Amount,
+		Currency:       link.Currency,
+		Description:    link.Description,
+		SingleUse:      link.SingleUse,
+		ExpiresAt:      link.ExpiresAt,
+		Status:         string(link.Status),
+		ViewCount:      link.ViewCount,
+		PaymentCount:   link.PaymentCount,
+		TotalCollected: link.TotalCollected,
+		LastPaidAt:     link.LastPaidAt,
+	}
+}
+
+func ToPublicPaymentLinkResponse(link *models.PaymentLink) PublicPaymentLinkResponse {
+	return PublicPaymentLinkResponse{
+		Token:         link.Token,
+		RecipientName: link.User.Name,
+		Amount:        link.Amount,
+		Currency:      link.Currency,
+		Description:   link.Description,
+		ExpiresAt:     link.ExpiresAt,
+		Payable:       link.IsPayable(),
+	}
+}
+
+func ToWalletTierResponse(tier *models.WalletTier) WalletTierResponse {
+	return WalletTierResponse{
+		ID:                    tier.ID,
+		Name:                  tier.Name,
+		Description:           tier.Description,
+		IsDefault:             tier.IsDefault,
+		TransferFeeFlat:       tier.TransferFeeFlat,
+		TransferFeePercent:    tier.TransferFeePercent,
+		WithdrawalFeeFlat:     tier.WithdrawalFeeFlat,
+		WithdrawalFeePercent:  tier.WithdrawalFeePercent,
+		MaxTransactionAmount:  tier.MaxTransactionAmount,
+		DailyDebitLimit:       tier.DailyDebitLimit,
+		MonthlyDebitLimit:     tier.MonthlyDebitLimit,
+		MaxBalance:            tier.MaxBalance,
+		PaymentLinksEnabled:   tier.PaymentLinksEnabled,
+		QRPaymentsEnabled:     tier.QRPaymentsEnabled,
+		StandingOrdersEnabled: tier.StandingOrdersEnabled,
+	}
+}
+
+func ToBudgetResponse(budget *models.Budget, spent decimal.Decimal, display *fx.Conversion) BudgetResponse {
+	remaining := decimal.Max(budget.Amount.Sub(spent), decimal.Zero)
+	return BudgetResponse{
+		ID:          budget.ID,
+		CreatedAt:   budget.CreatedAt,
+		Category:    budget.Category,
+		Amount:      budget.Amount,
+		Currency:    budget.Currency,
+		Spent:       spent,
+		Remaining:   remaining,
+		PercentUsed: spent.Mul(decimal.NewFromInt(100)).Div(budget.Amount).Round(0),
+
+		DisplaySpent:     ToDisplayValueResponse(display, spent),
+		DisplayRemaining: ToDisplayValueResponse(display, remaining),
+	}
+}
+
+func ToTenantResponse(tenant *models.Tenant) TenantResponse {
+	return TenantResponse{
+		ID:        tenant.ID,
+		CreatedAt: tenant.CreatedAt,
+		Name:      tenant.Name,
+		Slug:      tenant.Slug,
+		Active:    tenant.Active,
+	}
+}
+
+func ToBusinessAccountResponse(account *models.BusinessAccount, role models.BusinessRole) BusinessAccountResponse {
+	response := BusinessAccountResponse{
+		ID:        account.ID,
+		CreatedAt: account.CreatedAt,
+		Name:      account.Name,
+		OwnerID:   account.OwnerID,
+		Role:      string(role),
+		Wallet:    ToWalletResponse(&account.Wallet),
+	}
+	for i := range account.Members {
+		response.Members = append(response.Members, ToBusinessMemberResponse(&account.Members[i]))
+	}
+	return response
+}
+
+func ToBusinessMemberResponse(member *models.BusinessMember) BusinessMemberResponse {
+	return BusinessMemberResponse{
+		UserID: member.UserID,
+		Name:   member.User.Name,
+		Email:  member.User.Email,
+		Role:   string(member.Role),
+	}
+}
+
+func ToPaymentApprovalResponse(approval *models.PaymentApproval) PaymentApprovalResponse {
+	response := PaymentApprovalResponse{
+		ID:                   approval.ID,
+		CreatedAt:            approval.CreatedAt,
+		BusinessAccountID:    approval.BusinessAccountID,
+		Purpose:              string(approval.Purpose),
+		CounterpartyWalletID: approval.CounterpartyWalletID,
+		Amount:               approval.Amount,
+		Currency:             approval.Currency,
+		Reference:            approval.Reference,
+		Description:          approval.Description,
+		InitiatorID:          approval.InitiatorID,
+		Status:               string(approval.Status),
+		ApproverID:           approval.ApproverID,
+		DecisionNote:         approval.DecisionNote,
+		DecidedAt:            approval.DecidedAt,
+		TransactionID:        approval.TransactionID,
+		FailureReason:        approval.FailureReason,
+	}
+	if approval.BankAccount.AccountNumber != "" {
+		response.BankAccount = &BankAccountRequest{
+			BankCode:      approval.BankAccount.BankCode,
+			AccountNumber: approval.BankAccount.AccountNumber,
+			AccountName:   approval.BankAccount.AccountName,
+		}
+	}
+	return response
+}
+
+func ToEscrowResponse(escrow *models.Escrow) EscrowResponse {
+	return EscrowResponse{
+		ID:                       escrow.ID,
+		CreatedAt:                escrow.CreatedAt,
+		Reference:                escrow.Reference,
+		PayerID:                  escrow.PayerID,
+		PayerName:                escrow.Payer.Name,
+		PayerEmail:               escrow.Payer.Email,
+		PayeeID:                  escrow.PayeeID,
+		PayeeName:                escrow.Payee.Name,
+		PayeeEmail:               escrow.Payee.Email,
+		Amount:                   escrow.Amount,
+		Currency:                 escrow.Currency,
+		Condition:                escrow.Condition,
+		ExpiresAt:                escrow.ExpiresAt,
+		Status:                   string(escrow.Status),
+		FundingJournalEntryID:    escrow.FundingJournalEntryID,
+		SettlementJournalEntryID: escrow.SettlementJournalEntryID,
+		SettledAt:                escrow.SettledAt,
+	}
+}
+
+func ToSubscriptionPlanResponse(plan *models.SubscriptionPlan) SubscriptionPlanResponse {
+	return SubscriptionPlanResponse{
+		ID:           plan.ID,
+		CreatedAt:    plan.CreatedAt,
+		MerchantID:   plan.MerchantID,
+		MerchantName: plan.Merchant.Name,
+		Name:         plan.Name,
+		Description:  plan.Description,
+		Amount:       plan.Amount,
+		Currency:     plan.Currency,
+		Interval:     string(plan.Interval),
+		Active:       plan.Active,
+	}
+}
+
+func ToSubscriptionResponse(subscription *models.Subscription) SubscriptionResponse {
+	return SubscriptionResponse{
+		ID:             subscription.ID,
+		CreatedAt:      subscription.CreatedAt,
+		Reference:      subscription.Reference,
+		PlanID:         subscription.PlanID,
+		PlanName:       subscription.Plan.Name,
+		UserID:         subscription.UserID,
+		Amount:         subscription.Plan.Amount,
+		Currency:       subscription.Plan.Currency,
+		Interval:       string(subscription.Plan.Interval),
+		Status:         string(subscription.Status),
+		PaidPeriods:    subscription.PaidPeriods,
+		PaidUntil:      subscription.PaidUntil,
+		NextBillingAt:  subscription.NextBillingAt,
+		FailedAttempts: subscription.FailedAttempts,
+		LastError:      subscription.LastError,
+		SuspendedAt:    subscription.SuspendedAt,
+		CancelledAt:    subscription.CancelledAt,
+	}
+}
+
+func ToSubscriptionChargeResponse(charge *models.SubscriptionCharge) SubscriptionChargeResponse {
+	return SubscriptionChargeResponse{
+		ID:            charge.ID,
+		CreatedAt:     charge.CreatedAt,
+		Reference:     charge.Reference,
+		PeriodStart:   charge.PeriodStart,
+		PeriodEnd:     charge.PeriodEnd,
+		Amount:        charge.Amount,
+		Currency:      charge.Currency,
+		Attempt:       charge.Attempt,
+		Status:        string(charge.Status),
+		Error:         charge.Error,
+		TransactionID: charge.TransactionID,
+	}
+}
+
+func ToWebhookSubscriptionResponse(subscription *models.WebhookSubscription) WebhookSubscriptionResponse {
+	return WebhookSubscriptionResponse{
+		ID:         subscription.ID,
+		CreatedAt:  subscription.CreatedAt,
+		URL:        subscription.URL,
+		EventTypes: subscription.Types(),
+		Active:     subscription.Active,
+	}
+}
+
+func ToWebhookDeliveryResponse(delivery *models.WebhookDelivery) WebhookDeliveryResponse {
+	response := WebhookDeliveryResponse{
+		ID:             delivery.ID,
+		CreatedAt:      delivery.CreatedAt,
+		SubscriptionID: delivery.SubscriptionID,
+		EventID:        delivery.EventID,
+		EventType:      delivery.EventType,
+		Replay:         delivery.Replay,
+		Payload:        delivery.Payload,
+		Status:         string(delivery.Status),
+		Attempts:       delivery.Attempts,
+		LastStatusCode: delivery.LastStatusCode,
+		LastDurationMS: delivery.LastDurationMS,
+		LastError:      delivery.LastError,
+		DeliveredAt:    delivery.DeliveredAt,
+		DeadAt:         delivery.DeadAt,
+	}
+	if delivery.Status == models.WebhookDeliveryPending {
+		response.NextAttemptAt = &delivery.NextAttemptAt
+	}
+	return response
+}
+
+// ToWebhookDeliveryDetailResponse returns a delivery with the history of its attempts
+func ToWebhookDeliveryDetailResponse(delivery *models.WebhookDelivery, attempts []models.WebhookDeliveryAttempt) WebhookDeliveryResponse {
+	response := ToWebhookDeliveryResponse(delivery)
+	response.AttemptHistory = make([]WebhookDeliveryAttemptResponse, len(attempts))
+	for i, attempt := range attempts {
+		response.AttemptHistory[i] = WebhookDeliveryAttemptResponse{
+			CreatedAt:  attempt.CreatedAt,
+			Attempt:    attempt.Attempt,
+			StatusCode: attempt.StatusCode,
+			Error:      attempt.Error,
+			DurationMS: attempt.DurationMS,
+		}
+	}
+	return response
+}
+
+func ToStandingOrderResponse(order *models.StandingOrder) StandingOrderResponse {
+	return StandingOrderResponse{
+		ID:                  order.ID,
+		CreatedAt:           order.CreatedAt,
+		WalletID:            order.WalletID,
+		DestinationWalletID: order.DestinationWalletID,
+		Amount:              order.Amount,
+		Currency:            order.Currency,
+		Description:         order.Description,
+		Frequency:           string(order.Frequency),
+		NextRunAt:           order.NextRunAt,
+		EndsAt:              order.EndsAt,
+		Status:              string(order.Status),
+	}
+}
+
+func ToStandingOrderRunResponse(run *models.StandingOrderRun) StandingOrderRunResponse {
+	return StandingOrderRunResponse{
+		ID:            run.ID,
+		ScheduledFor:  run.ScheduledFor,
+		Status:        string(run.Status),
+		Attempts:      run.Attempts,
+		NextAttemptAt: run.NextAttemptAt,
+		LastError:     run.LastError,
+		TransactionID: run.TransactionID,
+		CompletedAt:   run.CompletedAt,
+	}
+}
+
 func ToReconciliationReportResponse(report *models.ReconciliationReport) ReconciliationReportResponse {
-	return ReconciliationReportResponse{
+	response := ReconciliationReportResponse{
 		ID:                report.ID,
 		CreatedAt:         report.CreatedAt,
 		WalletID:          report.WalletID,
@@ -199,6 +2124,402 @@ func ToReconciliationReportResponse(report *models.ReconciliationReport) Reconci
 		CalculatedBalance: report.CalculatedBalance,
 		Difference:        report.Difference,
 		Status:            string(report.Status),
+		BrokenPairs:       report.BrokenPairs,
 		Notes:             report.Notes,
 	}
+	if !report.HasAnyIssue() {
+		return response
+	}
+	response.ResolutionStatus = string(report.ResolutionStatus)
+	response.AssigneeID = report.AssigneeID
+	response.AcknowledgedBy = report.AcknowledgedBy
+	response.AcknowledgedAt = report.AcknowledgedAt
+	response.InvestigationNotes = report.InvestigationNotes
+	if report.ResolutionType != nil {
+		response.ResolutionType = string(*report.ResolutionType)
+	}
+	response.ResolvedBy = report.ResolvedBy
+	response.ResolvedAt = report.ResolvedAt
+	return response
+}
+
+// ToTransactionTypeResponse converts a TransactionTypeDefinition model to TransactionTypeResponse
+func ToTransactionTypeResponse(definition *models.TransactionTypeDefinition) TransactionTypeResponse {
+	return TransactionTypeResponse{
+		Name:        string(definition.Name),
+		Description: definition.Description,
+	}
+}
+
+// ToTransactionPurposeResponse converts a TransactionPurposeDefinition model to TransactionPurposeResponse
+func ToTransactionPurposeResponse(definition *models.TransactionPurposeDefinition) TransactionPurposeResponse {
+	return TransactionPurposeResponse{
+		Name:                 string(definition.Name),
+		Description:          definition.Description,
+		ChargesFees:          definition.ChargesFees,
+		CountsTowardLimits:   definition.CountsTowardLimits,
+		NotificationTemplate: definition.NotificationTemplate,
+		BuiltIn:              models.IsDefaultTransactionPurpose(definition.Name),
+		UpdatedAt:            definition.UpdatedAt,
+	}
+}
+
+// ToReconciliationSummaryResponse converts a ReconciliationSummary model to ReconciliationSummaryResponse
+func ToReconciliationSummaryResponse(summary *models.ReconciliationSummary) ReconciliationSummaryResponse {
+	// The differences are written by the summary itself, so they always decode
+	differences, _ := summary.Differences()
+	return ReconciliationSummaryResponse{
+		ID:                 summary.ID,
+		Date:               summary.Date.Format("2006-01-02"),
+		Checks:             summary.Checks,
+		WalletsChecked:     summary.WalletsChecked,
+		Matches:            summary.Matches,
+		Mismatches:         summary.Mismatches,
+		DoubleEntryErrors:  summary.DoubleEntryErrors,
+		LargestDifferences: differences,
+		DeliveredAt:        summary.DeliveredAt,
+	}
+}
+
+// ToReconciliationRunResponse converts a ReconciliationRun model to ReconciliationRunResponse
+func ToReconciliationRunResponse(run *models.ReconciliationRun) ReconciliationRunResponse {
+	// The failures and scope are written by the run itself, so they always decode
+	failures, _ := run.FailureList()
+	if failures == nil {
+		failures = []models.ReconciliationRunFailure{}
+	}
+	scope, _ := run.DecodeScope()
+	return ReconciliationRunResponse{
+		ID:                run.ID,
+		CreatedAt:         run.CreatedAt,
+		RequestedBy:       run.RequestedBy,
+		Status:            string(run.Status),
+		Progress:          run.Progress(),
+		TotalWallets:      run.TotalWallets,
+		ProcessedWallets:  run.ProcessedWallets,
+		Matches:           run.Matches,
+		Mismatches:        run.Mismatches,
+		DoubleEntryErrors: run.DoubleEntryErrors,
+		FailedWallets:     run.FailedWallets,
+		Failures:          failures,
+		Scope:             scope,
+		StartedAt:         run.StartedAt,
+		CompletedAt:       run.CompletedAt,
+	}
+}
+
+// ReconciliationReportCSVHeader is the header row of reconciliation report exports
+var ReconciliationReportCSVHeader = []string{
+	"report_id", "created_at", "wallet_id", "currency", "stored_balance", "calculated_balance", "difference", "status", "notes",
+}
+
+// ToReconciliationReportCSVRow converts a reconciliation report to a row of its CSV export
+func ToReconciliationReportCSVRow(report *models.ReconciliationReport) []string {
+	return []string{
+		strconv.FormatUint(uint64(report.ID), 10),
+		report.CreatedAt.UTC().Format(time.RFC3339),
+		strconv.FormatUint(uint64(report.WalletID), 10),
+		report.Wallet.Currency,
+		report.StoredBalance.StringFixed(2),
+		report.CalculatedBalance.StringFixed(2),
+		report.Difference.StringFixed(2),
+		string(report.Status),
+		csvText(report.Notes),
+	}
+}
+
+// csvText keeps free text from being run as a formula by the spreadsheet the export is opened in
+func csvText(value string) string {
+	if value != "" && strings.ContainsRune("=+-@\t\r", rune(value[0])) {
+		return "'" + value
+	}
+	return value
+}
+
+func ToFraudReviewResponse(review *models.FraudReview) FraudReviewResponse {
+	response := FraudReviewResponse{
+		ID:                   review.ID,
+		CreatedAt:            review.CreatedAt,
+		TransactionID:        review.TransactionID,
+		WalletID:             review.WalletID,
+		Purpose:              string(review.Purpose),
+		CounterpartyWalletID: review.CounterpartyWalletID,
+		Amount:               review.Amount,
+		Fee:                  review.Fee,
+		Currency:             review.Currency,
+		Rule:                 review.Rule,
+		Reason:               review.Reason,
+		Status:               string(review.Status),
+		ReviewerID:           review.ReviewerID,
+		ReviewNote:           review.ReviewNote,
+		ReviewedAt:           review.ReviewedAt,
+	}
+	if review.BankAccount.AccountNumber != "" {
+		response.BankAccount = &BankAccountRequest{
+			BankCode:      review.BankAccount.BankCode,
+			AccountNumber: review.BankAccount.AccountNumber,
+			AccountName:   review.BankAccount.AccountName,
+		}
+	}
+	return response
+}
+
+func ToBlocklistEntryResponse(entry *models.BlocklistEntry) BlocklistEntryResponse {
+	return BlocklistEntryResponse{
+		ID:          entry.ID,
+		CreatedAt:   entry.CreatedAt,
+		Type:        string(entry.Type),
+		Value:       entry.Value,
+		Reason:      entry.Reason,
+		CreatedByID: entry.CreatedByID,
+	}
+}
+
+func ToIPAllowlistEntryResponse(entry *models.IPAllowlistEntry) IPAllowlistEntryResponse {
+	return IPAllowlistEntryResponse{
+		ID:          entry.ID,
+		CreatedAt:   entry.CreatedAt,
+		CIDR:        entry.CIDR,
+		Description: entry.Description,
+		CreatedByID: entry.CreatedByID,
+	}
+}
+
+func ToComplianceLogResponse(entry *models.ComplianceLog) ComplianceLogResponse {
+	return ComplianceLogResponse{
+		ID:                   entry.ID,
+		CreatedAt:            entry.CreatedAt,
+		Check:                string(entry.Check),
+		UserID:               entry.UserID,
+		WalletID:             entry.WalletID,
+		CounterpartyWalletID: entry.CounterpartyWalletID,
+		Purpose:              string(entry.Purpose),
+		Amount:               entry.Amount,
+		Reference:            entry.Reference,
+		Subject:              entry.Subject,
+		Reason:               entry.Reason,
+	}
+}
+
+func ToAMLCaseResponse(amlCase *models.AMLCase) AMLCaseResponse {
+	response := AMLCaseResponse{
+		ID:                   amlCase.ID,
+		CreatedAt:            amlCase.CreatedAt,
+		TransactionID:        amlCase.TransactionID,
+		WalletID:             amlCase.WalletID,
+		Purpose:              string(amlCase.Purpose),
+		CounterpartyWalletID: amlCase.CounterpartyWalletID,
+		Amount:               amlCase.Amount,
+		Fee:                  amlCase.Fee,
+		Currency:             amlCase.Currency,
+		Screener:             amlCase.Screener,
+		CaseReference:        amlCase.CaseReference,
+		Reason:               amlCase.Reason,
+		Status:               string(amlCase.Status),
+		ReviewerID:           amlCase.ReviewerID,
+		ReviewNote:           amlCase.ReviewNote,
+		ReviewedAt:           amlCase.ReviewedAt,
+	}
+	if amlCase.BankAccount.AccountNumber != "" {
+		response.BankAccount = &BankAccountRequest{
+			BankCode:      amlCase.BankAccount.BankCode,
+			AccountNumber: amlCase.BankAccount.AccountNumber,
+			AccountName:   amlCase.BankAccount.AccountName,
+		}
+	}
+	return response
+}
+
+func ToJobResponse(job *models.Job) JobResponse {
+	return JobResponse{
+		ID:          job.ID,
+		CreatedAt:   job.CreatedAt,
+		Type:        job.Type,
+		Payload:     job.Payload,
+		Status:      string(job.Status),
+		Attempts:    job.Attempts,
+		MaxAttempts: job.MaxAttempts,
+		RunAt:       job.RunAt,
+		LockedUntil: job.LockedUntil,
+		LastError:   job.LastError,
+	}
+}
+
+func ToCircuitBreakerResponse(stats breaker.Stats) CircuitBreakerResponse {
+	return CircuitBreakerResponse{
+		Name:                stats.Name,
+		State:               string(stats.State),
+		ConsecutiveFailures: stats.ConsecutiveFailures,
+		Successes:           stats.Successes,
+		Failures:            stats.Failures,
+		Rejections:          stats.Rejections,
+		Opens:               stats.Opens,
+		OpenedAt:            stats.OpenedAt,
+	}
+}
+
+func ToLedgerAccountResponse(account *models.LedgerAccount) LedgerAccountResponse {
+	return LedgerAccountResponse{
+		ID:       account.ID,
+		Code:     string(account.Code),
+		Name:     account.Name,
+		Type:     string(account.Type),
+		Sandbox:  account.Sandbox,
+		WalletID: account.WalletID,
+		Balance:  account.Wallet.Balance,
+		Currency: account.Wallet.Currency,
+	}
+}
+
+func ToJournalEntryResponse(entry *models.JournalEntry) JournalEntryResponse {
+	legs := make([]TransactionResponse, len(entry.Legs))
+	for i := range entry.Legs {
+		legs[i] = ToTransactionResponse(&entry.Legs[i])
+	}
+	return JournalEntryResponse{
+		ID:          entry.ID,
+		CreatedAt:   entry.CreatedAt,
+		Reference:   entry.Reference,
+		Description: entry.Description,
+		PostedBy:    entry.PostedBy,
+		Legs:        legs,
+	}
+}
+
+func ToSuspenseItemResponse(item *models.SuspenseItem) SuspenseItemResponse {
+	return SuspenseItemResponse{
+		ID:                       item.ID,
+		CreatedAt:                item.CreatedAt,
+		Provider:                 item.Provider,
+		ExternalReference:        item.ExternalReference,
+		Amount:                   item.Amount,
+		Currency:                 item.Currency,
+		Reason:                   string(item.Reason),
+		DepositID:                item.DepositID,
+		WalletID:                 item.WalletID,
+		Status:                   string(item.Status),
+		JournalEntryID:           item.JournalEntryID,
+		Notes:                    item.Notes,
+		InvestigatorID:           item.InvestigatorID,
+		ReallocatedWalletID:      item.ReallocatedWalletID,
+		ResolutionJournalEntryID: item.ResolutionJournalEntryID,
+		ResolvedBy:               item.ResolvedBy,
+		ResolvedAt:               item.ResolvedAt,
+	}
+}
+
+func ToBalanceAdjustmentResponse(adjustment *models.BalanceAdjustment) BalanceAdjustmentResponse {
+	return BalanceAdjustmentResponse{
+		ID:             adjustment.ID,
+		CreatedAt:      adjustment.CreatedAt,
+		WalletID:       adjustment.WalletID,
+		Type:           string(adjustment.Type),
+		Amount:         adjustment.Amount,
+		Currency:       adjustment.Currency,
+		Reason:         adjustment.Reason,
+		RequestedBy:    adjustment.RequestedBy,
+		Status:         string(adjustment.Status),
+		ApproverID:     adjustment.ApproverID,
+		DecisionNote:   adjustment.DecisionNote,
+		DecidedAt:      adjustment.DecidedAt,
+		JournalEntryID: adjustment.JournalEntryID,
+	}
+}
+
+func ToSettlementBatchResponse(batch *models.SettlementBatch) SettlementBatchResponse {
+	return SettlementBatchResponse{
+		ID:           batch.ID,
+		CreatedAt:    batch.CreatedAt,
+		Provider:     batch.Provider,
+		Currency:     batch.Currency,
+		Date:         batch.Date.Format("2006-01-02"),
+		DepositCount: batch.DepositCount,
+		DepositTotal: batch.DepositTotal,
+		PayoutCount:  batch.PayoutCount,
+		PayoutTotal:  batch.PayoutTotal,
+		ExpectedNet:  batch.ExpectedNet,
+		ReceivedNet:  batch.ReceivedNet,
+		Difference:   batch.Difference,
+		Status:       string(batch.Status),
+		Notes:        batch.Notes,
+		ReconciledBy: batch.ReconciledBy,
+		ReconciledAt: batch.ReconciledAt,
+	}
+}
+
+func ToSettlementBatchReportResponse(batch *models.SettlementBatch) SettlementBatchReportResponse {
+	deposits := make([]DepositResponse, len(batch.Deposits))
+	for i := range batch.Deposits {
+		deposits[i] = ToDepositResponse(&batch.Deposits[i])
+	}
+	payouts := make([]PayoutResponse, len(batch.Payouts))
+	for i := range batch.Payouts {
+		payouts[i] = ToPayoutResponse(&batch.Payouts[i])
+	}
+	return SettlementBatchReportResponse{
+		SettlementBatchResponse: ToSettlementBatchResponse(batch),
+		Deposits:                deposits,
+		Payouts:                 payouts,
+	}
+}
+
+func ToProviderStatementResponse(statement *models.ProviderStatement) ProviderStatementResponse {
+	return ProviderStatementResponse{
+		ID:             statement.ID,
+		CreatedAt:      statement.CreatedAt,
+		Provider:       statement.Provider,
+		FileName:       statement.FileName,
+		UploadedBy:     statement.UploadedBy,
+		LineCount:      statement.LineCount,
+		MatchedCount:   statement.MatchedCount,
+		ExceptionCount: statement.ExceptionCount,
+	}
+}
+
+func ToStatementLineResponse(line *models.StatementLine) StatementLineResponse {
+	return StatementLineResponse{
+		ID:          line.ID,
+		StatementID: line.StatementID,
+		Provider:    line.Provider,
+		LineNumber:  line.LineNumber,
+		Reference:   line.Reference,
+		Type:        string(line.Type),
+		Amount:      line.Amount,
+		Currency:    line.Currency,
+		ValueDate:   line.ValueDate,
+		Status:      string(line.Status),
+		Reason:      string(line.Reason),
+		DepositID:   line.DepositID,
+		PayoutID:    line.PayoutID,
+		Notes:       line.Notes,
+		ResolvedBy:  line.ResolvedBy,
+		ResolvedAt:  line.ResolvedAt,
+	}
+}
+
+func ToProviderStatementReportResponse(statement *models.ProviderStatement) ProviderStatementReportResponse {
+	lines := make([]StatementLineResponse, len(statement.Lines))
+	for i := range statement.Lines {
+		lines[i] = ToStatementLineResponse(&statement.Lines[i])
+	}
+	return ProviderStatementReportResponse{
+		ProviderStatementResponse: ToProviderStatementResponse(statement),
+		Lines:                     lines,
+	}
+}
+
+func ToAccountStatementResponse(statement *models.AccountStatement) AccountStatementResponse {
+	return AccountStatementResponse{
+		ID:               statement.ID,
+		CreatedAt:        statement.CreatedAt,
+		WalletID:         statement.WalletID,
+		From:             statement.PeriodStart.Format("2006-01-02"),
+		To:               statement.PeriodEnd.AddDate(0, 0, -1).Format("2006-01-02"),
+		Format:           string(statement.Format),
+		Status:           string(statement.Status),
+		TransactionCount: statement.TransactionCount,
+		FileSize:         statement.FileSize,
+		FailureReason:    statement.FailureReason,
+		CompletedAt:      statement.CompletedAt,
+	}
 }
@@ -0,0 +1,85 @@
+// Package v2 holds the payloads of the /api/v2 endpoints. They evolve independently of the v1 payloads
+// in the dto package; the response envelope and error responses are shared by both versions
+package v2
+
+import (
+	"time"
+
+	"github.com/limistah/wallet-service/internal/fx"
+	"github.com/limistah/wallet-service/internal/models"
+	"github.com/shopspring/decimal"
+)
+
+// Money is an amount together with its currency
+type Money struct {
+	Amount   decimal.Decimal `json:"amount" example:"1000.50"`
+	Currency string          `json:"currency" example:"USD"`
+} //@name MoneyV2
+
+// DisplayMoney is an amount converted to the display currency of the user at the current exchange
+// rate. It is indicative only: money moves at the rate quoted when it moves
+type DisplayMoney struct {
+	Money
+	Rate       decimal.Decimal `json:"rate" example:"1550"`
+	Indicative bool            `json:"indicative" example:"true"`
+} //@name DisplayMoneyV2
+
+// ToDisplayMoney converts amount for display, or returns nil without a conversion
+func ToDisplayMoney(conversion *fx.Conversion, amount decimal.Decimal) *DisplayMoney {
+	if conversion == nil {
+		return nil
+	}
+	return &DisplayMoney{
+		Money:      Money{Amount: conversion.Convert(amount), Currency: conversion.Currency},
+		Rate:       conversion.Rate,
+		Indicative: true,
+	}
+}
+
+// WalletResponse represents wallet response data; unlike v1 the balance carries its currency
+type WalletResponse struct {
+	ID        uint      `json:"id" example:"1"`
+	UserID    uint      `json:"user_id" example:"1"`
+	Balance   Money     `json:"balance"`
+	Status    string    `json:"status" example:"ACTIVE"`
+	Version   uint      `json:"version" example:"1"`
+	Sandbox   bool      `json:"sandbox" example:"false"`
+	TierID    *uint     `json:"tier_id,omitempty" example:"1"`
+	Nickname  string    `json:"nickname,omitempty" example:"Rent"`
+	IsDefault bool      `json:"is_default" example:"true"`
+	CreatedAt time.Time `json:"created_at" example:"2023-01-01T00:00:00Z"`
+	UpdatedAt time.Time `json:"updated_at" example:"2023-01-01T00:00:00Z"`
+	// DisplayBalance is the balance in the display currency of the user, when they chose one
+	DisplayBalance *DisplayMoney `json:"display_balance,omitempty"`
+} //@name WalletResponseV2
+
+// BalanceResponse represents wallet balance response
+type BalanceResponse struct {
+	WalletID uint  `json:"wallet_id" example:"1"`
+	Balance  Money `json:"balance"`
+	// DisplayBalance is the balance in the display currency of the user, when they chose one
+	DisplayBalance *DisplayMoney `json:"display_balance,omitempty"`
+} //@name BalanceResponseV2
+
+func ToWalletResponse(wallet *models.Wallet) WalletResponse {
+	return WalletResponse{
+		ID:        wallet.ID,
+		UserID:    wallet.UserID,
+		Balance:   Money{Amount: wallet.Balance, Currency: wallet.Currency},
+		Status:    string(wallet.Status),
+		Version:   wallet.Version,
+		Sandbox:   wallet.Sandbox,
+		TierID:    wallet.TierID,
+		Nickname:  wallet.Nickname,
+		IsDefault: wallet.IsDefault,
+		CreatedAt: wallet.CreatedAt,
+		UpdatedAt: wallet.UpdatedAt,
+	}
+}
+
+func ToBalanceResponse(wallet *models.Wallet) BalanceResponse {
+	return BalanceResponse{
+		WalletID: wallet.ID,
+		Balance:  Money{Amount: wallet.Balance, Currency: wallet.Currency},
+	}
+}
@@ -0,0 +1,255 @@
+package events
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/limistah/wallet-service/internal/awsauth"
+	"github.com/limistah/wallet-service/internal/utils"
+)
+
+// AWSConfig says where an AWSPublisher publishes events. Topics and queues whose names end in .fifo
+// receive the events of each wallet in the order they happened
+type AWSConfig struct {
+	Region      string
+	Credentials awsauth.Credentials
+	// TopicARN publishes every event to an SNS topic, QueueURL sends it to an SQS queue; either or
+	// both may be set
+	TopicARN string
+	QueueURL string
+	// QueueSize bounds the events waiting to be published; MaxAttempts is how often publishing an
+	// event is tried before it is dropped
+	QueueSize   int
+	MaxAttempts int
+}
+
+// AWSPublisher is an EventPublisher that publishes events as JSON to an SNS topic or an SQS queue for
+// services running on AWS. Events are published one at a time, in the order they were published, so
+// FIFO topics and queues grouping them by wallet keep each wallet's events in order
+type AWSPublisher struct {
+	config      AWSConfig
+	snsEndpoint string
+	sqsEndpoint string
+	client      *http.Client
+	now         func() time.Time
+	backoff     func(attempt int) time.Duration
+
+	queue chan Event
+	wg    sync.WaitGroup
+	once  sync.Once
+}
+
+// NewAWSPublisher creates a publisher for the topic or queue of cfg and starts publishing
+func NewAWSPublisher(cfg AWSConfig) (*AWSPublisher, error) {
+	if cfg.TopicARN == "" && cfg.QueueURL == "" {
+		return nil, fmt.Errorf("an SNS topic or an SQS queue is required")
+	}
+	if cfg.QueueSize <= 0 {
+		cfg.QueueSize = 1000
+	}
+	if cfg.MaxAttempts <= 0 {
+		cfg.MaxAttempts = 1
+	}
+	p := &AWSPublisher{
+		config:      cfg,
+		snsEndpoint: fmt.Sprintf("https://sns.%s.amazonaws.com/", cfg.Region),
+		client:      &http.Client{Timeout: 10 * time.Second},
+		now:         time.Now,
+		backoff: func(attempt int) time.Duration {
+			return utils.Backoff(200*time.Millisecond, 10*time.Second, attempt)
+		},
+		queue: make(chan Event, cfg.QueueSize),
+	}
+	if cfg.QueueURL != "" {
+		queueURL, err := url.Parse(cfg.QueueURL)
+		if err != nil || queueURL.Host == "" {
+			return nil, fmt.Errorf("invalid SQS queue URL %q", cfg.QueueURL)
+		}
+		p.sqsEndpoint = queueURL.Scheme + "://" + queueURL.Host + "/"
+	}
+	p.wg.Add(1)
+	go p.run()
+	return p, nil
+}
+
+// Publish enqueues an event without blocking the caller; events are dropped when the queue is full
+func (p *AWSPublisher) Publish(event Event) {
+	if event.OccurredAt.IsZero() {
+		event.OccurredAt = time.Now()
+	}
+	select {
+	case p.queue <- event:
+	default:
+		log.Printf("AWS event queue full, dropping %s event for wallet %d", event.Type, event.WalletID)
+	}
+}
+
+// Close stops accepting events and waits for queued events to be published
+func (p *AWSPublisher) Close() {
+	p.once.Do(func() {
+		close(p.queue)
+	})
+	p.wg.Wait()
+}
+
+func (p *AWSPublisher) run() {
+	defer p.wg.Done()
+	for event := range p.queue {
+		message, err := json.Marshal(event)
+		if err != nil {
+			log.Printf("Failed to encode %s event for wallet %d: %v", event.Type, event.WalletID, err)
+			continue
+		}
+		if p.config.TopicARN != "" {
+			p.deliver(event, "SNS", func() error { return p.publishToTopic(event, message) })
+		}
+		if p.config.QueueURL != "" {
+			p.deliver(event, "SQS", func() error { return p.sendToQueue(event, message) })
+		}
+	}
+}
+
+// deliver tries to publish an event until it succeeds, fails for good or runs out of attempts; a later
+// event of the wallet waits meanwhile, so it cannot overtake this one
+func (p *AWSPublisher) deliver(event Event, target string, send func() error) {
+	for attempt := 1; ; attempt++ {
+		err := send()
+		if err == nil {
+			return
+		}
+		var failure *awsError
+		retryable := !errors.As(err, &failure) || failure.retryable()
+		if !retryable || attempt >= p.config.MaxAttempts {
+			log.Printf("Failed to publish %s event for wallet %d to %s after %d attempts: %v", event.Type, event.WalletID, target, attempt, err)
+			return
+		}
+		time.Sleep(p.backoff(attempt))
+	}
+}
+
+// publishToTopic publishes an event with the SNS Publish action
+func (p *AWSPublisher) publishToTopic(event Event, message []byte) error {
+	form := url.Values{}
+	form.Set("Action", "Publish")
+	form.Set("Version", "2010-03-31")
+	form.Set("TopicArn", p.config.TopicARN)
+	form.Set("Message", string(message))
+	for i, attribute := range messageAttributes(event) {
+		prefix := fmt.Sprintf("MessageAttributes.entry.%d.", i+1)
+		form.Set(prefix+"Name", attribute.name)
+		form.Set(prefix+"Value.DataType", attribute.dataType)
+		form.Set(prefix+"Value.StringValue", attribute.value)
+	}
+	if strings.HasSuffix(p.config.TopicARN, ".fifo") {
+		form.Set("MessageGroupId", MessageGroupID(event))
+		form.Set("MessageDeduplicationId", deduplicationID(message))
+	}
+
+	payload := []byte(form.Encode())
+	req, err := http.NewRequest(http.MethodPost, p.snsEndpoint, bytes.NewReader(payload))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded; charset=utf-8")
+	return p.send(req, payload, "sns")
+}
+
+// sendToQueue sends an event with the SQS SendMessage action
+func (p *AWSPublisher) sendToQueue(event Event, message []byte) error {
+	attributes := make(map[string]map[string]string)
+	for _, attribute := range messageAttributes(event) {
+		attributes[attribute.name] = map[string]string{"DataType": attribute.dataType, "StringValue": attribute.value}
+	}
+	body := map[string]interface{}{
+		"QueueUrl":          p.config.QueueURL,
+		"MessageBody":       string(message),
+		"MessageAttributes": attributes,
+	}
+	if strings.HasSuffix(p.config.QueueURL, ".fifo") {
+		body["MessageGroupId"] = MessageGroupID(event)
+		body["MessageDeduplicationId"] = deduplicationID(message)
+	}
+
+	payload, err := json.Marshal(body)
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequest(http.MethodPost, p.sqsEndpoint, bytes.NewReader(payload))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/x-amz-json-1.0")
+	req.Header.Set("X-Amz-Target", "AmazonSQS.SendMessage")
+	return p.send(req, payload, "sqs")
+}
+
+func (p *AWSPublisher) send(req *http.Request, payload []byte, service string) error {
+	awsauth.Sign(req, payload, service, p.config.Region, p.config.Credentials, p.now())
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to reach %s: %w", strings.ToUpper(service), err)
+	}
+	defer resp.Body.Close()
+	body, _ := io.ReadAll(io.LimitReader(resp.Body, 64<<10))
+	if resp.StatusCode >= 200 && resp.StatusCode < 300 {
+		return nil
+	}
+	return &awsError{service: strings.ToUpper(service), status: resp.StatusCode, body: strings.TrimSpace(string(body))}
+}
+
+// MessageGroupID is the FIFO message group of an event: its wallet, or its user for events of an
+// account rather than a wallet
+func MessageGroupID(event Event) string {
+	if event.WalletID == 0 {
+		return "user-" + strconv.FormatUint(uint64(event.UserID), 10)
+	}
+	return "wallet-" + strconv.FormatUint(uint64(event.WalletID), 10)
+}
+
+// deduplicationID identifies a message by its content, so FIFO topics and queues drop a retry of a
+// message they already accepted
+func deduplicationID(message []byte) string {
+	sum := sha256.Sum256(message)
+	return hex.EncodeToString(sum[:])
+}
+
+type messageAttribute struct {
+	name, dataType, value string
+}
+
+// messageAttributes lets subscribers filter events without decoding them
+func messageAttributes(event Event) []messageAttribute {
+	return []messageAttribute{
+		{name: "event_type", dataType: "String", value: string(event.Type)},
+		{name: "wallet_id", dataType: "Number", value: strconv.FormatUint(uint64(event.WalletID), 10)},
+	}
+}
+
+// awsError is a request AWS answered with an error status
+type awsError struct {
+	service string
+	status  int
+	body    string
+}
+
+func (e *awsError) Error() string {
+	return fmt.Sprintf("%s returned status %d: %s", e.service, e.status, e.body)
+}
+
+// retryable reports whether AWS may accept the request later: it was throttled or failed on its side
+func (e *awsError) retryable() bool {
+	return e.status >= http.StatusInternalServerError || e.status == http.StatusTooManyRequests ||
+		strings.Contains(e.body, "Throttl")
+}
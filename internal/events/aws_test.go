@@ -0,0 +1,129 @@
+package events
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/limistah/wallet-service/internal/awsauth"
+)
+
+func newTestAWSPublisher(t *testing.T, cfg AWSConfig, server *httptest.Server) *AWSPublisher {
+	t.Helper()
+	cfg.Region = "eu-west-1"
+	cfg.Credentials = awsauth.Credentials{AccessKeyID: "AKID", SecretAccessKey: "secret"}
+	p := &AWSPublisher{
+		config:      cfg,
+		snsEndpoint: server.URL + "/",
+		sqsEndpoint: server.URL + "/",
+		client:      server.Client(),
+		now:         time.Now,
+		backoff:     func(int) time.Duration { return 0 },
+		queue:       make(chan Event, 10),
+	}
+	if p.config.MaxAttempts == 0 {
+		p.config.MaxAttempts = 1
+	}
+	p.wg.Add(1)
+	go p.run()
+	return p
+}
+
+func TestAWSPublisher_FIFOQueue(t *testing.T) {
+	var mu sync.Mutex
+	var bodies []map[string]interface{}
+	failures := 1
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("X-Amz-Target") != "AmazonSQS.SendMessage" || !strings.HasPrefix(r.Header.Get("Authorization"), "AWS4-HMAC-SHA256 ") {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		mu.Lock()
+		defer mu.Unlock()
+		if failures > 0 {
+			failures--
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		var body map[string]interface{}
+		json.NewDecoder(r.Body).Decode(&body)
+		bodies = append(bodies, body)
+		w.Write([]byte(`{"MessageId":"1"}`))
+	}))
+	defer server.Close()
+
+	queueURL := "https://sqs.eu-west-1.amazonaws.com/123456789012/wallet-events.fifo"
+	p := newTestAWSPublisher(t, AWSConfig{QueueURL: queueURL, MaxAttempts: 3}, server)
+	p.Publish(Event{Type: EventTransferSent, UserID: 1, WalletID: 7, Reference: "REF-1"})
+	p.Publish(Event{Type: EventCreditReceived, UserID: 1, WalletID: 7, Reference: "REF-2"})
+	p.Close()
+
+	if len(bodies) != 2 {
+		t.Fatalf("Expected 2 messages after retrying the failed one, got %d", len(bodies))
+	}
+	for i, reference := range []string{"REF-1", "REF-2"} {
+		body := bodies[i]
+		if body["QueueUrl"] != queueURL || body["MessageGroupId"] != "wallet-7" {
+			t.Errorf("Expected message %d to be grouped by wallet on the queue, got %v", i, body)
+		}
+		if dedup, _ := body["MessageDeduplicationId"].(string); len(dedup) != 64 {
+			t.Errorf("Expected message %d to carry a deduplication ID, got %q", i, dedup)
+		}
+		var event Event
+		json.Unmarshal([]byte(body["MessageBody"].(string)), &event)
+		if event.Reference != reference {
+			t.Errorf("Expected message %d to be %s, got %s", i, reference, event.Reference)
+		}
+	}
+}
+
+func TestAWSPublisher_StandardTopic(t *testing.T) {
+	var form url.Values
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		payload, _ := io.ReadAll(r.Body)
+		form, _ = url.ParseQuery(string(payload))
+		w.WriteHeader(http.StatusBadRequest)
+		w.Write([]byte("<Error><Code>InvalidParameter</Code></Error>"))
+	}))
+	defer server.Close()
+
+	topicARN := "arn:aws:sns:eu-west-1:123456789012:wallet-events"
+	p := newTestAWSPublisher(t, AWSConfig{TopicARN: topicARN, MaxAttempts: 3}, server)
+	p.Publish(Event{Type: EventWithdrawalCompleted, UserID: 1, WalletID: 9})
+	p.Close()
+
+	if form.Get("Action") != "Publish" || form.Get("TopicArn") != topicARN {
+		t.Fatalf("Expected an SNS Publish to the topic, got %v", form)
+	}
+	if form.Get("MessageAttributes.entry.1.Value.StringValue") != string(EventWithdrawalCompleted) ||
+		form.Get("MessageAttributes.entry.2.Value.StringValue") != "9" {
+		t.Errorf("Expected the event type and wallet as message attributes, got %v", form)
+	}
+	if form.Has("MessageGroupId") {
+		t.Errorf("Expected no message group on a standard topic")
+	}
+}
+
+func TestMessageGroupID(t *testing.T) {
+	if got := MessageGroupID(Event{UserID: 3, WalletID: 12}); got != "wallet-12" {
+		t.Errorf("Expected wallet-12, got %s", got)
+	}
+	if got := MessageGroupID(Event{UserID: 3}); got != "user-3" {
+		t.Errorf("Expected user-3 for events without a wallet, got %s", got)
+	}
+}
+
+func TestNewAWSPublisher_RequiresTarget(t *testing.T) {
+	if _, err := NewAWSPublisher(AWSConfig{Region: "eu-west-1"}); err == nil {
+		t.Error("Expected an error without a topic or queue")
+	}
+	if _, err := NewAWSPublisher(AWSConfig{Region: "eu-west-1", QueueURL: "not a url"}); err == nil {
+		t.Error("Expected an error for an invalid queue URL")
+	}
+}
@@ -0,0 +1,80 @@
+package events
+
+import (
+	"log"
+	"sync"
+	"time"
+)
+
+// Bus is an in-process EventPublisher that dispatches events to subscribers asynchronously
+type Bus struct {
+	mu       sync.RWMutex
+	handlers []Handler
+	queue    chan Event
+	wg       sync.WaitGroup
+	once     sync.Once
+}
+
+// NewBus creates a new event bus with the given queue size and starts its dispatcher
+func NewBus(queueSize int) *Bus {
+	if queueSize <= 0 {
+		queueSize = 1000
+	}
+	bus := &Bus{
+		queue: make(chan Event, queueSize),
+	}
+	bus.wg.Add(1)
+	go bus.dispatch()
+	return bus
+}
+
+// Subscribe registers a handler that receives every published event
+func (b *Bus) Subscribe(handler Handler) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.handlers = append(b.handlers, handler)
+}
+
+// Publish enqueues an event without blocking the caller; events are dropped when the queue is full
+func (b *Bus) Publish(event Event) {
+	if event.OccurredAt.IsZero() {
+		event.OccurredAt = time.Now()
+	}
+	select {
+	case b.queue <- event:
+	default:
+		log.Printf("Event queue full, dropping %s event for wallet %d", event.Type, event.WalletID)
+	}
+}
+
+// Close stops accepting events and waits for queued events to be dispatched
+func (b *Bus) Close() {
+	b.once.Do(func() {
+		close(b.queue)
+	})
+	b.wg.Wait()
+}
+
+func (b *Bus) dispatch() {
+	defer b.wg.Done()
+	for event := range b.queue {
+		b.mu.RLock()
+		handlers := make([]Handler, len(b.handlers))
+		copy(handlers, b.handlers)
+		b.mu.RUnlock()
+
+		for _, handler := range handlers {
+			b.invoke(handler, event)
+		}
+	}
+}
+
+// invoke runs a handler and keeps a panicking subscriber from stopping the dispatcher
+func (b *Bus) invoke(handler Handler, event Event) {
+	defer func() {
+		if r := recover(); r != nil {
+			log.Printf("Event handler panicked on %s event: %v", event.Type, r)
+		}
+	}()
+	handler(event)
+}
@@ -0,0 +1,77 @@
+package events
+
+import (
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+// EventType identifies a domain event emitted by the use cases
+type EventType string
+
+const (
+	EventCreditReceived      EventType = "wallet.credit_received"
+	EventWithdrawalCompleted EventType = "wallet.withdrawal_completed"
+	EventTransferSent        EventType = "wallet.transfer_sent"
+	EventTransactionFailed   EventType = "wallet.transaction_failed"
+	EventTransactionExpired  EventType = "wallet.transaction_expired"
+	EventSuspiciousActivity  EventType = "wallet.suspicious_activity"
+	EventMoneyRequested      EventType = "wallet.money_requested"
+	EventStandingOrderFailed EventType = "wallet.standing_order_failed"
+	// EventSubscriptionPaymentFailed is raised when a subscription charge fails and will be retried
+	EventSubscriptionPaymentFailed EventType = "wallet.subscription_payment_failed"
+	// EventSubscriptionSuspended is raised when a subscription is suspended after its last failed retry
+	EventSubscriptionSuspended EventType = "wallet.subscription_suspended"
+	// EventBudgetThreshold is raised when spending in a budget category reaches 80% or 100% of the budget
+	EventBudgetThreshold EventType = "wallet.budget_threshold"
+	// EventAccountLocked is raised when too many failed logins lock an account
+	EventAccountLocked EventType = "user.account_locked"
+	// EventNewDeviceLogin is raised when a user logs in from a browser or app never used before
+	EventNewDeviceLogin EventType = "user.new_device_login"
+)
+
+// IsKnownEventType checks if the use cases emit events of the given type
+func IsKnownEventType(eventType EventType) bool {
+	switch eventType {
+	case EventCreditReceived, EventWithdrawalCompleted, EventTransferSent, EventTransactionFailed,
+		EventTransactionExpired, EventSuspiciousActivity, EventMoneyRequested, EventStandingOrderFailed,
+		EventSubscriptionPaymentFailed, EventSubscriptionSuspended, EventBudgetThreshold,
+		EventAccountLocked, EventNewDeviceLogin:
+		return true
+	default:
+		return false
+	}
+}
+
+// Event represents something that happened to a wallet
+type Event struct {
+	Type          EventType         `json:"type"`
+	UserID        uint              `json:"user_id"`
+	WalletID      uint              `json:"wallet_id"`
+	TransactionID uint              `json:"transaction_id,omitempty"`
+	Reference     string            `json:"reference,omitempty"`
+	Amount        decimal.Decimal   `json:"amount"`
+	Currency      string            `json:"currency"`
+	Reason        string            `json:"reason,omitempty"`
+	Data          map[string]string `json:"data,omitempty"`
+	OccurredAt    time.Time         `json:"occurred_at"`
+}
+
+// IsDebit checks if the event represents money leaving the wallet
+func (e Event) IsDebit() bool {
+	return e.Type == EventWithdrawalCompleted || e.Type == EventTransferSent
+}
+
+// EventPublisher publishes domain events to interested subscribers
+type EventPublisher interface {
+	Publish(event Event)
+}
+
+// Handler processes a published event
+type Handler func(event Event)
+
+// NoopPublisher discards every event
+type NoopPublisher struct{}
+
+// Publish implements EventPublisher
+func (NoopPublisher) Publish(Event) {}
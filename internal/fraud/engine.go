@@ -0,0 +1,102 @@
+package fraud
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/limistah/wallet-service/internal/models"
+	"github.com/shopspring/decimal"
+)
+
+// Action is what the engine decides to do with a debit
+type Action string
+
+const (
+	ActionAllow Action = "ALLOW"
+	ActionFlag  Action = "FLAG"  // Hold the debit as PENDING_REVIEW until an admin approves or rejects it
+	ActionBlock Action = "BLOCK" // Reject the debit outright
+)
+
+// ParseAction reads a configured action, accepting only FLAG and BLOCK
+func ParseAction(value string) (Action, error) {
+	switch Action(value) {
+	case ActionFlag, ActionBlock:
+		return Action(value), nil
+	default:
+		return "", fmt.Errorf("invalid fraud rule action %q: must be FLAG or BLOCK", value)
+	}
+}
+
+// severity orders actions so the strictest rule outcome wins
+func (a Action) severity() int {
+	switch a {
+	case ActionBlock:
+		return 2
+	case ActionFlag:
+		return 1
+	default:
+		return 0
+	}
+}
+
+// Origin is where a user-initiated debit was requested from
+type Origin struct {
+	IPAddress string
+	Country   string
+}
+
+// Check describes a debit about to be made
+type Check struct {
+	Purpose              models.TransactionPurpose
+	WalletID             uint
+	CounterpartyWalletID uint // Zero for withdrawals
+	Amount               decimal.Decimal
+	Origin               *Origin // Nil when the debit did not come from an API request, such as standing orders
+	At                   time.Time
+}
+
+// History gives rules access to the past activity of a wallet
+type History interface {
+	// RecentDebits returns the debits of the wallet since the given time, fees excluded
+	RecentDebits(walletID uint, since time.Time) ([]models.Transaction, error)
+	// HasTransferredTo reports whether the wallet has completed a transfer to the counterparty before
+	HasTransferredTo(walletID, counterpartyWalletID uint) (bool, error)
+}
+
+// Rule inspects a debit and returns ActionAllow or the action it was configured with, and the reason
+type Rule interface {
+	Name() string
+	Evaluate(check Check, history History) (Action, string, error)
+}
+
+// Verdict is the outcome of evaluating every rule against a debit
+type Verdict struct {
+	Action Action
+	Rule   string
+	Reason string
+}
+
+// Engine evaluates debits against an ordered set of rules
+type Engine struct {
+	rules []Rule
+}
+
+// NewEngine creates a rules engine
+func NewEngine(rules ...Rule) *Engine {
+	return &Engine{rules: rules}
+}
+
+// Evaluate runs every rule and returns the strictest outcome; the first rule wins between equal outcomes
+func (e *Engine) Evaluate(check Check, history History) (Verdict, error) {
+	verdict := Verdict{Action: ActionAllow}
+	for _, rule := range e.rules {
+		action, reason, err := rule.Evaluate(check, history)
+		if err != nil {
+			return Verdict{}, fmt.Errorf("fraud rule %s failed: %w", rule.Name(), err)
+		}
+		if action.severity() > verdict.Action.severity() {
+			verdict = Verdict{Action: action, Rule: rule.Name(), Reason: reason}
+		}
+	}
+	return verdict, nil
+}
@@ -0,0 +1,185 @@
+package fraud
+
+import (
+	"testing"
+	"time"
+
+	"github.com/limistah/wallet-service/internal/models"
+	"github.com/shopspring/decimal"
+)
+
+// fakeHistory serves fixed debits and known recipients
+type fakeHistory struct {
+	debits     []models.Transaction
+	recipients map[uint]bool
+}
+
+func (h fakeHistory) RecentDebits(walletID uint, since time.Time) ([]models.Transaction, error) {
+	var debits []models.Transaction
+	for _, debit := range h.debits {
+		if debit.WalletID == walletID && !debit.CreatedAt.Before(since) {
+			debits = append(debits, debit)
+		}
+	}
+	return debits, nil
+}
+
+func (h fakeHistory) HasTransferredTo(walletID, counterpartyWalletID uint) (bool, error) {
+	return h.recipients[counterpartyWalletID], nil
+}
+
+func debit(amount int64, at time.Time, ip, country string) models.Transaction {
+	return models.Transaction{
+		WalletID:      1,
+		Amount:        decimal.NewFromInt(amount),
+		CreatedAt:     at,
+		OriginIP:      ip,
+		OriginCountry: country,
+	}
+}
+
+func TestVelocityRule(t *testing.T) {
+	now := time.Now()
+	rule := VelocityRule{Limit: 3, Window: time.Minute, Action: ActionBlock}
+	check := Check{Purpose: models.TransactionPurposeTransfer, WalletID: 1, Amount: decimal.NewFromInt(10), At: now}
+
+	history := fakeHistory{debits: []models.Transaction{
+		debit(10, now.Add(-10*time.Second), "", ""),
+		debit(10, now.Add(-20*time.Second), "", ""),
+		debit(10, now.Add(-2*time.Minute), "", ""),
+	}}
+	if action, _, _ := rule.Evaluate(check, history); action != ActionAllow {
+		t.Errorf("Expected the third debit within a minute to be allowed, got %s", action)
+	}
+
+	history.debits = append(history.debits, debit(10, now.Add(-30*time.Second), "", ""))
+	if action, _, _ := rule.Evaluate(check, history); action != ActionBlock {
+		t.Errorf("Expected the fourth debit within a minute to be blocked, got %s", action)
+	}
+}
+
+func TestNewRecipientRule(t *testing.T) {
+	rule := NewRecipientRule{Threshold: decimal.NewFromInt(1000), Action: ActionFlag}
+	history := fakeHistory{recipients: map[uint]bool{2: true}}
+	check := Check{Purpose: models.TransactionPurposeTransfer, WalletID: 1, CounterpartyWalletID: 3, Amount: decimal.NewFromInt(1500), At: time.Now()}
+
+	if action, _, _ := rule.Evaluate(check, history); action != ActionFlag {
+		t.Errorf("Expected a large transfer to a new recipient to be flagged, got %s", action)
+	}
+
+	check.CounterpartyWalletID = 2
+	if action, _, _ := rule.Evaluate(check, history); action != ActionAllow {
+		t.Errorf("Expected a transfer to a known recipient to be allowed, got %s", action)
+	}
+
+	check.CounterpartyWalletID = 3
+	check.Amount = decimal.NewFromInt(999)
+	if action, _, _ := rule.Evaluate(check, history); action != ActionAllow {
+		t.Errorf("Expected a transfer below the threshold to be allowed, got %s", action)
+	}
+
+	check.Purpose = models.TransactionPurposeWithdrawal
+	check.Amount = decimal.NewFromInt(1500)
+	if action, _, _ := rule.Evaluate(check, history); action != ActionAllow {
+		t.Errorf("Expected withdrawals to be ignored, got %s", action)
+	}
+}
+
+func TestOriginAnomalyRule(t *testing.T) {
+	now := time.Now()
+	rule := OriginAnomalyRule{Threshold: decimal.NewFromInt(100), Action: ActionFlag}
+	check := Check{
+		Purpose:  models.TransactionPurposeWithdrawal,
+		WalletID: 1,
+		Amount:   decimal.NewFromInt(500),
+		Origin:   &Origin{IPAddress: "198.51.100.9", Country: "RU"},
+		At:       now,
+	}
+
+	if action, _, _ := rule.Evaluate(check, fakeHistory{}); action != ActionAllow {
+		t.Errorf("Expected wallets without origin history to be allowed, got %s", action)
+	}
+
+	history := fakeHistory{debits: []models.Transaction{debit(50, now.Add(-time.Hour), "203.0.113.7", "NG")}}
+	if action, reason, _ := rule.Evaluate(check, history); action != ActionFlag {
+		t.Errorf("Expected a debit from an unfamiliar location to be flagged, got %s", action)
+	} else if reason != "debit requested from unfamiliar location 198.51.100.9 (RU)" {
+		t.Errorf("Unexpected reason: %s", reason)
+	}
+
+	check.Origin = &Origin{IPAddress: "198.51.100.9", Country: "NG"}
+	if action, _, _ := rule.Evaluate(check, history); action != ActionAllow {
+		t.Errorf("Expected a debit from a known country to be allowed, got %s", action)
+	}
+
+	check.Origin = nil
+	if action, _, _ := rule.Evaluate(check, history); action != ActionAllow {
+		t.Errorf("Expected debits without an origin to be allowed, got %s", action)
+	}
+}
+
+func TestStructuringRule(t *testing.T) {
+	now := time.Now()
+	rule := StructuringRule{
+		Threshold: decimal.NewFromInt(10000),
+		Margin:    decimal.NewFromInt(10),
+		MinCount:  3,
+		Window:    24 * time.Hour,
+		Action:    ActionFlag,
+	}
+	check := Check{Purpose: models.TransactionPurposeTransfer, WalletID: 1, Amount: decimal.NewFromInt(9500), At: now}
+
+	history := fakeHistory{debits: []models.Transaction{
+		debit(9900, now.Add(-time.Hour), "", ""),
+		debit(5000, now.Add(-2*time.Hour), "", ""),
+		debit(9800, now.Add(-48*time.Hour), "", ""),
+	}}
+	if action, _, _ := rule.Evaluate(check, history); action != ActionAllow {
+		t.Errorf("Expected two debits just below the threshold to be allowed, got %s", action)
+	}
+
+	history.debits = append(history.debits, debit(9000, now.Add(-3*time.Hour), "", ""))
+	if action, _, _ := rule.Evaluate(check, history); action != ActionFlag {
+		t.Errorf("Expected three debits just below the threshold to be flagged, got %s", action)
+	}
+
+	check.Amount = decimal.NewFromInt(10000)
+	if action, _, _ := rule.Evaluate(check, history); action != ActionAllow {
+		t.Errorf("Expected a debit at the threshold to be allowed, got %s", action)
+	}
+}
+
+func TestEngine_StrictestRuleWins(t *testing.T) {
+	engine := NewEngine(
+		NewRecipientRule{Threshold: decimal.NewFromInt(100), Action: ActionFlag},
+		VelocityRule{Limit: 1, Window: time.Minute, Action: ActionBlock},
+	)
+	now := time.Now()
+	check := Check{Purpose: models.TransactionPurposeTransfer, WalletID: 1, CounterpartyWalletID: 2, Amount: decimal.NewFromInt(200), At: now}
+
+	verdict, err := engine.Evaluate(check, fakeHistory{})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if verdict.Action != ActionFlag || verdict.Rule != "new_recipient" {
+		t.Errorf("Expected the new recipient rule to flag the debit, got %+v", verdict)
+	}
+
+	verdict, _ = engine.Evaluate(check, fakeHistory{debits: []models.Transaction{debit(10, now, "", "")}})
+	if verdict.Action != ActionBlock || verdict.Rule != "velocity" {
+		t.Errorf("Expected the velocity rule to block the debit, got %+v", verdict)
+	}
+
+	if verdict, _ := NewEngine().Evaluate(check, fakeHistory{}); verdict.Action != ActionAllow {
+		t.Errorf("Expected an engine without rules to allow every debit, got %+v", verdict)
+	}
+}
+
+func TestParseAction(t *testing.T) {
+	if action, err := ParseAction("FLAG"); err != nil || action != ActionFlag {
+		t.Errorf("Expected FLAG to parse, got %s, %v", action, err)
+	}
+	if _, err := ParseAction("ALLOW"); err == nil {
+		t.Error("Expected ALLOW to be rejected as a rule action")
+	}
+}
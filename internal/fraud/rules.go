@@ -0,0 +1,133 @@
+package fraud
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/limistah/wallet-service/internal/models"
+	"github.com/shopspring/decimal"
+)
+
+// originLookback is how far back the origins of past debits are considered known
+const originLookback = 90 * 24 * time.Hour
+
+// VelocityRule catches bursts of debits: more than Limit debits within Window
+type VelocityRule struct {
+	Limit  int
+	Window time.Duration
+	Action Action
+}
+
+func (r VelocityRule) Name() string { return "velocity" }
+
+func (r VelocityRule) Evaluate(check Check, history History) (Action, string, error) {
+	debits, err := history.RecentDebits(check.WalletID, check.At.Add(-r.Window))
+	if err != nil {
+		return ActionAllow, "", err
+	}
+	if len(debits)+1 > r.Limit {
+		return r.Action, fmt.Sprintf("more than %d debits within %s", r.Limit, r.Window), nil
+	}
+	return ActionAllow, "", nil
+}
+
+// NewRecipientRule catches large transfers to a wallet the sender has never paid before
+type NewRecipientRule struct {
+	Threshold decimal.Decimal
+	Action    Action
+}
+
+func (r NewRecipientRule) Name() string { return "new_recipient" }
+
+func (r NewRecipientRule) Evaluate(check Check, history History) (Action, string, error) {
+	if check.Purpose != models.TransactionPurposeTransfer || check.Amount.LessThan(r.Threshold) {
+		return ActionAllow, "", nil
+	}
+	known, err := history.HasTransferredTo(check.WalletID, check.CounterpartyWalletID)
+	if err != nil {
+		return ActionAllow, "", err
+	}
+	if !known {
+		return r.Action, fmt.Sprintf("transfer of %s to a new recipient", check.Amount.StringFixed(2)), nil
+	}
+	return ActionAllow, "", nil
+}
+
+// OriginAnomalyRule catches debits of at least Threshold requested from an IP address and country
+// that none of the wallet's recent debits came from. Wallets without origin history are not checked
+type OriginAnomalyRule struct {
+	Threshold decimal.Decimal
+	Action    Action
+}
+
+func (r OriginAnomalyRule) Name() string { return "origin_anomaly" }
+
+func (r OriginAnomalyRule) Evaluate(check Check, history History) (Action, string, error) {
+	if check.Origin == nil || check.Origin.IPAddress == "" || check.Amount.LessThan(r.Threshold) {
+		return ActionAllow, "", nil
+	}
+
+	debits, err := history.RecentDebits(check.WalletID, check.At.Add(-originLookback))
+	if err != nil {
+		return ActionAllow, "", err
+	}
+
+	seen := false
+	for _, debit := range debits {
+		if debit.OriginIP == "" {
+			continue
+		}
+		seen = true
+		if debit.OriginIP == check.Origin.IPAddress ||
+			(check.Origin.Country != "" && debit.OriginCountry == check.Origin.Country) {
+			return ActionAllow, "", nil
+		}
+	}
+	if !seen {
+		return ActionAllow, "", nil
+	}
+
+	location := check.Origin.IPAddress
+	if check.Origin.Country != "" {
+		location = fmt.Sprintf("%s (%s)", location, check.Origin.Country)
+	}
+	return r.Action, fmt.Sprintf("debit requested from unfamiliar location %s", location), nil
+}
+
+// StructuringRule catches amounts split to stay under a reporting threshold: MinCount or more debits
+// within Window that each fall within Margin percent below Threshold
+type StructuringRule struct {
+	Threshold decimal.Decimal
+	Margin    decimal.Decimal
+	MinCount  int
+	Window    time.Duration
+	Action    Action
+}
+
+func (r StructuringRule) Name() string { return "structuring" }
+
+func (r StructuringRule) Evaluate(check Check, history History) (Action, string, error) {
+	floor := r.Threshold.Sub(r.Threshold.Mul(r.Margin).Div(decimal.NewFromInt(100)))
+	justBelow := func(amount decimal.Decimal) bool {
+		return amount.GreaterThanOrEqual(floor) && amount.LessThan(r.Threshold)
+	}
+	if !justBelow(check.Amount) {
+		return ActionAllow, "", nil
+	}
+
+	debits, err := history.RecentDebits(check.WalletID, check.At.Add(-r.Window))
+	if err != nil {
+		return ActionAllow, "", err
+	}
+
+	count := 1
+	for _, debit := range debits {
+		if justBelow(debit.Amount) {
+			count++
+		}
+	}
+	if count >= r.MinCount {
+		return r.Action, fmt.Sprintf("%d debits just below %s within %s", count, r.Threshold.StringFixed(2), r.Window), nil
+	}
+	return ActionAllow, "", nil
+}
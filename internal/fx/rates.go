@@ -0,0 +1,79 @@
+package fx
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/shopspring/decimal"
+)
+
+// ErrRateUnavailable is returned when no rate is known for a currency pair
+var ErrRateUnavailable = errors.New("exchange rate unavailable")
+
+// RateProvider gives the rate at which an amount in one currency is converted to another, typically
+// from an external market data provider
+type RateProvider interface {
+	// Rate returns how many units of to one unit of from buys
+	Rate(from, to string) (decimal.Decimal, error)
+}
+
+// StaticRates serves fixed rates, looking pairs up in either direction
+type StaticRates struct {
+	rates map[string]decimal.Decimal
+}
+
+// ParseRates reads rates written as FROM/TO=RATE, for example "USD/NGN=1550,EUR/USD=1.08"
+func ParseRates(entries []string) (*StaticRates, error) {
+	r := &StaticRates{rates: make(map[string]decimal.Decimal)}
+	for _, entry := range entries {
+		pair, value, ok := strings.Cut(strings.TrimSpace(entry), "=")
+		from, to, pairOK := strings.Cut(pair, "/")
+		if !ok || !pairOK {
+			return nil, fmt.Errorf("invalid exchange rate %q: use FROM/TO=RATE", entry)
+		}
+		rate, err := decimal.NewFromString(strings.TrimSpace(value))
+		if err != nil || !rate.IsPositive() {
+			return nil, fmt.Errorf("invalid exchange rate %q: rate must be a positive number", entry)
+		}
+		r.rates[pairKey(from, to)] = rate
+	}
+	return r, nil
+}
+
+// Rate returns the configured rate of the pair, or the inverse of the reverse pair
+func (r *StaticRates) Rate(from, to string) (decimal.Decimal, error) {
+	if strings.EqualFold(from, to) {
+		return decimal.NewFromInt(1), nil
+	}
+	if rate, ok := r.rates[pairKey(from, to)]; ok {
+		return rate, nil
+	}
+	if rate, ok := r.rates[pairKey(to, from)]; ok {
+		return decimal.NewFromInt(1).DivRound(rate, 8), nil
+	}
+	return decimal.Zero, ErrRateUnavailable
+}
+
+func pairKey(from, to string) string {
+	return strings.ToUpper(strings.TrimSpace(from)) + "/" + strings.ToUpper(strings.TrimSpace(to))
+}
+
+// Conversion converts amounts into Currency at Rate
+type Conversion struct {
+	Currency string
+	Rate     decimal.Decimal
+}
+
+// Convert returns amount in the currency of the conversion, rounded to cents
+func (c Conversion) Convert(amount decimal.Decimal) decimal.Decimal {
+	return amount.Mul(c.Rate).Round(2)
+}
+
+// String identifies the conversion, such as in cache validators; it is empty without a conversion
+func (c *Conversion) String() string {
+	if c == nil {
+		return ""
+	}
+	return c.Currency + "@" + c.Rate.String()
+}
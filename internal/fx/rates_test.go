@@ -0,0 +1,45 @@
+package fx
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/shopspring/decimal"
+)
+
+func TestStaticRates(t *testing.T) {
+	rates, err := ParseRates([]string{"USD/NGN=1550", " eur/usd = 1.08 "})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	tests := []struct {
+		from, to string
+		want     string
+	}{
+		{"USD", "NGN", "1550"},
+		{"NGN", "USD", "0.00064516"},
+		{"EUR", "USD", "1.08"},
+		{"usd", "USD", "1"},
+	}
+	for _, tt := range tests {
+		rate, err := rates.Rate(tt.from, tt.to)
+		if err != nil {
+			t.Errorf("%s/%s: unexpected error: %v", tt.from, tt.to, err)
+			continue
+		}
+		if !rate.Equal(decimal.RequireFromString(tt.want)) {
+			t.Errorf("%s/%s: expected %s, got %s", tt.from, tt.to, tt.want, rate)
+		}
+	}
+
+	if _, err := rates.Rate("EUR", "NGN"); !errors.Is(err, ErrRateUnavailable) {
+		t.Errorf("Expected no rate for EUR/NGN, got: %v", err)
+	}
+
+	for _, entry := range []string{"USDNGN=1550", "USD/NGN", "USD/NGN=-1", "USD/NGN=abc"} {
+		if _, err := ParseRates([]string{entry}); err == nil {
+			t.Errorf("Expected %q to be rejected", entry)
+		}
+	}
+}
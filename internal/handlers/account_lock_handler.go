@@ -0,0 +1,56 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/limistah/wallet-service/internal/dto"
+	"github.com/limistah/wallet-service/internal/middleware"
+	"github.com/limistah/wallet-service/internal/usecases"
+)
+
+type AccountLockHandler struct {
+	loginUseCase usecases.LoginUseCase
+}
+
+func NewAccountLockHandler(loginUseCase usecases.LoginUseCase) *AccountLockHandler {
+	return &AccountLockHandler{
+		loginUseCase: loginUseCase,
+	}
+}
+
+// UnlockUser godoc
+//
+//	@Summary		Unlock a user account
+//	@Description	Lift the lock of an account locked after too many failed logins and clear its failed login count (admin only)
+//	@Tags			admin
+//	@Accept			json
+//	@Produce		json
+//	@Security		BearerAuth
+//	@Param			id	path		int	true	"User ID"
+//	@Success		200	{object}	dto.APIResponse{data=dto.UserResponse}
+//	@Failure		400	{object}	dto.ErrorResponse
+//	@Failure		401	{object}	dto.ErrorResponse
+//	@Failure		403	{object}	dto.ErrorResponse
+//	@Failure		404	{object}	dto.ErrorResponse
+//	@Failure		500	{object}	dto.ErrorResponse
+//	@Router			/admin/users/{id}/unlock [post]
+func (h *AccountLockHandler) UnlockUser(c *gin.Context) {
+	userID, err := parseIDParam(c, "id")
+	if err != nil {
+		c.Error(err).SetMeta("user.invalid_id")
+		return
+	}
+
+	user, err := h.loginUseCase.UnlockUser(c.Request.Context(), userID)
+	if err != nil {
+		c.Error(err).SetMeta("user.unlock_failed")
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.APIResponse{
+		Success: true,
+		Message: middleware.Translate(c, "user.unlocked"),
+		Data:    dto.ToUserResponse(user),
+	})
+}
@@ -0,0 +1,208 @@
+package handlers
+
+import (
+	"fmt"
+	"net/http"
+	"path"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/limistah/wallet-service/internal/apperrors"
+	"github.com/limistah/wallet-service/internal/dto"
+	"github.com/limistah/wallet-service/internal/middleware"
+	"github.com/limistah/wallet-service/internal/models"
+	"github.com/limistah/wallet-service/internal/usecases"
+)
+
+type AccountStatementHandler struct {
+	accountStatementUseCase usecases.AccountStatementUseCase
+}
+
+func NewAccountStatementHandler(accountStatementUseCase usecases.AccountStatementUseCase) *AccountStatementHandler {
+	return &AccountStatementHandler{
+		accountStatementUseCase: accountStatementUseCase,
+	}
+}
+
+// RequestStatement godoc
+//
+//	@Summary		Request an account statement
+//	@Description	Ask for a CSV or PDF statement of the authenticated user's wallet over up to a year. The statement is rendered in the background; poll GET /statements/{id} for its download link.
+//	@Tags			statements
+//	@Accept			json
+//	@Produce		json
+//	@Security		BearerAuth
+//	@Param			request	body		dto.RequestAccountStatementRequest	true	"Statement period and format"
+//	@Success		202		{object}	dto.APIResponse{data=dto.AccountStatementResponse}
+//	@Failure		400		{object}	dto.ErrorResponse
+//	@Failure		401		{object}	dto.ErrorResponse
+//	@Failure		404		{object}	dto.ErrorResponse
+//	@Failure		503		{object}	dto.ErrorResponse	"Statements are not configured"
+//	@Failure		500		{object}	dto.ErrorResponse
+//	@Router			/statements [post]
+func (h *AccountStatementHandler) RequestStatement(c *gin.Context) {
+	userID, exists := middleware.GetUserID(c)
+	if !exists {
+		c.Error(apperrors.ErrUnauthenticated)
+		return
+	}
+
+	var req dto.RequestAccountStatementRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.Error(apperrors.ErrValidation.Withf("invalid request data: %w", err)).SetMeta("request.invalid")
+		return
+	}
+
+	from, err := time.Parse("2006-01-02", req.From)
+	var to time.Time
+	if err == nil {
+		to, err = time.Parse("2006-01-02", req.To)
+	}
+	if err != nil {
+		c.Error(apperrors.ErrValidation.Withf("invalid date: %w", err)).SetMeta("request.invalid_date")
+		return
+	}
+	format := models.AccountStatementFormat(strings.ToUpper(req.Format))
+	if format == "" {
+		format = models.AccountStatementFormatCSV
+	}
+
+	statement, err := h.accountStatementUseCase.RequestStatement(c.Request.Context(), userID, from, to, format)
+	if err != nil {
+		c.Error(err).SetMeta("account_statement.request_failed")
+		return
+	}
+
+	c.JSON(http.StatusAccepted, dto.APIResponse{
+		Success: true,
+		Message: middleware.Translate(c, "account_statement.requested"),
+		Data:    dto.ToAccountStatementResponse(statement),
+	})
+}
+
+// ListStatements godoc
+//
+//	@Summary		List account statements
+//	@Description	List the statements the authenticated user asked for, newest first
+//	@Tags			statements
+//	@Accept			json
+//	@Produce		json
+//	@Security		BearerAuth
+//	@Param			page		query		int	false	"Page number"	default(1)
+//	@Param			page_size	query		int	false	"Page size"		default(20)
+//	@Success		200			{object}	dto.APIResponse{data=[]dto.AccountStatementResponse}
+//	@Failure		401			{object}	dto.ErrorResponse
+//	@Failure		500			{object}	dto.ErrorResponse
+//	@Router			/statements [get]
+func (h *AccountStatementHandler) ListStatements(c *gin.Context) {
+	userID, exists := middleware.GetUserID(c)
+	if !exists {
+		c.Error(apperrors.ErrUnauthenticated)
+		return
+	}
+
+	page, pageSize := parsePagination(c)
+	statements, err := h.accountStatementUseCase.ListStatements(c.Request.Context(), userID, page, pageSize)
+	if err != nil {
+		c.Error(err).SetMeta("account_statement.list_failed")
+		return
+	}
+
+	responses := make([]dto.AccountStatementResponse, len(statements))
+	for i := range statements {
+		responses[i] = dto.ToAccountStatementResponse(&statements[i])
+	}
+
+	c.JSON(http.StatusOK, dto.APIResponse{
+		Success: true,
+		Message: middleware.Translate(c, "account_statement.list_retrieved"),
+		Data:    responses,
+	})
+}
+
+// GetStatement godoc
+//
+//	@Summary		Get an account statement
+//	@Description	Get the status of a requested statement; once READY it carries a signed download link that expires after a few minutes, so fetch the statement again for a fresh one
+//	@Tags			statements
+//	@Accept			json
+//	@Produce		json
+//	@Security		BearerAuth
+//	@Param			id	path		int	true	"Statement ID"
+//	@Success		200	{object}	dto.APIResponse{data=dto.AccountStatementResponse}
+//	@Failure		400	{object}	dto.ErrorResponse
+//	@Failure		401	{object}	dto.ErrorResponse
+//	@Failure		404	{object}	dto.ErrorResponse
+//	@Failure		500	{object}	dto.ErrorResponse
+//	@Router			/statements/{id} [get]
+func (h *AccountStatementHandler) GetStatement(c *gin.Context) {
+	userID, exists := middleware.GetUserID(c)
+	if !exists {
+		c.Error(apperrors.ErrUnauthenticated)
+		return
+	}
+
+	statementID, err := parseIDParam(c, "id")
+	if err != nil {
+		c.Error(err).SetMeta("account_statement.invalid_id")
+		return
+	}
+
+	statement, download, err := h.accountStatementUseCase.GetStatement(c.Request.Context(), userID, statementID)
+	if err != nil {
+		c.Error(err).SetMeta("account_statement.retrieve_failed")
+		return
+	}
+
+	response := dto.ToAccountStatementResponse(statement)
+	if download != nil {
+		response.DownloadURL = download.URL
+		response.DownloadExpiresAt = &download.ExpiresAt
+	}
+
+	c.JSON(http.StatusOK, dto.APIResponse{
+		Success: true,
+		Message: middleware.Translate(c, "account_statement.retrieved"),
+		Data:    response,
+	})
+}
+
+// Download godoc
+//
+//	@Summary		Download a file
+//	@Description	Download a rendered statement through a signed link from GET /statements/{id}. The link itself authorizes the download, so no token is needed.
+//	@Tags			statements
+//	@Produce		octet-stream
+//	@Param			key			query		string	true	"Stored file key"
+//	@Param			expires		query		int		true	"Link expiry as a Unix timestamp"
+//	@Param			signature	query		string	true	"Link signature"
+//	@Success		200			{file}		file
+//	@Failure		400			{object}	dto.ErrorResponse
+//	@Failure		403			{object}	dto.ErrorResponse	"Invalid or expired link"
+//	@Failure		404			{object}	dto.ErrorResponse
+//	@Failure		500			{object}	dto.ErrorResponse
+//	@Router			/downloads [get]
+func (h *AccountStatementHandler) Download(c *gin.Context) {
+	key := c.Query("key")
+	expires, err := strconv.ParseInt(c.Query("expires"), 10, 64)
+	if key == "" || err != nil {
+		c.Error(apperrors.ErrValidation.Withf("key and expires are required")).SetMeta("account_statement.invalid_download_link")
+		return
+	}
+
+	content, err := h.accountStatementUseCase.OpenDownload(c.Request.Context(), key, expires, c.Query("signature"))
+	if err != nil {
+		c.Error(err).SetMeta("account_statement.download_failed")
+		return
+	}
+
+	contentType := "text/csv; charset=utf-8"
+	if path.Ext(key) == ".pdf" {
+		contentType = "application/pdf"
+	}
+	c.Header("Content-Disposition", fmt.Sprintf(`attachment; filename="%s"`, path.Base(key)))
+	c.Header("Cache-Control", "private, no-store")
+	c.Data(http.StatusOK, contentType, content)
+}
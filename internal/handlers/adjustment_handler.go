@@ -0,0 +1,237 @@
+package handlers
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/limistah/wallet-service/internal/apperrors"
+	"github.com/limistah/wallet-service/internal/dto"
+	"github.com/limistah/wallet-service/internal/middleware"
+	"github.com/limistah/wallet-service/internal/models"
+	"github.com/limistah/wallet-service/internal/usecases"
+)
+
+type AdjustmentHandler struct {
+	adjustmentUseCase usecases.AdjustmentUseCase
+}
+
+func NewAdjustmentHandler(adjustmentUseCase usecases.AdjustmentUseCase) *AdjustmentHandler {
+	return &AdjustmentHandler{
+		adjustmentUseCase: adjustmentUseCase,
+	}
+}
+
+// RequestAdjustment godoc
+//
+//	@Summary		Request a balance adjustment
+//	@Description	Ask for a manual credit or debit of a wallet with the reason for it. Nothing is posted until another admin approves it (admin only)
+//	@Tags			admin
+//	@Accept			json
+//	@Produce		json
+//	@Security		BearerAuth
+//	@Param			request	body		dto.BalanceAdjustmentRequest	true	"Adjustment"
+//	@Success		201		{object}	dto.APIResponse{data=dto.BalanceAdjustmentResponse}
+//	@Failure		400		{object}	dto.ErrorResponse
+//	@Failure		401		{object}	dto.ErrorResponse
+//	@Failure		403		{object}	dto.ErrorResponse
+//	@Failure		404		{object}	dto.ErrorResponse	"Wallet not found"
+//	@Failure		500		{object}	dto.ErrorResponse
+//	@Router			/admin/adjustments [post]
+func (h *AdjustmentHandler) RequestAdjustment(c *gin.Context) {
+	adminID, exists := middleware.GetUserID(c)
+	if !exists {
+		c.Error(apperrors.ErrUnauthenticated)
+		return
+	}
+
+	var req dto.BalanceAdjustmentRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.Error(apperrors.ErrValidation.Withf("invalid request data: %w", err)).SetMeta("request.invalid")
+		return
+	}
+
+	adjustment, err := h.adjustmentUseCase.RequestAdjustment(c.Request.Context(), usecases.BalanceAdjustmentInput{
+		WalletID:    req.WalletID,
+		Type:        models.TransactionType(req.Type),
+		Amount:      req.Amount,
+		Reason:      req.Reason,
+		RequestedBy: adminID,
+	})
+	if err != nil {
+		c.Error(err).SetMeta("adjustment.request_failed")
+		return
+	}
+
+	c.JSON(http.StatusCreated, dto.APIResponse{
+		Success: true,
+		Message: middleware.Translate(c, "adjustment.requested"),
+		Data:    dto.ToBalanceAdjustmentResponse(adjustment),
+	})
+}
+
+// ListAdjustments godoc
+//
+//	@Summary		List balance adjustments
+//	@Description	List manual wallet adjustments, oldest first (admin and support)
+//	@Tags			admin
+//	@Accept			json
+//	@Produce		json
+//	@Security		BearerAuth
+//	@Param			status		query		string	false	"Adjustment status (PENDING, APPROVED, REJECTED); omit for every adjustment"
+//	@Param			page		query		int		false	"Page number"	default(1)
+//	@Param			page_size	query		int		false	"Page size"		default(20)
+//	@Success		200			{object}	dto.APIResponse{data=[]dto.BalanceAdjustmentResponse}
+//	@Failure		400			{object}	dto.ErrorResponse
+//	@Failure		401			{object}	dto.ErrorResponse
+//	@Failure		403			{object}	dto.ErrorResponse
+//	@Failure		500			{object}	dto.ErrorResponse
+//	@Router			/admin/adjustments [get]
+func (h *AdjustmentHandler) ListAdjustments(c *gin.Context) {
+	status := models.BalanceAdjustmentStatus(strings.ToUpper(c.Query("status")))
+	switch status {
+	case "", models.BalanceAdjustmentStatusPending, models.BalanceAdjustmentStatusApproved, models.BalanceAdjustmentStatusRejected:
+	default:
+		c.Error(apperrors.ErrValidation.Withf("invalid status")).SetMeta("adjustment.invalid_status")
+		return
+	}
+
+	page, pageSize := parsePagination(c)
+	adjustments, err := h.adjustmentUseCase.ListAdjustments(c.Request.Context(), status, page, pageSize)
+	if err != nil {
+		c.Error(err).SetMeta("adjustment.list_failed")
+		return
+	}
+
+	responses := make([]dto.BalanceAdjustmentResponse, len(adjustments))
+	for i := range adjustments {
+		responses[i] = dto.ToBalanceAdjustmentResponse(&adjustments[i])
+	}
+
+	c.JSON(http.StatusOK, dto.APIResponse{
+		Success: true,
+		Message: middleware.Translate(c, "adjustment.listed"),
+		Data:    responses,
+	})
+}
+
+// GetAdjustment godoc
+//
+//	@Summary		Get a balance adjustment
+//	@Description	Get a manual wallet adjustment with its approval (admin and support)
+//	@Tags			admin
+//	@Accept			json
+//	@Produce		json
+//	@Security		BearerAuth
+//	@Param			id	path		int	true	"Adjustment ID"
+//	@Success		200	{object}	dto.APIResponse{data=dto.BalanceAdjustmentResponse}
+//	@Failure		400	{object}	dto.ErrorResponse
+//	@Failure		401	{object}	dto.ErrorResponse
+//	@Failure		403	{object}	dto.ErrorResponse
+//	@Failure		404	{object}	dto.ErrorResponse
+//	@Failure		500	{object}	dto.ErrorResponse
+//	@Router			/admin/adjustments/{id} [get]
+func (h *AdjustmentHandler) GetAdjustment(c *gin.Context) {
+	adjustmentID, err := parseIDParam(c, "id")
+	if err != nil {
+		c.Error(err).SetMeta("adjustment.invalid_id")
+		return
+	}
+
+	adjustment, err := h.adjustmentUseCase.GetAdjustment(c.Request.Context(), adjustmentID)
+	h.respond(c, adjustment, err, "adjustment.retrieve_failed", "adjustment.retrieved")
+}
+
+// ApproveAdjustment godoc
+//
+//	@Summary		Approve a balance adjustment
+//	@Description	Post a pending adjustment requested by another admin to the wallet, against the adjustments ledger account (admin only)
+//	@Tags			admin
+//	@Accept			json
+//	@Produce		json
+//	@Security		BearerAuth
+//	@Param			id		path		int								true	"Adjustment ID"
+//	@Param			request	body		dto.AdjustmentDecisionRequest	false	"Approval note"
+//	@Success		200		{object}	dto.APIResponse{data=dto.BalanceAdjustmentResponse}
+//	@Failure		400		{object}	dto.ErrorResponse	"Insufficient funds in the wallet or the adjustments account"
+//	@Failure		401		{object}	dto.ErrorResponse
+//	@Failure		403		{object}	dto.ErrorResponse	"The admin's own adjustment"
+//	@Failure		404		{object}	dto.ErrorResponse
+//	@Failure		409		{object}	dto.ErrorResponse	"Adjustment already decided"
+//	@Failure		500		{object}	dto.ErrorResponse
+//	@Router			/admin/adjustments/{id}/approve [post]
+func (h *AdjustmentHandler) ApproveAdjustment(c *gin.Context) {
+	adminID, adjustmentID, note, ok := h.decision(c)
+	if !ok {
+		return
+	}
+
+	adjustment, err := h.adjustmentUseCase.ApproveAdjustment(c.Request.Context(), adjustmentID, adminID, note)
+	h.respond(c, adjustment, err, "adjustment.approve_failed", "adjustment.approved")
+}
+
+// RejectAdjustment godoc
+//
+//	@Summary		Reject a balance adjustment
+//	@Description	Decline a pending adjustment requested by another admin; nothing is posted (admin only)
+//	@Tags			admin
+//	@Accept			json
+//	@Produce		json
+//	@Security		BearerAuth
+//	@Param			id		path		int								true	"Adjustment ID"
+//	@Param			request	body		dto.AdjustmentDecisionRequest	false	"Rejection note"
+//	@Success		200		{object}	dto.APIResponse{data=dto.BalanceAdjustmentResponse}
+//	@Failure		400		{object}	dto.ErrorResponse
+//	@Failure		401		{object}	dto.ErrorResponse
+//	@Failure		403		{object}	dto.ErrorResponse	"The admin's own adjustment"
+//	@Failure		404		{object}	dto.ErrorResponse
+//	@Failure		409		{object}	dto.ErrorResponse	"Adjustment already decided"
+//	@Failure		500		{object}	dto.ErrorResponse
+//	@Router			/admin/adjustments/{id}/reject [post]
+func (h *AdjustmentHandler) RejectAdjustment(c *gin.Context) {
+	adminID, adjustmentID, note, ok := h.decision(c)
+	if !ok {
+		return
+	}
+
+	adjustment, err := h.adjustmentUseCase.RejectAdjustment(c.Request.Context(), adjustmentID, adminID, note)
+	h.respond(c, adjustment, err, "adjustment.reject_failed", "adjustment.rejected")
+}
+
+// decision reads the signed in admin, the adjustment ID and the optional note of an approval or
+// rejection, writing the error response when one is invalid
+func (h *AdjustmentHandler) decision(c *gin.Context) (uint, uint, string, bool) {
+	adminID, exists := middleware.GetUserID(c)
+	if !exists {
+		c.Error(apperrors.ErrUnauthenticated)
+		return 0, 0, "", false
+	}
+
+	adjustmentID, err := parseIDParam(c, "id")
+	if err != nil {
+		c.Error(err).SetMeta("adjustment.invalid_id")
+		return 0, 0, "", false
+	}
+
+	var req dto.AdjustmentDecisionRequest
+	if err := c.ShouldBindJSON(&req); err != nil && !errors.Is(err, io.EOF) {
+		c.Error(apperrors.ErrValidation.Withf("invalid request data: %w", err)).SetMeta("request.invalid")
+		return 0, 0, "", false
+	}
+	return adminID, adjustmentID, strings.TrimSpace(req.Note), true
+}
+
+func (h *AdjustmentHandler) respond(c *gin.Context, adjustment *models.BalanceAdjustment, err error, failureKey, successKey string) {
+	if err != nil {
+		c.Error(err).SetMeta(failureKey)
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.APIResponse{
+		Success: true,
+		Message: middleware.Translate(c, successKey),
+		Data:    dto.ToBalanceAdjustmentResponse(adjustment),
+	})
+}
@@ -0,0 +1,843 @@
+package handlers
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/limistah/wallet-service/internal/apperrors"
+	"github.com/limistah/wallet-service/internal/dto"
+	"github.com/limistah/wallet-service/internal/middleware"
+	"github.com/limistah/wallet-service/internal/models"
+	"github.com/limistah/wallet-service/internal/repositories"
+	"github.com/limistah/wallet-service/internal/usecases"
+	"github.com/shopspring/decimal"
+	"gorm.io/gorm"
+)
+
+type AdminHandler struct {
+	walletUseCase         usecases.WalletUseCase
+	reconciliationUseCase usecases.ReconciliationUseCase
+	auditUseCase          usecases.AuditUseCase
+}
+
+func NewAdminHandler(walletUseCase usecases.WalletUseCase, reconciliationUseCase usecases.ReconciliationUseCase, auditUseCase usecases.AuditUseCase) *AdminHandler {
+	return &AdminHandler{
+		walletUseCase:         walletUseCase,
+		reconciliationUseCase: reconciliationUseCase,
+		auditUseCase:          auditUseCase,
+	}
+}
+
+// parsePagination reads page and page_size query parameters with sane defaults
+func parsePagination(c *gin.Context) (int, int) {
+	page := 1
+	if p := c.Query("page"); p != "" {
+		if parsed, err := strconv.Atoi(p); err == nil && parsed > 0 {
+			page = parsed
+		}
+	}
+
+	pageSize := 20
+	if ps := c.Query("page_size"); ps != "" {
+		if parsed, err := strconv.Atoi(ps); err == nil && parsed > 0 && parsed <= 100 {
+			pageSize = parsed
+		}
+	}
+
+	return page, pageSize
+}
+
+// parseIDParam reads a numeric path parameter
+func parseIDParam(c *gin.Context, name string) (uint, error) {
+	id, err := strconv.ParseUint(c.Param(name), 10, 64)
+	if err != nil || id == 0 {
+		return 0, apperrors.ErrValidation.Withf("invalid %s", name)
+	}
+	return uint(id), nil
+}
+
+// ListWallets godoc
+//
+//	@Summary		List wallets
+//	@Description	List all wallets with optional filters (admin and support)
+//	@Tags			admin
+//	@Accept			json
+//	@Produce		json
+//	@Security		BearerAuth
+//	@Param			status		query		string	false	"Wallet status (ACTIVE, SUSPENDED, CLOSED)"
+//	@Param			currency	query		string	false	"Currency code"
+//	@Param			min_balance	query		string	false	"Minimum balance"
+//	@Param			max_balance	query		string	false	"Maximum balance"
+//	@Param			email		query		string	false	"Owner email (partial match)"
+//	@Param			page		query		int		false	"Page number"	default(1)
+//	@Param			page_size	query		int		false	"Page size"		default(20)
+//	@Success		200			{object}	dto.APIResponse{data=dto.AdminWalletListResponse}
+//	@Failure		400			{object}	dto.ErrorResponse
+//	@Failure		401			{object}	dto.ErrorResponse
+//	@Failure		403			{object}	dto.ErrorResponse
+//	@Failure		500			{object}	dto.ErrorResponse
+//	@Router			/admin/wallets [get]
+func (h *AdminHandler) ListWallets(c *gin.Context) {
+	filter := repositories.WalletFilter{
+		Status:    models.WalletStatus(strings.ToUpper(c.Query("status"))),
+		Currency:  strings.ToUpper(c.Query("currency")),
+		UserEmail: strings.TrimSpace(c.Query("email")),
+	}
+
+	switch filter.Status {
+	case "", models.WalletStatusActive, models.WalletStatusSuspended, models.WalletStatusClosed:
+	default:
+		c.Error(apperrors.ErrValidation.Withf("invalid status")).SetMeta("admin.invalid_wallet_status")
+		return
+	}
+
+	for param, target := range map[string]**decimal.Decimal{
+		"min_balance": &filter.MinBalance,
+		"max_balance": &filter.MaxBalance,
+	} {
+		value := c.Query(param)
+		if value == "" {
+			continue
+		}
+		parsed, err := decimal.NewFromString(value)
+		if err != nil {
+			c.Error(apperrors.ErrValidation.Withf("invalid %s: %w", param, err)).SetMeta("admin.invalid_balance")
+			return
+		}
+		*target = &parsed
+	}
+
+	page, pageSize := parsePagination(c)
+
+	wallets, total, err := h.walletUseCase.ListWallets(c.Request.Context(), filter, page, pageSize)
+	if err != nil {
+		c.Error(err).SetMeta("admin.wallets_list_failed")
+		return
+	}
+
+	walletResponses := make([]dto.AdminWalletResponse, len(wallets))
+	for i, wallet := range wallets {
+		walletResponses[i] = dto.ToAdminWalletResponse(&wallet)
+	}
+
+	totalPages := int((total + int64(pageSize) - 1) / int64(pageSize))
+
+	c.JSON(http.StatusOK, dto.APIResponse{
+		Success: true,
+		Message: middleware.Translate(c, "admin.wallets_retrieved"),
+		Data: dto.AdminWalletListResponse{
+			Wallets: walletResponses,
+			Pagination: dto.PaginationMeta{
+				Page:      page,
+				PageSize:  pageSize,
+				Total:     int(total),
+				TotalPage: totalPages,
+			},
+		},
+	})
+}
+
+// GetWallet godoc
+//
+//	@Summary		Get wallet detail
+//	@Description	Retrieve any wallet with its owner and reconciliation history (admin and support)
+//	@Tags			admin
+//	@Accept			json
+//	@Produce		json
+//	@Security		BearerAuth
+//	@Param			id	path		int	true	"Wallet ID"
+//	@Success		200	{object}	dto.APIResponse{data=dto.AdminWalletDetailResponse}
+//	@Failure		400	{object}	dto.ErrorResponse
+//	@Failure		401	{object}	dto.ErrorResponse
+//	@Failure		403	{object}	dto.ErrorResponse
+//	@Failure		404	{object}	dto.ErrorResponse
+//	@Failure		500	{object}	dto.ErrorResponse
+//	@Router			/admin/wallets/{id} [get]
+func (h *AdminHandler) GetWallet(c *gin.Context) {
+	walletID, err := parseIDParam(c, "id")
+	if err != nil {
+		c.Error(err).SetMeta("admin.invalid_wallet_id")
+		return
+	}
+
+	wallet, err := h.walletUseCase.GetWallet(c.Request.Context(), walletID)
+	if err != nil {
+		h.respondWalletLookupError(c, err)
+		return
+	}
+
+	reports, err := h.reconciliationUseCase.GetWalletReconciliationReports(c.Request.Context(), walletID)
+	if err != nil {
+		c.Error(err).SetMeta("admin.reconciliation_history_retrieve_failed")
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.APIResponse{
+		Success: true,
+		Message: middleware.Translate(c, "wallet.retrieved"),
+		Data: dto.AdminWalletDetailResponse{
+			Wallet:                dto.ToAdminWalletResponse(wallet),
+			ReconciliationReports: toReconciliationReportResponses(reports),
+			TransactionsURL:       fmt.Sprintf("/api/v1/admin/wallets/%d/transactions", wallet.ID),
+		},
+	})
+}
+
+// GetWalletReconciliationReports godoc
+//
+//	@Summary		Get wallet reconciliation history
+//	@Description	Retrieve the reconciliation reports recorded for any wallet (admin and support)
+//	@Tags			admin
+//	@Accept			json
+//	@Produce		json
+//	@Security		BearerAuth
+//	@Param			id	path		int	true	"Wallet ID"
+//	@Success		200	{object}	dto.APIResponse{data=[]dto.ReconciliationReportResponse}
+//	@Failure		400	{object}	dto.ErrorResponse
+//	@Failure		401	{object}	dto.ErrorResponse
+//	@Failure		403	{object}	dto.ErrorResponse
+//	@Failure		404	{object}	dto.ErrorResponse
+//	@Failure		500	{object}	dto.ErrorResponse
+//	@Router			/admin/wallets/{id}/reconciliation [get]
+func (h *AdminHandler) GetWalletReconciliationReports(c *gin.Context) {
+	walletID, err := parseIDParam(c, "id")
+	if err != nil {
+		c.Error(err).SetMeta("admin.invalid_wallet_id")
+		return
+	}
+
+	reports, err := h.reconciliationUseCase.GetWalletReconciliationReports(c.Request.Context(), walletID)
+	if err != nil {
+		h.respondWalletLookupError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.APIResponse{
+		Success: true,
+		Message: middleware.Translate(c, "admin.reconciliation_reports_retrieved"),
+		Data:    toReconciliationReportResponses(reports),
+	})
+}
+
+// ExportReconciliationReports godoc
+//
+//	@Summary		Export reconciliation reports
+//	@Description	Stream reconciliation reports as CSV, oldest first, with the wallet, stored and calculated balances and their difference (admin and support). Dates are RFC 3339 times or YYYY-MM-DD days; a to day is included
+//	@Tags			admin
+//	@Produce		text/csv
+//	@Security		BearerAuth
+//	@Param			status	query		string	false	"Report status (MATCH, MISMATCH, DOUBLE_ENTRY_ERROR)"
+//	@Param			from	query		string	false	"Earliest report time"
+//	@Param			to		query		string	false	"Latest report time"
+//	@Success		200		{file}		file
+//	@Failure		400		{object}	dto.ErrorResponse
+//	@Failure		401		{object}	dto.ErrorResponse
+//	@Failure		403		{object}	dto.ErrorResponse
+//	@Failure		500		{object}	dto.ErrorResponse
+//	@Router			/admin/reconciliation/reports/export [get]
+func (h *AdminHandler) ExportReconciliationReports(c *gin.Context) {
+	filter := repositories.ReconciliationReportFilter{
+		Status: models.ReconciliationStatus(strings.ToUpper(c.Query("status"))),
+	}
+
+	switch filter.Status {
+	case "", models.ReconciliationStatusMatch, models.ReconciliationStatusMismatch, models.ReconciliationStatusDoubleEntryError:
+	default:
+		c.Error(apperrors.ErrValidation.Withf("invalid status")).SetMeta("admin.invalid_reconciliation_status")
+		return
+	}
+
+	var err error
+	if filter.From, err = parseTimeQuery(c, "from", false); err == nil {
+		filter.To, err = parseTimeQuery(c, "to", true)
+	}
+	if err != nil {
+		c.Error(err).SetMeta("request.invalid_date_param")
+		return
+	}
+
+	// The CSV starts with the first batch, so a failure before it can still be answered with JSON
+	writer := csv.NewWriter(c.Writer)
+	started := false
+	start := func() error {
+		started = true
+		c.Header("Content-Type", "text/csv; charset=utf-8")
+		c.Header("Content-Disposition", fmt.Sprintf(`attachment; filename="reconciliation-reports-%s.csv"`, time.Now().UTC().Format("20060102")))
+		c.Status(http.StatusOK)
+		return writer.Write(dto.ReconciliationReportCSVHeader)
+	}
+
+	err = h.reconciliationUseCase.ExportReports(c.Request.Context(), filter, func(reports []models.ReconciliationReport) error {
+		if !started {
+			if err := start(); err != nil {
+				return err
+			}
+		}
+		for _, report := range reports {
+			if err := writer.Write(dto.ToReconciliationReportCSVRow(&report)); err != nil {
+				return err
+			}
+		}
+		writer.Flush()
+		return writer.Error()
+	})
+	if err == nil && !started {
+		// No report matched; the file only has its header
+		err = start()
+		writer.Flush()
+	}
+	if err != nil && started {
+		// The status is sent already; the truncated file is all the client gets
+		log.Printf("Failed to export reconciliation reports: %v", err)
+		return
+	}
+	if err != nil {
+		c.Error(err).SetMeta("admin.reconciliation_reports_export_failed")
+	}
+}
+
+// ListReconciliationSummaries godoc
+//
+//	@Summary		List daily reconciliation summaries
+//	@Description	List the stored daily summaries of reconciliation reports, newest day first
+//	@Tags			admin
+//	@Produce		json
+//	@Security		BearerAuth
+//	@Param			page		query		int	false	"Page number"	default(1)
+//	@Param			page_size	query		int	false	"Page size"		default(20)
+//	@Success		200			{object}	dto.APIResponse{data=[]dto.ReconciliationSummaryResponse}
+//	@Header			200			{string}	Link	"RFC 5988 links to the first, previous and next pages"
+//	@Failure		401			{object}	dto.ErrorResponse
+//	@Failure		403			{object}	dto.ErrorResponse
+//	@Failure		500			{object}	dto.ErrorResponse
+//	@Router			/admin/reconciliation/summaries [get]
+func (h *AdminHandler) ListReconciliationSummaries(c *gin.Context) {
+	page, pageSize := parsePagination(c)
+
+	summaries, err := h.reconciliationUseCase.GetSummaries(c.Request.Context(), page, pageSize)
+	if err != nil {
+		c.Error(err).SetMeta("admin.reconciliation_summaries_retrieve_failed")
+		return
+	}
+
+	responses := make([]dto.ReconciliationSummaryResponse, len(summaries))
+	for i, summary := range summaries {
+		responses[i] = dto.ToReconciliationSummaryResponse(&summary)
+	}
+
+	setPageLinks(c, page, len(summaries) == pageSize)
+
+	c.JSON(http.StatusOK, dto.APIResponse{
+		Success: true,
+		Message: middleware.Translate(c, "admin.reconciliation_summaries_retrieved"),
+		Data:    responses,
+	})
+}
+
+// StartReconciliationRun godoc
+//
+//	@Summary		Start a reconciliation run
+//	@Description	Reconcile every wallet, or the cohort of a scope such as the wallets of a currency or those that changed during an incident, in background batches (admin only). The run is returned straight away; poll GET /admin/reconciliation/runs/{id} for its progress
+//	@Tags			admin
+//	@Accept			json
+//	@Produce		json
+//	@Security		BearerAuth
+//	@Param			request	body		dto.StartReconciliationRunRequest	false	"Wallets to reconcile; every wallet when empty"
+//	@Success		202		{object}	dto.APIResponse{data=dto.ReconciliationRunResponse}
+//	@Failure		400		{object}	dto.ErrorResponse
+//	@Failure		401		{object}	dto.ErrorResponse
+//	@Failure		403		{object}	dto.ErrorResponse
+//	@Failure		500		{object}	dto.ErrorResponse
+//	@Router			/admin/reconciliation/runs [post]
+func (h *AdminHandler) StartReconciliationRun(c *gin.Context) {
+	userID, exists := middleware.GetUserID(c)
+	if !exists {
+		c.Error(apperrors.ErrUnauthenticated)
+		return
+	}
+
+	var req dto.StartReconciliationRunRequest
+	if c.Request.ContentLength != 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.Error(apperrors.ErrValidation.Withf("invalid request data: %w", err)).SetMeta("request.invalid")
+			return
+		}
+	}
+	scope := models.ReconciliationScope{
+		Currency:   req.Currency,
+		Status:     models.WalletStatus(req.Status),
+		ActiveFrom: req.ActiveFrom,
+		ActiveTo:   req.ActiveTo,
+		WalletIDs:  req.WalletIDs,
+	}
+
+	run, err := h.reconciliationUseCase.StartRun(c.Request.Context(), userID, scope)
+	if err != nil {
+		c.Error(err).SetMeta("admin.reconciliation_run_start_failed")
+		return
+	}
+
+	c.JSON(http.StatusAccepted, dto.APIResponse{
+		Success: true,
+		Message: middleware.Translate(c, "admin.reconciliation_run_started"),
+		Data:    dto.ToReconciliationRunResponse(run),
+	})
+}
+
+// GetReconciliationRun godoc
+//
+//	@Summary		Get a reconciliation run
+//	@Description	Get the status of a reconciliation run with the wallets processed so far, the match, mismatch and double-entry counts and the wallets that could not be reconciled
+//	@Tags			admin
+//	@Produce		json
+//	@Security		BearerAuth
+//	@Param			id	path		int	true	"Run ID"
+//	@Success		200	{object}	dto.APIResponse{data=dto.ReconciliationRunResponse}
+//	@Failure		400	{object}	dto.ErrorResponse
+//	@Failure		401	{object}	dto.ErrorResponse
+//	@Failure		403	{object}	dto.ErrorResponse
+//	@Failure		404	{object}	dto.ErrorResponse
+//	@Failure		500	{object}	dto.ErrorResponse
+//	@Router			/admin/reconciliation/runs/{id} [get]
+func (h *AdminHandler) GetReconciliationRun(c *gin.Context) {
+	runID, err := parseIDParam(c, "id")
+	if err != nil {
+		c.Error(err).SetMeta("admin.invalid_reconciliation_run_id")
+		return
+	}
+
+	run, err := h.reconciliationUseCase.GetRun(c.Request.Context(), runID)
+	if err != nil {
+		c.Error(err).SetMeta("admin.reconciliation_run_retrieve_failed")
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.APIResponse{
+		Success: true,
+		Message: middleware.Translate(c, "admin.reconciliation_run_retrieved"),
+		Data:    dto.ToReconciliationRunResponse(run),
+	})
+}
+
+// AcknowledgeReconciliationReport godoc
+//
+//	@Summary		Acknowledge a reconciliation report
+//	@Description	Record that an operator has picked up the mismatch or double-entry error of a report, with an optional first note (admin and support)
+//	@Tags			admin
+//	@Accept			json
+//	@Produce		json
+//	@Security		BearerAuth
+//	@Param			id		path		int									true	"Report ID"
+//	@Param			request	body		dto.ReconciliationReportNoteRequest	false	"Investigation note"
+//	@Success		200		{object}	dto.APIResponse{data=dto.ReconciliationReportResponse}
+//	@Failure		400		{object}	dto.ErrorResponse
+//	@Failure		401		{object}	dto.ErrorResponse
+//	@Failure		403		{object}	dto.ErrorResponse
+//	@Failure		404		{object}	dto.ErrorResponse
+//	@Failure		409		{object}	dto.ErrorResponse	"Report already resolved or has no issue"
+//	@Failure		500		{object}	dto.ErrorResponse
+//	@Router			/admin/reconciliation/reports/{id}/acknowledge [post]
+func (h *AdminHandler) AcknowledgeReconciliationReport(c *gin.Context) {
+	operatorID, reportID, ok := h.actorAndReport(c)
+	if !ok {
+		return
+	}
+
+	var req dto.ReconciliationReportNoteRequest
+	if err := c.ShouldBindJSON(&req); err != nil && !errors.Is(err, io.EOF) {
+		c.Error(apperrors.ErrValidation.Withf("invalid request data: %w", err)).SetMeta("request.invalid")
+		return
+	}
+
+	report, err := h.reconciliationUseCase.AcknowledgeReport(c.Request.Context(), reportID, operatorID, strings.TrimSpace(req.Note))
+	h.respondReport(c, report, err, "admin.reconciliation_report_acknowledge_failed", "admin.reconciliation_report_acknowledged")
+}
+
+// AssignReconciliationReport godoc
+//
+//	@Summary		Assign a reconciliation report
+//	@Description	Hand the issue of a reconciliation report to an admin or support operator (admin only)
+//	@Tags			admin
+//	@Accept			json
+//	@Produce		json
+//	@Security		BearerAuth
+//	@Param			id		path		int										true	"Report ID"
+//	@Param			request	body		dto.AssignReconciliationReportRequest	true	"Operator to assign"
+//	@Success		200		{object}	dto.APIResponse{data=dto.ReconciliationReportResponse}
+//	@Failure		400		{object}	dto.ErrorResponse
+//	@Failure		401		{object}	dto.ErrorResponse
+//	@Failure		403		{object}	dto.ErrorResponse
+//	@Failure		404		{object}	dto.ErrorResponse
+//	@Failure		409		{object}	dto.ErrorResponse	"Report already resolved or has no issue"
+//	@Failure		500		{object}	dto.ErrorResponse
+//	@Router			/admin/reconciliation/reports/{id}/assign [post]
+func (h *AdminHandler) AssignReconciliationReport(c *gin.Context) {
+	operatorID, reportID, ok := h.actorAndReport(c)
+	if !ok {
+		return
+	}
+
+	var req dto.AssignReconciliationReportRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.Error(apperrors.ErrValidation.Withf("invalid request data: %w", err)).SetMeta("request.invalid")
+		return
+	}
+
+	report, err := h.reconciliationUseCase.AssignReport(c.Request.Context(), reportID, req.AssigneeID, operatorID)
+	h.respondReport(c, report, err, "admin.reconciliation_report_assign_failed", "admin.reconciliation_report_assigned")
+}
+
+// AddReconciliationReportNote godoc
+//
+//	@Summary		Record a reconciliation investigation note
+//	@Description	Add a finding to the investigation log of an unresolved reconciliation report (admin and support)
+//	@Tags			admin
+//	@Accept			json
+//	@Produce		json
+//	@Security		BearerAuth
+//	@Param			id		path		int									true	"Report ID"
+//	@Param			request	body		dto.ReconciliationReportNoteRequest	true	"Investigation note"
+//	@Success		200		{object}	dto.APIResponse{data=dto.ReconciliationReportResponse}
+//	@Failure		400		{object}	dto.ErrorResponse
+//	@Failure		401		{object}	dto.ErrorResponse
+//	@Failure		403		{object}	dto.ErrorResponse
+//	@Failure		404		{object}	dto.ErrorResponse
+//	@Failure		409		{object}	dto.ErrorResponse	"Report already resolved or has no issue"
+//	@Failure		500		{object}	dto.ErrorResponse
+//	@Router			/admin/reconciliation/reports/{id}/notes [post]
+func (h *AdminHandler) AddReconciliationReportNote(c *gin.Context) {
+	operatorID, reportID, ok := h.actorAndReport(c)
+	if !ok {
+		return
+	}
+
+	var req dto.ReconciliationReportNoteRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.Error(apperrors.ErrValidation.Withf("invalid request data: %w", err)).SetMeta("request.invalid")
+		return
+	}
+
+	report, err := h.reconciliationUseCase.AddReportNote(c.Request.Context(), reportID, operatorID, strings.TrimSpace(req.Note))
+	h.respondReport(c, report, err, "admin.reconciliation_report_note_failed", "admin.reconciliation_report_noted")
+}
+
+// ResolveReconciliationReport godoc
+//
+//	@Summary		Resolve a reconciliation report
+//	@Description	Close the issue of a reconciliation report as ADJUSTED (the balance was corrected), FALSE_POSITIVE (there was no issue) or DATA_FIX (the records were repaired) (admin only)
+//	@Tags			admin
+//	@Accept			json
+//	@Produce		json
+//	@Security		BearerAuth
+//	@Param			id		path		int										true	"Report ID"
+//	@Param			request	body		dto.ResolveReconciliationReportRequest	true	"Resolution"
+//	@Success		200		{object}	dto.APIResponse{data=dto.ReconciliationReportResponse}
+//	@Failure		400		{object}	dto.ErrorResponse
+//	@Failure		401		{object}	dto.ErrorResponse
+//	@Failure		403		{object}	dto.ErrorResponse
+//	@Failure		404		{object}	dto.ErrorResponse
+//	@Failure		409		{object}	dto.ErrorResponse	"Report already resolved or has no issue"
+//	@Failure		500		{object}	dto.ErrorResponse
+//	@Router			/admin/reconciliation/reports/{id}/resolve [post]
+func (h *AdminHandler) ResolveReconciliationReport(c *gin.Context) {
+	operatorID, reportID, ok := h.actorAndReport(c)
+	if !ok {
+		return
+	}
+
+	var req dto.ResolveReconciliationReportRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.Error(apperrors.ErrValidation.Withf("invalid request data: %w", err)).SetMeta("request.invalid")
+		return
+	}
+
+	resolution := models.ReconciliationResolutionType(req.ResolutionType)
+	report, err := h.reconciliationUseCase.ResolveReport(c.Request.Context(), reportID, operatorID, resolution, strings.TrimSpace(req.Note))
+	h.respondReport(c, report, err, "admin.reconciliation_report_resolve_failed", "admin.reconciliation_report_resolved")
+}
+
+// actorAndReport reads the signed in operator and the report ID, writing the error response when
+// either is missing
+func (h *AdminHandler) actorAndReport(c *gin.Context) (uint, uint, bool) {
+	operatorID, exists := middleware.GetUserID(c)
+	if !exists {
+		c.Error(apperrors.ErrUnauthenticated)
+		return 0, 0, false
+	}
+
+	reportID, err := parseIDParam(c, "id")
+	if err != nil {
+		c.Error(err).SetMeta("admin.invalid_reconciliation_report_id")
+		return 0, 0, false
+	}
+	return operatorID, reportID, true
+}
+
+func (h *AdminHandler) respondReport(c *gin.Context, report *models.ReconciliationReport, err error, failureKey, successKey string) {
+	if err != nil {
+		c.Error(err).SetMeta(failureKey)
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.APIResponse{
+		Success: true,
+		Message: middleware.Translate(c, successKey),
+		Data:    dto.ToReconciliationReportResponse(report),
+	})
+}
+
+// ListTransactionTypes godoc
+//
+//	@Summary		List transaction types
+//	@Description	List the transaction types transactions can be recorded with
+//	@Tags			admin
+//	@Produce		json
+//	@Security		BearerAuth
+//	@Success		200	{object}	dto.APIResponse{data=[]dto.TransactionTypeResponse}
+//	@Failure		401	{object}	dto.ErrorResponse
+//	@Failure		403	{object}	dto.ErrorResponse
+//	@Failure		500	{object}	dto.ErrorResponse
+//	@Router			/admin/transaction-types [get]
+func (h *AdminHandler) ListTransactionTypes(c *gin.Context) {
+	definitions, err := h.walletUseCase.ListTransactionTypes(c.Request.Context())
+	if err != nil {
+		c.Error(err).SetMeta("admin.transaction_types_retrieve_failed")
+		return
+	}
+
+	responses := make([]dto.TransactionTypeResponse, len(definitions))
+	for i, definition := range definitions {
+		responses[i] = dto.ToTransactionTypeResponse(&definition)
+	}
+
+	c.JSON(http.StatusOK, dto.APIResponse{
+		Success: true,
+		Message: middleware.Translate(c, "admin.transaction_types_retrieved"),
+		Data:    responses,
+	})
+}
+
+// GetWalletTransactions godoc
+//
+//	@Summary		Get wallet ledger
+//	@Description	Retrieve cursor-paginated transactions for any wallet, including the related double-entry leg (admin and support)
+//	@Tags			admin
+//	@Accept			json
+//	@Produce		json
+//	@Security		BearerAuth
+//	@Param			id		path		int		true	"Wallet ID"
+//	@Param			cursor	query		string	false	"Cursor for pagination"
+//	@Param			limit	query		int		false	"Page size"	default(20)
+//	@Success		200		{object}	dto.APIResponse{data=dto.AdminTransactionHistoryResponse}
+//	@Header			200		{string}	Link	"RFC 5988 links to the next page, and to the first once paged"
+//	@Failure		400		{object}	dto.ErrorResponse
+//	@Failure		401		{object}	dto.ErrorResponse
+//	@Failure		403		{object}	dto.ErrorResponse
+//	@Failure		404		{object}	dto.ErrorResponse
+//	@Failure		500		{object}	dto.ErrorResponse
+//	@Router			/admin/wallets/{id}/transactions [get]
+func (h *AdminHandler) GetWalletTransactions(c *gin.Context) {
+	walletID, err := parseIDParam(c, "id")
+	if err != nil {
+		c.Error(err).SetMeta("admin.invalid_wallet_id")
+		return
+	}
+
+	limit := 20
+	if l := c.Query("limit"); l != "" {
+		if parsed, err := strconv.Atoi(l); err == nil && parsed > 0 && parsed <= 100 {
+			limit = parsed
+		}
+	}
+
+	var cursorPtr *string
+	if cursor := c.Query("cursor"); cursor != "" {
+		cursorPtr = &cursor
+	}
+
+	transactions, nextCursor, err := h.walletUseCase.GetTransactionHistory(c.Request.Context(), walletID, cursorPtr, limit)
+	if err != nil {
+		c.Error(err).SetMeta("admin.transactions_retrieve_failed")
+		return
+	}
+
+	transactionResponses := make([]dto.AdminTransactionResponse, len(transactions))
+	for i, tx := range transactions {
+		transactionResponses[i] = dto.ToAdminTransactionResponse(&tx)
+	}
+
+	setCursorLinks(c, nextCursor)
+
+	c.JSON(http.StatusOK, dto.APIResponse{
+		Success: true,
+		Message: middleware.Translate(c, "transactions.retrieved"),
+		Data: dto.AdminTransactionHistoryResponse{
+			Transactions: transactionResponses,
+			Pagination: dto.CursorPaginationMeta{
+				PageSize:    limit,
+				NextCursor:  nextCursor,
+				HasNextPage: nextCursor != nil && *nextCursor != "",
+			},
+		},
+	})
+}
+
+// GetTransaction godoc
+//
+//	@Summary		Get transaction detail
+//	@Description	Retrieve any transaction with its related double-entry leg (admin and support)
+//	@Tags			admin
+//	@Accept			json
+//	@Produce		json
+//	@Security		BearerAuth
+//	@Param			id	path		int	true	"Transaction ID"
+//	@Success		200	{object}	dto.APIResponse{data=dto.AdminTransactionResponse}
+//	@Failure		400	{object}	dto.ErrorResponse
+//	@Failure		401	{object}	dto.ErrorResponse
+//	@Failure		403	{object}	dto.ErrorResponse
+//	@Failure		404	{object}	dto.ErrorResponse
+//	@Failure		500	{object}	dto.ErrorResponse
+//	@Router			/admin/transactions/{id} [get]
+func (h *AdminHandler) GetTransaction(c *gin.Context) {
+	transactionID, err := parseIDParam(c, "id")
+	if err != nil {
+		c.Error(err).SetMeta("admin.invalid_transaction_id")
+		return
+	}
+
+	transaction, err := h.walletUseCase.GetTransaction(c.Request.Context(), transactionID)
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			err = apperrors.ErrTransactionNotFound
+		}
+		c.Error(err).SetMeta("admin.transaction_retrieve_failed")
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.APIResponse{
+		Success: true,
+		Message: middleware.Translate(c, "transaction.retrieved"),
+		Data:    dto.ToAdminTransactionResponse(transaction),
+	})
+}
+
+// GetTransactionByReference godoc
+//
+//	@Summary		Get transaction legs by reference
+//	@Description	Look up an operation by the reference it was made with and return both double-entry legs, the one carrying the reference first (admin and support)
+//	@Tags			admin
+//	@Accept			json
+//	@Produce		json
+//	@Security		BearerAuth
+//	@Param			reference	path		string	true	"Transaction reference"
+//	@Success		200			{object}	dto.APIResponse{data=[]dto.AdminTransactionResponse}
+//	@Failure		401			{object}	dto.ErrorResponse
+//	@Failure		403			{object}	dto.ErrorResponse
+//	@Failure		404			{object}	dto.ErrorResponse
+//	@Failure		500			{object}	dto.ErrorResponse
+//	@Router			/admin/transactions/by-reference/{reference} [get]
+func (h *AdminHandler) GetTransactionByReference(c *gin.Context) {
+	transaction, counter, err := h.walletUseCase.GetTransactionByReference(c.Request.Context(), c.Param("reference"))
+	if err != nil {
+		c.Error(err).SetMeta("admin.transaction_retrieve_failed")
+		return
+	}
+
+	legs := []dto.AdminTransactionResponse{dto.ToAdminTransactionResponse(transaction)}
+	if counter != nil {
+		legs = append(legs, dto.ToAdminTransactionResponse(counter))
+	}
+
+	c.JSON(http.StatusOK, dto.APIResponse{
+		Success: true,
+		Message: middleware.Translate(c, "transaction.retrieved"),
+		Data:    legs,
+	})
+}
+
+// ListAuditLogs godoc
+//
+//	@Summary		List audit logs
+//	@Description	Retrieve the audit trail of privileged actions (admin only)
+//	@Tags			admin
+//	@Accept			json
+//	@Produce		json
+//	@Security		BearerAuth
+//	@Param			actor_id	query		int	false	"Filter by actor user ID"
+//	@Param			page		query		int	false	"Page number"	default(1)
+//	@Param			page_size	query		int	false	"Page size"		default(20)
+//	@Success		200			{object}	dto.APIResponse{data=[]dto.AuditLogResponse}
+//	@Failure		401			{object}	dto.ErrorResponse
+//	@Failure		403			{object}	dto.ErrorResponse
+//	@Failure		500			{object}	dto.ErrorResponse
+//	@Router			/admin/audit-logs [get]
+func (h *AdminHandler) ListAuditLogs(c *gin.Context) {
+	page, pageSize := parsePagination(c)
+
+	var entries []models.AuditLog
+	var err error
+	if actorID, parseErr := strconv.ParseUint(c.Query("actor_id"), 10, 64); parseErr == nil && actorID > 0 {
+		entries, err = h.auditUseCase.ListAuditLogsByActor(c.Request.Context(), uint(actorID), page, pageSize)
+	} else {
+		entries, err = h.auditUseCase.ListAuditLogs(c.Request.Context(), page, pageSize)
+	}
+	if err != nil {
+		c.Error(err).SetMeta("admin.audit_logs_retrieve_failed")
+		return
+	}
+
+	responses := make([]dto.AuditLogResponse, len(entries))
+	for i, entry := range entries {
+		responses[i] = dto.ToAuditLogResponse(&entry)
+	}
+
+	c.JSON(http.StatusOK, dto.APIResponse{
+		Success: true,
+		Message: middleware.Translate(c, "admin.audit_logs_retrieved"),
+		Data:    responses,
+	})
+}
+
+// respondWalletLookupError reports a 404 for missing wallets and a 500 otherwise
+func (h *AdminHandler) respondWalletLookupError(c *gin.Context, err error) {
+	if err == gorm.ErrRecordNotFound {
+		err = apperrors.ErrWalletNotFound
+	}
+	c.Error(err).SetMeta("admin.wallet_retrieve_failed")
+}
+
+// parseTimeQuery reads an optional RFC 3339 time or YYYY-MM-DD day; with endOfDay a day is read as the
+// start of the next one, so it is included by an exclusive bound
+func parseTimeQuery(c *gin.Context, name string, endOfDay bool) (*time.Time, error) {
+	value := c.Query(name)
+	if value == "" {
+		return nil, nil
+	}
+	if parsed, err := time.Parse(time.RFC3339, value); err == nil {
+		return &parsed, nil
+	}
+	day, err := time.Parse("2006-01-02", value)
+	if err != nil {
+		return nil, apperrors.ErrValidation.Withf("invalid %s: %q", name, value)
+	}
+	if endOfDay {
+		day = day.AddDate(0, 0, 1)
+	}
+	return &day, nil
+}
+
+func toReconciliationReportResponses(reports []models.ReconciliationReport) []dto.ReconciliationReportResponse {
+	responses := make([]dto.ReconciliationReportResponse, len(reports))
+	for i, report := range reports {
+		responses[i] = dto.ToReconciliationReportResponse(&report)
+	}
+	return responses
+}
@@ -0,0 +1,192 @@
+package handlers
+
+import (
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/limistah/wallet-service/internal/dto"
+	"github.com/limistah/wallet-service/internal/middleware"
+	"github.com/limistah/wallet-service/internal/models"
+	"github.com/limistah/wallet-service/internal/repositories"
+	"github.com/limistah/wallet-service/internal/usecases"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestAdminHandler_ListWallets(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	tests := []struct {
+		name           string
+		queryParams    string
+		setupMock      func(*MockWalletUseCase)
+		expectedStatus int
+		expectedTotal  float64
+	}{
+		{
+			name:        "filters are passed to the use case",
+			queryParams: "?status=active&currency=usd&min_balance=10&email=john&page=2&page_size=5",
+			setupMock: func(mockUC *MockWalletUseCase) {
+				wallets := []models.Wallet{
+					{ID: 7, UserID: 3, Balance: decimal.NewFromInt(50), Currency: "USD", Status: models.WalletStatusActive},
+				}
+				mockUC.On("ListWallets", mock.MatchedBy(func(filter repositories.WalletFilter) bool {
+					return filter.Status == models.WalletStatusActive &&
+						filter.Currency == "USD" &&
+						filter.MinBalance != nil && filter.MinBalance.Equal(decimal.NewFromInt(10)) &&
+						filter.MaxBalance == nil &&
+						filter.UserEmail == "john"
+				}), 2, 5).Return(wallets, int64(6), nil)
+			},
+			expectedStatus: http.StatusOK,
+			expectedTotal:  6,
+		},
+		{
+			name:           "invalid status",
+			queryParams:    "?status=frozen",
+			setupMock:      func(mockUC *MockWalletUseCase) {},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "invalid balance",
+			queryParams:    "?max_balance=abc",
+			setupMock:      func(mockUC *MockWalletUseCase) {},
+			expectedStatus: http.StatusBadRequest,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockUC := new(MockWalletUseCase)
+			tt.setupMock(mockUC)
+
+			handler := NewAdminHandler(mockUC, nil, nil)
+
+			router := gin.New()
+			router.Use(middleware.ErrorHandler())
+			router.GET("/admin/wallets", handler.ListWallets)
+
+			req, _ := http.NewRequest("GET", "/admin/wallets"+tt.queryParams, nil)
+			resp := httptest.NewRecorder()
+
+			router.ServeHTTP(resp, req)
+
+			assert.Equal(t, tt.expectedStatus, resp.Code)
+
+			if tt.expectedStatus == http.StatusOK {
+				var response dto.APIResponse
+				err := json.Unmarshal(resp.Body.Bytes(), &response)
+				assert.NoError(t, err)
+				assert.True(t, response.Success)
+
+				responseData, ok := response.Data.(map[string]interface{})
+				assert.True(t, ok)
+
+				pagination, ok := responseData["pagination"].(map[string]interface{})
+				assert.True(t, ok)
+				assert.Equal(t, tt.expectedTotal, pagination["total"])
+				assert.Equal(t, float64(2), pagination["total_pages"])
+			}
+
+			mockUC.AssertExpectations(t)
+		})
+	}
+}
+
+// exportingReconciliationUseCase hands its reports to exports in one batch and records the filter
+type exportingReconciliationUseCase struct {
+	usecases.ReconciliationUseCase
+	reports []models.ReconciliationReport
+	filter  repositories.ReconciliationReportFilter
+}
+
+func (u *exportingReconciliationUseCase) ExportReports(ctx context.Context, filter repositories.ReconciliationReportFilter, fn func([]models.ReconciliationReport) error) error {
+	u.filter = filter
+	return fn(u.reports)
+}
+
+func TestAdminHandler_ExportReconciliationReports(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	reconciliationUC := &exportingReconciliationUseCase{
+		reports: []models.ReconciliationReport{
+			{
+				ID:                4,
+				CreatedAt:         time.Date(2025, 3, 1, 2, 0, 0, 0, time.UTC),
+				WalletID:          7,
+				StoredBalance:     decimal.NewFromInt(100),
+				CalculatedBalance: decimal.RequireFromString("90.5"),
+				Difference:        decimal.RequireFromString("9.5"),
+				Status:            models.ReconciliationStatusMismatch,
+				Notes:             "=HYPERLINK(\"http://evil\")",
+				Wallet:            models.Wallet{ID: 7, Currency: "USD"},
+			},
+		},
+	}
+	handler := NewAdminHandler(nil, reconciliationUC, nil)
+
+	router := gin.New()
+	router.Use(middleware.ErrorHandler())
+	router.GET("/admin/reconciliation/reports/export", handler.ExportReconciliationReports)
+
+	req, _ := http.NewRequest("GET", "/admin/reconciliation/reports/export?status=mismatch&from=2025-03-01&to=2025-03-01", nil)
+	resp := httptest.NewRecorder()
+	router.ServeHTTP(resp, req)
+
+	assert.Equal(t, http.StatusOK, resp.Code)
+	assert.Equal(t, "text/csv; charset=utf-8", resp.Header().Get("Content-Type"))
+	assert.Contains(t, resp.Header().Get("Content-Disposition"), "attachment")
+
+	assert.Equal(t, models.ReconciliationStatusMismatch, reconciliationUC.filter.Status)
+	assert.True(t, reconciliationUC.filter.From.Equal(time.Date(2025, 3, 1, 0, 0, 0, 0, time.UTC)))
+	assert.True(t, reconciliationUC.filter.To.Equal(time.Date(2025, 3, 2, 0, 0, 0, 0, time.UTC)), "the to day is included")
+
+	rows, err := csv.NewReader(resp.Body).ReadAll()
+	assert.NoError(t, err)
+	assert.Equal(t, [][]string{
+		dto.ReconciliationReportCSVHeader,
+		{"4", "2025-03-01T02:00:00Z", "7", "USD", "100.00", "90.50", "9.50", "MISMATCH", "'=HYPERLINK(\"http://evil\")"},
+	}, rows)
+
+	for _, query := range []string{"?status=broken", "?from=yesterday"} {
+		req, _ := http.NewRequest("GET", "/admin/reconciliation/reports/export"+query, nil)
+		resp := httptest.NewRecorder()
+		router.ServeHTTP(resp, req)
+		assert.Equal(t, http.StatusBadRequest, resp.Code, query)
+	}
+}
+
+func TestAdminHandler_ListTransactionTypes(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	mockUC := new(MockWalletUseCase)
+	mockUC.On("ListTransactionTypes").Return(models.DefaultTransactionTypes(), nil)
+	handler := NewAdminHandler(mockUC, nil, nil)
+
+	router := gin.New()
+	router.Use(middleware.ErrorHandler())
+	router.GET("/admin/transaction-types", handler.ListTransactionTypes)
+
+	req, _ := http.NewRequest("GET", "/admin/transaction-types", nil)
+	resp := httptest.NewRecorder()
+	router.ServeHTTP(resp, req)
+
+	assert.Equal(t, http.StatusOK, resp.Code)
+
+	var body struct {
+		Data []dto.TransactionTypeResponse `json:"data"`
+	}
+	assert.NoError(t, json.Unmarshal(resp.Body.Bytes(), &body))
+	assert.Equal(t, []dto.TransactionTypeResponse{
+		{Name: "CREDIT", Description: "Money into the wallet"},
+		{Name: "DEBIT", Description: "Money out of the wallet"},
+	}, body.Data)
+	mockUC.AssertExpectations(t)
+}
@@ -0,0 +1,149 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/limistah/wallet-service/internal/apperrors"
+	"github.com/limistah/wallet-service/internal/dto"
+	"github.com/limistah/wallet-service/internal/middleware"
+	"github.com/limistah/wallet-service/internal/models"
+	"github.com/limistah/wallet-service/internal/usecases"
+)
+
+type AMLCaseHandler struct {
+	walletUseCase usecases.WalletUseCase
+}
+
+func NewAMLCaseHandler(walletUseCase usecases.WalletUseCase) *AMLCaseHandler {
+	return &AMLCaseHandler{
+		walletUseCase: walletUseCase,
+	}
+}
+
+// ListAMLCases godoc
+//
+//	@Summary		List AML cases
+//	@Description	List debits parked as PENDING by AML screening, oldest first (admin and support)
+//	@Tags			admin
+//	@Accept			json
+//	@Produce		json
+//	@Security		BearerAuth
+//	@Param			status		query		string	false	"Case status (PENDING, APPROVED, REJECTED, EXPIRED)"	default(PENDING)
+//	@Param			page		query		int		false	"Page number"											default(1)
+//	@Param			page_size	query		int		false	"Page size"												default(20)
+//	@Success		200			{object}	dto.APIResponse{data=[]dto.AMLCaseResponse}
+//	@Failure		400			{object}	dto.ErrorResponse
+//	@Failure		401			{object}	dto.ErrorResponse
+//	@Failure		403			{object}	dto.ErrorResponse
+//	@Failure		500			{object}	dto.ErrorResponse
+//	@Router			/admin/aml-cases [get]
+func (h *AMLCaseHandler) ListAMLCases(c *gin.Context) {
+	status := models.AMLCaseStatus(strings.ToUpper(c.DefaultQuery("status", string(models.AMLCaseStatusPending))))
+	switch status {
+	case models.AMLCaseStatusPending, models.AMLCaseStatusApproved, models.AMLCaseStatusRejected, models.AMLCaseStatusExpired:
+	default:
+		c.Error(apperrors.ErrValidation.Withf("invalid status")).SetMeta("aml_case.invalid_status")
+		return
+	}
+
+	page, pageSize := parsePagination(c)
+	cases, err := h.walletUseCase.ListAMLCases(c.Request.Context(), status, page, pageSize)
+	if err != nil {
+		c.Error(err).SetMeta("aml_case.list_failed")
+		return
+	}
+
+	responses := make([]dto.AMLCaseResponse, len(cases))
+	for i, amlCase := range cases {
+		responses[i] = dto.ToAMLCaseResponse(&amlCase)
+	}
+
+	c.JSON(http.StatusOK, dto.APIResponse{
+		Success: true,
+		Message: middleware.Translate(c, "aml_case.list_retrieved"),
+		Data:    responses,
+	})
+}
+
+// ApproveAMLCase godoc
+//
+//	@Summary		Clear a debit under AML review
+//	@Description	Clear a debit parked by AML screening: transfers are credited to the recipient and withdrawals are completed or paid out (admin only)
+//	@Tags			admin
+//	@Accept			json
+//	@Produce		json
+//	@Security		BearerAuth
+//	@Param			id		path		int							true	"AML case ID"
+//	@Param			request	body		dto.ResolveAMLCaseRequest	false	"Decision note"
+//	@Success		200		{object}	dto.APIResponse{data=dto.AMLCaseResponse}
+//	@Failure		400		{object}	dto.ErrorResponse
+//	@Failure		401		{object}	dto.ErrorResponse
+//	@Failure		403		{object}	dto.ErrorResponse
+//	@Failure		404		{object}	dto.ErrorResponse
+//	@Failure		409		{object}	dto.ErrorResponse	"Case already resolved"
+//	@Failure		500		{object}	dto.ErrorResponse
+//	@Failure		502		{object}	dto.ErrorResponse
+//	@Router			/admin/aml-cases/{id}/approve [post]
+func (h *AMLCaseHandler) ApproveAMLCase(c *gin.Context) {
+	h.resolve(c, h.walletUseCase.ApproveAMLCase, "aml_case.approve_failed", "aml_case.approved")
+}
+
+// RejectAMLCase godoc
+//
+//	@Summary		Reject a debit under AML review
+//	@Description	Fail a debit parked by AML screening and return the held amount and fee to the wallet (admin only)
+//	@Tags			admin
+//	@Accept			json
+//	@Produce		json
+//	@Security		BearerAuth
+//	@Param			id		path		int							true	"AML case ID"
+//	@Param			request	body		dto.ResolveAMLCaseRequest	false	"Decision note"
+//	@Success		200		{object}	dto.APIResponse{data=dto.AMLCaseResponse}
+//	@Failure		400		{object}	dto.ErrorResponse
+//	@Failure		401		{object}	dto.ErrorResponse
+//	@Failure		403		{object}	dto.ErrorResponse
+//	@Failure		404		{object}	dto.ErrorResponse
+//	@Failure		409		{object}	dto.ErrorResponse	"Case already resolved"
+//	@Failure		500		{object}	dto.ErrorResponse
+//	@Router			/admin/aml-cases/{id}/reject [post]
+func (h *AMLCaseHandler) RejectAMLCase(c *gin.Context) {
+	h.resolve(c, h.walletUseCase.RejectAMLCase, "aml_case.reject_failed", "aml_case.rejected")
+}
+
+// resolve records a compliance decision on an AML case; the request body is optional
+func (h *AMLCaseHandler) resolve(c *gin.Context, decide func(ctx context.Context, caseID, reviewerID uint, note string) (*models.AMLCase, error), failureKey, successKey string) {
+	reviewerID, exists := middleware.GetUserID(c)
+	if !exists {
+		c.Error(apperrors.ErrUnauthenticated)
+		return
+	}
+
+	caseID, err := parseIDParam(c, "id")
+	if err != nil {
+		c.Error(err).SetMeta("aml_case.invalid_id")
+		return
+	}
+
+	var req dto.ResolveAMLCaseRequest
+	if err := c.ShouldBindJSON(&req); err != nil && !errors.Is(err, io.EOF) {
+		c.Error(apperrors.ErrValidation.Withf("invalid request data: %w", err)).SetMeta("request.invalid")
+		return
+	}
+
+	amlCase, err := decide(c.Request.Context(), caseID, reviewerID, strings.TrimSpace(req.Note))
+	if err != nil {
+		c.Error(err).SetMeta(failureKey)
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.APIResponse{
+		Success: true,
+		Message: middleware.Translate(c, successKey),
+		Data:    dto.ToAMLCaseResponse(amlCase),
+	})
+}
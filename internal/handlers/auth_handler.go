@@ -1,9 +1,13 @@
 package handlers
 
 import (
+	"context"
 	"net/http"
+	"strconv"
+	"time"
 
 	"github.com/gin-gonic/gin"
+	"github.com/limistah/wallet-service/internal/apperrors"
 	"github.com/limistah/wallet-service/internal/auth"
 	"github.com/limistah/wallet-service/internal/dto"
 	"github.com/limistah/wallet-service/internal/middleware"
@@ -12,14 +16,20 @@ import (
 )
 
 type AuthHandler struct {
-	userUseCase usecases.UserUseCase
-	jwtService  *auth.JWTService
+	userUseCase  usecases.UserUseCase
+	loginUseCase usecases.LoginUseCase
+	revocations  usecases.TokenRevocationUseCase
+	sessions     usecases.SessionUseCase
+	jwtService   *auth.JWTService
 }
 
-func NewAuthHandler(userUseCase usecases.UserUseCase, jwtService *auth.JWTService) *AuthHandler {
+func NewAuthHandler(userUseCase usecases.UserUseCase, loginUseCase usecases.LoginUseCase, revocations usecases.TokenRevocationUseCase, sessions usecases.SessionUseCase, jwtService *auth.JWTService) *AuthHandler {
 	return &AuthHandler{
-		userUseCase: userUseCase,
-		jwtService:  jwtService,
+		userUseCase:  userUseCase,
+		loginUseCase: loginUseCase,
+		revocations:  revocations,
+		sessions:     sessions,
+		jwtService:   jwtService,
 	}
 }
 
@@ -31,18 +41,20 @@ func NewAuthHandler(userUseCase usecases.UserUseCase, jwtService *auth.JWTServic
 // @Produce json
 // @Param user body dto.CreateUserRequest true "User registration data"
 // @Success 201 {object} dto.APIResponse{data=dto.UserResponse}
-// @Failure 400 {object} dto.ErrorResponse
+// @Failure 400 {object} dto.ErrorResponse "Invalid data, or a password breaking the password policy (code WEAK_PASSWORD) or seen in a data breach (code PASSWORD_BREACHED)"
+// @Failure 403 {object} dto.ErrorResponse "Sanctions screening match (code SANCTIONS_MATCH)"
 // @Failure 409 {object} dto.ErrorResponse
 // @Failure 500 {object} dto.ErrorResponse
 // @Router /auth/register [post]
 func (h *AuthHandler) Register(c *gin.Context) {
 	var req dto.CreateUserRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, dto.ErrorResponse{
-			Success: false,
-			Message: "Invalid request data",
-			Error:   err.Error(),
-		})
+		c.Error(apperrors.ErrValidation.Withf("invalid request data: %w", err)).SetMeta("request.invalid")
+		return
+	}
+
+	if err := h.userUseCase.ValidatePassword(c.Request.Context(), req.Password); err != nil {
+		c.Error(err).SetMeta("auth.password_policy_failed")
 		return
 	}
 
@@ -50,92 +62,58 @@ func (h *AuthHandler) Register(c *gin.Context) {
 		Name:  req.Name,
 		Email: req.Email,
 		Age:   req.Age,
+		Role:  models.UserRoleUser,
 	}
 
 	if err := user.HashPassword(req.Password); err != nil {
-		c.JSON(http.StatusInternalServerError, dto.ErrorResponse{
-			Success: false,
-			Message: "Failed to process password",
-			Error:   err.Error(),
-		})
+		c.Error(err).SetMeta("auth.password_process_failed")
 		return
 	}
 
-	createdUser, err := h.userUseCase.CreateUser(user)
+	createdUser, err := h.userUseCase.CreateUser(c.Request.Context(), user)
 	if err != nil {
-		if err.Error() == "user with this email already exists" {
-			c.JSON(http.StatusConflict, dto.ErrorResponse{
-				Success: false,
-				Message: "User already exists",
-				Error:   err.Error(),
-			})
-			return
-		}
-		c.JSON(http.StatusInternalServerError, dto.ErrorResponse{
-			Success: false,
-			Message: "Failed to create user",
-			Error:   err.Error(),
-		})
+		c.Error(err).SetMeta("auth.register_failed")
 		return
 	}
 
 	userResponse := dto.ToUserResponse(createdUser)
 	c.JSON(http.StatusCreated, dto.APIResponse{
 		Success: true,
-		Message: "User registered successfully",
+		Message: middleware.Translate(c, "auth.registered"),
 		Data:    userResponse,
 	})
 }
 
 // Login godoc
 // @Summary Login user
-// @Description Authenticate user and return JWT token
+// @Description Authenticate user and return JWT token. Too many wrong passwords lock the account for a cooldown, and IP addresses failing too often are throttled
 // @Tags auth
 // @Accept json
 // @Produce json
 // @Param credentials body dto.LoginRequest true "User login credentials"
 // @Success 200 {object} dto.APIResponse{data=dto.LoginResponse}
 // @Failure 400 {object} dto.ErrorResponse
-// @Failure 401 {object} dto.ErrorResponse
+// @Failure 401 {object} dto.ErrorResponse "Wrong email or password (code INVALID_CREDENTIALS)"
+// @Failure 403 {object} dto.ErrorResponse "Account locked (code ACCOUNT_LOCKED)"
+// @Failure 429 {object} dto.ErrorResponse "Too many failed logins from the IP address (code TOO_MANY_LOGIN_ATTEMPTS)"
 // @Failure 500 {object} dto.ErrorResponse
 // @Router /auth/login [post]
 func (h *AuthHandler) Login(c *gin.Context) {
 	var req dto.LoginRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, dto.ErrorResponse{
-			Success: false,
-			Message: "Invalid request data",
-			Error:   err.Error(),
-		})
+		c.Error(apperrors.ErrValidation.Withf("invalid request data: %w", err)).SetMeta("request.invalid")
 		return
 	}
 
-	user, err := h.userUseCase.GetUserByEmail(req.Email)
+	user, err := h.loginUseCase.Authenticate(c.Request.Context(), req.Email, req.Password, clientInfo(c))
 	if err != nil {
-		c.JSON(http.StatusUnauthorized, dto.ErrorResponse{
-			Success: false,
-			Message: "Invalid credentials",
-			Error:   "email or password is incorrect",
-		})
+		c.Error(err).SetMeta("auth.login_failed")
 		return
 	}
 
-	if err := user.CheckPassword(req.Password); err != nil {
-		c.JSON(http.StatusUnauthorized, dto.ErrorResponse{
-			Success: false,
-			Message: "Invalid credentials",
-			Error:   "email or password is incorrect",
-		})
-		return
-	}
-
-	token, err := h.jwtService.GenerateToken(user.ID, user.Email)
+	token, err := issueSessionToken(c, h.jwtService, h.sessions, user, req.Sandbox)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, dto.ErrorResponse{
-			Success: false,
-			Message: "Failed to generate token",
-			Error:   err.Error(),
-		})
+		c.Error(err).SetMeta("auth.token_generate_failed")
 		return
 	}
 
@@ -146,92 +124,150 @@ func (h *AuthHandler) Login(c *gin.Context) {
 
 	c.JSON(http.StatusOK, dto.APIResponse{
 		Success: true,
-		Message: "Login successful",
+		Message: middleware.Translate(c, "auth.logged_in"),
 		Data:    loginResponse,
 	})
 }
 
 // ChangePassword godoc
 // @Summary Change user password
-// @Description Change the password for the authenticated user
+// @Description Change the password for the authenticated user. The new password must follow the password policy and differ from the recent passwords of the user. Every session of the user is signed out, including the current one, so the user must log in again
 // @Tags auth
 // @Accept json
 // @Produce json
 // @Security BearerAuth
 // @Param password body dto.ChangePasswordRequest true "Password change data"
 // @Success 200 {object} dto.APIResponse
-// @Failure 400 {object} dto.ErrorResponse
+// @Failure 400 {object} dto.ErrorResponse "Wrong current password (code INCORRECT_PASSWORD), or a new password that is weak (code WEAK_PASSWORD), breached (code PASSWORD_BREACHED) or used recently (code PASSWORD_REUSED)"
 // @Failure 401 {object} dto.ErrorResponse
 // @Failure 500 {object} dto.ErrorResponse
 // @Router /auth/change-password [post]
 func (h *AuthHandler) ChangePassword(c *gin.Context) {
 	var req dto.ChangePasswordRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, dto.ErrorResponse{
-			Success: false,
-			Message: "Invalid request data",
-			Error:   err.Error(),
-		})
+		c.Error(apperrors.ErrValidation.Withf("invalid request data: %w", err)).SetMeta("request.invalid")
 		return
 	}
 
 	userID, exists := middleware.GetUserID(c)
 	if !exists {
-		c.JSON(http.StatusUnauthorized, dto.ErrorResponse{
-			Success: false,
-			Message: "User not authenticated",
-			Error:   "user ID not found in context",
-		})
+		c.Error(apperrors.ErrUnauthenticated)
 		return
 	}
 
-	user, err := h.userUseCase.GetUserByID(userID)
+	if err := h.userUseCase.ChangePassword(c.Request.Context(), userID, req.CurrentPassword, req.NewPassword); err != nil {
+		c.Error(err).SetMeta("auth.password_change_failed")
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.APIResponse{
+		Success: true,
+		Message: middleware.Translate(c, "auth.password_changed"),
+	})
+}
+
+// LoginHistory godoc
+// @Summary Get login history
+// @Description List the latest logins to the authenticated user's account, successful or not, with their IP address and device, so the user can spot access that was not theirs
+// @Tags auth
+// @Produce json
+// @Security BearerAuth
+// @Param limit query int false "Number of entries, at most 100" default(20)
+// @Success 200 {object} dto.APIResponse{data=[]dto.LoginAttemptResponse}
+// @Failure 401 {object} dto.ErrorResponse
+// @Failure 500 {object} dto.ErrorResponse
+// @Router /auth/sessions/history [get]
+func (h *AuthHandler) LoginHistory(c *gin.Context) {
+	userID, exists := middleware.GetUserID(c)
+	if !exists {
+		c.Error(apperrors.ErrUnauthenticated)
+		return
+	}
+
+	limit := 20
+	if l := c.Query("limit"); l != "" {
+		if parsed, err := strconv.Atoi(l); err == nil && parsed > 0 && parsed <= 100 {
+			limit = parsed
+		}
+	}
+
+	attempts, err := h.loginUseCase.LoginHistory(c.Request.Context(), userID, limit)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, dto.ErrorResponse{
-			Success: false,
-			Message: "Failed to get user",
-			Error:   err.Error(),
-		})
+		c.Error(err).SetMeta("auth.login_history_retrieve_failed")
 		return
 	}
 
-	if err := user.CheckPassword(req.CurrentPassword); err != nil {
-		c.JSON(http.StatusBadRequest, dto.ErrorResponse{
-			Success: false,
-			Message: "Current password is incorrect",
-			Error:   "invalid current password",
-		})
+	attemptResponses := make([]dto.LoginAttemptResponse, len(attempts))
+	for i, attempt := range attempts {
+		attemptResponses[i] = dto.ToLoginAttemptResponse(&attempt)
+	}
+
+	c.JSON(http.StatusOK, dto.APIResponse{
+		Success: true,
+		Message: middleware.Translate(c, "auth.login_history_retrieved"),
+		Data:    attemptResponses,
+	})
+}
+
+// Logout godoc
+// @Summary Logout
+// @Description Revoke the token used for this request; it is refused from now on even before it expires
+// @Tags auth
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} dto.APIResponse
+// @Failure 401 {object} dto.ErrorResponse
+// @Failure 500 {object} dto.ErrorResponse
+// @Router /auth/logout [post]
+func (h *AuthHandler) Logout(c *gin.Context) {
+	claims, exists := middleware.GetTokenClaims(c)
+	if !exists {
+		c.Error(apperrors.ErrUnauthenticated)
 		return
 	}
 
-	if err := user.HashPassword(req.NewPassword); err != nil {
-		c.JSON(http.StatusInternalServerError, dto.ErrorResponse{
-			Success: false,
-			Message: "Failed to process new password",
-			Error:   err.Error(),
-		})
+	if err := h.revokeToken(c.Request.Context(), claims); err != nil {
+		c.Error(err).SetMeta("auth.logout_failed")
 		return
 	}
 
-	_, err = h.userUseCase.UpdateUser(userID, user)
-	if err != nil {
-		c.JSON(http.StatusInternalServerError, dto.ErrorResponse{
-			Success: false,
-			Message: "Failed to update password",
-			Error:   err.Error(),
-		})
+	c.JSON(http.StatusOK, dto.APIResponse{
+		Success: true,
+		Message: middleware.Translate(c, "auth.logged_out"),
+	})
+}
+
+// LogoutAll godoc
+// @Summary Logout from every session
+// @Description Revoke every token issued to the authenticated user so far, on all devices
+// @Tags auth
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} dto.APIResponse
+// @Failure 401 {object} dto.ErrorResponse
+// @Failure 500 {object} dto.ErrorResponse
+// @Router /auth/logout/all [post]
+func (h *AuthHandler) LogoutAll(c *gin.Context) {
+	userID, exists := middleware.GetUserID(c)
+	if !exists {
+		c.Error(apperrors.ErrUnauthenticated)
+		return
+	}
+
+	if err := h.revocations.RevokeAllTokens(c.Request.Context(), userID); err != nil {
+		c.Error(err).SetMeta("auth.logout_failed")
 		return
 	}
 
 	c.JSON(http.StatusOK, dto.APIResponse{
 		Success: true,
-		Message: "Password changed successfully",
+		Message: middleware.Translate(c, "auth.logged_out_everywhere"),
 	})
 }
 
 // RefreshToken godoc
 // @Summary Refresh JWT token
-// @Description Generate a new JWT token using the current valid token
+// @Description Generate a new JWT token using the current valid token. The current token is revoked, and its session moves to the new token
 // @Tags auth
 // @Accept json
 // @Produce json
@@ -241,32 +277,88 @@ func (h *AuthHandler) ChangePassword(c *gin.Context) {
 // @Failure 500 {object} dto.ErrorResponse
 // @Router /auth/refresh [post]
 func (h *AuthHandler) RefreshToken(c *gin.Context) {
-	// Get the current token from the Authorization header
-	authHeader := c.GetHeader("Authorization")
-	if authHeader == "" {
-		c.JSON(http.StatusUnauthorized, dto.ErrorResponse{
-			Success: false,
-			Message: "Authorization header is required",
-			Error:   "missing authorization header",
-		})
+	claims, exists := middleware.GetTokenClaims(c)
+	if !exists {
+		c.Error(apperrors.ErrUnauthenticated)
+		return
+	}
+	if claims.Impersonator != nil {
+		// A refresh would turn a short-lived impersonation into a regular session of the user
+		err := apperrors.ErrImpersonationForbidden.Withf("impersonation tokens cannot be refreshed")
+		c.Error(err).SetMeta("auth.token_refresh_failed")
 		return
 	}
 
-	tokenString := authHeader[7:] // Removes the "Bearer " prefix
-
-	newToken, err := h.jwtService.RefreshToken(tokenString)
+	// The new token carries the same user data with an extended expiry
+	newToken, newClaims, err := h.jwtService.IssueToken(claims.UserID, claims.TenantID, claims.Email, claims.Role, claims.Sandbox)
 	if err != nil {
-		c.JSON(http.StatusUnauthorized, dto.ErrorResponse{
-			Success: false,
-			Message: "Failed to refresh token",
-			Error:   err.Error(),
-		})
+		c.Error(err).SetMeta("auth.token_refresh_failed")
+		return
+	}
+	if _, err := h.sessions.RefreshSession(c.Request.Context(), claims.UserID, claims.ID, tokenInfo(newClaims), clientInfo(c)); err != nil {
+		c.Error(err).SetMeta("auth.token_refresh_failed")
+		return
+	}
+
+	// Revoke the replaced token so a refresh cannot keep a leaked token alive
+	if err := h.revokeToken(c.Request.Context(), claims); err != nil {
+		c.Error(err).SetMeta("auth.token_refresh_failed")
 		return
 	}
 
 	c.JSON(http.StatusOK, dto.APIResponse{
 		Success: true,
-		Message: "Token refreshed successfully",
+		Message: middleware.Translate(c, "auth.token_refreshed"),
 		Data:    map[string]string{"token": newToken},
 	})
 }
+
+// revokeToken revokes a token until it expires
+func (h *AuthHandler) revokeToken(ctx context.Context, claims *auth.Claims) error {
+	var expiresAt time.Time
+	if claims.ExpiresAt != nil {
+		expiresAt = claims.ExpiresAt.Time
+	}
+	return h.revocations.RevokeToken(ctx, claims.ID, claims.UserID, expiresAt)
+}
+
+// clientInfo describes the client of a login request
+func clientInfo(c *gin.Context) usecases.ClientInfo {
+	return usecases.ClientInfo{
+		IPAddress: c.ClientIP(),
+		UserAgent: c.Request.UserAgent(),
+	}
+}
+
+// issueSessionToken issues a token to the client of a login and records it as a session of the user
+func issueSessionToken(c *gin.Context, jwtService *auth.JWTService, sessions usecases.SessionUseCase, user *models.User, sandbox bool) (string, error) {
+	token, claims, err := jwtService.IssueToken(user.ID, user.TenantID, user.Email, string(user.Role), sandbox)
+	if err != nil {
+		return "", err
+	}
+	if _, err := sessions.StartSession(c.Request.Context(), user.ID, tokenInfo(claims), clientInfo(c)); err != nil {
+		return "", err
+	}
+	return token, nil
+}
+
+// tokenInfo identifies the token with the given claims
+func tokenInfo(claims *auth.Claims) usecases.TokenInfo {
+	return usecases.TokenInfo{
+		ID:        claims.ID,
+		IssuedAt:  claims.IssuedAt.Time,
+		ExpiresAt: claims.ExpiresAt.Time,
+	}
+}
+
+// JWKS godoc
+// @Summary JSON Web Key Set
+// @Description Public keys other services verify access tokens with, matched by the kid header of a token. The set is empty when tokens are signed with the shared secret
+// @Tags auth
+// @Produce json
+// @Success 200 {object} auth.JWKS
+// @Router /.well-known/jwks.json [get]
+func (h *AuthHandler) JWKS(c *gin.Context) {
+	c.Header("Cache-Control", "public, max-age=300")
+	c.JSON(http.StatusOK, h.jwtService.JWKS())
+}
@@ -0,0 +1,192 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/limistah/wallet-service/internal/apperrors"
+	"github.com/limistah/wallet-service/internal/dto"
+	"github.com/limistah/wallet-service/internal/middleware"
+	"github.com/limistah/wallet-service/internal/usecases"
+	"github.com/shopspring/decimal"
+)
+
+type BudgetHandler struct {
+	budgetUseCase usecases.BudgetUseCase
+}
+
+func NewBudgetHandler(budgetUseCase usecases.BudgetUseCase) *BudgetHandler {
+	return &BudgetHandler{
+		budgetUseCase: budgetUseCase,
+	}
+}
+
+// CreateBudget godoc
+//
+//	@Summary		Create a budget
+//	@Description	Set a monthly budget for a spending category. Debits tagged with the category count against it, and the user is notified when spending reaches 80% and 100% of the budget.
+//	@Tags			budgets
+//	@Accept			json
+//	@Produce		json
+//	@Security		BearerAuth
+//	@Param			request	body		dto.CreateBudgetRequest	true	"Budget"
+//	@Success		201		{object}	dto.APIResponse{data=dto.BudgetResponse}
+//	@Failure		400		{object}	dto.ErrorResponse
+//	@Failure		401		{object}	dto.ErrorResponse
+//	@Failure		404		{object}	dto.ErrorResponse
+//	@Failure		409		{object}	dto.ErrorResponse	"A budget already exists for the category"
+//	@Failure		500		{object}	dto.ErrorResponse
+//	@Router			/wallets/me/budgets [post]
+func (h *BudgetHandler) CreateBudget(c *gin.Context) {
+	userID, exists := middleware.GetUserID(c)
+	if !exists {
+		c.Error(apperrors.ErrUnauthenticated)
+		return
+	}
+
+	if rejectSandbox(c) {
+		return
+	}
+
+	var req dto.CreateBudgetRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.Error(apperrors.ErrValidation.Withf("invalid request data: %w", err)).SetMeta("request.invalid")
+		return
+	}
+
+	budget, err := h.budgetUseCase.CreateBudget(c.Request.Context(), userID, req.Category, req.Amount)
+	if err != nil {
+		c.Error(err).SetMeta("budget.create_failed")
+		return
+	}
+
+	c.JSON(http.StatusCreated, dto.APIResponse{
+		Success: true,
+		Message: middleware.Translate(c, "budget.created"),
+		Data:    dto.ToBudgetResponse(budget, decimal.Zero, nil),
+	})
+}
+
+// ListBudgets godoc
+//
+//	@Summary		List budgets
+//	@Description	List the budgets of the authenticated user with the spending in each category this month (UTC)
+//	@Tags			budgets
+//	@Accept			json
+//	@Produce		json
+//	@Security		BearerAuth
+//	@Success		200	{object}	dto.APIResponse{data=[]dto.BudgetResponse}
+//	@Failure		401	{object}	dto.ErrorResponse
+//	@Failure		500	{object}	dto.ErrorResponse
+//	@Router			/wallets/me/budgets [get]
+func (h *BudgetHandler) ListBudgets(c *gin.Context) {
+	userID, exists := middleware.GetUserID(c)
+	if !exists {
+		c.Error(apperrors.ErrUnauthenticated)
+		return
+	}
+
+	budgets, err := h.budgetUseCase.ListBudgets(c.Request.Context(), userID)
+	if err != nil {
+		c.Error(err).SetMeta("budget.list_failed")
+		return
+	}
+
+	responses := make([]dto.BudgetResponse, len(budgets))
+	for i, progress := range budgets {
+		responses[i] = dto.ToBudgetResponse(&progress.Budget, progress.Spent, progress.Display)
+	}
+
+	c.JSON(http.StatusOK, dto.APIResponse{
+		Success: true,
+		Message: middleware.Translate(c, "budget.list_retrieved"),
+		Data:    responses,
+	})
+}
+
+// UpdateBudget godoc
+//
+//	@Summary		Update a budget
+//	@Description	Change the monthly amount of a budget of the authenticated user; alerts are sent again when spending crosses a threshold of the new amount
+//	@Tags			budgets
+//	@Accept			json
+//	@Produce		json
+//	@Security		BearerAuth
+//	@Param			id		path		int							true	"Budget ID"
+//	@Param			request	body		dto.UpdateBudgetRequest	true	"New amount"
+//	@Success		200		{object}	dto.APIResponse{data=dto.BudgetResponse}
+//	@Failure		400		{object}	dto.ErrorResponse
+//	@Failure		401		{object}	dto.ErrorResponse
+//	@Failure		404		{object}	dto.ErrorResponse
+//	@Failure		500		{object}	dto.ErrorResponse
+//	@Router			/wallets/me/budgets/{id} [put]
+func (h *BudgetHandler) UpdateBudget(c *gin.Context) {
+	userID, exists := middleware.GetUserID(c)
+	if !exists {
+		c.Error(apperrors.ErrUnauthenticated)
+		return
+	}
+
+	budgetID, err := parseIDParam(c, "id")
+	if err != nil {
+		c.Error(err).SetMeta("budget.invalid_id")
+		return
+	}
+
+	var req dto.UpdateBudgetRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.Error(apperrors.ErrValidation.Withf("invalid request data: %w", err)).SetMeta("request.invalid")
+		return
+	}
+
+	budget, err := h.budgetUseCase.UpdateBudget(c.Request.Context(), userID, budgetID, req.Amount)
+	if err != nil {
+		c.Error(err).SetMeta("budget.update_failed")
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.APIResponse{
+		Success: true,
+		Message: middleware.Translate(c, "budget.updated"),
+		Data:    dto.ToBudgetResponse(budget, decimal.Zero, nil),
+	})
+}
+
+// DeleteBudget godoc
+//
+//	@Summary		Delete a budget
+//	@Description	Remove a budget of the authenticated user; its category is no longer tracked
+//	@Tags			budgets
+//	@Accept			json
+//	@Produce		json
+//	@Security		BearerAuth
+//	@Param			id	path		int	true	"Budget ID"
+//	@Success		200	{object}	dto.APIResponse
+//	@Failure		400	{object}	dto.ErrorResponse
+//	@Failure		401	{object}	dto.ErrorResponse
+//	@Failure		404	{object}	dto.ErrorResponse
+//	@Failure		500	{object}	dto.ErrorResponse
+//	@Router			/wallets/me/budgets/{id} [delete]
+func (h *BudgetHandler) DeleteBudget(c *gin.Context) {
+	userID, exists := middleware.GetUserID(c)
+	if !exists {
+		c.Error(apperrors.ErrUnauthenticated)
+		return
+	}
+
+	budgetID, err := parseIDParam(c, "id")
+	if err != nil {
+		c.Error(err).SetMeta("budget.invalid_id")
+		return
+	}
+
+	if err := h.budgetUseCase.DeleteBudget(c.Request.Context(), userID, budgetID); err != nil {
+		c.Error(err).SetMeta("budget.delete_failed")
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.APIResponse{
+		Success: true,
+		Message: middleware.Translate(c, "budget.deleted"),
+	})
+}
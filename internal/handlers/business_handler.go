@@ -0,0 +1,577 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/limistah/wallet-service/internal/apperrors"
+	"github.com/limistah/wallet-service/internal/dto"
+	"github.com/limistah/wallet-service/internal/middleware"
+	"github.com/limistah/wallet-service/internal/models"
+	"github.com/limistah/wallet-service/internal/usecases"
+	"github.com/shopspring/decimal"
+)
+
+type BusinessHandler struct {
+	businessUseCase usecases.BusinessUseCase
+	walletUseCase   usecases.WalletUseCase
+}
+
+func NewBusinessHandler(businessUseCase usecases.BusinessUseCase, walletUseCase usecases.WalletUseCase) *BusinessHandler {
+	return &BusinessHandler{
+		businessUseCase: businessUseCase,
+		walletUseCase:   walletUseCase,
+	}
+}
+
+// CreateBusinessAccount godoc
+//
+//	@Summary		Create a business account
+//	@Description	Open a business wallet in the currency of the authenticated user's wallet. The user owns the account, manages its members and is its first approver
+//	@Tags			businesses
+//	@Accept			json
+//	@Produce		json
+//	@Security		BearerAuth
+//	@Param			request	body		dto.CreateBusinessAccountRequest	true	"Business account"
+//	@Success		201		{object}	dto.APIResponse{data=dto.BusinessAccountResponse}
+//	@Failure		400		{object}	dto.ErrorResponse
+//	@Failure		401		{object}	dto.ErrorResponse
+//	@Failure		403		{object}	dto.ErrorResponse
+//	@Failure		404		{object}	dto.ErrorResponse
+//	@Failure		500		{object}	dto.ErrorResponse
+//	@Router			/businesses [post]
+func (h *BusinessHandler) CreateBusinessAccount(c *gin.Context) {
+	userID, exists := middleware.GetUserID(c)
+	if !exists {
+		c.Error(apperrors.ErrUnauthenticated)
+		return
+	}
+
+	if rejectSandbox(c) {
+		return
+	}
+
+	var req dto.CreateBusinessAccountRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.Error(apperrors.ErrValidation.Withf("invalid request data: %w", err)).SetMeta("request.invalid")
+		return
+	}
+
+	account, err := h.businessUseCase.CreateBusinessAccount(c.Request.Context(), userID, req.Name)
+	if err != nil {
+		c.Error(err).SetMeta("business.account_create_failed")
+		return
+	}
+
+	c.JSON(http.StatusCreated, dto.APIResponse{
+		Success: true,
+		Message: middleware.Translate(c, "business.account_created"),
+		Data:    dto.ToBusinessAccountResponse(account, models.BusinessRoleApprover),
+	})
+}
+
+// ListBusinessAccounts godoc
+//
+//	@Summary		List business accounts
+//	@Description	List the business accounts the authenticated user is a member of
+//	@Tags			businesses
+//	@Accept			json
+//	@Produce		json
+//	@Security		BearerAuth
+//	@Success		200	{object}	dto.APIResponse{data=[]dto.BusinessAccountResponse}
+//	@Failure		401	{object}	dto.ErrorResponse
+//	@Failure		500	{object}	dto.ErrorResponse
+//	@Router			/businesses [get]
+func (h *BusinessHandler) ListBusinessAccounts(c *gin.Context) {
+	userID, exists := middleware.GetUserID(c)
+	if !exists {
+		c.Error(apperrors.ErrUnauthenticated)
+		return
+	}
+
+	accounts, err := h.businessUseCase.ListBusinessAccounts(c.Request.Context(), userID)
+	if err != nil {
+		c.Error(err).SetMeta("business.accounts_retrieve_failed")
+		return
+	}
+
+	responses := make([]dto.BusinessAccountResponse, len(accounts))
+	for i := range accounts {
+		var role models.BusinessRole
+		for _, member := range accounts[i].Members {
+			if member.UserID == userID {
+				role = member.Role
+			}
+		}
+		responses[i] = dto.ToBusinessAccountResponse(&accounts[i], role)
+	}
+
+	c.JSON(http.StatusOK, dto.APIResponse{
+		Success: true,
+		Message: middleware.Translate(c, "business.accounts_retrieved"),
+		Data:    responses,
+	})
+}
+
+// GetBusinessAccount godoc
+//
+//	@Summary		Get a business account
+//	@Description	Get a business account of which the authenticated user is a member, with its wallet, members and the user's role. Other accounts are reported as not found
+//	@Tags			businesses
+//	@Accept			json
+//	@Produce		json
+//	@Security		BearerAuth
+//	@Param			id	path		int	true	"Business account ID"
+//	@Success		200	{object}	dto.APIResponse{data=dto.BusinessAccountResponse}
+//	@Failure		400	{object}	dto.ErrorResponse
+//	@Failure		401	{object}	dto.ErrorResponse
+//	@Failure		404	{object}	dto.ErrorResponse
+//	@Failure		500	{object}	dto.ErrorResponse
+//	@Router			/businesses/{id} [get]
+func (h *BusinessHandler) GetBusinessAccount(c *gin.Context) {
+	userID, accountID, ok := h.accountParams(c)
+	if !ok {
+		return
+	}
+
+	account, role, err := h.businessUseCase.GetBusinessAccount(c.Request.Context(), userID, accountID)
+	if err != nil {
+		c.Error(err).SetMeta("business.account_retrieve_failed")
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.APIResponse{
+		Success: true,
+		Message: middleware.Translate(c, "business.account_retrieved"),
+		Data:    dto.ToBusinessAccountResponse(account, role),
+	})
+}
+
+// GetTransactionHistory godoc
+//
+//	@Summary		Get business transaction history
+//	@Description	Retrieve cursor-paginated transaction history of a business wallet; every member may view it
+//	@Tags			businesses
+//	@Accept			json
+//	@Produce		json
+//	@Security		BearerAuth
+//	@Param			id		path		int		true	"Business account ID"
+//	@Param			cursor	query		string	false	"Cursor for pagination"
+//	@Param			limit	query		int		false	"Page size"	default(20)
+//	@Success		200		{object}	dto.APIResponse{data=dto.TransactionHistoryResponse}
+//	@Header			200		{string}	Link	"RFC 5988 links to the next page, and to the first once paged"
+//	@Failure		400		{object}	dto.ErrorResponse
+//	@Failure		401		{object}	dto.ErrorResponse
+//	@Failure		404		{object}	dto.ErrorResponse
+//	@Failure		500		{object}	dto.ErrorResponse
+//	@Router			/businesses/{id}/transactions [get]
+func (h *BusinessHandler) GetTransactionHistory(c *gin.Context) {
+	userID, accountID, ok := h.accountParams(c)
+	if !ok {
+		return
+	}
+
+	account, _, err := h.businessUseCase.GetBusinessAccount(c.Request.Context(), userID, accountID)
+	if err != nil {
+		c.Error(err).SetMeta("business.account_retrieve_failed")
+		return
+	}
+
+	limit := 20
+	if l := c.Query("limit"); l != "" {
+		if parsed, err := strconv.Atoi(l); err == nil && parsed > 0 && parsed <= 100 {
+			limit = parsed
+		}
+	}
+	var cursor *string
+	if value := c.Query("cursor"); value != "" {
+		cursor = &value
+	}
+
+	transactions, nextCursor, err := h.walletUseCase.GetTransactionHistory(c.Request.Context(), account.WalletID, cursor, limit)
+	if err != nil {
+		c.Error(err).SetMeta("business.transactions_retrieve_failed")
+		return
+	}
+
+	transactionResponses := make([]dto.TransactionResponse, len(transactions))
+	for i, tx := range transactions {
+		transactionResponses[i] = dto.ToTransactionResponse(&tx)
+	}
+
+	setCursorLinks(c, nextCursor)
+	c.JSON(http.StatusOK, dto.APIResponse{
+		Success: true,
+		Message: middleware.Translate(c, "business.transactions_retrieved"),
+		Data: dto.TransactionHistoryResponse{
+			Transactions: transactionResponses,
+			Pagination: dto.CursorPaginationMeta{
+				PageSize:    limit,
+				NextCursor:  nextCursor,
+				HasNextPage: nextCursor != nil && *nextCursor != "",
+			},
+		},
+	})
+}
+
+// AddMember godoc
+//
+//	@Summary		Add a business account member
+//	@Description	Give a registered user a role on a business account (owner only). Viewers see the wallet and its approvals, initiators also request payments and approvers also pay and decide on requests
+//	@Tags			businesses
+//	@Accept			json
+//	@Produce		json
+//	@Security		BearerAuth
+//	@Param			id		path		int							true	"Business account ID"
+//	@Param			request	body		dto.BusinessMemberRequest	true	"Member"
+//	@Success		201		{object}	dto.APIResponse{data=dto.BusinessMemberResponse}
+//	@Failure		400		{object}	dto.ErrorResponse
+//	@Failure		401		{object}	dto.ErrorResponse
+//	@Failure		403		{object}	dto.ErrorResponse
+//	@Failure		404		{object}	dto.ErrorResponse
+//	@Failure		409		{object}	dto.ErrorResponse	"The user is already a member"
+//	@Failure		500		{object}	dto.ErrorResponse
+//	@Router			/businesses/{id}/members [post]
+func (h *BusinessHandler) AddMember(c *gin.Context) {
+	userID, accountID, ok := h.accountParams(c)
+	if !ok {
+		return
+	}
+
+	var req dto.BusinessMemberRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.Error(apperrors.ErrValidation.Withf("invalid request data: %w", err)).SetMeta("request.invalid")
+		return
+	}
+
+	member, err := h.businessUseCase.AddMember(c.Request.Context(), userID, accountID, req.Email, models.BusinessRole(req.Role))
+	if err != nil {
+		c.Error(err).SetMeta("business.member_add_failed")
+		return
+	}
+
+	c.JSON(http.StatusCreated, dto.APIResponse{
+		Success: true,
+		Message: middleware.Translate(c, "business.member_added"),
+		Data:    dto.ToBusinessMemberResponse(member),
+	})
+}
+
+// UpdateMember godoc
+//
+//	@Summary		Change a member's role
+//	@Description	Change the role of a member of a business account other than the owner (owner only)
+//	@Tags			businesses
+//	@Accept			json
+//	@Produce		json
+//	@Security		BearerAuth
+//	@Param			id		path		int								true	"Business account ID"
+//	@Param			user_id	path		int								true	"Member user ID"
+//	@Param			request	body		dto.UpdateBusinessMemberRequest	true	"Role"
+//	@Success		200		{object}	dto.APIResponse{data=dto.BusinessMemberResponse}
+//	@Failure		400		{object}	dto.ErrorResponse
+//	@Failure		401		{object}	dto.ErrorResponse
+//	@Failure		403		{object}	dto.ErrorResponse
+//	@Failure		404		{object}	dto.ErrorResponse
+//	@Failure		500		{object}	dto.ErrorResponse
+//	@Router			/businesses/{id}/members/{user_id} [put]
+func (h *BusinessHandler) UpdateMember(c *gin.Context) {
+	userID, accountID, ok := h.accountParams(c)
+	if !ok {
+		return
+	}
+
+	memberUserID, err := parseIDParam(c, "user_id")
+	if err != nil {
+		c.Error(err).SetMeta("business.invalid_member_user_id")
+		return
+	}
+
+	var req dto.UpdateBusinessMemberRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.Error(apperrors.ErrValidation.Withf("invalid request data: %w", err)).SetMeta("request.invalid")
+		return
+	}
+
+	member, err := h.businessUseCase.UpdateMemberRole(c.Request.Context(), userID, accountID, memberUserID, models.BusinessRole(req.Role))
+	if err != nil {
+		c.Error(err).SetMeta("business.member_update_failed")
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.APIResponse{
+		Success: true,
+		Message: middleware.Translate(c, "business.member_updated"),
+		Data:    dto.ToBusinessMemberResponse(member),
+	})
+}
+
+// RemoveMember godoc
+//
+//	@Summary		Remove a business account member
+//	@Description	Take away the access of a member of a business account other than the owner (owner only). Their pending requests stay for approvers to decide on
+//	@Tags			businesses
+//	@Accept			json
+//	@Produce		json
+//	@Security		BearerAuth
+//	@Param			id		path		int	true	"Business account ID"
+//	@Param			user_id	path		int	true	"Member user ID"
+//	@Success		200		{object}	dto.APIResponse
+//	@Failure		400		{object}	dto.ErrorResponse
+//	@Failure		401		{object}	dto.ErrorResponse
+//	@Failure		403		{object}	dto.ErrorResponse
+//	@Failure		404		{object}	dto.ErrorResponse
+//	@Failure		500		{object}	dto.ErrorResponse
+//	@Router			/businesses/{id}/members/{user_id} [delete]
+func (h *BusinessHandler) RemoveMember(c *gin.Context) {
+	userID, accountID, ok := h.accountParams(c)
+	if !ok {
+		return
+	}
+
+	memberUserID, err := parseIDParam(c, "user_id")
+	if err != nil {
+		c.Error(err).SetMeta("business.invalid_member_user_id")
+		return
+	}
+
+	if err := h.businessUseCase.RemoveMember(c.Request.Context(), userID, accountID, memberUserID); err != nil {
+		c.Error(err).SetMeta("business.member_remove_failed")
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.APIResponse{
+		Success: true,
+		Message: middleware.Translate(c, "business.member_removed"),
+	})
+}
+
+// RequestPayment godoc
+//
+//	@Summary		Pay from a business wallet
+//	@Description	Transfer to a wallet or, with a bank account, withdraw from a business wallet. Payments of approvers are recorded and made at once, with the approver's transaction PIN when they set one and the amount is above the PIN threshold; payments of initiators wait as PENDING until another approver approves them, and nothing is debited before then. A payment the wallet cannot make is recorded as FAILED with the reason. Viewers cannot pay
+//	@Tags			businesses
+//	@Accept			json
+//	@Produce		json
+//	@Security		BearerAuth
+//	@Param			id		path		int							true	"Business account ID"
+//	@Param			request	body		dto.BusinessPaymentRequest	true	"Payment"
+//	@Success		201		{object}	dto.APIResponse{data=dto.PaymentApprovalResponse}	"Made, or awaiting approval when the status is PENDING"
+//	@Failure		400		{object}	dto.ErrorResponse
+//	@Failure		401		{object}	dto.ErrorResponse
+//	@Failure		403		{object}	dto.ErrorResponse	"Viewers cannot pay, or a missing, wrong or locked transaction PIN (codes PIN_REQUIRED, INVALID_PIN, PIN_LOCKED)"
+//	@Failure		404		{object}	dto.ErrorResponse
+//	@Failure		409		{object}	dto.ErrorResponse	"Duplicate reference or insufficient funds"
+//	@Failure		500		{object}	dto.ErrorResponse
+//	@Router			/businesses/{id}/payments [post]
+func (h *BusinessHandler) RequestPayment(c *gin.Context) {
+	userID, accountID, ok := h.accountParams(c)
+	if !ok {
+		return
+	}
+
+	if rejectSandbox(c) {
+		return
+	}
+
+	var req dto.BusinessPaymentRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.Error(apperrors.ErrValidation.Withf("invalid request data: %w", err)).SetMeta("request.invalid")
+		return
+	}
+
+	if req.Amount.LessThanOrEqual(decimal.Zero) {
+		c.Error(apperrors.ErrInvalidAmount)
+		return
+	}
+
+	payment := usecases.BusinessPayment{
+		ToWalletID:  req.ToWalletID,
+		Amount:      req.Amount,
+		Reference:   req.Reference,
+		Description: req.Description,
+		PIN:         req.PIN,
+	}
+	if req.BankAccount != nil {
+		payment.BankAccount = &models.BankAccount{
+			BankCode:      req.BankAccount.BankCode,
+			AccountNumber: req.BankAccount.AccountNumber,
+			AccountName:   req.BankAccount.AccountName,
+		}
+	}
+
+	approval, err := h.businessUseCase.RequestPayment(c.Request.Context(), userID, accountID, payment, middleware.GetOrigin(c))
+	if err != nil {
+		c.Error(err).SetMeta("business.payment_make_failed")
+		return
+	}
+
+	message := middleware.Translate(c, "business.payment_made")
+	if approval.IsPending() {
+		message = middleware.Translate(c, "business.payment_awaiting_approval")
+	}
+	c.JSON(http.StatusCreated, dto.APIResponse{
+		Success: true,
+		Message: message,
+		Data:    dto.ToPaymentApprovalResponse(approval),
+	})
+}
+
+// ListApprovals godoc
+//
+//	@Summary		List payment approvals
+//	@Description	List the payments of a business wallet with their decisions, newest first; every member may view them
+//	@Tags			businesses
+//	@Accept			json
+//	@Produce		json
+//	@Security		BearerAuth
+//	@Param			id			path		int		true	"Business account ID"
+//	@Param			status		query		string	false	"Approval status (PENDING, APPROVED, REJECTED, FAILED)"
+//	@Param			page		query		int		false	"Page number"	default(1)
+//	@Param			page_size	query		int		false	"Page size"		default(20)
+//	@Success		200			{object}	dto.APIResponse{data=[]dto.PaymentApprovalResponse}
+//	@Failure		400			{object}	dto.ErrorResponse
+//	@Failure		401			{object}	dto.ErrorResponse
+//	@Failure		404			{object}	dto.ErrorResponse
+//	@Failure		500			{object}	dto.ErrorResponse
+//	@Router			/businesses/{id}/approvals [get]
+func (h *BusinessHandler) ListApprovals(c *gin.Context) {
+	userID, accountID, ok := h.accountParams(c)
+	if !ok {
+		return
+	}
+
+	status := models.PaymentApprovalStatus(strings.ToUpper(c.Query("status")))
+	switch status {
+	case "", models.PaymentApprovalStatusPending, models.PaymentApprovalStatusApproved,
+		models.PaymentApprovalStatusRejected, models.PaymentApprovalStatusFailed:
+	default:
+		c.Error(apperrors.ErrValidation.Withf("unknown payment approval status")).SetMeta("business.invalid_status")
+		return
+	}
+
+	page, pageSize := parsePagination(c)
+	approvals, err := h.businessUseCase.ListApprovals(c.Request.Context(), userID, accountID, status, page, pageSize)
+	if err != nil {
+		c.Error(err).SetMeta("business.payment_approvals_retrieve_failed")
+		return
+	}
+
+	responses := make([]dto.PaymentApprovalResponse, len(approvals))
+	for i := range approvals {
+		responses[i] = dto.ToPaymentApprovalResponse(&approvals[i])
+	}
+
+	c.JSON(http.StatusOK, dto.APIResponse{
+		Success: true,
+		Message: middleware.Translate(c, "business.payment_approvals_retrieved"),
+		Data:    responses,
+	})
+}
+
+// ApprovePayment godoc
+//
+//	@Summary		Approve a payment
+//	@Description	Approve and make a pending payment requested by another member (approvers only). Approvers who set a transaction PIN send it with amounts above the PIN threshold. A payment the wallet cannot make, such as for insufficient funds, is recorded as FAILED with the reason
+//	@Tags			businesses
+//	@Accept			json
+//	@Produce		json
+//	@Security		BearerAuth
+//	@Param			id			path		int							true	"Business account ID"
+//	@Param			approval_id	path		int							true	"Payment approval ID"
+//	@Param			request		body		dto.PaymentDecisionRequest	false	"Decision note and transaction PIN"
+//	@Success		200			{object}	dto.APIResponse{data=dto.PaymentApprovalResponse}
+//	@Failure		400			{object}	dto.ErrorResponse
+//	@Failure		401			{object}	dto.ErrorResponse
+//	@Failure		403			{object}	dto.ErrorResponse	"Not an approver, the approver's own request, or a missing, wrong or locked transaction PIN"
+//	@Failure		404			{object}	dto.ErrorResponse
+//	@Failure		409			{object}	dto.ErrorResponse	"Already decided or insufficient funds"
+//	@Failure		500			{object}	dto.ErrorResponse
+//	@Router			/businesses/{id}/approvals/{approval_id}/approve [post]
+func (h *BusinessHandler) ApprovePayment(c *gin.Context) {
+	if rejectSandbox(c) {
+		return
+	}
+	h.decide(c, "business.payment_approve_failed", "business.payment_approved", func(userID, accountID, approvalID uint, req dto.PaymentDecisionRequest) (*models.PaymentApproval, error) {
+		return h.businessUseCase.ApprovePayment(c.Request.Context(), userID, accountID, approvalID, req.Note, req.PIN, middleware.GetOrigin(c))
+	})
+}
+
+// RejectPayment godoc
+//
+//	@Summary		Reject a payment
+//	@Description	Decline a pending payment requested by another member (approvers only); nothing is debited
+//	@Tags			businesses
+//	@Accept			json
+//	@Produce		json
+//	@Security		BearerAuth
+//	@Param			id			path		int							true	"Business account ID"
+//	@Param			approval_id	path		int							true	"Payment approval ID"
+//	@Param			request		body		dto.PaymentDecisionRequest	false	"Decision note"
+//	@Success		200			{object}	dto.APIResponse{data=dto.PaymentApprovalResponse}
+//	@Failure		400			{object}	dto.ErrorResponse
+//	@Failure		401			{object}	dto.ErrorResponse
+//	@Failure		403			{object}	dto.ErrorResponse	"Not an approver, or the approver's own request"
+//	@Failure		404			{object}	dto.ErrorResponse
+//	@Failure		409			{object}	dto.ErrorResponse	"Already decided"
+//	@Failure		500			{object}	dto.ErrorResponse
+//	@Router			/businesses/{id}/approvals/{approval_id}/reject [post]
+func (h *BusinessHandler) RejectPayment(c *gin.Context) {
+	h.decide(c, "business.payment_reject_failed", "business.payment_rejected", func(userID, accountID, approvalID uint, req dto.PaymentDecisionRequest) (*models.PaymentApproval, error) {
+		return h.businessUseCase.RejectPayment(c.Request.Context(), userID, accountID, approvalID, req.Note)
+	})
+}
+
+// decide records the authenticated approver's decision on a pending payment
+func (h *BusinessHandler) decide(c *gin.Context, failureKey, successKey string, action func(userID, accountID, approvalID uint, req dto.PaymentDecisionRequest) (*models.PaymentApproval, error)) {
+	userID, accountID, ok := h.accountParams(c)
+	if !ok {
+		return
+	}
+
+	approvalID, err := parseIDParam(c, "approval_id")
+	if err != nil {
+		c.Error(err).SetMeta("business.invalid_payment_approval_id")
+		return
+	}
+
+	// The note and the PIN are optional, so an empty body is accepted
+	var req dto.PaymentDecisionRequest
+	if c.Request.ContentLength > 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.Error(apperrors.ErrValidation.Withf("invalid request data: %w", err)).SetMeta("request.invalid")
+			return
+		}
+	}
+
+	approval, err := action(userID, accountID, approvalID, req)
+	if err != nil {
+		c.Error(err).SetMeta(failureKey)
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.APIResponse{
+		Success: true,
+		Message: middleware.Translate(c, successKey),
+		Data:    dto.ToPaymentApprovalResponse(approval),
+	})
+}
+
+// accountParams reads the authenticated user and the business account ID of the path, writing the
+// error response when either is missing
+func (h *BusinessHandler) accountParams(c *gin.Context) (userID, accountID uint, ok bool) {
+	userID, exists := middleware.GetUserID(c)
+	if !exists {
+		c.Error(apperrors.ErrUnauthenticated)
+		return 0, 0, false
+	}
+
+	accountID, err := parseIDParam(c, "id")
+	if err != nil {
+		c.Error(err).SetMeta("business.invalid_account_id")
+		return 0, 0, false
+	}
+	return userID, accountID, true
+}
@@ -0,0 +1,46 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/limistah/wallet-service/internal/breaker"
+	"github.com/limistah/wallet-service/internal/dto"
+	"github.com/limistah/wallet-service/internal/middleware"
+)
+
+type CircuitBreakerHandler struct {
+	breakers *breaker.Registry
+}
+
+func NewCircuitBreakerHandler(breakers *breaker.Registry) *CircuitBreakerHandler {
+	return &CircuitBreakerHandler{
+		breakers: breakers,
+	}
+}
+
+// ListCircuitBreakers godoc
+//
+//	@Summary		List circuit breakers
+//	@Description	Report the state and call counters of the circuit breakers around payment providers and email, SMS and push senders (admin and support)
+//	@Tags			admin
+//	@Accept			json
+//	@Produce		json
+//	@Security		BearerAuth
+//	@Success		200	{object}	dto.APIResponse{data=[]dto.CircuitBreakerResponse}
+//	@Failure		401	{object}	dto.ErrorResponse
+//	@Failure		403	{object}	dto.ErrorResponse
+//	@Router			/admin/circuit-breakers [get]
+func (h *CircuitBreakerHandler) ListCircuitBreakers(c *gin.Context) {
+	stats := h.breakers.Stats()
+	responses := make([]dto.CircuitBreakerResponse, len(stats))
+	for i, s := range stats {
+		responses[i] = dto.ToCircuitBreakerResponse(s)
+	}
+
+	c.JSON(http.StatusOK, dto.APIResponse{
+		Success: true,
+		Message: middleware.Translate(c, "circuit_breaker.list_retrieved"),
+		Data:    responses,
+	})
+}
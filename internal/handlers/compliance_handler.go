@@ -0,0 +1,185 @@
+package handlers
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/limistah/wallet-service/internal/apperrors"
+	"github.com/limistah/wallet-service/internal/dto"
+	"github.com/limistah/wallet-service/internal/middleware"
+	"github.com/limistah/wallet-service/internal/models"
+	"github.com/limistah/wallet-service/internal/usecases"
+)
+
+type ComplianceHandler struct {
+	complianceUseCase usecases.ComplianceUseCase
+}
+
+func NewComplianceHandler(complianceUseCase usecases.ComplianceUseCase) *ComplianceHandler {
+	return &ComplianceHandler{
+		complianceUseCase: complianceUseCase,
+	}
+}
+
+// ListBlocklistEntries godoc
+//
+//	@Summary		List blocklist entries
+//	@Description	List blocklisted emails, wallets and bank accounts, newest first (admin and support)
+//	@Tags			admin
+//	@Accept			json
+//	@Produce		json
+//	@Security		BearerAuth
+//	@Param			type		query		string	false	"Entry type (EMAIL, WALLET, BANK_ACCOUNT)"
+//	@Param			page		query		int		false	"Page number"	default(1)
+//	@Param			page_size	query		int		false	"Page size"		default(20)
+//	@Success		200			{object}	dto.APIResponse{data=[]dto.BlocklistEntryResponse}
+//	@Failure		400			{object}	dto.ErrorResponse
+//	@Failure		401			{object}	dto.ErrorResponse
+//	@Failure		403			{object}	dto.ErrorResponse
+//	@Failure		500			{object}	dto.ErrorResponse
+//	@Router			/admin/blocklist [get]
+func (h *ComplianceHandler) ListBlocklistEntries(c *gin.Context) {
+	entryType := models.BlocklistEntryType(strings.ToUpper(c.Query("type")))
+	switch entryType {
+	case "", models.BlocklistEntryEmail, models.BlocklistEntryWallet, models.BlocklistEntryBankAccount:
+	default:
+		c.Error(apperrors.ErrValidation.Withf("invalid type")).SetMeta("compliance.invalid_type")
+		return
+	}
+
+	page, pageSize := parsePagination(c)
+	entries, err := h.complianceUseCase.ListBlocklistEntries(c.Request.Context(), entryType, page, pageSize)
+	if err != nil {
+		c.Error(err).SetMeta("compliance.blocklist_entries_retrieve_failed")
+		return
+	}
+
+	responses := make([]dto.BlocklistEntryResponse, len(entries))
+	for i, entry := range entries {
+		responses[i] = dto.ToBlocklistEntryResponse(&entry)
+	}
+
+	c.JSON(http.StatusOK, dto.APIResponse{
+		Success: true,
+		Message: middleware.Translate(c, "compliance.blocklist_entries_retrieved"),
+		Data:    responses,
+	})
+}
+
+// CreateBlocklistEntry godoc
+//
+//	@Summary		Blocklist an email, wallet or bank account
+//	@Description	Stop transfers and withdrawals to or from a user email, wallet ID or bank account (admin only)
+//	@Tags			admin
+//	@Accept			json
+//	@Produce		json
+//	@Security		BearerAuth
+//	@Param			request	body		dto.BlocklistEntryRequest	true	"Blocklist entry"
+//	@Success		201		{object}	dto.APIResponse{data=dto.BlocklistEntryResponse}
+//	@Failure		400		{object}	dto.ErrorResponse
+//	@Failure		401		{object}	dto.ErrorResponse
+//	@Failure		403		{object}	dto.ErrorResponse
+//	@Failure		409		{object}	dto.ErrorResponse	"Value already blocklisted"
+//	@Failure		500		{object}	dto.ErrorResponse
+//	@Router			/admin/blocklist [post]
+func (h *ComplianceHandler) CreateBlocklistEntry(c *gin.Context) {
+	adminID, exists := middleware.GetUserID(c)
+	if !exists {
+		c.Error(apperrors.ErrUnauthenticated)
+		return
+	}
+
+	var req dto.BlocklistEntryRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.Error(apperrors.ErrValidation.Withf("invalid request data: %w", err)).SetMeta("request.invalid")
+		return
+	}
+
+	entry, err := h.complianceUseCase.CreateBlocklistEntry(c.Request.Context(), &models.BlocklistEntry{
+		Type:        models.BlocklistEntryType(req.Type),
+		Value:       req.Value,
+		Reason:      strings.TrimSpace(req.Reason),
+		CreatedByID: adminID,
+	})
+	if err != nil {
+		c.Error(err).SetMeta("compliance.blocklist_entry_create_failed")
+		return
+	}
+
+	c.JSON(http.StatusCreated, dto.APIResponse{
+		Success: true,
+		Message: middleware.Translate(c, "compliance.blocklist_entry_created"),
+		Data:    dto.ToBlocklistEntryResponse(entry),
+	})
+}
+
+// DeleteBlocklistEntry godoc
+//
+//	@Summary		Remove a blocklist entry
+//	@Description	Allow a previously blocklisted email, wallet or bank account to transact again (admin only)
+//	@Tags			admin
+//	@Accept			json
+//	@Produce		json
+//	@Security		BearerAuth
+//	@Param			id	path		int	true	"Blocklist entry ID"
+//	@Success		200	{object}	dto.APIResponse
+//	@Failure		400	{object}	dto.ErrorResponse
+//	@Failure		401	{object}	dto.ErrorResponse
+//	@Failure		403	{object}	dto.ErrorResponse
+//	@Failure		404	{object}	dto.ErrorResponse
+//	@Failure		500	{object}	dto.ErrorResponse
+//	@Router			/admin/blocklist/{id} [delete]
+func (h *ComplianceHandler) DeleteBlocklistEntry(c *gin.Context) {
+	entryID, err := parseIDParam(c, "id")
+	if err != nil {
+		c.Error(err).SetMeta("compliance.invalid_blocklist_entry_id")
+		return
+	}
+
+	if err := h.complianceUseCase.DeleteBlocklistEntry(c.Request.Context(), entryID); err != nil {
+		c.Error(err).SetMeta("compliance.blocklist_entry_remove_failed")
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.APIResponse{
+		Success: true,
+		Message: middleware.Translate(c, "compliance.blocklist_entry_removed"),
+	})
+}
+
+// ListComplianceLogs godoc
+//
+//	@Summary		List compliance log
+//	@Description	List operations refused by compliance controls such as the blocklist, AML and sanctions screening, newest first (admin and support)
+//	@Tags			admin
+//	@Accept			json
+//	@Produce		json
+//	@Security		BearerAuth
+//	@Param			check		query		string	false	"Compliance control (BLOCKLIST, AML, SANCTIONS)"
+//	@Param			page		query		int		false	"Page number"	default(1)
+//	@Param			page_size	query		int		false	"Page size"		default(20)
+//	@Success		200			{object}	dto.APIResponse{data=[]dto.ComplianceLogResponse}
+//	@Failure		401			{object}	dto.ErrorResponse
+//	@Failure		403			{object}	dto.ErrorResponse
+//	@Failure		500			{object}	dto.ErrorResponse
+//	@Router			/admin/compliance-logs [get]
+func (h *ComplianceHandler) ListComplianceLogs(c *gin.Context) {
+	page, pageSize := parsePagination(c)
+	entries, err := h.complianceUseCase.ListComplianceLogs(c.Request.Context(), models.ComplianceCheck(strings.ToUpper(c.Query("check"))), page, pageSize)
+	if err != nil {
+		c.Error(err).SetMeta("compliance.log_retrieve_failed")
+		return
+	}
+
+	responses := make([]dto.ComplianceLogResponse, len(entries))
+	for i, entry := range entries {
+		responses[i] = dto.ToComplianceLogResponse(&entry)
+	}
+
+	c.JSON(http.StatusOK, dto.APIResponse{
+		Success: true,
+		Message: middleware.Translate(c, "compliance.log_retrieved"),
+		Data:    responses,
+	})
+}
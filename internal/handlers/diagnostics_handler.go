@@ -0,0 +1,73 @@
+package handlers
+
+import (
+	"expvar"
+	"net/http/pprof"
+
+	"github.com/gin-gonic/gin"
+
+	// The error responses of these endpoints are documented with dto types
+	_ "github.com/limistah/wallet-service/internal/dto"
+)
+
+// ListProfiles godoc
+//
+//	@Summary		List runtime profiles
+//	@Description	Index of the Go runtime profiles of this instance, for diagnosing memory and CPU issues in production (admin only)
+//	@Tags			admin
+//	@Produce		html
+//	@Security		BearerAuth
+//	@Success		200
+//	@Failure		401	{object}	dto.ErrorResponse
+//	@Failure		403	{object}	dto.ErrorResponse
+//	@Router			/admin/debug/pprof/ [get]
+func ListProfiles(c *gin.Context) {
+	pprof.Index(c.Writer, c.Request)
+}
+
+// GetProfile godoc
+//
+//	@Summary		Get a runtime profile
+//	@Description	Take a profile of this instance: cmdline, profile (CPU, for the given seconds), symbol, trace, or a named profile such as heap, goroutine, allocs, block or mutex (admin only)
+//	@Tags			admin
+//	@Produce		octet-stream
+//	@Security		BearerAuth
+//	@Param			profile	path	string	true	"Profile name"
+//	@Param			seconds	query	int		false	"Duration of CPU profiles and traces"
+//	@Param			debug	query	int		false	"1 renders named profiles as text"
+//	@Success		200
+//	@Failure		401	{object}	dto.ErrorResponse
+//	@Failure		403	{object}	dto.ErrorResponse
+//	@Failure		404
+//	@Router			/admin/debug/pprof/{profile} [get]
+//	@Router			/admin/debug/pprof/{profile} [post]
+func GetProfile(c *gin.Context) {
+	// pprof.Index only finds named profiles under /debug/pprof/, so the names are dispatched here
+	switch name := c.Param("profile"); name {
+	case "cmdline":
+		pprof.Cmdline(c.Writer, c.Request)
+	case "profile":
+		pprof.Profile(c.Writer, c.Request)
+	case "symbol":
+		pprof.Symbol(c.Writer, c.Request)
+	case "trace":
+		pprof.Trace(c.Writer, c.Request)
+	default:
+		pprof.Handler(name).ServeHTTP(c.Writer, c.Request)
+	}
+}
+
+// GetRuntimeVars godoc
+//
+//	@Summary		Get runtime variables
+//	@Description	Memory statistics, the command line and other variables published with expvar by this instance (admin only)
+//	@Tags			admin
+//	@Produce		json
+//	@Security		BearerAuth
+//	@Success		200	{object}	map[string]interface{}
+//	@Failure		401	{object}	dto.ErrorResponse
+//	@Failure		403	{object}	dto.ErrorResponse
+//	@Router			/admin/debug/vars [get]
+func GetRuntimeVars(c *gin.Context) {
+	expvar.Handler().ServeHTTP(c.Writer, c.Request)
+}
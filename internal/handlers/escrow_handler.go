@@ -0,0 +1,215 @@
+package handlers
+
+import (
+	"context"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/limistah/wallet-service/internal/apperrors"
+	"github.com/limistah/wallet-service/internal/dto"
+	"github.com/limistah/wallet-service/internal/middleware"
+	"github.com/limistah/wallet-service/internal/models"
+	"github.com/limistah/wallet-service/internal/usecases"
+	"github.com/shopspring/decimal"
+)
+
+type EscrowHandler struct {
+	escrowUseCase usecases.EscrowUseCase
+}
+
+func NewEscrowHandler(escrowUseCase usecases.EscrowUseCase) *EscrowHandler {
+	return &EscrowHandler{
+		escrowUseCase: escrowUseCase,
+	}
+}
+
+// CreateEscrow godoc
+//
+//	@Summary		Hold funds in escrow
+//	@Description	Move an amount from the authenticated user's wallet to the escrow account for another user. The payer releases it to the payee, for instance once the condition is met; cancelled escrows and escrows still held at expiry return to the payer. Users who set a transaction PIN send it with amounts above the PIN threshold. Amounts above the step-up threshold are refused; they are paid as transfers confirmed with a one-time code
+//	@Tags			escrows
+//	@Accept			json
+//	@Produce		json
+//	@Security		BearerAuth
+//	@Param			request	body		dto.CreateEscrowRequest	true	"Escrow"
+//	@Success		201		{object}	dto.APIResponse{data=dto.EscrowResponse}
+//	@Failure		400		{object}	dto.ErrorResponse
+//	@Failure		401		{object}	dto.ErrorResponse
+//	@Failure		403		{object}	dto.ErrorResponse	"Missing, wrong or locked transaction PIN (codes PIN_REQUIRED, INVALID_PIN, PIN_LOCKED), or refused by a fraud rule, the compliance blocklist or AML screening"
+//	@Failure		404		{object}	dto.ErrorResponse
+//	@Failure		409		{object}	dto.ErrorResponse	"Insufficient funds"
+//	@Failure		500		{object}	dto.ErrorResponse
+//	@Router			/escrows [post]
+func (h *EscrowHandler) CreateEscrow(c *gin.Context) {
+	userID, exists := middleware.GetUserID(c)
+	if !exists {
+		c.Error(apperrors.ErrUnauthenticated)
+		return
+	}
+
+	if rejectSandbox(c) {
+		return
+	}
+
+	var req dto.CreateEscrowRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.Error(apperrors.ErrValidation.Withf("invalid request data: %w", err)).SetMeta("request.invalid")
+		return
+	}
+
+	if req.Amount.LessThanOrEqual(decimal.Zero) {
+		c.Error(apperrors.ErrInvalidAmount)
+		return
+	}
+
+	escrow, err := h.escrowUseCase.CreateEscrow(c.Request.Context(), userID, req.PayeeEmail, req.Amount, req.Condition, req.ExpiresAt, req.PIN, middleware.GetOrigin(c))
+	if err != nil {
+		c.Error(err).SetMeta("escrow.create_failed")
+		return
+	}
+
+	c.JSON(http.StatusCreated, dto.APIResponse{
+		Success: true,
+		Message: middleware.Translate(c, "escrow.created"),
+		Data:    dto.ToEscrowResponse(escrow),
+	})
+}
+
+// ListEscrows godoc
+//
+//	@Summary		List escrows
+//	@Description	List escrows the authenticated user pays or is paid by, newest first
+//	@Tags			escrows
+//	@Accept			json
+//	@Produce		json
+//	@Security		BearerAuth
+//	@Param			status		query		string	false	"Escrow status (HELD, RELEASED, CANCELLED, EXPIRED)"
+//	@Param			page		query		int		false	"Page number"	default(1)
+//	@Param			page_size	query		int		false	"Page size"		default(20)
+//	@Success		200			{object}	dto.APIResponse{data=[]dto.EscrowResponse}
+//	@Failure		400			{object}	dto.ErrorResponse
+//	@Failure		401			{object}	dto.ErrorResponse
+//	@Failure		500			{object}	dto.ErrorResponse
+//	@Router			/escrows [get]
+func (h *EscrowHandler) ListEscrows(c *gin.Context) {
+	userID, exists := middleware.GetUserID(c)
+	if !exists {
+		c.Error(apperrors.ErrUnauthenticated)
+		return
+	}
+
+	status := models.EscrowStatus(strings.ToUpper(c.Query("status")))
+	switch status {
+	case "", models.EscrowStatusHeld, models.EscrowStatusReleased, models.EscrowStatusCancelled, models.EscrowStatusExpired:
+	default:
+		c.Error(apperrors.ErrValidation.Withf("unknown escrow status")).SetMeta("escrow.invalid_status")
+		return
+	}
+
+	page, pageSize := parsePagination(c)
+	escrows, err := h.escrowUseCase.ListEscrows(c.Request.Context(), userID, status, page, pageSize)
+	if err != nil {
+		c.Error(err).SetMeta("escrow.list_failed")
+		return
+	}
+
+	responses := make([]dto.EscrowResponse, len(escrows))
+	for i := range escrows {
+		responses[i] = dto.ToEscrowResponse(&escrows[i])
+	}
+
+	c.JSON(http.StatusOK, dto.APIResponse{
+		Success: true,
+		Message: middleware.Translate(c, "escrow.list_retrieved"),
+		Data:    responses,
+	})
+}
+
+// GetEscrow godoc
+//
+//	@Summary		Get an escrow
+//	@Description	Get an escrow the authenticated user pays or is paid by. Other escrows are reported as not found
+//	@Tags			escrows
+//	@Accept			json
+//	@Produce		json
+//	@Security		BearerAuth
+//	@Param			id	path		int	true	"Escrow ID"
+//	@Success		200	{object}	dto.APIResponse{data=dto.EscrowResponse}
+//	@Failure		400	{object}	dto.ErrorResponse
+//	@Failure		401	{object}	dto.ErrorResponse
+//	@Failure		404	{object}	dto.ErrorResponse
+//	@Failure		500	{object}	dto.ErrorResponse
+//	@Router			/escrows/{id} [get]
+func (h *EscrowHandler) GetEscrow(c *gin.Context) {
+	h.respond(c, "escrow.retrieved", "escrow.retrieve_failed", h.escrowUseCase.GetEscrow)
+}
+
+// ReleaseEscrow godoc
+//
+//	@Summary		Release an escrow
+//	@Description	Pay the held funds to the payee (payer only, before expiry). Amounts above the step-up threshold are refused
+//	@Tags			escrows
+//	@Accept			json
+//	@Produce		json
+//	@Security		BearerAuth
+//	@Param			id	path		int	true	"Escrow ID"
+//	@Success		200	{object}	dto.APIResponse{data=dto.EscrowResponse}
+//	@Failure		400	{object}	dto.ErrorResponse
+//	@Failure		401	{object}	dto.ErrorResponse
+//	@Failure		403	{object}	dto.ErrorResponse
+//	@Failure		404	{object}	dto.ErrorResponse
+//	@Failure		409	{object}	dto.ErrorResponse	"Escrow already settled or expired"
+//	@Failure		500	{object}	dto.ErrorResponse
+//	@Router			/escrows/{id}/release [post]
+func (h *EscrowHandler) ReleaseEscrow(c *gin.Context) {
+	h.respond(c, "escrow.released", "escrow.release_failed", h.escrowUseCase.ReleaseEscrow)
+}
+
+// CancelEscrow godoc
+//
+//	@Summary		Cancel an escrow
+//	@Description	Return the held funds to the payer. The payer may call the deal off and the payee may decline it
+//	@Tags			escrows
+//	@Accept			json
+//	@Produce		json
+//	@Security		BearerAuth
+//	@Param			id	path		int	true	"Escrow ID"
+//	@Success		200	{object}	dto.APIResponse{data=dto.EscrowResponse}
+//	@Failure		400	{object}	dto.ErrorResponse
+//	@Failure		401	{object}	dto.ErrorResponse
+//	@Failure		404	{object}	dto.ErrorResponse
+//	@Failure		409	{object}	dto.ErrorResponse	"Escrow already settled"
+//	@Failure		500	{object}	dto.ErrorResponse
+//	@Router			/escrows/{id}/cancel [post]
+func (h *EscrowHandler) CancelEscrow(c *gin.Context) {
+	h.respond(c, "escrow.cancelled", "escrow.cancel_failed", h.escrowUseCase.CancelEscrow)
+}
+
+// respond runs an action on an escrow of the authenticated user, reporting it with the message keys
+// success and failure
+func (h *EscrowHandler) respond(c *gin.Context, success, failure string, action func(ctx context.Context, userID, escrowID uint) (*models.Escrow, error)) {
+	userID, exists := middleware.GetUserID(c)
+	if !exists {
+		c.Error(apperrors.ErrUnauthenticated)
+		return
+	}
+
+	escrowID, err := parseIDParam(c, "id")
+	if err != nil {
+		c.Error(err).SetMeta("escrow.invalid_id")
+		return
+	}
+
+	escrow, err := action(c.Request.Context(), userID, escrowID)
+	if err != nil {
+		c.Error(err).SetMeta(failure)
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.APIResponse{
+		Success: true,
+		Message: middleware.Translate(c, success),
+		Data:    dto.ToEscrowResponse(escrow),
+	})
+}
@@ -0,0 +1,149 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/limistah/wallet-service/internal/apperrors"
+	"github.com/limistah/wallet-service/internal/dto"
+	"github.com/limistah/wallet-service/internal/middleware"
+	"github.com/limistah/wallet-service/internal/models"
+	"github.com/limistah/wallet-service/internal/usecases"
+)
+
+type FraudReviewHandler struct {
+	walletUseCase usecases.WalletUseCase
+}
+
+func NewFraudReviewHandler(walletUseCase usecases.WalletUseCase) *FraudReviewHandler {
+	return &FraudReviewHandler{
+		walletUseCase: walletUseCase,
+	}
+}
+
+// ListFraudReviews godoc
+//
+//	@Summary		List fraud reviews
+//	@Description	List debits held as PENDING_REVIEW by fraud rules, oldest first (admin and support)
+//	@Tags			admin
+//	@Accept			json
+//	@Produce		json
+//	@Security		BearerAuth
+//	@Param			status		query		string	false	"Review status (PENDING, APPROVED, REJECTED)"	default(PENDING)
+//	@Param			page		query		int		false	"Page number"									default(1)
+//	@Param			page_size	query		int		false	"Page size"										default(20)
+//	@Success		200			{object}	dto.APIResponse{data=[]dto.FraudReviewResponse}
+//	@Failure		400			{object}	dto.ErrorResponse
+//	@Failure		401			{object}	dto.ErrorResponse
+//	@Failure		403			{object}	dto.ErrorResponse
+//	@Failure		500			{object}	dto.ErrorResponse
+//	@Router			/admin/fraud-reviews [get]
+func (h *FraudReviewHandler) ListFraudReviews(c *gin.Context) {
+	status := models.FraudReviewStatus(strings.ToUpper(c.DefaultQuery("status", string(models.FraudReviewStatusPending))))
+	switch status {
+	case models.FraudReviewStatusPending, models.FraudReviewStatusApproved, models.FraudReviewStatusRejected:
+	default:
+		c.Error(apperrors.ErrValidation.Withf("invalid status")).SetMeta("fraud_review.invalid_status")
+		return
+	}
+
+	page, pageSize := parsePagination(c)
+	reviews, err := h.walletUseCase.ListFraudReviews(c.Request.Context(), status, page, pageSize)
+	if err != nil {
+		c.Error(err).SetMeta("fraud_review.list_failed")
+		return
+	}
+
+	responses := make([]dto.FraudReviewResponse, len(reviews))
+	for i, review := range reviews {
+		responses[i] = dto.ToFraudReviewResponse(&review)
+	}
+
+	c.JSON(http.StatusOK, dto.APIResponse{
+		Success: true,
+		Message: middleware.Translate(c, "fraud_review.list_retrieved"),
+		Data:    responses,
+	})
+}
+
+// ApproveFraudReview godoc
+//
+//	@Summary		Approve a held debit
+//	@Description	Release a debit held by a fraud rule: transfers are credited to the recipient and withdrawals are completed or paid out (admin only)
+//	@Tags			admin
+//	@Accept			json
+//	@Produce		json
+//	@Security		BearerAuth
+//	@Param			id		path		int								true	"Fraud review ID"
+//	@Param			request	body		dto.ResolveFraudReviewRequest	false	"Review note"
+//	@Success		200		{object}	dto.APIResponse{data=dto.FraudReviewResponse}
+//	@Failure		400		{object}	dto.ErrorResponse
+//	@Failure		401		{object}	dto.ErrorResponse
+//	@Failure		403		{object}	dto.ErrorResponse
+//	@Failure		404		{object}	dto.ErrorResponse
+//	@Failure		409		{object}	dto.ErrorResponse	"Review already resolved"
+//	@Failure		500		{object}	dto.ErrorResponse
+//	@Failure		502		{object}	dto.ErrorResponse
+//	@Router			/admin/fraud-reviews/{id}/approve [post]
+func (h *FraudReviewHandler) ApproveFraudReview(c *gin.Context) {
+	h.resolve(c, h.walletUseCase.ApproveFraudReview, "fraud_review.approve_failed", "fraud_review.approved")
+}
+
+// RejectFraudReview godoc
+//
+//	@Summary		Reject a held debit
+//	@Description	Fail a debit held by a fraud rule and return the held amount and fee to the wallet (admin only)
+//	@Tags			admin
+//	@Accept			json
+//	@Produce		json
+//	@Security		BearerAuth
+//	@Param			id		path		int								true	"Fraud review ID"
+//	@Param			request	body		dto.ResolveFraudReviewRequest	false	"Review note"
+//	@Success		200		{object}	dto.APIResponse{data=dto.FraudReviewResponse}
+//	@Failure		400		{object}	dto.ErrorResponse
+//	@Failure		401		{object}	dto.ErrorResponse
+//	@Failure		403		{object}	dto.ErrorResponse
+//	@Failure		404		{object}	dto.ErrorResponse
+//	@Failure		409		{object}	dto.ErrorResponse	"Review already resolved"
+//	@Failure		500		{object}	dto.ErrorResponse
+//	@Router			/admin/fraud-reviews/{id}/reject [post]
+func (h *FraudReviewHandler) RejectFraudReview(c *gin.Context) {
+	h.resolve(c, h.walletUseCase.RejectFraudReview, "fraud_review.reject_failed", "fraud_review.rejected")
+}
+
+// resolve records an admin decision on a fraud review; the request body is optional
+func (h *FraudReviewHandler) resolve(c *gin.Context, decide func(ctx context.Context, reviewID, reviewerID uint, note string) (*models.FraudReview, error), failureKey, successKey string) {
+	reviewerID, exists := middleware.GetUserID(c)
+	if !exists {
+		c.Error(apperrors.ErrUnauthenticated)
+		return
+	}
+
+	reviewID, err := parseIDParam(c, "id")
+	if err != nil {
+		c.Error(err).SetMeta("fraud_review.invalid_id")
+		return
+	}
+
+	var req dto.ResolveFraudReviewRequest
+	if err := c.ShouldBindJSON(&req); err != nil && !errors.Is(err, io.EOF) {
+		c.Error(apperrors.ErrValidation.Withf("invalid request data: %w", err)).SetMeta("request.invalid")
+		return
+	}
+
+	review, err := decide(c.Request.Context(), reviewID, reviewerID, strings.TrimSpace(req.Note))
+	if err != nil {
+		c.Error(err).SetMeta(failureKey)
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.APIResponse{
+		Success: true,
+		Message: middleware.Translate(c, successKey),
+		Data:    dto.ToFraudReviewResponse(review),
+	})
+}
@@ -1,11 +1,18 @@
 package handlers
 
 import (
+	"context"
+	"database/sql"
 	"net/http"
+	"time"
 
 	"github.com/gin-gonic/gin"
 )
 
+// readinessPingTimeout bounds the database ping of a readiness check, so a hung database fails the
+// probe instead of holding it until the prober gives up
+const readinessPingTimeout = 2 * time.Second
+
 // HealthCheck godoc
 //
 //	@Summary		Health check
@@ -20,3 +27,50 @@ func HealthCheck(c *gin.Context) {
 		"message": "Server is running",
 	})
 }
+
+type ReadinessHandler struct {
+	db *sql.DB
+}
+
+// NewReadinessHandler creates a readiness check of db; without a database, as with DB_DRIVER=memory,
+// the server is always ready
+func NewReadinessHandler(db *sql.DB) *ReadinessHandler {
+	return &ReadinessHandler{db: db}
+}
+
+// Ready godoc
+//
+//	@Summary		Readiness check
+//	@Description	Check if the server can serve requests: the database answers a ping. The state of the database connection pool is reported either way, unless the server runs without a database
+//	@Tags			health
+//	@Produce		json
+//	@Success		200	{object}	map[string]interface{}
+//	@Failure		503	{object}	map[string]interface{}	"The database cannot be reached"
+//	@Router			/ready [get]
+func (h *ReadinessHandler) Ready(c *gin.Context) {
+	ctx, cancel := context.WithTimeout(c.Request.Context(), readinessPingTimeout)
+	defer cancel()
+
+	if h.db == nil {
+		c.JSON(http.StatusOK, gin.H{"status": "ready"})
+		return
+	}
+
+	status, code := "ready", http.StatusOK
+	if err := h.db.PingContext(ctx); err != nil {
+		status, code = "unavailable", http.StatusServiceUnavailable
+	}
+
+	stats := h.db.Stats()
+	c.JSON(code, gin.H{
+		"status": status,
+		"database": gin.H{
+			"max_open_connections": stats.MaxOpenConnections,
+			"open_connections":     stats.OpenConnections,
+			"in_use":               stats.InUse,
+			"idle":                 stats.Idle,
+			"wait_count":           stats.WaitCount,
+			"wait_duration_ms":     stats.WaitDuration.Milliseconds(),
+		},
+	})
+}
@@ -0,0 +1,95 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/limistah/wallet-service/internal/apperrors"
+	"github.com/limistah/wallet-service/internal/auth"
+	"github.com/limistah/wallet-service/internal/dto"
+	"github.com/limistah/wallet-service/internal/middleware"
+	"github.com/limistah/wallet-service/internal/usecases"
+)
+
+type ImpersonationHandler struct {
+	impersonationUseCase usecases.ImpersonationUseCase
+	jwtService           *auth.JWTService
+}
+
+func NewImpersonationHandler(impersonationUseCase usecases.ImpersonationUseCase, jwtService *auth.JWTService) *ImpersonationHandler {
+	return &ImpersonationHandler{
+		impersonationUseCase: impersonationUseCase,
+		jwtService:           jwtService,
+	}
+}
+
+// ImpersonateUser godoc
+//
+//	@Summary		Impersonate a user
+//	@Description	Mint a short-lived token acting as a user to debug a support case. The token names the staff member in its impersonator claim, is read-only unless an admin asks for write access, cannot be refreshed, and every request made with it is written to the audit log with the reason
+//	@Tags			admin
+//	@Accept			json
+//	@Produce		json
+//	@Security		BearerAuth
+//	@Param			id				path		int							true	"User ID"
+//	@Param			impersonation	body		dto.ImpersonationRequest	true	"Reason and access"
+//	@Success		200				{object}	dto.APIResponse{data=dto.ImpersonationResponse}
+//	@Failure		400				{object}	dto.ErrorResponse
+//	@Failure		401				{object}	dto.ErrorResponse
+//	@Failure		403				{object}	dto.ErrorResponse	"Staff or system account, or write access asked by support staff (code IMPERSONATION_FORBIDDEN)"
+//	@Failure		404				{object}	dto.ErrorResponse
+//	@Failure		500				{object}	dto.ErrorResponse
+//	@Router			/admin/users/{id}/impersonate [post]
+func (h *ImpersonationHandler) ImpersonateUser(c *gin.Context) {
+	staffID, exists := middleware.GetUserID(c)
+	if !exists {
+		c.Error(apperrors.ErrUnauthenticated)
+		return
+	}
+	if _, impersonating := middleware.GetImpersonator(c); impersonating {
+		err := apperrors.ErrImpersonationForbidden.Withf("impersonation tokens cannot start another impersonation")
+		c.Error(err).SetMeta("impersonation.user_impersonate_failed")
+		return
+	}
+
+	userID, err := parseIDParam(c, "id")
+	if err != nil {
+		c.Error(err).SetMeta("impersonation.invalid_user_id")
+		return
+	}
+
+	var req dto.ImpersonationRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.Error(apperrors.ErrValidation.Withf("invalid request data: %w", err)).SetMeta("request.invalid")
+		return
+	}
+
+	grant, err := h.impersonationUseCase.StartImpersonation(c.Request.Context(), staffID, userID, req.WriteAccess)
+	if err != nil {
+		c.Error(err).SetMeta("impersonation.user_impersonate_failed")
+		return
+	}
+
+	token, claims, err := h.jwtService.IssueImpersonationToken(grant.User.ID, grant.User.TenantID, grant.User.Email, string(grant.User.Role), auth.Impersonator{
+		ID:       grant.Staff.ID,
+		Email:    grant.Staff.Email,
+		Role:     string(grant.Staff.Role),
+		Reason:   req.Reason,
+		ReadOnly: grant.ReadOnly,
+	}, grant.TTL)
+	if err != nil {
+		c.Error(err).SetMeta("impersonation.token_generate_failed")
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.APIResponse{
+		Success: true,
+		Message: middleware.Translate(c, "impersonation.started"),
+		Data: dto.ImpersonationResponse{
+			User:      dto.ToUserResponse(grant.User),
+			Token:     token,
+			ExpiresAt: claims.ExpiresAt.Time,
+			ReadOnly:  grant.ReadOnly,
+		},
+	})
+}
@@ -0,0 +1,139 @@
+package handlers
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/limistah/wallet-service/internal/apperrors"
+	"github.com/limistah/wallet-service/internal/dto"
+	"github.com/limistah/wallet-service/internal/middleware"
+	"github.com/limistah/wallet-service/internal/models"
+	"github.com/limistah/wallet-service/internal/usecases"
+)
+
+type IPAllowlistHandler struct {
+	ipAllowlistUseCase usecases.IPAllowlistUseCase
+}
+
+func NewIPAllowlistHandler(ipAllowlistUseCase usecases.IPAllowlistUseCase) *IPAllowlistHandler {
+	return &IPAllowlistHandler{
+		ipAllowlistUseCase: ipAllowlistUseCase,
+	}
+}
+
+// ListIPAllowlist godoc
+//
+//	@Summary		List the admin IP allowlist
+//	@Description	List the CIDR ranges allowed to reach the admin endpoints, from configuration and added at runtime (admin and support)
+//	@Tags			admin
+//	@Accept			json
+//	@Produce		json
+//	@Security		BearerAuth
+//	@Success		200	{object}	dto.APIResponse{data=dto.IPAllowlistResponse}
+//	@Failure		401	{object}	dto.ErrorResponse
+//	@Failure		403	{object}	dto.ErrorResponse
+//	@Failure		500	{object}	dto.ErrorResponse
+//	@Router			/admin/ip-allowlist [get]
+func (h *IPAllowlistHandler) ListIPAllowlist(c *gin.Context) {
+	entries, err := h.ipAllowlistUseCase.ListEntries(c.Request.Context())
+	if err != nil {
+		c.Error(err).SetMeta("ip_allowlist.retrieve_failed")
+		return
+	}
+
+	responses := make([]dto.IPAllowlistEntryResponse, len(entries))
+	for i, entry := range entries {
+		responses[i] = dto.ToIPAllowlistEntryResponse(&entry)
+	}
+
+	c.JSON(http.StatusOK, dto.APIResponse{
+		Success: true,
+		Message: middleware.Translate(c, "ip_allowlist.retrieved"),
+		Data: dto.IPAllowlistResponse{
+			ConfiguredCIDRs: h.ipAllowlistUseCase.StaticCIDRs(c.Request.Context()),
+			Entries:         responses,
+		},
+	})
+}
+
+// CreateIPAllowlistEntry godoc
+//
+//	@Summary		Allow a CIDR range
+//	@Description	Allow clients from a CIDR range or single IP address to reach the admin endpoints; applies right away (admin only)
+//	@Tags			admin
+//	@Accept			json
+//	@Produce		json
+//	@Security		BearerAuth
+//	@Param			request	body		dto.IPAllowlistEntryRequest	true	"Allowed range"
+//	@Success		201		{object}	dto.APIResponse{data=dto.IPAllowlistEntryResponse}
+//	@Failure		400		{object}	dto.ErrorResponse
+//	@Failure		401		{object}	dto.ErrorResponse
+//	@Failure		403		{object}	dto.ErrorResponse
+//	@Failure		409		{object}	dto.ErrorResponse	"Range already allowed, or the first range would not include your own IP address"
+//	@Failure		500		{object}	dto.ErrorResponse
+//	@Router			/admin/ip-allowlist [post]
+func (h *IPAllowlistHandler) CreateIPAllowlistEntry(c *gin.Context) {
+	adminID, exists := middleware.GetUserID(c)
+	if !exists {
+		c.Error(apperrors.ErrUnauthenticated)
+		return
+	}
+
+	var req dto.IPAllowlistEntryRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.Error(apperrors.ErrValidation.Withf("invalid request data: %w", err)).SetMeta("request.invalid")
+		return
+	}
+
+	entry, err := h.ipAllowlistUseCase.CreateEntry(c.Request.Context(), &models.IPAllowlistEntry{
+		CIDR:        req.CIDR,
+		Description: strings.TrimSpace(req.Description),
+		CreatedByID: adminID,
+	}, c.ClientIP())
+	if err != nil {
+		c.Error(err).SetMeta("ip_allowlist.cidr_range_allow_failed")
+		return
+	}
+
+	c.JSON(http.StatusCreated, dto.APIResponse{
+		Success: true,
+		Message: middleware.Translate(c, "ip_allowlist.cidr_range_allowed"),
+		Data:    dto.ToIPAllowlistEntryResponse(entry),
+	})
+}
+
+// DeleteIPAllowlistEntry godoc
+//
+//	@Summary		Remove an allowed CIDR range
+//	@Description	Stop allowing a range added at runtime; ranges from configuration cannot be removed (admin only)
+//	@Tags			admin
+//	@Accept			json
+//	@Produce		json
+//	@Security		BearerAuth
+//	@Param			id	path		int	true	"IP allowlist entry ID"
+//	@Success		200	{object}	dto.APIResponse
+//	@Failure		400	{object}	dto.ErrorResponse
+//	@Failure		401	{object}	dto.ErrorResponse
+//	@Failure		403	{object}	dto.ErrorResponse
+//	@Failure		404	{object}	dto.ErrorResponse
+//	@Failure		409	{object}	dto.ErrorResponse	"The remaining ranges would not include your own IP address"
+//	@Failure		500	{object}	dto.ErrorResponse
+//	@Router			/admin/ip-allowlist/{id} [delete]
+func (h *IPAllowlistHandler) DeleteIPAllowlistEntry(c *gin.Context) {
+	entryID, err := parseIDParam(c, "id")
+	if err != nil {
+		c.Error(err).SetMeta("ip_allowlist.invalid_entry_id")
+		return
+	}
+
+	if err := h.ipAllowlistUseCase.DeleteEntry(c.Request.Context(), entryID, c.ClientIP()); err != nil {
+		c.Error(err).SetMeta("ip_allowlist.cidr_range_remove_failed")
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.APIResponse{
+		Success: true,
+		Message: middleware.Translate(c, "ip_allowlist.cidr_range_removed"),
+	})
+}
@@ -0,0 +1,105 @@
+package handlers
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/limistah/wallet-service/internal/apperrors"
+	"github.com/limistah/wallet-service/internal/dto"
+	"github.com/limistah/wallet-service/internal/middleware"
+	"github.com/limistah/wallet-service/internal/models"
+	"github.com/limistah/wallet-service/internal/usecases"
+)
+
+type JobHandler struct {
+	jobUseCase usecases.JobUseCase
+}
+
+func NewJobHandler(jobUseCase usecases.JobUseCase) *JobHandler {
+	return &JobHandler{
+		jobUseCase: jobUseCase,
+	}
+}
+
+// ListJobs godoc
+//
+//	@Summary		List background jobs
+//	@Description	List post-transaction audits and notification deliveries waiting in the job queue, oldest first. Jobs that failed every attempt stay DEAD until they are retried (admin and support)
+//	@Tags			admin
+//	@Accept			json
+//	@Produce		json
+//	@Security		BearerAuth
+//	@Param			status		query		string	false	"Job status (PENDING, RUNNING, DEAD)"	default(DEAD)
+//	@Param			page		query		int		false	"Page number"							default(1)
+//	@Param			page_size	query		int		false	"Page size"								default(20)
+//	@Success		200			{object}	dto.APIResponse{data=[]dto.JobResponse}
+//	@Failure		400			{object}	dto.ErrorResponse
+//	@Failure		401			{object}	dto.ErrorResponse
+//	@Failure		403			{object}	dto.ErrorResponse
+//	@Failure		500			{object}	dto.ErrorResponse
+//	@Router			/admin/jobs [get]
+func (h *JobHandler) ListJobs(c *gin.Context) {
+	status := models.JobStatus(strings.ToUpper(c.DefaultQuery("status", string(models.JobStatusDead))))
+	switch status {
+	case models.JobStatusPending, models.JobStatusRunning, models.JobStatusDead:
+	default:
+		c.Error(apperrors.ErrValidation.Withf("invalid status")).SetMeta("job.invalid_status")
+		return
+	}
+
+	page, pageSize := parsePagination(c)
+	jobs, err := h.jobUseCase.ListJobs(c.Request.Context(), status, page, pageSize)
+	if err != nil {
+		c.Error(err).SetMeta("job.list_failed")
+		return
+	}
+
+	responses := make([]dto.JobResponse, len(jobs))
+	for i, job := range jobs {
+		responses[i] = dto.ToJobResponse(&job)
+	}
+
+	c.JSON(http.StatusOK, dto.APIResponse{
+		Success: true,
+		Message: middleware.Translate(c, "job.list_retrieved"),
+		Data:    responses,
+	})
+}
+
+// RetryJob godoc
+//
+//	@Summary		Retry a dead job
+//	@Description	Move a job out of the dead letter queue and run it again with a fresh set of attempts (admin only)
+//	@Tags			admin
+//	@Accept			json
+//	@Produce		json
+//	@Security		BearerAuth
+//	@Param			id	path		int	true	"Job ID"
+//	@Success		200	{object}	dto.APIResponse{data=dto.JobResponse}
+//	@Failure		400	{object}	dto.ErrorResponse
+//	@Failure		401	{object}	dto.ErrorResponse
+//	@Failure		403	{object}	dto.ErrorResponse
+//	@Failure		404	{object}	dto.ErrorResponse
+//	@Failure		409	{object}	dto.ErrorResponse	"Job is not dead"
+//	@Failure		500	{object}	dto.ErrorResponse
+//	@Router			/admin/jobs/{id}/retry [post]
+func (h *JobHandler) RetryJob(c *gin.Context) {
+	jobID, err := parseIDParam(c, "id")
+	if err != nil {
+		c.Error(err).SetMeta("job.invalid_id")
+		return
+	}
+
+	job, err := h.jobUseCase.RetryJob(c.Request.Context(), jobID)
+	if err != nil {
+		c.Error(err).SetMeta("job.retry_failed")
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.APIResponse{
+		Success: true,
+		Message: middleware.Translate(c, "job.retried"),
+		Data:    dto.ToJobResponse(job),
+	})
+}
@@ -0,0 +1,151 @@
+package handlers
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/limistah/wallet-service/internal/apperrors"
+	"github.com/limistah/wallet-service/internal/dto"
+	"github.com/limistah/wallet-service/internal/middleware"
+	"github.com/limistah/wallet-service/internal/models"
+	"github.com/limistah/wallet-service/internal/usecases"
+)
+
+type LedgerHandler struct {
+	ledgerUseCase usecases.LedgerUseCase
+}
+
+func NewLedgerHandler(ledgerUseCase usecases.LedgerUseCase) *LedgerHandler {
+	return &LedgerHandler{
+		ledgerUseCase: ledgerUseCase,
+	}
+}
+
+// ListLedgerAccounts godoc
+//
+//	@Summary		List ledger accounts
+//	@Description	List the chart of ledger accounts with the balance of the wallet backing each account (admin and support)
+//	@Tags			admin
+//	@Accept			json
+//	@Produce		json
+//	@Security		BearerAuth
+//	@Param			sandbox	query		bool	false	"List the sandbox ledger accounts"
+//	@Success		200		{object}	dto.APIResponse{data=[]dto.LedgerAccountResponse}
+//	@Failure		401		{object}	dto.ErrorResponse
+//	@Failure		403		{object}	dto.ErrorResponse
+//	@Failure		500		{object}	dto.ErrorResponse
+//	@Router			/admin/ledger/accounts [get]
+func (h *LedgerHandler) ListLedgerAccounts(c *gin.Context) {
+	accounts, err := h.ledgerUseCase.ListAccounts(c.Request.Context(), c.Query("sandbox") == "true")
+	if err != nil {
+		c.Error(err).SetMeta("ledger.accounts_retrieve_failed")
+		return
+	}
+
+	responses := make([]dto.LedgerAccountResponse, len(accounts))
+	for i := range accounts {
+		responses[i] = dto.ToLedgerAccountResponse(&accounts[i])
+	}
+
+	c.JSON(http.StatusOK, dto.APIResponse{
+		Success: true,
+		Message: middleware.Translate(c, "ledger.accounts_retrieved"),
+		Data:    responses,
+	})
+}
+
+// PostJournalEntry godoc
+//
+//	@Summary		Post a journal entry
+//	@Description	Post a balanced multi-leg journal entry between ledger accounts, such as funding interest from cash (admin only)
+//	@Tags			admin
+//	@Accept			json
+//	@Produce		json
+//	@Security		BearerAuth
+//	@Param			request	body		dto.JournalEntryRequest	true	"Journal entry"
+//	@Success		201		{object}	dto.APIResponse{data=dto.JournalEntryResponse}
+//	@Failure		400		{object}	dto.ErrorResponse	"Invalid or unbalanced entry"
+//	@Failure		401		{object}	dto.ErrorResponse
+//	@Failure		403		{object}	dto.ErrorResponse
+//	@Failure		404		{object}	dto.ErrorResponse	"Ledger account or transaction purpose not found"
+//	@Failure		409		{object}	dto.ErrorResponse	"Duplicate reference"
+//	@Failure		500		{object}	dto.ErrorResponse
+//	@Router			/admin/ledger/journal-entries [post]
+func (h *LedgerHandler) PostJournalEntry(c *gin.Context) {
+	adminID, exists := middleware.GetUserID(c)
+	if !exists {
+		c.Error(apperrors.ErrUnauthenticated)
+		return
+	}
+
+	var req dto.JournalEntryRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.Error(apperrors.ErrValidation.Withf("invalid request data: %w", err)).SetMeta("request.invalid")
+		return
+	}
+
+	legs := make([]usecases.JournalLeg, len(req.Legs))
+	for i, leg := range req.Legs {
+		legs[i] = usecases.JournalLeg{
+			Account: models.LedgerAccountCode(leg.Account),
+			Type:    models.TransactionType(leg.Type),
+			Amount:  leg.Amount,
+		}
+	}
+
+	entry, err := h.ledgerUseCase.PostJournalEntry(c.Request.Context(), usecases.JournalEntryInput{
+		Reference:   req.Reference,
+		Description: strings.TrimSpace(req.Description),
+		Sandbox:     req.Sandbox,
+		PostedBy:    adminID,
+		Purpose:     models.TransactionPurpose(strings.ToUpper(strings.TrimSpace(req.Purpose))),
+		Legs:        legs,
+	})
+	if err != nil {
+		c.Error(err).SetMeta("ledger.journal_entry_post_failed")
+		return
+	}
+
+	c.JSON(http.StatusCreated, dto.APIResponse{
+		Success: true,
+		Message: middleware.Translate(c, "ledger.journal_entry_posted"),
+		Data:    dto.ToJournalEntryResponse(entry),
+	})
+}
+
+// GetJournalEntry godoc
+//
+//	@Summary		Get a journal entry
+//	@Description	Get a journal entry with its legs (admin and support)
+//	@Tags			admin
+//	@Accept			json
+//	@Produce		json
+//	@Security		BearerAuth
+//	@Param			id	path		int	true	"Journal entry ID"
+//	@Success		200	{object}	dto.APIResponse{data=dto.JournalEntryResponse}
+//	@Failure		400	{object}	dto.ErrorResponse
+//	@Failure		401	{object}	dto.ErrorResponse
+//	@Failure		403	{object}	dto.ErrorResponse
+//	@Failure		404	{object}	dto.ErrorResponse
+//	@Failure		500	{object}	dto.ErrorResponse
+//	@Router			/admin/ledger/journal-entries/{id} [get]
+func (h *LedgerHandler) GetJournalEntry(c *gin.Context) {
+	entryID, err := parseIDParam(c, "id")
+	if err != nil {
+		c.Error(err).SetMeta("ledger.invalid_journal_entry_id")
+		return
+	}
+
+	entry, err := h.ledgerUseCase.GetJournalEntry(c.Request.Context(), entryID)
+	if err != nil {
+		c.Error(err).SetMeta("ledger.journal_entry_retrieve_failed")
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.APIResponse{
+		Success: true,
+		Message: middleware.Translate(c, "ledger.journal_entry_retrieved"),
+		Data:    dto.ToJournalEntryResponse(entry),
+	})
+}
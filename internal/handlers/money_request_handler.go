@@ -0,0 +1,258 @@
+package handlers
+
+import (
+	"context"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/limistah/wallet-service/internal/apperrors"
+	"github.com/limistah/wallet-service/internal/dto"
+	"github.com/limistah/wallet-service/internal/middleware"
+	"github.com/limistah/wallet-service/internal/models"
+	"github.com/limistah/wallet-service/internal/usecases"
+	"github.com/shopspring/decimal"
+)
+
+type MoneyRequestHandler struct {
+	moneyRequestUseCase usecases.MoneyRequestUseCase
+}
+
+func NewMoneyRequestHandler(moneyRequestUseCase usecases.MoneyRequestUseCase) *MoneyRequestHandler {
+	return &MoneyRequestHandler{
+		moneyRequestUseCase: moneyRequestUseCase,
+	}
+}
+
+// CreateRequest godoc
+//
+//	@Summary		Request money
+//	@Description	Ask another user for funds. The payer is notified and can accept or decline the request.
+//	@Tags			money-requests
+//	@Accept			json
+//	@Produce		json
+//	@Security		BearerAuth
+//	@Param			request	body		dto.CreateMoneyRequestRequest	true	"Money request"
+//	@Success		201		{object}	dto.APIResponse{data=dto.MoneyRequestResponse}
+//	@Failure		400		{object}	dto.ErrorResponse
+//	@Failure		401		{object}	dto.ErrorResponse
+//	@Failure		403		{object}	dto.ErrorResponse
+//	@Failure		404		{object}	dto.ErrorResponse
+//	@Failure		500		{object}	dto.ErrorResponse
+//	@Router			/wallets/me/requests [post]
+func (h *MoneyRequestHandler) CreateRequest(c *gin.Context) {
+	userID, exists := middleware.GetUserID(c)
+	if !exists {
+		c.Error(apperrors.ErrUnauthenticated)
+		return
+	}
+
+	if rejectSandbox(c) {
+		return
+	}
+
+	var req dto.CreateMoneyRequestRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.Error(apperrors.ErrValidation.Withf("invalid request data: %w", err)).SetMeta("request.invalid")
+		return
+	}
+
+	if req.Amount.LessThanOrEqual(decimal.Zero) {
+		c.Error(apperrors.ErrInvalidAmount)
+		return
+	}
+
+	request, err := h.moneyRequestUseCase.CreateRequest(c.Request.Context(), userID, req.PayerEmail, req.Amount, req.Note)
+	if err != nil {
+		c.Error(err).SetMeta("money_request.create_failed")
+		return
+	}
+
+	c.JSON(http.StatusCreated, dto.APIResponse{
+		Success: true,
+		Message: middleware.Translate(c, "money_request.created"),
+		Data:    dto.ToMoneyRequestResponse(request),
+	})
+}
+
+// ListRequests godoc
+//
+//	@Summary		List money requests
+//	@Description	List money requests the authenticated user sent (outgoing) or received (incoming)
+//	@Tags			money-requests
+//	@Accept			json
+//	@Produce		json
+//	@Security		BearerAuth
+//	@Param			direction	query		string	false	"incoming or outgoing; both when omitted"
+//	@Param			status		query		string	false	"Request status (PENDING, ACCEPTED, DECLINED, CANCELLED)"
+//	@Param			page		query		int		false	"Page number"	default(1)
+//	@Param			page_size	query		int		false	"Page size"		default(20)
+//	@Success		200			{object}	dto.APIResponse{data=[]dto.MoneyRequestResponse}
+//	@Failure		400			{object}	dto.ErrorResponse
+//	@Failure		401			{object}	dto.ErrorResponse
+//	@Failure		500			{object}	dto.ErrorResponse
+//	@Router			/wallets/me/requests [get]
+func (h *MoneyRequestHandler) ListRequests(c *gin.Context) {
+	userID, exists := middleware.GetUserID(c)
+	if !exists {
+		c.Error(apperrors.ErrUnauthenticated)
+		return
+	}
+
+	direction := models.MoneyRequestDirection(strings.ToLower(c.Query("direction")))
+	if direction != "" && direction != models.MoneyRequestIncoming && direction != models.MoneyRequestOutgoing {
+		c.Error(apperrors.ErrValidation.Withf("direction must be incoming or outgoing")).SetMeta("money_request.invalid_direction")
+		return
+	}
+
+	status := models.MoneyRequestStatus(strings.ToUpper(c.Query("status")))
+	switch status {
+	case "", models.MoneyRequestStatusPending, models.MoneyRequestStatusAccepted,
+		models.MoneyRequestStatusDeclined, models.MoneyRequestStatusCancelled:
+	default:
+		c.Error(apperrors.ErrValidation.Withf("unknown money request status")).SetMeta("money_request.invalid_status")
+		return
+	}
+
+	page, pageSize := parsePagination(c)
+	requests, err := h.moneyRequestUseCase.ListRequests(c.Request.Context(), userID, direction, status, page, pageSize)
+	if err != nil {
+		c.Error(err).SetMeta("money_request.list_failed")
+		return
+	}
+
+	responses := make([]dto.MoneyRequestResponse, len(requests))
+	for i, request := range requests {
+		responses[i] = dto.ToMoneyRequestResponse(&request)
+	}
+
+	c.JSON(http.StatusOK, dto.APIResponse{
+		Success: true,
+		Message: middleware.Translate(c, "money_request.list_retrieved"),
+		Data:    responses,
+	})
+}
+
+// AcceptRequest godoc
+//
+//	@Summary		Accept a money request
+//	@Description	Pay an incoming money request by transferring the amount to the requester. The transfer carries the request reference. Users who set a transaction PIN send it with amounts above the PIN threshold. Amounts above the step-up threshold are refused; they are paid as transfers confirmed with a one-time code.
+//	@Tags			money-requests
+//	@Accept			json
+//	@Produce		json
+//	@Security		BearerAuth
+//	@Param			id		path		int								true	"Money request ID"
+//	@Param			request	body		dto.AcceptMoneyRequestRequest	false	"Transaction PIN"
+//	@Success		200		{object}	dto.APIResponse{data=dto.MoneyRequestResponse}
+//	@Failure		400		{object}	dto.ErrorResponse
+//	@Failure		401		{object}	dto.ErrorResponse
+//	@Failure		403		{object}	dto.ErrorResponse	"Missing, wrong or locked transaction PIN (codes PIN_REQUIRED, INVALID_PIN, PIN_LOCKED)"
+//	@Failure		404		{object}	dto.ErrorResponse
+//	@Failure		409		{object}	dto.ErrorResponse	"Request already answered or insufficient funds"
+//	@Failure		500		{object}	dto.ErrorResponse
+//	@Router			/wallets/me/requests/{id}/accept [post]
+func (h *MoneyRequestHandler) AcceptRequest(c *gin.Context) {
+	userID, exists := middleware.GetUserID(c)
+	if !exists {
+		c.Error(apperrors.ErrUnauthenticated)
+		return
+	}
+
+	if rejectSandbox(c) {
+		return
+	}
+
+	requestID, err := parseIDParam(c, "id")
+	if err != nil {
+		c.Error(err).SetMeta("money_request.invalid_id")
+		return
+	}
+
+	// The PIN is only needed above the threshold, so an empty body is accepted
+	var req dto.AcceptMoneyRequestRequest
+	if c.Request.ContentLength != 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.Error(apperrors.ErrValidation.Withf("invalid request data: %w", err)).SetMeta("request.invalid")
+			return
+		}
+	}
+
+	request, _, err := h.moneyRequestUseCase.AcceptRequest(c.Request.Context(), userID, requestID, req.PIN, middleware.GetOrigin(c))
+	if err != nil {
+		c.Error(err).SetMeta("money_request.accept_failed")
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.APIResponse{
+		Success: true,
+		Message: middleware.Translate(c, "money_request.accepted"),
+		Data:    dto.ToMoneyRequestResponse(request),
+	})
+}
+
+// DeclineRequest godoc
+//
+//	@Summary		Decline a money request
+//	@Description	Decline an incoming money request without paying it
+//	@Tags			money-requests
+//	@Accept			json
+//	@Produce		json
+//	@Security		BearerAuth
+//	@Param			id	path		int	true	"Money request ID"
+//	@Success		200	{object}	dto.APIResponse{data=dto.MoneyRequestResponse}
+//	@Failure		400	{object}	dto.ErrorResponse
+//	@Failure		401	{object}	dto.ErrorResponse
+//	@Failure		404	{object}	dto.ErrorResponse
+//	@Failure		409	{object}	dto.ErrorResponse	"Request already answered"
+//	@Failure		500	{object}	dto.ErrorResponse
+//	@Router			/wallets/me/requests/{id}/decline [post]
+func (h *MoneyRequestHandler) DeclineRequest(c *gin.Context) {
+	h.respond(c, "money_request.declined", h.moneyRequestUseCase.DeclineRequest)
+}
+
+// CancelRequest godoc
+//
+//	@Summary		Cancel a money request
+//	@Description	Withdraw an outgoing money request before the payer answers it
+//	@Tags			money-requests
+//	@Accept			json
+//	@Produce		json
+//	@Security		BearerAuth
+//	@Param			id	path		int	true	"Money request ID"
+//	@Success		200	{object}	dto.APIResponse{data=dto.MoneyRequestResponse}
+//	@Failure		400	{object}	dto.ErrorResponse
+//	@Failure		401	{object}	dto.ErrorResponse
+//	@Failure		404	{object}	dto.ErrorResponse
+//	@Failure		409	{object}	dto.ErrorResponse	"Request already answered"
+//	@Failure		500	{object}	dto.ErrorResponse
+//	@Router			/wallets/me/requests/{id}/cancel [post]
+func (h *MoneyRequestHandler) CancelRequest(c *gin.Context) {
+	h.respond(c, "money_request.cancelled", h.moneyRequestUseCase.CancelRequest)
+}
+
+// respond closes a pending request on behalf of the authenticated user
+func (h *MoneyRequestHandler) respond(c *gin.Context, successKey string, action func(ctx context.Context, userID, requestID uint) (*models.MoneyRequest, error)) {
+	userID, exists := middleware.GetUserID(c)
+	if !exists {
+		c.Error(apperrors.ErrUnauthenticated)
+		return
+	}
+
+	requestID, err := parseIDParam(c, "id")
+	if err != nil {
+		c.Error(err).SetMeta("money_request.invalid_id")
+		return
+	}
+
+	request, err := action(c.Request.Context(), userID, requestID)
+	if err != nil {
+		c.Error(err).SetMeta("money_request.update_failed")
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.APIResponse{
+		Success: true,
+		Message: middleware.Translate(c, successKey),
+		Data:    dto.ToMoneyRequestResponse(request),
+	})
+}
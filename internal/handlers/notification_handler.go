@@ -0,0 +1,327 @@
+package handlers
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/limistah/wallet-service/internal/apperrors"
+	"github.com/limistah/wallet-service/internal/dto"
+	"github.com/limistah/wallet-service/internal/middleware"
+	"github.com/limistah/wallet-service/internal/models"
+	"github.com/limistah/wallet-service/internal/usecases"
+)
+
+type NotificationHandler struct {
+	notificationUseCase usecases.NotificationUseCase
+}
+
+func NewNotificationHandler(notificationUseCase usecases.NotificationUseCase) *NotificationHandler {
+	return &NotificationHandler{
+		notificationUseCase: notificationUseCase,
+	}
+}
+
+// GetPreferences godoc
+//
+//	@Summary		Get notification preferences
+//	@Description	Retrieve the notification preferences of the authenticated user
+//	@Tags			notifications
+//	@Accept			json
+//	@Produce		json
+//	@Security		BearerAuth
+//	@Success		200	{object}	dto.APIResponse{data=dto.NotificationPreferenceResponse}
+//	@Failure		401	{object}	dto.ErrorResponse
+//	@Failure		500	{object}	dto.ErrorResponse
+//	@Router			/users/me/notification-preferences [get]
+func (h *NotificationHandler) GetPreferences(c *gin.Context) {
+	userID, exists := middleware.GetUserID(c)
+	if !exists {
+		c.Error(apperrors.ErrUnauthenticated)
+		return
+	}
+
+	preference, err := h.notificationUseCase.GetPreferences(c.Request.Context(), userID)
+	if err != nil {
+		c.Error(err).SetMeta("notification.preferences_retrieve_failed")
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.APIResponse{
+		Success: true,
+		Message: middleware.Translate(c, "notification.preferences_retrieved"),
+		Data:    dto.ToNotificationPreferenceResponse(preference),
+	})
+}
+
+// UpdatePreferences godoc
+//
+//	@Summary		Update notification preferences
+//	@Description	Update the notification preferences of the authenticated user; omitted fields are left unchanged
+//	@Tags			notifications
+//	@Accept			json
+//	@Produce		json
+//	@Security		BearerAuth
+//	@Param			request	body		dto.UpdateNotificationPreferenceRequest	true	"Notification preferences"
+//	@Success		200		{object}	dto.APIResponse{data=dto.NotificationPreferenceResponse}
+//	@Failure		400		{object}	dto.ErrorResponse
+//	@Failure		401		{object}	dto.ErrorResponse
+//	@Failure		500		{object}	dto.ErrorResponse
+//	@Router			/users/me/notification-preferences [put]
+func (h *NotificationHandler) UpdatePreferences(c *gin.Context) {
+	userID, exists := middleware.GetUserID(c)
+	if !exists {
+		c.Error(apperrors.ErrUnauthenticated)
+		return
+	}
+
+	var req dto.UpdateNotificationPreferenceRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.Error(apperrors.ErrValidation.Withf("invalid request data: %w", err)).SetMeta("request.invalid")
+		return
+	}
+
+	preference, err := h.notificationUseCase.GetPreferences(c.Request.Context(), userID)
+	if err != nil {
+		c.Error(err).SetMeta("notification.preferences_retrieve_failed")
+		return
+	}
+
+	if req.EmailEnabled != nil {
+		preference.EmailEnabled = *req.EmailEnabled
+	}
+	if req.CreditReceived != nil {
+		preference.CreditReceived = *req.CreditReceived
+	}
+	if req.WithdrawalCompleted != nil {
+		preference.WithdrawalCompleted = *req.WithdrawalCompleted
+	}
+	if req.TransactionFailed != nil {
+		preference.TransactionFailed = *req.TransactionFailed
+	}
+	if req.SuspiciousActivity != nil {
+		preference.SuspiciousActivity = *req.SuspiciousActivity
+	}
+	if req.SMSEnabled != nil {
+		preference.SMSEnabled = *req.SMSEnabled
+	}
+	if req.SMSDebitThreshold != nil {
+		preference.SMSDebitThreshold = *req.SMSDebitThreshold
+	}
+	if req.PushEnabled != nil {
+		preference.PushEnabled = *req.PushEnabled
+	}
+
+	updated, err := h.notificationUseCase.UpdatePreferences(c.Request.Context(), userID, preference)
+	if err != nil {
+		c.Error(err).SetMeta("notification.preferences_update_failed")
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.APIResponse{
+		Success: true,
+		Message: middleware.Translate(c, "notification.preferences_updated"),
+		Data:    dto.ToNotificationPreferenceResponse(updated),
+	})
+}
+
+// RequestPhoneVerification godoc
+//
+//	@Summary		Start phone verification
+//	@Description	Send a one-time verification code by SMS to the given phone number
+//	@Tags			notifications
+//	@Accept			json
+//	@Produce		json
+//	@Security		BearerAuth
+//	@Param			request	body		dto.PhoneVerificationRequest	true	"Phone number in E.164 format"
+//	@Success		200		{object}	dto.APIResponse{data=dto.PhoneVerificationResponse}
+//	@Failure		400		{object}	dto.ErrorResponse
+//	@Failure		401		{object}	dto.ErrorResponse
+//	@Failure		502		{object}	dto.ErrorResponse
+//	@Failure		503		{object}	dto.ErrorResponse
+//	@Router			/users/me/phone [post]
+func (h *NotificationHandler) RequestPhoneVerification(c *gin.Context) {
+	userID, exists := middleware.GetUserID(c)
+	if !exists {
+		c.Error(apperrors.ErrUnauthenticated)
+		return
+	}
+
+	var req dto.PhoneVerificationRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.Error(apperrors.ErrValidation.Withf("invalid request data: %w", err)).SetMeta("request.invalid")
+		return
+	}
+
+	verification, err := h.notificationUseCase.RequestPhoneVerification(c.Request.Context(), userID, strings.TrimSpace(req.PhoneNumber))
+	if err != nil {
+		c.Error(err).SetMeta("notification.phone_verification_start_failed")
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.APIResponse{
+		Success: true,
+		Message: middleware.Translate(c, "notification.phone_verification_started"),
+		Data: dto.PhoneVerificationResponse{
+			PhoneNumber: verification.PhoneNumber,
+			ExpiresAt:   verification.ExpiresAt,
+		},
+	})
+}
+
+// VerifyPhone godoc
+//
+//	@Summary		Confirm phone verification
+//	@Description	Confirm the phone number of the authenticated user with the code received by SMS
+//	@Tags			notifications
+//	@Accept			json
+//	@Produce		json
+//	@Security		BearerAuth
+//	@Param			request	body		dto.VerifyPhoneRequest	true	"Verification code"
+//	@Success		200		{object}	dto.APIResponse{data=dto.UserResponse}
+//	@Failure		400		{object}	dto.ErrorResponse
+//	@Failure		401		{object}	dto.ErrorResponse
+//	@Failure		500		{object}	dto.ErrorResponse
+//	@Router			/users/me/phone/verify [post]
+func (h *NotificationHandler) VerifyPhone(c *gin.Context) {
+	userID, exists := middleware.GetUserID(c)
+	if !exists {
+		c.Error(apperrors.ErrUnauthenticated)
+		return
+	}
+
+	var req dto.VerifyPhoneRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.Error(apperrors.ErrValidation.Withf("invalid request data: %w", err)).SetMeta("request.invalid")
+		return
+	}
+
+	user, err := h.notificationUseCase.VerifyPhone(c.Request.Context(), userID, req.Code)
+	if err != nil {
+		c.Error(err).SetMeta("notification.phone_number_verify_failed")
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.APIResponse{
+		Success: true,
+		Message: middleware.Translate(c, "notification.phone_number_verified"),
+		Data:    dto.ToUserResponse(user),
+	})
+}
+
+// RegisterDevice godoc
+//
+//	@Summary		Register a push device
+//	@Description	Register an FCM registration token for the authenticated user; re-registering an existing token updates it
+//	@Tags			notifications
+//	@Accept			json
+//	@Produce		json
+//	@Security		BearerAuth
+//	@Param			request	body		dto.RegisterDeviceRequest	true	"Device registration"
+//	@Success		201		{object}	dto.APIResponse{data=dto.DeviceResponse}
+//	@Failure		400		{object}	dto.ErrorResponse
+//	@Failure		401		{object}	dto.ErrorResponse
+//	@Failure		500		{object}	dto.ErrorResponse
+//	@Router			/users/me/devices [post]
+func (h *NotificationHandler) RegisterDevice(c *gin.Context) {
+	userID, exists := middleware.GetUserID(c)
+	if !exists {
+		c.Error(apperrors.ErrUnauthenticated)
+		return
+	}
+
+	var req dto.RegisterDeviceRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.Error(apperrors.ErrValidation.Withf("invalid request data: %w", err)).SetMeta("request.invalid")
+		return
+	}
+
+	platform := models.DevicePlatform(strings.ToUpper(strings.TrimSpace(req.Platform)))
+	device, err := h.notificationUseCase.RegisterDevice(c.Request.Context(), userID, strings.TrimSpace(req.Token), platform, req.DeviceName)
+	if err != nil {
+		c.Error(err).SetMeta("notification.device_register_failed")
+		return
+	}
+
+	c.JSON(http.StatusCreated, dto.APIResponse{
+		Success: true,
+		Message: middleware.Translate(c, "notification.device_registered"),
+		Data:    dto.ToDeviceResponse(device),
+	})
+}
+
+// ListDevices godoc
+//
+//	@Summary		List push devices
+//	@Description	List the devices registered for push notifications by the authenticated user
+//	@Tags			notifications
+//	@Accept			json
+//	@Produce		json
+//	@Security		BearerAuth
+//	@Success		200	{object}	dto.APIResponse{data=[]dto.DeviceResponse}
+//	@Failure		401	{object}	dto.ErrorResponse
+//	@Failure		500	{object}	dto.ErrorResponse
+//	@Router			/users/me/devices [get]
+func (h *NotificationHandler) ListDevices(c *gin.Context) {
+	userID, exists := middleware.GetUserID(c)
+	if !exists {
+		c.Error(apperrors.ErrUnauthenticated)
+		return
+	}
+
+	devices, err := h.notificationUseCase.ListDevices(c.Request.Context(), userID)
+	if err != nil {
+		c.Error(err).SetMeta("notification.devices_retrieve_failed")
+		return
+	}
+
+	deviceResponses := make([]dto.DeviceResponse, len(devices))
+	for i, device := range devices {
+		deviceResponses[i] = dto.ToDeviceResponse(&device)
+	}
+
+	c.JSON(http.StatusOK, dto.APIResponse{
+		Success: true,
+		Message: middleware.Translate(c, "notification.devices_retrieved"),
+		Data:    deviceResponses,
+	})
+}
+
+// RemoveDevice godoc
+//
+//	@Summary		Remove a push device
+//	@Description	Stop sending push notifications to a device of the authenticated user
+//	@Tags			notifications
+//	@Accept			json
+//	@Produce		json
+//	@Security		BearerAuth
+//	@Param			id	path		int	true	"Device ID"
+//	@Success		200	{object}	dto.APIResponse
+//	@Failure		400	{object}	dto.ErrorResponse
+//	@Failure		401	{object}	dto.ErrorResponse
+//	@Failure		404	{object}	dto.ErrorResponse
+//	@Failure		500	{object}	dto.ErrorResponse
+//	@Router			/users/me/devices/{id} [delete]
+func (h *NotificationHandler) RemoveDevice(c *gin.Context) {
+	userID, exists := middleware.GetUserID(c)
+	if !exists {
+		c.Error(apperrors.ErrUnauthenticated)
+		return
+	}
+
+	deviceID, err := parseIDParam(c, "id")
+	if err != nil {
+		c.Error(err).SetMeta("notification.invalid_device_id")
+		return
+	}
+
+	if err := h.notificationUseCase.RemoveDevice(c.Request.Context(), userID, deviceID); err != nil {
+		c.Error(err).SetMeta("notification.device_remove_failed")
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.APIResponse{
+		Success: true,
+		Message: middleware.Translate(c, "notification.device_removed"),
+	})
+}
@@ -0,0 +1,148 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/limistah/wallet-service/internal/apperrors"
+	"github.com/limistah/wallet-service/internal/auth"
+	"github.com/limistah/wallet-service/internal/dto"
+	"github.com/limistah/wallet-service/internal/middleware"
+	"github.com/limistah/wallet-service/internal/oauth"
+	"github.com/limistah/wallet-service/internal/usecases"
+)
+
+const (
+	// oauthSessionCookie carries the sealed sign in session from the start endpoint to the callback
+	oauthSessionCookie = "oauth_session"
+	oauthCookiePath    = "/api/v1/auth/oauth/"
+)
+
+type OAuthHandler struct {
+	oauthUseCase usecases.OAuthUseCase
+	loginUseCase usecases.LoginUseCase
+	sessions     usecases.SessionUseCase
+	providers    *oauth.Registry
+	jwtService   *auth.JWTService
+}
+
+func NewOAuthHandler(oauthUseCase usecases.OAuthUseCase, loginUseCase usecases.LoginUseCase, sessions usecases.SessionUseCase, providers *oauth.Registry, jwtService *auth.JWTService) *OAuthHandler {
+	return &OAuthHandler{
+		oauthUseCase: oauthUseCase,
+		loginUseCase: loginUseCase,
+		sessions:     sessions,
+		providers:    providers,
+		jwtService:   jwtService,
+	}
+}
+
+// StartOAuth godoc
+//
+//	@Summary		Start signing in with an identity provider
+//	@Description	Redirect the browser to the sign in page of Google or the configured OpenID Connect provider; the provider sends the user back to the callback endpoint
+//	@Tags			auth
+//	@Param			provider	path	string	true	"Identity provider, such as google"
+//	@Success		302
+//	@Failure		404	{object}	dto.ErrorResponse
+//	@Failure		500	{object}	dto.ErrorResponse
+//	@Failure		502	{object}	dto.ErrorResponse	"Provider discovery failed"
+//	@Router			/auth/oauth/{provider}/start [get]
+func (h *OAuthHandler) StartOAuth(c *gin.Context) {
+	provider, ok := h.providers.Get(c.Param("provider"))
+	if !ok {
+		c.Error(apperrors.ErrOAuthProviderNotFound)
+		return
+	}
+
+	session, err := h.providers.Sessions().New(provider.Name())
+	if err != nil {
+		c.Error(err).SetMeta("oauth.start_failed")
+		return
+	}
+	sealed, err := h.providers.Sessions().Seal(session)
+	if err != nil {
+		c.Error(err).SetMeta("oauth.start_failed")
+		return
+	}
+	authURL, err := provider.AuthCodeURL(session)
+	if err != nil {
+		// Only the discovery of a generic provider's endpoints can fail here
+		c.Error(apperrors.ErrOAuthUnavailable.Wrap(err))
+		return
+	}
+
+	// Lax lets the cookie come back on the provider's top-level redirect to the callback
+	c.SetSameSite(http.SameSiteLaxMode)
+	c.SetCookie(oauthSessionCookie, sealed, 0, oauthCookiePath, "", true, true)
+	c.Redirect(http.StatusFound, authURL)
+}
+
+// OAuthCallback godoc
+//
+//	@Summary		Finish signing in with an identity provider
+//	@Description	Redeem the authorization code the provider sent back and return a token of this service. A provider account seen for the first time is linked to the user with the same verified email, or a new user with a default wallet is created
+//	@Tags			auth
+//	@Produce		json
+//	@Param			provider	path		string	true	"Identity provider, such as google"
+//	@Param			code		query		string	true	"Authorization code"
+//	@Param			state		query		string	true	"State sent to the provider"
+//	@Success		200			{object}	dto.APIResponse{data=dto.LoginResponse}
+//	@Failure		401			{object}	dto.ErrorResponse	"Forged, expired or denied sign in (code OAUTH_FAILED)"
+//	@Failure		403			{object}	dto.ErrorResponse	"Email not verified by the provider (code OAUTH_EMAIL_UNVERIFIED) or sanctions match"
+//	@Failure		404			{object}	dto.ErrorResponse
+//	@Failure		500			{object}	dto.ErrorResponse
+//	@Router			/auth/oauth/{provider}/callback [get]
+func (h *OAuthHandler) OAuthCallback(c *gin.Context) {
+	provider, ok := h.providers.Get(c.Param("provider"))
+	if !ok {
+		c.Error(apperrors.ErrOAuthProviderNotFound)
+		return
+	}
+
+	// The session is single use whatever the outcome
+	sealed, _ := c.Cookie(oauthSessionCookie)
+	c.SetSameSite(http.SameSiteLaxMode)
+	c.SetCookie(oauthSessionCookie, "", -1, oauthCookiePath, "", true, true)
+
+	session, err := h.providers.Sessions().Open(sealed, provider.Name())
+	if err == nil && c.Query("state") != session.State {
+		err = oauth.ErrInvalidSession
+	}
+	if err == nil && c.Query("error") != "" {
+		err = apperrors.ErrOAuthFailed.Withf("provider returned %s", c.Query("error"))
+	}
+	var identity *oauth.Identity
+	if err == nil {
+		identity, err = provider.Exchange(c.Query("code"), session)
+	}
+	if err != nil {
+		if apperrors.From(err) == nil {
+			err = apperrors.ErrOAuthFailed.Wrap(err)
+		}
+		c.Error(err).SetMeta("oauth.sign_in_failed")
+		return
+	}
+
+	user, err := h.oauthUseCase.SignIn(c.Request.Context(), *identity)
+	if err != nil {
+		c.Error(err).SetMeta("oauth.sign_in_failed")
+		return
+	}
+
+	h.loginUseCase.RecordLogin(c.Request.Context(), user, provider.Name(), clientInfo(c))
+
+	token, err := issueSessionToken(c, h.jwtService, h.sessions, user, false)
+	if err != nil {
+		c.Error(err).SetMeta("oauth.token_generate_failed")
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.APIResponse{
+		Success: true,
+		Message: middleware.Translate(c, "auth.logged_in"),
+		Data: dto.LoginResponse{
+			User:  dto.ToUserResponse(user),
+			Token: token,
+		},
+	})
+}
@@ -0,0 +1,70 @@
+package handlers
+
+import (
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// setCursorLinks sets an RFC 5988 Link header on a cursor-paginated listing: next points at the
+// page after this one, and first at the start of the listing once the client paged away from it.
+// Cursors only go forward, so there is no prev link
+func setCursorLinks(c *gin.Context, nextCursor *string) {
+	links := make(map[string]url.Values)
+	if nextCursor != nil && *nextCursor != "" {
+		links["next"] = withQuery(c, "cursor", *nextCursor)
+	}
+	if c.Query("cursor") != "" {
+		links["first"] = withQuery(c, "cursor", "")
+	}
+	setLinkHeader(c, links)
+}
+
+// setPageLinks sets an RFC 5988 Link header on a page-numbered listing with links to the first,
+// previous and next pages. hasNext tells whether a next page may exist, which listings without a
+// total know from getting a full page
+func setPageLinks(c *gin.Context, page int, hasNext bool) {
+	links := make(map[string]url.Values)
+	if page > 1 {
+		links["first"] = withQuery(c, "page", "1")
+		links["prev"] = withQuery(c, "page", strconv.Itoa(page-1))
+	}
+	if hasNext {
+		links["next"] = withQuery(c, "page", strconv.Itoa(page+1))
+	}
+	setLinkHeader(c, links)
+}
+
+// withQuery returns the query of the request with param set to value, or removed when value is empty
+func withQuery(c *gin.Context, param, value string) url.Values {
+	query := c.Request.URL.Query()
+	if value == "" {
+		query.Del(param)
+	} else {
+		query.Set(param, value)
+	}
+	return query
+}
+
+// setLinkHeader writes links as one Link header on the path of the request, in the order next, prev,
+// first
+func setLinkHeader(c *gin.Context, links map[string]url.Values) {
+	var parts []string
+	for _, rel := range []string{"next", "prev", "first"} {
+		query, ok := links[rel]
+		if !ok {
+			continue
+		}
+		target := c.Request.URL.Path
+		if encoded := query.Encode(); encoded != "" {
+			target += "?" + encoded
+		}
+		parts = append(parts, fmt.Sprintf(`<%s>; rel="%s"`, target, rel))
+	}
+	if len(parts) > 0 {
+		c.Header("Link", strings.Join(parts, ", "))
+	}
+}
@@ -0,0 +1,35 @@
+package handlers
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSetPageLinks(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	tests := []struct {
+		query   string
+		hasNext bool
+		want    string
+	}{
+		{"", true, `</admin/reconciliation/summaries?page=2>; rel="next"`},
+		{"?page=2&page_size=10", true, `</admin/reconciliation/summaries?page=3&page_size=10>; rel="next", ` +
+			`</admin/reconciliation/summaries?page=1&page_size=10>; rel="prev", ` +
+			`</admin/reconciliation/summaries?page=1&page_size=10>; rel="first"`},
+		{"?page=3", false, `</admin/reconciliation/summaries?page=2>; rel="prev", </admin/reconciliation/summaries?page=1>; rel="first"`},
+		{"", false, ""},
+	}
+	for _, tt := range tests {
+		c, _ := gin.CreateTestContext(httptest.NewRecorder())
+		c.Request = httptest.NewRequest(http.MethodGet, "/admin/reconciliation/summaries"+tt.query, nil)
+		page, _ := parsePagination(c)
+
+		setPageLinks(c, page, tt.hasNext)
+		assert.Equal(t, tt.want, c.Writer.Header().Get("Link"), tt.query)
+	}
+}
@@ -0,0 +1,155 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/limistah/wallet-service/internal/apperrors"
+	"github.com/limistah/wallet-service/internal/dto"
+	"github.com/limistah/wallet-service/internal/middleware"
+	"github.com/limistah/wallet-service/internal/usecases"
+	"github.com/shopspring/decimal"
+)
+
+type PaymentHandler struct {
+	walletUseCase  usecases.WalletUseCase
+	depositUseCase usecases.DepositUseCase
+}
+
+func NewPaymentHandler(walletUseCase usecases.WalletUseCase, depositUseCase usecases.DepositUseCase) *PaymentHandler {
+	return &PaymentHandler{
+		walletUseCase:  walletUseCase,
+		depositUseCase: depositUseCase,
+	}
+}
+
+// rejectSandbox aborts requests made with sandbox tokens, which must never reach real payment providers
+func rejectSandbox(c *gin.Context) bool {
+	if !middleware.IsSandbox(c) {
+		return false
+	}
+	c.Error(apperrors.ErrSandboxProviderForbidden)
+	return true
+}
+
+// FundWithCard godoc
+//
+//	@Summary		Fund wallet by card
+//	@Description	Create a card payment for the authenticated user's wallet. The wallet is credited once the provider confirms the payment by webhook.
+//	@Tags			wallets
+//	@Accept			json
+//	@Produce		json
+//	@Security		BearerAuth
+//	@Param			request	body		dto.CardFundingRequest	true	"Funding amount"
+//	@Success		201		{object}	dto.APIResponse{data=dto.CardFundingResponse}
+//	@Failure		400		{object}	dto.ErrorResponse
+//	@Failure		401		{object}	dto.ErrorResponse
+//	@Failure		403		{object}	dto.ErrorResponse
+//	@Failure		404		{object}	dto.ErrorResponse
+//	@Failure		502		{object}	dto.ErrorResponse
+//	@Failure		503		{object}	dto.ErrorResponse
+//	@Router			/wallets/me/fund/card [post]
+func (h *PaymentHandler) FundWithCard(c *gin.Context) {
+	userID, exists := middleware.GetUserID(c)
+	if !exists {
+		c.Error(apperrors.ErrUnauthenticated)
+		return
+	}
+
+	if rejectSandbox(c) {
+		return
+	}
+
+	var req dto.CardFundingRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.Error(apperrors.ErrValidation.Withf("invalid request data: %w", err)).SetMeta("request.invalid")
+		return
+	}
+
+	if req.Amount.LessThanOrEqual(decimal.Zero) {
+		c.Error(apperrors.ErrInvalidAmount)
+		return
+	}
+
+	wallet, err := h.walletUseCase.GetWalletByUserID(c.Request.Context(), userID)
+	if err != nil {
+		c.Error(apperrors.ErrWalletNotFound.Wrap(err))
+		return
+	}
+
+	deposit, clientSecret, err := h.depositUseCase.FundWithCard(c.Request.Context(), wallet.ID, req.Amount)
+	if err != nil {
+		c.Error(err).SetMeta("payment.card_funding_start_failed")
+		return
+	}
+
+	c.JSON(http.StatusCreated, dto.APIResponse{
+		Success: true,
+		Message: middleware.Translate(c, "payment.card_funding_started"),
+		Data: dto.CardFundingResponse{
+			Deposit:      dto.ToDepositResponse(deposit),
+			ClientSecret: clientSecret,
+		},
+	})
+}
+
+// FundWithCheckout godoc
+//
+//	@Summary		Fund wallet through a hosted checkout
+//	@Description	Start a bank or card funding on the configured payment provider (Paystack or Flutterwave). Redirect the customer to the authorization URL; the wallet is credited once the provider confirms the payment by webhook.
+//	@Tags			wallets
+//	@Accept			json
+//	@Produce		json
+//	@Security		BearerAuth
+//	@Param			request	body		dto.CardFundingRequest	true	"Funding amount"
+//	@Success		201		{object}	dto.APIResponse{data=dto.CheckoutFundingResponse}
+//	@Failure		400		{object}	dto.ErrorResponse
+//	@Failure		401		{object}	dto.ErrorResponse
+//	@Failure		403		{object}	dto.ErrorResponse
+//	@Failure		404		{object}	dto.ErrorResponse
+//	@Failure		502		{object}	dto.ErrorResponse
+//	@Failure		503		{object}	dto.ErrorResponse
+//	@Router			/wallets/me/fund/checkout [post]
+func (h *PaymentHandler) FundWithCheckout(c *gin.Context) {
+	userID, exists := middleware.GetUserID(c)
+	if !exists {
+		c.Error(apperrors.ErrUnauthenticated)
+		return
+	}
+
+	if rejectSandbox(c) {
+		return
+	}
+
+	var req dto.CardFundingRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.Error(apperrors.ErrValidation.Withf("invalid request data: %w", err)).SetMeta("request.invalid")
+		return
+	}
+
+	if req.Amount.LessThanOrEqual(decimal.Zero) {
+		c.Error(apperrors.ErrInvalidAmount)
+		return
+	}
+
+	wallet, err := h.walletUseCase.GetWalletByUserID(c.Request.Context(), userID)
+	if err != nil {
+		c.Error(apperrors.ErrWalletNotFound.Wrap(err))
+		return
+	}
+
+	deposit, checkout, err := h.depositUseCase.InitializeCheckout(c.Request.Context(), wallet.ID, req.Amount)
+	if err != nil {
+		c.Error(err).SetMeta("payment.checkout_start_failed")
+		return
+	}
+
+	c.JSON(http.StatusCreated, dto.APIResponse{
+		Success: true,
+		Message: middleware.Translate(c, "payment.checkout_started"),
+		Data: dto.CheckoutFundingResponse{
+			Deposit:          dto.ToDepositResponse(deposit),
+			AuthorizationURL: checkout.AuthorizationURL,
+		},
+	})
+}
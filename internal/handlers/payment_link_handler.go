@@ -0,0 +1,223 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/limistah/wallet-service/internal/apperrors"
+	"github.com/limistah/wallet-service/internal/dto"
+	"github.com/limistah/wallet-service/internal/middleware"
+	"github.com/limistah/wallet-service/internal/usecases"
+)
+
+type PaymentLinkHandler struct {
+	paymentLinkUseCase usecases.PaymentLinkUseCase
+}
+
+func NewPaymentLinkHandler(paymentLinkUseCase usecases.PaymentLinkUseCase) *PaymentLinkHandler {
+	return &PaymentLinkHandler{
+		paymentLinkUseCase: paymentLinkUseCase,
+	}
+}
+
+// CreateLink godoc
+//
+//	@Summary		Create a payment link
+//	@Description	Create a shareable link that pays into the authenticated user's wallet. Leave amount empty to let the payer choose.
+//	@Tags			payment-links
+//	@Accept			json
+//	@Produce		json
+//	@Security		BearerAuth
+//	@Param			request	body		dto.CreatePaymentLinkRequest	true	"Payment link"
+//	@Success		201		{object}	dto.APIResponse{data=dto.PaymentLinkResponse}
+//	@Failure		400		{object}	dto.ErrorResponse
+//	@Failure		401		{object}	dto.ErrorResponse
+//	@Failure		403		{object}	dto.ErrorResponse
+//	@Failure		404		{object}	dto.ErrorResponse
+//	@Failure		500		{object}	dto.ErrorResponse
+//	@Router			/payment-links [post]
+func (h *PaymentLinkHandler) CreateLink(c *gin.Context) {
+	userID, exists := middleware.GetUserID(c)
+	if !exists {
+		c.Error(apperrors.ErrUnauthenticated)
+		return
+	}
+
+	if rejectSandbox(c) {
+		return
+	}
+
+	var req dto.CreatePaymentLinkRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.Error(apperrors.ErrValidation.Withf("invalid request data: %w", err)).SetMeta("request.invalid")
+		return
+	}
+
+	link, err := h.paymentLinkUseCase.CreateLink(c.Request.Context(), userID, req.Amount, req.Description, req.ExpiresAt, req.SingleUse)
+	if err != nil {
+		c.Error(err).SetMeta("payment_link.create_failed")
+		return
+	}
+
+	c.JSON(http.StatusCreated, dto.APIResponse{
+		Success: true,
+		Message: middleware.Translate(c, "payment_link.created"),
+		Data:    dto.ToPaymentLinkResponse(link),
+	})
+}
+
+// ListLinks godoc
+//
+//	@Summary		List payment links
+//	@Description	List the authenticated user's payment links with view and payment analytics
+//	@Tags			payment-links
+//	@Accept			json
+//	@Produce		json
+//	@Security		BearerAuth
+//	@Param			page		query		int	false	"Page number"	default(1)
+//	@Param			page_size	query		int	false	"Page size"		default(20)
+//	@Success		200			{object}	dto.APIResponse{data=[]dto.PaymentLinkResponse}
+//	@Failure		401			{object}	dto.ErrorResponse
+//	@Failure		500			{object}	dto.ErrorResponse
+//	@Router			/payment-links [get]
+func (h *PaymentLinkHandler) ListLinks(c *gin.Context) {
+	userID, exists := middleware.GetUserID(c)
+	if !exists {
+		c.Error(apperrors.ErrUnauthenticated)
+		return
+	}
+
+	page, pageSize := parsePagination(c)
+	links, err := h.paymentLinkUseCase.ListLinks(c.Request.Context(), userID, page, pageSize)
+	if err != nil {
+		c.Error(err).SetMeta("payment_link.list_failed")
+		return
+	}
+
+	responses := make([]dto.PaymentLinkResponse, len(links))
+	for i, link := range links {
+		responses[i] = dto.ToPaymentLinkResponse(&link)
+	}
+
+	c.JSON(http.StatusOK, dto.APIResponse{
+		Success: true,
+		Message: middleware.Translate(c, "payment_link.list_retrieved"),
+		Data:    responses,
+	})
+}
+
+// DisableLink godoc
+//
+//	@Summary		Disable a payment link
+//	@Description	Stop a payment link of the authenticated user from accepting further payments
+//	@Tags			payment-links
+//	@Accept			json
+//	@Produce		json
+//	@Security		BearerAuth
+//	@Param			id	path		int	true	"Payment link ID"
+//	@Success		200	{object}	dto.APIResponse{data=dto.PaymentLinkResponse}
+//	@Failure		400	{object}	dto.ErrorResponse
+//	@Failure		401	{object}	dto.ErrorResponse
+//	@Failure		404	{object}	dto.ErrorResponse
+//	@Failure		409	{object}	dto.ErrorResponse	"Link already used or disabled"
+//	@Failure		500	{object}	dto.ErrorResponse
+//	@Router			/payment-links/{id} [delete]
+func (h *PaymentLinkHandler) DisableLink(c *gin.Context) {
+	userID, exists := middleware.GetUserID(c)
+	if !exists {
+		c.Error(apperrors.ErrUnauthenticated)
+		return
+	}
+
+	linkID, err := parseIDParam(c, "id")
+	if err != nil {
+		c.Error(err).SetMeta("payment_link.invalid_id")
+		return
+	}
+
+	link, err := h.paymentLinkUseCase.DisableLink(c.Request.Context(), userID, linkID)
+	if err != nil {
+		c.Error(err).SetMeta("payment_link.disable_failed")
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.APIResponse{
+		Success: true,
+		Message: middleware.Translate(c, "payment_link.disabled"),
+		Data:    dto.ToPaymentLinkResponse(link),
+	})
+}
+
+// ResolveLink godoc
+//
+//	@Summary		View a payment link
+//	@Description	Resolve a payment link token to the recipient and amount. No authentication is required.
+//	@Tags			payment-links
+//	@Produce		json
+//	@Param			token	path		string	true	"Payment link token"
+//	@Success		200		{object}	dto.APIResponse{data=dto.PublicPaymentLinkResponse}
+//	@Failure		404		{object}	dto.ErrorResponse
+//	@Router			/pay/{token} [get]
+func (h *PaymentLinkHandler) ResolveLink(c *gin.Context) {
+	link, err := h.paymentLinkUseCase.ResolveLink(c.Request.Context(), c.Param("token"))
+	if err != nil {
+		c.Error(err).SetMeta("payment_link.resolve_failed")
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.APIResponse{
+		Success: true,
+		Message: middleware.Translate(c, "payment_link.retrieved"),
+		Data:    dto.ToPublicPaymentLinkResponse(link),
+	})
+}
+
+// PayLink godoc
+//
+//	@Summary		Pay a payment link
+//	@Description	Transfer from the authenticated user's wallet into the wallet of the link creator. Users who set a transaction PIN send it with payments above the PIN threshold. Amounts above the step-up threshold are refused; they are paid as transfers confirmed with a one-time code
+//	@Tags			payment-links
+//	@Accept			json
+//	@Produce		json
+//	@Security		BearerAuth
+//	@Param			token	path		string						true	"Payment link token"
+//	@Param			request	body		dto.PayPaymentLinkRequest	false	"Amount for links without a fixed amount"
+//	@Success		200		{object}	dto.APIResponse{data=dto.TransactionResponse}
+//	@Failure		400		{object}	dto.ErrorResponse
+//	@Failure		401		{object}	dto.ErrorResponse
+//	@Failure		403		{object}	dto.ErrorResponse	"Missing, wrong or locked transaction PIN (codes PIN_REQUIRED, INVALID_PIN, PIN_LOCKED)"
+//	@Failure		404		{object}	dto.ErrorResponse
+//	@Failure		409		{object}	dto.ErrorResponse	"Link no longer active or insufficient funds"
+//	@Failure		500		{object}	dto.ErrorResponse
+//	@Router			/pay/{token} [post]
+func (h *PaymentLinkHandler) PayLink(c *gin.Context) {
+	userID, exists := middleware.GetUserID(c)
+	if !exists {
+		c.Error(apperrors.ErrUnauthenticated)
+		return
+	}
+
+	if rejectSandbox(c) {
+		return
+	}
+
+	var req dto.PayPaymentLinkRequest
+	if c.Request.ContentLength != 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.Error(apperrors.ErrValidation.Withf("invalid request data: %w", err)).SetMeta("request.invalid")
+			return
+		}
+	}
+
+	_, transaction, err := h.paymentLinkUseCase.PayLink(c.Request.Context(), userID, c.Param("token"), req.Amount, req.PIN, middleware.GetOrigin(c))
+	if err != nil {
+		c.Error(err).SetMeta("payment_link.pay_failed")
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.APIResponse{
+		Success: true,
+		Message: middleware.Translate(c, "payment_link.paid"),
+		Data:    dto.ToTransactionResponse(transaction),
+	})
+}
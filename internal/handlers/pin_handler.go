@@ -0,0 +1,143 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/limistah/wallet-service/internal/apperrors"
+	"github.com/limistah/wallet-service/internal/dto"
+	"github.com/limistah/wallet-service/internal/middleware"
+	"github.com/limistah/wallet-service/internal/usecases"
+)
+
+type PINHandler struct {
+	pinUseCase usecases.PINUseCase
+}
+
+func NewPINHandler(pinUseCase usecases.PINUseCase) *PINHandler {
+	return &PINHandler{
+		pinUseCase: pinUseCase,
+	}
+}
+
+// SetPIN godoc
+//
+//	@Summary		Set transaction PIN
+//	@Description	Set the 4 to 6 digit PIN that withdrawals and transfers above the PIN threshold must carry from now on
+//	@Tags			users
+//	@Accept			json
+//	@Produce		json
+//	@Security		BearerAuth
+//	@Param			request	body		dto.SetPINRequest	true	"Transaction PIN"
+//	@Success		200		{object}	dto.APIResponse
+//	@Failure		400		{object}	dto.ErrorResponse
+//	@Failure		401		{object}	dto.ErrorResponse
+//	@Failure		409		{object}	dto.ErrorResponse	"A PIN is already set (code PIN_ALREADY_SET)"
+//	@Failure		500		{object}	dto.ErrorResponse
+//	@Router			/users/me/pin [post]
+func (h *PINHandler) SetPIN(c *gin.Context) {
+	userID, ok := h.userID(c)
+	if !ok {
+		return
+	}
+
+	var req dto.SetPINRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.Error(apperrors.ErrValidation.Withf("invalid request data: %w", err)).SetMeta("request.invalid")
+		return
+	}
+
+	if err := h.pinUseCase.SetPIN(c.Request.Context(), userID, req.PIN); err != nil {
+		c.Error(err).SetMeta("pin.set_failed")
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.APIResponse{
+		Success: true,
+		Message: middleware.Translate(c, "pin.set"),
+	})
+}
+
+// ChangePIN godoc
+//
+//	@Summary		Change transaction PIN
+//	@Description	Replace the transaction PIN. A wrong current PIN counts towards locking the PIN
+//	@Tags			users
+//	@Accept			json
+//	@Produce		json
+//	@Security		BearerAuth
+//	@Param			request	body		dto.ChangePINRequest	true	"Current and new PIN"
+//	@Success		200		{object}	dto.APIResponse
+//	@Failure		400		{object}	dto.ErrorResponse	"Invalid PIN or no PIN set (code PIN_NOT_SET)"
+//	@Failure		401		{object}	dto.ErrorResponse
+//	@Failure		403		{object}	dto.ErrorResponse	"Wrong or locked current PIN (codes INVALID_PIN, PIN_LOCKED)"
+//	@Failure		500		{object}	dto.ErrorResponse
+//	@Router			/users/me/pin [put]
+func (h *PINHandler) ChangePIN(c *gin.Context) {
+	userID, ok := h.userID(c)
+	if !ok {
+		return
+	}
+
+	var req dto.ChangePINRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.Error(apperrors.ErrValidation.Withf("invalid request data: %w", err)).SetMeta("request.invalid")
+		return
+	}
+
+	if err := h.pinUseCase.ChangePIN(c.Request.Context(), userID, req.CurrentPIN, req.NewPIN); err != nil {
+		c.Error(err).SetMeta("pin.change_failed")
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.APIResponse{
+		Success: true,
+		Message: middleware.Translate(c, "pin.changed"),
+	})
+}
+
+// ResetPIN godoc
+//
+//	@Summary		Reset transaction PIN
+//	@Description	Replace a forgotten or locked transaction PIN after confirming the account password; the PIN is unlocked
+//	@Tags			users
+//	@Accept			json
+//	@Produce		json
+//	@Security		BearerAuth
+//	@Param			request	body		dto.ResetPINRequest	true	"Password and new PIN"
+//	@Success		200		{object}	dto.APIResponse
+//	@Failure		400		{object}	dto.ErrorResponse	"Invalid PIN or wrong password (code INCORRECT_PASSWORD)"
+//	@Failure		401		{object}	dto.ErrorResponse
+//	@Failure		500		{object}	dto.ErrorResponse
+//	@Router			/users/me/pin/reset [post]
+func (h *PINHandler) ResetPIN(c *gin.Context) {
+	userID, ok := h.userID(c)
+	if !ok {
+		return
+	}
+
+	var req dto.ResetPINRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.Error(apperrors.ErrValidation.Withf("invalid request data: %w", err)).SetMeta("request.invalid")
+		return
+	}
+
+	if err := h.pinUseCase.ResetPIN(c.Request.Context(), userID, req.Password, req.NewPIN); err != nil {
+		c.Error(err).SetMeta("pin.reset_failed")
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.APIResponse{
+		Success: true,
+		Message: middleware.Translate(c, "pin.reset"),
+	})
+}
+
+// userID writes a 401 response when the request carries no authenticated user
+func (h *PINHandler) userID(c *gin.Context) (uint, bool) {
+	userID, exists := middleware.GetUserID(c)
+	if !exists {
+		c.Error(apperrors.ErrUnauthenticated)
+	}
+	return userID, exists
+}
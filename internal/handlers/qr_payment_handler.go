@@ -0,0 +1,154 @@
+package handlers
+
+import (
+	"encoding/base64"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/limistah/wallet-service/internal/apperrors"
+	"github.com/limistah/wallet-service/internal/dto"
+	"github.com/limistah/wallet-service/internal/middleware"
+	"github.com/limistah/wallet-service/internal/usecases"
+	"github.com/shopspring/decimal"
+)
+
+const (
+	defaultQRSize = 256
+	maxQRSize     = 1024
+)
+
+type QRPaymentHandler struct {
+	qrPaymentUseCase usecases.QRPaymentUseCase
+}
+
+func NewQRPaymentHandler(qrPaymentUseCase usecases.QRPaymentUseCase) *QRPaymentHandler {
+	return &QRPaymentHandler{
+		qrPaymentUseCase: qrPaymentUseCase,
+	}
+}
+
+// GenerateCode godoc
+//
+//	@Summary		Generate a payment QR code
+//	@Description	Generate a QR code other users can scan to pay the authenticated user's wallet. Returns JSON with the payload and a base64 PNG, or the PNG itself when format=png.
+//	@Tags			qr-payments
+//	@Produce		json
+//	@Produce		png
+//	@Security		BearerAuth
+//	@Param			amount	query		string	false	"Fixed amount to request"
+//	@Param			note	query		string	false	"Note shown to the payer"
+//	@Param			size	query		int		false	"Image size in pixels"	default(256)
+//	@Param			format	query		string	false	"png to return the image directly"
+//	@Success		200		{object}	dto.APIResponse{data=dto.QRCodeResponse}
+//	@Failure		400		{object}	dto.ErrorResponse
+//	@Failure		401		{object}	dto.ErrorResponse
+//	@Failure		403		{object}	dto.ErrorResponse
+//	@Failure		404		{object}	dto.ErrorResponse
+//	@Failure		500		{object}	dto.ErrorResponse
+//	@Router			/wallets/me/qr [get]
+func (h *QRPaymentHandler) GenerateCode(c *gin.Context) {
+	userID, exists := middleware.GetUserID(c)
+	if !exists {
+		c.Error(apperrors.ErrUnauthenticated)
+		return
+	}
+
+	if rejectSandbox(c) {
+		return
+	}
+
+	var amount *decimal.Decimal
+	if raw := c.Query("amount"); raw != "" {
+		parsed, err := decimal.NewFromString(raw)
+		if err != nil {
+			c.Error(apperrors.ErrValidation.Withf("invalid amount: %w", err)).SetMeta("qr.invalid_amount")
+			return
+		}
+		amount = &parsed
+	}
+
+	size := defaultQRSize
+	if raw := c.Query("size"); raw != "" {
+		if parsed, err := strconv.Atoi(raw); err == nil && parsed > 0 && parsed <= maxQRSize {
+			size = parsed
+		}
+	}
+
+	payload, err := h.qrPaymentUseCase.GenerateCode(c.Request.Context(), userID, amount, c.Query("note"))
+	if err != nil {
+		c.Error(err).SetMeta("qr.code_generate_failed")
+		return
+	}
+
+	png, err := payload.PNG(size)
+	if err != nil {
+		c.Error(err).SetMeta("qr.code_generate_failed")
+		return
+	}
+
+	if c.Query("format") == "png" {
+		c.Data(http.StatusOK, "image/png", png)
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.APIResponse{
+		Success: true,
+		Message: middleware.Translate(c, "qr.code_generated"),
+		Data: dto.QRCodeResponse{
+			Payload:  payload.Encode(),
+			WalletID: payload.WalletID,
+			Currency: payload.Currency,
+			Amount:   payload.Amount,
+			Note:     payload.Note,
+			PNG:      base64.StdEncoding.EncodeToString(png),
+		},
+	})
+}
+
+// PayCode godoc
+//
+//	@Summary		Scan and pay a QR code
+//	@Description	Decode a scanned payment QR payload and transfer from the authenticated user's wallet to the wallet it names. Users who set a transaction PIN send it with payments above the PIN threshold. Amounts above the step-up threshold are refused; they are paid as transfers confirmed with a one-time code
+//	@Tags			qr-payments
+//	@Accept			json
+//	@Produce		json
+//	@Security		BearerAuth
+//	@Param			request	body		dto.PayQRCodeRequest	true	"Scanned payload"
+//	@Success		200		{object}	dto.APIResponse{data=dto.TransactionResponse}
+//	@Failure		400		{object}	dto.ErrorResponse
+//	@Failure		401		{object}	dto.ErrorResponse
+//	@Failure		403		{object}	dto.ErrorResponse	"Missing, wrong or locked transaction PIN (codes PIN_REQUIRED, INVALID_PIN, PIN_LOCKED)"
+//	@Failure		404		{object}	dto.ErrorResponse
+//	@Failure		409		{object}	dto.ErrorResponse	"Insufficient funds"
+//	@Failure		500		{object}	dto.ErrorResponse
+//	@Router			/wallets/me/qr/pay [post]
+func (h *QRPaymentHandler) PayCode(c *gin.Context) {
+	userID, exists := middleware.GetUserID(c)
+	if !exists {
+		c.Error(apperrors.ErrUnauthenticated)
+		return
+	}
+
+	if rejectSandbox(c) {
+		return
+	}
+
+	var req dto.PayQRCodeRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.Error(apperrors.ErrValidation.Withf("invalid request data: %w", err)).SetMeta("request.invalid")
+		return
+	}
+
+	transaction, err := h.qrPaymentUseCase.PayCode(c.Request.Context(), userID, req.Payload, req.Amount, req.PIN, middleware.GetOrigin(c))
+	if err != nil {
+		c.Error(err).SetMeta("qr.code_pay_failed")
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.APIResponse{
+		Success: true,
+		Message: middleware.Translate(c, "qr.code_paid"),
+		Data:    dto.ToTransactionResponse(transaction),
+	})
+}
@@ -0,0 +1,80 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/limistah/wallet-service/internal/apperrors"
+	"github.com/limistah/wallet-service/internal/dto"
+	"github.com/limistah/wallet-service/internal/middleware"
+	"github.com/limistah/wallet-service/internal/usecases"
+	"github.com/shopspring/decimal"
+)
+
+type SandboxHandler struct {
+	walletUseCase usecases.WalletUseCase
+}
+
+func NewSandboxHandler(walletUseCase usecases.WalletUseCase) *SandboxHandler {
+	return &SandboxHandler{
+		walletUseCase: walletUseCase,
+	}
+}
+
+// MintFunds godoc
+//
+//	@Summary		Mint sandbox funds
+//	@Description	Credit arbitrary test money to the authenticated user's sandbox wallet. Requires a token issued with sandbox enabled.
+//	@Tags			sandbox
+//	@Accept			json
+//	@Produce		json
+//	@Security		BearerAuth
+//	@Param			request	body		dto.MintSandboxFundsRequest	true	"Amount to mint"
+//	@Success		201		{object}	dto.APIResponse{data=dto.TransactionResponse}
+//	@Failure		400		{object}	dto.ErrorResponse
+//	@Failure		401		{object}	dto.ErrorResponse
+//	@Failure		403		{object}	dto.ErrorResponse
+//	@Failure		404		{object}	dto.ErrorResponse
+//	@Failure		500		{object}	dto.ErrorResponse
+//	@Router			/sandbox/mint [post]
+func (h *SandboxHandler) MintFunds(c *gin.Context) {
+	userID, exists := middleware.GetUserID(c)
+	if !exists {
+		c.Error(apperrors.ErrUnauthenticated)
+		return
+	}
+
+	if !middleware.IsSandbox(c) {
+		c.Error(apperrors.ErrSandboxTokenRequired)
+		return
+	}
+
+	var req dto.MintSandboxFundsRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.Error(apperrors.ErrValidation.Withf("invalid request data: %w", err)).SetMeta("request.invalid")
+		return
+	}
+
+	if req.Amount.LessThanOrEqual(decimal.Zero) {
+		c.Error(apperrors.ErrInvalidAmount)
+		return
+	}
+
+	wallet, err := h.walletUseCase.GetSandboxWallet(c.Request.Context(), userID)
+	if err != nil {
+		c.Error(err).SetMeta("sandbox.wallet_unavailable")
+		return
+	}
+
+	transaction, err := h.walletUseCase.MintSandboxFunds(c.Request.Context(), wallet.ID, req.Amount)
+	if err != nil {
+		c.Error(err).SetMeta("sandbox.funds_mint_failed")
+		return
+	}
+
+	c.JSON(http.StatusCreated, dto.APIResponse{
+		Success: true,
+		Message: middleware.Translate(c, "sandbox.funds_minted"),
+		Data:    dto.ToTransactionResponse(transaction),
+	})
+}
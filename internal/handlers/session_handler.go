@@ -0,0 +1,95 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/limistah/wallet-service/internal/apperrors"
+	"github.com/limistah/wallet-service/internal/dto"
+	"github.com/limistah/wallet-service/internal/middleware"
+	"github.com/limistah/wallet-service/internal/usecases"
+)
+
+type SessionHandler struct {
+	sessionUseCase usecases.SessionUseCase
+}
+
+func NewSessionHandler(sessionUseCase usecases.SessionUseCase) *SessionHandler {
+	return &SessionHandler{
+		sessionUseCase: sessionUseCase,
+	}
+}
+
+// ListSessions godoc
+//
+//	@Summary		List active sessions
+//	@Description	List the devices holding a valid token of the authenticated user, latest first; the session of the token used for this request is marked as current
+//	@Tags			auth
+//	@Produce		json
+//	@Security		BearerAuth
+//	@Success		200	{object}	dto.APIResponse{data=[]dto.SessionResponse}
+//	@Failure		401	{object}	dto.ErrorResponse
+//	@Failure		500	{object}	dto.ErrorResponse
+//	@Router			/auth/sessions [get]
+func (h *SessionHandler) ListSessions(c *gin.Context) {
+	claims, exists := middleware.GetTokenClaims(c)
+	if !exists {
+		c.Error(apperrors.ErrUnauthenticated)
+		return
+	}
+
+	sessions, err := h.sessionUseCase.ListSessions(c.Request.Context(), claims.UserID)
+	if err != nil {
+		c.Error(err).SetMeta("session.list_failed")
+		return
+	}
+
+	sessionResponses := make([]dto.SessionResponse, len(sessions))
+	for i, session := range sessions {
+		sessionResponses[i] = dto.ToSessionResponse(&session, claims.ID)
+	}
+
+	c.JSON(http.StatusOK, dto.APIResponse{
+		Success: true,
+		Message: middleware.Translate(c, "session.list_retrieved"),
+		Data:    sessionResponses,
+	})
+}
+
+// RevokeSession godoc
+//
+//	@Summary		Revoke a session
+//	@Description	Sign a device of the authenticated user out, for example a lost phone, without changing the password; its token is refused from now on
+//	@Tags			auth
+//	@Produce		json
+//	@Security		BearerAuth
+//	@Param			id	path		int	true	"Session ID"
+//	@Success		200	{object}	dto.APIResponse
+//	@Failure		400	{object}	dto.ErrorResponse
+//	@Failure		401	{object}	dto.ErrorResponse
+//	@Failure		404	{object}	dto.ErrorResponse
+//	@Failure		500	{object}	dto.ErrorResponse
+//	@Router			/auth/sessions/{id} [delete]
+func (h *SessionHandler) RevokeSession(c *gin.Context) {
+	userID, exists := middleware.GetUserID(c)
+	if !exists {
+		c.Error(apperrors.ErrUnauthenticated)
+		return
+	}
+
+	sessionID, err := parseIDParam(c, "id")
+	if err != nil {
+		c.Error(err).SetMeta("session.invalid_id")
+		return
+	}
+
+	if err := h.sessionUseCase.RevokeSession(c.Request.Context(), userID, sessionID); err != nil {
+		c.Error(err).SetMeta("session.revoke_failed")
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.APIResponse{
+		Success: true,
+		Message: middleware.Translate(c, "session.revoked"),
+	})
+}
@@ -0,0 +1,216 @@
+package handlers
+
+import (
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/limistah/wallet-service/internal/apperrors"
+	"github.com/limistah/wallet-service/internal/dto"
+	"github.com/limistah/wallet-service/internal/middleware"
+	"github.com/limistah/wallet-service/internal/models"
+	"github.com/limistah/wallet-service/internal/repositories"
+	"github.com/limistah/wallet-service/internal/usecases"
+)
+
+type SettlementHandler struct {
+	settlementUseCase usecases.SettlementUseCase
+}
+
+func NewSettlementHandler(settlementUseCase usecases.SettlementUseCase) *SettlementHandler {
+	return &SettlementHandler{
+		settlementUseCase: settlementUseCase,
+	}
+}
+
+// ListSettlementBatches godoc
+//
+//	@Summary		List settlement batches
+//	@Description	List the daily provider settlement batches with their expected and received nets, newest day first (admin and support)
+//	@Tags			admin
+//	@Accept			json
+//	@Produce		json
+//	@Security		BearerAuth
+//	@Param			provider	query		string	false	"Provider name, e.g. paystack"
+//	@Param			status		query		string	false	"Batch status (PENDING, MATCHED, MISMATCHED); omit for every batch"
+//	@Param			from		query		string	false	"First settlement day (YYYY-MM-DD)"
+//	@Param			to			query		string	false	"Last settlement day (YYYY-MM-DD)"
+//	@Param			page		query		int		false	"Page number"	default(1)
+//	@Param			page_size	query		int		false	"Page size"		default(20)
+//	@Success		200			{object}	dto.APIResponse{data=[]dto.SettlementBatchResponse}
+//	@Failure		400			{object}	dto.ErrorResponse
+//	@Failure		401			{object}	dto.ErrorResponse
+//	@Failure		403			{object}	dto.ErrorResponse
+//	@Failure		500			{object}	dto.ErrorResponse
+//	@Router			/admin/settlements [get]
+func (h *SettlementHandler) ListSettlementBatches(c *gin.Context) {
+	filter := repositories.SettlementBatchFilter{
+		Provider: strings.TrimSpace(c.Query("provider")),
+		Status:   models.SettlementBatchStatus(strings.ToUpper(c.Query("status"))),
+	}
+	switch filter.Status {
+	case "", models.SettlementBatchStatusPending, models.SettlementBatchStatusMatched, models.SettlementBatchStatusMismatched:
+	default:
+		c.Error(apperrors.ErrValidation.Withf("invalid status")).SetMeta("settlement.invalid_status")
+		return
+	}
+
+	var err error
+	if filter.From, err = parseTimeQuery(c, "from", false); err == nil {
+		filter.To, err = parseTimeQuery(c, "to", true)
+	}
+	if err != nil {
+		c.Error(err).SetMeta("request.invalid_date_param")
+		return
+	}
+
+	page, pageSize := parsePagination(c)
+	batches, err := h.settlementUseCase.ListBatches(c.Request.Context(), filter, page, pageSize)
+	if err != nil {
+		c.Error(err).SetMeta("settlement.batches_retrieve_failed")
+		return
+	}
+
+	responses := make([]dto.SettlementBatchResponse, len(batches))
+	for i := range batches {
+		responses[i] = dto.ToSettlementBatchResponse(&batches[i])
+	}
+
+	c.JSON(http.StatusOK, dto.APIResponse{
+		Success: true,
+		Message: middleware.Translate(c, "settlement.batches_retrieved"),
+		Data:    responses,
+	})
+}
+
+// GetSettlementBatch godoc
+//
+//	@Summary		Get a settlement batch report
+//	@Description	Get a settlement batch with the deposits and payouts it holds, for reconciliation against the provider statement (admin and support)
+//	@Tags			admin
+//	@Accept			json
+//	@Produce		json
+//	@Security		BearerAuth
+//	@Param			id	path		int	true	"Settlement batch ID"
+//	@Success		200	{object}	dto.APIResponse{data=dto.SettlementBatchReportResponse}
+//	@Failure		400	{object}	dto.ErrorResponse
+//	@Failure		401	{object}	dto.ErrorResponse
+//	@Failure		403	{object}	dto.ErrorResponse
+//	@Failure		404	{object}	dto.ErrorResponse
+//	@Failure		500	{object}	dto.ErrorResponse
+//	@Router			/admin/settlements/{id} [get]
+func (h *SettlementHandler) GetSettlementBatch(c *gin.Context) {
+	batchID, err := parseIDParam(c, "id")
+	if err != nil {
+		c.Error(err).SetMeta("settlement.invalid_batch_id")
+		return
+	}
+
+	batch, err := h.settlementUseCase.GetBatch(c.Request.Context(), batchID)
+	if err != nil {
+		c.Error(err).SetMeta("settlement.batch_retrieve_failed")
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.APIResponse{
+		Success: true,
+		Message: middleware.Translate(c, "settlement.batch_retrieved"),
+		Data:    dto.ToSettlementBatchReportResponse(batch),
+	})
+}
+
+// CloseSettlementDay godoc
+//
+//	@Summary		Close a settlement day
+//	@Description	Batch the deposits and payouts completed on a UTC day that are not batched yet; the scheduler does this for the previous day, this closes an earlier day again (admin only)
+//	@Tags			admin
+//	@Accept			json
+//	@Produce		json
+//	@Security		BearerAuth
+//	@Param			request	body		dto.CloseSettlementDayRequest	true	"Settlement day"
+//	@Success		200		{object}	dto.APIResponse{data=[]dto.SettlementBatchResponse}
+//	@Failure		400		{object}	dto.ErrorResponse
+//	@Failure		401		{object}	dto.ErrorResponse
+//	@Failure		403		{object}	dto.ErrorResponse
+//	@Failure		409		{object}	dto.ErrorResponse	"Day being closed concurrently"
+//	@Failure		500		{object}	dto.ErrorResponse
+//	@Router			/admin/settlements/close [post]
+func (h *SettlementHandler) CloseSettlementDay(c *gin.Context) {
+	var req dto.CloseSettlementDayRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.Error(apperrors.ErrValidation.Withf("invalid request data: %w", err)).SetMeta("request.invalid")
+		return
+	}
+	day, err := time.Parse("2006-01-02", req.Date)
+	if err != nil {
+		c.Error(apperrors.ErrValidation.Withf("invalid date: %w", err)).SetMeta("request.invalid_date")
+		return
+	}
+
+	batches, err := h.settlementUseCase.CloseDay(c.Request.Context(), day)
+	if err != nil {
+		c.Error(err).SetMeta("settlement.day_close_failed")
+		return
+	}
+
+	responses := make([]dto.SettlementBatchResponse, len(batches))
+	for i := range batches {
+		responses[i] = dto.ToSettlementBatchResponse(&batches[i])
+	}
+
+	c.JSON(http.StatusOK, dto.APIResponse{
+		Success: true,
+		Message: middleware.Translate(c, "settlement.day_closed"),
+		Data:    responses,
+	})
+}
+
+// RecordSettlementReceived godoc
+//
+//	@Summary		Record a provider settlement
+//	@Description	Record the net a provider settled for a batch from its statement; the batch is marked MATCHED when it equals the expected net and MISMATCHED otherwise (admin only)
+//	@Tags			admin
+//	@Accept			json
+//	@Produce		json
+//	@Security		BearerAuth
+//	@Param			id		path		int							true	"Settlement batch ID"
+//	@Param			request	body		dto.RecordSettlementRequest	true	"Received net"
+//	@Success		200		{object}	dto.APIResponse{data=dto.SettlementBatchResponse}
+//	@Failure		400		{object}	dto.ErrorResponse
+//	@Failure		401		{object}	dto.ErrorResponse
+//	@Failure		403		{object}	dto.ErrorResponse
+//	@Failure		404		{object}	dto.ErrorResponse
+//	@Failure		500		{object}	dto.ErrorResponse
+//	@Router			/admin/settlements/{id}/received [post]
+func (h *SettlementHandler) RecordSettlementReceived(c *gin.Context) {
+	adminID, exists := middleware.GetUserID(c)
+	if !exists {
+		c.Error(apperrors.ErrUnauthenticated)
+		return
+	}
+
+	batchID, err := parseIDParam(c, "id")
+	if err != nil {
+		c.Error(err).SetMeta("settlement.invalid_batch_id")
+		return
+	}
+
+	var req dto.RecordSettlementRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.Error(apperrors.ErrValidation.Withf("invalid request data: %w", err)).SetMeta("request.invalid")
+		return
+	}
+
+	batch, err := h.settlementUseCase.RecordReceived(c.Request.Context(), batchID, adminID, *req.ReceivedNet, strings.TrimSpace(req.Note))
+	if err != nil {
+		c.Error(err).SetMeta("settlement.record_failed")
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.APIResponse{
+		Success: true,
+		Message: middleware.Translate(c, "settlement.recorded"),
+		Data:    dto.ToSettlementBatchResponse(batch),
+	})
+}
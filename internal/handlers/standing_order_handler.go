@@ -0,0 +1,203 @@
+package handlers
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/limistah/wallet-service/internal/apperrors"
+	"github.com/limistah/wallet-service/internal/dto"
+	"github.com/limistah/wallet-service/internal/middleware"
+	"github.com/limistah/wallet-service/internal/models"
+	"github.com/limistah/wallet-service/internal/usecases"
+)
+
+type StandingOrderHandler struct {
+	standingOrderUseCase usecases.StandingOrderUseCase
+}
+
+func NewStandingOrderHandler(standingOrderUseCase usecases.StandingOrderUseCase) *StandingOrderHandler {
+	return &StandingOrderHandler{
+		standingOrderUseCase: standingOrderUseCase,
+	}
+}
+
+// CreateStandingOrder godoc
+//
+//	@Summary		Create a standing order
+//	@Description	Schedule a recurring transfer from the authenticated user's wallet. When the wallet cannot cover an occurrence it is retried with backoff for a grace period before it is marked failed and the user is notified. Users who set a transaction PIN send it when the amount is above the PIN threshold; it authorizes every occurrence.
+//	@Tags			standing-orders
+//	@Accept			json
+//	@Produce		json
+//	@Security		BearerAuth
+//	@Param			request	body		dto.CreateStandingOrderRequest	true	"Standing order"
+//	@Success		201		{object}	dto.APIResponse{data=dto.StandingOrderResponse}
+//	@Failure		400		{object}	dto.ErrorResponse
+//	@Failure		401		{object}	dto.ErrorResponse
+//	@Failure		403		{object}	dto.ErrorResponse	"Missing, wrong or locked transaction PIN (codes PIN_REQUIRED, INVALID_PIN, PIN_LOCKED)"
+//	@Failure		404		{object}	dto.ErrorResponse
+//	@Failure		500		{object}	dto.ErrorResponse
+//	@Router			/wallets/me/standing-orders [post]
+func (h *StandingOrderHandler) CreateStandingOrder(c *gin.Context) {
+	userID, exists := middleware.GetUserID(c)
+	if !exists {
+		c.Error(apperrors.ErrUnauthenticated)
+		return
+	}
+
+	if rejectSandbox(c) {
+		return
+	}
+
+	var req dto.CreateStandingOrderRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.Error(apperrors.ErrValidation.Withf("invalid request data: %w", err)).SetMeta("request.invalid")
+		return
+	}
+
+	var startAt time.Time
+	if req.StartAt != nil {
+		startAt = *req.StartAt
+	}
+
+	order, err := h.standingOrderUseCase.CreateStandingOrder(c.Request.Context(), userID, req.DestinationWalletID, req.Amount,
+		models.StandingOrderFrequency(req.Frequency), startAt, req.EndsAt, req.Description, req.PIN)
+	if err != nil {
+		c.Error(err).SetMeta("standing_order.create_failed")
+		return
+	}
+
+	c.JSON(http.StatusCreated, dto.APIResponse{
+		Success: true,
+		Message: middleware.Translate(c, "standing_order.created"),
+		Data:    dto.ToStandingOrderResponse(order),
+	})
+}
+
+// ListStandingOrders godoc
+//
+//	@Summary		List standing orders
+//	@Description	List the standing orders of the authenticated user
+//	@Tags			standing-orders
+//	@Accept			json
+//	@Produce		json
+//	@Security		BearerAuth
+//	@Success		200	{object}	dto.APIResponse{data=[]dto.StandingOrderResponse}
+//	@Failure		401	{object}	dto.ErrorResponse
+//	@Failure		500	{object}	dto.ErrorResponse
+//	@Router			/wallets/me/standing-orders [get]
+func (h *StandingOrderHandler) ListStandingOrders(c *gin.Context) {
+	userID, exists := middleware.GetUserID(c)
+	if !exists {
+		c.Error(apperrors.ErrUnauthenticated)
+		return
+	}
+
+	orders, err := h.standingOrderUseCase.ListStandingOrders(c.Request.Context(), userID)
+	if err != nil {
+		c.Error(err).SetMeta("standing_order.list_failed")
+		return
+	}
+
+	responses := make([]dto.StandingOrderResponse, len(orders))
+	for i, order := range orders {
+		responses[i] = dto.ToStandingOrderResponse(&order)
+	}
+
+	c.JSON(http.StatusOK, dto.APIResponse{
+		Success: true,
+		Message: middleware.Translate(c, "standing_order.list_retrieved"),
+		Data:    responses,
+	})
+}
+
+// CancelStandingOrder godoc
+//
+//	@Summary		Cancel a standing order
+//	@Description	Stop a standing order of the authenticated user; occurrences still being retried are not paid
+//	@Tags			standing-orders
+//	@Accept			json
+//	@Produce		json
+//	@Security		BearerAuth
+//	@Param			id	path		int	true	"Standing order ID"
+//	@Success		200	{object}	dto.APIResponse{data=dto.StandingOrderResponse}
+//	@Failure		400	{object}	dto.ErrorResponse
+//	@Failure		401	{object}	dto.ErrorResponse
+//	@Failure		404	{object}	dto.ErrorResponse
+//	@Failure		409	{object}	dto.ErrorResponse	"Standing order already cancelled or completed"
+//	@Failure		500	{object}	dto.ErrorResponse
+//	@Router			/wallets/me/standing-orders/{id} [delete]
+func (h *StandingOrderHandler) CancelStandingOrder(c *gin.Context) {
+	userID, exists := middleware.GetUserID(c)
+	if !exists {
+		c.Error(apperrors.ErrUnauthenticated)
+		return
+	}
+
+	orderID, err := parseIDParam(c, "id")
+	if err != nil {
+		c.Error(err).SetMeta("standing_order.invalid_id")
+		return
+	}
+
+	order, err := h.standingOrderUseCase.CancelStandingOrder(c.Request.Context(), userID, orderID)
+	if err != nil {
+		c.Error(err).SetMeta("standing_order.cancel_failed")
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.APIResponse{
+		Success: true,
+		Message: middleware.Translate(c, "standing_order.cancelled"),
+		Data:    dto.ToStandingOrderResponse(order),
+	})
+}
+
+// ListRuns godoc
+//
+//	@Summary		List standing order occurrences
+//	@Description	List the scheduled occurrences of a standing order with their retry state
+//	@Tags			standing-orders
+//	@Accept			json
+//	@Produce		json
+//	@Security		BearerAuth
+//	@Param			id			path		int	true	"Standing order ID"
+//	@Param			page		query		int	false	"Page number"	default(1)
+//	@Param			page_size	query		int	false	"Page size"		default(20)
+//	@Success		200			{object}	dto.APIResponse{data=[]dto.StandingOrderRunResponse}
+//	@Failure		400			{object}	dto.ErrorResponse
+//	@Failure		401			{object}	dto.ErrorResponse
+//	@Failure		404			{object}	dto.ErrorResponse
+//	@Failure		500			{object}	dto.ErrorResponse
+//	@Router			/wallets/me/standing-orders/{id}/runs [get]
+func (h *StandingOrderHandler) ListRuns(c *gin.Context) {
+	userID, exists := middleware.GetUserID(c)
+	if !exists {
+		c.Error(apperrors.ErrUnauthenticated)
+		return
+	}
+
+	orderID, err := parseIDParam(c, "id")
+	if err != nil {
+		c.Error(err).SetMeta("standing_order.invalid_id")
+		return
+	}
+
+	page, pageSize := parsePagination(c)
+	runs, err := h.standingOrderUseCase.ListRuns(c.Request.Context(), userID, orderID, page, pageSize)
+	if err != nil {
+		c.Error(err).SetMeta("standing_order.occurrences_retrieve_failed")
+		return
+	}
+
+	responses := make([]dto.StandingOrderRunResponse, len(runs))
+	for i, run := range runs {
+		responses[i] = dto.ToStandingOrderRunResponse(&run)
+	}
+
+	c.JSON(http.StatusOK, dto.APIResponse{
+		Success: true,
+		Message: middleware.Translate(c, "standing_order.occurrences_retrieved"),
+		Data:    responses,
+	})
+}
@@ -0,0 +1,276 @@
+package handlers
+
+import (
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/limistah/wallet-service/internal/apperrors"
+	"github.com/limistah/wallet-service/internal/dto"
+	"github.com/limistah/wallet-service/internal/middleware"
+	"github.com/limistah/wallet-service/internal/models"
+	"github.com/limistah/wallet-service/internal/repositories"
+	"github.com/limistah/wallet-service/internal/usecases"
+)
+
+type StatementHandler struct {
+	statementUseCase usecases.StatementUseCase
+}
+
+func NewStatementHandler(statementUseCase usecases.StatementUseCase) *StatementHandler {
+	return &StatementHandler{
+		statementUseCase: statementUseCase,
+	}
+}
+
+// ImportStatement godoc
+//
+//	@Summary		Import a provider statement
+//	@Description	Upload a provider settlement file as CSV with a header row. The reference and amount columns are required; currency, type (CREDIT or DEBIT) and date are optional. Lines are matched to deposits and payouts by reference and amount, and lines that do not match are flagged as exceptions (admin only)
+//	@Tags			admin
+//	@Accept			multipart/form-data
+//	@Produce		json
+//	@Security		BearerAuth
+//	@Param			provider	formData	string	true	"Provider name, e.g. paystack"
+//	@Param			file		formData	file	true	"Statement CSV file"
+//	@Success		201			{object}	dto.APIResponse{data=dto.ProviderStatementResponse}
+//	@Failure		400			{object}	dto.ErrorResponse	"Invalid statement file"
+//	@Failure		401			{object}	dto.ErrorResponse
+//	@Failure		403			{object}	dto.ErrorResponse
+//	@Failure		409			{object}	dto.ErrorResponse	"Statement file already imported"
+//	@Failure		413			{object}	dto.ErrorResponse
+//	@Failure		500			{object}	dto.ErrorResponse
+//	@Router			/admin/statements [post]
+func (h *StatementHandler) ImportStatement(c *gin.Context) {
+	adminID, exists := middleware.GetUserID(c)
+	if !exists {
+		c.Error(apperrors.ErrUnauthenticated)
+		return
+	}
+
+	fileHeader, err := c.FormFile("file")
+	if err != nil {
+		// A form cut off by the body limit is reported as too large rather than as a missing file
+		if apperrors.HTTPStatus(err) != http.StatusRequestEntityTooLarge {
+			err = apperrors.ErrValidation.Withf("statement file is required: %w", err)
+		}
+		c.Error(err).SetMeta("statement.file_required")
+		return
+	}
+	file, err := fileHeader.Open()
+	if err != nil {
+		c.Error(apperrors.ErrValidation.Withf("unreadable statement file: %w", err)).SetMeta("statement.file_read_failed")
+		return
+	}
+	defer file.Close()
+	content, err := io.ReadAll(file)
+	if err != nil {
+		c.Error(apperrors.ErrValidation.Withf("unreadable statement file: %w", err)).SetMeta("statement.file_read_failed")
+		return
+	}
+
+	statement, err := h.statementUseCase.Import(c.Request.Context(), c.PostForm("provider"), fileHeader.Filename, adminID, content)
+	if err != nil {
+		c.Error(err).SetMeta("statement.import_failed")
+		return
+	}
+
+	c.JSON(http.StatusCreated, dto.APIResponse{
+		Success: true,
+		Message: middleware.Translate(c, "statement.imported"),
+		Data:    dto.ToProviderStatementResponse(statement),
+	})
+}
+
+// ListStatements godoc
+//
+//	@Summary		List provider statements
+//	@Description	List imported provider statements with their matched and exception counts, newest first (admin and support)
+//	@Tags			admin
+//	@Accept			json
+//	@Produce		json
+//	@Security		BearerAuth
+//	@Param			provider	query		string	false	"Provider name, e.g. paystack"
+//	@Param			page		query		int		false	"Page number"	default(1)
+//	@Param			page_size	query		int		false	"Page size"		default(20)
+//	@Success		200			{object}	dto.APIResponse{data=[]dto.ProviderStatementResponse}
+//	@Header			200			{string}	Link	"RFC 5988 links to the first, previous and next pages"
+//	@Failure		401			{object}	dto.ErrorResponse
+//	@Failure		403			{object}	dto.ErrorResponse
+//	@Failure		500			{object}	dto.ErrorResponse
+//	@Router			/admin/statements [get]
+func (h *StatementHandler) ListStatements(c *gin.Context) {
+	page, pageSize := parsePagination(c)
+	statements, err := h.statementUseCase.ListStatements(c.Request.Context(), strings.TrimSpace(c.Query("provider")), page, pageSize)
+	if err != nil {
+		c.Error(err).SetMeta("statement.list_failed")
+		return
+	}
+
+	responses := make([]dto.ProviderStatementResponse, len(statements))
+	for i := range statements {
+		responses[i] = dto.ToProviderStatementResponse(&statements[i])
+	}
+
+	setPageLinks(c, page, len(statements) == pageSize)
+
+	c.JSON(http.StatusOK, dto.APIResponse{
+		Success: true,
+		Message: middleware.Translate(c, "statement.list_retrieved"),
+		Data:    responses,
+	})
+}
+
+// GetStatement godoc
+//
+//	@Summary		Get a provider statement
+//	@Description	Get an imported provider statement with every line and what it matched (admin and support)
+//	@Tags			admin
+//	@Accept			json
+//	@Produce		json
+//	@Security		BearerAuth
+//	@Param			id	path		int	true	"Statement ID"
+//	@Success		200	{object}	dto.APIResponse{data=dto.ProviderStatementReportResponse}
+//	@Failure		400	{object}	dto.ErrorResponse
+//	@Failure		401	{object}	dto.ErrorResponse
+//	@Failure		403	{object}	dto.ErrorResponse
+//	@Failure		404	{object}	dto.ErrorResponse
+//	@Failure		500	{object}	dto.ErrorResponse
+//	@Router			/admin/statements/{id} [get]
+func (h *StatementHandler) GetStatement(c *gin.Context) {
+	statementID, err := parseIDParam(c, "id")
+	if err != nil {
+		c.Error(err).SetMeta("statement.invalid_id")
+		return
+	}
+
+	statement, err := h.statementUseCase.GetStatement(c.Request.Context(), statementID)
+	if err != nil {
+		c.Error(err).SetMeta("statement.retrieve_failed")
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.APIResponse{
+		Success: true,
+		Message: middleware.Translate(c, "statement.retrieved"),
+		Data:    dto.ToProviderStatementReportResponse(statement),
+	})
+}
+
+// ListStatementExceptions godoc
+//
+//	@Summary		List statement exceptions
+//	@Description	List statement lines that did not match a deposit or payout, oldest first, for review (admin and support)
+//	@Tags			admin
+//	@Accept			json
+//	@Produce		json
+//	@Security		BearerAuth
+//	@Param			provider		query		string	false	"Provider name, e.g. paystack"
+//	@Param			status			query		string	false	"Line status (EXCEPTION, RESOLVED)"	default(EXCEPTION)
+//	@Param			reason			query		string	false	"Exception reason (NOT_FOUND, AMOUNT_MISMATCH, STATUS_MISMATCH, DUPLICATE)"
+//	@Param			statement_id	query		int		false	"Only lines of this statement"
+//	@Param			page			query		int		false	"Page number"	default(1)
+//	@Param			page_size		query		int		false	"Page size"		default(20)
+//	@Success		200				{object}	dto.APIResponse{data=[]dto.StatementLineResponse}
+//	@Failure		400				{object}	dto.ErrorResponse
+//	@Failure		401				{object}	dto.ErrorResponse
+//	@Failure		403				{object}	dto.ErrorResponse
+//	@Failure		500				{object}	dto.ErrorResponse
+//	@Router			/admin/statements/exceptions [get]
+func (h *StatementHandler) ListStatementExceptions(c *gin.Context) {
+	filter := repositories.StatementLineFilter{
+		Provider: strings.TrimSpace(c.Query("provider")),
+		Status:   models.StatementLineStatus(strings.ToUpper(c.DefaultQuery("status", string(models.StatementLineStatusException)))),
+		Reason:   models.StatementExceptionReason(strings.ToUpper(c.Query("reason"))),
+	}
+	switch filter.Status {
+	case models.StatementLineStatusException, models.StatementLineStatusResolved:
+	default:
+		c.Error(apperrors.ErrValidation.Withf("invalid status")).SetMeta("statement.invalid_status")
+		return
+	}
+	switch filter.Reason {
+	case "", models.StatementExceptionNotFound, models.StatementExceptionAmountMismatch, models.StatementExceptionStatusMismatch, models.StatementExceptionDuplicate:
+	default:
+		c.Error(apperrors.ErrValidation.Withf("invalid reason")).SetMeta("statement.invalid_reason")
+		return
+	}
+	if value := c.Query("statement_id"); value != "" {
+		statementID, err := strconv.ParseUint(value, 10, 32)
+		if err != nil {
+			c.Error(apperrors.ErrValidation.Withf("invalid statement id: %w", err)).SetMeta("statement.invalid_id")
+			return
+		}
+		filter.StatementID = uint(statementID)
+	}
+
+	page, pageSize := parsePagination(c)
+	lines, err := h.statementUseCase.ListLines(c.Request.Context(), filter, page, pageSize)
+	if err != nil {
+		c.Error(err).SetMeta("statement.exceptions_retrieve_failed")
+		return
+	}
+
+	responses := make([]dto.StatementLineResponse, len(lines))
+	for i := range lines {
+		responses[i] = dto.ToStatementLineResponse(&lines[i])
+	}
+
+	c.JSON(http.StatusOK, dto.APIResponse{
+		Success: true,
+		Message: middleware.Translate(c, "statement.exceptions_retrieved"),
+		Data:    responses,
+	})
+}
+
+// ResolveStatementException godoc
+//
+//	@Summary		Resolve a statement exception
+//	@Description	Close a reviewed statement exception with a note explaining the outcome (admin and support)
+//	@Tags			admin
+//	@Accept			json
+//	@Produce		json
+//	@Security		BearerAuth
+//	@Param			id		path		int								true	"Statement line ID"
+//	@Param			request	body		dto.ResolveStatementLineRequest	true	"Resolution note"
+//	@Success		200		{object}	dto.APIResponse{data=dto.StatementLineResponse}
+//	@Failure		400		{object}	dto.ErrorResponse
+//	@Failure		401		{object}	dto.ErrorResponse
+//	@Failure		403		{object}	dto.ErrorResponse
+//	@Failure		404		{object}	dto.ErrorResponse
+//	@Failure		409		{object}	dto.ErrorResponse	"Line is not an open exception"
+//	@Failure		500		{object}	dto.ErrorResponse
+//	@Router			/admin/statements/lines/{id}/resolve [post]
+func (h *StatementHandler) ResolveStatementException(c *gin.Context) {
+	adminID, exists := middleware.GetUserID(c)
+	if !exists {
+		c.Error(apperrors.ErrUnauthenticated)
+		return
+	}
+
+	lineID, err := parseIDParam(c, "id")
+	if err != nil {
+		c.Error(err).SetMeta("statement.invalid_line_id")
+		return
+	}
+
+	var req dto.ResolveStatementLineRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.Error(apperrors.ErrValidation.Withf("invalid request data: %w", err)).SetMeta("request.invalid")
+		return
+	}
+
+	line, err := h.statementUseCase.ResolveException(c.Request.Context(), lineID, adminID, strings.TrimSpace(req.Note))
+	if err != nil {
+		c.Error(err).SetMeta("statement.exception_resolve_failed")
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.APIResponse{
+		Success: true,
+		Message: middleware.Translate(c, "statement.exception_resolved"),
+		Data:    dto.ToStatementLineResponse(line),
+	})
+}
@@ -0,0 +1,475 @@
+package handlers
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/limistah/wallet-service/internal/apperrors"
+	"github.com/limistah/wallet-service/internal/dto"
+	"github.com/limistah/wallet-service/internal/middleware"
+	"github.com/limistah/wallet-service/internal/models"
+	"github.com/limistah/wallet-service/internal/usecases"
+	"github.com/shopspring/decimal"
+)
+
+type SubscriptionHandler struct {
+	subscriptionUseCase usecases.SubscriptionUseCase
+}
+
+func NewSubscriptionHandler(subscriptionUseCase usecases.SubscriptionUseCase) *SubscriptionHandler {
+	return &SubscriptionHandler{
+		subscriptionUseCase: subscriptionUseCase,
+	}
+}
+
+// CreatePlan godoc
+//
+//	@Summary		Create a subscription plan
+//	@Description	Define a plan whose subscribers are charged the amount every interval, paid into the authenticated merchant's wallet in its currency
+//	@Tags			subscriptions
+//	@Accept			json
+//	@Produce		json
+//	@Security		BearerAuth
+//	@Param			request	body		dto.CreateSubscriptionPlanRequest	true	"Plan"
+//	@Success		201		{object}	dto.APIResponse{data=dto.SubscriptionPlanResponse}
+//	@Failure		400		{object}	dto.ErrorResponse
+//	@Failure		401		{object}	dto.ErrorResponse
+//	@Failure		403		{object}	dto.ErrorResponse
+//	@Failure		404		{object}	dto.ErrorResponse
+//	@Failure		500		{object}	dto.ErrorResponse
+//	@Router			/subscription-plans [post]
+func (h *SubscriptionHandler) CreatePlan(c *gin.Context) {
+	userID, exists := middleware.GetUserID(c)
+	if !exists {
+		c.Error(apperrors.ErrUnauthenticated)
+		return
+	}
+
+	if rejectSandbox(c) {
+		return
+	}
+
+	var req dto.CreateSubscriptionPlanRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.Error(apperrors.ErrValidation.Withf("invalid request data: %w", err)).SetMeta("request.invalid")
+		return
+	}
+
+	if req.Amount.LessThanOrEqual(decimal.Zero) {
+		c.Error(apperrors.ErrInvalidAmount)
+		return
+	}
+
+	plan, err := h.subscriptionUseCase.CreatePlan(c.Request.Context(), userID, req.Name, req.Description, req.Amount, models.BillingInterval(req.Interval))
+	if err != nil {
+		c.Error(err).SetMeta("subscription.plan_create_failed")
+		return
+	}
+
+	c.JSON(http.StatusCreated, dto.APIResponse{
+		Success: true,
+		Message: middleware.Translate(c, "subscription.plan_created"),
+		Data:    dto.ToSubscriptionPlanResponse(plan),
+	})
+}
+
+// ListPlans godoc
+//
+//	@Summary		List subscription plans
+//	@Description	List the plans that take new subscribers, newest first
+//	@Tags			subscriptions
+//	@Accept			json
+//	@Produce		json
+//	@Security		BearerAuth
+//	@Param			page		query		int	false	"Page number"	default(1)
+//	@Param			page_size	query		int	false	"Page size"		default(20)
+//	@Success		200			{object}	dto.APIResponse{data=[]dto.SubscriptionPlanResponse}
+//	@Failure		401			{object}	dto.ErrorResponse
+//	@Failure		500			{object}	dto.ErrorResponse
+//	@Router			/subscription-plans [get]
+func (h *SubscriptionHandler) ListPlans(c *gin.Context) {
+	page, pageSize := parsePagination(c)
+	plans, err := h.subscriptionUseCase.ListPlans(c.Request.Context(), page, pageSize)
+	h.respondPlans(c, plans, err)
+}
+
+// ListMerchantPlans godoc
+//
+//	@Summary		List my subscription plans
+//	@Description	List the plans of the authenticated merchant, archived ones included
+//	@Tags			subscriptions
+//	@Accept			json
+//	@Produce		json
+//	@Security		BearerAuth
+//	@Success		200	{object}	dto.APIResponse{data=[]dto.SubscriptionPlanResponse}
+//	@Failure		401	{object}	dto.ErrorResponse
+//	@Failure		500	{object}	dto.ErrorResponse
+//	@Router			/subscription-plans/mine [get]
+func (h *SubscriptionHandler) ListMerchantPlans(c *gin.Context) {
+	userID, exists := middleware.GetUserID(c)
+	if !exists {
+		c.Error(apperrors.ErrUnauthenticated)
+		return
+	}
+
+	plans, err := h.subscriptionUseCase.ListMerchantPlans(c.Request.Context(), userID)
+	h.respondPlans(c, plans, err)
+}
+
+// GetPlan godoc
+//
+//	@Summary		Get a subscription plan
+//	@Tags			subscriptions
+//	@Accept			json
+//	@Produce		json
+//	@Security		BearerAuth
+//	@Param			id	path		int	true	"Plan ID"
+//	@Success		200	{object}	dto.APIResponse{data=dto.SubscriptionPlanResponse}
+//	@Failure		400	{object}	dto.ErrorResponse
+//	@Failure		401	{object}	dto.ErrorResponse
+//	@Failure		404	{object}	dto.ErrorResponse
+//	@Failure		500	{object}	dto.ErrorResponse
+//	@Router			/subscription-plans/{id} [get]
+func (h *SubscriptionHandler) GetPlan(c *gin.Context) {
+	planID, err := parseIDParam(c, "id")
+	if err != nil {
+		c.Error(err).SetMeta("subscription.invalid_plan_id")
+		return
+	}
+
+	plan, err := h.subscriptionUseCase.GetPlan(c.Request.Context(), planID)
+	if err != nil {
+		c.Error(err).SetMeta("subscription.plan_retrieve_failed")
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.APIResponse{
+		Success: true,
+		Message: middleware.Translate(c, "subscription.plan_retrieved"),
+		Data:    dto.ToSubscriptionPlanResponse(plan),
+	})
+}
+
+// ArchivePlan godoc
+//
+//	@Summary		Archive a subscription plan
+//	@Description	Stop a plan of the authenticated merchant from taking new subscribers. Its subscriptions keep being billed until their subscribers cancel them
+//	@Tags			subscriptions
+//	@Accept			json
+//	@Produce		json
+//	@Security		BearerAuth
+//	@Param			id	path		int	true	"Plan ID"
+//	@Success		200	{object}	dto.APIResponse{data=dto.SubscriptionPlanResponse}
+//	@Failure		400	{object}	dto.ErrorResponse
+//	@Failure		401	{object}	dto.ErrorResponse
+//	@Failure		403	{object}	dto.ErrorResponse
+//	@Failure		404	{object}	dto.ErrorResponse
+//	@Failure		500	{object}	dto.ErrorResponse
+//	@Router			/subscription-plans/{id} [delete]
+func (h *SubscriptionHandler) ArchivePlan(c *gin.Context) {
+	userID, exists := middleware.GetUserID(c)
+	if !exists {
+		c.Error(apperrors.ErrUnauthenticated)
+		return
+	}
+
+	planID, err := parseIDParam(c, "id")
+	if err != nil {
+		c.Error(err).SetMeta("subscription.invalid_plan_id")
+		return
+	}
+
+	plan, err := h.subscriptionUseCase.ArchivePlan(c.Request.Context(), userID, planID)
+	if err != nil {
+		c.Error(err).SetMeta("subscription.plan_archive_failed")
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.APIResponse{
+		Success: true,
+		Message: middleware.Translate(c, "subscription.plan_archived"),
+		Data:    dto.ToSubscriptionPlanResponse(plan),
+	})
+}
+
+// ListPlanSubscriptions godoc
+//
+//	@Summary		List the subscriptions to a plan
+//	@Description	List the subscriptions to a plan of the authenticated merchant, newest first
+//	@Tags			subscriptions
+//	@Accept			json
+//	@Produce		json
+//	@Security		BearerAuth
+//	@Param			id			path		int	true	"Plan ID"
+//	@Param			page		query		int	false	"Page number"	default(1)
+//	@Param			page_size	query		int	false	"Page size"		default(20)
+//	@Success		200			{object}	dto.APIResponse{data=[]dto.SubscriptionResponse}
+//	@Failure		400			{object}	dto.ErrorResponse
+//	@Failure		401			{object}	dto.ErrorResponse
+//	@Failure		403			{object}	dto.ErrorResponse
+//	@Failure		404			{object}	dto.ErrorResponse
+//	@Failure		500			{object}	dto.ErrorResponse
+//	@Router			/subscription-plans/{id}/subscriptions [get]
+func (h *SubscriptionHandler) ListPlanSubscriptions(c *gin.Context) {
+	userID, exists := middleware.GetUserID(c)
+	if !exists {
+		c.Error(apperrors.ErrUnauthenticated)
+		return
+	}
+
+	planID, err := parseIDParam(c, "id")
+	if err != nil {
+		c.Error(err).SetMeta("subscription.invalid_plan_id")
+		return
+	}
+
+	page, pageSize := parsePagination(c)
+	subscriptions, err := h.subscriptionUseCase.ListPlanSubscriptions(c.Request.Context(), userID, planID, page, pageSize)
+	h.respondSubscriptions(c, subscriptions, err)
+}
+
+// Subscribe godoc
+//
+//	@Summary		Subscribe to a plan
+//	@Description	Charge the authenticated user's wallet for the first period of a plan and subscribe to it. Each following period is charged when it starts; a failed charge is retried and the subscription is suspended once the retries run out
+//	@Tags			subscriptions
+//	@Accept			json
+//	@Produce		json
+//	@Security		BearerAuth
+//	@Param			request	body		dto.SubscribeRequest	true	"Plan to subscribe to"
+//	@Success		201		{object}	dto.APIResponse{data=dto.SubscriptionResponse}
+//	@Failure		400		{object}	dto.ErrorResponse
+//	@Failure		401		{object}	dto.ErrorResponse
+//	@Failure		403		{object}	dto.ErrorResponse
+//	@Failure		404		{object}	dto.ErrorResponse
+//	@Failure		409		{object}	dto.ErrorResponse	"Already subscribed, archived plan or insufficient funds"
+//	@Failure		500		{object}	dto.ErrorResponse
+//	@Router			/subscriptions [post]
+func (h *SubscriptionHandler) Subscribe(c *gin.Context) {
+	userID, exists := middleware.GetUserID(c)
+	if !exists {
+		c.Error(apperrors.ErrUnauthenticated)
+		return
+	}
+
+	if rejectSandbox(c) {
+		return
+	}
+
+	var req dto.SubscribeRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.Error(apperrors.ErrValidation.Withf("invalid request data: %w", err)).SetMeta("request.invalid")
+		return
+	}
+
+	subscription, err := h.subscriptionUseCase.Subscribe(c.Request.Context(), userID, req.PlanID)
+	if err != nil {
+		c.Error(err).SetMeta("subscription.subscribe_failed")
+		return
+	}
+
+	c.JSON(http.StatusCreated, dto.APIResponse{
+		Success: true,
+		Message: middleware.Translate(c, "subscription.subscribed"),
+		Data:    dto.ToSubscriptionResponse(subscription),
+	})
+}
+
+// ListSubscriptions godoc
+//
+//	@Summary		List subscriptions
+//	@Description	List the subscriptions of the authenticated user, newest first
+//	@Tags			subscriptions
+//	@Accept			json
+//	@Produce		json
+//	@Security		BearerAuth
+//	@Success		200	{object}	dto.APIResponse{data=[]dto.SubscriptionResponse}
+//	@Failure		401	{object}	dto.ErrorResponse
+//	@Failure		500	{object}	dto.ErrorResponse
+//	@Router			/subscriptions [get]
+func (h *SubscriptionHandler) ListSubscriptions(c *gin.Context) {
+	userID, exists := middleware.GetUserID(c)
+	if !exists {
+		c.Error(apperrors.ErrUnauthenticated)
+		return
+	}
+
+	subscriptions, err := h.subscriptionUseCase.ListSubscriptions(c.Request.Context(), userID)
+	h.respondSubscriptions(c, subscriptions, err)
+}
+
+// GetSubscription godoc
+//
+//	@Summary		Get a subscription
+//	@Description	Get a subscription of which the authenticated user is the subscriber or the merchant. Other subscriptions are reported as not found
+//	@Tags			subscriptions
+//	@Accept			json
+//	@Produce		json
+//	@Security		BearerAuth
+//	@Param			id	path		int	true	"Subscription ID"
+//	@Success		200	{object}	dto.APIResponse{data=dto.SubscriptionResponse}
+//	@Failure		400	{object}	dto.ErrorResponse
+//	@Failure		401	{object}	dto.ErrorResponse
+//	@Failure		404	{object}	dto.ErrorResponse
+//	@Failure		500	{object}	dto.ErrorResponse
+//	@Router			/subscriptions/{id} [get]
+func (h *SubscriptionHandler) GetSubscription(c *gin.Context) {
+	h.respond(c, "subscription.retrieved", "subscription.retrieve_failed", h.subscriptionUseCase.GetSubscription)
+}
+
+// ListCharges godoc
+//
+//	@Summary		List the charges of a subscription
+//	@Description	List every attempt to charge a subscription, newest first, for its subscriber or merchant
+//	@Tags			subscriptions
+//	@Accept			json
+//	@Produce		json
+//	@Security		BearerAuth
+//	@Param			id			path		int	true	"Subscription ID"
+//	@Param			page		query		int	false	"Page number"	default(1)
+//	@Param			page_size	query		int	false	"Page size"		default(20)
+//	@Success		200			{object}	dto.APIResponse{data=[]dto.SubscriptionChargeResponse}
+//	@Failure		400			{object}	dto.ErrorResponse
+//	@Failure		401			{object}	dto.ErrorResponse
+//	@Failure		404			{object}	dto.ErrorResponse
+//	@Failure		500			{object}	dto.ErrorResponse
+//	@Router			/subscriptions/{id}/charges [get]
+func (h *SubscriptionHandler) ListCharges(c *gin.Context) {
+	userID, exists := middleware.GetUserID(c)
+	if !exists {
+		c.Error(apperrors.ErrUnauthenticated)
+		return
+	}
+
+	subscriptionID, err := parseIDParam(c, "id")
+	if err != nil {
+		c.Error(err).SetMeta("subscription.invalid_id")
+		return
+	}
+
+	page, pageSize := parsePagination(c)
+	charges, err := h.subscriptionUseCase.ListCharges(c.Request.Context(), userID, subscriptionID, page, pageSize)
+	if err != nil {
+		c.Error(err).SetMeta("subscription.charges_retrieve_failed")
+		return
+	}
+
+	responses := make([]dto.SubscriptionChargeResponse, len(charges))
+	for i := range charges {
+		responses[i] = dto.ToSubscriptionChargeResponse(&charges[i])
+	}
+
+	c.JSON(http.StatusOK, dto.APIResponse{
+		Success: true,
+		Message: middleware.Translate(c, "subscription.charges_retrieved"),
+		Data:    responses,
+	})
+}
+
+// CancelSubscription godoc
+//
+//	@Summary		Cancel a subscription
+//	@Description	Stop charging a subscription of the authenticated user. The period already paid is not refunded
+//	@Tags			subscriptions
+//	@Accept			json
+//	@Produce		json
+//	@Security		BearerAuth
+//	@Param			id	path		int	true	"Subscription ID"
+//	@Success		200	{object}	dto.APIResponse{data=dto.SubscriptionResponse}
+//	@Failure		400	{object}	dto.ErrorResponse
+//	@Failure		401	{object}	dto.ErrorResponse
+//	@Failure		404	{object}	dto.ErrorResponse
+//	@Failure		409	{object}	dto.ErrorResponse	"Subscription already cancelled"
+//	@Failure		500	{object}	dto.ErrorResponse
+//	@Router			/subscriptions/{id}/cancel [post]
+func (h *SubscriptionHandler) CancelSubscription(c *gin.Context) {
+	h.respond(c, "subscription.cancelled", "subscription.cancel_failed", h.subscriptionUseCase.CancelSubscription)
+}
+
+// ResumeSubscription godoc
+//
+//	@Summary		Resume a suspended subscription
+//	@Description	Charge a subscription suspended after failed payments for a new period starting now, and bill it again from there. It stays suspended when the charge fails
+//	@Tags			subscriptions
+//	@Accept			json
+//	@Produce		json
+//	@Security		BearerAuth
+//	@Param			id	path		int	true	"Subscription ID"
+//	@Success		200	{object}	dto.APIResponse{data=dto.SubscriptionResponse}
+//	@Failure		400	{object}	dto.ErrorResponse
+//	@Failure		401	{object}	dto.ErrorResponse
+//	@Failure		404	{object}	dto.ErrorResponse
+//	@Failure		409	{object}	dto.ErrorResponse	"Subscription not suspended or insufficient funds"
+//	@Failure		500	{object}	dto.ErrorResponse
+//	@Router			/subscriptions/{id}/resume [post]
+func (h *SubscriptionHandler) ResumeSubscription(c *gin.Context) {
+	if rejectSandbox(c) {
+		return
+	}
+	h.respond(c, "subscription.resumed", "subscription.resume_failed", h.subscriptionUseCase.ResumeSubscription)
+}
+
+// respond runs an action on a subscription of the authenticated user, reporting it with the message
+// keys success and failure
+func (h *SubscriptionHandler) respond(c *gin.Context, success, failure string, action func(ctx context.Context, userID, subscriptionID uint) (*models.Subscription, error)) {
+	userID, exists := middleware.GetUserID(c)
+	if !exists {
+		c.Error(apperrors.ErrUnauthenticated)
+		return
+	}
+
+	subscriptionID, err := parseIDParam(c, "id")
+	if err != nil {
+		c.Error(err).SetMeta("subscription.invalid_id")
+		return
+	}
+
+	subscription, err := action(c.Request.Context(), userID, subscriptionID)
+	if err != nil {
+		c.Error(err).SetMeta(failure)
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.APIResponse{
+		Success: true,
+		Message: middleware.Translate(c, success),
+		Data:    dto.ToSubscriptionResponse(subscription),
+	})
+}
+
+func (h *SubscriptionHandler) respondPlans(c *gin.Context, plans []models.SubscriptionPlan, err error) {
+	if err != nil {
+		c.Error(err).SetMeta("subscription.plans_retrieve_failed")
+		return
+	}
+
+	responses := make([]dto.SubscriptionPlanResponse, len(plans))
+	for i := range plans {
+		responses[i] = dto.ToSubscriptionPlanResponse(&plans[i])
+	}
+
+	c.JSON(http.StatusOK, dto.APIResponse{
+		Success: true,
+		Message: middleware.Translate(c, "subscription.plans_retrieved"),
+		Data:    responses,
+	})
+}
+
+func (h *SubscriptionHandler) respondSubscriptions(c *gin.Context, subscriptions []models.Subscription, err error) {
+	if err != nil {
+		c.Error(err).SetMeta("subscription.list_failed")
+		return
+	}
+
+	responses := make([]dto.SubscriptionResponse, len(subscriptions))
+	for i := range subscriptions {
+		responses[i] = dto.ToSubscriptionResponse(&subscriptions[i])
+	}
+
+	c.JSON(http.StatusOK, dto.APIResponse{
+		Success: true,
+		Message: middleware.Translate(c, "subscription.list_retrieved"),
+		Data:    responses,
+	})
+}
@@ -0,0 +1,238 @@
+package handlers
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/limistah/wallet-service/internal/apperrors"
+	"github.com/limistah/wallet-service/internal/dto"
+	"github.com/limistah/wallet-service/internal/middleware"
+	"github.com/limistah/wallet-service/internal/models"
+	"github.com/limistah/wallet-service/internal/usecases"
+)
+
+type SuspenseHandler struct {
+	suspenseUseCase usecases.SuspenseUseCase
+}
+
+func NewSuspenseHandler(suspenseUseCase usecases.SuspenseUseCase) *SuspenseHandler {
+	return &SuspenseHandler{
+		suspenseUseCase: suspenseUseCase,
+	}
+}
+
+// ListSuspenseItems godoc
+//
+//	@Summary		List suspense items
+//	@Description	List inbound credits parked in the suspense account because they matched no wallet, oldest first (admin and support)
+//	@Tags			admin
+//	@Accept			json
+//	@Produce		json
+//	@Security		BearerAuth
+//	@Param			status		query		string	false	"Item status (OPEN, INVESTIGATING, REALLOCATED, RETURNED); omit for every item"
+//	@Param			page		query		int		false	"Page number"	default(1)
+//	@Param			page_size	query		int		false	"Page size"		default(20)
+//	@Success		200			{object}	dto.APIResponse{data=[]dto.SuspenseItemResponse}
+//	@Failure		400			{object}	dto.ErrorResponse
+//	@Failure		401			{object}	dto.ErrorResponse
+//	@Failure		403			{object}	dto.ErrorResponse
+//	@Failure		500			{object}	dto.ErrorResponse
+//	@Router			/admin/suspense [get]
+func (h *SuspenseHandler) ListSuspenseItems(c *gin.Context) {
+	status := models.SuspenseItemStatus(strings.ToUpper(c.Query("status")))
+	switch status {
+	case "", models.SuspenseItemStatusOpen, models.SuspenseItemStatusInvestigating, models.SuspenseItemStatusReallocated, models.SuspenseItemStatusReturned:
+	default:
+		c.Error(apperrors.ErrValidation.Withf("invalid status")).SetMeta("suspense.invalid_status")
+		return
+	}
+
+	page, pageSize := parsePagination(c)
+	items, err := h.suspenseUseCase.ListItems(c.Request.Context(), status, page, pageSize)
+	if err != nil {
+		c.Error(err).SetMeta("suspense.items_retrieve_failed")
+		return
+	}
+
+	responses := make([]dto.SuspenseItemResponse, len(items))
+	for i := range items {
+		responses[i] = dto.ToSuspenseItemResponse(&items[i])
+	}
+
+	c.JSON(http.StatusOK, dto.APIResponse{
+		Success: true,
+		Message: middleware.Translate(c, "suspense.items_retrieved"),
+		Data:    responses,
+	})
+}
+
+// GetSuspenseItem godoc
+//
+//	@Summary		Get a suspense item
+//	@Description	Get an inbound credit parked in the suspense account with its investigation notes (admin and support)
+//	@Tags			admin
+//	@Accept			json
+//	@Produce		json
+//	@Security		BearerAuth
+//	@Param			id	path		int	true	"Suspense item ID"
+//	@Success		200	{object}	dto.APIResponse{data=dto.SuspenseItemResponse}
+//	@Failure		400	{object}	dto.ErrorResponse
+//	@Failure		401	{object}	dto.ErrorResponse
+//	@Failure		403	{object}	dto.ErrorResponse
+//	@Failure		404	{object}	dto.ErrorResponse
+//	@Failure		500	{object}	dto.ErrorResponse
+//	@Router			/admin/suspense/{id} [get]
+func (h *SuspenseHandler) GetSuspenseItem(c *gin.Context) {
+	itemID, err := parseIDParam(c, "id")
+	if err != nil {
+		c.Error(err).SetMeta("suspense.invalid_item_id")
+		return
+	}
+
+	item, err := h.suspenseUseCase.GetItem(c.Request.Context(), itemID)
+	if err != nil {
+		c.Error(err).SetMeta("suspense.item_retrieve_failed")
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.APIResponse{
+		Success: true,
+		Message: middleware.Translate(c, "suspense.item_retrieved"),
+		Data:    dto.ToSuspenseItemResponse(item),
+	})
+}
+
+// InvestigateSuspenseItem godoc
+//
+//	@Summary		Record a suspense investigation note
+//	@Description	Add a note to an unresolved suspense item and mark it as under investigation (admin and support)
+//	@Tags			admin
+//	@Accept			json
+//	@Produce		json
+//	@Security		BearerAuth
+//	@Param			id		path		int						true	"Suspense item ID"
+//	@Param			request	body		dto.SuspenseNoteRequest	true	"Investigation note"
+//	@Success		200		{object}	dto.APIResponse{data=dto.SuspenseItemResponse}
+//	@Failure		400		{object}	dto.ErrorResponse
+//	@Failure		401		{object}	dto.ErrorResponse
+//	@Failure		403		{object}	dto.ErrorResponse
+//	@Failure		404		{object}	dto.ErrorResponse
+//	@Failure		409		{object}	dto.ErrorResponse	"Item already resolved"
+//	@Failure		500		{object}	dto.ErrorResponse
+//	@Router			/admin/suspense/{id}/investigate [post]
+func (h *SuspenseHandler) InvestigateSuspenseItem(c *gin.Context) {
+	adminID, itemID, ok := h.actorAndItem(c)
+	if !ok {
+		return
+	}
+
+	var req dto.SuspenseNoteRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.Error(apperrors.ErrValidation.Withf("invalid request data: %w", err)).SetMeta("request.invalid")
+		return
+	}
+
+	item, err := h.suspenseUseCase.Investigate(c.Request.Context(), itemID, adminID, strings.TrimSpace(req.Note))
+	h.respond(c, item, err, "suspense.investigate_failed", "suspense.investigated")
+}
+
+// ReallocateSuspenseItem godoc
+//
+//	@Summary		Reallocate suspense funds
+//	@Description	Credit the funds of a suspense item to the wallet they belong to (admin only)
+//	@Tags			admin
+//	@Accept			json
+//	@Produce		json
+//	@Security		BearerAuth
+//	@Param			id		path		int									true	"Suspense item ID"
+//	@Param			request	body		dto.ReallocateSuspenseItemRequest	true	"Destination wallet"
+//	@Success		200		{object}	dto.APIResponse{data=dto.SuspenseItemResponse}
+//	@Failure		400		{object}	dto.ErrorResponse
+//	@Failure		401		{object}	dto.ErrorResponse
+//	@Failure		403		{object}	dto.ErrorResponse
+//	@Failure		404		{object}	dto.ErrorResponse
+//	@Failure		409		{object}	dto.ErrorResponse	"Item already resolved"
+//	@Failure		500		{object}	dto.ErrorResponse
+//	@Router			/admin/suspense/{id}/reallocate [post]
+func (h *SuspenseHandler) ReallocateSuspenseItem(c *gin.Context) {
+	adminID, itemID, ok := h.actorAndItem(c)
+	if !ok {
+		return
+	}
+
+	var req dto.ReallocateSuspenseItemRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.Error(apperrors.ErrValidation.Withf("invalid request data: %w", err)).SetMeta("request.invalid")
+		return
+	}
+
+	item, err := h.suspenseUseCase.Reallocate(c.Request.Context(), itemID, req.WalletID, adminID, strings.TrimSpace(req.Note))
+	h.respond(c, item, err, "suspense.reallocate_failed", "suspense.reallocated")
+}
+
+// ReturnSuspenseItem godoc
+//
+//	@Summary		Return suspense funds
+//	@Description	Record that the funds of a suspense item were refunded to the payer and move them back to cash (admin only)
+//	@Tags			admin
+//	@Accept			json
+//	@Produce		json
+//	@Security		BearerAuth
+//	@Param			id		path		int						true	"Suspense item ID"
+//	@Param			request	body		dto.SuspenseNoteRequest	false	"Return note"
+//	@Success		200		{object}	dto.APIResponse{data=dto.SuspenseItemResponse}
+//	@Failure		400		{object}	dto.ErrorResponse
+//	@Failure		401		{object}	dto.ErrorResponse
+//	@Failure		403		{object}	dto.ErrorResponse
+//	@Failure		404		{object}	dto.ErrorResponse
+//	@Failure		409		{object}	dto.ErrorResponse	"Item already resolved"
+//	@Failure		500		{object}	dto.ErrorResponse
+//	@Router			/admin/suspense/{id}/return [post]
+func (h *SuspenseHandler) ReturnSuspenseItem(c *gin.Context) {
+	adminID, itemID, ok := h.actorAndItem(c)
+	if !ok {
+		return
+	}
+
+	var req dto.SuspenseNoteRequest
+	if err := c.ShouldBindJSON(&req); err != nil && !errors.Is(err, io.EOF) {
+		c.Error(apperrors.ErrValidation.Withf("invalid request data: %w", err)).SetMeta("request.invalid")
+		return
+	}
+
+	item, err := h.suspenseUseCase.Return(c.Request.Context(), itemID, adminID, strings.TrimSpace(req.Note))
+	h.respond(c, item, err, "suspense.return_failed", "suspense.returned")
+}
+
+// actorAndItem reads the signed in admin and the suspense item ID, writing the error response when
+// either is missing
+func (h *SuspenseHandler) actorAndItem(c *gin.Context) (uint, uint, bool) {
+	adminID, exists := middleware.GetUserID(c)
+	if !exists {
+		c.Error(apperrors.ErrUnauthenticated)
+		return 0, 0, false
+	}
+
+	itemID, err := parseIDParam(c, "id")
+	if err != nil {
+		c.Error(err).SetMeta("suspense.invalid_item_id")
+		return 0, 0, false
+	}
+	return adminID, itemID, true
+}
+
+func (h *SuspenseHandler) respond(c *gin.Context, item *models.SuspenseItem, err error, failureKey, successKey string) {
+	if err != nil {
+		c.Error(err).SetMeta(failureKey)
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.APIResponse{
+		Success: true,
+		Message: middleware.Translate(c, successKey),
+		Data:    dto.ToSuspenseItemResponse(item),
+	})
+}
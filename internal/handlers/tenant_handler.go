@@ -0,0 +1,89 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/limistah/wallet-service/internal/apperrors"
+	"github.com/limistah/wallet-service/internal/dto"
+	"github.com/limistah/wallet-service/internal/middleware"
+	"github.com/limistah/wallet-service/internal/usecases"
+)
+
+type TenantHandler struct {
+	tenantUseCase usecases.TenantUseCase
+}
+
+func NewTenantHandler(tenantUseCase usecases.TenantUseCase) *TenantHandler {
+	return &TenantHandler{
+		tenantUseCase: tenantUseCase,
+	}
+}
+
+// CreateTenant godoc
+//
+//	@Summary		Create a tenant
+//	@Description	Add a business to the deployment with system accounts of its own (admins of the default tenant only). Its clients send the slug in the X-Tenant-ID header to register and log in; their tokens then only reach the tenant's users and wallets
+//	@Tags			admin
+//	@Accept			json
+//	@Produce		json
+//	@Security		BearerAuth
+//	@Param			request	body		dto.CreateTenantRequest	true	"Tenant"
+//	@Success		201		{object}	dto.APIResponse{data=dto.TenantResponse}
+//	@Failure		400		{object}	dto.ErrorResponse
+//	@Failure		401		{object}	dto.ErrorResponse
+//	@Failure		403		{object}	dto.ErrorResponse
+//	@Failure		409		{object}	dto.ErrorResponse	"The slug is taken"
+//	@Failure		500		{object}	dto.ErrorResponse
+//	@Router			/admin/tenants [post]
+func (h *TenantHandler) CreateTenant(c *gin.Context) {
+	var req dto.CreateTenantRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.Error(apperrors.ErrValidation.Withf("invalid request data: %w", err)).SetMeta("request.invalid")
+		return
+	}
+
+	tenant, err := h.tenantUseCase.CreateTenant(c.Request.Context(), req.Name, req.Slug)
+	if err != nil {
+		c.Error(err).SetMeta("tenant.create_failed")
+		return
+	}
+
+	c.JSON(http.StatusCreated, dto.APIResponse{
+		Success: true,
+		Message: middleware.Translate(c, "tenant.created"),
+		Data:    dto.ToTenantResponse(tenant),
+	})
+}
+
+// ListTenants godoc
+//
+//	@Summary		List tenants
+//	@Description	List the businesses sharing the deployment (admins of the default tenant only)
+//	@Tags			admin
+//	@Accept			json
+//	@Produce		json
+//	@Security		BearerAuth
+//	@Success		200	{object}	dto.APIResponse{data=[]dto.TenantResponse}
+//	@Failure		401	{object}	dto.ErrorResponse
+//	@Failure		403	{object}	dto.ErrorResponse
+//	@Failure		500	{object}	dto.ErrorResponse
+//	@Router			/admin/tenants [get]
+func (h *TenantHandler) ListTenants(c *gin.Context) {
+	tenants, err := h.tenantUseCase.ListTenants(c.Request.Context())
+	if err != nil {
+		c.Error(err).SetMeta("tenant.list_failed")
+		return
+	}
+
+	responses := make([]dto.TenantResponse, len(tenants))
+	for i, tenant := range tenants {
+		responses[i] = dto.ToTenantResponse(&tenant)
+	}
+
+	c.JSON(http.StatusOK, dto.APIResponse{
+		Success: true,
+		Message: middleware.Translate(c, "tenant.list_retrieved"),
+		Data:    responses,
+	})
+}
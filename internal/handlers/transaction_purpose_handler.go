@@ -0,0 +1,184 @@
+package handlers
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/limistah/wallet-service/internal/apperrors"
+	"github.com/limistah/wallet-service/internal/dto"
+	"github.com/limistah/wallet-service/internal/middleware"
+	"github.com/limistah/wallet-service/internal/models"
+	"github.com/limistah/wallet-service/internal/usecases"
+)
+
+type TransactionPurposeHandler struct {
+	purposeUseCase usecases.TransactionPurposeUseCase
+}
+
+func NewTransactionPurposeHandler(purposeUseCase usecases.TransactionPurposeUseCase) *TransactionPurposeHandler {
+	return &TransactionPurposeHandler{
+		purposeUseCase: purposeUseCase,
+	}
+}
+
+// ListTransactionPurposes godoc
+//
+//	@Summary		List transaction purposes
+//	@Description	List the purposes transactions can be recorded with and the hooks that tune their fees, limits and notifications (admin and support)
+//	@Tags			admin
+//	@Produce		json
+//	@Security		BearerAuth
+//	@Success		200	{object}	dto.APIResponse{data=[]dto.TransactionPurposeResponse}
+//	@Failure		401	{object}	dto.ErrorResponse
+//	@Failure		403	{object}	dto.ErrorResponse
+//	@Failure		500	{object}	dto.ErrorResponse
+//	@Router			/admin/transaction-purposes [get]
+func (h *TransactionPurposeHandler) ListTransactionPurposes(c *gin.Context) {
+	definitions, err := h.purposeUseCase.ListPurposes(c.Request.Context())
+	if err != nil {
+		c.Error(err).SetMeta("transaction_purpose.list_failed")
+		return
+	}
+
+	responses := make([]dto.TransactionPurposeResponse, len(definitions))
+	for i := range definitions {
+		responses[i] = dto.ToTransactionPurposeResponse(&definitions[i])
+	}
+
+	c.JSON(http.StatusOK, dto.APIResponse{
+		Success: true,
+		Message: middleware.Translate(c, "transaction_purpose.listed"),
+		Data:    responses,
+	})
+}
+
+// GetTransactionPurpose godoc
+//
+//	@Summary		Get a transaction purpose
+//	@Description	Get a transaction purpose with its hooks (admin and support)
+//	@Tags			admin
+//	@Produce		json
+//	@Security		BearerAuth
+//	@Param			name	path		string	true	"Purpose name"
+//	@Success		200		{object}	dto.APIResponse{data=dto.TransactionPurposeResponse}
+//	@Failure		401		{object}	dto.ErrorResponse
+//	@Failure		403		{object}	dto.ErrorResponse
+//	@Failure		404		{object}	dto.ErrorResponse
+//	@Failure		500		{object}	dto.ErrorResponse
+//	@Router			/admin/transaction-purposes/{name} [get]
+func (h *TransactionPurposeHandler) GetTransactionPurpose(c *gin.Context) {
+	definition, err := h.purposeUseCase.GetPurpose(c.Request.Context(), purposeParam(c))
+	h.respond(c, http.StatusOK, definition, err, "transaction_purpose.retrieve_failed", "transaction_purpose.retrieved")
+}
+
+// CreateTransactionPurpose godoc
+//
+//	@Summary		Create a transaction purpose
+//	@Description	Add a purpose transactions can be recorded with, such as a new product, with the hooks that tune its fees, limits and notifications (admin only)
+//	@Tags			admin
+//	@Accept			json
+//	@Produce		json
+//	@Security		BearerAuth
+//	@Param			request	body		dto.CreateTransactionPurposeRequest	true	"Purpose"
+//	@Success		201		{object}	dto.APIResponse{data=dto.TransactionPurposeResponse}
+//	@Failure		400		{object}	dto.ErrorResponse	"Invalid name or notification template"
+//	@Failure		401		{object}	dto.ErrorResponse
+//	@Failure		403		{object}	dto.ErrorResponse
+//	@Failure		409		{object}	dto.ErrorResponse	"Purpose already exists"
+//	@Failure		500		{object}	dto.ErrorResponse
+//	@Router			/admin/transaction-purposes [post]
+func (h *TransactionPurposeHandler) CreateTransactionPurpose(c *gin.Context) {
+	var req dto.CreateTransactionPurposeRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.Error(apperrors.ErrValidation.Withf("invalid request data: %w", err)).SetMeta("request.invalid")
+		return
+	}
+
+	input := purposeInput(req.TransactionPurposeRequest)
+	input.Name = models.TransactionPurpose(req.Name)
+	definition, err := h.purposeUseCase.CreatePurpose(c.Request.Context(), input)
+	h.respond(c, http.StatusCreated, definition, err, "transaction_purpose.create_failed", "transaction_purpose.created")
+}
+
+// UpdateTransactionPurpose godoc
+//
+//	@Summary		Update a transaction purpose
+//	@Description	Replace the description and hooks of a transaction purpose; the name stays (admin only)
+//	@Tags			admin
+//	@Accept			json
+//	@Produce		json
+//	@Security		BearerAuth
+//	@Param			name	path		string							true	"Purpose name"
+//	@Param			request	body		dto.TransactionPurposeRequest	true	"Purpose"
+//	@Success		200		{object}	dto.APIResponse{data=dto.TransactionPurposeResponse}
+//	@Failure		400		{object}	dto.ErrorResponse	"Invalid notification template"
+//	@Failure		401		{object}	dto.ErrorResponse
+//	@Failure		403		{object}	dto.ErrorResponse
+//	@Failure		404		{object}	dto.ErrorResponse
+//	@Failure		500		{object}	dto.ErrorResponse
+//	@Router			/admin/transaction-purposes/{name} [put]
+func (h *TransactionPurposeHandler) UpdateTransactionPurpose(c *gin.Context) {
+	var req dto.TransactionPurposeRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.Error(apperrors.ErrValidation.Withf("invalid request data: %w", err)).SetMeta("request.invalid")
+		return
+	}
+
+	definition, err := h.purposeUseCase.UpdatePurpose(c.Request.Context(), purposeParam(c), purposeInput(req))
+	h.respond(c, http.StatusOK, definition, err, "transaction_purpose.update_failed", "transaction_purpose.updated")
+}
+
+// DeleteTransactionPurpose godoc
+//
+//	@Summary		Delete a transaction purpose
+//	@Description	Remove a purpose no transaction was recorded with; the purposes the service posts cannot be removed (admin only)
+//	@Tags			admin
+//	@Produce		json
+//	@Security		BearerAuth
+//	@Param			name	path		string	true	"Purpose name"
+//	@Success		200		{object}	dto.APIResponse
+//	@Failure		401		{object}	dto.ErrorResponse
+//	@Failure		403		{object}	dto.ErrorResponse
+//	@Failure		404		{object}	dto.ErrorResponse
+//	@Failure		409		{object}	dto.ErrorResponse	"Purpose in use"
+//	@Failure		500		{object}	dto.ErrorResponse
+//	@Router			/admin/transaction-purposes/{name} [delete]
+func (h *TransactionPurposeHandler) DeleteTransactionPurpose(c *gin.Context) {
+	if err := h.purposeUseCase.DeletePurpose(c.Request.Context(), purposeParam(c)); err != nil {
+		c.Error(err).SetMeta("transaction_purpose.delete_failed")
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.APIResponse{
+		Success: true,
+		Message: middleware.Translate(c, "transaction_purpose.deleted"),
+	})
+}
+
+// purposeParam reads the purpose name of the path, which is matched in upper case
+func purposeParam(c *gin.Context) models.TransactionPurpose {
+	return models.TransactionPurpose(strings.ToUpper(c.Param("name")))
+}
+
+func purposeInput(req dto.TransactionPurposeRequest) usecases.TransactionPurposeInput {
+	return usecases.TransactionPurposeInput{
+		Description:          req.Description,
+		ChargesFees:          req.ChargesFees,
+		CountsTowardLimits:   req.CountsTowardLimits,
+		NotificationTemplate: req.NotificationTemplate,
+	}
+}
+
+func (h *TransactionPurposeHandler) respond(c *gin.Context, status int, definition *models.TransactionPurposeDefinition, err error, failureKey, successKey string) {
+	if err != nil {
+		c.Error(err).SetMeta(failureKey)
+		return
+	}
+
+	c.JSON(status, dto.APIResponse{
+		Success: true,
+		Message: middleware.Translate(c, successKey),
+		Data:    dto.ToTransactionPurposeResponse(definition),
+	})
+}
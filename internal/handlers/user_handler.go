@@ -0,0 +1,296 @@
+package handlers
+
+import (
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/limistah/wallet-service/internal/apperrors"
+	"github.com/limistah/wallet-service/internal/dto"
+	"github.com/limistah/wallet-service/internal/middleware"
+	"github.com/limistah/wallet-service/internal/usecases"
+)
+
+type UserHandler struct {
+	userUseCase usecases.UserUseCase
+}
+
+func NewUserHandler(userUseCase usecases.UserUseCase) *UserHandler {
+	return &UserHandler{
+		userUseCase: userUseCase,
+	}
+}
+
+// GetProfile godoc
+//
+//	@Summary		Get profile
+//	@Description	Get the profile of the authenticated user with their phone number, date of birth, address and avatar link
+//	@Tags			users
+//	@Produce		json
+//	@Security		BearerAuth
+//	@Success		200	{object}	dto.APIResponse{data=dto.UserResponse}
+//	@Failure		401	{object}	dto.ErrorResponse
+//	@Failure		404	{object}	dto.ErrorResponse
+//	@Failure		500	{object}	dto.ErrorResponse
+//	@Router			/users/me [get]
+func (h *UserHandler) GetProfile(c *gin.Context) {
+	userID, ok := h.userID(c)
+	if !ok {
+		return
+	}
+
+	user, err := h.userUseCase.GetUserByID(c.Request.Context(), userID)
+	if err != nil {
+		c.Error(apperrors.ErrUserNotFound).SetMeta("user.profile_retrieve_failed")
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.APIResponse{
+		Success: true,
+		Message: middleware.Translate(c, "user.profile_retrieved"),
+		Data:    dto.ToUserResponse(user),
+	})
+}
+
+// UpdateProfile godoc
+//
+//	@Summary		Update profile
+//	@Description	Change the name, phone number, date of birth, address or display currency of the authenticated user; fields left out are not changed. A changed phone number is stored unverified until confirmed through /users/me/phone, and an address replaces the previous one as a whole
+//	@Tags			users
+//	@Accept			json
+//	@Produce		json
+//	@Security		BearerAuth
+//	@Param			request	body		dto.UpdateProfileRequest	true	"Profile fields to change"
+//	@Success		200		{object}	dto.APIResponse{data=dto.UserResponse}
+//	@Failure		400		{object}	dto.ErrorResponse
+//	@Failure		401		{object}	dto.ErrorResponse
+//	@Failure		404		{object}	dto.ErrorResponse
+//	@Failure		500		{object}	dto.ErrorResponse
+//	@Router			/users/me [patch]
+func (h *UserHandler) UpdateProfile(c *gin.Context) {
+	userID, ok := h.userID(c)
+	if !ok {
+		return
+	}
+
+	var req dto.UpdateProfileRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.Error(apperrors.ErrValidation.Withf("invalid request data: %w", err)).SetMeta("request.invalid")
+		return
+	}
+
+	update := usecases.ProfileUpdate{
+		Name:            req.Name,
+		PhoneNumber:     req.PhoneNumber,
+		DisplayCurrency: req.DisplayCurrency,
+	}
+	if req.DateOfBirth != nil {
+		dateOfBirth, err := time.Parse(time.DateOnly, *req.DateOfBirth)
+		if err != nil {
+			c.Error(apperrors.ErrValidation.Withf("date_of_birth must be a YYYY-MM-DD date")).SetMeta("request.invalid")
+			return
+		}
+		update.DateOfBirth = &dateOfBirth
+	}
+	if req.Address != nil {
+		update.Address = &usecases.ProfileAddress{
+			Line1:      req.Address.Line1,
+			Line2:      req.Address.Line2,
+			City:       req.Address.City,
+			State:      req.Address.State,
+			PostalCode: req.Address.PostalCode,
+			Country:    req.Address.Country,
+		}
+	}
+
+	user, err := h.userUseCase.UpdateProfile(c.Request.Context(), userID, update)
+	if err != nil {
+		c.Error(err).SetMeta("user.profile_update_failed")
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.APIResponse{
+		Success: true,
+		Message: middleware.Translate(c, "user.profile_updated"),
+		Data:    dto.ToUserResponse(user),
+	})
+}
+
+// SetHandle godoc
+//
+//	@Summary		Set handle
+//	@Description	Choose the unique handle, such as @alice, other users pay the authenticated user by instead of a wallet ID; payments by handle land in the default wallet. Handles are 3 to 30 letters, digits and underscores starting with a letter, stored in lower case; an empty handle removes it
+//	@Tags			users
+//	@Accept			json
+//	@Produce		json
+//	@Security		BearerAuth
+//	@Param			request	body		dto.SetHandleRequest	true	"Handle"
+//	@Success		200		{object}	dto.APIResponse{data=dto.UserResponse}
+//	@Failure		400		{object}	dto.ErrorResponse
+//	@Failure		401		{object}	dto.ErrorResponse
+//	@Failure		404		{object}	dto.ErrorResponse
+//	@Failure		409		{object}	dto.ErrorResponse	"Handle taken by another user (code HANDLE_TAKEN)"
+//	@Failure		500		{object}	dto.ErrorResponse
+//	@Router			/users/me/handle [put]
+func (h *UserHandler) SetHandle(c *gin.Context) {
+	userID, ok := h.userID(c)
+	if !ok {
+		return
+	}
+
+	var req dto.SetHandleRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.Error(apperrors.ErrValidation.Withf("invalid request data: %w", err)).SetMeta("request.invalid")
+		return
+	}
+
+	user, err := h.userUseCase.SetHandle(c.Request.Context(), userID, req.Handle)
+	if err != nil {
+		c.Error(err).SetMeta("handle.update_failed")
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.APIResponse{
+		Success: true,
+		Message: middleware.Translate(c, "handle.updated"),
+		Data:    dto.ToUserResponse(user),
+	})
+}
+
+// DeleteAccount godoc
+//
+//	@Summary		Delete account
+//	@Description	Ask for the account of the authenticated user to be erased, confirmed with their password. Every session is signed out, logins are refused and the wallet is suspended right away; the name, email and other personal data are anonymized after a grace period, while wallets and transactions are kept for regulatory retention. The wallet balance has to be withdrawn first
+//	@Tags			users
+//	@Accept			json
+//	@Produce		json
+//	@Security		BearerAuth
+//	@Param			request	body		dto.DeleteAccountRequest	true	"Password confirming the deletion"
+//	@Success		202		{object}	dto.APIResponse
+//	@Failure		400		{object}	dto.ErrorResponse	"Wrong password (code INCORRECT_PASSWORD)"
+//	@Failure		401		{object}	dto.ErrorResponse
+//	@Failure		403		{object}	dto.ErrorResponse	"Impersonation tokens cannot delete accounts"
+//	@Failure		409		{object}	dto.ErrorResponse	"The wallet still holds money (code ACCOUNT_HAS_BALANCE)"
+//	@Failure		500		{object}	dto.ErrorResponse
+//	@Router			/users/me [delete]
+func (h *UserHandler) DeleteAccount(c *gin.Context) {
+	userID, ok := h.userID(c)
+	if !ok {
+		return
+	}
+	if _, impersonating := middleware.GetImpersonator(c); impersonating {
+		err := apperrors.ErrImpersonationForbidden.Withf("impersonation tokens cannot delete accounts")
+		c.Error(err).SetMeta("user.deletion_failed")
+		return
+	}
+
+	var req dto.DeleteAccountRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.Error(apperrors.ErrValidation.Withf("invalid request data: %w", err)).SetMeta("request.invalid")
+		return
+	}
+
+	if _, err := h.userUseCase.RequestDeletion(c.Request.Context(), userID, req.Password); err != nil {
+		c.Error(err).SetMeta("user.deletion_failed")
+		return
+	}
+
+	c.JSON(http.StatusAccepted, dto.APIResponse{
+		Success: true,
+		Message: middleware.Translate(c, "user.deletion_requested"),
+	})
+}
+
+// UploadAvatar godoc
+//
+//	@Summary		Upload avatar
+//	@Description	Set the profile picture of the authenticated user from a PNG, JPEG, GIF or WebP image, replacing the previous one
+//	@Tags			users
+//	@Accept			multipart/form-data
+//	@Produce		json
+//	@Security		BearerAuth
+//	@Param			avatar	formData	file	true	"Profile picture"
+//	@Success		200		{object}	dto.APIResponse{data=dto.UserResponse}
+//	@Failure		400		{object}	dto.ErrorResponse	"Missing image or not an image (code INVALID_AVATAR)"
+//	@Failure		401		{object}	dto.ErrorResponse
+//	@Failure		413		{object}	dto.ErrorResponse
+//	@Failure		500		{object}	dto.ErrorResponse
+//	@Failure		503		{object}	dto.ErrorResponse	"Avatar storage is not configured"
+//	@Router			/users/me/avatar [put]
+func (h *UserHandler) UploadAvatar(c *gin.Context) {
+	userID, ok := h.userID(c)
+	if !ok {
+		return
+	}
+
+	fileHeader, err := c.FormFile("avatar")
+	if err != nil {
+		// A form cut off by the body limit is reported as too large rather than as a missing file
+		if apperrors.HTTPStatus(err) != http.StatusRequestEntityTooLarge {
+			err = apperrors.ErrValidation.Withf("avatar image is required: %w", err)
+		}
+		c.Error(err).SetMeta("user.avatar_required")
+		return
+	}
+	file, err := fileHeader.Open()
+	if err != nil {
+		c.Error(apperrors.ErrInvalidAvatar).SetMeta("user.avatar_upload_failed")
+		return
+	}
+	defer file.Close()
+	content, err := io.ReadAll(file)
+	if err != nil {
+		c.Error(apperrors.ErrInvalidAvatar).SetMeta("user.avatar_upload_failed")
+		return
+	}
+
+	user, err := h.userUseCase.SetAvatar(c.Request.Context(), userID, content)
+	if err != nil {
+		c.Error(err).SetMeta("user.avatar_upload_failed")
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.APIResponse{
+		Success: true,
+		Message: middleware.Translate(c, "user.avatar_uploaded"),
+		Data:    dto.ToUserResponse(user),
+	})
+}
+
+// GetAvatar godoc
+//
+//	@Summary		Get avatar
+//	@Description	Download the profile picture of the authenticated user
+//	@Tags			users
+//	@Produce		image/png,image/jpeg,image/gif,image/webp
+//	@Security		BearerAuth
+//	@Success		200	{file}		file
+//	@Failure		401	{object}	dto.ErrorResponse
+//	@Failure		404	{object}	dto.ErrorResponse	"No avatar uploaded (code AVATAR_NOT_FOUND)"
+//	@Failure		500	{object}	dto.ErrorResponse
+//	@Router			/users/me/avatar [get]
+func (h *UserHandler) GetAvatar(c *gin.Context) {
+	userID, ok := h.userID(c)
+	if !ok {
+		return
+	}
+
+	content, contentType, err := h.userUseCase.GetAvatar(c.Request.Context(), userID)
+	if err != nil {
+		c.Error(err).SetMeta("user.avatar_retrieve_failed")
+		return
+	}
+
+	// The URL changes with every upload, so a cached copy is never stale
+	c.Header("Cache-Control", "private, max-age=86400")
+	c.Data(http.StatusOK, contentType, content)
+}
+
+func (h *UserHandler) userID(c *gin.Context) (uint, bool) {
+	userID, exists := middleware.GetUserID(c)
+	if !exists {
+		c.Error(apperrors.ErrUnauthenticated)
+	}
+	return userID, exists
+}
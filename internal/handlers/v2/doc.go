@@ -0,0 +1,24 @@
+// Package v2 serves the /api/v2 endpoints. Its Swagger document is generated separately from v1:
+//
+//	swag init -g doc.go -d internal/handlers/v2,internal/dto/v2,internal/dto --instanceName v2 -o docs/v2
+package v2
+
+// @title Wallet Service API
+// @version 2.0
+// @description Version 2 of the wallet service API. Endpoints not listed here are only available in v1
+// @termsOfService http://swagger.io/terms/
+
+// @contact.name API Support
+// @contact.url http://www.swagger.io/support
+// @contact.email support@swagger.io
+
+// @license.name Apache 2.0
+// @license.url http://www.apache.org/licenses/LICENSE-2.0.html
+
+// @host localhost:8080
+// @BasePath /api/v2
+
+// @securityDefinitions.apikey BearerAuth
+// @in header
+// @name Authorization
+// @description Type "Bearer" followed by a space and JWT token.
@@ -0,0 +1,106 @@
+package v2
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/limistah/wallet-service/internal/apperrors"
+	"github.com/limistah/wallet-service/internal/dto"
+	dtov2 "github.com/limistah/wallet-service/internal/dto/v2"
+	"github.com/limistah/wallet-service/internal/middleware"
+	"github.com/limistah/wallet-service/internal/models"
+	"github.com/limistah/wallet-service/internal/usecases"
+)
+
+type WalletHandler struct {
+	walletUseCase usecases.WalletUseCase
+}
+
+func NewWalletHandler(walletUseCase usecases.WalletUseCase) *WalletHandler {
+	return &WalletHandler{
+		walletUseCase: walletUseCase,
+	}
+}
+
+// getAuthenticatedUserWallet gets the wallet for the authenticated user
+func (h *WalletHandler) getAuthenticatedUserWallet(c *gin.Context) (*models.Wallet, error) {
+	userID, exists := middleware.GetUserID(c)
+	if !exists {
+		return nil, apperrors.ErrUnauthenticated
+	}
+
+	if middleware.IsSandbox(c) {
+		return h.walletUseCase.GetSandboxWallet(c.Request.Context(), userID)
+	}
+	return h.walletUseCase.GetWalletByUserID(c.Request.Context(), userID)
+}
+
+// GetWallet godoc
+//
+//	@Summary		Get wallet by authenticated user
+//	@Description	Retrieve wallet information for the authenticated user; the balance is returned with its currency, and display_balance is an indicative conversion to their display currency when they chose one
+//	@Tags			wallets
+//	@Accept			json
+//	@Produce		json
+//	@Security		BearerAuth
+//	@Param			If-None-Match	header	string	false	"ETag of a previous response"
+//	@Success		200	{object}	dto.APIResponse{data=dtov2.WalletResponse}
+//	@Success		304
+//	@Failure		401	{object}	dto.ErrorResponse
+//	@Failure		404	{object}	dto.ErrorResponse
+//	@Failure		500	{object}	dto.ErrorResponse
+//	@Router			/wallets/me [get]
+func (h *WalletHandler) GetWallet(c *gin.Context) {
+	wallet, err := h.getAuthenticatedUserWallet(c)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+	display := h.walletUseCase.DisplayConversion(c.Request.Context(), wallet.UserID, wallet.Currency)
+	if middleware.NotModified(c, middleware.VariantETag(wallet.ETag(), display.String())) {
+		return
+	}
+
+	response := dtov2.ToWalletResponse(wallet)
+	response.DisplayBalance = dtov2.ToDisplayMoney(display, wallet.Balance)
+	c.JSON(http.StatusOK, dto.APIResponse{
+		Success: true,
+		Message: middleware.Translate(c, "wallet.retrieved"),
+		Data:    response,
+	})
+}
+
+// GetWalletBalance godoc
+//
+//	@Summary		Get wallet balance
+//	@Description	Retrieve current balance of the authenticated user's wallet; display_balance is an indicative conversion to their display currency, when they chose one
+//	@Tags			wallets
+//	@Accept			json
+//	@Produce		json
+//	@Security		BearerAuth
+//	@Param			If-None-Match	header	string	false	"ETag of a previous response"
+//	@Success		200	{object}	dto.APIResponse{data=dtov2.BalanceResponse}
+//	@Success		304
+//	@Failure		401	{object}	dto.ErrorResponse
+//	@Failure		404	{object}	dto.ErrorResponse
+//	@Failure		500	{object}	dto.ErrorResponse
+//	@Router			/wallets/me/balance [get]
+func (h *WalletHandler) GetWalletBalance(c *gin.Context) {
+	wallet, err := h.getAuthenticatedUserWallet(c)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+	display := h.walletUseCase.DisplayConversion(c.Request.Context(), wallet.UserID, wallet.Currency)
+	if middleware.NotModified(c, middleware.VariantETag(wallet.ETag(), display.String())) {
+		return
+	}
+
+	response := dtov2.ToBalanceResponse(wallet)
+	response.DisplayBalance = dtov2.ToDisplayMoney(display, wallet.Balance)
+	c.JSON(http.StatusOK, dto.APIResponse{
+		Success: true,
+		Message: middleware.Translate(c, "wallet.balance_retrieved"),
+		Data:    response,
+	})
+}
@@ -0,0 +1,133 @@
+package v2
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/limistah/wallet-service/internal/apperrors"
+	"github.com/limistah/wallet-service/internal/fx"
+	"github.com/limistah/wallet-service/internal/middleware"
+	"github.com/limistah/wallet-service/internal/models"
+	"github.com/limistah/wallet-service/internal/usecases"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+)
+
+// stubWalletUseCase serves the authenticated user's wallet; other methods are not used by v2
+type stubWalletUseCase struct {
+	usecases.WalletUseCase
+	wallet  *models.Wallet
+	display *fx.Conversion
+}
+
+func (s *stubWalletUseCase) GetWalletByUserID(ctx context.Context, userID uint) (*models.Wallet, error) {
+	if s.wallet == nil || s.wallet.UserID != userID {
+		return nil, apperrors.ErrWalletNotFound
+	}
+	return s.wallet, nil
+}
+
+func (s *stubWalletUseCase) DisplayConversion(ctx context.Context, userID uint, currency string) *fx.Conversion {
+	return s.display
+}
+
+func newTestRouter(wallet *models.Wallet) *gin.Engine {
+	return newTestRouterWithDisplay(wallet, nil)
+}
+
+func newTestRouterWithDisplay(wallet *models.Wallet, display *fx.Conversion) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+
+	handler := NewWalletHandler(&stubWalletUseCase{wallet: wallet, display: display})
+	router := gin.New()
+	router.Use(middleware.ErrorHandler())
+	router.Use(func(c *gin.Context) {
+		c.Set("user_id", uint(1))
+		c.Next()
+	})
+	router.GET("/api/v2/wallets/me", handler.GetWallet)
+	router.GET("/api/v2/wallets/me/balance", handler.GetWalletBalance)
+	return router
+}
+
+func TestWalletHandler_GetWallet(t *testing.T) {
+	router := newTestRouter(&models.Wallet{
+		ID:       7,
+		UserID:   1,
+		Balance:  decimal.RequireFromString("1000.50"),
+		Currency: "NGN",
+		Status:   models.WalletStatusActive,
+	})
+
+	req, _ := http.NewRequest("GET", "/api/v2/wallets/me", nil)
+	resp := httptest.NewRecorder()
+	router.ServeHTTP(resp, req)
+
+	assert.Equal(t, http.StatusOK, resp.Code)
+
+	var body struct {
+		Data map[string]interface{} `json:"data"`
+	}
+	assert.NoError(t, json.Unmarshal(resp.Body.Bytes(), &body))
+	assert.Equal(t, map[string]interface{}{"amount": "1000.5", "currency": "NGN"}, body.Data["balance"])
+	assert.NotContains(t, body.Data, "currency", "v2 carries the currency inside the balance")
+}
+
+func TestWalletHandler_GetWalletBalance(t *testing.T) {
+	router := newTestRouter(&models.Wallet{ID: 7, UserID: 1, Balance: decimal.NewFromInt(25), Currency: "USD"})
+
+	req, _ := http.NewRequest("GET", "/api/v2/wallets/me/balance", nil)
+	resp := httptest.NewRecorder()
+	router.ServeHTTP(resp, req)
+
+	assert.Equal(t, http.StatusOK, resp.Code)
+
+	var body struct {
+		Data json.RawMessage `json:"data"`
+	}
+	assert.NoError(t, json.Unmarshal(resp.Body.Bytes(), &body))
+	assert.JSONEq(t, `{"wallet_id": 7, "balance": {"amount": "25", "currency": "USD"}}`, string(body.Data))
+}
+
+func TestWalletHandler_GetWalletBalanceDisplayCurrency(t *testing.T) {
+	wallet := &models.Wallet{ID: 7, UserID: 1, Balance: decimal.RequireFromString("10.50"), Currency: "USD"}
+	router := newTestRouterWithDisplay(wallet, &fx.Conversion{Currency: "NGN", Rate: decimal.NewFromInt(1550)})
+
+	req, _ := http.NewRequest("GET", "/api/v2/wallets/me/balance", nil)
+	resp := httptest.NewRecorder()
+	router.ServeHTTP(resp, req)
+
+	assert.Equal(t, http.StatusOK, resp.Code)
+	var body struct {
+		Data json.RawMessage `json:"data"`
+	}
+	assert.NoError(t, json.Unmarshal(resp.Body.Bytes(), &body))
+	assert.JSONEq(t, `{
+		"wallet_id": 7,
+		"balance": {"amount": "10.5", "currency": "USD"},
+		"display_balance": {"amount": "16275", "currency": "NGN", "rate": "1550", "indicative": true}
+	}`, string(body.Data))
+	assert.NotEqual(t, wallet.ETag(), resp.Header().Get("ETag"), "the display currency is part of the representation")
+
+	// A client holding the plain wallet ETag gets the converted balance rather than a 304
+	req, _ = http.NewRequest("GET", "/api/v2/wallets/me/balance", nil)
+	req.Header.Set("If-None-Match", wallet.ETag())
+	resp = httptest.NewRecorder()
+	router.ServeHTTP(resp, req)
+	assert.Equal(t, http.StatusOK, resp.Code)
+}
+
+func TestWalletHandler_GetWalletNotFound(t *testing.T) {
+	router := newTestRouter(nil)
+
+	req, _ := http.NewRequest("GET", "/api/v2/wallets/me", nil)
+	resp := httptest.NewRecorder()
+	router.ServeHTTP(resp, req)
+
+	assert.Equal(t, http.StatusNotFound, resp.Code)
+	assert.Contains(t, resp.Body.String(), apperrors.CodeWalletNotFound)
+}
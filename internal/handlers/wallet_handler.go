@@ -1,12 +1,12 @@
 package handlers
 
 import (
-	"errors"
 	"net/http"
 	"strconv"
 	"strings"
 
 	"github.com/gin-gonic/gin"
+	"github.com/limistah/wallet-service/internal/apperrors"
 	"github.com/limistah/wallet-service/internal/dto"
 	"github.com/limistah/wallet-service/internal/middleware"
 	"github.com/limistah/wallet-service/internal/models"
@@ -16,22 +16,31 @@ import (
 
 type WalletHandler struct {
 	walletUseCase usecases.WalletUseCase
+	pinUseCase    usecases.PINUseCase
+	stepUpUseCase usecases.TransferChallengeUseCase
 }
 
-func NewWalletHandler(walletUseCase usecases.WalletUseCase) *WalletHandler {
+func NewWalletHandler(walletUseCase usecases.WalletUseCase, pinUseCase usecases.PINUseCase, stepUpUseCase usecases.TransferChallengeUseCase) *WalletHandler {
 	return &WalletHandler{
 		walletUseCase: walletUseCase,
+		pinUseCase:    pinUseCase,
+		stepUpUseCase: stepUpUseCase,
 	}
 }
 
-// getAuthenticatedUserWallet gets the wallet for the authenticated user
+// getAuthenticatedUserWallet gets the default wallet of the authenticated user, or their sandbox wallet
+// for sandbox requests
 func (h *WalletHandler) getAuthenticatedUserWallet(c *gin.Context) (*models.Wallet, error) {
 	userID, exists := middleware.GetUserID(c)
 	if !exists {
-		return nil, errors.New("user not authenticated")
+		return nil, apperrors.ErrUnauthenticated
 	}
 
-	wallet, err := h.walletUseCase.GetWalletByUserID(userID)
+	if middleware.IsSandbox(c) {
+		return h.walletUseCase.GetSandboxWallet(c.Request.Context(), userID)
+	}
+
+	wallet, err := h.walletUseCase.GetWalletByUserID(c.Request.Context(), userID)
 	if err != nil {
 		return nil, err
 	}
@@ -42,12 +51,14 @@ func (h *WalletHandler) getAuthenticatedUserWallet(c *gin.Context) (*models.Wall
 // GetWallet godoc
 //
 //	@Summary		Get wallet by authenticated user
-//	@Description	Retrieve wallet information for the authenticated user
+//	@Description	Retrieve wallet information for the authenticated user; display_balance is an indicative conversion to their display currency, when they chose one
 //	@Tags			wallets
 //	@Accept			json
 //	@Produce		json
 //	@Security		BearerAuth
+//	@Param			If-None-Match	header	string	false	"ETag of a previous response"
 //	@Success		200	{object}	dto.APIResponse{data=dto.WalletResponse}
+//	@Success		304
 //	@Failure		401	{object}	dto.ErrorResponse
 //	@Failure		404	{object}	dto.ErrorResponse
 //	@Failure		500	{object}	dto.ErrorResponse
@@ -55,38 +66,34 @@ func (h *WalletHandler) getAuthenticatedUserWallet(c *gin.Context) (*models.Wall
 func (h *WalletHandler) GetWallet(c *gin.Context) {
 	wallet, err := h.getAuthenticatedUserWallet(c)
 	if err != nil {
-		status := http.StatusNotFound
-		message := "Wallet not found"
-
-		if err.Error() == "user not authenticated" {
-			status = http.StatusUnauthorized
-			message = "User not authenticated"
-		}
-
-		c.JSON(status, dto.ErrorResponse{
-			Success: false,
-			Message: message,
-			Error:   err.Error(),
-		})
+		c.Error(err)
+		return
+	}
+	display := h.walletUseCase.DisplayConversion(c.Request.Context(), wallet.UserID, wallet.Currency)
+	if middleware.NotModified(c, middleware.VariantETag(wallet.ETag(), display.String())) {
 		return
 	}
 
+	response := dto.ToWalletResponse(wallet)
+	response.DisplayBalance = dto.ToDisplayValueResponse(display, wallet.Balance)
 	c.JSON(http.StatusOK, dto.APIResponse{
 		Success: true,
-		Message: "Wallet retrieved successfully",
-		Data:    dto.ToWalletResponse(wallet),
+		Message: middleware.Translate(c, "wallet.retrieved"),
+		Data:    response,
 	})
 }
 
 // GetWalletBalance godoc
 //
 //	@Summary		Get wallet balance
-//	@Description	Retrieve current balance of the authenticated user's wallet
+//	@Description	Retrieve current balance of the authenticated user's wallet; display_balance is an indicative conversion to their display currency, when they chose one
 //	@Tags			wallets
 //	@Accept			json
 //	@Produce		json
 //	@Security		BearerAuth
+//	@Param			If-None-Match	header	string	false	"ETag of a previous response"
 //	@Success		200	{object}	dto.APIResponse{data=dto.BalanceResponse}
+//	@Success		304
 //	@Failure		401	{object}	dto.ErrorResponse
 //	@Failure		404	{object}	dto.ErrorResponse
 //	@Failure		500	{object}	dto.ErrorResponse
@@ -94,33 +101,280 @@ func (h *WalletHandler) GetWallet(c *gin.Context) {
 func (h *WalletHandler) GetWalletBalance(c *gin.Context) {
 	wallet, err := h.getAuthenticatedUserWallet(c)
 	if err != nil {
-		status := http.StatusNotFound
-		message := "Wallet not found"
+		c.Error(err)
+		return
+	}
+	display := h.walletUseCase.DisplayConversion(c.Request.Context(), wallet.UserID, wallet.Currency)
+	if middleware.NotModified(c, middleware.VariantETag(wallet.ETag(), display.String())) {
+		return
+	}
 
-		if err.Error() == "user not authenticated" {
-			status = http.StatusUnauthorized
-			message = "User not authenticated"
-		}
+	c.JSON(http.StatusOK, dto.APIResponse{
+		Success: true,
+		Message: middleware.Translate(c, "wallet.balance_retrieved"),
+		Data: dto.BalanceResponse{
+			WalletID:       wallet.ID,
+			Balance:        wallet.Balance,
+			Currency:       wallet.Currency,
+			DisplayBalance: dto.ToDisplayValueResponse(display, wallet.Balance),
+		},
+	})
+}
+
+// GetWalletLimits godoc
+//
+//	@Summary		Get wallet limits
+//	@Description	Retrieve the limits of the authenticated user's wallet tier with the daily and monthly allowance left, and the largest amount each operation may move right now. Omitted limits are unlimited
+//	@Tags			wallets
+//	@Accept			json
+//	@Produce		json
+//	@Security		BearerAuth
+//	@Success		200	{object}	dto.APIResponse{data=dto.WalletLimitsResponse}
+//	@Failure		401	{object}	dto.ErrorResponse
+//	@Failure		404	{object}	dto.ErrorResponse
+//	@Failure		500	{object}	dto.ErrorResponse
+//	@Router			/wallets/me/limits [get]
+func (h *WalletHandler) GetWalletLimits(c *gin.Context) {
+	wallet, err := h.getAuthenticatedUserWallet(c)
+	if err != nil {
+		c.Error(err)
+		return
+	}
 
-		c.JSON(status, dto.ErrorResponse{
-			Success: false,
-			Message: message,
-			Error:   err.Error(),
-		})
+	limits, err := h.walletUseCase.GetWalletLimits(c.Request.Context(), wallet.ID)
+	if err != nil {
+		c.Error(err).SetMeta("wallet.limits_retrieve_failed")
 		return
 	}
 
 	c.JSON(http.StatusOK, dto.APIResponse{
 		Success: true,
-		Message: "Balance retrieved successfully",
-		Data: dto.BalanceResponse{
-			WalletID: wallet.ID,
-			Balance:  wallet.Balance,
-			Currency: wallet.Currency,
+		Message: middleware.Translate(c, "wallet.limits_retrieved"),
+		Data: dto.WalletLimitsResponse{
+			WalletID:             wallet.ID,
+			Currency:             wallet.Currency,
+			Tier:                 limits.Tier.Name,
+			MaxTransactionAmount: limits.Tier.MaxTransactionAmount,
+			MaxBalance:           limits.Tier.MaxBalance,
+			Daily:                toDebitAllowanceResponse(limits.Daily),
+			Monthly:              toDebitAllowanceResponse(limits.Monthly),
+			Remaining: dto.OperationLimitsResponse{
+				Transfer:   limits.MaxDebit,
+				Withdrawal: limits.MaxDebit,
+				Funding:    limits.MaxCredit,
+			},
 		},
 	})
 }
 
+func toDebitAllowanceResponse(allowance *usecases.DebitAllowance) *dto.DebitAllowanceResponse {
+	if allowance == nil {
+		return nil
+	}
+	return &dto.DebitAllowanceResponse{
+		Limit:     allowance.Limit,
+		Used:      allowance.Used,
+		Remaining: allowance.Remaining,
+		ResetsAt:  allowance.ResetsAt,
+	}
+}
+
+// ListWallets godoc
+//
+//	@Summary		List wallets
+//	@Description	List the wallets the authenticated user owns, business wallets included, in the order they were opened; sandbox requests list sandbox wallets. Given a nickname, only the wallets labelled with it are listed, whatever its case
+//	@Tags			wallets
+//	@Accept			json
+//	@Produce		json
+//	@Security		BearerAuth
+//	@Param			nickname	query		string	false	"Nickname of the wallets, such as Rent"
+//	@Success		200			{object}	dto.APIResponse{data=[]dto.WalletResponse}
+//	@Failure		401			{object}	dto.ErrorResponse
+//	@Failure		500			{object}	dto.ErrorResponse
+//	@Router			/wallets [get]
+func (h *WalletHandler) ListWallets(c *gin.Context) {
+	userID, exists := middleware.GetUserID(c)
+	if !exists {
+		c.Error(apperrors.ErrUnauthenticated)
+		return
+	}
+
+	wallets, err := h.walletUseCase.ListUserWallets(c.Request.Context(), userID, middleware.IsSandbox(c), c.Query("nickname"))
+	if err != nil {
+		c.Error(err).SetMeta("wallet.list_failed")
+		return
+	}
+
+	responses := make([]dto.WalletResponse, len(wallets))
+	for i := range wallets {
+		responses[i] = dto.ToWalletResponse(&wallets[i])
+	}
+
+	c.JSON(http.StatusOK, dto.APIResponse{
+		Success: true,
+		Message: middleware.Translate(c, "wallet.listed"),
+		Data:    responses,
+	})
+}
+
+// CreateWallet godoc
+//
+//	@Summary		Open a wallet
+//	@Description	Open another live wallet for the authenticated user, such as a wallet in a second currency or one set aside for rent. Wallets of a user must differ by currency or nickname. The first wallet of a user becomes their default one; later ones can be made the default with /wallets/{id}/default
+//	@Tags			wallets
+//	@Accept			json
+//	@Produce		json
+//	@Security		BearerAuth
+//	@Param			request	body		dto.CreateWalletRequest	true	"Currency and nickname"
+//	@Success		201		{object}	dto.APIResponse{data=dto.WalletResponse}
+//	@Failure		400		{object}	dto.ErrorResponse
+//	@Failure		401		{object}	dto.ErrorResponse
+//	@Failure		409		{object}	dto.ErrorResponse	"The user already has a wallet in this currency with this nickname"
+//	@Failure		500		{object}	dto.ErrorResponse
+//	@Router			/wallets [post]
+func (h *WalletHandler) CreateWallet(c *gin.Context) {
+	userID, exists := middleware.GetUserID(c)
+	if !exists {
+		c.Error(apperrors.ErrUnauthenticated)
+		return
+	}
+
+	var req dto.CreateWalletRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.Error(apperrors.ErrValidation.Withf("invalid request data: %w", err)).SetMeta("request.invalid")
+		return
+	}
+
+	wallet, err := h.walletUseCase.CreateWallet(c.Request.Context(), userID, req.Currency, req.Nickname)
+	if err != nil {
+		c.Error(err).SetMeta("wallet.create_failed")
+		return
+	}
+
+	c.JSON(http.StatusCreated, dto.APIResponse{
+		Success: true,
+		Message: middleware.Translate(c, "wallet.created"),
+		Data:    dto.ToWalletResponse(wallet),
+	})
+}
+
+// SetWalletNickname godoc
+//
+//	@Summary		Name a wallet
+//	@Description	Label a wallet of the authenticated user, such as "Rent" or "Travel", to tell their wallets apart; an empty nickname clears it. Nicknames are shown to the owner only
+//	@Tags			wallets
+//	@Accept			json
+//	@Produce		json
+//	@Security		BearerAuth
+//	@Param			id		path		int							true	"Wallet ID"
+//	@Param			request	body		dto.WalletNicknameRequest	true	"Nickname"
+//	@Success		200		{object}	dto.APIResponse{data=dto.WalletResponse}
+//	@Failure		400		{object}	dto.ErrorResponse
+//	@Failure		401		{object}	dto.ErrorResponse
+//	@Failure		404		{object}	dto.ErrorResponse	"No wallet of the user with this ID"
+//	@Failure		500		{object}	dto.ErrorResponse
+//	@Router			/wallets/{id}/nickname [put]
+func (h *WalletHandler) SetWalletNickname(c *gin.Context) {
+	userID, exists := middleware.GetUserID(c)
+	if !exists {
+		c.Error(apperrors.ErrUnauthenticated)
+		return
+	}
+
+	walletID, err := parseIDParam(c, "id")
+	if err != nil {
+		c.Error(err).SetMeta("wallet.invalid_id")
+		return
+	}
+
+	var req dto.WalletNicknameRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.Error(apperrors.ErrValidation.Withf("invalid request data: %w", err)).SetMeta("request.invalid")
+		return
+	}
+
+	wallet, err := h.walletUseCase.SetWalletNickname(c.Request.Context(), userID, walletID, req.Nickname)
+	if err != nil {
+		c.Error(err).SetMeta("wallet.nickname_failed")
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.APIResponse{
+		Success: true,
+		Message: middleware.Translate(c, "wallet.nickname_set"),
+		Data:    dto.ToWalletResponse(wallet),
+	})
+}
+
+// SetDefaultWallet godoc
+//
+//	@Summary		Set the default wallet
+//	@Description	Make an active live wallet of the authenticated user their default one, in place of the previous default. Payments addressed to the user, such as money requests, escrows and other endpoints that take no wallet, and fundings of /wallets/me land in the default wallet, which /wallets/me returns
+//	@Tags			wallets
+//	@Accept			json
+//	@Produce		json
+//	@Security		BearerAuth
+//	@Param			id	path		int	true	"Wallet ID"
+//	@Success		200	{object}	dto.APIResponse{data=dto.WalletResponse}
+//	@Failure		400	{object}	dto.ErrorResponse	"Sandbox or inactive wallet"
+//	@Failure		401	{object}	dto.ErrorResponse
+//	@Failure		404	{object}	dto.ErrorResponse	"No wallet of the user with this ID"
+//	@Failure		500	{object}	dto.ErrorResponse
+//	@Router			/wallets/{id}/default [put]
+func (h *WalletHandler) SetDefaultWallet(c *gin.Context) {
+	userID, exists := middleware.GetUserID(c)
+	if !exists {
+		c.Error(apperrors.ErrUnauthenticated)
+		return
+	}
+
+	walletID, err := parseIDParam(c, "id")
+	if err != nil {
+		c.Error(err).SetMeta("wallet.invalid_id")
+		return
+	}
+
+	wallet, err := h.walletUseCase.SetDefaultWallet(c.Request.Context(), userID, walletID)
+	if err != nil {
+		c.Error(err).SetMeta("wallet.default_failed")
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.APIResponse{
+		Success: true,
+		Message: middleware.Translate(c, "wallet.default_set"),
+		Data:    dto.ToWalletResponse(wallet),
+	})
+}
+
+// ResolveHandle godoc
+//
+//	@Summary		Resolve a handle
+//	@Description	Find the user going by a handle, written with or without its @, and the default wallet payments to them land in, to confirm who is paid before sending to_handle with a transfer
+//	@Tags			wallets
+//	@Accept			json
+//	@Produce		json
+//	@Security		BearerAuth
+//	@Param			handle	path		string	true	"Handle"
+//	@Success		200		{object}	dto.APIResponse{data=dto.HandleResponse}
+//	@Failure		401		{object}	dto.ErrorResponse
+//	@Failure		404		{object}	dto.ErrorResponse	"No user goes by the handle (code HANDLE_NOT_FOUND)"
+//	@Failure		500		{object}	dto.ErrorResponse
+//	@Router			/handles/{handle} [get]
+func (h *WalletHandler) ResolveHandle(c *gin.Context) {
+	wallet, err := h.walletUseCase.ResolveHandle(c.Request.Context(), c.Param("handle"))
+	if err != nil {
+		c.Error(err).SetMeta("handle.resolve_failed")
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.APIResponse{
+		Success: true,
+		Message: middleware.Translate(c, "handle.resolved"),
+		Data:    dto.ToHandleResponse(wallet),
+	})
+}
+
 // FundWallet godoc
 //
 //	@Summary		Fund wallet
@@ -140,52 +394,24 @@ func (h *WalletHandler) GetWalletBalance(c *gin.Context) {
 func (h *WalletHandler) FundWallet(c *gin.Context) {
 	wallet, err := h.getAuthenticatedUserWallet(c)
 	if err != nil {
-		status := http.StatusNotFound
-		message := "Wallet not found"
-
-		if err.Error() == "user not authenticated" {
-			status = http.StatusUnauthorized
-			message = "User not authenticated"
-		}
-
-		c.JSON(status, dto.ErrorResponse{
-			Success: false,
-			Message: message,
-			Error:   err.Error(),
-		})
+		c.Error(err)
 		return
 	}
 
 	var req dto.FundWalletRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, dto.ErrorResponse{
-			Success: false,
-			Message: "Invalid request data",
-			Error:   err.Error(),
-		})
+		c.Error(apperrors.ErrValidation.Withf("invalid request data: %w", err)).SetMeta("request.invalid")
 		return
 	}
 
 	if req.Amount.LessThanOrEqual(decimal.Zero) {
-		c.JSON(http.StatusBadRequest, dto.ErrorResponse{
-			Success: false,
-			Message: "Amount must be greater than zero",
-			Error:   "invalid amount",
-		})
+		c.Error(apperrors.ErrInvalidAmount)
 		return
 	}
 
-	userTransaction, systemTransaction, err := h.walletUseCase.FundWallet(wallet.ID, req.Amount, req.Reference, req.Description)
+	userTransaction, systemTransaction, err := h.walletUseCase.FundWallet(c.Request.Context(), wallet.ID, req.Amount, req.Reference, req.Description)
 	if err != nil {
-		status := http.StatusInternalServerError
-		if err.Error() == "duplicate reference" {
-			status = http.StatusConflict
-		}
-		c.JSON(status, dto.ErrorResponse{
-			Success: false,
-			Message: "Failed to fund wallet",
-			Error:   err.Error(),
-		})
+		c.Error(err).SetMeta("wallet.fund_failed")
 		return
 	}
 
@@ -197,7 +423,7 @@ func (h *WalletHandler) FundWallet(c *gin.Context) {
 
 	c.JSON(http.StatusOK, dto.APIResponse{
 		Success: true,
-		Message: "Wallet funded successfully",
+		Message: middleware.Translate(c, "wallet.funded"),
 		Data:    response,
 	})
 }
@@ -205,89 +431,75 @@ func (h *WalletHandler) FundWallet(c *gin.Context) {
 // WithdrawFunds godoc
 //
 //	@Summary		Withdraw funds
-//	@Description	Withdraw money from the authenticated user's wallet
+//	@Description	Withdraw money from the authenticated user's wallet. When bank payouts are enabled a bank account is required and the withdrawal completes asynchronously. Users who set a transaction PIN send it with withdrawals above the PIN threshold.
 //	@Tags			wallets
 //	@Accept			json
 //	@Produce		json
 //	@Security		BearerAuth
 //	@Param			request	body		dto.WithdrawRequest	true	"Withdraw request"
 //	@Success		200		{object}	dto.APIResponse{data=dto.TransactionResponse}
+//	@Success		202		{object}	dto.APIResponse{data=dto.TransactionResponse}	"Payout pending, or held for fraud or AML review"
 //	@Failure		400		{object}	dto.ErrorResponse
 //	@Failure		401		{object}	dto.ErrorResponse
+//	@Failure		403		{object}	dto.ErrorResponse	"Missing, wrong or locked transaction PIN (codes PIN_REQUIRED, INVALID_PIN, PIN_LOCKED), or blocked by a fraud rule, the compliance blocklist or AML screening"
 //	@Failure		404		{object}	dto.ErrorResponse
 //	@Failure		409		{object}	dto.ErrorResponse	"Duplicate reference or insufficient funds"
 //	@Failure		500		{object}	dto.ErrorResponse
+//	@Failure		502		{object}	dto.ErrorResponse
 //	@Router			/wallets/me/withdraw [post]
 func (h *WalletHandler) WithdrawFunds(c *gin.Context) {
 	wallet, err := h.getAuthenticatedUserWallet(c)
 	if err != nil {
-		status := http.StatusNotFound
-		message := "Wallet not found"
-
-		if err.Error() == "user not authenticated" {
-			status = http.StatusUnauthorized
-			message = "User not authenticated"
-		}
-
-		c.JSON(status, dto.ErrorResponse{
-			Success: false,
-			Message: message,
-			Error:   err.Error(),
-		})
+		c.Error(err)
 		return
 	}
 
 	var req dto.WithdrawRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, dto.ErrorResponse{
-			Success: false,
-			Message: "Invalid request data",
-			Error:   err.Error(),
-		})
+		c.Error(apperrors.ErrValidation.Withf("invalid request data: %w", err)).SetMeta("request.invalid")
 		return
 	}
 
 	if req.Amount.LessThanOrEqual(decimal.Zero) {
-		c.JSON(http.StatusBadRequest, dto.ErrorResponse{
-			Success: false,
-			Message: "Amount must be greater than zero",
-			Error:   "invalid amount",
-		})
-		return
-	}
-
-	userTransaction, systemTransaction, err := h.walletUseCase.WithdrawFunds(wallet.ID, req.Amount, req.Reference, req.Description)
-	if err != nil {
-		status := http.StatusInternalServerError
-		message := "Failed to withdraw funds"
-
-		// Handle specific error types
-		switch {
-		case err.Error() == "insufficient funds":
-			status = http.StatusConflict
-			message = "Insufficient funds for withdrawal"
-		case err.Error() == "duplicate reference":
-			status = http.StatusConflict
-			message = "Duplicate transaction reference"
-		case strings.Contains(err.Error(), "balance mismatch detected"):
-			status = http.StatusConflict
-			message = "Wallet balance inconsistency detected. Please contact support."
-		case strings.Contains(err.Error(), "reconciliation"):
-			status = http.StatusServiceUnavailable
-			message = "Wallet reconciliation in progress. Please try again later."
+		c.Error(apperrors.ErrInvalidAmount)
+		return
+	}
+
+	if err := h.pinUseCase.AuthorizeDebit(c.Request.Context(), wallet.UserID, req.Amount, req.PIN); err != nil {
+		c.Error(err)
+		return
+	}
+
+	var destination *models.BankAccount
+	if req.BankAccount != nil {
+		destination = &models.BankAccount{
+			BankCode:      strings.TrimSpace(req.BankAccount.BankCode),
+			AccountNumber: strings.TrimSpace(req.BankAccount.AccountNumber),
+			AccountName:   strings.TrimSpace(req.BankAccount.AccountName),
 		}
+	}
 
-		c.JSON(status, dto.ErrorResponse{
-			Success: false,
-			Message: message,
-			Error:   err.Error(),
-		})
+	userTransaction, systemTransaction, err := h.walletUseCase.WithdrawFunds(c.Request.Context(), wallet.ID, req.Amount, req.Reference, req.Description, destination, middleware.GetOrigin(c))
+	if err != nil {
+		c.Error(err).SetMeta("wallet.withdraw_failed")
 		return
 	}
 
-	c.JSON(http.StatusOK, dto.APIResponse{
+	// Bank payouts settle asynchronously; the debit stays PENDING until the provider confirms it
+	status := http.StatusOK
+	message := middleware.Translate(c, "wallet.withdrawn")
+	switch userTransaction.Status {
+	case models.TransactionStatusPending:
+		status = http.StatusAccepted
+		message = middleware.Translate(c, "wallet.withdrawal_pending")
+	case models.TransactionStatusPendingReview:
+		status = http.StatusAccepted
+		message = middleware.Translate(c, "wallet.withdrawal_on_hold")
+	}
+
+	c.JSON(status, dto.APIResponse{
 		Success: true,
-		Message: "Funds withdrawn successfully",
+		Message: message,
 		Data: map[string]interface{}{
 			"user_transaction":   dto.ToTransactionResponse(userTransaction),
 			"system_transaction": dto.ToTransactionResponse(systemTransaction),
@@ -298,16 +510,18 @@ func (h *WalletHandler) WithdrawFunds(c *gin.Context) {
 // TransferFunds godoc
 //
 //	@Summary		Transfer funds
-//	@Description	Transfer money from authenticated user's wallet to another wallet
+//	@Description	Transfer money from authenticated user's wallet to another wallet, given by ID or by the handle of its owner, whose default wallet is paid. Users who set a transaction PIN send it with transfers above the PIN threshold. Transfers above the step-up threshold are held: a one-time code is sent to the user's verified phone and the transfer is made once confirmed at /wallets/me/transfer/confirm. A quote_id from /wallets/me/transfer/quote makes the transfer with the quoted fee and exchange rate
 //	@Tags			wallets
 //	@Accept			json
 //	@Produce		json
 //	@Security		BearerAuth
 //	@Param			request	body		dto.TransferRequest	true	"Transfer request"
 //	@Success		200		{object}	dto.APIResponse{data=[]dto.TransactionResponse}
-//	@Failure		400		{object}	dto.ErrorResponse
+//	@Success		202		{object}	dto.APIResponse{data=[]dto.TransactionResponse}	"Held for fraud or AML review, or dto.TransferChallengeResponse when the transfer needs confirming"
+//	@Failure		400		{object}	dto.ErrorResponse	"Invalid request, or a quote that expired or does not match the transfer (codes QUOTE_EXPIRED, QUOTE_MISMATCH)"
 //	@Failure		401		{object}	dto.ErrorResponse
-//	@Failure		404		{object}	dto.ErrorResponse
+//	@Failure		403		{object}	dto.ErrorResponse	"Missing, wrong or locked transaction PIN (codes PIN_REQUIRED, INVALID_PIN, PIN_LOCKED), no verified phone for a step-up confirmation (code STEP_UP_PHONE_REQUIRED), or blocked by a fraud rule, the compliance blocklist, AML or sanctions screening (code SANCTIONS_MATCH)"
+//	@Failure		404		{object}	dto.ErrorResponse	"Unknown wallet or handle (code HANDLE_NOT_FOUND)"
 //	@Failure		409		{object}	dto.ErrorResponse	"Duplicate reference or insufficient funds"
 //	@Failure		500		{object}	dto.ErrorResponse
 //	@Router			/wallets/me/transfer [post]
@@ -315,94 +529,258 @@ func (h *WalletHandler) TransferFunds(c *gin.Context) {
 	// Get the authenticated user's wallet as the source wallet
 	fromWallet, err := h.getAuthenticatedUserWallet(c)
 	if err != nil {
-		status := http.StatusNotFound
-		message := "Source wallet not found"
-
-		if err.Error() == "user not authenticated" {
-			status = http.StatusUnauthorized
-			message = "User not authenticated"
-		}
-
-		c.JSON(status, dto.ErrorResponse{
-			Success: false,
-			Message: message,
-			Error:   err.Error(),
-		})
+		c.Error(err)
 		return
 	}
 
 	var req dto.TransferRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, dto.ErrorResponse{
-			Success: false,
-			Message: "Invalid request data",
-			Error:   err.Error(),
-		})
+		c.Error(apperrors.ErrValidation.Withf("invalid request data: %w", err)).SetMeta("request.invalid")
 		return
 	}
 
+	// A handle pays the default wallet of the user going by it
+	if req.ToHandle != "" {
+		if req.ToWalletID != 0 {
+			c.Error(apperrors.ErrValidation.Withf("send either to_wallet_id or to_handle")).SetMeta("request.invalid")
+			return
+		}
+		recipient, err := h.walletUseCase.ResolveHandle(c.Request.Context(), req.ToHandle)
+		if err != nil {
+			c.Error(err).SetMeta("wallet.transfer_failed")
+			return
+		}
+		req.ToWalletID = recipient.ID
+	}
+
 	// Validate amount
 	if req.Amount.LessThanOrEqual(decimal.Zero) {
-		c.JSON(http.StatusBadRequest, dto.ErrorResponse{
-			Success: false,
-			Message: "Amount must be greater than zero",
-			Error:   "invalid amount",
-		})
+		c.Error(apperrors.ErrInvalidAmount)
 		return
 	}
 
 	// Validate that source and destination are different
 	if fromWallet.ID == req.ToWalletID {
-		c.JSON(http.StatusBadRequest, dto.ErrorResponse{
-			Success: false,
-			Message: "Cannot transfer to the same wallet",
-			Error:   "invalid transfer",
-		})
-		return
-	}
-
-	outTx, inTx, err := h.walletUseCase.TransferFunds(fromWallet.ID, req.ToWalletID, req.Amount, req.Reference, req.Description)
-	if err != nil {
-		status := http.StatusInternalServerError
-		message := "Failed to transfer funds"
-
-		// Handle specific error types
-		switch {
-		case err.Error() == "insufficient funds":
-			status = http.StatusConflict
-			message = "Insufficient funds for transfer"
-		case err.Error() == "duplicate reference":
-			status = http.StatusConflict
-			message = "Duplicate transaction reference"
-		case strings.Contains(err.Error(), "balance mismatch detected"):
-			status = http.StatusConflict
-			message = "Wallet balance inconsistency detected. Please contact support."
-		case strings.Contains(err.Error(), "reconciliation"):
-			status = http.StatusServiceUnavailable
-			message = "Wallet reconciliation in progress. Please try again later."
-		case strings.Contains(err.Error(), "source wallet"):
-			status = http.StatusNotFound
-			message = "Source wallet not found or access denied"
-		case strings.Contains(err.Error(), "destination wallet"):
-			status = http.StatusNotFound
-			message = "Destination wallet not found or inactive"
+		c.Error(apperrors.ErrSelfPayment)
+		return
+	}
+
+	if err := h.pinUseCase.AuthorizeDebit(c.Request.Context(), fromWallet.UserID, req.Amount, req.PIN); err != nil {
+		c.Error(err)
+		return
+	}
+
+	// Sandbox transfers move test money and are never challenged
+	if !middleware.IsSandbox(c) {
+		challenge, err := h.stepUpUseCase.StartTransfer(c.Request.Context(), fromWallet.UserID, fromWallet.ID, req.ToWalletID, req.Amount, req.Reference, req.Description, req.QuoteID)
+		if err != nil {
+			c.Error(err).SetMeta("wallet.transfer_failed")
+			return
+		}
+		if challenge != nil {
+			c.JSON(http.StatusAccepted, dto.APIResponse{
+				Success: true,
+				Message: middleware.Translate(c, "wallet.transfer_challenge"),
+				Data:    dto.ToTransferChallengeResponse(challenge),
+			})
+			return
+		}
+	}
+
+	var outTx, inTx *models.Transaction
+	if req.QuoteID != nil {
+		outTx, inTx, err = h.walletUseCase.TransferFundsWithQuote(c.Request.Context(), *req.QuoteID, fromWallet.ID, req.ToWalletID, req.Amount, req.Reference, req.Description, middleware.GetOrigin(c))
+	} else {
+		outTx, inTx, err = h.walletUseCase.TransferFunds(c.Request.Context(), fromWallet.ID, req.ToWalletID, req.Amount, req.Reference, req.Description, middleware.GetOrigin(c))
+	}
+	if err != nil {
+		c.Error(err).SetMeta("wallet.transfer_failed")
+		return
+	}
+
+	h.respondTransfer(c, fromWallet, outTx, inTx)
+}
+
+// SplitPayment godoc
+//
+//	@Summary		Split a payment between wallets
+//	@Description	Debit the authenticated user's wallet once and credit 2 to 50 wallets of the same currency, each by a fixed amount or a percentage of what the fixed amounts leave, all at once. The transfer fee is charged once on the whole amount. The credits are linked to the debit and share the journal entry named by the reference. Splits are not held for review or a step-up confirmation: one above the step-up threshold, or that a fraud rule or AML screening would hold, is refused
+//	@Tags			wallets
+//	@Accept			json
+//	@Produce		json
+//	@Security		BearerAuth
+//	@Param			request	body		dto.SplitPaymentRequest	true	"Split payment"
+//	@Success		201		{object}	dto.APIResponse{data=dto.SplitPaymentResponse}
+//	@Failure		400		{object}	dto.ErrorResponse	"Invalid recipients, shares that do not add up, or a recipient in another currency (code CURRENCY_MISMATCH)"
+//	@Failure		401		{object}	dto.ErrorResponse
+//	@Failure		403		{object}	dto.ErrorResponse	"Missing, wrong or locked transaction PIN, or refused by a fraud rule, the compliance blocklist or AML screening"
+//	@Failure		404		{object}	dto.ErrorResponse
+//	@Failure		409		{object}	dto.ErrorResponse	"Duplicate reference or insufficient funds"
+//	@Failure		500		{object}	dto.ErrorResponse
+//	@Router			/wallets/me/splits [post]
+func (h *WalletHandler) SplitPayment(c *gin.Context) {
+	fromWallet, err := h.getAuthenticatedUserWallet(c)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	var req dto.SplitPaymentRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.Error(apperrors.ErrValidation.Withf("invalid request data: %w", err)).SetMeta("request.invalid")
+		return
+	}
+
+	if req.Amount.LessThanOrEqual(decimal.Zero) {
+		c.Error(apperrors.ErrInvalidAmount)
+		return
+	}
+
+	if err := h.pinUseCase.AuthorizeDebit(c.Request.Context(), fromWallet.UserID, req.Amount, req.PIN); err != nil {
+		c.Error(err)
+		return
+	}
+
+	// A split cannot be held for a one-time code, so only amounts a transfer would not challenge go through
+	if !middleware.IsSandbox(c) {
+		if err := h.stepUpUseCase.CheckUnchallenged(c.Request.Context(), req.Amount); err != nil {
+			c.Error(err).SetMeta("wallet.split_failed")
+			return
 		}
+	}
 
-		c.JSON(status, dto.ErrorResponse{
-			Success: false,
-			Message: message,
-			Error:   err.Error(),
-		})
+	recipients := make([]usecases.SplitRecipient, len(req.Recipients))
+	for i, recipient := range req.Recipients {
+		recipients[i] = usecases.SplitRecipient{WalletID: recipient.WalletID, Amount: recipient.Amount, Percentage: recipient.Percentage}
+	}
+
+	split, err := h.walletUseCase.SplitFunds(c.Request.Context(), fromWallet.ID, req.Amount, recipients, req.Reference, req.Description, middleware.GetOrigin(c))
+	if err != nil {
+		c.Error(err).SetMeta("wallet.split_failed")
+		return
+	}
+
+	c.JSON(http.StatusCreated, dto.APIResponse{
+		Success: true,
+		Message: middleware.Translate(c, "wallet.split"),
+		Data:    dto.ToSplitPaymentResponse(split.Reference, split.Debit, split.Credits),
+	})
+}
+
+// QuoteTransfer godoc
+//
+//	@Summary		Quote a transfer
+//	@Description	Check a transfer from the authenticated user's wallet without making it and return its fee, the exchange rate and credited amount when the wallets' currencies differ, and the resulting balance. Sending the quote_id with the transfer before the quote expires makes it with these terms; a quote can be used once
+//	@Tags			wallets
+//	@Accept			json
+//	@Produce		json
+//	@Security		BearerAuth
+//	@Param			request	body		dto.TransferQuoteRequest	true	"Transfer to quote"
+//	@Success		200		{object}	dto.APIResponse{data=dto.TransferQuoteResponse}
+//	@Failure		400		{object}	dto.ErrorResponse	"Invalid amount, or no exchange rate between the wallets' currencies (code CURRENCY_MISMATCH)"
+//	@Failure		401		{object}	dto.ErrorResponse
+//	@Failure		404		{object}	dto.ErrorResponse
+//	@Failure		409		{object}	dto.ErrorResponse	"Insufficient funds"
+//	@Failure		500		{object}	dto.ErrorResponse
+//	@Router			/wallets/me/transfer/quote [post]
+func (h *WalletHandler) QuoteTransfer(c *gin.Context) {
+	fromWallet, err := h.getAuthenticatedUserWallet(c)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	var req dto.TransferQuoteRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.Error(apperrors.ErrValidation.Withf("invalid request data: %w", err)).SetMeta("request.invalid")
+		return
+	}
+
+	quote, err := h.walletUseCase.QuoteTransfer(c.Request.Context(), fromWallet.UserID, fromWallet.ID, req.ToWalletID, req.Amount)
+	if err != nil {
+		c.Error(err).SetMeta("wallet.transfer_quote_failed")
 		return
 	}
 
 	c.JSON(http.StatusOK, dto.APIResponse{
 		Success: true,
-		Message: "Funds transferred successfully",
-		Data: []dto.TransactionResponse{
-			dto.ToTransactionResponse(outTx),
-			dto.ToTransactionResponse(inTx),
-		},
+		Message: middleware.Translate(c, "wallet.transfer_quoted"),
+		Data:    dto.ToTransferQuoteResponse(quote, fromWallet),
+	})
+}
+
+// ConfirmTransfer godoc
+//
+//	@Summary		Confirm a held transfer
+//	@Description	Make a transfer held by a step-up challenge with the one-time code sent to the user's verified phone. A challenge can be confirmed once; after 5 wrong codes or once expired the transfer has to be started again
+//	@Tags			wallets
+//	@Accept			json
+//	@Produce		json
+//	@Security		BearerAuth
+//	@Param			request	body		dto.ConfirmTransferRequest	true	"Challenge and code"
+//	@Success		200		{object}	dto.APIResponse{data=[]dto.TransactionResponse}
+//	@Success		202		{object}	dto.APIResponse{data=[]dto.TransactionResponse}	"Held for fraud or AML review"
+//	@Failure		400		{object}	dto.ErrorResponse	"Wrong code (code INVALID_CHALLENGE_CODE) or expired challenge (code CHALLENGE_EXPIRED)"
+//	@Failure		401		{object}	dto.ErrorResponse
+//	@Failure		403		{object}	dto.ErrorResponse
+//	@Failure		404		{object}	dto.ErrorResponse
+//	@Failure		409		{object}	dto.ErrorResponse	"Duplicate reference or insufficient funds"
+//	@Failure		500		{object}	dto.ErrorResponse
+//	@Router			/wallets/me/transfer/confirm [post]
+func (h *WalletHandler) ConfirmTransfer(c *gin.Context) {
+	userID, exists := middleware.GetUserID(c)
+	if !exists {
+		c.Error(apperrors.ErrUnauthenticated)
+		return
+	}
+
+	var req dto.ConfirmTransferRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.Error(apperrors.ErrValidation.Withf("invalid request data: %w", err)).SetMeta("request.invalid")
+		return
+	}
+
+	outTx, inTx, err := h.stepUpUseCase.ConfirmTransfer(c.Request.Context(), userID, req.ChallengeID, req.Code, middleware.GetOrigin(c))
+	if err != nil {
+		c.Error(err).SetMeta("wallet.transfer_failed")
+		return
+	}
+
+	// The transfer is made by now, so a wallet that cannot be read only leaves the nickname out
+	fromWallet, err := h.walletUseCase.GetWallet(c.Request.Context(), outTx.WalletID)
+	if err != nil || fromWallet.UserID != userID {
+		fromWallet = nil
+	}
+	h.respondTransfer(c, fromWallet, outTx, inTx)
+}
+
+// respondTransfer writes the legs of a transfer, the sender's leg labelled with the nickname of the
+// wallet it was made from
+func (h *WalletHandler) respondTransfer(c *gin.Context, fromWallet *models.Wallet, outTx, inTx *models.Transaction) {
+	// Transfers held by a fraud rule or parked by AML screening are only credited to the recipient once reviewed
+	status := http.StatusOK
+	message := middleware.Translate(c, "wallet.transferred")
+	switch outTx.Status {
+	case models.TransactionStatusPending:
+		status = http.StatusAccepted
+		message = middleware.Translate(c, "wallet.transfer_pending")
+	case models.TransactionStatusPendingReview:
+		status = http.StatusAccepted
+		message = middleware.Translate(c, "wallet.transfer_on_hold")
+	}
+
+	legs := []dto.TransactionResponse{
+		dto.ToTransactionResponse(outTx),
+		dto.ToTransactionResponse(inTx),
+	}
+	if fromWallet != nil {
+		legs[0].WalletNickname = fromWallet.Nickname
+	}
+
+	c.JSON(status, dto.APIResponse{
+		Success: true,
+		Message: message,
+		Data:    legs,
 	})
 }
 
@@ -417,6 +795,7 @@ func (h *WalletHandler) TransferFunds(c *gin.Context) {
 //	@Param			cursor		query		string	false	"Cursor for pagination"
 //	@Param			limit		query		int		false	"Page size"		default(20)
 //	@Success		200			{object}	dto.APIResponse{data=dto.TransactionHistoryResponse}
+//	@Header			200			{string}	Link	"RFC 5988 links to the next page, and to the first once paged"
 //	@Failure		400			{object}	dto.ErrorResponse
 //	@Failure		401			{object}	dto.ErrorResponse
 //	@Failure		404			{object}	dto.ErrorResponse
@@ -425,19 +804,7 @@ func (h *WalletHandler) TransferFunds(c *gin.Context) {
 func (h *WalletHandler) GetTransactionHistory(c *gin.Context) {
 	wallet, err := h.getAuthenticatedUserWallet(c)
 	if err != nil {
-		status := http.StatusNotFound
-		message := "Wallet not found"
-
-		if err.Error() == "user not authenticated" {
-			status = http.StatusUnauthorized
-			message = "User not authenticated"
-		}
-
-		c.JSON(status, dto.ErrorResponse{
-			Success: false,
-			Message: message,
-			Error:   err.Error(),
-		})
+		c.Error(err)
 		return
 	}
 
@@ -459,21 +826,13 @@ func (h *WalletHandler) GetTransactionHistory(c *gin.Context) {
 
 	// Validate direction
 	if direction != "next" && direction != "prev" {
-		c.JSON(http.StatusBadRequest, dto.ErrorResponse{
-			Success: false,
-			Message: "Invalid direction parameter. Use 'next' or 'prev'",
-			Error:   "invalid direction",
-		})
+		c.Error(apperrors.ErrValidation.Withf("invalid direction")).SetMeta("request.invalid_direction")
 		return
 	}
 
-	transactions, nextCursor, err := h.walletUseCase.GetTransactionHistory(wallet.ID, cursorPtr, limit)
+	transactions, nextCursor, err := h.walletUseCase.GetTransactionHistory(c.Request.Context(), wallet.ID, cursorPtr, limit)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, dto.ErrorResponse{
-			Success: false,
-			Message: "Failed to retrieve transaction history",
-			Error:   err.Error(),
-		})
+		c.Error(err).SetMeta("transactions.retrieve_failed")
 		return
 	}
 
@@ -483,6 +842,7 @@ func (h *WalletHandler) GetTransactionHistory(c *gin.Context) {
 		transactionResponses[i] = dto.ToTransactionResponse(&tx)
 	}
 
+	setCursorLinks(c, nextCursor)
 	response := dto.TransactionHistoryResponse{
 		Transactions: transactionResponses,
 		Pagination: dto.CursorPaginationMeta{
@@ -494,7 +854,130 @@ func (h *WalletHandler) GetTransactionHistory(c *gin.Context) {
 
 	c.JSON(http.StatusOK, dto.APIResponse{
 		Success: true,
-		Message: "Transaction history retrieved successfully",
+		Message: middleware.Translate(c, "transactions.retrieved"),
 		Data:    response,
 	})
 }
+
+// GetTransaction godoc
+//
+//	@Summary		Get a transaction
+//	@Description	Get a transaction of the authenticated user's wallet with its metadata, related transaction and status history. Transactions of other wallets are reported as not found.
+//	@Tags			wallets
+//	@Accept			json
+//	@Produce		json
+//	@Security		BearerAuth
+//	@Param			id	path		int	true	"Transaction ID"
+//	@Success		200	{object}	dto.APIResponse{data=dto.TransactionDetailResponse}
+//	@Failure		400	{object}	dto.ErrorResponse
+//	@Failure		401	{object}	dto.ErrorResponse
+//	@Failure		404	{object}	dto.ErrorResponse
+//	@Failure		500	{object}	dto.ErrorResponse
+//	@Router			/wallets/me/transactions/{id} [get]
+func (h *WalletHandler) GetTransaction(c *gin.Context) {
+	transactionID, err := parseIDParam(c, "id")
+	if err != nil {
+		c.Error(apperrors.ErrValidation.Withf("invalid transaction id")).SetMeta("request.invalid")
+		return
+	}
+
+	wallet, err := h.getAuthenticatedUserWallet(c)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	transaction, related, history, err := h.walletUseCase.GetWalletTransaction(c.Request.Context(), wallet.ID, transactionID)
+	if err != nil {
+		c.Error(err).SetMeta("transaction.retrieve_failed")
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.APIResponse{
+		Success: true,
+		Message: middleware.Translate(c, "transaction.retrieved"),
+		Data:    dto.ToTransactionDetailResponse(transaction, related, history),
+	})
+}
+
+// CategorizeTransaction godoc
+//
+//	@Summary		Categorize a transaction
+//	@Description	Tag a transaction of the authenticated user's wallet with a spending category. Completed debits count against the budget of their category; an empty category clears it.
+//	@Tags			wallets
+//	@Accept			json
+//	@Produce		json
+//	@Security		BearerAuth
+//	@Param			id		path		int									true	"Transaction ID"
+//	@Param			request	body		dto.CategorizeTransactionRequest	true	"Category"
+//	@Success		200		{object}	dto.APIResponse{data=dto.TransactionResponse}
+//	@Failure		400		{object}	dto.ErrorResponse
+//	@Failure		401		{object}	dto.ErrorResponse
+//	@Failure		404		{object}	dto.ErrorResponse
+//	@Failure		500		{object}	dto.ErrorResponse
+//	@Router			/wallets/me/transactions/{id}/category [put]
+func (h *WalletHandler) CategorizeTransaction(c *gin.Context) {
+	transactionID, err := parseIDParam(c, "id")
+	if err != nil {
+		c.Error(apperrors.ErrValidation.Withf("invalid transaction id")).SetMeta("request.invalid")
+		return
+	}
+
+	var req dto.CategorizeTransactionRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.Error(apperrors.ErrValidation.Withf("invalid request data: %w", err)).SetMeta("request.invalid")
+		return
+	}
+
+	wallet, err := h.getAuthenticatedUserWallet(c)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	transaction, err := h.walletUseCase.CategorizeTransaction(c.Request.Context(), wallet.ID, transactionID, req.Category)
+	if err != nil {
+		c.Error(err).SetMeta("transaction.categorize_failed")
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.APIResponse{
+		Success: true,
+		Message: middleware.Translate(c, "transaction.categorized"),
+		Data:    dto.ToTransactionResponse(transaction),
+	})
+}
+
+// GetTransactionByReference godoc
+//
+//	@Summary		Get a transaction by reference
+//	@Description	Look up the outcome of an operation by the reference it was made with: the authenticated user's leg and its double-entry counter leg. Transfers are found by the reference sent with them as well as by the -OUT and -IN legs.
+//	@Tags			wallets
+//	@Accept			json
+//	@Produce		json
+//	@Security		BearerAuth
+//	@Param			reference	path		string	true	"Transaction reference"
+//	@Success		200			{object}	dto.APIResponse{data=dto.TransactionLookupResponse}
+//	@Failure		401			{object}	dto.ErrorResponse
+//	@Failure		404			{object}	dto.ErrorResponse
+//	@Failure		500			{object}	dto.ErrorResponse
+//	@Router			/wallets/me/transactions/by-reference/{reference} [get]
+func (h *WalletHandler) GetTransactionByReference(c *gin.Context) {
+	wallet, err := h.getAuthenticatedUserWallet(c)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	transaction, counter, err := h.walletUseCase.GetWalletTransactionByReference(c.Request.Context(), wallet.ID, c.Param("reference"))
+	if err != nil {
+		c.Error(err).SetMeta("transaction.retrieve_failed")
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.APIResponse{
+		Success: true,
+		Message: middleware.Translate(c, "transaction.retrieved"),
+		Data:    dto.ToTransactionLookupResponse(transaction, counter),
+	})
+}
@@ -1,20 +1,33 @@
 package handlers
 
 import (
+	"context"
 	"encoding/base64"
 	"encoding/json"
 	"fmt"
 	"net/http"
 	"net/http/httptest"
+	"net/url"
+	"strings"
 	"testing"
 	"time"
 
 	"github.com/gin-gonic/gin"
+	"github.com/limistah/wallet-service/docs"
+	"github.com/limistah/wallet-service/internal/apperrors"
 	"github.com/limistah/wallet-service/internal/dto"
+	"github.com/limistah/wallet-service/internal/fraud"
+	"github.com/limistah/wallet-service/internal/fx"
+	"github.com/limistah/wallet-service/internal/middleware"
 	"github.com/limistah/wallet-service/internal/models"
+	"github.com/limistah/wallet-service/internal/openapi"
+	"github.com/limistah/wallet-service/internal/payments"
+	"github.com/limistah/wallet-service/internal/repositories"
+	"github.com/limistah/wallet-service/internal/usecases"
 	"github.com/shopspring/decimal"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
 )
 
 // MockWalletUseCase is a mock implementation of WalletUseCase for testing
@@ -22,46 +35,262 @@ type MockWalletUseCase struct {
 	mock.Mock
 }
 
-func (m *MockWalletUseCase) CreateWallet(userID uint, currency string) (*models.Wallet, error) {
-	args := m.Called(userID, currency)
+func (m *MockWalletUseCase) CreateWallet(ctx context.Context, userID uint, currency, nickname string) (*models.Wallet, error) {
+	args := m.Called(userID, currency, nickname)
 	return args.Get(0).(*models.Wallet), args.Error(1)
 }
 
-func (m *MockWalletUseCase) GetWallet(id uint) (*models.Wallet, error) {
+func (m *MockWalletUseCase) GetWallet(ctx context.Context, id uint) (*models.Wallet, error) {
 	args := m.Called(id)
 	return args.Get(0).(*models.Wallet), args.Error(1)
 }
 
-func (m *MockWalletUseCase) GetWalletByUserID(userID uint) (*models.Wallet, error) {
+func (m *MockWalletUseCase) GetWalletByUserID(ctx context.Context, userID uint) (*models.Wallet, error) {
 	args := m.Called(userID)
 	return args.Get(0).(*models.Wallet), args.Error(1)
 }
 
-func (m *MockWalletUseCase) FundWallet(walletID uint, amount decimal.Decimal, reference, description string) (*models.Transaction, *models.Transaction, error) {
+func (m *MockWalletUseCase) ListUserWallets(ctx context.Context, userID uint, sandbox bool, nickname string) ([]models.Wallet, error) {
+	args := m.Called(userID, sandbox, nickname)
+	wallets, _ := args.Get(0).([]models.Wallet)
+	return wallets, args.Error(1)
+}
+
+func (m *MockWalletUseCase) SetWalletNickname(ctx context.Context, userID, walletID uint, nickname string) (*models.Wallet, error) {
+	args := m.Called(userID, walletID, nickname)
+	wallet, _ := args.Get(0).(*models.Wallet)
+	return wallet, args.Error(1)
+}
+
+func (m *MockWalletUseCase) SetDefaultWallet(ctx context.Context, userID, walletID uint) (*models.Wallet, error) {
+	args := m.Called(userID, walletID)
+	wallet, _ := args.Get(0).(*models.Wallet)
+	return wallet, args.Error(1)
+}
+
+func (m *MockWalletUseCase) ResolveHandle(ctx context.Context, handle string) (*models.Wallet, error) {
+	args := m.Called(handle)
+	wallet, _ := args.Get(0).(*models.Wallet)
+	return wallet, args.Error(1)
+}
+
+func (m *MockWalletUseCase) FundWallet(ctx context.Context, walletID uint, amount decimal.Decimal, reference, description string) (*models.Transaction, *models.Transaction, error) {
 	args := m.Called(walletID, amount, reference, description)
 	return args.Get(0).(*models.Transaction), args.Get(1).(*models.Transaction), args.Error(2)
 }
 
-func (m *MockWalletUseCase) WithdrawFunds(walletID uint, amount decimal.Decimal, reference, description string) (*models.Transaction, *models.Transaction, error) {
-	args := m.Called(walletID, amount, reference, description)
+func (m *MockWalletUseCase) WithdrawFunds(ctx context.Context, walletID uint, amount decimal.Decimal, reference, description string, destination *models.BankAccount, origin *fraud.Origin) (*models.Transaction, *models.Transaction, error) {
+	args := m.Called(walletID, amount, reference, description, destination, origin)
 	return args.Get(0).(*models.Transaction), args.Get(1).(*models.Transaction), args.Error(2)
 }
 
-func (m *MockWalletUseCase) TransferFunds(fromWalletID, toWalletID uint, amount decimal.Decimal, reference, description string) (*models.Transaction, *models.Transaction, error) {
-	args := m.Called(fromWalletID, toWalletID, amount, reference, description)
+func (m *MockWalletUseCase) TransferFunds(ctx context.Context, fromWalletID, toWalletID uint, amount decimal.Decimal, reference, description string, origin *fraud.Origin) (*models.Transaction, *models.Transaction, error) {
+	args := m.Called(fromWalletID, toWalletID, amount, reference, description, origin)
 	return args.Get(0).(*models.Transaction), args.Get(1).(*models.Transaction), args.Error(2)
 }
 
-func (m *MockWalletUseCase) GetWalletBalance(walletID uint) (decimal.Decimal, error) {
+func (m *MockWalletUseCase) QuoteTransfer(ctx context.Context, userID, fromWalletID, toWalletID uint, amount decimal.Decimal) (*models.TransferQuote, error) {
+	args := m.Called(userID, fromWalletID, toWalletID, amount)
+	quote, _ := args.Get(0).(*models.TransferQuote)
+	return quote, args.Error(1)
+}
+
+func (m *MockWalletUseCase) TransferFundsWithQuote(ctx context.Context, quoteID, fromWalletID, toWalletID uint, amount decimal.Decimal, reference, description string, origin *fraud.Origin) (*models.Transaction, *models.Transaction, error) {
+	args := m.Called(quoteID, fromWalletID, toWalletID, amount, reference, description, origin)
+	outTx, _ := args.Get(0).(*models.Transaction)
+	inTx, _ := args.Get(1).(*models.Transaction)
+	return outTx, inTx, args.Error(2)
+}
+
+func (m *MockWalletUseCase) SplitFunds(ctx context.Context, fromWalletID uint, amount decimal.Decimal, recipients []usecases.SplitRecipient, reference, description string, origin *fraud.Origin) (*usecases.SplitPayment, error) {
+	args := m.Called(fromWalletID, amount, recipients, reference, description, origin)
+	split, _ := args.Get(0).(*usecases.SplitPayment)
+	return split, args.Error(1)
+}
+
+func (m *MockWalletUseCase) ScreenPayment(ctx context.Context, fromWallet, toWallet *models.Wallet, amount decimal.Decimal, reference string, origin *fraud.Origin) error {
+	args := m.Called(fromWallet, toWallet, amount, reference, origin)
+	return args.Error(0)
+}
+
+func (m *MockWalletUseCase) GetWalletBalance(ctx context.Context, walletID uint) (decimal.Decimal, error) {
 	args := m.Called(walletID)
 	return args.Get(0).(decimal.Decimal), args.Error(1)
 }
 
-func (m *MockWalletUseCase) GetTransactionHistory(walletID uint, cursor *string, limit int) ([]models.Transaction, *string, error) {
+func (m *MockWalletUseCase) GetTransactionHistory(ctx context.Context, walletID uint, cursor *string, limit int) ([]models.Transaction, *string, error) {
 	args := m.Called(walletID, cursor, limit)
 	return args.Get(0).([]models.Transaction), args.Get(1).(*string), args.Error(2)
 }
 
+func (m *MockWalletUseCase) ListWallets(ctx context.Context, filter repositories.WalletFilter, page, pageSize int) ([]models.Wallet, int64, error) {
+	args := m.Called(filter, page, pageSize)
+	return args.Get(0).([]models.Wallet), args.Get(1).(int64), args.Error(2)
+}
+
+func (m *MockWalletUseCase) GetTransaction(ctx context.Context, id uint) (*models.Transaction, error) {
+	args := m.Called(id)
+	return args.Get(0).(*models.Transaction), args.Error(1)
+}
+
+func (m *MockWalletUseCase) GetTransactionByReference(ctx context.Context, reference string) (*models.Transaction, *models.Transaction, error) {
+	args := m.Called(reference)
+	transaction, _ := args.Get(0).(*models.Transaction)
+	counter, _ := args.Get(1).(*models.Transaction)
+	return transaction, counter, args.Error(2)
+}
+
+func (m *MockWalletUseCase) GetWalletTransactionByReference(ctx context.Context, walletID uint, reference string) (*models.Transaction, *models.Transaction, error) {
+	args := m.Called(walletID, reference)
+	transaction, _ := args.Get(0).(*models.Transaction)
+	counter, _ := args.Get(1).(*models.Transaction)
+	return transaction, counter, args.Error(2)
+}
+
+func (m *MockWalletUseCase) GetWalletTransaction(ctx context.Context, walletID, id uint) (*models.Transaction, *models.Transaction, []models.TransactionStatusChange, error) {
+	args := m.Called(walletID, id)
+	transaction, _ := args.Get(0).(*models.Transaction)
+	related, _ := args.Get(1).(*models.Transaction)
+	history, _ := args.Get(2).([]models.TransactionStatusChange)
+	return transaction, related, history, args.Error(3)
+}
+
+func (m *MockWalletUseCase) GetWalletLimits(ctx context.Context, walletID uint) (*usecases.WalletLimits, error) {
+	args := m.Called(walletID)
+	limits, _ := args.Get(0).(*usecases.WalletLimits)
+	return limits, args.Error(1)
+}
+
+func (m *MockWalletUseCase) CategorizeTransaction(ctx context.Context, walletID, id uint, category string) (*models.Transaction, error) {
+	args := m.Called(walletID, id, category)
+	transaction, _ := args.Get(0).(*models.Transaction)
+	return transaction, args.Error(1)
+}
+
+func (m *MockWalletUseCase) ListTransactionTypes(ctx context.Context) ([]models.TransactionTypeDefinition, error) {
+	args := m.Called()
+	return args.Get(0).([]models.TransactionTypeDefinition), args.Error(1)
+}
+
+func (m *MockWalletUseCase) SettlePayout(ctx context.Context, provider string, event payments.PayoutEvent) (*models.Payout, error) {
+	args := m.Called(provider, event)
+	return args.Get(0).(*models.Payout), args.Error(1)
+}
+
+func (m *MockWalletUseCase) GetSandboxWallet(ctx context.Context, userID uint) (*models.Wallet, error) {
+	args := m.Called(userID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.Wallet), args.Error(1)
+}
+
+// DisplayConversion shows no display currency, so tests need not expect it on every wallet read
+func (m *MockWalletUseCase) DisplayConversion(ctx context.Context, userID uint, currency string) *fx.Conversion {
+	return nil
+}
+
+func (m *MockWalletUseCase) MintSandboxFunds(ctx context.Context, walletID uint, amount decimal.Decimal) (*models.Transaction, error) {
+	args := m.Called(walletID, amount)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.Transaction), args.Error(1)
+}
+
+func (m *MockWalletUseCase) ListFraudReviews(ctx context.Context, status models.FraudReviewStatus, page, pageSize int) ([]models.FraudReview, error) {
+	args := m.Called(status, page, pageSize)
+	return args.Get(0).([]models.FraudReview), args.Error(1)
+}
+
+func (m *MockWalletUseCase) ApproveFraudReview(ctx context.Context, reviewID, reviewerID uint, note string) (*models.FraudReview, error) {
+	args := m.Called(reviewID, reviewerID, note)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.FraudReview), args.Error(1)
+}
+
+func (m *MockWalletUseCase) RejectFraudReview(ctx context.Context, reviewID, reviewerID uint, note string) (*models.FraudReview, error) {
+	args := m.Called(reviewID, reviewerID, note)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.FraudReview), args.Error(1)
+}
+
+func (m *MockWalletUseCase) ExpirePendingTransactions(ctx context.Context, before time.Time) (int, error) {
+	args := m.Called(before)
+	return args.Int(0), args.Error(1)
+}
+
+func (m *MockWalletUseCase) AuditWallet(ctx context.Context, walletID uint) error {
+	args := m.Called(walletID)
+	return args.Error(0)
+}
+
+func (m *MockWalletUseCase) DispatchQueuedPayout(ctx context.Context, reference, narration string) error {
+	args := m.Called(reference, narration)
+	return args.Error(0)
+}
+
+func (m *MockWalletUseCase) ListAMLCases(ctx context.Context, status models.AMLCaseStatus, page, pageSize int) ([]models.AMLCase, error) {
+	args := m.Called(status, page, pageSize)
+	return args.Get(0).([]models.AMLCase), args.Error(1)
+}
+
+func (m *MockWalletUseCase) ApproveAMLCase(ctx context.Context, caseID, reviewerID uint, note string) (*models.AMLCase, error) {
+	args := m.Called(caseID, reviewerID, note)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.AMLCase), args.Error(1)
+}
+
+func (m *MockWalletUseCase) RejectAMLCase(ctx context.Context, caseID, reviewerID uint, note string) (*models.AMLCase, error) {
+	args := m.Called(caseID, reviewerID, note)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.AMLCase), args.Error(1)
+}
+
+// stubPINUseCase authorizes debits with err, nil letting every debit through
+type stubPINUseCase struct {
+	err error
+}
+
+func (s *stubPINUseCase) SetPIN(ctx context.Context, userID uint, pin string) error { return nil }
+
+func (s *stubPINUseCase) ChangePIN(ctx context.Context, userID uint, currentPIN, newPIN string) error {
+	return nil
+}
+
+func (s *stubPINUseCase) ResetPIN(ctx context.Context, userID uint, password, newPIN string) error {
+	return nil
+}
+
+func (s *stubPINUseCase) AuthorizeDebit(ctx context.Context, userID uint, amount decimal.Decimal, pin string) error {
+	return s.err
+}
+
+// stubTransferChallengeUseCase holds every transfer with challenge, nil letting them through
+type stubTransferChallengeUseCase struct {
+	challenge *models.TransferChallenge
+}
+
+func (s *stubTransferChallengeUseCase) StartTransfer(ctx context.Context, userID, fromWalletID, toWalletID uint, amount decimal.Decimal, reference, description string, quoteID *uint) (*models.TransferChallenge, error) {
+	return s.challenge, nil
+}
+
+func (s *stubTransferChallengeUseCase) ConfirmTransfer(ctx context.Context, userID, challengeID uint, code string, origin *fraud.Origin) (*models.Transaction, *models.Transaction, error) {
+	return nil, nil, apperrors.ErrChallengeNotFound
+}
+
+func (s *stubTransferChallengeUseCase) CheckUnchallenged(ctx context.Context, amount decimal.Decimal) error {
+	return nil
+}
+
 func createTestCursor(id uint, createdAt time.Time) string {
 	type TransactionCursor struct {
 		ID        uint      `json:"id"`
@@ -142,9 +371,10 @@ func TestWalletHandler_GetTransactionHistoryWithCursor(t *testing.T) {
 			mockUC := new(MockWalletUseCase)
 			tt.setupMock(mockUC)
 
-			handler := NewWalletHandler(mockUC)
+			handler := NewWalletHandler(mockUC, &stubPINUseCase{}, &stubTransferChallengeUseCase{})
 
 			router := gin.New()
+			router.Use(middleware.ErrorHandler())
 			router.Use(func(c *gin.Context) {
 				c.Set("user_id", uint(1)) // Mock authenticated user
 				c.Next()
@@ -172,9 +402,585 @@ func TestWalletHandler_GetTransactionHistoryWithCursor(t *testing.T) {
 				assert.True(t, ok)
 
 				assert.Equal(t, tt.expectedNext, pagination["has_next_page"])
+
+				link := resp.Header().Get("Link")
+				if tt.expectedNext {
+					nextCursor, _ := pagination["next_cursor"].(string)
+					assert.Contains(t, link, "</wallets/me/transactions?"+url.Values{"cursor": {nextCursor}, "limit": {"2"}}.Encode()+`>; rel="next"`)
+				} else {
+					assert.NotContains(t, link, `rel="next"`)
+				}
+				if strings.Contains(tt.queryParams, "cursor=") {
+					assert.Contains(t, link, `</wallets/me/transactions?limit=2>; rel="first"`)
+				}
+			}
+
+			mockUC.AssertExpectations(t)
+		})
+	}
+}
+
+func TestWalletHandler_TransferFundsErrors(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	tests := []struct {
+		name           string
+		err            error
+		expectedStatus int
+		expectedCode   string
+	}{
+		{
+			name:           "insufficient funds",
+			err:            apperrors.ErrInsufficientFunds.Withf("insufficient funds in source wallet: available=10.00, requested=75.00"),
+			expectedStatus: http.StatusConflict,
+			expectedCode:   apperrors.CodeInsufficientFunds,
+		},
+		{
+			name:           "destination wallet not found",
+			err:            apperrors.ErrCounterpartyWalletNotFound,
+			expectedStatus: http.StatusNotFound,
+			expectedCode:   apperrors.CodeCounterpartyWalletNotFound,
+		},
+		{
+			name:           "sanctions match",
+			err:            apperrors.ErrSanctionsMatch,
+			expectedStatus: http.StatusForbidden,
+			expectedCode:   apperrors.CodeSanctionsMatch,
+		},
+		{
+			name:           "balance mismatch behind reconciliation",
+			err:            fmt.Errorf("source wallet reconciliation failed: %w", apperrors.ErrBalanceMismatch),
+			expectedStatus: http.StatusConflict,
+			expectedCode:   apperrors.CodeBalanceMismatch,
+		},
+		{
+			name:           "unexpected failure",
+			err:            fmt.Errorf("failed to create transfer transaction: connection refused"),
+			expectedStatus: http.StatusInternalServerError,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockUC := new(MockWalletUseCase)
+			mockUC.On("GetWalletByUserID", uint(1)).Return(&models.Wallet{ID: 1, UserID: 1}, nil)
+			mockUC.On("TransferFunds", uint(1), uint(2), mock.Anything, "TRF001", "", mock.Anything).
+				Return((*models.Transaction)(nil), (*models.Transaction)(nil), tt.err)
+
+			handler := NewWalletHandler(mockUC, &stubPINUseCase{}, &stubTransferChallengeUseCase{})
+
+			router := gin.New()
+			router.Use(middleware.ErrorHandler())
+			router.Use(func(c *gin.Context) {
+				c.Set("user_id", uint(1))
+				c.Next()
+			})
+			router.POST("/wallets/me/transfer", handler.TransferFunds)
+
+			body := strings.NewReader(`{"to_wallet_id": 2, "amount": "75.00", "reference": "TRF001"}`)
+			req, _ := http.NewRequest("POST", "/wallets/me/transfer", body)
+			req.Header.Set("Content-Type", "application/json")
+			resp := httptest.NewRecorder()
+
+			router.ServeHTTP(resp, req)
+
+			assert.Equal(t, tt.expectedStatus, resp.Code)
+
+			var response dto.ErrorResponse
+			assert.NoError(t, json.Unmarshal(resp.Body.Bytes(), &response))
+			assert.Equal(t, tt.expectedCode, response.Code)
+			assert.Equal(t, tt.err.Error(), response.Error)
+
+			mockUC.AssertExpectations(t)
+		})
+	}
+}
+
+func TestWalletHandler_TransferFundsToHandle(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	mockUC := new(MockWalletUseCase)
+	mockUC.On("GetWalletByUserID", uint(1)).Return(&models.Wallet{ID: 1, UserID: 1}, nil)
+	mockUC.On("ResolveHandle", "@alice").Return(&models.Wallet{ID: 2, UserID: 2}, nil)
+	mockUC.On("ResolveHandle", "@nobody").Return(nil, apperrors.ErrHandleNotFound)
+	outTx := &models.Transaction{ID: 7, Reference: "TRF1-OUT", WalletID: 1, Amount: decimal.NewFromInt(75), Status: models.TransactionStatusCompleted}
+	inTx := &models.Transaction{ID: 8, Reference: "TRF1-IN", WalletID: 2, Amount: decimal.NewFromInt(75), Status: models.TransactionStatusCompleted}
+	mockUC.On("TransferFunds", uint(1), uint(2), mock.Anything, "TRF1", "", mock.Anything).Return(outTx, inTx, nil)
+
+	handler := NewWalletHandler(mockUC, &stubPINUseCase{}, &stubTransferChallengeUseCase{})
+	router := gin.New()
+	router.Use(middleware.ErrorHandler())
+	router.Use(func(c *gin.Context) {
+		c.Set("user_id", uint(1))
+		c.Next()
+	})
+	router.POST("/wallets/me/transfer", handler.TransferFunds)
+
+	tests := []struct {
+		name           string
+		body           string
+		expectedStatus int
+	}{
+		{"handle", `{"to_handle": "@alice", "amount": "75", "reference": "TRF1"}`, http.StatusOK},
+		{"unknown handle", `{"to_handle": "@nobody", "amount": "75", "reference": "TRF1"}`, http.StatusNotFound},
+		{"handle and wallet", `{"to_handle": "@alice", "to_wallet_id": 2, "amount": "75", "reference": "TRF1"}`, http.StatusBadRequest},
+		{"no recipient", `{"amount": "75", "reference": "TRF1"}`, http.StatusBadRequest},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resp := httptest.NewRecorder()
+			req, _ := http.NewRequest("POST", "/wallets/me/transfer", strings.NewReader(tt.body))
+			req.Header.Set("Content-Type", "application/json")
+			router.ServeHTTP(resp, req)
+			assert.Equal(t, tt.expectedStatus, resp.Code)
+		})
+	}
+	mockUC.AssertNumberOfCalls(t, "TransferFunds", 1)
+}
+
+func TestWalletHandler_TransferFundsToHandleWithRequestValidation(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	validator, err := openapi.Load([]byte(docs.SwaggerInfo.ReadDoc()))
+	require.NoError(t, err)
+
+	mockUC := new(MockWalletUseCase)
+	mockUC.On("GetWalletByUserID", uint(1)).Return(&models.Wallet{ID: 1, UserID: 1}, nil)
+	mockUC.On("ResolveHandle", "@alice").Return(&models.Wallet{ID: 2, UserID: 2}, nil)
+	outTx := &models.Transaction{ID: 7, Reference: "TRF1-OUT", WalletID: 1, Amount: decimal.NewFromInt(75), Status: models.TransactionStatusCompleted}
+	inTx := &models.Transaction{ID: 8, Reference: "TRF1-IN", WalletID: 2, Amount: decimal.NewFromInt(75), Status: models.TransactionStatusCompleted}
+	mockUC.On("TransferFunds", uint(1), uint(2), mock.Anything, "TRF1", "", mock.Anything).Return(outTx, inTx, nil)
+
+	handler := NewWalletHandler(mockUC, &stubPINUseCase{}, &stubTransferChallengeUseCase{})
+	router := gin.New()
+	router.Use(middleware.ErrorHandler())
+	router.Use(middleware.ValidateRequests(validator))
+	router.Use(func(c *gin.Context) {
+		c.Set("user_id", uint(1))
+		c.Next()
+	})
+	router.GET("/api/v1/handles/:handle", handler.ResolveHandle)
+	router.POST("/api/v1/wallets/me/transfer", handler.TransferFunds)
+
+	tests := []struct {
+		name           string
+		method         string
+		target         string
+		body           string
+		expectedStatus int
+	}{
+		{"look up the handle", "GET", "/api/v1/handles/@alice", "", http.StatusOK},
+		{"pay the handle", "POST", "/api/v1/wallets/me/transfer", `{"to_handle": "@alice", "amount": 75, "reference": "TRF1"}`, http.StatusOK},
+		{"handle too long", "POST", "/api/v1/wallets/me/transfer", `{"to_handle": "@` + strings.Repeat("a", 31) + `", "amount": 75, "reference": "TRF1"}`, http.StatusBadRequest},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resp := httptest.NewRecorder()
+			req, _ := http.NewRequest(tt.method, tt.target, strings.NewReader(tt.body))
+			req.Header.Set("Content-Type", "application/json")
+			router.ServeHTTP(resp, req)
+			assert.Equal(t, tt.expectedStatus, resp.Code, resp.Body.String())
+		})
+	}
+	mockUC.AssertNumberOfCalls(t, "TransferFunds", 1)
+}
+
+func TestWalletHandler_CreateWallet(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	mockUC := new(MockWalletUseCase)
+	mockUC.On("CreateWallet", uint(1), "EUR", "Travel").Return(&models.Wallet{ID: 3, UserID: 1, Currency: "EUR", Nickname: "Travel", Status: models.WalletStatusActive}, nil)
+	mockUC.On("CreateWallet", uint(1), "USD", "").Return((*models.Wallet)(nil), apperrors.ErrWalletExists)
+
+	handler := NewWalletHandler(mockUC, &stubPINUseCase{}, &stubTransferChallengeUseCase{})
+	router := gin.New()
+	router.Use(middleware.ErrorHandler())
+	router.Use(func(c *gin.Context) {
+		c.Set("user_id", uint(1))
+		c.Next()
+	})
+	router.POST("/wallets", handler.CreateWallet)
+
+	tests := []struct {
+		name           string
+		body           string
+		expectedStatus int
+	}{
+		{"second wallet", `{"currency": "EUR", "nickname": "Travel"}`, http.StatusCreated},
+		{"same wallet twice", `{"currency": "USD"}`, http.StatusConflict},
+		{"no currency", `{"nickname": "Travel"}`, http.StatusBadRequest},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resp := httptest.NewRecorder()
+			req, _ := http.NewRequest("POST", "/wallets", strings.NewReader(tt.body))
+			req.Header.Set("Content-Type", "application/json")
+			router.ServeHTTP(resp, req)
+			assert.Equal(t, tt.expectedStatus, resp.Code)
+			if tt.expectedStatus == http.StatusCreated {
+				var response struct {
+					Data dto.WalletResponse `json:"data"`
+				}
+				assert.NoError(t, json.Unmarshal(resp.Body.Bytes(), &response))
+				assert.Equal(t, uint(3), response.Data.ID)
+				assert.Equal(t, "Travel", response.Data.Nickname)
+			}
+		})
+	}
+	mockUC.AssertNumberOfCalls(t, "CreateWallet", 2)
+}
+
+func TestWalletHandler_TransferFundsLocalizedErrors(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	tests := []struct {
+		name            string
+		acceptLanguage  string
+		body            string
+		err             error
+		expectedStatus  int
+		expectedMessage string
+		expectedLocale  string
+	}{
+		{
+			name:            "validation error in French",
+			acceptLanguage:  "fr-CH, fr;q=0.9, en;q=0.8",
+			body:            `{"to_wallet_id": 2, "amount": "75.00"}`,
+			expectedStatus:  http.StatusBadRequest,
+			expectedMessage: "reference est obligatoire",
+			expectedLocale:  "fr",
+		},
+		{
+			name:            "domain error in Spanish",
+			acceptLanguage:  "es",
+			body:            `{"to_wallet_id": 2, "amount": "75.00", "reference": "TRF001"}`,
+			err:             apperrors.ErrInsufficientFunds,
+			expectedStatus:  http.StatusConflict,
+			expectedMessage: "Fondos insuficientes",
+			expectedLocale:  "es",
+		},
+		{
+			name:            "unsupported language falls back to English",
+			acceptLanguage:  "de",
+			body:            `{"to_wallet_id": 2, "amount": "75.00", "reference": "TRF001"}`,
+			err:             fmt.Errorf("failed to create transfer transaction: connection refused"),
+			expectedStatus:  http.StatusInternalServerError,
+			expectedMessage: "Failed to transfer funds",
+			expectedLocale:  "en",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockUC := new(MockWalletUseCase)
+			mockUC.On("GetWalletByUserID", uint(1)).Return(&models.Wallet{ID: 1, UserID: 1}, nil)
+			if tt.err != nil {
+				mockUC.On("TransferFunds", uint(1), uint(2), mock.Anything, "TRF001", "", mock.Anything).
+					Return((*models.Transaction)(nil), (*models.Transaction)(nil), tt.err)
 			}
 
+			handler := NewWalletHandler(mockUC, &stubPINUseCase{}, &stubTransferChallengeUseCase{})
+
+			router := gin.New()
+			router.Use(middleware.Locale())
+			router.Use(middleware.ErrorHandler())
+			router.Use(func(c *gin.Context) {
+				c.Set("user_id", uint(1))
+				c.Next()
+			})
+			router.POST("/wallets/me/transfer", handler.TransferFunds)
+
+			req, _ := http.NewRequest("POST", "/wallets/me/transfer", strings.NewReader(tt.body))
+			req.Header.Set("Content-Type", "application/json")
+			req.Header.Set("Accept-Language", tt.acceptLanguage)
+			resp := httptest.NewRecorder()
+
+			router.ServeHTTP(resp, req)
+
+			assert.Equal(t, tt.expectedStatus, resp.Code)
+			assert.Equal(t, tt.expectedLocale, resp.Header().Get("Content-Language"))
+
+			var response dto.ErrorResponse
+			assert.NoError(t, json.Unmarshal(resp.Body.Bytes(), &response))
+			assert.Equal(t, tt.expectedMessage, response.Message)
+
 			mockUC.AssertExpectations(t)
 		})
 	}
 }
+
+func TestWalletHandler_TransferFundsRequiresPIN(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	mockUC := new(MockWalletUseCase)
+	mockUC.On("GetWalletByUserID", uint(1)).Return(&models.Wallet{ID: 1, UserID: 1}, nil)
+
+	handler := NewWalletHandler(mockUC, &stubPINUseCase{err: apperrors.ErrPINRequired}, &stubTransferChallengeUseCase{})
+
+	router := gin.New()
+	router.Use(middleware.ErrorHandler())
+	router.Use(func(c *gin.Context) {
+		c.Set("user_id", uint(1))
+		c.Next()
+	})
+	router.POST("/wallets/me/transfer", handler.TransferFunds)
+
+	body := strings.NewReader(`{"to_wallet_id": 2, "amount": "75.00", "reference": "TRF001"}`)
+	req, _ := http.NewRequest("POST", "/wallets/me/transfer", body)
+	req.Header.Set("Content-Type", "application/json")
+	resp := httptest.NewRecorder()
+
+	router.ServeHTTP(resp, req)
+
+	assert.Equal(t, http.StatusForbidden, resp.Code)
+
+	var response dto.ErrorResponse
+	assert.NoError(t, json.Unmarshal(resp.Body.Bytes(), &response))
+	assert.Equal(t, apperrors.CodePINRequired, response.Code)
+
+	// The transfer is never attempted
+	mockUC.AssertNotCalled(t, "TransferFunds", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestWalletHandler_TransferFundsHeldForChallenge(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	mockUC := new(MockWalletUseCase)
+	mockUC.On("GetWalletByUserID", uint(1)).Return(&models.Wallet{ID: 1, UserID: 1}, nil)
+
+	expiresAt := time.Date(2025, 3, 1, 12, 5, 0, 0, time.UTC)
+	challenges := &stubTransferChallengeUseCase{challenge: &models.TransferChallenge{ID: 12, ExpiresAt: expiresAt}}
+	handler := NewWalletHandler(mockUC, &stubPINUseCase{}, challenges)
+
+	router := gin.New()
+	router.Use(middleware.ErrorHandler())
+	router.Use(func(c *gin.Context) {
+		c.Set("user_id", uint(1))
+		c.Next()
+	})
+	router.POST("/wallets/me/transfer", handler.TransferFunds)
+
+	body := strings.NewReader(`{"to_wallet_id": 2, "amount": "75000.00", "reference": "TRF001"}`)
+	req, _ := http.NewRequest("POST", "/wallets/me/transfer", body)
+	req.Header.Set("Content-Type", "application/json")
+	resp := httptest.NewRecorder()
+
+	router.ServeHTTP(resp, req)
+
+	assert.Equal(t, http.StatusAccepted, resp.Code)
+
+	var response struct {
+		Data dto.TransferChallengeResponse `json:"data"`
+	}
+	assert.NoError(t, json.Unmarshal(resp.Body.Bytes(), &response))
+	assert.Equal(t, uint(12), response.Data.ChallengeID)
+	assert.True(t, expiresAt.Equal(response.Data.ExpiresAt))
+
+	// The transfer waits for the confirmation
+	mockUC.AssertNotCalled(t, "TransferFunds", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestWalletHandler_GetTransactionByReference(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	mockUC := new(MockWalletUseCase)
+	mockUC.On("GetWalletByUserID", uint(1)).Return(&models.Wallet{ID: 1, UserID: 1}, nil)
+	leg := &models.Transaction{ID: 8, Reference: "TRF1-IN", WalletID: 1, TransactionType: models.TransactionTypeCredit,
+		Amount: decimal.NewFromInt(75), BalanceAfter: decimal.NewFromInt(175), Status: models.TransactionStatusCompleted}
+	counter := &models.Transaction{ID: 7, Reference: "TRF1-OUT", WalletID: 2, TransactionType: models.TransactionTypeDebit,
+		Amount: decimal.NewFromInt(75), BalanceAfter: decimal.NewFromInt(925), Status: models.TransactionStatusCompleted}
+	mockUC.On("GetWalletTransactionByReference", uint(1), "TRF1").Return(leg, counter, nil)
+	mockUC.On("GetWalletTransactionByReference", uint(1), "MISSING").Return(nil, nil, apperrors.ErrTransactionNotFound)
+
+	handler := NewWalletHandler(mockUC, &stubPINUseCase{}, &stubTransferChallengeUseCase{})
+	router := gin.New()
+	router.Use(middleware.ErrorHandler())
+	router.Use(func(c *gin.Context) {
+		c.Set("user_id", uint(1))
+		c.Next()
+	})
+	router.GET("/wallets/me/transactions/by-reference/:reference", handler.GetTransactionByReference)
+
+	resp := httptest.NewRecorder()
+	req, _ := http.NewRequest("GET", "/wallets/me/transactions/by-reference/TRF1", nil)
+	router.ServeHTTP(resp, req)
+
+	assert.Equal(t, http.StatusOK, resp.Code)
+	var response struct {
+		Data map[string]map[string]interface{} `json:"data"`
+	}
+	assert.NoError(t, json.Unmarshal(resp.Body.Bytes(), &response))
+	assert.Equal(t, "TRF1-IN", response.Data["transaction"]["reference"])
+	assert.Equal(t, "175", response.Data["transaction"]["balance_after"])
+	assert.Equal(t, "TRF1-OUT", response.Data["counter_leg"]["reference"])
+	assert.NotContains(t, response.Data["counter_leg"], "balance_after", "the counterparty's balance must not be shown")
+
+	resp = httptest.NewRecorder()
+	req, _ = http.NewRequest("GET", "/wallets/me/transactions/by-reference/MISSING", nil)
+	router.ServeHTTP(resp, req)
+
+	assert.Equal(t, http.StatusNotFound, resp.Code)
+	assert.Contains(t, resp.Body.String(), apperrors.CodeTransactionNotFound)
+}
+
+func TestWalletHandler_GetTransaction(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	mockUC := new(MockWalletUseCase)
+	mockUC.On("GetWalletByUserID", uint(1)).Return(&models.Wallet{ID: 1, UserID: 1}, nil)
+	createdAt := time.Date(2024, 3, 1, 9, 0, 0, 0, time.UTC)
+	transaction := &models.Transaction{ID: 7, CreatedAt: createdAt, Reference: "TRF1-OUT", WalletID: 1,
+		TransactionType: models.TransactionTypeDebit, Amount: decimal.NewFromInt(75), Metadata: `{"channel":"app"}`,
+		Status: models.TransactionStatusCompleted}
+	related := &models.Transaction{ID: 8, Reference: "TRF1-IN", WalletID: 2, TransactionType: models.TransactionTypeCredit,
+		Amount: decimal.NewFromInt(75), BalanceAfter: decimal.NewFromInt(175), Status: models.TransactionStatusCompleted}
+	history := []models.TransactionStatusChange{{TransactionID: 7, FromStatus: models.TransactionStatusPendingReview,
+		ToStatus: models.TransactionStatusCompleted, Reason: "approved after review", CreatedAt: createdAt.Add(time.Hour)}}
+	mockUC.On("GetWalletTransaction", uint(1), uint(7)).Return(transaction, related, history, nil)
+	mockUC.On("GetWalletTransaction", uint(1), uint(9)).Return(nil, nil, nil, apperrors.ErrTransactionNotFound)
+
+	handler := NewWalletHandler(mockUC, &stubPINUseCase{}, &stubTransferChallengeUseCase{})
+	router := gin.New()
+	router.Use(middleware.ErrorHandler())
+	router.Use(func(c *gin.Context) {
+		c.Set("user_id", uint(1))
+		c.Next()
+	})
+	router.GET("/wallets/me/transactions/:id", handler.GetTransaction)
+
+	resp := httptest.NewRecorder()
+	req, _ := http.NewRequest("GET", "/wallets/me/transactions/7", nil)
+	router.ServeHTTP(resp, req)
+
+	assert.Equal(t, http.StatusOK, resp.Code)
+	var response struct {
+		Data dto.TransactionDetailResponse `json:"data"`
+	}
+	assert.NoError(t, json.Unmarshal(resp.Body.Bytes(), &response))
+	assert.Equal(t, `{"channel":"app"}`, response.Data.Metadata)
+	if assert.NotNil(t, response.Data.RelatedTransaction) {
+		assert.Equal(t, "TRF1-IN", response.Data.RelatedTransaction.Reference)
+	}
+	assert.NotContains(t, resp.Body.String(), `"balance_after":"175"`, "the counterparty's balance must not be shown")
+	if assert.Len(t, response.Data.StatusHistory, 2) {
+		assert.Equal(t, "PENDING_REVIEW", response.Data.StatusHistory[0].Status)
+		assert.True(t, createdAt.Equal(response.Data.StatusHistory[0].ChangedAt))
+		assert.Equal(t, "COMPLETED", response.Data.StatusHistory[1].Status)
+		assert.Equal(t, "approved after review", response.Data.StatusHistory[1].Reason)
+	}
+
+	// Transactions of other wallets are not found
+	resp = httptest.NewRecorder()
+	req, _ = http.NewRequest("GET", "/wallets/me/transactions/9", nil)
+	router.ServeHTTP(resp, req)
+	assert.Equal(t, http.StatusNotFound, resp.Code)
+
+	resp = httptest.NewRecorder()
+	req, _ = http.NewRequest("GET", "/wallets/me/transactions/abc", nil)
+	router.ServeHTTP(resp, req)
+	assert.Equal(t, http.StatusBadRequest, resp.Code)
+}
+
+func TestWalletHandler_QuoteTransfer(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	mockUC := new(MockWalletUseCase)
+	mockUC.On("GetWalletByUserID", uint(1)).Return(&models.Wallet{ID: 1, UserID: 1, Balance: decimal.NewFromInt(500), Currency: "USD", Nickname: "Travel"}, nil)
+	expiresAt := time.Now().Add(time.Minute).UTC().Truncate(time.Second)
+	quote := &models.TransferQuote{ID: 31, FromWalletID: 1, ToWalletID: 2, Amount: decimal.NewFromInt(100), Fee: decimal.NewFromInt(2),
+		SourceCurrency: "USD", TargetCurrency: "NGN", ExchangeRate: decimal.NewFromInt(1550), CreditAmount: decimal.NewFromInt(155000), ExpiresAt: expiresAt}
+	mockUC.On("QuoteTransfer", uint(1), uint(1), uint(2), decimal.NewFromInt(100)).Return(quote, nil)
+	outTx := &models.Transaction{ID: 7, Reference: "TRF1-OUT", WalletID: 1, Amount: decimal.NewFromInt(100), Status: models.TransactionStatusCompleted}
+	inTx := &models.Transaction{ID: 8, Reference: "TRF1-IN", WalletID: 2, Amount: decimal.NewFromInt(155000), Status: models.TransactionStatusCompleted}
+	mockUC.On("TransferFundsWithQuote", uint(31), uint(1), uint(2), decimal.NewFromInt(100), "TRF1", "", mock.Anything).Return(outTx, inTx, nil)
+
+	handler := NewWalletHandler(mockUC, &stubPINUseCase{}, &stubTransferChallengeUseCase{})
+	router := gin.New()
+	router.Use(middleware.ErrorHandler())
+	router.Use(func(c *gin.Context) {
+		c.Set("user_id", uint(1))
+		c.Next()
+	})
+	router.POST("/wallets/me/transfer/quote", handler.QuoteTransfer)
+	router.POST("/wallets/me/transfer", handler.TransferFunds)
+
+	resp := httptest.NewRecorder()
+	req, _ := http.NewRequest("POST", "/wallets/me/transfer/quote", strings.NewReader(`{"to_wallet_id": 2, "amount": "100"}`))
+	req.Header.Set("Content-Type", "application/json")
+	router.ServeHTTP(resp, req)
+
+	assert.Equal(t, http.StatusOK, resp.Code)
+	var response struct {
+		Data dto.TransferQuoteResponse `json:"data"`
+	}
+	assert.NoError(t, json.Unmarshal(resp.Body.Bytes(), &response))
+	assert.Equal(t, uint(31), response.Data.QuoteID)
+	assert.True(t, decimal.NewFromInt(102).Equal(response.Data.TotalDebit))
+	assert.True(t, decimal.NewFromInt(155000).Equal(response.Data.CreditAmount))
+	assert.True(t, decimal.NewFromInt(398).Equal(response.Data.BalanceAfter))
+	assert.True(t, expiresAt.Equal(response.Data.ExpiresAt))
+	assert.Equal(t, "Travel", response.Data.WalletNickname)
+
+	// Sending the quote makes the transfer with its terms
+	resp = httptest.NewRecorder()
+	req, _ = http.NewRequest("POST", "/wallets/me/transfer", strings.NewReader(`{"to_wallet_id": 2, "amount": "100", "reference": "TRF1", "quote_id": 31}`))
+	req.Header.Set("Content-Type", "application/json")
+	router.ServeHTTP(resp, req)
+
+	assert.Equal(t, http.StatusOK, resp.Code)
+	var transfer struct {
+		Data []dto.TransactionResponse `json:"data"`
+	}
+	assert.NoError(t, json.Unmarshal(resp.Body.Bytes(), &transfer))
+	assert.Equal(t, "Travel", transfer.Data[0].WalletNickname)
+	assert.Empty(t, transfer.Data[1].WalletNickname)
+	mockUC.AssertNotCalled(t, "TransferFunds", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestWalletHandler_GetWalletLimits(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	maxTransaction := decimal.NewFromInt(20000)
+	maxDebit := decimal.NewFromInt(8000)
+	maxCredit := decimal.NewFromInt(150000)
+	resetsAt := time.Date(2024, 3, 2, 0, 0, 0, 0, time.UTC)
+	mockUC := new(MockWalletUseCase)
+	mockUC.On("GetWalletByUserID", uint(1)).Return(&models.Wallet{ID: 1, UserID: 1, Currency: "NGN"}, nil)
+	mockUC.On("GetWalletLimits", uint(1)).Return(&usecases.WalletLimits{
+		Tier: &models.WalletTier{Name: "basic", MaxTransactionAmount: &maxTransaction},
+		Daily: &usecases.DebitAllowance{Limit: decimal.NewFromInt(50000), Used: decimal.NewFromInt(42000),
+			Remaining: maxDebit, ResetsAt: resetsAt},
+		MaxDebit:  &maxDebit,
+		MaxCredit: &maxCredit,
+	}, nil)
+
+	handler := NewWalletHandler(mockUC, &stubPINUseCase{}, &stubTransferChallengeUseCase{})
+	router := gin.New()
+	router.Use(middleware.ErrorHandler())
+	router.Use(func(c *gin.Context) {
+		c.Set("user_id", uint(1))
+		c.Next()
+	})
+	router.GET("/wallets/me/limits", handler.GetWalletLimits)
+
+	resp := httptest.NewRecorder()
+	req, _ := http.NewRequest("GET", "/wallets/me/limits", nil)
+	router.ServeHTTP(resp, req)
+
+	assert.Equal(t, http.StatusOK, resp.Code)
+	var response struct {
+		Data dto.WalletLimitsResponse `json:"data"`
+	}
+	assert.NoError(t, json.Unmarshal(resp.Body.Bytes(), &response))
+	assert.Equal(t, "basic", response.Data.Tier)
+	assert.Equal(t, "NGN", response.Data.Currency)
+	if assert.NotNil(t, response.Data.Daily) {
+		assert.Equal(t, "8000", response.Data.Daily.Remaining.String())
+		assert.True(t, resetsAt.Equal(response.Data.Daily.ResetsAt))
+	}
+	assert.Nil(t, response.Data.Monthly, "no monthly limit is configured")
+	if assert.NotNil(t, response.Data.Remaining.Transfer) && assert.NotNil(t, response.Data.Remaining.Funding) {
+		assert.Equal(t, "8000", response.Data.Remaining.Transfer.String())
+		assert.Equal(t, "150000", response.Data.Remaining.Funding.String())
+	}
+	assert.NotContains(t, resp.Body.String(), `"max_balance"`, "an unlimited balance is omitted")
+}
@@ -0,0 +1,194 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/limistah/wallet-service/internal/apperrors"
+	"github.com/limistah/wallet-service/internal/dto"
+	"github.com/limistah/wallet-service/internal/middleware"
+	"github.com/limistah/wallet-service/internal/models"
+	"github.com/limistah/wallet-service/internal/usecases"
+)
+
+type WalletTierHandler struct {
+	walletTierUseCase usecases.WalletTierUseCase
+}
+
+func NewWalletTierHandler(walletTierUseCase usecases.WalletTierUseCase) *WalletTierHandler {
+	return &WalletTierHandler{
+		walletTierUseCase: walletTierUseCase,
+	}
+}
+
+func walletTierFromRequest(req *dto.WalletTierRequest) *models.WalletTier {
+	return &models.WalletTier{
+		Name:                  req.Name,
+		Description:           req.Description,
+		IsDefault:             req.IsDefault,
+		TransferFeeFlat:       req.TransferFeeFlat,
+		TransferFeePercent:    req.TransferFeePercent,
+		WithdrawalFeeFlat:     req.WithdrawalFeeFlat,
+		WithdrawalFeePercent:  req.WithdrawalFeePercent,
+		MaxTransactionAmount:  req.MaxTransactionAmount,
+		DailyDebitLimit:       req.DailyDebitLimit,
+		MonthlyDebitLimit:     req.MonthlyDebitLimit,
+		MaxBalance:            req.MaxBalance,
+		PaymentLinksEnabled:   req.PaymentLinksEnabled,
+		QRPaymentsEnabled:     req.QRPaymentsEnabled,
+		StandingOrdersEnabled: req.StandingOrdersEnabled,
+	}
+}
+
+// ListTiers godoc
+//
+//	@Summary		List wallet tiers
+//	@Description	List the wallet tiers with their fees, limits and features (admin and support)
+//	@Tags			admin
+//	@Accept			json
+//	@Produce		json
+//	@Security		BearerAuth
+//	@Success		200	{object}	dto.APIResponse{data=[]dto.WalletTierResponse}
+//	@Failure		401	{object}	dto.ErrorResponse
+//	@Failure		403	{object}	dto.ErrorResponse
+//	@Failure		500	{object}	dto.ErrorResponse
+//	@Router			/admin/tiers [get]
+func (h *WalletTierHandler) ListTiers(c *gin.Context) {
+	tiers, err := h.walletTierUseCase.ListTiers(c.Request.Context())
+	if err != nil {
+		c.Error(err).SetMeta("wallet_tier.list_failed")
+		return
+	}
+
+	responses := make([]dto.WalletTierResponse, len(tiers))
+	for i, tier := range tiers {
+		responses[i] = dto.ToWalletTierResponse(&tier)
+	}
+
+	c.JSON(http.StatusOK, dto.APIResponse{
+		Success: true,
+		Message: middleware.Translate(c, "wallet_tier.list_retrieved"),
+		Data:    responses,
+	})
+}
+
+// CreateTier godoc
+//
+//	@Summary		Create a wallet tier
+//	@Description	Create a wallet plan with its fees, limits and features (admin only)
+//	@Tags			admin
+//	@Accept			json
+//	@Produce		json
+//	@Security		BearerAuth
+//	@Param			request	body		dto.WalletTierRequest	true	"Wallet tier"
+//	@Success		201		{object}	dto.APIResponse{data=dto.WalletTierResponse}
+//	@Failure		400		{object}	dto.ErrorResponse
+//	@Failure		401		{object}	dto.ErrorResponse
+//	@Failure		403		{object}	dto.ErrorResponse
+//	@Failure		500		{object}	dto.ErrorResponse
+//	@Router			/admin/tiers [post]
+func (h *WalletTierHandler) CreateTier(c *gin.Context) {
+	var req dto.WalletTierRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.Error(apperrors.ErrValidation.Withf("invalid request data: %w", err)).SetMeta("request.invalid")
+		return
+	}
+
+	tier, err := h.walletTierUseCase.CreateTier(c.Request.Context(), walletTierFromRequest(&req))
+	if err != nil {
+		c.Error(err).SetMeta("wallet_tier.create_failed")
+		return
+	}
+
+	c.JSON(http.StatusCreated, dto.APIResponse{
+		Success: true,
+		Message: middleware.Translate(c, "wallet_tier.created"),
+		Data:    dto.ToWalletTierResponse(tier),
+	})
+}
+
+// UpdateTier godoc
+//
+//	@Summary		Update a wallet tier
+//	@Description	Replace the fees, limits and features of a wallet tier; changes apply to every wallet on the tier (admin only)
+//	@Tags			admin
+//	@Accept			json
+//	@Produce		json
+//	@Security		BearerAuth
+//	@Param			id		path		int						true	"Wallet tier ID"
+//	@Param			request	body		dto.WalletTierRequest	true	"Wallet tier"
+//	@Success		200		{object}	dto.APIResponse{data=dto.WalletTierResponse}
+//	@Failure		400		{object}	dto.ErrorResponse
+//	@Failure		401		{object}	dto.ErrorResponse
+//	@Failure		403		{object}	dto.ErrorResponse
+//	@Failure		404		{object}	dto.ErrorResponse
+//	@Failure		500		{object}	dto.ErrorResponse
+//	@Router			/admin/tiers/{id} [put]
+func (h *WalletTierHandler) UpdateTier(c *gin.Context) {
+	tierID, err := parseIDParam(c, "id")
+	if err != nil {
+		c.Error(err).SetMeta("wallet_tier.invalid_id")
+		return
+	}
+
+	var req dto.WalletTierRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.Error(apperrors.ErrValidation.Withf("invalid request data: %w", err)).SetMeta("request.invalid")
+		return
+	}
+
+	tier, err := h.walletTierUseCase.UpdateTier(c.Request.Context(), tierID, walletTierFromRequest(&req))
+	if err != nil {
+		c.Error(err).SetMeta("wallet_tier.update_failed")
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.APIResponse{
+		Success: true,
+		Message: middleware.Translate(c, "wallet_tier.updated"),
+		Data:    dto.ToWalletTierResponse(tier),
+	})
+}
+
+// AssignTier godoc
+//
+//	@Summary		Assign a wallet tier
+//	@Description	Move a wallet to a tier, or back to the default tier when tier_id is omitted (admin only)
+//	@Tags			admin
+//	@Accept			json
+//	@Produce		json
+//	@Security		BearerAuth
+//	@Param			id		path		int							true	"Wallet ID"
+//	@Param			request	body		dto.AssignWalletTierRequest	true	"Tier assignment"
+//	@Success		200		{object}	dto.APIResponse{data=dto.AdminWalletResponse}
+//	@Failure		400		{object}	dto.ErrorResponse
+//	@Failure		401		{object}	dto.ErrorResponse
+//	@Failure		403		{object}	dto.ErrorResponse
+//	@Failure		404		{object}	dto.ErrorResponse
+//	@Failure		500		{object}	dto.ErrorResponse
+//	@Router			/admin/wallets/{id}/tier [put]
+func (h *WalletTierHandler) AssignTier(c *gin.Context) {
+	walletID, err := parseIDParam(c, "id")
+	if err != nil {
+		c.Error(err).SetMeta("wallet_tier.invalid_wallet_id")
+		return
+	}
+
+	var req dto.AssignWalletTierRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.Error(apperrors.ErrValidation.Withf("invalid request data: %w", err)).SetMeta("request.invalid")
+		return
+	}
+
+	wallet, err := h.walletTierUseCase.AssignTier(c.Request.Context(), walletID, req.TierID)
+	if err != nil {
+		c.Error(err).SetMeta("wallet_tier.assign_failed")
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.APIResponse{
+		Success: true,
+		Message: middleware.Translate(c, "wallet_tier.assigned"),
+		Data:    dto.ToAdminWalletResponse(wallet),
+	})
+}
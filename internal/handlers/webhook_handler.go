@@ -0,0 +1,172 @@
+package handlers
+
+import (
+	"errors"
+	"io"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/limistah/wallet-service/internal/apperrors"
+	"github.com/limistah/wallet-service/internal/dto"
+	"github.com/limistah/wallet-service/internal/middleware"
+	"github.com/limistah/wallet-service/internal/payments"
+	"github.com/limistah/wallet-service/internal/usecases"
+)
+
+// maxWebhookPayloadSize bounds the body read from provider webhooks
+const maxWebhookPayloadSize = 1 << 20
+
+type WebhookHandler struct {
+	depositUseCase  usecases.DepositUseCase
+	walletUseCase   usecases.WalletUseCase
+	suspenseUseCase usecases.SuspenseUseCase
+	verifiers       *payments.Registry
+}
+
+func NewWebhookHandler(depositUseCase usecases.DepositUseCase, walletUseCase usecases.WalletUseCase, suspenseUseCase usecases.SuspenseUseCase, verifiers *payments.Registry) *WebhookHandler {
+	return &WebhookHandler{
+		depositUseCase:  depositUseCase,
+		walletUseCase:   walletUseCase,
+		suspenseUseCase: suspenseUseCase,
+		verifiers:       verifiers,
+	}
+}
+
+// ProviderWebhook godoc
+//
+//	@Summary		Receive a payment provider webhook
+//	@Description	Verify a signed deposit notification from a payment provider and credit the matching pending deposit. Settled credits that match no deposit, or whose wallet is no longer active, are parked in the suspense account. Repeated deliveries are acknowledged without crediting twice.
+//	@Tags			webhooks
+//	@Accept			json
+//	@Produce		json
+//	@Param			provider	path		string	true	"Payment provider name"
+//	@Success		200			{object}	dto.APIResponse{data=dto.DepositResponse}
+//	@Failure		400			{object}	dto.ErrorResponse
+//	@Failure		401			{object}	dto.ErrorResponse
+//	@Failure		404			{object}	dto.ErrorResponse
+//	@Failure		500			{object}	dto.ErrorResponse
+//	@Router			/webhooks/providers/{provider} [post]
+func (h *WebhookHandler) ProviderWebhook(c *gin.Context) {
+	provider := c.Param("provider")
+	verifier, ok := h.verifiers.Get(provider)
+	if !ok {
+		c.Error(apperrors.ErrPaymentProviderNotFound.Withf("no webhook verifier configured for %s", provider))
+		return
+	}
+
+	payload, err := io.ReadAll(io.LimitReader(c.Request.Body, maxWebhookPayloadSize))
+	if err != nil {
+		c.Error(apperrors.ErrValidation.Withf("invalid request data: %w", err)).SetMeta("request.invalid")
+		return
+	}
+
+	event, err := verifier.ParseWebhook(payload, c.Request.Header)
+	if err != nil {
+		if errors.Is(err, payments.ErrInvalidSignature) {
+			err = apperrors.ErrInvalidWebhookSignature.Wrap(err)
+		} else {
+			err = apperrors.ErrValidation.Withf("invalid webhook payload: %w", err)
+		}
+		c.Error(err).SetMeta("webhook.invalid_payload")
+		return
+	}
+
+	if event == nil {
+		c.JSON(http.StatusOK, dto.APIResponse{
+			Success: true,
+			Message: middleware.Translate(c, "provider_webhook.ignored"),
+		})
+		return
+	}
+
+	deposit, err := h.depositUseCase.ConfirmDeposit(c.Request.Context(), verifier.Name(), *event)
+	if errors.Is(err, apperrors.ErrDepositNotFound) && event.Status == payments.DepositEventSucceeded {
+		h.parkUnmatchedCredit(c, verifier.Name(), *event)
+		return
+	}
+	if err != nil {
+		c.Error(err).SetMeta("provider_webhook.deposit_process_failed")
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.APIResponse{
+		Success: true,
+		Message: middleware.Translate(c, "provider_webhook.processed"),
+		Data:    dto.ToDepositResponse(deposit),
+	})
+}
+
+// parkUnmatchedCredit acknowledges a settled credit that matches no deposit once it is parked in suspense
+func (h *WebhookHandler) parkUnmatchedCredit(c *gin.Context, provider string, event payments.DepositEvent) {
+	item, err := h.suspenseUseCase.ParkUnmatchedCredit(c.Request.Context(), provider, event)
+	if err != nil {
+		c.Error(err).SetMeta("provider_webhook.unmatched_credit_park_failed")
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.APIResponse{
+		Success: true,
+		Message: middleware.Translate(c, "provider_webhook.unmatched_credit_parked"),
+		Data:    dto.ToSuspenseItemResponse(item),
+	})
+}
+
+// PayoutWebhook godoc
+//
+//	@Summary		Receive a payout status webhook
+//	@Description	Verify a signed payout notification from a payment provider and settle the pending withdrawal. Failed payouts are refunded to the wallet; repeated deliveries are acknowledged without settling twice.
+//	@Tags			webhooks
+//	@Accept			json
+//	@Produce		json
+//	@Param			provider	path		string	true	"Payout provider name"
+//	@Success		200			{object}	dto.APIResponse{data=dto.PayoutResponse}
+//	@Failure		400			{object}	dto.ErrorResponse
+//	@Failure		401			{object}	dto.ErrorResponse
+//	@Failure		404			{object}	dto.ErrorResponse
+//	@Failure		500			{object}	dto.ErrorResponse
+//	@Router			/webhooks/payouts/{provider} [post]
+func (h *WebhookHandler) PayoutWebhook(c *gin.Context) {
+	provider := c.Param("provider")
+	payoutProvider, ok := h.verifiers.GetPayoutProvider(provider)
+	if !ok {
+		c.Error(apperrors.ErrPaymentProviderNotFound.Withf("no payout provider configured for %s", provider))
+		return
+	}
+
+	payload, err := io.ReadAll(io.LimitReader(c.Request.Body, maxWebhookPayloadSize))
+	if err != nil {
+		c.Error(apperrors.ErrValidation.Withf("invalid request data: %w", err)).SetMeta("request.invalid")
+		return
+	}
+
+	event, err := payoutProvider.ParsePayoutWebhook(payload, c.Request.Header)
+	if err != nil {
+		if errors.Is(err, payments.ErrInvalidSignature) {
+			err = apperrors.ErrInvalidWebhookSignature.Wrap(err)
+		} else {
+			err = apperrors.ErrValidation.Withf("invalid webhook payload: %w", err)
+		}
+		c.Error(err).SetMeta("webhook.invalid_payload")
+		return
+	}
+
+	if event == nil {
+		c.JSON(http.StatusOK, dto.APIResponse{
+			Success: true,
+			Message: middleware.Translate(c, "provider_webhook.ignored"),
+		})
+		return
+	}
+
+	payout, err := h.walletUseCase.SettlePayout(c.Request.Context(), payoutProvider.Name(), *event)
+	if err != nil {
+		c.Error(err).SetMeta("provider_webhook.payout_process_failed")
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.APIResponse{
+		Success: true,
+		Message: middleware.Translate(c, "provider_webhook.processed"),
+		Data:    dto.ToPayoutResponse(payout),
+	})
+}
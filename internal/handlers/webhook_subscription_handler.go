@@ -0,0 +1,458 @@
+package handlers
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/limistah/wallet-service/internal/apperrors"
+	"github.com/limistah/wallet-service/internal/dto"
+	"github.com/limistah/wallet-service/internal/middleware"
+	"github.com/limistah/wallet-service/internal/models"
+	"github.com/limistah/wallet-service/internal/repositories"
+	"github.com/limistah/wallet-service/internal/usecases"
+)
+
+type WebhookSubscriptionHandler struct {
+	webhookUseCase usecases.WebhookUseCase
+}
+
+func NewWebhookSubscriptionHandler(webhookUseCase usecases.WebhookUseCase) *WebhookSubscriptionHandler {
+	return &WebhookSubscriptionHandler{
+		webhookUseCase: webhookUseCase,
+	}
+}
+
+// CreateSubscription godoc
+//
+//	@Summary		Register a webhook endpoint
+//	@Description	Receive the events of the authenticated user's wallets as POST requests to the URL, signed with the returned secret in the X-Wallet-Signature header (hex HMAC-SHA256 of the body). The secret is only shown once. Failed deliveries are retried with exponential backoff
+//	@Tags			webhooks
+//	@Accept			json
+//	@Produce		json
+//	@Security		BearerAuth
+//	@Param			request	body		dto.CreateWebhookSubscriptionRequest	true	"Endpoint"
+//	@Success		201		{object}	dto.APIResponse{data=dto.WebhookSubscriptionResponse}
+//	@Failure		400		{object}	dto.ErrorResponse
+//	@Failure		401		{object}	dto.ErrorResponse
+//	@Failure		500		{object}	dto.ErrorResponse
+//	@Router			/webhook-subscriptions [post]
+func (h *WebhookSubscriptionHandler) CreateSubscription(c *gin.Context) {
+	userID, exists := middleware.GetUserID(c)
+	if !exists {
+		c.Error(apperrors.ErrUnauthenticated)
+		return
+	}
+
+	var req dto.CreateWebhookSubscriptionRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.Error(apperrors.ErrValidation.Withf("invalid request data: %w", err)).SetMeta("request.invalid")
+		return
+	}
+
+	subscription, err := h.webhookUseCase.CreateSubscription(c.Request.Context(), userID, req.URL, req.EventTypes)
+	if err != nil {
+		c.Error(err).SetMeta("webhook.subscription_create_failed")
+		return
+	}
+
+	response := dto.ToWebhookSubscriptionResponse(subscription)
+	response.Secret = subscription.Secret
+	c.JSON(http.StatusCreated, dto.APIResponse{
+		Success: true,
+		Message: middleware.Translate(c, "webhook.subscription_created"),
+		Data:    response,
+	})
+}
+
+// ListSubscriptions godoc
+//
+//	@Summary		List webhook endpoints
+//	@Description	List the authenticated user's webhook endpoints, disabled ones included
+//	@Tags			webhooks
+//	@Accept			json
+//	@Produce		json
+//	@Security		BearerAuth
+//	@Success		200	{object}	dto.APIResponse{data=[]dto.WebhookSubscriptionResponse}
+//	@Failure		401	{object}	dto.ErrorResponse
+//	@Failure		500	{object}	dto.ErrorResponse
+//	@Router			/webhook-subscriptions [get]
+func (h *WebhookSubscriptionHandler) ListSubscriptions(c *gin.Context) {
+	userID, exists := middleware.GetUserID(c)
+	if !exists {
+		c.Error(apperrors.ErrUnauthenticated)
+		return
+	}
+
+	subscriptions, err := h.webhookUseCase.ListSubscriptions(c.Request.Context(), userID)
+	if err != nil {
+		c.Error(err).SetMeta("webhook.subscriptions_retrieve_failed")
+		return
+	}
+
+	responses := make([]dto.WebhookSubscriptionResponse, len(subscriptions))
+	for i := range subscriptions {
+		responses[i] = dto.ToWebhookSubscriptionResponse(&subscriptions[i])
+	}
+
+	c.JSON(http.StatusOK, dto.APIResponse{
+		Success: true,
+		Message: middleware.Translate(c, "webhook.subscriptions_retrieved"),
+		Data:    responses,
+	})
+}
+
+// DisableSubscription godoc
+//
+//	@Summary		Disable a webhook endpoint
+//	@Description	Stop delivering events to one of the authenticated user's webhook endpoints; deliveries still pending are dropped to the dead letter queue
+//	@Tags			webhooks
+//	@Accept			json
+//	@Produce		json
+//	@Security		BearerAuth
+//	@Param			id	path		int	true	"Webhook subscription ID"
+//	@Success		200	{object}	dto.APIResponse{data=dto.WebhookSubscriptionResponse}
+//	@Failure		400	{object}	dto.ErrorResponse
+//	@Failure		401	{object}	dto.ErrorResponse
+//	@Failure		404	{object}	dto.ErrorResponse
+//	@Failure		500	{object}	dto.ErrorResponse
+//	@Router			/webhook-subscriptions/{id} [delete]
+func (h *WebhookSubscriptionHandler) DisableSubscription(c *gin.Context) {
+	userID, exists := middleware.GetUserID(c)
+	if !exists {
+		c.Error(apperrors.ErrUnauthenticated)
+		return
+	}
+
+	subscriptionID, err := parseIDParam(c, "id")
+	if err != nil {
+		c.Error(err).SetMeta("webhook.invalid_subscription_id")
+		return
+	}
+
+	subscription, err := h.webhookUseCase.DisableSubscription(c.Request.Context(), userID, subscriptionID)
+	if err != nil {
+		c.Error(err).SetMeta("webhook.subscription_disable_failed")
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.APIResponse{
+		Success: true,
+		Message: middleware.Translate(c, "webhook.subscription_disabled"),
+		Data:    dto.ToWebhookSubscriptionResponse(subscription),
+	})
+}
+
+// ListDeliveries godoc
+//
+//	@Summary		List webhook deliveries
+//	@Description	List the events sent to one of the authenticated user's webhook endpoints with the outcome of their last attempt, newest first
+//	@Tags			webhooks
+//	@Accept			json
+//	@Produce		json
+//	@Security		BearerAuth
+//	@Param			id			path		int	true	"Webhook subscription ID"
+//	@Param			page		query		int	false	"Page number"	default(1)
+//	@Param			page_size	query		int	false	"Page size"		default(20)
+//	@Success		200			{object}	dto.APIResponse{data=[]dto.WebhookDeliveryResponse}
+//	@Failure		400			{object}	dto.ErrorResponse
+//	@Failure		401			{object}	dto.ErrorResponse
+//	@Failure		404			{object}	dto.ErrorResponse
+//	@Failure		500			{object}	dto.ErrorResponse
+//	@Router			/webhook-subscriptions/{id}/deliveries [get]
+func (h *WebhookSubscriptionHandler) ListDeliveries(c *gin.Context) {
+	userID, exists := middleware.GetUserID(c)
+	if !exists {
+		c.Error(apperrors.ErrUnauthenticated)
+		return
+	}
+
+	subscriptionID, err := parseIDParam(c, "id")
+	if err != nil {
+		c.Error(err).SetMeta("webhook.invalid_subscription_id")
+		return
+	}
+
+	page, pageSize := parsePagination(c)
+	deliveries, err := h.webhookUseCase.ListDeliveries(c.Request.Context(), userID, subscriptionID, page, pageSize)
+	h.respondDeliveries(c, deliveries, err)
+}
+
+// GetDelivery godoc
+//
+//	@Summary		Get a webhook delivery
+//	@Description	Get an event sent to one of the authenticated user's webhook endpoints with the history of every attempt to deliver it
+//	@Tags			webhooks
+//	@Accept			json
+//	@Produce		json
+//	@Security		BearerAuth
+//	@Param			id			path		int	true	"Webhook subscription ID"
+//	@Param			delivery_id	path		int	true	"Webhook delivery ID"
+//	@Success		200			{object}	dto.APIResponse{data=dto.WebhookDeliveryResponse}
+//	@Failure		400			{object}	dto.ErrorResponse
+//	@Failure		401			{object}	dto.ErrorResponse
+//	@Failure		404			{object}	dto.ErrorResponse
+//	@Failure		500			{object}	dto.ErrorResponse
+//	@Router			/webhook-subscriptions/{id}/deliveries/{delivery_id} [get]
+func (h *WebhookSubscriptionHandler) GetDelivery(c *gin.Context) {
+	userID, exists := middleware.GetUserID(c)
+	if !exists {
+		c.Error(apperrors.ErrUnauthenticated)
+		return
+	}
+
+	subscriptionID, err := parseIDParam(c, "id")
+	if err != nil {
+		c.Error(err).SetMeta("webhook.invalid_subscription_id")
+		return
+	}
+	deliveryID, err := parseIDParam(c, "delivery_id")
+	if err != nil {
+		c.Error(err).SetMeta("webhook.invalid_delivery_id")
+		return
+	}
+
+	delivery, attempts, err := h.webhookUseCase.GetSubscriptionDelivery(c.Request.Context(), userID, subscriptionID, deliveryID)
+	h.respondDelivery(c, delivery, attempts, err)
+}
+
+// ReplayEvents godoc
+//
+//	@Summary		Replay events to a webhook endpoint
+//	@Description	Deliver again to one of the authenticated user's webhook endpoints the stored events of a wallet, of a time range, or of a wallet in a time range, to recover after an outage. Up to 1000 events are replayed at once
+//	@Tags			webhooks
+//	@Accept			json
+//	@Produce		json
+//	@Security		BearerAuth
+//	@Param			id		path		int								true	"Webhook subscription ID"
+//	@Param			request	body		dto.ReplayWebhookEventsRequest	true	"Events to replay"
+//	@Success		202		{object}	dto.APIResponse{data=dto.ReplayWebhookEventsResponse}
+//	@Failure		400		{object}	dto.ErrorResponse
+//	@Failure		401		{object}	dto.ErrorResponse
+//	@Failure		404		{object}	dto.ErrorResponse
+//	@Failure		409		{object}	dto.ErrorResponse	"Subscription was disabled"
+//	@Failure		500		{object}	dto.ErrorResponse
+//	@Router			/webhook-subscriptions/{id}/replay [post]
+func (h *WebhookSubscriptionHandler) ReplayEvents(c *gin.Context) {
+	userID, exists := middleware.GetUserID(c)
+	if !exists {
+		c.Error(apperrors.ErrUnauthenticated)
+		return
+	}
+
+	h.replay(c, func(ctx context.Context, subscriptionID uint, replay usecases.EventReplay) (int, error) {
+		return h.webhookUseCase.ReplaySubscriptionEvents(ctx, userID, subscriptionID, replay)
+	})
+}
+
+// AdminReplayEvents godoc
+//
+//	@Summary		Replay events to any webhook endpoint
+//	@Description	Deliver again to a webhook endpoint the stored events of its owner for a wallet, a time range, or a wallet in a time range. Up to 1000 events are replayed at once (admin only)
+//	@Tags			admin
+//	@Accept			json
+//	@Produce		json
+//	@Security		BearerAuth
+//	@Param			id		path		int								true	"Webhook subscription ID"
+//	@Param			request	body		dto.ReplayWebhookEventsRequest	true	"Events to replay"
+//	@Success		202		{object}	dto.APIResponse{data=dto.ReplayWebhookEventsResponse}
+//	@Failure		400		{object}	dto.ErrorResponse
+//	@Failure		401		{object}	dto.ErrorResponse
+//	@Failure		403		{object}	dto.ErrorResponse
+//	@Failure		404		{object}	dto.ErrorResponse
+//	@Failure		409		{object}	dto.ErrorResponse	"Subscription was disabled"
+//	@Failure		500		{object}	dto.ErrorResponse
+//	@Router			/admin/webhooks/subscriptions/{id}/replay [post]
+func (h *WebhookSubscriptionHandler) AdminReplayEvents(c *gin.Context) {
+	h.replay(c, h.webhookUseCase.ReplayEvents)
+}
+
+// ListDeadDeliveries godoc
+//
+//	@Summary		List dead webhook deliveries
+//	@Description	List the webhook deliveries that failed every attempt and wait in the dead letter queue to be redelivered, with redacted payloads, most recent first (admin and support)
+//	@Tags			admin
+//	@Accept			json
+//	@Produce		json
+//	@Security		BearerAuth
+//	@Param			page		query		int	false	"Page number"	default(1)
+//	@Param			page_size	query		int	false	"Page size"		default(20)
+//	@Success		200			{object}	dto.APIResponse{data=[]dto.WebhookDeliveryResponse}
+//	@Failure		401			{object}	dto.ErrorResponse
+//	@Failure		403			{object}	dto.ErrorResponse
+//	@Failure		500			{object}	dto.ErrorResponse
+//	@Router			/admin/webhooks/dead-letters [get]
+func (h *WebhookSubscriptionHandler) ListDeadDeliveries(c *gin.Context) {
+	page, pageSize := parsePagination(c)
+	deliveries, err := h.webhookUseCase.ListDeadDeliveries(c.Request.Context(), page, pageSize)
+	h.respondDeliveries(c, deliveries, err)
+}
+
+// SearchDeliveries godoc
+//
+//	@Summary		Search webhook deliveries
+//	@Description	List the webhook deliveries of every endpoint with the response code and latency of their last attempt and a redacted snapshot of the payload, most recently updated first (admin and support)
+//	@Tags			admin
+//	@Accept			json
+//	@Produce		json
+//	@Security		BearerAuth
+//	@Param			status			query		string	false	"Delivery status (PENDING, DELIVERED, DEAD); omit for every delivery"
+//	@Param			event_type		query		string	false	"Event type, e.g. wallet.credit_received"
+//	@Param			subscription_id	query		int		false	"Webhook subscription ID"
+//	@Param			page			query		int		false	"Page number"	default(1)
+//	@Param			page_size		query		int		false	"Page size"		default(20)
+//	@Success		200				{object}	dto.APIResponse{data=[]dto.WebhookDeliveryResponse}
+//	@Failure		400				{object}	dto.ErrorResponse
+//	@Failure		401				{object}	dto.ErrorResponse
+//	@Failure		403				{object}	dto.ErrorResponse
+//	@Failure		500				{object}	dto.ErrorResponse
+//	@Router			/admin/webhooks/deliveries [get]
+func (h *WebhookSubscriptionHandler) SearchDeliveries(c *gin.Context) {
+	filter := repositories.WebhookDeliveryFilter{
+		Status:    models.WebhookDeliveryStatus(strings.ToUpper(c.Query("status"))),
+		EventType: c.Query("event_type"),
+	}
+	switch filter.Status {
+	case "", models.WebhookDeliveryPending, models.WebhookDeliveryDelivered, models.WebhookDeliveryDead:
+	default:
+		c.Error(apperrors.ErrValidation.Withf("invalid status")).SetMeta("webhook.invalid_status")
+		return
+	}
+
+	if raw := c.Query("subscription_id"); raw != "" {
+		subscriptionID, err := strconv.ParseUint(raw, 10, 64)
+		if err != nil || subscriptionID == 0 {
+			c.Error(apperrors.ErrValidation.Withf("invalid subscription ID")).SetMeta("webhook.invalid_subscription_param")
+			return
+		}
+		filter.SubscriptionID = uint(subscriptionID)
+	}
+
+	page, pageSize := parsePagination(c)
+	deliveries, err := h.webhookUseCase.SearchDeliveries(c.Request.Context(), filter, page, pageSize)
+	h.respondDeliveries(c, deliveries, err)
+}
+
+// AdminGetDelivery godoc
+//
+//	@Summary		Get any webhook delivery
+//	@Description	Get a webhook delivery with the history of every attempt to deliver it (admin and support)
+//	@Tags			admin
+//	@Accept			json
+//	@Produce		json
+//	@Security		BearerAuth
+//	@Param			id	path		int	true	"Webhook delivery ID"
+//	@Success		200	{object}	dto.APIResponse{data=dto.WebhookDeliveryResponse}
+//	@Failure		400	{object}	dto.ErrorResponse
+//	@Failure		401	{object}	dto.ErrorResponse
+//	@Failure		403	{object}	dto.ErrorResponse
+//	@Failure		404	{object}	dto.ErrorResponse
+//	@Failure		500	{object}	dto.ErrorResponse
+//	@Router			/admin/webhooks/deliveries/{id} [get]
+func (h *WebhookSubscriptionHandler) AdminGetDelivery(c *gin.Context) {
+	deliveryID, err := parseIDParam(c, "id")
+	if err != nil {
+		c.Error(err).SetMeta("webhook.invalid_delivery_id")
+		return
+	}
+
+	delivery, attempts, err := h.webhookUseCase.GetDelivery(c.Request.Context(), deliveryID)
+	h.respondDelivery(c, delivery, attempts, err)
+}
+
+// Redeliver godoc
+//
+//	@Summary		Redeliver a dead webhook delivery
+//	@Description	Move a webhook delivery out of the dead letter queue and send it again with a fresh set of attempts (admin only)
+//	@Tags			admin
+//	@Accept			json
+//	@Produce		json
+//	@Security		BearerAuth
+//	@Param			id	path		int	true	"Webhook delivery ID"
+//	@Success		200	{object}	dto.APIResponse{data=dto.WebhookDeliveryResponse}
+//	@Failure		400	{object}	dto.ErrorResponse
+//	@Failure		401	{object}	dto.ErrorResponse
+//	@Failure		403	{object}	dto.ErrorResponse
+//	@Failure		404	{object}	dto.ErrorResponse
+//	@Failure		409	{object}	dto.ErrorResponse	"Delivery is not dead or its subscription was disabled"
+//	@Failure		500	{object}	dto.ErrorResponse
+//	@Router			/admin/webhooks/deliveries/{id}/redeliver [post]
+func (h *WebhookSubscriptionHandler) Redeliver(c *gin.Context) {
+	deliveryID, err := parseIDParam(c, "id")
+	if err != nil {
+		c.Error(err).SetMeta("webhook.invalid_delivery_id")
+		return
+	}
+
+	delivery, err := h.webhookUseCase.Redeliver(c.Request.Context(), deliveryID)
+	if err != nil {
+		c.Error(err).SetMeta("webhook.redeliver_failed")
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.APIResponse{
+		Success: true,
+		Message: middleware.Translate(c, "webhook.redelivery_queued"),
+		Data:    dto.ToWebhookDeliveryResponse(delivery),
+	})
+}
+
+// replay queues the events the request selects to the subscription of the path with action
+func (h *WebhookSubscriptionHandler) replay(c *gin.Context, action func(ctx context.Context, subscriptionID uint, replay usecases.EventReplay) (int, error)) {
+	subscriptionID, err := parseIDParam(c, "id")
+	if err != nil {
+		c.Error(err).SetMeta("webhook.invalid_subscription_id")
+		return
+	}
+
+	var req dto.ReplayWebhookEventsRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.Error(apperrors.ErrValidation.Withf("invalid request data: %w", err)).SetMeta("request.invalid")
+		return
+	}
+
+	queued, err := action(c.Request.Context(), subscriptionID, usecases.EventReplay{WalletID: req.WalletID, From: req.From, To: req.To})
+	if err != nil {
+		c.Error(err).SetMeta("webhook.events_replay_failed")
+		return
+	}
+
+	c.JSON(http.StatusAccepted, dto.APIResponse{
+		Success: true,
+		Message: middleware.Translate(c, "webhook.events_replay_queued"),
+		Data:    dto.ReplayWebhookEventsResponse{Queued: queued},
+	})
+}
+
+func (h *WebhookSubscriptionHandler) respondDelivery(c *gin.Context, delivery *models.WebhookDelivery, attempts []models.WebhookDeliveryAttempt, err error) {
+	if err != nil {
+		c.Error(err).SetMeta("webhook.delivery_retrieve_failed")
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.APIResponse{
+		Success: true,
+		Message: middleware.Translate(c, "webhook.delivery_retrieved"),
+		Data:    dto.ToWebhookDeliveryDetailResponse(delivery, attempts),
+	})
+}
+
+func (h *WebhookSubscriptionHandler) respondDeliveries(c *gin.Context, deliveries []models.WebhookDelivery, err error) {
+	if err != nil {
+		c.Error(err).SetMeta("webhook.deliveries_retrieve_failed")
+		return
+	}
+
+	responses := make([]dto.WebhookDeliveryResponse, len(deliveries))
+	for i := range deliveries {
+		responses[i] = dto.ToWebhookDeliveryResponse(&deliveries[i])
+	}
+
+	c.JSON(http.StatusOK, dto.APIResponse{
+		Success: true,
+		Message: middleware.Translate(c, "webhook.deliveries_retrieved"),
+		Data:    responses,
+	})
+}
@@ -0,0 +1,124 @@
+package i18n
+
+import (
+	"embed"
+	"encoding/json"
+	"fmt"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// DefaultLanguage is used when a client accepts none of the supported languages, and for messages
+// missing from a translation
+const DefaultLanguage = "en"
+
+//go:embed locales/*.json
+var localeFiles embed.FS
+
+// catalog holds the messages of every supported language by message key
+var catalog = mustLoad()
+
+// mustLoad reads the embedded translations; each file is named after its language and maps message
+// keys to messages. Placeholders such as {field} are filled in by T
+func mustLoad() map[string]map[string]string {
+	entries, err := localeFiles.ReadDir("locales")
+	if err != nil {
+		panic(fmt.Sprintf("i18n: failed to read translations: %v", err))
+	}
+
+	loaded := make(map[string]map[string]string, len(entries))
+	for _, entry := range entries {
+		data, err := localeFiles.ReadFile(path.Join("locales", entry.Name()))
+		if err != nil {
+			panic(fmt.Sprintf("i18n: failed to read %s: %v", entry.Name(), err))
+		}
+		var messages map[string]string
+		if err := json.Unmarshal(data, &messages); err != nil {
+			panic(fmt.Sprintf("i18n: invalid translation file %s: %v", entry.Name(), err))
+		}
+		loaded[strings.TrimSuffix(entry.Name(), ".json")] = messages
+	}
+	if _, ok := loaded[DefaultLanguage]; !ok {
+		panic("i18n: missing translations for the default language")
+	}
+	return loaded
+}
+
+// Languages returns the supported languages in alphabetical order
+func Languages() []string {
+	languages := make([]string, 0, len(catalog))
+	for language := range catalog {
+		languages = append(languages, language)
+	}
+	sort.Strings(languages)
+	return languages
+}
+
+// Has reports whether key has a message in the default language
+func Has(key string) bool {
+	_, ok := catalog[DefaultLanguage][key]
+	return ok
+}
+
+// T returns the message for key in language, falling back to English and then to the key itself.
+// args are placeholder names and values in pairs: T("fr", "validation.required", "field", "amount")
+func T(language, key string, args ...string) string {
+	message, ok := catalog[language][key]
+	if !ok {
+		if message, ok = catalog[DefaultLanguage][key]; !ok {
+			message = key
+		}
+	}
+
+	for i := 0; i+1 < len(args); i += 2 {
+		message = strings.ReplaceAll(message, "{"+args[i]+"}", args[i+1])
+	}
+	return message
+}
+
+// Match returns the supported language the client prefers according to an Accept-Language header,
+// such as "fr-CH, fr;q=0.9, en;q=0.8", or DefaultLanguage when it accepts none of them
+func Match(acceptLanguage string) string {
+	type preference struct {
+		language string
+		quality  float64
+	}
+
+	var preferences []preference
+	for _, part := range strings.Split(acceptLanguage, ",") {
+		fields := strings.Split(strings.TrimSpace(part), ";")
+		tag := strings.ToLower(strings.TrimSpace(fields[0]))
+		if tag == "" {
+			continue
+		}
+
+		quality := 1.0
+		for _, param := range fields[1:] {
+			if value, ok := strings.CutPrefix(strings.TrimSpace(param), "q="); ok {
+				if q, err := strconv.ParseFloat(value, 64); err == nil {
+					quality = q
+				}
+			}
+		}
+		if quality <= 0 {
+			continue
+		}
+
+		// Regional variants use the translation of their base language
+		base, _, _ := strings.Cut(tag, "-")
+		preferences = append(preferences, preference{language: base, quality: quality})
+	}
+
+	sort.SliceStable(preferences, func(i, j int) bool { return preferences[i].quality > preferences[j].quality })
+	for _, p := range preferences {
+		if p.language == "*" {
+			return DefaultLanguage
+		}
+		if _, ok := catalog[p.language]; ok {
+			return p.language
+		}
+	}
+	return DefaultLanguage
+}
@@ -0,0 +1,48 @@
+package i18n
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMatch(t *testing.T) {
+	tests := []struct {
+		name           string
+		acceptLanguage string
+		expected       string
+	}{
+		{"empty header", "", DefaultLanguage},
+		{"supported language", "fr", "fr"},
+		{"regional variant", "es-MX", "es"},
+		{"quality order", "en;q=0.5, fr;q=0.9", "fr"},
+		{"unsupported languages skipped", "de, ja;q=0.9, es;q=0.8", "es"},
+		{"refused language", "fr;q=0, es;q=0.5", "es"},
+		{"wildcard", "de, *;q=0.5", DefaultLanguage},
+		{"nothing supported", "de, ja", DefaultLanguage},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, Match(tt.acceptLanguage))
+		})
+	}
+}
+
+func TestT(t *testing.T) {
+	assert.Equal(t, "amount est obligatoire", T("fr", "validation.required", "field", "amount"))
+	assert.Equal(t, "amount must be at least 1", T("de", "validation.min", "field", "amount", "param", "1"))
+	assert.Equal(t, "unknown.key", T("fr", "unknown.key"))
+}
+
+func TestTranslationsAreComplete(t *testing.T) {
+	for _, language := range Languages() {
+		for key := range catalog[DefaultLanguage] {
+			_, ok := catalog[language][key]
+			assert.True(t, ok, "%s translation is missing %s", language, key)
+		}
+		for key := range catalog[language] {
+			assert.True(t, Has(key), "%s translation has unknown key %s", language, key)
+		}
+	}
+}
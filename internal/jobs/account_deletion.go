@@ -0,0 +1,39 @@
+package jobs
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"github.com/limistah/wallet-service/internal/usecases"
+)
+
+// StartAccountDeletion anonymizes the accounts of users who asked for deletion more than
+// gracePeriod ago every interval; the returned function stops the job
+func StartAccountDeletion(userUseCase usecases.UserUseCase, interval, gracePeriod time.Duration) func() {
+	ticker := time.NewTicker(interval)
+	ctx, cancel := context.WithCancel(context.Background())
+
+	go func() {
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case now := <-ticker.C:
+				anonymized, err := userUseCase.AnonymizeDeletedAccounts(ctx, now.Add(-gracePeriod))
+				if err != nil {
+					log.Printf("Failed to anonymize deleted accounts: %v", err)
+					continue
+				}
+				if anonymized > 0 {
+					log.Printf("Anonymized %d deleted accounts", anonymized)
+				}
+			}
+		}
+	}()
+
+	return func() {
+		ticker.Stop()
+		cancel()
+	}
+}
@@ -0,0 +1,40 @@
+package jobs
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"github.com/limistah/wallet-service/internal/usecases"
+)
+
+// StartBalanceCheckpoints moves wallet balance checkpoints up to the transactions settled more than
+// lag ago every interval in the background, keeping reconciliation sums short; the returned function
+// stops the job
+func StartBalanceCheckpoints(reconciliationUseCase usecases.ReconciliationUseCase, interval, lag time.Duration) func() {
+	ticker := time.NewTicker(interval)
+	ctx, cancel := context.WithCancel(context.Background())
+
+	go func() {
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case now := <-ticker.C:
+				advanced, err := reconciliationUseCase.CheckpointBalances(ctx, now.Add(-lag))
+				if err != nil {
+					log.Printf("Failed to checkpoint wallet balances: %v", err)
+					continue
+				}
+				if advanced > 0 {
+					log.Printf("Checkpointed %d wallet balances", advanced)
+				}
+			}
+		}
+	}()
+
+	return func() {
+		ticker.Stop()
+		cancel()
+	}
+}
@@ -0,0 +1,38 @@
+package jobs
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"github.com/limistah/wallet-service/internal/usecases"
+)
+
+// StartBudgetAlerts compares this month's spending with users' budgets every interval and alerts those
+// who crossed a threshold; the returned function stops the job
+func StartBudgetAlerts(budgetUseCase usecases.BudgetUseCase, interval time.Duration) func() {
+	ticker := time.NewTicker(interval)
+	ctx, cancel := context.WithCancel(context.Background())
+
+	go func() {
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case now := <-ticker.C:
+				alerts, err := budgetUseCase.EvaluateBudgets(ctx, now)
+				if err != nil {
+					log.Printf("Failed to evaluate budgets: %v", err)
+				}
+				if alerts > 0 {
+					log.Printf("Sent %d budget alerts", alerts)
+				}
+			}
+		}
+	}()
+
+	return func() {
+		ticker.Stop()
+		cancel()
+	}
+}
@@ -0,0 +1,38 @@
+package jobs
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"github.com/limistah/wallet-service/internal/usecases"
+)
+
+// StartEscrowExpiry returns escrows still held past their expiry to their payers every interval; the
+// returned function stops the job
+func StartEscrowExpiry(escrowUseCase usecases.EscrowUseCase, interval time.Duration) func() {
+	ticker := time.NewTicker(interval)
+	ctx, cancel := context.WithCancel(context.Background())
+
+	go func() {
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case now := <-ticker.C:
+				expired, err := escrowUseCase.ExpireEscrows(ctx, now)
+				if err != nil {
+					log.Printf("Failed to expire escrows: %v", err)
+				}
+				if expired > 0 {
+					log.Printf("Returned %d expired escrows to their payers", expired)
+				}
+			}
+		}
+	}()
+
+	return func() {
+		ticker.Stop()
+		cancel()
+	}
+}
@@ -0,0 +1,39 @@
+package jobs
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"github.com/limistah/wallet-service/internal/usecases"
+)
+
+// StartPendingExpiry cancels withdrawals and transfers left PENDING for longer than ttl every
+// interval in the background, refunding the held funds; the returned function stops the job
+func StartPendingExpiry(walletUseCase usecases.WalletUseCase, interval, ttl time.Duration) func() {
+	ticker := time.NewTicker(interval)
+	ctx, cancel := context.WithCancel(context.Background())
+
+	go func() {
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case now := <-ticker.C:
+				expired, err := walletUseCase.ExpirePendingTransactions(ctx, now.Add(-ttl))
+				if err != nil {
+					log.Printf("Failed to expire pending transactions: %v", err)
+					continue
+				}
+				if expired > 0 {
+					log.Printf("Expired %d pending transactions", expired)
+				}
+			}
+		}
+	}()
+
+	return func() {
+		ticker.Stop()
+		cancel()
+	}
+}
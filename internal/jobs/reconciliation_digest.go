@@ -0,0 +1,50 @@
+package jobs
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"github.com/limistah/wallet-service/internal/notifications"
+	"github.com/limistah/wallet-service/internal/usecases"
+)
+
+// StartReconciliationDigest summarises the previous UTC day's reconciliation reports and delivers the
+// digest to recipients, checking every interval so a failed delivery is retried on the next tick; the
+// returned function stops the job
+func StartReconciliationDigest(reconciliationUseCase usecases.ReconciliationUseCase, notifier *notifications.Notifier, recipients []string, interval time.Duration) func() {
+	ticker := time.NewTicker(interval)
+	ctx, cancel := context.WithCancel(context.Background())
+
+	go func() {
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case now := <-ticker.C:
+				summary, err := reconciliationUseCase.SummarizeDay(ctx, now.UTC().AddDate(0, 0, -1))
+				if err != nil {
+					log.Printf("Failed to summarise reconciliation reports: %v", err)
+					continue
+				}
+				if summary.DeliveredAt != nil {
+					continue
+				}
+				if err := notifier.DeliverReconciliationDigest(summary, recipients); err != nil {
+					log.Printf("Failed to deliver reconciliation digest for %s: %v", summary.Date.Format("2006-01-02"), err)
+					continue
+				}
+				if err := reconciliationUseCase.MarkSummaryDelivered(ctx, summary.ID); err != nil {
+					log.Printf("Failed to mark reconciliation digest delivered: %v", err)
+					continue
+				}
+				log.Printf("Delivered reconciliation digest for %s", summary.Date.Format("2006-01-02"))
+			}
+		}
+	}()
+
+	return func() {
+		ticker.Stop()
+		cancel()
+	}
+}
@@ -0,0 +1,41 @@
+package jobs
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"github.com/limistah/wallet-service/internal/usecases"
+)
+
+// StartSettlementBatches groups the previous UTC day's completed deposits and payouts into settlement
+// batches every interval, so items that complete late still reach their day's batch; the returned
+// function stops the job
+func StartSettlementBatches(settlementUseCase usecases.SettlementUseCase, interval time.Duration) func() {
+	ticker := time.NewTicker(interval)
+	ctx, cancel := context.WithCancel(context.Background())
+
+	go func() {
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case now := <-ticker.C:
+				day := now.UTC().AddDate(0, 0, -1)
+				batches, err := settlementUseCase.CloseDay(ctx, day)
+				if err != nil {
+					log.Printf("Failed to close settlement day %s: %v", day.Format("2006-01-02"), err)
+					continue
+				}
+				if len(batches) > 0 {
+					log.Printf("Updated %d settlement batches for %s", len(batches), day.Format("2006-01-02"))
+				}
+			}
+		}
+	}()
+
+	return func() {
+		ticker.Stop()
+		cancel()
+	}
+}
@@ -0,0 +1,39 @@
+package jobs
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"github.com/limistah/wallet-service/internal/usecases"
+)
+
+// StartStandingOrders processes due standing orders and their retries every interval in the
+// background; the returned function stops the job
+func StartStandingOrders(standingOrderUseCase usecases.StandingOrderUseCase, interval time.Duration) func() {
+	ticker := time.NewTicker(interval)
+	ctx, cancel := context.WithCancel(context.Background())
+
+	go func() {
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case now := <-ticker.C:
+				attempts, err := standingOrderUseCase.ProcessDue(ctx, now)
+				if err != nil {
+					log.Printf("Failed to process standing orders: %v", err)
+					continue
+				}
+				if attempts > 0 {
+					log.Printf("Processed %d standing order payments", attempts)
+				}
+			}
+		}
+	}()
+
+	return func() {
+		ticker.Stop()
+		cancel()
+	}
+}
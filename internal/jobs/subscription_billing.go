@@ -0,0 +1,39 @@
+package jobs
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"github.com/limistah/wallet-service/internal/usecases"
+)
+
+// StartSubscriptionBilling charges due subscriptions and retries failed charges every interval in the
+// background; the returned function stops the job
+func StartSubscriptionBilling(subscriptionUseCase usecases.SubscriptionUseCase, interval time.Duration) func() {
+	ticker := time.NewTicker(interval)
+	ctx, cancel := context.WithCancel(context.Background())
+
+	go func() {
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case now := <-ticker.C:
+				charges, err := subscriptionUseCase.BillDue(ctx, now)
+				if err != nil {
+					log.Printf("Failed to bill subscriptions: %v", err)
+					continue
+				}
+				if charges > 0 {
+					log.Printf("Attempted %d subscription charges", charges)
+				}
+			}
+		}
+	}()
+
+	return func() {
+		ticker.Stop()
+		cancel()
+	}
+}
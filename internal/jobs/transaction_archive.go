@@ -0,0 +1,39 @@
+package jobs
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"github.com/limistah/wallet-service/internal/usecases"
+)
+
+// StartTransactionArchive moves settled transactions older than retention into the archive every
+// interval in the background, checkpointing wallet balances first; the returned function stops the job
+func StartTransactionArchive(archiveUseCase usecases.ArchiveUseCase, interval, retention time.Duration) func() {
+	ticker := time.NewTicker(interval)
+	ctx, cancel := context.WithCancel(context.Background())
+
+	go func() {
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case now := <-ticker.C:
+				result, err := archiveUseCase.ArchiveTransactions(ctx, now.Add(-retention))
+				if err != nil {
+					log.Printf("Failed to archive transactions: %v", err)
+					continue
+				}
+				if result.Archived > 0 || result.Checkpointed > 0 {
+					log.Printf("Archived %d transactions and checkpointed %d wallets", result.Archived, result.Checkpointed)
+				}
+			}
+		}
+	}()
+
+	return func() {
+		ticker.Stop()
+		cancel()
+	}
+}
@@ -0,0 +1,282 @@
+// Package metrics keeps the gauges and histograms of the service and exposes them in the Prometheus
+// text format for scraping
+package metrics
+
+import (
+	"bufio"
+	"crypto/subtle"
+	"fmt"
+	"io"
+	"log"
+	"math"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// contentType is the media type of the Prometheus text exposition format
+const contentType = "text/plain; version=0.0.4; charset=utf-8"
+
+// Sample is one value of a metric collected when it is scraped; Labels are in the order the metric
+// was registered with
+type Sample struct {
+	Labels []string
+	Value  float64
+}
+
+// collector writes the exposition of one metric family
+type collector interface {
+	write(w *bufio.Writer) error
+}
+
+// Registry holds the metrics exposed by a service
+type Registry struct {
+	mu         sync.Mutex
+	collectors []collector
+	names      map[string]bool
+}
+
+// NewRegistry creates an empty registry
+func NewRegistry() *Registry {
+	return &Registry{names: make(map[string]bool)}
+}
+
+// register adds a collector; registering a name twice is a programming error
+func (r *Registry) register(name string, c collector) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.names[name] {
+		panic(fmt.Sprintf("metrics: %s registered twice", name))
+	}
+	r.names[name] = true
+	r.collectors = append(r.collectors, c)
+}
+
+// NewGauge registers a gauge with the given label names
+func (r *Registry) NewGauge(name, help string, labels ...string) *Gauge {
+	g := &Gauge{family: family{name: name, help: help, kind: "gauge", labels: labels}, points: make(map[string]*point)}
+	r.register(name, g)
+	return g
+}
+
+// NewHistogram registers a histogram with the given upper bounds, in increasing order, and label names
+func (r *Registry) NewHistogram(name, help string, buckets []float64, labels ...string) *Histogram {
+	h := &Histogram{
+		family:  family{name: name, help: help, kind: "histogram", labels: labels},
+		buckets: append([]float64(nil), buckets...),
+		points:  make(map[string]*histogramPoint),
+	}
+	sort.Float64s(h.buckets)
+	r.register(name, h)
+	return h
+}
+
+// NewGaugeFunc registers a gauge whose samples are collected by collect every time the registry is
+// scraped, for values read from the database rather than kept by this instance
+func (r *Registry) NewGaugeFunc(name, help string, labels []string, collect func() ([]Sample, error)) {
+	r.register(name, &collectedFamily{family: family{name: name, help: help, kind: "gauge", labels: labels}, collect: collect})
+}
+
+// NewCounterFunc registers a counter whose samples are collected by collect every time the registry
+// is scraped, for totals kept elsewhere such as by the database driver
+func (r *Registry) NewCounterFunc(name, help string, labels []string, collect func() ([]Sample, error)) {
+	r.register(name, &collectedFamily{family: family{name: name, help: help, kind: "counter", labels: labels}, collect: collect})
+}
+
+// Write writes every metric in the text format. A metric that fails to collect is left out and
+// logged so the others are still scraped
+func (r *Registry) Write(w io.Writer) error {
+	r.mu.Lock()
+	collectors := append([]collector(nil), r.collectors...)
+	r.mu.Unlock()
+
+	buf := bufio.NewWriter(w)
+	for _, c := range collectors {
+		if err := c.write(buf); err != nil {
+			log.Printf("Metrics: %v", err)
+		}
+	}
+	return buf.Flush()
+}
+
+// Handler serves the metrics to scrapers. When token is set, scrapers must send it as a bearer token
+func (r *Registry) Handler(token string) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		if token != "" {
+			sent := strings.TrimPrefix(req.Header.Get("Authorization"), "Bearer ")
+			if subtle.ConstantTimeCompare([]byte(sent), []byte(token)) != 1 {
+				http.Error(w, "unauthorized", http.StatusUnauthorized)
+				return
+			}
+		}
+		w.Header().Set("Content-Type", contentType)
+		if err := r.Write(w); err != nil {
+			log.Printf("Metrics: failed to write the scrape: %v", err)
+		}
+	})
+}
+
+// family is the name, help text, type and label names shared by the series of a metric
+type family struct {
+	name   string
+	help   string
+	kind   string
+	labels []string
+}
+
+func (f family) writeHeader(w *bufio.Writer) {
+	fmt.Fprintf(w, "# HELP %s %s\n", f.name, strings.NewReplacer(`\`, `\\`, "\n", `\n`).Replace(f.help))
+	fmt.Fprintf(w, "# TYPE %s %s\n", f.name, f.kind)
+}
+
+// key identifies a series by its label values, after checking there is one per label name
+func (f family) key(values []string) string {
+	if len(values) != len(f.labels) {
+		panic(fmt.Sprintf("metrics: %s takes %d label values, got %d", f.name, len(f.labels), len(values)))
+	}
+	return strings.Join(values, "\xff")
+}
+
+// labelSet formats label pairs as {name="value",...}; extra pairs such as the le of histogram buckets
+// are appended after the labels of the family
+func (f family) labelSet(values []string, extra ...string) string {
+	if len(values) == 0 && len(extra) == 0 {
+		return ""
+	}
+	pairs := make([]string, 0, len(values)+len(extra)/2)
+	for i, value := range values {
+		pairs = append(pairs, f.labels[i]+`="`+escapeLabel(value)+`"`)
+	}
+	for i := 0; i+1 < len(extra); i += 2 {
+		pairs = append(pairs, extra[i]+`="`+escapeLabel(extra[i+1])+`"`)
+	}
+	return "{" + strings.Join(pairs, ",") + "}"
+}
+
+func escapeLabel(value string) string {
+	return strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`).Replace(value)
+}
+
+func formatFloat(v float64) string {
+	switch {
+	case math.IsInf(v, 1):
+		return "+Inf"
+	case math.IsInf(v, -1):
+		return "-Inf"
+	case math.IsNaN(v):
+		return "NaN"
+	}
+	return strconv.FormatFloat(v, 'g', -1, 64)
+}
+
+// sortedKeys returns the keys of a series map in a stable order so scrapes are deterministic
+func sortedKeys[T any](m map[string]T) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+type point struct {
+	labels []string
+	value  float64
+}
+
+// Gauge is a value that goes up and down, one series per combination of label values
+type Gauge struct {
+	family
+	mu     sync.Mutex
+	points map[string]*point
+}
+
+// Set sets the series of the given label values to value
+func (g *Gauge) Set(value float64, labelValues ...string) {
+	key := g.key(labelValues)
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	g.points[key] = &point{labels: append([]string(nil), labelValues...), value: value}
+}
+
+func (g *Gauge) write(w *bufio.Writer) error {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	g.writeHeader(w)
+	for _, key := range sortedKeys(g.points) {
+		p := g.points[key]
+		fmt.Fprintf(w, "%s%s %s\n", g.name, g.labelSet(p.labels), formatFloat(p.value))
+	}
+	return nil
+}
+
+type histogramPoint struct {
+	labels []string
+	counts []uint64 // Observations at or below each bucket, not cumulative
+	count  uint64
+	sum    float64
+}
+
+// Histogram counts observations in buckets, one series per combination of label values
+type Histogram struct {
+	family
+	buckets []float64
+	mu      sync.Mutex
+	points  map[string]*histogramPoint
+}
+
+// Observe adds a value to the series of the given label values
+func (h *Histogram) Observe(value float64, labelValues ...string) {
+	key := h.key(labelValues)
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	p, ok := h.points[key]
+	if !ok {
+		p = &histogramPoint{labels: append([]string(nil), labelValues...), counts: make([]uint64, len(h.buckets))}
+		h.points[key] = p
+	}
+	if i := sort.SearchFloat64s(h.buckets, value); i < len(h.buckets) {
+		p.counts[i]++
+	}
+	p.count++
+	p.sum += value
+}
+
+func (h *Histogram) write(w *bufio.Writer) error {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.writeHeader(w)
+	for _, key := range sortedKeys(h.points) {
+		p := h.points[key]
+		var cumulative uint64
+		for i, bound := range h.buckets {
+			cumulative += p.counts[i]
+			fmt.Fprintf(w, "%s_bucket%s %d\n", h.name, h.labelSet(p.labels, "le", formatFloat(bound)), cumulative)
+		}
+		fmt.Fprintf(w, "%s_bucket%s %d\n", h.name, h.labelSet(p.labels, "le", "+Inf"), p.count)
+		fmt.Fprintf(w, "%s_sum%s %s\n", h.name, h.labelSet(p.labels), formatFloat(p.sum))
+		fmt.Fprintf(w, "%s_count%s %d\n", h.name, h.labelSet(p.labels), p.count)
+	}
+	return nil
+}
+
+// collectedFamily is a gauge or counter collected on every scrape
+type collectedFamily struct {
+	family
+	collect func() ([]Sample, error)
+}
+
+func (g *collectedFamily) write(w *bufio.Writer) error {
+	samples, err := g.collect()
+	if err != nil {
+		return fmt.Errorf("failed to collect %s: %w", g.name, err)
+	}
+	g.writeHeader(w)
+	for _, sample := range samples {
+		g.key(sample.Labels)
+		fmt.Fprintf(w, "%s%s %s\n", g.name, g.labelSet(sample.Labels), formatFloat(sample.Value))
+	}
+	return nil
+}
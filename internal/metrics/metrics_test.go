@@ -0,0 +1,88 @@
+package metrics
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestRegistry_WritesTextFormat(t *testing.T) {
+	registry := NewRegistry()
+	last := registry.NewGauge("recon_last_success_timestamp_seconds", "When the last run succeeded", "scope")
+	duration := registry.NewHistogram("recon_run_duration_seconds", "How long runs take", []float64{1, 0.1}, "scope")
+	registry.NewGaugeFunc("recon_open_mismatch_amount", "Open mismatch amount", []string{"currency"}, func() ([]Sample, error) {
+		return []Sample{{Labels: []string{`US"D`}, Value: 12.5}}, nil
+	})
+	registry.NewGaugeFunc("recon_broken", "Fails to collect", nil, func() ([]Sample, error) {
+		return nil, errors.New("database is down")
+	})
+	registry.NewCounterFunc("db_wait_count_total", "Connections waited for", nil, func() ([]Sample, error) {
+		return []Sample{{Value: 7}}, nil
+	})
+
+	last.Set(1700000000, "all")
+	duration.Observe(0.05, "wallet")
+	duration.Observe(0.5, "wallet")
+	duration.Observe(3, "wallet")
+
+	var out strings.Builder
+	if err := registry.Write(&out); err != nil {
+		t.Fatalf("Unexpected error writing metrics: %v", err)
+	}
+	want := `# HELP recon_last_success_timestamp_seconds When the last run succeeded
+# TYPE recon_last_success_timestamp_seconds gauge
+recon_last_success_timestamp_seconds{scope="all"} 1.7e+09
+# HELP recon_run_duration_seconds How long runs take
+# TYPE recon_run_duration_seconds histogram
+recon_run_duration_seconds_bucket{scope="wallet",le="0.1"} 1
+recon_run_duration_seconds_bucket{scope="wallet",le="1"} 2
+recon_run_duration_seconds_bucket{scope="wallet",le="+Inf"} 3
+recon_run_duration_seconds_sum{scope="wallet"} 3.55
+recon_run_duration_seconds_count{scope="wallet"} 3
+# HELP recon_open_mismatch_amount Open mismatch amount
+# TYPE recon_open_mismatch_amount gauge
+recon_open_mismatch_amount{currency="US\"D"} 12.5
+# HELP db_wait_count_total Connections waited for
+# TYPE db_wait_count_total counter
+db_wait_count_total 7
+`
+	if out.String() != want {
+		t.Errorf("Unexpected exposition:\n%s\nwant:\n%s", out.String(), want)
+	}
+}
+
+func TestRegistry_HandlerRequiresToken(t *testing.T) {
+	registry := NewRegistry()
+	registry.NewGauge("up", "Whether the service is up").Set(1)
+	handler := registry.Handler("scrape-token")
+
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+	if rec.Code != http.StatusUnauthorized {
+		t.Errorf("Expected scrapes without the token to be refused, got %d", rec.Code)
+	}
+
+	req := httptest.NewRequest(http.MethodGet, "/metrics", nil)
+	req.Header.Set("Authorization", "Bearer scrape-token")
+	rec = httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+	if rec.Code != http.StatusOK || !strings.Contains(rec.Body.String(), "up 1\n") {
+		t.Errorf("Expected the metrics with the token, got %d %q", rec.Code, rec.Body.String())
+	}
+	if ct := rec.Header().Get("Content-Type"); !strings.HasPrefix(ct, "text/plain; version=0.0.4") {
+		t.Errorf("Expected the Prometheus content type, got %q", ct)
+	}
+}
+
+func TestRegistry_RefusesDuplicateNames(t *testing.T) {
+	registry := NewRegistry()
+	registry.NewGauge("up", "Whether the service is up")
+	defer func() {
+		if recover() == nil {
+			t.Error("Expected registering a name twice to panic")
+		}
+	}()
+	registry.NewGauge("up", "Again")
+}
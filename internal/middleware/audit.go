@@ -0,0 +1,60 @@
+package middleware
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/limistah/wallet-service/internal/models"
+	"github.com/limistah/wallet-service/internal/usecases"
+)
+
+// AuditTrail creates a middleware function that records every request in the audit log.
+// It must be mounted after AuthMiddleware so the actor is known.
+func AuditTrail(auditUseCase usecases.AuditUseCase) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		c.Next()
+
+		userID, _ := GetUserID(c)
+		email, _ := GetUserEmail(c)
+		role, _ := GetUserRole(c)
+
+		resourceType, resourceID := auditResource(c)
+
+		entry := &models.AuditLog{
+			ActorID:      userID,
+			ActorEmail:   email,
+			ActorRole:    role,
+			Action:       fmt.Sprintf("%s %s", c.Request.Method, c.FullPath()),
+			ResourceType: resourceType,
+			ResourceID:   resourceID,
+			Method:       c.Request.Method,
+			Path:         c.Request.URL.RequestURI(),
+			StatusCode:   c.Writer.Status(),
+			IPAddress:    c.ClientIP(),
+			UserAgent:    c.Request.UserAgent(),
+		}
+
+		// The request is over, but a client hanging up must not keep it out of the audit trail
+		if err := auditUseCase.Record(context.WithoutCancel(c.Request.Context()), entry); err != nil {
+			log.Printf("Failed to record audit log for %s: %v", entry.Action, err)
+		}
+	}
+}
+
+// auditResource derives the resource type and identifier from the matched route,
+// e.g. /api/v1/admin/wallets/:id resolves to ("wallets", "<id>")
+func auditResource(c *gin.Context) (string, string) {
+	segments := strings.Split(strings.Trim(c.FullPath(), "/"), "/")
+	for i, segment := range segments {
+		if segment == ":id" && i > 0 {
+			return segments[i-1], c.Param("id")
+		}
+	}
+	if len(segments) > 0 {
+		return segments[len(segments)-1], ""
+	}
+	return "", ""
+}
@@ -2,63 +2,79 @@ package middleware
 
 import (
 	"fmt"
-	"net/http"
 	"strings"
+	"time"
 
 	"github.com/gin-gonic/gin"
+	"github.com/limistah/wallet-service/internal/apperrors"
 	"github.com/limistah/wallet-service/internal/auth"
+	"github.com/limistah/wallet-service/internal/models"
+	"github.com/limistah/wallet-service/internal/usecases"
 )
 
-// AuthMiddleware creates a middleware function for JWT authentication
-func AuthMiddleware(jwtService *auth.JWTService) gin.HandlerFunc {
+// AuthMiddleware creates a middleware function for JWT authentication. Tokens signed out with logout or
+// a password change are refused, and so are requests changing state made with read-only impersonation
+// tokens
+func AuthMiddleware(jwtService *auth.JWTService, revocations usecases.TokenRevocationUseCase) gin.HandlerFunc {
 	return func(c *gin.Context) {
 		authHeader := c.GetHeader("Authorization")
 		if authHeader == "" {
-			c.JSON(http.StatusUnauthorized, gin.H{
-				"success": false,
-				"message": "Authorization header is required",
-				"error":   "missing authorization header",
-			})
+			c.Error(apperrors.ErrUnauthenticated.Withf("missing authorization header"))
 			c.Abort()
 			return
 		}
 
 		// Check if the header starts with "Bearer "
 		if !strings.HasPrefix(authHeader, "Bearer ") {
-			c.JSON(http.StatusUnauthorized, gin.H{
-				"success": false,
-				"message": "Invalid authorization header format",
-				"error":   "authorization header must start with 'Bearer '",
-			})
+			c.Error(apperrors.ErrInvalidToken.Withf("authorization header must start with 'Bearer '"))
 			c.Abort()
 			return
 		}
 
 		tokenString := strings.TrimPrefix(authHeader, "Bearer ")
-		fmt.Println(tokenString)
 		if tokenString == "" {
-			c.JSON(http.StatusUnauthorized, gin.H{
-				"success": false,
-				"message": "Token is required",
-				"error":   "empty token",
-			})
+			c.Error(apperrors.ErrInvalidToken.Withf("empty token"))
 			c.Abort()
 			return
 		}
 
 		claims, err := jwtService.ValidateToken(tokenString)
 		if err != nil {
-			c.JSON(http.StatusUnauthorized, gin.H{
-				"success": false,
-				"message": "Invalid or expired token",
-				"error":   err.Error(),
-			})
+			c.Error(apperrors.ErrInvalidToken.Wrap(err))
+			c.Abort()
+			return
+		}
+
+		var issuedAt time.Time
+		if claims.IssuedAt != nil {
+			issuedAt = claims.IssuedAt.Time
+		}
+		revoked, err := revocations.IsRevoked(c.Request.Context(), claims.ID, claims.UserID, issuedAt)
+		if err != nil {
+			c.Error(fmt.Errorf("failed to check token revocation: %w", err))
+			c.Abort()
+			return
+		}
+		if revoked {
+			c.Error(apperrors.ErrTokenRevoked)
 			c.Abort()
 			return
 		}
 
 		c.Set("user_id", claims.UserID)
 		c.Set("user_email", claims.Email)
+		c.Set("user_role", claims.Role)
+		c.Set("sandbox", claims.Sandbox)
+		c.Set("token_claims", claims)
+
+		if claims.Impersonator != nil {
+			c.Set("impersonator", claims.Impersonator)
+			if claims.Impersonator.ReadOnly && !allowedReadOnly(c) {
+				c.Error(apperrors.ErrImpersonationReadOnly)
+				c.Abort()
+				return
+			}
+		}
 
 		c.Next()
 	}
@@ -76,6 +92,18 @@ func GetUserID(c *gin.Context) (uint, bool) {
 	return 0, false
 }
 
+// GetTokenClaims extracts the claims of the request's token from the Gin context
+func GetTokenClaims(c *gin.Context) (*auth.Claims, bool) {
+	claims, exists := c.Get("token_claims")
+	if !exists {
+		return nil, false
+	}
+	if tokenClaims, ok := claims.(*auth.Claims); ok {
+		return tokenClaims, true
+	}
+	return nil, false
+}
+
 // GetUserEmail extracts user email from the Gin context
 func GetUserEmail(c *gin.Context) (string, bool) {
 	email, exists := c.Get("user_email")
@@ -87,3 +115,29 @@ func GetUserEmail(c *gin.Context) (string, bool) {
 	}
 	return "", false
 }
+
+// GetUserRole extracts user role from the Gin context
+func GetUserRole(c *gin.Context) (string, bool) {
+	role, exists := c.Get("user_role")
+	if !exists {
+		return "", false
+	}
+	if roleStr, ok := role.(string); ok {
+		return roleStr, true
+	}
+	return "", false
+}
+
+// IsSandbox reports whether the request was authenticated with a sandbox token
+func IsSandbox(c *gin.Context) bool {
+	return c.GetBool("sandbox")
+}
+
+// GetTenantID returns the tenant of the request's token. Tokens issued before tenants existed belong
+// to the default tenant
+func GetTenantID(c *gin.Context) uint {
+	if claims, ok := GetTokenClaims(c); ok && claims.TenantID != 0 {
+		return claims.TenantID
+	}
+	return models.DefaultTenantID
+}
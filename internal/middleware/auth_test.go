@@ -0,0 +1,73 @@
+package middleware
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/limistah/wallet-service/internal/apperrors"
+	"github.com/limistah/wallet-service/internal/auth"
+	"github.com/limistah/wallet-service/internal/dto"
+	"github.com/limistah/wallet-service/internal/models"
+	"github.com/stretchr/testify/assert"
+)
+
+// noRevocations never reports a token revoked
+type noRevocations struct{}
+
+func (noRevocations) RevokeToken(ctx context.Context, tokenID string, userID uint, expiresAt time.Time) error {
+	return nil
+}
+
+func (noRevocations) RevokeAllTokens(ctx context.Context, userID uint) error { return nil }
+
+func (noRevocations) IsRevoked(ctx context.Context, tokenID string, userID uint, issuedAt time.Time) (bool, error) {
+	return false, nil
+}
+
+func TestAuthMiddleware_ErrorResponses(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	jwtService := auth.NewJWTService("test-secret", "wallet-service")
+	userToken, err := jwtService.GenerateToken(2, "user@example.com", string(models.UserRoleUser), false)
+	assert.NoError(t, err)
+
+	router := gin.New()
+	router.Use(ErrorHandler())
+	router.GET("/admin/users", AuthMiddleware(jwtService, noRevocations{}), RequireRole(models.UserRoleAdmin), func(c *gin.Context) {
+		c.Status(http.StatusOK)
+	})
+
+	tests := []struct {
+		name          string
+		authorization string
+		status        int
+		code          string
+	}{
+		{"missing header", "", http.StatusUnauthorized, apperrors.CodeUnauthenticated},
+		{"not a bearer token", "Basic dXNlcjpwYXNz", http.StatusUnauthorized, apperrors.CodeInvalidToken},
+		{"invalid token", "Bearer not-a-token", http.StatusUnauthorized, apperrors.CodeInvalidToken},
+		{"role not allowed", "Bearer " + userToken, http.StatusForbidden, apperrors.CodeForbidden},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest("GET", "/admin/users", nil)
+			if tt.authorization != "" {
+				req.Header.Set("Authorization", tt.authorization)
+			}
+			resp := httptest.NewRecorder()
+			router.ServeHTTP(resp, req)
+
+			assert.Equal(t, tt.status, resp.Code)
+			var body dto.ErrorResponse
+			assert.NoError(t, json.Unmarshal(resp.Body.Bytes(), &body))
+			assert.False(t, body.Success)
+			assert.Equal(t, tt.code, body.Code)
+			assert.NotEmpty(t, body.Message)
+			assert.NotEmpty(t, body.Error)
+		})
+	}
+}
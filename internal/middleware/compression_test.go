@@ -0,0 +1,60 @@
+package middleware
+
+import (
+	"compress/gzip"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCompress(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	large := strings.Repeat("transaction ", 200)
+	router := gin.New()
+	router.Use(Compress(1024))
+	router.Use(ErrorHandler())
+	router.GET("/transactions", func(c *gin.Context) { c.JSON(http.StatusOK, gin.H{"data": large}) })
+	router.GET("/balance", func(c *gin.Context) { c.JSON(http.StatusOK, gin.H{"balance": "10.00"}) })
+	router.GET("/statement.pdf", func(c *gin.Context) { c.Data(http.StatusOK, "application/pdf", []byte(large)) })
+	router.GET("/failing", func(c *gin.Context) { c.Error(errors.New(large)) })
+
+	get := func(path, acceptEncoding string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodGet, path, nil)
+		req.Header.Set("Accept-Encoding", acceptEncoding)
+		resp := httptest.NewRecorder()
+		router.ServeHTTP(resp, req)
+		return resp
+	}
+
+	resp := get("/transactions", "br;q=1.0, gzip;q=0.8")
+	require.Equal(t, "gzip", resp.Header().Get("Content-Encoding"))
+	assert.Equal(t, "Accept-Encoding", resp.Header().Get("Vary"))
+	reader, err := gzip.NewReader(resp.Body)
+	require.NoError(t, err)
+	body, err := io.ReadAll(reader)
+	require.NoError(t, err)
+	assert.Contains(t, string(body), large)
+
+	resp = get("/balance", "gzip")
+	assert.Empty(t, resp.Header().Get("Content-Encoding"), "small responses are sent as they are")
+	assert.JSONEq(t, `{"balance":"10.00"}`, resp.Body.String())
+
+	resp = get("/statement.pdf", "gzip")
+	assert.Empty(t, resp.Header().Get("Content-Encoding"), "already compressed types are sent as they are")
+
+	resp = get("/transactions", "gzip;q=0")
+	assert.Empty(t, resp.Header().Get("Content-Encoding"))
+	assert.Contains(t, resp.Body.String(), large)
+
+	resp = get("/failing", "gzip")
+	assert.Equal(t, http.StatusInternalServerError, resp.Code)
+	assert.Equal(t, "gzip", resp.Header().Get("Content-Encoding"), "errors written after the handler are compressed too")
+}
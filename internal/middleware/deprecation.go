@@ -0,0 +1,36 @@
+package middleware
+
+import (
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Deprecation describes the retirement of an endpoint
+type Deprecation struct {
+	// DeprecatedAt is when the endpoint was deprecated; the zero time leaves it undeprecated
+	DeprecatedAt time.Time
+	// Sunset is when the endpoint stops responding; the zero time means no date is planned yet
+	Sunset time.Time
+	// Successor is the path of the endpoint replacing it, if any
+	Successor string
+}
+
+// Deprecated announces the retirement of an endpoint to clients with the Deprecation (RFC 9745) and
+// Sunset (RFC 8594) headers, and links its successor with rel="successor-version"
+func Deprecated(deprecation Deprecation) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if !deprecation.DeprecatedAt.IsZero() {
+			c.Header("Deprecation", fmt.Sprintf("@%d", deprecation.DeprecatedAt.Unix()))
+			if !deprecation.Sunset.IsZero() {
+				c.Header("Sunset", deprecation.Sunset.UTC().Format(http.TimeFormat))
+			}
+			if deprecation.Successor != "" {
+				c.Header("Link", fmt.Sprintf(`<%s>; rel="successor-version"`, deprecation.Successor))
+			}
+		}
+		c.Next()
+	}
+}
@@ -0,0 +1,41 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDeprecated(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	deprecatedAt := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	sunset := time.Date(2026, 7, 1, 0, 0, 0, 0, time.UTC)
+
+	router := gin.New()
+	router.GET("/api/v1/wallets/me", Deprecated(Deprecation{
+		DeprecatedAt: deprecatedAt,
+		Sunset:       sunset,
+		Successor:    "/api/v2/wallets/me",
+	}), func(c *gin.Context) { c.Status(http.StatusOK) })
+	router.GET("/api/v1/wallets/me/transactions", Deprecated(Deprecation{}), func(c *gin.Context) { c.Status(http.StatusOK) })
+
+	req, _ := http.NewRequest("GET", "/api/v1/wallets/me", nil)
+	resp := httptest.NewRecorder()
+	router.ServeHTTP(resp, req)
+
+	assert.Equal(t, "@1767225600", resp.Header().Get("Deprecation"))
+	assert.Equal(t, "Wed, 01 Jul 2026 00:00:00 GMT", resp.Header().Get("Sunset"))
+	assert.Equal(t, `</api/v2/wallets/me>; rel="successor-version"`, resp.Header().Get("Link"))
+
+	req, _ = http.NewRequest("GET", "/api/v1/wallets/me/transactions", nil)
+	resp = httptest.NewRecorder()
+	router.ServeHTTP(resp, req)
+
+	assert.Empty(t, resp.Header().Get("Deprecation"), "endpoints are not deprecated until a date is configured")
+	assert.Empty(t, resp.Header().Get("Sunset"))
+}
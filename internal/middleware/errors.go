@@ -0,0 +1,123 @@
+package middleware
+
+import (
+	"errors"
+	"log"
+	"net/http"
+	"reflect"
+	"strings"
+	"sync"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gin-gonic/gin/binding"
+	"github.com/go-playground/validator/v10"
+	"github.com/limistah/wallet-service/internal/apperrors"
+	"github.com/limistah/wallet-service/internal/dto"
+	"github.com/limistah/wallet-service/internal/i18n"
+)
+
+// useJSONFieldNames makes validation errors name fields as clients send them
+var useJSONFieldNames sync.Once
+
+// ErrorHandler writes the error a handler recorded with c.Error as an ErrorResponse, so every endpoint
+// reports domain errors with the same status codes and bodies. The message is translated into the
+// language of the request: validation errors describe the first invalid field, and requests breaking
+// the API documentation also list every violation; domain errors use the "error.<CODE>" message of
+// their code, and other errors the message key the handler attached:
+//
+//	c.Error(err).SetMeta("wallet.withdraw_failed")
+func ErrorHandler() gin.HandlerFunc {
+	useJSONFieldNames.Do(func() {
+		if v, ok := binding.Validator.Engine().(*validator.Validate); ok {
+			v.RegisterTagNameFunc(jsonFieldName)
+		}
+	})
+
+	return func(c *gin.Context) {
+		c.Next()
+
+		if len(c.Errors) == 0 || c.Writer.Written() {
+			return
+		}
+
+		last := c.Errors.Last()
+		err := last.Err
+		status := apperrors.HTTPStatus(err)
+		code := apperrors.CodeOf(err)
+
+		message := errorMessage(c, err, code, last.Meta)
+		if message == "" {
+			message = http.StatusText(status)
+		}
+		if status >= http.StatusInternalServerError {
+			log.Printf("%s %s failed: %v", c.Request.Method, c.FullPath(), err)
+		}
+
+		c.JSON(status, dto.ErrorResponse{
+			Success:    false,
+			Message:    message,
+			Error:      err.Error(),
+			Code:       code,
+			Violations: violationResponses(c, err),
+		})
+	}
+}
+
+// violationResponses describes the constraints of the API documentation a refused request breaks, or
+// returns nil for other errors
+func violationResponses(c *gin.Context, err error) []dto.ViolationResponse {
+	var invalid *invalidRequest
+	if !errors.As(err, &invalid) {
+		return nil
+	}
+	responses := make([]dto.ViolationResponse, len(invalid.violations))
+	for i, violation := range invalid.violations {
+		responses[i] = dto.ViolationResponse{
+			In:      violation.In,
+			Field:   violation.Field,
+			Rule:    violation.Rule,
+			Message: validationMessage(c, violation.Rule, violation.Field, violation.Param),
+		}
+	}
+	return responses
+}
+
+// errorMessage returns the translated message for an error, or an empty string when there is none
+func errorMessage(c *gin.Context, err error, code string, meta interface{}) string {
+	var validationErrors validator.ValidationErrors
+	if errors.As(err, &validationErrors) && len(validationErrors) > 0 {
+		field := validationErrors[0]
+		return validationMessage(c, field.Tag(), field.Field(), field.Param())
+	}
+	var invalid *invalidRequest
+	if errors.As(err, &invalid) {
+		violation := invalid.violations[0]
+		return validationMessage(c, violation.Rule, violation.Field, violation.Param)
+	}
+
+	if code != "" && i18n.Has("error."+code) {
+		return Translate(c, "error."+code)
+	}
+	if key, ok := meta.(string); ok && key != "" {
+		return Translate(c, key)
+	}
+	return ""
+}
+
+// validationMessage returns the translated message for a field breaking a validation rule
+func validationMessage(c *gin.Context, rule, field, param string) string {
+	key := "validation." + rule
+	if !i18n.Has(key) {
+		key = "validation.invalid"
+	}
+	return Translate(c, key, "field", field, "param", param)
+}
+
+// jsonFieldName returns the name of a request field in the JSON body
+func jsonFieldName(field reflect.StructField) string {
+	name := strings.SplitN(field.Tag.Get("json"), ",", 2)[0]
+	if name == "" || name == "-" {
+		return field.Name
+	}
+	return name
+}
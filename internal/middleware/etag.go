@@ -0,0 +1,35 @@
+package middleware
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// NotModified sets the ETag of the response and answers 304 Not Modified when the If-None-Match
+// header of the request already names it, in which case the handler must not write a body. Clients
+// are told to revalidate every time, so polling costs a 304 until the resource changes
+func NotModified(c *gin.Context, etag string) bool {
+	c.Header("ETag", etag)
+	c.Header("Cache-Control", "private, no-cache")
+
+	for _, candidate := range strings.Split(c.GetHeader("If-None-Match"), ",") {
+		candidate = strings.TrimSpace(candidate)
+		// GET requests compare weakly, so W/ prefixes are ignored
+		if candidate == "*" || strings.TrimPrefix(candidate, "W/") == strings.TrimPrefix(etag, "W/") {
+			c.Status(http.StatusNotModified)
+			return true
+		}
+	}
+	return false
+}
+
+// VariantETag derives the ETag of a response that also depends on variant, such as the display
+// currency of the user, from the ETag of the resource it shows
+func VariantETag(etag, variant string) string {
+	if variant == "" {
+		return etag
+	}
+	return strings.TrimSuffix(etag, `"`) + "-" + variant + `"`
+}
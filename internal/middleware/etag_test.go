@@ -0,0 +1,47 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNotModified(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	router := gin.New()
+	router.GET("/wallets/me", func(c *gin.Context) {
+		if NotModified(c, `W/"1-3-abc"`) {
+			return
+		}
+		c.JSON(http.StatusOK, gin.H{"balance": "10.00"})
+	})
+
+	get := func(ifNoneMatch string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodGet, "/wallets/me", nil)
+		if ifNoneMatch != "" {
+			req.Header.Set("If-None-Match", ifNoneMatch)
+		}
+		resp := httptest.NewRecorder()
+		router.ServeHTTP(resp, req)
+		return resp
+	}
+
+	resp := get("")
+	assert.Equal(t, http.StatusOK, resp.Code)
+	assert.Equal(t, `W/"1-3-abc"`, resp.Header().Get("ETag"))
+	assert.Equal(t, "private, no-cache", resp.Header().Get("Cache-Control"))
+
+	for _, header := range []string{`W/"1-3-abc"`, `"1-3-abc"`, `"0-1-xyz", W/"1-3-abc"`, "*"} {
+		resp = get(header)
+		assert.Equal(t, http.StatusNotModified, resp.Code, header)
+		assert.Empty(t, resp.Body.String(), header)
+	}
+
+	resp = get(`W/"1-2-abc"`)
+	assert.Equal(t, http.StatusOK, resp.Code, "a wallet that changed is sent again")
+	assert.JSONEq(t, `{"balance":"10.00"}`, resp.Body.String())
+}
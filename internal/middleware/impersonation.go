@@ -0,0 +1,82 @@
+package middleware
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/limistah/wallet-service/internal/auth"
+	"github.com/limistah/wallet-service/internal/models"
+	"github.com/limistah/wallet-service/internal/usecases"
+)
+
+// logoutPath stays open to read-only impersonation tokens so staff can end the impersonation early
+const logoutPath = "/api/v1/auth/logout"
+
+// ImpersonationAudit creates a middleware function that records every request made with an
+// impersonation token in the audit log, with the staff member as actor, whether it succeeded or not.
+// It must be mounted on the router, before ErrorHandler so the status of refused requests is final
+// when it is recorded.
+func ImpersonationAudit(auditUseCase usecases.AuditUseCase) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		c.Next()
+
+		impersonator, exists := GetImpersonator(c)
+		if !exists {
+			return
+		}
+		userID, _ := GetUserID(c)
+		claims, _ := GetTokenClaims(c)
+
+		metadata, _ := json.Marshal(map[string]interface{}{
+			"impersonated_user_id": userID,
+			"token_id":             claims.ID,
+			"reason":               impersonator.Reason,
+			"read_only":            impersonator.ReadOnly,
+		})
+		entry := &models.AuditLog{
+			ActorID:      impersonator.ID,
+			ActorEmail:   impersonator.Email,
+			ActorRole:    impersonator.Role,
+			Action:       fmt.Sprintf("IMPERSONATE %s %s", c.Request.Method, c.FullPath()),
+			ResourceType: "users",
+			ResourceID:   strconv.FormatUint(uint64(userID), 10),
+			Method:       c.Request.Method,
+			Path:         c.Request.URL.RequestURI(),
+			StatusCode:   c.Writer.Status(),
+			IPAddress:    c.ClientIP(),
+			UserAgent:    c.Request.UserAgent(),
+			Metadata:     string(metadata),
+		}
+
+		if err := auditUseCase.Record(context.WithoutCancel(c.Request.Context()), entry); err != nil {
+			log.Printf("Failed to record impersonation audit log for %s: %v", entry.Action, err)
+		}
+	}
+}
+
+// GetImpersonator extracts the staff member acting as the user from the Gin context; it is only set
+// for requests made with an impersonation token
+func GetImpersonator(c *gin.Context) (*auth.Impersonator, bool) {
+	impersonator, exists := c.Get("impersonator")
+	if !exists {
+		return nil, false
+	}
+	if tokenImpersonator, ok := impersonator.(*auth.Impersonator); ok {
+		return tokenImpersonator, true
+	}
+	return nil, false
+}
+
+// allowedReadOnly reports whether a read-only impersonation token may make the request
+func allowedReadOnly(c *gin.Context) bool {
+	switch c.Request.Method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions:
+		return true
+	}
+	return c.FullPath() == logoutPath
+}
@@ -0,0 +1,29 @@
+package middleware
+
+import (
+	"fmt"
+
+	"github.com/gin-gonic/gin"
+	"github.com/limistah/wallet-service/internal/apperrors"
+	"github.com/limistah/wallet-service/internal/usecases"
+)
+
+// IPAllowlist refuses requests from client addresses outside the allowlist with 403. The address is
+// taken from X-Forwarded-For only when the request comes through a trusted proxy, see
+// SERVER_TRUSTED_PROXIES
+func IPAllowlist(allowlist usecases.IPAllowlistUseCase) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		allowed, err := allowlist.Allows(c.Request.Context(), c.ClientIP())
+		if err != nil {
+			c.Error(fmt.Errorf("failed to check IP allowlist: %w", err))
+			c.Abort()
+			return
+		}
+		if !allowed {
+			c.Error(apperrors.ErrIPNotAllowed)
+			c.Abort()
+			return
+		}
+		c.Next()
+	}
+}
@@ -0,0 +1,30 @@
+package middleware
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/limistah/wallet-service/internal/i18n"
+)
+
+// Locale picks the language of response messages from the Accept-Language header and reports it in
+// Content-Language; clients that accept none of the supported languages get English
+func Locale() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		language := i18n.Match(c.GetHeader("Accept-Language"))
+		c.Set("locale", language)
+		c.Header("Content-Language", language)
+		c.Next()
+	}
+}
+
+// GetLocale returns the language chosen for the request
+func GetLocale(c *gin.Context) string {
+	if language := c.GetString("locale"); language != "" {
+		return language
+	}
+	return i18n.DefaultLanguage
+}
+
+// Translate returns the message for key in the language of the request
+func Translate(c *gin.Context, key string, args ...string) string {
+	return i18n.T(GetLocale(c), key, args...)
+}
@@ -0,0 +1,66 @@
+package middleware
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/limistah/wallet-service/internal/apperrors"
+	"github.com/limistah/wallet-service/internal/openapi"
+)
+
+// invalidRequest is a request refused for breaking constraints of the API documentation
+type invalidRequest struct {
+	violations []openapi.Violation
+}
+
+func (e *invalidRequest) Error() string {
+	described := make([]string, len(e.violations))
+	for i, violation := range e.violations {
+		described[i] = fmt.Sprintf("%s %s: %s", violation.In, violation.Field, violation.Rule)
+		if violation.Param != "" {
+			described[i] += " " + violation.Param
+		}
+	}
+	return "request does not match the API documentation: " + strings.Join(described, "; ")
+}
+
+func (e *invalidRequest) Unwrap() error { return apperrors.ErrValidation }
+
+// ValidateRequests refuses requests to documented operations that break the constraints of the API
+// documentation with 400, listing every violation, before they reach the handlers. Requests to
+// operations the documentation does not describe pass through unchecked
+func ValidateRequests(validator *openapi.Validator) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		route := c.FullPath()
+		if route == "" || !validator.Documented(c.Request.Method, route) {
+			c.Next()
+			return
+		}
+
+		var body []byte
+		if c.Request.Body != nil && c.Request.Body != http.NoBody {
+			var err error
+			if body, err = io.ReadAll(c.Request.Body); err != nil {
+				c.Error(err)
+				c.Abort()
+				return
+			}
+			c.Request.Body = io.NopCloser(bytes.NewReader(body))
+		}
+
+		params := make(map[string]string, len(c.Params))
+		for _, param := range c.Params {
+			params[param.Key] = strings.TrimPrefix(param.Value, "/")
+		}
+		if violations := validator.Validate(c.Request, route, params, body); len(violations) > 0 {
+			c.Error(&invalidRequest{violations: violations})
+			c.Abort()
+			return
+		}
+		c.Next()
+	}
+}
@@ -0,0 +1,81 @@
+package middleware
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/limistah/wallet-service/internal/apperrors"
+	"github.com/limistah/wallet-service/internal/dto"
+	"github.com/limistah/wallet-service/internal/openapi"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const testAPIDocument = `{
+	"swagger": "2.0",
+	"basePath": "/api/v1",
+	"paths": {
+		"/wallets/me/fund": {
+			"post": {
+				"parameters": [{"in": "body", "name": "request", "required": true, "schema": {"$ref": "#/definitions/FundWalletRequest"}}]
+			}
+		}
+	},
+	"definitions": {
+		"FundWalletRequest": {
+			"type": "object",
+			"required": ["amount", "reference"],
+			"properties": {
+				"amount": {"type": "number", "minimum": 1},
+				"reference": {"type": "string"},
+				"description": {"type": "string", "maxLength": 10}
+			}
+		}
+	}
+}`
+
+func TestValidateRequests(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	validator, err := openapi.Load([]byte(testAPIDocument))
+	require.NoError(t, err)
+
+	router := gin.New()
+	router.Use(ErrorHandler())
+	router.Use(ValidateRequests(validator))
+	router.POST("/api/v1/wallets/me/fund", func(c *gin.Context) {
+		body, _ := io.ReadAll(c.Request.Body)
+		c.String(http.StatusOK, string(body))
+	})
+	router.POST("/api/v1/wallets/me/withdraw", func(c *gin.Context) { c.Status(http.StatusOK) })
+
+	resp := httptest.NewRecorder()
+	router.ServeHTTP(resp, httptest.NewRequest("POST", "/api/v1/wallets/me/fund", strings.NewReader(`{"amount":"0.5","description":"far too long"}`)))
+
+	assert.Equal(t, http.StatusBadRequest, resp.Code)
+	var body dto.ErrorResponse
+	require.NoError(t, json.Unmarshal(resp.Body.Bytes(), &body))
+	assert.Equal(t, apperrors.CodeValidation, body.Code)
+	assert.Equal(t, "reference is required", body.Message)
+	assert.Equal(t, []dto.ViolationResponse{
+		{In: "body", Field: "reference", Rule: "required", Message: "reference is required"},
+		{In: "body", Field: "amount", Rule: "min", Message: "amount must be at least 1"},
+		{In: "body", Field: "description", Rule: "max", Message: "description must be at most 10"},
+	}, body.Violations)
+
+	valid := `{"amount":25,"reference":"REF-1"}`
+	resp = httptest.NewRecorder()
+	router.ServeHTTP(resp, httptest.NewRequest("POST", "/api/v1/wallets/me/fund", strings.NewReader(valid)))
+
+	assert.Equal(t, http.StatusOK, resp.Code)
+	assert.Equal(t, valid, resp.Body.String(), "the handler should still read the body")
+
+	resp = httptest.NewRecorder()
+	router.ServeHTTP(resp, httptest.NewRequest("POST", "/api/v1/wallets/me/withdraw", strings.NewReader(`{}`)))
+
+	assert.Equal(t, http.StatusOK, resp.Code, "undocumented operations should not be checked")
+}
@@ -0,0 +1,29 @@
+package middleware
+
+import (
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/limistah/wallet-service/internal/fraud"
+)
+
+// RequestOrigin records the country reported for the client in countryHeader, typically set by a CDN
+// or load balancer in front of the service
+func RequestOrigin(countryHeader string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if countryHeader != "" {
+			if country := strings.ToUpper(strings.TrimSpace(c.GetHeader(countryHeader))); len(country) == 2 {
+				c.Set("origin_country", country)
+			}
+		}
+		c.Next()
+	}
+}
+
+// GetOrigin returns where the request came from for fraud screening
+func GetOrigin(c *gin.Context) *fraud.Origin {
+	return &fraud.Origin{
+		IPAddress: c.ClientIP(),
+		Country:   c.GetString("origin_country"),
+	}
+}
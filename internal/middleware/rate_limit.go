@@ -0,0 +1,87 @@
+package middleware
+
+import (
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/limistah/wallet-service/internal/apperrors"
+)
+
+// RateLimit lets each client address send limit requests per window and refuses the rest with 429.
+// Every response tells the client where it stands, so it can throttle itself: X-RateLimit-Limit,
+// X-RateLimit-Remaining and X-RateLimit-Reset, the Unix time the window resets at. Refused requests
+// also get Retry-After in seconds. Build it once and share it between routers, so a client counts
+// against one window whichever tenant it calls. Health checks are not counted
+func RateLimit(limit int, window time.Duration) gin.HandlerFunc {
+	limiter := &rateLimiter{limit: limit, window: window, clients: make(map[string]*rateWindow)}
+	return func(c *gin.Context) {
+		if limit <= 0 || c.Request.URL.Path == "/health" {
+			c.Next()
+			return
+		}
+
+		now := time.Now()
+		remaining, reset, allowed := limiter.take(c.ClientIP(), now)
+		header := c.Writer.Header()
+		header.Set("X-RateLimit-Limit", strconv.Itoa(limit))
+		header.Set("X-RateLimit-Remaining", strconv.Itoa(remaining))
+		header.Set("X-RateLimit-Reset", strconv.FormatInt(ceilSeconds(reset.Sub(time.Unix(0, 0))), 10))
+		if !allowed {
+			header.Set("Retry-After", strconv.FormatInt(max(ceilSeconds(reset.Sub(now)), 1), 10))
+			c.Error(apperrors.ErrRateLimited)
+			c.Abort()
+			return
+		}
+		c.Next()
+	}
+}
+
+// ceilSeconds rounds d up to whole seconds, so clients never retry before the window resets
+func ceilSeconds(d time.Duration) int64 {
+	return int64((d + time.Second - 1) / time.Second)
+}
+
+// rateLimiter counts requests per client in fixed windows
+type rateLimiter struct {
+	mu      sync.Mutex
+	limit   int
+	window  time.Duration
+	clients map[string]*rateWindow
+	// sweepAt is when windows that ended are next dropped, so clients that went away are forgotten
+	sweepAt time.Time
+}
+
+type rateWindow struct {
+	start time.Time
+	count int
+}
+
+// take counts a request of client and returns how many it has left, when its window resets and
+// whether the request is allowed
+func (l *rateLimiter) take(client string, now time.Time) (remaining int, reset time.Time, allowed bool) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if !now.Before(l.sweepAt) {
+		for key, w := range l.clients {
+			if !now.Before(w.start.Add(l.window)) {
+				delete(l.clients, key)
+			}
+		}
+		l.sweepAt = now.Add(l.window)
+	}
+
+	w, ok := l.clients[client]
+	if !ok || !now.Before(w.start.Add(l.window)) {
+		w = &rateWindow{start: now}
+		l.clients[client] = w
+	}
+	reset = w.start.Add(l.window)
+	if w.count >= l.limit {
+		return 0, reset, false
+	}
+	w.count++
+	return l.limit - w.count, reset, true
+}
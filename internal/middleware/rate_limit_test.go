@@ -0,0 +1,75 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/limistah/wallet-service/internal/apperrors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRateLimit(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	router := gin.New()
+	router.Use(ErrorHandler())
+	router.Use(RateLimit(2, time.Minute))
+	router.GET("/wallets/me", func(c *gin.Context) { c.JSON(http.StatusOK, gin.H{"balance": "10.00"}) })
+	router.GET("/health", func(c *gin.Context) { c.Status(http.StatusOK) })
+
+	get := func(path, address string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodGet, path, nil)
+		req.RemoteAddr = address + ":1234"
+		resp := httptest.NewRecorder()
+		router.ServeHTTP(resp, req)
+		return resp
+	}
+
+	resp := get("/wallets/me", "10.0.0.1")
+	assert.Equal(t, http.StatusOK, resp.Code)
+	assert.Equal(t, "2", resp.Header().Get("X-RateLimit-Limit"))
+	assert.Equal(t, "1", resp.Header().Get("X-RateLimit-Remaining"))
+	reset, err := strconv.ParseInt(resp.Header().Get("X-RateLimit-Reset"), 10, 64)
+	require.NoError(t, err)
+	assert.InDelta(t, time.Now().Add(time.Minute).Unix(), reset, 2)
+
+	resp = get("/wallets/me", "10.0.0.1")
+	assert.Equal(t, http.StatusOK, resp.Code)
+	assert.Equal(t, "0", resp.Header().Get("X-RateLimit-Remaining"))
+
+	resp = get("/wallets/me", "10.0.0.1")
+	assert.Equal(t, http.StatusTooManyRequests, resp.Code)
+	assert.Contains(t, resp.Body.String(), apperrors.CodeRateLimited)
+	assert.Equal(t, "0", resp.Header().Get("X-RateLimit-Remaining"))
+	retryAfter, err := strconv.Atoi(resp.Header().Get("Retry-After"))
+	require.NoError(t, err)
+	assert.True(t, retryAfter > 0 && retryAfter <= 60, "Retry-After = %d", retryAfter)
+
+	assert.Equal(t, http.StatusOK, get("/wallets/me", "10.0.0.2").Code, "other clients have windows of their own")
+	resp = get("/health", "10.0.0.1")
+	assert.Equal(t, http.StatusOK, resp.Code, "health checks are not limited")
+	assert.Empty(t, resp.Header().Get("X-RateLimit-Limit"))
+}
+
+func TestRateLimiter_WindowResets(t *testing.T) {
+	limiter := &rateLimiter{limit: 1, window: time.Minute, clients: make(map[string]*rateWindow)}
+	start := time.Now()
+
+	_, _, allowed := limiter.take("10.0.0.1", start)
+	assert.True(t, allowed)
+	_, reset, allowed := limiter.take("10.0.0.1", start.Add(30*time.Second))
+	assert.False(t, allowed)
+	assert.Equal(t, start.Add(time.Minute), reset)
+
+	remaining, _, allowed := limiter.take("10.0.0.1", start.Add(time.Minute))
+	assert.True(t, allowed, "a new window starts once the last one ended")
+	assert.Equal(t, 0, remaining)
+
+	limiter.take("10.0.0.2", start.Add(3*time.Minute))
+	assert.Len(t, limiter.clients, 1, "windows that ended are dropped")
+}
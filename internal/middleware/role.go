@@ -0,0 +1,43 @@
+package middleware
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/limistah/wallet-service/internal/apperrors"
+	"github.com/limistah/wallet-service/internal/models"
+)
+
+// RequireRole creates a middleware function that only allows users with one of the given roles.
+// It must be mounted after AuthMiddleware.
+func RequireRole(roles ...models.UserRole) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		role, exists := GetUserRole(c)
+		if !exists {
+			c.Error(apperrors.ErrUnauthenticated.Withf("user role not found in context"))
+			c.Abort()
+			return
+		}
+
+		for _, allowed := range roles {
+			if role == string(allowed) {
+				c.Next()
+				return
+			}
+		}
+
+		c.Error(apperrors.ErrForbidden.Withf("access denied"))
+		c.Abort()
+	}
+}
+
+// RequireDefaultTenant only allows users of the default tenant, who operate the deployment. It must
+// be mounted after AuthMiddleware.
+func RequireDefaultTenant() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if GetTenantID(c) != models.DefaultTenantID {
+			c.Error(apperrors.ErrForbidden.Withf("only the default tenant can use this endpoint"))
+			c.Abort()
+			return
+		}
+		c.Next()
+	}
+}
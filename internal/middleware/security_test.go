@@ -0,0 +1,99 @@
+package middleware
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/limistah/wallet-service/internal/apperrors"
+	"github.com/limistah/wallet-service/internal/dto"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSecurityHeaders(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	router := gin.New()
+	router.Use(SecurityHeaders(365 * 24 * time.Hour))
+	router.GET("/api/v1/wallets/me", func(c *gin.Context) { c.Status(http.StatusOK) })
+	router.GET("/swagger/*any", func(c *gin.Context) { c.Status(http.StatusOK) })
+
+	resp := httptest.NewRecorder()
+	router.ServeHTTP(resp, httptest.NewRequest("GET", "/api/v1/wallets/me", nil))
+
+	assert.Equal(t, "max-age=31536000; includeSubDomains", resp.Header().Get("Strict-Transport-Security"))
+	assert.Equal(t, "nosniff", resp.Header().Get("X-Content-Type-Options"))
+	assert.Equal(t, "DENY", resp.Header().Get("X-Frame-Options"))
+	assert.Equal(t, apiContentSecurityPolicy, resp.Header().Get("Content-Security-Policy"))
+
+	resp = httptest.NewRecorder()
+	router.ServeHTTP(resp, httptest.NewRequest("GET", "/swagger/index.html", nil))
+
+	assert.Equal(t, swaggerContentSecurityPolicy, resp.Header().Get("Content-Security-Policy"))
+}
+
+func TestSecurityHeaders_WithoutHSTS(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	router := gin.New()
+	router.Use(SecurityHeaders(0))
+	router.GET("/health", func(c *gin.Context) { c.Status(http.StatusOK) })
+
+	resp := httptest.NewRecorder()
+	router.ServeHTTP(resp, httptest.NewRequest("GET", "/health", nil))
+
+	assert.Empty(t, resp.Header().Get("Strict-Transport-Security"))
+	assert.Equal(t, "nosniff", resp.Header().Get("X-Content-Type-Options"))
+}
+
+func TestBodyLimit(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	router := gin.New()
+	router.Use(ErrorHandler())
+	router.Use(BodyLimit(64))
+	router.POST("/wallets/me/fund", func(c *gin.Context) {
+		var req dto.FundWalletRequest
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.Error(apperrors.ErrValidation.Withf("invalid request data: %w", err)).SetMeta("request.invalid")
+			return
+		}
+		c.Status(http.StatusOK)
+	})
+
+	tests := []struct {
+		name           string
+		body           string
+		chunked        bool
+		expectedStatus int
+	}{
+		{"within the limit", `{"amount": "10.00", "reference": "REF1"}`, false, http.StatusOK},
+		{"declared length over the limit", `{"amount": "10.00", "reference": "` + strings.Repeat("A", 100) + `"}`, false, http.StatusRequestEntityTooLarge},
+		{"unknown length read past the limit", `{"amount": "10.00", "reference": "` + strings.Repeat("A", 100) + `"}`, true, http.StatusRequestEntityTooLarge},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest("POST", "/wallets/me/fund", strings.NewReader(tt.body))
+			req.Header.Set("Content-Type", "application/json")
+			if tt.chunked {
+				req.ContentLength = -1
+			}
+			resp := httptest.NewRecorder()
+
+			router.ServeHTTP(resp, req)
+
+			assert.Equal(t, tt.expectedStatus, resp.Code)
+			if tt.expectedStatus == http.StatusRequestEntityTooLarge {
+				var response dto.ErrorResponse
+				assert.NoError(t, json.Unmarshal(resp.Body.Bytes(), &response))
+				assert.Equal(t, apperrors.CodeRequestTooLarge, response.Code)
+				assert.Equal(t, "Request body is too large", response.Message)
+			}
+		})
+	}
+}
@@ -0,0 +1,53 @@
+package models
+
+import "time"
+
+// AccountStatementFormat is the file format an account statement is rendered in
+type AccountStatementFormat string
+
+const (
+	AccountStatementFormatCSV AccountStatementFormat = "CSV"
+	AccountStatementFormatPDF AccountStatementFormat = "PDF"
+)
+
+// AccountStatementStatus represents where an account statement is in its generation
+type AccountStatementStatus string
+
+const (
+	AccountStatementStatusPending AccountStatementStatus = "PENDING" // Waiting for a worker to render it
+	AccountStatementStatusReady   AccountStatementStatus = "READY"
+	AccountStatementStatusFailed  AccountStatementStatus = "FAILED"
+)
+
+// AccountStatement is a statement of a wallet's transactions over a period, requested by its owner and
+// rendered in the background. The rendered file is kept in blob storage under BlobKey
+type AccountStatement struct {
+	ID               uint                   `json:"id" gorm:"primarykey"`
+	CreatedAt        time.Time              `json:"created_at"`
+	UpdatedAt        time.Time              `json:"updated_at"`
+	UserID           uint                   `json:"user_id" gorm:"not null;index"`
+	WalletID         uint                   `json:"wallet_id" gorm:"not null;index"`
+	PeriodStart      time.Time              `json:"period_start" gorm:"not null"`
+	PeriodEnd        time.Time              `json:"period_end" gorm:"not null"` // Exclusive
+	Format           AccountStatementFormat `json:"format" gorm:"type:varchar(20);check:format IN ('CSV','PDF');not null"`
+	Status           AccountStatementStatus `json:"status" gorm:"type:varchar(20);check:status IN ('PENDING','READY','FAILED');not null;default:'PENDING';index"`
+	BlobKey          string                 `json:"-" gorm:"type:varchar(255)"`
+	FileSize         int64                  `json:"file_size,omitempty"`
+	TransactionCount int                    `json:"transaction_count"`
+	FailureReason    string                 `json:"failure_reason,omitempty" gorm:"type:text"`
+	CompletedAt      *time.Time             `json:"completed_at,omitempty"`
+}
+
+// TableName overrides the table name used by AccountStatement
+func (AccountStatement) TableName() string {
+	return "account_statements"
+}
+
+// FileName is the name the rendered statement is downloaded as
+func (s *AccountStatement) FileName() string {
+	extension := "csv"
+	if s.Format == AccountStatementFormatPDF {
+		extension = "pdf"
+	}
+	return "statement-" + s.PeriodStart.Format("20060102") + "-" + s.PeriodEnd.AddDate(0, 0, -1).Format("20060102") + "." + extension
+}
@@ -0,0 +1,52 @@
+package models
+
+import (
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+// AMLCaseStatus represents the outcome of a manual AML review
+type AMLCaseStatus string
+
+const (
+	AMLCaseStatusPending  AMLCaseStatus = "PENDING"
+	AMLCaseStatusApproved AMLCaseStatus = "APPROVED"
+	AMLCaseStatusRejected AMLCaseStatus = "REJECTED"
+	AMLCaseStatusExpired  AMLCaseStatus = "EXPIRED" // Not decided before the pending transaction TTL ran out
+)
+
+// AMLCase is a debit parked as PENDING by AML screening. The held amount and fee stay debited
+// from the wallet until a compliance officer approves the debit or rejects it and refunds the wallet
+type AMLCase struct {
+	ID                   uint               `json:"id" gorm:"primarykey"`
+	CreatedAt            time.Time          `json:"created_at"`
+	UpdatedAt            time.Time          `json:"updated_at"`
+	TransactionID        uint               `json:"transaction_id" gorm:"not null;uniqueIndex"` // The held debit
+	WalletID             uint               `json:"wallet_id" gorm:"not null;index"`
+	Purpose              TransactionPurpose `json:"purpose" gorm:"type:varchar(20);check:purpose IN ('WITHDRAWAL','TRANSFER');not null"`
+	CounterpartyWalletID *uint              `json:"counterparty_wallet_id,omitempty"`
+	Amount               decimal.Decimal    `json:"amount" gorm:"type:decimal(15,2);not null"`
+	Fee                  decimal.Decimal    `json:"fee" gorm:"type:decimal(15,2);not null;default:0.00"`
+	Currency             string             `json:"currency" gorm:"type:varchar(3);not null"`
+	Screener             string             `json:"screener" gorm:"type:varchar(50);not null"`
+	CaseReference        string             `json:"case_reference,omitempty" gorm:"type:varchar(100)"` // The screener's own reference
+	Reason               string             `json:"reason" gorm:"type:text"`
+	BankAccount          BankAccount        `json:"bank_account" gorm:"embedded;embeddedPrefix:bank_"` // Payout destination of held withdrawals
+	Status               AMLCaseStatus      `json:"status" gorm:"type:varchar(20);check:status IN ('PENDING','APPROVED','REJECTED','EXPIRED');not null;default:'PENDING';index"`
+	ReviewerID           *uint              `json:"reviewer_id,omitempty"`
+	ReviewNote           string             `json:"review_note,omitempty" gorm:"type:text"`
+	ReviewedAt           *time.Time         `json:"reviewed_at,omitempty"`
+
+	Transaction Transaction `json:"transaction,omitempty" gorm:"foreignKey:TransactionID"`
+}
+
+// TableName overrides the table name used by AMLCase
+func (AMLCase) TableName() string {
+	return "aml_cases"
+}
+
+// IsPending checks if the case is still awaiting a decision
+func (c *AMLCase) IsPending() bool {
+	return c.Status == AMLCaseStatusPending
+}
@@ -0,0 +1,89 @@
+package models
+
+import (
+	"time"
+
+	"github.com/shopspring/decimal"
+	"gorm.io/gorm"
+)
+
+// ArchivedTransaction is a transaction moved out of the live ledger once it is older than the
+// retention window. It keeps the ID and every column of the original so history reads the same
+type ArchivedTransaction struct {
+	ID                   uint               `json:"id" gorm:"primarykey;autoIncrement:false"`
+	CreatedAt            time.Time          `json:"created_at" gorm:"index:idx_archived_wallet_created,priority:2"`
+	UpdatedAt            time.Time          `json:"updated_at"`
+	DeletedAt            gorm.DeletedAt     `json:"deleted_at,omitempty"`
+	Reference            string             `json:"reference" gorm:"type:varchar(255);index;not null"`
+	WalletID             uint               `json:"wallet_id" gorm:"not null;index:idx_archived_wallet_created,priority:1"`
+	TransactionPurpose   TransactionPurpose `json:"transaction_purpose,omitempty" gorm:"type:varchar(20);not null;"`
+	TransactionType      TransactionType    `json:"transaction_type,omitempty" gorm:"type:varchar(10);not null;"`
+	Amount               decimal.Decimal    `json:"amount" gorm:"type:decimal(15,2);not null"`
+	BalanceBefore        decimal.Decimal    `json:"balance_before" gorm:"type:decimal(15,2);not null"`
+	BalanceAfter         decimal.Decimal    `json:"balance_after" gorm:"type:decimal(15,2);not null"`
+	Description          string             `json:"description" gorm:"type:text"`
+	Category             string             `json:"category,omitempty" gorm:"type:varchar(50)"`
+	Metadata             string             `json:"metadata" gorm:"type:json"`
+	Status               TransactionStatus  `json:"status" gorm:"type:varchar(20);check:status IN ('PENDING','PENDING_REVIEW','COMPLETED','FAILED','CANCELLED');not null"`
+	RelatedTransactionID *uint              `json:"related_transaction_id,omitempty" gorm:"index"`
+	JournalEntryID       *uint              `json:"journal_entry_id,omitempty" gorm:"index"`
+	OriginIP             string             `json:"origin_ip,omitempty" gorm:"type:varchar(45)"`
+	OriginCountry        string             `json:"origin_country,omitempty" gorm:"type:varchar(2)"`
+	ArchivedAt           time.Time          `json:"archived_at" gorm:"not null"`
+}
+
+// TableName overrides the table name used by ArchivedTransaction
+func (ArchivedTransaction) TableName() string {
+	return "archived_transactions"
+}
+
+// NewArchivedTransaction copies a transaction into its archived form
+func NewArchivedTransaction(t *Transaction, archivedAt time.Time) ArchivedTransaction {
+	return ArchivedTransaction{
+		ID:                   t.ID,
+		CreatedAt:            t.CreatedAt,
+		UpdatedAt:            t.UpdatedAt,
+		DeletedAt:            t.DeletedAt,
+		Reference:            t.Reference,
+		WalletID:             t.WalletID,
+		TransactionPurpose:   t.TransactionPurpose,
+		TransactionType:      t.TransactionType,
+		Amount:               t.Amount,
+		BalanceBefore:        t.BalanceBefore,
+		BalanceAfter:         t.BalanceAfter,
+		Description:          t.Description,
+		Category:             t.Category,
+		Metadata:             t.Metadata,
+		Status:               t.Status,
+		RelatedTransactionID: t.RelatedTransactionID,
+		JournalEntryID:       t.JournalEntryID,
+		OriginIP:             t.OriginIP,
+		OriginCountry:        t.OriginCountry,
+		ArchivedAt:           archivedAt,
+	}
+}
+
+// ToTransaction restores the transaction as it was before it was archived
+func (a *ArchivedTransaction) ToTransaction() Transaction {
+	return Transaction{
+		ID:                   a.ID,
+		CreatedAt:            a.CreatedAt,
+		UpdatedAt:            a.UpdatedAt,
+		DeletedAt:            a.DeletedAt,
+		Reference:            a.Reference,
+		WalletID:             a.WalletID,
+		TransactionPurpose:   a.TransactionPurpose,
+		TransactionType:      a.TransactionType,
+		Amount:               a.Amount,
+		BalanceBefore:        a.BalanceBefore,
+		BalanceAfter:         a.BalanceAfter,
+		Description:          a.Description,
+		Category:             a.Category,
+		Metadata:             a.Metadata,
+		Status:               a.Status,
+		RelatedTransactionID: a.RelatedTransactionID,
+		JournalEntryID:       a.JournalEntryID,
+		OriginIP:             a.OriginIP,
+		OriginCountry:        a.OriginCountry,
+	}
+}
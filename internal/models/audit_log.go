@@ -0,0 +1,28 @@
+package models
+
+import (
+	"time"
+)
+
+// AuditLog records a privileged action performed through the API
+type AuditLog struct {
+	ID           uint      `json:"id" gorm:"primarykey"`
+	CreatedAt    time.Time `json:"created_at" gorm:"index"`
+	ActorID      uint      `json:"actor_id" gorm:"not null;index"`
+	ActorEmail   string    `json:"actor_email" gorm:"type:varchar(255)"`
+	ActorRole    string    `json:"actor_role" gorm:"type:varchar(20)"`
+	Action       string    `json:"action" gorm:"type:varchar(255);not null;index"`
+	ResourceType string    `json:"resource_type" gorm:"type:varchar(50);index"`
+	ResourceID   string    `json:"resource_id" gorm:"type:varchar(100);index"`
+	Method       string    `json:"method" gorm:"type:varchar(10)"`
+	Path         string    `json:"path" gorm:"type:varchar(500)"`
+	StatusCode   int       `json:"status_code"`
+	IPAddress    string    `json:"ip_address" gorm:"type:varchar(45)"`
+	UserAgent    string    `json:"user_agent" gorm:"type:varchar(500)"`
+	Metadata     string    `json:"metadata" gorm:"type:text"`
+}
+
+// TableName overrides the table name used by AuditLog
+func (AuditLog) TableName() string {
+	return "audit_logs"
+}
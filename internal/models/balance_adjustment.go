@@ -0,0 +1,46 @@
+package models
+
+import (
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+// BalanceAdjustmentStatus represents where a manual balance adjustment is in its approval
+type BalanceAdjustmentStatus string
+
+const (
+	BalanceAdjustmentStatusPending  BalanceAdjustmentStatus = "PENDING"
+	BalanceAdjustmentStatusApproved BalanceAdjustmentStatus = "APPROVED" // Posted to the wallet
+	BalanceAdjustmentStatusRejected BalanceAdjustmentStatus = "REJECTED"
+)
+
+// BalanceAdjustment is a manual credit or debit of a wallet requested by an admin to fix its balance.
+// Nothing is posted until a second admin approves it; the posting is a journal entry between the
+// wallet and the adjustments ledger account
+type BalanceAdjustment struct {
+	ID             uint                    `json:"id" gorm:"primarykey"`
+	CreatedAt      time.Time               `json:"created_at"`
+	UpdatedAt      time.Time               `json:"updated_at"`
+	WalletID       uint                    `json:"wallet_id" gorm:"not null;index"`
+	Type           TransactionType         `json:"type" gorm:"type:varchar(10);check:type IN ('CREDIT','DEBIT');not null"` // The side of the wallet the adjustment posts to
+	Amount         decimal.Decimal         `json:"amount" gorm:"type:decimal(15,2);not null"`
+	Currency       string                  `json:"currency" gorm:"type:varchar(3);not null"`
+	Reason         string                  `json:"reason" gorm:"type:text;not null"`
+	RequestedBy    uint                    `json:"requested_by" gorm:"not null;index"`
+	Status         BalanceAdjustmentStatus `json:"status" gorm:"type:varchar(20);check:status IN ('PENDING','APPROVED','REJECTED');not null;default:'PENDING';index"`
+	ApproverID     *uint                   `json:"approver_id,omitempty"`
+	DecisionNote   string                  `json:"decision_note,omitempty" gorm:"type:text"`
+	DecidedAt      *time.Time              `json:"decided_at,omitempty"`
+	JournalEntryID *uint                   `json:"journal_entry_id,omitempty"` // The entry posted on approval
+}
+
+// TableName overrides the table name used by BalanceAdjustment
+func (BalanceAdjustment) TableName() string {
+	return "balance_adjustments"
+}
+
+// IsPending checks if the adjustment is still awaiting a decision
+func (a *BalanceAdjustment) IsPending() bool {
+	return a.Status == BalanceAdjustmentStatusPending
+}
@@ -0,0 +1,26 @@
+package models
+
+import (
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+// BalanceCheckpoint records what a wallet's oldest transactions add up to. It covers every transaction
+// of the wallet up to ThroughTransactionID, all of which had settled when the checkpoint was taken, so
+// the balance is the checkpoint plus the transactions after it, even once the covered ones are archived
+type BalanceCheckpoint struct {
+	ID                   uint            `json:"id" gorm:"primarykey"`
+	CreatedAt            time.Time       `json:"created_at"`
+	UpdatedAt            time.Time       `json:"updated_at"`
+	WalletID             uint            `json:"wallet_id" gorm:"not null;uniqueIndex"`
+	ThroughTransactionID uint            `json:"through_transaction_id" gorm:"not null"`
+	Balance              decimal.Decimal `json:"balance" gorm:"type:decimal(15,2);not null"`
+	ArchivedCount        int64           `json:"archived_count" gorm:"not null;default:0"`
+	ArchivedThrough      *time.Time      `json:"archived_through,omitempty"` // Creation time of the newest archived transaction
+}
+
+// TableName overrides the table name used by BalanceCheckpoint
+func (BalanceCheckpoint) TableName() string {
+	return "balance_checkpoints"
+}
@@ -0,0 +1,50 @@
+package models
+
+import (
+	"strings"
+	"time"
+)
+
+// BlocklistEntryType is the kind of value a blocklist entry matches
+type BlocklistEntryType string
+
+const (
+	BlocklistEntryEmail       BlocklistEntryType = "EMAIL"
+	BlocklistEntryWallet      BlocklistEntryType = "WALLET"
+	BlocklistEntryBankAccount BlocklistEntryType = "BANK_ACCOUNT"
+)
+
+// BlocklistEntry bars a user email, wallet or bank account from sending or receiving funds
+type BlocklistEntry struct {
+	ID          uint               `json:"id" gorm:"primarykey"`
+	CreatedAt   time.Time          `json:"created_at"`
+	UpdatedAt   time.Time          `json:"updated_at"`
+	Type        BlocklistEntryType `json:"type" gorm:"type:varchar(20);check:type IN ('EMAIL','WALLET','BANK_ACCOUNT');not null;uniqueIndex:idx_blocklist_type_value"`
+	Value       string             `json:"value" gorm:"type:varchar(255);not null;uniqueIndex:idx_blocklist_type_value"` // Normalized with BlocklistValue
+	Reason      string             `json:"reason" gorm:"type:text"`
+	CreatedByID uint               `json:"created_by_id" gorm:"not null"`
+}
+
+// TableName overrides the table name used by BlocklistEntry
+func (BlocklistEntry) TableName() string {
+	return "blocklist_entries"
+}
+
+// BlocklistValue normalizes a value so entries and lookups compare equal: emails are lower-cased and
+// bank accounts are written as BANKCODE:ACCOUNTNUMBER
+func BlocklistValue(entryType BlocklistEntryType, value string) string {
+	value = strings.TrimSpace(value)
+	switch entryType {
+	case BlocklistEntryEmail:
+		return strings.ToLower(value)
+	case BlocklistEntryBankAccount:
+		return strings.ToUpper(strings.ReplaceAll(value, " ", ""))
+	default:
+		return value
+	}
+}
+
+// BlocklistValue returns the blocklist value matching the bank account
+func (b BankAccount) BlocklistValue() string {
+	return BlocklistValue(BlocklistEntryBankAccount, b.BankCode+":"+b.AccountNumber)
+}
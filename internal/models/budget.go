@@ -0,0 +1,34 @@
+package models
+
+import (
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+// Budget is a monthly spending limit a user sets on a category of their debits. Spending is the total
+// of the wallet's completed debits tagged with the category since the first day of the month (UTC)
+type Budget struct {
+	ID        uint            `json:"id" gorm:"primarykey"`
+	CreatedAt time.Time       `json:"created_at"`
+	UpdatedAt time.Time       `json:"updated_at"`
+	UserID    uint            `json:"user_id" gorm:"not null;uniqueIndex:idx_budget_user_category"`
+	WalletID  uint            `json:"wallet_id" gorm:"not null;index"`
+	Category  string          `json:"category" gorm:"type:varchar(50);not null;uniqueIndex:idx_budget_user_category"`
+	Amount    decimal.Decimal `json:"amount" gorm:"type:decimal(15,2);not null"`
+	Currency  string          `json:"currency" gorm:"type:varchar(3);not null"`
+	// AlertedPeriod and AlertedPercent record the highest threshold the user was alerted about and the
+	// month (YYYY-MM) it was in, so each alert is sent once a month
+	AlertedPeriod  string `json:"-" gorm:"type:varchar(7)"`
+	AlertedPercent int    `json:"-" gorm:"not null;default:0"`
+}
+
+// TableName overrides the table name used by Budget
+func (Budget) TableName() string {
+	return "budgets"
+}
+
+// BudgetPeriod returns the month (YYYY-MM, UTC) budgets are evaluated for at t
+func BudgetPeriod(t time.Time) string {
+	return t.UTC().Format("2006-01")
+}
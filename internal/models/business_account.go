@@ -0,0 +1,111 @@
+package models
+
+import (
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+// BusinessRole is what a member may do with a business wallet
+type BusinessRole string
+
+const (
+	// BusinessRoleViewer sees the wallet, its transactions and its payment approvals
+	BusinessRoleViewer BusinessRole = "VIEWER"
+	// BusinessRoleInitiator also requests payments, which wait for an approver
+	BusinessRoleInitiator BusinessRole = "INITIATOR"
+	// BusinessRoleApprover also pays directly and approves or rejects the requests of initiators
+	BusinessRoleApprover BusinessRole = "APPROVER"
+)
+
+// IsValid checks if the role is one members can be given
+func (r BusinessRole) IsValid() bool {
+	switch r {
+	case BusinessRoleViewer, BusinessRoleInitiator, BusinessRoleApprover:
+		return true
+	}
+	return false
+}
+
+// BusinessAccount is an organization whose members share a wallet. The wallet belongs to the owner,
+// who manages the members and is an approver of their own
+type BusinessAccount struct {
+	ID        uint      `json:"id" gorm:"primarykey"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+	Name      string    `json:"name" gorm:"type:varchar(255);not null"`
+	OwnerID   uint      `json:"owner_id" gorm:"not null;index"`
+	WalletID  uint      `json:"wallet_id" gorm:"not null;uniqueIndex"`
+
+	Wallet  Wallet           `json:"wallet,omitempty" gorm:"foreignKey:WalletID"`
+	Members []BusinessMember `json:"members,omitempty" gorm:"foreignKey:BusinessAccountID"`
+}
+
+// TableName overrides the table name used by BusinessAccount
+func (BusinessAccount) TableName() string {
+	return "business_accounts"
+}
+
+// BusinessMember gives a user a role on a business account
+type BusinessMember struct {
+	ID                uint         `json:"id" gorm:"primarykey"`
+	CreatedAt         time.Time    `json:"created_at"`
+	UpdatedAt         time.Time    `json:"updated_at"`
+	BusinessAccountID uint         `json:"business_account_id" gorm:"not null;uniqueIndex:idx_business_member"`
+	UserID            uint         `json:"user_id" gorm:"not null;uniqueIndex:idx_business_member;index"`
+	Role              BusinessRole `json:"role" gorm:"type:varchar(20);not null"`
+
+	User User `json:"user,omitempty" gorm:"foreignKey:UserID"`
+}
+
+// TableName overrides the table name used by BusinessMember
+func (BusinessMember) TableName() string {
+	return "business_members"
+}
+
+// PaymentApprovalStatus represents where a payment request of an initiator stands
+type PaymentApprovalStatus string
+
+const (
+	PaymentApprovalStatusPending  PaymentApprovalStatus = "PENDING"
+	PaymentApprovalStatusApproved PaymentApprovalStatus = "APPROVED"
+	PaymentApprovalStatusRejected PaymentApprovalStatus = "REJECTED"
+	// PaymentApprovalStatusFailed is an approved payment the wallet could not make, such as for
+	// insufficient funds
+	PaymentApprovalStatusFailed PaymentApprovalStatus = "FAILED"
+)
+
+// PaymentApproval is a transfer or withdrawal from a business wallet. Requests of initiators wait as
+// PENDING and nothing is debited until an approver other than the initiator approves them; payments
+// of approvers are approved as they are made
+type PaymentApproval struct {
+	ID                   uint                  `json:"id" gorm:"primarykey"`
+	CreatedAt            time.Time             `json:"created_at"`
+	UpdatedAt            time.Time             `json:"updated_at"`
+	BusinessAccountID    uint                  `json:"business_account_id" gorm:"not null;index"`
+	WalletID             uint                  `json:"wallet_id" gorm:"not null;index"`
+	Purpose              TransactionPurpose    `json:"purpose" gorm:"type:varchar(20);check:purpose IN ('WITHDRAWAL','TRANSFER');not null"`
+	CounterpartyWalletID *uint                 `json:"counterparty_wallet_id,omitempty"`
+	BankAccount          BankAccount           `json:"bank_account" gorm:"embedded;embeddedPrefix:bank_"` // Payout destination of withdrawals
+	Amount               decimal.Decimal       `json:"amount" gorm:"type:decimal(15,2);not null"`
+	Currency             string                `json:"currency" gorm:"type:varchar(3);not null"`
+	Reference            string                `json:"reference" gorm:"type:varchar(255);not null;uniqueIndex"`
+	Description          string                `json:"description,omitempty" gorm:"type:text"`
+	InitiatorID          uint                  `json:"initiator_id" gorm:"not null;index"`
+	Status               PaymentApprovalStatus `json:"status" gorm:"type:varchar(20);check:status IN ('PENDING','APPROVED','REJECTED','FAILED');not null;default:'PENDING';index"`
+	ApproverID           *uint                 `json:"approver_id,omitempty"`
+	DecisionNote         string                `json:"decision_note,omitempty" gorm:"type:text"`
+	DecidedAt            *time.Time            `json:"decided_at,omitempty"`
+	TransactionID        *uint                 `json:"transaction_id,omitempty"` // The debit made on approval
+	FailureReason        string                `json:"failure_reason,omitempty" gorm:"type:text"`
+}
+
+// TableName overrides the table name used by PaymentApproval
+func (PaymentApproval) TableName() string {
+	return "payment_approvals"
+}
+
+// IsPending checks if the payment is still awaiting a decision
+func (a *PaymentApproval) IsPending() bool {
+	return a.Status == PaymentApprovalStatusPending
+}
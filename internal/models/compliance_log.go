@@ -0,0 +1,36 @@
+package models
+
+import (
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+// ComplianceCheck names the control that stopped an operation
+type ComplianceCheck string
+
+const (
+	ComplianceCheckBlocklist ComplianceCheck = "BLOCKLIST"
+	ComplianceCheckAML       ComplianceCheck = "AML"
+	ComplianceCheckSanctions ComplianceCheck = "SANCTIONS"
+)
+
+// ComplianceLog records an operation refused by a compliance control so it can be reviewed
+type ComplianceLog struct {
+	ID                   uint               `json:"id" gorm:"primarykey"`
+	CreatedAt            time.Time          `json:"created_at" gorm:"index"`
+	Check                ComplianceCheck    `json:"check" gorm:"column:check_type;type:varchar(20);check:check_type IN ('BLOCKLIST','AML','SANCTIONS');not null;index"`
+	UserID               *uint              `json:"user_id,omitempty" gorm:"index"`
+	WalletID             *uint              `json:"wallet_id,omitempty" gorm:"index"`
+	CounterpartyWalletID *uint              `json:"counterparty_wallet_id,omitempty"`
+	Purpose              TransactionPurpose `json:"purpose,omitempty" gorm:"type:varchar(20)"`
+	Amount               decimal.Decimal    `json:"amount" gorm:"type:decimal(15,2);not null;default:0.00"`
+	Reference            string             `json:"reference,omitempty" gorm:"type:varchar(100)"`
+	Subject              string             `json:"subject" gorm:"type:varchar(255)"` // The value that matched, such as a blocklisted email
+	Reason               string             `json:"reason" gorm:"type:text"`
+}
+
+// TableName overrides the table name used by ComplianceLog
+func (ComplianceLog) TableName() string {
+	return "compliance_logs"
+}
@@ -0,0 +1,48 @@
+package models
+
+import (
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+// DepositStatus represents the state of an externally funded deposit
+type DepositStatus string
+
+const (
+	DepositStatusPending   DepositStatus = "PENDING"
+	DepositStatusCompleted DepositStatus = "COMPLETED"
+	DepositStatusFailed    DepositStatus = "FAILED"
+	DepositStatusCancelled DepositStatus = "CANCELLED"
+	DepositStatusParked    DepositStatus = "PARKED" // Settled while its wallet was inactive; the funds wait in suspense
+)
+
+// Deposit tracks wallet funding that is confirmed asynchronously by a payment provider
+type Deposit struct {
+	ID                uint            `json:"id" gorm:"primarykey"`
+	CreatedAt         time.Time       `json:"created_at"`
+	UpdatedAt         time.Time       `json:"updated_at"`
+	WalletID          uint            `json:"wallet_id" gorm:"not null;index"`
+	Provider          string          `json:"provider" gorm:"type:varchar(50);not null;uniqueIndex:idx_deposit_provider_reference"`
+	ExternalReference string          `json:"external_reference" gorm:"type:varchar(255);not null;uniqueIndex:idx_deposit_provider_reference"`
+	Reference         string          `json:"reference" gorm:"type:varchar(255);uniqueIndex;not null"`
+	Amount            decimal.Decimal `json:"amount" gorm:"type:decimal(15,2);not null"`
+	Currency          string          `json:"currency" gorm:"type:varchar(3);not null"`
+	Status            DepositStatus   `json:"status" gorm:"type:varchar(20);check:status IN ('PENDING','COMPLETED','FAILED','CANCELLED','PARKED');not null;default:'PENDING'"`
+	TransactionID     *uint           `json:"transaction_id,omitempty"`
+	FailureReason     string          `json:"failure_reason,omitempty" gorm:"type:text"`
+	CompletedAt       *time.Time      `json:"completed_at,omitempty"`
+	SettlementBatchID *uint           `json:"settlement_batch_id,omitempty" gorm:"index"`
+
+	Wallet Wallet `json:"wallet,omitempty" gorm:"foreignKey:WalletID"`
+}
+
+// TableName overrides the table name used by Deposit
+func (Deposit) TableName() string {
+	return "deposits"
+}
+
+// IsPending checks if the deposit is still awaiting provider confirmation
+func (d *Deposit) IsPending() bool {
+	return d.Status == DepositStatusPending
+}
@@ -0,0 +1,41 @@
+package models
+
+import (
+	"time"
+)
+
+// DevicePlatform represents the platform a push token was issued for
+type DevicePlatform string
+
+const (
+	DevicePlatformAndroid DevicePlatform = "ANDROID"
+	DevicePlatformIOS     DevicePlatform = "IOS"
+	DevicePlatformWeb     DevicePlatform = "WEB"
+)
+
+// DeviceToken stores a Firebase Cloud Messaging registration token for a user's device
+type DeviceToken struct {
+	ID         uint           `json:"id" gorm:"primarykey"`
+	CreatedAt  time.Time      `json:"created_at"`
+	UpdatedAt  time.Time      `json:"updated_at"`
+	UserID     uint           `json:"user_id" gorm:"not null;index"`
+	Token      string         `json:"token" gorm:"type:varchar(512);uniqueIndex;not null"`
+	Platform   DevicePlatform `json:"platform" gorm:"type:varchar(10);not null"`
+	DeviceName string         `json:"device_name" gorm:"type:varchar(255)"`
+	LastUsedAt *time.Time     `json:"last_used_at,omitempty"`
+}
+
+// TableName overrides the table name used by DeviceToken
+func (DeviceToken) TableName() string {
+	return "device_tokens"
+}
+
+// IsValidDevicePlatform checks if the platform is supported
+func IsValidDevicePlatform(platform DevicePlatform) bool {
+	switch platform {
+	case DevicePlatformAndroid, DevicePlatformIOS, DevicePlatformWeb:
+		return true
+	default:
+		return false
+	}
+}
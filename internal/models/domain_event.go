@@ -0,0 +1,20 @@
+package models
+
+import "time"
+
+// DomainEvent is an event emitted by the use cases, kept so webhook consumers can have events replayed
+// after an outage
+type DomainEvent struct {
+	ID         uint      `json:"id" gorm:"primarykey"`
+	CreatedAt  time.Time `json:"created_at"`
+	Type       string    `json:"type" gorm:"type:varchar(100);not null;index"`
+	UserID     uint      `json:"user_id" gorm:"not null;index:idx_domain_event_user_occurred"`
+	WalletID   uint      `json:"wallet_id" gorm:"not null;index"`
+	OccurredAt time.Time `json:"occurred_at" gorm:"not null;index:idx_domain_event_user_occurred"`
+	Payload    string    `json:"payload" gorm:"type:text;not null"` // JSON encoded event, as delivered to webhooks
+}
+
+// TableName overrides the table name used by DomainEvent
+func (DomainEvent) TableName() string {
+	return "domain_events"
+}
@@ -0,0 +1,62 @@
+package models
+
+import (
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+// EscrowStatus represents where the funds of an escrow are
+type EscrowStatus string
+
+const (
+	// EscrowStatusHeld funds sit in the escrow ledger account, debited from the payer
+	EscrowStatusHeld EscrowStatus = "HELD"
+	// EscrowStatusReleased funds were paid to the payee
+	EscrowStatusReleased EscrowStatus = "RELEASED"
+	// EscrowStatusCancelled funds were returned to the payer by the payer or the payee
+	EscrowStatusCancelled EscrowStatus = "CANCELLED"
+	// EscrowStatusExpired funds were returned to the payer once the escrow expired unreleased
+	EscrowStatusExpired EscrowStatus = "EXPIRED"
+)
+
+// Escrow is a payment from a payer to a payee held in the escrow ledger account until the payer
+// releases it, for instance once the condition is met, or it is cancelled or expires and returns to
+// the payer
+type Escrow struct {
+	ID                       uint            `json:"id" gorm:"primarykey"`
+	CreatedAt                time.Time       `json:"created_at"`
+	UpdatedAt                time.Time       `json:"updated_at"`
+	TenantID                 uint            `json:"-" gorm:"not null;default:1;index"`
+	Reference                string          `json:"reference" gorm:"type:varchar(255);not null;uniqueIndex"`
+	PayerID                  uint            `json:"payer_id" gorm:"not null;index"`
+	PayerWalletID            uint            `json:"payer_wallet_id" gorm:"not null"`
+	PayeeID                  uint            `json:"payee_id" gorm:"not null;index"`
+	PayeeWalletID            uint            `json:"payee_wallet_id" gorm:"not null"`
+	Amount                   decimal.Decimal `json:"amount" gorm:"type:decimal(15,2);not null"`
+	Currency                 string          `json:"currency" gorm:"type:varchar(3);not null"`
+	Condition                string          `json:"condition" gorm:"type:text"`
+	ExpiresAt                time.Time       `json:"expires_at" gorm:"not null;index"`
+	Status                   EscrowStatus    `json:"status" gorm:"type:varchar(20);check:status IN ('HELD','RELEASED','CANCELLED','EXPIRED');not null;default:'HELD';index"`
+	FundingJournalEntryID    uint            `json:"funding_journal_entry_id" gorm:"not null"`
+	SettlementJournalEntryID *uint           `json:"settlement_journal_entry_id,omitempty"`
+	SettledAt                *time.Time      `json:"settled_at,omitempty"`
+
+	Payer User `json:"payer,omitempty" gorm:"foreignKey:PayerID"`
+	Payee User `json:"payee,omitempty" gorm:"foreignKey:PayeeID"`
+}
+
+// TableName overrides the table name used by Escrow
+func (Escrow) TableName() string {
+	return "escrows"
+}
+
+// IsHeld checks if the funds are still in escrow
+func (e *Escrow) IsHeld() bool {
+	return e.Status == EscrowStatusHeld
+}
+
+// IsExpired checks if the escrow can no longer be released at the given time
+func (e *Escrow) IsExpired(now time.Time) bool {
+	return !now.Before(e.ExpiresAt)
+}
@@ -0,0 +1,50 @@
+package models
+
+import (
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+// FraudReviewStatus represents the outcome of a manual fraud review
+type FraudReviewStatus string
+
+const (
+	FraudReviewStatusPending  FraudReviewStatus = "PENDING"
+	FraudReviewStatusApproved FraudReviewStatus = "APPROVED"
+	FraudReviewStatusRejected FraudReviewStatus = "REJECTED"
+)
+
+// FraudReview is a debit held as PENDING_REVIEW by a fraud rule. The held amount and fee stay
+// debited from the wallet until an admin approves the debit or rejects it and refunds the wallet
+type FraudReview struct {
+	ID                   uint               `json:"id" gorm:"primarykey"`
+	CreatedAt            time.Time          `json:"created_at"`
+	UpdatedAt            time.Time          `json:"updated_at"`
+	TransactionID        uint               `json:"transaction_id" gorm:"not null;uniqueIndex"` // The held debit
+	WalletID             uint               `json:"wallet_id" gorm:"not null;index"`
+	Purpose              TransactionPurpose `json:"purpose" gorm:"type:varchar(20);check:purpose IN ('WITHDRAWAL','TRANSFER');not null"`
+	CounterpartyWalletID *uint              `json:"counterparty_wallet_id,omitempty"`
+	Amount               decimal.Decimal    `json:"amount" gorm:"type:decimal(15,2);not null"`
+	Fee                  decimal.Decimal    `json:"fee" gorm:"type:decimal(15,2);not null;default:0.00"`
+	Currency             string             `json:"currency" gorm:"type:varchar(3);not null"`
+	Rule                 string             `json:"rule" gorm:"type:varchar(50);not null"`
+	Reason               string             `json:"reason" gorm:"type:text"`
+	BankAccount          BankAccount        `json:"bank_account" gorm:"embedded;embeddedPrefix:bank_"` // Payout destination of held withdrawals
+	Status               FraudReviewStatus  `json:"status" gorm:"type:varchar(20);check:status IN ('PENDING','APPROVED','REJECTED');not null;default:'PENDING';index"`
+	ReviewerID           *uint              `json:"reviewer_id,omitempty"`
+	ReviewNote           string             `json:"review_note,omitempty" gorm:"type:text"`
+	ReviewedAt           *time.Time         `json:"reviewed_at,omitempty"`
+
+	Transaction Transaction `json:"transaction,omitempty" gorm:"foreignKey:TransactionID"`
+}
+
+// TableName overrides the table name used by FraudReview
+func (FraudReview) TableName() string {
+	return "fraud_reviews"
+}
+
+// IsPending checks if the review is still awaiting a decision
+func (r *FraudReview) IsPending() bool {
+	return r.Status == FraudReviewStatusPending
+}
@@ -0,0 +1,46 @@
+package models
+
+import (
+	"fmt"
+	"net"
+	"strings"
+	"time"
+)
+
+// IPAllowlistEntry lets clients from a CIDR range reach the admin endpoints. Entries are managed at
+// runtime and add to the ranges configured with ADMIN_IP_ALLOWLIST
+type IPAllowlistEntry struct {
+	ID          uint      `json:"id" gorm:"primarykey"`
+	CreatedAt   time.Time `json:"created_at"`
+	UpdatedAt   time.Time `json:"updated_at"`
+	CIDR        string    `json:"cidr" gorm:"type:varchar(64);not null;uniqueIndex"` // Normalized with NormalizeCIDR
+	Description string    `json:"description" gorm:"type:varchar(255)"`
+	CreatedByID uint      `json:"created_by_id" gorm:"not null"`
+}
+
+// TableName overrides the table name used by IPAllowlistEntry
+func (IPAllowlistEntry) TableName() string {
+	return "ip_allowlist_entries"
+}
+
+// NormalizeCIDR returns the canonical form of a CIDR range so entries compare equal, such as
+// 10.0.0.0/8 for "10.1.2.3/8"; a single address becomes a /32 or /128 range
+func NormalizeCIDR(value string) (string, error) {
+	value = strings.TrimSpace(value)
+	if !strings.Contains(value, "/") {
+		ip := net.ParseIP(value)
+		if ip == nil {
+			return "", fmt.Errorf("invalid IP address or CIDR range %q", value)
+		}
+		if ip.To4() != nil {
+			return ip.String() + "/32", nil
+		}
+		return ip.String() + "/128", nil
+	}
+
+	_, network, err := net.ParseCIDR(value)
+	if err != nil {
+		return "", fmt.Errorf("invalid IP address or CIDR range %q", value)
+	}
+	return network.String(), nil
+}
@@ -0,0 +1,38 @@
+package models
+
+import "time"
+
+// JobStatus represents where a background job is in the queue
+type JobStatus string
+
+const (
+	JobStatusPending JobStatus = "PENDING" // Waiting for RunAt, including retries
+	JobStatusRunning JobStatus = "RUNNING" // Claimed by a worker until LockedUntil
+	JobStatusDead    JobStatus = "DEAD"    // Failed every attempt; kept until an admin retries it
+)
+
+// Job is a unit of background work that survives restarts. Completed jobs are deleted, so the
+// table only holds work still to do and the dead letter queue
+type Job struct {
+	ID          uint       `json:"id" gorm:"primarykey"`
+	CreatedAt   time.Time  `json:"created_at"`
+	UpdatedAt   time.Time  `json:"updated_at"`
+	Type        string     `json:"type" gorm:"type:varchar(100);not null;index"`
+	Payload     string     `json:"payload" gorm:"type:text;not null"` // JSON encoded arguments of the job
+	Status      JobStatus  `json:"status" gorm:"type:varchar(20);check:status IN ('PENDING','RUNNING','DEAD');not null;default:'PENDING';index:idx_job_status_run_at"`
+	Attempts    int        `json:"attempts" gorm:"not null;default:0"`
+	MaxAttempts int        `json:"max_attempts" gorm:"not null"`
+	RunAt       time.Time  `json:"run_at" gorm:"not null;index:idx_job_status_run_at"`
+	LockedUntil *time.Time `json:"locked_until,omitempty"` // A running job whose lease expired is picked up again
+	LastError   string     `json:"last_error,omitempty" gorm:"type:text"`
+}
+
+// TableName overrides the table name used by Job
+func (Job) TableName() string {
+	return "jobs"
+}
+
+// IsDead checks if the job exhausted its attempts
+func (j *Job) IsDead() bool {
+	return j.Status == JobStatusDead
+}
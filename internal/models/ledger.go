@@ -0,0 +1,87 @@
+package models
+
+import (
+	"time"
+)
+
+// LedgerAccountCode identifies an account of the chart of ledger accounts
+type LedgerAccountCode string
+
+const (
+	// LedgerAccountCash is the system wallet that funds top-ups and receives withdrawals
+	LedgerAccountCash LedgerAccountCode = "CASH"
+	// LedgerAccountFees collects the tier fees charged on withdrawals and transfers
+	LedgerAccountFees LedgerAccountCode = "FEES"
+	// LedgerAccountInterest pays interest to wallets; it is funded from cash by a journal entry
+	LedgerAccountInterest LedgerAccountCode = "INTEREST"
+	// LedgerAccountSuspense holds funds that cannot be attributed to a wallet yet
+	LedgerAccountSuspense LedgerAccountCode = "SUSPENSE"
+	// LedgerAccountEscrow holds the funds of escrows until they are released to the payee or returned
+	// to the payer
+	LedgerAccountEscrow LedgerAccountCode = "ESCROW"
+	// LedgerAccountAdjustments is the other side of manual wallet adjustments; like interest, it is
+	// funded from cash by a journal entry before it can credit wallets
+	LedgerAccountAdjustments LedgerAccountCode = "ADJUSTMENTS"
+)
+
+// LedgerAccountType is the accounting class of a ledger account
+type LedgerAccountType string
+
+const (
+	LedgerAccountTypeAsset     LedgerAccountType = "ASSET"
+	LedgerAccountTypeLiability LedgerAccountType = "LIABILITY"
+	LedgerAccountTypeIncome    LedgerAccountType = "INCOME"
+	LedgerAccountTypeExpense   LedgerAccountType = "EXPENSE"
+)
+
+// LedgerAccount is an account of the chart of ledger accounts. Each account is backed by a wallet of
+// the system account, so its legs are ordinary transactions that reconciliation checks like any other.
+// Every tenant has a chart of its own, backed by the wallets of its own system accounts
+type LedgerAccount struct {
+	ID        uint              `json:"id" gorm:"primarykey"`
+	CreatedAt time.Time         `json:"created_at"`
+	TenantID  uint              `json:"-" gorm:"not null;default:1;uniqueIndex:idx_ledger_account_tenant_code,priority:1"`
+	Code      LedgerAccountCode `json:"code" gorm:"type:varchar(20);not null;uniqueIndex:idx_ledger_account_tenant_code,priority:2"`
+	Sandbox   bool              `json:"sandbox" gorm:"not null;default:false;uniqueIndex:idx_ledger_account_tenant_code,priority:3"`
+	Name      string            `json:"name" gorm:"type:varchar(100);not null"`
+	Type      LedgerAccountType `json:"type" gorm:"type:varchar(20);check:type IN ('ASSET','LIABILITY','INCOME','EXPENSE');not null"`
+	WalletID  uint              `json:"wallet_id" gorm:"not null;uniqueIndex"`
+
+	// Relationships
+	Wallet Wallet `json:"wallet,omitempty" gorm:"foreignKey:WalletID"`
+}
+
+// TableName overrides the table name used by LedgerAccount
+func (LedgerAccount) TableName() string {
+	return "ledger_accounts"
+}
+
+// DefaultLedgerAccounts returns the chart of ledger accounts created on startup and with each tenant
+func DefaultLedgerAccounts() []LedgerAccount {
+	return []LedgerAccount{
+		{Code: LedgerAccountCash, Name: "Cash", Type: LedgerAccountTypeAsset},
+		{Code: LedgerAccountFees, Name: "Fee income", Type: LedgerAccountTypeIncome},
+		{Code: LedgerAccountInterest, Name: "Interest expense", Type: LedgerAccountTypeExpense},
+		{Code: LedgerAccountSuspense, Name: "Suspense", Type: LedgerAccountTypeLiability},
+		{Code: LedgerAccountEscrow, Name: "Escrow", Type: LedgerAccountTypeLiability},
+		{Code: LedgerAccountAdjustments, Name: "Manual adjustments", Type: LedgerAccountTypeExpense},
+	}
+}
+
+// JournalEntry groups the legs of one balanced posting: the debits and credits of its transactions
+// add up to the same amount
+type JournalEntry struct {
+	ID          uint      `json:"id" gorm:"primarykey"`
+	CreatedAt   time.Time `json:"created_at"`
+	Reference   string    `json:"reference" gorm:"type:varchar(255);uniqueIndex;not null"`
+	Description string    `json:"description" gorm:"type:text"`
+	PostedBy    *uint     `json:"posted_by,omitempty" gorm:"index"` // Admin who posted a manual entry
+
+	// Relationships
+	Legs []Transaction `json:"legs,omitempty" gorm:"foreignKey:JournalEntryID"`
+}
+
+// TableName overrides the table name used by JournalEntry
+func (JournalEntry) TableName() string {
+	return "journal_entries"
+}
@@ -0,0 +1,27 @@
+package models
+
+import "time"
+
+// LoginMethodPassword identifies logins with an email and password; sign ins with an identity provider
+// use the provider name as method
+const LoginMethodPassword = "password"
+
+// LoginAttempt records a login, successful or not, to throttle IP addresses that fail too often, show
+// users their recent access and investigate attacks on accounts
+type LoginAttempt struct {
+	ID        uint      `json:"id" gorm:"primarykey"`
+	CreatedAt time.Time `json:"created_at" gorm:"index"`
+	UserID    *uint     `json:"user_id,omitempty" gorm:"index"` // Nil when no account has the email
+	Email     string    `json:"email" gorm:"type:varchar(255);not null"`
+	IPAddress string    `json:"ip_address" gorm:"type:varchar(45);not null;index"`
+	UserAgent string    `json:"user_agent" gorm:"type:varchar(512)"`
+	Method    string    `json:"method" gorm:"type:varchar(32);not null;default:'password'"`
+	Succeeded bool      `json:"succeeded" gorm:"not null;default:false"`
+	// TwoFactorUsed tells whether the login was confirmed with a second factor
+	TwoFactorUsed bool `json:"two_factor_used" gorm:"not null;default:false"`
+}
+
+// TableName overrides the table name used by LoginAttempt
+func (LoginAttempt) TableName() string {
+	return "login_attempts"
+}
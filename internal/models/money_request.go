@@ -0,0 +1,59 @@
+package models
+
+import (
+	"strconv"
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+// MoneyRequestStatus represents the state of a request for funds between users
+type MoneyRequestStatus string
+
+const (
+	MoneyRequestStatusPending   MoneyRequestStatus = "PENDING"
+	MoneyRequestStatusAccepted  MoneyRequestStatus = "ACCEPTED"
+	MoneyRequestStatusDeclined  MoneyRequestStatus = "DECLINED"
+	MoneyRequestStatusCancelled MoneyRequestStatus = "CANCELLED"
+)
+
+// MoneyRequestDirection selects requests a user sent or received
+type MoneyRequestDirection string
+
+const (
+	MoneyRequestIncoming MoneyRequestDirection = "incoming"
+	MoneyRequestOutgoing MoneyRequestDirection = "outgoing"
+)
+
+// MoneyRequest is a request from one user (the requester) asking another (the payer) for funds
+type MoneyRequest struct {
+	ID            uint               `json:"id" gorm:"primarykey"`
+	CreatedAt     time.Time          `json:"created_at"`
+	UpdatedAt     time.Time          `json:"updated_at"`
+	RequesterID   uint               `json:"requester_id" gorm:"not null;index"`
+	PayerID       uint               `json:"payer_id" gorm:"not null;index"`
+	Amount        decimal.Decimal    `json:"amount" gorm:"type:decimal(15,2);not null"`
+	Currency      string             `json:"currency" gorm:"type:varchar(3);not null"`
+	Note          string             `json:"note" gorm:"type:text"`
+	Status        MoneyRequestStatus `json:"status" gorm:"type:varchar(20);check:status IN ('PENDING','ACCEPTED','DECLINED','CANCELLED');not null;default:'PENDING';index"`
+	TransactionID *uint              `json:"transaction_id,omitempty"`
+	RespondedAt   *time.Time         `json:"responded_at,omitempty"`
+
+	Requester User `json:"requester,omitempty" gorm:"foreignKey:RequesterID"`
+	Payer     User `json:"payer,omitempty" gorm:"foreignKey:PayerID"`
+}
+
+// TableName overrides the table name used by MoneyRequest
+func (MoneyRequest) TableName() string {
+	return "money_requests"
+}
+
+// IsPending checks if the request is still awaiting the payer's response
+func (r *MoneyRequest) IsPending() bool {
+	return r.Status == MoneyRequestStatusPending
+}
+
+// Reference returns the transaction reference used when the request is paid
+func (r *MoneyRequest) Reference() string {
+	return "MRQ-" + strconv.FormatUint(uint64(r.ID), 10)
+}
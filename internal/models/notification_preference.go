@@ -0,0 +1,48 @@
+package models
+
+import (
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+// NotificationPreference stores which notifications a user wants to receive
+type NotificationPreference struct {
+	ID                  uint      `json:"id" gorm:"primarykey"`
+	CreatedAt           time.Time `json:"created_at"`
+	UpdatedAt           time.Time `json:"updated_at"`
+	UserID              uint      `json:"user_id" gorm:"not null;uniqueIndex"`
+	EmailEnabled        bool      `json:"email_enabled" gorm:"not null;default:true"`
+	CreditReceived      bool      `json:"credit_received" gorm:"not null;default:true"`
+	WithdrawalCompleted bool      `json:"withdrawal_completed" gorm:"not null;default:true"`
+	TransactionFailed   bool      `json:"transaction_failed" gorm:"not null;default:true"`
+	SuspiciousActivity  bool      `json:"suspicious_activity" gorm:"not null;default:true"`
+	PushEnabled         bool      `json:"push_enabled" gorm:"not null;default:true"`
+
+	// SMS alerts are sent for debits at or above SMSDebitThreshold once the phone number is verified
+	SMSEnabled        bool            `json:"sms_enabled" gorm:"not null;default:false"`
+	SMSDebitThreshold decimal.Decimal `json:"sms_debit_threshold" gorm:"type:decimal(15,2);not null;default:1000.00"`
+}
+
+// TableName overrides the table name used by NotificationPreference
+func (NotificationPreference) TableName() string {
+	return "notification_preferences"
+}
+
+// DefaultSMSDebitThreshold is the debit amount that triggers an SMS alert when the user has not set one
+var DefaultSMSDebitThreshold = decimal.NewFromInt(1000)
+
+// DefaultNotificationPreference returns the preferences applied to users who never changed them
+func DefaultNotificationPreference(userID uint) *NotificationPreference {
+	return &NotificationPreference{
+		UserID:              userID,
+		EmailEnabled:        true,
+		CreditReceived:      true,
+		WithdrawalCompleted: true,
+		TransactionFailed:   true,
+		SuspiciousActivity:  true,
+		PushEnabled:         true,
+		SMSEnabled:          false,
+		SMSDebitThreshold:   DefaultSMSDebitThreshold,
+	}
+}
@@ -0,0 +1,16 @@
+package models
+
+import "time"
+
+// PasswordHistory keeps the hash of a password a user replaced, so recent passwords cannot be chosen again
+type PasswordHistory struct {
+	ID           uint      `json:"id" gorm:"primarykey"`
+	CreatedAt    time.Time `json:"created_at"`
+	UserID       uint      `json:"user_id" gorm:"not null;index"`
+	PasswordHash string    `json:"-" gorm:"type:varchar(255);not null"`
+}
+
+// TableName overrides the table name used by PasswordHistory
+func (PasswordHistory) TableName() string {
+	return "password_histories"
+}
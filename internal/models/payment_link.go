@@ -0,0 +1,53 @@
+package models
+
+import (
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+// PaymentLinkStatus represents whether a payment link can still be paid
+type PaymentLinkStatus string
+
+const (
+	PaymentLinkStatusActive   PaymentLinkStatus = "ACTIVE"
+	PaymentLinkStatusUsed     PaymentLinkStatus = "USED"
+	PaymentLinkStatusDisabled PaymentLinkStatus = "DISABLED"
+)
+
+// PaymentLink is a shareable token that lets other users pay into the creator's wallet
+type PaymentLink struct {
+	ID             uint              `json:"id" gorm:"primarykey"`
+	CreatedAt      time.Time         `json:"created_at"`
+	UpdatedAt      time.Time         `json:"updated_at"`
+	UserID         uint              `json:"user_id" gorm:"not null;index"`
+	WalletID       uint              `json:"wallet_id" gorm:"not null;index"`
+	Token          string            `json:"token" gorm:"type:varchar(64);uniqueIndex;not null"`
+	Amount         *decimal.Decimal  `json:"amount,omitempty" gorm:"type:decimal(15,2)"` // Nil lets the payer choose the amount
+	Currency       string            `json:"currency" gorm:"type:varchar(3);not null"`
+	Description    string            `json:"description" gorm:"type:text"`
+	SingleUse      bool              `json:"single_use" gorm:"not null;default:false"`
+	ExpiresAt      *time.Time        `json:"expires_at,omitempty"`
+	Status         PaymentLinkStatus `json:"status" gorm:"type:varchar(20);check:status IN ('ACTIVE','USED','DISABLED');not null;default:'ACTIVE'"`
+	ViewCount      uint              `json:"view_count" gorm:"not null;default:0"`
+	PaymentCount   uint              `json:"payment_count" gorm:"not null;default:0"`
+	TotalCollected decimal.Decimal   `json:"total_collected" gorm:"type:decimal(15,2);not null;default:0.00"`
+	LastPaidAt     *time.Time        `json:"last_paid_at,omitempty"`
+
+	User User `json:"user,omitempty" gorm:"foreignKey:UserID"`
+}
+
+// TableName overrides the table name used by PaymentLink
+func (PaymentLink) TableName() string {
+	return "payment_links"
+}
+
+// IsExpired checks if the link expiry has passed
+func (l *PaymentLink) IsExpired() bool {
+	return l.ExpiresAt != nil && time.Now().After(*l.ExpiresAt)
+}
+
+// IsPayable checks if the link can accept a payment
+func (l *PaymentLink) IsPayable() bool {
+	return l.Status == PaymentLinkStatusActive && !l.IsExpired()
+}
@@ -0,0 +1,54 @@
+package models
+
+import (
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+// PayoutStatus represents the state of a bank payout
+type PayoutStatus string
+
+const (
+	PayoutStatusPending   PayoutStatus = "PENDING"
+	PayoutStatusCompleted PayoutStatus = "COMPLETED"
+	PayoutStatusFailed    PayoutStatus = "FAILED"
+	PayoutStatusCancelled PayoutStatus = "CANCELLED" // Expired before the provider accepted it
+)
+
+// BankAccount identifies the destination of a payout
+type BankAccount struct {
+	BankCode      string `json:"bank_code" gorm:"type:varchar(20)"`
+	AccountNumber string `json:"account_number" gorm:"type:varchar(34)"`
+	AccountName   string `json:"account_name" gorm:"type:varchar(255)"`
+}
+
+// Payout tracks a withdrawal sent to a bank account through a payout provider
+type Payout struct {
+	ID                uint            `json:"id" gorm:"primarykey"`
+	CreatedAt         time.Time       `json:"created_at"`
+	UpdatedAt         time.Time       `json:"updated_at"`
+	WalletID          uint            `json:"wallet_id" gorm:"not null;index"`
+	TransactionID     uint            `json:"transaction_id" gorm:"not null;index"`
+	Reference         string          `json:"reference" gorm:"type:varchar(255);uniqueIndex;not null"`
+	Provider          string          `json:"provider" gorm:"type:varchar(50);not null"`
+	ProviderReference string          `json:"provider_reference" gorm:"type:varchar(255);index"`
+	Amount            decimal.Decimal `json:"amount" gorm:"type:decimal(15,2);not null"`
+	Fee               decimal.Decimal `json:"fee" gorm:"type:decimal(15,2);not null;default:0.00"` // Held with the amount and refunded if the payout fails
+	Currency          string          `json:"currency" gorm:"type:varchar(3);not null"`
+	BankAccount       BankAccount     `json:"bank_account" gorm:"embedded"`
+	Status            PayoutStatus    `json:"status" gorm:"type:varchar(20);check:status IN ('PENDING','COMPLETED','FAILED','CANCELLED');not null;default:'PENDING'"`
+	FailureReason     string          `json:"failure_reason,omitempty" gorm:"type:text"`
+	CompletedAt       *time.Time      `json:"completed_at,omitempty"`
+	SettlementBatchID *uint           `json:"settlement_batch_id,omitempty" gorm:"index"`
+}
+
+// TableName overrides the table name used by Payout
+func (Payout) TableName() string {
+	return "payouts"
+}
+
+// IsPending checks if the payout is still awaiting a final status from the provider
+func (p *Payout) IsPending() bool {
+	return p.Status == PayoutStatusPending
+}
@@ -0,0 +1,47 @@
+package models
+
+import (
+	"time"
+
+	"golang.org/x/crypto/bcrypt"
+)
+
+// MaxPhoneVerificationAttempts is the number of wrong codes accepted before a verification is invalidated
+const MaxPhoneVerificationAttempts = 5
+
+// PhoneVerification holds a pending one-time code sent to a user's phone
+type PhoneVerification struct {
+	ID          uint       `json:"id" gorm:"primarykey"`
+	CreatedAt   time.Time  `json:"created_at"`
+	UserID      uint       `json:"user_id" gorm:"not null;index"`
+	PhoneNumber string     `json:"phone_number" gorm:"type:varchar(20);not null"`
+	CodeHash    string     `json:"-" gorm:"type:varchar(255);not null"`
+	Attempts    int        `json:"attempts" gorm:"not null;default:0"`
+	ExpiresAt   time.Time  `json:"expires_at" gorm:"not null"`
+	VerifiedAt  *time.Time `json:"verified_at,omitempty"`
+}
+
+// TableName overrides the table name used by PhoneVerification
+func (PhoneVerification) TableName() string {
+	return "phone_verifications"
+}
+
+// SetCode hashes and stores the verification code
+func (v *PhoneVerification) SetCode(code string) error {
+	hashed, err := bcrypt.GenerateFromPassword([]byte(code), bcrypt.DefaultCost)
+	if err != nil {
+		return err
+	}
+	v.CodeHash = string(hashed)
+	return nil
+}
+
+// CheckCode verifies the code against the stored hash
+func (v *PhoneVerification) CheckCode(code string) bool {
+	return bcrypt.CompareHashAndPassword([]byte(v.CodeHash), []byte(code)) == nil
+}
+
+// IsUsable checks if the verification can still be completed
+func (v *PhoneVerification) IsUsable() bool {
+	return v.VerifiedAt == nil && v.Attempts < MaxPhoneVerificationAttempts && time.Now().Before(v.ExpiresAt)
+}
@@ -0,0 +1,85 @@
+package models
+
+import (
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+// ProviderStatement is a settlement file imported from a payment provider. Each line of the file is
+// matched against the deposits and payouts made through the provider
+type ProviderStatement struct {
+	ID             uint      `json:"id" gorm:"primarykey"`
+	CreatedAt      time.Time `json:"created_at"`
+	Provider       string    `json:"provider" gorm:"type:varchar(50);not null;uniqueIndex:idx_statement_provider_checksum"`
+	FileName       string    `json:"file_name" gorm:"type:varchar(255)"`
+	Checksum       string    `json:"checksum" gorm:"type:varchar(64);not null;uniqueIndex:idx_statement_provider_checksum"` // SHA-256 of the file, so a file is imported once
+	UploadedBy     uint      `json:"uploaded_by" gorm:"not null"`
+	LineCount      int       `json:"line_count" gorm:"not null;default:0"`
+	MatchedCount   int       `json:"matched_count" gorm:"not null;default:0"`
+	ExceptionCount int       `json:"exception_count" gorm:"not null;default:0"`
+
+	// Relationships
+	Lines []StatementLine `json:"lines,omitempty" gorm:"foreignKey:StatementID"`
+}
+
+// TableName overrides the table name used by ProviderStatement
+func (ProviderStatement) TableName() string {
+	return "provider_statements"
+}
+
+// StatementLineStatus represents the outcome of matching a statement line
+type StatementLineStatus string
+
+const (
+	StatementLineStatusMatched   StatementLineStatus = "MATCHED"
+	StatementLineStatusException StatementLineStatus = "EXCEPTION" // Waiting for review
+	StatementLineStatusResolved  StatementLineStatus = "RESOLVED"  // Exception reviewed and closed by an admin
+)
+
+// StatementExceptionReason explains why a statement line could not be matched
+type StatementExceptionReason string
+
+const (
+	// StatementExceptionNotFound is a line whose reference matches no deposit or payout of the provider
+	StatementExceptionNotFound StatementExceptionReason = "NOT_FOUND"
+	// StatementExceptionAmountMismatch is a line whose amount or currency differs from the matched item
+	StatementExceptionAmountMismatch StatementExceptionReason = "AMOUNT_MISMATCH"
+	// StatementExceptionStatusMismatch is a line settled by the provider for an item not completed here
+	StatementExceptionStatusMismatch StatementExceptionReason = "STATUS_MISMATCH"
+	// StatementExceptionDuplicate is a reference repeated in the file or matched by an earlier statement
+	StatementExceptionDuplicate StatementExceptionReason = "DUPLICATE"
+)
+
+// StatementLine is one row of a provider statement and the deposit or payout it matched
+type StatementLine struct {
+	ID          uint                     `json:"id" gorm:"primarykey"`
+	CreatedAt   time.Time                `json:"created_at"`
+	UpdatedAt   time.Time                `json:"updated_at"`
+	StatementID uint                     `json:"statement_id" gorm:"not null;index"`
+	Provider    string                   `json:"provider" gorm:"type:varchar(50);not null;index:idx_statement_line_reference"`
+	LineNumber  int                      `json:"line_number" gorm:"not null"`
+	Reference   string                   `json:"reference" gorm:"type:varchar(255);not null;index:idx_statement_line_reference"`
+	Type        TransactionType          `json:"type,omitempty" gorm:"type:varchar(10)"` // CREDIT for collections, DEBIT for payouts; empty matches either
+	Amount      decimal.Decimal          `json:"amount" gorm:"type:decimal(15,2);not null"`
+	Currency    string                   `json:"currency,omitempty" gorm:"type:varchar(3)"`
+	ValueDate   *time.Time               `json:"value_date,omitempty"`
+	Status      StatementLineStatus      `json:"status" gorm:"type:varchar(20);check:status IN ('MATCHED','EXCEPTION','RESOLVED');not null;index"`
+	Reason      StatementExceptionReason `json:"reason,omitempty" gorm:"type:varchar(30)"`
+	DepositID   *uint                    `json:"deposit_id,omitempty" gorm:"index"`
+	PayoutID    *uint                    `json:"payout_id,omitempty" gorm:"index"`
+	Notes       string                   `json:"notes,omitempty" gorm:"type:text"`
+	ResolvedBy  *uint                    `json:"resolved_by,omitempty"`
+	ResolvedAt  *time.Time               `json:"resolved_at,omitempty"`
+}
+
+// TableName overrides the table name used by StatementLine
+func (StatementLine) TableName() string {
+	return "statement_lines"
+}
+
+// Flag marks the line as an exception for review
+func (l *StatementLine) Flag(reason StatementExceptionReason) {
+	l.Status = StatementLineStatusException
+	l.Reason = reason
+}
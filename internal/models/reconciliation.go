@@ -6,16 +6,26 @@ import (
 	"github.com/shopspring/decimal"
 )
 
-// ReconciliationReport represents a reconciliation report
+// ReconciliationReport represents a reconciliation report. Reports with an issue are worked by
+// operators: acknowledged, assigned, annotated and finally resolved with how the issue was settled
 type ReconciliationReport struct {
-	ID                uint                 `json:"id" gorm:"primarykey"`
-	CreatedAt         time.Time            `json:"created_at"`
-	WalletID          uint                 `json:"wallet_id" gorm:"not null;index"`
-	StoredBalance     decimal.Decimal      `json:"stored_balance" gorm:"type:decimal(15,2);not null"`
-	CalculatedBalance decimal.Decimal      `json:"calculated_balance" gorm:"type:decimal(15,2);not null"`
-	Difference        decimal.Decimal      `json:"difference" gorm:"type:decimal(15,2);not null"`
-	Status            ReconciliationStatus `json:"status" gorm:"type:enum('MATCH','MISMATCH','DOUBLE_ENTRY_ERROR');not null"`
-	Notes             string               `json:"notes" gorm:"type:text"`
+	ID                 uint                          `json:"id" gorm:"primarykey"`
+	CreatedAt          time.Time                     `json:"created_at"`
+	WalletID           uint                          `json:"wallet_id" gorm:"not null;index"`
+	StoredBalance      decimal.Decimal               `json:"stored_balance" gorm:"type:decimal(15,2);not null"`
+	CalculatedBalance  decimal.Decimal               `json:"calculated_balance" gorm:"type:decimal(15,2);not null"`
+	Difference         decimal.Decimal               `json:"difference" gorm:"type:decimal(15,2);not null"`
+	Status             ReconciliationStatus          `json:"status" gorm:"type:varchar(20);check:status IN ('MATCH','MISMATCH','DOUBLE_ENTRY_ERROR');not null"`
+	BrokenPairs        int                           `json:"broken_pairs" gorm:"not null;default:0"` // Double-entry pairs of the wallet that do not balance
+	Notes              string                        `json:"notes" gorm:"type:text"`
+	ResolutionStatus   ReconciliationResolution      `json:"resolution_status" gorm:"type:varchar(20);check:resolution_status IN ('OPEN','ACKNOWLEDGED','RESOLVED');not null;default:'OPEN';index"`
+	AssigneeID         *uint                         `json:"assignee_id,omitempty" gorm:"index"` // The operator investigating the issue
+	AcknowledgedBy     *uint                         `json:"acknowledged_by,omitempty"`
+	AcknowledgedAt     *time.Time                    `json:"acknowledged_at,omitempty"`
+	InvestigationNotes string                        `json:"investigation_notes,omitempty" gorm:"type:text"`
+	ResolutionType     *ReconciliationResolutionType `json:"resolution_type,omitempty" gorm:"type:varchar(20);check:resolution_type IN ('ADJUSTED','FALSE_POSITIVE','DATA_FIX')"`
+	ResolvedBy         *uint                         `json:"resolved_by,omitempty"`
+	ResolvedAt         *time.Time                    `json:"resolved_at,omitempty"`
 
 	// Relationships
 	Wallet Wallet `json:"wallet,omitempty" gorm:"foreignKey:WalletID"`
@@ -30,6 +40,27 @@ const (
 	ReconciliationStatusDoubleEntryError ReconciliationStatus = "DOUBLE_ENTRY_ERROR"
 )
 
+// ReconciliationResolution represents how far operators have got with the issue of a report
+type ReconciliationResolution string
+
+const (
+	ReconciliationResolutionOpen         ReconciliationResolution = "OPEN"
+	ReconciliationResolutionAcknowledged ReconciliationResolution = "ACKNOWLEDGED" // An operator is looking into it
+	ReconciliationResolutionResolved     ReconciliationResolution = "RESOLVED"
+)
+
+// ReconciliationResolutionType records how the issue of a report was settled
+type ReconciliationResolutionType string
+
+const (
+	// ReconciliationResolutionAdjusted is an issue corrected by adjusting the wallet balance
+	ReconciliationResolutionAdjusted ReconciliationResolutionType = "ADJUSTED"
+	// ReconciliationResolutionFalsePositive is an issue that turned out not to be one
+	ReconciliationResolutionFalsePositive ReconciliationResolutionType = "FALSE_POSITIVE"
+	// ReconciliationResolutionDataFix is an issue corrected by repairing the stored records
+	ReconciliationResolutionDataFix ReconciliationResolutionType = "DATA_FIX"
+)
+
 // TableName overrides the table name used by ReconciliationReport
 func (ReconciliationReport) TableName() string {
 	return "reconciliation_reports"
@@ -37,12 +68,13 @@ func (ReconciliationReport) TableName() string {
 
 // HasMismatch checks if there's a balance mismatch
 func (r *ReconciliationReport) HasMismatch() bool {
-	return r.Status == ReconciliationStatusMismatch
+	return r.Status == ReconciliationStatusMismatch || !r.Difference.IsZero()
 }
 
-// HasDoubleEntryError checks if there's a double-entry error
+// HasDoubleEntryError checks if there's a double-entry error. A wallet with a balance mismatch keeps
+// the MISMATCH status, so its broken pairs are only counted
 func (r *ReconciliationReport) HasDoubleEntryError() bool {
-	return r.Status == ReconciliationStatusDoubleEntryError
+	return r.Status == ReconciliationStatusDoubleEntryError || r.BrokenPairs > 0
 }
 
 // HasAnyIssue checks if there's any reconciliation issue
@@ -50,8 +82,16 @@ func (r *ReconciliationReport) HasAnyIssue() bool {
 	return r.Status != ReconciliationStatusMatch
 }
 
+// IsResolved checks if operators have settled the issue of the report
+func (r *ReconciliationReport) IsResolved() bool {
+	return r.ResolutionStatus == ReconciliationResolutionResolved
+}
+
 // GetSeverity returns the severity level of the reconciliation issue
 func (r *ReconciliationReport) GetSeverity() string {
+	if r.HasDoubleEntryError() {
+		return "CRITICAL"
+	}
 	switch r.Status {
 	case ReconciliationStatusMatch:
 		return "INFO"
@@ -0,0 +1,159 @@
+package models
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// MaxReconciliationRunFailures caps how many wallet failures a run keeps; FailedWallets still counts
+// all of them
+const MaxReconciliationRunFailures = 100
+
+// ReconciliationRunStatus represents where a bulk reconciliation run is
+type ReconciliationRunStatus string
+
+const (
+	ReconciliationRunStatusPending   ReconciliationRunStatus = "PENDING" // Waiting for a worker to pick up its first batch
+	ReconciliationRunStatusRunning   ReconciliationRunStatus = "RUNNING"
+	ReconciliationRunStatusCompleted ReconciliationRunStatus = "COMPLETED"
+)
+
+// ReconciliationScope narrows the wallets a reconciliation covers, such as the cohort affected by an
+// incident; empty fields match every wallet. ActiveFrom (inclusive) and ActiveTo (exclusive) bound
+// when a wallet last changed, as its balance moving does
+type ReconciliationScope struct {
+	Currency   string       `json:"currency,omitempty"`
+	Status     WalletStatus `json:"status,omitempty"`
+	ActiveFrom *time.Time   `json:"active_from,omitempty"`
+	ActiveTo   *time.Time   `json:"active_to,omitempty"`
+	WalletIDs  []uint       `json:"wallet_ids,omitempty"`
+}
+
+// IsEmpty checks if the scope covers every wallet
+func (s *ReconciliationScope) IsEmpty() bool {
+	return s.Currency == "" && s.Status == "" && s.ActiveFrom == nil && s.ActiveTo == nil && len(s.WalletIDs) == 0
+}
+
+// Matches checks if a wallet falls within the scope
+func (s *ReconciliationScope) Matches(wallet *Wallet) bool {
+	if s.Currency != "" && wallet.Currency != s.Currency {
+		return false
+	}
+	if s.Status != "" && wallet.Status != s.Status {
+		return false
+	}
+	if s.ActiveFrom != nil && wallet.UpdatedAt.Before(*s.ActiveFrom) {
+		return false
+	}
+	if s.ActiveTo != nil && !wallet.UpdatedAt.Before(*s.ActiveTo) {
+		return false
+	}
+	if len(s.WalletIDs) == 0 {
+		return true
+	}
+	for _, id := range s.WalletIDs {
+		if id == wallet.ID {
+			return true
+		}
+	}
+	return false
+}
+
+// ReconciliationRun is a reconciliation of every wallet in its scope requested by an admin and
+// processed in background batches. LastWalletID is the highest wallet reconciled so far, where the
+// next batch starts
+type ReconciliationRun struct {
+	ID                uint                    `json:"id" gorm:"primarykey"`
+	CreatedAt         time.Time               `json:"created_at"`
+	UpdatedAt         time.Time               `json:"updated_at"`
+	RequestedBy       uint                    `json:"requested_by" gorm:"not null;index"`
+	Status            ReconciliationRunStatus `json:"status" gorm:"type:varchar(20);check:status IN ('PENDING','RUNNING','COMPLETED');not null;default:'PENDING';index"`
+	TotalWallets      int                     `json:"total_wallets" gorm:"not null"`
+	ProcessedWallets  int                     `json:"processed_wallets" gorm:"not null"`
+	Matches           int                     `json:"matches" gorm:"not null"`
+	Mismatches        int                     `json:"mismatches" gorm:"not null"`
+	DoubleEntryErrors int                     `json:"double_entry_errors" gorm:"not null"`
+	FailedWallets     int                     `json:"failed_wallets" gorm:"not null"`
+	Scope             string                  `json:"-" gorm:"type:text"`
+	LastWalletID      uint                    `json:"-" gorm:"not null"`
+	Failures          string                  `json:"-" gorm:"type:text"`
+	StartedAt         *time.Time              `json:"started_at,omitempty"`
+	CompletedAt       *time.Time              `json:"completed_at,omitempty"`
+}
+
+// ReconciliationRunFailure is a wallet a run could not reconcile
+type ReconciliationRunFailure struct {
+	WalletID uint   `json:"wallet_id"`
+	Error    string `json:"error"`
+}
+
+// TableName overrides the table name used by ReconciliationRun
+func (ReconciliationRun) TableName() string {
+	return "reconciliation_runs"
+}
+
+// DecodeScope decodes the wallets the run covers
+func (r *ReconciliationRun) DecodeScope() (ReconciliationScope, error) {
+	var scope ReconciliationScope
+	if r.Scope == "" {
+		return scope, nil
+	}
+	err := json.Unmarshal([]byte(r.Scope), &scope)
+	return scope, err
+}
+
+// SetScope stores the wallets the run covers
+func (r *ReconciliationRun) SetScope(scope ReconciliationScope) error {
+	encoded, err := json.Marshal(scope)
+	if err != nil {
+		return err
+	}
+	r.Scope = string(encoded)
+	return nil
+}
+
+// FailureList decodes the wallets the run could not reconcile, in the order they failed
+func (r *ReconciliationRun) FailureList() ([]ReconciliationRunFailure, error) {
+	if r.Failures == "" {
+		return nil, nil
+	}
+	var failures []ReconciliationRunFailure
+	if err := json.Unmarshal([]byte(r.Failures), &failures); err != nil {
+		return nil, err
+	}
+	return failures, nil
+}
+
+// AddFailure counts a wallet the run could not reconcile, keeping the first MaxReconciliationRunFailures
+// of them
+func (r *ReconciliationRun) AddFailure(walletID uint, err error) error {
+	r.FailedWallets++
+	failures, decodeErr := r.FailureList()
+	if decodeErr != nil {
+		return decodeErr
+	}
+	if len(failures) >= MaxReconciliationRunFailures {
+		return nil
+	}
+	encoded, encodeErr := json.Marshal(append(failures, ReconciliationRunFailure{WalletID: walletID, Error: err.Error()}))
+	if encodeErr != nil {
+		return encodeErr
+	}
+	r.Failures = string(encoded)
+	return nil
+}
+
+// Progress is the share of the run's wallets processed so far, from 0 to 100
+func (r *ReconciliationRun) Progress() int {
+	if r.Status == ReconciliationRunStatusCompleted {
+		return 100
+	}
+	if r.TotalWallets == 0 {
+		return 0
+	}
+	progress := r.ProcessedWallets * 100 / r.TotalWallets
+	if progress > 99 {
+		return 99
+	}
+	return progress
+}
@@ -0,0 +1,61 @@
+package models
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+// ReconciliationSummary aggregates the reconciliation reports of one UTC day for the daily digest
+type ReconciliationSummary struct {
+	ID                 uint       `json:"id" gorm:"primarykey"`
+	CreatedAt          time.Time  `json:"created_at"`
+	Date               time.Time  `json:"date" gorm:"type:date;not null;uniqueIndex"`
+	Checks             int        `json:"checks" gorm:"not null"`
+	WalletsChecked     int        `json:"wallets_checked" gorm:"not null"`
+	Matches            int        `json:"matches" gorm:"not null"`
+	Mismatches         int        `json:"mismatches" gorm:"not null"`
+	DoubleEntryErrors  int        `json:"double_entry_errors" gorm:"not null"`
+	LargestDifferences string     `json:"-" gorm:"type:text"`
+	DeliveredAt        *time.Time `json:"delivered_at"`
+}
+
+// ReconciliationDifference is a wallet whose stored balance drifted from its ledger on the day
+type ReconciliationDifference struct {
+	WalletID   uint            `json:"wallet_id"`
+	ReportID   uint            `json:"report_id"`
+	Difference decimal.Decimal `json:"difference"`
+}
+
+// TableName overrides the table name used by ReconciliationSummary
+func (ReconciliationSummary) TableName() string {
+	return "reconciliation_summaries"
+}
+
+// Differences decodes the largest differences of the day, largest first
+func (s *ReconciliationSummary) Differences() ([]ReconciliationDifference, error) {
+	if s.LargestDifferences == "" {
+		return nil, nil
+	}
+	var differences []ReconciliationDifference
+	if err := json.Unmarshal([]byte(s.LargestDifferences), &differences); err != nil {
+		return nil, err
+	}
+	return differences, nil
+}
+
+// SetDifferences stores the largest differences of the day
+func (s *ReconciliationSummary) SetDifferences(differences []ReconciliationDifference) error {
+	encoded, err := json.Marshal(differences)
+	if err != nil {
+		return err
+	}
+	s.LargestDifferences = string(encoded)
+	return nil
+}
+
+// HasIssues checks if any wallet failed reconciliation on the day
+func (s *ReconciliationSummary) HasIssues() bool {
+	return s.Mismatches > 0 || s.DoubleEntryErrors > 0
+}
@@ -0,0 +1,18 @@
+package models
+
+import "time"
+
+// RevokedToken records the ID (jti) of a JWT that was signed out before it expired. Rows are only
+// needed until ExpiresAt; after that the token is refused as expired anyway
+type RevokedToken struct {
+	ID        uint      `json:"id" gorm:"primarykey"`
+	CreatedAt time.Time `json:"created_at"`
+	TokenID   string    `json:"token_id" gorm:"type:varchar(64);not null;uniqueIndex"`
+	UserID    uint      `json:"user_id" gorm:"not null;index"`
+	ExpiresAt time.Time `json:"expires_at" gorm:"not null;index"`
+}
+
+// TableName overrides the table name used by RevokedToken
+func (RevokedToken) TableName() string {
+	return "revoked_tokens"
+}
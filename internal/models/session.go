@@ -0,0 +1,22 @@
+package models
+
+import "time"
+
+// Session is a device signed in to an account. It follows the token of the device through refreshes,
+// so a user can list the devices holding a valid token and sign one of them out
+type Session struct {
+	ID        uint       `json:"id" gorm:"primarykey"`
+	CreatedAt time.Time  `json:"created_at"`
+	UserID    uint       `json:"user_id" gorm:"not null;index"`
+	TokenID   string     `json:"-" gorm:"type:varchar(64);not null;uniqueIndex"` // ID (jti) of the latest token of the device
+	IPAddress string     `json:"ip_address" gorm:"type:varchar(45)"`
+	UserAgent string     `json:"user_agent" gorm:"type:varchar(512)"`
+	IssuedAt  time.Time  `json:"issued_at" gorm:"not null"` // When the latest token was issued
+	ExpiresAt time.Time  `json:"expires_at" gorm:"not null;index"`
+	RevokedAt *time.Time `json:"revoked_at,omitempty"`
+}
+
+// TableName overrides the table name used by Session
+func (Session) TableName() string {
+	return "sessions"
+}
@@ -0,0 +1,73 @@
+package models
+
+import (
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+// SettlementBatchStatus represents how a batch compares with what the provider settled
+type SettlementBatchStatus string
+
+const (
+	SettlementBatchStatusPending    SettlementBatchStatus = "PENDING" // Waiting for the provider's settlement
+	SettlementBatchStatusMatched    SettlementBatchStatus = "MATCHED"
+	SettlementBatchStatusMismatched SettlementBatchStatus = "MISMATCHED"
+)
+
+// SettlementBatch groups the deposits and payouts a provider completed on one UTC day in one
+// currency. The provider owes the deposits and has paid out the payouts, so the net it is expected to
+// settle is their difference; finance records what was actually received from the provider statement
+type SettlementBatch struct {
+	ID           uint                  `json:"id" gorm:"primarykey"`
+	CreatedAt    time.Time             `json:"created_at"`
+	UpdatedAt    time.Time             `json:"updated_at"`
+	Provider     string                `json:"provider" gorm:"type:varchar(50);not null;uniqueIndex:idx_settlement_batch"`
+	Currency     string                `json:"currency" gorm:"type:varchar(3);not null;uniqueIndex:idx_settlement_batch"`
+	Date         time.Time             `json:"date" gorm:"type:date;not null;uniqueIndex:idx_settlement_batch"`
+	DepositCount int                   `json:"deposit_count" gorm:"not null;default:0"`
+	DepositTotal decimal.Decimal       `json:"deposit_total" gorm:"type:decimal(15,2);not null;default:0.00"`
+	PayoutCount  int                   `json:"payout_count" gorm:"not null;default:0"`
+	PayoutTotal  decimal.Decimal       `json:"payout_total" gorm:"type:decimal(15,2);not null;default:0.00"`
+	ExpectedNet  decimal.Decimal       `json:"expected_net" gorm:"type:decimal(15,2);not null;default:0.00"`
+	ReceivedNet  *decimal.Decimal      `json:"received_net,omitempty" gorm:"type:decimal(15,2)"`
+	Difference   *decimal.Decimal      `json:"difference,omitempty" gorm:"type:decimal(15,2)"` // Received minus expected
+	Status       SettlementBatchStatus `json:"status" gorm:"type:varchar(20);check:status IN ('PENDING','MATCHED','MISMATCHED');not null;default:'PENDING';index"`
+	Notes        string                `json:"notes,omitempty" gorm:"type:text"`
+	ReconciledBy *uint                 `json:"reconciled_by,omitempty"`
+	ReconciledAt *time.Time            `json:"reconciled_at,omitempty"`
+
+	// Relationships
+	Deposits []Deposit `json:"deposits,omitempty" gorm:"foreignKey:SettlementBatchID"`
+	Payouts  []Payout  `json:"payouts,omitempty" gorm:"foreignKey:SettlementBatchID"`
+}
+
+// TableName overrides the table name used by SettlementBatch
+func (SettlementBatch) TableName() string {
+	return "settlement_batches"
+}
+
+// AddDeposit counts a completed deposit towards the batch
+func (b *SettlementBatch) AddDeposit(amount decimal.Decimal) {
+	b.DepositCount++
+	b.DepositTotal = b.DepositTotal.Add(amount)
+	b.ExpectedNet = b.DepositTotal.Sub(b.PayoutTotal)
+}
+
+// AddPayout counts a completed payout towards the batch
+func (b *SettlementBatch) AddPayout(amount decimal.Decimal) {
+	b.PayoutCount++
+	b.PayoutTotal = b.PayoutTotal.Add(amount)
+	b.ExpectedNet = b.DepositTotal.Sub(b.PayoutTotal)
+}
+
+// SetReceived records the net the provider settled and compares it with the expected net
+func (b *SettlementBatch) SetReceived(received decimal.Decimal) {
+	difference := received.Sub(b.ExpectedNet)
+	b.ReceivedNet = &received
+	b.Difference = &difference
+	b.Status = SettlementBatchStatusMatched
+	if !difference.IsZero() {
+		b.Status = SettlementBatchStatusMismatched
+	}
+}
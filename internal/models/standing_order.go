@@ -0,0 +1,111 @@
+package models
+
+import (
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+// StandingOrderFrequency is how often a standing order debits its wallet
+type StandingOrderFrequency string
+
+const (
+	StandingOrderDaily   StandingOrderFrequency = "DAILY"
+	StandingOrderWeekly  StandingOrderFrequency = "WEEKLY"
+	StandingOrderMonthly StandingOrderFrequency = "MONTHLY"
+)
+
+// StandingOrderStatus represents whether a standing order still schedules occurrences
+type StandingOrderStatus string
+
+const (
+	StandingOrderStatusActive    StandingOrderStatus = "ACTIVE"
+	StandingOrderStatusCancelled StandingOrderStatus = "CANCELLED"
+	StandingOrderStatusCompleted StandingOrderStatus = "COMPLETED"
+)
+
+// StandingOrderRunStatus represents the state of a single occurrence of a standing order
+type StandingOrderRunStatus string
+
+const (
+	StandingOrderRunPending   StandingOrderRunStatus = "PENDING"
+	StandingOrderRunRetrying  StandingOrderRunStatus = "RETRYING"
+	StandingOrderRunSucceeded StandingOrderRunStatus = "SUCCEEDED"
+	StandingOrderRunFailed    StandingOrderRunStatus = "FAILED"
+)
+
+// StandingOrder is a recurring transfer from a user's wallet to another wallet
+type StandingOrder struct {
+	ID                  uint                   `json:"id" gorm:"primarykey"`
+	CreatedAt           time.Time              `json:"created_at"`
+	UpdatedAt           time.Time              `json:"updated_at"`
+	UserID              uint                   `json:"user_id" gorm:"not null;index"`
+	WalletID            uint                   `json:"wallet_id" gorm:"not null;index"`
+	DestinationWalletID uint                   `json:"destination_wallet_id" gorm:"not null"`
+	Amount              decimal.Decimal        `json:"amount" gorm:"type:decimal(15,2);not null"`
+	Currency            string                 `json:"currency" gorm:"type:varchar(3);not null"`
+	Description         string                 `json:"description" gorm:"type:text"`
+	Frequency           StandingOrderFrequency `json:"frequency" gorm:"type:varchar(20);check:frequency IN ('DAILY','WEEKLY','MONTHLY');not null"`
+	NextRunAt           time.Time              `json:"next_run_at" gorm:"not null;index"`
+	EndsAt              *time.Time             `json:"ends_at,omitempty"`
+	Status              StandingOrderStatus    `json:"status" gorm:"type:varchar(20);check:status IN ('ACTIVE','CANCELLED','COMPLETED');not null;default:'ACTIVE';index"`
+}
+
+// TableName overrides the table name used by StandingOrder
+func (StandingOrder) TableName() string {
+	return "standing_orders"
+}
+
+// IsActive checks if the standing order still schedules occurrences
+func (o *StandingOrder) IsActive() bool {
+	return o.Status == StandingOrderStatusActive
+}
+
+// NextOccurrence returns the occurrence that follows the given one
+func (o *StandingOrder) NextOccurrence(after time.Time) time.Time {
+	switch o.Frequency {
+	case StandingOrderDaily:
+		return after.AddDate(0, 0, 1)
+	case StandingOrderWeekly:
+		return after.AddDate(0, 0, 7)
+	default:
+		return after.AddDate(0, 1, 0)
+	}
+}
+
+// IsValidStandingOrderFrequency checks if the frequency is supported
+func IsValidStandingOrderFrequency(frequency StandingOrderFrequency) bool {
+	switch frequency {
+	case StandingOrderDaily, StandingOrderWeekly, StandingOrderMonthly:
+		return true
+	default:
+		return false
+	}
+}
+
+// StandingOrderRun is one scheduled occurrence of a standing order and its retry state
+type StandingOrderRun struct {
+	ID              uint                   `json:"id" gorm:"primarykey"`
+	CreatedAt       time.Time              `json:"created_at"`
+	UpdatedAt       time.Time              `json:"updated_at"`
+	StandingOrderID uint                   `json:"standing_order_id" gorm:"not null;uniqueIndex:idx_standing_order_run"`
+	ScheduledFor    time.Time              `json:"scheduled_for" gorm:"not null;uniqueIndex:idx_standing_order_run"`
+	Status          StandingOrderRunStatus `json:"status" gorm:"type:varchar(20);check:status IN ('PENDING','RETRYING','SUCCEEDED','FAILED');not null;default:'PENDING';index"`
+	Attempts        int                    `json:"attempts" gorm:"not null;default:0"`
+	NextAttemptAt   *time.Time             `json:"next_attempt_at,omitempty" gorm:"index"`
+	LastError       string                 `json:"last_error,omitempty" gorm:"type:text"`
+	TransactionID   *uint                  `json:"transaction_id,omitempty"`
+	CompletedAt     *time.Time             `json:"completed_at,omitempty"`
+
+	StandingOrder StandingOrder `json:"standing_order,omitempty" gorm:"foreignKey:StandingOrderID"`
+}
+
+// TableName overrides the table name used by StandingOrderRun
+func (StandingOrderRun) TableName() string {
+	return "standing_order_runs"
+}
+
+// IsOpen checks if the occurrence still has to be attempted
+func (r *StandingOrderRun) IsOpen() bool {
+	return r.Status == StandingOrderRunPending || r.Status == StandingOrderRunRetrying
+}
@@ -0,0 +1,138 @@
+package models
+
+import (
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+// BillingInterval is how often a subscription plan is charged
+type BillingInterval string
+
+const (
+	BillingIntervalWeekly  BillingInterval = "WEEKLY"
+	BillingIntervalMonthly BillingInterval = "MONTHLY"
+	BillingIntervalYearly  BillingInterval = "YEARLY"
+)
+
+// IsValidBillingInterval checks if the interval is supported
+func IsValidBillingInterval(interval BillingInterval) bool {
+	switch interval {
+	case BillingIntervalWeekly, BillingIntervalMonthly, BillingIntervalYearly:
+		return true
+	default:
+		return false
+	}
+}
+
+// Next returns the start of the billing period that follows the one starting at start
+func (i BillingInterval) Next(start time.Time) time.Time {
+	switch i {
+	case BillingIntervalWeekly:
+		return start.AddDate(0, 0, 7)
+	case BillingIntervalYearly:
+		return start.AddDate(1, 0, 0)
+	default:
+		return start.AddDate(0, 1, 0)
+	}
+}
+
+// SubscriptionPlan is a recurring price a merchant charges subscribers, paid into the merchant's wallet
+type SubscriptionPlan struct {
+	ID          uint            `json:"id" gorm:"primarykey"`
+	CreatedAt   time.Time       `json:"created_at"`
+	UpdatedAt   time.Time       `json:"updated_at"`
+	TenantID    uint            `json:"-" gorm:"not null;default:1;index"`
+	MerchantID  uint            `json:"merchant_id" gorm:"not null;index"`
+	WalletID    uint            `json:"wallet_id" gorm:"not null"`
+	Name        string          `json:"name" gorm:"type:varchar(100);not null"`
+	Description string          `json:"description" gorm:"type:text"`
+	Amount      decimal.Decimal `json:"amount" gorm:"type:decimal(15,2);not null"`
+	Currency    string          `json:"currency" gorm:"type:varchar(3);not null"`
+	Interval    BillingInterval `json:"interval" gorm:"type:varchar(20);check:interval IN ('WEEKLY','MONTHLY','YEARLY');not null"`
+	// Active plans take new subscribers; subscriptions to an archived plan keep being billed
+	Active bool `json:"active" gorm:"not null;default:true;index"`
+
+	Merchant User `json:"merchant,omitempty" gorm:"foreignKey:MerchantID"`
+}
+
+// TableName overrides the table name used by SubscriptionPlan
+func (SubscriptionPlan) TableName() string {
+	return "subscription_plans"
+}
+
+// SubscriptionStatus represents where a subscription stands with its payments
+type SubscriptionStatus string
+
+const (
+	SubscriptionStatusActive SubscriptionStatus = "ACTIVE"
+	// SubscriptionStatusPastDue subscriptions failed to pay their current period and are being retried
+	SubscriptionStatusPastDue SubscriptionStatus = "PAST_DUE"
+	// SubscriptionStatusSuspended subscriptions ran out of retries; they are not billed until resumed
+	SubscriptionStatusSuspended SubscriptionStatus = "SUSPENDED"
+	SubscriptionStatusCancelled SubscriptionStatus = "CANCELLED"
+)
+
+// Subscription is a user paying a plan from their wallet every billing period
+type Subscription struct {
+	ID        uint               `json:"id" gorm:"primarykey"`
+	CreatedAt time.Time          `json:"created_at"`
+	UpdatedAt time.Time          `json:"updated_at"`
+	TenantID  uint               `json:"-" gorm:"not null;default:1;index"`
+	Reference string             `json:"reference" gorm:"type:varchar(255);not null;uniqueIndex"`
+	PlanID    uint               `json:"plan_id" gorm:"not null;index"`
+	UserID    uint               `json:"user_id" gorm:"not null;index"`
+	WalletID  uint               `json:"wallet_id" gorm:"not null"`
+	Status    SubscriptionStatus `json:"status" gorm:"type:varchar(20);check:status IN ('ACTIVE','PAST_DUE','SUSPENDED','CANCELLED');not null;default:'ACTIVE';index"`
+	// PaidPeriods counts the billing periods paid; the charge of the next one is numbered after it
+	PaidPeriods int `json:"paid_periods" gorm:"not null;default:0"`
+	// PaidUntil is the end of the last period paid and the start of the next one to charge
+	PaidUntil time.Time `json:"paid_until" gorm:"not null"`
+	// NextBillingAt is when the next charge is attempted: PaidUntil, or the next retry of a failed charge
+	NextBillingAt  time.Time  `json:"next_billing_at" gorm:"not null;index"`
+	FailedAttempts int        `json:"failed_attempts" gorm:"not null;default:0"`
+	LastError      string     `json:"last_error,omitempty" gorm:"type:text"`
+	SuspendedAt    *time.Time `json:"suspended_at,omitempty"`
+	CancelledAt    *time.Time `json:"cancelled_at,omitempty"`
+
+	Plan SubscriptionPlan `json:"plan,omitempty" gorm:"foreignKey:PlanID"`
+}
+
+// TableName overrides the table name used by Subscription
+func (Subscription) TableName() string {
+	return "subscriptions"
+}
+
+// IsBillable checks if the subscription is still charged by the billing runner
+func (s *Subscription) IsBillable() bool {
+	return s.Status == SubscriptionStatusActive || s.Status == SubscriptionStatusPastDue
+}
+
+// SubscriptionChargeStatus represents the outcome of one attempt to charge a subscription
+type SubscriptionChargeStatus string
+
+const (
+	SubscriptionChargePaid   SubscriptionChargeStatus = "PAID"
+	SubscriptionChargeFailed SubscriptionChargeStatus = "FAILED"
+)
+
+// SubscriptionCharge is one attempt to charge a billing period of a subscription
+type SubscriptionCharge struct {
+	ID             uint                     `json:"id" gorm:"primarykey"`
+	CreatedAt      time.Time                `json:"created_at"`
+	SubscriptionID uint                     `json:"subscription_id" gorm:"not null;index"`
+	Reference      string                   `json:"reference" gorm:"type:varchar(255);not null;index"`
+	PeriodStart    time.Time                `json:"period_start" gorm:"not null"`
+	PeriodEnd      time.Time                `json:"period_end" gorm:"not null"`
+	Amount         decimal.Decimal          `json:"amount" gorm:"type:decimal(15,2);not null"`
+	Currency       string                   `json:"currency" gorm:"type:varchar(3);not null"`
+	Attempt        int                      `json:"attempt" gorm:"not null"`
+	Status         SubscriptionChargeStatus `json:"status" gorm:"type:varchar(20);check:status IN ('PAID','FAILED');not null"`
+	Error          string                   `json:"error,omitempty" gorm:"type:text"`
+	TransactionID  *uint                    `json:"transaction_id,omitempty"`
+}
+
+// TableName overrides the table name used by SubscriptionCharge
+func (SubscriptionCharge) TableName() string {
+	return "subscription_charges"
+}
@@ -0,0 +1,61 @@
+package models
+
+import (
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+// SuspenseItemStatus represents the progress of an unmatched credit through the exception queue
+type SuspenseItemStatus string
+
+const (
+	SuspenseItemStatusOpen          SuspenseItemStatus = "OPEN"
+	SuspenseItemStatusInvestigating SuspenseItemStatus = "INVESTIGATING"
+	SuspenseItemStatusReallocated   SuspenseItemStatus = "REALLOCATED" // Credited to a wallet
+	SuspenseItemStatusReturned      SuspenseItemStatus = "RETURNED"    // Sent back to the payer
+)
+
+// SuspenseReason explains why an inbound credit could not be applied to a wallet
+type SuspenseReason string
+
+const (
+	// SuspenseReasonUnknownReference is a provider credit whose reference matches no deposit
+	SuspenseReasonUnknownReference SuspenseReason = "UNKNOWN_REFERENCE"
+	// SuspenseReasonWalletInactive is a deposit whose wallet was closed, suspended or removed before it settled
+	SuspenseReasonWalletInactive SuspenseReason = "WALLET_INACTIVE"
+)
+
+// SuspenseItem is an inbound credit parked in the suspense ledger account because it could not be
+// matched to a wallet. The funds stay in suspense until an admin reallocates them to a wallet or
+// returns them to the payer
+type SuspenseItem struct {
+	ID                       uint               `json:"id" gorm:"primarykey"`
+	CreatedAt                time.Time          `json:"created_at"`
+	UpdatedAt                time.Time          `json:"updated_at"`
+	Provider                 string             `json:"provider" gorm:"type:varchar(50);not null;uniqueIndex:idx_suspense_provider_reference"`
+	ExternalReference        string             `json:"external_reference" gorm:"type:varchar(255);not null;uniqueIndex:idx_suspense_provider_reference"`
+	Amount                   decimal.Decimal    `json:"amount" gorm:"type:decimal(15,2);not null"`
+	Currency                 string             `json:"currency" gorm:"type:varchar(3);not null"`
+	Reason                   SuspenseReason     `json:"reason" gorm:"type:varchar(20);check:reason IN ('UNKNOWN_REFERENCE','WALLET_INACTIVE');not null"`
+	DepositID                *uint              `json:"deposit_id,omitempty" gorm:"index"` // The deposit that could not be credited, when there is one
+	WalletID                 *uint              `json:"wallet_id,omitempty" gorm:"index"`  // The wallet the credit was meant for, when known
+	Status                   SuspenseItemStatus `json:"status" gorm:"type:varchar(20);check:status IN ('OPEN','INVESTIGATING','REALLOCATED','RETURNED');not null;default:'OPEN';index"`
+	JournalEntryID           uint               `json:"journal_entry_id" gorm:"not null"` // The entry that parked the funds
+	Notes                    string             `json:"notes,omitempty" gorm:"type:text"`
+	InvestigatorID           *uint              `json:"investigator_id,omitempty"`
+	ReallocatedWalletID      *uint              `json:"reallocated_wallet_id,omitempty"`
+	ResolutionJournalEntryID *uint              `json:"resolution_journal_entry_id,omitempty"`
+	ResolvedBy               *uint              `json:"resolved_by,omitempty"`
+	ResolvedAt               *time.Time         `json:"resolved_at,omitempty"`
+}
+
+// TableName overrides the table name used by SuspenseItem
+func (SuspenseItem) TableName() string {
+	return "suspense_items"
+}
+
+// IsResolved checks if the funds have left the suspense account
+func (s *SuspenseItem) IsResolved() bool {
+	return s.Status == SuspenseItemStatusReallocated || s.Status == SuspenseItemStatusReturned
+}
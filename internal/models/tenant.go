@@ -0,0 +1,27 @@
+package models
+
+import "time"
+
+// DefaultTenantID is the tenant created with the database. Users and wallets that existed before
+// tenants were introduced belong to it, and so do requests that name no tenant
+const DefaultTenantID uint = 1
+
+// DefaultTenantSlug is the slug of the default tenant
+const DefaultTenantSlug = "default"
+
+// Tenant is a business sharing the deployment. Its users and wallets are only visible to requests
+// made for the tenant, and its transfers balance against system accounts of its own
+type Tenant struct {
+	ID        uint      `json:"id" gorm:"primarykey"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+	Name      string    `json:"name" gorm:"type:varchar(255);not null"`
+	// Slug names the tenant in the X-Tenant-ID header of requests made without a token
+	Slug   string `json:"slug" gorm:"type:varchar(50);not null;uniqueIndex"`
+	Active bool   `json:"active" gorm:"not null;default:true"`
+}
+
+// TableName overrides the table name used by Tenant
+func (Tenant) TableName() string {
+	return "tenants"
+}
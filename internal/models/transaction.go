@@ -7,7 +7,8 @@ import (
 	"gorm.io/gorm"
 )
 
-// TransactionType represents the type of transaction
+// TransactionType represents the type of transaction; the values are the names of the rows of the
+// transaction_types lookup table
 type TransactionType string
 
 // Transaction type constants
@@ -16,13 +17,18 @@ const (
 	TransactionTypeDebit  TransactionType = "DEBIT"
 )
 
-// TransactionPurpose represents the type of transaction
+// TransactionPurpose represents what a transaction is for; the values are the names of the rows of
+// the transaction_purposes lookup table
 type TransactionPurpose string
 
 const (
 	TransactionPurposeWalletTopUp TransactionPurpose = "WALLET_TOP_UP"
 	TransactionPurposeWithdrawal  TransactionPurpose = "WITHDRAWAL"
 	TransactionPurposeTransfer    TransactionPurpose = "TRANSFER"
+	TransactionPurposeFee         TransactionPurpose = "FEE"
+	TransactionPurposeAdjustment  TransactionPurpose = "ADJUSTMENT" // Manual journal entry between ledger accounts, or an approved manual wallet adjustment
+	TransactionPurposeInterest    TransactionPurpose = "INTEREST"
+	TransactionPurposeRefund      TransactionPurpose = "REFUND"
 )
 
 // Transaction represents a wallet transaction
@@ -33,28 +39,35 @@ type Transaction struct {
 	DeletedAt            gorm.DeletedAt     `json:"deleted_at,omitempty" gorm:"index"`
 	Reference            string             `json:"reference" gorm:"type:varchar(255);uniqueIndex;not null"`
 	WalletID             uint               `json:"wallet_id" gorm:"not null;index"`
-	TransactionPurpose   TransactionPurpose `json:"transaction_purpose,omitempty" gorm:"type:enum('WITHDRAWAL','WALLET_TOP_UP','TRANSFER');not null;"`
-	TransactionType      TransactionType    `json:"transaction_type,omitempty" gorm:"type:enum('CREDIT','DEBIT');not null;"`
+	TransactionPurpose   TransactionPurpose `json:"transaction_purpose,omitempty" gorm:"type:varchar(20);not null;index"`
+	TransactionType      TransactionType    `json:"transaction_type,omitempty" gorm:"type:varchar(10);not null;index"`
 	Amount               decimal.Decimal    `json:"amount" gorm:"type:decimal(15,2);not null;check:amount > 0"`
 	BalanceBefore        decimal.Decimal    `json:"balance_before" gorm:"type:decimal(15,2);not null"`
 	BalanceAfter         decimal.Decimal    `json:"balance_after" gorm:"type:decimal(15,2);not null"`
 	Description          string             `json:"description" gorm:"type:text"`
+	Category             string             `json:"category,omitempty" gorm:"type:varchar(50);index"` // Spending category the owner tagged the transaction with
 	Metadata             string             `json:"metadata" gorm:"type:json"`
-	Status               TransactionStatus  `json:"status" gorm:"type:enum('PENDING','COMPLETED','FAILED','CANCELLED');not null;default:'PENDING'"`
+	Status               TransactionStatus  `json:"status" gorm:"type:varchar(20);check:status IN ('PENDING','PENDING_REVIEW','COMPLETED','FAILED','CANCELLED');not null;default:'PENDING'"`
 	RelatedTransactionID *uint              `json:"related_transaction_id,omitempty" gorm:"index"`
+	JournalEntryID       *uint              `json:"journal_entry_id,omitempty" gorm:"index"`         // Set on the legs of a multi-leg journal entry
+	OriginIP             string             `json:"origin_ip,omitempty" gorm:"type:varchar(45)"`     // Where a user-initiated debit was requested from
+	OriginCountry        string             `json:"origin_country,omitempty" gorm:"type:varchar(2)"` // ISO country code reported for OriginIP
 
-	Wallet             Wallet       `json:"wallet,omitempty" gorm:"foreignKey:WalletID"`
-	RelatedTransaction *Transaction `json:"related_transaction,omitempty" gorm:"foreignKey:RelatedTransactionID"`
+	Wallet             Wallet                        `json:"wallet,omitempty" gorm:"foreignKey:WalletID"`
+	RelatedTransaction *Transaction                  `json:"related_transaction,omitempty" gorm:"foreignKey:RelatedTransactionID"`
+	TypeDefinition     *TransactionTypeDefinition    `json:"-" gorm:"foreignKey:TransactionType;references:Name;constraint:OnUpdate:CASCADE,OnDelete:RESTRICT"`
+	PurposeDefinition  *TransactionPurposeDefinition `json:"-" gorm:"foreignKey:TransactionPurpose;references:Name;constraint:OnUpdate:CASCADE,OnDelete:RESTRICT"`
 }
 
 // TransactionStatus represents the status of a transaction
 type TransactionStatus string
 
 const (
-	TransactionStatusPending   TransactionStatus = "PENDING"
-	TransactionStatusCompleted TransactionStatus = "COMPLETED"
-	TransactionStatusFailed    TransactionStatus = "FAILED"
-	TransactionStatusCancelled TransactionStatus = "CANCELLED"
+	TransactionStatusPending       TransactionStatus = "PENDING"
+	TransactionStatusPendingReview TransactionStatus = "PENDING_REVIEW" // Held by a fraud rule until an admin reviews it
+	TransactionStatusCompleted     TransactionStatus = "COMPLETED"
+	TransactionStatusFailed        TransactionStatus = "FAILED"
+	TransactionStatusCancelled     TransactionStatus = "CANCELLED"
 )
 
 // TableName overrides the table name used by Transaction
@@ -0,0 +1,50 @@
+package models
+
+import "time"
+
+// TransactionPurposeDefinition is a row of the transaction_purposes lookup table. Transactions
+// reference it by name, so new products get a purpose by adding a row rather than changing the schema.
+// The hooks tune how transactions of the purpose are handled: ChargesFees applies the wallet tier's
+// fees to its withdrawals and transfers, CountsTowardLimits counts its debits against the tier's
+// limits, and NotificationTemplate is the text/template of the subject of the emails sent about its
+// transactions, rendered with the recipient's Name and the Event
+type TransactionPurposeDefinition struct {
+	Name                 TransactionPurpose `json:"name" gorm:"type:varchar(20);primaryKey"`
+	Description          string             `json:"description" gorm:"type:varchar(255)"`
+	ChargesFees          bool               `json:"charges_fees" gorm:"not null"`
+	CountsTowardLimits   bool               `json:"counts_toward_limits" gorm:"not null"`
+	NotificationTemplate string             `json:"notification_template,omitempty" gorm:"type:varchar(255)"`
+	CreatedAt            time.Time          `json:"created_at"`
+	UpdatedAt            time.Time          `json:"updated_at"`
+}
+
+// TableName overrides the table name used by TransactionPurposeDefinition
+func (TransactionPurposeDefinition) TableName() string {
+	return "transaction_purposes"
+}
+
+// DefaultTransactionPurposes returns the transaction purposes every database is seeded with. The
+// service posts transactions with each of them, so they cannot be deleted
+func DefaultTransactionPurposes() []TransactionPurposeDefinition {
+	return []TransactionPurposeDefinition{
+		{Name: TransactionPurposeWalletTopUp, Description: "Funding of a wallet", CountsTowardLimits: true},
+		{Name: TransactionPurposeWithdrawal, Description: "Withdrawal to a bank account", ChargesFees: true, CountsTowardLimits: true},
+		{Name: TransactionPurposeTransfer, Description: "Transfer between wallets", ChargesFees: true, CountsTowardLimits: true},
+		{Name: TransactionPurposeFee, Description: "Fee charged for a transaction"},
+		{Name: TransactionPurposeAdjustment, Description: "Manual journal entry or wallet adjustment"},
+		{Name: TransactionPurposeInterest, Description: "Interest paid on a balance",
+			NotificationTemplate: "Interest of {{.Event.Amount.StringFixed 2}} {{.Event.Currency}} was paid to your wallet"},
+		{Name: TransactionPurposeRefund, Description: "Refund of an earlier payment",
+			NotificationTemplate: "You were refunded {{.Event.Amount.StringFixed 2}} {{.Event.Currency}}"},
+	}
+}
+
+// IsDefaultTransactionPurpose checks if a purpose is one the database is seeded with
+func IsDefaultTransactionPurpose(name TransactionPurpose) bool {
+	for _, definition := range DefaultTransactionPurposes() {
+		if definition.Name == name {
+			return true
+		}
+	}
+	return false
+}
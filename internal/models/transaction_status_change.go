@@ -0,0 +1,19 @@
+package models
+
+import "time"
+
+// TransactionStatusChange records a transaction moving from one status to another after it was
+// created. The first change's FromStatus is the status the transaction was created with
+type TransactionStatusChange struct {
+	ID            uint              `json:"id" gorm:"primarykey"`
+	CreatedAt     time.Time         `json:"created_at"`
+	TransactionID uint              `json:"transaction_id" gorm:"not null;index"`
+	FromStatus    TransactionStatus `json:"from_status" gorm:"type:varchar(20);not null"`
+	ToStatus      TransactionStatus `json:"to_status" gorm:"type:varchar(20);not null"`
+	Reason        string            `json:"reason,omitempty" gorm:"type:varchar(255)"`
+}
+
+// TableName overrides the table name used by TransactionStatusChange
+func (TransactionStatusChange) TableName() string {
+	return "transaction_status_changes"
+}
@@ -0,0 +1,24 @@
+package models
+
+import "time"
+
+// TransactionTypeDefinition is a row of the transaction_types lookup table. Transactions reference it
+// by name, so the database only accepts the types defined here
+type TransactionTypeDefinition struct {
+	Name        TransactionType `json:"name" gorm:"type:varchar(10);primaryKey"`
+	Description string          `json:"description" gorm:"type:varchar(255)"`
+	CreatedAt   time.Time       `json:"created_at"`
+}
+
+// TableName overrides the table name used by TransactionTypeDefinition
+func (TransactionTypeDefinition) TableName() string {
+	return "transaction_types"
+}
+
+// DefaultTransactionTypes returns the transaction types every database is seeded with
+func DefaultTransactionTypes() []TransactionTypeDefinition {
+	return []TransactionTypeDefinition{
+		{Name: TransactionTypeCredit, Description: "Money into the wallet"},
+		{Name: TransactionTypeDebit, Description: "Money out of the wallet"},
+	}
+}
@@ -0,0 +1,54 @@
+package models
+
+import (
+	"time"
+
+	"github.com/shopspring/decimal"
+	"golang.org/x/crypto/bcrypt"
+)
+
+// MaxTransferChallengeAttempts is the number of wrong codes accepted before a challenge is invalidated
+const MaxTransferChallengeAttempts = 5
+
+// TransferChallenge holds a large transfer until the user confirms it with the one-time code sent to
+// their verified phone
+type TransferChallenge struct {
+	ID           uint            `json:"id" gorm:"primarykey"`
+	CreatedAt    time.Time       `json:"created_at"`
+	UserID       uint            `json:"user_id" gorm:"not null;index"`
+	FromWalletID uint            `json:"from_wallet_id" gorm:"not null"`
+	ToWalletID   uint            `json:"to_wallet_id" gorm:"not null"`
+	Amount       decimal.Decimal `json:"amount" gorm:"type:decimal(15,2);not null"`
+	Reference    string          `json:"reference" gorm:"type:varchar(255);not null"`
+	Description  string          `json:"description" gorm:"type:text"`
+	QuoteID      *uint           `json:"quote_id,omitempty"` // Quote whose terms the transfer is made with
+	CodeHash     string          `json:"-" gorm:"type:varchar(255);not null"`
+	Attempts     int             `json:"attempts" gorm:"not null;default:0"`
+	ExpiresAt    time.Time       `json:"expires_at" gorm:"not null"`
+	ConfirmedAt  *time.Time      `json:"confirmed_at,omitempty"`
+}
+
+// TableName overrides the table name used by TransferChallenge
+func (TransferChallenge) TableName() string {
+	return "transfer_challenges"
+}
+
+// SetCode hashes and stores the confirmation code
+func (c *TransferChallenge) SetCode(code string) error {
+	hashed, err := bcrypt.GenerateFromPassword([]byte(code), bcrypt.DefaultCost)
+	if err != nil {
+		return err
+	}
+	c.CodeHash = string(hashed)
+	return nil
+}
+
+// CheckCode verifies the code against the stored hash
+func (c *TransferChallenge) CheckCode(code string) bool {
+	return bcrypt.CompareHashAndPassword([]byte(c.CodeHash), []byte(code)) == nil
+}
+
+// IsUsable checks if the challenge can still be confirmed at the given time
+func (c *TransferChallenge) IsUsable(now time.Time) bool {
+	return c.ConfirmedAt == nil && c.Attempts < MaxTransferChallengeAttempts && now.Before(c.ExpiresAt)
+}
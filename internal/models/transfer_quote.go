@@ -0,0 +1,41 @@
+package models
+
+import (
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+// TransferQuote holds the terms a transfer was previewed with: its fee and, between wallets of
+// different currencies, the exchange rate and the amount the recipient is credited. A transfer made
+// with the quote before it expires keeps these terms
+type TransferQuote struct {
+	ID             uint            `json:"id" gorm:"primarykey"`
+	CreatedAt      time.Time       `json:"created_at"`
+	UserID         uint            `json:"user_id" gorm:"not null;index"`
+	FromWalletID   uint            `json:"from_wallet_id" gorm:"not null"`
+	ToWalletID     uint            `json:"to_wallet_id" gorm:"not null"`
+	Amount         decimal.Decimal `json:"amount" gorm:"type:decimal(15,2);not null"`
+	Fee            decimal.Decimal `json:"fee" gorm:"type:decimal(15,2);not null"`
+	SourceCurrency string          `json:"source_currency" gorm:"type:varchar(3);not null"`
+	TargetCurrency string          `json:"target_currency" gorm:"type:varchar(3);not null"`
+	ExchangeRate   decimal.Decimal `json:"exchange_rate" gorm:"type:decimal(20,8);not null"`
+	CreditAmount   decimal.Decimal `json:"credit_amount" gorm:"type:decimal(15,2);not null"` // Amount credited to the recipient, in TargetCurrency
+	ExpiresAt      time.Time       `json:"expires_at" gorm:"not null;index"`
+	UsedAt         *time.Time      `json:"used_at,omitempty"`
+}
+
+// TableName overrides the table name used by TransferQuote
+func (TransferQuote) TableName() string {
+	return "transfer_quotes"
+}
+
+// TotalDebit is the amount and fee taken from the sender's wallet
+func (q *TransferQuote) TotalDebit() decimal.Decimal {
+	return q.Amount.Add(q.Fee)
+}
+
+// IsUsable checks if the quote can still be used at the given time
+func (q *TransferQuote) IsUsable(now time.Time) bool {
+	return q.UsedAt == nil && now.Before(q.ExpiresAt)
+}
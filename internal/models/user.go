@@ -1,8 +1,10 @@
 package models
 
 import (
+	"fmt"
 	"time"
 
+	"github.com/shopspring/decimal"
 	"golang.org/x/crypto/bcrypt"
 	"gorm.io/gorm"
 )
@@ -11,6 +13,27 @@ import (
 const (
 	SystemAccountEmail = "system@wallet.internal"
 	SystemAccountName  = "System Account"
+
+	// SandboxSystemAccountEmail owns the sandbox system wallet that backs test money
+	SandboxSystemAccountEmail = "sandbox-system@wallet.internal"
+	SandboxSystemAccountName  = "Sandbox System Account"
+
+	// DeletedUserName replaces the name of users whose account was erased
+	DeletedUserName   = "Deleted User"
+	deletedUserDomain = "deleted.invalid"
+)
+
+// SystemWalletOpeningBalance is the balance system wallets are created with
+var SystemWalletOpeningBalance = decimal.NewFromInt(1000000000)
+
+// UserRole represents the access level of a user
+type UserRole string
+
+const (
+	UserRoleUser  UserRole = "USER"
+	UserRoleAdmin UserRole = "ADMIN"
+	// UserRoleSupport grants read-only access to admin views for support staff
+	UserRoleSupport UserRole = "SUPPORT"
 )
 
 type User struct {
@@ -19,10 +42,52 @@ type User struct {
 	UpdatedAt time.Time      `json:"updated_at"`
 	DeletedAt gorm.DeletedAt `json:"deleted_at,omitempty" gorm:"index"`
 	Name      string         `json:"name" gorm:"type:varchar(255);not null" validate:"required,min=2,max=100"`
-	Email     string         `json:"email" gorm:"type:varchar(255);uniqueIndex;not null" validate:"required,email"`
+	TenantID  uint           `json:"tenant_id" gorm:"not null;default:1;uniqueIndex:idx_user_tenant_email,priority:1;uniqueIndex:idx_user_tenant_handle,priority:1"`
+	Email     string         `json:"email" gorm:"type:varchar(255);uniqueIndex:idx_user_tenant_email,priority:2;not null" validate:"required,email"`
 	Password  string         `json:"-" gorm:"type:varchar(255);not null" validate:"required,min=6"` // "-" excludes from JSON serialization
 	Age       int            `json:"age" validate:"omitempty,gte=0,lte=150"`
 	IsSystem  bool           `json:"is_system" gorm:"default:false;index"` // For system accounts
+	Role      UserRole       `json:"role" gorm:"type:varchar(20);not null;default:'USER';index"`
+
+	PhoneNumber     string     `json:"phone_number,omitempty" gorm:"type:varchar(20);index"`
+	PhoneVerifiedAt *time.Time `json:"phone_verified_at,omitempty"`
+
+	// Profile details needed for KYC; DateOfBirth only carries a date and Country is an ISO 3166-1
+	// alpha-2 code
+	DateOfBirth  *time.Time `json:"date_of_birth,omitempty" gorm:"type:date"`
+	AddressLine1 string     `json:"address_line1,omitempty" gorm:"type:varchar(255)"`
+	AddressLine2 string     `json:"address_line2,omitempty" gorm:"type:varchar(255)"`
+	City         string     `json:"city,omitempty" gorm:"type:varchar(100)"`
+	State        string     `json:"state,omitempty" gorm:"type:varchar(100)"`
+	PostalCode   string     `json:"postal_code,omitempty" gorm:"type:varchar(20)"`
+	Country      string     `json:"country,omitempty" gorm:"type:varchar(2)"`
+	// Handle is the unique name, without its @, other users pay the user by instead of a wallet ID
+	Handle *string `json:"handle,omitempty" gorm:"type:varchar(30);uniqueIndex:idx_user_tenant_handle,priority:2"`
+	// DisplayCurrency is the currency balances and spending are also shown in, at indicative rates
+	DisplayCurrency string `json:"display_currency,omitempty" gorm:"type:varchar(3)"`
+	// AvatarKey is where the profile picture is stored; AvatarUpdatedAt tells clients when to fetch it again
+	AvatarKey       string     `json:"-" gorm:"type:varchar(255)"`
+	AvatarUpdatedAt *time.Time `json:"avatar_updated_at,omitempty"`
+
+	// TokensRevokedAt revokes every token issued up to this time, such as on a password change
+	TokensRevokedAt *time.Time `json:"-"`
+
+	// FailedLoginAttempts counts wrong passwords in a row; reaching the limit locks the account
+	// until LockedUntil
+	FailedLoginAttempts int        `json:"failed_login_attempts" gorm:"not null;default:0"`
+	LockedUntil         *time.Time `json:"locked_until,omitempty"`
+
+	// TransactionPIN is the bcrypt hash of the optional PIN confirming debits; wrong PINs in a row
+	// lock it until PINLockedUntil
+	TransactionPIN    string     `json:"-" gorm:"type:varchar(255)"`
+	FailedPINAttempts int        `json:"-" gorm:"not null;default:0"`
+	PINLockedUntil    *time.Time `json:"-"`
+
+	// DeletionRequestedAt is when the user asked for their account to be erased; they cannot log in
+	// from then on. AnonymizedAt is when their personal data was removed after the grace period, while
+	// wallets and transactions are kept for regulatory retention
+	DeletionRequestedAt *time.Time `json:"deletion_requested_at,omitempty" gorm:"index"`
+	AnonymizedAt        *time.Time `json:"anonymized_at,omitempty"`
 
 	// Relationships
 	Wallets []Wallet `json:"wallets,omitempty" gorm:"foreignKey:UserID"`
@@ -53,6 +118,79 @@ func (u *User) IsSystemAccount() bool {
 	return u.IsSystem
 }
 
+// IsAdmin checks if the user has administrative access
+func (u *User) IsAdmin() bool {
+	return u.Role == UserRoleAdmin
+}
+
+// IsLocked checks if the account is locked out of password logins at the given time
+func (u *User) IsLocked(now time.Time) bool {
+	return u.LockedUntil != nil && now.Before(*u.LockedUntil)
+}
+
+// HasTransactionPIN checks if the user set a transaction PIN
+func (u *User) HasTransactionPIN() bool {
+	return u.TransactionPIN != ""
+}
+
+// SetTransactionPIN hashes the transaction PIN using bcrypt
+func (u *User) SetTransactionPIN(pin string) error {
+	hashedPIN, err := bcrypt.GenerateFromPassword([]byte(pin), bcrypt.DefaultCost)
+	if err != nil {
+		return err
+	}
+	u.TransactionPIN = string(hashedPIN)
+	return nil
+}
+
+// CheckTransactionPIN verifies the PIN against the hashed transaction PIN
+func (u *User) CheckTransactionPIN(pin string) error {
+	return bcrypt.CompareHashAndPassword([]byte(u.TransactionPIN), []byte(pin))
+}
+
+// IsPINLocked checks if the transaction PIN is locked at the given time
+func (u *User) IsPINLocked(now time.Time) bool {
+	return u.PINLockedUntil != nil && now.Before(*u.PINLockedUntil)
+}
+
+// HasVerifiedPhone checks if the user has a verified phone number
+func (u *User) HasVerifiedPhone() bool {
+	return u.PhoneNumber != "" && u.PhoneVerifiedAt != nil
+}
+
+// HasAvatar checks if the user uploaded a profile picture
+func (u *User) HasAvatar() bool {
+	return u.AvatarKey != ""
+}
+
+// IsDeletionRequested checks if the user asked for their account to be erased
+func (u *User) IsDeletionRequested() bool {
+	return u.DeletionRequestedAt != nil
+}
+
+// Anonymize removes the personal data of the user, keeping the record their wallets and transactions
+// refer to. The email is replaced by one that is unique and cannot receive mail
+func (u *User) Anonymize(at time.Time) {
+	u.Name = DeletedUserName
+	u.Email = fmt.Sprintf("deleted-%d@%s", u.ID, deletedUserDomain)
+	u.Age = 0
+	u.PhoneNumber = ""
+	u.PhoneVerifiedAt = nil
+	u.DateOfBirth = nil
+	u.AddressLine1 = ""
+	u.AddressLine2 = ""
+	u.City = ""
+	u.State = ""
+	u.PostalCode = ""
+	u.Country = ""
+	u.DisplayCurrency = ""
+	u.Handle = nil
+	u.AvatarKey = ""
+	u.AvatarUpdatedAt = nil
+	u.TransactionPIN = ""
+	u.AnonymizedAt = &at
+}
+
 // CreateSystemUser creates a system user instance
 func CreateSystemUser() *User {
 	return &User{
@@ -60,5 +198,14 @@ func CreateSystemUser() *User {
 		Email:    SystemAccountEmail,
 		Password: "system-account-password", // This will be hashed
 		IsSystem: true,
+		Role:     UserRoleUser,
 	}
 }
+
+// CreateSandboxSystemUser creates the system user instance for sandbox ledgers
+func CreateSandboxSystemUser() *User {
+	user := CreateSystemUser()
+	user.Name = SandboxSystemAccountName
+	user.Email = SandboxSystemAccountEmail
+	return user
+}
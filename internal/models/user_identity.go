@@ -0,0 +1,19 @@
+package models
+
+import "time"
+
+// UserIdentity links a user to an account at an external identity provider such as Google, so the
+// user can sign in with it
+type UserIdentity struct {
+	ID        uint      `json:"id" gorm:"primarykey"`
+	CreatedAt time.Time `json:"created_at"`
+	UserID    uint      `json:"user_id" gorm:"not null;index"`
+	Provider  string    `json:"provider" gorm:"type:varchar(50);not null;uniqueIndex:idx_user_identity_subject"`
+	Subject   string    `json:"subject" gorm:"type:varchar(255);not null;uniqueIndex:idx_user_identity_subject"` // Account ID at the provider
+	Email     string    `json:"email" gorm:"type:varchar(255)"`                                                  // Email reported by the provider when linked
+}
+
+// TableName overrides the table name used by UserIdentity
+func (UserIdentity) TableName() string {
+	return "user_identities"
+}
@@ -1,6 +1,8 @@
 package models
 
 import (
+	"fmt"
+	"strconv"
 	"time"
 
 	"github.com/shopspring/decimal"
@@ -14,13 +16,19 @@ type Wallet struct {
 	UpdatedAt time.Time       `json:"updated_at"`
 	DeletedAt gorm.DeletedAt  `json:"deleted_at,omitempty" gorm:"index"`
 	UserID    uint            `json:"user_id" gorm:"not null;index"`
+	TenantID  uint            `json:"tenant_id" gorm:"not null;default:1;index"`
 	Balance   decimal.Decimal `json:"balance" gorm:"type:decimal(15,2);not null;default:0.00;check:balance >= 0"`
 	Currency  string          `json:"currency" gorm:"type:varchar(3);not null;default:'USD'"`
-	Status    WalletStatus    `json:"status" gorm:"type:enum('ACTIVE','SUSPENDED','CLOSED');not null;default:'ACTIVE'"`
-	Version   uint            `json:"version" gorm:"not null;default:0"` // For optimistic locking
+	Status    WalletStatus    `json:"status" gorm:"type:varchar(20);check:status IN ('ACTIVE','SUSPENDED','CLOSED');not null;default:'ACTIVE'"`
+	Version   uint            `json:"version" gorm:"not null;default:0"`           // For optimistic locking
+	Sandbox   bool            `json:"sandbox" gorm:"not null;default:false;index"` // Test money isolated from live ledgers
+	TierID    *uint           `json:"tier_id,omitempty" gorm:"index"`              // Nil uses the default tier
+	Nickname  string          `json:"nickname,omitempty" gorm:"type:varchar(50)"`  // Label the owner gave the wallet, such as "Rent"
+	IsDefault bool            `json:"is_default" gorm:"not null;default:false"`    // The live wallet payments to the user and fundings land in
 
 	// Relationships
 	User         User          `json:"user,omitempty" gorm:"foreignKey:UserID"`
+	Tier         *WalletTier   `json:"tier,omitempty" gorm:"foreignKey:TierID"`
 	Transactions []Transaction `json:"transactions,omitempty" gorm:"foreignKey:WalletID"`
 }
 
@@ -47,3 +55,9 @@ func (w *Wallet) IsActive() bool {
 func (w *Wallet) CanDebit(amount decimal.Decimal) bool {
 	return w.IsActive() && w.Balance.GreaterThanOrEqual(amount)
 }
+
+// ETag identifies this state of the wallet for conditional requests. The version moves with every
+// balance change; the update time covers status and tier changes, which keep the version
+func (w *Wallet) ETag() string {
+	return fmt.Sprintf(`W/"%d-%d-%s"`, w.ID, w.Version, strconv.FormatInt(w.UpdatedAt.UnixNano(), 36))
+}
@@ -0,0 +1,55 @@
+package models
+
+import (
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+// WalletTier is a plan attached to wallets that sets their fees, limits and available features
+type WalletTier struct {
+	ID          uint      `json:"id" gorm:"primarykey"`
+	CreatedAt   time.Time `json:"created_at"`
+	UpdatedAt   time.Time `json:"updated_at"`
+	Name        string    `json:"name" gorm:"type:varchar(50);uniqueIndex;not null"`
+	Description string    `json:"description" gorm:"type:text"`
+	IsDefault   bool      `json:"is_default" gorm:"not null;default:false"` // Applies to wallets without an assigned tier
+
+	// Fees are a flat amount plus a percentage of the amount moved, charged to the sender
+	TransferFeeFlat      decimal.Decimal `json:"transfer_fee_flat" gorm:"type:decimal(15,2);not null;default:0.00"`
+	TransferFeePercent   decimal.Decimal `json:"transfer_fee_percent" gorm:"type:decimal(5,2);not null;default:0.00"`
+	WithdrawalFeeFlat    decimal.Decimal `json:"withdrawal_fee_flat" gorm:"type:decimal(15,2);not null;default:0.00"`
+	WithdrawalFeePercent decimal.Decimal `json:"withdrawal_fee_percent" gorm:"type:decimal(5,2);not null;default:0.00"`
+
+	// Limits are unlimited when nil
+	MaxTransactionAmount *decimal.Decimal `json:"max_transaction_amount,omitempty" gorm:"type:decimal(15,2)"`
+	DailyDebitLimit      *decimal.Decimal `json:"daily_debit_limit,omitempty" gorm:"type:decimal(15,2)"`
+	MonthlyDebitLimit    *decimal.Decimal `json:"monthly_debit_limit,omitempty" gorm:"type:decimal(15,2)"`
+	MaxBalance           *decimal.Decimal `json:"max_balance,omitempty" gorm:"type:decimal(15,2)"`
+
+	PaymentLinksEnabled   bool `json:"payment_links_enabled" gorm:"not null"`
+	QRPaymentsEnabled     bool `json:"qr_payments_enabled" gorm:"not null"`
+	StandingOrdersEnabled bool `json:"standing_orders_enabled" gorm:"not null"`
+}
+
+// DefaultWalletTierName is the tier created on startup for wallets without an assigned tier
+const DefaultWalletTierName = "standard"
+
+// TableName overrides the table name used by WalletTier
+func (WalletTier) TableName() string {
+	return "wallet_tiers"
+}
+
+// TransferFee returns the fee charged to the sender of a transfer
+func (t *WalletTier) TransferFee(amount decimal.Decimal) decimal.Decimal {
+	return tierFee(amount, t.TransferFeeFlat, t.TransferFeePercent)
+}
+
+// WithdrawalFee returns the fee charged on a withdrawal
+func (t *WalletTier) WithdrawalFee(amount decimal.Decimal) decimal.Decimal {
+	return tierFee(amount, t.WithdrawalFeeFlat, t.WithdrawalFeePercent)
+}
+
+func tierFee(amount, flat, percent decimal.Decimal) decimal.Decimal {
+	return flat.Add(amount.Mul(percent).Div(decimal.NewFromInt(100))).Round(2)
+}
@@ -0,0 +1,110 @@
+package models
+
+import (
+	"strings"
+	"time"
+)
+
+// WebhookSubscription is an endpoint of an integrator that receives the events of the user's wallets
+// as signed POST requests
+type WebhookSubscription struct {
+	ID        uint      `json:"id" gorm:"primarykey"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+	TenantID  uint      `json:"-" gorm:"not null;default:1;index"`
+	UserID    uint      `json:"user_id" gorm:"not null;index"`
+	URL       string    `json:"url" gorm:"type:varchar(2048);not null"`
+	// Secret signs the body of every delivery; it is only shown when the subscription is created
+	Secret string `json:"-" gorm:"type:varchar(64);not null"`
+	// EventTypes is a comma separated list of the event types delivered; empty means every event
+	EventTypes string `json:"event_types" gorm:"type:text"`
+	Active     bool   `json:"active" gorm:"not null;default:true;index"`
+}
+
+// TableName overrides the table name used by WebhookSubscription
+func (WebhookSubscription) TableName() string {
+	return "webhook_subscriptions"
+}
+
+// Types returns the event types the subscription is limited to, or nil for every event
+func (s *WebhookSubscription) Types() []string {
+	if s.EventTypes == "" {
+		return nil
+	}
+	return strings.Split(s.EventTypes, ",")
+}
+
+// Wants checks if events of the given type are delivered to the subscription
+func (s *WebhookSubscription) Wants(eventType string) bool {
+	types := s.Types()
+	if types == nil {
+		return true
+	}
+	for _, t := range types {
+		if t == eventType {
+			return true
+		}
+	}
+	return false
+}
+
+// WebhookDeliveryStatus represents where the delivery of an event to a webhook stands
+type WebhookDeliveryStatus string
+
+const (
+	WebhookDeliveryPending   WebhookDeliveryStatus = "PENDING"   // Waiting for NextAttemptAt, including retries
+	WebhookDeliveryDelivered WebhookDeliveryStatus = "DELIVERED" // The endpoint answered with a 2xx status
+	WebhookDeliveryDead      WebhookDeliveryStatus = "DEAD"      // Failed every attempt; kept until an admin redelivers it
+)
+
+// WebhookDelivery is one event sent to one webhook subscription. Deliveries that fail every attempt
+// stay in the table as the dead letter queue of webhooks
+type WebhookDelivery struct {
+	ID             uint      `json:"id" gorm:"primarykey"`
+	CreatedAt      time.Time `json:"created_at"`
+	UpdatedAt      time.Time `json:"updated_at"`
+	TenantID       uint      `json:"-" gorm:"not null;default:1;index"`
+	SubscriptionID uint      `json:"subscription_id" gorm:"not null;index"`
+	EventID        uint      `json:"event_id" gorm:"index"`
+	EventType      string    `json:"event_type" gorm:"type:varchar(100);not null;index"`
+	// Replay deliveries were queued again on request rather than when the event happened
+	Replay         bool                  `json:"replay" gorm:"not null;default:false"`
+	Payload        string                `json:"payload" gorm:"type:text;not null"` // JSON body posted to the endpoint
+	Status         WebhookDeliveryStatus `json:"status" gorm:"type:varchar(20);check:status IN ('PENDING','DELIVERED','DEAD');not null;default:'PENDING';index:idx_webhook_delivery_status_next"`
+	Attempts       int                   `json:"attempts" gorm:"not null;default:0"`
+	NextAttemptAt  time.Time             `json:"next_attempt_at" gorm:"not null;index:idx_webhook_delivery_status_next"`
+	LastStatusCode int                   `json:"last_status_code,omitempty"`
+	LastDurationMS int64                 `json:"last_duration_ms,omitempty"` // How long the endpoint took to answer the last attempt
+	LastError      string                `json:"last_error,omitempty" gorm:"type:text"`
+	DeliveredAt    *time.Time            `json:"delivered_at,omitempty"`
+	DeadAt         *time.Time            `json:"dead_at,omitempty"`
+
+	Subscription WebhookSubscription `json:"subscription,omitempty" gorm:"foreignKey:SubscriptionID"`
+}
+
+// TableName overrides the table name used by WebhookDelivery
+func (WebhookDelivery) TableName() string {
+	return "webhook_deliveries"
+}
+
+// IsDead checks if the delivery exhausted its attempts
+func (d *WebhookDelivery) IsDead() bool {
+	return d.Status == WebhookDeliveryDead
+}
+
+// WebhookDeliveryAttempt records one request made to deliver an event and how the endpoint answered
+type WebhookDeliveryAttempt struct {
+	ID         uint      `json:"id" gorm:"primarykey"`
+	CreatedAt  time.Time `json:"created_at"`
+	DeliveryID uint      `json:"delivery_id" gorm:"not null;index"`
+	Attempt    int       `json:"attempt" gorm:"not null"`
+	// StatusCode is zero when no response was received
+	StatusCode int    `json:"status_code,omitempty"`
+	Error      string `json:"error,omitempty" gorm:"type:text"`
+	DurationMS int64  `json:"duration_ms" gorm:"not null"`
+}
+
+// TableName overrides the table name used by WebhookDeliveryAttempt
+func (WebhookDeliveryAttempt) TableName() string {
+	return "webhook_delivery_attempts"
+}
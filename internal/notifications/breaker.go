@@ -0,0 +1,77 @@
+package notifications
+
+import "github.com/limistah/wallet-service/internal/breaker"
+
+// NewBreakerEmailSender guards an email sender with a circuit breaker
+func NewBreakerEmailSender(sender EmailSender, b *breaker.Breaker) EmailSender {
+	return &breakerEmailSender{sender: sender, breaker: b}
+}
+
+type breakerEmailSender struct {
+	sender  EmailSender
+	breaker *breaker.Breaker
+}
+
+func (s *breakerEmailSender) Send(message EmailMessage) error {
+	return s.breaker.Execute(func() error {
+		return s.sender.Send(message)
+	})
+}
+
+// NewBreakerSMSSender guards an SMS sender with a circuit breaker
+func NewBreakerSMSSender(sender SMSSender, b *breaker.Breaker) SMSSender {
+	return &breakerSMSSender{sender: sender, breaker: b}
+}
+
+type breakerSMSSender struct {
+	sender  SMSSender
+	breaker *breaker.Breaker
+}
+
+func (s *breakerSMSSender) Send(to, message string) error {
+	return s.breaker.Execute(func() error {
+		return s.sender.Send(to, message)
+	})
+}
+
+// NewBreakerSlackSender guards a Slack sender with a circuit breaker
+func NewBreakerSlackSender(sender SlackSender, b *breaker.Breaker) SlackSender {
+	return &breakerSlackSender{sender: sender, breaker: b}
+}
+
+type breakerSlackSender struct {
+	sender  SlackSender
+	breaker *breaker.Breaker
+}
+
+func (s *breakerSlackSender) Send(text string) error {
+	return s.breaker.Execute(func() error {
+		return s.sender.Send(text)
+	})
+}
+
+// NewBreakerPushSender guards a push sender with a circuit breaker. An invalid device token is an
+// answer from a healthy provider and does not count as a failure
+func NewBreakerPushSender(sender PushSender, b *breaker.Breaker) PushSender {
+	return &breakerPushSender{sender: sender, breaker: b}
+}
+
+type breakerPushSender struct {
+	sender  PushSender
+	breaker *breaker.Breaker
+}
+
+func (s *breakerPushSender) Send(message PushMessage) error {
+	var sendErr error
+	err := s.breaker.Execute(func() error {
+		sendErr = s.sender.Send(message)
+		if sendErr == ErrInvalidPushToken {
+			return nil
+		}
+		return sendErr
+	})
+	if err != nil {
+		return err
+	}
+	return sendErr
+}
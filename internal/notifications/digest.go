@@ -0,0 +1,84 @@
+package notifications
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"log"
+	"text/template"
+
+	"github.com/limistah/wallet-service/internal/models"
+)
+
+// digestData is the data made available to the reconciliation digest template
+type digestData struct {
+	Summary     *models.ReconciliationSummary
+	Differences []models.ReconciliationDifference
+}
+
+var digestBody = template.Must(template.New("digest").Parse(`Reconciliation summary for {{.Summary.Date.Format "2006-01-02"}} (UTC)
+
+Checks: {{.Summary.Checks}}
+Wallets checked: {{.Summary.WalletsChecked}}
+Matches: {{.Summary.Matches}}
+Mismatches: {{.Summary.Mismatches}}
+Double-entry errors: {{.Summary.DoubleEntryErrors}}
+{{if .Differences}}
+Largest differences:
+{{range .Differences}}- Wallet {{.WalletID}}: {{.Difference.StringFixed 2}} (report {{.ReportID}})
+{{end}}{{else}}
+No balance differences were found.
+{{end}}`))
+
+// renderDigest renders the subject and body of the digest of a reconciliation summary
+func renderDigest(summary *models.ReconciliationSummary) (string, string, error) {
+	differences, err := summary.Differences()
+	if err != nil {
+		return "", "", fmt.Errorf("invalid largest differences: %w", err)
+	}
+
+	subject := fmt.Sprintf("Reconciliation digest for %s: all wallets match", summary.Date.Format("2006-01-02"))
+	if summary.HasIssues() {
+		subject = fmt.Sprintf("Reconciliation digest for %s: %d issues found",
+			summary.Date.Format("2006-01-02"), summary.Mismatches+summary.DoubleEntryErrors)
+	}
+
+	var body bytes.Buffer
+	if err := digestBody.Execute(&body, digestData{Summary: summary, Differences: differences}); err != nil {
+		return "", "", err
+	}
+	return subject, body.String(), nil
+}
+
+// DeliverReconciliationDigest emails the digest of a reconciliation summary to the recipients and posts
+// it to Slack. A failing channel is logged and only fails the delivery when no channel got the digest,
+// so a retry does not repeat it to recipients who already have it
+func (n *Notifier) DeliverReconciliationDigest(summary *models.ReconciliationSummary, recipients []string) error {
+	subject, body, err := renderDigest(summary)
+	if err != nil {
+		return err
+	}
+
+	attempted, delivered := 0, 0
+	for _, recipient := range recipients {
+		attempted++
+		if err := n.emailSender.Send(EmailMessage{To: recipient, Subject: subject, Body: body}); err != nil {
+			log.Printf("Notifier: failed to email reconciliation digest: %v", err)
+			continue
+		}
+		delivered++
+	}
+	if n.slackSender != nil {
+		attempted++
+		if err := n.slackSender.Send(subject + "\n\n" + body); err != nil {
+			log.Printf("Notifier: failed to post reconciliation digest to slack: %v", err)
+		} else {
+			delivered++
+		}
+	}
+
+	if attempted > 0 && delivered == 0 {
+		return errors.New("reconciliation digest could not be delivered on any channel")
+	}
+	return nil
+}
@@ -0,0 +1,101 @@
+package notifications
+
+import (
+	"fmt"
+	"log"
+	"net/smtp"
+	"strings"
+	"sync/atomic"
+)
+
+// EmailMessage represents an email to be delivered
+type EmailMessage struct {
+	To      string
+	Subject string
+	Body    string
+}
+
+// EmailSender delivers email messages
+type EmailSender interface {
+	Send(message EmailMessage) error
+}
+
+// SMTPSender delivers email through an SMTP server
+type SMTPSender struct {
+	host     string
+	port     string
+	username string
+	password string
+	from     string
+}
+
+// NewSMTPSender creates a new SMTP email sender
+func NewSMTPSender(host, port, username, password, from string) *SMTPSender {
+	return &SMTPSender{
+		host:     host,
+		port:     port,
+		username: username,
+		password: password,
+		from:     from,
+	}
+}
+
+// Send implements EmailSender
+func (s *SMTPSender) Send(message EmailMessage) error {
+	var auth smtp.Auth
+	if s.username != "" {
+		auth = smtp.PlainAuth("", s.username, s.password, s.host)
+	}
+
+	headers := []string{
+		fmt.Sprintf("From: %s", s.from),
+		fmt.Sprintf("To: %s", message.To),
+		fmt.Sprintf("Subject: %s", message.Subject),
+		"MIME-Version: 1.0",
+		"Content-Type: text/plain; charset=\"utf-8\"",
+	}
+	body := strings.Join(headers, "\r\n") + "\r\n\r\n" + message.Body
+
+	addr := fmt.Sprintf("%s:%s", s.host, s.port)
+	if err := smtp.SendMail(addr, auth, s.from, []string{message.To}, []byte(body)); err != nil {
+		return fmt.Errorf("failed to send email to %s: %w", message.To, err)
+	}
+	return nil
+}
+
+// LogSender writes emails to the application log instead of delivering them.
+// It is used in development and when no SMTP server is configured.
+type LogSender struct{}
+
+// Send implements EmailSender
+func (LogSender) Send(message EmailMessage) error {
+	log.Printf("Email to %s: %s\n%s", message.To, message.Subject, message.Body)
+	return nil
+}
+
+// SwitchableEmailSender delivers through an email sender while enabled and writes to the log
+// otherwise, so email delivery can be switched on and off without a restart
+type SwitchableEmailSender struct {
+	sender  EmailSender
+	enabled atomic.Bool
+}
+
+// NewSwitchableEmailSender creates a sender delivering through sender while enabled
+func NewSwitchableEmailSender(sender EmailSender, enabled bool) *SwitchableEmailSender {
+	s := &SwitchableEmailSender{sender: sender}
+	s.enabled.Store(enabled)
+	return s
+}
+
+// SetEnabled switches delivery on or off and reports whether it was on
+func (s *SwitchableEmailSender) SetEnabled(enabled bool) bool {
+	return s.enabled.Swap(enabled)
+}
+
+// Send implements EmailSender
+func (s *SwitchableEmailSender) Send(message EmailMessage) error {
+	if !s.enabled.Load() {
+		return LogSender{}.Send(message)
+	}
+	return s.sender.Send(message)
+}
@@ -0,0 +1,58 @@
+package notifications
+
+import (
+	"log"
+
+	"github.com/limistah/wallet-service/internal/events"
+	"github.com/limistah/wallet-service/internal/queue"
+)
+
+// Job types that deliver an event on one channel each, so a failing channel is retried without
+// repeating the others
+const (
+	JobEmail = "notifications.email"
+	JobSMS   = "notifications.sms"
+	JobPush  = "notifications.push"
+)
+
+// RegisterJobs registers the delivery handlers of the notification jobs
+func RegisterJobs(q *queue.Queue, notifier *Notifier, pushDispatcher *PushDispatcher) {
+	q.Register(JobEmail, eventJob(notifier.DeliverEmail))
+	q.Register(JobSMS, eventJob(notifier.DeliverSMS))
+	q.Register(JobPush, eventJob(pushDispatcher.Deliver))
+}
+
+// EnqueueDeliveries returns an events.Bus handler that turns each event into delivery jobs for the
+// channels that may carry it
+func EnqueueDeliveries(jobs queue.Enqueuer) events.Handler {
+	return func(event events.Event) {
+		if event.UserID == 0 {
+			return
+		}
+
+		jobTypes := []string{JobEmail}
+		if event.IsDebit() {
+			jobTypes = append(jobTypes, JobSMS)
+		}
+		if _, _, ok := pushContent(event); ok {
+			jobTypes = append(jobTypes, JobPush)
+		}
+
+		for _, jobType := range jobTypes {
+			if err := jobs.Enqueue(jobType, event); err != nil {
+				log.Printf("Notifications: failed to queue %s event for user %d: %v", event.Type, event.UserID, err)
+			}
+		}
+	}
+}
+
+// eventJob decodes the event a delivery job carries and hands it to deliver
+func eventJob(deliver func(events.Event) error) queue.Handler {
+	return func(payload []byte) error {
+		var event events.Event
+		if err := queue.Decode(payload, &event); err != nil {
+			return err
+		}
+		return deliver(event)
+	}
+}
@@ -0,0 +1,42 @@
+package notifications
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/limistah/wallet-service/internal/events"
+	"github.com/shopspring/decimal"
+)
+
+// recordingEnqueuer keeps the job types it was asked to enqueue
+type recordingEnqueuer struct {
+	jobTypes []string
+}
+
+func (e *recordingEnqueuer) Enqueue(jobType string, payload interface{}) error {
+	e.jobTypes = append(e.jobTypes, jobType)
+	return nil
+}
+
+func TestEnqueueDeliveries(t *testing.T) {
+	tests := []struct {
+		name  string
+		event events.Event
+		want  []string
+	}{
+		{"debit", events.Event{Type: events.EventTransferSent, UserID: 1, Amount: decimal.NewFromInt(10)}, []string{JobEmail, JobSMS, JobPush}},
+		{"credit", events.Event{Type: events.EventCreditReceived, UserID: 1, Amount: decimal.NewFromInt(10)}, []string{JobEmail, JobPush}},
+		{"no push content", events.Event{Type: "unknown", UserID: 1}, []string{JobEmail}},
+		{"no user", events.Event{Type: events.EventCreditReceived}, nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			jobs := &recordingEnqueuer{}
+			EnqueueDeliveries(jobs)(tt.event)
+			if !reflect.DeepEqual(jobs.jobTypes, tt.want) {
+				t.Errorf("Expected jobs %v, got %v", tt.want, jobs.jobTypes)
+			}
+		})
+	}
+}
@@ -0,0 +1,171 @@
+package notifications
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+
+	"github.com/limistah/wallet-service/internal/events"
+	"github.com/limistah/wallet-service/internal/models"
+	"github.com/limistah/wallet-service/internal/queue"
+	"github.com/limistah/wallet-service/internal/repositories"
+	"gorm.io/gorm"
+)
+
+// Notifier turns wallet events into user notifications
+type Notifier struct {
+	repos       *repositories.Repositories
+	emailSender EmailSender
+	smsSender   SMSSender
+	slackSender SlackSender
+}
+
+// NewNotifier creates a new notifier; slackSender may be nil when no Slack channel is configured
+func NewNotifier(repos *repositories.Repositories, emailSender EmailSender, smsSender SMSSender, slackSender SlackSender) *Notifier {
+	return &Notifier{
+		repos:       repos,
+		emailSender: emailSender,
+		smsSender:   smsSender,
+		slackSender: slackSender,
+	}
+}
+
+// Handle delivers a single event right away, logging failures; it is meant to be subscribed to an
+// events.Bus when notifications do not go through the job queue
+func (n *Notifier) Handle(event events.Event) {
+	if err := n.DeliverEmail(event); err != nil {
+		log.Printf("Notifier: %v", err)
+	}
+	if err := n.DeliverSMS(event); err != nil {
+		log.Printf("Notifier: %v", err)
+	}
+}
+
+// DeliverEmail emails the user about an event when their preferences ask for it
+func (n *Notifier) DeliverEmail(event events.Event) error {
+	user, preference, err := n.recipient(event)
+	if err != nil || user == nil {
+		return err
+	}
+	if !preference.EmailEnabled || !wantsEvent(preference, event.Type) {
+		return nil
+	}
+
+	subject, body, err := renderEmail(user.Name, event)
+	if err != nil {
+		// A template that fails to render fails the same way on every attempt
+		return queue.Permanent(err)
+	}
+	if subject, err = n.purposeSubject(user.Name, event, subject); err != nil {
+		return err
+	}
+
+	return n.emailSender.Send(EmailMessage{To: user.Email, Subject: subject, Body: body})
+}
+
+// purposeSubject replaces the subject of an email about a transaction with the notification template
+// of the transaction's purpose, when the purpose has one
+func (n *Notifier) purposeSubject(name string, event events.Event, subject string) (string, error) {
+	purpose := event.Data["purpose"]
+	if purpose == "" {
+		return subject, nil
+	}
+	definition, err := n.repos.TransactionPurpose.GetByName(context.Background(), models.TransactionPurpose(purpose))
+	if errors.Is(err, gorm.ErrRecordNotFound) || (err == nil && definition.NotificationTemplate == "") {
+		return subject, nil
+	}
+	if err != nil {
+		return "", fmt.Errorf("failed to load transaction purpose %s: %w", purpose, err)
+	}
+	rendered, err := renderPurposeSubject(definition.NotificationTemplate, name, event)
+	if err != nil {
+		return "", queue.Permanent(err)
+	}
+	return rendered, nil
+}
+
+// DeliverSMS texts the user about a debit large enough for their SMS alerts
+func (n *Notifier) DeliverSMS(event events.Event) error {
+	if n.smsSender == nil {
+		return nil
+	}
+
+	user, preference, err := n.recipient(event)
+	if err != nil || user == nil {
+		return err
+	}
+	if !wantsSMS(user, preference, event) {
+		return nil
+	}
+
+	message := fmt.Sprintf("Wallet alert: %s %s debited from your wallet. Ref: %s. If this wasn't you, contact support.",
+		event.Amount.StringFixed(2), event.Currency, event.Reference)
+
+	return n.smsSender.Send(user.PhoneNumber, message)
+}
+
+// recipient loads the user an event is for with their preferences; the user is nil when nobody
+// should be notified
+func (n *Notifier) recipient(event events.Event) (*models.User, *models.NotificationPreference, error) {
+	if event.UserID == 0 {
+		return nil, nil, nil
+	}
+
+	user, err := n.repos.User.GetByID(context.Background(), event.UserID)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to load user %d: %w", event.UserID, err)
+	}
+	if user.IsSystemAccount() {
+		return nil, nil, nil
+	}
+
+	preference, err := n.preferenceFor(user.ID)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to load preferences for user %d: %w", user.ID, err)
+	}
+	return user, preference, nil
+}
+
+// preferenceFor returns the stored preferences or the defaults when none exist
+func (n *Notifier) preferenceFor(userID uint) (*models.NotificationPreference, error) {
+	preference, err := n.repos.NotificationPreference.GetByUserID(context.Background(), userID)
+	if err == gorm.ErrRecordNotFound {
+		return models.DefaultNotificationPreference(userID), nil
+	}
+	return preference, err
+}
+
+// wantsSMS checks if a debit is large enough to trigger an SMS alert for the user
+func wantsSMS(user *models.User, preference *models.NotificationPreference, event events.Event) bool {
+	return event.IsDebit() &&
+		preference.SMSEnabled &&
+		user.HasVerifiedPhone() &&
+		event.Amount.GreaterThanOrEqual(preference.SMSDebitThreshold)
+}
+
+// wantsEvent checks the per-event toggles of a preference
+func wantsEvent(preference *models.NotificationPreference, eventType events.EventType) bool {
+	switch eventType {
+	case events.EventCreditReceived:
+		return preference.CreditReceived
+	case events.EventWithdrawalCompleted:
+		return preference.WithdrawalCompleted
+	case events.EventTransactionFailed, events.EventTransactionExpired, events.EventStandingOrderFailed,
+		events.EventSubscriptionPaymentFailed, events.EventSubscriptionSuspended:
+		return preference.TransactionFailed
+	case events.EventSuspiciousActivity:
+		return preference.SuspiciousActivity
+	case events.EventBudgetThreshold:
+		// Users set budgets to be told when they near them
+		return true
+	case events.EventMoneyRequested:
+		// Requests wait on the payer's action, so they are always delivered
+		return true
+	case events.EventAccountLocked, events.EventNewDeviceLogin:
+		// The user must learn that someone is guessing their password or got into the account
+		return true
+	default:
+		return false
+	}
+}
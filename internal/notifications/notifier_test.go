@@ -0,0 +1,207 @@
+package notifications
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/limistah/wallet-service/internal/events"
+	"github.com/limistah/wallet-service/internal/models"
+	"github.com/shopspring/decimal"
+)
+
+func TestRenderEmail(t *testing.T) {
+	event := events.Event{
+		Type:       events.EventCreditReceived,
+		Reference:  "REF123",
+		Amount:     decimal.NewFromFloat(25.5),
+		Currency:   "USD",
+		OccurredAt: time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC),
+	}
+
+	subject, body, err := renderEmail("Jane", event)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if subject != "You received 25.50 USD" {
+		t.Errorf("Unexpected subject: %q", subject)
+	}
+	for _, expected := range []string{"Hi Jane", "REF123", "2024-01-02 03:04:05 UTC"} {
+		if !strings.Contains(body, expected) {
+			t.Errorf("Expected body to contain %q, got: %s", expected, body)
+		}
+	}
+
+	request := events.Event{
+		Type:     events.EventMoneyRequested,
+		Amount:   decimal.NewFromInt(15),
+		Currency: "USD",
+		Reason:   "Lunch",
+		Data:     map[string]string{"requester_name": "John", "requester_email": "john@example.com"},
+	}
+	subject, body, err = renderEmail("Jane", request)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if subject != "John requested 15.00 USD" {
+		t.Errorf("Unexpected subject: %q", subject)
+	}
+	if !strings.Contains(body, "Note: Lunch") {
+		t.Errorf("Expected body to contain the request note, got: %s", body)
+	}
+
+	locked := events.Event{
+		Type: events.EventAccountLocked,
+		Data: map[string]string{"ip_address": "203.0.113.7", "locked_until": "2024-01-02 03:19:05 UTC"},
+	}
+	_, body, err = renderEmail("Jane", locked)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	for _, expected := range []string{"from 203.0.113.7", "after 2024-01-02 03:19:05 UTC"} {
+		if !strings.Contains(body, expected) {
+			t.Errorf("Expected body to contain %q, got: %s", expected, body)
+		}
+	}
+
+	newDevice := events.Event{
+		Type: events.EventNewDeviceLogin,
+		Data: map[string]string{"ip_address": "203.0.113.7", "user_agent": "Mozilla/5.0 (iPhone)", "method": "password"},
+	}
+	_, body, err = renderEmail("Jane", newDevice)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	for _, expected := range []string{"Device: Mozilla/5.0 (iPhone)", "IP address: 203.0.113.7"} {
+		if !strings.Contains(body, expected) {
+			t.Errorf("Expected body to contain %q, got: %s", expected, body)
+		}
+	}
+
+	if _, _, err := renderEmail("Jane", events.Event{Type: "unknown"}); err == nil {
+		t.Error("Expected error for event without template")
+	}
+}
+
+func TestRenderPurposeSubject(t *testing.T) {
+	var interest string
+	for _, definition := range models.DefaultTransactionPurposes() {
+		if definition.Name == models.TransactionPurposeInterest {
+			interest = definition.NotificationTemplate
+		}
+	}
+	event := events.Event{Type: events.EventCreditReceived, Amount: decimal.NewFromFloat(3.2), Currency: "NGN"}
+	if subject, err := renderPurposeSubject(interest, "Jane", event); err != nil || subject != "Interest of 3.20 NGN was paid to your wallet" {
+		t.Errorf("Unexpected subject: %q, %v", subject, err)
+	}
+
+	if err := ValidatePurposeTemplate("Hi {{.Name}}, {{index .Event.Data \"purpose\"}}"); err != nil {
+		t.Errorf("Expected a template using the event data to be valid, got: %v", err)
+	}
+	if err := ValidatePurposeTemplate("{{.Event.Total}}"); err == nil {
+		t.Error("Expected a template using an unknown field to be rejected")
+	}
+}
+
+func TestWantsEvent(t *testing.T) {
+	preference := models.DefaultNotificationPreference(1)
+	preference.WithdrawalCompleted = false
+
+	if !wantsEvent(preference, events.EventCreditReceived) {
+		t.Error("Expected credit notifications to be enabled by default")
+	}
+	if wantsEvent(preference, events.EventWithdrawalCompleted) {
+		t.Error("Expected withdrawal notifications to be disabled")
+	}
+	if wantsEvent(preference, "unknown") {
+		t.Error("Expected unknown events to be ignored")
+	}
+}
+
+func TestWantsSMS(t *testing.T) {
+	verifiedAt := time.Now()
+	user := &models.User{PhoneNumber: "+2348012345678", PhoneVerifiedAt: &verifiedAt}
+
+	preference := models.DefaultNotificationPreference(1)
+	preference.SMSEnabled = true
+	preference.SMSDebitThreshold = decimal.NewFromInt(500)
+
+	debit := events.Event{Type: events.EventTransferSent, Amount: decimal.NewFromInt(500)}
+	if !wantsSMS(user, preference, debit) {
+		t.Error("Expected SMS for debit at threshold")
+	}
+
+	small := debit
+	small.Amount = decimal.NewFromInt(499)
+	if wantsSMS(user, preference, small) {
+		t.Error("Expected no SMS for debit below threshold")
+	}
+
+	credit := events.Event{Type: events.EventCreditReceived, Amount: decimal.NewFromInt(5000)}
+	if wantsSMS(user, preference, credit) {
+		t.Error("Expected no SMS for credits")
+	}
+
+	unverified := &models.User{PhoneNumber: "+2348012345678"}
+	if wantsSMS(unverified, preference, debit) {
+		t.Error("Expected no SMS for unverified phone numbers")
+	}
+}
+
+type recordingEmailSender struct {
+	messages []EmailMessage
+	failFor  string
+}
+
+func (s *recordingEmailSender) Send(message EmailMessage) error {
+	if message.To == s.failFor {
+		return errors.New("smtp unavailable")
+	}
+	s.messages = append(s.messages, message)
+	return nil
+}
+
+type failingSlackSender struct{}
+
+func (failingSlackSender) Send(text string) error {
+	return errors.New("slack unavailable")
+}
+
+func TestDeliverReconciliationDigest(t *testing.T) {
+	summary := &models.ReconciliationSummary{
+		Date:           time.Date(2024, 1, 2, 0, 0, 0, 0, time.UTC),
+		Checks:         12,
+		WalletsChecked: 10,
+		Matches:        9,
+		Mismatches:     3,
+	}
+	if err := summary.SetDifferences([]models.ReconciliationDifference{
+		{WalletID: 7, ReportID: 40, Difference: decimal.NewFromFloat(-250.5)},
+	}); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	emails := &recordingEmailSender{failFor: "down@example.com"}
+	notifier := NewNotifier(nil, emails, nil, failingSlackSender{})
+
+	if err := notifier.DeliverReconciliationDigest(summary, []string{"finance@example.com", "down@example.com"}); err != nil {
+		t.Fatalf("Expected delivery to succeed while one channel works, got: %v", err)
+	}
+	if len(emails.messages) != 1 {
+		t.Fatalf("Expected 1 email, got %d", len(emails.messages))
+	}
+	message := emails.messages[0]
+	if message.Subject != "Reconciliation digest for 2024-01-02: 3 issues found" {
+		t.Errorf("Unexpected subject: %s", message.Subject)
+	}
+	for _, expected := range []string{"Wallets checked: 10", "Mismatches: 3", "- Wallet 7: -250.50 (report 40)"} {
+		if !strings.Contains(message.Body, expected) {
+			t.Errorf("Expected body to contain %q, got: %s", expected, message.Body)
+		}
+	}
+
+	if err := notifier.DeliverReconciliationDigest(summary, []string{"down@example.com"}); err == nil {
+		t.Error("Expected error when no channel delivered the digest")
+	}
+}
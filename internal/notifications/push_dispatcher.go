@@ -0,0 +1,140 @@
+package notifications
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strconv"
+
+	"github.com/limistah/wallet-service/internal/events"
+	"github.com/limistah/wallet-service/internal/repositories"
+	"gorm.io/gorm"
+)
+
+// PushDispatcher delivers wallet events as push notifications to every registered device of a user
+type PushDispatcher struct {
+	repos  *repositories.Repositories
+	sender PushSender
+}
+
+// NewPushDispatcher creates a new push dispatcher
+func NewPushDispatcher(repos *repositories.Repositories, sender PushSender) *PushDispatcher {
+	return &PushDispatcher{
+		repos:  repos,
+		sender: sender,
+	}
+}
+
+// Handle delivers a single event right away, logging failures; it is meant to be subscribed to an
+// events.Bus when notifications do not go through the job queue
+func (d *PushDispatcher) Handle(event events.Event) {
+	if err := d.Deliver(event); err != nil {
+		log.Printf("PushDispatcher: %v", err)
+	}
+}
+
+// Deliver pushes an event to every device of the user. It only fails when no device could be
+// reached, so a retry never repeats the notification on devices that already received it
+func (d *PushDispatcher) Deliver(event events.Event) error {
+	if event.UserID == 0 {
+		return nil
+	}
+
+	title, body, ok := pushContent(event)
+	if !ok {
+		return nil
+	}
+
+	preference, err := d.repos.NotificationPreference.GetByUserID(context.Background(), event.UserID)
+	if err != nil && err != gorm.ErrRecordNotFound {
+		return fmt.Errorf("failed to load preferences for user %d: %w", event.UserID, err)
+	}
+	if preference != nil && !preference.PushEnabled {
+		return nil
+	}
+
+	devices, err := d.repos.DeviceToken.GetByUserID(context.Background(), event.UserID)
+	if err != nil {
+		return fmt.Errorf("failed to load devices for user %d: %w", event.UserID, err)
+	}
+
+	data := map[string]string{
+		"type":      string(event.Type),
+		"wallet_id": strconv.FormatUint(uint64(event.WalletID), 10),
+		"reference": event.Reference,
+	}
+	if requestID, ok := event.Data["request_id"]; ok {
+		data["request_id"] = requestID
+	}
+	if event.TransactionID != 0 {
+		data["transaction_id"] = strconv.FormatUint(uint64(event.TransactionID), 10)
+	}
+
+	var lastErr error
+	delivered := 0
+	for _, device := range devices {
+		err := d.sender.Send(PushMessage{
+			Token: device.Token,
+			Title: title,
+			Body:  body,
+			Data:  data,
+		})
+		switch {
+		case err == ErrInvalidPushToken:
+			// The device uninstalled the app or the token rotated; stop sending to it
+			if err := d.repos.DeviceToken.DeleteByToken(context.Background(), device.Token); err != nil {
+				log.Printf("PushDispatcher: failed to remove invalid token for user %d: %v", event.UserID, err)
+			}
+		case err != nil:
+			log.Printf("PushDispatcher: %v", err)
+			lastErr = err
+		default:
+			delivered++
+			if err := d.repos.DeviceToken.MarkUsed(context.Background(), device.Token); err != nil {
+				log.Printf("PushDispatcher: failed to update device %d: %v", device.ID, err)
+			}
+		}
+	}
+
+	if delivered == 0 && lastErr != nil {
+		return lastErr
+	}
+	return nil
+}
+
+// pushContent returns the title and body for events that should reach mobile clients
+func pushContent(event events.Event) (string, string, bool) {
+	amount := fmt.Sprintf("%s %s", event.Amount.StringFixed(2), event.Currency)
+
+	switch event.Type {
+	case events.EventCreditReceived:
+		return "Money received", fmt.Sprintf("%s has been credited to your wallet.", amount), true
+	case events.EventWithdrawalCompleted:
+		return "Withdrawal completed", fmt.Sprintf("Your withdrawal of %s has been completed.", amount), true
+	case events.EventTransferSent:
+		return "Transfer sent", fmt.Sprintf("You sent %s.", amount), true
+	case events.EventTransactionFailed:
+		return "Transaction failed", fmt.Sprintf("Your transaction of %s could not be completed.", amount), true
+	case events.EventTransactionExpired:
+		return "Transaction cancelled", fmt.Sprintf("Your pending transaction of %s was cancelled and the funds returned.", amount), true
+	case events.EventSuspiciousActivity:
+		// Raised when reconciliation finds a mismatch or a fraud rule holds or blocks a debit
+		return "Unusual activity", "We detected unusual activity on your wallet and paused affected operations while we review it.", true
+	case events.EventStandingOrderFailed:
+		return "Standing order failed", fmt.Sprintf("Your standing order of %s could not be paid.", amount), true
+	case events.EventSubscriptionPaymentFailed:
+		return "Subscription payment failed", fmt.Sprintf("Your %s subscription of %s could not be paid and will be retried.", event.Data["plan"], amount), true
+	case events.EventSubscriptionSuspended:
+		return "Subscription suspended", fmt.Sprintf("Your %s subscription was suspended after its payments failed.", event.Data["plan"]), true
+	case events.EventBudgetThreshold:
+		return "Budget alert", fmt.Sprintf("You have spent %s%% of your %s budget of %s this month.", event.Data["percent"], event.Data["category"], amount), true
+	case events.EventMoneyRequested:
+		return "Money request", fmt.Sprintf("%s requested %s from you.", event.Data["requester_name"], amount), true
+	case events.EventAccountLocked:
+		return "Account locked", "Your account was locked after too many failed login attempts.", true
+	case events.EventNewDeviceLogin:
+		return "New sign in", fmt.Sprintf("Your account was accessed from a new device at %s.", event.Data["ip_address"]), true
+	default:
+		return "", "", false
+	}
+}
@@ -0,0 +1,131 @@
+package notifications
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/limistah/wallet-service/internal/breaker"
+	"github.com/limistah/wallet-service/internal/events"
+	"github.com/shopspring/decimal"
+)
+
+func TestPushContent(t *testing.T) {
+	event := events.Event{
+		Type:     events.EventCreditReceived,
+		Amount:   decimal.NewFromInt(40),
+		Currency: "USD",
+	}
+
+	title, body, ok := pushContent(event)
+	if !ok {
+		t.Fatal("Expected credit events to produce a push notification")
+	}
+	if title != "Money received" || !strings.Contains(body, "40.00 USD") {
+		t.Errorf("Unexpected content: %q / %q", title, body)
+	}
+
+	if _, _, ok := pushContent(events.Event{Type: events.EventSuspiciousActivity}); !ok {
+		t.Error("Expected reconciliation holds to produce a push notification")
+	}
+	if _, _, ok := pushContent(events.Event{Type: "unknown"}); ok {
+		t.Error("Expected unknown events to be ignored")
+	}
+}
+
+func TestFCMSender_Send(t *testing.T) {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatalf("Failed to generate key: %v", err)
+	}
+	keyPEM := pem.EncodeToMemory(&pem.Block{Type: "RSA PRIVATE KEY", Bytes: x509.MarshalPKCS1PrivateKey(key)})
+
+	tokenRequests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/token":
+			tokenRequests++
+			w.Header().Set("Content-Type", "application/json")
+			w.Write([]byte(`{"access_token":"access","expires_in":3600}`))
+		case "/send":
+			if r.Header.Get("Authorization") != "Bearer access" {
+				t.Errorf("Unexpected authorization header: %q", r.Header.Get("Authorization"))
+			}
+			var payload struct {
+				Message struct {
+					Token string `json:"token"`
+				} `json:"message"`
+			}
+			json.NewDecoder(r.Body).Decode(&payload)
+			if payload.Message.Token == "stale" {
+				w.WriteHeader(http.StatusNotFound)
+				w.Write([]byte(`{"error":{"status":"NOT_FOUND","details":[{"errorCode":"UNREGISTERED"}]}}`))
+				return
+			}
+			w.Write([]byte(`{"name":"projects/test/messages/1"}`))
+		}
+	}))
+	defer server.Close()
+
+	credentials, _ := json.Marshal(map[string]string{
+		"project_id":   "test",
+		"client_email": "push@test.iam.gserviceaccount.com",
+		"private_key":  string(keyPEM),
+		"token_uri":    server.URL + "/token",
+	})
+	credentialsFile := filepath.Join(t.TempDir(), "fcm.json")
+	if err := os.WriteFile(credentialsFile, credentials, 0o600); err != nil {
+		t.Fatalf("Failed to write credentials: %v", err)
+	}
+
+	sender, err := NewFCMSender(credentialsFile)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	sender.endpoint = server.URL + "/send"
+
+	if err := sender.Send(PushMessage{Token: "valid", Title: "Hi", Body: "There"}); err != nil {
+		t.Errorf("Expected delivery to succeed, got: %v", err)
+	}
+	if err := sender.Send(PushMessage{Token: "stale", Title: "Hi", Body: "There"}); err != ErrInvalidPushToken {
+		t.Errorf("Expected ErrInvalidPushToken, got: %v", err)
+	}
+	if tokenRequests != 1 {
+		t.Errorf("Expected the access token to be cached, got %d token requests", tokenRequests)
+	}
+}
+
+// stubPushSender returns a fixed error for every message
+type stubPushSender struct {
+	err error
+}
+
+func (s stubPushSender) Send(PushMessage) error { return s.err }
+
+func TestBreakerPushSender_InvalidTokensDoNotTrip(t *testing.T) {
+	b := breaker.New("push", breaker.Config{FailureThreshold: 2, OpenTimeout: time.Minute})
+
+	invalid := NewBreakerPushSender(stubPushSender{err: ErrInvalidPushToken}, b)
+	for i := 0; i < 3; i++ {
+		if err := invalid.Send(PushMessage{}); err != ErrInvalidPushToken {
+			t.Fatalf("Expected the invalid token error to be returned, got %v", err)
+		}
+	}
+
+	failing := NewBreakerPushSender(stubPushSender{err: errors.New("fcm unavailable")}, b)
+	failing.Send(PushMessage{})
+	failing.Send(PushMessage{})
+	if err := invalid.Send(PushMessage{}); err != breaker.ErrOpen {
+		t.Errorf("Expected provider failures to open the circuit, got %v", err)
+	}
+}
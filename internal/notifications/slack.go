@@ -0,0 +1,54 @@
+package notifications
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"time"
+)
+
+// SlackSender posts messages to a Slack channel
+type SlackSender interface {
+	Send(text string) error
+}
+
+// SlackWebhookSender posts messages through a Slack incoming webhook
+type SlackWebhookSender struct {
+	webhookURL string
+	client     *http.Client
+}
+
+// NewSlackWebhookSender creates a new sender for the given incoming webhook URL
+func NewSlackWebhookSender(webhookURL string) *SlackWebhookSender {
+	return &SlackWebhookSender{
+		webhookURL: webhookURL,
+		client:     &http.Client{Timeout: 10 * time.Second},
+	}
+}
+
+// Send implements SlackSender
+func (s *SlackWebhookSender) Send(text string) error {
+	payload, err := json.Marshal(map[string]string{"text": text})
+	if err != nil {
+		return fmt.Errorf("failed to encode slack message: %w", err)
+	}
+
+	req, err := http.NewRequest(http.MethodPost, s.webhookURL, bytes.NewReader(payload))
+	if err != nil {
+		return fmt.Errorf("failed to build slack request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	return doProviderRequest(s.client, req, "slack")
+}
+
+// LogSlackSender writes Slack messages to the application log instead of posting them
+type LogSlackSender struct{}
+
+// Send implements SlackSender
+func (LogSlackSender) Send(text string) error {
+	log.Printf("Slack: %s", text)
+	return nil
+}
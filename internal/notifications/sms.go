@@ -0,0 +1,124 @@
+package notifications
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// SMSSender delivers text messages to phone numbers
+type SMSSender interface {
+	Send(to, message string) error
+}
+
+// TwilioSender delivers SMS through the Twilio Messages API
+type TwilioSender struct {
+	accountSID string
+	authToken  string
+	from       string
+	baseURL    string
+	client     *http.Client
+}
+
+// NewTwilioSender creates a new Twilio SMS sender
+func NewTwilioSender(accountSID, authToken, from string) *TwilioSender {
+	return &TwilioSender{
+		accountSID: accountSID,
+		authToken:  authToken,
+		from:       from,
+		baseURL:    "https://api.twilio.com/2010-04-01",
+		client:     &http.Client{Timeout: 10 * time.Second},
+	}
+}
+
+// Send implements SMSSender
+func (s *TwilioSender) Send(to, message string) error {
+	form := url.Values{}
+	form.Set("To", to)
+	form.Set("From", s.from)
+	form.Set("Body", message)
+
+	endpoint := fmt.Sprintf("%s/Accounts/%s/Messages.json", s.baseURL, s.accountSID)
+	req, err := http.NewRequest(http.MethodPost, endpoint, strings.NewReader(form.Encode()))
+	if err != nil {
+		return fmt.Errorf("failed to build twilio request: %w", err)
+	}
+	req.SetBasicAuth(s.accountSID, s.authToken)
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+	return doProviderRequest(s.client, req, "twilio")
+}
+
+// TermiiSender delivers SMS through the Termii messaging API
+type TermiiSender struct {
+	apiKey   string
+	senderID string
+	baseURL  string
+	client   *http.Client
+}
+
+// NewTermiiSender creates a new Termii SMS sender
+func NewTermiiSender(apiKey, senderID, baseURL string) *TermiiSender {
+	if baseURL == "" {
+		baseURL = "https://api.ng.termii.com"
+	}
+	return &TermiiSender{
+		apiKey:   apiKey,
+		senderID: senderID,
+		baseURL:  strings.TrimRight(baseURL, "/"),
+		client:   &http.Client{Timeout: 10 * time.Second},
+	}
+}
+
+// Send implements SMSSender
+func (s *TermiiSender) Send(to, message string) error {
+	payload, err := json.Marshal(map[string]string{
+		"api_key": s.apiKey,
+		"to":      strings.TrimPrefix(to, "+"),
+		"from":    s.senderID,
+		"sms":     message,
+		"type":    "plain",
+		"channel": "generic",
+	})
+	if err != nil {
+		return fmt.Errorf("failed to encode termii request: %w", err)
+	}
+
+	req, err := http.NewRequest(http.MethodPost, s.baseURL+"/api/sms/send", bytes.NewReader(payload))
+	if err != nil {
+		return fmt.Errorf("failed to build termii request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	return doProviderRequest(s.client, req, "termii")
+}
+
+// doProviderRequest executes a provider request and treats any non-2xx response as a failure
+func doProviderRequest(client *http.Client, req *http.Request, provider string) error {
+	resp, err := client.Do(req)
+	if err != nil {
+		return fmt.Errorf("%s request failed: %w", provider, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return fmt.Errorf("%s returned status %d: %s", provider, resp.StatusCode, strings.TrimSpace(string(body)))
+	}
+	return nil
+}
+
+// LogSMSSender writes text messages to the application log instead of delivering them
+type LogSMSSender struct{}
+
+// Send implements SMSSender
+func (LogSMSSender) Send(to, message string) error {
+	log.Printf("SMS to %s: %s", to, message)
+	return nil
+}
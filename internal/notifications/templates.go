@@ -0,0 +1,200 @@
+package notifications
+
+import (
+	"bytes"
+	"fmt"
+	"text/template"
+
+	"github.com/limistah/wallet-service/internal/events"
+)
+
+// emailTemplate holds the subject and body templates for an event type
+type emailTemplate struct {
+	subject *template.Template
+	body    *template.Template
+}
+
+// templateData is the data made available to email templates
+type templateData struct {
+	Name  string
+	Event events.Event
+}
+
+var emailTemplates = map[events.EventType]emailTemplate{
+	events.EventCreditReceived: newEmailTemplate(
+		"You received {{.Event.Amount.StringFixed 2}} {{.Event.Currency}}",
+		`Hi {{.Name}},
+
+Your wallet has been credited with {{.Event.Amount.StringFixed 2}} {{.Event.Currency}}.
+
+Reference: {{.Event.Reference}}
+Date: {{.Event.OccurredAt.Format "2006-01-02 15:04:05 MST"}}
+`),
+	events.EventWithdrawalCompleted: newEmailTemplate(
+		"Withdrawal of {{.Event.Amount.StringFixed 2}} {{.Event.Currency}} completed",
+		`Hi {{.Name}},
+
+Your withdrawal of {{.Event.Amount.StringFixed 2}} {{.Event.Currency}} has been completed.
+
+Reference: {{.Event.Reference}}
+Date: {{.Event.OccurredAt.Format "2006-01-02 15:04:05 MST"}}
+`),
+	events.EventTransactionFailed: newEmailTemplate(
+		"Your transaction could not be completed",
+		`Hi {{.Name}},
+
+A transaction of {{.Event.Amount.StringFixed 2}} {{.Event.Currency}} on your wallet failed.
+
+Reference: {{.Event.Reference}}
+Reason: {{.Event.Reason}}
+Date: {{.Event.OccurredAt.Format "2006-01-02 15:04:05 MST"}}
+`),
+	events.EventTransactionExpired: newEmailTemplate(
+		"Your transaction of {{.Event.Amount.StringFixed 2}} {{.Event.Currency}} was cancelled",
+		`Hi {{.Name}},
+
+A pending transaction of {{.Event.Amount.StringFixed 2}} {{.Event.Currency}} was not completed in time and has been cancelled.
+The held amount and any fee have been returned to your wallet.
+
+Reference: {{.Event.Reference}}
+Date: {{.Event.OccurredAt.Format "2006-01-02 15:04:05 MST"}}
+`),
+	events.EventSuspiciousActivity: newEmailTemplate(
+		"Unusual activity detected on your wallet",
+		`Hi {{.Name}},
+
+We detected unusual activity on your wallet and have paused affected operations while we review it.
+
+Details: {{.Event.Reason}}
+Date: {{.Event.OccurredAt.Format "2006-01-02 15:04:05 MST"}}
+
+If you did not initiate this activity, please contact support immediately.
+`),
+	events.EventStandingOrderFailed: newEmailTemplate(
+		"Your standing order of {{.Event.Amount.StringFixed 2}} {{.Event.Currency}} could not be paid",
+		`Hi {{.Name}},
+
+We could not pay your standing order of {{.Event.Amount.StringFixed 2}} {{.Event.Currency}} and have stopped retrying this payment.
+Future payments of the standing order will still be attempted.
+
+Reference: {{.Event.Reference}}
+Reason: {{.Event.Reason}}
+Date: {{.Event.OccurredAt.Format "2006-01-02 15:04:05 MST"}}
+`),
+	events.EventSubscriptionPaymentFailed: newEmailTemplate(
+		"Your {{index .Event.Data \"plan\"}} subscription payment failed",
+		`Hi {{.Name}},
+
+We could not charge {{.Event.Amount.StringFixed 2}} {{.Event.Currency}} for your {{index .Event.Data "plan"}} subscription.
+We will try again on {{index .Event.Data "next_attempt_at"}}. Top up your wallet to keep the subscription active.
+
+Reference: {{.Event.Reference}}
+Reason: {{.Event.Reason}}
+Date: {{.Event.OccurredAt.Format "2006-01-02 15:04:05 MST"}}
+`),
+	events.EventSubscriptionSuspended: newEmailTemplate(
+		"Your {{index .Event.Data \"plan\"}} subscription has been suspended",
+		`Hi {{.Name}},
+
+We could not charge {{.Event.Amount.StringFixed 2}} {{.Event.Currency}} for your {{index .Event.Data "plan"}} subscription after {{index .Event.Data "attempt"}} attempts, and have suspended it.
+Top up your wallet and resume the subscription to keep using it.
+
+Reference: {{.Event.Reference}}
+Reason: {{.Event.Reason}}
+Date: {{.Event.OccurredAt.Format "2006-01-02 15:04:05 MST"}}
+`),
+	events.EventBudgetThreshold: newEmailTemplate(
+		"You have spent {{index .Event.Data \"percent\"}}% of your {{index .Event.Data \"category\"}} budget",
+		`Hi {{.Name}},
+
+You have spent {{index .Event.Data "spent"}} {{.Event.Currency}} on {{index .Event.Data "category"}} this month, {{index .Event.Data "percent"}}% of your budget of {{.Event.Amount.StringFixed 2}} {{.Event.Currency}}.
+
+Date: {{.Event.OccurredAt.Format "2006-01-02 15:04:05 MST"}}
+`),
+	events.EventMoneyRequested: newEmailTemplate(
+		"{{index .Event.Data \"requester_name\"}} requested {{.Event.Amount.StringFixed 2}} {{.Event.Currency}}",
+		`Hi {{.Name}},
+
+{{index .Event.Data "requester_name"}} ({{index .Event.Data "requester_email"}}) has asked you for {{.Event.Amount.StringFixed 2}} {{.Event.Currency}}.
+{{if .Event.Reason}}
+Note: {{.Event.Reason}}
+{{end}}
+Open the app to accept or decline the request.
+
+Reference: {{.Event.Reference}}
+Date: {{.Event.OccurredAt.Format "2006-01-02 15:04:05 MST"}}
+`),
+	events.EventAccountLocked: newEmailTemplate(
+		"Your account has been locked",
+		`Hi {{.Name}},
+
+Your account was locked after too many failed login attempts{{with index .Event.Data "ip_address"}} from {{.}}{{end}}.
+You can log in again after {{index .Event.Data "locked_until"}}, or contact support to unlock it sooner.
+
+If these attempts were not yours, someone may be trying to guess your password. Consider changing it once you are back in.
+
+Date: {{.Event.OccurredAt.Format "2006-01-02 15:04:05 MST"}}
+`),
+	events.EventNewDeviceLogin: newEmailTemplate(
+		"New sign in to your account",
+		`Hi {{.Name}},
+
+Your account was just accessed from a device we have not seen before.
+
+Device: {{with index .Event.Data "user_agent"}}{{.}}{{else}}unknown{{end}}
+IP address: {{index .Event.Data "ip_address"}}
+Signed in with: {{index .Event.Data "method"}}
+
+If this was you, there is nothing to do. Otherwise change your password right away; it signs out every session.
+
+Date: {{.Event.OccurredAt.Format "2006-01-02 15:04:05 MST"}}
+`),
+}
+
+func newEmailTemplate(subject, body string) emailTemplate {
+	return emailTemplate{
+		subject: template.Must(template.New("subject").Parse(subject)),
+		body:    template.Must(template.New("body").Parse(body)),
+	}
+}
+
+// renderEmail renders the subject and body for the given event
+func renderEmail(name string, event events.Event) (string, string, error) {
+	tmpl, ok := emailTemplates[event.Type]
+	if !ok {
+		return "", "", fmt.Errorf("no email template for event %s", event.Type)
+	}
+
+	data := templateData{Name: name, Event: event}
+
+	var subject, body bytes.Buffer
+	if err := tmpl.subject.Execute(&subject, data); err != nil {
+		return "", "", fmt.Errorf("failed to render subject: %w", err)
+	}
+	if err := tmpl.body.Execute(&body, data); err != nil {
+		return "", "", fmt.Errorf("failed to render body: %w", err)
+	}
+
+	return subject.String(), body.String(), nil
+}
+
+// ValidatePurposeTemplate checks that the notification template of a transaction purpose parses and
+// only uses the data email templates are rendered with
+func ValidatePurposeTemplate(text string) error {
+	_, err := renderPurposeSubject(text, "Ada", events.Event{Type: events.EventCreditReceived, Data: map[string]string{}})
+	return err
+}
+
+// renderPurposeSubject renders the notification template of a transaction purpose as the subject of
+// an email about one of its transactions
+func renderPurposeSubject(text, name string, event events.Event) (string, error) {
+	tmpl, err := template.New("purpose").Option("missingkey=zero").Parse(text)
+	if err != nil {
+		return "", fmt.Errorf("invalid notification template: %w", err)
+	}
+	var subject bytes.Buffer
+	if err := tmpl.Execute(&subject, templateData{Name: name, Event: event}); err != nil {
+		return "", fmt.Errorf("failed to render notification template: %w", err)
+	}
+	return subject.String(), nil
+}
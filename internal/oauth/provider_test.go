@@ -0,0 +1,123 @@
+package oauth
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v4"
+)
+
+// idToken builds an unsigned ID token; the provider trusts the token endpoint's TLS instead
+func idToken(t *testing.T, claims jwt.MapClaims) string {
+	token, err := jwt.NewWithClaims(jwt.SigningMethodNone, claims).SignedString(jwt.UnsafeAllowNoneSignatureType)
+	if err != nil {
+		t.Fatalf("Failed to build ID token: %v", err)
+	}
+	return token
+}
+
+func TestProvider_DiscoveryAndExchange(t *testing.T) {
+	var issuer string
+	var session *Session
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/.well-known/openid-configuration":
+			json.NewEncoder(w).Encode(map[string]string{
+				"issuer":                 issuer,
+				"authorization_endpoint": issuer + "/authorize",
+				"token_endpoint":         issuer + "/token",
+			})
+		case "/token":
+			r.ParseForm()
+			if r.Form.Get("code") != "code-1" || r.Form.Get("code_verifier") != session.CodeVerifier ||
+				r.Form.Get("client_secret") != "secret" {
+				t.Errorf("Unexpected token request: %v", r.Form)
+			}
+			json.NewEncoder(w).Encode(map[string]string{
+				"id_token": idToken(t, jwt.MapClaims{
+					"iss":            issuer,
+					"aud":            "client-1",
+					"sub":            "subject-1",
+					"exp":            time.Now().Add(time.Hour).Unix(),
+					"nonce":          session.Nonce,
+					"email":          "Ada@Example.com",
+					"email_verified": "true",
+					"name":           "Ada Lovelace",
+				}),
+			})
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer server.Close()
+	issuer = server.URL
+
+	provider := NewProvider(Config{Name: "acme", Issuer: issuer + "/", ClientID: "client-1", ClientSecret: "secret", RedirectURL: "https://wallet.example/cb"})
+	session, _ = NewSessionCodec("key", time.Minute).New("acme")
+
+	authURL, err := provider.AuthCodeURL(session)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	parsed, _ := url.Parse(authURL)
+	query := parsed.Query()
+	if !strings.HasPrefix(authURL, issuer+"/authorize?") || query.Get("state") != session.State ||
+		query.Get("code_challenge") != session.CodeChallenge() || query.Get("code_challenge_method") != "S256" ||
+		query.Get("redirect_uri") != "https://wallet.example/cb" {
+		t.Errorf("Unexpected authorization URL: %s", authURL)
+	}
+
+	identity, err := provider.Exchange("code-1", session)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if identity.Provider != "acme" || identity.Subject != "subject-1" || identity.Email != "ada@example.com" ||
+		!identity.EmailVerified || identity.Name != "Ada Lovelace" {
+		t.Errorf("Unexpected identity: %+v", identity)
+	}
+}
+
+func TestProvider_ParseIDTokenRejections(t *testing.T) {
+	provider := NewGoogleProvider("client-1", "secret", "https://wallet.example/cb")
+	now := time.Unix(1700000000, 0)
+	valid := jwt.MapClaims{
+		"iss":   googleIssuer,
+		"aud":   "client-1",
+		"sub":   "subject-1",
+		"exp":   now.Add(time.Hour).Unix(),
+		"nonce": "nonce-1",
+	}
+
+	if _, err := provider.parseIDToken(idToken(t, valid), "nonce-1", now); err != nil {
+		t.Fatalf("Expected a valid token, got %v", err)
+	}
+
+	tests := []struct {
+		name  string
+		claim string
+		value interface{}
+	}{
+		{"other issuer", "iss", "https://evil.example"},
+		{"other audience", "aud", "client-2"},
+		{"expired", "exp", now.Add(-time.Minute).Unix()},
+		{"replayed nonce", "nonce", "nonce-2"},
+		{"no subject", "sub", ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			claims := jwt.MapClaims{}
+			for key, value := range valid {
+				claims[key] = value
+			}
+			claims[tt.claim] = tt.value
+			if _, err := provider.parseIDToken(idToken(t, claims), "nonce-1", now); err == nil {
+				t.Error("Expected the ID token to be rejected")
+			}
+		})
+	}
+}
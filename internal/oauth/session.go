@@ -0,0 +1,114 @@
+package oauth
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"strings"
+	"time"
+)
+
+// ErrInvalidSession is returned for sign in sessions that were tampered with, expired or started for
+// another provider
+var ErrInvalidSession = errors.New("invalid or expired sign in session")
+
+// Session holds what the callback needs to finish a sign in started by the same browser: the state
+// that guards against forged callbacks, the nonce expected in the ID token and the PKCE verifier
+type Session struct {
+	Provider     string    `json:"p"`
+	State        string    `json:"s"`
+	Nonce        string    `json:"n"`
+	CodeVerifier string    `json:"v"`
+	ExpiresAt    time.Time `json:"e"`
+}
+
+// CodeChallenge returns the S256 PKCE challenge of the session's verifier
+func (s *Session) CodeChallenge() string {
+	sum := sha256.Sum256([]byte(s.CodeVerifier))
+	return base64.RawURLEncoding.EncodeToString(sum[:])
+}
+
+// SessionCodec starts sign in sessions and seals them into a signed cookie value, so no server side
+// storage is needed between the redirect to the provider and the callback
+type SessionCodec struct {
+	secret []byte
+	ttl    time.Duration
+	now    func() time.Time
+}
+
+// NewSessionCodec creates a codec signing sessions with secret; sessions expire after ttl
+func NewSessionCodec(secret string, ttl time.Duration) *SessionCodec {
+	return &SessionCodec{
+		secret: []byte(secret),
+		ttl:    ttl,
+		now:    time.Now,
+	}
+}
+
+// New starts a sign in session with a provider
+func (c *SessionCodec) New(provider string) (*Session, error) {
+	values := make([]string, 3)
+	for i := range values {
+		value, err := randomString()
+		if err != nil {
+			return nil, err
+		}
+		values[i] = value
+	}
+	return &Session{
+		Provider:     provider,
+		State:        values[0],
+		Nonce:        values[1],
+		CodeVerifier: values[2],
+		ExpiresAt:    c.now().Add(c.ttl),
+	}, nil
+}
+
+// Seal encodes a session as "<payload>.<signature>"
+func (c *SessionCodec) Seal(session *Session) (string, error) {
+	payload, err := json.Marshal(session)
+	if err != nil {
+		return "", err
+	}
+	encoded := base64.RawURLEncoding.EncodeToString(payload)
+	return encoded + "." + c.sign(encoded), nil
+}
+
+// Open decodes a sealed session of a provider, refusing tampered and expired ones
+func (c *SessionCodec) Open(value, provider string) (*Session, error) {
+	encoded, signature, found := strings.Cut(value, ".")
+	if !found || !hmac.Equal([]byte(signature), []byte(c.sign(encoded))) {
+		return nil, ErrInvalidSession
+	}
+	payload, err := base64.RawURLEncoding.DecodeString(encoded)
+	if err != nil {
+		return nil, ErrInvalidSession
+	}
+
+	var session Session
+	if err := json.Unmarshal(payload, &session); err != nil {
+		return nil, ErrInvalidSession
+	}
+	if !strings.EqualFold(session.Provider, provider) || !c.now().Before(session.ExpiresAt) {
+		return nil, ErrInvalidSession
+	}
+	return &session, nil
+}
+
+func (c *SessionCodec) sign(encoded string) string {
+	mac := hmac.New(sha256.New, c.secret)
+	mac.Write([]byte("oauth-session:" + encoded))
+	return base64.RawURLEncoding.EncodeToString(mac.Sum(nil))
+}
+
+// randomString returns 256 random bits, URL-safe encoded
+func randomString() (string, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(b), nil
+}
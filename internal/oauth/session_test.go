@@ -0,0 +1,44 @@
+package oauth
+
+import (
+	"testing"
+	"time"
+)
+
+func TestSessionCodec_SealAndOpen(t *testing.T) {
+	codec := NewSessionCodec("key", 10*time.Minute)
+	now := time.Unix(1700000000, 0)
+	codec.now = func() time.Time { return now }
+
+	session, err := codec.New("google")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	sealed, err := codec.Seal(session)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	opened, err := codec.Open(sealed, "google")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if opened.State != session.State || opened.Nonce != session.Nonce || opened.CodeVerifier != session.CodeVerifier {
+		t.Errorf("Unexpected session: %+v", opened)
+	}
+
+	if _, err := codec.Open(sealed, "oidc"); err != ErrInvalidSession {
+		t.Errorf("Expected a session of another provider to be rejected, got %v", err)
+	}
+	if _, err := NewSessionCodec("other", time.Minute).Open(sealed, "google"); err != ErrInvalidSession {
+		t.Errorf("Expected a session signed with another key to be rejected, got %v", err)
+	}
+	if _, err := codec.Open("x"+sealed, "google"); err != ErrInvalidSession {
+		t.Errorf("Expected a tampered session to be rejected, got %v", err)
+	}
+
+	codec.now = func() time.Time { return now.Add(10 * time.Minute) }
+	if _, err := codec.Open(sealed, "google"); err != ErrInvalidSession {
+		t.Errorf("Expected an expired session to be rejected, got %v", err)
+	}
+}
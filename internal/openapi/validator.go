@@ -0,0 +1,501 @@
+// Package openapi checks requests against the Swagger documents generated from the handler
+// annotations, so what the documentation promises and what the service accepts cannot drift apart
+package openapi
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"math"
+	"net/http"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/go-openapi/spec"
+	"github.com/shopspring/decimal"
+)
+
+// Where a violated constraint was found
+const (
+	InBody   = "body"
+	InQuery  = "query"
+	InPath   = "path"
+	InHeader = "header"
+)
+
+// Violation is a constraint of the documentation a request breaks. Rule names the constraint as the
+// validation messages do (required, type, oneof, min, max, gt, lt, pattern, invalid) and Param is its
+// argument, such as the minimum or the expected type
+type Violation struct {
+	In    string
+	Field string
+	Rule  string
+	Param string
+}
+
+// Validator checks requests against the operations of one or more Swagger documents
+type Validator struct {
+	operations map[string]*operation
+	patterns   map[string]*regexp.Regexp
+}
+
+// operation is a documented operation with the definitions its schemas refer to and the compiled
+// patterns of the document
+type operation struct {
+	parameters  []spec.Parameter
+	definitions spec.Definitions
+	patterns    map[string]*regexp.Regexp
+}
+
+// Load reads Swagger 2.0 documents, such as those swag generates under docs/. Operations are matched
+// by the base path of their document followed by their path
+func Load(documents ...[]byte) (*Validator, error) {
+	v := &Validator{operations: make(map[string]*operation), patterns: make(map[string]*regexp.Regexp)}
+	for _, document := range documents {
+		var swagger spec.Swagger
+		if err := json.Unmarshal(document, &swagger); err != nil {
+			return nil, fmt.Errorf("failed to parse API document: %w", err)
+		}
+		if swagger.Paths == nil {
+			continue
+		}
+		if err := compilePatterns(&swagger, v.patterns); err != nil {
+			return nil, err
+		}
+		basePath := strings.TrimSuffix(swagger.BasePath, "/")
+		for path, item := range swagger.Paths.Paths {
+			for method, op := range map[string]*spec.Operation{
+				http.MethodGet: item.Get, http.MethodPut: item.Put, http.MethodPost: item.Post, http.MethodDelete: item.Delete,
+				http.MethodPatch: item.Patch, http.MethodHead: item.Head, http.MethodOptions: item.Options,
+			} {
+				if op == nil {
+					continue
+				}
+				v.operations[method+" "+basePath+path] = newOperation(item.Parameters, op.Parameters, swagger.Definitions, v.patterns)
+			}
+		}
+	}
+	return v, nil
+}
+
+// newOperation merges the parameters of a path with those of its operation, which override them
+func newOperation(shared, own []spec.Parameter, definitions spec.Definitions, patterns map[string]*regexp.Regexp) *operation {
+	op := &operation{definitions: definitions, patterns: patterns}
+	seen := make(map[string]bool)
+	for _, parameter := range own {
+		seen[parameter.In+" "+parameter.Name] = true
+		op.parameters = append(op.parameters, parameter)
+	}
+	for _, parameter := range shared {
+		if !seen[parameter.In+" "+parameter.Name] {
+			op.parameters = append(op.parameters, parameter)
+		}
+	}
+	return op
+}
+
+// compilePatterns compiles the patterns of a document's parameters and schemas once, when it is loaded
+func compilePatterns(swagger *spec.Swagger, patterns map[string]*regexp.Regexp) error {
+	compile := func(pattern string) error {
+		if pattern == "" || patterns[pattern] != nil {
+			return nil
+		}
+		re, err := regexp.Compile(pattern)
+		if err != nil {
+			return fmt.Errorf("invalid pattern %q: %w", pattern, err)
+		}
+		patterns[pattern] = re
+		return nil
+	}
+	var walk func(schema *spec.Schema) error
+	walk = func(schema *spec.Schema) error {
+		if schema == nil {
+			return nil
+		}
+		if err := compile(schema.Pattern); err != nil {
+			return err
+		}
+		for name := range schema.Properties {
+			property := schema.Properties[name]
+			if err := walk(&property); err != nil {
+				return err
+			}
+		}
+		for i := range schema.AllOf {
+			if err := walk(&schema.AllOf[i]); err != nil {
+				return err
+			}
+		}
+		if schema.Items != nil {
+			return walk(schema.Items.Schema)
+		}
+		return nil
+	}
+
+	for name := range swagger.Definitions {
+		definition := swagger.Definitions[name]
+		if err := walk(&definition); err != nil {
+			return fmt.Errorf("definition %s: %w", name, err)
+		}
+	}
+	for path, item := range swagger.Paths.Paths {
+		parameters := append([]spec.Parameter(nil), item.Parameters...)
+		for _, op := range []*spec.Operation{item.Get, item.Put, item.Post, item.Delete, item.Patch, item.Head, item.Options} {
+			if op != nil {
+				parameters = append(parameters, op.Parameters...)
+			}
+		}
+		for _, parameter := range parameters {
+			err := compile(parameter.Pattern)
+			if err == nil && parameter.Items != nil {
+				err = compile(parameter.Items.Pattern)
+			}
+			if err == nil {
+				err = walk(parameter.Schema)
+			}
+			if err != nil {
+				return fmt.Errorf("%s: %w", path, err)
+			}
+		}
+	}
+	return nil
+}
+
+// Documented reports whether the operation a request to method and route is made for is documented.
+// route is the pattern the request was routed by, with :name and *name parameters
+func (v *Validator) Documented(method, route string) bool {
+	_, ok := v.operations[method+" "+swaggerPath(route)]
+	return ok
+}
+
+// Validate returns every constraint of the documented operation that a request breaks: the
+// parameters in its path, query and headers, and its JSON body. Requests for undocumented operations
+// are not checked. pathParams holds the values of the parameters in route
+func (v *Validator) Validate(r *http.Request, route string, pathParams map[string]string, body []byte) []Violation {
+	op, ok := v.operations[r.Method+" "+swaggerPath(route)]
+	if !ok {
+		return nil
+	}
+
+	var violations []Violation
+	query := r.URL.Query()
+	for _, parameter := range op.parameters {
+		switch parameter.In {
+		case InBody:
+			violations = append(violations, op.validateBody(parameter, r.Header.Get("Content-Type"), body)...)
+		case InQuery:
+			values, present := query[parameter.Name]
+			violations = append(violations, op.validateParameter(parameter, values, present)...)
+		case InHeader:
+			values := r.Header.Values(parameter.Name)
+			violations = append(violations, op.validateParameter(parameter, values, len(values) > 0)...)
+		case InPath:
+			value, present := pathParams[parameter.Name]
+			violations = append(violations, op.validateParameter(parameter, []string{value}, present)...)
+		}
+	}
+	return violations
+}
+
+// validateParameter checks a parameter sent as text; arrays are sent comma-separated unless the
+// parameter says otherwise
+func (op *operation) validateParameter(parameter spec.Parameter, values []string, present bool) []Violation {
+	field := Violation{In: parameter.In, Field: parameter.Name}
+	if !present || (len(values) == 1 && values[0] == "" && parameter.Type != "string") {
+		if parameter.Required {
+			return []Violation{field.with("required", "")}
+		}
+		return nil
+	}
+
+	if parameter.Type == "array" {
+		if parameter.CollectionFormat != "multi" && len(values) == 1 {
+			values = strings.Split(values[0], collectionSeparator(parameter.CollectionFormat))
+		}
+		var violations []Violation
+		if violation, ok := op.checkCount(field, int64(len(values)), parameter.MinItems, parameter.MaxItems); !ok {
+			violations = append(violations, violation)
+		}
+		if parameter.Items != nil {
+			for i, value := range values {
+				element := Violation{In: parameter.In, Field: fmt.Sprintf("%s[%d]", parameter.Name, i)}
+				violations = append(violations, op.checkSimple(element, parameter.Items.Type, parameter.Items.CommonValidations, value)...)
+			}
+		}
+		return violations
+	}
+	return op.checkSimple(field, parameter.Type, parameter.CommonValidations, values[0])
+}
+
+// checkSimple checks a value sent as text against its type and validations
+func (op *operation) checkSimple(field Violation, typ string, validations spec.CommonValidations, text string) []Violation {
+	var value any = text
+	switch typ {
+	case "integer", "number":
+		value = json.Number(text)
+	case "boolean":
+		parsed, err := strconv.ParseBool(text)
+		if err != nil {
+			return []Violation{field.with("type", typ)}
+		}
+		value = parsed
+	}
+	if !hasType(value, typ) {
+		return []Violation{field.with("type", typ)}
+	}
+	return op.checkValue(field, value, validations)
+}
+
+// validateBody checks a JSON body against the schema of the body parameter. Bodies of other content
+// types are left to the handler
+func (op *operation) validateBody(parameter spec.Parameter, contentType string, body []byte) []Violation {
+	field := Violation{In: InBody, Field: parameter.Name}
+	if len(bytes.TrimSpace(body)) == 0 {
+		if parameter.Required {
+			return []Violation{field.with("required", "")}
+		}
+		return nil
+	}
+	if parameter.Schema == nil || (contentType != "" && !strings.Contains(contentType, "json")) {
+		return nil
+	}
+
+	decoder := json.NewDecoder(bytes.NewReader(body))
+	decoder.UseNumber()
+	var value any
+	if err := decoder.Decode(&value); err != nil {
+		return []Violation{field.with("invalid", "")}
+	}
+	return op.checkSchema(Violation{In: InBody}, parameter.Schema, value)
+}
+
+// checkSchema checks a decoded JSON value against a schema. Null stands for a missing value
+func (op *operation) checkSchema(field Violation, schema *spec.Schema, value any) []Violation {
+	schema = op.resolve(schema)
+	if schema == nil || value == nil {
+		return nil
+	}
+
+	var violations []Violation
+	for i := range schema.AllOf {
+		violations = append(violations, op.checkSchema(field, &schema.AllOf[i], value)...)
+	}
+	if len(schema.Type) > 0 && !anyType(value, schema.Type) {
+		return append(violations, field.with("type", strings.Join(schema.Type, " or ")))
+	}
+
+	switch typed := value.(type) {
+	case map[string]any:
+		for _, name := range schema.Required {
+			if typed[name] == nil {
+				violations = append(violations, field.child(name).with("required", ""))
+			}
+		}
+		names := make([]string, 0, len(schema.Properties))
+		for name := range schema.Properties {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		for _, name := range names {
+			property := schema.Properties[name]
+			violations = append(violations, op.checkSchema(field.child(name), &property, typed[name])...)
+		}
+	case []any:
+		if violation, ok := op.checkCount(field, int64(len(typed)), schema.MinItems, schema.MaxItems); !ok {
+			violations = append(violations, violation)
+		}
+		if schema.Items != nil && schema.Items.Schema != nil {
+			for i, element := range typed {
+				violations = append(violations, op.checkSchema(field.index(i), schema.Items.Schema, element)...)
+			}
+		}
+	default:
+		violations = append(violations, op.checkValue(field, value, spec.CommonValidations{
+			Maximum: schema.Maximum, ExclusiveMaximum: schema.ExclusiveMaximum,
+			Minimum: schema.Minimum, ExclusiveMinimum: schema.ExclusiveMinimum,
+			MaxLength: schema.MaxLength, MinLength: schema.MinLength,
+			Pattern: schema.Pattern, Enum: schema.Enum,
+		})...)
+	}
+	return violations
+}
+
+// checkValue checks a scalar against the enum, bounds, lengths and pattern it must keep to
+func (op *operation) checkValue(field Violation, value any, validations spec.CommonValidations) []Violation {
+	if len(validations.Enum) > 0 && !inEnum(value, validations.Enum) {
+		allowed := make([]string, len(validations.Enum))
+		for i, option := range validations.Enum {
+			allowed[i] = fmt.Sprint(option)
+		}
+		return []Violation{field.with("oneof", strings.Join(allowed, " "))}
+	}
+
+	var violations []Violation
+	if number, ok := numeric(value); ok {
+		if min := validations.Minimum; min != nil {
+			if validations.ExclusiveMinimum && number <= *min {
+				violations = append(violations, field.with("gt", formatFloat(*min)))
+			} else if number < *min {
+				violations = append(violations, field.with("min", formatFloat(*min)))
+			}
+		}
+		if max := validations.Maximum; max != nil {
+			if validations.ExclusiveMaximum && number >= *max {
+				violations = append(violations, field.with("lt", formatFloat(*max)))
+			} else if number > *max {
+				violations = append(violations, field.with("max", formatFloat(*max)))
+			}
+		}
+	}
+	if text, ok := value.(string); ok {
+		if violation, ok := op.checkCount(field, int64(len([]rune(text))), validations.MinLength, validations.MaxLength); !ok {
+			violations = append(violations, violation)
+		}
+		if re := op.patterns[validations.Pattern]; re != nil && !re.MatchString(text) {
+			violations = append(violations, field.with("pattern", validations.Pattern))
+		}
+	}
+	return violations
+}
+
+// checkCount checks a length or a number of items against its bounds
+func (op *operation) checkCount(field Violation, count int64, min, max *int64) (Violation, bool) {
+	if min != nil && count < *min {
+		return field.with("min", strconv.FormatInt(*min, 10)), false
+	}
+	if max != nil && count > *max {
+		return field.with("max", strconv.FormatInt(*max, 10)), false
+	}
+	return Violation{}, true
+}
+
+// resolve follows a reference to the definitions of the document
+func (op *operation) resolve(schema *spec.Schema) *spec.Schema {
+	for depth := 0; schema != nil && schema.Ref.String() != "" && depth < 10; depth++ {
+		name := strings.TrimPrefix(schema.Ref.String(), "#/definitions/")
+		definition, ok := op.definitions[name]
+		if !ok {
+			return nil
+		}
+		schema = &definition
+	}
+	return schema
+}
+
+func (f Violation) with(rule, param string) Violation {
+	f.Rule, f.Param = rule, param
+	return f
+}
+
+// child names a property of the field, as clients send it
+func (f Violation) child(name string) Violation {
+	if f.Field != "" {
+		name = f.Field + "." + name
+	}
+	return Violation{In: f.In, Field: name}
+}
+
+func (f Violation) index(i int) Violation {
+	return Violation{In: f.In, Field: fmt.Sprintf("%s[%d]", f.Field, i)}
+}
+
+// anyType reports whether value has one of the types
+func anyType(value any, types spec.StringOrArray) bool {
+	for _, typ := range types {
+		if hasType(value, typ) {
+			return true
+		}
+	}
+	return false
+}
+
+// hasType reports whether a decoded JSON value has a Swagger type. Amounts are decimals, which are
+// accepted as JSON numbers and as strings holding one, so numbers may be sent as either
+func hasType(value any, typ string) bool {
+	switch typ {
+	case "object":
+		_, ok := value.(map[string]any)
+		return ok
+	case "array":
+		_, ok := value.([]any)
+		return ok
+	case "string":
+		_, ok := value.(string)
+		return ok
+	case "boolean":
+		_, ok := value.(bool)
+		return ok
+	case "integer":
+		number, ok := value.(json.Number)
+		if !ok {
+			return false
+		}
+		parsed, err := number.Float64()
+		return err == nil && parsed == math.Trunc(parsed)
+	case "number":
+		switch typed := value.(type) {
+		case json.Number:
+			_, err := typed.Float64()
+			return err == nil
+		case string:
+			_, err := decimal.NewFromString(typed)
+			return err == nil
+		}
+		return false
+	}
+	return true
+}
+
+// numeric returns the value of a number, or of a string holding one
+func numeric(value any) (float64, bool) {
+	switch typed := value.(type) {
+	case json.Number:
+		number, err := typed.Float64()
+		return number, err == nil
+	case string:
+		parsed, err := decimal.NewFromString(typed)
+		if err != nil {
+			return 0, false
+		}
+		return parsed.InexactFloat64(), true
+	}
+	return 0, false
+}
+
+func inEnum(value any, enum []any) bool {
+	for _, option := range enum {
+		if fmt.Sprint(option) == fmt.Sprint(value) {
+			return true
+		}
+	}
+	return false
+}
+
+func formatFloat(number float64) string {
+	return strconv.FormatFloat(number, 'f', -1, 64)
+}
+
+func collectionSeparator(format string) string {
+	switch format {
+	case "ssv":
+		return " "
+	case "tsv":
+		return "\t"
+	case "pipes":
+		return "|"
+	}
+	return ","
+}
+
+// swaggerPath turns a route pattern such as /wallets/:id into the path of its document, /wallets/{id}
+func swaggerPath(route string) string {
+	segments := strings.Split(route, "/")
+	for i, segment := range segments {
+		if strings.HasPrefix(segment, ":") || strings.HasPrefix(segment, "*") {
+			segments[i] = "{" + segment[1:] + "}"
+		}
+	}
+	return strings.Join(segments, "/")
+}
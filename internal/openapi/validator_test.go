@@ -0,0 +1,139 @@
+package openapi
+
+import (
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/limistah/wallet-service/docs"
+	docsv2 "github.com/limistah/wallet-service/docs/v2"
+)
+
+const testDocument = `{
+	"swagger": "2.0",
+	"basePath": "/api/v1",
+	"paths": {
+		"/wallets/{id}/splits": {
+			"parameters": [{"in": "path", "name": "id", "required": true, "type": "integer"}],
+			"post": {
+				"parameters": [
+					{"in": "query", "name": "mode", "type": "string", "enum": ["fixed", "percentage"]},
+					{"in": "query", "name": "tags", "type": "array", "items": {"type": "string", "pattern": "^[a-z]+$"}, "maxItems": 3},
+					{"in": "body", "name": "request", "required": true, "schema": {"$ref": "#/definitions/SplitRequest"}}
+				]
+			}
+		}
+	},
+	"definitions": {
+		"SplitRequest": {
+			"type": "object",
+			"required": ["amount", "recipients"],
+			"properties": {
+				"amount": {"type": "number", "minimum": 0, "exclusiveMinimum": true},
+				"recipients": {"type": "array", "minItems": 2, "items": {"$ref": "#/definitions/Recipient"}}
+			}
+		},
+		"Recipient": {
+			"type": "object",
+			"required": ["wallet_id"],
+			"properties": {
+				"wallet_id": {"type": "integer"},
+				"amount": {"type": "number"}
+			}
+		}
+	}
+}`
+
+func TestValidator_Validate(t *testing.T) {
+	validator, err := Load([]byte(testDocument))
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+
+	tests := []struct {
+		name   string
+		target string
+		id     string
+		body   string
+		want   []Violation
+	}{
+		{
+			name:   "valid",
+			target: "/api/v1/wallets/7/splits?mode=fixed&tags=rent,bills",
+			id:     "7",
+			body:   `{"amount": "100.50", "recipients": [{"wallet_id": 2, "amount": 60}, {"wallet_id": 3}]}`,
+		},
+		{
+			name:   "every violation",
+			target: "/api/v1/wallets/seven/splits?mode=equal&tags=a,b,c,d",
+			id:     "seven",
+			body:   `{"amount": 0, "recipients": [{"wallet_id": 2.5, "amount": "lots"}]}`,
+			want: []Violation{
+				{In: InQuery, Field: "mode", Rule: "oneof", Param: "fixed percentage"},
+				{In: InQuery, Field: "tags", Rule: "max", Param: "3"},
+				{In: InBody, Field: "amount", Rule: "gt", Param: "0"},
+				{In: InBody, Field: "recipients", Rule: "min", Param: "2"},
+				{In: InBody, Field: "recipients[0].amount", Rule: "type", Param: "number"},
+				{In: InBody, Field: "recipients[0].wallet_id", Rule: "type", Param: "integer"},
+				{In: InPath, Field: "id", Rule: "type", Param: "integer"},
+			},
+		},
+		{
+			name:   "missing body",
+			target: "/api/v1/wallets/7/splits?tags=Rent",
+			id:     "7",
+			want: []Violation{
+				{In: InQuery, Field: "tags[0]", Rule: "pattern", Param: "^[a-z]+$"},
+				{In: InBody, Field: "request", Rule: "required"},
+			},
+		},
+		{
+			name:   "malformed body",
+			target: "/api/v1/wallets/7/splits",
+			id:     "7",
+			body:   `{"amount":`,
+			want:   []Violation{{In: InBody, Field: "request", Rule: "invalid"}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := httptest.NewRequest("POST", tt.target, strings.NewReader(tt.body))
+			r.Header.Set("Content-Type", "application/json")
+
+			got := validator.Validate(r, "/api/v1/wallets/:id/splits", map[string]string{"id": tt.id}, []byte(tt.body))
+			if len(got) != len(tt.want) {
+				t.Fatalf("Expected %d violations, got %+v", len(tt.want), got)
+			}
+			for i := range got {
+				if got[i] != tt.want[i] {
+					t.Errorf("Violation %d: expected %+v, got %+v", i, tt.want[i], got[i])
+				}
+			}
+		})
+	}
+}
+
+func TestValidator_UndocumentedOperation(t *testing.T) {
+	validator, _ := Load([]byte(testDocument))
+	r := httptest.NewRequest("GET", "/api/v1/wallets/7/splits", nil)
+
+	if validator.Documented("GET", "/api/v1/wallets/:id/splits") {
+		t.Error("Expected GET to be undocumented")
+	}
+	if got := validator.Validate(r, "/api/v1/wallets/:id/splits", nil, nil); got != nil {
+		t.Errorf("Expected undocumented operations to pass, got %+v", got)
+	}
+}
+
+func TestLoad_GeneratedDocuments(t *testing.T) {
+	validator, err := Load([]byte(docs.SwaggerInfo.ReadDoc()), []byte(docsv2.SwaggerInfov2.ReadDoc()))
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	for _, route := range []string{"POST /api/v1/wallets/me/transfer", "GET /api/v2/wallets/me"} {
+		method, path, _ := strings.Cut(route, " ")
+		if !validator.Documented(method, path) {
+			t.Errorf("Expected %s to be documented", route)
+		}
+	}
+}
@@ -0,0 +1,70 @@
+package passwords
+
+import (
+	"bufio"
+	"crypto/sha1"
+	"encoding/hex"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// BreachChecker tells whether a password appeared in a known data breach
+type BreachChecker interface {
+	Breached(password string) (bool, error)
+}
+
+// PwnedPasswordsChecker looks passwords up in the Have I Been Pwned range API. Only the first 5
+// characters of the password's SHA-1 hash are sent (k-anonymity); the matching suffixes come back and
+// are compared locally, with padding so the response size reveals nothing either
+type PwnedPasswordsChecker struct {
+	baseURL string
+	client  *http.Client
+}
+
+// NewPwnedPasswordsChecker creates a checker for the range API at baseURL, such as
+// https://api.pwnedpasswords.com
+func NewPwnedPasswordsChecker(baseURL string) *PwnedPasswordsChecker {
+	return &PwnedPasswordsChecker{
+		baseURL: strings.TrimSuffix(baseURL, "/"),
+		client:  &http.Client{Timeout: 5 * time.Second},
+	}
+}
+
+// Breached reports whether the password has been seen in a breach
+func (c *PwnedPasswordsChecker) Breached(password string) (bool, error) {
+	sum := sha1.Sum([]byte(password))
+	hash := strings.ToUpper(hex.EncodeToString(sum[:]))
+	prefix, suffix := hash[:5], hash[5:]
+
+	req, err := http.NewRequest(http.MethodGet, c.baseURL+"/range/"+prefix, nil)
+	if err != nil {
+		return false, err
+	}
+	req.Header.Set("Add-Padding", "true")
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return false, fmt.Errorf("breached password lookup failed: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return false, fmt.Errorf("breached password lookup failed with status %d", resp.StatusCode)
+	}
+
+	// Each line is "<suffix>:<count>"; padding lines have a count of 0
+	scanner := bufio.NewScanner(resp.Body)
+	for scanner.Scan() {
+		candidate, count, found := strings.Cut(strings.TrimSpace(scanner.Text()), ":")
+		if !found || !strings.EqualFold(candidate, suffix) {
+			continue
+		}
+		n, err := strconv.Atoi(count)
+		return err == nil && n > 0, nil
+	}
+	if err := scanner.Err(); err != nil {
+		return false, fmt.Errorf("failed to read breached password lookup: %w", err)
+	}
+	return false, nil
+}
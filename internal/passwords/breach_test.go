@@ -0,0 +1,59 @@
+package passwords
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+// The SHA-1 of "password" is 5BAA61E4C9B93F3F0682250B6CF8331B7EE68FD8
+func newRangeServer(t *testing.T, body string) *httptest.Server {
+	t.Helper()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/range/5BAA6" {
+			t.Errorf("Expected only the hash prefix to be sent, got %s", r.URL.Path)
+		}
+		if r.Header.Get("Add-Padding") != "true" {
+			t.Error("Expected padding to be requested")
+		}
+		w.Write([]byte(body))
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+func TestPwnedPasswordsChecker_Breached(t *testing.T) {
+	server := newRangeServer(t, "003D68EB55068C33ACE09247EE4C639306B:3\r\n1E4C9B93F3F0682250B6CF8331B7EE68FD8:9545824\r\n")
+
+	breached, err := NewPwnedPasswordsChecker(server.URL).Breached("password")
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if !breached {
+		t.Error("Expected the password to be reported as breached")
+	}
+}
+
+func TestPwnedPasswordsChecker_NotBreached(t *testing.T) {
+	// Padding entries carry a count of 0
+	server := newRangeServer(t, "003D68EB55068C33ACE09247EE4C639306B:3\r\n1E4C9B93F3F0682250B6CF8331B7EE68FD8:0\r\n")
+
+	breached, err := NewPwnedPasswordsChecker(server.URL).Breached("password")
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if breached {
+		t.Error("Expected a padding entry not to count as a breach")
+	}
+}
+
+func TestPwnedPasswordsChecker_Unavailable(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer server.Close()
+
+	if _, err := NewPwnedPasswordsChecker(server.URL).Breached("password"); err == nil {
+		t.Error("Expected an error when the API is unavailable")
+	}
+}
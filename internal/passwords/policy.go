@@ -0,0 +1,66 @@
+package passwords
+
+import (
+	"fmt"
+	"unicode"
+	"unicode/utf8"
+)
+
+// Policy is the strength new passwords must have
+type Policy struct {
+	MinLength     int
+	RequireUpper  bool
+	RequireLower  bool
+	RequireDigit  bool
+	RequireSymbol bool
+	// HistorySize is how many of the latest passwords of a user, the current one included, cannot be
+	// chosen again; 0 allows any reuse
+	HistorySize int
+}
+
+// DefaultPolicy asks for 8 characters mixing upper and lower case letters and digits, and refuses the
+// last 5 passwords of the user
+func DefaultPolicy() Policy {
+	return Policy{
+		MinLength:    8,
+		RequireUpper: true,
+		RequireLower: true,
+		RequireDigit: true,
+		HistorySize:  5,
+	}
+}
+
+// Violations lists the rules of the policy a password breaks, in the words of "password must ..."
+func (p Policy) Violations(password string) []string {
+	var hasUpper, hasLower, hasDigit, hasSymbol bool
+	for _, r := range password {
+		switch {
+		case unicode.IsUpper(r):
+			hasUpper = true
+		case unicode.IsLower(r):
+			hasLower = true
+		case unicode.IsDigit(r):
+			hasDigit = true
+		case unicode.IsPunct(r) || unicode.IsSymbol(r) || unicode.IsSpace(r):
+			hasSymbol = true
+		}
+	}
+
+	var violations []string
+	if utf8.RuneCountInString(password) < p.MinLength {
+		violations = append(violations, fmt.Sprintf("be at least %d characters long", p.MinLength))
+	}
+	if p.RequireUpper && !hasUpper {
+		violations = append(violations, "contain an upper case letter")
+	}
+	if p.RequireLower && !hasLower {
+		violations = append(violations, "contain a lower case letter")
+	}
+	if p.RequireDigit && !hasDigit {
+		violations = append(violations, "contain a digit")
+	}
+	if p.RequireSymbol && !hasSymbol {
+		violations = append(violations, "contain a symbol")
+	}
+	return violations
+}
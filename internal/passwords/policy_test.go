@@ -0,0 +1,39 @@
+package passwords
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestPolicy_Violations(t *testing.T) {
+	policy := Policy{MinLength: 8, RequireUpper: true, RequireLower: true, RequireDigit: true, RequireSymbol: true}
+
+	tests := []struct {
+		name     string
+		password string
+		want     []string
+	}{
+		{"strong", "Correct-horse7", nil},
+		{"too short", "Ab1!", []string{"be at least 8 characters long"}},
+		{"no upper case", "correct-horse7", []string{"contain an upper case letter"}},
+		{"no lower case", "CORRECT-HORSE7", []string{"contain a lower case letter"}},
+		{"no digit", "Correct-horse", []string{"contain a digit"}},
+		{"no symbol", "Correcthorse7", []string{"contain a symbol"}},
+		{"several rules", "abc", []string{"be at least 8 characters long", "contain an upper case letter", "contain a digit", "contain a symbol"}},
+		{"length counts characters", "Äöü-ßé1x", nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := policy.Violations(tt.password); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Violations(%q) = %v, want %v", tt.password, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPolicy_ViolationsOnlyLength(t *testing.T) {
+	if got := (Policy{MinLength: 6}).Violations("simple"); got != nil {
+		t.Errorf("Expected no violation without character classes, got %v", got)
+	}
+}
@@ -0,0 +1,68 @@
+package payments
+
+import (
+	"github.com/limistah/wallet-service/internal/breaker"
+	"github.com/shopspring/decimal"
+)
+
+// NewBreakerPaymentProvider guards checkout initialization with a circuit breaker; webhooks are
+// inbound and are not affected
+func NewBreakerPaymentProvider(provider PaymentProvider, b *breaker.Breaker) PaymentProvider {
+	return &breakerPaymentProvider{PaymentProvider: provider, breaker: b}
+}
+
+type breakerPaymentProvider struct {
+	PaymentProvider
+	breaker *breaker.Breaker
+}
+
+func (p *breakerPaymentProvider) InitializeCheckout(req CheckoutRequest) (*Checkout, error) {
+	var checkout *Checkout
+	err := p.breaker.Execute(func() error {
+		var err error
+		checkout, err = p.PaymentProvider.InitializeCheckout(req)
+		return err
+	})
+	return checkout, err
+}
+
+// NewBreakerCardPaymentProvider guards payment intent creation with a circuit breaker
+func NewBreakerCardPaymentProvider(provider CardPaymentProvider, b *breaker.Breaker) CardPaymentProvider {
+	return &breakerCardPaymentProvider{CardPaymentProvider: provider, breaker: b}
+}
+
+type breakerCardPaymentProvider struct {
+	CardPaymentProvider
+	breaker *breaker.Breaker
+}
+
+func (p *breakerCardPaymentProvider) CreatePaymentIntent(amount decimal.Decimal, currency, reference string) (*PaymentIntent, error) {
+	var intent *PaymentIntent
+	err := p.breaker.Execute(func() error {
+		var err error
+		intent, err = p.CardPaymentProvider.CreatePaymentIntent(amount, currency, reference)
+		return err
+	})
+	return intent, err
+}
+
+// NewBreakerPayoutProvider guards payout submission with a circuit breaker. While the circuit is
+// open InitiatePayout returns breaker.ErrOpen and the payout was never sent, so it is safe to retry
+func NewBreakerPayoutProvider(provider PayoutProvider, b *breaker.Breaker) PayoutProvider {
+	return &breakerPayoutProvider{PayoutProvider: provider, breaker: b}
+}
+
+type breakerPayoutProvider struct {
+	PayoutProvider
+	breaker *breaker.Breaker
+}
+
+func (p *breakerPayoutProvider) InitiatePayout(req PayoutRequest) (string, error) {
+	var providerReference string
+	err := p.breaker.Execute(func() error {
+		var err error
+		providerReference, err = p.PayoutProvider.InitiatePayout(req)
+		return err
+	})
+	return providerReference, err
+}
@@ -0,0 +1,43 @@
+package payments
+
+import (
+	"net/http"
+
+	"github.com/shopspring/decimal"
+)
+
+// PayoutRequest describes a transfer from the platform to a customer's bank account
+type PayoutRequest struct {
+	Reference     string
+	Amount        decimal.Decimal
+	Currency      string
+	BankCode      string
+	AccountNumber string
+	AccountName   string
+	Narration     string
+}
+
+// PayoutEventStatus is the final outcome reported by a provider for a payout
+type PayoutEventStatus string
+
+const (
+	PayoutEventSucceeded PayoutEventStatus = "SUCCEEDED"
+	PayoutEventFailed    PayoutEventStatus = "FAILED"
+)
+
+// PayoutEvent is a provider agnostic payout status update extracted from a webhook
+type PayoutEvent struct {
+	Reference     string
+	Status        PayoutEventStatus
+	FailureReason string
+}
+
+// PayoutProvider sends bank payouts and reports their outcome through signed webhooks
+type PayoutProvider interface {
+	Name() string
+	// InitiatePayout submits the transfer and returns the provider's reference for it
+	InitiatePayout(req PayoutRequest) (string, error)
+	// ParsePayoutWebhook verifies the payload signature and extracts the payout outcome.
+	// It returns a nil event for notifications that do not settle a payout.
+	ParsePayoutWebhook(payload []byte, headers http.Header) (*PayoutEvent, error)
+}
@@ -0,0 +1,221 @@
+package payments
+
+import (
+	"bytes"
+	"crypto/sha512"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+const (
+	// PaystackProviderName identifies Paystack deposits and webhooks
+	PaystackProviderName = "paystack"
+
+	paystackAPIBaseURL = "https://api.paystack.co"
+)
+
+// PaystackClient initializes Paystack transactions and verifies Paystack webhooks
+type PaystackClient struct {
+	secretKey string
+	baseURL   string
+	client    *http.Client
+}
+
+// NewPaystackClient creates a new Paystack client
+func NewPaystackClient(secretKey string) *PaystackClient {
+	return &PaystackClient{
+		secretKey: secretKey,
+		baseURL:   paystackAPIBaseURL,
+		client:    &http.Client{Timeout: 15 * time.Second},
+	}
+}
+
+// Name implements PaymentProvider
+func (p *PaystackClient) Name() string {
+	return PaystackProviderName
+}
+
+// InitializeCheckout implements PaymentProvider
+func (p *PaystackClient) InitializeCheckout(req CheckoutRequest) (*Checkout, error) {
+	payload, err := json.Marshal(map[string]interface{}{
+		"email":        req.Email,
+		"amount":       req.Amount.Shift(2).Round(0).IntPart(),
+		"currency":     strings.ToUpper(req.Currency),
+		"reference":    req.Reference,
+		"callback_url": req.CallbackURL,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode Paystack request: %w", err)
+	}
+
+	var response struct {
+		Status  bool   `json:"status"`
+		Message string `json:"message"`
+		Data    struct {
+			AuthorizationURL string `json:"authorization_url"`
+			Reference        string `json:"reference"`
+		} `json:"data"`
+	}
+	if err := doProviderRequest(p.client, p.baseURL+"/transaction/initialize", p.secretKey, payload, &response); err != nil {
+		return nil, fmt.Errorf("Paystack %w", err)
+	}
+	if !response.Status {
+		return nil, fmt.Errorf("Paystack rejected checkout: %s", response.Message)
+	}
+
+	return &Checkout{
+		Reference:        response.Data.Reference,
+		AuthorizationURL: response.Data.AuthorizationURL,
+	}, nil
+}
+
+// ParseWebhook implements WebhookVerifier; Paystack signs the body with HMAC-SHA512 of the secret key
+func (p *PaystackClient) ParseWebhook(payload []byte, headers http.Header) (*DepositEvent, error) {
+	if !ValidHMAC(sha512.New, []byte(p.secretKey), payload, headers.Get("X-Paystack-Signature")) {
+		return nil, ErrInvalidSignature
+	}
+
+	var envelope struct {
+		Event string `json:"event"`
+		Data  struct {
+			Reference       string `json:"reference"`
+			Amount          int64  `json:"amount"`
+			Currency        string `json:"currency"`
+			Status          string `json:"status"`
+			GatewayResponse string `json:"gateway_response"`
+		} `json:"data"`
+	}
+	if err := json.Unmarshal(payload, &envelope); err != nil {
+		return nil, fmt.Errorf("invalid webhook payload: %w", err)
+	}
+	if envelope.Event != "charge.success" {
+		return nil, nil
+	}
+
+	event := &DepositEvent{
+		ExternalReference: envelope.Data.Reference,
+		Amount:            decimal.New(envelope.Data.Amount, -2),
+		Currency:          strings.ToUpper(envelope.Data.Currency),
+		Status:            DepositEventSucceeded,
+	}
+	if envelope.Data.Status != "success" {
+		event.Status = DepositEventFailed
+		event.FailureReason = envelope.Data.GatewayResponse
+	}
+	return event, nil
+}
+
+// InitiatePayout implements PayoutProvider; Paystack requires a transfer recipient before the transfer itself
+func (p *PaystackClient) InitiatePayout(req PayoutRequest) (string, error) {
+	recipientPayload, err := json.Marshal(map[string]string{
+		"type":           "nuban",
+		"name":           req.AccountName,
+		"account_number": req.AccountNumber,
+		"bank_code":      req.BankCode,
+		"currency":       strings.ToUpper(req.Currency),
+	})
+	if err != nil {
+		return "", fmt.Errorf("failed to encode Paystack request: %w", err)
+	}
+
+	var recipient struct {
+		Status  bool   `json:"status"`
+		Message string `json:"message"`
+		Data    struct {
+			RecipientCode string `json:"recipient_code"`
+		} `json:"data"`
+	}
+	if err := doProviderRequest(p.client, p.baseURL+"/transferrecipient", p.secretKey, recipientPayload, &recipient); err != nil {
+		return "", fmt.Errorf("Paystack %w", err)
+	}
+	if !recipient.Status {
+		return "", fmt.Errorf("Paystack rejected recipient: %s", recipient.Message)
+	}
+
+	transferPayload, err := json.Marshal(map[string]interface{}{
+		"source":    "balance",
+		"amount":    req.Amount.Shift(2).Round(0).IntPart(),
+		"recipient": recipient.Data.RecipientCode,
+		"reference": req.Reference,
+		"reason":    req.Narration,
+	})
+	if err != nil {
+		return "", fmt.Errorf("failed to encode Paystack request: %w", err)
+	}
+
+	var transfer struct {
+		Status  bool   `json:"status"`
+		Message string `json:"message"`
+		Data    struct {
+			TransferCode string `json:"transfer_code"`
+		} `json:"data"`
+	}
+	if err := doProviderRequest(p.client, p.baseURL+"/transfer", p.secretKey, transferPayload, &transfer); err != nil {
+		return "", fmt.Errorf("Paystack %w", err)
+	}
+	if !transfer.Status {
+		return "", fmt.Errorf("Paystack rejected payout: %s", transfer.Message)
+	}
+	return transfer.Data.TransferCode, nil
+}
+
+// ParsePayoutWebhook implements PayoutProvider
+func (p *PaystackClient) ParsePayoutWebhook(payload []byte, headers http.Header) (*PayoutEvent, error) {
+	if !ValidHMAC(sha512.New, []byte(p.secretKey), payload, headers.Get("X-Paystack-Signature")) {
+		return nil, ErrInvalidSignature
+	}
+
+	var envelope struct {
+		Event string `json:"event"`
+		Data  struct {
+			Reference string `json:"reference"`
+		} `json:"data"`
+	}
+	if err := json.Unmarshal(payload, &envelope); err != nil {
+		return nil, fmt.Errorf("invalid webhook payload: %w", err)
+	}
+
+	event := &PayoutEvent{Reference: envelope.Data.Reference}
+	switch envelope.Event {
+	case "transfer.success":
+		event.Status = PayoutEventSucceeded
+	case "transfer.failed", "transfer.reversed":
+		event.Status = PayoutEventFailed
+		event.FailureReason = strings.Replace(envelope.Event, ".", " ", 1)
+	default:
+		return nil, nil
+	}
+	return event, nil
+}
+
+// doProviderRequest posts a JSON payload with a bearer secret and decodes the JSON response
+func doProviderRequest(client *http.Client, endpoint, secretKey string, payload []byte, out interface{}) error {
+	req, err := http.NewRequest(http.MethodPost, endpoint, bytes.NewReader(payload))
+	if err != nil {
+		return fmt.Errorf("failed to build request: %w", err)
+	}
+	req.Header.Set("Authorization", "Bearer "+secretKey)
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return fmt.Errorf("request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	body, _ := io.ReadAll(io.LimitReader(resp.Body, 1<<20))
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("returned status %d: %s", resp.StatusCode, strings.TrimSpace(string(body)))
+	}
+
+	if err := json.Unmarshal(body, out); err != nil {
+		return fmt.Errorf("failed to decode response: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,26 @@
+package payments
+
+import (
+	"github.com/shopspring/decimal"
+)
+
+// CheckoutRequest describes a hosted checkout to fund a wallet
+type CheckoutRequest struct {
+	Reference   string
+	Amount      decimal.Decimal
+	Currency    string
+	Email       string
+	CallbackURL string
+}
+
+// Checkout is a hosted payment page the customer is redirected to
+type Checkout struct {
+	Reference        string
+	AuthorizationURL string
+}
+
+// PaymentProvider initializes hosted checkouts and confirms them through signed webhooks
+type PaymentProvider interface {
+	WebhookVerifier
+	InitializeCheckout(req CheckoutRequest) (*Checkout, error)
+}
@@ -0,0 +1,144 @@
+package payments
+
+import (
+	"crypto/hmac"
+	"crypto/sha512"
+	"encoding/hex"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/shopspring/decimal"
+)
+
+func TestPaystackClient_ParseWebhook(t *testing.T) {
+	client := NewPaystackClient("sk_test")
+	payload := []byte(`{"event":"charge.success","data":{"reference":"TXN-1","amount":1050000,"currency":"NGN","status":"success"}}`)
+
+	mac := hmac.New(sha512.New, []byte("sk_test"))
+	mac.Write(payload)
+	headers := http.Header{}
+	headers.Set("X-Paystack-Signature", hex.EncodeToString(mac.Sum(nil)))
+
+	event, err := client.ParseWebhook(payload, headers)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if event.ExternalReference != "TXN-1" || event.Status != DepositEventSucceeded || !event.Amount.Equal(decimal.NewFromInt(10500)) {
+		t.Errorf("Unexpected event: %+v", event)
+	}
+
+	headers.Set("X-Paystack-Signature", "deadbeef")
+	if _, err := client.ParseWebhook(payload, headers); err != ErrInvalidSignature {
+		t.Errorf("Expected ErrInvalidSignature, got %v", err)
+	}
+}
+
+func TestFlutterwaveClient_ParseWebhook(t *testing.T) {
+	client := NewFlutterwaveClient("FLWSECK_TEST", "hash")
+	payload := []byte(`{"event":"charge.completed","data":{"tx_ref":"TXN-2","amount":200.5,"currency":"GHS","status":"failed","processor_response":"Declined"}}`)
+
+	headers := http.Header{}
+	headers.Set("Verif-Hash", "hash")
+	event, err := client.ParseWebhook(payload, headers)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if event.ExternalReference != "TXN-2" || event.Status != DepositEventFailed || event.FailureReason != "Declined" {
+		t.Errorf("Unexpected event: %+v", event)
+	}
+
+	headers.Set("Verif-Hash", "other")
+	if _, err := client.ParseWebhook(payload, headers); err != ErrInvalidSignature {
+		t.Errorf("Expected ErrInvalidSignature, got %v", err)
+	}
+}
+
+func TestPaystackClient_InitializeCheckout(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer sk_test" {
+			t.Errorf("Unexpected authorization header: %q", r.Header.Get("Authorization"))
+		}
+		var body map[string]interface{}
+		json.NewDecoder(r.Body).Decode(&body)
+		if body["amount"] != float64(2500) || body["reference"] != "TXN-3" {
+			t.Errorf("Unexpected body: %v", body)
+		}
+		w.Write([]byte(`{"status":true,"data":{"authorization_url":"https://checkout.paystack.com/abc","reference":"TXN-3"}}`))
+	}))
+	defer server.Close()
+
+	client := NewPaystackClient("sk_test")
+	client.baseURL = server.URL
+
+	checkout, err := client.InitializeCheckout(CheckoutRequest{
+		Reference: "TXN-3",
+		Amount:    decimal.NewFromInt(25),
+		Currency:  "NGN",
+		Email:     "jane@example.com",
+	})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if checkout.AuthorizationURL != "https://checkout.paystack.com/abc" || checkout.Reference != "TXN-3" {
+		t.Errorf("Unexpected checkout: %+v", checkout)
+	}
+}
+
+func TestFlutterwaveClient_ParsePayoutWebhook(t *testing.T) {
+	client := NewFlutterwaveClient("FLWSECK_TEST", "hash")
+	headers := http.Header{}
+	headers.Set("Verif-Hash", "hash")
+
+	payload := []byte(`{"event":"transfer.completed","data":{"reference":"WTH-1","status":"FAILED","complete_message":"Account resolve failed"}}`)
+	event, err := client.ParsePayoutWebhook(payload, headers)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if event.Reference != "WTH-1" || event.Status != PayoutEventFailed || event.FailureReason != "Account resolve failed" {
+		t.Errorf("Unexpected event: %+v", event)
+	}
+
+	charge := []byte(`{"event":"charge.completed","data":{"tx_ref":"TXN-1","status":"successful"}}`)
+	if event, err := client.ParsePayoutWebhook(charge, headers); err != nil || event != nil {
+		t.Errorf("Expected charge events to be ignored, got %+v, %v", event, err)
+	}
+}
+
+func TestPaystackClient_InitiatePayout(t *testing.T) {
+	var paths []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		paths = append(paths, r.URL.Path)
+		switch r.URL.Path {
+		case "/transferrecipient":
+			w.Write([]byte(`{"status":true,"data":{"recipient_code":"RCP_1"}}`))
+		case "/transfer":
+			var body map[string]interface{}
+			json.NewDecoder(r.Body).Decode(&body)
+			if body["recipient"] != "RCP_1" || body["amount"] != float64(5025) {
+				t.Errorf("Unexpected transfer body: %v", body)
+			}
+			w.Write([]byte(`{"status":true,"data":{"transfer_code":"TRF_1"}}`))
+		}
+	}))
+	defer server.Close()
+
+	client := NewPaystackClient("sk_test")
+	client.baseURL = server.URL
+
+	reference, err := client.InitiatePayout(PayoutRequest{
+		Reference:     "WTH-2",
+		Amount:        decimal.RequireFromString("50.25"),
+		Currency:      "NGN",
+		BankCode:      "058",
+		AccountNumber: "0123456789",
+		AccountName:   "Jane Doe",
+	})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if reference != "TRF_1" || len(paths) != 2 {
+		t.Errorf("Unexpected result %q after calls %v", reference, paths)
+	}
+}
@@ -0,0 +1,87 @@
+package payments
+
+import (
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/limistah/wallet-service/internal/apperrors"
+	"github.com/shopspring/decimal"
+	"github.com/skip2/go-qrcode"
+)
+
+const (
+	// QRScheme prefixes every wallet QR payload so scanners can tell them apart from other codes
+	QRScheme = "walletpay"
+
+	// qrMaxNoteLength keeps payloads small enough to scan reliably from a phone screen
+	qrMaxNoteLength = 100
+)
+
+// ErrInvalidQRPayload is returned when a scanned code is not a wallet payment code
+var ErrInvalidQRPayload = apperrors.New(apperrors.KindInvalid, apperrors.CodeInvalidQRCode, "invalid QR payload")
+
+// QRPayload is the content of an in-person payment code: who to pay and, optionally, how much
+type QRPayload struct {
+	WalletID uint
+	Currency string
+	Amount   *decimal.Decimal
+	Note     string
+}
+
+// Encode renders the payload as a walletpay:// URI
+func (p QRPayload) Encode() string {
+	query := url.Values{}
+	query.Set("wallet", strconv.FormatUint(uint64(p.WalletID), 10))
+	query.Set("currency", p.Currency)
+	if p.Amount != nil {
+		query.Set("amount", p.Amount.StringFixed(2))
+	}
+	if p.Note != "" {
+		query.Set("note", p.Note)
+	}
+	return QRScheme + "://pay?" + query.Encode()
+}
+
+// PNG renders the encoded payload as a QR code image of the given size in pixels
+func (p QRPayload) PNG(size int) ([]byte, error) {
+	png, err := qrcode.Encode(p.Encode(), qrcode.Medium, size)
+	if err != nil {
+		return nil, fmt.Errorf("failed to render QR code: %w", err)
+	}
+	return png, nil
+}
+
+// ParseQRPayload decodes a scanned walletpay:// URI
+func ParseQRPayload(raw string) (*QRPayload, error) {
+	u, err := url.Parse(strings.TrimSpace(raw))
+	if err != nil || u.Scheme != QRScheme || u.Host != "pay" {
+		return nil, ErrInvalidQRPayload
+	}
+
+	query := u.Query()
+	walletID, err := strconv.ParseUint(query.Get("wallet"), 10, 64)
+	if err != nil || walletID == 0 {
+		return nil, ErrInvalidQRPayload
+	}
+
+	payload := &QRPayload{
+		WalletID: uint(walletID),
+		Currency: strings.ToUpper(query.Get("currency")),
+		Note:     query.Get("note"),
+	}
+	if len(payload.Currency) != 3 || len(payload.Note) > qrMaxNoteLength {
+		return nil, ErrInvalidQRPayload
+	}
+
+	if raw := query.Get("amount"); raw != "" {
+		amount, err := decimal.NewFromString(raw)
+		if err != nil || amount.LessThanOrEqual(decimal.Zero) {
+			return nil, ErrInvalidQRPayload
+		}
+		payload.Amount = &amount
+	}
+
+	return payload, nil
+}
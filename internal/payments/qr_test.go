@@ -0,0 +1,43 @@
+package payments
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/shopspring/decimal"
+)
+
+func TestQRPayload_RoundTrip(t *testing.T) {
+	amount := decimal.NewFromFloat(12.5)
+	payload := QRPayload{WalletID: 7, Currency: "USD", Amount: &amount, Note: "Coffee & cake"}
+
+	parsed, err := ParseQRPayload(payload.Encode())
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if parsed.WalletID != 7 || parsed.Currency != "USD" || parsed.Note != "Coffee & cake" {
+		t.Errorf("Unexpected payload: %+v", parsed)
+	}
+	if parsed.Amount == nil || !parsed.Amount.Equal(amount) {
+		t.Errorf("Expected amount %s, got %v", amount, parsed.Amount)
+	}
+
+	png, err := payload.PNG(256)
+	if err != nil {
+		t.Fatalf("Unexpected error rendering PNG: %v", err)
+	}
+	if !bytes.HasPrefix(png, []byte("\x89PNG")) {
+		t.Error("Expected a PNG image")
+	}
+
+	for _, raw := range []string{
+		"https://pay?wallet=7&currency=USD",
+		"walletpay://pay?wallet=abc&currency=USD",
+		"walletpay://pay?wallet=7&currency=US",
+		"walletpay://pay?wallet=7&currency=USD&amount=-5",
+	} {
+		if _, err := ParseQRPayload(raw); err != ErrInvalidQRPayload {
+			t.Errorf("Expected %q to be rejected, got %v", raw, err)
+		}
+	}
+}
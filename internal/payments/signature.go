@@ -0,0 +1,23 @@
+package payments
+
+import (
+	"crypto/hmac"
+	"encoding/hex"
+	"hash"
+)
+
+// ValidHMAC reports whether signature is the hex encoded HMAC of payload, comparing in constant time
+func ValidHMAC(h func() hash.Hash, secret, payload []byte, signature string) bool {
+	if len(secret) == 0 || signature == "" {
+		return false
+	}
+
+	expected, err := hex.DecodeString(signature)
+	if err != nil {
+		return false
+	}
+
+	mac := hmac.New(h, secret)
+	mac.Write(payload)
+	return hmac.Equal(mac.Sum(nil), expected)
+}
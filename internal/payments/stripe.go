@@ -0,0 +1,212 @@
+package payments
+
+import (
+	"crypto/sha256"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+const (
+	// StripeProviderName identifies Stripe deposits and webhooks
+	StripeProviderName = "stripe"
+
+	stripeAPIBaseURL = "https://api.stripe.com/v1"
+
+	// stripeSignatureTolerance bounds how old a signed webhook may be to limit replays
+	stripeSignatureTolerance = 5 * time.Minute
+)
+
+// stripeZeroDecimalCurrencies are charged in whole units rather than cents
+var stripeZeroDecimalCurrencies = map[string]bool{
+	"BIF": true, "CLP": true, "DJF": true, "GNF": true, "JPY": true, "KMF": true, "KRW": true, "MGA": true,
+	"PYG": true, "RWF": true, "UGX": true, "VND": true, "VUV": true, "XAF": true, "XOF": true, "XPF": true,
+}
+
+// PaymentIntent is the subset of a Stripe PaymentIntent needed to complete a card funding
+type PaymentIntent struct {
+	ID           string `json:"id"`
+	ClientSecret string `json:"client_secret"`
+	Status       string `json:"status"`
+}
+
+// CardPaymentProvider creates card payments that are confirmed by the client and settled by webhook
+type CardPaymentProvider interface {
+	Name() string
+	CreatePaymentIntent(amount decimal.Decimal, currency, reference string) (*PaymentIntent, error)
+}
+
+// StripeClient talks to the Stripe API and verifies Stripe webhooks
+type StripeClient struct {
+	secretKey     string
+	webhookSecret []byte
+	baseURL       string
+	client        *http.Client
+	now           func() time.Time
+}
+
+// NewStripeClient creates a new Stripe client
+func NewStripeClient(secretKey, webhookSecret string) *StripeClient {
+	return &StripeClient{
+		secretKey:     secretKey,
+		webhookSecret: []byte(webhookSecret),
+		baseURL:       stripeAPIBaseURL,
+		client:        &http.Client{Timeout: 15 * time.Second},
+		now:           time.Now,
+	}
+}
+
+// Name implements CardPaymentProvider and WebhookVerifier
+func (s *StripeClient) Name() string {
+	return StripeProviderName
+}
+
+// CreatePaymentIntent implements CardPaymentProvider
+func (s *StripeClient) CreatePaymentIntent(amount decimal.Decimal, currency, reference string) (*PaymentIntent, error) {
+	form := url.Values{}
+	form.Set("amount", strconv.FormatInt(toStripeAmount(amount, currency), 10))
+	form.Set("currency", strings.ToLower(currency))
+	form.Set("automatic_payment_methods[enabled]", "true")
+	form.Set("metadata[reference]", reference)
+
+	req, err := http.NewRequest(http.MethodPost, s.baseURL+"/payment_intents", strings.NewReader(form.Encode()))
+	if err != nil {
+		return nil, fmt.Errorf("failed to build Stripe request: %w", err)
+	}
+	req.SetBasicAuth(s.secretKey, "")
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	// Retrying with the same reference never creates a second intent
+	req.Header.Set("Idempotency-Key", reference)
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("Stripe request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	body, _ := io.ReadAll(io.LimitReader(resp.Body, 1<<20))
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return nil, fmt.Errorf("Stripe returned status %d: %s", resp.StatusCode, stripeErrorMessage(body))
+	}
+
+	var intent PaymentIntent
+	if err := json.Unmarshal(body, &intent); err != nil {
+		return nil, fmt.Errorf("failed to decode Stripe response: %w", err)
+	}
+	return &intent, nil
+}
+
+// ParseWebhook implements WebhookVerifier
+func (s *StripeClient) ParseWebhook(payload []byte, headers http.Header) (*DepositEvent, error) {
+	if err := s.verifySignature(payload, headers.Get("Stripe-Signature")); err != nil {
+		return nil, err
+	}
+
+	var envelope struct {
+		Type string `json:"type"`
+		Data struct {
+			Object struct {
+				ID               string `json:"id"`
+				AmountReceived   int64  `json:"amount_received"`
+				Currency         string `json:"currency"`
+				LastPaymentError *struct {
+					Message string `json:"message"`
+				} `json:"last_payment_error"`
+				CancellationReason string `json:"cancellation_reason"`
+			} `json:"object"`
+		} `json:"data"`
+	}
+	if err := json.Unmarshal(payload, &envelope); err != nil {
+		return nil, fmt.Errorf("invalid webhook payload: %w", err)
+	}
+
+	intent := envelope.Data.Object
+	event := &DepositEvent{
+		ExternalReference: intent.ID,
+		Currency:          strings.ToUpper(intent.Currency),
+	}
+
+	switch envelope.Type {
+	case "payment_intent.succeeded":
+		event.Status = DepositEventSucceeded
+		event.Amount = fromStripeAmount(intent.AmountReceived, intent.Currency)
+	case "payment_intent.payment_failed":
+		event.Status = DepositEventFailed
+		if intent.LastPaymentError != nil {
+			event.FailureReason = intent.LastPaymentError.Message
+		}
+	case "payment_intent.canceled":
+		event.Status = DepositEventCancelled
+		event.FailureReason = intent.CancellationReason
+	default:
+		return nil, nil
+	}
+	return event, nil
+}
+
+// verifySignature checks a Stripe-Signature header of the form t=<unix>,v1=<hex>[,v1=<hex>]
+func (s *StripeClient) verifySignature(payload []byte, header string) error {
+	var timestamp string
+	var signatures []string
+	for _, part := range strings.Split(header, ",") {
+		key, value, _ := strings.Cut(strings.TrimSpace(part), "=")
+		switch key {
+		case "t":
+			timestamp = value
+		case "v1":
+			signatures = append(signatures, value)
+		}
+	}
+
+	signedAt, err := strconv.ParseInt(timestamp, 10, 64)
+	if err != nil || len(signatures) == 0 {
+		return ErrInvalidSignature
+	}
+	if s.now().Sub(time.Unix(signedAt, 0)) > stripeSignatureTolerance {
+		return fmt.Errorf("%w: timestamp outside tolerance", ErrInvalidSignature)
+	}
+
+	signedPayload := append([]byte(timestamp+"."), payload...)
+	for _, signature := range signatures {
+		if ValidHMAC(sha256.New, s.webhookSecret, signedPayload, signature) {
+			return nil
+		}
+	}
+	return ErrInvalidSignature
+}
+
+// toStripeAmount converts an amount to the currency's smallest unit
+func toStripeAmount(amount decimal.Decimal, currency string) int64 {
+	if stripeZeroDecimalCurrencies[strings.ToUpper(currency)] {
+		return amount.Round(0).IntPart()
+	}
+	return amount.Shift(2).Round(0).IntPart()
+}
+
+// fromStripeAmount converts an amount in the currency's smallest unit back to a decimal
+func fromStripeAmount(amount int64, currency string) decimal.Decimal {
+	if stripeZeroDecimalCurrencies[strings.ToUpper(currency)] {
+		return decimal.NewFromInt(amount)
+	}
+	return decimal.New(amount, -2)
+}
+
+// stripeErrorMessage extracts the error message from a Stripe error response
+func stripeErrorMessage(body []byte) string {
+	var stripeErr struct {
+		Error struct {
+			Message string `json:"message"`
+		} `json:"error"`
+	}
+	if err := json.Unmarshal(body, &stripeErr); err != nil || stripeErr.Error.Message == "" {
+		return strings.TrimSpace(string(body))
+	}
+	return stripeErr.Error.Message
+}
@@ -0,0 +1,80 @@
+package payments
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+func TestStripeClient_ParseWebhook(t *testing.T) {
+	client := NewStripeClient("sk_test", "whsec_test")
+	now := time.Unix(1700000000, 0)
+	client.now = func() time.Time { return now }
+
+	payload := []byte(`{"type":"payment_intent.succeeded","data":{"object":{"id":"pi_1","amount_received":10050,"currency":"usd"}}}`)
+	timestamp := fmt.Sprint(now.Unix())
+	validHeader := "t=" + timestamp + ",v1=" + sign("whsec_test", append([]byte(timestamp+"."), payload...))
+
+	headers := http.Header{}
+	headers.Set("Stripe-Signature", validHeader)
+	event, err := client.ParseWebhook(payload, headers)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if event.ExternalReference != "pi_1" || event.Status != DepositEventSucceeded || event.Currency != "USD" ||
+		!event.Amount.Equal(decimal.RequireFromString("100.50")) {
+		t.Errorf("Unexpected event: %+v", event)
+	}
+
+	headers.Set("Stripe-Signature", "t="+timestamp+",v1="+sign("wrong", payload))
+	if _, err := client.ParseWebhook(payload, headers); err != ErrInvalidSignature {
+		t.Errorf("Expected ErrInvalidSignature, got %v", err)
+	}
+
+	client.now = func() time.Time { return now.Add(time.Hour) }
+	headers.Set("Stripe-Signature", validHeader)
+	if _, err := client.ParseWebhook(payload, headers); err == nil {
+		t.Error("Expected stale signature to be rejected")
+	}
+}
+
+func TestStripeClient_CreatePaymentIntent(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if user, _, _ := r.BasicAuth(); user != "sk_test" {
+			t.Errorf("Unexpected API key: %q", user)
+		}
+		if r.Header.Get("Idempotency-Key") != "TXN-1" {
+			t.Errorf("Unexpected idempotency key: %q", r.Header.Get("Idempotency-Key"))
+		}
+		r.ParseForm()
+		if r.Form.Get("amount") != "2500" || r.Form.Get("currency") != "usd" {
+			t.Errorf("Unexpected form: %v", r.Form)
+		}
+		w.Write([]byte(`{"id":"pi_1","client_secret":"pi_1_secret","status":"requires_payment_method"}`))
+	}))
+	defer server.Close()
+
+	client := NewStripeClient("sk_test", "whsec_test")
+	client.baseURL = server.URL
+
+	intent, err := client.CreatePaymentIntent(decimal.NewFromInt(25), "USD", "TXN-1")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if intent.ID != "pi_1" || intent.ClientSecret != "pi_1_secret" {
+		t.Errorf("Unexpected intent: %+v", intent)
+	}
+}
+
+func TestToStripeAmount(t *testing.T) {
+	if got := toStripeAmount(decimal.RequireFromString("10.505"), "USD"); got != 1051 {
+		t.Errorf("Expected 1051, got %d", got)
+	}
+	if got := toStripeAmount(decimal.NewFromInt(500), "JPY"); got != 500 {
+		t.Errorf("Expected 500, got %d", got)
+	}
+}
@@ -0,0 +1,144 @@
+package payments
+
+import (
+	"crypto/sha256"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/shopspring/decimal"
+)
+
+// ErrInvalidSignature is returned when a webhook payload was not signed by the provider
+var ErrInvalidSignature = errors.New("invalid webhook signature")
+
+// DepositEventStatus is the outcome reported by a provider for a deposit
+type DepositEventStatus string
+
+const (
+	DepositEventSucceeded DepositEventStatus = "SUCCEEDED"
+	DepositEventFailed    DepositEventStatus = "FAILED"
+	DepositEventCancelled DepositEventStatus = "CANCELLED"
+)
+
+// DepositEvent is a provider agnostic deposit confirmation extracted from a webhook
+type DepositEvent struct {
+	ExternalReference string
+	Amount            decimal.Decimal
+	Currency          string
+	Status            DepositEventStatus
+	FailureReason     string
+}
+
+// WebhookVerifier authenticates and parses webhook deliveries from a payment provider
+type WebhookVerifier interface {
+	// Name is the provider identifier used in the webhook URL
+	Name() string
+	// ParseWebhook verifies the payload signature and extracts the deposit event.
+	// It returns a nil event for notifications that do not concern deposits.
+	ParseWebhook(payload []byte, headers http.Header) (*DepositEvent, error)
+}
+
+// Registry looks up webhook verifiers and payout providers by provider name
+type Registry struct {
+	verifiers map[string]WebhookVerifier
+	payouts   map[string]PayoutProvider
+}
+
+// NewRegistry creates a registry holding the given verifiers
+func NewRegistry(verifiers ...WebhookVerifier) *Registry {
+	registry := &Registry{
+		verifiers: make(map[string]WebhookVerifier),
+		payouts:   make(map[string]PayoutProvider),
+	}
+	for _, verifier := range verifiers {
+		registry.Register(verifier)
+	}
+	return registry
+}
+
+// Register adds or replaces the verifier for its provider name
+func (r *Registry) Register(verifier WebhookVerifier) {
+	r.verifiers[strings.ToLower(verifier.Name())] = verifier
+}
+
+// Get returns the verifier for a provider
+func (r *Registry) Get(provider string) (WebhookVerifier, bool) {
+	verifier, ok := r.verifiers[strings.ToLower(provider)]
+	return verifier, ok
+}
+
+// RegisterPayoutProvider adds or replaces the payout provider for its provider name
+func (r *Registry) RegisterPayoutProvider(provider PayoutProvider) {
+	r.payouts[strings.ToLower(provider.Name())] = provider
+}
+
+// GetPayoutProvider returns the payout provider for a provider name
+func (r *Registry) GetPayoutProvider(provider string) (PayoutProvider, bool) {
+	payoutProvider, ok := r.payouts[strings.ToLower(provider)]
+	return payoutProvider, ok
+}
+
+// HMACSignatureHeader carries the hex encoded HMAC-SHA256 of the raw request body
+const HMACSignatureHeader = "X-Webhook-Signature"
+
+// HMACWebhookVerifier handles providers that sign a simple JSON payload with a shared secret
+type HMACWebhookVerifier struct {
+	name   string
+	secret []byte
+}
+
+// NewHMACWebhookVerifier creates a verifier for a provider using a shared HMAC secret
+func NewHMACWebhookVerifier(name, secret string) *HMACWebhookVerifier {
+	return &HMACWebhookVerifier{
+		name:   name,
+		secret: []byte(secret),
+	}
+}
+
+// Name implements WebhookVerifier
+func (v *HMACWebhookVerifier) Name() string {
+	return v.name
+}
+
+// ParseWebhook implements WebhookVerifier
+func (v *HMACWebhookVerifier) ParseWebhook(payload []byte, headers http.Header) (*DepositEvent, error) {
+	signature := strings.TrimPrefix(headers.Get(HMACSignatureHeader), "sha256=")
+	if !ValidHMAC(sha256.New, v.secret, payload, signature) {
+		return nil, ErrInvalidSignature
+	}
+
+	var body struct {
+		Reference string          `json:"reference"`
+		Amount    decimal.Decimal `json:"amount"`
+		Currency  string          `json:"currency"`
+		Status    string          `json:"status"`
+		Reason    string          `json:"reason"`
+	}
+	if err := json.Unmarshal(payload, &body); err != nil {
+		return nil, fmt.Errorf("invalid webhook payload: %w", err)
+	}
+	if body.Reference == "" {
+		return nil, errors.New("invalid webhook payload: missing reference")
+	}
+
+	event := &DepositEvent{
+		ExternalReference: body.Reference,
+		Amount:            body.Amount,
+		Currency:          strings.ToUpper(body.Currency),
+		FailureReason:     body.Reason,
+	}
+	switch strings.ToLower(body.Status) {
+	case "success", "successful", "succeeded", "completed":
+		event.Status = DepositEventSucceeded
+	case "failed":
+		event.Status = DepositEventFailed
+	case "cancelled", "canceled":
+		event.Status = DepositEventCancelled
+	default:
+		return nil, nil
+	}
+	return event, nil
+}
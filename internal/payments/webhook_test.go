@@ -0,0 +1,100 @@
+package payments
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"net/http"
+	"testing"
+
+	"github.com/shopspring/decimal"
+)
+
+func sign(secret string, payload []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(payload)
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+func TestHMACWebhookVerifier_ParseWebhook(t *testing.T) {
+	verifier := NewHMACWebhookVerifier("bank", "secret")
+	payload := []byte(`{"reference":"EXT-1","amount":"150.00","currency":"ngn","status":"successful"}`)
+
+	tests := []struct {
+		name       string
+		payload    []byte
+		signature  string
+		wantErr    error
+		wantStatus DepositEventStatus
+		wantNil    bool
+	}{
+		{
+			name:       "valid signature",
+			payload:    payload,
+			signature:  sign("secret", payload),
+			wantStatus: DepositEventSucceeded,
+		},
+		{
+			name:       "prefixed signature",
+			payload:    payload,
+			signature:  "sha256=" + sign("secret", payload),
+			wantStatus: DepositEventSucceeded,
+		},
+		{
+			name:      "wrong secret",
+			payload:   payload,
+			signature: sign("other", payload),
+			wantErr:   ErrInvalidSignature,
+		},
+		{
+			name:      "missing signature",
+			payload:   payload,
+			signature: "",
+			wantErr:   ErrInvalidSignature,
+		},
+		{
+			name:      "unrelated status is ignored",
+			payload:   []byte(`{"reference":"EXT-1","status":"processing"}`),
+			signature: sign("secret", []byte(`{"reference":"EXT-1","status":"processing"}`)),
+			wantNil:   true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			headers := http.Header{}
+			headers.Set(HMACSignatureHeader, tt.signature)
+
+			event, err := verifier.ParseWebhook(tt.payload, headers)
+			if err != tt.wantErr {
+				t.Fatalf("Expected error %v, got %v", tt.wantErr, err)
+			}
+			if tt.wantErr != nil {
+				return
+			}
+			if tt.wantNil {
+				if event != nil {
+					t.Errorf("Expected event to be ignored, got %+v", event)
+				}
+				return
+			}
+			if event.Status != tt.wantStatus {
+				t.Errorf("Expected status %s, got %s", tt.wantStatus, event.Status)
+			}
+			if event.ExternalReference != "EXT-1" || event.Currency != "NGN" || !event.Amount.Equal(decimal.NewFromInt(150)) {
+				t.Errorf("Unexpected event: %+v", event)
+			}
+		})
+	}
+}
+
+func TestRegistry_Get(t *testing.T) {
+	registry := NewRegistry(NewHMACWebhookVerifier("Bank", "secret"))
+
+	if _, ok := registry.Get("bank"); !ok {
+		t.Error("Expected provider lookup to be case insensitive")
+	}
+	if _, ok := registry.Get("unknown"); ok {
+		t.Error("Expected unknown provider to be missing")
+	}
+}
@@ -0,0 +1,272 @@
+package queue
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"sync"
+	"time"
+
+	"github.com/limistah/wallet-service/internal/models"
+	"github.com/limistah/wallet-service/internal/repositories"
+	"github.com/limistah/wallet-service/internal/utils"
+)
+
+// lease is how long a worker owns a claimed job; a job still running after that is assumed lost
+// with its worker and is picked up again
+const lease = 5 * time.Minute
+
+// Handler runs a job from its JSON payload; returning an error schedules a retry
+type Handler func(payload []byte) error
+
+// Enqueuer schedules background jobs
+type Enqueuer interface {
+	Enqueue(jobType string, payload interface{}) error
+}
+
+// NoopEnqueuer discards every job
+type NoopEnqueuer struct{}
+
+// Enqueue implements Enqueuer
+func (NoopEnqueuer) Enqueue(string, interface{}) error { return nil }
+
+// permanentError marks a failure that retrying cannot fix
+type permanentError struct {
+	err error
+}
+
+func (e permanentError) Error() string { return e.err.Error() }
+
+func (e permanentError) Unwrap() error { return e.err }
+
+// Permanent wraps an error so the job goes straight to the dead letter queue instead of being retried
+func Permanent(err error) error {
+	return permanentError{err: err}
+}
+
+// Decode unmarshals a job payload; a payload that cannot be decoded fails the job permanently
+func Decode(payload []byte, v interface{}) error {
+	if err := json.Unmarshal(payload, v); err != nil {
+		return Permanent(fmt.Errorf("invalid job payload: %w", err))
+	}
+	return nil
+}
+
+// Config controls the workers and the retry policy of a queue
+type Config struct {
+	// Workers is how many jobs run at the same time
+	Workers int
+	// PollInterval is how often an idle queue checks for due jobs
+	PollInterval time.Duration
+	// MaxAttempts is how many times a job runs before it is moved to the dead letter queue
+	MaxAttempts int
+	// InitialBackoff is the wait before the first retry; each further retry doubles it up to MaxBackoff
+	InitialBackoff time.Duration
+	MaxBackoff     time.Duration
+}
+
+// Backoff returns the wait before the retry that follows the given attempt, starting at 1
+func (c Config) Backoff(attempt int) time.Duration {
+	return utils.Backoff(c.InitialBackoff, c.MaxBackoff, attempt)
+}
+
+// Retry is the retry policy of a job type that does not follow the one of its queue
+type Retry struct {
+	MaxAttempts    int
+	InitialBackoff time.Duration
+	MaxBackoff     time.Duration
+}
+
+// Backoff returns the wait before the retry that follows the given attempt, starting at 1
+func (r Retry) Backoff(attempt int) time.Duration {
+	return utils.Backoff(r.InitialBackoff, r.MaxBackoff, attempt)
+}
+
+// Queue is a database-backed job queue: jobs survive restarts, failed jobs are retried with
+// backoff, and jobs that fail every attempt are kept as dead letters
+type Queue struct {
+	repo   repositories.JobRepository
+	config Config
+	now    func() time.Time
+
+	mu       sync.RWMutex
+	handlers map[string]Handler
+	retries  map[string]Retry
+}
+
+// New creates a queue storing its jobs in repo
+func New(repo repositories.JobRepository, config Config) *Queue {
+	if config.Workers <= 0 {
+		config.Workers = 1
+	}
+	if config.MaxAttempts <= 0 {
+		config.MaxAttempts = 1
+	}
+	return &Queue{
+		repo:     repo,
+		config:   config,
+		now:      time.Now,
+		handlers: make(map[string]Handler),
+		retries:  make(map[string]Retry),
+	}
+}
+
+// Register sets the handler that runs jobs of the given type
+func (q *Queue) Register(jobType string, handler Handler) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	q.handlers[jobType] = handler
+}
+
+// RegisterWithRetry sets the handler of a job type whose jobs are retried with their own policy
+// instead of the one of the queue
+func (q *Queue) RegisterWithRetry(jobType string, handler Handler, retry Retry) {
+	if retry.MaxAttempts <= 0 {
+		retry.MaxAttempts = 1
+	}
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	q.handlers[jobType] = handler
+	q.retries[jobType] = retry
+}
+
+// retry returns the retry policy of a job type
+func (q *Queue) retry(jobType string) Retry {
+	q.mu.RLock()
+	defer q.mu.RUnlock()
+	if retry, ok := q.retries[jobType]; ok {
+		return retry
+	}
+	return Retry{
+		MaxAttempts:    q.config.MaxAttempts,
+		InitialBackoff: q.config.InitialBackoff,
+		MaxBackoff:     q.config.MaxBackoff,
+	}
+}
+
+// Enqueue stores a job to run as soon as a worker is free; payload is encoded as JSON
+func (q *Queue) Enqueue(jobType string, payload interface{}) error {
+	encoded, err := json.Marshal(payload)
+	if err != nil {
+		return fmt.Errorf("failed to encode %s job: %w", jobType, err)
+	}
+
+	job := &models.Job{
+		Type:        jobType,
+		Payload:     string(encoded),
+		Status:      models.JobStatusPending,
+		MaxAttempts: q.retry(jobType).MaxAttempts,
+		RunAt:       q.now(),
+	}
+	if err := q.repo.Create(context.Background(), job); err != nil {
+		return fmt.Errorf("failed to enqueue %s job: %w", jobType, err)
+	}
+	return nil
+}
+
+// RunDue claims a batch of due jobs, runs them on the workers and returns how many ran
+func (q *Queue) RunDue() (int, error) {
+	jobs, err := q.repo.ClaimDue(context.Background(), q.now(), lease, q.config.Workers)
+	if err != nil {
+		return 0, fmt.Errorf("failed to claim jobs: %w", err)
+	}
+
+	var wg sync.WaitGroup
+	for i := range jobs {
+		wg.Add(1)
+		go func(job *models.Job) {
+			defer wg.Done()
+			q.process(job)
+		}(&jobs[i])
+	}
+	wg.Wait()
+
+	return len(jobs), nil
+}
+
+// Start runs due jobs in the background until the returned function is called; the function
+// waits for the jobs in flight to finish
+func (q *Queue) Start() func() {
+	ticker := time.NewTicker(q.config.PollInterval)
+	done := make(chan struct{})
+	stopped := make(chan struct{})
+
+	go func() {
+		defer close(stopped)
+		for {
+			select {
+			case <-done:
+				return
+			case <-ticker.C:
+				// Keep draining while batches come back full instead of waiting a tick per batch
+				for {
+					ran, err := q.RunDue()
+					if err != nil {
+						log.Printf("Job queue: %v", err)
+					}
+					if ran < q.config.Workers || isClosed(done) {
+						break
+					}
+				}
+			}
+		}
+	}()
+
+	return func() {
+		ticker.Stop()
+		close(done)
+		<-stopped
+	}
+}
+
+// process runs a claimed job and records the outcome: completed, retried or dead
+func (q *Queue) process(job *models.Job) {
+	var err error
+	if job.Attempts > job.MaxAttempts {
+		// The job was claimed again after its worker died mid-run on the last attempt
+		err = Permanent(errors.New("job exceeded its attempts without finishing"))
+	} else {
+		err = q.run(job)
+	}
+
+	switch {
+	case err == nil:
+		err = q.repo.Complete(context.Background(), job.ID)
+	case errors.As(err, &permanentError{}) || job.Attempts >= job.MaxAttempts:
+		log.Printf("Job queue: %s job %d moved to the dead letter queue after %d attempts: %v", job.Type, job.ID, job.Attempts, err)
+		err = q.repo.Bury(context.Background(), job.ID, err.Error())
+	default:
+		err = q.repo.Retry(context.Background(), job.ID, q.now().Add(q.retry(job.Type).Backoff(job.Attempts)), err.Error())
+	}
+	if err != nil {
+		log.Printf("Job queue: failed to record the outcome of %s job %d: %v", job.Type, job.ID, err)
+	}
+}
+
+// run invokes the handler of a job and turns a panic into a failed attempt
+func (q *Queue) run(job *models.Job) (err error) {
+	q.mu.RLock()
+	handler, ok := q.handlers[job.Type]
+	q.mu.RUnlock()
+	if !ok {
+		return fmt.Errorf("no handler registered for job type %s", job.Type)
+	}
+
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("job handler panicked: %v", r)
+		}
+	}()
+	return handler([]byte(job.Payload))
+}
+
+func isClosed(done <-chan struct{}) bool {
+	select {
+	case <-done:
+		return true
+	default:
+		return false
+	}
+}
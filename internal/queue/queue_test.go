@@ -0,0 +1,304 @@
+package queue
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/limistah/wallet-service/internal/models"
+	"gorm.io/gorm"
+)
+
+// memoryJobRepository keeps jobs in memory and claims them the way the database repository does
+type memoryJobRepository struct {
+	mu     sync.Mutex
+	jobs   map[uint]*models.Job
+	nextID uint
+}
+
+func newMemoryJobRepository() *memoryJobRepository {
+	return &memoryJobRepository{jobs: make(map[uint]*models.Job)}
+}
+
+func (r *memoryJobRepository) Create(ctx context.Context, job *models.Job) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.nextID++
+	job.ID = r.nextID
+	stored := *job
+	r.jobs[job.ID] = &stored
+	return nil
+}
+
+func (r *memoryJobRepository) GetByID(ctx context.Context, id uint) (*models.Job, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	job, ok := r.jobs[id]
+	if !ok {
+		return nil, gorm.ErrRecordNotFound
+	}
+	copied := *job
+	return &copied, nil
+}
+
+func (r *memoryJobRepository) List(ctx context.Context, status models.JobStatus, offset, limit int) ([]models.Job, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var jobs []models.Job
+	for id := uint(1); id <= r.nextID; id++ {
+		if job, ok := r.jobs[id]; ok && (status == "" || job.Status == status) {
+			jobs = append(jobs, *job)
+		}
+	}
+	return jobs, nil
+}
+
+func (r *memoryJobRepository) ClaimDue(ctx context.Context, now time.Time, lease time.Duration, limit int) ([]models.Job, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var claimed []models.Job
+	for id := uint(1); id <= r.nextID && len(claimed) < limit; id++ {
+		job, ok := r.jobs[id]
+		if !ok {
+			continue
+		}
+		due := job.Status == models.JobStatusPending && !job.RunAt.After(now)
+		expired := job.Status == models.JobStatusRunning && job.LockedUntil.Before(now)
+		if !due && !expired {
+			continue
+		}
+		lockedUntil := now.Add(lease)
+		job.Status = models.JobStatusRunning
+		job.LockedUntil = &lockedUntil
+		job.Attempts++
+		claimed = append(claimed, *job)
+	}
+	return claimed, nil
+}
+
+func (r *memoryJobRepository) Complete(ctx context.Context, id uint) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	delete(r.jobs, id)
+	return nil
+}
+
+func (r *memoryJobRepository) Retry(ctx context.Context, id uint, runAt time.Time, lastError string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	job := r.jobs[id]
+	job.Status = models.JobStatusPending
+	job.RunAt = runAt
+	job.LockedUntil = nil
+	job.LastError = lastError
+	return nil
+}
+
+func (r *memoryJobRepository) Bury(ctx context.Context, id uint, lastError string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	job := r.jobs[id]
+	job.Status = models.JobStatusDead
+	job.LockedUntil = nil
+	job.LastError = lastError
+	return nil
+}
+
+func (r *memoryJobRepository) Requeue(ctx context.Context, id uint, runAt time.Time) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	job, ok := r.jobs[id]
+	if !ok || job.Status != models.JobStatusDead {
+		return gorm.ErrRecordNotFound
+	}
+	job.Status = models.JobStatusPending
+	job.Attempts = 0
+	job.RunAt = runAt
+	return nil
+}
+
+func newTestQueue(repo *memoryJobRepository, clock *time.Time) *Queue {
+	q := New(repo, Config{Workers: 2, PollInterval: time.Second, MaxAttempts: 3, InitialBackoff: time.Minute, MaxBackoff: 10 * time.Minute})
+	q.now = func() time.Time { return *clock }
+	return q
+}
+
+func TestQueue_RunsAndCompletesJobs(t *testing.T) {
+	repo := newMemoryJobRepository()
+	clock := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	q := newTestQueue(repo, &clock)
+
+	var received []uint
+	var mu sync.Mutex
+	q.Register("wallet.audit", func(payload []byte) error {
+		var args struct {
+			WalletID uint `json:"wallet_id"`
+		}
+		if err := Decode(payload, &args); err != nil {
+			return err
+		}
+		mu.Lock()
+		received = append(received, args.WalletID)
+		mu.Unlock()
+		return nil
+	})
+
+	for _, walletID := range []uint{7, 8} {
+		if err := q.Enqueue("wallet.audit", map[string]uint{"wallet_id": walletID}); err != nil {
+			t.Fatalf("Expected no error enqueueing, got %v", err)
+		}
+	}
+
+	ran, err := q.RunDue()
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if ran != 2 || len(received) != 2 {
+		t.Fatalf("Expected 2 jobs to run, ran %d and received %v", ran, received)
+	}
+	if remaining, _ := repo.List(context.Background(), "", 0, 10); len(remaining) != 0 {
+		t.Errorf("Expected completed jobs to be removed, found %d", len(remaining))
+	}
+}
+
+func TestQueue_RetriesWithBackoffThenBuries(t *testing.T) {
+	repo := newMemoryJobRepository()
+	clock := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	q := newTestQueue(repo, &clock)
+
+	calls := 0
+	q.Register("notifications.email", func([]byte) error {
+		calls++
+		return errors.New("smtp unavailable")
+	})
+	q.Enqueue("notifications.email", map[string]string{"reference": "TXN001"})
+
+	q.RunDue()
+	job, _ := repo.GetByID(context.Background(), 1)
+	if job.Status != models.JobStatusPending || !job.RunAt.Equal(clock.Add(time.Minute)) {
+		t.Fatalf("Expected a retry in 1m, got status %s at %s", job.Status, job.RunAt)
+	}
+	if job.LastError != "smtp unavailable" {
+		t.Errorf("Expected the failure to be recorded, got %q", job.LastError)
+	}
+
+	// Not due yet
+	if ran, _ := q.RunDue(); ran != 0 {
+		t.Fatalf("Expected the retry to wait for its backoff, ran %d", ran)
+	}
+
+	clock = clock.Add(time.Minute)
+	q.RunDue()
+	job, _ = repo.GetByID(context.Background(), 1)
+	if !job.RunAt.Equal(clock.Add(2 * time.Minute)) {
+		t.Fatalf("Expected the backoff to double to 2m, got run at %s", job.RunAt)
+	}
+
+	clock = clock.Add(2 * time.Minute)
+	q.RunDue()
+	job, _ = repo.GetByID(context.Background(), 1)
+	if !job.IsDead() || calls != 3 {
+		t.Fatalf("Expected the job to be dead after 3 attempts, got status %s after %d calls", job.Status, calls)
+	}
+
+	if err := repo.Requeue(context.Background(), job.ID, clock); err != nil {
+		t.Fatalf("Expected the dead job to be requeued, got %v", err)
+	}
+	q.Register("notifications.email", func([]byte) error { return nil })
+	q.RunDue()
+	if _, err := repo.GetByID(context.Background(), 1); err == nil {
+		t.Error("Expected the requeued job to complete")
+	}
+}
+
+func TestQueue_JobTypesWithTheirOwnRetryPolicy(t *testing.T) {
+	repo := newMemoryJobRepository()
+	clock := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	q := newTestQueue(repo, &clock)
+
+	calls := 0
+	q.RegisterWithRetry("webhooks.deliver", func([]byte) error {
+		calls++
+		return errors.New("endpoint returned 503")
+	}, Retry{MaxAttempts: 2, InitialBackoff: 30 * time.Second, MaxBackoff: time.Hour})
+	q.Enqueue("webhooks.deliver", map[string]uint{"delivery_id": 1})
+
+	job, _ := repo.GetByID(context.Background(), 1)
+	if job.MaxAttempts != 2 {
+		t.Fatalf("Expected the job to get the attempts of its type, got %d", job.MaxAttempts)
+	}
+
+	q.RunDue()
+	job, _ = repo.GetByID(context.Background(), 1)
+	if !job.RunAt.Equal(clock.Add(30 * time.Second)) {
+		t.Fatalf("Expected a retry after the backoff of its type, got run at %s", job.RunAt)
+	}
+
+	clock = clock.Add(30 * time.Second)
+	q.RunDue()
+	job, _ = repo.GetByID(context.Background(), 1)
+	if !job.IsDead() || calls != 2 {
+		t.Errorf("Expected the job to be dead after 2 attempts, got status %s after %d calls", job.Status, calls)
+	}
+}
+
+func TestQueue_PermanentFailuresSkipRetries(t *testing.T) {
+	repo := newMemoryJobRepository()
+	clock := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	q := newTestQueue(repo, &clock)
+
+	q.Register("wallet.audit", func(payload []byte) error {
+		var walletID uint
+		return Decode(payload, &walletID)
+	})
+	q.Enqueue("wallet.audit", "not a wallet id")
+	q.RunDue()
+
+	job, _ := repo.GetByID(context.Background(), 1)
+	if !job.IsDead() || job.Attempts != 1 {
+		t.Errorf("Expected an undecodable payload to be buried on the first attempt, got status %s after %d attempts", job.Status, job.Attempts)
+	}
+}
+
+func TestQueue_RecoversPanickingHandlers(t *testing.T) {
+	repo := newMemoryJobRepository()
+	clock := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	q := newTestQueue(repo, &clock)
+
+	q.Register("notifications.push", func([]byte) error { panic("nil sender") })
+	q.Enqueue("notifications.push", nil)
+	q.RunDue()
+
+	job, _ := repo.GetByID(context.Background(), 1)
+	if job.Status != models.JobStatusPending || job.LastError != "job handler panicked: nil sender" {
+		t.Errorf("Expected the panic to be retried, got status %s with error %q", job.Status, job.LastError)
+	}
+}
+
+func TestQueue_ReclaimsJobsWithExpiredLeases(t *testing.T) {
+	repo := newMemoryJobRepository()
+	clock := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	q := newTestQueue(repo, &clock)
+	q.Enqueue("wallet.audit", nil)
+
+	// A worker claimed the job and died without recording the outcome
+	repo.ClaimDue(context.Background(), clock, lease, 1)
+
+	ran := 0
+	q.Register("wallet.audit", func([]byte) error {
+		ran++
+		return nil
+	})
+	q.RunDue()
+	if ran != 0 {
+		t.Fatal("Expected a leased job not to run again before its lease expires")
+	}
+
+	clock = clock.Add(lease + time.Second)
+	q.RunDue()
+	if ran != 1 {
+		t.Errorf("Expected the job to run again once its lease expired, ran %d times", ran)
+	}
+}
@@ -0,0 +1,43 @@
+package repositories
+
+import (
+	"context"
+
+	"github.com/limistah/wallet-service/internal/models"
+	"gorm.io/gorm"
+)
+
+type accountStatementRepository struct {
+	db *gorm.DB
+}
+
+// NewAccountStatementRepository creates a new account statement repository
+func NewAccountStatementRepository(db *gorm.DB) AccountStatementRepository {
+	return &accountStatementRepository{db: db}
+}
+
+func (r *accountStatementRepository) Create(ctx context.Context, statement *models.AccountStatement) error {
+	return withContext(r.db, ctx).Create(statement).Error
+}
+
+func (r *accountStatementRepository) GetByID(ctx context.Context, id uint) (*models.AccountStatement, error) {
+	var statement models.AccountStatement
+	if err := withContext(r.db, ctx).First(&statement, id).Error; err != nil {
+		return nil, err
+	}
+	return &statement, nil
+}
+
+func (r *accountStatementRepository) Update(ctx context.Context, statement *models.AccountStatement) error {
+	return withContext(r.db, ctx).Save(statement).Error
+}
+
+// ListByUserID returns a user's statements newest first
+func (r *accountStatementRepository) ListByUserID(ctx context.Context, userID uint, offset, limit int) ([]models.AccountStatement, error) {
+	var statements []models.AccountStatement
+	err := withContext(r.db, ctx).Where("user_id = ?", userID).
+		Order("created_at DESC, id DESC").
+		Offset(offset).Limit(limit).
+		Find(&statements).Error
+	return statements, err
+}
@@ -0,0 +1,63 @@
+package repositories
+
+import (
+	"context"
+	"time"
+
+	"github.com/limistah/wallet-service/internal/models"
+	"gorm.io/gorm"
+)
+
+type amlCaseRepository struct {
+	db *gorm.DB
+}
+
+// NewAMLCaseRepository creates a new AML case repository
+func NewAMLCaseRepository(db *gorm.DB) AMLCaseRepository {
+	return &amlCaseRepository{db: db}
+}
+
+func (r *amlCaseRepository) GetByID(ctx context.Context, id uint) (*models.AMLCase, error) {
+	var amlCase models.AMLCase
+	err := withContext(r.db, ctx).Preload("Transaction").First(&amlCase, id).Error
+	if err != nil {
+		return nil, err
+	}
+	return &amlCase, nil
+}
+
+// List returns cases oldest first so the queue is worked in order; an empty status lists every case
+func (r *amlCaseRepository) List(ctx context.Context, status models.AMLCaseStatus, offset, limit int) ([]models.AMLCase, error) {
+	var cases []models.AMLCase
+	query := withContext(r.db, ctx).Model(&models.AMLCase{})
+	if status != "" {
+		query = query.Where("status = ?", status)
+	}
+	err := query.Order("created_at ASC, id ASC").Offset(offset).Limit(limit).Find(&cases).Error
+	return cases, err
+}
+
+func (r *amlCaseRepository) Create(ctx context.Context, amlCase *models.AMLCase) error {
+	return withContext(r.db, ctx).Create(amlCase).Error
+}
+
+func (r *amlCaseRepository) Resolve(ctx context.Context, id uint, status models.AMLCaseStatus, reviewerID uint, note string, at time.Time) (bool, error) {
+	result := withContext(r.db, ctx).Model(&models.AMLCase{}).
+		Where("id = ? AND status = ?", id, models.AMLCaseStatusPending).
+		Updates(map[string]interface{}{
+			"status":      status,
+			"reviewer_id": reviewerID,
+			"review_note": note,
+			"reviewed_at": at,
+		})
+	return result.RowsAffected > 0, result.Error
+}
+
+func (r *amlCaseRepository) ExpireByTransactionID(ctx context.Context, transactionID uint, at time.Time) error {
+	return withContext(r.db, ctx).Model(&models.AMLCase{}).
+		Where("transaction_id = ? AND status = ?", transactionID, models.AMLCaseStatusPending).
+		Updates(map[string]interface{}{
+			"status":      models.AMLCaseStatusExpired,
+			"reviewed_at": at,
+		}).Error
+}
@@ -0,0 +1,110 @@
+package repositories
+
+import (
+	"context"
+	"time"
+
+	"github.com/limistah/wallet-service/internal/models"
+	"gorm.io/gorm"
+)
+
+type archivedTransactionRepository struct {
+	db *gorm.DB
+}
+
+// NewArchivedTransactionRepository creates a new archived transaction repository
+func NewArchivedTransactionRepository(db *gorm.DB) ArchivedTransactionRepository {
+	return &archivedTransactionRepository{db: db}
+}
+
+func (r *archivedTransactionRepository) GetByID(ctx context.Context, id uint) (*models.ArchivedTransaction, error) {
+	var transaction models.ArchivedTransaction
+	if err := withContext(r.db, ctx).First(&transaction, id).Error; err != nil {
+		return nil, err
+	}
+	return &transaction, nil
+}
+
+// GetByWalletIDWithCursor pages through a wallet's archived transactions the same way as the live
+// ones, fetching one extra to show whether there is a next page
+func (r *archivedTransactionRepository) GetByWalletIDWithCursor(ctx context.Context, walletID uint, cursor *time.Time, cursorID *uint, limit int) ([]models.ArchivedTransaction, error) {
+	var transactions []models.ArchivedTransaction
+	query := withContext(r.db, ctx).Where("wallet_id = ?", walletID)
+	if cursor != nil && cursorID != nil {
+		query = query.Where("(created_at < ? OR (created_at = ? AND id < ?))", cursor, cursor, cursorID)
+	}
+	err := query.Order("created_at DESC, id DESC").Limit(limit + 1).Find(&transactions).Error
+	return transactions, err
+}
+
+// GetByWalletIDBetween returns a wallet's archived transactions created from from up to but not
+// including to, oldest first
+func (r *archivedTransactionRepository) GetByWalletIDBetween(ctx context.Context, walletID uint, from, to time.Time) ([]models.ArchivedTransaction, error) {
+	var transactions []models.ArchivedTransaction
+	err := withContext(r.db, ctx).Where("wallet_id = ? AND created_at >= ? AND created_at < ?", walletID, from, to).
+		Order("created_at ASC, id ASC").
+		Find(&transactions).Error
+	return transactions, err
+}
+
+func (r *archivedTransactionRepository) GetByReference(ctx context.Context, reference string) (*models.ArchivedTransaction, error) {
+	var transaction models.ArchivedTransaction
+	if err := withContext(r.db, ctx).Where("reference = ?", reference).First(&transaction).Error; err != nil {
+		return nil, err
+	}
+	return &transaction, nil
+}
+
+// GetByRelatedTransactionID returns the archived transactions pointing at the given one
+func (r *archivedTransactionRepository) GetByRelatedTransactionID(ctx context.Context, relatedID uint) ([]models.ArchivedTransaction, error) {
+	var transactions []models.ArchivedTransaction
+	err := withContext(r.db, ctx).Where("related_transaction_id = ?", relatedID).
+		Order("id ASC").
+		Find(&transactions).Error
+	return transactions, err
+}
+
+func (r *archivedTransactionRepository) Create(ctx context.Context, transactions []models.ArchivedTransaction) error {
+	return withContext(r.db, ctx).CreateInBatches(&transactions, 100).Error
+}
+
+type balanceCheckpointRepository struct {
+	db *gorm.DB
+}
+
+// NewBalanceCheckpointRepository creates a new balance checkpoint repository
+func NewBalanceCheckpointRepository(db *gorm.DB) BalanceCheckpointRepository {
+	return &balanceCheckpointRepository{db: db}
+}
+
+func (r *balanceCheckpointRepository) Create(ctx context.Context, checkpoint *models.BalanceCheckpoint) error {
+	return withContext(r.db, ctx).Create(checkpoint).Error
+}
+
+func (r *balanceCheckpointRepository) GetByWalletID(ctx context.Context, walletID uint) (*models.BalanceCheckpoint, error) {
+	var checkpoint models.BalanceCheckpoint
+	if err := withContext(r.db, ctx).Where("wallet_id = ?", walletID).First(&checkpoint).Error; err != nil {
+		return nil, err
+	}
+	return &checkpoint, nil
+}
+
+// Advance moves a checkpoint forward to its new balance and transaction, but only while it is still
+// at previousThroughID, so two runs cannot count the same transactions twice
+func (r *balanceCheckpointRepository) Advance(ctx context.Context, checkpoint *models.BalanceCheckpoint, previousThroughID uint) (bool, error) {
+	result := withContext(r.db, ctx).Model(&models.BalanceCheckpoint{}).
+		Where("id = ? AND through_transaction_id = ?", checkpoint.ID, previousThroughID).
+		Updates(map[string]interface{}{
+			"balance":                checkpoint.Balance,
+			"through_transaction_id": checkpoint.ThroughTransactionID,
+		})
+	return result.RowsAffected == 1, result.Error
+}
+
+func (r *balanceCheckpointRepository) RecordArchived(ctx context.Context, checkpoint *models.BalanceCheckpoint) error {
+	return withContext(r.db, ctx).Model(&models.BalanceCheckpoint{}).Where("id = ?", checkpoint.ID).
+		Updates(map[string]interface{}{
+			"archived_count":   checkpoint.ArchivedCount,
+			"archived_through": checkpoint.ArchivedThrough,
+		}).Error
+}
@@ -0,0 +1,38 @@
+package repositories
+
+import (
+	"context"
+
+	"github.com/limistah/wallet-service/internal/models"
+	"gorm.io/gorm"
+)
+
+type auditLogRepository struct {
+	db *gorm.DB
+}
+
+// NewAuditLogRepository creates a new audit log repository
+func NewAuditLogRepository(db *gorm.DB) AuditLogRepository {
+	return &auditLogRepository{db: db}
+}
+
+func (r *auditLogRepository) Create(ctx context.Context, entry *models.AuditLog) error {
+	return withContext(r.db, ctx).Create(entry).Error
+}
+
+func (r *auditLogRepository) List(ctx context.Context, offset, limit int) ([]models.AuditLog, error) {
+	var entries []models.AuditLog
+	err := withContext(r.db, ctx).Order("created_at DESC, id DESC").
+		Offset(offset).Limit(limit).
+		Find(&entries).Error
+	return entries, err
+}
+
+func (r *auditLogRepository) GetByActorID(ctx context.Context, actorID uint, offset, limit int) ([]models.AuditLog, error) {
+	var entries []models.AuditLog
+	err := withContext(r.db, ctx).Where("actor_id = ?", actorID).
+		Order("created_at DESC, id DESC").
+		Offset(offset).Limit(limit).
+		Find(&entries).Error
+	return entries, err
+}
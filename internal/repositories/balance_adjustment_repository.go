@@ -0,0 +1,68 @@
+package repositories
+
+import (
+	"context"
+	"time"
+
+	"github.com/limistah/wallet-service/internal/models"
+	"gorm.io/gorm"
+)
+
+type balanceAdjustmentRepository struct {
+	db *gorm.DB
+}
+
+// NewBalanceAdjustmentRepository creates a new balance adjustment repository
+func NewBalanceAdjustmentRepository(db *gorm.DB) BalanceAdjustmentRepository {
+	return &balanceAdjustmentRepository{db: db}
+}
+
+func (r *balanceAdjustmentRepository) Create(ctx context.Context, adjustment *models.BalanceAdjustment) error {
+	return withContext(r.db, ctx).Create(adjustment).Error
+}
+
+func (r *balanceAdjustmentRepository) GetByID(ctx context.Context, id uint) (*models.BalanceAdjustment, error) {
+	var adjustment models.BalanceAdjustment
+	err := withContext(r.db, ctx).First(&adjustment, id).Error
+	if err != nil {
+		return nil, err
+	}
+	return &adjustment, nil
+}
+
+// List returns adjustments oldest first so pending ones are approved in order; an empty status lists
+// every adjustment
+func (r *balanceAdjustmentRepository) List(ctx context.Context, status models.BalanceAdjustmentStatus, offset, limit int) ([]models.BalanceAdjustment, error) {
+	var adjustments []models.BalanceAdjustment
+	query := withContext(r.db, ctx).Model(&models.BalanceAdjustment{})
+	if status != "" {
+		query = query.Where("status = ?", status)
+	}
+	err := query.Order("created_at ASC, id ASC").Offset(offset).Limit(limit).Find(&adjustments).Error
+	return adjustments, err
+}
+
+func (r *balanceAdjustmentRepository) Reject(ctx context.Context, id, approverID uint, note string, at time.Time) (bool, error) {
+	result := withContext(r.db, ctx).Model(&models.BalanceAdjustment{}).
+		Where("id = ? AND status = ?", id, models.BalanceAdjustmentStatusPending).
+		Updates(map[string]interface{}{
+			"status":        models.BalanceAdjustmentStatusRejected,
+			"approver_id":   approverID,
+			"decision_note": note,
+			"decided_at":    at,
+		})
+	return result.RowsAffected > 0, result.Error
+}
+
+func (r *balanceAdjustmentRepository) Approve(ctx context.Context, id, approverID uint, note string, journalEntryID uint, at time.Time) (bool, error) {
+	result := withContext(r.db, ctx).Model(&models.BalanceAdjustment{}).
+		Where("id = ? AND status = ?", id, models.BalanceAdjustmentStatusPending).
+		Updates(map[string]interface{}{
+			"status":           models.BalanceAdjustmentStatusApproved,
+			"approver_id":      approverID,
+			"decision_note":    note,
+			"decided_at":       at,
+			"journal_entry_id": journalEntryID,
+		})
+	return result.RowsAffected > 0, result.Error
+}
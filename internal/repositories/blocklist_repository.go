@@ -0,0 +1,61 @@
+package repositories
+
+import (
+	"context"
+
+	"github.com/limistah/wallet-service/internal/models"
+	"gorm.io/gorm"
+)
+
+type blocklistRepository struct {
+	db *gorm.DB
+}
+
+// NewBlocklistRepository creates a new blocklist repository
+func NewBlocklistRepository(db *gorm.DB) BlocklistRepository {
+	return &blocklistRepository{db: db}
+}
+
+func (r *blocklistRepository) Create(ctx context.Context, entry *models.BlocklistEntry) error {
+	return withContext(r.db, ctx).Create(entry).Error
+}
+
+func (r *blocklistRepository) GetByID(ctx context.Context, id uint) (*models.BlocklistEntry, error) {
+	var entry models.BlocklistEntry
+	err := withContext(r.db, ctx).First(&entry, id).Error
+	if err != nil {
+		return nil, err
+	}
+	return &entry, nil
+}
+
+func (r *blocklistRepository) GetByValue(ctx context.Context, entryType models.BlocklistEntryType, value string) (*models.BlocklistEntry, error) {
+	var entry models.BlocklistEntry
+	err := withContext(r.db, ctx).Where("type = ? AND value = ?", entryType, value).First(&entry).Error
+	if err != nil {
+		return nil, err
+	}
+	return &entry, nil
+}
+
+// List returns entries newest first; an empty type lists every entry
+func (r *blocklistRepository) List(ctx context.Context, entryType models.BlocklistEntryType, offset, limit int) ([]models.BlocklistEntry, error) {
+	var entries []models.BlocklistEntry
+	query := withContext(r.db, ctx).Model(&models.BlocklistEntry{})
+	if entryType != "" {
+		query = query.Where("type = ?", entryType)
+	}
+	err := query.Order("created_at DESC, id DESC").Offset(offset).Limit(limit).Find(&entries).Error
+	return entries, err
+}
+
+func (r *blocklistRepository) Delete(ctx context.Context, id uint) error {
+	result := withContext(r.db, ctx).Delete(&models.BlocklistEntry{}, id)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
@@ -0,0 +1,50 @@
+package repositories
+
+import (
+	"context"
+
+	"github.com/limistah/wallet-service/internal/models"
+	"gorm.io/gorm"
+)
+
+type budgetRepository struct {
+	db *gorm.DB
+}
+
+// NewBudgetRepository creates a new budget repository
+func NewBudgetRepository(db *gorm.DB) BudgetRepository {
+	return &budgetRepository{db: db}
+}
+
+func (r *budgetRepository) Create(ctx context.Context, budget *models.Budget) error {
+	return withContext(r.db, ctx).Create(budget).Error
+}
+
+func (r *budgetRepository) GetByID(ctx context.Context, id uint) (*models.Budget, error) {
+	var budget models.Budget
+	err := withContext(r.db, ctx).First(&budget, id).Error
+	if err != nil {
+		return nil, err
+	}
+	return &budget, nil
+}
+
+func (r *budgetRepository) ListByUserID(ctx context.Context, userID uint) ([]models.Budget, error) {
+	var budgets []models.Budget
+	err := withContext(r.db, ctx).Where("user_id = ?", userID).Order("category ASC").Find(&budgets).Error
+	return budgets, err
+}
+
+func (r *budgetRepository) ListAfter(ctx context.Context, afterID uint, limit int) ([]models.Budget, error) {
+	var budgets []models.Budget
+	err := withContext(r.db, ctx).Where("id > ?", afterID).Order("id ASC").Limit(limit).Find(&budgets).Error
+	return budgets, err
+}
+
+func (r *budgetRepository) Update(ctx context.Context, budget *models.Budget) error {
+	return withContext(r.db, ctx).Save(budget).Error
+}
+
+func (r *budgetRepository) Delete(ctx context.Context, id uint) error {
+	return withContext(r.db, ctx).Delete(&models.Budget{}, id).Error
+}
@@ -0,0 +1,123 @@
+package repositories
+
+import (
+	"context"
+	"time"
+
+	"github.com/limistah/wallet-service/internal/models"
+	"gorm.io/gorm"
+)
+
+type businessAccountRepository struct {
+	db *gorm.DB
+}
+
+// NewBusinessAccountRepository creates a new business account repository
+func NewBusinessAccountRepository(db *gorm.DB) BusinessAccountRepository {
+	return &businessAccountRepository{db: db}
+}
+
+func (r *businessAccountRepository) Create(ctx context.Context, account *models.BusinessAccount) error {
+	return withContext(r.db, ctx).Create(account).Error
+}
+
+func (r *businessAccountRepository) GetByID(ctx context.Context, id uint) (*models.BusinessAccount, error) {
+	var account models.BusinessAccount
+	err := withContext(r.db, ctx).Preload("Wallet").Preload("Members", func(db *gorm.DB) *gorm.DB {
+		return db.Order("id ASC")
+	}).Preload("Members.User").First(&account, id).Error
+	if err != nil {
+		return nil, err
+	}
+	return &account, nil
+}
+
+func (r *businessAccountRepository) ListByMemberUserID(ctx context.Context, userID uint) ([]models.BusinessAccount, error) {
+	var accounts []models.BusinessAccount
+	err := withContext(r.db, ctx).Preload("Wallet").Preload("Members").
+		Joins("JOIN business_members ON business_members.business_account_id = business_accounts.id").
+		Where("business_members.user_id = ?", userID).
+		Order("business_accounts.id ASC").
+		Find(&accounts).Error
+	return accounts, err
+}
+
+func (r *businessAccountRepository) GetMember(ctx context.Context, accountID, userID uint) (*models.BusinessMember, error) {
+	var member models.BusinessMember
+	err := withContext(r.db, ctx).Where("business_account_id = ? AND user_id = ?", accountID, userID).First(&member).Error
+	if err != nil {
+		return nil, err
+	}
+	return &member, nil
+}
+
+func (r *businessAccountRepository) AddMember(ctx context.Context, member *models.BusinessMember) error {
+	return withContext(r.db, ctx).Create(member).Error
+}
+
+func (r *businessAccountRepository) UpdateMember(ctx context.Context, member *models.BusinessMember) error {
+	return withContext(r.db, ctx).Save(member).Error
+}
+
+func (r *businessAccountRepository) RemoveMember(ctx context.Context, id uint) error {
+	return withContext(r.db, ctx).Delete(&models.BusinessMember{}, id).Error
+}
+
+type paymentApprovalRepository struct {
+	db *gorm.DB
+}
+
+// NewPaymentApprovalRepository creates a new payment approval repository
+func NewPaymentApprovalRepository(db *gorm.DB) PaymentApprovalRepository {
+	return &paymentApprovalRepository{db: db}
+}
+
+func (r *paymentApprovalRepository) Create(ctx context.Context, approval *models.PaymentApproval) error {
+	return withContext(r.db, ctx).Create(approval).Error
+}
+
+func (r *paymentApprovalRepository) GetByID(ctx context.Context, id uint) (*models.PaymentApproval, error) {
+	var approval models.PaymentApproval
+	err := withContext(r.db, ctx).First(&approval, id).Error
+	if err != nil {
+		return nil, err
+	}
+	return &approval, nil
+}
+
+func (r *paymentApprovalRepository) GetByReference(ctx context.Context, reference string) (*models.PaymentApproval, error) {
+	var approval models.PaymentApproval
+	err := withContext(r.db, ctx).Where("reference = ?", reference).First(&approval).Error
+	if err != nil {
+		return nil, err
+	}
+	return &approval, nil
+}
+
+// ListByBusinessAccount returns the approvals of an account newest first; an empty status lists
+// every approval
+func (r *paymentApprovalRepository) ListByBusinessAccount(ctx context.Context, accountID uint, status models.PaymentApprovalStatus, offset, limit int) ([]models.PaymentApproval, error) {
+	var approvals []models.PaymentApproval
+	query := withContext(r.db, ctx).Where("business_account_id = ?", accountID)
+	if status != "" {
+		query = query.Where("status = ?", status)
+	}
+	err := query.Order("created_at DESC, id DESC").Offset(offset).Limit(limit).Find(&approvals).Error
+	return approvals, err
+}
+
+func (r *paymentApprovalRepository) Decide(ctx context.Context, id uint, status models.PaymentApprovalStatus, approverID uint, note string, at time.Time) (bool, error) {
+	result := withContext(r.db, ctx).Model(&models.PaymentApproval{}).
+		Where("id = ? AND status = ?", id, models.PaymentApprovalStatusPending).
+		Updates(map[string]interface{}{
+			"status":        status,
+			"approver_id":   approverID,
+			"decision_note": note,
+			"decided_at":    at,
+		})
+	return result.RowsAffected > 0, result.Error
+}
+
+func (r *paymentApprovalRepository) Update(ctx context.Context, approval *models.PaymentApproval) error {
+	return withContext(r.db, ctx).Save(approval).Error
+}
@@ -0,0 +1,32 @@
+package repositories
+
+import (
+	"context"
+
+	"github.com/limistah/wallet-service/internal/models"
+	"gorm.io/gorm"
+)
+
+type complianceLogRepository struct {
+	db *gorm.DB
+}
+
+// NewComplianceLogRepository creates a new compliance log repository
+func NewComplianceLogRepository(db *gorm.DB) ComplianceLogRepository {
+	return &complianceLogRepository{db: db}
+}
+
+func (r *complianceLogRepository) Create(ctx context.Context, entry *models.ComplianceLog) error {
+	return withContext(r.db, ctx).Create(entry).Error
+}
+
+// List returns log entries newest first; an empty check lists every entry
+func (r *complianceLogRepository) List(ctx context.Context, check models.ComplianceCheck, offset, limit int) ([]models.ComplianceLog, error) {
+	var entries []models.ComplianceLog
+	query := withContext(r.db, ctx).Model(&models.ComplianceLog{})
+	if check != "" {
+		query = query.Where("check_type = ?", check)
+	}
+	err := query.Order("created_at DESC, id DESC").Offset(offset).Limit(limit).Find(&entries).Error
+	return entries, err
+}
@@ -0,0 +1,60 @@
+package repositories
+
+import (
+	"context"
+	"time"
+
+	"github.com/limistah/wallet-service/internal/models"
+	"gorm.io/gorm"
+)
+
+type depositRepository struct {
+	db *gorm.DB
+}
+
+// NewDepositRepository creates a new deposit repository
+func NewDepositRepository(db *gorm.DB) DepositRepository {
+	return &depositRepository{db: db}
+}
+
+func (r *depositRepository) Create(ctx context.Context, deposit *models.Deposit) error {
+	return withContext(r.db, ctx).Create(deposit).Error
+}
+
+func (r *depositRepository) GetByID(ctx context.Context, id uint) (*models.Deposit, error) {
+	var deposit models.Deposit
+	err := withContext(r.db, ctx).First(&deposit, id).Error
+	if err != nil {
+		return nil, err
+	}
+	return &deposit, nil
+}
+
+func (r *depositRepository) GetByExternalReference(ctx context.Context, provider, externalReference string) (*models.Deposit, error) {
+	var deposit models.Deposit
+	err := withContext(r.db, ctx).Where("provider = ? AND external_reference = ?", provider, externalReference).
+		First(&deposit).Error
+	if err != nil {
+		return nil, err
+	}
+	return &deposit, nil
+}
+
+func (r *depositRepository) Update(ctx context.Context, deposit *models.Deposit) error {
+	return withContext(r.db, ctx).Save(deposit).Error
+}
+
+func (r *depositRepository) ListUnbatched(ctx context.Context, from, to time.Time) ([]models.Deposit, error) {
+	var deposits []models.Deposit
+	err := withContext(r.db, ctx).Where("status = ? AND settlement_batch_id IS NULL AND completed_at >= ? AND completed_at < ?", models.DepositStatusCompleted, from, to).
+		Order("id ASC").
+		Find(&deposits).Error
+	return deposits, err
+}
+
+func (r *depositRepository) AssignToBatch(ctx context.Context, ids []uint, batchID uint) (int, error) {
+	result := withContext(r.db, ctx).Model(&models.Deposit{}).
+		Where("id IN ? AND settlement_batch_id IS NULL", ids).
+		Update("settlement_batch_id", batchID)
+	return int(result.RowsAffected), result.Error
+}
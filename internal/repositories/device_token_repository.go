@@ -0,0 +1,62 @@
+package repositories
+
+import (
+	"context"
+	"time"
+
+	"github.com/limistah/wallet-service/internal/models"
+	"gorm.io/gorm"
+)
+
+type deviceTokenRepository struct {
+	db *gorm.DB
+}
+
+// NewDeviceTokenRepository creates a new device token repository
+func NewDeviceTokenRepository(db *gorm.DB) DeviceTokenRepository {
+	return &deviceTokenRepository{db: db}
+}
+
+func (r *deviceTokenRepository) Save(ctx context.Context, device *models.DeviceToken) error {
+	// A token belongs to one device; re-registering moves it to the current user
+	var existing models.DeviceToken
+	err := withContext(r.db, ctx).Where("token = ?", device.Token).First(&existing).Error
+	if err == nil {
+		device.ID = existing.ID
+		device.CreatedAt = existing.CreatedAt
+		return withContext(r.db, ctx).Save(device).Error
+	}
+	if err != gorm.ErrRecordNotFound {
+		return err
+	}
+	return withContext(r.db, ctx).Create(device).Error
+}
+
+func (r *deviceTokenRepository) GetByUserID(ctx context.Context, userID uint) ([]models.DeviceToken, error) {
+	var devices []models.DeviceToken
+	err := withContext(r.db, ctx).Where("user_id = ?", userID).
+		Order("created_at DESC").
+		Find(&devices).Error
+	return devices, err
+}
+
+func (r *deviceTokenRepository) Delete(ctx context.Context, userID, id uint) error {
+	result := withContext(r.db, ctx).Where("user_id = ?", userID).Delete(&models.DeviceToken{}, id)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
+
+func (r *deviceTokenRepository) DeleteByToken(ctx context.Context, token string) error {
+	return withContext(r.db, ctx).Where("token = ?", token).Delete(&models.DeviceToken{}).Error
+}
+
+func (r *deviceTokenRepository) MarkUsed(ctx context.Context, token string) error {
+	return withContext(r.db, ctx).Model(&models.DeviceToken{}).
+		Where("token = ?", token).
+		Update("last_used_at", time.Now()).Error
+}
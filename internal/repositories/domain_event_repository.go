@@ -0,0 +1,42 @@
+package repositories
+
+import (
+	"context"
+
+	"github.com/limistah/wallet-service/internal/models"
+	"gorm.io/gorm"
+)
+
+type domainEventRepository struct {
+	db *gorm.DB
+}
+
+// NewDomainEventRepository creates a new domain event repository
+func NewDomainEventRepository(db *gorm.DB) DomainEventRepository {
+	return &domainEventRepository{db: db}
+}
+
+func (r *domainEventRepository) Create(ctx context.Context, event *models.DomainEvent) error {
+	return withContext(r.db, ctx).Create(event).Error
+}
+
+func (r *domainEventRepository) List(ctx context.Context, filter DomainEventFilter, limit int) ([]models.DomainEvent, error) {
+	query := withContext(r.db, ctx).Model(&models.DomainEvent{})
+
+	if filter.UserID != 0 {
+		query = query.Where("user_id = ?", filter.UserID)
+	}
+	if filter.WalletID != 0 {
+		query = query.Where("wallet_id = ?", filter.WalletID)
+	}
+	if !filter.From.IsZero() {
+		query = query.Where("occurred_at >= ?", filter.From)
+	}
+	if !filter.To.IsZero() {
+		query = query.Where("occurred_at < ?", filter.To)
+	}
+
+	var events []models.DomainEvent
+	err := query.Order("occurred_at ASC, id ASC").Limit(limit).Find(&events).Error
+	return events, err
+}
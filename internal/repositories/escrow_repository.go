@@ -0,0 +1,63 @@
+package repositories
+
+import (
+	"context"
+	"time"
+
+	"github.com/limistah/wallet-service/internal/models"
+	"gorm.io/gorm"
+)
+
+type escrowRepository struct {
+	db *gorm.DB
+}
+
+// NewEscrowRepository creates a new escrow repository
+func NewEscrowRepository(db *gorm.DB) EscrowRepository {
+	return &escrowRepository{db: db}
+}
+
+func (r *escrowRepository) GetByID(ctx context.Context, id uint) (*models.Escrow, error) {
+	var escrow models.Escrow
+	err := withContext(r.db, ctx).Preload("Payer").Preload("Payee").First(&escrow, id).Error
+	if err != nil {
+		return nil, err
+	}
+	return &escrow, nil
+}
+
+func (r *escrowRepository) ListByUser(ctx context.Context, userID uint, status models.EscrowStatus, offset, limit int) ([]models.Escrow, error) {
+	var escrows []models.Escrow
+	query := withContext(r.db, ctx).Preload("Payer").Preload("Payee").Where("payer_id = ? OR payee_id = ?", userID, userID)
+	if status != "" {
+		query = query.Where("status = ?", status)
+	}
+	err := query.Order("created_at DESC, id DESC").Offset(offset).Limit(limit).Find(&escrows).Error
+	return escrows, err
+}
+
+// ListExpired returns held escrows whose expiry has passed, oldest expiry first
+func (r *escrowRepository) ListExpired(ctx context.Context, now time.Time, limit int) ([]models.Escrow, error) {
+	var escrows []models.Escrow
+	err := withContext(r.db, ctx).Where("status = ? AND expires_at <= ?", models.EscrowStatusHeld, now).
+		Order("expires_at ASC, id ASC").
+		Limit(limit).
+		Find(&escrows).Error
+	return escrows, err
+}
+
+func (r *escrowRepository) Create(ctx context.Context, escrow *models.Escrow) error {
+	return withContext(r.db, ctx).Create(escrow).Error
+}
+
+func (r *escrowRepository) Settle(ctx context.Context, id uint, status models.EscrowStatus, at time.Time) (bool, error) {
+	result := withContext(r.db, ctx).Model(&models.Escrow{}).
+		Where("id = ? AND status = ?", id, models.EscrowStatusHeld).
+		Updates(map[string]interface{}{"status": status, "settled_at": at})
+	return result.RowsAffected > 0, result.Error
+}
+
+func (r *escrowRepository) SetSettlementJournalEntry(ctx context.Context, id, journalEntryID uint) error {
+	return withContext(r.db, ctx).Model(&models.Escrow{}).Where("id = ?", id).
+		Update("settlement_journal_entry_id", journalEntryID).Error
+}
@@ -0,0 +1,54 @@
+package repositories
+
+import (
+	"context"
+	"time"
+
+	"github.com/limistah/wallet-service/internal/models"
+	"gorm.io/gorm"
+)
+
+type fraudReviewRepository struct {
+	db *gorm.DB
+}
+
+// NewFraudReviewRepository creates a new fraud review repository
+func NewFraudReviewRepository(db *gorm.DB) FraudReviewRepository {
+	return &fraudReviewRepository{db: db}
+}
+
+func (r *fraudReviewRepository) GetByID(ctx context.Context, id uint) (*models.FraudReview, error) {
+	var review models.FraudReview
+	err := withContext(r.db, ctx).Preload("Transaction").First(&review, id).Error
+	if err != nil {
+		return nil, err
+	}
+	return &review, nil
+}
+
+// List returns reviews oldest first so the queue is worked in order; an empty status lists every review
+func (r *fraudReviewRepository) List(ctx context.Context, status models.FraudReviewStatus, offset, limit int) ([]models.FraudReview, error) {
+	var reviews []models.FraudReview
+	query := withContext(r.db, ctx).Model(&models.FraudReview{})
+	if status != "" {
+		query = query.Where("status = ?", status)
+	}
+	err := query.Order("created_at ASC, id ASC").Offset(offset).Limit(limit).Find(&reviews).Error
+	return reviews, err
+}
+
+func (r *fraudReviewRepository) Create(ctx context.Context, review *models.FraudReview) error {
+	return withContext(r.db, ctx).Create(review).Error
+}
+
+func (r *fraudReviewRepository) Resolve(ctx context.Context, id uint, status models.FraudReviewStatus, reviewerID uint, note string, at time.Time) (bool, error) {
+	result := withContext(r.db, ctx).Model(&models.FraudReview{}).
+		Where("id = ? AND status = ?", id, models.FraudReviewStatusPending).
+		Updates(map[string]interface{}{
+			"status":      status,
+			"reviewer_id": reviewerID,
+			"review_note": note,
+			"reviewed_at": at,
+		})
+	return result.RowsAffected > 0, result.Error
+}
@@ -1,6 +1,7 @@
 package repositories
 
 import (
+	"context"
 	"time"
 
 	"github.com/limistah/wallet-service/internal/models"
@@ -10,69 +11,744 @@ import (
 
 // UserRepository defines the interface for user data operations
 type UserRepository interface {
-	Create(user *models.User) error
-	GetByID(id uint) (*models.User, error)
-	GetByEmail(email string) (*models.User, error)
-	Update(user *models.User) error
-	Delete(id uint) error
-	List(offset, limit int) ([]models.User, error)
+	Create(ctx context.Context, user *models.User) error
+	GetByID(ctx context.Context, id uint) (*models.User, error)
+	GetByEmail(ctx context.Context, email string) (*models.User, error)
+	// GetByEmailInTenant finds a user of the tenant even when the repository is not scoped to it
+	GetByEmailInTenant(ctx context.Context, tenantID uint, email string) (*models.User, error)
+	GetByHandle(ctx context.Context, handle string) (*models.User, error)
+	// SetHandle sets the handle of a user; nil removes it
+	SetHandle(ctx context.Context, id uint, handle *string) error
+	Update(ctx context.Context, user *models.User) error
+	Delete(ctx context.Context, id uint) error
+	List(ctx context.Context, offset, limit int) ([]models.User, error)
+	RevokeTokens(ctx context.Context, id uint, at time.Time) error
+	GetTokensRevokedAt(ctx context.Context, id uint) (*time.Time, error)
+	IncrementFailedLogins(ctx context.Context, id uint) (int, error)
+	SetLoginLock(ctx context.Context, id uint, lockedUntil *time.Time) error
+	UpdateTransactionPIN(ctx context.Context, id uint, hashedPIN string) error
+	IncrementFailedPINAttempts(ctx context.Context, id uint) (int, error)
+	SetPINLock(ctx context.Context, id uint, lockedUntil *time.Time) error
+	// ListDeletionsDue returns users who asked for deletion before the cutoff and still hold personal data
+	ListDeletionsDue(ctx context.Context, before time.Time, limit int) ([]models.User, error)
+}
+
+// WalletFilter holds optional criteria for listing wallets
+type WalletFilter struct {
+	Status     models.WalletStatus
+	Currency   string
+	MinBalance *decimal.Decimal
+	MaxBalance *decimal.Decimal
+	UserEmail  string
 }
 
 // WalletRepository defines the interface for wallet data operations
 type WalletRepository interface {
-	Create(wallet *models.Wallet) error
-	GetByID(id uint) (*models.Wallet, error)
-	GetByUserID(userID uint) (*models.Wallet, error)
-	Update(wallet *models.Wallet) error
-	UpdateBalance(walletID uint, newBalance decimal.Decimal, version uint) error
-	List(offset, limit int) ([]models.Wallet, error)
-	GetAllForReconciliation() ([]models.Wallet, error)
+	Create(ctx context.Context, wallet *models.Wallet) error
+	GetByID(ctx context.Context, id uint) (*models.Wallet, error)
+	// GetByUserID returns the default live wallet of a user, or their first one when none is the default
+	GetByUserID(ctx context.Context, userID uint) (*models.Wallet, error)
+	GetSandboxByUserID(ctx context.Context, userID uint) (*models.Wallet, error)
+	Update(ctx context.Context, wallet *models.Wallet) error
+	UpdateBalance(ctx context.Context, walletID uint, newBalance decimal.Decimal, version uint) error
+	// AdjustBalance adds amount to the current balance of a wallet and returns the balance before the
+	// change; applied is false when a concurrent change got in between
+	AdjustBalance(ctx context.Context, walletID uint, amount decimal.Decimal) (balanceBefore decimal.Decimal, applied bool, err error)
+	List(ctx context.Context, offset, limit int) ([]models.Wallet, error)
+	ListWithFilter(ctx context.Context, filter WalletFilter, offset, limit int) ([]models.Wallet, int64, error)
+	GetAllForReconciliation(ctx context.Context) ([]models.Wallet, error)
+	// ListForReconciliation returns at most limit wallets of the scope with an ID above afterID in ID
+	// order, so the wallets of a scope can be walked in batches
+	ListForReconciliation(ctx context.Context, scope models.ReconciliationScope, afterID uint, limit int) ([]models.Wallet, error)
+	CountForReconciliation(ctx context.Context, scope models.ReconciliationScope) (int64, error)
+	SetTier(ctx context.Context, walletID uint, tierID *uint) error
+	// ListByUserID returns the live or sandbox wallets of a user in ID order, only those labelled
+	// nickname when one is given; nicknames match regardless of case
+	ListByUserID(ctx context.Context, userID uint, sandbox bool, nickname string) ([]models.Wallet, error)
+	SetNickname(ctx context.Context, walletID uint, nickname string) error
+	// SetDefault makes a live wallet of a user their default one, in place of any other
+	SetDefault(ctx context.Context, userID, walletID uint) error
 }
 
 // TransactionRepository defines the interface for transaction data operations
 type TransactionRepository interface {
-	Create(transaction *models.Transaction) error
-	GetByID(id uint) (*models.Transaction, error)
-	GetByReference(reference string) (*models.Transaction, error)
-	GetByWalletID(walletID uint, offset, limit int) ([]models.Transaction, error)
-	GetByWalletIDWithCursor(walletID uint, cursor *time.Time, cursorID *uint, limit int) ([]models.Transaction, error)
-	Update(transaction *models.Transaction) error
-	CalculateBalance(walletID uint) (decimal.Decimal, error)
-	List(offset, limit int) ([]models.Transaction, error)
+	Create(ctx context.Context, transaction *models.Transaction) error
+	GetByID(ctx context.Context, id uint) (*models.Transaction, error)
+	GetByReference(ctx context.Context, reference string) (*models.Transaction, error)
+	GetByWalletID(ctx context.Context, walletID uint, offset, limit int) ([]models.Transaction, error)
+	GetByWalletIDWithCursor(ctx context.Context, walletID uint, cursor *time.Time, cursorID *uint, limit int) ([]models.Transaction, error)
+	Update(ctx context.Context, transaction *models.Transaction) error
+	// LinkRelated points a transaction at its counterpart leg
+	LinkRelated(ctx context.Context, id, relatedID uint) error
+	CalculateBalance(ctx context.Context, walletID uint) (decimal.Decimal, error)
+	CalculateBalanceAfter(ctx context.Context, walletID, afterID uint) (decimal.Decimal, error)
+	CalculateBalanceBetween(ctx context.Context, walletID, afterID, throughID uint) (decimal.Decimal, error)
+	SumDebitsSince(ctx context.Context, walletID uint, since time.Time) (decimal.Decimal, error)
+	GetDebitsSince(ctx context.Context, walletID uint, since time.Time) ([]models.Transaction, error)
+	SumCategoryDebitsSince(ctx context.Context, walletID uint, category string, since time.Time) (decimal.Decimal, error)
+	SetCategory(ctx context.Context, id uint, category string) error
+	HasTransferBetween(ctx context.Context, fromWalletID, toWalletID uint) (bool, error)
+	GetPendingDebitsBefore(ctx context.Context, before time.Time, limit int) ([]models.Transaction, error)
+	List(ctx context.Context, offset, limit int) ([]models.Transaction, error)
+	FindCompletedByWalletID(ctx context.Context, walletID uint, batchSize int, fn func([]models.Transaction) error) error
+	GetByRelatedTransactionID(ctx context.Context, relatedID uint) ([]models.Transaction, error)
+	GetSettledThrough(ctx context.Context, walletID uint, before time.Time) (uint, error)
+	FindArchivable(ctx context.Context, before time.Time, afterID uint, limit int) ([]models.Transaction, error)
+	FindLinked(ctx context.Context, transactions []models.Transaction) ([]models.Transaction, error)
+	GetCaseReferencedIDs(ctx context.Context, ids []uint) ([]uint, error)
+	GetByWalletIDBetween(ctx context.Context, walletID uint, from, to time.Time) ([]models.Transaction, error)
+	// GetLegs returns a transaction with its counter legs and fee legs, in ID order
+	GetLegs(ctx context.Context, id uint) ([]models.Transaction, error)
+	// UpdateStatus moves a transaction still in status from to status to; it reports false when the
+	// transaction changed status first
+	UpdateStatus(ctx context.Context, id uint, from, to models.TransactionStatus) (bool, error)
+	// UpdateLegsStatus moves a transaction, its counter legs and fee legs to the same status
+	UpdateLegsStatus(ctx context.Context, id uint, status models.TransactionStatus) error
+	// SetBalances rewrites the balances a transaction recorded, for legs applied after they were created
+	SetBalances(ctx context.Context, id uint, balanceBefore, balanceAfter decimal.Decimal) error
+	// DeleteArchived removes transactions copied into the archive, unlinking them from each other first
+	DeleteArchived(ctx context.Context, ids []uint) error
+}
+
+// ArchivedTransactionRepository defines the interface for reading archived transactions
+type ArchivedTransactionRepository interface {
+	GetByID(ctx context.Context, id uint) (*models.ArchivedTransaction, error)
+	GetByWalletIDWithCursor(ctx context.Context, walletID uint, cursor *time.Time, cursorID *uint, limit int) ([]models.ArchivedTransaction, error)
+	GetByWalletIDBetween(ctx context.Context, walletID uint, from, to time.Time) ([]models.ArchivedTransaction, error)
+	GetByReference(ctx context.Context, reference string) (*models.ArchivedTransaction, error)
+	GetByRelatedTransactionID(ctx context.Context, relatedID uint) ([]models.ArchivedTransaction, error)
+	Create(ctx context.Context, transactions []models.ArchivedTransaction) error
+}
+
+// BalanceCheckpointRepository defines the interface for wallet balance checkpoints
+type BalanceCheckpointRepository interface {
+	Create(ctx context.Context, checkpoint *models.BalanceCheckpoint) error
+	GetByWalletID(ctx context.Context, walletID uint) (*models.BalanceCheckpoint, error)
+	Advance(ctx context.Context, checkpoint *models.BalanceCheckpoint, previousThroughID uint) (bool, error)
+	// RecordArchived saves how many transactions of the wallet were archived and the newest of them
+	RecordArchived(ctx context.Context, checkpoint *models.BalanceCheckpoint) error
 }
 
 // TransactionTypeRepository defines the interface for transaction type operations
 type TransactionTypeRepository interface {
-	GetByName(name string) (*models.TransactionType, error)
-	List() ([]models.TransactionType, error)
-	Create(transactionType *models.TransactionType) error
+	GetByName(ctx context.Context, name models.TransactionType) (*models.TransactionTypeDefinition, error)
+	List(ctx context.Context) ([]models.TransactionTypeDefinition, error)
+	Create(ctx context.Context, definition *models.TransactionTypeDefinition) error
+}
+
+// TransactionPurposeRepository defines the interface for transaction purpose operations
+type TransactionPurposeRepository interface {
+	GetByName(ctx context.Context, name models.TransactionPurpose) (*models.TransactionPurposeDefinition, error)
+	List(ctx context.Context) ([]models.TransactionPurposeDefinition, error)
+	Create(ctx context.Context, definition *models.TransactionPurposeDefinition) error
+	Update(ctx context.Context, definition *models.TransactionPurposeDefinition) error
+	Delete(ctx context.Context, name models.TransactionPurpose) error
+	// InUse checks if live or archived transactions were recorded with the purpose
+	InUse(ctx context.Context, name models.TransactionPurpose) (bool, error)
 }
 
 // ReconciliationRepository defines the interface for reconciliation operations
 type ReconciliationRepository interface {
-	Create(report *models.ReconciliationReport) error
-	GetByWalletID(walletID uint) ([]models.ReconciliationReport, error)
-	List(offset, limit int) ([]models.ReconciliationReport, error)
-	GetMismatches(offset, limit int) ([]models.ReconciliationReport, error)
+	Create(ctx context.Context, report *models.ReconciliationReport) error
+	GetByID(ctx context.Context, id uint) (*models.ReconciliationReport, error)
+	Update(ctx context.Context, report *models.ReconciliationReport) error
+	GetByWalletID(ctx context.Context, walletID uint) ([]models.ReconciliationReport, error)
+	List(ctx context.Context, offset, limit int) ([]models.ReconciliationReport, error)
+	GetMismatches(ctx context.Context, offset, limit int) ([]models.ReconciliationReport, error)
+	ExportReports(ctx context.Context, filter ReconciliationReportFilter, batchSize int, fn func([]models.ReconciliationReport) error) error
+	// OpenMismatches totals, per currency, the wallets whose latest report is not a match and the
+	// absolute differences of those reports
+	OpenMismatches(ctx context.Context) ([]OpenMismatchTotal, error)
+}
+
+// OpenMismatchTotal is the drift of the wallets of a currency whose latest reconciliation failed
+type OpenMismatchTotal struct {
+	Currency string
+	Wallets  int64
+	Amount   decimal.Decimal
+}
+
+// ReconciliationReportFilter holds optional criteria for exporting reconciliation reports; From is
+// inclusive and To exclusive
+type ReconciliationReportFilter struct {
+	Status models.ReconciliationStatus
+	From   *time.Time
+	To     *time.Time
+}
+
+// ReconciliationSummaryRepository defines the interface for daily reconciliation summary operations
+type ReconciliationSummaryRepository interface {
+	Create(ctx context.Context, summary *models.ReconciliationSummary) error
+	GetByDate(ctx context.Context, date time.Time) (*models.ReconciliationSummary, error)
+	List(ctx context.Context, offset, limit int) ([]models.ReconciliationSummary, error)
+	MarkDelivered(ctx context.Context, id uint, at time.Time) error
+}
+
+// ReconciliationRunRepository defines the interface for bulk reconciliation runs
+type ReconciliationRunRepository interface {
+	Create(ctx context.Context, run *models.ReconciliationRun) error
+	GetByID(ctx context.Context, id uint) (*models.ReconciliationRun, error)
+	Update(ctx context.Context, run *models.ReconciliationRun) error
+}
+
+// AuditLogRepository defines the interface for audit log operations
+type AuditLogRepository interface {
+	Create(ctx context.Context, entry *models.AuditLog) error
+	List(ctx context.Context, offset, limit int) ([]models.AuditLog, error)
+	GetByActorID(ctx context.Context, actorID uint, offset, limit int) ([]models.AuditLog, error)
+}
+
+// NotificationPreferenceRepository defines the interface for notification preference operations
+type NotificationPreferenceRepository interface {
+	GetByUserID(ctx context.Context, userID uint) (*models.NotificationPreference, error)
+	Save(ctx context.Context, preference *models.NotificationPreference) error
+}
+
+// PhoneVerificationRepository defines the interface for phone verification operations
+type PhoneVerificationRepository interface {
+	Create(ctx context.Context, verification *models.PhoneVerification) error
+	GetLatestByUserID(ctx context.Context, userID uint) (*models.PhoneVerification, error)
+	Update(ctx context.Context, verification *models.PhoneVerification) error
+}
+
+// DeviceTokenRepository defines the interface for push device token operations
+type DeviceTokenRepository interface {
+	Save(ctx context.Context, device *models.DeviceToken) error
+	GetByUserID(ctx context.Context, userID uint) ([]models.DeviceToken, error)
+	Delete(ctx context.Context, userID, id uint) error
+	DeleteByToken(ctx context.Context, token string) error
+	MarkUsed(ctx context.Context, token string) error
+}
+
+// DepositRepository defines the interface for provider funded deposit operations
+type DepositRepository interface {
+	Create(ctx context.Context, deposit *models.Deposit) error
+	GetByID(ctx context.Context, id uint) (*models.Deposit, error)
+	GetByExternalReference(ctx context.Context, provider, externalReference string) (*models.Deposit, error)
+	Update(ctx context.Context, deposit *models.Deposit) error
+	// ListUnbatched returns the deposits completed in [from, to) that no settlement batch holds yet
+	ListUnbatched(ctx context.Context, from, to time.Time) ([]models.Deposit, error)
+	// AssignToBatch puts the deposits no settlement batch holds yet among ids in the batch and returns
+	// how many it assigned
+	AssignToBatch(ctx context.Context, ids []uint, batchID uint) (int, error)
+}
+
+// PayoutRepository defines the interface for bank payout operations
+type PayoutRepository interface {
+	Create(ctx context.Context, payout *models.Payout) error
+	GetByReference(ctx context.Context, reference string) (*models.Payout, error)
+	UpdateProviderReference(ctx context.Context, id uint, providerReference string) error
+	GetByProviderReference(ctx context.Context, provider, providerReference string) (*models.Payout, error)
+	// ListUnbatched returns the payouts completed in [from, to) that no settlement batch holds yet
+	ListUnbatched(ctx context.Context, from, to time.Time) ([]models.Payout, error)
+	GetByTransactionID(ctx context.Context, transactionID uint) (*models.Payout, error)
+	// Settle records the final status of a pending payout; it reports false when the payout was
+	// settled first
+	Settle(ctx context.Context, id uint, status models.PayoutStatus, failureReason string, at time.Time) (bool, error)
+	// AssignToBatch puts the payouts no settlement batch holds yet among ids in the batch and returns
+	// how many it assigned
+	AssignToBatch(ctx context.Context, ids []uint, batchID uint) (int, error)
+}
+
+// MoneyRequestRepository defines the interface for user to user money request operations
+type MoneyRequestRepository interface {
+	Create(ctx context.Context, request *models.MoneyRequest) error
+	GetByID(ctx context.Context, id uint) (*models.MoneyRequest, error)
+	UpdateStatus(ctx context.Context, id uint, from, to models.MoneyRequestStatus) (bool, error)
+	SetTransaction(ctx context.Context, id, transactionID uint) error
+	ListByUser(ctx context.Context, userID uint, direction models.MoneyRequestDirection, status models.MoneyRequestStatus, offset, limit int) ([]models.MoneyRequest, error)
+}
+
+// PaymentLinkRepository defines the interface for shareable payment link operations
+type PaymentLinkRepository interface {
+	Create(ctx context.Context, link *models.PaymentLink) error
+	GetByID(ctx context.Context, id uint) (*models.PaymentLink, error)
+	GetByToken(ctx context.Context, token string) (*models.PaymentLink, error)
+	ListByUserID(ctx context.Context, userID uint, offset, limit int) ([]models.PaymentLink, error)
+	IncrementViews(ctx context.Context, id uint) error
+	UpdateStatus(ctx context.Context, id uint, from, to models.PaymentLinkStatus) (bool, error)
+	RecordPayment(ctx context.Context, id uint, amount decimal.Decimal) error
+}
+
+// StandingOrderRepository defines the interface for recurring transfer operations
+type StandingOrderRepository interface {
+	Create(ctx context.Context, order *models.StandingOrder) error
+	GetByID(ctx context.Context, id uint) (*models.StandingOrder, error)
+	ListByUserID(ctx context.Context, userID uint) ([]models.StandingOrder, error)
+	GetDue(ctx context.Context, now time.Time, limit int) ([]models.StandingOrder, error)
+	Advance(ctx context.Context, id uint, from, to time.Time, status models.StandingOrderStatus) (bool, error)
+	UpdateStatus(ctx context.Context, id uint, status models.StandingOrderStatus) error
+	CreateRun(ctx context.Context, run *models.StandingOrderRun) error
+	GetDueRuns(ctx context.Context, now time.Time, limit int) ([]models.StandingOrderRun, error)
+	UpdateRun(ctx context.Context, run *models.StandingOrderRun) error
+	ListRuns(ctx context.Context, orderID uint, offset, limit int) ([]models.StandingOrderRun, error)
+}
+
+// WalletTierRepository defines the interface for wallet tier data operations
+type WalletTierRepository interface {
+	Create(ctx context.Context, tier *models.WalletTier) error
+	GetByID(ctx context.Context, id uint) (*models.WalletTier, error)
+	GetDefault(ctx context.Context) (*models.WalletTier, error)
+	List(ctx context.Context) ([]models.WalletTier, error)
+	Update(ctx context.Context, tier *models.WalletTier) error
+}
+
+// FraudReviewRepository defines the interface for the fraud review queue
+type FraudReviewRepository interface {
+	Create(ctx context.Context, review *models.FraudReview) error
+	GetByID(ctx context.Context, id uint) (*models.FraudReview, error)
+	List(ctx context.Context, status models.FraudReviewStatus, offset, limit int) ([]models.FraudReview, error)
+	// Resolve records the decision on a pending review; it reports false when another reviewer
+	// decided first
+	Resolve(ctx context.Context, id uint, status models.FraudReviewStatus, reviewerID uint, note string, at time.Time) (bool, error)
+}
+
+// BlocklistRepository defines the interface for blocklist data operations
+type BlocklistRepository interface {
+	Create(ctx context.Context, entry *models.BlocklistEntry) error
+	GetByID(ctx context.Context, id uint) (*models.BlocklistEntry, error)
+	GetByValue(ctx context.Context, entryType models.BlocklistEntryType, value string) (*models.BlocklistEntry, error)
+	List(ctx context.Context, entryType models.BlocklistEntryType, offset, limit int) ([]models.BlocklistEntry, error)
+	Delete(ctx context.Context, id uint) error
+}
+
+// ComplianceLogRepository defines the interface for compliance log operations
+type ComplianceLogRepository interface {
+	Create(ctx context.Context, entry *models.ComplianceLog) error
+	List(ctx context.Context, check models.ComplianceCheck, offset, limit int) ([]models.ComplianceLog, error)
+}
+
+// AMLCaseRepository defines the interface for the AML review queue
+type AMLCaseRepository interface {
+	Create(ctx context.Context, amlCase *models.AMLCase) error
+	GetByID(ctx context.Context, id uint) (*models.AMLCase, error)
+	List(ctx context.Context, status models.AMLCaseStatus, offset, limit int) ([]models.AMLCase, error)
+	// Resolve records the decision on a pending case; it reports false when another reviewer decided
+	// first
+	Resolve(ctx context.Context, id uint, status models.AMLCaseStatus, reviewerID uint, note string, at time.Time) (bool, error)
+	// ExpireByTransactionID expires the pending case of a transaction, if it has one
+	ExpireByTransactionID(ctx context.Context, transactionID uint, at time.Time) error
+}
+
+// JobRepository defines the interface for the durable background job queue
+type JobRepository interface {
+	Create(ctx context.Context, job *models.Job) error
+	GetByID(ctx context.Context, id uint) (*models.Job, error)
+	List(ctx context.Context, status models.JobStatus, offset, limit int) ([]models.Job, error)
+	ClaimDue(ctx context.Context, now time.Time, lease time.Duration, limit int) ([]models.Job, error)
+	Complete(ctx context.Context, id uint) error
+	Retry(ctx context.Context, id uint, runAt time.Time, lastError string) error
+	Bury(ctx context.Context, id uint, lastError string) error
+	Requeue(ctx context.Context, id uint, runAt time.Time) error
+}
+
+// IPAllowlistRepository defines the interface for admin IP allowlist data operations
+type IPAllowlistRepository interface {
+	Create(ctx context.Context, entry *models.IPAllowlistEntry) error
+	GetByCIDR(ctx context.Context, cidr string) (*models.IPAllowlistEntry, error)
+	List(ctx context.Context) ([]models.IPAllowlistEntry, error)
+	Delete(ctx context.Context, id uint) error
+}
+
+// RevokedTokenRepository defines the interface for revoked JWT data operations
+type RevokedTokenRepository interface {
+	Create(ctx context.Context, token *models.RevokedToken) error
+	Exists(ctx context.Context, tokenID string) (bool, error)
+	DeleteExpired(ctx context.Context, before time.Time) (int64, error)
+}
+
+// UserIdentityRepository defines the interface for external identity provider link data operations
+type UserIdentityRepository interface {
+	Create(ctx context.Context, identity *models.UserIdentity) error
+	GetByProviderSubject(ctx context.Context, provider, subject string) (*models.UserIdentity, error)
+	DeleteByUser(ctx context.Context, userID uint) error
+}
+
+// LoginAttemptRepository defines the interface for login attempt data operations
+type LoginAttemptRepository interface {
+	Create(ctx context.Context, attempt *models.LoginAttempt) error
+	CountFailuresByIP(ctx context.Context, ipAddress string, since time.Time) (int64, error)
+	ListByUser(ctx context.Context, userID uint, limit int) ([]models.LoginAttempt, error)
+	HasSucceeded(ctx context.Context, userID uint) (bool, error)
+	HasSucceededWithUserAgent(ctx context.Context, userID uint, userAgent string) (bool, error)
+	DeleteByUser(ctx context.Context, userID uint) error
+}
+
+// SessionRepository defines the interface for signed in device data operations
+type SessionRepository interface {
+	Create(ctx context.Context, session *models.Session) error
+	GetByID(ctx context.Context, id uint) (*models.Session, error)
+	GetByTokenID(ctx context.Context, tokenID string) (*models.Session, error)
+	Update(ctx context.Context, session *models.Session) error
+	ListActive(ctx context.Context, userID uint, now time.Time) ([]models.Session, error)
+	RevokeByTokenID(ctx context.Context, tokenID string, at time.Time) error
+	DeleteExpired(ctx context.Context, before time.Time) (int64, error)
+}
+
+// PasswordHistoryRepository defines the interface for replaced password data operations
+type PasswordHistoryRepository interface {
+	Create(ctx context.Context, entry *models.PasswordHistory) error
+	ListRecent(ctx context.Context, userID uint, limit int) ([]models.PasswordHistory, error)
+	Prune(ctx context.Context, userID uint, keep int) error
+}
+
+// TransferChallengeRepository defines the interface for the step-up challenges of large transfers
+type TransferChallengeRepository interface {
+	Create(ctx context.Context, challenge *models.TransferChallenge) error
+	GetByID(ctx context.Context, id uint) (*models.TransferChallenge, error)
+	IncrementAttempts(ctx context.Context, id uint) error
+	MarkConfirmed(ctx context.Context, id uint, at time.Time) (bool, error)
+	DeleteExpired(ctx context.Context, before time.Time) error
+}
+
+// TransferQuoteRepository defines the interface for transfer quotes. A quote is used inside the
+// database transaction of the transfer made with it
+type TransferQuoteRepository interface {
+	Create(ctx context.Context, quote *models.TransferQuote) error
+	GetByID(ctx context.Context, id uint) (*models.TransferQuote, error)
+	// Use marks a quote used; used is false when it expired or a concurrent transfer used it first
+	Use(ctx context.Context, id uint, now time.Time) (used bool, err error)
+	DeleteExpired(ctx context.Context, before time.Time) error
+}
+
+// LedgerAccountRepository defines the interface for chart of accounts operations
+type LedgerAccountRepository interface {
+	Create(ctx context.Context, account *models.LedgerAccount) error
+	GetByCode(ctx context.Context, code models.LedgerAccountCode, sandbox bool) (*models.LedgerAccount, error)
+	List(ctx context.Context, sandbox bool) ([]models.LedgerAccount, error)
+}
+
+// JournalEntryRepository defines the interface for journal entry operations; entries are written with
+// their legs inside the posting transaction
+type JournalEntryRepository interface {
+	Create(ctx context.Context, entry *models.JournalEntry) error
+	GetByID(ctx context.Context, id uint) (*models.JournalEntry, error)
+	GetByReference(ctx context.Context, reference string) (*models.JournalEntry, error)
+}
+
+// SuspenseItemRepository defines the interface for the exception queue of unmatched credits; items are
+// created and resolved together with their journal entries
+type SuspenseItemRepository interface {
+	Create(ctx context.Context, item *models.SuspenseItem) error
+	GetByID(ctx context.Context, id uint) (*models.SuspenseItem, error)
+	GetByExternalReference(ctx context.Context, provider, externalReference string) (*models.SuspenseItem, error)
+	List(ctx context.Context, status models.SuspenseItemStatus, offset, limit int) ([]models.SuspenseItem, error)
+	Update(ctx context.Context, item *models.SuspenseItem) error
+	// Resolve saves the resolution of an item still open or under investigation; it reports false
+	// when another admin resolved it first
+	Resolve(ctx context.Context, item *models.SuspenseItem) (bool, error)
+}
+
+// BalanceAdjustmentRepository defines the interface for manual wallet adjustments awaiting or past
+// their approval; approved adjustments are updated together with their journal entries
+type BalanceAdjustmentRepository interface {
+	Create(ctx context.Context, adjustment *models.BalanceAdjustment) error
+	GetByID(ctx context.Context, id uint) (*models.BalanceAdjustment, error)
+	List(ctx context.Context, status models.BalanceAdjustmentStatus, offset, limit int) ([]models.BalanceAdjustment, error)
+	// Reject records the rejection of a pending adjustment; it reports false when another admin
+	// decided first
+	Reject(ctx context.Context, id, approverID uint, note string, at time.Time) (bool, error)
+	// Approve records the approval of a pending adjustment posted as the journal entry; it reports
+	// false when another admin decided first
+	Approve(ctx context.Context, id, approverID uint, note string, journalEntryID uint, at time.Time) (bool, error)
+}
+
+// SettlementBatchFilter narrows the settlement batches listed; zero fields do not filter
+type SettlementBatchFilter struct {
+	Provider string
+	Status   models.SettlementBatchStatus
+	From     *time.Time
+	To       *time.Time
+}
+
+// SettlementBatchRepository defines the interface for end-of-day provider settlement batches; batches
+// are saved together with the deposits and payouts they take in
+type SettlementBatchRepository interface {
+	GetByID(ctx context.Context, id uint) (*models.SettlementBatch, error)
+	GetByKey(ctx context.Context, provider, currency string, date time.Time) (*models.SettlementBatch, error)
+	List(ctx context.Context, filter SettlementBatchFilter, offset, limit int) ([]models.SettlementBatch, error)
+	// Update saves the totals of a batch, creating it when it has no ID yet; the deposits and payouts
+	// it holds are assigned through their own repositories
+	Update(ctx context.Context, batch *models.SettlementBatch) error
+}
+
+// ProviderStatementRepository defines the interface for imported provider settlement files
+type ProviderStatementRepository interface {
+	Create(ctx context.Context, statement *models.ProviderStatement) error
+	GetByID(ctx context.Context, id uint) (*models.ProviderStatement, error)
+	GetByChecksum(ctx context.Context, provider, checksum string) (*models.ProviderStatement, error)
+	List(ctx context.Context, provider string, offset, limit int) ([]models.ProviderStatement, error)
+}
+
+// StatementLineFilter narrows the statement lines listed; zero fields do not filter
+type StatementLineFilter struct {
+	Provider    string
+	Status      models.StatementLineStatus
+	Reason      models.StatementExceptionReason
+	StatementID uint
+}
+
+// StatementLineRepository defines the interface for the lines of provider statements
+type StatementLineRepository interface {
+	GetByID(ctx context.Context, id uint) (*models.StatementLine, error)
+	List(ctx context.Context, filter StatementLineFilter, offset, limit int) ([]models.StatementLine, error)
+	// MatchedReferences returns which of the references an earlier statement of the provider matched
+	MatchedReferences(ctx context.Context, provider string, references []string) ([]string, error)
+	Update(ctx context.Context, line *models.StatementLine) error
+}
+
+// AccountStatementRepository defines the interface for account statements requested by users
+type AccountStatementRepository interface {
+	Create(ctx context.Context, statement *models.AccountStatement) error
+	GetByID(ctx context.Context, id uint) (*models.AccountStatement, error)
+	Update(ctx context.Context, statement *models.AccountStatement) error
+	ListByUserID(ctx context.Context, userID uint, offset, limit int) ([]models.AccountStatement, error)
+}
+
+// TransactionStatusChangeRepository defines the interface for the status history of transactions.
+// Changes are written in the unit of work that updates the status
+type TransactionStatusChangeRepository interface {
+	ListByTransactionID(ctx context.Context, transactionID uint) ([]models.TransactionStatusChange, error)
+	Create(ctx context.Context, changes []models.TransactionStatusChange) error
+}
+
+// BudgetRepository defines the interface for monthly spending budgets
+type BudgetRepository interface {
+	Create(ctx context.Context, budget *models.Budget) error
+	GetByID(ctx context.Context, id uint) (*models.Budget, error)
+	ListByUserID(ctx context.Context, userID uint) ([]models.Budget, error)
+	// ListAfter pages through every budget by ID for the evaluator
+	ListAfter(ctx context.Context, afterID uint, limit int) ([]models.Budget, error)
+	Update(ctx context.Context, budget *models.Budget) error
+	Delete(ctx context.Context, id uint) error
+}
+
+// TenantRepository defines the interface for tenant data operations
+type TenantRepository interface {
+	Create(ctx context.Context, tenant *models.Tenant) error
+	GetByID(ctx context.Context, id uint) (*models.Tenant, error)
+	GetBySlug(ctx context.Context, slug string) (*models.Tenant, error)
+	List(ctx context.Context) ([]models.Tenant, error)
+}
+
+// BusinessAccountRepository defines the interface for business accounts and their members
+type BusinessAccountRepository interface {
+	Create(ctx context.Context, account *models.BusinessAccount) error
+	// GetByID loads the account with its wallet and members
+	GetByID(ctx context.Context, id uint) (*models.BusinessAccount, error)
+	ListByMemberUserID(ctx context.Context, userID uint) ([]models.BusinessAccount, error)
+	GetMember(ctx context.Context, accountID, userID uint) (*models.BusinessMember, error)
+	AddMember(ctx context.Context, member *models.BusinessMember) error
+	UpdateMember(ctx context.Context, member *models.BusinessMember) error
+	RemoveMember(ctx context.Context, id uint) error
+}
+
+// PaymentApprovalRepository defines the interface for payments of business wallets
+type PaymentApprovalRepository interface {
+	Create(ctx context.Context, approval *models.PaymentApproval) error
+	GetByID(ctx context.Context, id uint) (*models.PaymentApproval, error)
+	GetByReference(ctx context.Context, reference string) (*models.PaymentApproval, error)
+	ListByBusinessAccount(ctx context.Context, accountID uint, status models.PaymentApprovalStatus, offset, limit int) ([]models.PaymentApproval, error)
+	// Decide records the decision on a pending approval; it reports false when another approver
+	// decided first
+	Decide(ctx context.Context, id uint, status models.PaymentApprovalStatus, approverID uint, note string, at time.Time) (bool, error)
+	Update(ctx context.Context, approval *models.PaymentApproval) error
+}
+
+// EscrowRepository defines the interface for escrow data operations. Escrows are created and settled
+// with the journal entries moving their funds, in the use case's unit of work
+type EscrowRepository interface {
+	Create(ctx context.Context, escrow *models.Escrow) error
+	GetByID(ctx context.Context, id uint) (*models.Escrow, error)
+	ListByUser(ctx context.Context, userID uint, status models.EscrowStatus, offset, limit int) ([]models.Escrow, error)
+	ListExpired(ctx context.Context, now time.Time, limit int) ([]models.Escrow, error)
+	// Settle moves a held escrow to its final status; it reports false when the escrow was settled first
+	Settle(ctx context.Context, id uint, status models.EscrowStatus, at time.Time) (bool, error)
+	SetSettlementJournalEntry(ctx context.Context, id, journalEntryID uint) error
+}
+
+// SubscriptionRepository defines the interface for subscription plan, subscription and charge data
+// operations
+type SubscriptionRepository interface {
+	CreatePlan(ctx context.Context, plan *models.SubscriptionPlan) error
+	GetPlan(ctx context.Context, id uint) (*models.SubscriptionPlan, error)
+	ListActivePlans(ctx context.Context, offset, limit int) ([]models.SubscriptionPlan, error)
+	ListPlansByMerchant(ctx context.Context, merchantID uint) ([]models.SubscriptionPlan, error)
+	ArchivePlan(ctx context.Context, id uint) error
+	Create(ctx context.Context, subscription *models.Subscription) error
+	GetByID(ctx context.Context, id uint) (*models.Subscription, error)
+	// GetOpenByUserAndPlan returns the subscription of the user to the plan that was not cancelled
+	GetOpenByUserAndPlan(ctx context.Context, userID, planID uint) (*models.Subscription, error)
+	ListByUser(ctx context.Context, userID uint) ([]models.Subscription, error)
+	ListByPlan(ctx context.Context, planID uint, offset, limit int) ([]models.Subscription, error)
+	ListDue(ctx context.Context, now time.Time, limit int) ([]models.Subscription, error)
+	// Update saves the billing state of a subscription still in status from; it reports false when
+	// the subscription changed status first
+	Update(ctx context.Context, subscription *models.Subscription, from models.SubscriptionStatus) (bool, error)
+	CreateCharge(ctx context.Context, charge *models.SubscriptionCharge) error
+	ListCharges(ctx context.Context, subscriptionID uint, offset, limit int) ([]models.SubscriptionCharge, error)
+}
+
+// WebhookDeliveryFilter narrows the webhook deliveries listed; zero fields do not filter
+type WebhookDeliveryFilter struct {
+	Status         models.WebhookDeliveryStatus
+	EventType      string
+	SubscriptionID uint
+}
+
+// WebhookRepository defines the interface for webhook subscription, delivery and delivery attempt data
+// operations
+type WebhookRepository interface {
+	CreateSubscription(ctx context.Context, subscription *models.WebhookSubscription) error
+	GetSubscription(ctx context.Context, id uint) (*models.WebhookSubscription, error)
+	ListSubscriptionsByUser(ctx context.Context, userID uint) ([]models.WebhookSubscription, error)
+	ListActiveSubscriptionsByUser(ctx context.Context, userID uint) ([]models.WebhookSubscription, error)
+	DisableSubscription(ctx context.Context, id uint) error
+	CreateDelivery(ctx context.Context, delivery *models.WebhookDelivery) error
+	GetDelivery(ctx context.Context, id uint) (*models.WebhookDelivery, error)
+	ListDeliveries(ctx context.Context, subscriptionID uint, offset, limit int) ([]models.WebhookDelivery, error)
+	// SearchDeliveries returns the deliveries matching the filter, most recently updated first
+	SearchDeliveries(ctx context.Context, filter WebhookDeliveryFilter, offset, limit int) ([]models.WebhookDelivery, error)
+	UpdateDelivery(ctx context.Context, delivery *models.WebhookDelivery) error
+	// Requeue moves a dead delivery back to pending with a fresh set of attempts; it reports false when
+	// the delivery is not dead
+	Requeue(ctx context.Context, id uint, at time.Time) (bool, error)
+	CreateAttempt(ctx context.Context, attempt *models.WebhookDeliveryAttempt) error
+	ListAttempts(ctx context.Context, deliveryID uint) ([]models.WebhookDeliveryAttempt, error)
+}
+
+// DomainEventFilter holds the criteria of events to replay; zero values match everything
+type DomainEventFilter struct {
+	UserID   uint
+	WalletID uint
+	From     time.Time
+	To       time.Time
+}
+
+// DomainEventRepository defines the interface for the store of emitted domain events
+type DomainEventRepository interface {
+	Create(ctx context.Context, event *models.DomainEvent) error
+	// List returns the events matching the filter in the order they occurred
+	List(ctx context.Context, filter DomainEventFilter, limit int) ([]models.DomainEvent, error)
 }
 
 // Repositories holds all repository interfaces
 type Repositories struct {
-	User            UserRepository
-	Wallet          WalletRepository
-	Transaction     TransactionRepository
-	TransactionType TransactionTypeRepository
-	Reconciliation  ReconciliationRepository
-	DB              *gorm.DB
+	User                    UserRepository
+	Wallet                  WalletRepository
+	Transaction             TransactionRepository
+	TransactionType         TransactionTypeRepository
+	TransactionPurpose      TransactionPurposeRepository
+	Reconciliation          ReconciliationRepository
+	AuditLog                AuditLogRepository
+	NotificationPreference  NotificationPreferenceRepository
+	PhoneVerification       PhoneVerificationRepository
+	DeviceToken             DeviceTokenRepository
+	Deposit                 DepositRepository
+	Payout                  PayoutRepository
+	MoneyRequest            MoneyRequestRepository
+	PaymentLink             PaymentLinkRepository
+	StandingOrder           StandingOrderRepository
+	WalletTier              WalletTierRepository
+	FraudReview             FraudReviewRepository
+	Blocklist               BlocklistRepository
+	ComplianceLog           ComplianceLogRepository
+	AMLCase                 AMLCaseRepository
+	Job                     JobRepository
+	IPAllowlist             IPAllowlistRepository
+	RevokedToken            RevokedTokenRepository
+	UserIdentity            UserIdentityRepository
+	LoginAttempt            LoginAttemptRepository
+	PasswordHistory         PasswordHistoryRepository
+	Session                 SessionRepository
+	TransferChallenge       TransferChallengeRepository
+	TransferQuote           TransferQuoteRepository
+	ReconciliationSummary   ReconciliationSummaryRepository
+	ReconciliationRun       ReconciliationRunRepository
+	LedgerAccount           LedgerAccountRepository
+	JournalEntry            JournalEntryRepository
+	SuspenseItem            SuspenseItemRepository
+	BalanceAdjustment       BalanceAdjustmentRepository
+	SettlementBatch         SettlementBatchRepository
+	ProviderStatement       ProviderStatementRepository
+	StatementLine           StatementLineRepository
+	ArchivedTransaction     ArchivedTransactionRepository
+	BalanceCheckpoint       BalanceCheckpointRepository
+	AccountStatement        AccountStatementRepository
+	TransactionStatusChange TransactionStatusChangeRepository
+	Budget                  BudgetRepository
+	Tenant                  TenantRepository
+	BusinessAccount         BusinessAccountRepository
+	PaymentApproval         PaymentApprovalRepository
+	Escrow                  EscrowRepository
+	Subscription            SubscriptionRepository
+	Webhook                 WebhookRepository
+	DomainEvent             DomainEventRepository
+	DB                      *gorm.DB
+	// UnitOfWork runs changes to several repositories atomically
+	UnitOfWork UnitOfWork
+
+	// TransactionRetry controls how RunInTransaction runs again transactions aborted by deadlocks, lock
+	// wait timeouts and connections lost before the statement was sent
+	TransactionRetry RetryPolicy
 }
 
 // NewRepositories creates a new instance of all repositories
 func NewRepositories(db *gorm.DB) *Repositories {
-	return &Repositories{
-		User:           NewUserRepository(db),
-		Wallet:         NewWalletRepository(db),
-		Transaction:    NewTransactionRepository(db),
-		Reconciliation: NewReconciliationRepository(db),
-		DB:             db,
+	repos := &Repositories{
+		User:                    NewUserRepository(db),
+		Wallet:                  NewWalletRepository(db),
+		Transaction:             NewTransactionRepository(db),
+		TransactionType:         NewTransactionTypeRepository(db),
+		TransactionPurpose:      NewTransactionPurposeRepository(db),
+		Reconciliation:          NewReconciliationRepository(db),
+		AuditLog:                NewAuditLogRepository(db),
+		NotificationPreference:  NewNotificationPreferenceRepository(db),
+		PhoneVerification:       NewPhoneVerificationRepository(db),
+		DeviceToken:             NewDeviceTokenRepository(db),
+		Deposit:                 NewDepositRepository(db),
+		Payout:                  NewPayoutRepository(db),
+		MoneyRequest:            NewMoneyRequestRepository(db),
+		PaymentLink:             NewPaymentLinkRepository(db),
+		StandingOrder:           NewStandingOrderRepository(db),
+		WalletTier:              NewWalletTierRepository(db),
+		FraudReview:             NewFraudReviewRepository(db),
+		Blocklist:               NewBlocklistRepository(db),
+		ComplianceLog:           NewComplianceLogRepository(db),
+		AMLCase:                 NewAMLCaseRepository(db),
+		Job:                     NewJobRepository(db),
+		IPAllowlist:             NewIPAllowlistRepository(db),
+		RevokedToken:            NewRevokedTokenRepository(db),
+		UserIdentity:            NewUserIdentityRepository(db),
+		LoginAttempt:            NewLoginAttemptRepository(db),
+		PasswordHistory:         NewPasswordHistoryRepository(db),
+		Session:                 NewSessionRepository(db),
+		TransferChallenge:       NewTransferChallengeRepository(db),
+		TransferQuote:           NewTransferQuoteRepository(db),
+		ReconciliationSummary:   NewReconciliationSummaryRepository(db),
+		ReconciliationRun:       NewReconciliationRunRepository(db),
+		LedgerAccount:           NewLedgerAccountRepository(db),
+		JournalEntry:            NewJournalEntryRepository(db),
+		SuspenseItem:            NewSuspenseItemRepository(db),
+		BalanceAdjustment:       NewBalanceAdjustmentRepository(db),
+		SettlementBatch:         NewSettlementBatchRepository(db),
+		ProviderStatement:       NewProviderStatementRepository(db),
+		StatementLine:           NewStatementLineRepository(db),
+		ArchivedTransaction:     NewArchivedTransactionRepository(db),
+		BalanceCheckpoint:       NewBalanceCheckpointRepository(db),
+		AccountStatement:        NewAccountStatementRepository(db),
+		TransactionStatusChange: NewTransactionStatusChangeRepository(db),
+		Budget:                  NewBudgetRepository(db),
+		Tenant:                  NewTenantRepository(db),
+		BusinessAccount:         NewBusinessAccountRepository(db),
+		PaymentApproval:         NewPaymentApprovalRepository(db),
+		Escrow:                  NewEscrowRepository(db),
+		Subscription:            NewSubscriptionRepository(db),
+		Webhook:                 NewWebhookRepository(db),
+		DomainEvent:             NewDomainEventRepository(db),
+		DB:                      db,
+		TransactionRetry:        DefaultRetryPolicy(),
 	}
+	repos.UnitOfWork = gormUnitOfWork{repos: repos}
+	return repos
 }
@@ -0,0 +1,48 @@
+package repositories
+
+import (
+	"context"
+
+	"github.com/limistah/wallet-service/internal/models"
+	"gorm.io/gorm"
+)
+
+type ipAllowlistRepository struct {
+	db *gorm.DB
+}
+
+// NewIPAllowlistRepository creates a new IP allowlist repository
+func NewIPAllowlistRepository(db *gorm.DB) IPAllowlistRepository {
+	return &ipAllowlistRepository{db: db}
+}
+
+func (r *ipAllowlistRepository) Create(ctx context.Context, entry *models.IPAllowlistEntry) error {
+	return withContext(r.db, ctx).Create(entry).Error
+}
+
+func (r *ipAllowlistRepository) GetByCIDR(ctx context.Context, cidr string) (*models.IPAllowlistEntry, error) {
+	var entry models.IPAllowlistEntry
+	err := withContext(r.db, ctx).Where("cidr = ?", cidr).First(&entry).Error
+	if err != nil {
+		return nil, err
+	}
+	return &entry, nil
+}
+
+// List returns every entry, oldest first; the allowlist is small enough to load at once
+func (r *ipAllowlistRepository) List(ctx context.Context) ([]models.IPAllowlistEntry, error) {
+	var entries []models.IPAllowlistEntry
+	err := withContext(r.db, ctx).Order("id ASC").Find(&entries).Error
+	return entries, err
+}
+
+func (r *ipAllowlistRepository) Delete(ctx context.Context, id uint) error {
+	result := withContext(r.db, ctx).Delete(&models.IPAllowlistEntry{}, id)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
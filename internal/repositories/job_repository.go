@@ -0,0 +1,124 @@
+package repositories
+
+import (
+	"context"
+	"time"
+
+	"github.com/limistah/wallet-service/internal/models"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type jobRepository struct {
+	db *gorm.DB
+}
+
+// NewJobRepository creates a new job repository
+func NewJobRepository(db *gorm.DB) JobRepository {
+	return &jobRepository{db: db}
+}
+
+func (r *jobRepository) Create(ctx context.Context, job *models.Job) error {
+	return withContext(r.db, ctx).Create(job).Error
+}
+
+func (r *jobRepository) GetByID(ctx context.Context, id uint) (*models.Job, error) {
+	var job models.Job
+	err := withContext(r.db, ctx).First(&job, id).Error
+	if err != nil {
+		return nil, err
+	}
+	return &job, nil
+}
+
+// List returns jobs oldest first; an empty status lists every job
+func (r *jobRepository) List(ctx context.Context, status models.JobStatus, offset, limit int) ([]models.Job, error) {
+	var jobs []models.Job
+	query := withContext(r.db, ctx).Model(&models.Job{})
+	if status != "" {
+		query = query.Where("status = ?", status)
+	}
+	err := query.Order("created_at ASC, id ASC").Offset(offset).Limit(limit).Find(&jobs).Error
+	return jobs, err
+}
+
+// ClaimDue leases up to limit jobs that are due, or whose previous lease expired, to the caller.
+// Rows locked by another worker are skipped so several instances can share the queue
+func (r *jobRepository) ClaimDue(ctx context.Context, now time.Time, lease time.Duration, limit int) ([]models.Job, error) {
+	var jobs []models.Job
+	err := withContext(r.db, ctx).Transaction(func(tx *gorm.DB) error {
+		err := tx.Clauses(clause.Locking{Strength: "UPDATE", Options: "SKIP LOCKED"}).
+			Where("(status = ? AND run_at <= ?) OR (status = ? AND locked_until < ?)",
+				models.JobStatusPending, now, models.JobStatusRunning, now).
+			Order("run_at ASC, id ASC").
+			Limit(limit).
+			Find(&jobs).Error
+		if err != nil || len(jobs) == 0 {
+			return err
+		}
+
+		ids := make([]uint, len(jobs))
+		for i := range jobs {
+			ids[i] = jobs[i].ID
+		}
+		lockedUntil := now.Add(lease)
+		err = tx.Model(&models.Job{}).Where("id IN ?", ids).Updates(map[string]interface{}{
+			"status":       models.JobStatusRunning,
+			"locked_until": lockedUntil,
+			"attempts":     gorm.Expr("attempts + 1"),
+		}).Error
+		if err != nil {
+			return err
+		}
+
+		for i := range jobs {
+			jobs[i].Status = models.JobStatusRunning
+			jobs[i].LockedUntil = &lockedUntil
+			jobs[i].Attempts++
+		}
+		return nil
+	})
+	return jobs, err
+}
+
+// Complete removes a job that ran successfully
+func (r *jobRepository) Complete(ctx context.Context, id uint) error {
+	return withContext(r.db, ctx).Delete(&models.Job{}, id).Error
+}
+
+// Retry releases a failed job to run again at runAt
+func (r *jobRepository) Retry(ctx context.Context, id uint, runAt time.Time, lastError string) error {
+	return withContext(r.db, ctx).Model(&models.Job{}).Where("id = ?", id).Updates(map[string]interface{}{
+		"status":       models.JobStatusPending,
+		"run_at":       runAt,
+		"locked_until": nil,
+		"last_error":   lastError,
+	}).Error
+}
+
+// Bury moves a job that will not be retried to the dead letter queue
+func (r *jobRepository) Bury(ctx context.Context, id uint, lastError string) error {
+	return withContext(r.db, ctx).Model(&models.Job{}).Where("id = ?", id).Updates(map[string]interface{}{
+		"status":       models.JobStatusDead,
+		"locked_until": nil,
+		"last_error":   lastError,
+	}).Error
+}
+
+// Requeue gives a dead job a fresh set of attempts starting at runAt
+func (r *jobRepository) Requeue(ctx context.Context, id uint, runAt time.Time) error {
+	result := withContext(r.db, ctx).Model(&models.Job{}).
+		Where("id = ? AND status = ?", id, models.JobStatusDead).
+		Updates(map[string]interface{}{
+			"status":   models.JobStatusPending,
+			"attempts": 0,
+			"run_at":   runAt,
+		})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
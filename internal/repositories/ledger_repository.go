@@ -0,0 +1,76 @@
+package repositories
+
+import (
+	"context"
+
+	"github.com/limistah/wallet-service/internal/models"
+	"gorm.io/gorm"
+)
+
+type ledgerAccountRepository struct {
+	db *gorm.DB
+}
+
+// NewLedgerAccountRepository creates a new ledger account repository
+func NewLedgerAccountRepository(db *gorm.DB) LedgerAccountRepository {
+	return &ledgerAccountRepository{db: db}
+}
+
+func (r *ledgerAccountRepository) Create(ctx context.Context, account *models.LedgerAccount) error {
+	return withContext(r.db, ctx).Create(account).Error
+}
+
+func (r *ledgerAccountRepository) GetByCode(ctx context.Context, code models.LedgerAccountCode, sandbox bool) (*models.LedgerAccount, error) {
+	var account models.LedgerAccount
+	err := withContext(r.db, ctx).Preload("Wallet").
+		Where("code = ? AND sandbox = ?", code, sandbox).
+		First(&account).Error
+	if err != nil {
+		return nil, err
+	}
+	return &account, nil
+}
+
+func (r *ledgerAccountRepository) List(ctx context.Context, sandbox bool) ([]models.LedgerAccount, error) {
+	var accounts []models.LedgerAccount
+	err := withContext(r.db, ctx).Preload("Wallet").
+		Where("sandbox = ?", sandbox).
+		Order("id ASC").
+		Find(&accounts).Error
+	return accounts, err
+}
+
+type journalEntryRepository struct {
+	db *gorm.DB
+}
+
+// NewJournalEntryRepository creates a new journal entry repository
+func NewJournalEntryRepository(db *gorm.DB) JournalEntryRepository {
+	return &journalEntryRepository{db: db}
+}
+
+func (r *journalEntryRepository) Create(ctx context.Context, entry *models.JournalEntry) error {
+	return withContext(r.db, ctx).Create(entry).Error
+}
+
+func (r *journalEntryRepository) GetByID(ctx context.Context, id uint) (*models.JournalEntry, error) {
+	var entry models.JournalEntry
+	err := withContext(r.db, ctx).Preload("Legs", func(db *gorm.DB) *gorm.DB {
+		return db.Order("id ASC")
+	}).First(&entry, id).Error
+	if err != nil {
+		return nil, err
+	}
+	return &entry, nil
+}
+
+func (r *journalEntryRepository) GetByReference(ctx context.Context, reference string) (*models.JournalEntry, error) {
+	var entry models.JournalEntry
+	err := withContext(r.db, ctx).Preload("Legs", func(db *gorm.DB) *gorm.DB {
+		return db.Order("id ASC")
+	}).Where("reference = ?", reference).First(&entry).Error
+	if err != nil {
+		return nil, err
+	}
+	return &entry, nil
+}
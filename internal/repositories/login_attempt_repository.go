@@ -0,0 +1,61 @@
+package repositories
+
+import (
+	"context"
+	"time"
+
+	"github.com/limistah/wallet-service/internal/models"
+	"gorm.io/gorm"
+)
+
+type loginAttemptRepository struct {
+	db *gorm.DB
+}
+
+// NewLoginAttemptRepository creates a new login attempt repository
+func NewLoginAttemptRepository(db *gorm.DB) LoginAttemptRepository {
+	return &loginAttemptRepository{db: db}
+}
+
+func (r *loginAttemptRepository) Create(ctx context.Context, attempt *models.LoginAttempt) error {
+	return withContext(r.db, ctx).Create(attempt).Error
+}
+
+// CountFailuresByIP counts the failed logins from an IP address since the given time
+func (r *loginAttemptRepository) CountFailuresByIP(ctx context.Context, ipAddress string, since time.Time) (int64, error) {
+	var count int64
+	err := withContext(r.db, ctx).Model(&models.LoginAttempt{}).
+		Where("ip_address = ? AND succeeded = ? AND created_at >= ?", ipAddress, false, since).
+		Count(&count).Error
+	return count, err
+}
+
+// ListByUser returns the latest login attempts on an account, newest first
+func (r *loginAttemptRepository) ListByUser(ctx context.Context, userID uint, limit int) ([]models.LoginAttempt, error) {
+	var attempts []models.LoginAttempt
+	err := withContext(r.db, ctx).Where("user_id = ?", userID).Order("id DESC").Limit(limit).Find(&attempts).Error
+	return attempts, err
+}
+
+// HasSucceeded tells whether the user ever logged in
+func (r *loginAttemptRepository) HasSucceeded(ctx context.Context, userID uint) (bool, error) {
+	var count int64
+	err := withContext(r.db, ctx).Model(&models.LoginAttempt{}).
+		Where("user_id = ? AND succeeded = ?", userID, true).
+		Limit(1).Count(&count).Error
+	return count > 0, err
+}
+
+// HasSucceededWithUserAgent tells whether the user ever logged in from a client with the user agent
+func (r *loginAttemptRepository) HasSucceededWithUserAgent(ctx context.Context, userID uint, userAgent string) (bool, error) {
+	var count int64
+	err := withContext(r.db, ctx).Model(&models.LoginAttempt{}).
+		Where("user_id = ? AND succeeded = ? AND user_agent = ?", userID, true, userAgent).
+		Limit(1).Count(&count).Error
+	return count > 0, err
+}
+
+// DeleteByUser removes the login history of the user
+func (r *loginAttemptRepository) DeleteByUser(ctx context.Context, userID uint) error {
+	return withContext(r.db, ctx).Where("user_id = ?", userID).Delete(&models.LoginAttempt{}).Error
+}
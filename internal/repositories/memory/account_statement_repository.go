@@ -0,0 +1,63 @@
+package memory
+
+import (
+	"context"
+	"sync"
+
+	"github.com/limistah/wallet-service/internal/models"
+	"gorm.io/gorm"
+)
+
+// AccountStatementRepository is an in-memory repositories.AccountStatementRepository
+type AccountStatementRepository struct {
+	mu         sync.RWMutex
+	statements map[uint]*models.AccountStatement
+	idCounter  uint
+}
+
+// NewAccountStatementRepository creates an empty account statement repository
+func NewAccountStatementRepository() *AccountStatementRepository {
+	return &AccountStatementRepository{
+		statements: make(map[uint]*models.AccountStatement),
+	}
+}
+
+func (m *AccountStatementRepository) Create(ctx context.Context, statement *models.AccountStatement) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.idCounter++
+	statement.ID = m.idCounter
+	stamp(statement, true)
+	m.statements[statement.ID] = clone(statement)
+	return nil
+}
+
+func (m *AccountStatementRepository) GetByID(ctx context.Context, id uint) (*models.AccountStatement, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	if statement, ok := m.statements[id]; ok {
+		return clone(statement), nil
+	}
+	return nil, gorm.ErrRecordNotFound
+}
+
+func (m *AccountStatementRepository) Update(ctx context.Context, statement *models.AccountStatement) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	stamp(statement, false)
+	m.statements[statement.ID] = clone(statement)
+	return nil
+}
+
+// ListByUserID returns a user's statements newest first
+func (m *AccountStatementRepository) ListByUserID(ctx context.Context, userID uint, offset, limit int) ([]models.AccountStatement, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	var statements []models.AccountStatement
+	for id := m.idCounter; id > 0; id-- {
+		if statement, ok := m.statements[id]; ok && statement.UserID == userID {
+			statements = append(statements, *statement)
+		}
+	}
+	return page(statements, offset, limit), nil
+}
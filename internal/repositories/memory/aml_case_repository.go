@@ -0,0 +1,120 @@
+package memory
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/limistah/wallet-service/internal/models"
+	"gorm.io/gorm"
+)
+
+// AMLCaseRepository is an in-memory repositories.AMLCaseRepository
+type AMLCaseRepository struct {
+	mu           sync.RWMutex
+	cases        map[uint]*models.AMLCase
+	idCounter    uint
+	transactions *TransactionRepository
+}
+
+// NewAMLCaseRepository creates an empty AML case repository loading the held debits of cases
+// from transactions
+func NewAMLCaseRepository(transactions *TransactionRepository) *AMLCaseRepository {
+	return &AMLCaseRepository{
+		cases:        make(map[uint]*models.AMLCase),
+		transactions: transactions,
+	}
+}
+
+func (m *AMLCaseRepository) Create(ctx context.Context, amlCase *models.AMLCase) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for _, stored := range m.cases {
+		if stored.TransactionID == amlCase.TransactionID {
+			return gorm.ErrDuplicatedKey
+		}
+	}
+	m.idCounter++
+	amlCase.ID = m.idCounter
+	stamp(amlCase, true)
+	stored := clone(amlCase)
+	stored.Transaction = models.Transaction{}
+	m.cases[amlCase.ID] = stored
+	return nil
+}
+
+// GetByID returns a case with its held debit
+func (m *AMLCaseRepository) GetByID(ctx context.Context, id uint) (*models.AMLCase, error) {
+	m.mu.RLock()
+	amlCase, ok := m.cases[id]
+	if ok {
+		amlCase = clone(amlCase)
+	}
+	m.mu.RUnlock()
+	if !ok {
+		return nil, gorm.ErrRecordNotFound
+	}
+	if m.transactions != nil {
+		if transaction, err := m.transactions.GetByID(ctx, amlCase.TransactionID); err == nil {
+			amlCase.Transaction = *transaction
+		}
+	}
+	return amlCase, nil
+}
+
+// List returns cases oldest first so the queue is worked in order; an empty status lists every case
+func (m *AMLCaseRepository) List(ctx context.Context, status models.AMLCaseStatus, offset, limit int) ([]models.AMLCase, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	var cases []models.AMLCase
+	for _, amlCase := range inOrder(m.cases) {
+		if status == "" || amlCase.Status == status {
+			cases = append(cases, *amlCase)
+		}
+	}
+	sortStable(cases, func(a, b models.AMLCase) bool { return a.CreatedAt.Before(b.CreatedAt) })
+	return page(cases, offset, limit), nil
+}
+
+func (m *AMLCaseRepository) Resolve(ctx context.Context, id uint, status models.AMLCaseStatus, reviewerID uint, note string, at time.Time) (bool, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	record, ok := m.cases[id]
+	if !ok || !record.IsPending() {
+		return false, nil
+	}
+	record.Status = status
+	record.ReviewerID = &reviewerID
+	record.ReviewNote = note
+	record.ReviewedAt = &at
+	stamp(record, false)
+	return true, nil
+}
+
+// references reports whether a case holds the transaction
+func (m *AMLCaseRepository) references(transactionID uint) bool {
+	if m == nil {
+		return false
+	}
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	for _, amlCase := range m.cases {
+		if amlCase.TransactionID == transactionID {
+			return true
+		}
+	}
+	return false
+}
+
+func (m *AMLCaseRepository) ExpireByTransactionID(ctx context.Context, transactionID uint, at time.Time) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for _, amlCase := range m.cases {
+		if amlCase.TransactionID == transactionID && amlCase.IsPending() {
+			amlCase.Status = models.AMLCaseStatusExpired
+			amlCase.ReviewedAt = &at
+			stamp(amlCase, false)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,107 @@
+package memory
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/limistah/wallet-service/internal/models"
+	"gorm.io/gorm"
+)
+
+// ArchivedTransactionRepository is an in-memory repositories.ArchivedTransactionRepository
+type ArchivedTransactionRepository struct {
+	mu           sync.RWMutex
+	transactions map[uint]*models.ArchivedTransaction
+}
+
+// NewArchivedTransactionRepository creates an empty archived transaction repository
+func NewArchivedTransactionRepository() *ArchivedTransactionRepository {
+	return &ArchivedTransactionRepository{
+		transactions: make(map[uint]*models.ArchivedTransaction),
+	}
+}
+
+// Add stores an archived transaction under its own ID
+func (m *ArchivedTransactionRepository) Add(transaction models.ArchivedTransaction) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.transactions[transaction.ID] = &transaction
+}
+
+// Create stores archived transactions under the IDs they had while live
+func (m *ArchivedTransactionRepository) Create(ctx context.Context, transactions []models.ArchivedTransaction) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for _, transaction := range transactions {
+		m.transactions[transaction.ID] = &transaction
+	}
+	return nil
+}
+
+func (m *ArchivedTransactionRepository) GetByID(ctx context.Context, id uint) (*models.ArchivedTransaction, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	if transaction, ok := m.transactions[id]; ok {
+		return clone(transaction), nil
+	}
+	return nil, gorm.ErrRecordNotFound
+}
+
+// GetByWalletIDWithCursor returns up to limit+1 transactions of a wallet older than the cursor, newest
+// first, so the caller can tell whether there is another page
+func (m *ArchivedTransactionRepository) GetByWalletIDWithCursor(ctx context.Context, walletID uint, cursor *time.Time, cursorID *uint, limit int) ([]models.ArchivedTransaction, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	var transactions []models.ArchivedTransaction
+	for _, transaction := range inOrder(m.transactions) {
+		if transaction.WalletID != walletID {
+			continue
+		}
+		if cursor != nil && cursorID != nil && !(transaction.CreatedAt.Before(*cursor) ||
+			(transaction.CreatedAt.Equal(*cursor) && transaction.ID < *cursorID)) {
+			continue
+		}
+		transactions = append(transactions, *transaction)
+	}
+	sortStable(transactions, func(a, b models.ArchivedTransaction) bool {
+		return a.CreatedAt.After(b.CreatedAt) || (a.CreatedAt.Equal(b.CreatedAt) && a.ID > b.ID)
+	})
+	return page(transactions, 0, limit+1), nil
+}
+
+func (m *ArchivedTransactionRepository) GetByWalletIDBetween(ctx context.Context, walletID uint, from, to time.Time) ([]models.ArchivedTransaction, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	var transactions []models.ArchivedTransaction
+	for _, transaction := range inOrder(m.transactions) {
+		if transaction.WalletID == walletID && !transaction.CreatedAt.Before(from) && transaction.CreatedAt.Before(to) {
+			transactions = append(transactions, *transaction)
+		}
+	}
+	sortStable(transactions, func(a, b models.ArchivedTransaction) bool { return a.CreatedAt.Before(b.CreatedAt) })
+	return transactions, nil
+}
+
+func (m *ArchivedTransactionRepository) GetByReference(ctx context.Context, reference string) (*models.ArchivedTransaction, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	for _, transaction := range m.transactions {
+		if transaction.Reference == reference {
+			return clone(transaction), nil
+		}
+	}
+	return nil, gorm.ErrRecordNotFound
+}
+
+func (m *ArchivedTransactionRepository) GetByRelatedTransactionID(ctx context.Context, relatedID uint) ([]models.ArchivedTransaction, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	var transactions []models.ArchivedTransaction
+	for _, transaction := range inOrder(m.transactions) {
+		if transaction.RelatedTransactionID != nil && *transaction.RelatedTransactionID == relatedID {
+			transactions = append(transactions, *transaction)
+		}
+	}
+	return transactions, nil
+}
@@ -0,0 +1,52 @@
+package memory
+
+import (
+	"context"
+	"sync"
+
+	"github.com/limistah/wallet-service/internal/models"
+)
+
+// AuditLogRepository is an in-memory repositories.AuditLogRepository
+type AuditLogRepository struct {
+	mu        sync.RWMutex
+	entries   []models.AuditLog
+	idCounter uint
+}
+
+// NewAuditLogRepository creates an empty audit log repository
+func NewAuditLogRepository() *AuditLogRepository {
+	return &AuditLogRepository{}
+}
+
+func (m *AuditLogRepository) Create(ctx context.Context, entry *models.AuditLog) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.idCounter++
+	entry.ID = m.idCounter
+	stamp(entry, true)
+	m.entries = append(m.entries, *entry)
+	return nil
+}
+
+// List returns entries newest first
+func (m *AuditLogRepository) List(ctx context.Context, offset, limit int) ([]models.AuditLog, error) {
+	return page(m.newestFirst(func(*models.AuditLog) bool { return true }), offset, limit), nil
+}
+
+func (m *AuditLogRepository) GetByActorID(ctx context.Context, actorID uint, offset, limit int) ([]models.AuditLog, error) {
+	return page(m.newestFirst(func(entry *models.AuditLog) bool { return entry.ActorID == actorID }), offset, limit), nil
+}
+
+func (m *AuditLogRepository) newestFirst(keep func(*models.AuditLog) bool) []models.AuditLog {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	var entries []models.AuditLog
+	for i := len(m.entries) - 1; i >= 0; i-- {
+		if keep(&m.entries[i]) {
+			entries = append(entries, m.entries[i])
+		}
+	}
+	sortStable(entries, func(a, b models.AuditLog) bool { return a.CreatedAt.After(b.CreatedAt) })
+	return entries
+}
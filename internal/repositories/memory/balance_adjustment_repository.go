@@ -0,0 +1,89 @@
+package memory
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/limistah/wallet-service/internal/models"
+	"gorm.io/gorm"
+)
+
+// BalanceAdjustmentRepository is an in-memory repositories.BalanceAdjustmentRepository
+type BalanceAdjustmentRepository struct {
+	mu          sync.RWMutex
+	adjustments map[uint]*models.BalanceAdjustment
+	idCounter   uint
+}
+
+// NewBalanceAdjustmentRepository creates an empty balance adjustment repository
+func NewBalanceAdjustmentRepository() *BalanceAdjustmentRepository {
+	return &BalanceAdjustmentRepository{
+		adjustments: make(map[uint]*models.BalanceAdjustment),
+	}
+}
+
+func (m *BalanceAdjustmentRepository) Create(ctx context.Context, adjustment *models.BalanceAdjustment) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.idCounter++
+	adjustment.ID = m.idCounter
+	stamp(adjustment, true)
+	m.adjustments[adjustment.ID] = clone(adjustment)
+	return nil
+}
+
+func (m *BalanceAdjustmentRepository) GetByID(ctx context.Context, id uint) (*models.BalanceAdjustment, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	if adjustment, ok := m.adjustments[id]; ok {
+		return clone(adjustment), nil
+	}
+	return nil, gorm.ErrRecordNotFound
+}
+
+// List returns adjustments oldest first so pending ones are approved in order; an empty status lists
+// every adjustment
+func (m *BalanceAdjustmentRepository) List(ctx context.Context, status models.BalanceAdjustmentStatus, offset, limit int) ([]models.BalanceAdjustment, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	adjustments := make([]models.BalanceAdjustment, 0)
+	for _, adjustment := range inOrder(m.adjustments) {
+		if status == "" || adjustment.Status == status {
+			adjustments = append(adjustments, *adjustment)
+		}
+	}
+	sortStable(adjustments, func(a, b models.BalanceAdjustment) bool { return a.CreatedAt.Before(b.CreatedAt) })
+	return page(adjustments, offset, limit), nil
+}
+
+func (m *BalanceAdjustmentRepository) Reject(ctx context.Context, id, approverID uint, note string, at time.Time) (bool, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	adjustment, ok := m.adjustments[id]
+	if !ok || !adjustment.IsPending() {
+		return false, nil
+	}
+	adjustment.Status = models.BalanceAdjustmentStatusRejected
+	adjustment.ApproverID = &approverID
+	adjustment.DecisionNote = note
+	adjustment.DecidedAt = &at
+	adjustment.UpdatedAt = time.Now()
+	return true, nil
+}
+
+func (m *BalanceAdjustmentRepository) Approve(ctx context.Context, id, approverID uint, note string, journalEntryID uint, at time.Time) (bool, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	adjustment, ok := m.adjustments[id]
+	if !ok || !adjustment.IsPending() {
+		return false, nil
+	}
+	adjustment.Status = models.BalanceAdjustmentStatusApproved
+	adjustment.ApproverID = &approverID
+	adjustment.DecisionNote = note
+	adjustment.DecidedAt = &at
+	adjustment.JournalEntryID = &journalEntryID
+	adjustment.UpdatedAt = time.Now()
+	return true, nil
+}
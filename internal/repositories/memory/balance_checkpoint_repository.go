@@ -0,0 +1,68 @@
+package memory
+
+import (
+	"context"
+	"sync"
+
+	"github.com/limistah/wallet-service/internal/models"
+	"gorm.io/gorm"
+)
+
+// BalanceCheckpointRepository is an in-memory repositories.BalanceCheckpointRepository
+type BalanceCheckpointRepository struct {
+	mu          sync.RWMutex
+	checkpoints map[uint]*models.BalanceCheckpoint
+	idCounter   uint
+}
+
+// NewBalanceCheckpointRepository creates an empty balance checkpoint repository
+func NewBalanceCheckpointRepository() *BalanceCheckpointRepository {
+	return &BalanceCheckpointRepository{
+		checkpoints: make(map[uint]*models.BalanceCheckpoint),
+	}
+}
+
+func (m *BalanceCheckpointRepository) Create(ctx context.Context, checkpoint *models.BalanceCheckpoint) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.idCounter++
+	checkpoint.ID = m.idCounter
+	stamp(checkpoint, true)
+	m.checkpoints[checkpoint.WalletID] = clone(checkpoint)
+	return nil
+}
+
+func (m *BalanceCheckpointRepository) GetByWalletID(ctx context.Context, walletID uint) (*models.BalanceCheckpoint, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	if checkpoint, ok := m.checkpoints[walletID]; ok {
+		return clone(checkpoint), nil
+	}
+	return nil, gorm.ErrRecordNotFound
+}
+
+// Advance moves a checkpoint forward to its new balance and transaction, but only while it is still
+// at previousThroughID, so two runs cannot count the same transactions twice
+func (m *BalanceCheckpointRepository) Advance(ctx context.Context, checkpoint *models.BalanceCheckpoint, previousThroughID uint) (bool, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	stored, ok := m.checkpoints[checkpoint.WalletID]
+	if !ok || stored.ThroughTransactionID != previousThroughID {
+		return false, nil
+	}
+	stored.Balance = checkpoint.Balance
+	stored.ThroughTransactionID = checkpoint.ThroughTransactionID
+	stamp(stored, false)
+	return true, nil
+}
+
+func (m *BalanceCheckpointRepository) RecordArchived(ctx context.Context, checkpoint *models.BalanceCheckpoint) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if stored, ok := m.checkpoints[checkpoint.WalletID]; ok {
+		stored.ArchivedCount = checkpoint.ArchivedCount
+		stored.ArchivedThrough = checkpoint.ArchivedThrough
+		stamp(stored, false)
+	}
+	return nil
+}
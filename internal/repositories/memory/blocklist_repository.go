@@ -0,0 +1,84 @@
+package memory
+
+import (
+	"context"
+	"sync"
+
+	"github.com/limistah/wallet-service/internal/models"
+	"gorm.io/gorm"
+)
+
+// BlocklistRepository is an in-memory repositories.BlocklistRepository
+type BlocklistRepository struct {
+	mu        sync.RWMutex
+	entries   map[uint]*models.BlocklistEntry
+	idCounter uint
+}
+
+// NewBlocklistRepository creates an empty blocklist repository
+func NewBlocklistRepository() *BlocklistRepository {
+	return &BlocklistRepository{
+		entries: make(map[uint]*models.BlocklistEntry),
+	}
+}
+
+func (m *BlocklistRepository) Create(ctx context.Context, entry *models.BlocklistEntry) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for _, existing := range m.entries {
+		if existing.Type == entry.Type && existing.Value == entry.Value {
+			return gorm.ErrDuplicatedKey
+		}
+	}
+	m.idCounter++
+	entry.ID = m.idCounter
+	stamp(entry, true)
+	m.entries[entry.ID] = clone(entry)
+	return nil
+}
+
+func (m *BlocklistRepository) GetByID(ctx context.Context, id uint) (*models.BlocklistEntry, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	if entry, ok := m.entries[id]; ok {
+		return clone(entry), nil
+	}
+	return nil, gorm.ErrRecordNotFound
+}
+
+func (m *BlocklistRepository) GetByValue(ctx context.Context, entryType models.BlocklistEntryType, value string) (*models.BlocklistEntry, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	for _, entry := range m.entries {
+		if entry.Type == entryType && entry.Value == value {
+			return clone(entry), nil
+		}
+	}
+	return nil, gorm.ErrRecordNotFound
+}
+
+// List returns entries newest first; an empty type lists every entry
+func (m *BlocklistRepository) List(ctx context.Context, entryType models.BlocklistEntryType, offset, limit int) ([]models.BlocklistEntry, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	var entries []models.BlocklistEntry
+	for _, entry := range inOrder(m.entries) {
+		if entryType == "" || entry.Type == entryType {
+			entries = append(entries, *entry)
+		}
+	}
+	sortStable(entries, func(a, b models.BlocklistEntry) bool {
+		return a.CreatedAt.After(b.CreatedAt) || a.CreatedAt.Equal(b.CreatedAt) && a.ID > b.ID
+	})
+	return page(entries, offset, limit), nil
+}
+
+func (m *BlocklistRepository) Delete(ctx context.Context, id uint) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if _, ok := m.entries[id]; !ok {
+		return gorm.ErrRecordNotFound
+	}
+	delete(m.entries, id)
+	return nil
+}
@@ -0,0 +1,82 @@
+package memory
+
+import (
+	"context"
+	"sync"
+
+	"github.com/limistah/wallet-service/internal/models"
+	"gorm.io/gorm"
+)
+
+// BudgetRepository is an in-memory repositories.BudgetRepository
+type BudgetRepository struct {
+	mu        sync.RWMutex
+	budgets   map[uint]*models.Budget
+	idCounter uint
+}
+
+// NewBudgetRepository creates an empty budget repository
+func NewBudgetRepository() *BudgetRepository {
+	return &BudgetRepository{
+		budgets: make(map[uint]*models.Budget),
+	}
+}
+
+func (m *BudgetRepository) Create(ctx context.Context, budget *models.Budget) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.idCounter++
+	budget.ID = m.idCounter
+	stamp(budget, true)
+	m.budgets[budget.ID] = clone(budget)
+	return nil
+}
+
+func (m *BudgetRepository) GetByID(ctx context.Context, id uint) (*models.Budget, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	if budget, ok := m.budgets[id]; ok {
+		return clone(budget), nil
+	}
+	return nil, gorm.ErrRecordNotFound
+}
+
+func (m *BudgetRepository) ListByUserID(ctx context.Context, userID uint) ([]models.Budget, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	var budgets []models.Budget
+	for _, budget := range inOrder(m.budgets) {
+		if budget.UserID == userID {
+			budgets = append(budgets, *budget)
+		}
+	}
+	sortStable(budgets, func(a, b models.Budget) bool { return a.Category < b.Category })
+	return budgets, nil
+}
+
+func (m *BudgetRepository) ListAfter(ctx context.Context, afterID uint, limit int) ([]models.Budget, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	var budgets []models.Budget
+	for _, budget := range inOrder(m.budgets) {
+		if budget.ID > afterID {
+			budgets = append(budgets, *budget)
+		}
+	}
+	return page(budgets, 0, limit), nil
+}
+
+func (m *BudgetRepository) Update(ctx context.Context, budget *models.Budget) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	stamp(budget, false)
+	m.budgets[budget.ID] = clone(budget)
+	return nil
+}
+
+func (m *BudgetRepository) Delete(ctx context.Context, id uint) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	delete(m.budgets, id)
+	return nil
+}
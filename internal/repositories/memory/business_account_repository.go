@@ -0,0 +1,152 @@
+package memory
+
+import (
+	"context"
+	"sync"
+
+	"github.com/limistah/wallet-service/internal/models"
+	"gorm.io/gorm"
+)
+
+// BusinessAccountRepository is an in-memory repositories.BusinessAccountRepository
+type BusinessAccountRepository struct {
+	mu              sync.RWMutex
+	accounts        map[uint]*models.BusinessAccount
+	members         map[uint]*models.BusinessMember
+	idCounter       uint
+	memberIDCounter uint
+	wallets         *WalletRepository
+	// users fills in the users of the members read, when set
+	users *UserRepository
+}
+
+// NewBusinessAccountRepository creates an empty business account repository loading the wallets of
+// accounts from wallets
+func NewBusinessAccountRepository(wallets *WalletRepository) *BusinessAccountRepository {
+	return &BusinessAccountRepository{
+		accounts: make(map[uint]*models.BusinessAccount),
+		members:  make(map[uint]*models.BusinessMember),
+		wallets:  wallets,
+	}
+}
+
+func (m *BusinessAccountRepository) Create(ctx context.Context, account *models.BusinessAccount) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for _, stored := range m.accounts {
+		if stored.WalletID == account.WalletID {
+			return gorm.ErrDuplicatedKey
+		}
+	}
+	m.idCounter++
+	account.ID = m.idCounter
+	stamp(account, true)
+	stored := clone(account)
+	stored.Wallet = models.Wallet{}
+	stored.Members = nil
+	m.accounts[account.ID] = stored
+	return nil
+}
+
+// GetByID returns an account with its wallet and its members in the order they joined
+func (m *BusinessAccountRepository) GetByID(ctx context.Context, id uint) (*models.BusinessAccount, error) {
+	m.mu.RLock()
+	account, ok := m.accounts[id]
+	if ok {
+		account = clone(account)
+		account.Members = m.membersOf(id)
+	}
+	m.mu.RUnlock()
+	if !ok {
+		return nil, gorm.ErrRecordNotFound
+	}
+	m.loadWallet(ctx, account)
+	if m.users != nil {
+		for i := range account.Members {
+			account.Members[i].User = m.users.load(account.Members[i].UserID)
+		}
+	}
+	return account, nil
+}
+
+func (m *BusinessAccountRepository) ListByMemberUserID(ctx context.Context, userID uint) ([]models.BusinessAccount, error) {
+	m.mu.RLock()
+	accounts := make([]models.BusinessAccount, 0)
+	for _, member := range inOrder(m.members) {
+		if member.UserID != userID {
+			continue
+		}
+		if account, ok := m.accounts[member.BusinessAccountID]; ok {
+			loaded := *account
+			loaded.Members = m.membersOf(account.ID)
+			accounts = append(accounts, loaded)
+		}
+	}
+	m.mu.RUnlock()
+	sortStable(accounts, func(a, b models.BusinessAccount) bool { return a.ID < b.ID })
+	for i := range accounts {
+		m.loadWallet(ctx, &accounts[i])
+	}
+	return accounts, nil
+}
+
+func (m *BusinessAccountRepository) GetMember(ctx context.Context, accountID, userID uint) (*models.BusinessMember, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	for _, member := range m.members {
+		if member.BusinessAccountID == accountID && member.UserID == userID {
+			return clone(member), nil
+		}
+	}
+	return nil, gorm.ErrRecordNotFound
+}
+
+func (m *BusinessAccountRepository) AddMember(ctx context.Context, member *models.BusinessMember) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for _, stored := range m.members {
+		if stored.BusinessAccountID == member.BusinessAccountID && stored.UserID == member.UserID {
+			return gorm.ErrDuplicatedKey
+		}
+	}
+	m.memberIDCounter++
+	member.ID = m.memberIDCounter
+	stamp(member, true)
+	m.members[member.ID] = clone(member)
+	return nil
+}
+
+func (m *BusinessAccountRepository) UpdateMember(ctx context.Context, member *models.BusinessMember) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	stamp(member, false)
+	m.members[member.ID] = clone(member)
+	return nil
+}
+
+func (m *BusinessAccountRepository) RemoveMember(ctx context.Context, id uint) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	delete(m.members, id)
+	return nil
+}
+
+// membersOf returns the members of an account in ID order; the caller holds the lock
+func (m *BusinessAccountRepository) membersOf(accountID uint) []models.BusinessMember {
+	var members []models.BusinessMember
+	for _, member := range inOrder(m.members) {
+		if member.BusinessAccountID == accountID {
+			members = append(members, *member)
+		}
+	}
+	return members
+}
+
+func (m *BusinessAccountRepository) loadWallet(ctx context.Context, account *models.BusinessAccount) {
+	if m.wallets == nil {
+		return
+	}
+	if wallet, err := m.wallets.GetByID(ctx, account.WalletID); err == nil {
+		account.Wallet = *wallet
+	}
+}
@@ -0,0 +1,41 @@
+package memory
+
+import (
+	"context"
+	"sync"
+
+	"github.com/limistah/wallet-service/internal/models"
+)
+
+// ComplianceLogRepository is an in-memory repositories.ComplianceLogRepository
+type ComplianceLogRepository struct {
+	mu      sync.RWMutex
+	entries []models.ComplianceLog
+}
+
+// NewComplianceLogRepository creates an empty compliance log repository
+func NewComplianceLogRepository() *ComplianceLogRepository {
+	return &ComplianceLogRepository{}
+}
+
+func (m *ComplianceLogRepository) Create(ctx context.Context, entry *models.ComplianceLog) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	entry.ID = uint(len(m.entries) + 1)
+	stamp(entry, true)
+	m.entries = append(m.entries, *entry)
+	return nil
+}
+
+// List returns log entries newest first; an empty check lists every entry
+func (m *ComplianceLogRepository) List(ctx context.Context, check models.ComplianceCheck, offset, limit int) ([]models.ComplianceLog, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	var entries []models.ComplianceLog
+	for i := len(m.entries) - 1; i >= 0; i-- {
+		if check == "" || m.entries[i].Check == check {
+			entries = append(entries, m.entries[i])
+		}
+	}
+	return page(entries, offset, limit), nil
+}
@@ -0,0 +1,107 @@
+package memory
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/limistah/wallet-service/internal/models"
+	"gorm.io/gorm"
+)
+
+// DepositRepository is an in-memory repositories.DepositRepository
+type DepositRepository struct {
+	mu        sync.RWMutex
+	deposits  map[uint]*models.Deposit
+	idCounter uint
+}
+
+// NewDepositRepository creates an empty deposit repository
+func NewDepositRepository() *DepositRepository {
+	return &DepositRepository{
+		deposits: make(map[uint]*models.Deposit),
+	}
+}
+
+func (m *DepositRepository) Create(ctx context.Context, deposit *models.Deposit) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for _, existing := range m.deposits {
+		if existing.Provider == deposit.Provider && existing.ExternalReference == deposit.ExternalReference {
+			return gorm.ErrDuplicatedKey
+		}
+	}
+	m.idCounter++
+	deposit.ID = m.idCounter
+	stamp(deposit, true)
+	m.deposits[deposit.ID] = clone(deposit)
+	return nil
+}
+
+func (m *DepositRepository) GetByID(ctx context.Context, id uint) (*models.Deposit, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	if deposit, ok := m.deposits[id]; ok {
+		return clone(deposit), nil
+	}
+	return nil, gorm.ErrRecordNotFound
+}
+
+func (m *DepositRepository) GetByExternalReference(ctx context.Context, provider, externalReference string) (*models.Deposit, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	for _, deposit := range m.deposits {
+		if deposit.Provider == provider && deposit.ExternalReference == externalReference {
+			return clone(deposit), nil
+		}
+	}
+	return nil, gorm.ErrRecordNotFound
+}
+
+func (m *DepositRepository) Update(ctx context.Context, deposit *models.Deposit) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	stamp(deposit, false)
+	m.deposits[deposit.ID] = clone(deposit)
+	return nil
+}
+
+func (m *DepositRepository) ListUnbatched(ctx context.Context, from, to time.Time) ([]models.Deposit, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	var deposits []models.Deposit
+	for _, deposit := range inOrder(m.deposits) {
+		if deposit.Status == models.DepositStatusCompleted && deposit.SettlementBatchID == nil && deposit.CompletedAt != nil &&
+			!deposit.CompletedAt.Before(from) && deposit.CompletedAt.Before(to) {
+			deposits = append(deposits, *deposit)
+		}
+	}
+	return deposits, nil
+}
+
+func (m *DepositRepository) AssignToBatch(ctx context.Context, ids []uint, batchID uint) (int, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	assigned := 0
+	for _, id := range ids {
+		if deposit, ok := m.deposits[id]; ok && deposit.SettlementBatchID == nil {
+			deposit.SettlementBatchID = &batchID
+			stamp(deposit, false)
+			assigned++
+		}
+	}
+	return assigned, nil
+}
+
+// inBatch returns the deposits a settlement batch holds in ID order
+func (m *DepositRepository) inBatch(batchID uint) []models.Deposit {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	var deposits []models.Deposit
+	for _, deposit := range inOrder(m.deposits) {
+		if deposit.SettlementBatchID != nil && *deposit.SettlementBatchID == batchID {
+			deposits = append(deposits, *deposit)
+		}
+	}
+	return deposits
+}
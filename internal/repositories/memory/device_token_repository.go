@@ -0,0 +1,93 @@
+package memory
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/limistah/wallet-service/internal/models"
+	"gorm.io/gorm"
+)
+
+// DeviceTokenRepository is an in-memory repositories.DeviceTokenRepository
+type DeviceTokenRepository struct {
+	mu        sync.RWMutex
+	devices   map[uint]*models.DeviceToken
+	idCounter uint
+}
+
+// NewDeviceTokenRepository creates an empty device token repository
+func NewDeviceTokenRepository() *DeviceTokenRepository {
+	return &DeviceTokenRepository{
+		devices: make(map[uint]*models.DeviceToken),
+	}
+}
+
+// Save registers a device, taking over the record of a token registered before
+func (m *DeviceTokenRepository) Save(ctx context.Context, device *models.DeviceToken) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for _, existing := range m.devices {
+		if existing.Token == device.Token {
+			device.ID = existing.ID
+			device.CreatedAt = existing.CreatedAt
+			stamp(device, false)
+			m.devices[device.ID] = clone(device)
+			return nil
+		}
+	}
+	m.idCounter++
+	device.ID = m.idCounter
+	stamp(device, true)
+	m.devices[device.ID] = clone(device)
+	return nil
+}
+
+// GetByUserID returns the devices of a user newest first
+func (m *DeviceTokenRepository) GetByUserID(ctx context.Context, userID uint) ([]models.DeviceToken, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	var devices []models.DeviceToken
+	for _, device := range inOrder(m.devices) {
+		if device.UserID == userID {
+			devices = append(devices, *device)
+		}
+	}
+	sortStable(devices, func(a, b models.DeviceToken) bool { return a.CreatedAt.After(b.CreatedAt) })
+	return devices, nil
+}
+
+func (m *DeviceTokenRepository) Delete(ctx context.Context, userID, id uint) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	device, ok := m.devices[id]
+	if !ok || device.UserID != userID {
+		return gorm.ErrRecordNotFound
+	}
+	delete(m.devices, id)
+	return nil
+}
+
+func (m *DeviceTokenRepository) DeleteByToken(ctx context.Context, token string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for id, device := range m.devices {
+		if device.Token == token {
+			delete(m.devices, id)
+		}
+	}
+	return nil
+}
+
+func (m *DeviceTokenRepository) MarkUsed(ctx context.Context, token string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	now := time.Now()
+	for _, device := range m.devices {
+		if device.Token == token {
+			device.LastUsedAt = &now
+			device.UpdatedAt = now
+		}
+	}
+	return nil
+}
@@ -0,0 +1,53 @@
+package memory
+
+import (
+	"context"
+	"sync"
+
+	"github.com/limistah/wallet-service/internal/models"
+	"github.com/limistah/wallet-service/internal/repositories"
+)
+
+// DomainEventRepository is an in-memory repositories.DomainEventRepository
+type DomainEventRepository struct {
+	mu     sync.RWMutex
+	events []models.DomainEvent
+}
+
+// NewDomainEventRepository creates an empty domain event repository
+func NewDomainEventRepository() *DomainEventRepository {
+	return &DomainEventRepository{}
+}
+
+func (m *DomainEventRepository) Create(ctx context.Context, event *models.DomainEvent) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	event.ID = uint(len(m.events) + 1)
+	stamp(event, true)
+	m.events = append(m.events, *event)
+	return nil
+}
+
+func (m *DomainEventRepository) List(ctx context.Context, filter repositories.DomainEventFilter, limit int) ([]models.DomainEvent, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	var events []models.DomainEvent
+	for _, e := range m.events {
+		if (filter.UserID != 0 && e.UserID != filter.UserID) || (filter.WalletID != 0 && e.WalletID != filter.WalletID) {
+			continue
+		}
+		if (!filter.From.IsZero() && e.OccurredAt.Before(filter.From)) || (!filter.To.IsZero() && !e.OccurredAt.Before(filter.To)) {
+			continue
+		}
+		events = append(events, e)
+	}
+	sortStable(events, func(a, b models.DomainEvent) bool { return a.OccurredAt.Before(b.OccurredAt) })
+	return page(events, 0, limit), nil
+}
+
+// Events returns the stored events, oldest first
+func (m *DomainEventRepository) Events() []models.DomainEvent {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return append([]models.DomainEvent(nil), m.events...)
+}
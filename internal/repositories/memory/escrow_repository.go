@@ -0,0 +1,120 @@
+package memory
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/limistah/wallet-service/internal/models"
+	"gorm.io/gorm"
+)
+
+// EscrowRepository is an in-memory repositories.EscrowRepository
+type EscrowRepository struct {
+	mu      sync.RWMutex
+	escrows map[uint]*models.Escrow
+	// users fills in the payers and payees of the escrows read, when set
+	users *UserRepository
+}
+
+// NewEscrowRepository creates an empty escrow repository
+func NewEscrowRepository() *EscrowRepository {
+	return &EscrowRepository{
+		escrows: make(map[uint]*models.Escrow),
+	}
+}
+
+func (m *EscrowRepository) Create(ctx context.Context, escrow *models.Escrow) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	escrow.ID = uint(len(m.escrows) + 1)
+	stamp(escrow, true)
+	stored := clone(escrow)
+	stored.Payer, stored.Payee = models.User{}, models.User{}
+	m.escrows[escrow.ID] = stored
+	return nil
+}
+
+// GetByID returns an escrow with its payer and payee
+func (m *EscrowRepository) GetByID(ctx context.Context, id uint) (*models.Escrow, error) {
+	m.mu.RLock()
+	escrow, ok := m.escrows[id]
+	if ok {
+		escrow = clone(escrow)
+	}
+	m.mu.RUnlock()
+	if !ok {
+		return nil, gorm.ErrRecordNotFound
+	}
+	m.loadParties(ctx, escrow)
+	return escrow, nil
+}
+
+// ListByUser returns the escrows of a payer or payee with their payers and payees, newest first
+func (m *EscrowRepository) ListByUser(ctx context.Context, userID uint, status models.EscrowStatus, offset, limit int) ([]models.Escrow, error) {
+	m.mu.RLock()
+	var escrows []models.Escrow
+	for _, escrow := range inOrder(m.escrows) {
+		if (escrow.PayerID == userID || escrow.PayeeID == userID) && (status == "" || escrow.Status == status) {
+			escrows = append(escrows, *escrow)
+		}
+	}
+	m.mu.RUnlock()
+	sortStable(escrows, func(a, b models.Escrow) bool {
+		return a.CreatedAt.After(b.CreatedAt) || a.CreatedAt.Equal(b.CreatedAt) && a.ID > b.ID
+	})
+	escrows = page(escrows, offset, limit)
+	for i := range escrows {
+		m.loadParties(ctx, &escrows[i])
+	}
+	return escrows, nil
+}
+
+// ListExpired returns held escrows whose expiry has passed, oldest expiry first
+func (m *EscrowRepository) ListExpired(ctx context.Context, now time.Time, limit int) ([]models.Escrow, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	var escrows []models.Escrow
+	for _, escrow := range inOrder(m.escrows) {
+		if escrow.IsHeld() && escrow.IsExpired(now) {
+			escrows = append(escrows, *escrow)
+		}
+	}
+	sortStable(escrows, func(a, b models.Escrow) bool { return a.ExpiresAt.Before(b.ExpiresAt) })
+	return page(escrows, 0, limit), nil
+}
+
+func (m *EscrowRepository) Settle(ctx context.Context, id uint, status models.EscrowStatus, at time.Time) (bool, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	escrow, ok := m.escrows[id]
+	if !ok || !escrow.IsHeld() {
+		return false, nil
+	}
+	escrow.Status = status
+	escrow.SettledAt = &at
+	stamp(escrow, false)
+	return true, nil
+}
+
+func (m *EscrowRepository) SetSettlementJournalEntry(ctx context.Context, id, journalEntryID uint) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if escrow, ok := m.escrows[id]; ok {
+		escrow.SettlementJournalEntryID = &journalEntryID
+		stamp(escrow, false)
+	}
+	return nil
+}
+
+func (m *EscrowRepository) loadParties(ctx context.Context, escrow *models.Escrow) {
+	if m.users == nil {
+		return
+	}
+	if payer, err := m.users.GetByID(ctx, escrow.PayerID); err == nil {
+		escrow.Payer = *payer
+	}
+	if payee, err := m.users.GetByID(ctx, escrow.PayeeID); err == nil {
+		escrow.Payee = *payee
+	}
+}
@@ -0,0 +1,107 @@
+package memory
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/limistah/wallet-service/internal/models"
+	"gorm.io/gorm"
+)
+
+// FraudReviewRepository is an in-memory repositories.FraudReviewRepository
+type FraudReviewRepository struct {
+	mu           sync.RWMutex
+	reviews      map[uint]*models.FraudReview
+	idCounter    uint
+	transactions *TransactionRepository
+}
+
+// NewFraudReviewRepository creates an empty fraud review repository loading the held debits of reviews
+// from transactions
+func NewFraudReviewRepository(transactions *TransactionRepository) *FraudReviewRepository {
+	return &FraudReviewRepository{
+		reviews:      make(map[uint]*models.FraudReview),
+		transactions: transactions,
+	}
+}
+
+func (m *FraudReviewRepository) Create(ctx context.Context, review *models.FraudReview) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for _, stored := range m.reviews {
+		if stored.TransactionID == review.TransactionID {
+			return gorm.ErrDuplicatedKey
+		}
+	}
+	m.idCounter++
+	review.ID = m.idCounter
+	stamp(review, true)
+	stored := clone(review)
+	stored.Transaction = models.Transaction{}
+	m.reviews[review.ID] = stored
+	return nil
+}
+
+// GetByID returns a review with its held debit
+func (m *FraudReviewRepository) GetByID(ctx context.Context, id uint) (*models.FraudReview, error) {
+	m.mu.RLock()
+	review, ok := m.reviews[id]
+	if ok {
+		review = clone(review)
+	}
+	m.mu.RUnlock()
+	if !ok {
+		return nil, gorm.ErrRecordNotFound
+	}
+	if m.transactions != nil {
+		if transaction, err := m.transactions.GetByID(ctx, review.TransactionID); err == nil {
+			review.Transaction = *transaction
+		}
+	}
+	return review, nil
+}
+
+// List returns reviews oldest first so the queue is worked in order; an empty status lists every review
+func (m *FraudReviewRepository) List(ctx context.Context, status models.FraudReviewStatus, offset, limit int) ([]models.FraudReview, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	var reviews []models.FraudReview
+	for _, review := range inOrder(m.reviews) {
+		if status == "" || review.Status == status {
+			reviews = append(reviews, *review)
+		}
+	}
+	sortStable(reviews, func(a, b models.FraudReview) bool { return a.CreatedAt.Before(b.CreatedAt) })
+	return page(reviews, offset, limit), nil
+}
+
+func (m *FraudReviewRepository) Resolve(ctx context.Context, id uint, status models.FraudReviewStatus, reviewerID uint, note string, at time.Time) (bool, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	record, ok := m.reviews[id]
+	if !ok || !record.IsPending() {
+		return false, nil
+	}
+	record.Status = status
+	record.ReviewerID = &reviewerID
+	record.ReviewNote = note
+	record.ReviewedAt = &at
+	stamp(record, false)
+	return true, nil
+}
+
+// references reports whether a review holds the transaction
+func (m *FraudReviewRepository) references(transactionID uint) bool {
+	if m == nil {
+		return false
+	}
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	for _, review := range m.reviews {
+		if review.TransactionID == transactionID {
+			return true
+		}
+	}
+	return false
+}
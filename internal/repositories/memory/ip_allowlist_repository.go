@@ -0,0 +1,66 @@
+package memory
+
+import (
+	"context"
+	"sync"
+
+	"github.com/limistah/wallet-service/internal/models"
+	"gorm.io/gorm"
+)
+
+// IPAllowlistRepository is an in-memory repositories.IPAllowlistRepository
+type IPAllowlistRepository struct {
+	mu        sync.RWMutex
+	entries   []models.IPAllowlistEntry
+	idCounter uint
+}
+
+// NewIPAllowlistRepository creates an empty IP allowlist repository
+func NewIPAllowlistRepository() *IPAllowlistRepository {
+	return &IPAllowlistRepository{}
+}
+
+func (m *IPAllowlistRepository) Create(ctx context.Context, entry *models.IPAllowlistEntry) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for _, existing := range m.entries {
+		if existing.CIDR == entry.CIDR {
+			return gorm.ErrDuplicatedKey
+		}
+	}
+	m.idCounter++
+	entry.ID = m.idCounter
+	stamp(entry, true)
+	m.entries = append(m.entries, *entry)
+	return nil
+}
+
+func (m *IPAllowlistRepository) GetByCIDR(ctx context.Context, cidr string) (*models.IPAllowlistEntry, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	for _, entry := range m.entries {
+		if entry.CIDR == cidr {
+			return &entry, nil
+		}
+	}
+	return nil, gorm.ErrRecordNotFound
+}
+
+// List returns every entry, oldest first
+func (m *IPAllowlistRepository) List(ctx context.Context) ([]models.IPAllowlistEntry, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return append([]models.IPAllowlistEntry(nil), m.entries...), nil
+}
+
+func (m *IPAllowlistRepository) Delete(ctx context.Context, id uint) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for i, entry := range m.entries {
+		if entry.ID == id {
+			m.entries = append(m.entries[:i:i], m.entries[i+1:]...)
+			return nil
+		}
+	}
+	return gorm.ErrRecordNotFound
+}
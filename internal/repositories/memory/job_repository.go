@@ -0,0 +1,137 @@
+package memory
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/limistah/wallet-service/internal/models"
+	"gorm.io/gorm"
+)
+
+// JobRepository is an in-memory repositories.JobRepository
+type JobRepository struct {
+	mu        sync.RWMutex
+	jobs      map[uint]*models.Job
+	idCounter uint
+}
+
+// NewJobRepository creates an empty job repository
+func NewJobRepository() *JobRepository {
+	return &JobRepository{
+		jobs: make(map[uint]*models.Job),
+	}
+}
+
+func (m *JobRepository) Create(ctx context.Context, job *models.Job) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if job.Status == "" {
+		job.Status = models.JobStatusPending
+	}
+	m.idCounter++
+	job.ID = m.idCounter
+	stamp(job, true)
+	m.jobs[job.ID] = clone(job)
+	return nil
+}
+
+func (m *JobRepository) GetByID(ctx context.Context, id uint) (*models.Job, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	if job, ok := m.jobs[id]; ok {
+		return clone(job), nil
+	}
+	return nil, gorm.ErrRecordNotFound
+}
+
+// List returns jobs oldest first; an empty status lists every job
+func (m *JobRepository) List(ctx context.Context, status models.JobStatus, offset, limit int) ([]models.Job, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	var jobs []models.Job
+	for _, job := range inOrder(m.jobs) {
+		if status == "" || job.Status == status {
+			jobs = append(jobs, *job)
+		}
+	}
+	sortStable(jobs, func(a, b models.Job) bool { return a.CreatedAt.Before(b.CreatedAt) })
+	return page(jobs, offset, limit), nil
+}
+
+// ClaimDue leases up to limit pending jobs due by now, and running jobs whose lease expired, to the
+// caller, counting an attempt for each
+func (m *JobRepository) ClaimDue(ctx context.Context, now time.Time, lease time.Duration, limit int) ([]models.Job, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	var due []*models.Job
+	for _, job := range inOrder(m.jobs) {
+		if (job.Status == models.JobStatusPending && !job.RunAt.After(now)) ||
+			(job.Status == models.JobStatusRunning && job.LockedUntil != nil && job.LockedUntil.Before(now)) {
+			due = append(due, job)
+		}
+	}
+	sortStable(due, func(a, b *models.Job) bool { return a.RunAt.Before(b.RunAt) })
+	due = page(due, 0, limit)
+
+	lockedUntil := now.Add(lease)
+	jobs := make([]models.Job, 0, len(due))
+	for _, job := range due {
+		job.Status = models.JobStatusRunning
+		job.LockedUntil = &lockedUntil
+		job.Attempts++
+		job.UpdatedAt = time.Now()
+		jobs = append(jobs, *job)
+	}
+	return jobs, nil
+}
+
+// Complete removes a job that ran
+func (m *JobRepository) Complete(ctx context.Context, id uint) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	delete(m.jobs, id)
+	return nil
+}
+
+func (m *JobRepository) Retry(ctx context.Context, id uint, runAt time.Time, lastError string) error {
+	return m.update(id, func(job *models.Job) {
+		job.Status = models.JobStatusPending
+		job.RunAt = runAt
+		job.LockedUntil = nil
+		job.LastError = lastError
+	})
+}
+
+func (m *JobRepository) Bury(ctx context.Context, id uint, lastError string) error {
+	return m.update(id, func(job *models.Job) {
+		job.Status = models.JobStatusDead
+		job.LockedUntil = nil
+		job.LastError = lastError
+	})
+}
+
+// Requeue runs a dead job again from runAt with its attempts reset
+func (m *JobRepository) Requeue(ctx context.Context, id uint, runAt time.Time) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	job, ok := m.jobs[id]
+	if !ok || job.Status != models.JobStatusDead {
+		return gorm.ErrRecordNotFound
+	}
+	job.Status = models.JobStatusPending
+	job.Attempts = 0
+	job.RunAt = runAt
+	job.UpdatedAt = time.Now()
+	return nil
+}
+
+func (m *JobRepository) update(id uint, change func(job *models.Job)) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if job, ok := m.jobs[id]; ok {
+		change(job)
+		job.UpdatedAt = time.Now()
+	}
+	return nil
+}
@@ -0,0 +1,68 @@
+package memory
+
+import (
+	"context"
+	"sync"
+
+	"github.com/limistah/wallet-service/internal/models"
+	"gorm.io/gorm"
+)
+
+// JournalEntryRepository is an in-memory repositories.JournalEntryRepository; legs are read from the
+// transaction repository like the preload does
+type JournalEntryRepository struct {
+	mu           sync.RWMutex
+	entries      map[uint]*models.JournalEntry
+	transactions *TransactionRepository
+}
+
+// NewJournalEntryRepository creates an empty journal entry repository reading legs from transactions
+func NewJournalEntryRepository(transactions *TransactionRepository) *JournalEntryRepository {
+	return &JournalEntryRepository{
+		entries:      make(map[uint]*models.JournalEntry),
+		transactions: transactions,
+	}
+}
+
+func (m *JournalEntryRepository) Create(ctx context.Context, entry *models.JournalEntry) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for _, existing := range m.entries {
+		if existing.Reference == entry.Reference {
+			return gorm.ErrDuplicatedKey
+		}
+	}
+	entry.ID = uint(len(m.entries) + 1)
+	stamp(entry, true)
+	stored := clone(entry)
+	stored.Legs = nil
+	m.entries[entry.ID] = stored
+	return nil
+}
+
+func (m *JournalEntryRepository) GetByID(ctx context.Context, id uint) (*models.JournalEntry, error) {
+	m.mu.RLock()
+	entry, ok := m.entries[id]
+	m.mu.RUnlock()
+	if !ok {
+		return nil, gorm.ErrRecordNotFound
+	}
+	loaded := clone(entry)
+	loaded.Legs = m.transactions.journalLegs(id)
+	return loaded, nil
+}
+
+func (m *JournalEntryRepository) GetByReference(ctx context.Context, reference string) (*models.JournalEntry, error) {
+	m.mu.RLock()
+	var id uint
+	for _, entry := range m.entries {
+		if entry.Reference == reference {
+			id = entry.ID
+		}
+	}
+	m.mu.RUnlock()
+	if id == 0 {
+		return nil, gorm.ErrRecordNotFound
+	}
+	return m.GetByID(ctx, id)
+}
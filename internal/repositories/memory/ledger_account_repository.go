@@ -0,0 +1,67 @@
+package memory
+
+import (
+	"context"
+	"sync"
+
+	"github.com/limistah/wallet-service/internal/models"
+	"gorm.io/gorm"
+)
+
+// LedgerAccountRepository is an in-memory repositories.LedgerAccountRepository
+type LedgerAccountRepository struct {
+	mu       sync.RWMutex
+	accounts []models.LedgerAccount
+	wallets  *WalletRepository
+}
+
+// NewLedgerAccountRepository creates an empty ledger account repository loading the wallets of
+// accounts from wallets; without wallets an account keeps the wallet it was created with
+func NewLedgerAccountRepository(wallets *WalletRepository) *LedgerAccountRepository {
+	return &LedgerAccountRepository{wallets: wallets}
+}
+
+// Create adds an account to the chart of ledger accounts, as bootstrapping a database or adding a
+// tenant does
+func (m *LedgerAccountRepository) Create(ctx context.Context, account *models.LedgerAccount) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	account.ID = uint(len(m.accounts) + 1)
+	stamp(account, true)
+	m.accounts = append(m.accounts, *account)
+	return nil
+}
+
+func (m *LedgerAccountRepository) GetByCode(ctx context.Context, code models.LedgerAccountCode, sandbox bool) (*models.LedgerAccount, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	for _, account := range m.accounts {
+		if account.Code == code && account.Sandbox == sandbox {
+			m.loadWallet(ctx, &account)
+			return &account, nil
+		}
+	}
+	return nil, gorm.ErrRecordNotFound
+}
+
+func (m *LedgerAccountRepository) List(ctx context.Context, sandbox bool) ([]models.LedgerAccount, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	var accounts []models.LedgerAccount
+	for _, account := range m.accounts {
+		if account.Sandbox == sandbox {
+			m.loadWallet(ctx, &account)
+			accounts = append(accounts, account)
+		}
+	}
+	return accounts, nil
+}
+
+func (m *LedgerAccountRepository) loadWallet(ctx context.Context, account *models.LedgerAccount) {
+	if m.wallets == nil {
+		return
+	}
+	if wallet, err := m.wallets.GetByID(ctx, account.WalletID); err == nil {
+		account.Wallet = *wallet
+	}
+}
@@ -0,0 +1,104 @@
+package memory
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/limistah/wallet-service/internal/models"
+)
+
+// LoginAttemptRepository is an in-memory repositories.LoginAttemptRepository; attempts are stamped
+// with the clock given to NewLoginAttemptRepository
+type LoginAttemptRepository struct {
+	mu        sync.RWMutex
+	attempts  []models.LoginAttempt
+	idCounter uint
+	now       *time.Time
+}
+
+// NewLoginAttemptRepository creates a login attempt repository stamping attempts with *now, or with
+// the current time when now is nil
+func NewLoginAttemptRepository(now *time.Time) *LoginAttemptRepository {
+	return &LoginAttemptRepository{now: now}
+}
+
+func (m *LoginAttemptRepository) Create(ctx context.Context, attempt *models.LoginAttempt) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.idCounter++
+	attempt.ID = m.idCounter
+	attempt.CreatedAt = time.Now()
+	if m.now != nil {
+		attempt.CreatedAt = *m.now
+	}
+	m.attempts = append(m.attempts, *attempt)
+	return nil
+}
+
+func (m *LoginAttemptRepository) CountFailuresByIP(ctx context.Context, ipAddress string, since time.Time) (int64, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	var count int64
+	for _, attempt := range m.attempts {
+		if attempt.IPAddress == ipAddress && !attempt.Succeeded && !attempt.CreatedAt.Before(since) {
+			count++
+		}
+	}
+	return count, nil
+}
+
+// ListByUser returns the latest attempts of a user first
+func (m *LoginAttemptRepository) ListByUser(ctx context.Context, userID uint, limit int) ([]models.LoginAttempt, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	var attempts []models.LoginAttempt
+	for i := len(m.attempts) - 1; i >= 0; i-- {
+		if m.attempts[i].UserID != nil && *m.attempts[i].UserID == userID {
+			attempts = append(attempts, m.attempts[i])
+		}
+	}
+	return page(attempts, 0, limit), nil
+}
+
+func (m *LoginAttemptRepository) HasSucceeded(ctx context.Context, userID uint) (bool, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	for _, attempt := range m.attempts {
+		if attempt.UserID != nil && *attempt.UserID == userID && attempt.Succeeded {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+func (m *LoginAttemptRepository) HasSucceededWithUserAgent(ctx context.Context, userID uint, userAgent string) (bool, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	for _, attempt := range m.attempts {
+		if attempt.UserID != nil && *attempt.UserID == userID && attempt.Succeeded && attempt.UserAgent == userAgent {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+func (m *LoginAttemptRepository) DeleteByUser(ctx context.Context, userID uint) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	kept := m.attempts[:0]
+	for _, attempt := range m.attempts {
+		if attempt.UserID == nil || *attempt.UserID != userID {
+			kept = append(kept, attempt)
+		}
+	}
+	m.attempts = kept
+	return nil
+}
+
+// Attempts returns the recorded login attempts, oldest first
+func (m *LoginAttemptRepository) Attempts() []models.LoginAttempt {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return append([]models.LoginAttempt(nil), m.attempts...)
+}
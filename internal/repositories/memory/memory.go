@@ -0,0 +1,86 @@
+// Package memory implements the repositories in memory, for demos and short-lived environments run
+// without a database (DB_DRIVER=memory) and for tests. The repositories are safe for concurrent use.
+// Records are copied in and out, so a record returned by a repository does not change until it is
+// read again. Nothing is kept across restarts
+package memory
+
+import (
+	"reflect"
+	"regexp"
+	"sort"
+	"time"
+)
+
+// clone copies a record so the caller and the repository do not share it
+func clone[T any](record *T) *T {
+	copied := *record
+	return &copied
+}
+
+// page returns the records from offset on, at most limit of them; a limit of zero or less returns the
+// rest of them
+func page[T any](records []T, offset, limit int) []T {
+	if offset > len(records) {
+		return records[:0]
+	}
+	if offset > 0 {
+		records = records[offset:]
+	}
+	if limit > 0 && len(records) > limit {
+		records = records[:limit]
+	}
+	return records
+}
+
+// inOrder returns the records of a map keyed by ID in ID order, the order of a table without ORDER BY
+func inOrder[T any](records map[uint]*T) []*T {
+	ids := make([]uint, 0, len(records))
+	for id := range records {
+		ids = append(ids, id)
+	}
+	sort.Slice(ids, func(i, j int) bool { return ids[i] < ids[j] })
+	ordered := make([]*T, len(ids))
+	for i, id := range ids {
+		ordered[i] = records[id]
+	}
+	return ordered
+}
+
+// sortStable sorts records by less, leaving records that compare equal in the order they were in
+func sortStable[T any](records []T, less func(a, b T) bool) {
+	sort.SliceStable(records, func(i, j int) bool { return less(records[i], records[j]) })
+}
+
+// sameDay reports whether two times fall on the same calendar day, as DATE columns compare
+func sameDay(a, b time.Time) bool {
+	return a.Format(time.DateOnly) == b.Format(time.DateOnly)
+}
+
+var (
+	timeType = reflect.TypeOf(time.Time{})
+	// columnDefault matches the quoted default of a column in a gorm tag, such as default:'PENDING'
+	columnDefault = regexp.MustCompile(`default:'([^']*)'`)
+)
+
+// stamp sets the timestamps of a record like gorm saving it: CreatedAt and UpdatedAt when they are
+// zero on create, and UpdatedAt on every later save. On create, empty text columns with a default,
+// such as statuses, are given it as the database would
+func stamp(record any, create bool) {
+	value := reflect.ValueOf(record).Elem()
+	if create {
+		for i := 0; i < value.NumField(); i++ {
+			field := value.Field(i)
+			match := columnDefault.FindStringSubmatch(value.Type().Field(i).Tag.Get("gorm"))
+			if match != nil && field.Kind() == reflect.String && field.String() == "" {
+				field.SetString(match[1])
+			}
+		}
+	}
+	now := reflect.ValueOf(time.Now())
+	if field := value.FieldByName("CreatedAt"); create && field.IsValid() && field.Type() == timeType && field.IsZero() {
+		field.Set(now)
+	}
+	if field := value.FieldByName("UpdatedAt"); field.IsValid() && field.Type() == timeType && (!create || field.IsZero()) {
+		field.Set(now)
+	}
+}
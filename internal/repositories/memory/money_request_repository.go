@@ -0,0 +1,122 @@
+package memory
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/limistah/wallet-service/internal/models"
+	"gorm.io/gorm"
+)
+
+// MoneyRequestRepository is an in-memory repositories.MoneyRequestRepository
+type MoneyRequestRepository struct {
+	mu        sync.RWMutex
+	requests  map[uint]*models.MoneyRequest
+	idCounter uint
+	// users fills in the requester and payer of the requests read, when set
+	users *UserRepository
+}
+
+// NewMoneyRequestRepository creates an empty money request repository
+func NewMoneyRequestRepository() *MoneyRequestRepository {
+	return &MoneyRequestRepository{
+		requests: make(map[uint]*models.MoneyRequest),
+	}
+}
+
+func (m *MoneyRequestRepository) Create(ctx context.Context, request *models.MoneyRequest) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.idCounter++
+	request.ID = m.idCounter
+	stamp(request, true)
+	m.requests[request.ID] = clone(request)
+	return nil
+}
+
+func (m *MoneyRequestRepository) GetByID(ctx context.Context, id uint) (*models.MoneyRequest, error) {
+	m.mu.RLock()
+	request, ok := m.requests[id]
+	if ok {
+		request = clone(request)
+	}
+	m.mu.RUnlock()
+	if !ok {
+		return nil, gorm.ErrRecordNotFound
+	}
+	m.preload(request)
+	return request, nil
+}
+
+func (m *MoneyRequestRepository) UpdateStatus(ctx context.Context, id uint, from, to models.MoneyRequestStatus) (bool, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	request, ok := m.requests[id]
+	if !ok || request.Status != from {
+		return false, nil
+	}
+	request.Status = to
+	if from == models.MoneyRequestStatusPending {
+		now := time.Now()
+		request.RespondedAt = &now
+	}
+	if to == models.MoneyRequestStatusPending {
+		request.RespondedAt = nil
+	}
+	request.UpdatedAt = time.Now()
+	return true, nil
+}
+
+func (m *MoneyRequestRepository) SetTransaction(ctx context.Context, id, transactionID uint) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if request, ok := m.requests[id]; ok {
+		request.TransactionID = &transactionID
+		request.UpdatedAt = time.Now()
+	}
+	return nil
+}
+
+// ListByUser returns the requests newest first
+func (m *MoneyRequestRepository) ListByUser(ctx context.Context, userID uint, direction models.MoneyRequestDirection, status models.MoneyRequestStatus, offset, limit int) ([]models.MoneyRequest, error) {
+	m.mu.RLock()
+	requests := make([]models.MoneyRequest, 0)
+	for _, request := range inOrder(m.requests) {
+		switch direction {
+		case models.MoneyRequestIncoming:
+			if request.PayerID != userID {
+				continue
+			}
+		case models.MoneyRequestOutgoing:
+			if request.RequesterID != userID {
+				continue
+			}
+		default:
+			if request.PayerID != userID && request.RequesterID != userID {
+				continue
+			}
+		}
+		if status != "" && request.Status != status {
+			continue
+		}
+		requests = append(requests, *request)
+	}
+	m.mu.RUnlock()
+	sortStable(requests, func(a, b models.MoneyRequest) bool {
+		return a.CreatedAt.After(b.CreatedAt) || (a.CreatedAt.Equal(b.CreatedAt) && a.ID > b.ID)
+	})
+	requests = page(requests, offset, limit)
+	for i := range requests {
+		m.preload(&requests[i])
+	}
+	return requests, nil
+}
+
+// preload fills in the requester and payer of a request read
+func (m *MoneyRequestRepository) preload(request *models.MoneyRequest) {
+	if m.users != nil {
+		request.Requester = m.users.load(request.RequesterID)
+		request.Payer = m.users.load(request.PayerID)
+	}
+}
@@ -0,0 +1,55 @@
+package memory
+
+import (
+	"context"
+	"sync"
+
+	"github.com/limistah/wallet-service/internal/models"
+	"gorm.io/gorm"
+)
+
+// NotificationPreferenceRepository is an in-memory repositories.NotificationPreferenceRepository
+type NotificationPreferenceRepository struct {
+	mu          sync.RWMutex
+	preferences map[uint]*models.NotificationPreference
+	idCounter   uint
+}
+
+// NewNotificationPreferenceRepository creates an empty notification preference repository
+func NewNotificationPreferenceRepository() *NotificationPreferenceRepository {
+	return &NotificationPreferenceRepository{
+		preferences: make(map[uint]*models.NotificationPreference),
+	}
+}
+
+func (m *NotificationPreferenceRepository) GetByUserID(ctx context.Context, userID uint) (*models.NotificationPreference, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	for _, preference := range m.preferences {
+		if preference.UserID == userID {
+			return clone(preference), nil
+		}
+	}
+	return nil, gorm.ErrRecordNotFound
+}
+
+// Save creates the preferences of a user the first time and replaces them after; a user has one set
+func (m *NotificationPreferenceRepository) Save(ctx context.Context, preference *models.NotificationPreference) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if preference.ID != 0 {
+		stamp(preference, false)
+		m.preferences[preference.ID] = clone(preference)
+		return nil
+	}
+	for _, stored := range m.preferences {
+		if stored.UserID == preference.UserID {
+			return gorm.ErrDuplicatedKey
+		}
+	}
+	m.idCounter++
+	preference.ID = m.idCounter
+	stamp(preference, true)
+	m.preferences[preference.ID] = clone(preference)
+	return nil
+}
@@ -0,0 +1,74 @@
+package memory
+
+import (
+	"context"
+	"sync"
+
+	"github.com/limistah/wallet-service/internal/models"
+)
+
+// PasswordHistoryRepository is an in-memory repositories.PasswordHistoryRepository
+type PasswordHistoryRepository struct {
+	mu        sync.RWMutex
+	entries   []models.PasswordHistory
+	idCounter uint
+}
+
+// NewPasswordHistoryRepository creates an empty password history repository
+func NewPasswordHistoryRepository() *PasswordHistoryRepository {
+	return &PasswordHistoryRepository{}
+}
+
+func (m *PasswordHistoryRepository) Create(ctx context.Context, entry *models.PasswordHistory) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.idCounter++
+	entry.ID = m.idCounter
+	stamp(entry, true)
+	m.entries = append(m.entries, *entry)
+	return nil
+}
+
+// ListRecent returns the latest replaced passwords of a user, newest first
+func (m *PasswordHistoryRepository) ListRecent(ctx context.Context, userID uint, limit int) ([]models.PasswordHistory, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return m.recent(userID, limit), nil
+}
+
+func (m *PasswordHistoryRepository) recent(userID uint, limit int) []models.PasswordHistory {
+	var entries []models.PasswordHistory
+	for i := len(m.entries) - 1; i >= 0; i-- {
+		if m.entries[i].UserID == userID {
+			entries = append(entries, m.entries[i])
+		}
+	}
+	return page(entries, 0, limit)
+}
+
+// Prune deletes the replaced passwords of a user beyond the latest keep
+func (m *PasswordHistoryRepository) Prune(ctx context.Context, userID uint, keep int) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	kept := make(map[uint]bool)
+	if keep > 0 {
+		for _, entry := range m.recent(userID, keep) {
+			kept[entry.ID] = true
+		}
+	}
+	var entries []models.PasswordHistory
+	for _, entry := range m.entries {
+		if entry.UserID != userID || kept[entry.ID] {
+			entries = append(entries, entry)
+		}
+	}
+	m.entries = entries
+	return nil
+}
+
+// Entries returns the stored password hashes, oldest first
+func (m *PasswordHistoryRepository) Entries() []models.PasswordHistory {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return append([]models.PasswordHistory(nil), m.entries...)
+}
@@ -0,0 +1,98 @@
+package memory
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/limistah/wallet-service/internal/models"
+	"gorm.io/gorm"
+)
+
+// PaymentApprovalRepository is an in-memory repositories.PaymentApprovalRepository
+type PaymentApprovalRepository struct {
+	mu        sync.RWMutex
+	approvals map[uint]*models.PaymentApproval
+	idCounter uint
+}
+
+// NewPaymentApprovalRepository creates an empty payment approval repository
+func NewPaymentApprovalRepository() *PaymentApprovalRepository {
+	return &PaymentApprovalRepository{
+		approvals: make(map[uint]*models.PaymentApproval),
+	}
+}
+
+func (m *PaymentApprovalRepository) Create(ctx context.Context, approval *models.PaymentApproval) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for _, stored := range m.approvals {
+		if stored.Reference == approval.Reference {
+			return gorm.ErrDuplicatedKey
+		}
+	}
+	m.idCounter++
+	approval.ID = m.idCounter
+	stamp(approval, true)
+	m.approvals[approval.ID] = clone(approval)
+	return nil
+}
+
+func (m *PaymentApprovalRepository) GetByID(ctx context.Context, id uint) (*models.PaymentApproval, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	if approval, ok := m.approvals[id]; ok {
+		return clone(approval), nil
+	}
+	return nil, gorm.ErrRecordNotFound
+}
+
+func (m *PaymentApprovalRepository) GetByReference(ctx context.Context, reference string) (*models.PaymentApproval, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	for _, approval := range m.approvals {
+		if approval.Reference == reference {
+			return clone(approval), nil
+		}
+	}
+	return nil, gorm.ErrRecordNotFound
+}
+
+// ListByBusinessAccount returns the approvals of an account newest first
+func (m *PaymentApprovalRepository) ListByBusinessAccount(ctx context.Context, accountID uint, status models.PaymentApprovalStatus, offset, limit int) ([]models.PaymentApproval, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	approvals := make([]models.PaymentApproval, 0)
+	for _, approval := range inOrder(m.approvals) {
+		if approval.BusinessAccountID == accountID && (status == "" || approval.Status == status) {
+			approvals = append(approvals, *approval)
+		}
+	}
+	sortStable(approvals, func(a, b models.PaymentApproval) bool {
+		return a.CreatedAt.After(b.CreatedAt) || (a.CreatedAt.Equal(b.CreatedAt) && a.ID > b.ID)
+	})
+	return page(approvals, offset, limit), nil
+}
+
+func (m *PaymentApprovalRepository) Decide(ctx context.Context, id uint, status models.PaymentApprovalStatus, approverID uint, note string, at time.Time) (bool, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	approval, ok := m.approvals[id]
+	if !ok || approval.Status != models.PaymentApprovalStatusPending {
+		return false, nil
+	}
+	approval.Status = status
+	approval.ApproverID = &approverID
+	approval.DecisionNote = note
+	approval.DecidedAt = &at
+	approval.UpdatedAt = time.Now()
+	return true, nil
+}
+
+func (m *PaymentApprovalRepository) Update(ctx context.Context, approval *models.PaymentApproval) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	stamp(approval, false)
+	m.approvals[approval.ID] = clone(approval)
+	return nil
+}
@@ -0,0 +1,122 @@
+package memory
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/limistah/wallet-service/internal/models"
+	"github.com/shopspring/decimal"
+	"gorm.io/gorm"
+)
+
+// PaymentLinkRepository is an in-memory repositories.PaymentLinkRepository
+type PaymentLinkRepository struct {
+	mu        sync.RWMutex
+	links     map[uint]*models.PaymentLink
+	idCounter uint
+	// users fills in the recipient of the links read by token, when set
+	users *UserRepository
+}
+
+// NewPaymentLinkRepository creates an empty payment link repository
+func NewPaymentLinkRepository() *PaymentLinkRepository {
+	return &PaymentLinkRepository{
+		links: make(map[uint]*models.PaymentLink),
+	}
+}
+
+func (m *PaymentLinkRepository) Create(ctx context.Context, link *models.PaymentLink) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for _, stored := range m.links {
+		if stored.Token == link.Token {
+			return gorm.ErrDuplicatedKey
+		}
+	}
+	m.idCounter++
+	link.ID = m.idCounter
+	stamp(link, true)
+	m.links[link.ID] = clone(link)
+	return nil
+}
+
+func (m *PaymentLinkRepository) GetByID(ctx context.Context, id uint) (*models.PaymentLink, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	if link, ok := m.links[id]; ok {
+		return clone(link), nil
+	}
+	return nil, gorm.ErrRecordNotFound
+}
+
+func (m *PaymentLinkRepository) GetByToken(ctx context.Context, token string) (*models.PaymentLink, error) {
+	m.mu.RLock()
+	var found *models.PaymentLink
+	for _, link := range m.links {
+		if link.Token == token {
+			found = clone(link)
+			break
+		}
+	}
+	m.mu.RUnlock()
+	if found == nil {
+		return nil, gorm.ErrRecordNotFound
+	}
+	if m.users != nil {
+		found.User = m.users.load(found.UserID)
+	}
+	return found, nil
+}
+
+// ListByUserID returns the links of a user newest first
+func (m *PaymentLinkRepository) ListByUserID(ctx context.Context, userID uint, offset, limit int) ([]models.PaymentLink, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	links := make([]models.PaymentLink, 0)
+	for _, link := range inOrder(m.links) {
+		if link.UserID == userID {
+			links = append(links, *link)
+		}
+	}
+	sortStable(links, func(a, b models.PaymentLink) bool {
+		return a.CreatedAt.After(b.CreatedAt) || (a.CreatedAt.Equal(b.CreatedAt) && a.ID > b.ID)
+	})
+	return page(links, offset, limit), nil
+}
+
+func (m *PaymentLinkRepository) IncrementViews(ctx context.Context, id uint) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if link, ok := m.links[id]; ok {
+		link.ViewCount++
+	}
+	return nil
+}
+
+func (m *PaymentLinkRepository) UpdateStatus(ctx context.Context, id uint, from, to models.PaymentLinkStatus) (bool, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	link, ok := m.links[id]
+	if !ok || link.Status != from {
+		return false, nil
+	}
+	link.Status = to
+	link.UpdatedAt = time.Now()
+	return true, nil
+}
+
+func (m *PaymentLinkRepository) RecordPayment(ctx context.Context, id uint, amount decimal.Decimal) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	link, ok := m.links[id]
+	if !ok {
+		return nil
+	}
+	now := time.Now()
+	link.PaymentCount++
+	link.TotalCollected = link.TotalCollected.Add(amount)
+	link.LastPaidAt = &now
+	link.UpdatedAt = now
+	return nil
+}
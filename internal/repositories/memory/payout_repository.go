@@ -0,0 +1,130 @@
+package memory
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/limistah/wallet-service/internal/models"
+	"gorm.io/gorm"
+)
+
+// PayoutRepository is an in-memory repositories.PayoutRepository
+type PayoutRepository struct {
+	mu        sync.RWMutex
+	payouts   map[uint]*models.Payout
+	idCounter uint
+}
+
+// NewPayoutRepository creates an empty payout repository
+func NewPayoutRepository() *PayoutRepository {
+	return &PayoutRepository{
+		payouts: make(map[uint]*models.Payout),
+	}
+}
+
+func (m *PayoutRepository) Create(ctx context.Context, payout *models.Payout) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.idCounter++
+	payout.ID = m.idCounter
+	stamp(payout, true)
+	m.payouts[payout.ID] = clone(payout)
+	return nil
+}
+
+func (m *PayoutRepository) GetByReference(ctx context.Context, reference string) (*models.Payout, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	for _, payout := range m.payouts {
+		if payout.Reference == reference {
+			return clone(payout), nil
+		}
+	}
+	return nil, gorm.ErrRecordNotFound
+}
+
+func (m *PayoutRepository) GetByProviderReference(ctx context.Context, provider, providerReference string) (*models.Payout, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	for _, payout := range m.payouts {
+		if payout.Provider == provider && payout.ProviderReference == providerReference {
+			return clone(payout), nil
+		}
+	}
+	return nil, gorm.ErrRecordNotFound
+}
+
+func (m *PayoutRepository) UpdateProviderReference(ctx context.Context, id uint, providerReference string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if payout, ok := m.payouts[id]; ok {
+		payout.ProviderReference = providerReference
+	}
+	return nil
+}
+
+func (m *PayoutRepository) ListUnbatched(ctx context.Context, from, to time.Time) ([]models.Payout, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	var payouts []models.Payout
+	for _, payout := range inOrder(m.payouts) {
+		if payout.Status == models.PayoutStatusCompleted && payout.SettlementBatchID == nil && payout.CompletedAt != nil &&
+			!payout.CompletedAt.Before(from) && payout.CompletedAt.Before(to) {
+			payouts = append(payouts, *payout)
+		}
+	}
+	return payouts, nil
+}
+
+func (m *PayoutRepository) GetByTransactionID(ctx context.Context, transactionID uint) (*models.Payout, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	for _, payout := range inOrder(m.payouts) {
+		if payout.TransactionID == transactionID {
+			return clone(payout), nil
+		}
+	}
+	return nil, gorm.ErrRecordNotFound
+}
+
+func (m *PayoutRepository) Settle(ctx context.Context, id uint, status models.PayoutStatus, failureReason string, at time.Time) (bool, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	payout, ok := m.payouts[id]
+	if !ok || !payout.IsPending() {
+		return false, nil
+	}
+	payout.Status = status
+	payout.FailureReason = failureReason
+	payout.CompletedAt = &at
+	stamp(payout, false)
+	return true, nil
+}
+
+func (m *PayoutRepository) AssignToBatch(ctx context.Context, ids []uint, batchID uint) (int, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	assigned := 0
+	for _, id := range ids {
+		if payout, ok := m.payouts[id]; ok && payout.SettlementBatchID == nil {
+			payout.SettlementBatchID = &batchID
+			stamp(payout, false)
+			assigned++
+		}
+	}
+	return assigned, nil
+}
+
+// inBatch returns the payouts a settlement batch holds in ID order
+func (m *PayoutRepository) inBatch(batchID uint) []models.Payout {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	var payouts []models.Payout
+	for _, payout := range inOrder(m.payouts) {
+		if payout.SettlementBatchID != nil && *payout.SettlementBatchID == batchID {
+			payouts = append(payouts, *payout)
+		}
+	}
+	return payouts
+}
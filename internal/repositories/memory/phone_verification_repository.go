@@ -0,0 +1,55 @@
+package memory
+
+import (
+	"context"
+	"sync"
+
+	"github.com/limistah/wallet-service/internal/models"
+	"gorm.io/gorm"
+)
+
+// PhoneVerificationRepository is an in-memory repositories.PhoneVerificationRepository
+type PhoneVerificationRepository struct {
+	mu            sync.RWMutex
+	verifications map[uint]*models.PhoneVerification
+	idCounter     uint
+}
+
+// NewPhoneVerificationRepository creates an empty phone verification repository
+func NewPhoneVerificationRepository() *PhoneVerificationRepository {
+	return &PhoneVerificationRepository{
+		verifications: make(map[uint]*models.PhoneVerification),
+	}
+}
+
+func (m *PhoneVerificationRepository) Create(ctx context.Context, verification *models.PhoneVerification) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.idCounter++
+	verification.ID = m.idCounter
+	stamp(verification, true)
+	m.verifications[verification.ID] = clone(verification)
+	return nil
+}
+
+func (m *PhoneVerificationRepository) GetLatestByUserID(ctx context.Context, userID uint) (*models.PhoneVerification, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	var latest *models.PhoneVerification
+	for _, verification := range inOrder(m.verifications) {
+		if verification.UserID == userID && (latest == nil || !verification.CreatedAt.Before(latest.CreatedAt)) {
+			latest = verification
+		}
+	}
+	if latest == nil {
+		return nil, gorm.ErrRecordNotFound
+	}
+	return clone(latest), nil
+}
+
+func (m *PhoneVerificationRepository) Update(ctx context.Context, verification *models.PhoneVerification) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.verifications[verification.ID] = clone(verification)
+	return nil
+}
@@ -0,0 +1,85 @@
+package memory
+
+import (
+	"context"
+	"sync"
+
+	"github.com/limistah/wallet-service/internal/models"
+	"gorm.io/gorm"
+)
+
+// ProviderStatementRepository is an in-memory repositories.ProviderStatementRepository; lines are
+// kept in the line repository like the association save does
+type ProviderStatementRepository struct {
+	mu         sync.RWMutex
+	statements map[uint]*models.ProviderStatement
+	lines      *StatementLineRepository
+}
+
+// NewProviderStatementRepository creates an empty provider statement repository keeping lines in lines
+func NewProviderStatementRepository(lines *StatementLineRepository) *ProviderStatementRepository {
+	return &ProviderStatementRepository{
+		statements: make(map[uint]*models.ProviderStatement),
+		lines:      lines,
+	}
+}
+
+func (m *ProviderStatementRepository) Create(ctx context.Context, statement *models.ProviderStatement) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for _, existing := range m.statements {
+		if existing.Provider == statement.Provider && existing.Checksum == statement.Checksum {
+			return gorm.ErrDuplicatedKey
+		}
+	}
+	statement.ID = uint(len(m.statements) + 1)
+	stamp(statement, true)
+	for i := range statement.Lines {
+		statement.Lines[i].StatementID = statement.ID
+		m.lines.Create(&statement.Lines[i])
+	}
+	stored := clone(statement)
+	stored.Lines = nil
+	m.statements[statement.ID] = stored
+	return nil
+}
+
+// GetByID loads a statement with its lines in file order
+func (m *ProviderStatementRepository) GetByID(ctx context.Context, id uint) (*models.ProviderStatement, error) {
+	m.mu.RLock()
+	statement, ok := m.statements[id]
+	m.mu.RUnlock()
+	if !ok {
+		return nil, gorm.ErrRecordNotFound
+	}
+	loaded := clone(statement)
+	loaded.Lines = m.lines.ofStatement(id)
+	return loaded, nil
+}
+
+func (m *ProviderStatementRepository) GetByChecksum(ctx context.Context, provider, checksum string) (*models.ProviderStatement, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	for _, statement := range m.statements {
+		if statement.Provider == provider && statement.Checksum == checksum {
+			return clone(statement), nil
+		}
+	}
+	return nil, gorm.ErrRecordNotFound
+}
+
+// List returns statements newest first; an empty provider lists every statement
+func (m *ProviderStatementRepository) List(ctx context.Context, provider string, offset, limit int) ([]models.ProviderStatement, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	var statements []models.ProviderStatement
+	for _, statement := range inOrder(m.statements) {
+		if provider == "" || statement.Provider == provider {
+			statements = append(statements, *statement)
+		}
+	}
+	sortStable(statements, func(a, b models.ProviderStatement) bool {
+		return a.CreatedAt.After(b.CreatedAt) || a.CreatedAt.Equal(b.CreatedAt) && a.ID > b.ID
+	})
+	return page(statements, offset, limit), nil
+}
@@ -0,0 +1,181 @@
+package memory
+
+import (
+	"context"
+	"sort"
+	"sync"
+
+	"github.com/limistah/wallet-service/internal/models"
+	"github.com/limistah/wallet-service/internal/repositories"
+	"github.com/shopspring/decimal"
+	"gorm.io/gorm"
+)
+
+// ReconciliationRepository is an in-memory repositories.ReconciliationRepository
+type ReconciliationRepository struct {
+	mu        sync.RWMutex
+	reports   map[uint]*models.ReconciliationReport
+	idCounter uint
+	// wallets fills in the wallets of the reports read and the currencies of open mismatches, when set
+	wallets *WalletRepository
+}
+
+// NewReconciliationRepository creates an empty reconciliation repository
+func NewReconciliationRepository() *ReconciliationRepository {
+	return &ReconciliationRepository{
+		reports: make(map[uint]*models.ReconciliationReport),
+	}
+}
+
+// Create stores the report, keeping an ID that is already set
+func (m *ReconciliationRepository) Create(ctx context.Context, report *models.ReconciliationReport) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if report.ID == 0 {
+		m.idCounter++
+		report.ID = m.idCounter
+	} else if report.ID > m.idCounter {
+		m.idCounter = report.ID
+	}
+	stamp(report, true)
+	stored := clone(report)
+	stored.Wallet = models.Wallet{}
+	m.reports[report.ID] = stored
+	return nil
+}
+
+func (m *ReconciliationRepository) GetByID(ctx context.Context, id uint) (*models.ReconciliationReport, error) {
+	m.mu.RLock()
+	report, ok := m.reports[id]
+	if ok {
+		report = clone(report)
+	}
+	m.mu.RUnlock()
+	if !ok {
+		return nil, gorm.ErrRecordNotFound
+	}
+	reports := []models.ReconciliationReport{*report}
+	m.preload(ctx, reports)
+	return &reports[0], nil
+}
+
+func (m *ReconciliationRepository) Update(ctx context.Context, report *models.ReconciliationReport) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	stamp(report, false)
+	stored := clone(report)
+	stored.Wallet = models.Wallet{}
+	m.reports[report.ID] = stored
+	return nil
+}
+
+func (m *ReconciliationRepository) GetByWalletID(ctx context.Context, walletID uint) ([]models.ReconciliationReport, error) {
+	return m.list(ctx, func(report *models.ReconciliationReport) bool { return report.WalletID == walletID }, 0, 0), nil
+}
+
+func (m *ReconciliationRepository) List(ctx context.Context, offset, limit int) ([]models.ReconciliationReport, error) {
+	return m.list(ctx, func(*models.ReconciliationReport) bool { return true }, offset, limit), nil
+}
+
+func (m *ReconciliationRepository) GetMismatches(ctx context.Context, offset, limit int) ([]models.ReconciliationReport, error) {
+	return m.list(ctx, func(report *models.ReconciliationReport) bool {
+		return report.Status == models.ReconciliationStatusMismatch
+	}, offset, limit), nil
+}
+
+// ExportReports passes the matching reports to fn in batches of batchSize, in ID order
+func (m *ReconciliationRepository) ExportReports(ctx context.Context, filter repositories.ReconciliationReportFilter, batchSize int, fn func([]models.ReconciliationReport) error) error {
+	m.mu.RLock()
+	reports := make([]models.ReconciliationReport, 0, len(m.reports))
+	for _, report := range inOrder(m.reports) {
+		if filter.Status != "" && report.Status != filter.Status {
+			continue
+		}
+		if filter.From != nil && report.CreatedAt.Before(*filter.From) {
+			continue
+		}
+		if filter.To != nil && !report.CreatedAt.Before(*filter.To) {
+			continue
+		}
+		reports = append(reports, *report)
+	}
+	m.mu.RUnlock()
+	m.preload(ctx, reports)
+	for len(reports) > 0 {
+		batch := page(reports, 0, batchSize)
+		if err := fn(batch); err != nil {
+			return err
+		}
+		reports = reports[len(batch):]
+	}
+	return nil
+}
+
+// OpenMismatches totals, per currency, the wallets whose latest report is not a match
+func (m *ReconciliationRepository) OpenMismatches(ctx context.Context) ([]repositories.OpenMismatchTotal, error) {
+	m.mu.RLock()
+	latest := make(map[uint]*models.ReconciliationReport)
+	for _, report := range m.reports {
+		if current, ok := latest[report.WalletID]; !ok || report.ID > current.ID {
+			latest[report.WalletID] = report
+		}
+	}
+	open := make([]models.ReconciliationReport, 0)
+	for _, report := range latest {
+		if report.Status != models.ReconciliationStatusMatch {
+			open = append(open, *report)
+		}
+	}
+	m.mu.RUnlock()
+	if m.wallets == nil {
+		return nil, nil
+	}
+
+	totals := make(map[string]*repositories.OpenMismatchTotal)
+	for _, report := range open {
+		wallet, err := m.wallets.GetByID(ctx, report.WalletID)
+		if err != nil {
+			continue
+		}
+		total, ok := totals[wallet.Currency]
+		if !ok {
+			total = &repositories.OpenMismatchTotal{Currency: wallet.Currency, Amount: decimal.Zero}
+			totals[wallet.Currency] = total
+		}
+		total.Wallets++
+		total.Amount = total.Amount.Add(report.Difference.Abs())
+	}
+	result := make([]repositories.OpenMismatchTotal, 0, len(totals))
+	for _, total := range totals {
+		result = append(result, *total)
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i].Currency < result[j].Currency })
+	return result, nil
+}
+
+// list returns the reports matching keep newest first with their wallets
+func (m *ReconciliationRepository) list(ctx context.Context, keep func(*models.ReconciliationReport) bool, offset, limit int) []models.ReconciliationReport {
+	m.mu.RLock()
+	reports := make([]models.ReconciliationReport, 0)
+	for _, report := range inOrder(m.reports) {
+		if keep(report) {
+			reports = append(reports, *report)
+		}
+	}
+	m.mu.RUnlock()
+	sortStable(reports, func(a, b models.ReconciliationReport) bool { return a.CreatedAt.After(b.CreatedAt) })
+	reports = page(reports, offset, limit)
+	m.preload(ctx, reports)
+	return reports
+}
+
+func (m *ReconciliationRepository) preload(ctx context.Context, reports []models.ReconciliationReport) {
+	if m.wallets == nil {
+		return
+	}
+	for i := range reports {
+		if wallet, err := m.wallets.GetByID(ctx, reports[i].WalletID); err == nil {
+			reports[i].Wallet = *wallet
+		}
+	}
+}
@@ -0,0 +1,50 @@
+package memory
+
+import (
+	"context"
+	"sync"
+
+	"github.com/limistah/wallet-service/internal/models"
+	"gorm.io/gorm"
+)
+
+// ReconciliationRunRepository is an in-memory repositories.ReconciliationRunRepository
+type ReconciliationRunRepository struct {
+	mu        sync.RWMutex
+	runs      map[uint]*models.ReconciliationRun
+	idCounter uint
+}
+
+// NewReconciliationRunRepository creates an empty reconciliation run repository
+func NewReconciliationRunRepository() *ReconciliationRunRepository {
+	return &ReconciliationRunRepository{
+		runs: make(map[uint]*models.ReconciliationRun),
+	}
+}
+
+func (m *ReconciliationRunRepository) Create(ctx context.Context, run *models.ReconciliationRun) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.idCounter++
+	run.ID = m.idCounter
+	stamp(run, true)
+	m.runs[run.ID] = clone(run)
+	return nil
+}
+
+func (m *ReconciliationRunRepository) GetByID(ctx context.Context, id uint) (*models.ReconciliationRun, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	if run, ok := m.runs[id]; ok {
+		return clone(run), nil
+	}
+	return nil, gorm.ErrRecordNotFound
+}
+
+func (m *ReconciliationRunRepository) Update(ctx context.Context, run *models.ReconciliationRun) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	stamp(run, false)
+	m.runs[run.ID] = clone(run)
+	return nil
+}
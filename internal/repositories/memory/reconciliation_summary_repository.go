@@ -0,0 +1,72 @@
+package memory
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/limistah/wallet-service/internal/models"
+	"gorm.io/gorm"
+)
+
+// ReconciliationSummaryRepository is an in-memory repositories.ReconciliationSummaryRepository
+type ReconciliationSummaryRepository struct {
+	mu        sync.RWMutex
+	summaries map[uint]*models.ReconciliationSummary
+}
+
+// NewReconciliationSummaryRepository creates an empty reconciliation summary repository
+func NewReconciliationSummaryRepository() *ReconciliationSummaryRepository {
+	return &ReconciliationSummaryRepository{
+		summaries: make(map[uint]*models.ReconciliationSummary),
+	}
+}
+
+// Create stores the summary of a day, failing with gorm.ErrDuplicatedKey when the day has one
+func (m *ReconciliationSummaryRepository) Create(ctx context.Context, summary *models.ReconciliationSummary) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for _, existing := range m.summaries {
+		if sameDay(existing.Date, summary.Date) {
+			return gorm.ErrDuplicatedKey
+		}
+	}
+	summary.ID = uint(len(m.summaries) + 1)
+	stamp(summary, true)
+	m.summaries[summary.ID] = clone(summary)
+	return nil
+}
+
+func (m *ReconciliationSummaryRepository) GetByDate(ctx context.Context, date time.Time) (*models.ReconciliationSummary, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	for _, summary := range m.summaries {
+		if sameDay(summary.Date, date) {
+			return clone(summary), nil
+		}
+	}
+	return nil, gorm.ErrRecordNotFound
+}
+
+// List returns summaries latest day first
+func (m *ReconciliationSummaryRepository) List(ctx context.Context, offset, limit int) ([]models.ReconciliationSummary, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	summaries := make([]models.ReconciliationSummary, 0, len(m.summaries))
+	for _, summary := range inOrder(m.summaries) {
+		summaries = append(summaries, *summary)
+	}
+	sortStable(summaries, func(a, b models.ReconciliationSummary) bool { return a.Date.After(b.Date) })
+	return page(summaries, offset, limit), nil
+}
+
+func (m *ReconciliationSummaryRepository) MarkDelivered(ctx context.Context, id uint, at time.Time) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	summary, ok := m.summaries[id]
+	if !ok {
+		return gorm.ErrRecordNotFound
+	}
+	summary.DeliveredAt = &at
+	return nil
+}
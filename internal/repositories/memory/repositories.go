@@ -0,0 +1,194 @@
+package memory
+
+import (
+	"context"
+	"fmt"
+	"log"
+
+	"github.com/limistah/wallet-service/internal/config"
+	"github.com/limistah/wallet-service/internal/models"
+	"github.com/limistah/wallet-service/internal/repositories"
+	"github.com/shopspring/decimal"
+)
+
+// NewRepositories returns empty in-memory repositories that load the associations of the records
+// they return from each other, as gorm preloads them, with a unit of work that runs changes directly
+// against them. A unit of work that fails rolls the repositories back to what they held before it
+func NewRepositories() *repositories.Repositories {
+	users := NewUserRepository()
+	wallets := NewWalletRepository()
+	wallets.users = users
+	transactions := NewTransactionRepository()
+	transactions.wallets = wallets
+	checkpoints := NewBalanceCheckpointRepository()
+	fraudReviews := NewFraudReviewRepository(transactions)
+	amlCases := NewAMLCaseRepository(transactions)
+	transactions.checkpoints = checkpoints
+	transactions.fraudReviews = fraudReviews
+	transactions.amlCases = amlCases
+	reconciliation := NewReconciliationRepository()
+	reconciliation.wallets = wallets
+	moneyRequests := NewMoneyRequestRepository()
+	moneyRequests.users = users
+	paymentLinks := NewPaymentLinkRepository()
+	paymentLinks.users = users
+	businessAccounts := NewBusinessAccountRepository(wallets)
+	businessAccounts.users = users
+	subscriptions := NewSubscriptionRepository()
+	subscriptions.users = users
+	statementLines := NewStatementLineRepository()
+	deposits := NewDepositRepository()
+	payouts := NewPayoutRepository()
+	settlementBatches := NewSettlementBatchRepository()
+	settlementBatches.deposits = deposits
+	settlementBatches.payouts = payouts
+	escrows := NewEscrowRepository()
+	escrows.users = users
+	archivedTransactions := NewArchivedTransactionRepository()
+	purposes := NewTransactionPurposeRepository()
+	purposes.transactions = transactions
+	purposes.archived = archivedTransactions
+	transactions.purposes = purposes
+
+	repos := &repositories.Repositories{
+		User:                    users,
+		Wallet:                  wallets,
+		Transaction:             transactions,
+		TransactionType:         NewTransactionTypeRepository(),
+		TransactionPurpose:      purposes,
+		Reconciliation:          reconciliation,
+		AuditLog:                NewAuditLogRepository(),
+		NotificationPreference:  NewNotificationPreferenceRepository(),
+		PhoneVerification:       NewPhoneVerificationRepository(),
+		DeviceToken:             NewDeviceTokenRepository(),
+		Deposit:                 deposits,
+		Payout:                  payouts,
+		MoneyRequest:            moneyRequests,
+		PaymentLink:             paymentLinks,
+		StandingOrder:           NewStandingOrderRepository(),
+		WalletTier:              NewWalletTierRepository(),
+		FraudReview:             fraudReviews,
+		Blocklist:               NewBlocklistRepository(),
+		ComplianceLog:           NewComplianceLogRepository(),
+		AMLCase:                 amlCases,
+		Job:                     NewJobRepository(),
+		IPAllowlist:             NewIPAllowlistRepository(),
+		RevokedToken:            NewRevokedTokenRepository(),
+		UserIdentity:            NewUserIdentityRepository(),
+		LoginAttempt:            NewLoginAttemptRepository(nil),
+		PasswordHistory:         NewPasswordHistoryRepository(),
+		Session:                 NewSessionRepository(),
+		TransferChallenge:       NewTransferChallengeRepository(),
+		TransferQuote:           NewTransferQuoteRepository(),
+		ReconciliationSummary:   NewReconciliationSummaryRepository(),
+		ReconciliationRun:       NewReconciliationRunRepository(),
+		LedgerAccount:           NewLedgerAccountRepository(wallets),
+		JournalEntry:            NewJournalEntryRepository(transactions),
+		SuspenseItem:            NewSuspenseItemRepository(),
+		BalanceAdjustment:       NewBalanceAdjustmentRepository(),
+		SettlementBatch:         settlementBatches,
+		ProviderStatement:       NewProviderStatementRepository(statementLines),
+		StatementLine:           statementLines,
+		ArchivedTransaction:     archivedTransactions,
+		BalanceCheckpoint:       checkpoints,
+		AccountStatement:        NewAccountStatementRepository(),
+		TransactionStatusChange: NewTransactionStatusChangeRepository(),
+		Budget:                  NewBudgetRepository(),
+		Tenant:                  NewTenantRepository(),
+		BusinessAccount:         businessAccounts,
+		PaymentApproval:         NewPaymentApprovalRepository(),
+		Escrow:                  escrows,
+		Subscription:            subscriptions,
+		Webhook:                 NewWebhookRepository(),
+		DomainEvent:             NewDomainEventRepository(),
+	}
+	repositories.NewInMemoryUnitOfWork(repos)
+	return repos
+}
+
+// Open returns in-memory repositories holding what a freshly migrated database is bootstrapped with:
+// the default tenant, the system account and its ledger accounts, the transaction types and purposes
+// (which their repositories start with), the default wallet tier and, as configured, the sandbox system
+// account and the administrator
+func Open(ctx context.Context, cfg config.AppConfig) (*repositories.Repositories, error) {
+	repos := NewRepositories()
+
+	tenant := &models.Tenant{ID: models.DefaultTenantID, Name: "Default", Slug: models.DefaultTenantSlug, Active: true}
+	if err := repos.Tenant.Create(ctx, tenant); err != nil {
+		return nil, fmt.Errorf("failed to bootstrap default tenant: %v", err)
+	}
+	if err := createSystemAccount(ctx, repos, models.CreateSystemUser(), false); err != nil {
+		return nil, fmt.Errorf("failed to bootstrap system account: %v", err)
+	}
+	tier := &models.WalletTier{
+		Name:                  models.DefaultWalletTierName,
+		Description:           "Default plan with no fees or limits",
+		IsDefault:             true,
+		PaymentLinksEnabled:   true,
+		QRPaymentsEnabled:     true,
+		StandingOrdersEnabled: true,
+	}
+	if err := repos.WalletTier.Create(ctx, tier); err != nil {
+		return nil, fmt.Errorf("failed to bootstrap default wallet tier: %v", err)
+	}
+	if cfg.SandboxEnabled {
+		if err := createSystemAccount(ctx, repos, models.CreateSandboxSystemUser(), true); err != nil {
+			return nil, fmt.Errorf("failed to bootstrap sandbox account: %v", err)
+		}
+	}
+	if cfg.AdminEmail != "" && cfg.AdminPassword != "" {
+		admin := &models.User{Name: "Administrator", Email: cfg.AdminEmail, Role: models.UserRoleAdmin}
+		if err := admin.HashPassword(cfg.AdminPassword); err != nil {
+			return nil, fmt.Errorf("failed to hash admin password: %v", err)
+		}
+		if err := repos.User.Create(ctx, admin); err != nil {
+			return nil, fmt.Errorf("failed to bootstrap admin account: %v", err)
+		}
+	}
+
+	log.Println("In-memory repositories bootstrapped; nothing is kept across restarts")
+	return repos, nil
+}
+
+// createSystemAccount creates a system user with its wallet and the chart of ledger accounts. Cash is
+// the system wallet; every other account gets a wallet of its own, starting empty
+func createSystemAccount(ctx context.Context, repos *repositories.Repositories, systemUser *models.User, sandbox bool) error {
+	if err := systemUser.HashPassword(systemUser.Password); err != nil {
+		return fmt.Errorf("failed to hash system account password: %v", err)
+	}
+	if err := repos.User.Create(ctx, systemUser); err != nil {
+		return fmt.Errorf("failed to create system user: %v", err)
+	}
+	systemWallet := &models.Wallet{
+		UserID:   systemUser.ID,
+		Balance:  models.SystemWalletOpeningBalance,
+		Currency: "USD",
+		Status:   models.WalletStatusActive,
+		Sandbox:  sandbox,
+	}
+	if err := repos.Wallet.Create(ctx, systemWallet); err != nil {
+		return fmt.Errorf("failed to create system wallet: %v", err)
+	}
+
+	for _, account := range models.DefaultLedgerAccounts() {
+		wallet := systemWallet
+		if account.Code != models.LedgerAccountCash {
+			wallet = &models.Wallet{
+				UserID:   systemUser.ID,
+				Balance:  decimal.Zero,
+				Currency: "USD",
+				Status:   models.WalletStatusActive,
+				Sandbox:  sandbox,
+			}
+			if err := repos.Wallet.Create(ctx, wallet); err != nil {
+				return fmt.Errorf("failed to create wallet of ledger account %s: %v", account.Code, err)
+			}
+		}
+		account.Sandbox = sandbox
+		account.WalletID = wallet.ID
+		if err := repos.LedgerAccount.Create(ctx, &account); err != nil {
+			return fmt.Errorf("failed to create ledger account %s: %v", account.Code, err)
+		}
+	}
+	return nil
+}
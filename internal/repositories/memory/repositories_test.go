@@ -0,0 +1,145 @@
+package memory
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+
+	"github.com/limistah/wallet-service/internal/config"
+	"github.com/limistah/wallet-service/internal/models"
+	"github.com/limistah/wallet-service/internal/repositories"
+	"github.com/shopspring/decimal"
+	"gorm.io/gorm"
+)
+
+func TestOpen(t *testing.T) {
+	ctx := context.Background()
+	repos, err := Open(ctx, config.AppConfig{AdminEmail: "admin@example.com", AdminPassword: "Admin-passw0rd", SandboxEnabled: true})
+	if err != nil {
+		t.Fatalf("Open() error = %v", err)
+	}
+
+	if _, err := repos.Tenant.GetBySlug(ctx, models.DefaultTenantSlug); err != nil {
+		t.Errorf("Expected the default tenant, got %v", err)
+	}
+	for _, sandbox := range []bool{false, true} {
+		cash, err := repos.LedgerAccount.GetByCode(ctx, models.LedgerAccountCash, sandbox)
+		if err != nil {
+			t.Fatalf("Expected a cash account (sandbox=%v), got %v", sandbox, err)
+		}
+		if !cash.Wallet.Balance.Equal(models.SystemWalletOpeningBalance) || cash.Wallet.Sandbox != sandbox {
+			t.Errorf("Expected cash to be the funded system wallet (sandbox=%v), got %+v", sandbox, cash.Wallet)
+		}
+		accounts, _ := repos.LedgerAccount.List(ctx, sandbox)
+		if len(accounts) != len(models.DefaultLedgerAccounts()) {
+			t.Errorf("Expected the chart of ledger accounts (sandbox=%v), got %d accounts", sandbox, len(accounts))
+		}
+	}
+	if types, _ := repos.TransactionType.List(ctx); len(types) != len(models.DefaultTransactionTypes()) {
+		t.Errorf("Expected the default transaction types, got %d", len(types))
+	}
+	if tier, err := repos.WalletTier.GetDefault(ctx); err != nil || tier.Name != models.DefaultWalletTierName {
+		t.Errorf("Expected the default wallet tier, got %+v, %v", tier, err)
+	}
+	admin, err := repos.User.GetByEmail(ctx, "admin@example.com")
+	if err != nil || !admin.IsAdmin() || admin.CheckPassword("Admin-passw0rd") != nil {
+		t.Errorf("Expected the configured administrator, got %+v, %v", admin, err)
+	}
+
+	if err := repos.RunInTransaction(ctx, func(*gorm.DB) error { return nil }); !errors.Is(err, repositories.ErrNoDatabase) {
+		t.Errorf("Expected database transactions to be unavailable, got %v", err)
+	}
+	if repos.ForTenant(2) != repos {
+		t.Error("Expected the repositories to be shared by every tenant")
+	}
+}
+
+func TestWalletRepository_AdjustBalanceConcurrently(t *testing.T) {
+	ctx := context.Background()
+	wallets := NewWalletRepository()
+	wallet := &models.Wallet{UserID: 1, Balance: decimal.NewFromInt(100)}
+	wallets.Create(ctx, wallet)
+
+	var wg sync.WaitGroup
+	var mu sync.Mutex
+	debited := 0
+	for i := 0; i < 50; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if _, _, err := wallets.AdjustBalance(ctx, wallet.ID, decimal.NewFromInt(-10)); err == nil {
+				mu.Lock()
+				debited++
+				mu.Unlock()
+			} else if !errors.Is(err, gorm.ErrCheckConstraintViolated) {
+				t.Errorf("AdjustBalance() error = %v", err)
+			}
+		}()
+	}
+	wg.Wait()
+
+	stored, _ := wallets.GetByID(ctx, wallet.ID)
+	if debited != 10 || !stored.Balance.IsZero() || stored.Version != 10 {
+		t.Errorf("Expected 10 debits to empty the wallet, got %d debits leaving %s at version %d", debited, stored.Balance, stored.Version)
+	}
+	if wallet.Status != models.WalletStatusActive || wallet.Currency != "USD" {
+		t.Errorf("Expected the column defaults to be applied, got %s %s", wallet.Status, wallet.Currency)
+	}
+}
+
+func TestInMemoryUnitOfWork_RollsBackFailedUnits(t *testing.T) {
+	ctx := context.Background()
+	repos, err := Open(ctx, config.AppConfig{})
+	if err != nil {
+		t.Fatalf("Open() error = %v", err)
+	}
+	wallet := &models.Wallet{UserID: 1, Balance: decimal.NewFromInt(100)}
+	repos.Wallet.Create(ctx, wallet)
+
+	failed := errors.New("failed")
+	err = repos.UnitOfWork.Do(ctx, func(tx *repositories.Repositories) error {
+		if _, _, err := tx.Wallet.AdjustBalance(ctx, wallet.ID, decimal.NewFromInt(50)); err != nil {
+			return err
+		}
+		if err := tx.Transaction.Create(ctx, &models.Transaction{WalletID: wallet.ID, Reference: "TXN-ROLLED-BACK", Amount: decimal.NewFromInt(50)}); err != nil {
+			return err
+		}
+		return failed
+	})
+	if !errors.Is(err, failed) {
+		t.Fatalf("Expected the unit's error, got %v", err)
+	}
+	if stored, _ := repos.Wallet.GetByID(ctx, wallet.ID); !stored.Balance.Equal(decimal.NewFromInt(100)) || stored.Version != 0 {
+		t.Errorf("Expected the balance to be rolled back, got %s at version %d", stored.Balance, stored.Version)
+	}
+	if _, err := repos.Transaction.GetByReference(ctx, "TXN-ROLLED-BACK"); !errors.Is(err, gorm.ErrRecordNotFound) {
+		t.Errorf("Expected the transaction to be rolled back, got %v", err)
+	}
+	next := &models.Transaction{WalletID: wallet.ID, Reference: "TXN-NEXT", Amount: decimal.NewFromInt(1)}
+	repos.Transaction.Create(ctx, next)
+	if next.ID != 1 {
+		t.Errorf("Expected the ID counter to be rolled back, got ID %d", next.ID)
+	}
+
+	// A nested unit that fails rolls back on its own, like a savepoint
+	err = repos.UnitOfWork.Do(ctx, func(tx *repositories.Repositories) error {
+		if _, _, err := tx.Wallet.AdjustBalance(ctx, wallet.ID, decimal.NewFromInt(50)); err != nil {
+			return err
+		}
+		nested := tx.UnitOfWork.Do(ctx, func(tx *repositories.Repositories) error {
+			tx.Wallet.AdjustBalance(ctx, wallet.ID, decimal.NewFromInt(25))
+			return failed
+		})
+		if !errors.Is(nested, failed) {
+			t.Errorf("Expected the nested unit's error, got %v", nested)
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("Do() error = %v", err)
+	}
+	if stored, _ := repos.Wallet.GetByID(ctx, wallet.ID); !stored.Balance.Equal(decimal.NewFromInt(150)) {
+		t.Errorf("Expected only the outer unit's credit to be kept, got %s", stored.Balance)
+	}
+}
@@ -0,0 +1,49 @@
+package memory
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/limistah/wallet-service/internal/models"
+)
+
+// RevokedTokenRepository is an in-memory repositories.RevokedTokenRepository
+type RevokedTokenRepository struct {
+	mu     sync.RWMutex
+	tokens map[string]models.RevokedToken
+}
+
+// NewRevokedTokenRepository creates an empty revoked token repository
+func NewRevokedTokenRepository() *RevokedTokenRepository {
+	return &RevokedTokenRepository{tokens: make(map[string]models.RevokedToken)}
+}
+
+func (m *RevokedTokenRepository) Create(ctx context.Context, token *models.RevokedToken) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	stamp(token, true)
+	m.tokens[token.TokenID] = *token
+	return nil
+}
+
+func (m *RevokedTokenRepository) Exists(ctx context.Context, tokenID string) (bool, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	_, ok := m.tokens[tokenID]
+	return ok, nil
+}
+
+// DeleteExpired removes the revocations of tokens that expired before the given time
+func (m *RevokedTokenRepository) DeleteExpired(ctx context.Context, before time.Time) (int64, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	var deleted int64
+	for id, token := range m.tokens {
+		if token.ExpiresAt.Before(before) {
+			delete(m.tokens, id)
+			deleted++
+		}
+	}
+	return deleted, nil
+}
@@ -0,0 +1,103 @@
+package memory
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/limistah/wallet-service/internal/models"
+	"gorm.io/gorm"
+)
+
+// SessionRepository is an in-memory repositories.SessionRepository
+type SessionRepository struct {
+	mu        sync.RWMutex
+	sessions  map[uint]*models.Session
+	idCounter uint
+}
+
+// NewSessionRepository creates an empty session repository
+func NewSessionRepository() *SessionRepository {
+	return &SessionRepository{sessions: make(map[uint]*models.Session)}
+}
+
+func (m *SessionRepository) Create(ctx context.Context, session *models.Session) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for _, stored := range m.sessions {
+		if stored.TokenID == session.TokenID {
+			return gorm.ErrDuplicatedKey
+		}
+	}
+	m.idCounter++
+	session.ID = m.idCounter
+	stamp(session, true)
+	m.sessions[session.ID] = clone(session)
+	return nil
+}
+
+func (m *SessionRepository) GetByID(ctx context.Context, id uint) (*models.Session, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	if session, ok := m.sessions[id]; ok {
+		return clone(session), nil
+	}
+	return nil, gorm.ErrRecordNotFound
+}
+
+func (m *SessionRepository) GetByTokenID(ctx context.Context, tokenID string) (*models.Session, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	for _, session := range m.sessions {
+		if session.TokenID == tokenID {
+			return clone(session), nil
+		}
+	}
+	return nil, gorm.ErrRecordNotFound
+}
+
+func (m *SessionRepository) Update(ctx context.Context, session *models.Session) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	stamp(session, false)
+	m.sessions[session.ID] = clone(session)
+	return nil
+}
+
+// ListActive returns the sessions of a user latest first
+func (m *SessionRepository) ListActive(ctx context.Context, userID uint, now time.Time) ([]models.Session, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	var sessions []models.Session
+	for _, session := range inOrder(m.sessions) {
+		if session.UserID == userID && session.RevokedAt == nil && session.ExpiresAt.After(now) {
+			sessions = append(sessions, *session)
+		}
+	}
+	sortStable(sessions, func(a, b models.Session) bool { return a.IssuedAt.After(b.IssuedAt) })
+	return sessions, nil
+}
+
+func (m *SessionRepository) RevokeByTokenID(ctx context.Context, tokenID string, at time.Time) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for _, session := range m.sessions {
+		if session.TokenID == tokenID && session.RevokedAt == nil {
+			session.RevokedAt = &at
+		}
+	}
+	return nil
+}
+
+func (m *SessionRepository) DeleteExpired(ctx context.Context, before time.Time) (int64, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	var deleted int64
+	for id, session := range m.sessions {
+		if session.ExpiresAt.Before(before) {
+			delete(m.sessions, id)
+			deleted++
+		}
+	}
+	return deleted, nil
+}
@@ -0,0 +1,105 @@
+package memory
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/limistah/wallet-service/internal/models"
+	"github.com/limistah/wallet-service/internal/repositories"
+	"gorm.io/gorm"
+)
+
+// SettlementBatchRepository is an in-memory repositories.SettlementBatchRepository
+type SettlementBatchRepository struct {
+	mu        sync.RWMutex
+	batches   map[uint]*models.SettlementBatch
+	idCounter uint
+	// deposits and payouts fill in the items of the batches read by ID, when set; otherwise batches
+	// hold the items they were created with
+	deposits *DepositRepository
+	payouts  *PayoutRepository
+}
+
+// NewSettlementBatchRepository creates an empty settlement batch repository
+func NewSettlementBatchRepository() *SettlementBatchRepository {
+	return &SettlementBatchRepository{
+		batches: make(map[uint]*models.SettlementBatch),
+	}
+}
+
+// Create adds a batch, as the settlement use case does in a database transaction
+func (m *SettlementBatchRepository) Create(batch *models.SettlementBatch) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.idCounter++
+	batch.ID = m.idCounter
+	stamp(batch, true)
+	m.batches[batch.ID] = clone(batch)
+}
+
+func (m *SettlementBatchRepository) GetByID(ctx context.Context, id uint) (*models.SettlementBatch, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	batch, ok := m.batches[id]
+	if !ok {
+		return nil, gorm.ErrRecordNotFound
+	}
+	batch = clone(batch)
+	if m.deposits != nil && m.payouts != nil {
+		batch.Deposits, batch.Payouts = m.deposits.inBatch(id), m.payouts.inBatch(id)
+	}
+	return batch, nil
+}
+
+func (m *SettlementBatchRepository) GetByKey(ctx context.Context, provider, currency string, date time.Time) (*models.SettlementBatch, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	for _, batch := range m.batches {
+		if batch.Provider == provider && batch.Currency == currency && sameDay(batch.Date, date) {
+			return clone(batch), nil
+		}
+	}
+	return nil, gorm.ErrRecordNotFound
+}
+
+// List returns batches newest day first
+func (m *SettlementBatchRepository) List(ctx context.Context, filter repositories.SettlementBatchFilter, offset, limit int) ([]models.SettlementBatch, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	var batches []models.SettlementBatch
+	for _, batch := range inOrder(m.batches) {
+		if (filter.Provider != "" && batch.Provider != filter.Provider) || (filter.Status != "" && batch.Status != filter.Status) ||
+			(filter.From != nil && batch.Date.Before(*filter.From)) || (filter.To != nil && !batch.Date.Before(*filter.To)) {
+			continue
+		}
+		batches = append(batches, *batch)
+	}
+	sortStable(batches, func(a, b models.SettlementBatch) bool {
+		if !a.Date.Equal(b.Date) {
+			return a.Date.After(b.Date)
+		}
+		if a.Provider != b.Provider {
+			return a.Provider < b.Provider
+		}
+		return a.Currency < b.Currency
+	})
+	return page(batches, offset, limit), nil
+}
+
+func (m *SettlementBatchRepository) Update(ctx context.Context, batch *models.SettlementBatch) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if batch.ID == 0 {
+		m.idCounter++
+		batch.ID = m.idCounter
+		stamp(batch, true)
+	}
+	stored := clone(batch)
+	if existing, ok := m.batches[batch.ID]; ok {
+		stored.Deposits, stored.Payouts = existing.Deposits, existing.Payouts
+	}
+	stamp(stored, false)
+	m.batches[batch.ID] = stored
+	return nil
+}
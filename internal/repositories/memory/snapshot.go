@@ -0,0 +1,575 @@
+package memory
+
+import "github.com/limistah/wallet-service/internal/models"
+
+// The repositories implement repositories.Snapshotter: Snapshot saves what a repository holds and
+// returns a function restoring it, so the unit of work can roll back the changes of a unit that fails.
+// Records are copied, since the repositories change stored records in place
+
+// copyRecords copies a map of records along with the records
+func copyRecords[K comparable, T any](records map[K]*T) map[K]*T {
+	copied := make(map[K]*T, len(records))
+	for key, record := range records {
+		copied[key] = clone(record)
+	}
+	return copied
+}
+
+// copyValues copies a map of records kept by value
+func copyValues[K comparable, T any](records map[K]T) map[K]T {
+	copied := make(map[K]T, len(records))
+	for key, record := range records {
+		copied[key] = record
+	}
+	return copied
+}
+
+func (m *AccountStatementRepository) Snapshot() func() {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	statements, idCounter := copyRecords(m.statements), m.idCounter
+	return func() {
+		m.mu.Lock()
+		defer m.mu.Unlock()
+		m.statements, m.idCounter = statements, idCounter
+	}
+}
+
+func (m *AMLCaseRepository) Snapshot() func() {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	cases, idCounter := copyRecords(m.cases), m.idCounter
+	return func() {
+		m.mu.Lock()
+		defer m.mu.Unlock()
+		m.cases, m.idCounter = cases, idCounter
+	}
+}
+
+func (m *ArchivedTransactionRepository) Snapshot() func() {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	transactions := copyRecords(m.transactions)
+	return func() {
+		m.mu.Lock()
+		defer m.mu.Unlock()
+		m.transactions = transactions
+	}
+}
+
+func (m *AuditLogRepository) Snapshot() func() {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	entries, idCounter := append([]models.AuditLog(nil), m.entries...), m.idCounter
+	return func() {
+		m.mu.Lock()
+		defer m.mu.Unlock()
+		m.entries, m.idCounter = entries, idCounter
+	}
+}
+
+func (m *BalanceAdjustmentRepository) Snapshot() func() {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	adjustments, idCounter := copyRecords(m.adjustments), m.idCounter
+	return func() {
+		m.mu.Lock()
+		defer m.mu.Unlock()
+		m.adjustments, m.idCounter = adjustments, idCounter
+	}
+}
+
+func (m *BalanceCheckpointRepository) Snapshot() func() {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	checkpoints, idCounter := copyRecords(m.checkpoints), m.idCounter
+	return func() {
+		m.mu.Lock()
+		defer m.mu.Unlock()
+		m.checkpoints, m.idCounter = checkpoints, idCounter
+	}
+}
+
+func (m *BlocklistRepository) Snapshot() func() {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	entries, idCounter := copyRecords(m.entries), m.idCounter
+	return func() {
+		m.mu.Lock()
+		defer m.mu.Unlock()
+		m.entries, m.idCounter = entries, idCounter
+	}
+}
+
+func (m *BudgetRepository) Snapshot() func() {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	budgets, idCounter := copyRecords(m.budgets), m.idCounter
+	return func() {
+		m.mu.Lock()
+		defer m.mu.Unlock()
+		m.budgets, m.idCounter = budgets, idCounter
+	}
+}
+
+func (m *BusinessAccountRepository) Snapshot() func() {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	accounts, members, idCounter, memberIDCounter := copyRecords(m.accounts), copyRecords(m.members), m.idCounter, m.memberIDCounter
+	return func() {
+		m.mu.Lock()
+		defer m.mu.Unlock()
+		m.accounts, m.members, m.idCounter, m.memberIDCounter = accounts, members, idCounter, memberIDCounter
+	}
+}
+
+func (m *ComplianceLogRepository) Snapshot() func() {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	entries := append([]models.ComplianceLog(nil), m.entries...)
+	return func() {
+		m.mu.Lock()
+		defer m.mu.Unlock()
+		m.entries = entries
+	}
+}
+
+func (m *DepositRepository) Snapshot() func() {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	deposits, idCounter := copyRecords(m.deposits), m.idCounter
+	return func() {
+		m.mu.Lock()
+		defer m.mu.Unlock()
+		m.deposits, m.idCounter = deposits, idCounter
+	}
+}
+
+func (m *DeviceTokenRepository) Snapshot() func() {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	devices, idCounter := copyRecords(m.devices), m.idCounter
+	return func() {
+		m.mu.Lock()
+		defer m.mu.Unlock()
+		m.devices, m.idCounter = devices, idCounter
+	}
+}
+
+func (m *DomainEventRepository) Snapshot() func() {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	events := append([]models.DomainEvent(nil), m.events...)
+	return func() {
+		m.mu.Lock()
+		defer m.mu.Unlock()
+		m.events = events
+	}
+}
+
+func (m *EscrowRepository) Snapshot() func() {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	escrows := copyRecords(m.escrows)
+	return func() {
+		m.mu.Lock()
+		defer m.mu.Unlock()
+		m.escrows = escrows
+	}
+}
+
+func (m *FraudReviewRepository) Snapshot() func() {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	reviews, idCounter := copyRecords(m.reviews), m.idCounter
+	return func() {
+		m.mu.Lock()
+		defer m.mu.Unlock()
+		m.reviews, m.idCounter = reviews, idCounter
+	}
+}
+
+func (m *IPAllowlistRepository) Snapshot() func() {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	entries, idCounter := append([]models.IPAllowlistEntry(nil), m.entries...), m.idCounter
+	return func() {
+		m.mu.Lock()
+		defer m.mu.Unlock()
+		m.entries, m.idCounter = entries, idCounter
+	}
+}
+
+func (m *JobRepository) Snapshot() func() {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	jobs, idCounter := copyRecords(m.jobs), m.idCounter
+	return func() {
+		m.mu.Lock()
+		defer m.mu.Unlock()
+		m.jobs, m.idCounter = jobs, idCounter
+	}
+}
+
+func (m *JournalEntryRepository) Snapshot() func() {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	entries := copyRecords(m.entries)
+	return func() {
+		m.mu.Lock()
+		defer m.mu.Unlock()
+		m.entries = entries
+	}
+}
+
+func (m *LedgerAccountRepository) Snapshot() func() {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	accounts := append([]models.LedgerAccount(nil), m.accounts...)
+	return func() {
+		m.mu.Lock()
+		defer m.mu.Unlock()
+		m.accounts = accounts
+	}
+}
+
+func (m *LoginAttemptRepository) Snapshot() func() {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	attempts, idCounter := append([]models.LoginAttempt(nil), m.attempts...), m.idCounter
+	return func() {
+		m.mu.Lock()
+		defer m.mu.Unlock()
+		m.attempts, m.idCounter = attempts, idCounter
+	}
+}
+
+func (m *MoneyRequestRepository) Snapshot() func() {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	requests, idCounter := copyRecords(m.requests), m.idCounter
+	return func() {
+		m.mu.Lock()
+		defer m.mu.Unlock()
+		m.requests, m.idCounter = requests, idCounter
+	}
+}
+
+func (m *NotificationPreferenceRepository) Snapshot() func() {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	preferences, idCounter := copyRecords(m.preferences), m.idCounter
+	return func() {
+		m.mu.Lock()
+		defer m.mu.Unlock()
+		m.preferences, m.idCounter = preferences, idCounter
+	}
+}
+
+func (m *PasswordHistoryRepository) Snapshot() func() {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	entries, idCounter := append([]models.PasswordHistory(nil), m.entries...), m.idCounter
+	return func() {
+		m.mu.Lock()
+		defer m.mu.Unlock()
+		m.entries, m.idCounter = entries, idCounter
+	}
+}
+
+func (m *PaymentApprovalRepository) Snapshot() func() {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	approvals, idCounter := copyRecords(m.approvals), m.idCounter
+	return func() {
+		m.mu.Lock()
+		defer m.mu.Unlock()
+		m.approvals, m.idCounter = approvals, idCounter
+	}
+}
+
+func (m *PaymentLinkRepository) Snapshot() func() {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	links, idCounter := copyRecords(m.links), m.idCounter
+	return func() {
+		m.mu.Lock()
+		defer m.mu.Unlock()
+		m.links, m.idCounter = links, idCounter
+	}
+}
+
+func (m *PayoutRepository) Snapshot() func() {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	payouts, idCounter := copyRecords(m.payouts), m.idCounter
+	return func() {
+		m.mu.Lock()
+		defer m.mu.Unlock()
+		m.payouts, m.idCounter = payouts, idCounter
+	}
+}
+
+func (m *PhoneVerificationRepository) Snapshot() func() {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	verifications, idCounter := copyRecords(m.verifications), m.idCounter
+	return func() {
+		m.mu.Lock()
+		defer m.mu.Unlock()
+		m.verifications, m.idCounter = verifications, idCounter
+	}
+}
+
+func (m *ProviderStatementRepository) Snapshot() func() {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	statements := copyRecords(m.statements)
+	return func() {
+		m.mu.Lock()
+		defer m.mu.Unlock()
+		m.statements = statements
+	}
+}
+
+func (m *ReconciliationRepository) Snapshot() func() {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	reports, idCounter := copyRecords(m.reports), m.idCounter
+	return func() {
+		m.mu.Lock()
+		defer m.mu.Unlock()
+		m.reports, m.idCounter = reports, idCounter
+	}
+}
+
+func (m *ReconciliationRunRepository) Snapshot() func() {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	runs, idCounter := copyRecords(m.runs), m.idCounter
+	return func() {
+		m.mu.Lock()
+		defer m.mu.Unlock()
+		m.runs, m.idCounter = runs, idCounter
+	}
+}
+
+func (m *ReconciliationSummaryRepository) Snapshot() func() {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	summaries := copyRecords(m.summaries)
+	return func() {
+		m.mu.Lock()
+		defer m.mu.Unlock()
+		m.summaries = summaries
+	}
+}
+
+func (m *RevokedTokenRepository) Snapshot() func() {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	tokens := copyValues(m.tokens)
+	return func() {
+		m.mu.Lock()
+		defer m.mu.Unlock()
+		m.tokens = tokens
+	}
+}
+
+func (m *SessionRepository) Snapshot() func() {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	sessions, idCounter := copyRecords(m.sessions), m.idCounter
+	return func() {
+		m.mu.Lock()
+		defer m.mu.Unlock()
+		m.sessions, m.idCounter = sessions, idCounter
+	}
+}
+
+func (m *SettlementBatchRepository) Snapshot() func() {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	batches, idCounter := copyRecords(m.batches), m.idCounter
+	return func() {
+		m.mu.Lock()
+		defer m.mu.Unlock()
+		m.batches, m.idCounter = batches, idCounter
+	}
+}
+
+func (m *StandingOrderRepository) Snapshot() func() {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	orders, runs, idCounter, runIDCounter := copyRecords(m.orders), copyRecords(m.runs), m.idCounter, m.runIDCounter
+	return func() {
+		m.mu.Lock()
+		defer m.mu.Unlock()
+		m.orders, m.runs, m.idCounter, m.runIDCounter = orders, runs, idCounter, runIDCounter
+	}
+}
+
+func (m *StatementLineRepository) Snapshot() func() {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	lines, idCounter := copyRecords(m.lines), m.idCounter
+	return func() {
+		m.mu.Lock()
+		defer m.mu.Unlock()
+		m.lines, m.idCounter = lines, idCounter
+	}
+}
+
+func (m *SubscriptionRepository) Snapshot() func() {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	plans, subscriptions, charges, planIDCounter, subscriptionIDCounter, chargeIDCounter := copyRecords(m.plans), copyRecords(m.subscriptions), copyRecords(m.charges), m.planIDCounter, m.subscriptionIDCounter, m.chargeIDCounter
+	return func() {
+		m.mu.Lock()
+		defer m.mu.Unlock()
+		m.plans, m.subscriptions, m.charges, m.planIDCounter, m.subscriptionIDCounter, m.chargeIDCounter = plans, subscriptions, charges, planIDCounter, subscriptionIDCounter, chargeIDCounter
+	}
+}
+
+func (m *SuspenseItemRepository) Snapshot() func() {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	items, idCounter := copyRecords(m.items), m.idCounter
+	return func() {
+		m.mu.Lock()
+		defer m.mu.Unlock()
+		m.items, m.idCounter = items, idCounter
+	}
+}
+
+func (m *TenantRepository) Snapshot() func() {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	tenants := append([]models.Tenant(nil), m.tenants...)
+	return func() {
+		m.mu.Lock()
+		defer m.mu.Unlock()
+		m.tenants = tenants
+	}
+}
+
+func (m *TransactionPurposeRepository) Snapshot() func() {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	purposes := copyRecords(m.purposes)
+	return func() {
+		m.mu.Lock()
+		defer m.mu.Unlock()
+		m.purposes = purposes
+	}
+}
+
+func (m *TransactionRepository) Snapshot() func() {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	transactions, idCounter := copyRecords(m.transactions), m.idCounter
+	return func() {
+		m.mu.Lock()
+		defer m.mu.Unlock()
+		m.transactions, m.idCounter = transactions, idCounter
+	}
+}
+
+func (m *TransactionStatusChangeRepository) Snapshot() func() {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	changes := append([]models.TransactionStatusChange(nil), m.changes...)
+	return func() {
+		m.mu.Lock()
+		defer m.mu.Unlock()
+		m.changes = changes
+	}
+}
+
+func (m *TransactionTypeRepository) Snapshot() func() {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	types := copyRecords(m.types)
+	return func() {
+		m.mu.Lock()
+		defer m.mu.Unlock()
+		m.types = types
+	}
+}
+
+func (m *TransferChallengeRepository) Snapshot() func() {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	challenges, idCounter := copyRecords(m.challenges), m.idCounter
+	return func() {
+		m.mu.Lock()
+		defer m.mu.Unlock()
+		m.challenges, m.idCounter = challenges, idCounter
+	}
+}
+
+func (m *TransferQuoteRepository) Snapshot() func() {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	quotes, idCounter := copyRecords(m.quotes), m.idCounter
+	return func() {
+		m.mu.Lock()
+		defer m.mu.Unlock()
+		m.quotes, m.idCounter = quotes, idCounter
+	}
+}
+
+func (m *UserIdentityRepository) Snapshot() func() {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	identities, idCounter := append([]models.UserIdentity(nil), m.identities...), m.idCounter
+	return func() {
+		m.mu.Lock()
+		defer m.mu.Unlock()
+		m.identities, m.idCounter = identities, idCounter
+	}
+}
+
+func (m *UserRepository) Snapshot() func() {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	users, idCounter := copyRecords(m.users), m.idCounter
+	return func() {
+		m.mu.Lock()
+		defer m.mu.Unlock()
+		m.users, m.idCounter = users, idCounter
+	}
+}
+
+func (m *WalletRepository) Snapshot() func() {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	wallets, idCounter := copyRecords(m.wallets), m.idCounter
+	return func() {
+		m.mu.Lock()
+		defer m.mu.Unlock()
+		m.wallets, m.idCounter = wallets, idCounter
+	}
+}
+
+func (m *WalletTierRepository) Snapshot() func() {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	tiers, idCounter := copyRecords(m.tiers), m.idCounter
+	return func() {
+		m.mu.Lock()
+		defer m.mu.Unlock()
+		m.tiers, m.idCounter = tiers, idCounter
+	}
+}
+
+func (m *WebhookRepository) Snapshot() func() {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	subscriptions, deliveries, attempts, subscriptionIDCounter, deliveryIDCounter, attemptIDCounter := copyRecords(m.subscriptions), copyRecords(m.deliveries), copyRecords(m.attempts), m.subscriptionIDCounter, m.deliveryIDCounter, m.attemptIDCounter
+	return func() {
+		m.mu.Lock()
+		defer m.mu.Unlock()
+		m.subscriptions, m.deliveries, m.attempts, m.subscriptionIDCounter, m.deliveryIDCounter, m.attemptIDCounter = subscriptions, deliveries, attempts, subscriptionIDCounter, deliveryIDCounter, attemptIDCounter
+	}
+}
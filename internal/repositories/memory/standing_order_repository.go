@@ -0,0 +1,169 @@
+package memory
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/limistah/wallet-service/internal/models"
+	"gorm.io/gorm"
+)
+
+// StandingOrderRepository is an in-memory repositories.StandingOrderRepository
+type StandingOrderRepository struct {
+	mu           sync.RWMutex
+	orders       map[uint]*models.StandingOrder
+	runs         map[uint]*models.StandingOrderRun
+	idCounter    uint
+	runIDCounter uint
+}
+
+// NewStandingOrderRepository creates an empty standing order repository
+func NewStandingOrderRepository() *StandingOrderRepository {
+	return &StandingOrderRepository{
+		orders: make(map[uint]*models.StandingOrder),
+		runs:   make(map[uint]*models.StandingOrderRun),
+	}
+}
+
+func (m *StandingOrderRepository) Create(ctx context.Context, order *models.StandingOrder) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.idCounter++
+	order.ID = m.idCounter
+	stamp(order, true)
+	m.orders[order.ID] = clone(order)
+	return nil
+}
+
+func (m *StandingOrderRepository) GetByID(ctx context.Context, id uint) (*models.StandingOrder, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	if order, ok := m.orders[id]; ok {
+		return clone(order), nil
+	}
+	return nil, gorm.ErrRecordNotFound
+}
+
+// ListByUserID returns the orders of a user newest first
+func (m *StandingOrderRepository) ListByUserID(ctx context.Context, userID uint) ([]models.StandingOrder, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	orders := make([]models.StandingOrder, 0)
+	for _, order := range inOrder(m.orders) {
+		if order.UserID == userID {
+			orders = append(orders, *order)
+		}
+	}
+	sortStable(orders, func(a, b models.StandingOrder) bool {
+		return a.CreatedAt.After(b.CreatedAt) || (a.CreatedAt.Equal(b.CreatedAt) && a.ID > b.ID)
+	})
+	return orders, nil
+}
+
+// GetDue returns the active orders due by now, the longest overdue first
+func (m *StandingOrderRepository) GetDue(ctx context.Context, now time.Time, limit int) ([]models.StandingOrder, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	orders := make([]models.StandingOrder, 0)
+	for _, order := range inOrder(m.orders) {
+		if order.IsActive() && !order.NextRunAt.After(now) {
+			orders = append(orders, *order)
+		}
+	}
+	sortStable(orders, func(a, b models.StandingOrder) bool { return a.NextRunAt.Before(b.NextRunAt) })
+	return page(orders, 0, limit), nil
+}
+
+func (m *StandingOrderRepository) Advance(ctx context.Context, id uint, from, to time.Time, status models.StandingOrderStatus) (bool, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	order, ok := m.orders[id]
+	if !ok || !order.NextRunAt.Equal(from) || !order.IsActive() {
+		return false, nil
+	}
+	order.NextRunAt = to
+	order.Status = status
+	order.UpdatedAt = time.Now()
+	return true, nil
+}
+
+func (m *StandingOrderRepository) UpdateStatus(ctx context.Context, id uint, status models.StandingOrderStatus) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if order, ok := m.orders[id]; ok {
+		order.Status = status
+		order.UpdatedAt = time.Now()
+	}
+	return nil
+}
+
+func (m *StandingOrderRepository) CreateRun(ctx context.Context, run *models.StandingOrderRun) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for _, stored := range m.runs {
+		if stored.StandingOrderID == run.StandingOrderID && stored.ScheduledFor.Equal(run.ScheduledFor) {
+			return gorm.ErrDuplicatedKey
+		}
+	}
+	m.runIDCounter++
+	run.ID = m.runIDCounter
+	stamp(run, true)
+	stored := clone(run)
+	stored.StandingOrder = models.StandingOrder{}
+	m.runs[run.ID] = stored
+	return nil
+}
+
+// GetDueRuns returns the open runs due for an attempt by now with their orders, the longest overdue
+// first
+func (m *StandingOrderRepository) GetDueRuns(ctx context.Context, now time.Time, limit int) ([]models.StandingOrderRun, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	runs := make([]models.StandingOrderRun, 0)
+	for _, run := range inOrder(m.runs) {
+		if run.IsOpen() && run.NextAttemptAt != nil && !run.NextAttemptAt.After(now) {
+			loaded := *run
+			if order, ok := m.orders[run.StandingOrderID]; ok {
+				loaded.StandingOrder = *order
+			}
+			runs = append(runs, loaded)
+		}
+	}
+	sortStable(runs, func(a, b models.StandingOrderRun) bool { return a.NextAttemptAt.Before(*b.NextAttemptAt) })
+	return page(runs, 0, limit), nil
+}
+
+// UpdateRun saves the progress of a run: its status, attempts, next attempt, error, transaction and
+// completion
+func (m *StandingOrderRepository) UpdateRun(ctx context.Context, run *models.StandingOrderRun) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	stored, ok := m.runs[run.ID]
+	if !ok {
+		return nil
+	}
+	stored.Status = run.Status
+	stored.Attempts = run.Attempts
+	stored.NextAttemptAt = run.NextAttemptAt
+	stored.LastError = run.LastError
+	stored.TransactionID = run.TransactionID
+	stored.CompletedAt = run.CompletedAt
+	stored.UpdatedAt = time.Now()
+	run.UpdatedAt = stored.UpdatedAt
+	return nil
+}
+
+// ListRuns returns the runs of an order latest scheduled first
+func (m *StandingOrderRepository) ListRuns(ctx context.Context, orderID uint, offset, limit int) ([]models.StandingOrderRun, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	runs := make([]models.StandingOrderRun, 0)
+	for _, run := range inOrder(m.runs) {
+		if run.StandingOrderID == orderID {
+			runs = append(runs, *run)
+		}
+	}
+	sortStable(runs, func(a, b models.StandingOrderRun) bool { return a.ScheduledFor.After(b.ScheduledFor) })
+	return page(runs, offset, limit), nil
+}
@@ -0,0 +1,98 @@
+package memory
+
+import (
+	"context"
+	"sync"
+
+	"github.com/limistah/wallet-service/internal/models"
+	"github.com/limistah/wallet-service/internal/repositories"
+	"gorm.io/gorm"
+)
+
+// StatementLineRepository is an in-memory repositories.StatementLineRepository
+type StatementLineRepository struct {
+	mu        sync.RWMutex
+	lines     map[uint]*models.StatementLine
+	idCounter uint
+}
+
+// NewStatementLineRepository creates an empty statement line repository
+func NewStatementLineRepository() *StatementLineRepository {
+	return &StatementLineRepository{
+		lines: make(map[uint]*models.StatementLine),
+	}
+}
+
+// Create adds a line, as saving the statement it belongs to does
+func (m *StatementLineRepository) Create(line *models.StatementLine) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.idCounter++
+	line.ID = m.idCounter
+	stamp(line, true)
+	m.lines[line.ID] = clone(line)
+}
+
+func (m *StatementLineRepository) GetByID(ctx context.Context, id uint) (*models.StatementLine, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	if line, ok := m.lines[id]; ok {
+		return clone(line), nil
+	}
+	return nil, gorm.ErrRecordNotFound
+}
+
+// List returns lines oldest first so exceptions are reviewed in order
+func (m *StatementLineRepository) List(ctx context.Context, filter repositories.StatementLineFilter, offset, limit int) ([]models.StatementLine, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	var lines []models.StatementLine
+	for _, line := range inOrder(m.lines) {
+		if (filter.Provider != "" && line.Provider != filter.Provider) || (filter.Status != "" && line.Status != filter.Status) ||
+			(filter.Reason != "" && line.Reason != filter.Reason) || (filter.StatementID != 0 && line.StatementID != filter.StatementID) {
+			continue
+		}
+		lines = append(lines, *line)
+	}
+	sortStable(lines, func(a, b models.StatementLine) bool { return a.CreatedAt.Before(b.CreatedAt) })
+	return page(lines, offset, limit), nil
+}
+
+func (m *StatementLineRepository) MatchedReferences(ctx context.Context, provider string, references []string) ([]string, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	wanted := make(map[string]bool, len(references))
+	for _, reference := range references {
+		wanted[reference] = true
+	}
+	var matched []string
+	for _, line := range m.lines {
+		if line.Provider == provider && wanted[line.Reference] && line.Status == models.StatementLineStatusMatched {
+			matched = append(matched, line.Reference)
+			delete(wanted, line.Reference)
+		}
+	}
+	return matched, nil
+}
+
+func (m *StatementLineRepository) Update(ctx context.Context, line *models.StatementLine) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	stamp(line, false)
+	m.lines[line.ID] = clone(line)
+	return nil
+}
+
+// ofStatement returns the lines of a statement in file order
+func (m *StatementLineRepository) ofStatement(statementID uint) []models.StatementLine {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	var lines []models.StatementLine
+	for _, line := range inOrder(m.lines) {
+		if line.StatementID == statementID {
+			lines = append(lines, *line)
+		}
+	}
+	sortStable(lines, func(a, b models.StatementLine) bool { return a.LineNumber < b.LineNumber })
+	return lines
+}
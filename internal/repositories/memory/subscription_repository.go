@@ -0,0 +1,227 @@
+package memory
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/limistah/wallet-service/internal/models"
+	"gorm.io/gorm"
+)
+
+// SubscriptionRepository is an in-memory repositories.SubscriptionRepository
+type SubscriptionRepository struct {
+	mu                    sync.RWMutex
+	plans                 map[uint]*models.SubscriptionPlan
+	subscriptions         map[uint]*models.Subscription
+	charges               map[uint]*models.SubscriptionCharge
+	planIDCounter         uint
+	subscriptionIDCounter uint
+	chargeIDCounter       uint
+	// users fills in the merchants of the plans read, when set
+	users *UserRepository
+}
+
+// NewSubscriptionRepository creates an empty subscription repository
+func NewSubscriptionRepository() *SubscriptionRepository {
+	return &SubscriptionRepository{
+		plans:         make(map[uint]*models.SubscriptionPlan),
+		subscriptions: make(map[uint]*models.Subscription),
+		charges:       make(map[uint]*models.SubscriptionCharge),
+	}
+}
+
+func (m *SubscriptionRepository) CreatePlan(ctx context.Context, plan *models.SubscriptionPlan) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.planIDCounter++
+	plan.ID = m.planIDCounter
+	stamp(plan, true)
+	stored := clone(plan)
+	stored.Merchant = models.User{}
+	m.plans[plan.ID] = stored
+	return nil
+}
+
+func (m *SubscriptionRepository) GetPlan(ctx context.Context, id uint) (*models.SubscriptionPlan, error) {
+	m.mu.RLock()
+	plan, ok := m.plans[id]
+	if ok {
+		plan = clone(plan)
+	}
+	m.mu.RUnlock()
+	if !ok {
+		return nil, gorm.ErrRecordNotFound
+	}
+	if m.users != nil {
+		plan.Merchant = m.users.load(plan.MerchantID)
+	}
+	return plan, nil
+}
+
+// ListActivePlans returns the active plans newest first with their merchants
+func (m *SubscriptionRepository) ListActivePlans(ctx context.Context, offset, limit int) ([]models.SubscriptionPlan, error) {
+	plans := page(m.listPlans(func(plan *models.SubscriptionPlan) bool { return plan.Active }), offset, limit)
+	if m.users != nil {
+		for i := range plans {
+			plans[i].Merchant = m.users.load(plans[i].MerchantID)
+		}
+	}
+	return plans, nil
+}
+
+func (m *SubscriptionRepository) ListPlansByMerchant(ctx context.Context, merchantID uint) ([]models.SubscriptionPlan, error) {
+	return m.listPlans(func(plan *models.SubscriptionPlan) bool { return plan.MerchantID == merchantID }), nil
+}
+
+func (m *SubscriptionRepository) ArchivePlan(ctx context.Context, id uint) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if plan, ok := m.plans[id]; ok {
+		plan.Active = false
+		plan.UpdatedAt = time.Now()
+	}
+	return nil
+}
+
+func (m *SubscriptionRepository) Create(ctx context.Context, subscription *models.Subscription) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for _, stored := range m.subscriptions {
+		if stored.Reference == subscription.Reference {
+			return gorm.ErrDuplicatedKey
+		}
+	}
+	m.subscriptionIDCounter++
+	subscription.ID = m.subscriptionIDCounter
+	stamp(subscription, true)
+	stored := clone(subscription)
+	stored.Plan = models.SubscriptionPlan{}
+	m.subscriptions[subscription.ID] = stored
+	return nil
+}
+
+func (m *SubscriptionRepository) GetByID(ctx context.Context, id uint) (*models.Subscription, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	if subscription, ok := m.subscriptions[id]; ok {
+		return m.withPlan(subscription), nil
+	}
+	return nil, gorm.ErrRecordNotFound
+}
+
+func (m *SubscriptionRepository) GetOpenByUserAndPlan(ctx context.Context, userID, planID uint) (*models.Subscription, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	for _, subscription := range inOrder(m.subscriptions) {
+		if subscription.UserID == userID && subscription.PlanID == planID && subscription.Status != models.SubscriptionStatusCancelled {
+			return clone(subscription), nil
+		}
+	}
+	return nil, gorm.ErrRecordNotFound
+}
+
+func (m *SubscriptionRepository) ListByUser(ctx context.Context, userID uint) ([]models.Subscription, error) {
+	return m.listSubscriptions(func(subscription *models.Subscription) bool { return subscription.UserID == userID }), nil
+}
+
+func (m *SubscriptionRepository) ListByPlan(ctx context.Context, planID uint, offset, limit int) ([]models.Subscription, error) {
+	subscriptions := m.listSubscriptions(func(subscription *models.Subscription) bool { return subscription.PlanID == planID })
+	return page(subscriptions, offset, limit), nil
+}
+
+// ListDue returns the billable subscriptions due by now, the longest overdue first
+func (m *SubscriptionRepository) ListDue(ctx context.Context, now time.Time, limit int) ([]models.Subscription, error) {
+	subscriptions := m.listSubscriptions(func(subscription *models.Subscription) bool {
+		return subscription.IsBillable() && !subscription.NextBillingAt.After(now)
+	})
+	sortStable(subscriptions, func(a, b models.Subscription) bool { return a.NextBillingAt.Before(b.NextBillingAt) })
+	return page(subscriptions, 0, limit), nil
+}
+
+// Update saves the billing state of a subscription still in status from
+func (m *SubscriptionRepository) Update(ctx context.Context, subscription *models.Subscription, from models.SubscriptionStatus) (bool, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	stored, ok := m.subscriptions[subscription.ID]
+	if !ok || stored.Status != from {
+		return false, nil
+	}
+	stored.Status = subscription.Status
+	stored.PaidPeriods = subscription.PaidPeriods
+	stored.PaidUntil = subscription.PaidUntil
+	stored.NextBillingAt = subscription.NextBillingAt
+	stored.FailedAttempts = subscription.FailedAttempts
+	stored.LastError = subscription.LastError
+	stored.SuspendedAt = subscription.SuspendedAt
+	stored.CancelledAt = subscription.CancelledAt
+	stored.UpdatedAt = time.Now()
+	return true, nil
+}
+
+func (m *SubscriptionRepository) CreateCharge(ctx context.Context, charge *models.SubscriptionCharge) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.chargeIDCounter++
+	charge.ID = m.chargeIDCounter
+	stamp(charge, true)
+	m.charges[charge.ID] = clone(charge)
+	return nil
+}
+
+// ListCharges returns the charges of a subscription newest first
+func (m *SubscriptionRepository) ListCharges(ctx context.Context, subscriptionID uint, offset, limit int) ([]models.SubscriptionCharge, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	var charges []models.SubscriptionCharge
+	for _, charge := range inOrder(m.charges) {
+		if charge.SubscriptionID == subscriptionID {
+			charges = append(charges, *charge)
+		}
+	}
+	sortStable(charges, func(a, b models.SubscriptionCharge) bool {
+		return a.CreatedAt.After(b.CreatedAt) || (a.CreatedAt.Equal(b.CreatedAt) && a.ID > b.ID)
+	})
+	return page(charges, offset, limit), nil
+}
+
+// listPlans returns the plans matching keep newest first
+func (m *SubscriptionRepository) listPlans(keep func(*models.SubscriptionPlan) bool) []models.SubscriptionPlan {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	var plans []models.SubscriptionPlan
+	for _, plan := range inOrder(m.plans) {
+		if keep(plan) {
+			plans = append(plans, *plan)
+		}
+	}
+	sortStable(plans, func(a, b models.SubscriptionPlan) bool {
+		return a.CreatedAt.After(b.CreatedAt) || (a.CreatedAt.Equal(b.CreatedAt) && a.ID > b.ID)
+	})
+	return plans
+}
+
+// listSubscriptions returns the subscriptions matching keep newest first with their plans
+func (m *SubscriptionRepository) listSubscriptions(keep func(*models.Subscription) bool) []models.Subscription {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	var subscriptions []models.Subscription
+	for _, subscription := range inOrder(m.subscriptions) {
+		if keep(subscription) {
+			subscriptions = append(subscriptions, *m.withPlan(subscription))
+		}
+	}
+	sortStable(subscriptions, func(a, b models.Subscription) bool {
+		return a.CreatedAt.After(b.CreatedAt) || (a.CreatedAt.Equal(b.CreatedAt) && a.ID > b.ID)
+	})
+	return subscriptions
+}
+
+// withPlan copies a subscription with its plan; the caller holds the lock
+func (m *SubscriptionRepository) withPlan(subscription *models.Subscription) *models.Subscription {
+	loaded := clone(subscription)
+	if plan, ok := m.plans[subscription.PlanID]; ok {
+		loaded.Plan = *plan
+	}
+	return loaded
+}
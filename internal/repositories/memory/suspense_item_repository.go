@@ -0,0 +1,104 @@
+package memory
+
+import (
+	"context"
+	"sync"
+
+	"github.com/limistah/wallet-service/internal/models"
+	"gorm.io/gorm"
+)
+
+// SuspenseItemRepository is an in-memory repositories.SuspenseItemRepository
+type SuspenseItemRepository struct {
+	mu        sync.RWMutex
+	items     map[uint]*models.SuspenseItem
+	idCounter uint
+}
+
+// NewSuspenseItemRepository creates an empty suspense item repository
+func NewSuspenseItemRepository() *SuspenseItemRepository {
+	return &SuspenseItemRepository{
+		items: make(map[uint]*models.SuspenseItem),
+	}
+}
+
+func (m *SuspenseItemRepository) Create(ctx context.Context, item *models.SuspenseItem) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for _, stored := range m.items {
+		if stored.Provider == item.Provider && stored.ExternalReference == item.ExternalReference {
+			return gorm.ErrDuplicatedKey
+		}
+	}
+	m.idCounter++
+	item.ID = m.idCounter
+	stamp(item, true)
+	m.items[item.ID] = clone(item)
+	return nil
+}
+
+func (m *SuspenseItemRepository) GetByID(ctx context.Context, id uint) (*models.SuspenseItem, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	if item, ok := m.items[id]; ok {
+		return clone(item), nil
+	}
+	return nil, gorm.ErrRecordNotFound
+}
+
+func (m *SuspenseItemRepository) GetByExternalReference(ctx context.Context, provider, externalReference string) (*models.SuspenseItem, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	for _, item := range m.items {
+		if item.Provider == provider && item.ExternalReference == externalReference {
+			return clone(item), nil
+		}
+	}
+	return nil, gorm.ErrRecordNotFound
+}
+
+// List returns items oldest first so the queue is worked in order; an empty status lists every item
+func (m *SuspenseItemRepository) List(ctx context.Context, status models.SuspenseItemStatus, offset, limit int) ([]models.SuspenseItem, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	var items []models.SuspenseItem
+	for _, item := range inOrder(m.items) {
+		if status == "" || item.Status == status {
+			items = append(items, *item)
+		}
+	}
+	sortStable(items, func(a, b models.SuspenseItem) bool { return a.CreatedAt.Before(b.CreatedAt) })
+	return page(items, offset, limit), nil
+}
+
+func (m *SuspenseItemRepository) Update(ctx context.Context, item *models.SuspenseItem) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	stamp(item, false)
+	m.items[item.ID] = clone(item)
+	return nil
+}
+
+func (m *SuspenseItemRepository) Resolve(ctx context.Context, item *models.SuspenseItem) (bool, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	stored, ok := m.items[item.ID]
+	if !ok || stored.IsResolved() {
+		return false, nil
+	}
+	stored.Status = item.Status
+	stored.ReallocatedWalletID = item.ReallocatedWalletID
+	stored.ResolutionJournalEntryID = item.ResolutionJournalEntryID
+	stored.ResolvedBy = item.ResolvedBy
+	stored.ResolvedAt = item.ResolvedAt
+	stored.Notes = item.Notes
+	stamp(stored, false)
+	return true, nil
+}
+
+// Count returns how many suspense items are stored
+func (m *SuspenseItemRepository) Count() int {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return len(m.items)
+}
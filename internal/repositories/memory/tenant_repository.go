@@ -0,0 +1,69 @@
+package memory
+
+import (
+	"context"
+	"sync"
+
+	"github.com/limistah/wallet-service/internal/models"
+	"gorm.io/gorm"
+)
+
+// TenantRepository is an in-memory repositories.TenantRepository
+type TenantRepository struct {
+	mu      sync.RWMutex
+	tenants []models.Tenant
+}
+
+// NewTenantRepository creates an empty tenant repository
+func NewTenantRepository() *TenantRepository {
+	return &TenantRepository{}
+}
+
+func (m *TenantRepository) Create(ctx context.Context, tenant *models.Tenant) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for _, existing := range m.tenants {
+		if existing.Slug == tenant.Slug || (tenant.ID != 0 && existing.ID == tenant.ID) {
+			return gorm.ErrDuplicatedKey
+		}
+	}
+	if tenant.ID == 0 {
+		for _, existing := range m.tenants {
+			tenant.ID = max(tenant.ID, existing.ID)
+		}
+		tenant.ID++
+	}
+	stamp(tenant, true)
+	m.tenants = append(m.tenants, *tenant)
+	return nil
+}
+
+func (m *TenantRepository) GetByID(ctx context.Context, id uint) (*models.Tenant, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	for _, tenant := range m.tenants {
+		if tenant.ID == id {
+			return &tenant, nil
+		}
+	}
+	return nil, gorm.ErrRecordNotFound
+}
+
+func (m *TenantRepository) GetBySlug(ctx context.Context, slug string) (*models.Tenant, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	for _, tenant := range m.tenants {
+		if tenant.Slug == slug {
+			return &tenant, nil
+		}
+	}
+	return nil, gorm.ErrRecordNotFound
+}
+
+func (m *TenantRepository) List(ctx context.Context) ([]models.Tenant, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	tenants := append([]models.Tenant(nil), m.tenants...)
+	sortStable(tenants, func(a, b models.Tenant) bool { return a.ID < b.ID })
+	return tenants, nil
+}
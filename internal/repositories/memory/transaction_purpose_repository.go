@@ -0,0 +1,107 @@
+package memory
+
+import (
+	"context"
+	"sort"
+	"sync"
+
+	"github.com/limistah/wallet-service/internal/models"
+	"gorm.io/gorm"
+)
+
+// TransactionPurposeRepository is an in-memory repositories.TransactionPurposeRepository
+type TransactionPurposeRepository struct {
+	mu       sync.RWMutex
+	purposes map[models.TransactionPurpose]*models.TransactionPurposeDefinition
+	// transactions and archived answer InUse, when set
+	transactions *TransactionRepository
+	archived     *ArchivedTransactionRepository
+}
+
+// NewTransactionPurposeRepository creates a transaction purpose repository holding the default purposes
+func NewTransactionPurposeRepository() *TransactionPurposeRepository {
+	repo := &TransactionPurposeRepository{
+		purposes: make(map[models.TransactionPurpose]*models.TransactionPurposeDefinition),
+	}
+	for _, definition := range models.DefaultTransactionPurposes() {
+		repo.Create(context.Background(), &definition)
+	}
+	return repo
+}
+
+func (m *TransactionPurposeRepository) GetByName(ctx context.Context, name models.TransactionPurpose) (*models.TransactionPurposeDefinition, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	if definition, ok := m.purposes[name]; ok {
+		return clone(definition), nil
+	}
+	return nil, gorm.ErrRecordNotFound
+}
+
+func (m *TransactionPurposeRepository) List(ctx context.Context) ([]models.TransactionPurposeDefinition, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	definitions := make([]models.TransactionPurposeDefinition, 0, len(m.purposes))
+	for _, definition := range m.purposes {
+		definitions = append(definitions, *definition)
+	}
+	sort.Slice(definitions, func(i, j int) bool { return definitions[i].Name < definitions[j].Name })
+	return definitions, nil
+}
+
+func (m *TransactionPurposeRepository) Create(ctx context.Context, definition *models.TransactionPurposeDefinition) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if _, ok := m.purposes[definition.Name]; ok {
+		return gorm.ErrDuplicatedKey
+	}
+	stamp(definition, true)
+	m.purposes[definition.Name] = clone(definition)
+	return nil
+}
+
+func (m *TransactionPurposeRepository) Update(ctx context.Context, definition *models.TransactionPurposeDefinition) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	stamp(definition, false)
+	m.purposes[definition.Name] = clone(definition)
+	return nil
+}
+
+func (m *TransactionPurposeRepository) Delete(ctx context.Context, name models.TransactionPurpose) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	delete(m.purposes, name)
+	return nil
+}
+
+func (m *TransactionPurposeRepository) InUse(ctx context.Context, name models.TransactionPurpose) (bool, error) {
+	if m.transactions != nil && len(m.transactions.filter(func(transaction *models.Transaction) bool {
+		return transaction.TransactionPurpose == name
+	})) > 0 {
+		return true, nil
+	}
+	if m.archived != nil {
+		m.archived.mu.RLock()
+		defer m.archived.mu.RUnlock()
+		for _, transaction := range m.archived.transactions {
+			if transaction.TransactionPurpose == name {
+				return true, nil
+			}
+		}
+	}
+	return false, nil
+}
+
+// uncounted returns the purposes whose debits do not count toward tier limits
+func (m *TransactionPurposeRepository) uncounted() map[models.TransactionPurpose]bool {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	purposes := make(map[models.TransactionPurpose]bool)
+	for name, definition := range m.purposes {
+		if !definition.CountsTowardLimits {
+			purposes[name] = true
+		}
+	}
+	return purposes
+}
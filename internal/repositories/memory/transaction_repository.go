@@ -0,0 +1,476 @@
+package memory
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/limistah/wallet-service/internal/models"
+	"github.com/shopspring/decimal"
+	"gorm.io/gorm"
+)
+
+// TransactionRepository is an in-memory repositories.TransactionRepository
+type TransactionRepository struct {
+	mu           sync.RWMutex
+	transactions map[uint]*models.Transaction
+	idCounter    uint
+	// wallets fills in the wallets of the transactions read, when set
+	wallets *WalletRepository
+	// checkpoints, fraudReviews and amlCases answer FindArchivable and GetCaseReferencedIDs, when set
+	checkpoints  *BalanceCheckpointRepository
+	fraudReviews *FraudReviewRepository
+	amlCases     *AMLCaseRepository
+	// purposes tells SumDebitsSince which purposes count toward tier limits, when set; otherwise
+	// every purpose but fees does
+	purposes *TransactionPurposeRepository
+}
+
+// NewTransactionRepository creates an empty transaction repository
+func NewTransactionRepository() *TransactionRepository {
+	return &TransactionRepository{
+		transactions: make(map[uint]*models.Transaction),
+	}
+}
+
+// Create stores the transaction; references are unique, though transactions may be created without one
+func (m *TransactionRepository) Create(ctx context.Context, transaction *models.Transaction) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for _, stored := range m.transactions {
+		if transaction.Reference != "" && stored.Reference == transaction.Reference {
+			return gorm.ErrDuplicatedKey
+		}
+	}
+	m.idCounter++
+	transaction.ID = m.idCounter
+	stamp(transaction, true)
+	m.transactions[transaction.ID] = storedTransaction(transaction)
+	return nil
+}
+
+// GetByID returns a transaction with its wallet and the transaction it relates to
+func (m *TransactionRepository) GetByID(ctx context.Context, id uint) (*models.Transaction, error) {
+	m.mu.RLock()
+	transaction, ok := m.transactions[id]
+	if ok {
+		transaction = m.withRelated(transaction)
+	}
+	m.mu.RUnlock()
+	if !ok {
+		return nil, gorm.ErrRecordNotFound
+	}
+	m.loadWallet(ctx, transaction)
+	return transaction, nil
+}
+
+func (m *TransactionRepository) GetByReference(ctx context.Context, reference string) (*models.Transaction, error) {
+	m.mu.RLock()
+	var found *models.Transaction
+	for _, transaction := range m.transactions {
+		if transaction.Reference == reference {
+			found = clone(transaction)
+			break
+		}
+	}
+	m.mu.RUnlock()
+	if found == nil {
+		return nil, gorm.ErrRecordNotFound
+	}
+	m.loadWallet(ctx, found)
+	return found, nil
+}
+
+// GetByWalletID returns the transactions of a wallet newest first
+func (m *TransactionRepository) GetByWalletID(ctx context.Context, walletID uint, offset, limit int) ([]models.Transaction, error) {
+	transactions := m.filter(func(transaction *models.Transaction) bool { return transaction.WalletID == walletID })
+	sortStable(transactions, func(a, b models.Transaction) bool { return a.CreatedAt.After(b.CreatedAt) })
+	return page(transactions, offset, limit), nil
+}
+
+// GetByWalletIDWithCursor returns up to limit+1 transactions of a wallet older than the cursor with the
+// transactions they relate to, newest first, so the caller can tell whether there is another page
+func (m *TransactionRepository) GetByWalletIDWithCursor(ctx context.Context, walletID uint, cursor *time.Time, cursorID *uint, limit int) ([]models.Transaction, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	transactions := make([]models.Transaction, 0)
+	for _, transaction := range inOrder(m.transactions) {
+		if transaction.WalletID != walletID {
+			continue
+		}
+		if cursor != nil && cursorID != nil && !(transaction.CreatedAt.Before(*cursor) ||
+			(transaction.CreatedAt.Equal(*cursor) && transaction.ID < *cursorID)) {
+			continue
+		}
+		transactions = append(transactions, *m.withRelated(transaction))
+	}
+	sortStable(transactions, newestFirst)
+	return page(transactions, 0, limit+1), nil
+}
+
+func (m *TransactionRepository) Update(ctx context.Context, transaction *models.Transaction) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	stamp(transaction, false)
+	m.transactions[transaction.ID] = storedTransaction(transaction)
+	return nil
+}
+
+func (m *TransactionRepository) LinkRelated(ctx context.Context, id, relatedID uint) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if transaction, ok := m.transactions[id]; ok {
+		transaction.RelatedTransactionID = &relatedID
+		transaction.UpdatedAt = time.Now()
+	}
+	return nil
+}
+
+func (m *TransactionRepository) CalculateBalance(ctx context.Context, walletID uint) (decimal.Decimal, error) {
+	return m.CalculateBalanceBetween(ctx, walletID, 0, 0)
+}
+
+func (m *TransactionRepository) CalculateBalanceAfter(ctx context.Context, walletID, afterID uint) (decimal.Decimal, error) {
+	return m.CalculateBalanceBetween(ctx, walletID, afterID, 0)
+}
+
+// CalculateBalanceBetween sums the completed credits less the held debits of a wallet with IDs after
+// afterID and, when throughID is set, up to throughID
+func (m *TransactionRepository) CalculateBalanceBetween(ctx context.Context, walletID, afterID, throughID uint) (decimal.Decimal, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	balance := decimal.Zero
+	for _, transaction := range m.transactions {
+		if transaction.WalletID != walletID || transaction.ID <= afterID || (throughID > 0 && transaction.ID > throughID) {
+			continue
+		}
+		switch {
+		case transaction.TransactionType == models.TransactionTypeCredit && transaction.Status == models.TransactionStatusCompleted:
+			balance = balance.Add(transaction.Amount)
+		case transaction.TransactionType == models.TransactionTypeDebit && heldDebit(transaction):
+			balance = balance.Sub(transaction.Amount)
+		}
+	}
+	return balance, nil
+}
+
+// GetSettledThrough returns the last ID of the unbroken run of a wallet's transactions, from its first,
+// that are final and were created before before
+func (m *TransactionRepository) GetSettledThrough(ctx context.Context, walletID uint, before time.Time) (uint, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	var unsettled, through uint
+	for _, transaction := range m.transactions {
+		settled := transaction.Status == models.TransactionStatusCompleted || transaction.Status == models.TransactionStatusFailed ||
+			transaction.Status == models.TransactionStatusCancelled
+		if transaction.WalletID == walletID && (!settled || !transaction.CreatedAt.Before(before)) &&
+			(unsettled == 0 || transaction.ID < unsettled) {
+			unsettled = transaction.ID
+		}
+	}
+	for _, transaction := range m.transactions {
+		if transaction.WalletID == walletID && (unsettled == 0 || transaction.ID < unsettled) && transaction.ID > through {
+			through = transaction.ID
+		}
+	}
+	return through, nil
+}
+
+// FindArchivable returns, in ID order, transactions after afterID created before before and covered by
+// the balance checkpoint of their wallet
+func (m *TransactionRepository) FindArchivable(ctx context.Context, before time.Time, afterID uint, limit int) ([]models.Transaction, error) {
+	if m.checkpoints == nil {
+		return nil, nil
+	}
+	candidates := m.filter(func(transaction *models.Transaction) bool {
+		return transaction.ID > afterID && transaction.CreatedAt.Before(before)
+	})
+	var transactions []models.Transaction
+	for _, transaction := range candidates {
+		checkpoint, err := m.checkpoints.GetByWalletID(ctx, transaction.WalletID)
+		if err != nil || transaction.ID > checkpoint.ThroughTransactionID {
+			continue
+		}
+		transactions = append(transactions, transaction)
+	}
+	return page(transactions, 0, limit), nil
+}
+
+// FindLinked returns the transactions outside of transactions that relate to them, that they relate
+// to, that relate to the same transactions or that share their journal entries
+func (m *TransactionRepository) FindLinked(ctx context.Context, transactions []models.Transaction) ([]models.Transaction, error) {
+	if len(transactions) == 0 {
+		return nil, nil
+	}
+	given := make(map[uint]bool, len(transactions))
+	relatedTo := make(map[uint]bool)
+	related := make(map[uint]bool)
+	journals := make(map[uint]bool)
+	for _, transaction := range transactions {
+		given[transaction.ID] = true
+		relatedTo[transaction.ID] = true
+		if transaction.RelatedTransactionID != nil {
+			relatedTo[*transaction.RelatedTransactionID] = true
+			related[*transaction.RelatedTransactionID] = true
+		}
+		if transaction.JournalEntryID != nil {
+			journals[*transaction.JournalEntryID] = true
+		}
+	}
+	return m.filter(func(transaction *models.Transaction) bool {
+		if given[transaction.ID] {
+			return false
+		}
+		return (transaction.RelatedTransactionID != nil && relatedTo[*transaction.RelatedTransactionID]) ||
+			related[transaction.ID] || (transaction.JournalEntryID != nil && journals[*transaction.JournalEntryID])
+	}), nil
+}
+
+// GetCaseReferencedIDs returns the IDs among ids that a fraud review or an AML case refers to
+func (m *TransactionRepository) GetCaseReferencedIDs(ctx context.Context, ids []uint) ([]uint, error) {
+	var referenced []uint
+	for _, id := range ids {
+		if m.fraudReviews.references(id) || m.amlCases.references(id) {
+			referenced = append(referenced, id)
+		}
+	}
+	return referenced, nil
+}
+
+func (m *TransactionRepository) SumDebitsSince(ctx context.Context, walletID uint, since time.Time) (decimal.Decimal, error) {
+	uncounted := map[models.TransactionPurpose]bool{models.TransactionPurposeFee: true}
+	if m.purposes != nil {
+		uncounted = m.purposes.uncounted()
+	}
+	debits := m.filter(func(transaction *models.Transaction) bool {
+		return transaction.WalletID == walletID && transaction.TransactionType == models.TransactionTypeDebit && heldDebit(transaction) &&
+			!uncounted[transaction.TransactionPurpose] && !transaction.CreatedAt.Before(since)
+	})
+	total := decimal.Zero
+	for _, debit := range debits {
+		total = total.Add(debit.Amount)
+	}
+	return total, nil
+}
+
+// GetDebitsSince returns the held debits of a wallet since since other than fees, newest first
+func (m *TransactionRepository) GetDebitsSince(ctx context.Context, walletID uint, since time.Time) ([]models.Transaction, error) {
+	debits := m.filter(func(transaction *models.Transaction) bool {
+		return transaction.WalletID == walletID && transaction.TransactionType == models.TransactionTypeDebit && heldDebit(transaction) &&
+			transaction.TransactionPurpose != models.TransactionPurposeFee && !transaction.CreatedAt.Before(since)
+	})
+	sortStable(debits, newestFirst)
+	return debits, nil
+}
+
+func (m *TransactionRepository) SumCategoryDebitsSince(ctx context.Context, walletID uint, category string, since time.Time) (decimal.Decimal, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	total := decimal.Zero
+	for _, transaction := range m.transactions {
+		if transaction.WalletID == walletID && transaction.TransactionType == models.TransactionTypeDebit &&
+			transaction.Status == models.TransactionStatusCompleted && transaction.Category == category && !transaction.CreatedAt.Before(since) {
+			total = total.Add(transaction.Amount)
+		}
+	}
+	return total, nil
+}
+
+func (m *TransactionRepository) SetCategory(ctx context.Context, id uint, category string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if transaction, ok := m.transactions[id]; ok {
+		transaction.Category = category
+		transaction.UpdatedAt = time.Now()
+	}
+	return nil
+}
+
+func (m *TransactionRepository) HasTransferBetween(ctx context.Context, fromWalletID, toWalletID uint) (bool, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	for _, in := range m.transactions {
+		if in.WalletID != toWalletID || in.TransactionType != models.TransactionTypeCredit || in.RelatedTransactionID == nil {
+			continue
+		}
+		out, ok := m.transactions[*in.RelatedTransactionID]
+		if ok && out.WalletID == fromWalletID && out.TransactionType == models.TransactionTypeDebit &&
+			out.TransactionPurpose == models.TransactionPurposeTransfer && out.Status == models.TransactionStatusCompleted {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// GetPendingDebitsBefore returns pending withdrawals and transfers created before before, oldest first
+func (m *TransactionRepository) GetPendingDebitsBefore(ctx context.Context, before time.Time, limit int) ([]models.Transaction, error) {
+	pending := m.filter(func(transaction *models.Transaction) bool {
+		return transaction.Status == models.TransactionStatusPending && transaction.TransactionType == models.TransactionTypeDebit &&
+			(transaction.TransactionPurpose == models.TransactionPurposeWithdrawal || transaction.TransactionPurpose == models.TransactionPurposeTransfer) &&
+			transaction.CreatedAt.Before(before)
+	})
+	sortStable(pending, func(a, b models.Transaction) bool { return a.CreatedAt.Before(b.CreatedAt) })
+	return page(pending, 0, limit), nil
+}
+
+// List returns transactions newest first with their wallets
+func (m *TransactionRepository) List(ctx context.Context, offset, limit int) ([]models.Transaction, error) {
+	transactions := m.filter(func(*models.Transaction) bool { return true })
+	sortStable(transactions, func(a, b models.Transaction) bool { return a.CreatedAt.After(b.CreatedAt) })
+	transactions = page(transactions, offset, limit)
+	for i := range transactions {
+		m.loadWallet(ctx, &transactions[i])
+	}
+	return transactions, nil
+}
+
+// FindCompletedByWalletID passes the completed transactions of a wallet to fn in batches of batchSize,
+// in ID order, with the transactions they relate to
+func (m *TransactionRepository) FindCompletedByWalletID(ctx context.Context, walletID uint, batchSize int, fn func([]models.Transaction) error) error {
+	m.mu.RLock()
+	transactions := make([]models.Transaction, 0)
+	for _, transaction := range inOrder(m.transactions) {
+		if transaction.WalletID == walletID && transaction.Status == models.TransactionStatusCompleted {
+			transactions = append(transactions, *m.withRelated(transaction))
+		}
+	}
+	m.mu.RUnlock()
+	for len(transactions) > 0 {
+		batch := page(transactions, 0, batchSize)
+		if err := fn(batch); err != nil {
+			return err
+		}
+		transactions = transactions[len(batch):]
+	}
+	return nil
+}
+
+func (m *TransactionRepository) GetByRelatedTransactionID(ctx context.Context, relatedID uint) ([]models.Transaction, error) {
+	return m.filter(func(transaction *models.Transaction) bool {
+		return transaction.RelatedTransactionID != nil && *transaction.RelatedTransactionID == relatedID
+	}), nil
+}
+
+func (m *TransactionRepository) GetByWalletIDBetween(ctx context.Context, walletID uint, from, to time.Time) ([]models.Transaction, error) {
+	transactions := m.filter(func(transaction *models.Transaction) bool {
+		return transaction.WalletID == walletID && !transaction.CreatedAt.Before(from) && transaction.CreatedAt.Before(to)
+	})
+	sortStable(transactions, func(a, b models.Transaction) bool { return a.CreatedAt.Before(b.CreatedAt) })
+	return transactions, nil
+}
+
+func (m *TransactionRepository) GetLegs(ctx context.Context, id uint) ([]models.Transaction, error) {
+	return m.filter(func(transaction *models.Transaction) bool {
+		return transaction.ID == id || transaction.RelatedTransactionID != nil && *transaction.RelatedTransactionID == id
+	}), nil
+}
+
+func (m *TransactionRepository) UpdateStatus(ctx context.Context, id uint, from, to models.TransactionStatus) (bool, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	transaction, ok := m.transactions[id]
+	if !ok || transaction.Status != from {
+		return false, nil
+	}
+	transaction.Status = to
+	transaction.UpdatedAt = time.Now()
+	return true, nil
+}
+
+func (m *TransactionRepository) UpdateLegsStatus(ctx context.Context, id uint, status models.TransactionStatus) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for _, transaction := range m.transactions {
+		if transaction.ID == id || transaction.RelatedTransactionID != nil && *transaction.RelatedTransactionID == id {
+			transaction.Status = status
+			transaction.UpdatedAt = time.Now()
+		}
+	}
+	return nil
+}
+
+func (m *TransactionRepository) SetBalances(ctx context.Context, id uint, balanceBefore, balanceAfter decimal.Decimal) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if transaction, ok := m.transactions[id]; ok {
+		transaction.BalanceBefore = balanceBefore
+		transaction.BalanceAfter = balanceAfter
+		transaction.UpdatedAt = time.Now()
+	}
+	return nil
+}
+
+func (m *TransactionRepository) DeleteArchived(ctx context.Context, ids []uint) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for _, id := range ids {
+		delete(m.transactions, id)
+	}
+	return nil
+}
+
+// SetLastID makes the next transaction created get the ID after id, as if earlier ones had been
+// archived
+func (m *TransactionRepository) SetLastID(id uint) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.idCounter = id
+}
+
+// journalLegs returns the legs of a journal entry in ID order
+func (m *TransactionRepository) journalLegs(entryID uint) []models.Transaction {
+	return m.filter(func(transaction *models.Transaction) bool {
+		return transaction.JournalEntryID != nil && *transaction.JournalEntryID == entryID
+	})
+}
+
+// filter returns the transactions matching keep in ID order
+func (m *TransactionRepository) filter(keep func(*models.Transaction) bool) []models.Transaction {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	var transactions []models.Transaction
+	for _, transaction := range inOrder(m.transactions) {
+		if keep(transaction) {
+			transactions = append(transactions, *transaction)
+		}
+	}
+	return transactions
+}
+
+// withRelated copies a transaction with the transaction it relates to; the caller holds the lock
+func (m *TransactionRepository) withRelated(transaction *models.Transaction) *models.Transaction {
+	loaded := clone(transaction)
+	if transaction.RelatedTransactionID != nil {
+		if related, ok := m.transactions[*transaction.RelatedTransactionID]; ok {
+			loaded.RelatedTransaction = clone(related)
+		}
+	}
+	return loaded
+}
+
+func (m *TransactionRepository) loadWallet(ctx context.Context, transaction *models.Transaction) {
+	if m.wallets == nil {
+		return
+	}
+	if wallet, err := m.wallets.GetByID(ctx, transaction.WalletID); err == nil {
+		transaction.Wallet = *wallet
+	}
+}
+
+// storedTransaction copies a transaction for storing without the records it is loaded with
+func storedTransaction(transaction *models.Transaction) *models.Transaction {
+	copied := clone(transaction)
+	copied.Wallet = models.Wallet{}
+	copied.RelatedTransaction = nil
+	return copied
+}
+
+// heldDebit reports whether the amount of a debit has left the wallet balance
+func heldDebit(transaction *models.Transaction) bool {
+	return transaction.Status == models.TransactionStatusCompleted || transaction.Status == models.TransactionStatusPending ||
+		transaction.Status == models.TransactionStatusPendingReview
+}
+
+func newestFirst(a, b models.Transaction) bool {
+	return a.CreatedAt.After(b.CreatedAt) || (a.CreatedAt.Equal(b.CreatedAt) && a.ID > b.ID)
+}
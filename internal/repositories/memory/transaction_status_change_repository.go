@@ -0,0 +1,54 @@
+package memory
+
+import (
+	"context"
+	"sync"
+
+	"github.com/limistah/wallet-service/internal/models"
+)
+
+// TransactionStatusChangeRepository is an in-memory repositories.TransactionStatusChangeRepository
+type TransactionStatusChangeRepository struct {
+	mu      sync.RWMutex
+	changes []models.TransactionStatusChange
+}
+
+// NewTransactionStatusChangeRepository creates an empty transaction status change repository
+func NewTransactionStatusChangeRepository() *TransactionStatusChangeRepository {
+	return &TransactionStatusChangeRepository{}
+}
+
+// Add records a status change
+func (m *TransactionStatusChangeRepository) Add(change models.TransactionStatusChange) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.add(&change)
+}
+
+func (m *TransactionStatusChangeRepository) Create(ctx context.Context, changes []models.TransactionStatusChange) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for i := range changes {
+		m.add(&changes[i])
+	}
+	return nil
+}
+
+// add numbers and stores a change; the caller holds the lock
+func (m *TransactionStatusChangeRepository) add(change *models.TransactionStatusChange) {
+	change.ID = uint(len(m.changes) + 1)
+	stamp(change, true)
+	m.changes = append(m.changes, *change)
+}
+
+func (m *TransactionStatusChangeRepository) ListByTransactionID(ctx context.Context, transactionID uint) ([]models.TransactionStatusChange, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	var changes []models.TransactionStatusChange
+	for _, change := range m.changes {
+		if change.TransactionID == transactionID {
+			changes = append(changes, change)
+		}
+	}
+	return changes, nil
+}
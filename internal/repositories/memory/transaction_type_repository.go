@@ -0,0 +1,58 @@
+package memory
+
+import (
+	"context"
+	"sort"
+	"sync"
+
+	"github.com/limistah/wallet-service/internal/models"
+	"gorm.io/gorm"
+)
+
+// TransactionTypeRepository is an in-memory repositories.TransactionTypeRepository
+type TransactionTypeRepository struct {
+	mu    sync.RWMutex
+	types map[models.TransactionType]*models.TransactionTypeDefinition
+}
+
+// NewTransactionTypeRepository creates a transaction type repository holding the default types
+func NewTransactionTypeRepository() *TransactionTypeRepository {
+	repo := &TransactionTypeRepository{
+		types: make(map[models.TransactionType]*models.TransactionTypeDefinition),
+	}
+	for _, definition := range models.DefaultTransactionTypes() {
+		repo.Create(context.Background(), &definition)
+	}
+	return repo
+}
+
+func (m *TransactionTypeRepository) GetByName(ctx context.Context, name models.TransactionType) (*models.TransactionTypeDefinition, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	if definition, ok := m.types[name]; ok {
+		return clone(definition), nil
+	}
+	return nil, gorm.ErrRecordNotFound
+}
+
+func (m *TransactionTypeRepository) List(ctx context.Context) ([]models.TransactionTypeDefinition, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	definitions := make([]models.TransactionTypeDefinition, 0, len(m.types))
+	for _, definition := range m.types {
+		definitions = append(definitions, *definition)
+	}
+	sort.Slice(definitions, func(i, j int) bool { return definitions[i].Name < definitions[j].Name })
+	return definitions, nil
+}
+
+func (m *TransactionTypeRepository) Create(ctx context.Context, definition *models.TransactionTypeDefinition) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if _, ok := m.types[definition.Name]; ok {
+		return gorm.ErrDuplicatedKey
+	}
+	stamp(definition, true)
+	m.types[definition.Name] = clone(definition)
+	return nil
+}
@@ -0,0 +1,75 @@
+package memory
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/limistah/wallet-service/internal/models"
+	"gorm.io/gorm"
+)
+
+// TransferChallengeRepository is an in-memory repositories.TransferChallengeRepository
+type TransferChallengeRepository struct {
+	mu         sync.RWMutex
+	challenges map[uint]*models.TransferChallenge
+	idCounter  uint
+}
+
+// NewTransferChallengeRepository creates an empty transfer challenge repository
+func NewTransferChallengeRepository() *TransferChallengeRepository {
+	return &TransferChallengeRepository{challenges: make(map[uint]*models.TransferChallenge)}
+}
+
+func (m *TransferChallengeRepository) Create(ctx context.Context, challenge *models.TransferChallenge) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.idCounter++
+	challenge.ID = m.idCounter
+	stamp(challenge, true)
+	m.challenges[challenge.ID] = clone(challenge)
+	return nil
+}
+
+func (m *TransferChallengeRepository) GetByID(ctx context.Context, id uint) (*models.TransferChallenge, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	if challenge, ok := m.challenges[id]; ok {
+		return clone(challenge), nil
+	}
+	return nil, gorm.ErrRecordNotFound
+}
+
+// IncrementAttempts counts a wrong code, so concurrent guesses are all counted
+func (m *TransferChallengeRepository) IncrementAttempts(ctx context.Context, id uint) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if challenge, ok := m.challenges[id]; ok {
+		challenge.Attempts++
+	}
+	return nil
+}
+
+// MarkConfirmed consumes a challenge; it reports false when a concurrent request confirmed it first
+func (m *TransferChallengeRepository) MarkConfirmed(ctx context.Context, id uint, at time.Time) (bool, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	challenge, ok := m.challenges[id]
+	if !ok || challenge.ConfirmedAt != nil {
+		return false, nil
+	}
+	challenge.ConfirmedAt = &at
+	return true, nil
+}
+
+// DeleteExpired removes the challenges that expired before the given time
+func (m *TransferChallengeRepository) DeleteExpired(ctx context.Context, before time.Time) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for id, challenge := range m.challenges {
+		if challenge.ExpiresAt.Before(before) {
+			delete(m.challenges, id)
+		}
+	}
+	return nil
+}
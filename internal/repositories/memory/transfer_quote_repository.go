@@ -0,0 +1,65 @@
+package memory
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/limistah/wallet-service/internal/models"
+	"gorm.io/gorm"
+)
+
+// TransferQuoteRepository is an in-memory repositories.TransferQuoteRepository
+type TransferQuoteRepository struct {
+	mu        sync.RWMutex
+	quotes    map[uint]*models.TransferQuote
+	idCounter uint
+}
+
+// NewTransferQuoteRepository creates an empty transfer quote repository
+func NewTransferQuoteRepository() *TransferQuoteRepository {
+	return &TransferQuoteRepository{quotes: make(map[uint]*models.TransferQuote)}
+}
+
+func (m *TransferQuoteRepository) Create(ctx context.Context, quote *models.TransferQuote) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.idCounter++
+	quote.ID = m.idCounter
+	stamp(quote, true)
+	m.quotes[quote.ID] = clone(quote)
+	return nil
+}
+
+func (m *TransferQuoteRepository) GetByID(ctx context.Context, id uint) (*models.TransferQuote, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	if quote, ok := m.quotes[id]; ok {
+		return clone(quote), nil
+	}
+	return nil, gorm.ErrRecordNotFound
+}
+
+// Use sets the used time of a quote that is unused and not expired at now
+func (m *TransferQuoteRepository) Use(ctx context.Context, id uint, now time.Time) (bool, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	quote, ok := m.quotes[id]
+	if !ok || !quote.IsUsable(now) {
+		return false, nil
+	}
+	quote.UsedAt = &now
+	return true, nil
+}
+
+// DeleteExpired removes the quotes that expired before the given time
+func (m *TransferQuoteRepository) DeleteExpired(ctx context.Context, before time.Time) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for id, quote := range m.quotes {
+		if quote.ExpiresAt.Before(before) {
+			delete(m.quotes, id)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,68 @@
+package memory
+
+import (
+	"context"
+	"sync"
+
+	"github.com/limistah/wallet-service/internal/models"
+	"gorm.io/gorm"
+)
+
+// UserIdentityRepository is an in-memory repositories.UserIdentityRepository
+type UserIdentityRepository struct {
+	mu         sync.RWMutex
+	identities []models.UserIdentity
+	idCounter  uint
+}
+
+// NewUserIdentityRepository creates an empty user identity repository
+func NewUserIdentityRepository() *UserIdentityRepository {
+	return &UserIdentityRepository{}
+}
+
+func (m *UserIdentityRepository) Create(ctx context.Context, identity *models.UserIdentity) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for _, existing := range m.identities {
+		if existing.Provider == identity.Provider && existing.Subject == identity.Subject {
+			return gorm.ErrDuplicatedKey
+		}
+	}
+	m.idCounter++
+	identity.ID = m.idCounter
+	stamp(identity, true)
+	m.identities = append(m.identities, *identity)
+	return nil
+}
+
+func (m *UserIdentityRepository) GetByProviderSubject(ctx context.Context, provider, subject string) (*models.UserIdentity, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	for _, identity := range m.identities {
+		if identity.Provider == provider && identity.Subject == subject {
+			return &identity, nil
+		}
+	}
+	return nil, gorm.ErrRecordNotFound
+}
+
+// DeleteByUser unlinks every provider account of the user
+func (m *UserIdentityRepository) DeleteByUser(ctx context.Context, userID uint) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	var kept []models.UserIdentity
+	for _, identity := range m.identities {
+		if identity.UserID != userID {
+			kept = append(kept, identity)
+		}
+	}
+	m.identities = kept
+	return nil
+}
+
+// Identities returns the linked provider accounts
+func (m *UserIdentityRepository) Identities() []models.UserIdentity {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return append([]models.UserIdentity(nil), m.identities...)
+}
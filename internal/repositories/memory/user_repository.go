@@ -0,0 +1,233 @@
+package memory
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/limistah/wallet-service/internal/models"
+	"gorm.io/gorm"
+)
+
+// UserRepository is an in-memory repositories.UserRepository
+type UserRepository struct {
+	mu        sync.RWMutex
+	users     map[uint]*models.User
+	idCounter uint
+}
+
+// NewUserRepository creates an empty user repository
+func NewUserRepository() *UserRepository {
+	return &UserRepository{
+		users: make(map[uint]*models.User),
+	}
+}
+
+// Create stores the user, keeping an ID that is already set; emails are unique within a tenant, and
+// users belong to the default tenant unless they are created for another one
+func (m *UserRepository) Create(ctx context.Context, user *models.User) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if user.TenantID == 0 {
+		user.TenantID = models.DefaultTenantID
+	}
+	if _, ok := m.users[user.ID]; ok {
+		return gorm.ErrDuplicatedKey
+	}
+	for _, stored := range m.users {
+		if stored.TenantID == user.TenantID && stored.Email == user.Email {
+			return gorm.ErrDuplicatedKey
+		}
+	}
+	if user.ID == 0 {
+		m.idCounter++
+		user.ID = m.idCounter
+	} else if user.ID > m.idCounter {
+		m.idCounter = user.ID
+	}
+	stamp(user, true)
+	m.users[user.ID] = clone(user)
+	return nil
+}
+
+func (m *UserRepository) GetByID(ctx context.Context, id uint) (*models.User, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	if user, ok := m.users[id]; ok {
+		return clone(user), nil
+	}
+	return nil, gorm.ErrRecordNotFound
+}
+
+func (m *UserRepository) GetByEmail(ctx context.Context, email string) (*models.User, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	for _, user := range inOrder(m.users) {
+		if user.Email == email {
+			return clone(user), nil
+		}
+	}
+	return nil, gorm.ErrRecordNotFound
+}
+
+func (m *UserRepository) GetByEmailInTenant(ctx context.Context, tenantID uint, email string) (*models.User, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	for _, user := range m.users {
+		if user.Email == email && user.TenantID == tenantID {
+			return clone(user), nil
+		}
+	}
+	return nil, gorm.ErrRecordNotFound
+}
+
+func (m *UserRepository) GetByHandle(ctx context.Context, handle string) (*models.User, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	for _, user := range inOrder(m.users) {
+		if user.Handle != nil && *user.Handle == handle {
+			return clone(user), nil
+		}
+	}
+	return nil, gorm.ErrRecordNotFound
+}
+
+// SetHandle sets the handle of a user, failing with gorm.ErrDuplicatedKey when another user of the
+// tenant has it, as the unique index of the users table makes it
+func (m *UserRepository) SetHandle(ctx context.Context, id uint, handle *string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	user, ok := m.users[id]
+	if !ok {
+		return gorm.ErrRecordNotFound
+	}
+	if handle != nil {
+		for _, other := range m.users {
+			if other.ID != id && other.TenantID == user.TenantID && other.Handle != nil && *other.Handle == *handle {
+				return gorm.ErrDuplicatedKey
+			}
+		}
+	}
+	user.Handle = handle
+	user.UpdatedAt = time.Now()
+	return nil
+}
+
+func (m *UserRepository) Update(ctx context.Context, user *models.User) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	stamp(user, false)
+	m.users[user.ID] = clone(user)
+	return nil
+}
+
+func (m *UserRepository) Delete(ctx context.Context, id uint) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	delete(m.users, id)
+	return nil
+}
+
+func (m *UserRepository) List(ctx context.Context, offset, limit int) ([]models.User, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	users := make([]models.User, 0, len(m.users))
+	for _, user := range inOrder(m.users) {
+		users = append(users, *user)
+	}
+	return page(users, offset, limit), nil
+}
+
+// update applies change to a stored user
+func (m *UserRepository) update(id uint, change func(user *models.User)) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	user, ok := m.users[id]
+	if !ok {
+		return gorm.ErrRecordNotFound
+	}
+	change(user)
+	user.UpdatedAt = time.Now()
+	return nil
+}
+
+func (m *UserRepository) RevokeTokens(ctx context.Context, id uint, at time.Time) error {
+	return m.update(id, func(user *models.User) { user.TokensRevokedAt = &at })
+}
+
+func (m *UserRepository) GetTokensRevokedAt(ctx context.Context, id uint) (*time.Time, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	user, ok := m.users[id]
+	if !ok {
+		return nil, gorm.ErrRecordNotFound
+	}
+	return user.TokensRevokedAt, nil
+}
+
+func (m *UserRepository) IncrementFailedLogins(ctx context.Context, id uint) (int, error) {
+	var attempts int
+	err := m.update(id, func(user *models.User) {
+		user.FailedLoginAttempts++
+		attempts = user.FailedLoginAttempts
+	})
+	return attempts, err
+}
+
+func (m *UserRepository) SetLoginLock(ctx context.Context, id uint, lockedUntil *time.Time) error {
+	return m.update(id, func(user *models.User) {
+		user.FailedLoginAttempts = 0
+		user.LockedUntil = lockedUntil
+	})
+}
+
+func (m *UserRepository) UpdateTransactionPIN(ctx context.Context, id uint, hashedPIN string) error {
+	return m.update(id, func(user *models.User) {
+		user.TransactionPIN = hashedPIN
+		user.FailedPINAttempts = 0
+		user.PINLockedUntil = nil
+	})
+}
+
+func (m *UserRepository) IncrementFailedPINAttempts(ctx context.Context, id uint) (int, error) {
+	var attempts int
+	err := m.update(id, func(user *models.User) {
+		user.FailedPINAttempts++
+		attempts = user.FailedPINAttempts
+	})
+	return attempts, err
+}
+
+func (m *UserRepository) SetPINLock(ctx context.Context, id uint, lockedUntil *time.Time) error {
+	return m.update(id, func(user *models.User) {
+		user.FailedPINAttempts = 0
+		user.PINLockedUntil = lockedUntil
+	})
+}
+
+func (m *UserRepository) ListDeletionsDue(ctx context.Context, before time.Time, limit int) ([]models.User, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	var users []models.User
+	for _, user := range inOrder(m.users) {
+		if user.DeletionRequestedAt != nil && !user.DeletionRequestedAt.After(before) && user.AnonymizedAt == nil {
+			users = append(users, *user)
+		}
+	}
+	sortStable(users, func(a, b models.User) bool { return a.DeletionRequestedAt.Before(*b.DeletionRequestedAt) })
+	return page(users, 0, limit), nil
+}
+
+// load returns a stored user for filling in the association of another record, as gorm preloads it;
+// a zero user is returned when the repository is nil or holds no such user
+func (m *UserRepository) load(id uint) models.User {
+	if m == nil {
+		return models.User{}
+	}
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	if user, ok := m.users[id]; ok {
+		return *user
+	}
+	return models.User{}
+}
@@ -0,0 +1,269 @@
+package memory
+
+import (
+	"context"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/limistah/wallet-service/internal/models"
+	"github.com/limistah/wallet-service/internal/repositories"
+	"github.com/shopspring/decimal"
+	"gorm.io/gorm"
+)
+
+// WalletRepository is an in-memory repositories.WalletRepository. Saving a balance below zero fails,
+// as the check constraint of the wallets table makes it; wallets are created as they are given
+type WalletRepository struct {
+	mu        sync.RWMutex
+	wallets   map[uint]*models.Wallet
+	idCounter uint
+	// users fills in the owners of the wallets read, when set; without it a wallet keeps the owner it
+	// was stored with
+	users *UserRepository
+}
+
+// NewWalletRepository creates an empty wallet repository
+func NewWalletRepository() *WalletRepository {
+	return &WalletRepository{
+		wallets: make(map[uint]*models.Wallet),
+	}
+}
+
+// Create stores the wallet, keeping an ID that is already set; wallets belong to the default tenant
+// unless they are created for another one
+func (m *WalletRepository) Create(ctx context.Context, wallet *models.Wallet) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if wallet.TenantID == 0 {
+		wallet.TenantID = models.DefaultTenantID
+	}
+	if _, ok := m.wallets[wallet.ID]; ok {
+		return gorm.ErrDuplicatedKey
+	}
+	if wallet.ID == 0 {
+		m.idCounter++
+		wallet.ID = m.idCounter
+	} else if wallet.ID > m.idCounter {
+		m.idCounter = wallet.ID
+	}
+	stamp(wallet, true)
+	m.wallets[wallet.ID] = m.stored(wallet)
+	return nil
+}
+
+func (m *WalletRepository) GetByID(ctx context.Context, id uint) (*models.Wallet, error) {
+	return m.find(func(wallet *models.Wallet) bool { return wallet.ID == id })
+}
+
+// GetByUserID returns the default live wallet of a user, or their first one when none is the default
+func (m *WalletRepository) GetByUserID(ctx context.Context, userID uint) (*models.Wallet, error) {
+	live := func(wallet *models.Wallet) bool { return wallet.UserID == userID && !wallet.Sandbox }
+	if wallet, err := m.find(func(wallet *models.Wallet) bool { return live(wallet) && wallet.IsDefault }); err == nil {
+		return wallet, nil
+	}
+	return m.find(live)
+}
+
+func (m *WalletRepository) GetSandboxByUserID(ctx context.Context, userID uint) (*models.Wallet, error) {
+	return m.find(func(wallet *models.Wallet) bool { return wallet.UserID == userID && wallet.Sandbox })
+}
+
+func (m *WalletRepository) Update(ctx context.Context, wallet *models.Wallet) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if wallet.Balance.IsNegative() {
+		return gorm.ErrCheckConstraintViolated
+	}
+	stamp(wallet, false)
+	m.wallets[wallet.ID] = m.stored(wallet)
+	return nil
+}
+
+// UpdateBalance sets the balance of a wallet still at version, returning gorm.ErrRecordNotFound when
+// another update got there first
+func (m *WalletRepository) UpdateBalance(ctx context.Context, walletID uint, newBalance decimal.Decimal, version uint) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	wallet, ok := m.wallets[walletID]
+	if !ok || wallet.Version != version {
+		return gorm.ErrRecordNotFound
+	}
+	if newBalance.IsNegative() {
+		return gorm.ErrCheckConstraintViolated
+	}
+	wallet.Balance = newBalance
+	wallet.Version++
+	wallet.UpdatedAt = time.Now()
+	return nil
+}
+
+// AdjustBalance adds amount to the balance of a wallet, returning the balance before it
+func (m *WalletRepository) AdjustBalance(ctx context.Context, walletID uint, amount decimal.Decimal) (decimal.Decimal, bool, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	wallet, ok := m.wallets[walletID]
+	if !ok {
+		return decimal.Zero, false, gorm.ErrRecordNotFound
+	}
+	balanceBefore := wallet.Balance
+	if balanceBefore.Add(amount).IsNegative() {
+		return decimal.Zero, false, gorm.ErrCheckConstraintViolated
+	}
+	wallet.Balance = balanceBefore.Add(amount)
+	wallet.Version++
+	wallet.UpdatedAt = time.Now()
+	return balanceBefore, true, nil
+}
+
+func (m *WalletRepository) List(ctx context.Context, offset, limit int) ([]models.Wallet, error) {
+	wallets := m.filter(func(*models.Wallet) bool { return true })
+	wallets = page(wallets, offset, limit)
+	m.preload(wallets)
+	return wallets, nil
+}
+
+// ListWithFilter returns a page of the matching wallets in ID order and how many match in all
+func (m *WalletRepository) ListWithFilter(ctx context.Context, filter repositories.WalletFilter, offset, limit int) ([]models.Wallet, int64, error) {
+	wallets := m.filter(func(wallet *models.Wallet) bool {
+		return (filter.Status == "" || wallet.Status == filter.Status) &&
+			(filter.Currency == "" || wallet.Currency == filter.Currency) &&
+			(filter.MinBalance == nil || !wallet.Balance.LessThan(*filter.MinBalance)) &&
+			(filter.MaxBalance == nil || !wallet.Balance.GreaterThan(*filter.MaxBalance))
+	})
+	if filter.UserEmail != "" {
+		m.preload(wallets)
+		matching := wallets[:0]
+		for _, wallet := range wallets {
+			if strings.Contains(wallet.User.Email, filter.UserEmail) {
+				matching = append(matching, wallet)
+			}
+		}
+		wallets = matching
+	}
+	total := int64(len(wallets))
+	wallets = page(wallets, offset, limit)
+	m.preload(wallets)
+	return wallets, total, nil
+}
+
+func (m *WalletRepository) GetAllForReconciliation(ctx context.Context) ([]models.Wallet, error) {
+	return m.List(ctx, 0, 0)
+}
+
+// ListForReconciliation returns at most limit wallets of the scope with an ID above afterID in ID order
+func (m *WalletRepository) ListForReconciliation(ctx context.Context, scope models.ReconciliationScope, afterID uint, limit int) ([]models.Wallet, error) {
+	wallets := m.filter(func(wallet *models.Wallet) bool { return wallet.ID > afterID && scope.Matches(wallet) })
+	return page(wallets, 0, limit), nil
+}
+
+func (m *WalletRepository) CountForReconciliation(ctx context.Context, scope models.ReconciliationScope) (int64, error) {
+	return int64(len(m.filter(scope.Matches))), nil
+}
+
+func (m *WalletRepository) SetTier(ctx context.Context, walletID uint, tierID *uint) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	wallet, ok := m.wallets[walletID]
+	if !ok {
+		return gorm.ErrRecordNotFound
+	}
+	wallet.TierID = tierID
+	wallet.UpdatedAt = time.Now()
+	return nil
+}
+
+// ListByUserID returns the live or sandbox wallets of a user in ID order, only those labelled nickname
+// when one is given
+func (m *WalletRepository) ListByUserID(ctx context.Context, userID uint, sandbox bool, nickname string) ([]models.Wallet, error) {
+	wallets := m.filter(func(wallet *models.Wallet) bool {
+		return wallet.UserID == userID && wallet.Sandbox == sandbox &&
+			(nickname == "" || strings.EqualFold(wallet.Nickname, nickname))
+	})
+	return wallets, nil
+}
+
+func (m *WalletRepository) SetNickname(ctx context.Context, walletID uint, nickname string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	wallet, ok := m.wallets[walletID]
+	if !ok {
+		return gorm.ErrRecordNotFound
+	}
+	wallet.Nickname = nickname
+	wallet.UpdatedAt = time.Now()
+	return nil
+}
+
+// SetDefault makes a live wallet of a user their default one, clearing the flag of their other wallets
+func (m *WalletRepository) SetDefault(ctx context.Context, userID, walletID uint) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	wallet, ok := m.wallets[walletID]
+	if !ok || wallet.UserID != userID || wallet.Sandbox {
+		return gorm.ErrRecordNotFound
+	}
+	now := time.Now()
+	for _, other := range m.wallets {
+		if other.UserID == userID && other.IsDefault && other.ID != walletID {
+			other.IsDefault = false
+			other.UpdatedAt = now
+		}
+	}
+	wallet.IsDefault = true
+	wallet.UpdatedAt = now
+	return nil
+}
+
+// stored copies a wallet for storing without the records it is loaded with
+func (m *WalletRepository) stored(wallet *models.Wallet) *models.Wallet {
+	stored := clone(wallet)
+	if m.users != nil {
+		stored.User = models.User{}
+	}
+	stored.Tier = nil
+	stored.Transactions = nil
+	return stored
+}
+
+// find returns the first wallet in ID order matching keep, with its owner
+func (m *WalletRepository) find(keep func(*models.Wallet) bool) (*models.Wallet, error) {
+	m.mu.RLock()
+	var found *models.Wallet
+	for _, wallet := range inOrder(m.wallets) {
+		if keep(wallet) {
+			found = clone(wallet)
+			break
+		}
+	}
+	m.mu.RUnlock()
+	if found == nil {
+		return nil, gorm.ErrRecordNotFound
+	}
+	if m.users != nil {
+		found.User = m.users.load(found.UserID)
+	}
+	return found, nil
+}
+
+// filter returns the wallets matching keep in ID order
+func (m *WalletRepository) filter(keep func(*models.Wallet) bool) []models.Wallet {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	wallets := make([]models.Wallet, 0, len(m.wallets))
+	for _, wallet := range inOrder(m.wallets) {
+		if keep(wallet) {
+			wallets = append(wallets, *wallet)
+		}
+	}
+	return wallets
+}
+
+func (m *WalletRepository) preload(wallets []models.Wallet) {
+	if m.users == nil {
+		return
+	}
+	for i := range wallets {
+		wallets[i].User = m.users.load(wallets[i].UserID)
+	}
+}
@@ -0,0 +1,77 @@
+package memory
+
+import (
+	"context"
+	"sync"
+
+	"github.com/limistah/wallet-service/internal/models"
+	"gorm.io/gorm"
+)
+
+// WalletTierRepository is an in-memory repositories.WalletTierRepository
+type WalletTierRepository struct {
+	mu        sync.RWMutex
+	tiers     map[uint]*models.WalletTier
+	idCounter uint
+}
+
+// NewWalletTierRepository creates an empty wallet tier repository
+func NewWalletTierRepository() *WalletTierRepository {
+	return &WalletTierRepository{
+		tiers: make(map[uint]*models.WalletTier),
+	}
+}
+
+func (m *WalletTierRepository) Create(ctx context.Context, tier *models.WalletTier) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.idCounter++
+	tier.ID = m.idCounter
+	stamp(tier, true)
+	m.tiers[tier.ID] = clone(tier)
+	return nil
+}
+
+func (m *WalletTierRepository) GetByID(ctx context.Context, id uint) (*models.WalletTier, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	if tier, ok := m.tiers[id]; ok {
+		return clone(tier), nil
+	}
+	return nil, gorm.ErrRecordNotFound
+}
+
+func (m *WalletTierRepository) GetDefault(ctx context.Context) (*models.WalletTier, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	for _, tier := range inOrder(m.tiers) {
+		if tier.IsDefault {
+			return clone(tier), nil
+		}
+	}
+	return nil, gorm.ErrRecordNotFound
+}
+
+func (m *WalletTierRepository) List(ctx context.Context) ([]models.WalletTier, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	tiers := make([]models.WalletTier, 0, len(m.tiers))
+	for _, tier := range inOrder(m.tiers) {
+		tiers = append(tiers, *tier)
+	}
+	return tiers, nil
+}
+
+// Update saves the tier; making it the default clears the flag on every other tier
+func (m *WalletTierRepository) Update(ctx context.Context, tier *models.WalletTier) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if tier.IsDefault {
+		for _, other := range m.tiers {
+			other.IsDefault = false
+		}
+	}
+	stamp(tier, false)
+	m.tiers[tier.ID] = clone(tier)
+	return nil
+}
@@ -0,0 +1,226 @@
+package memory
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/limistah/wallet-service/internal/models"
+	"github.com/limistah/wallet-service/internal/repositories"
+	"gorm.io/gorm"
+)
+
+// WebhookRepository is an in-memory repositories.WebhookRepository
+type WebhookRepository struct {
+	mu                    sync.RWMutex
+	subscriptions         map[uint]*models.WebhookSubscription
+	deliveries            map[uint]*models.WebhookDelivery
+	attempts              map[uint]*models.WebhookDeliveryAttempt
+	subscriptionIDCounter uint
+	deliveryIDCounter     uint
+	attemptIDCounter      uint
+}
+
+// NewWebhookRepository creates an empty webhook repository
+func NewWebhookRepository() *WebhookRepository {
+	return &WebhookRepository{
+		subscriptions: make(map[uint]*models.WebhookSubscription),
+		deliveries:    make(map[uint]*models.WebhookDelivery),
+		attempts:      make(map[uint]*models.WebhookDeliveryAttempt),
+	}
+}
+
+func (m *WebhookRepository) CreateSubscription(ctx context.Context, subscription *models.WebhookSubscription) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.subscriptionIDCounter++
+	subscription.ID = m.subscriptionIDCounter
+	stamp(subscription, true)
+	m.subscriptions[subscription.ID] = clone(subscription)
+	return nil
+}
+
+func (m *WebhookRepository) GetSubscription(ctx context.Context, id uint) (*models.WebhookSubscription, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	if subscription, ok := m.subscriptions[id]; ok {
+		return clone(subscription), nil
+	}
+	return nil, gorm.ErrRecordNotFound
+}
+
+// ListSubscriptionsByUser returns the subscriptions of a user newest first
+func (m *WebhookRepository) ListSubscriptionsByUser(ctx context.Context, userID uint) ([]models.WebhookSubscription, error) {
+	subscriptions := m.listSubscriptions(func(subscription *models.WebhookSubscription) bool { return subscription.UserID == userID })
+	sortStable(subscriptions, func(a, b models.WebhookSubscription) bool {
+		return a.CreatedAt.After(b.CreatedAt) || (a.CreatedAt.Equal(b.CreatedAt) && a.ID > b.ID)
+	})
+	return subscriptions, nil
+}
+
+func (m *WebhookRepository) ListActiveSubscriptionsByUser(ctx context.Context, userID uint) ([]models.WebhookSubscription, error) {
+	return m.listSubscriptions(func(subscription *models.WebhookSubscription) bool {
+		return subscription.UserID == userID && subscription.Active
+	}), nil
+}
+
+func (m *WebhookRepository) DisableSubscription(ctx context.Context, id uint) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if subscription, ok := m.subscriptions[id]; ok {
+		subscription.Active = false
+		subscription.UpdatedAt = time.Now()
+	}
+	return nil
+}
+
+func (m *WebhookRepository) CreateDelivery(ctx context.Context, delivery *models.WebhookDelivery) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.deliveryIDCounter++
+	delivery.ID = m.deliveryIDCounter
+	stamp(delivery, true)
+	stored := clone(delivery)
+	stored.Subscription = models.WebhookSubscription{}
+	m.deliveries[delivery.ID] = stored
+	return nil
+}
+
+// GetDelivery returns a delivery with its subscription
+func (m *WebhookRepository) GetDelivery(ctx context.Context, id uint) (*models.WebhookDelivery, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	delivery, ok := m.deliveries[id]
+	if !ok {
+		return nil, gorm.ErrRecordNotFound
+	}
+	loaded := clone(delivery)
+	if subscription, ok := m.subscriptions[delivery.SubscriptionID]; ok {
+		loaded.Subscription = *subscription
+	}
+	return loaded, nil
+}
+
+// ListDeliveries returns the deliveries of a subscription newest first
+func (m *WebhookRepository) ListDeliveries(ctx context.Context, subscriptionID uint, offset, limit int) ([]models.WebhookDelivery, error) {
+	deliveries := m.listDeliveries(func(delivery *models.WebhookDelivery) bool { return delivery.SubscriptionID == subscriptionID })
+	sortStable(deliveries, func(a, b models.WebhookDelivery) bool {
+		return a.CreatedAt.After(b.CreatedAt) || (a.CreatedAt.Equal(b.CreatedAt) && a.ID > b.ID)
+	})
+	return page(deliveries, offset, limit), nil
+}
+
+// SearchDeliveries returns the matching deliveries most recently updated first
+func (m *WebhookRepository) SearchDeliveries(ctx context.Context, filter repositories.WebhookDeliveryFilter, offset, limit int) ([]models.WebhookDelivery, error) {
+	deliveries := m.listDeliveries(func(delivery *models.WebhookDelivery) bool {
+		return (filter.Status == "" || delivery.Status == filter.Status) &&
+			(filter.EventType == "" || delivery.EventType == filter.EventType) &&
+			(filter.SubscriptionID == 0 || delivery.SubscriptionID == filter.SubscriptionID)
+	})
+	sortStable(deliveries, func(a, b models.WebhookDelivery) bool {
+		return a.UpdatedAt.After(b.UpdatedAt) || (a.UpdatedAt.Equal(b.UpdatedAt) && a.ID > b.ID)
+	})
+	return page(deliveries, offset, limit), nil
+}
+
+// UpdateDelivery saves the delivery state of a delivery: its status, attempts, next attempt, last
+// response and when it was delivered or died
+func (m *WebhookRepository) UpdateDelivery(ctx context.Context, delivery *models.WebhookDelivery) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	stored, ok := m.deliveries[delivery.ID]
+	if !ok {
+		return nil
+	}
+	stored.Status = delivery.Status
+	stored.Attempts = delivery.Attempts
+	stored.NextAttemptAt = delivery.NextAttemptAt
+	stored.LastStatusCode = delivery.LastStatusCode
+	stored.LastDurationMS = delivery.LastDurationMS
+	stored.LastError = delivery.LastError
+	stored.DeliveredAt = delivery.DeliveredAt
+	stored.DeadAt = delivery.DeadAt
+	stored.UpdatedAt = time.Now()
+	delivery.UpdatedAt = stored.UpdatedAt
+	return nil
+}
+
+func (m *WebhookRepository) Requeue(ctx context.Context, id uint, at time.Time) (bool, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	delivery, ok := m.deliveries[id]
+	if !ok || delivery.Status != models.WebhookDeliveryDead {
+		return false, nil
+	}
+	delivery.Status = models.WebhookDeliveryPending
+	delivery.Attempts = 0
+	delivery.NextAttemptAt = at
+	delivery.DeadAt = nil
+	delivery.UpdatedAt = time.Now()
+	return true, nil
+}
+
+func (m *WebhookRepository) CreateAttempt(ctx context.Context, attempt *models.WebhookDeliveryAttempt) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.attemptIDCounter++
+	attempt.ID = m.attemptIDCounter
+	stamp(attempt, true)
+	m.attempts[attempt.ID] = clone(attempt)
+	return nil
+}
+
+// ListAttempts returns the attempts of a delivery oldest first
+func (m *WebhookRepository) ListAttempts(ctx context.Context, deliveryID uint) ([]models.WebhookDeliveryAttempt, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	var attempts []models.WebhookDeliveryAttempt
+	for _, attempt := range inOrder(m.attempts) {
+		if attempt.DeliveryID == deliveryID {
+			attempts = append(attempts, *attempt)
+		}
+	}
+	sortStable(attempts, func(a, b models.WebhookDeliveryAttempt) bool { return a.CreatedAt.Before(b.CreatedAt) })
+	return attempts, nil
+}
+
+// Delivery returns the stored delivery with the given ID, or nil
+func (m *WebhookRepository) Delivery(id uint) *models.WebhookDelivery {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	if delivery, ok := m.deliveries[id]; ok {
+		return clone(delivery)
+	}
+	return nil
+}
+
+// DeliveryCount returns how many deliveries are stored
+func (m *WebhookRepository) DeliveryCount() int {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return len(m.deliveries)
+}
+
+func (m *WebhookRepository) listSubscriptions(keep func(*models.WebhookSubscription) bool) []models.WebhookSubscription {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	var subscriptions []models.WebhookSubscription
+	for _, subscription := range inOrder(m.subscriptions) {
+		if keep(subscription) {
+			subscriptions = append(subscriptions, *subscription)
+		}
+	}
+	return subscriptions
+}
+
+func (m *WebhookRepository) listDeliveries(keep func(*models.WebhookDelivery) bool) []models.WebhookDelivery {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	var deliveries []models.WebhookDelivery
+	for _, delivery := range inOrder(m.deliveries) {
+		if keep(delivery) {
+			deliveries = append(deliveries, *delivery)
+		}
+	}
+	return deliveries
+}
@@ -0,0 +1,75 @@
+package repositories
+
+import (
+	"context"
+	"time"
+
+	"github.com/limistah/wallet-service/internal/models"
+	"gorm.io/gorm"
+)
+
+type moneyRequestRepository struct {
+	db *gorm.DB
+}
+
+// NewMoneyRequestRepository creates a new money request repository
+func NewMoneyRequestRepository(db *gorm.DB) MoneyRequestRepository {
+	return &moneyRequestRepository{db: db}
+}
+
+func (r *moneyRequestRepository) Create(ctx context.Context, request *models.MoneyRequest) error {
+	return withContext(r.db, ctx).Create(request).Error
+}
+
+func (r *moneyRequestRepository) GetByID(ctx context.Context, id uint) (*models.MoneyRequest, error) {
+	var request models.MoneyRequest
+	err := withContext(r.db, ctx).Preload("Requester").Preload("Payer").First(&request, id).Error
+	if err != nil {
+		return nil, err
+	}
+	return &request, nil
+}
+
+// UpdateStatus moves a request out of the expected status; it reports false when another
+// response got there first
+func (r *moneyRequestRepository) UpdateStatus(ctx context.Context, id uint, from, to models.MoneyRequestStatus) (bool, error) {
+	updates := map[string]interface{}{"status": to}
+	if from == models.MoneyRequestStatusPending {
+		updates["responded_at"] = time.Now()
+	}
+	if to == models.MoneyRequestStatusPending {
+		updates["responded_at"] = nil
+	}
+
+	result := withContext(r.db, ctx).Model(&models.MoneyRequest{}).
+		Where("id = ? AND status = ?", id, from).
+		Updates(updates)
+	if result.Error != nil {
+		return false, result.Error
+	}
+	return result.RowsAffected > 0, nil
+}
+
+func (r *moneyRequestRepository) SetTransaction(ctx context.Context, id, transactionID uint) error {
+	return withContext(r.db, ctx).Model(&models.MoneyRequest{}).Where("id = ?", id).Update("transaction_id", transactionID).Error
+}
+
+func (r *moneyRequestRepository) ListByUser(ctx context.Context, userID uint, direction models.MoneyRequestDirection, status models.MoneyRequestStatus, offset, limit int) ([]models.MoneyRequest, error) {
+	var requests []models.MoneyRequest
+	query := withContext(r.db, ctx).Preload("Requester").Preload("Payer")
+
+	switch direction {
+	case models.MoneyRequestIncoming:
+		query = query.Where("payer_id = ?", userID)
+	case models.MoneyRequestOutgoing:
+		query = query.Where("requester_id = ?", userID)
+	default:
+		query = query.Where("payer_id = ? OR requester_id = ?", userID, userID)
+	}
+	if status != "" {
+		query = query.Where("status = ?", status)
+	}
+
+	err := query.Order("created_at DESC, id DESC").Offset(offset).Limit(limit).Find(&requests).Error
+	return requests, err
+}
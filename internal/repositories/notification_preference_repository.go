@@ -0,0 +1,34 @@
+package repositories
+
+import (
+	"context"
+
+	"github.com/limistah/wallet-service/internal/models"
+	"gorm.io/gorm"
+)
+
+type notificationPreferenceRepository struct {
+	db *gorm.DB
+}
+
+// NewNotificationPreferenceRepository creates a new notification preference repository
+func NewNotificationPreferenceRepository(db *gorm.DB) NotificationPreferenceRepository {
+	return &notificationPreferenceRepository{db: db}
+}
+
+func (r *notificationPreferenceRepository) GetByUserID(ctx context.Context, userID uint) (*models.NotificationPreference, error) {
+	var preference models.NotificationPreference
+	err := withContext(r.db, ctx).Where("user_id = ?", userID).First(&preference).Error
+	if err != nil {
+		return nil, err
+	}
+	return &preference, nil
+}
+
+func (r *notificationPreferenceRepository) Save(ctx context.Context, preference *models.NotificationPreference) error {
+	// Select("*") makes false values persist instead of falling back to column defaults
+	if preference.ID == 0 {
+		return withContext(r.db, ctx).Select("*").Create(preference).Error
+	}
+	return withContext(r.db, ctx).Save(preference).Error
+}
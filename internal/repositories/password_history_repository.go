@@ -0,0 +1,43 @@
+package repositories
+
+import (
+	"context"
+
+	"github.com/limistah/wallet-service/internal/models"
+	"gorm.io/gorm"
+)
+
+type passwordHistoryRepository struct {
+	db *gorm.DB
+}
+
+// NewPasswordHistoryRepository creates a new password history repository
+func NewPasswordHistoryRepository(db *gorm.DB) PasswordHistoryRepository {
+	return &passwordHistoryRepository{db: db}
+}
+
+func (r *passwordHistoryRepository) Create(ctx context.Context, entry *models.PasswordHistory) error {
+	return withContext(r.db, ctx).Create(entry).Error
+}
+
+// ListRecent returns the latest replaced passwords of a user, newest first
+func (r *passwordHistoryRepository) ListRecent(ctx context.Context, userID uint, limit int) ([]models.PasswordHistory, error) {
+	var entries []models.PasswordHistory
+	err := withContext(r.db, ctx).Where("user_id = ?", userID).Order("id DESC").Limit(limit).Find(&entries).Error
+	return entries, err
+}
+
+// Prune deletes the replaced passwords of a user beyond the latest keep
+func (r *passwordHistoryRepository) Prune(ctx context.Context, userID uint, keep int) error {
+	var keepIDs []uint
+	if err := withContext(r.db, ctx).Model(&models.PasswordHistory{}).Where("user_id = ?", userID).
+		Order("id DESC").Limit(keep).Pluck("id", &keepIDs).Error; err != nil {
+		return err
+	}
+
+	query := withContext(r.db, ctx).Where("user_id = ?", userID)
+	if len(keepIDs) > 0 {
+		query = query.Where("id NOT IN ?", keepIDs)
+	}
+	return query.Delete(&models.PasswordHistory{}).Error
+}
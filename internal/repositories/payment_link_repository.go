@@ -0,0 +1,76 @@
+package repositories
+
+import (
+	"context"
+	"time"
+
+	"github.com/limistah/wallet-service/internal/models"
+	"github.com/shopspring/decimal"
+	"gorm.io/gorm"
+)
+
+type paymentLinkRepository struct {
+	db *gorm.DB
+}
+
+// NewPaymentLinkRepository creates a new payment link repository
+func NewPaymentLinkRepository(db *gorm.DB) PaymentLinkRepository {
+	return &paymentLinkRepository{db: db}
+}
+
+func (r *paymentLinkRepository) Create(ctx context.Context, link *models.PaymentLink) error {
+	return withContext(r.db, ctx).Create(link).Error
+}
+
+func (r *paymentLinkRepository) GetByID(ctx context.Context, id uint) (*models.PaymentLink, error) {
+	var link models.PaymentLink
+	err := withContext(r.db, ctx).First(&link, id).Error
+	if err != nil {
+		return nil, err
+	}
+	return &link, nil
+}
+
+func (r *paymentLinkRepository) GetByToken(ctx context.Context, token string) (*models.PaymentLink, error) {
+	var link models.PaymentLink
+	err := withContext(r.db, ctx).Preload("User").Where("token = ?", token).First(&link).Error
+	if err != nil {
+		return nil, err
+	}
+	return &link, nil
+}
+
+func (r *paymentLinkRepository) ListByUserID(ctx context.Context, userID uint, offset, limit int) ([]models.PaymentLink, error) {
+	var links []models.PaymentLink
+	err := withContext(r.db, ctx).Where("user_id = ?", userID).
+		Order("created_at DESC, id DESC").
+		Offset(offset).Limit(limit).
+		Find(&links).Error
+	return links, err
+}
+
+func (r *paymentLinkRepository) IncrementViews(ctx context.Context, id uint) error {
+	return withContext(r.db, ctx).Model(&models.PaymentLink{}).Where("id = ?", id).
+		UpdateColumn("view_count", gorm.Expr("view_count + 1")).Error
+}
+
+// UpdateStatus moves a link out of the expected status; it reports false when the link was
+// already moved on by someone else
+func (r *paymentLinkRepository) UpdateStatus(ctx context.Context, id uint, from, to models.PaymentLinkStatus) (bool, error) {
+	result := withContext(r.db, ctx).Model(&models.PaymentLink{}).
+		Where("id = ? AND status = ?", id, from).
+		Update("status", to)
+	if result.Error != nil {
+		return false, result.Error
+	}
+	return result.RowsAffected > 0, nil
+}
+
+func (r *paymentLinkRepository) RecordPayment(ctx context.Context, id uint, amount decimal.Decimal) error {
+	return withContext(r.db, ctx).Model(&models.PaymentLink{}).Where("id = ?", id).
+		Updates(map[string]interface{}{
+			"payment_count":   gorm.Expr("payment_count + 1"),
+			"total_collected": gorm.Expr("total_collected + ?", amount),
+			"last_paid_at":    time.Now(),
+		}).Error
+}
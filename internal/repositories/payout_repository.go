@@ -0,0 +1,81 @@
+package repositories
+
+import (
+	"context"
+	"time"
+
+	"github.com/limistah/wallet-service/internal/models"
+	"gorm.io/gorm"
+)
+
+type payoutRepository struct {
+	db *gorm.DB
+}
+
+// NewPayoutRepository creates a new payout repository
+func NewPayoutRepository(db *gorm.DB) PayoutRepository {
+	return &payoutRepository{db: db}
+}
+
+func (r *payoutRepository) Create(ctx context.Context, payout *models.Payout) error {
+	return withContext(r.db, ctx).Create(payout).Error
+}
+
+func (r *payoutRepository) GetByReference(ctx context.Context, reference string) (*models.Payout, error) {
+	var payout models.Payout
+	err := withContext(r.db, ctx).Where("reference = ?", reference).First(&payout).Error
+	if err != nil {
+		return nil, err
+	}
+	return &payout, nil
+}
+
+func (r *payoutRepository) GetByProviderReference(ctx context.Context, provider, providerReference string) (*models.Payout, error) {
+	var payout models.Payout
+	err := withContext(r.db, ctx).Where("provider = ? AND provider_reference = ?", provider, providerReference).First(&payout).Error
+	if err != nil {
+		return nil, err
+	}
+	return &payout, nil
+}
+
+func (r *payoutRepository) UpdateProviderReference(ctx context.Context, id uint, providerReference string) error {
+	// Only the provider reference is written so a concurrent settlement is never overwritten
+	return withContext(r.db, ctx).Model(&models.Payout{}).Where("id = ?", id).
+		Update("provider_reference", providerReference).Error
+}
+
+func (r *payoutRepository) ListUnbatched(ctx context.Context, from, to time.Time) ([]models.Payout, error) {
+	var payouts []models.Payout
+	err := withContext(r.db, ctx).Where("status = ? AND settlement_batch_id IS NULL AND completed_at >= ? AND completed_at < ?", models.PayoutStatusCompleted, from, to).
+		Order("id ASC").
+		Find(&payouts).Error
+	return payouts, err
+}
+
+func (r *payoutRepository) GetByTransactionID(ctx context.Context, transactionID uint) (*models.Payout, error) {
+	var payout models.Payout
+	err := withContext(r.db, ctx).Where("transaction_id = ?", transactionID).First(&payout).Error
+	if err != nil {
+		return nil, err
+	}
+	return &payout, nil
+}
+
+func (r *payoutRepository) Settle(ctx context.Context, id uint, status models.PayoutStatus, failureReason string, at time.Time) (bool, error) {
+	result := withContext(r.db, ctx).Model(&models.Payout{}).
+		Where("id = ? AND status = ?", id, models.PayoutStatusPending).
+		Updates(map[string]interface{}{
+			"status":         status,
+			"failure_reason": failureReason,
+			"completed_at":   at,
+		})
+	return result.RowsAffected > 0, result.Error
+}
+
+func (r *payoutRepository) AssignToBatch(ctx context.Context, ids []uint, batchID uint) (int, error) {
+	result := withContext(r.db, ctx).Model(&models.Payout{}).
+		Where("id IN ? AND settlement_batch_id IS NULL", ids).
+		Update("settlement_batch_id", batchID)
+	return int(result.RowsAffected), result.Error
+}
@@ -0,0 +1,36 @@
+package repositories
+
+import (
+	"context"
+
+	"github.com/limistah/wallet-service/internal/models"
+	"gorm.io/gorm"
+)
+
+type phoneVerificationRepository struct {
+	db *gorm.DB
+}
+
+// NewPhoneVerificationRepository creates a new phone verification repository
+func NewPhoneVerificationRepository(db *gorm.DB) PhoneVerificationRepository {
+	return &phoneVerificationRepository{db: db}
+}
+
+func (r *phoneVerificationRepository) Create(ctx context.Context, verification *models.PhoneVerification) error {
+	return withContext(r.db, ctx).Create(verification).Error
+}
+
+func (r *phoneVerificationRepository) GetLatestByUserID(ctx context.Context, userID uint) (*models.PhoneVerification, error) {
+	var verification models.PhoneVerification
+	err := withContext(r.db, ctx).Where("user_id = ?", userID).
+		Order("created_at DESC, id DESC").
+		First(&verification).Error
+	if err != nil {
+		return nil, err
+	}
+	return &verification, nil
+}
+
+func (r *phoneVerificationRepository) Update(ctx context.Context, verification *models.PhoneVerification) error {
+	return withContext(r.db, ctx).Save(verification).Error
+}
@@ -0,0 +1,108 @@
+package repositories
+
+import (
+	"context"
+
+	"github.com/limistah/wallet-service/internal/models"
+	"gorm.io/gorm"
+)
+
+type providerStatementRepository struct {
+	db *gorm.DB
+}
+
+// NewProviderStatementRepository creates a new provider statement repository
+func NewProviderStatementRepository(db *gorm.DB) ProviderStatementRepository {
+	return &providerStatementRepository{db: db}
+}
+
+// Create saves a statement together with its lines
+func (r *providerStatementRepository) Create(ctx context.Context, statement *models.ProviderStatement) error {
+	return withContext(r.db, ctx).Create(statement).Error
+}
+
+// GetByID loads a statement with its lines in file order
+func (r *providerStatementRepository) GetByID(ctx context.Context, id uint) (*models.ProviderStatement, error) {
+	var statement models.ProviderStatement
+	err := withContext(r.db, ctx).
+		Preload("Lines", func(db *gorm.DB) *gorm.DB { return db.Order("line_number ASC") }).
+		First(&statement, id).Error
+	if err != nil {
+		return nil, err
+	}
+	return &statement, nil
+}
+
+func (r *providerStatementRepository) GetByChecksum(ctx context.Context, provider, checksum string) (*models.ProviderStatement, error) {
+	var statement models.ProviderStatement
+	err := withContext(r.db, ctx).Where("provider = ? AND checksum = ?", provider, checksum).First(&statement).Error
+	if err != nil {
+		return nil, err
+	}
+	return &statement, nil
+}
+
+// List returns statements newest first; an empty provider lists every statement
+func (r *providerStatementRepository) List(ctx context.Context, provider string, offset, limit int) ([]models.ProviderStatement, error) {
+	var statements []models.ProviderStatement
+	query := withContext(r.db, ctx).Model(&models.ProviderStatement{})
+	if provider != "" {
+		query = query.Where("provider = ?", provider)
+	}
+	err := query.Order("created_at DESC, id DESC").Offset(offset).Limit(limit).Find(&statements).Error
+	return statements, err
+}
+
+type statementLineRepository struct {
+	db *gorm.DB
+}
+
+// NewStatementLineRepository creates a new statement line repository
+func NewStatementLineRepository(db *gorm.DB) StatementLineRepository {
+	return &statementLineRepository{db: db}
+}
+
+func (r *statementLineRepository) GetByID(ctx context.Context, id uint) (*models.StatementLine, error) {
+	var line models.StatementLine
+	err := withContext(r.db, ctx).First(&line, id).Error
+	if err != nil {
+		return nil, err
+	}
+	return &line, nil
+}
+
+// List returns lines oldest first so exceptions are reviewed in order
+func (r *statementLineRepository) List(ctx context.Context, filter StatementLineFilter, offset, limit int) ([]models.StatementLine, error) {
+	var lines []models.StatementLine
+	query := withContext(r.db, ctx).Model(&models.StatementLine{})
+	if filter.Provider != "" {
+		query = query.Where("provider = ?", filter.Provider)
+	}
+	if filter.Status != "" {
+		query = query.Where("status = ?", filter.Status)
+	}
+	if filter.Reason != "" {
+		query = query.Where("reason = ?", filter.Reason)
+	}
+	if filter.StatementID != 0 {
+		query = query.Where("statement_id = ?", filter.StatementID)
+	}
+	err := query.Order("created_at ASC, id ASC").Offset(offset).Limit(limit).Find(&lines).Error
+	return lines, err
+}
+
+func (r *statementLineRepository) MatchedReferences(ctx context.Context, provider string, references []string) ([]string, error) {
+	var matched []string
+	if len(references) == 0 {
+		return matched, nil
+	}
+	err := withContext(r.db, ctx).Model(&models.StatementLine{}).
+		Where("provider = ? AND reference IN ? AND status = ?", provider, references, models.StatementLineStatusMatched).
+		Distinct().
+		Pluck("reference", &matched).Error
+	return matched, err
+}
+
+func (r *statementLineRepository) Update(ctx context.Context, line *models.StatementLine) error {
+	return withContext(r.db, ctx).Save(line).Error
+}
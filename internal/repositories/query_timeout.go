@@ -0,0 +1,180 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+	"hash/fnv"
+	"log"
+	"regexp"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// queryClass picks which of the QueryTimeouts bounds a statement
+type queryClass int
+
+const (
+	defaultQuery queryClass = iota
+	// balanceQuery is a read on the hot path of payments, such as loading a wallet or summing its ledger
+	balanceQuery
+	// reconciliationQuery is an aggregate over whole ledgers run by reconciliation and its reports
+	reconciliationQuery
+)
+
+type queryClassContextKey struct{}
+
+// queryTimeoutKey is where the cancel function and parent context of a statement's deadline are kept
+// between the callbacks before and after it
+const queryTimeoutKey = "query_timeout:parent"
+
+// QueryTimeouts bounds how long statements may run before their context is cancelled. A zero
+// duration leaves the class without a timeout
+type QueryTimeouts struct {
+	// Default applies to every statement not in another class
+	Default time.Duration
+	// Balance applies to wallet and ledger balance reads, which should fail fast instead of holding
+	// up a payment
+	Balance time.Duration
+	// Reconciliation applies to the aggregates of reconciliation and its reports
+	Reconciliation time.Duration
+}
+
+func (t QueryTimeouts) of(class queryClass) time.Duration {
+	switch class {
+	case balanceQuery:
+		return t.Balance
+	case reconciliationQuery:
+		return t.Reconciliation
+	default:
+		return t.Default
+	}
+}
+
+// withQueryClass returns a context whose statements are bounded by the timeout of class
+func withQueryClass(ctx context.Context, class queryClass) context.Context {
+	return context.WithValue(ctx, queryClassContextKey{}, class)
+}
+
+func queryClassFromContext(ctx context.Context) queryClass {
+	if ctx == nil {
+		return defaultQuery
+	}
+	class, _ := ctx.Value(queryClassContextKey{}).(queryClass)
+	return class
+}
+
+type queryDeadline struct {
+	parent  context.Context
+	timeout time.Duration
+	cancel  context.CancelFunc
+}
+
+// RegisterQueryTimeouts runs every statement under a context deadline picked by its class (see
+// QueryTimeouts), and logs statements cancelled by it with their SQL fingerprint. Deadlines of the
+// caller's own context are left alone and not logged. Rows read after the statement returns, as
+// with Scan and Rows, stay bound by the deadline until it passes
+func RegisterQueryTimeouts(db *gorm.DB, timeouts QueryTimeouts) error {
+	before := func(db *gorm.DB) { startQueryDeadline(db, timeouts) }
+	callbacks := db.Callback()
+	if err := callbacks.Create().Before("gorm:create").Register("query_timeout:before_create", before); err != nil {
+		return err
+	}
+	if err := callbacks.Create().After("gorm:create").Register("query_timeout:after_create", endQueryDeadline); err != nil {
+		return err
+	}
+	if err := callbacks.Query().Before("gorm:query").Register("query_timeout:before_query", before); err != nil {
+		return err
+	}
+	if err := callbacks.Query().After("gorm:query").Register("query_timeout:after_query", endQueryDeadline); err != nil {
+		return err
+	}
+	if err := callbacks.Update().Before("gorm:update").Register("query_timeout:before_update", before); err != nil {
+		return err
+	}
+	if err := callbacks.Update().After("gorm:update").Register("query_timeout:after_update", endQueryDeadline); err != nil {
+		return err
+	}
+	if err := callbacks.Delete().Before("gorm:delete").Register("query_timeout:before_delete", before); err != nil {
+		return err
+	}
+	if err := callbacks.Delete().After("gorm:delete").Register("query_timeout:after_delete", endQueryDeadline); err != nil {
+		return err
+	}
+	if err := callbacks.Raw().Before("gorm:raw").Register("query_timeout:before_raw", before); err != nil {
+		return err
+	}
+	if err := callbacks.Raw().After("gorm:raw").Register("query_timeout:after_raw", endQueryDeadline); err != nil {
+		return err
+	}
+	if err := callbacks.Row().Before("gorm:row").Register("query_timeout:before_row", before); err != nil {
+		return err
+	}
+	return callbacks.Row().After("gorm:row").Register("query_timeout:after_row", endRowDeadline)
+}
+
+func startQueryDeadline(db *gorm.DB, timeouts QueryTimeouts) {
+	parent := db.Statement.Context
+	if parent == nil {
+		parent = context.Background()
+	}
+	timeout := timeouts.of(queryClassFromContext(parent))
+	if timeout <= 0 {
+		return
+	}
+	ctx, cancel := context.WithTimeout(parent, timeout)
+	db.Statement.Context = ctx
+	db.Statement.Settings.Store(queryTimeoutKey, &queryDeadline{parent: parent, timeout: timeout, cancel: cancel})
+}
+
+func endQueryDeadline(db *gorm.DB) {
+	if deadline := finishQueryDeadline(db); deadline != nil {
+		deadline.cancel()
+	}
+}
+
+// endRowDeadline keeps the deadline running, since the rows of the statement are read after the
+// callbacks return and cancelling would close them
+func endRowDeadline(db *gorm.DB) {
+	finishQueryDeadline(db)
+}
+
+// finishQueryDeadline restores the context of the caller on the statement, so a reused query is not
+// bound by the deadline of an earlier run, and logs the statement if the deadline cancelled it
+func finishQueryDeadline(db *gorm.DB) *queryDeadline {
+	value, ok := db.Statement.Settings.LoadAndDelete(queryTimeoutKey)
+	if !ok {
+		return nil
+	}
+	deadline := value.(*queryDeadline)
+	db.Statement.Context = deadline.parent
+
+	if errors.Is(db.Error, context.DeadlineExceeded) && deadline.parent.Err() == nil {
+		fingerprint := QueryFingerprint(db.Statement.SQL.String())
+		log.Printf("Query timed out after %s [%08x]: %s", deadline.timeout, fingerprintHash(fingerprint), fingerprint)
+	}
+	return deadline
+}
+
+var (
+	fingerprintLiterals   = regexp.MustCompile(`'(?:[^']|'')*'|\b\d+(?:\.\d+)?\b`)
+	fingerprintInLists    = regexp.MustCompile(`\(\s*\?(?:\s*,\s*\?)*\s*\)`)
+	fingerprintWhitespace = regexp.MustCompile(`\s+`)
+)
+
+// QueryFingerprint normalizes a SQL statement so that runs differing only in their values read the
+// same: literals become placeholders, lists of placeholders collapse to one and whitespace is squeezed
+func QueryFingerprint(sql string) string {
+	fingerprint := fingerprintLiterals.ReplaceAllString(sql, "?")
+	fingerprint = fingerprintInLists.ReplaceAllString(fingerprint, "(?)")
+	fingerprint = fingerprintWhitespace.ReplaceAllString(fingerprint, " ")
+	return strings.TrimSpace(fingerprint)
+}
+
+// fingerprintHash gives a fingerprint a short ID to group timeouts by in log searches
+func fingerprintHash(fingerprint string) uint32 {
+	hash := fnv.New32a()
+	hash.Write([]byte(fingerprint))
+	return hash.Sum32()
+}
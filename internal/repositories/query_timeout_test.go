@@ -0,0 +1,56 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/limistah/wallet-service/internal/models"
+)
+
+func TestRegisterQueryTimeouts(t *testing.T) {
+	repos := newTenantTestDB(t)
+	if err := RegisterQueryTimeouts(repos.DB, QueryTimeouts{Balance: time.Nanosecond, Reconciliation: time.Minute}); err != nil {
+		t.Fatalf("RegisterQueryTimeouts() error = %v", err)
+	}
+
+	// Statements without a class run unbounded when there is no default timeout
+	wallet := &models.Wallet{UserID: 1, Currency: "USD"}
+	if err := repos.Wallet.Create(context.Background(), wallet); err != nil {
+		t.Fatalf("Create() error = %v", err)
+	}
+
+	if _, err := repos.Wallet.GetByID(context.Background(), wallet.ID); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("Expected the balance read to time out, got %v", err)
+	}
+	wallets, err := repos.Wallet.GetAllForReconciliation(context.Background())
+	if err != nil || len(wallets) != 1 {
+		t.Errorf("Expected reconciliation reads to use their own timeout, got %d wallets, %v", len(wallets), err)
+	}
+}
+
+func TestQueryFingerprint(t *testing.T) {
+	tests := []struct {
+		sql  string
+		want string
+	}{
+		{
+			sql:  "SELECT * FROM `wallets` WHERE user_id = 42 AND currency = 'USD'",
+			want: "SELECT * FROM `wallets` WHERE user_id = ? AND currency = ?",
+		},
+		{
+			sql:  "SELECT * FROM transactions\n\t\tWHERE status IN (?,?, ?) AND t1.id > ?",
+			want: "SELECT * FROM transactions WHERE status IN (?) AND t1.id > ?",
+		},
+		{
+			sql:  "UPDATE users SET name = 'O''Brien' WHERE id = 7",
+			want: "UPDATE users SET name = ? WHERE id = ?",
+		},
+	}
+	for _, tt := range tests {
+		if got := QueryFingerprint(tt.sql); got != tt.want {
+			t.Errorf("QueryFingerprint(%q) = %q, want %q", tt.sql, got, tt.want)
+		}
+	}
+}
@@ -1,6 +1,8 @@
 package repositories
 
 import (
+	"context"
+
 	"github.com/limistah/wallet-service/internal/models"
 	"gorm.io/gorm"
 )
@@ -14,34 +16,81 @@ func NewReconciliationRepository(db *gorm.DB) ReconciliationRepository {
 	return &reconciliationRepository{db: db}
 }
 
-func (r *reconciliationRepository) Create(report *models.ReconciliationReport) error {
-	return r.db.Create(report).Error
+func (r *reconciliationRepository) Create(ctx context.Context, report *models.ReconciliationReport) error {
+	return withContext(r.db, ctx).Create(report).Error
+}
+
+func (r *reconciliationRepository) GetByID(ctx context.Context, id uint) (*models.ReconciliationReport, error) {
+	var report models.ReconciliationReport
+	err := withContext(r.db, ctx).Preload("Wallet").First(&report, id).Error
+	if err != nil {
+		return nil, err
+	}
+	return &report, nil
+}
+
+func (r *reconciliationRepository) Update(ctx context.Context, report *models.ReconciliationReport) error {
+	return withContext(r.db, ctx).Omit("Wallet").Save(report).Error
 }
 
-func (r *reconciliationRepository) GetByWalletID(walletID uint) ([]models.ReconciliationReport, error) {
+func (r *reconciliationRepository) GetByWalletID(ctx context.Context, walletID uint) ([]models.ReconciliationReport, error) {
 	var reports []models.ReconciliationReport
-	err := r.db.Preload("Wallet").
+	err := withContext(r.db, ctx).Preload("Wallet").
 		Where("wallet_id = ?", walletID).
 		Order("created_at DESC").
 		Find(&reports).Error
 	return reports, err
 }
 
-func (r *reconciliationRepository) List(offset, limit int) ([]models.ReconciliationReport, error) {
+func (r *reconciliationRepository) List(ctx context.Context, offset, limit int) ([]models.ReconciliationReport, error) {
 	var reports []models.ReconciliationReport
-	err := r.db.Preload("Wallet").
+	err := withContext(r.db, ctx).Preload("Wallet").
 		Order("created_at DESC").
 		Offset(offset).Limit(limit).
 		Find(&reports).Error
 	return reports, err
 }
 
-func (r *reconciliationRepository) GetMismatches(offset, limit int) ([]models.ReconciliationReport, error) {
+// ExportReports hands the matching reports to fn in batches, oldest first, so exports never hold the
+// whole table in memory
+func (r *reconciliationRepository) ExportReports(ctx context.Context, filter ReconciliationReportFilter, batchSize int, fn func([]models.ReconciliationReport) error) error {
+	query := withContext(r.db, withQueryClass(ctx, reconciliationQuery)).Preload("Wallet")
+	if filter.Status != "" {
+		query = query.Where("status = ?", filter.Status)
+	}
+	if filter.From != nil {
+		query = query.Where("created_at >= ?", *filter.From)
+	}
+	if filter.To != nil {
+		query = query.Where("created_at < ?", *filter.To)
+	}
+
+	var batch []models.ReconciliationReport
+	return query.FindInBatches(&batch, batchSize, func(tx *gorm.DB, _ int) error {
+		return fn(batch)
+	}).Error
+}
+
+func (r *reconciliationRepository) GetMismatches(ctx context.Context, offset, limit int) ([]models.ReconciliationReport, error) {
 	var reports []models.ReconciliationReport
-	err := r.db.Preload("Wallet").
+	err := withContext(r.db, ctx).Preload("Wallet").
 		Where("status = ?", models.ReconciliationStatusMismatch).
 		Order("created_at DESC").
 		Offset(offset).Limit(limit).
 		Find(&reports).Error
 	return reports, err
 }
+
+func (r *reconciliationRepository) OpenMismatches(ctx context.Context) ([]OpenMismatchTotal, error) {
+	ctx = withQueryClass(ctx, reconciliationQuery)
+	latest := withContext(r.db, ctx).Model(&models.ReconciliationReport{}).Select("MAX(id)").Group("wallet_id")
+	var totals []OpenMismatchTotal
+	err := withContext(r.db, ctx).Table("reconciliation_reports AS r").
+		Select("w.currency AS currency, COUNT(*) AS wallets, SUM(ABS(r.difference)) AS amount").
+		Joins("JOIN wallets AS w ON w.id = r.wallet_id").
+		Where("r.id IN (?) AND r.status <> ?", latest, models.ReconciliationStatusMatch).
+		Group("w.currency").
+		Order("w.currency").
+		Scan(&totals).Error
+	return totals, err
+}
@@ -0,0 +1,52 @@
+package repositories
+
+import (
+	"context"
+	"testing"
+
+	"github.com/limistah/wallet-service/internal/models"
+	"github.com/shopspring/decimal"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+func TestReconciliationRepository_OpenMismatches(t *testing.T) {
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{Logger: logger.Default.LogMode(logger.Silent)})
+	if err != nil {
+		t.Fatalf("Failed to open database: %v", err)
+	}
+	if err := db.AutoMigrate(&models.Wallet{}, &models.ReconciliationReport{}); err != nil {
+		t.Fatalf("Failed to create tables: %v", err)
+	}
+	for id, currency := range map[int]string{1: "USD", 2: "USD", 3: "USD", 4: "NGN"} {
+		db.Exec("INSERT INTO wallets (id, user_id, currency) VALUES (?, ?, ?)", id, id, currency)
+	}
+	repo := NewReconciliationRepository(db)
+	report := func(walletID uint, status models.ReconciliationStatus, difference string) {
+		if err := repo.Create(context.Background(), &models.ReconciliationReport{WalletID: walletID, Status: status, Difference: decimal.RequireFromString(difference)}); err != nil {
+			t.Fatalf("Failed to create report: %v", err)
+		}
+	}
+
+	report(1, models.ReconciliationStatusMismatch, "-5")
+	report(1, models.ReconciliationStatusMismatch, "-7.50") // Only the latest report of a wallet counts
+	report(2, models.ReconciliationStatusMismatch, "40")
+	report(2, models.ReconciliationStatusMatch, "0") // Fixed since
+	report(3, models.ReconciliationStatusDoubleEntryError, "2.25")
+	report(4, models.ReconciliationStatusMismatch, "100")
+
+	totals, err := repo.OpenMismatches(context.Background())
+	if err != nil {
+		t.Fatalf("OpenMismatches() error = %v", err)
+	}
+	if len(totals) != 2 {
+		t.Fatalf("OpenMismatches() = %+v, want a total for NGN and USD", totals)
+	}
+	if totals[0].Currency != "NGN" || totals[0].Wallets != 1 || !totals[0].Amount.Equal(decimal.NewFromInt(100)) {
+		t.Errorf("NGN total = %+v, want 1 wallet off by 100", totals[0])
+	}
+	if totals[1].Currency != "USD" || totals[1].Wallets != 2 || !totals[1].Amount.Equal(decimal.RequireFromString("9.75")) {
+		t.Errorf("USD total = %+v, want 2 wallets off by 9.75", totals[1])
+	}
+}
@@ -0,0 +1,33 @@
+package repositories
+
+import (
+	"context"
+
+	"github.com/limistah/wallet-service/internal/models"
+	"gorm.io/gorm"
+)
+
+type reconciliationRunRepository struct {
+	db *gorm.DB
+}
+
+// NewReconciliationRunRepository creates a new reconciliation run repository
+func NewReconciliationRunRepository(db *gorm.DB) ReconciliationRunRepository {
+	return &reconciliationRunRepository{db: db}
+}
+
+func (r *reconciliationRunRepository) Create(ctx context.Context, run *models.ReconciliationRun) error {
+	return withContext(r.db, ctx).Create(run).Error
+}
+
+func (r *reconciliationRunRepository) GetByID(ctx context.Context, id uint) (*models.ReconciliationRun, error) {
+	var run models.ReconciliationRun
+	if err := withContext(r.db, ctx).First(&run, id).Error; err != nil {
+		return nil, err
+	}
+	return &run, nil
+}
+
+func (r *reconciliationRunRepository) Update(ctx context.Context, run *models.ReconciliationRun) error {
+	return withContext(r.db, ctx).Save(run).Error
+}
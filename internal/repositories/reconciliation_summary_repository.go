@@ -0,0 +1,43 @@
+package repositories
+
+import (
+	"context"
+	"time"
+
+	"github.com/limistah/wallet-service/internal/models"
+	"gorm.io/gorm"
+)
+
+type reconciliationSummaryRepository struct {
+	db *gorm.DB
+}
+
+// NewReconciliationSummaryRepository creates a new reconciliation summary repository
+func NewReconciliationSummaryRepository(db *gorm.DB) ReconciliationSummaryRepository {
+	return &reconciliationSummaryRepository{db: db}
+}
+
+func (r *reconciliationSummaryRepository) Create(ctx context.Context, summary *models.ReconciliationSummary) error {
+	return withContext(r.db, ctx).Create(summary).Error
+}
+
+func (r *reconciliationSummaryRepository) GetByDate(ctx context.Context, date time.Time) (*models.ReconciliationSummary, error) {
+	var summary models.ReconciliationSummary
+	if err := withContext(r.db, ctx).Where("date = ?", date.Format("2006-01-02")).First(&summary).Error; err != nil {
+		return nil, err
+	}
+	return &summary, nil
+}
+
+func (r *reconciliationSummaryRepository) List(ctx context.Context, offset, limit int) ([]models.ReconciliationSummary, error) {
+	var summaries []models.ReconciliationSummary
+	err := withContext(r.db, ctx).Order("date DESC").
+		Offset(offset).Limit(limit).
+		Find(&summaries).Error
+	return summaries, err
+}
+
+func (r *reconciliationSummaryRepository) MarkDelivered(ctx context.Context, id uint, at time.Time) error {
+	return withContext(r.db, ctx).Model(&models.ReconciliationSummary{}).Where("id = ?", id).
+		Update("delivered_at", at).Error
+}
@@ -0,0 +1,99 @@
+package repositories
+
+import (
+	"context"
+	"database/sql/driver"
+	"errors"
+	"math/rand"
+	"syscall"
+	"time"
+
+	"github.com/go-sql-driver/mysql"
+	"github.com/limistah/wallet-service/internal/utils"
+	"github.com/mattn/go-sqlite3"
+	"gorm.io/gorm"
+)
+
+// MySQL error numbers for transactions the server aborted and that succeed when run again
+const (
+	mysqlLockWaitTimeout = 1205
+	mysqlDeadlock        = 1213
+)
+
+// ErrNoDatabase is returned by RunInTransaction for repositories that are not backed by a database
+var ErrNoDatabase = errors.New("database transactions are not available without a database")
+
+// RetryPolicy bounds how transactions aborted by transient database errors are run again
+type RetryPolicy struct {
+	// MaxAttempts is how many times a transaction runs in total; 1 disables retries
+	MaxAttempts int
+	// InitialBackoff is the wait before the first retry; each further retry doubles it up to MaxBackoff
+	InitialBackoff time.Duration
+	MaxBackoff     time.Duration
+}
+
+// DefaultRetryPolicy runs a transaction up to 3 times, waiting 20ms then 40ms
+func DefaultRetryPolicy() RetryPolicy {
+	return RetryPolicy{
+		MaxAttempts:    3,
+		InitialBackoff: 20 * time.Millisecond,
+		MaxBackoff:     time.Second,
+	}
+}
+
+// Backoff returns the wait before the retry that follows the given attempt, starting at 1
+func (p RetryPolicy) Backoff(attempt int) time.Duration {
+	return utils.Backoff(p.InitialBackoff, p.MaxBackoff, attempt)
+}
+
+// IsTransient reports whether err is a deadlock, a lock wait timeout, or a connection failure the
+// driver guarantees happened before the statement reached the server: failures after which the
+// transaction certainly did not commit, so running it again cannot apply it twice. A connection lost
+// mid-statement or during COMMIT is ambiguous and is not retried. Logical failures such as version
+// mismatches, constraint violations or missing records are not transient either
+func IsTransient(err error) bool {
+	if err == nil {
+		return false
+	}
+
+	var mysqlErr *mysql.MySQLError
+	if errors.As(err, &mysqlErr) {
+		return mysqlErr.Number == mysqlDeadlock || mysqlErr.Number == mysqlLockWaitTimeout
+	}
+
+	var sqliteErr sqlite3.Error
+	if errors.As(err, &sqliteErr) {
+		return sqliteErr.Code == sqlite3.ErrBusy || sqliteErr.Code == sqlite3.ErrLocked
+	}
+
+	// database/sql drivers only return ErrBadConn when the server cannot have run the statement, and a
+	// refused connection never reached it
+	return errors.Is(err, driver.ErrBadConn) || errors.Is(err, syscall.ECONNREFUSED)
+}
+
+// retry runs fn until it succeeds, fails with an error that is not transient, or uses up the attempts
+// of the policy; waits are jittered so transactions that deadlocked together do not collide again
+func retry(policy RetryPolicy, fn func() error) error {
+	var err error
+	for attempt := 1; ; attempt++ {
+		err = fn()
+		if err == nil || !IsTransient(err) || attempt >= policy.MaxAttempts {
+			return err
+		}
+
+		backoff := policy.Backoff(attempt)
+		time.Sleep(backoff/2 + time.Duration(rand.Int63n(int64(backoff/2)+1)))
+	}
+}
+
+// RunInTransaction runs fn in a database transaction and runs it again from the start when the database
+// aborts it for a transient reason. fn must only change state through tx so a retry starts clean.
+// Repositories without a database, such as the in-memory ones, return ErrNoDatabase
+func (r *Repositories) RunInTransaction(ctx context.Context, fn func(tx *gorm.DB) error) error {
+	if r.DB == nil {
+		return ErrNoDatabase
+	}
+	return retry(r.TransactionRetry, func() error {
+		return withContext(r.DB, ctx).Transaction(fn)
+	})
+}
@@ -0,0 +1,76 @@
+package repositories
+
+import (
+	"database/sql/driver"
+	"errors"
+	"fmt"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/go-sql-driver/mysql"
+	"gorm.io/gorm"
+)
+
+func TestIsTransient(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want bool
+	}{
+		{"deadlock", &mysql.MySQLError{Number: 1213, Message: "Deadlock found when trying to get lock"}, true},
+		{"wrapped lock wait timeout", fmt.Errorf("failed to update wallet: %w", &mysql.MySQLError{Number: 1205}), true},
+		{"bad connection", driver.ErrBadConn, true},
+		{"connection refused", fmt.Errorf("dial tcp: %w", syscall.ECONNREFUSED), true},
+		{"connection lost mid-statement", mysql.ErrInvalidConn, false},
+		{"connection reset during commit", fmt.Errorf("commit: %w", syscall.ECONNRESET), false},
+		{"duplicate key", &mysql.MySQLError{Number: 1062, Message: "Duplicate entry"}, false},
+		{"version mismatch", errors.New("wallet version mismatch - concurrent modification detected"), false},
+		{"not found", gorm.ErrRecordNotFound, false},
+		{"nil", nil, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := IsTransient(tt.err); got != tt.want {
+				t.Errorf("IsTransient(%v) = %v, want %v", tt.err, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRetry(t *testing.T) {
+	policy := RetryPolicy{MaxAttempts: 3, InitialBackoff: time.Millisecond, MaxBackoff: 2 * time.Millisecond}
+	deadlock := &mysql.MySQLError{Number: 1213}
+
+	attempts := 0
+	err := retry(policy, func() error {
+		attempts++
+		if attempts < 3 {
+			return deadlock
+		}
+		return nil
+	})
+	if err != nil || attempts != 3 {
+		t.Errorf("Expected a deadlocked transaction to succeed on the third attempt, got %v after %d attempts", err, attempts)
+	}
+
+	attempts = 0
+	err = retry(policy, func() error {
+		attempts++
+		return deadlock
+	})
+	if err != deadlock || attempts != 3 {
+		t.Errorf("Expected retries to stop after 3 attempts, got %v after %d attempts", err, attempts)
+	}
+
+	attempts = 0
+	mismatch := errors.New("wallet version mismatch - concurrent modification detected")
+	err = retry(policy, func() error {
+		attempts++
+		return mismatch
+	})
+	if err != mismatch || attempts != 1 {
+		t.Errorf("Expected logical failures not to be retried, got %v after %d attempts", err, attempts)
+	}
+}
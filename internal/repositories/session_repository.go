@@ -0,0 +1,63 @@
+package repositories
+
+import (
+	"context"
+	"time"
+
+	"github.com/limistah/wallet-service/internal/models"
+	"gorm.io/gorm"
+)
+
+type sessionRepository struct {
+	db *gorm.DB
+}
+
+// NewSessionRepository creates a new session repository
+func NewSessionRepository(db *gorm.DB) SessionRepository {
+	return &sessionRepository{db: db}
+}
+
+func (r *sessionRepository) Create(ctx context.Context, session *models.Session) error {
+	return withContext(r.db, ctx).Create(session).Error
+}
+
+func (r *sessionRepository) GetByID(ctx context.Context, id uint) (*models.Session, error) {
+	var session models.Session
+	if err := withContext(r.db, ctx).First(&session, id).Error; err != nil {
+		return nil, err
+	}
+	return &session, nil
+}
+
+func (r *sessionRepository) GetByTokenID(ctx context.Context, tokenID string) (*models.Session, error) {
+	var session models.Session
+	if err := withContext(r.db, ctx).Where("token_id = ?", tokenID).First(&session).Error; err != nil {
+		return nil, err
+	}
+	return &session, nil
+}
+
+func (r *sessionRepository) Update(ctx context.Context, session *models.Session) error {
+	return withContext(r.db, ctx).Save(session).Error
+}
+
+// ListActive returns the sessions of a user that are neither revoked nor expired, latest first
+func (r *sessionRepository) ListActive(ctx context.Context, userID uint, now time.Time) ([]models.Session, error) {
+	var sessions []models.Session
+	err := withContext(r.db, ctx).Where("user_id = ? AND revoked_at IS NULL AND expires_at > ?", userID, now).
+		Order("issued_at DESC").Find(&sessions).Error
+	return sessions, err
+}
+
+// RevokeByTokenID marks the session holding the token as revoked, if there is one
+func (r *sessionRepository) RevokeByTokenID(ctx context.Context, tokenID string, at time.Time) error {
+	return withContext(r.db, ctx).Model(&models.Session{}).
+		Where("token_id = ? AND revoked_at IS NULL", tokenID).
+		Update("revoked_at", at).Error
+}
+
+// DeleteExpired removes the sessions whose token expired before the given time
+func (r *sessionRepository) DeleteExpired(ctx context.Context, before time.Time) (int64, error) {
+	result := withContext(r.db, ctx).Where("expires_at < ?", before).Delete(&models.Session{})
+	return result.RowsAffected, result.Error
+}
@@ -0,0 +1,65 @@
+package repositories
+
+import (
+	"context"
+	"time"
+
+	"github.com/limistah/wallet-service/internal/models"
+	"gorm.io/gorm"
+)
+
+type settlementBatchRepository struct {
+	db *gorm.DB
+}
+
+// NewSettlementBatchRepository creates a new settlement batch repository
+func NewSettlementBatchRepository(db *gorm.DB) SettlementBatchRepository {
+	return &settlementBatchRepository{db: db}
+}
+
+// GetByID loads a batch with the deposits and payouts it holds
+func (r *settlementBatchRepository) GetByID(ctx context.Context, id uint) (*models.SettlementBatch, error) {
+	var batch models.SettlementBatch
+	err := withContext(r.db, ctx).
+		Preload("Deposits", func(db *gorm.DB) *gorm.DB { return db.Order("id ASC") }).
+		Preload("Payouts", func(db *gorm.DB) *gorm.DB { return db.Order("id ASC") }).
+		First(&batch, id).Error
+	if err != nil {
+		return nil, err
+	}
+	return &batch, nil
+}
+
+func (r *settlementBatchRepository) GetByKey(ctx context.Context, provider, currency string, date time.Time) (*models.SettlementBatch, error) {
+	var batch models.SettlementBatch
+	err := withContext(r.db, ctx).Where("provider = ? AND currency = ? AND date = ?", provider, currency, date.Format("2006-01-02")).
+		First(&batch).Error
+	if err != nil {
+		return nil, err
+	}
+	return &batch, nil
+}
+
+// List returns batches newest day first
+func (r *settlementBatchRepository) List(ctx context.Context, filter SettlementBatchFilter, offset, limit int) ([]models.SettlementBatch, error) {
+	var batches []models.SettlementBatch
+	query := withContext(r.db, ctx).Model(&models.SettlementBatch{})
+	if filter.Provider != "" {
+		query = query.Where("provider = ?", filter.Provider)
+	}
+	if filter.Status != "" {
+		query = query.Where("status = ?", filter.Status)
+	}
+	if filter.From != nil {
+		query = query.Where("date >= ?", filter.From.Format("2006-01-02"))
+	}
+	if filter.To != nil {
+		query = query.Where("date < ?", filter.To.Format("2006-01-02"))
+	}
+	err := query.Order("date DESC, provider ASC, currency ASC").Offset(offset).Limit(limit).Find(&batches).Error
+	return batches, err
+}
+
+func (r *settlementBatchRepository) Update(ctx context.Context, batch *models.SettlementBatch) error {
+	return withContext(r.db, ctx).Omit("Deposits", "Payouts").Save(batch).Error
+}
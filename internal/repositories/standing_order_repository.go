@@ -0,0 +1,88 @@
+package repositories
+
+import (
+	"context"
+	"time"
+
+	"github.com/limistah/wallet-service/internal/models"
+	"gorm.io/gorm"
+)
+
+type standingOrderRepository struct {
+	db *gorm.DB
+}
+
+// NewStandingOrderRepository creates a new standing order repository
+func NewStandingOrderRepository(db *gorm.DB) StandingOrderRepository {
+	return &standingOrderRepository{db: db}
+}
+
+func (r *standingOrderRepository) Create(ctx context.Context, order *models.StandingOrder) error {
+	return withContext(r.db, ctx).Create(order).Error
+}
+
+func (r *standingOrderRepository) GetByID(ctx context.Context, id uint) (*models.StandingOrder, error) {
+	var order models.StandingOrder
+	err := withContext(r.db, ctx).First(&order, id).Error
+	if err != nil {
+		return nil, err
+	}
+	return &order, nil
+}
+
+func (r *standingOrderRepository) ListByUserID(ctx context.Context, userID uint) ([]models.StandingOrder, error) {
+	var orders []models.StandingOrder
+	err := withContext(r.db, ctx).Where("user_id = ?", userID).Order("created_at DESC, id DESC").Find(&orders).Error
+	return orders, err
+}
+
+func (r *standingOrderRepository) GetDue(ctx context.Context, now time.Time, limit int) ([]models.StandingOrder, error) {
+	var orders []models.StandingOrder
+	err := withContext(r.db, ctx).Where("status = ? AND next_run_at <= ?", models.StandingOrderStatusActive, now).
+		Order("next_run_at ASC").Limit(limit).
+		Find(&orders).Error
+	return orders, err
+}
+
+// Advance moves an order to its next occurrence; it reports false when another scheduler
+// instance already advanced it
+func (r *standingOrderRepository) Advance(ctx context.Context, id uint, from, to time.Time, status models.StandingOrderStatus) (bool, error) {
+	result := withContext(r.db, ctx).Model(&models.StandingOrder{}).
+		Where("id = ? AND next_run_at = ? AND status = ?", id, from, models.StandingOrderStatusActive).
+		Updates(map[string]interface{}{"next_run_at": to, "status": status})
+	if result.Error != nil {
+		return false, result.Error
+	}
+	return result.RowsAffected > 0, nil
+}
+
+func (r *standingOrderRepository) UpdateStatus(ctx context.Context, id uint, status models.StandingOrderStatus) error {
+	return withContext(r.db, ctx).Model(&models.StandingOrder{}).Where("id = ?", id).Update("status", status).Error
+}
+
+func (r *standingOrderRepository) CreateRun(ctx context.Context, run *models.StandingOrderRun) error {
+	return withContext(r.db, ctx).Create(run).Error
+}
+
+func (r *standingOrderRepository) GetDueRuns(ctx context.Context, now time.Time, limit int) ([]models.StandingOrderRun, error) {
+	var runs []models.StandingOrderRun
+	err := withContext(r.db, ctx).Preload("StandingOrder").
+		Where("status IN ? AND next_attempt_at <= ?",
+			[]models.StandingOrderRunStatus{models.StandingOrderRunPending, models.StandingOrderRunRetrying}, now).
+		Order("next_attempt_at ASC").Limit(limit).
+		Find(&runs).Error
+	return runs, err
+}
+
+func (r *standingOrderRepository) UpdateRun(ctx context.Context, run *models.StandingOrderRun) error {
+	return withContext(r.db, ctx).Model(run).Select("status", "attempts", "next_attempt_at", "last_error", "transaction_id", "completed_at").
+		Updates(run).Error
+}
+
+func (r *standingOrderRepository) ListRuns(ctx context.Context, orderID uint, offset, limit int) ([]models.StandingOrderRun, error) {
+	var runs []models.StandingOrderRun
+	err := withContext(r.db, ctx).Where("standing_order_id = ?", orderID).
+		Order("scheduled_for DESC").Offset(offset).Limit(limit).
+		Find(&runs).Error
+	return runs, err
+}
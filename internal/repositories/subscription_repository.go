@@ -0,0 +1,120 @@
+package repositories
+
+import (
+	"context"
+	"time"
+
+	"github.com/limistah/wallet-service/internal/models"
+	"gorm.io/gorm"
+)
+
+type subscriptionRepository struct {
+	db *gorm.DB
+}
+
+// NewSubscriptionRepository creates a new subscription repository
+func NewSubscriptionRepository(db *gorm.DB) SubscriptionRepository {
+	return &subscriptionRepository{db: db}
+}
+
+func (r *subscriptionRepository) CreatePlan(ctx context.Context, plan *models.SubscriptionPlan) error {
+	return withContext(r.db, ctx).Create(plan).Error
+}
+
+func (r *subscriptionRepository) GetPlan(ctx context.Context, id uint) (*models.SubscriptionPlan, error) {
+	var plan models.SubscriptionPlan
+	err := withContext(r.db, ctx).Preload("Merchant").First(&plan, id).Error
+	if err != nil {
+		return nil, err
+	}
+	return &plan, nil
+}
+
+func (r *subscriptionRepository) ListActivePlans(ctx context.Context, offset, limit int) ([]models.SubscriptionPlan, error) {
+	var plans []models.SubscriptionPlan
+	err := withContext(r.db, ctx).Preload("Merchant").Where("active = ?", true).
+		Order("created_at DESC, id DESC").Offset(offset).Limit(limit).
+		Find(&plans).Error
+	return plans, err
+}
+
+func (r *subscriptionRepository) ListPlansByMerchant(ctx context.Context, merchantID uint) ([]models.SubscriptionPlan, error) {
+	var plans []models.SubscriptionPlan
+	err := withContext(r.db, ctx).Where("merchant_id = ?", merchantID).Order("created_at DESC, id DESC").Find(&plans).Error
+	return plans, err
+}
+
+func (r *subscriptionRepository) ArchivePlan(ctx context.Context, id uint) error {
+	return withContext(r.db, ctx).Model(&models.SubscriptionPlan{}).Where("id = ?", id).Update("active", false).Error
+}
+
+func (r *subscriptionRepository) Create(ctx context.Context, subscription *models.Subscription) error {
+	return withContext(r.db, ctx).Omit("Plan").Create(subscription).Error
+}
+
+func (r *subscriptionRepository) GetByID(ctx context.Context, id uint) (*models.Subscription, error) {
+	var subscription models.Subscription
+	err := withContext(r.db, ctx).Preload("Plan").First(&subscription, id).Error
+	if err != nil {
+		return nil, err
+	}
+	return &subscription, nil
+}
+
+func (r *subscriptionRepository) GetOpenByUserAndPlan(ctx context.Context, userID, planID uint) (*models.Subscription, error) {
+	var subscription models.Subscription
+	err := withContext(r.db, ctx).Where("user_id = ? AND plan_id = ? AND status <> ?", userID, planID, models.SubscriptionStatusCancelled).
+		First(&subscription).Error
+	if err != nil {
+		return nil, err
+	}
+	return &subscription, nil
+}
+
+func (r *subscriptionRepository) ListByUser(ctx context.Context, userID uint) ([]models.Subscription, error) {
+	var subscriptions []models.Subscription
+	err := withContext(r.db, ctx).Preload("Plan").Where("user_id = ?", userID).Order("created_at DESC, id DESC").Find(&subscriptions).Error
+	return subscriptions, err
+}
+
+func (r *subscriptionRepository) ListByPlan(ctx context.Context, planID uint, offset, limit int) ([]models.Subscription, error) {
+	var subscriptions []models.Subscription
+	err := withContext(r.db, ctx).Preload("Plan").Where("plan_id = ?", planID).
+		Order("created_at DESC, id DESC").Offset(offset).Limit(limit).
+		Find(&subscriptions).Error
+	return subscriptions, err
+}
+
+// ListDue returns active and past due subscriptions whose next charge is due, oldest first
+func (r *subscriptionRepository) ListDue(ctx context.Context, now time.Time, limit int) ([]models.Subscription, error) {
+	var subscriptions []models.Subscription
+	err := withContext(r.db, ctx).Preload("Plan").
+		Where("status IN ? AND next_billing_at <= ?",
+			[]models.SubscriptionStatus{models.SubscriptionStatusActive, models.SubscriptionStatusPastDue}, now).
+		Order("next_billing_at ASC").Limit(limit).
+		Find(&subscriptions).Error
+	return subscriptions, err
+}
+
+func (r *subscriptionRepository) Update(ctx context.Context, subscription *models.Subscription, from models.SubscriptionStatus) (bool, error) {
+	result := withContext(r.db, ctx).Model(&models.Subscription{}).
+		Where("id = ? AND status = ?", subscription.ID, from).
+		Select("status", "paid_periods", "paid_until", "next_billing_at", "failed_attempts", "last_error", "suspended_at", "cancelled_at").
+		Updates(subscription)
+	if result.Error != nil {
+		return false, result.Error
+	}
+	return result.RowsAffected > 0, nil
+}
+
+func (r *subscriptionRepository) CreateCharge(ctx context.Context, charge *models.SubscriptionCharge) error {
+	return withContext(r.db, ctx).Create(charge).Error
+}
+
+func (r *subscriptionRepository) ListCharges(ctx context.Context, subscriptionID uint, offset, limit int) ([]models.SubscriptionCharge, error) {
+	var charges []models.SubscriptionCharge
+	err := withContext(r.db, ctx).Where("subscription_id = ?", subscriptionID).
+		Order("created_at DESC, id DESC").Offset(offset).Limit(limit).
+		Find(&charges).Error
+	return charges, err
+}
@@ -0,0 +1,68 @@
+package repositories
+
+import (
+	"context"
+
+	"github.com/limistah/wallet-service/internal/models"
+	"gorm.io/gorm"
+)
+
+type suspenseItemRepository struct {
+	db *gorm.DB
+}
+
+// NewSuspenseItemRepository creates a new suspense item repository
+func NewSuspenseItemRepository(db *gorm.DB) SuspenseItemRepository {
+	return &suspenseItemRepository{db: db}
+}
+
+func (r *suspenseItemRepository) GetByID(ctx context.Context, id uint) (*models.SuspenseItem, error) {
+	var item models.SuspenseItem
+	err := withContext(r.db, ctx).First(&item, id).Error
+	if err != nil {
+		return nil, err
+	}
+	return &item, nil
+}
+
+func (r *suspenseItemRepository) GetByExternalReference(ctx context.Context, provider, externalReference string) (*models.SuspenseItem, error) {
+	var item models.SuspenseItem
+	err := withContext(r.db, ctx).Where("provider = ? AND external_reference = ?", provider, externalReference).First(&item).Error
+	if err != nil {
+		return nil, err
+	}
+	return &item, nil
+}
+
+// List returns items oldest first so the queue is worked in order; an empty status lists every item
+func (r *suspenseItemRepository) List(ctx context.Context, status models.SuspenseItemStatus, offset, limit int) ([]models.SuspenseItem, error) {
+	var items []models.SuspenseItem
+	query := withContext(r.db, ctx).Model(&models.SuspenseItem{})
+	if status != "" {
+		query = query.Where("status = ?", status)
+	}
+	err := query.Order("created_at ASC, id ASC").Offset(offset).Limit(limit).Find(&items).Error
+	return items, err
+}
+
+func (r *suspenseItemRepository) Update(ctx context.Context, item *models.SuspenseItem) error {
+	return withContext(r.db, ctx).Save(item).Error
+}
+
+func (r *suspenseItemRepository) Create(ctx context.Context, item *models.SuspenseItem) error {
+	return withContext(r.db, ctx).Create(item).Error
+}
+
+func (r *suspenseItemRepository) Resolve(ctx context.Context, item *models.SuspenseItem) (bool, error) {
+	result := withContext(r.db, ctx).Model(&models.SuspenseItem{}).
+		Where("id = ? AND status IN ?", item.ID, []models.SuspenseItemStatus{models.SuspenseItemStatusOpen, models.SuspenseItemStatusInvestigating}).
+		Updates(map[string]interface{}{
+			"status":                      item.Status,
+			"reallocated_wallet_id":       item.ReallocatedWalletID,
+			"resolution_journal_entry_id": item.ResolutionJournalEntryID,
+			"resolved_by":                 item.ResolvedBy,
+			"resolved_at":                 item.ResolvedAt,
+			"notes":                       item.Notes,
+		})
+	return result.RowsAffected > 0, result.Error
+}
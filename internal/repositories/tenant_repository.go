@@ -0,0 +1,45 @@
+package repositories
+
+import (
+	"context"
+
+	"github.com/limistah/wallet-service/internal/models"
+	"gorm.io/gorm"
+)
+
+type tenantRepository struct {
+	db *gorm.DB
+}
+
+// NewTenantRepository creates a new tenant repository
+func NewTenantRepository(db *gorm.DB) TenantRepository {
+	return &tenantRepository{db: db}
+}
+
+func (r *tenantRepository) Create(ctx context.Context, tenant *models.Tenant) error {
+	return withContext(r.db, ctx).Create(tenant).Error
+}
+
+func (r *tenantRepository) GetByID(ctx context.Context, id uint) (*models.Tenant, error) {
+	var tenant models.Tenant
+	err := withContext(r.db, ctx).First(&tenant, id).Error
+	if err != nil {
+		return nil, err
+	}
+	return &tenant, nil
+}
+
+func (r *tenantRepository) GetBySlug(ctx context.Context, slug string) (*models.Tenant, error) {
+	var tenant models.Tenant
+	err := withContext(r.db, ctx).Where("slug = ?", slug).First(&tenant).Error
+	if err != nil {
+		return nil, err
+	}
+	return &tenant, nil
+}
+
+func (r *tenantRepository) List(ctx context.Context) ([]models.Tenant, error) {
+	var tenants []models.Tenant
+	err := withContext(r.db, ctx).Order("id ASC").Find(&tenants).Error
+	return tenants, err
+}
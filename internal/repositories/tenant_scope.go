@@ -0,0 +1,117 @@
+package repositories
+
+import (
+	"context"
+	"reflect"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// tenantField is the field of models owned by a tenant
+const tenantField = "TenantID"
+
+type tenantContextKey struct{}
+
+// WithTenant returns a context whose database operations are scoped to the tenant
+func WithTenant(ctx context.Context, tenantID uint) context.Context {
+	return context.WithValue(ctx, tenantContextKey{}, tenantID)
+}
+
+// TenantFromContext returns the tenant database operations with the context are scoped to
+func TenantFromContext(ctx context.Context) (uint, bool) {
+	if ctx == nil {
+		return 0, false
+	}
+	tenantID, ok := ctx.Value(tenantContextKey{}).(uint)
+	return tenantID, ok
+}
+
+// withContext binds db to the context of a call so its queries are cancelled with it. Repositories
+// scoped with ForTenant stay scoped to their tenant whatever the context carries
+func withContext(db *gorm.DB, ctx context.Context) *gorm.DB {
+	if tenantID, ok := TenantFromContext(db.Statement.Context); ok {
+		ctx = WithTenant(ctx, tenantID)
+	}
+	return db.WithContext(ctx)
+}
+
+// TenantScope limits a query to the rows of a tenant
+func TenantScope(tenantID uint) func(*gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		return db.Where(tenantCondition(tenantID))
+	}
+}
+
+func tenantCondition(tenantID uint) clause.Expression {
+	return clause.Eq{Column: clause.Column{Table: clause.CurrentTable, Name: "tenant_id"}, Value: tenantID}
+}
+
+// RegisterTenantScoping makes every statement run with a tenant context (see WithTenant) apply
+// TenantScope to models with a TenantID, and fills the TenantID of the models it creates, so
+// repositories cannot read or change rows of another tenant however their queries are written.
+// Statements without a tenant context, such as those of background jobs, see every tenant
+func RegisterTenantScoping(db *gorm.DB) error {
+	callbacks := db.Callback()
+	if err := callbacks.Create().Before("gorm:create").Register("tenant:create", assignTenant); err != nil {
+		return err
+	}
+	if err := callbacks.Query().Before("gorm:query").Register("tenant:query", scopeTenant); err != nil {
+		return err
+	}
+	if err := callbacks.Update().Before("gorm:update").Register("tenant:update", scopeTenant); err != nil {
+		return err
+	}
+	if err := callbacks.Delete().Before("gorm:delete").Register("tenant:delete", scopeTenant); err != nil {
+		return err
+	}
+	return callbacks.Row().Before("gorm:row").Register("tenant:row", scopeTenant)
+}
+
+// ForTenant returns repositories whose every query is scoped to the tenant. Repositories without a
+// database are returned as they are
+func (r *Repositories) ForTenant(tenantID uint) *Repositories {
+	if r.DB == nil {
+		return r
+	}
+	scoped := NewRepositories(r.DB.WithContext(WithTenant(context.Background(), tenantID)))
+	scoped.TransactionRetry = r.TransactionRetry
+	return scoped
+}
+
+func scopeTenant(db *gorm.DB) {
+	tenantID, ok := TenantFromContext(db.Statement.Context)
+	if !ok || db.Statement.Schema == nil || db.Statement.Schema.LookUpField(tenantField) == nil {
+		return
+	}
+	db.Statement.AddClause(clause.Where{Exprs: []clause.Expression{tenantCondition(tenantID)}})
+}
+
+// assignTenant sets the TenantID of created models that have none
+func assignTenant(db *gorm.DB) {
+	tenantID, ok := TenantFromContext(db.Statement.Context)
+	if !ok || db.Statement.Schema == nil {
+		return
+	}
+	field := db.Statement.Schema.LookUpField(tenantField)
+	if field == nil {
+		return
+	}
+
+	ctx := db.Statement.Context
+	assign := func(value reflect.Value) {
+		if _, zero := field.ValueOf(ctx, value); zero {
+			if err := field.Set(ctx, value, tenantID); err != nil {
+				db.AddError(err)
+			}
+		}
+	}
+	switch value := db.Statement.ReflectValue; value.Kind() {
+	case reflect.Slice, reflect.Array:
+		for i := 0; i < value.Len(); i++ {
+			assign(reflect.Indirect(value.Index(i)))
+		}
+	case reflect.Struct:
+		assign(value)
+	}
+}
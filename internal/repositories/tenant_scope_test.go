@@ -0,0 +1,73 @@
+package repositories
+
+import (
+	"context"
+	"testing"
+
+	"github.com/limistah/wallet-service/internal/models"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+func newTenantTestDB(t *testing.T) *Repositories {
+	t.Helper()
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{Logger: logger.Default.LogMode(logger.Silent)})
+	if err != nil {
+		t.Fatalf("Failed to open database: %v", err)
+	}
+	if err := RegisterTenantScoping(db); err != nil {
+		t.Fatalf("Failed to register tenant scoping: %v", err)
+	}
+	if err := db.AutoMigrate(&models.User{}, &models.Wallet{}); err != nil {
+		t.Fatalf("Failed to create tables: %v", err)
+	}
+	return NewRepositories(db)
+}
+
+func TestRepositories_ForTenant(t *testing.T) {
+	repos := newTenantTestDB(t)
+	acme, globex := repos.ForTenant(2), repos.ForTenant(3)
+
+	// The same email may sign up with two tenants
+	acmeUser := &models.User{Name: "Ada", Email: "ada@example.com", Password: "secret"}
+	globexUser := &models.User{Name: "Ada", Email: "ada@example.com", Password: "secret"}
+	if err := acme.User.Create(context.Background(), acmeUser); err != nil {
+		t.Fatalf("Create() error = %v", err)
+	}
+	if err := globex.User.Create(context.Background(), globexUser); err != nil {
+		t.Fatalf("Create() of the same email in another tenant error = %v", err)
+	}
+	if acmeUser.TenantID != 2 || globexUser.TenantID != 3 {
+		t.Fatalf("Create() tenants = %d, %d, want 2, 3", acmeUser.TenantID, globexUser.TenantID)
+	}
+	if err := acme.Wallet.Create(context.Background(), &models.Wallet{UserID: acmeUser.ID, Currency: "USD", Status: models.WalletStatusActive}); err != nil {
+		t.Fatalf("Create() wallet error = %v", err)
+	}
+
+	user, err := acme.User.GetByEmail(context.Background(), "ada@example.com")
+	if err != nil || user.ID != acmeUser.ID {
+		t.Errorf("GetByEmail() in tenant 2 = %+v, %v, want user %d", user, err, acmeUser.ID)
+	}
+	if _, err := acme.User.GetByID(context.Background(), globexUser.ID); err == nil {
+		t.Error("GetByID() found a user of another tenant")
+	}
+	if _, err := globex.Wallet.GetByUserID(context.Background(), acmeUser.ID); err == nil {
+		t.Error("GetByUserID() found a wallet of another tenant")
+	}
+	if err := globex.User.Delete(context.Background(), acmeUser.ID); err != nil {
+		t.Fatalf("Delete() error = %v", err)
+	}
+	if _, err := acme.User.GetByID(context.Background(), acmeUser.ID); err != nil {
+		t.Errorf("Delete() from another tenant removed the user: %v", err)
+	}
+
+	// Repositories without a tenant see every tenant
+	users, err := repos.User.List(context.Background(), 0, 10)
+	if err != nil || len(users) != 2 {
+		t.Errorf("List() without a tenant = %d users, %v, want 2", len(users), err)
+	}
+	if user, err := repos.User.GetByEmailInTenant(context.Background(), 3, "ada@example.com"); err != nil || user.ID != globexUser.ID {
+		t.Errorf("GetByEmailInTenant() = %+v, %v, want user %d", user, err, globexUser.ID)
+	}
+}
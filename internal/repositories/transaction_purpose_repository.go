@@ -0,0 +1,54 @@
+package repositories
+
+import (
+	"context"
+
+	"github.com/limistah/wallet-service/internal/models"
+	"gorm.io/gorm"
+)
+
+type transactionPurposeRepository struct {
+	db *gorm.DB
+}
+
+// NewTransactionPurposeRepository creates a new transaction purpose repository
+func NewTransactionPurposeRepository(db *gorm.DB) TransactionPurposeRepository {
+	return &transactionPurposeRepository{db: db}
+}
+
+func (r *transactionPurposeRepository) GetByName(ctx context.Context, name models.TransactionPurpose) (*models.TransactionPurposeDefinition, error) {
+	var definition models.TransactionPurposeDefinition
+	if err := withContext(r.db, ctx).Where("name = ?", name).First(&definition).Error; err != nil {
+		return nil, err
+	}
+	return &definition, nil
+}
+
+func (r *transactionPurposeRepository) List(ctx context.Context) ([]models.TransactionPurposeDefinition, error) {
+	var definitions []models.TransactionPurposeDefinition
+	err := withContext(r.db, ctx).Order("name ASC").Find(&definitions).Error
+	return definitions, err
+}
+
+func (r *transactionPurposeRepository) Create(ctx context.Context, definition *models.TransactionPurposeDefinition) error {
+	return withContext(r.db, ctx).Create(definition).Error
+}
+
+func (r *transactionPurposeRepository) Update(ctx context.Context, definition *models.TransactionPurposeDefinition) error {
+	return withContext(r.db, ctx).Save(definition).Error
+}
+
+func (r *transactionPurposeRepository) Delete(ctx context.Context, name models.TransactionPurpose) error {
+	return withContext(r.db, ctx).Where("name = ?", name).Delete(&models.TransactionPurposeDefinition{}).Error
+}
+
+func (r *transactionPurposeRepository) InUse(ctx context.Context, name models.TransactionPurpose) (bool, error) {
+	var count int64
+	if err := withContext(r.db, ctx).Unscoped().Model(&models.Transaction{}).
+		Where("transaction_purpose = ?", name).Count(&count).Error; err != nil || count > 0 {
+		return count > 0, err
+	}
+	err := withContext(r.db, ctx).Unscoped().Model(&models.ArchivedTransaction{}).
+		Where("transaction_purpose = ?", name).Count(&count).Error
+	return count > 0, err
+}
@@ -1,6 +1,7 @@
 package repositories
 
 import (
+	"context"
 	"time"
 
 	"github.com/limistah/wallet-service/internal/models"
@@ -8,6 +9,20 @@ import (
 	"gorm.io/gorm"
 )
 
+// heldDebitStatuses are the debit statuses whose amount has left the wallet balance
+var heldDebitStatuses = []models.TransactionStatus{
+	models.TransactionStatusCompleted,
+	models.TransactionStatusPending,
+	models.TransactionStatusPendingReview,
+}
+
+// finalStatuses are the statuses a transaction no longer moves out of
+var finalStatuses = []models.TransactionStatus{
+	models.TransactionStatusCompleted,
+	models.TransactionStatusFailed,
+	models.TransactionStatusCancelled,
+}
+
 type transactionRepository struct {
 	db *gorm.DB
 }
@@ -17,22 +32,22 @@ func NewTransactionRepository(db *gorm.DB) TransactionRepository {
 	return &transactionRepository{db: db}
 }
 
-func (r *transactionRepository) Create(transaction *models.Transaction) error {
-	return r.db.Create(transaction).Error
+func (r *transactionRepository) Create(ctx context.Context, transaction *models.Transaction) error {
+	return withContext(r.db, ctx).Create(transaction).Error
 }
 
-func (r *transactionRepository) GetByID(id uint) (*models.Transaction, error) {
+func (r *transactionRepository) GetByID(ctx context.Context, id uint) (*models.Transaction, error) {
 	var transaction models.Transaction
-	err := r.db.Preload("Wallet").First(&transaction, id).Error
+	err := withContext(r.db, ctx).Preload("Wallet").Preload("RelatedTransaction").First(&transaction, id).Error
 	if err != nil {
 		return nil, err
 	}
 	return &transaction, nil
 }
 
-func (r *transactionRepository) GetByReference(reference string) (*models.Transaction, error) {
+func (r *transactionRepository) GetByReference(ctx context.Context, reference string) (*models.Transaction, error) {
 	var transaction models.Transaction
-	err := r.db.Preload("Wallet").
+	err := withContext(r.db, ctx).Preload("Wallet").
 		Where("reference = ?", reference).First(&transaction).Error
 	if err != nil {
 		return nil, err
@@ -40,19 +55,18 @@ func (r *transactionRepository) GetByReference(reference string) (*models.Transa
 	return &transaction, nil
 }
 
-func (r *transactionRepository) GetByWalletID(walletID uint, offset, limit int) ([]models.Transaction, error) {
+func (r *transactionRepository) GetByWalletID(ctx context.Context, walletID uint, offset, limit int) ([]models.Transaction, error) {
 	var transactions []models.Transaction
-	err := r.db.Preload("TransactionType").
-		Where("wallet_id = ?", walletID).
+	err := withContext(r.db, ctx).Where("wallet_id = ?", walletID).
 		Order("created_at DESC").
 		Offset(offset).Limit(limit).
 		Find(&transactions).Error
 	return transactions, err
 }
 
-func (r *transactionRepository) GetByWalletIDWithCursor(walletID uint, cursor *time.Time, cursorID *uint, limit int) ([]models.Transaction, error) {
+func (r *transactionRepository) GetByWalletIDWithCursor(ctx context.Context, walletID uint, cursor *time.Time, cursorID *uint, limit int) ([]models.Transaction, error) {
 	var transactions []models.Transaction
-	query := r.db.Where("wallet_id = ?", walletID)
+	query := withContext(r.db, ctx).Preload("RelatedTransaction").Where("wallet_id = ?", walletID)
 
 	// Only add cursor conditions if cursor is provided
 	if cursor != nil && cursorID != nil {
@@ -64,43 +78,280 @@ func (r *transactionRepository) GetByWalletIDWithCursor(walletID uint, cursor *t
 	return transactions, err
 }
 
-func (r *transactionRepository) Update(transaction *models.Transaction) error {
-	return r.db.Save(transaction).Error
+func (r *transactionRepository) Update(ctx context.Context, transaction *models.Transaction) error {
+	return withContext(r.db, ctx).Save(transaction).Error
+}
+
+func (r *transactionRepository) LinkRelated(ctx context.Context, id, relatedID uint) error {
+	return withContext(r.db, ctx).Model(&models.Transaction{}).Where("id = ?", id).Update("related_transaction_id", relatedID).Error
 }
 
-func (r *transactionRepository) CalculateBalance(walletID uint) (decimal.Decimal, error) {
-	var creditSum decimal.Decimal
-	var debitSum decimal.Decimal
+func (r *transactionRepository) CalculateBalance(ctx context.Context, walletID uint) (decimal.Decimal, error) {
+	return r.sumBalance(ctx, walletID, 0, 0)
+}
 
-	// Calculate sum of credits (CREDIT transactions)
-	err := r.db.Table("transactions t").
-		Where("t.wallet_id = ? AND t.status = ? AND t.transaction_type = ?",
-			walletID, models.TransactionStatusCompleted, models.TransactionTypeCredit).
-		Select("COALESCE(SUM(t.amount), 0)").
-		Scan(&creditSum).Error
+// CalculateBalanceAfter calculates what the transactions of a wallet after the given ID add to its
+// balance, for adding to a balance checkpoint
+func (r *transactionRepository) CalculateBalanceAfter(ctx context.Context, walletID, afterID uint) (decimal.Decimal, error) {
+	return r.sumBalance(ctx, walletID, afterID, 0)
+}
+
+// CalculateBalanceBetween calculates what the transactions of a wallet after afterID up to and
+// including throughID add to its balance
+func (r *transactionRepository) CalculateBalanceBetween(ctx context.Context, walletID, afterID, throughID uint) (decimal.Decimal, error) {
+	return r.sumBalance(ctx, walletID, afterID, throughID)
+}
 
+// sumBalance totals the wallet's transactions with an ID above afterID and, when throughID is set, no
+// higher than throughID, in one pass: completed credits add to the balance, and completed, pending and
+// held debits take from it
+func (r *transactionRepository) sumBalance(ctx context.Context, walletID, afterID, throughID uint) (decimal.Decimal, error) {
+	var balance decimal.Decimal
+	query := withContext(r.db, withQueryClass(ctx, balanceQuery)).Table("transactions t").Where("t.wallet_id = ? AND t.id > ?", walletID, afterID)
+	if throughID > 0 {
+		query = query.Where("t.id <= ?", throughID)
+	}
+	err := query.
+		Select(`COALESCE(SUM(CASE
+			WHEN t.transaction_type = ? AND t.status = ? THEN t.amount
+			WHEN t.transaction_type = ? AND t.status IN ? THEN -t.amount
+			ELSE 0 END), 0)`,
+			models.TransactionTypeCredit, models.TransactionStatusCompleted,
+			models.TransactionTypeDebit, heldDebitStatuses).
+		Scan(&balance).Error
 	if err != nil {
 		return decimal.Zero, err
 	}
+	return balance, nil
+}
 
-	// Calculate sum of debits (DEBIT transactions)
-	err = r.db.Table("transactions t").
-		Where("t.wallet_id = ? AND t.status = ? AND t.transaction_type = ?",
-			walletID, models.TransactionStatusCompleted, models.TransactionTypeDebit).
-		Select("COALESCE(SUM(t.amount), 0)").
-		Scan(&debitSum).Error
+// GetSettledThrough returns the highest transaction ID of a wallet such that every transaction of the
+// wallet up to it is in a final status and was created before the given time, or zero when there is none
+func (r *transactionRepository) GetSettledThrough(ctx context.Context, walletID uint, before time.Time) (uint, error) {
+	var unsettled *uint
+	err := withContext(r.db, ctx).Table("transactions t").
+		Where("t.wallet_id = ? AND (t.status NOT IN ? OR t.created_at >= ?)", walletID, finalStatuses, before).
+		Select("MIN(t.id)").
+		Scan(&unsettled).Error
 	if err != nil {
-		return decimal.Zero, err
+		return 0, err
+	}
+
+	var through *uint
+	query := withContext(r.db, ctx).Table("transactions t").Where("t.wallet_id = ?", walletID)
+	if unsettled != nil {
+		query = query.Where("t.id < ?", *unsettled)
+	}
+	if err := query.Select("MAX(t.id)").Scan(&through).Error; err != nil {
+		return 0, err
+	}
+	if through == nil {
+		return 0, nil
+	}
+	return *through, nil
+}
+
+// FindArchivable returns transactions created before the given time that are covered by their
+// wallet's balance checkpoint, in ID order after afterID
+func (r *transactionRepository) FindArchivable(ctx context.Context, before time.Time, afterID uint, limit int) ([]models.Transaction, error) {
+	var transactions []models.Transaction
+	err := withContext(r.db, ctx).Unscoped().
+		Joins("JOIN balance_checkpoints bc ON bc.wallet_id = transactions.wallet_id").
+		Where("transactions.id > ? AND transactions.id <= bc.through_transaction_id AND transactions.created_at < ?", afterID, before).
+		Order("transactions.id ASC").
+		Limit(limit).
+		Find(&transactions).Error
+	return transactions, err
+}
+
+// FindLinked returns the transactions outside the given set that are tied to it: the transactions
+// they relate to, the ones relating to them or to the same transaction, and the other legs of their
+// journal entries
+func (r *transactionRepository) FindLinked(ctx context.Context, transactions []models.Transaction) ([]models.Transaction, error) {
+	if len(transactions) == 0 {
+		return nil, nil
+	}
+	ids := make([]uint, 0, len(transactions))
+	var relatedIDs, journalIDs []uint
+	for _, t := range transactions {
+		ids = append(ids, t.ID)
+		if t.RelatedTransactionID != nil {
+			relatedIDs = append(relatedIDs, *t.RelatedTransactionID)
+		}
+		if t.JournalEntryID != nil {
+			journalIDs = append(journalIDs, *t.JournalEntryID)
+		}
+	}
+
+	links := withContext(r.db, ctx).Where("related_transaction_id IN ?", append(append([]uint{}, ids...), relatedIDs...))
+	if len(relatedIDs) > 0 {
+		links = links.Or("id IN ?", relatedIDs)
 	}
+	if len(journalIDs) > 0 {
+		links = links.Or("journal_entry_id IN ?", journalIDs)
+	}
+
+	var linked []models.Transaction
+	err := withContext(r.db, ctx).Unscoped().
+		Where("id NOT IN ?", ids).
+		Where(links).
+		Find(&linked).Error
+	return linked, err
+}
+
+// GetCaseReferencedIDs returns which of the given transactions a fraud review or AML case points at
+func (r *transactionRepository) GetCaseReferencedIDs(ctx context.Context, ids []uint) ([]uint, error) {
+	if len(ids) == 0 {
+		return nil, nil
+	}
+	var referenced []uint
+	err := withContext(r.db, ctx).Raw("SELECT transaction_id FROM fraud_reviews WHERE transaction_id IN ? UNION SELECT transaction_id FROM aml_cases WHERE transaction_id IN ?", ids, ids).
+		Scan(&referenced).Error
+	return referenced, err
+}
+
+// SumDebitsSince totals the completed, pending and held debits of a wallet since the given time that
+// count toward tier limits, which leaves out fees and the other purposes that do not count
+func (r *transactionRepository) SumDebitsSince(ctx context.Context, walletID uint, since time.Time) (decimal.Decimal, error) {
+	var total decimal.Decimal
+	err := withContext(r.db, ctx).Table("transactions t").
+		Joins("JOIN transaction_purposes p ON p.name = t.transaction_purpose AND p.counts_toward_limits = ?", true).
+		Where("t.wallet_id = ? AND t.transaction_type = ? AND t.status IN ? AND t.created_at >= ?",
+			walletID, models.TransactionTypeDebit, heldDebitStatuses, since).
+		Select("COALESCE(SUM(t.amount), 0)").
+		Scan(&total).Error
+	return total, err
+}
+
+// GetDebitsSince returns the completed, pending and held debits of a wallet since the given time, leaving out fees
+func (r *transactionRepository) GetDebitsSince(ctx context.Context, walletID uint, since time.Time) ([]models.Transaction, error) {
+	var transactions []models.Transaction
+	err := withContext(r.db, ctx).Where("wallet_id = ? AND transaction_type = ? AND status IN ? AND transaction_purpose <> ? AND created_at >= ?",
+		walletID, models.TransactionTypeDebit, heldDebitStatuses, models.TransactionPurposeFee, since).
+		Order("created_at DESC, id DESC").
+		Find(&transactions).Error
+	return transactions, err
+}
+
+// SumCategoryDebitsSince totals the completed debits of a wallet tagged with the category since the
+// given time
+func (r *transactionRepository) SumCategoryDebitsSince(ctx context.Context, walletID uint, category string, since time.Time) (decimal.Decimal, error) {
+	var total decimal.Decimal
+	err := withContext(r.db, ctx).Table("transactions t").
+		Where("t.wallet_id = ? AND t.transaction_type = ? AND t.status = ? AND t.category = ? AND t.created_at >= ?",
+			walletID, models.TransactionTypeDebit, models.TransactionStatusCompleted, category, since).
+		Select("COALESCE(SUM(t.amount), 0)").
+		Scan(&total).Error
+	return total, err
+}
+
+// SetCategory tags a transaction with a spending category, leaving its other columns alone
+func (r *transactionRepository) SetCategory(ctx context.Context, id uint, category string) error {
+	return withContext(r.db, ctx).Model(&models.Transaction{}).Where("id = ?", id).Update("category", category).Error
+}
+
+// HasTransferBetween reports whether a transfer from one wallet to the other has completed before
+func (r *transactionRepository) HasTransferBetween(ctx context.Context, fromWalletID, toWalletID uint) (bool, error) {
+	var count int64
+	err := withContext(r.db, ctx).Table("transactions o").
+		Joins("JOIN transactions i ON i.related_transaction_id = o.id").
+		Where("o.wallet_id = ? AND o.transaction_type = ? AND o.transaction_purpose = ? AND o.status = ? AND i.wallet_id = ? AND i.transaction_type = ?",
+			fromWalletID, models.TransactionTypeDebit, models.TransactionPurposeTransfer, models.TransactionStatusCompleted,
+			toWalletID, models.TransactionTypeCredit).
+		Count(&count).Error
+	return count > 0, err
+}
 
-	return creditSum.Sub(debitSum), nil
+// GetPendingDebitsBefore returns withdrawals and transfers still PENDING that were created before the
+// given time, oldest first; fee legs and counter legs are left out
+func (r *transactionRepository) GetPendingDebitsBefore(ctx context.Context, before time.Time, limit int) ([]models.Transaction, error) {
+	var transactions []models.Transaction
+	err := withContext(r.db, ctx).Where("status = ? AND transaction_type = ? AND transaction_purpose IN ? AND created_at < ?",
+		models.TransactionStatusPending, models.TransactionTypeDebit,
+		[]models.TransactionPurpose{models.TransactionPurposeWithdrawal, models.TransactionPurposeTransfer}, before).
+		Order("created_at ASC, id ASC").
+		Limit(limit).
+		Find(&transactions).Error
+	return transactions, err
 }
 
-func (r *transactionRepository) List(offset, limit int) ([]models.Transaction, error) {
+func (r *transactionRepository) List(ctx context.Context, offset, limit int) ([]models.Transaction, error) {
 	var transactions []models.Transaction
-	err := r.db.Preload("Wallet").
+	err := withContext(r.db, ctx).Preload("Wallet").
 		Order("created_at DESC").
 		Offset(offset).Limit(limit).
 		Find(&transactions).Error
 	return transactions, err
 }
+
+// FindCompletedByWalletID hands the completed transactions of a wallet to fn in batches, with their
+// related transaction loaded, so the double-entry check never holds a whole ledger in memory
+func (r *transactionRepository) FindCompletedByWalletID(ctx context.Context, walletID uint, batchSize int, fn func([]models.Transaction) error) error {
+	var batch []models.Transaction
+	return withContext(r.db, withQueryClass(ctx, reconciliationQuery)).Preload("RelatedTransaction").
+		Where("wallet_id = ? AND status = ?", walletID, models.TransactionStatusCompleted).
+		FindInBatches(&batch, batchSize, func(tx *gorm.DB, _ int) error {
+			return fn(batch)
+		}).Error
+}
+
+// GetByRelatedTransactionID returns the transactions pointing at the given one, such as the fee legs
+// charged for it
+func (r *transactionRepository) GetByRelatedTransactionID(ctx context.Context, relatedID uint) ([]models.Transaction, error) {
+	var transactions []models.Transaction
+	err := withContext(r.db, ctx).Where("related_transaction_id = ?", relatedID).
+		Order("id ASC").
+		Find(&transactions).Error
+	return transactions, err
+}
+
+// GetByWalletIDBetween returns a wallet's transactions created from from up to but not including to,
+// oldest first
+func (r *transactionRepository) GetByWalletIDBetween(ctx context.Context, walletID uint, from, to time.Time) ([]models.Transaction, error) {
+	var transactions []models.Transaction
+	err := withContext(r.db, ctx).Where("wallet_id = ? AND created_at >= ? AND created_at < ?", walletID, from, to).
+		Order("created_at ASC, id ASC").
+		Find(&transactions).Error
+	return transactions, err
+}
+
+func (r *transactionRepository) GetLegs(ctx context.Context, id uint) ([]models.Transaction, error) {
+	var transactions []models.Transaction
+	err := withContext(r.db, ctx).Where("id = ? OR related_transaction_id = ?", id, id).
+		Order("id ASC").
+		Find(&transactions).Error
+	return transactions, err
+}
+
+func (r *transactionRepository) UpdateStatus(ctx context.Context, id uint, from, to models.TransactionStatus) (bool, error) {
+	result := withContext(r.db, ctx).Model(&models.Transaction{}).
+		Where("id = ? AND status = ?", id, from).
+		Update("status", to)
+	return result.RowsAffected > 0, result.Error
+}
+
+func (r *transactionRepository) UpdateLegsStatus(ctx context.Context, id uint, status models.TransactionStatus) error {
+	return withContext(r.db, ctx).Model(&models.Transaction{}).
+		Where("id = ? OR related_transaction_id = ?", id, id).
+		Update("status", status).Error
+}
+
+func (r *transactionRepository) SetBalances(ctx context.Context, id uint, balanceBefore, balanceAfter decimal.Decimal) error {
+	return withContext(r.db, ctx).Model(&models.Transaction{}).Where("id = ?", id).
+		Updates(map[string]interface{}{
+			"balance_before": balanceBefore,
+			"balance_after":  balanceAfter,
+		}).Error
+}
+
+// DeleteArchived deletes the rows for good; transfer legs point at each other, so the links are cleared
+// before either is deleted
+func (r *transactionRepository) DeleteArchived(ctx context.Context, ids []uint) error {
+	db := withContext(r.db, ctx).Unscoped()
+	if err := db.Model(&models.Transaction{}).
+		Where("id IN ? AND related_transaction_id IS NOT NULL", ids).
+		Update("related_transaction_id", nil).Error; err != nil {
+		return err
+	}
+	return db.Where("id IN ?", ids).Delete(&models.Transaction{}).Error
+}
@@ -0,0 +1,229 @@
+package repositories
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/limistah/wallet-service/internal/models"
+	"github.com/shopspring/decimal"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"gorm.io/gorm/logger"
+)
+
+func newTransactionTestDB(t *testing.T) *gorm.DB {
+	t.Helper()
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{Logger: logger.Default.LogMode(logger.Silent)})
+	if err != nil {
+		t.Fatalf("Failed to open database: %v", err)
+	}
+	if err := db.AutoMigrate(&models.Transaction{}); err != nil {
+		t.Fatalf("Failed to create transactions table: %v", err)
+	}
+	return db
+}
+
+func insertTransaction(t *testing.T, db *gorm.DB, walletID uint, transactionType models.TransactionType, status models.TransactionStatus, amount string) *models.Transaction {
+	t.Helper()
+	var count int64
+	db.Table("transactions").Count(&count)
+	transaction := &models.Transaction{
+		Reference:          fmt.Sprintf("TXN-%d", count+1),
+		WalletID:           walletID,
+		TransactionPurpose: models.TransactionPurposeWalletTopUp,
+		TransactionType:    transactionType,
+		Amount:             decimal.RequireFromString(amount),
+		Status:             status,
+	}
+	if err := db.Omit(clause.Associations).Create(transaction).Error; err != nil {
+		t.Fatalf("Failed to insert transaction: %v", err)
+	}
+	return transaction
+}
+
+func TestTransactionRepository_CalculateBalance(t *testing.T) {
+	db := newTransactionTestDB(t)
+	repo := NewTransactionRepository(db)
+
+	balance, err := repo.CalculateBalance(context.Background(), 1)
+	if err != nil {
+		t.Fatalf("CalculateBalance() error = %v", err)
+	}
+	if !balance.IsZero() {
+		t.Errorf("CalculateBalance() of a wallet without transactions = %v, want 0", balance)
+	}
+
+	insertTransaction(t, db, 1, models.TransactionTypeCredit, models.TransactionStatusCompleted, "500.50")
+	insertTransaction(t, db, 1, models.TransactionTypeCredit, models.TransactionStatusPending, "70")
+	insertTransaction(t, db, 1, models.TransactionTypeCredit, models.TransactionStatusFailed, "90")
+	insertTransaction(t, db, 1, models.TransactionTypeDebit, models.TransactionStatusCompleted, "100.25")
+	insertTransaction(t, db, 1, models.TransactionTypeDebit, models.TransactionStatusPending, "20")
+	insertTransaction(t, db, 1, models.TransactionTypeDebit, models.TransactionStatusPendingReview, "30")
+	insertTransaction(t, db, 1, models.TransactionTypeDebit, models.TransactionStatusCancelled, "40")
+	insertTransaction(t, db, 1, models.TransactionTypeDebit, models.TransactionStatusFailed, "50")
+	insertTransaction(t, db, 2, models.TransactionTypeCredit, models.TransactionStatusCompleted, "1000")
+
+	tests := []struct {
+		name     string
+		walletID uint
+		want     string
+	}{
+		// 500.50 completed credit less 100.25 completed, 20 pending and 30 held debits
+		{"completed credits less held debits", 1, "350.25"},
+		{"other wallet", 2, "1000"},
+		{"unknown wallet", 3, "0"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := repo.CalculateBalance(context.Background(), tt.walletID)
+			if err != nil {
+				t.Fatalf("CalculateBalance() error = %v", err)
+			}
+			if !got.Equal(decimal.RequireFromString(tt.want)) {
+				t.Errorf("CalculateBalance(%d) = %v, want %s", tt.walletID, got, tt.want)
+			}
+		})
+	}
+
+	// Debits can take a wallet below zero in the sum, for example when a stored balance was corrupted
+	insertTransaction(t, db, 3, models.TransactionTypeDebit, models.TransactionStatusCompleted, "15")
+	if got, _ := repo.CalculateBalance(context.Background(), 3); !got.Equal(decimal.NewFromInt(-15)) {
+		t.Errorf("CalculateBalance(3) = %v, want -15", got)
+	}
+}
+
+func TestTransactionRepository_CalculateBalanceAfterCheckpoint(t *testing.T) {
+	db := newTransactionTestDB(t)
+	repo := NewTransactionRepository(db)
+
+	first := insertTransaction(t, db, 1, models.TransactionTypeCredit, models.TransactionStatusCompleted, "300")
+	second := insertTransaction(t, db, 1, models.TransactionTypeDebit, models.TransactionStatusCompleted, "100")
+	insertTransaction(t, db, 1, models.TransactionTypeCredit, models.TransactionStatusCompleted, "40")
+	insertTransaction(t, db, 1, models.TransactionTypeDebit, models.TransactionStatusPending, "15")
+
+	total, err := repo.CalculateBalance(context.Background(), 1)
+	if err != nil {
+		t.Fatalf("CalculateBalance() error = %v", err)
+	}
+	covered, err := repo.CalculateBalanceBetween(context.Background(), 1, 0, second.ID)
+	if err != nil {
+		t.Fatalf("CalculateBalanceBetween() error = %v", err)
+	}
+	after, err := repo.CalculateBalanceAfter(context.Background(), 1, second.ID)
+	if err != nil {
+		t.Fatalf("CalculateBalanceAfter() error = %v", err)
+	}
+
+	if !covered.Equal(decimal.NewFromInt(200)) || !after.Equal(decimal.NewFromInt(25)) {
+		t.Errorf("Expected 200 through transaction %d and 25 after it, got %v and %v", second.ID, covered, after)
+	}
+	if !covered.Add(after).Equal(total) {
+		t.Errorf("Expected the checkpointed and later sums to add up to %v, got %v", total, covered.Add(after))
+	}
+	if got, _ := repo.CalculateBalanceBetween(context.Background(), 1, first.ID, second.ID); !got.Equal(decimal.NewFromInt(-100)) {
+		t.Errorf("CalculateBalanceBetween(%d, %d) = %v, want -100", first.ID, second.ID, got)
+	}
+}
+
+func TestTransactionRepository_GetSettledThrough(t *testing.T) {
+	db := newTransactionTestDB(t)
+	repo := NewTransactionRepository(db)
+	cutoff := time.Now().Add(time.Hour)
+
+	if through, err := repo.GetSettledThrough(context.Background(), 1, cutoff); err != nil || through != 0 {
+		t.Fatalf("GetSettledThrough() of a wallet without transactions = %d, %v; want 0", through, err)
+	}
+
+	insertTransaction(t, db, 1, models.TransactionTypeCredit, models.TransactionStatusCompleted, "300")
+	failed := insertTransaction(t, db, 1, models.TransactionTypeDebit, models.TransactionStatusFailed, "100")
+	insertTransaction(t, db, 2, models.TransactionTypeCredit, models.TransactionStatusPending, "10")
+	pending := insertTransaction(t, db, 1, models.TransactionTypeDebit, models.TransactionStatusPending, "50")
+	insertTransaction(t, db, 1, models.TransactionTypeCredit, models.TransactionStatusCompleted, "20")
+
+	if through, err := repo.GetSettledThrough(context.Background(), 1, cutoff); err != nil || through != failed.ID {
+		t.Errorf("GetSettledThrough() = %d, %v; want %d, stopping before the pending debit", through, err, failed.ID)
+	}
+
+	pending.Status = models.TransactionStatusCompleted
+	db.Omit(clause.Associations).Save(pending)
+	if through, err := repo.GetSettledThrough(context.Background(), 1, cutoff); err != nil || through != pending.ID+1 {
+		t.Errorf("GetSettledThrough() = %d, %v; want %d once everything settled", through, err, pending.ID+1)
+	}
+	if through, err := repo.GetSettledThrough(context.Background(), 1, time.Now().Add(-time.Hour)); err != nil || through != 0 {
+		t.Errorf("GetSettledThrough() before any transaction = %d, %v; want 0", through, err)
+	}
+}
+
+func TestTransactionRepository_SumCategoryDebitsSince(t *testing.T) {
+	db := newTransactionTestDB(t)
+	repo := NewTransactionRepository(db)
+	since := time.Now().Add(-time.Hour)
+
+	groceries := insertTransaction(t, db, 1, models.TransactionTypeDebit, models.TransactionStatusCompleted, "40")
+	pending := insertTransaction(t, db, 1, models.TransactionTypeDebit, models.TransactionStatusPending, "25")
+	other := insertTransaction(t, db, 2, models.TransactionTypeDebit, models.TransactionStatusCompleted, "70")
+	insertTransaction(t, db, 1, models.TransactionTypeDebit, models.TransactionStatusCompleted, "15")
+	for _, transaction := range []*models.Transaction{groceries, pending, other} {
+		if err := repo.SetCategory(context.Background(), transaction.ID, "groceries"); err != nil {
+			t.Fatalf("SetCategory() error = %v", err)
+		}
+	}
+
+	total, err := repo.SumCategoryDebitsSince(context.Background(), 1, "groceries", since)
+	if err != nil {
+		t.Fatalf("SumCategoryDebitsSince() error = %v", err)
+	}
+	if !total.Equal(decimal.NewFromInt(40)) {
+		t.Errorf("SumCategoryDebitsSince() = %v, want 40 from the completed debit of the wallet", total)
+	}
+	if total, _ := repo.SumCategoryDebitsSince(context.Background(), 1, "groceries", time.Now().Add(time.Hour)); !total.IsZero() {
+		t.Errorf("SumCategoryDebitsSince() after every debit = %v, want 0", total)
+	}
+}
+
+func TestTransactionRepository_SumDebitsSince(t *testing.T) {
+	db := newTransactionTestDB(t)
+	if err := db.AutoMigrate(&models.ArchivedTransaction{}); err != nil {
+		t.Fatalf("Failed to create archived transactions table: %v", err)
+	}
+	purposes := models.DefaultTransactionPurposes()
+	if err := db.Create(&purposes).Error; err != nil {
+		t.Fatalf("Failed to seed transaction purposes: %v", err)
+	}
+	repo := NewTransactionRepository(db)
+	purposeRepo := NewTransactionPurposeRepository(db)
+	since := time.Now().Add(-time.Hour)
+
+	transfer := insertTransaction(t, db, 1, models.TransactionTypeDebit, models.TransactionStatusCompleted, "60")
+	fee := insertTransaction(t, db, 1, models.TransactionTypeDebit, models.TransactionStatusCompleted, "5")
+	insertTransaction(t, db, 1, models.TransactionTypeDebit, models.TransactionStatusFailed, "80")
+	db.Model(transfer).Update("transaction_purpose", models.TransactionPurposeTransfer)
+	db.Model(fee).Update("transaction_purpose", models.TransactionPurposeFee)
+
+	total, err := repo.SumDebitsSince(context.Background(), 1, since)
+	if err != nil {
+		t.Fatalf("SumDebitsSince() error = %v", err)
+	}
+	if !total.Equal(decimal.NewFromInt(60)) {
+		t.Errorf("SumDebitsSince() = %v, want 60 without the fee and the failed debit", total)
+	}
+
+	definition, _ := purposeRepo.GetByName(context.Background(), models.TransactionPurposeTransfer)
+	definition.CountsTowardLimits = false
+	if err := purposeRepo.Update(context.Background(), definition); err != nil {
+		t.Fatalf("Update() error = %v", err)
+	}
+	if total, _ := repo.SumDebitsSince(context.Background(), 1, since); !total.IsZero() {
+		t.Errorf("SumDebitsSince() = %v, want 0 once transfers stop counting toward limits", total)
+	}
+
+	if inUse, err := purposeRepo.InUse(context.Background(), models.TransactionPurposeFee); err != nil || !inUse {
+		t.Errorf("InUse(FEE) = %v, %v; want true", inUse, err)
+	}
+	if inUse, err := purposeRepo.InUse(context.Background(), models.TransactionPurposeRefund); err != nil || inUse {
+		t.Errorf("InUse(REFUND) = %v, %v; want false", inUse, err)
+	}
+}
@@ -0,0 +1,30 @@
+package repositories
+
+import (
+	"context"
+
+	"github.com/limistah/wallet-service/internal/models"
+	"gorm.io/gorm"
+)
+
+type transactionStatusChangeRepository struct {
+	db *gorm.DB
+}
+
+// NewTransactionStatusChangeRepository creates a new transaction status change repository
+func NewTransactionStatusChangeRepository(db *gorm.DB) TransactionStatusChangeRepository {
+	return &transactionStatusChangeRepository{db: db}
+}
+
+// ListByTransactionID returns the status changes of a transaction oldest first
+func (r *transactionStatusChangeRepository) ListByTransactionID(ctx context.Context, transactionID uint) ([]models.TransactionStatusChange, error) {
+	var changes []models.TransactionStatusChange
+	err := withContext(r.db, ctx).Where("transaction_id = ?", transactionID).
+		Order("created_at ASC, id ASC").
+		Find(&changes).Error
+	return changes, err
+}
+
+func (r *transactionStatusChangeRepository) Create(ctx context.Context, changes []models.TransactionStatusChange) error {
+	return withContext(r.db, ctx).Create(&changes).Error
+}
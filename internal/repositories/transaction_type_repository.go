@@ -0,0 +1,35 @@
+package repositories
+
+import (
+	"context"
+
+	"github.com/limistah/wallet-service/internal/models"
+	"gorm.io/gorm"
+)
+
+type transactionTypeRepository struct {
+	db *gorm.DB
+}
+
+// NewTransactionTypeRepository creates a new transaction type repository
+func NewTransactionTypeRepository(db *gorm.DB) TransactionTypeRepository {
+	return &transactionTypeRepository{db: db}
+}
+
+func (r *transactionTypeRepository) GetByName(ctx context.Context, name models.TransactionType) (*models.TransactionTypeDefinition, error) {
+	var definition models.TransactionTypeDefinition
+	if err := withContext(r.db, ctx).Where("name = ?", name).First(&definition).Error; err != nil {
+		return nil, err
+	}
+	return &definition, nil
+}
+
+func (r *transactionTypeRepository) List(ctx context.Context) ([]models.TransactionTypeDefinition, error) {
+	var definitions []models.TransactionTypeDefinition
+	err := withContext(r.db, ctx).Order("name ASC").Find(&definitions).Error
+	return definitions, err
+}
+
+func (r *transactionTypeRepository) Create(ctx context.Context, definition *models.TransactionTypeDefinition) error {
+	return withContext(r.db, ctx).Create(definition).Error
+}
@@ -0,0 +1,52 @@
+package repositories
+
+import (
+	"context"
+	"time"
+
+	"github.com/limistah/wallet-service/internal/models"
+	"gorm.io/gorm"
+)
+
+type transferChallengeRepository struct {
+	db *gorm.DB
+}
+
+// NewTransferChallengeRepository creates a new transfer challenge repository
+func NewTransferChallengeRepository(db *gorm.DB) TransferChallengeRepository {
+	return &transferChallengeRepository{db: db}
+}
+
+func (r *transferChallengeRepository) Create(ctx context.Context, challenge *models.TransferChallenge) error {
+	return withContext(r.db, ctx).Create(challenge).Error
+}
+
+func (r *transferChallengeRepository) GetByID(ctx context.Context, id uint) (*models.TransferChallenge, error) {
+	var challenge models.TransferChallenge
+	if err := withContext(r.db, ctx).First(&challenge, id).Error; err != nil {
+		return nil, err
+	}
+	return &challenge, nil
+}
+
+// IncrementAttempts counts a wrong code in the database, so concurrent guesses are all counted
+func (r *transferChallengeRepository) IncrementAttempts(ctx context.Context, id uint) error {
+	return withContext(r.db, ctx).Model(&models.TransferChallenge{}).Where("id = ?", id).
+		UpdateColumn("attempts", gorm.Expr("attempts + 1")).Error
+}
+
+// MarkConfirmed consumes a challenge; it reports false when a concurrent request confirmed it first
+func (r *transferChallengeRepository) MarkConfirmed(ctx context.Context, id uint, at time.Time) (bool, error) {
+	result := withContext(r.db, ctx).Model(&models.TransferChallenge{}).
+		Where("id = ? AND confirmed_at IS NULL", id).
+		Update("confirmed_at", at)
+	if result.Error != nil {
+		return false, result.Error
+	}
+	return result.RowsAffected > 0, nil
+}
+
+// DeleteExpired removes the challenges that expired before the given time
+func (r *transferChallengeRepository) DeleteExpired(ctx context.Context, before time.Time) error {
+	return withContext(r.db, ctx).Where("expires_at < ?", before).Delete(&models.TransferChallenge{}).Error
+}
@@ -0,0 +1,43 @@
+package repositories
+
+import (
+	"context"
+	"time"
+
+	"github.com/limistah/wallet-service/internal/models"
+	"gorm.io/gorm"
+)
+
+type transferQuoteRepository struct {
+	db *gorm.DB
+}
+
+// NewTransferQuoteRepository creates a new transfer quote repository
+func NewTransferQuoteRepository(db *gorm.DB) TransferQuoteRepository {
+	return &transferQuoteRepository{db: db}
+}
+
+func (r *transferQuoteRepository) Create(ctx context.Context, quote *models.TransferQuote) error {
+	return withContext(r.db, ctx).Create(quote).Error
+}
+
+func (r *transferQuoteRepository) GetByID(ctx context.Context, id uint) (*models.TransferQuote, error) {
+	var quote models.TransferQuote
+	if err := withContext(r.db, ctx).First(&quote, id).Error; err != nil {
+		return nil, err
+	}
+	return &quote, nil
+}
+
+// DeleteExpired removes the quotes that expired before the given time
+func (r *transferQuoteRepository) DeleteExpired(ctx context.Context, before time.Time) error {
+	return withContext(r.db, ctx).Where("expires_at < ?", before).Delete(&models.TransferQuote{}).Error
+}
+
+// Use sets the used time of a quote that is unused and not expired at now
+func (r *transferQuoteRepository) Use(ctx context.Context, id uint, now time.Time) (bool, error) {
+	result := withContext(r.db, ctx).Model(&models.TransferQuote{}).
+		Where("id = ? AND used_at IS NULL AND expires_at > ?", id, now).
+		Update("used_at", now)
+	return result.RowsAffected > 0, result.Error
+}
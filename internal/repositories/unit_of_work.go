@@ -0,0 +1,107 @@
+package repositories
+
+import (
+	"context"
+	"reflect"
+	"sync"
+
+	"gorm.io/gorm"
+)
+
+// UnitOfWork runs a function against repositories whose changes are kept together: all of them when
+// the function succeeds, none of them when it fails
+type UnitOfWork interface {
+	// Do runs fn with repositories bound to the unit; fn must only change state through them
+	Do(ctx context.Context, fn func(tx *Repositories) error) error
+}
+
+// gormUnitOfWork runs units in database transactions of the repositories it belongs to
+type gormUnitOfWork struct {
+	repos *Repositories
+}
+
+// Do runs fn with repositories bound to a database transaction, and runs it again from the start when
+// the database aborts the transaction for a transient reason
+func (u gormUnitOfWork) Do(ctx context.Context, fn func(tx *Repositories) error) error {
+	return u.repos.RunInTransaction(ctx, func(tx *gorm.DB) error {
+		bound := NewRepositories(tx)
+		// Retries belong to the outermost transaction; a nested unit runs in a savepoint
+		bound.TransactionRetry = RetryPolicy{MaxAttempts: 1}
+		return fn(bound)
+	})
+}
+
+// Snapshotter is implemented by repositories without a database that the in-memory unit of work can
+// roll back
+type Snapshotter interface {
+	// Snapshot saves what the repository holds and returns a function restoring it
+	Snapshot() (restore func())
+}
+
+// InMemoryUnitOfWork runs units directly against the repositories it was created with, for tests
+// and for running without a database (DB_DRIVER=memory). Units run one at a time; when one fails, the
+// repositories implementing Snapshotter are restored to what they held before it started, and so are
+// the changes made outside units in the meantime
+type InMemoryUnitOfWork struct {
+	mu    sync.Mutex
+	repos *Repositories
+}
+
+// NewInMemoryUnitOfWork creates a unit of work over repos and makes it theirs
+func NewInMemoryUnitOfWork(repos *Repositories) *InMemoryUnitOfWork {
+	unit := &InMemoryUnitOfWork{repos: repos}
+	repos.UnitOfWork = unit
+	return unit
+}
+
+// Do runs fn with the repositories of the unit of work, whose nested units join this one and roll
+// back on their own like savepoints
+func (u *InMemoryUnitOfWork) Do(ctx context.Context, fn func(tx *Repositories) error) error {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+	bound := *u.repos
+	bound.UnitOfWork = nestedUnitOfWork{repos: &bound}
+	return runRestoringOnError(&bound, fn)
+}
+
+// nestedUnitOfWork runs units inside a running in-memory unit
+type nestedUnitOfWork struct {
+	repos *Repositories
+}
+
+func (u nestedUnitOfWork) Do(ctx context.Context, fn func(tx *Repositories) error) error {
+	return runRestoringOnError(u.repos, fn)
+}
+
+// runRestoringOnError runs fn and restores the repositories it may have changed when it fails
+func runRestoringOnError(repos *Repositories, fn func(tx *Repositories) error) error {
+	restore := snapshot(repos)
+	if err := fn(repos); err != nil {
+		restore()
+		return err
+	}
+	return nil
+}
+
+// snapshot saves every repository of repos implementing Snapshotter and returns a function restoring
+// them all
+func snapshot(repos *Repositories) func() {
+	var restores []func()
+	seen := make(map[Snapshotter]bool)
+	fields := reflect.ValueOf(repos).Elem()
+	for i := 0; i < fields.NumField(); i++ {
+		field := fields.Field(i)
+		if field.Kind() != reflect.Interface || field.IsNil() {
+			continue
+		}
+		if snapshotter, ok := field.Interface().(Snapshotter); ok && !seen[snapshotter] {
+			seen[snapshotter] = true
+			restores = append(restores, snapshotter.Snapshot())
+		}
+	}
+	return func() {
+		for _, restore := range restores {
+			restore()
+		}
+	}
+}
@@ -1,6 +1,9 @@
 package repositories
 
 import (
+	"context"
+	"time"
+
 	"github.com/limistah/wallet-service/internal/models"
 	"gorm.io/gorm"
 )
@@ -14,38 +17,140 @@ func NewUserRepository(db *gorm.DB) UserRepository {
 	return &userRepository{db: db}
 }
 
-func (r *userRepository) Create(user *models.User) error {
-	return r.db.Create(user).Error
+func (r *userRepository) Create(ctx context.Context, user *models.User) error {
+	return withContext(r.db, ctx).Create(user).Error
+}
+
+func (r *userRepository) GetByID(ctx context.Context, id uint) (*models.User, error) {
+	var user models.User
+	err := withContext(r.db, ctx).Preload("Wallets").First(&user, id).Error
+	if err != nil {
+		return nil, err
+	}
+	return &user, nil
+}
+
+func (r *userRepository) GetByEmail(ctx context.Context, email string) (*models.User, error) {
+	var user models.User
+	err := withContext(r.db, ctx).Preload("Wallets").Where("email = ?", email).First(&user).Error
+	if err != nil {
+		return nil, err
+	}
+	return &user, nil
 }
 
-func (r *userRepository) GetByID(id uint) (*models.User, error) {
+func (r *userRepository) GetByEmailInTenant(ctx context.Context, tenantID uint, email string) (*models.User, error) {
 	var user models.User
-	err := r.db.Preload("Wallets").First(&user, id).Error
+	err := withContext(r.db, ctx).Scopes(TenantScope(tenantID)).Where("email = ?", email).First(&user).Error
 	if err != nil {
 		return nil, err
 	}
 	return &user, nil
 }
 
-func (r *userRepository) GetByEmail(email string) (*models.User, error) {
+func (r *userRepository) GetByHandle(ctx context.Context, handle string) (*models.User, error) {
 	var user models.User
-	err := r.db.Preload("Wallets").Where("email = ?", email).First(&user).Error
+	err := withContext(r.db, ctx).Where("handle = ?", handle).First(&user).Error
 	if err != nil {
 		return nil, err
 	}
 	return &user, nil
 }
 
-func (r *userRepository) Update(user *models.User) error {
-	return r.db.Save(user).Error
+func (r *userRepository) SetHandle(ctx context.Context, id uint, handle *string) error {
+	return withContext(r.db, ctx).Model(&models.User{}).Where("id = ?", id).Update("handle", handle).Error
+}
+
+func (r *userRepository) Update(ctx context.Context, user *models.User) error {
+	return withContext(r.db, ctx).Save(user).Error
+}
+
+func (r *userRepository) Delete(ctx context.Context, id uint) error {
+	return withContext(r.db, ctx).Delete(&models.User{}, id).Error
+}
+
+func (r *userRepository) List(ctx context.Context, offset, limit int) ([]models.User, error) {
+	var users []models.User
+	err := withContext(r.db, ctx).Preload("Wallets").Offset(offset).Limit(limit).Find(&users).Error
+	return users, err
+}
+
+// RevokeTokens revokes every token of the user issued up to at
+func (r *userRepository) RevokeTokens(ctx context.Context, id uint, at time.Time) error {
+	return withContext(r.db, ctx).Model(&models.User{}).Where("id = ?", id).Update("tokens_revoked_at", at).Error
+}
+
+// GetTokensRevokedAt loads only the revocation time, it is checked on every authenticated request
+func (r *userRepository) GetTokensRevokedAt(ctx context.Context, id uint) (*time.Time, error) {
+	var user models.User
+	err := withContext(r.db, ctx).Select("id", "tokens_revoked_at").First(&user, id).Error
+	if err != nil {
+		return nil, err
+	}
+	return user.TokensRevokedAt, nil
+}
+
+// IncrementFailedLogins counts a wrong password in the database, so concurrent attempts are all
+// counted, and returns the new count
+func (r *userRepository) IncrementFailedLogins(ctx context.Context, id uint) (int, error) {
+	var attempts int
+	err := withContext(r.db, ctx).Transaction(func(tx *gorm.DB) error {
+		err := tx.Model(&models.User{}).Where("id = ?", id).
+			UpdateColumn("failed_login_attempts", gorm.Expr("failed_login_attempts + 1")).Error
+		if err != nil {
+			return err
+		}
+		return tx.Model(&models.User{}).Where("id = ?", id).Pluck("failed_login_attempts", &attempts).Error
+	})
+	return attempts, err
+}
+
+// SetLoginLock locks the account until lockedUntil, or unlocks it when nil; either way the failed
+// login count starts over
+func (r *userRepository) SetLoginLock(ctx context.Context, id uint, lockedUntil *time.Time) error {
+	return withContext(r.db, ctx).Model(&models.User{}).Where("id = ?", id).Updates(map[string]interface{}{
+		"failed_login_attempts": 0,
+		"locked_until":          lockedUntil,
+	}).Error
+}
+
+// UpdateTransactionPIN stores a new transaction PIN hash and lifts any PIN lock
+func (r *userRepository) UpdateTransactionPIN(ctx context.Context, id uint, hashedPIN string) error {
+	return withContext(r.db, ctx).Model(&models.User{}).Where("id = ?", id).Updates(map[string]interface{}{
+		"transaction_pin":     hashedPIN,
+		"failed_pin_attempts": 0,
+		"pin_locked_until":    nil,
+	}).Error
+}
+
+// IncrementFailedPINAttempts counts a wrong transaction PIN in the database and returns the new count
+func (r *userRepository) IncrementFailedPINAttempts(ctx context.Context, id uint) (int, error) {
+	var attempts int
+	err := withContext(r.db, ctx).Transaction(func(tx *gorm.DB) error {
+		err := tx.Model(&models.User{}).Where("id = ?", id).
+			UpdateColumn("failed_pin_attempts", gorm.Expr("failed_pin_attempts + 1")).Error
+		if err != nil {
+			return err
+		}
+		return tx.Model(&models.User{}).Where("id = ?", id).Pluck("failed_pin_attempts", &attempts).Error
+	})
+	return attempts, err
 }
 
-func (r *userRepository) Delete(id uint) error {
-	return r.db.Delete(&models.User{}, id).Error
+// SetPINLock locks the transaction PIN until lockedUntil, or unlocks it when nil; either way the
+// failed PIN count starts over
+func (r *userRepository) SetPINLock(ctx context.Context, id uint, lockedUntil *time.Time) error {
+	return withContext(r.db, ctx).Model(&models.User{}).Where("id = ?", id).Updates(map[string]interface{}{
+		"failed_pin_attempts": 0,
+		"pin_locked_until":    lockedUntil,
+	}).Error
 }
 
-func (r *userRepository) List(offset, limit int) ([]models.User, error) {
+// ListDeletionsDue returns users who asked for deletion before the cutoff and were not anonymized yet,
+// oldest request first
+func (r *userRepository) ListDeletionsDue(ctx context.Context, before time.Time, limit int) ([]models.User, error) {
 	var users []models.User
-	err := r.db.Preload("Wallets").Offset(offset).Limit(limit).Find(&users).Error
+	err := withContext(r.db, ctx).Where("deletion_requested_at <= ? AND anonymized_at IS NULL", before).
+		Order("deletion_requested_at").Limit(limit).Find(&users).Error
 	return users, err
 }
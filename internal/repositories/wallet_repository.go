@@ -1,6 +1,8 @@
 package repositories
 
 import (
+	"context"
+
 	"github.com/limistah/wallet-service/internal/models"
 	"github.com/shopspring/decimal"
 	"gorm.io/gorm"
@@ -15,35 +17,45 @@ func NewWalletRepository(db *gorm.DB) WalletRepository {
 	return &walletRepository{db: db}
 }
 
-func (r *walletRepository) Create(wallet *models.Wallet) error {
-	return r.db.Create(wallet).Error
+func (r *walletRepository) Create(ctx context.Context, wallet *models.Wallet) error {
+	return withContext(r.db, ctx).Create(wallet).Error
 }
 
-func (r *walletRepository) GetByID(id uint) (*models.Wallet, error) {
+func (r *walletRepository) GetByID(ctx context.Context, id uint) (*models.Wallet, error) {
 	var wallet models.Wallet
-	err := r.db.Preload("User").First(&wallet, id).Error
+	err := withContext(r.db, withQueryClass(ctx, balanceQuery)).Preload("User").First(&wallet, id).Error
 	if err != nil {
 		return nil, err
 	}
 	return &wallet, nil
 }
 
-func (r *walletRepository) GetByUserID(userID uint) (*models.Wallet, error) {
+func (r *walletRepository) GetByUserID(ctx context.Context, userID uint) (*models.Wallet, error) {
 	var wallet models.Wallet
-	err := r.db.Preload("User").Where("user_id = ?", userID).First(&wallet).Error
+	err := withContext(r.db, withQueryClass(ctx, balanceQuery)).Preload("User").Where("user_id = ? AND sandbox = ?", userID, false).
+		Order("is_default DESC").First(&wallet).Error
 	if err != nil {
 		return nil, err
 	}
 	return &wallet, nil
 }
 
-func (r *walletRepository) Update(wallet *models.Wallet) error {
-	return r.db.Save(wallet).Error
+func (r *walletRepository) GetSandboxByUserID(ctx context.Context, userID uint) (*models.Wallet, error) {
+	var wallet models.Wallet
+	err := withContext(r.db, withQueryClass(ctx, balanceQuery)).Preload("User").Where("user_id = ? AND sandbox = ?", userID, true).First(&wallet).Error
+	if err != nil {
+		return nil, err
+	}
+	return &wallet, nil
+}
+
+func (r *walletRepository) Update(ctx context.Context, wallet *models.Wallet) error {
+	return withContext(r.db, ctx).Save(wallet).Error
 }
 
-func (r *walletRepository) UpdateBalance(walletID uint, newBalance decimal.Decimal, version uint) error {
+func (r *walletRepository) UpdateBalance(ctx context.Context, walletID uint, newBalance decimal.Decimal, version uint) error {
 	// Optimistic locking: update only if version matches
-	result := r.db.Model(&models.Wallet{}).
+	result := withContext(r.db, ctx).Model(&models.Wallet{}).
 		Where("id = ? AND version = ?", walletID, version).
 		Updates(map[string]interface{}{
 			"balance": newBalance,
@@ -61,14 +73,152 @@ func (r *walletRepository) UpdateBalance(walletID uint, newBalance decimal.Decim
 	return nil
 }
 
-func (r *walletRepository) List(offset, limit int) ([]models.Wallet, error) {
+func (r *walletRepository) List(ctx context.Context, offset, limit int) ([]models.Wallet, error) {
 	var wallets []models.Wallet
-	err := r.db.Preload("User").Offset(offset).Limit(limit).Find(&wallets).Error
+	err := withContext(r.db, ctx).Preload("User").Offset(offset).Limit(limit).Find(&wallets).Error
 	return wallets, err
 }
 
-func (r *walletRepository) GetAllForReconciliation() ([]models.Wallet, error) {
+func (r *walletRepository) ListWithFilter(ctx context.Context, filter WalletFilter, offset, limit int) ([]models.Wallet, int64, error) {
+	query := withContext(r.db, ctx).Model(&models.Wallet{})
+
+	if filter.Status != "" {
+		query = query.Where("wallets.status = ?", filter.Status)
+	}
+	if filter.Currency != "" {
+		query = query.Where("wallets.currency = ?", filter.Currency)
+	}
+	if filter.MinBalance != nil {
+		query = query.Where("wallets.balance >= ?", *filter.MinBalance)
+	}
+	if filter.MaxBalance != nil {
+		query = query.Where("wallets.balance <= ?", *filter.MaxBalance)
+	}
+	if filter.UserEmail != "" {
+		query = query.Joins("JOIN users ON users.id = wallets.user_id").
+			Where("users.email LIKE ?", "%"+filter.UserEmail+"%")
+	}
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
 	var wallets []models.Wallet
-	err := r.db.Preload("User").Find(&wallets).Error
+	err := query.Preload("User").
+		Order("wallets.id ASC").
+		Offset(offset).Limit(limit).
+		Find(&wallets).Error
+	return wallets, total, err
+}
+
+func (r *walletRepository) GetAllForReconciliation(ctx context.Context) ([]models.Wallet, error) {
+	var wallets []models.Wallet
+	err := withContext(r.db, withQueryClass(ctx, reconciliationQuery)).Preload("User").Find(&wallets).Error
+	return wallets, err
+}
+
+func (r *walletRepository) ListForReconciliation(ctx context.Context, scope models.ReconciliationScope, afterID uint, limit int) ([]models.Wallet, error) {
+	var wallets []models.Wallet
+	err := r.reconciliationScope(ctx, scope).
+		Where("id > ?", afterID).
+		Order("id ASC").
+		Limit(limit).
+		Find(&wallets).Error
 	return wallets, err
 }
+
+func (r *walletRepository) CountForReconciliation(ctx context.Context, scope models.ReconciliationScope) (int64, error) {
+	var count int64
+	err := r.reconciliationScope(ctx, scope).Count(&count).Error
+	return count, err
+}
+
+// reconciliationScope selects the wallets of a reconciliation scope
+func (r *walletRepository) reconciliationScope(ctx context.Context, scope models.ReconciliationScope) *gorm.DB {
+	query := withContext(r.db, withQueryClass(ctx, reconciliationQuery)).Model(&models.Wallet{})
+	if scope.Currency != "" {
+		query = query.Where("currency = ?", scope.Currency)
+	}
+	if scope.Status != "" {
+		query = query.Where("status = ?", scope.Status)
+	}
+	if scope.ActiveFrom != nil {
+		query = query.Where("updated_at >= ?", *scope.ActiveFrom)
+	}
+	if scope.ActiveTo != nil {
+		query = query.Where("updated_at < ?", *scope.ActiveTo)
+	}
+	if len(scope.WalletIDs) > 0 {
+		query = query.Where("id IN ?", scope.WalletIDs)
+	}
+	return query
+}
+
+func (r *walletRepository) SetTier(ctx context.Context, walletID uint, tierID *uint) error {
+	result := withContext(r.db, ctx).Model(&models.Wallet{}).Where("id = ?", walletID).Update("tier_id", tierID)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
+
+func (r *walletRepository) ListByUserID(ctx context.Context, userID uint, sandbox bool, nickname string) ([]models.Wallet, error) {
+	query := withContext(r.db, ctx).Where("user_id = ? AND sandbox = ?", userID, sandbox)
+	if nickname != "" {
+		query = query.Where("LOWER(nickname) = LOWER(?)", nickname)
+	}
+	var wallets []models.Wallet
+	err := query.Order("id ASC").Find(&wallets).Error
+	return wallets, err
+}
+
+func (r *walletRepository) SetNickname(ctx context.Context, walletID uint, nickname string) error {
+	result := withContext(r.db, ctx).Model(&models.Wallet{}).Where("id = ?", walletID).Update("nickname", nickname)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
+
+func (r *walletRepository) SetDefault(ctx context.Context, userID, walletID uint) error {
+	return withContext(r.db, ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(&models.Wallet{}).Where("user_id = ? AND sandbox = ? AND id <> ?", userID, false, walletID).
+			Update("is_default", false).Error; err != nil {
+			return err
+		}
+		result := tx.Model(&models.Wallet{}).Where("id = ? AND user_id = ? AND sandbox = ?", walletID, userID, false).Update("is_default", true)
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return gorm.ErrRecordNotFound
+		}
+		return nil
+	})
+}
+
+// AdjustBalance reads the balance of the wallet and writes it back with amount added, guarded by the
+// version it read
+func (r *walletRepository) AdjustBalance(ctx context.Context, walletID uint, amount decimal.Decimal) (decimal.Decimal, bool, error) {
+	var wallet models.Wallet
+	if err := withContext(r.db, ctx).First(&wallet, walletID).Error; err != nil {
+		return decimal.Zero, false, err
+	}
+
+	result := withContext(r.db, ctx).Model(&models.Wallet{}).Where("id = ? AND version = ?", wallet.ID, wallet.Version).
+		Updates(map[string]interface{}{
+			"balance": wallet.Balance.Add(amount),
+			"version": gorm.Expr("version + 1"),
+		})
+	if result.Error != nil {
+		return decimal.Zero, false, result.Error
+	}
+	return wallet.Balance, result.RowsAffected > 0, nil
+}
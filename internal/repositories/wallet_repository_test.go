@@ -0,0 +1,67 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/limistah/wallet-service/internal/models"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+func TestWalletRepository_DefaultWallet(t *testing.T) {
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{Logger: logger.Default.LogMode(logger.Silent)})
+	if err != nil {
+		t.Fatalf("Failed to open database: %v", err)
+	}
+	if err := db.AutoMigrate(&models.User{}, &models.Wallet{}); err != nil {
+		t.Fatalf("Failed to create tables: %v", err)
+	}
+	repo := NewWalletRepository(db)
+	ctx := context.Background()
+
+	user := &models.User{Name: "Ada", Email: "ada@example.com", Password: "secret"}
+	if err := db.Create(user).Error; err != nil {
+		t.Fatalf("Failed to create user: %v", err)
+	}
+	wallets := []*models.Wallet{
+		{UserID: user.ID, Currency: "USD", Status: models.WalletStatusActive},
+		{UserID: user.ID, Currency: "USD", Status: models.WalletStatusActive, Nickname: "Rent"},
+		{UserID: user.ID, Currency: "USD", Status: models.WalletStatusActive, Sandbox: true},
+	}
+	for _, wallet := range wallets {
+		if err := repo.Create(ctx, wallet); err != nil {
+			t.Fatalf("Create() error = %v", err)
+		}
+	}
+
+	if wallet, err := repo.GetByUserID(ctx, user.ID); err != nil || wallet.ID != wallets[0].ID {
+		t.Fatalf("GetByUserID() without a default = %+v, %v, want the first wallet", wallet, err)
+	}
+	if err := repo.SetDefault(ctx, user.ID, wallets[1].ID); err != nil {
+		t.Fatalf("SetDefault() error = %v", err)
+	}
+	if wallet, err := repo.GetByUserID(ctx, user.ID); err != nil || wallet.ID != wallets[1].ID {
+		t.Fatalf("GetByUserID() = %+v, %v, want the default wallet", wallet, err)
+	}
+	if err := repo.SetDefault(ctx, user.ID, wallets[2].ID); !errors.Is(err, gorm.ErrRecordNotFound) {
+		t.Errorf("SetDefault() of a sandbox wallet error = %v, want gorm.ErrRecordNotFound", err)
+	}
+	if err := repo.SetDefault(ctx, user.ID+1, wallets[0].ID); !errors.Is(err, gorm.ErrRecordNotFound) {
+		t.Errorf("SetDefault() of another user's wallet error = %v, want gorm.ErrRecordNotFound", err)
+	}
+
+	if err := repo.SetDefault(ctx, user.ID, wallets[0].ID); err != nil {
+		t.Fatalf("SetDefault() error = %v", err)
+	}
+	live, err := repo.ListByUserID(ctx, user.ID, false, "")
+	if err != nil || len(live) != 2 || !live[0].IsDefault || live[1].IsDefault {
+		t.Errorf("ListByUserID() = %+v, %v, want only the first wallet default", live, err)
+	}
+	labelled, err := repo.ListByUserID(ctx, user.ID, false, "RENT")
+	if err != nil || len(labelled) != 1 || labelled[0].ID != wallets[1].ID {
+		t.Errorf("ListByUserID() by nickname = %+v, %v, want the wallet labelled Rent", labelled, err)
+	}
+}
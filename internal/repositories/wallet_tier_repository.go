@@ -0,0 +1,57 @@
+package repositories
+
+import (
+	"context"
+
+	"github.com/limistah/wallet-service/internal/models"
+	"gorm.io/gorm"
+)
+
+type walletTierRepository struct {
+	db *gorm.DB
+}
+
+// NewWalletTierRepository creates a new wallet tier repository
+func NewWalletTierRepository(db *gorm.DB) WalletTierRepository {
+	return &walletTierRepository{db: db}
+}
+
+func (r *walletTierRepository) Create(ctx context.Context, tier *models.WalletTier) error {
+	return withContext(r.db, ctx).Create(tier).Error
+}
+
+func (r *walletTierRepository) GetByID(ctx context.Context, id uint) (*models.WalletTier, error) {
+	var tier models.WalletTier
+	err := withContext(r.db, ctx).First(&tier, id).Error
+	if err != nil {
+		return nil, err
+	}
+	return &tier, nil
+}
+
+func (r *walletTierRepository) GetDefault(ctx context.Context) (*models.WalletTier, error) {
+	var tier models.WalletTier
+	err := withContext(r.db, ctx).Where("is_default = ?", true).Order("id ASC").First(&tier).Error
+	if err != nil {
+		return nil, err
+	}
+	return &tier, nil
+}
+
+func (r *walletTierRepository) List(ctx context.Context) ([]models.WalletTier, error) {
+	var tiers []models.WalletTier
+	err := withContext(r.db, ctx).Order("id ASC").Find(&tiers).Error
+	return tiers, err
+}
+
+// Update saves the tier; making it the default clears the flag on every other tier
+func (r *walletTierRepository) Update(ctx context.Context, tier *models.WalletTier) error {
+	return withContext(r.db, ctx).Transaction(func(tx *gorm.DB) error {
+		if tier.IsDefault {
+			if err := tx.Model(&models.WalletTier{}).Where("id <> ?", tier.ID).Update("is_default", false).Error; err != nil {
+				return err
+			}
+		}
+		return tx.Save(tier).Error
+	})
+}
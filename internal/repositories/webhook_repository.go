@@ -0,0 +1,115 @@
+package repositories
+
+import (
+	"context"
+	"time"
+
+	"github.com/limistah/wallet-service/internal/models"
+	"gorm.io/gorm"
+)
+
+type webhookRepository struct {
+	db *gorm.DB
+}
+
+// NewWebhookRepository creates a new webhook repository
+func NewWebhookRepository(db *gorm.DB) WebhookRepository {
+	return &webhookRepository{db: db}
+}
+
+func (r *webhookRepository) CreateSubscription(ctx context.Context, subscription *models.WebhookSubscription) error {
+	return withContext(r.db, ctx).Create(subscription).Error
+}
+
+func (r *webhookRepository) GetSubscription(ctx context.Context, id uint) (*models.WebhookSubscription, error) {
+	var subscription models.WebhookSubscription
+	err := withContext(r.db, ctx).First(&subscription, id).Error
+	if err != nil {
+		return nil, err
+	}
+	return &subscription, nil
+}
+
+func (r *webhookRepository) ListSubscriptionsByUser(ctx context.Context, userID uint) ([]models.WebhookSubscription, error) {
+	var subscriptions []models.WebhookSubscription
+	err := withContext(r.db, ctx).Where("user_id = ?", userID).Order("created_at DESC, id DESC").Find(&subscriptions).Error
+	return subscriptions, err
+}
+
+func (r *webhookRepository) ListActiveSubscriptionsByUser(ctx context.Context, userID uint) ([]models.WebhookSubscription, error) {
+	var subscriptions []models.WebhookSubscription
+	err := withContext(r.db, ctx).Where("user_id = ? AND active = ?", userID, true).Find(&subscriptions).Error
+	return subscriptions, err
+}
+
+func (r *webhookRepository) DisableSubscription(ctx context.Context, id uint) error {
+	return withContext(r.db, ctx).Model(&models.WebhookSubscription{}).Where("id = ?", id).Update("active", false).Error
+}
+
+func (r *webhookRepository) CreateDelivery(ctx context.Context, delivery *models.WebhookDelivery) error {
+	return withContext(r.db, ctx).Omit("Subscription").Create(delivery).Error
+}
+
+func (r *webhookRepository) GetDelivery(ctx context.Context, id uint) (*models.WebhookDelivery, error) {
+	var delivery models.WebhookDelivery
+	err := withContext(r.db, ctx).Preload("Subscription").First(&delivery, id).Error
+	if err != nil {
+		return nil, err
+	}
+	return &delivery, nil
+}
+
+func (r *webhookRepository) ListDeliveries(ctx context.Context, subscriptionID uint, offset, limit int) ([]models.WebhookDelivery, error) {
+	var deliveries []models.WebhookDelivery
+	err := withContext(r.db, ctx).Where("subscription_id = ?", subscriptionID).
+		Order("created_at DESC, id DESC").Offset(offset).Limit(limit).
+		Find(&deliveries).Error
+	return deliveries, err
+}
+
+func (r *webhookRepository) SearchDeliveries(ctx context.Context, filter WebhookDeliveryFilter, offset, limit int) ([]models.WebhookDelivery, error) {
+	var deliveries []models.WebhookDelivery
+	query := withContext(r.db, ctx).Model(&models.WebhookDelivery{})
+	if filter.Status != "" {
+		query = query.Where("status = ?", filter.Status)
+	}
+	if filter.EventType != "" {
+		query = query.Where("event_type = ?", filter.EventType)
+	}
+	if filter.SubscriptionID != 0 {
+		query = query.Where("subscription_id = ?", filter.SubscriptionID)
+	}
+	err := query.Order("updated_at DESC, id DESC").Offset(offset).Limit(limit).Find(&deliveries).Error
+	return deliveries, err
+}
+
+func (r *webhookRepository) UpdateDelivery(ctx context.Context, delivery *models.WebhookDelivery) error {
+	return withContext(r.db, ctx).Model(delivery).
+		Select("status", "attempts", "next_attempt_at", "last_status_code", "last_duration_ms", "last_error", "delivered_at", "dead_at").
+		Updates(delivery).Error
+}
+
+func (r *webhookRepository) Requeue(ctx context.Context, id uint, at time.Time) (bool, error) {
+	result := withContext(r.db, ctx).Model(&models.WebhookDelivery{}).
+		Where("id = ? AND status = ?", id, models.WebhookDeliveryDead).
+		Updates(map[string]interface{}{
+			"status":          models.WebhookDeliveryPending,
+			"attempts":        0,
+			"next_attempt_at": at,
+			"dead_at":         nil,
+		})
+	if result.Error != nil {
+		return false, result.Error
+	}
+	return result.RowsAffected > 0, nil
+}
+
+func (r *webhookRepository) CreateAttempt(ctx context.Context, attempt *models.WebhookDeliveryAttempt) error {
+	return withContext(r.db, ctx).Create(attempt).Error
+}
+
+func (r *webhookRepository) ListAttempts(ctx context.Context, deliveryID uint) ([]models.WebhookDeliveryAttempt, error) {
+	var attempts []models.WebhookDeliveryAttempt
+	err := withContext(r.db, ctx).Where("delivery_id = ?", deliveryID).Order("created_at ASC, id ASC").Find(&attempts).Error
+	return attempts, err
+}
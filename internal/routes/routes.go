@@ -3,36 +3,348 @@ package routes
 import (
 	"github.com/gin-gonic/gin"
 	"github.com/limistah/wallet-service/internal/auth"
+	"github.com/limistah/wallet-service/internal/breaker"
 	"github.com/limistah/wallet-service/internal/handlers"
 	"github.com/limistah/wallet-service/internal/middleware"
+	"github.com/limistah/wallet-service/internal/models"
+	"github.com/limistah/wallet-service/internal/oauth"
+	"github.com/limistah/wallet-service/internal/payments"
 	"github.com/limistah/wallet-service/internal/usecases"
 )
 
-func SetupRoutes(router *gin.Engine, useCases *usecases.UseCases, jwtService *auth.JWTService) {
+// SetupRoutes registers every endpoint. v1Deprecation is announced on the v1 endpoints that have a v2
+// successor
+func SetupRoutes(router *gin.Engine, useCases *usecases.UseCases, jwtService *auth.JWTService, webhookVerifiers *payments.Registry, breakers *breaker.Registry, oauthProviders *oauth.Registry, v1Deprecation middleware.Deprecation) {
+	deprecatedBy := func(successor string) gin.HandlerFunc {
+		deprecation := v1Deprecation
+		deprecation.Successor = successor
+		return middleware.Deprecated(deprecation)
+	}
+
+	requireAuth := middleware.AuthMiddleware(jwtService, useCases.Revocation)
+
 	// Health check endpoint
 	router.GET("/health", handlers.HealthCheck)
 
-	authHandler := handlers.NewAuthHandler(useCases.User, jwtService)
+	authHandler := handlers.NewAuthHandler(useCases.User, useCases.Login, useCases.Revocation, useCases.Session, jwtService)
+	router.GET("/.well-known/jwks.json", authHandler.JWKS) // Public keys access tokens are verified with
 	authGroup := router.Group("/api/v1")
 	{
 		authGroup.POST("/auth/register", authHandler.Register)
 		authGroup.POST("/auth/login", authHandler.Login)
-		authGroup.POST("/auth/refresh", middleware.AuthMiddleware(jwtService), authHandler.RefreshToken)
-		authGroup.POST("/auth/change-password", middleware.AuthMiddleware(jwtService), authHandler.ChangePassword)
+		authGroup.POST("/auth/refresh", requireAuth, authHandler.RefreshToken)
+		authGroup.POST("/auth/change-password", requireAuth, authHandler.ChangePassword)
+		authGroup.POST("/auth/logout", requireAuth, authHandler.Logout)
+		authGroup.POST("/auth/logout/all", requireAuth, authHandler.LogoutAll)
+		authGroup.GET("/auth/sessions/history", requireAuth, authHandler.LoginHistory)
+
+		sessionHandler := handlers.NewSessionHandler(useCases.Session)
+		authGroup.GET("/auth/sessions", requireAuth, sessionHandler.ListSessions)         // List the devices signed in to the account
+		authGroup.DELETE("/auth/sessions/:id", requireAuth, sessionHandler.RevokeSession) // Sign a device out
+
+		oauthHandler := handlers.NewOAuthHandler(useCases.OAuth, useCases.Login, useCases.Session, oauthProviders, jwtService)
+		authGroup.GET("/auth/oauth/:provider/start", oauthHandler.StartOAuth)       // Redirect to an identity provider's sign in page
+		authGroup.GET("/auth/oauth/:provider/callback", oauthHandler.OAuthCallback) // Sign in with the code the identity provider sent back
+	}
+
+	webhookHandler := handlers.NewWebhookHandler(useCases.Deposit, useCases.Wallet, useCases.Suspense, webhookVerifiers)
+	webhooks := router.Group("/api/v1/webhooks")
+	{
+		webhooks.POST("/providers/:provider", webhookHandler.ProviderWebhook) // Confirm a pending deposit from a signed provider webhook
+		webhooks.POST("/payouts/:provider", webhookHandler.PayoutWebhook)     // Settle a pending bank payout from a signed provider webhook
 	}
 
+	paymentLinkHandler := handlers.NewPaymentLinkHandler(useCases.PaymentLink)
+	router.GET("/api/v1/pay/:token", paymentLinkHandler.ResolveLink) // Resolve a payment link without authentication
+
+	accountStatementHandler := handlers.NewAccountStatementHandler(useCases.AccountStatement)
+	router.GET("/api/v1/downloads", accountStatementHandler.Download) // Download a file through a signed link without authentication
+
 	v1 := router.Group("/api/v1")
-	v1.Use(middleware.AuthMiddleware(jwtService))
+	v1.Use(requireAuth)
 	{
-		walletHandler := handlers.NewWalletHandler(useCases.Wallet)
+		walletHandler := handlers.NewWalletHandler(useCases.Wallet, useCases.PIN, useCases.StepUp)
+		paymentHandler := handlers.NewPaymentHandler(useCases.Wallet, useCases.Deposit)
+		moneyRequestHandler := handlers.NewMoneyRequestHandler(useCases.MoneyRequest)
+		qrPaymentHandler := handlers.NewQRPaymentHandler(useCases.QRPayment)
+		standingOrderHandler := handlers.NewStandingOrderHandler(useCases.StandingOrder)
+		budgetHandler := handlers.NewBudgetHandler(useCases.Budget)
+		deprecatedWallet := deprecatedBy("/api/v2/wallets/me")
+		deprecatedBalance := deprecatedBy("/api/v2/wallets/me/balance")
 		wallets := v1.Group("/wallets")
 		{
-			wallets.GET("/me", walletHandler.GetWallet)                          // Get authenticated user's wallet
-			wallets.GET("/me/balance", walletHandler.GetWalletBalance)           // Get authenticated user's wallet balance
-			wallets.POST("/me/fund", walletHandler.FundWallet)                   // Fund authenticated user's wallet
-			wallets.POST("/me/withdraw", walletHandler.WithdrawFunds)            // Withdraw from authenticated user's wallet
-			wallets.POST("/me/transfer", walletHandler.TransferFunds)            // Transfer from authenticated user's wallet
-			wallets.GET("/me/transactions", walletHandler.GetTransactionHistory) // Get authenticated user's transaction history
+			wallets.GET("", walletHandler.ListWallets)                                                       // List authenticated user's wallets, filtered by nickname
+			wallets.POST("", walletHandler.CreateWallet)                                                     // Open another wallet in a currency or with a nickname
+			wallets.PUT("/:id/nickname", walletHandler.SetWalletNickname)                                    // Name a wallet of the authenticated user
+			wallets.PUT("/:id/default", walletHandler.SetDefaultWallet)                                      // Make a wallet the one payments to the user and fundings land in
+			wallets.GET("/me", deprecatedWallet, walletHandler.GetWallet)                                    // Get authenticated user's wallet
+			wallets.GET("/me/balance", deprecatedBalance, walletHandler.GetWalletBalance)                    // Get authenticated user's wallet balance
+			wallets.GET("/me/limits", walletHandler.GetWalletLimits)                                         // Tier limits and allowance left
+			wallets.POST("/me/fund", walletHandler.FundWallet)                                               // Fund authenticated user's wallet
+			wallets.POST("/me/fund/card", paymentHandler.FundWithCard)                                       // Start a card funding for authenticated user's wallet
+			wallets.POST("/me/fund/checkout", paymentHandler.FundWithCheckout)                               // Start a hosted checkout funding for authenticated user's wallet
+			wallets.POST("/me/withdraw", walletHandler.WithdrawFunds)                                        // Withdraw from authenticated user's wallet
+			wallets.POST("/me/transfer", walletHandler.TransferFunds)                                        // Transfer from authenticated user's wallet
+			wallets.POST("/me/transfer/confirm", walletHandler.ConfirmTransfer)                              // Make a large transfer held for a one-time code
+			wallets.POST("/me/transfer/quote", walletHandler.QuoteTransfer)                                  // Preview the fee and exchange rate of a transfer
+			wallets.POST("/me/splits", walletHandler.SplitPayment)                                           // Pay several wallets from one debit
+			wallets.GET("/me/transactions", walletHandler.GetTransactionHistory)                             // Get authenticated user's transaction history
+			wallets.GET("/me/transactions/by-reference/:reference", walletHandler.GetTransactionByReference) // Check the outcome of an operation by its reference
+			wallets.GET("/me/transactions/:id", walletHandler.GetTransaction)                                // Get a transaction with its related leg and status history
+			wallets.PUT("/me/transactions/:id/category", walletHandler.CategorizeTransaction)                // Tag a transaction with a spending category
+			wallets.POST("/me/requests", moneyRequestHandler.CreateRequest)                                  // Ask another user for funds
+			wallets.GET("/me/requests", moneyRequestHandler.ListRequests)                                    // List incoming and outgoing money requests
+			wallets.POST("/me/requests/:id/accept", moneyRequestHandler.AcceptRequest)                       // Pay an incoming money request
+			wallets.POST("/me/requests/:id/decline", moneyRequestHandler.DeclineRequest)                     // Decline an incoming money request
+			wallets.POST("/me/requests/:id/cancel", moneyRequestHandler.CancelRequest)                       // Cancel an outgoing money request
+			wallets.GET("/me/qr", qrPaymentHandler.GenerateCode)                                             // Generate a QR code to get paid in person
+			wallets.POST("/me/qr/pay", qrPaymentHandler.PayCode)                                             // Scan and pay another user's QR code
+			wallets.POST("/me/standing-orders", standingOrderHandler.CreateStandingOrder)                    // Schedule a recurring transfer
+			wallets.GET("/me/standing-orders", standingOrderHandler.ListStandingOrders)                      // List standing orders
+			wallets.DELETE("/me/standing-orders/:id", standingOrderHandler.CancelStandingOrder)              // Cancel a standing order
+			wallets.GET("/me/standing-orders/:id/runs", standingOrderHandler.ListRuns)                       // List occurrences of a standing order and their retries
+			wallets.POST("/me/budgets", budgetHandler.CreateBudget)                                          // Set a monthly budget for a spending category
+			wallets.GET("/me/budgets", budgetHandler.ListBudgets)                                            // List budgets with this month's spending
+			wallets.PUT("/me/budgets/:id", budgetHandler.UpdateBudget)                                       // Change the amount of a budget
+			wallets.DELETE("/me/budgets/:id", budgetHandler.DeleteBudget)                                    // Remove a budget
+		}
+
+		v1.GET("/handles/:handle", walletHandler.ResolveHandle) // Find the user going by a handle and the wallet payments to them land in
+
+		paymentLinks := v1.Group("/payment-links")
+		{
+			paymentLinks.POST("", paymentLinkHandler.CreateLink)        // Create a shareable payment link
+			paymentLinks.GET("", paymentLinkHandler.ListLinks)          // List payment links with analytics
+			paymentLinks.DELETE("/:id", paymentLinkHandler.DisableLink) // Disable a payment link
+		}
+		v1.POST("/pay/:token", paymentLinkHandler.PayLink) // Pay a payment link from authenticated user's wallet
+
+		statements := v1.Group("/statements")
+		{
+			statements.POST("", accountStatementHandler.RequestStatement) // Ask for a statement rendered in the background
+			statements.GET("", accountStatementHandler.ListStatements)    // List requested statements
+			statements.GET("/:id", accountStatementHandler.GetStatement)  // Get a statement's status and download link
+		}
+
+		businessHandler := handlers.NewBusinessHandler(useCases.Business, useCases.Wallet)
+		businesses := v1.Group("/businesses")
+		{
+			businesses.POST("", businessHandler.CreateBusinessAccount)                             // Open a business wallet shared with members
+			businesses.GET("", businessHandler.ListBusinessAccounts)                               // List business accounts the user is a member of
+			businesses.GET("/:id", businessHandler.GetBusinessAccount)                             // Get a business account with its members
+			businesses.GET("/:id/transactions", businessHandler.GetTransactionHistory)             // Get the business wallet's transaction history
+			businesses.POST("/:id/members", businessHandler.AddMember)                             // Give a user a role on the account (owner only)
+			businesses.PUT("/:id/members/:user_id", businessHandler.UpdateMember)                  // Change a member's role (owner only)
+			businesses.DELETE("/:id/members/:user_id", businessHandler.RemoveMember)               // Remove a member (owner only)
+			businesses.POST("/:id/payments", businessHandler.RequestPayment)                       // Pay, or request a payment awaiting approval
+			businesses.GET("/:id/approvals", businessHandler.ListApprovals)                        // List payments and their decisions
+			businesses.POST("/:id/approvals/:approval_id/approve", businessHandler.ApprovePayment) // Approve and make a pending payment
+			businesses.POST("/:id/approvals/:approval_id/reject", businessHandler.RejectPayment)   // Decline a pending payment
+		}
+
+		escrowHandler := handlers.NewEscrowHandler(useCases.Escrow)
+		escrows := v1.Group("/escrows")
+		{
+			escrows.POST("", escrowHandler.CreateEscrow)              // Hold funds in escrow for another user
+			escrows.GET("", escrowHandler.ListEscrows)                // List escrows the user pays or is paid by
+			escrows.GET("/:id", escrowHandler.GetEscrow)              // Get an escrow
+			escrows.POST("/:id/release", escrowHandler.ReleaseEscrow) // Pay the held funds to the payee
+			escrows.POST("/:id/cancel", escrowHandler.CancelEscrow)   // Return the held funds to the payer
+		}
+
+		subscriptionHandler := handlers.NewSubscriptionHandler(useCases.Subscription)
+		plans := v1.Group("/subscription-plans")
+		{
+			plans.POST("", subscriptionHandler.CreatePlan)                             // Define a plan billed to subscribers every interval
+			plans.GET("", subscriptionHandler.ListPlans)                               // List plans that take subscribers
+			plans.GET("/mine", subscriptionHandler.ListMerchantPlans)                  // List the merchant's own plans
+			plans.GET("/:id", subscriptionHandler.GetPlan)                             // Get a plan
+			plans.DELETE("/:id", subscriptionHandler.ArchivePlan)                      // Stop a plan taking new subscribers (merchant only)
+			plans.GET("/:id/subscriptions", subscriptionHandler.ListPlanSubscriptions) // List a plan's subscriptions (merchant only)
+		}
+		subscriptions := v1.Group("/subscriptions")
+		{
+			subscriptions.POST("", subscriptionHandler.Subscribe)                     // Pay the first period of a plan and subscribe
+			subscriptions.GET("", subscriptionHandler.ListSubscriptions)              // List the user's subscriptions
+			subscriptions.GET("/:id", subscriptionHandler.GetSubscription)            // Get a subscription as subscriber or merchant
+			subscriptions.GET("/:id/charges", subscriptionHandler.ListCharges)        // List the attempts to charge a subscription
+			subscriptions.POST("/:id/cancel", subscriptionHandler.CancelSubscription) // Stop billing a subscription
+			subscriptions.POST("/:id/resume", subscriptionHandler.ResumeSubscription) // Charge and reactivate a suspended subscription
+		}
+
+		webhookSubscriptionHandler := handlers.NewWebhookSubscriptionHandler(useCases.Webhook)
+		webhookSubscriptions := v1.Group("/webhook-subscriptions")
+		{
+			webhookSubscriptions.POST("", webhookSubscriptionHandler.CreateSubscription)                     // Receive the user's wallet events at an endpoint
+			webhookSubscriptions.GET("", webhookSubscriptionHandler.ListSubscriptions)                       // List the user's webhook endpoints
+			webhookSubscriptions.DELETE("/:id", webhookSubscriptionHandler.DisableSubscription)              // Stop delivering events to an endpoint
+			webhookSubscriptions.GET("/:id/deliveries", webhookSubscriptionHandler.ListDeliveries)           // List events sent to an endpoint
+			webhookSubscriptions.GET("/:id/deliveries/:delivery_id", webhookSubscriptionHandler.GetDelivery) // Get a delivery with its attempt history
+			webhookSubscriptions.POST("/:id/replay", webhookSubscriptionHandler.ReplayEvents)                // Deliver stored events again after an outage
+		}
+
+		sandboxHandler := handlers.NewSandboxHandler(useCases.Wallet)
+		v1.POST("/sandbox/mint", sandboxHandler.MintFunds) // Mint test money into the authenticated user's sandbox wallet
+
+		notificationHandler := handlers.NewNotificationHandler(useCases.Notification)
+		pinHandler := handlers.NewPINHandler(useCases.PIN)
+		userHandler := handlers.NewUserHandler(useCases.User)
+		users := v1.Group("/users")
+		{
+			users.GET("/me", userHandler.GetProfile)                                         // Get authenticated user's profile
+			users.PATCH("/me", userHandler.UpdateProfile)                                    // Change name, phone number, date of birth or address
+			users.DELETE("/me", userHandler.DeleteAccount)                                   // Sign out and schedule the account for anonymization
+			users.PUT("/me/handle", userHandler.SetHandle)                                   // Choose the @handle other users pay the user by
+			users.PUT("/me/avatar", userHandler.UploadAvatar)                                // Upload a profile picture
+			users.GET("/me/avatar", userHandler.GetAvatar)                                   // Download the profile picture
+			users.GET("/me/notification-preferences", notificationHandler.GetPreferences)    // Get authenticated user's notification preferences
+			users.PUT("/me/notification-preferences", notificationHandler.UpdatePreferences) // Update authenticated user's notification preferences
+			users.POST("/me/phone", notificationHandler.RequestPhoneVerification)            // Send a verification code to a phone number
+			users.POST("/me/phone/verify", notificationHandler.VerifyPhone)                  // Confirm the phone number with the received code
+			users.POST("/me/devices", notificationHandler.RegisterDevice)                    // Register a device for push notifications
+			users.GET("/me/devices", notificationHandler.ListDevices)                        // List registered push devices
+			users.DELETE("/me/devices/:id", notificationHandler.RemoveDevice)                // Remove a registered push device
+			users.POST("/me/pin", pinHandler.SetPIN)                                         // Set the transaction PIN confirming debits
+			users.PUT("/me/pin", pinHandler.ChangePIN)                                       // Change the transaction PIN
+			users.POST("/me/pin/reset", pinHandler.ResetPIN)                                 // Replace a forgotten or locked transaction PIN using the password
 		}
 	}
+
+	admin := router.Group("/api/v1/admin")
+	admin.Use(
+		middleware.IPAllowlist(useCases.IPAllowlist),
+		requireAuth,
+		middleware.RequireRole(models.UserRoleAdmin, models.UserRoleSupport),
+		middleware.AuditTrail(useCases.Audit),
+	)
+	{
+		adminHandler := handlers.NewAdminHandler(useCases.Wallet, useCases.Reconciliation, useCases.Audit)
+		admin.GET("/wallets", adminHandler.ListWallets)   // List the tenant's wallets with filters
+		admin.GET("/wallets/:id", adminHandler.GetWallet) // Get a wallet of the tenant with reconciliation history
+
+		accountLockHandler := handlers.NewAccountLockHandler(useCases.Login)
+		admin.POST("/users/:id/unlock", middleware.RequireRole(models.UserRoleAdmin), accountLockHandler.UnlockUser) // Unlock an account locked after failed logins
+
+		impersonationHandler := handlers.NewImpersonationHandler(useCases.Impersonation, jwtService)
+		admin.POST("/users/:id/impersonate", impersonationHandler.ImpersonateUser) // Mint a short-lived token acting as a user; write access is for admins only
+
+		// The other routes reach rows that belong to no tenant, such as transactions, the ledger and the
+		// settings of the deployment, so only the default tenant, which operates the deployment, uses them
+		deployment := admin.Group("", middleware.RequireDefaultTenant())
+		deployment.GET("/wallets/:id/reconciliation", adminHandler.GetWalletReconciliationReports)      // Get any wallet's reconciliation history
+		deployment.GET("/wallets/:id/transactions", adminHandler.GetWalletTransactions)                 // Get any wallet's ledger with both double-entry legs
+		deployment.GET("/transactions/:id", adminHandler.GetTransaction)                                // Get any transaction with its related leg
+		deployment.GET("/transactions/by-reference/:reference", adminHandler.GetTransactionByReference) // Get both legs of an operation by its reference
+		deployment.GET("/transaction-types", adminHandler.ListTransactionTypes)                         // List the transaction types transactions can be recorded with
+		deployment.GET("/reconciliation/reports/export", adminHandler.ExportReconciliationReports)      // Stream reconciliation reports as CSV for finance
+		deployment.GET("/reconciliation/summaries", adminHandler.ListReconciliationSummaries)           // List daily reconciliation summaries
+		deployment.GET("/audit-logs", middleware.RequireRole(models.UserRoleAdmin), adminHandler.ListAuditLogs)
+
+		// Runs reconcile every wallet in background batches, so starting one returns straight away
+		deployment.POST("/reconciliation/runs", middleware.RequireRole(models.UserRoleAdmin), adminHandler.StartReconciliationRun) // Start a reconciliation of every wallet
+		deployment.GET("/reconciliation/runs/:id", adminHandler.GetReconciliationRun)                                              // Get the progress, counts and failures of a run
+
+		// Reports with an issue are worked like a ticket until an admin resolves them
+		deployment.POST("/reconciliation/reports/:id/acknowledge", adminHandler.AcknowledgeReconciliationReport)
+		deployment.POST("/reconciliation/reports/:id/assign", middleware.RequireRole(models.UserRoleAdmin), adminHandler.AssignReconciliationReport)
+		deployment.POST("/reconciliation/reports/:id/notes", adminHandler.AddReconciliationReportNote)
+		deployment.POST("/reconciliation/reports/:id/resolve", middleware.RequireRole(models.UserRoleAdmin), adminHandler.ResolveReconciliationReport)
+
+		walletTierHandler := handlers.NewWalletTierHandler(useCases.WalletTier)
+		deployment.GET("/tiers", walletTierHandler.ListTiers)                                                           // List wallet tiers
+		deployment.POST("/tiers", middleware.RequireRole(models.UserRoleAdmin), walletTierHandler.CreateTier)           // Create a wallet tier
+		deployment.PUT("/tiers/:id", middleware.RequireRole(models.UserRoleAdmin), walletTierHandler.UpdateTier)        // Replace a wallet tier's fees, limits and features
+		deployment.PUT("/wallets/:id/tier", middleware.RequireRole(models.UserRoleAdmin), walletTierHandler.AssignTier) // Move a wallet to another tier
+
+		fraudReviewHandler := handlers.NewFraudReviewHandler(useCases.Wallet)
+		deployment.GET("/fraud-reviews", fraudReviewHandler.ListFraudReviews)                                                              // List debits held by fraud rules
+		deployment.POST("/fraud-reviews/:id/approve", middleware.RequireRole(models.UserRoleAdmin), fraudReviewHandler.ApproveFraudReview) // Release a held debit
+		deployment.POST("/fraud-reviews/:id/reject", middleware.RequireRole(models.UserRoleAdmin), fraudReviewHandler.RejectFraudReview)   // Fail a held debit and refund the wallet
+
+		complianceHandler := handlers.NewComplianceHandler(useCases.Compliance)
+		deployment.GET("/blocklist", complianceHandler.ListBlocklistEntries)                                                      // List blocklisted emails, wallets and bank accounts
+		deployment.POST("/blocklist", middleware.RequireRole(models.UserRoleAdmin), complianceHandler.CreateBlocklistEntry)       // Blocklist an email, wallet or bank account
+		deployment.DELETE("/blocklist/:id", middleware.RequireRole(models.UserRoleAdmin), complianceHandler.DeleteBlocklistEntry) // Remove a blocklist entry
+		deployment.GET("/compliance-logs", complianceHandler.ListComplianceLogs)                                                  // List operations refused by compliance controls
+
+		amlCaseHandler := handlers.NewAMLCaseHandler(useCases.Wallet)
+		deployment.GET("/aml-cases", amlCaseHandler.ListAMLCases)                                                              // List debits parked by AML screening
+		deployment.POST("/aml-cases/:id/approve", middleware.RequireRole(models.UserRoleAdmin), amlCaseHandler.ApproveAMLCase) // Clear a debit under AML review
+		deployment.POST("/aml-cases/:id/reject", middleware.RequireRole(models.UserRoleAdmin), amlCaseHandler.RejectAMLCase)   // Fail a debit under AML review and refund the wallet
+
+		jobHandler := handlers.NewJobHandler(useCases.Job)
+		deployment.GET("/jobs", jobHandler.ListJobs)                                                          // List queued and dead background jobs
+		deployment.POST("/jobs/:id/retry", middleware.RequireRole(models.UserRoleAdmin), jobHandler.RetryJob) // Run a dead job again
+
+		webhookAdminHandler := handlers.NewWebhookSubscriptionHandler(useCases.Webhook)
+		deployment.GET("/webhooks/dead-letters", webhookAdminHandler.ListDeadDeliveries)                                                           // List webhook deliveries that failed every attempt
+		deployment.GET("/webhooks/deliveries", webhookAdminHandler.SearchDeliveries)                                                               // Search webhook deliveries with redacted payloads
+		deployment.GET("/webhooks/deliveries/:id", webhookAdminHandler.AdminGetDelivery)                                                           // Get any webhook delivery with its attempt history
+		deployment.POST("/webhooks/deliveries/:id/redeliver", middleware.RequireRole(models.UserRoleAdmin), webhookAdminHandler.Redeliver)         // Send a dead webhook delivery again
+		deployment.POST("/webhooks/subscriptions/:id/replay", middleware.RequireRole(models.UserRoleAdmin), webhookAdminHandler.AdminReplayEvents) // Deliver a user's stored events again to an endpoint
+
+		ipAllowlistHandler := handlers.NewIPAllowlistHandler(useCases.IPAllowlist)
+		deployment.GET("/ip-allowlist", ipAllowlistHandler.ListIPAllowlist)                                                             // List the ranges allowed to reach admin endpoints
+		deployment.POST("/ip-allowlist", middleware.RequireRole(models.UserRoleAdmin), ipAllowlistHandler.CreateIPAllowlistEntry)       // Allow a CIDR range
+		deployment.DELETE("/ip-allowlist/:id", middleware.RequireRole(models.UserRoleAdmin), ipAllowlistHandler.DeleteIPAllowlistEntry) // Stop allowing a CIDR range
+
+		ledgerHandler := handlers.NewLedgerHandler(useCases.Ledger)
+		deployment.GET("/ledger/accounts", ledgerHandler.ListLedgerAccounts)                                                     // List the chart of ledger accounts with their balances
+		deployment.POST("/ledger/journal-entries", middleware.RequireRole(models.UserRoleAdmin), ledgerHandler.PostJournalEntry) // Post a balanced journal entry between ledger accounts
+		deployment.GET("/ledger/journal-entries/:id", ledgerHandler.GetJournalEntry)                                             // Get a journal entry with its legs
+
+		suspenseHandler := handlers.NewSuspenseHandler(useCases.Suspense)
+		deployment.GET("/suspense", suspenseHandler.ListSuspenseItems)                                                                    // List inbound credits parked in suspense
+		deployment.GET("/suspense/:id", suspenseHandler.GetSuspenseItem)                                                                  // Get a suspense item with its investigation notes
+		deployment.POST("/suspense/:id/investigate", suspenseHandler.InvestigateSuspenseItem)                                             // Record an investigation note
+		deployment.POST("/suspense/:id/reallocate", middleware.RequireRole(models.UserRoleAdmin), suspenseHandler.ReallocateSuspenseItem) // Credit parked funds to the wallet they belong to
+		deployment.POST("/suspense/:id/return", middleware.RequireRole(models.UserRoleAdmin), suspenseHandler.ReturnSuspenseItem)         // Record parked funds as refunded to the payer
+
+		// Manual adjustments need a second admin: the one who requests an adjustment cannot approve it
+		adjustmentHandler := handlers.NewAdjustmentHandler(useCases.Adjustment)
+		deployment.GET("/adjustments", adjustmentHandler.ListAdjustments)                                                              // List manual wallet adjustments
+		deployment.GET("/adjustments/:id", adjustmentHandler.GetAdjustment)                                                            // Get an adjustment with its approval
+		deployment.POST("/adjustments", middleware.RequireRole(models.UserRoleAdmin), adjustmentHandler.RequestAdjustment)             // Request a manual credit or debit of a wallet
+		deployment.POST("/adjustments/:id/approve", middleware.RequireRole(models.UserRoleAdmin), adjustmentHandler.ApproveAdjustment) // Post another admin's adjustment
+		deployment.POST("/adjustments/:id/reject", middleware.RequireRole(models.UserRoleAdmin), adjustmentHandler.RejectAdjustment)   // Decline another admin's adjustment
+
+		// Purposes are rows of a lookup table, so new products add one instead of changing the schema
+		purposeHandler := handlers.NewTransactionPurposeHandler(useCases.Purpose)
+		deployment.GET("/transaction-purposes", purposeHandler.ListTransactionPurposes)                                                         // List transaction purposes with their hooks
+		deployment.GET("/transaction-purposes/:name", purposeHandler.GetTransactionPurpose)                                                     // Get a transaction purpose
+		deployment.POST("/transaction-purposes", middleware.RequireRole(models.UserRoleAdmin), purposeHandler.CreateTransactionPurpose)         // Add a purpose for a new product
+		deployment.PUT("/transaction-purposes/:name", middleware.RequireRole(models.UserRoleAdmin), purposeHandler.UpdateTransactionPurpose)    // Change a purpose's fees, limits and notification hooks
+		deployment.DELETE("/transaction-purposes/:name", middleware.RequireRole(models.UserRoleAdmin), purposeHandler.DeleteTransactionPurpose) // Remove an unused purpose
+
+		settlementHandler := handlers.NewSettlementHandler(useCases.Settlement)
+		deployment.GET("/settlements", settlementHandler.ListSettlementBatches)                                                                // List daily provider settlement batches
+		deployment.GET("/settlements/:id", settlementHandler.GetSettlementBatch)                                                               // Get a settlement batch with its deposits and payouts
+		deployment.POST("/settlements/close", middleware.RequireRole(models.UserRoleAdmin), settlementHandler.CloseSettlementDay)              // Batch a day's unbatched deposits and payouts
+		deployment.POST("/settlements/:id/received", middleware.RequireRole(models.UserRoleAdmin), settlementHandler.RecordSettlementReceived) // Record the net settled by the provider
+
+		statementHandler := handlers.NewStatementHandler(useCases.Statement)
+		deployment.POST("/statements", middleware.RequireRole(models.UserRoleAdmin), statementHandler.ImportStatement) // Import a provider settlement CSV and match its lines
+		deployment.GET("/statements", statementHandler.ListStatements)                                                 // List imported provider statements
+		deployment.GET("/statements/exceptions", statementHandler.ListStatementExceptions)                             // List statement lines waiting for review
+		deployment.GET("/statements/:id", statementHandler.GetStatement)                                               // Get a statement with every line and its match
+		deployment.POST("/statements/lines/:id/resolve", statementHandler.ResolveStatementException)                   // Close a reviewed statement exception
+
+		circuitBreakerHandler := handlers.NewCircuitBreakerHandler(breakers)
+		deployment.GET("/circuit-breakers", circuitBreakerHandler.ListCircuitBreakers) // Report the state of the circuits around external providers
+
+		// Profiles of this instance for admins diagnosing memory and CPU issues; the handlers are not
+		// reachable through http.DefaultServeMux, which the server does not serve
+		diagnostics := deployment.Group("/debug", middleware.RequireRole(models.UserRoleAdmin))
+		diagnostics.GET("/pprof/", handlers.ListProfiles)        // Index of the runtime profiles
+		diagnostics.GET("/pprof/:profile", handlers.GetProfile)  // Take a CPU, heap, goroutine or other profile, or a trace
+		diagnostics.POST("/pprof/:profile", handlers.GetProfile) // Look up symbols for go tool pprof
+		diagnostics.GET("/vars", handlers.GetRuntimeVars)        // Memory statistics and other expvar variables
+
+		tenantHandler := handlers.NewTenantHandler(useCases.Tenant)
+		tenantAdmin := deployment.Group("/tenants", middleware.RequireRole(models.UserRoleAdmin))
+		tenantAdmin.GET("", tenantHandler.ListTenants)   // List the businesses sharing the deployment
+		tenantAdmin.POST("", tenantHandler.CreateTenant) // Add a business with system accounts of its own
+	}
+
+	setupV2Routes(router, useCases, jwtService)
 }
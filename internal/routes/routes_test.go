@@ -0,0 +1,128 @@
+package routes
+
+import (
+	"encoding/json"
+	"io"
+	"log"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/limistah/wallet-service/internal/auth"
+	"github.com/limistah/wallet-service/internal/breaker"
+	"github.com/limistah/wallet-service/internal/middleware"
+	"github.com/limistah/wallet-service/internal/oauth"
+	"github.com/limistah/wallet-service/internal/payments"
+	"github.com/limistah/wallet-service/internal/usecases"
+	"github.com/swaggo/swag"
+)
+
+// repoRoot is where the swag commands in the README are run from
+const repoRoot = "../.."
+
+// apiDocument is the part of a Swagger document routes are checked against
+type apiDocument struct {
+	BasePath string                                `json:"basePath"`
+	Paths    map[string]map[string]json.RawMessage `json:"paths"`
+}
+
+func readAPIDocument(t *testing.T, file string) apiDocument {
+	t.Helper()
+	content, err := os.ReadFile(filepath.Join(repoRoot, file))
+	if err != nil {
+		t.Fatalf("Failed to read %s: %v", file, err)
+	}
+	var document apiDocument
+	if err := json.Unmarshal(content, &document); err != nil {
+		t.Fatalf("Failed to parse %s: %v", file, err)
+	}
+	return document
+}
+
+// swaggerPath turns a gin route into the path of its Swagger operation
+func swaggerPath(route, basePath string) string {
+	route = strings.TrimPrefix(route, basePath)
+	segments := strings.Split(route, "/")
+	for i, segment := range segments {
+		if strings.HasPrefix(segment, ":") || strings.HasPrefix(segment, "*") {
+			segments[i] = "{" + segment[1:] + "}"
+		}
+	}
+	return strings.Join(segments, "/")
+}
+
+func TestSetupRoutes_EveryRouteIsDocumented(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	SetupRoutes(router, &usecases.UseCases{}, auth.NewJWTService("secret", "wallet-service"), payments.NewRegistry(),
+		breaker.NewRegistry(breaker.Config{}), oauth.NewRegistry(nil), middleware.Deprecation{})
+
+	documents := []apiDocument{readAPIDocument(t, "docs/v2/v2_swagger.json"), readAPIDocument(t, "docs/swagger.json")}
+	for _, route := range router.Routes() {
+		document := documents[1]
+		if strings.HasPrefix(route.Path, documents[0].BasePath+"/") {
+			document = documents[0]
+		}
+		path := swaggerPath(route.Path, document.BasePath)
+		if _, ok := document.Paths[path][strings.ToLower(route.Method)]; !ok {
+			t.Errorf("%s %s is not documented; annotate its handler and regenerate the docs", route.Method, route.Path)
+		}
+	}
+}
+
+func TestAPIDocuments_MatchTheAnnotations(t *testing.T) {
+	if testing.Short() {
+		t.Skip("generating the API documents parses every package")
+	}
+
+	// The same arguments as the swag init commands in the README
+	tests := []struct {
+		file       string
+		searchDirs []string
+		main       string
+		excludes   []string
+	}{
+		{"docs/swagger.json", []string{"."}, "cmd/main.go", []string{"internal/handlers/v2", "internal/dto/v2"}},
+		{"docs/v2/v2_swagger.json", []string{"internal/handlers/v2", "internal/dto/v2", "internal/dto"}, "doc.go", nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.file, func(t *testing.T) {
+			searchDirs := make([]string, len(tt.searchDirs))
+			for i, dir := range tt.searchDirs {
+				searchDirs[i] = filepath.Join(repoRoot, dir)
+			}
+			excludes := make([]string, len(tt.excludes))
+			for i, dir := range tt.excludes {
+				excludes[i] = filepath.Join(repoRoot, dir)
+			}
+			parser := swag.New(
+				swag.SetParseDependency(true),
+				swag.ParseUsingGoList(true),
+				swag.SetExcludedDirsAndFiles(strings.Join(excludes, ",")),
+				swag.SetDebugger(log.New(io.Discard, "", 0)),
+			)
+			parser.ParseInternal = true
+			if err := parser.ParseAPIMultiSearchDir(searchDirs, tt.main, 100); err != nil {
+				t.Fatalf("Failed to generate %s: %v", tt.file, err)
+			}
+
+			generated, err := json.Marshal(parser.GetSwagger())
+			if err != nil {
+				t.Fatalf("Failed to encode %s: %v", tt.file, err)
+			}
+			committed, err := os.ReadFile(filepath.Join(repoRoot, tt.file))
+			if err != nil {
+				t.Fatalf("Failed to read %s: %v", tt.file, err)
+			}
+			var want, got interface{}
+			json.Unmarshal(generated, &want)
+			json.Unmarshal(committed, &got)
+			if !reflect.DeepEqual(want, got) {
+				t.Errorf("%s is out of date with the handler annotations; regenerate it with swag init as described in the README", tt.file)
+			}
+		})
+	}
+}
@@ -0,0 +1,111 @@
+package routes
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+	"sync"
+
+	"github.com/limistah/wallet-service/internal/apperrors"
+	"github.com/limistah/wallet-service/internal/auth"
+	"github.com/limistah/wallet-service/internal/dto"
+	"github.com/limistah/wallet-service/internal/i18n"
+	"github.com/limistah/wallet-service/internal/models"
+	"github.com/limistah/wallet-service/internal/usecases"
+)
+
+// TenantHeader names the tenant, by slug, of requests made without a token such as registrations
+// and logins
+const TenantHeader = "X-Tenant-ID"
+
+// sharedPaths are served for the whole deployment rather than a tenant: provider webhooks and signed
+// downloads are not made on behalf of a tenant, and neither are health and readiness checks, docs and the JWKS
+var sharedPaths = []string{"/health", "/ready", "/.well-known/", "/swagger/", "/api/v1/webhooks/", "/api/v1/downloads"}
+
+// TenantRouter sends each request to the router of its tenant, whose repositories are scoped to the
+// tenant, so a request cannot reach the users and wallets of another. The tenant is the one of the
+// request's token, else the one named by TenantHeader, else the default tenant
+type TenantRouter struct {
+	shared     http.Handler
+	build      func(tenantID uint) http.Handler
+	jwtService *auth.JWTService
+	tenants    usecases.TenantUseCase
+
+	mu      sync.Mutex
+	routers map[uint]http.Handler
+}
+
+// NewTenantRouter creates a router serving the shared paths with shared and every tenant with the
+// router build returns for it, built on its first request
+func NewTenantRouter(shared http.Handler, build func(tenantID uint) http.Handler, jwtService *auth.JWTService, tenants usecases.TenantUseCase) *TenantRouter {
+	return &TenantRouter{
+		shared:     shared,
+		build:      build,
+		jwtService: jwtService,
+		tenants:    tenants,
+		routers:    make(map[uint]http.Handler),
+	}
+}
+
+func (t *TenantRouter) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	for _, prefix := range sharedPaths {
+		if strings.HasPrefix(r.URL.Path, prefix) {
+			t.shared.ServeHTTP(w, r)
+			return
+		}
+	}
+
+	tenantID, err := t.resolve(r)
+	if err != nil {
+		// The request has not reached the gin middleware yet, so the message is translated here
+		code := apperrors.CodeOf(err)
+		key := "tenant.resolve_failed"
+		if code != "" && i18n.Has("error."+code) {
+			key = "error." + code
+		}
+		language := i18n.Match(r.Header.Get("Accept-Language"))
+		w.Header().Set("Content-Type", "application/json; charset=utf-8")
+		w.Header().Set("Content-Language", language)
+		w.WriteHeader(apperrors.HTTPStatus(err))
+		json.NewEncoder(w).Encode(dto.ErrorResponse{
+			Success: false,
+			Message: i18n.T(language, key),
+			Error:   err.Error(),
+			Code:    code,
+		})
+		return
+	}
+	t.router(tenantID).ServeHTTP(w, r)
+}
+
+// resolve returns the tenant a request is made for. Invalid tokens are left for the authentication
+// middleware to refuse
+func (t *TenantRouter) resolve(r *http.Request) (uint, error) {
+	if token, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer "); ok && token != "" {
+		if claims, err := t.jwtService.ValidateToken(token); err == nil {
+			if claims.TenantID == 0 {
+				return models.DefaultTenantID, nil
+			}
+			return claims.TenantID, nil
+		}
+	}
+	if slug := r.Header.Get(TenantHeader); slug != "" {
+		tenant, err := t.tenants.ResolveTenant(r.Context(), slug)
+		if err != nil {
+			return 0, err
+		}
+		return tenant.ID, nil
+	}
+	return models.DefaultTenantID, nil
+}
+
+func (t *TenantRouter) router(tenantID uint) http.Handler {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	router, ok := t.routers[tenantID]
+	if !ok {
+		router = t.build(tenantID)
+		t.routers[tenantID] = router
+	}
+	return router
+}
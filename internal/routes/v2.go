@@ -0,0 +1,24 @@
+package routes
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/limistah/wallet-service/internal/auth"
+	handlersv2 "github.com/limistah/wallet-service/internal/handlers/v2"
+	"github.com/limistah/wallet-service/internal/middleware"
+	"github.com/limistah/wallet-service/internal/usecases"
+)
+
+// setupV2Routes registers the /api/v2 endpoints. Only endpoints whose payloads changed have a v2
+// version; clients keep using v1 for the rest
+func setupV2Routes(router *gin.Engine, useCases *usecases.UseCases, jwtService *auth.JWTService) {
+	v2 := router.Group("/api/v2")
+	v2.Use(middleware.AuthMiddleware(jwtService, useCases.Revocation))
+	{
+		walletHandler := handlersv2.NewWalletHandler(useCases.Wallet)
+		wallets := v2.Group("/wallets")
+		{
+			wallets.GET("/me", walletHandler.GetWallet)                // Get authenticated user's wallet with a currency-tagged balance
+			wallets.GET("/me/balance", walletHandler.GetWalletBalance) // Get authenticated user's wallet balance with its currency
+		}
+	}
+}
@@ -0,0 +1,75 @@
+package rpc
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/limistah/wallet-service/internal/apperrors"
+	"github.com/limistah/wallet-service/internal/auth"
+	"github.com/limistah/wallet-service/internal/models"
+	"github.com/limistah/wallet-service/internal/usecases"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+)
+
+// authenticator admits calls made with the token of an admin of the default tenant, the only callers
+// allowed to run deployment-wide operations such as reconciliation
+type authenticator struct {
+	jwtService  *auth.JWTService
+	revocations usecases.TokenRevocationUseCase
+}
+
+func (a *authenticator) unary(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+	if err := a.authenticate(ctx); err != nil {
+		return nil, statusError(info.FullMethod, err)
+	}
+	return handler(ctx, req)
+}
+
+func (a *authenticator) stream(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+	if err := a.authenticate(ss.Context()); err != nil {
+		return statusError(info.FullMethod, err)
+	}
+	return handler(srv, ss)
+}
+
+// authenticate checks the bearer token of the authorization metadata as the REST API checks the
+// Authorization header of admin requests
+func (a *authenticator) authenticate(ctx context.Context) error {
+	md, _ := metadata.FromIncomingContext(ctx)
+	values := md.Get("authorization")
+	if len(values) == 0 {
+		return apperrors.ErrUnauthenticated.Withf("missing authorization metadata")
+	}
+	token, ok := strings.CutPrefix(values[0], "Bearer ")
+	if !ok || token == "" {
+		return apperrors.ErrInvalidToken.Withf("authorization metadata must be 'Bearer ' followed by a token")
+	}
+
+	claims, err := a.jwtService.ValidateToken(token)
+	if err != nil {
+		return apperrors.ErrInvalidToken.Wrap(err)
+	}
+	var issuedAt time.Time
+	if claims.IssuedAt != nil {
+		issuedAt = claims.IssuedAt.Time
+	}
+	revoked, err := a.revocations.IsRevoked(ctx, claims.ID, claims.UserID, issuedAt)
+	if err != nil {
+		return fmt.Errorf("failed to check token revocation: %w", err)
+	}
+	if revoked {
+		return apperrors.ErrTokenRevoked
+	}
+
+	// Impersonation tokens act as a user, never as an admin, so the role check refuses them too
+	if claims.Role != string(models.UserRoleAdmin) || claims.Impersonator != nil {
+		return apperrors.ErrForbidden.Withf("access denied")
+	}
+	if claims.TenantID != 0 && claims.TenantID != models.DefaultTenantID {
+		return apperrors.ErrForbidden.Withf("only the default tenant can use the gRPC API")
+	}
+	return nil
+}
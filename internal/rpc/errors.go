@@ -0,0 +1,50 @@
+package rpc
+
+import (
+	"log"
+
+	"github.com/limistah/wallet-service/internal/apperrors"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// errorDomain is the domain of the ErrorInfo detail carrying the code of domain errors
+const errorDomain = "wallet-service"
+
+// grpcCodes maps the kind of a domain error to the status code reporting it, as apperrors.HTTPStatus
+// does for the REST API
+var grpcCodes = map[apperrors.Kind]codes.Code{
+	apperrors.KindInvalid:         codes.InvalidArgument,
+	apperrors.KindUnauthenticated: codes.Unauthenticated,
+	apperrors.KindForbidden:       codes.PermissionDenied,
+	apperrors.KindNotFound:        codes.NotFound,
+	apperrors.KindConflict:        codes.FailedPrecondition,
+	apperrors.KindUpstream:        codes.Unavailable,
+	apperrors.KindUnavailable:     codes.Unavailable,
+	apperrors.KindTooLarge:        codes.ResourceExhausted,
+	apperrors.KindRateLimited:     codes.ResourceExhausted,
+}
+
+// statusError turns err into the status a call of method fails with. Domain errors keep their code in
+// an ErrorInfo detail, so clients can tell them apart as REST clients do with the code field
+func statusError(method string, err error) error {
+	code := codes.Internal
+	domainErr := apperrors.From(err)
+	if domainErr != nil {
+		if grpcCode, ok := grpcCodes[domainErr.Kind]; ok {
+			code = grpcCode
+		}
+	}
+	if code == codes.Internal {
+		log.Printf("%s failed: %v", method, err)
+	}
+
+	st := status.New(code, err.Error())
+	if domainErr != nil {
+		if detailed, detailErr := st.WithDetails(&errdetails.ErrorInfo{Reason: domainErr.Code, Domain: errorDomain}); detailErr == nil {
+			st = detailed
+		}
+	}
+	return st.Err()
+}
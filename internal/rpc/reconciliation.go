@@ -0,0 +1,75 @@
+package rpc
+
+import (
+	"time"
+
+	"github.com/limistah/wallet-service/internal/models"
+	"github.com/limistah/wallet-service/internal/rpc/rpcpb"
+	"github.com/limistah/wallet-service/internal/usecases"
+	"google.golang.org/grpc"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+type reconciliationServer struct {
+	rpcpb.UnimplementedReconciliationServiceServer
+	reconciliation usecases.ReconciliationUseCase
+}
+
+// StreamReconciliation sends the outcome of each wallet of the scope as soon as it is reconciled. The
+// run stops when the client cancels the call
+func (s *reconciliationServer) StreamReconciliation(req *rpcpb.StreamReconciliationRequest, stream grpc.ServerStreamingServer[rpcpb.ReconciliationProgress]) error {
+	scope := models.ReconciliationScope{
+		Currency: req.GetCurrency(),
+		Status:   models.WalletStatus(req.GetStatus()),
+	}
+	if req.ActiveFrom != nil {
+		activeFrom := req.GetActiveFrom().AsTime()
+		scope.ActiveFrom = &activeFrom
+	}
+	if req.ActiveTo != nil {
+		activeTo := req.GetActiveTo().AsTime()
+		scope.ActiveTo = &activeTo
+	}
+	for _, id := range req.GetWalletIds() {
+		scope.WalletIDs = append(scope.WalletIDs, uint(id))
+	}
+
+	err := s.reconciliation.StreamReconciliation(stream.Context(), scope, func(progress usecases.ReconciliationProgress) error {
+		return stream.Send(toProgressMessage(progress))
+	})
+	if err != nil {
+		return statusError(rpcpb.ReconciliationService_StreamReconciliation_FullMethodName, err)
+	}
+	return nil
+}
+
+func toProgressMessage(progress usecases.ReconciliationProgress) *rpcpb.ReconciliationProgress {
+	message := &rpcpb.ReconciliationProgress{
+		WalletId: uint64(progress.WalletID),
+		Done:     int64(progress.Done),
+		Total:    int64(progress.Total),
+	}
+	if progress.Err != nil {
+		message.Error = progress.Err.Error()
+	}
+	if report := progress.Report; report != nil {
+		message.Report = &rpcpb.ReconciliationReport{
+			Id:                uint64(report.ID),
+			CreatedAt:         toTimestamp(report.CreatedAt),
+			StoredBalance:     report.StoredBalance.StringFixed(2),
+			CalculatedBalance: report.CalculatedBalance.StringFixed(2),
+			Difference:        report.Difference.StringFixed(2),
+			Status:            string(report.Status),
+			BrokenPairs:       int32(report.BrokenPairs),
+			Notes:             report.Notes,
+		}
+	}
+	return message
+}
+
+func toTimestamp(t time.Time) *timestamppb.Timestamp {
+	if t.IsZero() {
+		return nil
+	}
+	return timestamppb.New(t)
+}
@@ -0,0 +1,160 @@
+package rpc
+
+import (
+	"context"
+	"io"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/limistah/wallet-service/internal/apperrors"
+	"github.com/limistah/wallet-service/internal/auth"
+	"github.com/limistah/wallet-service/internal/models"
+	"github.com/limistah/wallet-service/internal/rpc/rpcpb"
+	"github.com/limistah/wallet-service/internal/usecases"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+)
+
+// noRevocations never reports a token revoked
+type noRevocations struct{}
+
+func (noRevocations) RevokeToken(ctx context.Context, tokenID string, userID uint, expiresAt time.Time) error {
+	return nil
+}
+
+func (noRevocations) RevokeAllTokens(ctx context.Context, userID uint) error { return nil }
+
+func (noRevocations) IsRevoked(ctx context.Context, tokenID string, userID uint, issuedAt time.Time) (bool, error) {
+	return false, nil
+}
+
+// streamingReconciliation reports the given progress for the scope it is called with
+type streamingReconciliation struct {
+	usecases.ReconciliationUseCase
+	progress []usecases.ReconciliationProgress
+	err      error
+	scope    models.ReconciliationScope
+}
+
+func (r *streamingReconciliation) StreamReconciliation(ctx context.Context, scope models.ReconciliationScope, fn func(usecases.ReconciliationProgress) error) error {
+	r.scope = scope
+	if r.err != nil {
+		return r.err
+	}
+	for _, progress := range r.progress {
+		if err := fn(progress); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func dialReconciliation(t *testing.T, reconciliation usecases.ReconciliationUseCase, jwtService *auth.JWTService) rpcpb.ReconciliationServiceClient {
+	t.Helper()
+	listener := bufconn.Listen(1 << 20)
+	server := NewServer(&usecases.UseCases{Reconciliation: reconciliation, Revocation: noRevocations{}}, jwtService)
+	go server.Serve(listener)
+	t.Cleanup(server.Stop)
+
+	conn, err := grpc.NewClient("passthrough:///bufconn",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) { return listener.DialContext(ctx) }),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+	)
+	require.NoError(t, err)
+	t.Cleanup(func() { conn.Close() })
+	return rpcpb.NewReconciliationServiceClient(conn)
+}
+
+// receiveAll reads the stream to its end and returns the messages with the error it ended with
+func receiveAll(stream grpc.ServerStreamingClient[rpcpb.ReconciliationProgress]) ([]*rpcpb.ReconciliationProgress, error) {
+	var messages []*rpcpb.ReconciliationProgress
+	for {
+		message, err := stream.Recv()
+		if err == io.EOF {
+			return messages, nil
+		}
+		if err != nil {
+			return messages, err
+		}
+		messages = append(messages, message)
+	}
+}
+
+func TestReconciliationServer_StreamReconciliation(t *testing.T) {
+	jwtService := auth.NewJWTService("test-secret", "wallet-service")
+	token := func(tenantID uint, role models.UserRole) string {
+		token, _, err := jwtService.IssueToken(1, tenantID, "operator@example.com", string(role), false)
+		require.NoError(t, err)
+		return "Bearer " + token
+	}
+
+	t.Run("admin follows the run wallet by wallet", func(t *testing.T) {
+		reconciliation := &streamingReconciliation{progress: []usecases.ReconciliationProgress{
+			{WalletID: 3, Done: 1, Total: 2, Report: &models.ReconciliationReport{
+				StoredBalance:     decimal.NewFromInt(100),
+				CalculatedBalance: decimal.NewFromInt(90),
+				Difference:        decimal.NewFromInt(10),
+				Status:            models.ReconciliationStatusMismatch,
+			}},
+			{WalletID: 4, Done: 2, Total: 2, Err: apperrors.ErrWalletNotFound},
+		}}
+		client := dialReconciliation(t, reconciliation, jwtService)
+
+		ctx := metadata.AppendToOutgoingContext(context.Background(), "authorization", token(models.DefaultTenantID, models.UserRoleAdmin))
+		stream, err := client.StreamReconciliation(ctx, &rpcpb.StreamReconciliationRequest{Currency: "USD", WalletIds: []uint64{3, 4}})
+		require.NoError(t, err)
+		messages, err := receiveAll(stream)
+		require.NoError(t, err)
+
+		assert.Equal(t, models.ReconciliationScope{Currency: "USD", WalletIDs: []uint{3, 4}}, reconciliation.scope)
+		require.Len(t, messages, 2)
+		assert.Equal(t, uint64(3), messages[0].GetWalletId())
+		assert.Equal(t, "10.00", messages[0].GetReport().GetDifference())
+		assert.Equal(t, string(models.ReconciliationStatusMismatch), messages[0].GetReport().GetStatus())
+		assert.Equal(t, int64(2), messages[1].GetDone())
+		assert.Nil(t, messages[1].GetReport())
+		assert.NotEmpty(t, messages[1].GetError())
+	})
+
+	tests := []struct {
+		name          string
+		authorization string
+		err           error
+		code          codes.Code
+		reason        string
+	}{
+		{"missing token", "", nil, codes.Unauthenticated, apperrors.CodeUnauthenticated},
+		{"user token", token(models.DefaultTenantID, models.UserRoleUser), nil, codes.PermissionDenied, apperrors.CodeForbidden},
+		{"admin of another tenant", token(models.DefaultTenantID+1, models.UserRoleAdmin), nil, codes.PermissionDenied, apperrors.CodeForbidden},
+		{"invalid scope", token(models.DefaultTenantID, models.UserRoleAdmin), apperrors.ErrValidation.Withf("active_from must be before active_to"), codes.InvalidArgument, apperrors.CodeValidation},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client := dialReconciliation(t, &streamingReconciliation{err: tt.err}, jwtService)
+
+			ctx := context.Background()
+			if tt.authorization != "" {
+				ctx = metadata.AppendToOutgoingContext(ctx, "authorization", tt.authorization)
+			}
+			stream, err := client.StreamReconciliation(ctx, &rpcpb.StreamReconciliationRequest{})
+			require.NoError(t, err)
+			_, err = receiveAll(stream)
+
+			st, _ := status.FromError(err)
+			assert.Equal(t, tt.code, st.Code())
+			require.Len(t, st.Details(), 1)
+			info, ok := st.Details()[0].(*errdetails.ErrorInfo)
+			require.True(t, ok)
+			assert.Equal(t, tt.reason, info.GetReason())
+		})
+	}
+}
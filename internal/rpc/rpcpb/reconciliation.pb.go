@@ -0,0 +1,369 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.11
+// 	protoc        (unknown)
+// source: reconciliation.proto
+
+package rpcpb
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	timestamppb "google.golang.org/protobuf/types/known/timestamppb"
+	reflect "reflect"
+	sync "sync"
+	unsafe "unsafe"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+// StreamReconciliationRequest narrows the run down to some wallets; an empty request covers every wallet
+type StreamReconciliationRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Currency      string                 `protobuf:"bytes,1,opt,name=currency,proto3" json:"currency,omitempty"`
+	Status        string                 `protobuf:"bytes,2,opt,name=status,proto3" json:"status,omitempty"`                           // ACTIVE, SUSPENDED or CLOSED
+	ActiveFrom    *timestamppb.Timestamp `protobuf:"bytes,3,opt,name=active_from,json=activeFrom,proto3" json:"active_from,omitempty"` // Wallets last changed at or after
+	ActiveTo      *timestamppb.Timestamp `protobuf:"bytes,4,opt,name=active_to,json=activeTo,proto3" json:"active_to,omitempty"`       // Wallets last changed before
+	WalletIds     []uint64               `protobuf:"varint,5,rep,packed,name=wallet_ids,json=walletIds,proto3" json:"wallet_ids,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *StreamReconciliationRequest) Reset() {
+	*x = StreamReconciliationRequest{}
+	mi := &file_reconciliation_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *StreamReconciliationRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*StreamReconciliationRequest) ProtoMessage() {}
+
+func (x *StreamReconciliationRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_reconciliation_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use StreamReconciliationRequest.ProtoReflect.Descriptor instead.
+func (*StreamReconciliationRequest) Descriptor() ([]byte, []int) {
+	return file_reconciliation_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *StreamReconciliationRequest) GetCurrency() string {
+	if x != nil {
+		return x.Currency
+	}
+	return ""
+}
+
+func (x *StreamReconciliationRequest) GetStatus() string {
+	if x != nil {
+		return x.Status
+	}
+	return ""
+}
+
+func (x *StreamReconciliationRequest) GetActiveFrom() *timestamppb.Timestamp {
+	if x != nil {
+		return x.ActiveFrom
+	}
+	return nil
+}
+
+func (x *StreamReconciliationRequest) GetActiveTo() *timestamppb.Timestamp {
+	if x != nil {
+		return x.ActiveTo
+	}
+	return nil
+}
+
+func (x *StreamReconciliationRequest) GetWalletIds() []uint64 {
+	if x != nil {
+		return x.WalletIds
+	}
+	return nil
+}
+
+// ReconciliationProgress is the outcome of one wallet and how far the run has got
+type ReconciliationProgress struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	WalletId      uint64                 `protobuf:"varint,1,opt,name=wallet_id,json=walletId,proto3" json:"wallet_id,omitempty"`
+	Report        *ReconciliationReport  `protobuf:"bytes,2,opt,name=report,proto3" json:"report,omitempty"` // Unset when the wallet could not be reconciled
+	Error         string                 `protobuf:"bytes,3,opt,name=error,proto3" json:"error,omitempty"`   // Why the wallet could not be reconciled
+	Done          int64                  `protobuf:"varint,4,opt,name=done,proto3" json:"done,omitempty"`    // Wallets handled so far, this one included
+	Total         int64                  `protobuf:"varint,5,opt,name=total,proto3" json:"total,omitempty"`  // Wallets in the run
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ReconciliationProgress) Reset() {
+	*x = ReconciliationProgress{}
+	mi := &file_reconciliation_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ReconciliationProgress) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ReconciliationProgress) ProtoMessage() {}
+
+func (x *ReconciliationProgress) ProtoReflect() protoreflect.Message {
+	mi := &file_reconciliation_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ReconciliationProgress.ProtoReflect.Descriptor instead.
+func (*ReconciliationProgress) Descriptor() ([]byte, []int) {
+	return file_reconciliation_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *ReconciliationProgress) GetWalletId() uint64 {
+	if x != nil {
+		return x.WalletId
+	}
+	return 0
+}
+
+func (x *ReconciliationProgress) GetReport() *ReconciliationReport {
+	if x != nil {
+		return x.Report
+	}
+	return nil
+}
+
+func (x *ReconciliationProgress) GetError() string {
+	if x != nil {
+		return x.Error
+	}
+	return ""
+}
+
+func (x *ReconciliationProgress) GetDone() int64 {
+	if x != nil {
+		return x.Done
+	}
+	return 0
+}
+
+func (x *ReconciliationProgress) GetTotal() int64 {
+	if x != nil {
+		return x.Total
+	}
+	return 0
+}
+
+// ReconciliationReport is the stored report of a wallet; amounts are decimal strings
+type ReconciliationReport struct {
+	state             protoimpl.MessageState `protogen:"open.v1"`
+	Id                uint64                 `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	CreatedAt         *timestamppb.Timestamp `protobuf:"bytes,2,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
+	StoredBalance     string                 `protobuf:"bytes,3,opt,name=stored_balance,json=storedBalance,proto3" json:"stored_balance,omitempty"`
+	CalculatedBalance string                 `protobuf:"bytes,4,opt,name=calculated_balance,json=calculatedBalance,proto3" json:"calculated_balance,omitempty"`
+	Difference        string                 `protobuf:"bytes,5,opt,name=difference,proto3" json:"difference,omitempty"`
+	Status            string                 `protobuf:"bytes,6,opt,name=status,proto3" json:"status,omitempty"` // MATCH, MISMATCH or DOUBLE_ENTRY_ERROR
+	BrokenPairs       int32                  `protobuf:"varint,7,opt,name=broken_pairs,json=brokenPairs,proto3" json:"broken_pairs,omitempty"`
+	Notes             string                 `protobuf:"bytes,8,opt,name=notes,proto3" json:"notes,omitempty"`
+	unknownFields     protoimpl.UnknownFields
+	sizeCache         protoimpl.SizeCache
+}
+
+func (x *ReconciliationReport) Reset() {
+	*x = ReconciliationReport{}
+	mi := &file_reconciliation_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ReconciliationReport) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ReconciliationReport) ProtoMessage() {}
+
+func (x *ReconciliationReport) ProtoReflect() protoreflect.Message {
+	mi := &file_reconciliation_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ReconciliationReport.ProtoReflect.Descriptor instead.
+func (*ReconciliationReport) Descriptor() ([]byte, []int) {
+	return file_reconciliation_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *ReconciliationReport) GetId() uint64 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+func (x *ReconciliationReport) GetCreatedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.CreatedAt
+	}
+	return nil
+}
+
+func (x *ReconciliationReport) GetStoredBalance() string {
+	if x != nil {
+		return x.StoredBalance
+	}
+	return ""
+}
+
+func (x *ReconciliationReport) GetCalculatedBalance() string {
+	if x != nil {
+		return x.CalculatedBalance
+	}
+	return ""
+}
+
+func (x *ReconciliationReport) GetDifference() string {
+	if x != nil {
+		return x.Difference
+	}
+	return ""
+}
+
+func (x *ReconciliationReport) GetStatus() string {
+	if x != nil {
+		return x.Status
+	}
+	return ""
+}
+
+func (x *ReconciliationReport) GetBrokenPairs() int32 {
+	if x != nil {
+		return x.BrokenPairs
+	}
+	return 0
+}
+
+func (x *ReconciliationReport) GetNotes() string {
+	if x != nil {
+		return x.Notes
+	}
+	return ""
+}
+
+var File_reconciliation_proto protoreflect.FileDescriptor
+
+const file_reconciliation_proto_rawDesc = "" +
+	"\n" +
+	"\x14reconciliation.proto\x12\twallet.v1\x1a\x1fgoogle/protobuf/timestamp.proto\"\xe6\x01\n" +
+	"\x1bStreamReconciliationRequest\x12\x1a\n" +
+	"\bcurrency\x18\x01 \x01(\tR\bcurrency\x12\x16\n" +
+	"\x06status\x18\x02 \x01(\tR\x06status\x12;\n" +
+	"\vactive_from\x18\x03 \x01(\v2\x1a.google.protobuf.TimestampR\n" +
+	"activeFrom\x127\n" +
+	"\tactive_to\x18\x04 \x01(\v2\x1a.google.protobuf.TimestampR\bactiveTo\x12\x1d\n" +
+	"\n" +
+	"wallet_ids\x18\x05 \x03(\x04R\twalletIds\"\xae\x01\n" +
+	"\x16ReconciliationProgress\x12\x1b\n" +
+	"\twallet_id\x18\x01 \x01(\x04R\bwalletId\x127\n" +
+	"\x06report\x18\x02 \x01(\v2\x1f.wallet.v1.ReconciliationReportR\x06report\x12\x14\n" +
+	"\x05error\x18\x03 \x01(\tR\x05error\x12\x12\n" +
+	"\x04done\x18\x04 \x01(\x03R\x04done\x12\x14\n" +
+	"\x05total\x18\x05 \x01(\x03R\x05total\"\xa8\x02\n" +
+	"\x14ReconciliationReport\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\x04R\x02id\x129\n" +
+	"\n" +
+	"created_at\x18\x02 \x01(\v2\x1a.google.protobuf.TimestampR\tcreatedAt\x12%\n" +
+	"\x0estored_balance\x18\x03 \x01(\tR\rstoredBalance\x12-\n" +
+	"\x12calculated_balance\x18\x04 \x01(\tR\x11calculatedBalance\x12\x1e\n" +
+	"\n" +
+	"difference\x18\x05 \x01(\tR\n" +
+	"difference\x12\x16\n" +
+	"\x06status\x18\x06 \x01(\tR\x06status\x12!\n" +
+	"\fbroken_pairs\x18\a \x01(\x05R\vbrokenPairs\x12\x14\n" +
+	"\x05notes\x18\b \x01(\tR\x05notes2|\n" +
+	"\x15ReconciliationService\x12c\n" +
+	"\x14StreamReconciliation\x12&.wallet.v1.StreamReconciliationRequest\x1a!.wallet.v1.ReconciliationProgress0\x01B7Z5github.com/limistah/wallet-service/internal/rpc/rpcpbb\x06proto3"
+
+var (
+	file_reconciliation_proto_rawDescOnce sync.Once
+	file_reconciliation_proto_rawDescData []byte
+)
+
+func file_reconciliation_proto_rawDescGZIP() []byte {
+	file_reconciliation_proto_rawDescOnce.Do(func() {
+		file_reconciliation_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_reconciliation_proto_rawDesc), len(file_reconciliation_proto_rawDesc)))
+	})
+	return file_reconciliation_proto_rawDescData
+}
+
+var file_reconciliation_proto_msgTypes = make([]protoimpl.MessageInfo, 3)
+var file_reconciliation_proto_goTypes = []any{
+	(*StreamReconciliationRequest)(nil), // 0: wallet.v1.StreamReconciliationRequest
+	(*ReconciliationProgress)(nil),      // 1: wallet.v1.ReconciliationProgress
+	(*ReconciliationReport)(nil),        // 2: wallet.v1.ReconciliationReport
+	(*timestamppb.Timestamp)(nil),       // 3: google.protobuf.Timestamp
+}
+var file_reconciliation_proto_depIdxs = []int32{
+	3, // 0: wallet.v1.StreamReconciliationRequest.active_from:type_name -> google.protobuf.Timestamp
+	3, // 1: wallet.v1.StreamReconciliationRequest.active_to:type_name -> google.protobuf.Timestamp
+	2, // 2: wallet.v1.ReconciliationProgress.report:type_name -> wallet.v1.ReconciliationReport
+	3, // 3: wallet.v1.ReconciliationReport.created_at:type_name -> google.protobuf.Timestamp
+	0, // 4: wallet.v1.ReconciliationService.StreamReconciliation:input_type -> wallet.v1.StreamReconciliationRequest
+	1, // 5: wallet.v1.ReconciliationService.StreamReconciliation:output_type -> wallet.v1.ReconciliationProgress
+	5, // [5:6] is the sub-list for method output_type
+	4, // [4:5] is the sub-list for method input_type
+	4, // [4:4] is the sub-list for extension type_name
+	4, // [4:4] is the sub-list for extension extendee
+	0, // [0:4] is the sub-list for field type_name
+}
+
+func init() { file_reconciliation_proto_init() }
+func file_reconciliation_proto_init() {
+	if File_reconciliation_proto != nil {
+		return
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_reconciliation_proto_rawDesc), len(file_reconciliation_proto_rawDesc)),
+			NumEnums:      0,
+			NumMessages:   3,
+			NumExtensions: 0,
+			NumServices:   1,
+		},
+		GoTypes:           file_reconciliation_proto_goTypes,
+		DependencyIndexes: file_reconciliation_proto_depIdxs,
+		MessageInfos:      file_reconciliation_proto_msgTypes,
+	}.Build()
+	File_reconciliation_proto = out.File
+	file_reconciliation_proto_goTypes = nil
+	file_reconciliation_proto_depIdxs = nil
+}
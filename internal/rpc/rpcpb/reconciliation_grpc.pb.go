@@ -0,0 +1,132 @@
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+// versions:
+// - protoc-gen-go-grpc v1.6.2
+// - protoc             (unknown)
+// source: reconciliation.proto
+
+package rpcpb
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.64.0 or later.
+const _ = grpc.SupportPackageIsVersion9
+
+const (
+	ReconciliationService_StreamReconciliation_FullMethodName = "/wallet.v1.ReconciliationService/StreamReconciliation"
+)
+
+// ReconciliationServiceClient is the client API for ReconciliationService service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+//
+// ReconciliationService lets operator tools reconcile wallets over gRPC
+type ReconciliationServiceClient interface {
+	// StreamReconciliation reconciles the wallets of a scope and sends each wallet's outcome as soon as
+	// it is known, so a tool can show the progress of a long run. Admins of the default tenant only
+	StreamReconciliation(ctx context.Context, in *StreamReconciliationRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[ReconciliationProgress], error)
+}
+
+type reconciliationServiceClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewReconciliationServiceClient(cc grpc.ClientConnInterface) ReconciliationServiceClient {
+	return &reconciliationServiceClient{cc}
+}
+
+func (c *reconciliationServiceClient) StreamReconciliation(ctx context.Context, in *StreamReconciliationRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[ReconciliationProgress], error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &ReconciliationService_ServiceDesc.Streams[0], ReconciliationService_StreamReconciliation_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &grpc.GenericClientStream[StreamReconciliationRequest, ReconciliationProgress]{ClientStream: stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type ReconciliationService_StreamReconciliationClient = grpc.ServerStreamingClient[ReconciliationProgress]
+
+// ReconciliationServiceServer is the server API for ReconciliationService service.
+// All implementations must embed UnimplementedReconciliationServiceServer
+// for forward compatibility.
+//
+// ReconciliationService lets operator tools reconcile wallets over gRPC
+type ReconciliationServiceServer interface {
+	// StreamReconciliation reconciles the wallets of a scope and sends each wallet's outcome as soon as
+	// it is known, so a tool can show the progress of a long run. Admins of the default tenant only
+	StreamReconciliation(*StreamReconciliationRequest, grpc.ServerStreamingServer[ReconciliationProgress]) error
+	mustEmbedUnimplementedReconciliationServiceServer()
+}
+
+// UnimplementedReconciliationServiceServer must be embedded to have
+// forward compatible implementations.
+//
+// NOTE: this should be embedded by value instead of pointer to avoid a nil
+// pointer dereference when methods are called.
+type UnimplementedReconciliationServiceServer struct{}
+
+func (UnimplementedReconciliationServiceServer) StreamReconciliation(*StreamReconciliationRequest, grpc.ServerStreamingServer[ReconciliationProgress]) error {
+	return status.Error(codes.Unimplemented, "method StreamReconciliation not implemented")
+}
+func (UnimplementedReconciliationServiceServer) mustEmbedUnimplementedReconciliationServiceServer() {}
+func (UnimplementedReconciliationServiceServer) testEmbeddedByValue()                               {}
+
+// UnsafeReconciliationServiceServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to ReconciliationServiceServer will
+// result in compilation errors.
+type UnsafeReconciliationServiceServer interface {
+	mustEmbedUnimplementedReconciliationServiceServer()
+}
+
+func RegisterReconciliationServiceServer(s grpc.ServiceRegistrar, srv ReconciliationServiceServer) {
+	// If the following call panics, it indicates UnimplementedReconciliationServiceServer was
+	// embedded by pointer and is nil.  This will cause panics if an
+	// unimplemented method is ever invoked, so we test this at initialization
+	// time to prevent it from happening at runtime later due to I/O.
+	if t, ok := srv.(interface{ testEmbeddedByValue() }); ok {
+		t.testEmbeddedByValue()
+	}
+	s.RegisterService(&ReconciliationService_ServiceDesc, srv)
+}
+
+func _ReconciliationService_StreamReconciliation_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(StreamReconciliationRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(ReconciliationServiceServer).StreamReconciliation(m, &grpc.GenericServerStream[StreamReconciliationRequest, ReconciliationProgress]{ServerStream: stream})
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type ReconciliationService_StreamReconciliationServer = grpc.ServerStreamingServer[ReconciliationProgress]
+
+// ReconciliationService_ServiceDesc is the grpc.ServiceDesc for ReconciliationService service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var ReconciliationService_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "wallet.v1.ReconciliationService",
+	HandlerType: (*ReconciliationServiceServer)(nil),
+	Methods:     []grpc.MethodDesc{},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "StreamReconciliation",
+			Handler:       _ReconciliationService_StreamReconciliation_Handler,
+			ServerStreams: true,
+		},
+	},
+	Metadata: "reconciliation.proto",
+}
@@ -0,0 +1,24 @@
+// Package rpc serves the gRPC API, for operator tools that need more than request and response, such
+// as following a reconciliation run while it progresses. The messages and services are defined in
+// rpcpb/reconciliation.proto
+package rpc
+
+import (
+	"github.com/limistah/wallet-service/internal/auth"
+	"github.com/limistah/wallet-service/internal/rpc/rpcpb"
+	"github.com/limistah/wallet-service/internal/usecases"
+	"google.golang.org/grpc"
+)
+
+// NewServer creates a gRPC server with every service of the API. Calls must carry the bearer token of
+// an admin of the default tenant in their authorization metadata
+func NewServer(useCases *usecases.UseCases, jwtService *auth.JWTService, opts ...grpc.ServerOption) *grpc.Server {
+	authenticator := &authenticator{jwtService: jwtService, revocations: useCases.Revocation}
+	opts = append(opts,
+		grpc.UnaryInterceptor(authenticator.unary),
+		grpc.StreamInterceptor(authenticator.stream),
+	)
+	server := grpc.NewServer(opts...)
+	rpcpb.RegisterReconciliationServiceServer(server, &reconciliationServer{reconciliation: useCases.Reconciliation})
+	return server
+}
@@ -0,0 +1,90 @@
+package secrets
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/limistah/wallet-service/internal/awsauth"
+)
+
+// AWSProviderName identifies AWS Secrets Manager
+const AWSProviderName = "aws"
+
+// AWSCredentials sign requests to AWS; SessionToken is only set for temporary credentials
+type AWSCredentials = awsauth.Credentials
+
+// AWSProvider reads secrets from an AWS Secrets Manager secret holding a JSON object
+type AWSProvider struct {
+	region      string
+	secretID    string
+	credentials AWSCredentials
+	endpoint    string
+	client      *http.Client
+	now         func() time.Time
+}
+
+// NewAWSProvider creates a provider reading the secret with the given name or ARN in region
+func NewAWSProvider(region, secretID string, credentials AWSCredentials) *AWSProvider {
+	return &AWSProvider{
+		region:      region,
+		secretID:    secretID,
+		credentials: credentials,
+		endpoint:    fmt.Sprintf("https://secretsmanager.%s.amazonaws.com/", region),
+		client:      &http.Client{Timeout: 10 * time.Second},
+		now:         time.Now,
+	}
+}
+
+// Name implements Provider
+func (p *AWSProvider) Name() string {
+	return AWSProviderName
+}
+
+// Fetch implements Provider
+func (p *AWSProvider) Fetch() (map[string]string, error) {
+	payload, err := json.Marshal(map[string]string{"SecretId": p.secretID})
+	if err != nil {
+		return nil, err
+	}
+	req, err := http.NewRequest(http.MethodPost, p.endpoint, bytes.NewReader(payload))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/x-amz-json-1.1")
+	req.Header.Set("X-Amz-Target", "secretsmanager.GetSecretValue")
+	awsauth.Sign(req, payload, "secretsmanager", p.region, p.credentials, p.now())
+
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to reach Secrets Manager: %w", err)
+	}
+	defer resp.Body.Close()
+	body, err := io.ReadAll(io.LimitReader(resp.Body, 1<<20))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read Secrets Manager response: %w", err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		var failure struct {
+			Type    string `json:"__type"`
+			Message string `json:"message"`
+		}
+		_ = json.Unmarshal(body, &failure)
+		return nil, fmt.Errorf("secrets manager returned status %d: %s %s", resp.StatusCode, failure.Type, failure.Message)
+	}
+
+	var response struct {
+		SecretString string `json:"SecretString"`
+	}
+	if err := json.Unmarshal(body, &response); err != nil {
+		return nil, fmt.Errorf("failed to decode Secrets Manager response: %w", err)
+	}
+	var data map[string]json.RawMessage
+	if err := json.Unmarshal([]byte(response.SecretString), &data); err != nil {
+		return nil, fmt.Errorf("secret %s is not a JSON object of key/value pairs", p.secretID)
+	}
+	return stringValues(data), nil
+}
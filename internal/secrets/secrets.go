@@ -0,0 +1,71 @@
+// Package secrets loads the JWT secret, database password and provider API keys from a secrets
+// backend instead of the environment, and keeps them current as the backend rotates them
+package secrets
+
+import (
+	"fmt"
+	"log"
+	"os"
+	"sort"
+	"time"
+)
+
+// Provider fetches secrets from a backend. Secrets are named after the environment variables they
+// replace, such as JWT_SECRET, DB_PASSWORD or PAYSTACK_SECRET_KEY
+type Provider interface {
+	Name() string
+	Fetch() (map[string]string, error)
+}
+
+// Apply fetches the secrets of provider and sets them as environment variables, where the
+// configuration reads them; secrets from the backend win over the environment. It returns the
+// names of the variables whose value changed
+func Apply(provider Provider) ([]string, error) {
+	values, err := provider.Fetch()
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch secrets from %s: %w", provider.Name(), err)
+	}
+
+	var changed []string
+	for name, value := range values {
+		if current, set := os.LookupEnv(name); set && current == value {
+			continue
+		}
+		if err := os.Setenv(name, value); err != nil {
+			return nil, fmt.Errorf("failed to set %s: %w", name, err)
+		}
+		changed = append(changed, name)
+	}
+	sort.Strings(changed)
+	return changed, nil
+}
+
+// Watch applies the secrets of provider every interval and calls onRotate with the names of the
+// secrets that changed. A failed fetch keeps the current secrets. Call the returned function to stop
+func Watch(provider Provider, interval time.Duration, onRotate func(changed []string)) func() {
+	ticker := time.NewTicker(interval)
+	done := make(chan struct{})
+
+	go func() {
+		for {
+			select {
+			case <-ticker.C:
+				changed, err := Apply(provider)
+				if err != nil {
+					log.Printf("Secret rotation: %v", err)
+					continue
+				}
+				if len(changed) > 0 {
+					onRotate(changed)
+				}
+			case <-done:
+				return
+			}
+		}
+	}()
+
+	return func() {
+		ticker.Stop()
+		close(done)
+	}
+}
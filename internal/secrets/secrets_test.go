@@ -0,0 +1,84 @@
+package secrets
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestAWSProvider_Fetch(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("X-Amz-Target") != "secretsmanager.GetSecretValue" || !strings.HasPrefix(r.Header.Get("Authorization"), "AWS4-HMAC-SHA256 ") {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		if r.Header.Get("X-Amz-Security-Token") != "session" {
+			t.Errorf("Expected the session token to be sent")
+		}
+		var body map[string]string
+		json.NewDecoder(r.Body).Decode(&body)
+		if body["SecretId"] != "wallet-service/production" {
+			t.Errorf("Expected the secret ID to be requested, got %v", body)
+		}
+		json.NewEncoder(w).Encode(map[string]string{"SecretString": `{"JWT_SECRET":"from-aws","DB_PORT":3306}`})
+	}))
+	defer server.Close()
+
+	provider := NewAWSProvider("eu-west-1", "wallet-service/production", AWSCredentials{AccessKeyID: "AKID", SecretAccessKey: "secret", SessionToken: "session"})
+	provider.endpoint = server.URL + "/"
+	values, err := provider.Fetch()
+	if err != nil {
+		t.Fatalf("Fetch() error = %v", err)
+	}
+	if values["JWT_SECRET"] != "from-aws" || values["DB_PORT"] != "3306" {
+		t.Errorf("Fetch() = %v", values)
+	}
+}
+
+func TestVaultProvider_ApplyAndRotate(t *testing.T) {
+	secret := "first"
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/v1/secret/data/wallet-service" || r.Header.Get("X-Vault-Token") != "root" {
+			w.WriteHeader(http.StatusForbidden)
+			return
+		}
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"data": map[string]interface{}{
+				"data":     map[string]string{"WALLET_TEST_SECRET": secret},
+				"metadata": map[string]interface{}{"version": 1},
+			},
+		})
+	}))
+	defer server.Close()
+	t.Setenv("WALLET_TEST_SECRET", "from-env")
+
+	provider := NewVaultProvider(server.URL+"/", "root", "/secret/data/wallet-service")
+	changed, err := Apply(provider)
+	if err != nil {
+		t.Fatalf("Apply() error = %v", err)
+	}
+	if os.Getenv("WALLET_TEST_SECRET") != "first" || len(changed) != 1 {
+		t.Errorf("Expected Vault to win over the environment, got %q changed %v", os.Getenv("WALLET_TEST_SECRET"), changed)
+	}
+
+	secret = "second"
+	rotated := make(chan []string, 1)
+	stop := Watch(provider, 10*time.Millisecond, func(changed []string) { rotated <- changed })
+	defer stop()
+	select {
+	case changed := <-rotated:
+		if len(changed) != 1 || changed[0] != "WALLET_TEST_SECRET" || os.Getenv("WALLET_TEST_SECRET") != "second" {
+			t.Errorf("Expected the rotated secret, got %v and %q", changed, os.Getenv("WALLET_TEST_SECRET"))
+		}
+	case <-time.After(time.Second):
+		t.Fatal("Expected the rotation to be noticed")
+	}
+
+	if _, err := Apply(NewVaultProvider(server.URL, "wrong", "secret/data/wallet-service")); err == nil {
+		t.Error("Expected a refused token to fail")
+	}
+}
@@ -0,0 +1,88 @@
+package secrets
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// VaultProviderName identifies HashiCorp Vault
+const VaultProviderName = "vault"
+
+// VaultProvider reads secrets from a HashiCorp Vault key/value secret
+type VaultProvider struct {
+	addr   string
+	token  string
+	path   string
+	client *http.Client
+}
+
+// NewVaultProvider creates a provider reading the secret at path, such as secret/data/wallet-service
+// for the version 2 key/value engine mounted at secret/
+func NewVaultProvider(addr, token, path string) *VaultProvider {
+	return &VaultProvider{
+		addr:   strings.TrimSuffix(addr, "/"),
+		token:  token,
+		path:   strings.Trim(path, "/"),
+		client: &http.Client{Timeout: 10 * time.Second},
+	}
+}
+
+// Name implements Provider
+func (p *VaultProvider) Name() string {
+	return VaultProviderName
+}
+
+// Fetch implements Provider
+func (p *VaultProvider) Fetch() (map[string]string, error) {
+	req, err := http.NewRequest(http.MethodGet, p.addr+"/v1/"+p.path, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("X-Vault-Token", p.token)
+
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to reach Vault: %w", err)
+	}
+	defer resp.Body.Close()
+	body, err := io.ReadAll(io.LimitReader(resp.Body, 1<<20))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read Vault response: %w", err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("vault returned status %d for %s", resp.StatusCode, p.path)
+	}
+
+	var response struct {
+		Data map[string]json.RawMessage `json:"data"`
+	}
+	if err := json.Unmarshal(body, &response); err != nil {
+		return nil, fmt.Errorf("failed to decode Vault response: %w", err)
+	}
+	// The version 2 engine nests the values under data.data next to their metadata
+	data := response.Data
+	if nested, ok := data["data"]; ok {
+		data = nil
+		if err := json.Unmarshal(nested, &data); err != nil {
+			return nil, fmt.Errorf("failed to decode Vault secret: %w", err)
+		}
+	}
+	return stringValues(data), nil
+}
+
+// stringValues keeps the values of a secret as text; numbers and booleans are written as in JSON
+func stringValues(data map[string]json.RawMessage) map[string]string {
+	values := make(map[string]string, len(data))
+	for name, raw := range data {
+		var value string
+		if err := json.Unmarshal(raw, &value); err != nil {
+			value = string(raw)
+		}
+		values[name] = value
+	}
+	return values
+}
@@ -0,0 +1,114 @@
+package server
+
+import (
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"io/fs"
+	"net"
+	"net/http"
+	"os"
+	"path"
+	"strings"
+	"time"
+)
+
+// unixPrefix marks addresses that are unix domain socket paths
+const unixPrefix = "unix:"
+
+// Listener is an address the service accepts requests on and the handler serving it
+type Listener struct {
+	// Address is host:port, or unix:PATH for a unix domain socket
+	Address string
+	Handler http.Handler
+	// Plain serves HTTP even when TLS is on, as the HTTPS redirect does; unix sockets are always plain
+	Plain bool
+}
+
+// Serve accepts requests on every listener and returns when one of them fails. TCP listeners are
+// served over TLS when tlsConfig is set
+func Serve(listeners []Listener, tlsConfig *tls.Config, readTimeout, writeTimeout time.Duration) error {
+	errs := make(chan error, len(listeners))
+	for _, listener := range listeners {
+		ln, err := Listen(listener.Address)
+		if err != nil {
+			return err
+		}
+		server := &http.Server{
+			Handler:      listener.Handler,
+			ReadTimeout:  readTimeout,
+			WriteTimeout: writeTimeout,
+		}
+		secure := tlsConfig != nil && !listener.Plain && !strings.HasPrefix(listener.Address, unixPrefix)
+		go func() {
+			var err error
+			if secure {
+				server.TLSConfig = tlsConfig
+				err = server.ServeTLS(ln, "", "")
+			} else {
+				err = server.Serve(ln)
+			}
+			errs <- fmt.Errorf("%s: %w", listener.Address, err)
+		}()
+	}
+	return <-errs
+}
+
+// Listen opens a TCP listener on host:port, or a unix domain socket on unix:PATH that the owner and
+// group of the process may connect to. A socket left behind by an earlier run is replaced
+func Listen(address string) (net.Listener, error) {
+	socketPath, ok := strings.CutPrefix(address, unixPrefix)
+	if !ok {
+		return net.Listen("tcp", address)
+	}
+
+	if info, err := os.Stat(socketPath); err == nil {
+		if info.Mode()&fs.ModeSocket == 0 {
+			return nil, fmt.Errorf("%s exists and is not a socket", socketPath)
+		}
+		if err := os.Remove(socketPath); err != nil {
+			return nil, fmt.Errorf("failed to remove stale socket: %w", err)
+		}
+	} else if !errors.Is(err, fs.ErrNotExist) {
+		return nil, err
+	}
+
+	ln, err := net.Listen("unix", socketPath)
+	if err != nil {
+		return nil, err
+	}
+	if err := os.Chmod(socketPath, 0o660); err != nil {
+		ln.Close()
+		return nil, fmt.Errorf("failed to set socket permissions: %w", err)
+	}
+	return ln, nil
+}
+
+// isAdminPath reports whether a request path is for the admin endpoints or the metrics
+func isAdminPath(requestPath string) bool {
+	requestPath = path.Clean("/" + requestPath)
+	return requestPath == "/metrics" || requestPath == "/api/v1/admin" || strings.HasPrefix(requestPath, "/api/v1/admin/")
+}
+
+// AdminOnly serves the admin endpoints and metrics of handler and answers 404 to everything else
+func AdminOnly(handler http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !isAdminPath(r.URL.Path) {
+			http.NotFound(w, r)
+			return
+		}
+		handler.ServeHTTP(w, r)
+	})
+}
+
+// WithoutAdmin answers 404 to the admin endpoints and metrics, which are served on the admin
+// listener instead
+func WithoutAdmin(handler http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if isAdminPath(r.URL.Path) {
+			http.NotFound(w, r)
+			return
+		}
+		handler.ServeHTTP(w, r)
+	})
+}
@@ -0,0 +1,108 @@
+package server
+
+import (
+	"context"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestServe_UnixSocketAndAdminListener(t *testing.T) {
+	api := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		io.WriteString(w, r.URL.Path)
+	})
+	socket := filepath.Join(t.TempDir(), "api.sock")
+	// A socket left behind by a crashed run must not stop the server from starting
+	stale, err := net.Listen("unix", socket)
+	if err != nil {
+		t.Fatalf("Failed to create stale socket: %v", err)
+	}
+	stale.(*net.UnixListener).SetUnlinkOnClose(false)
+	stale.Close()
+
+	admin, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("Failed to reserve admin port: %v", err)
+	}
+	adminAddress := admin.Addr().String()
+	admin.Close()
+
+	errs := make(chan error, 1)
+	go func() {
+		errs <- Serve([]Listener{
+			{Address: "unix:" + socket, Handler: WithoutAdmin(api)},
+			{Address: adminAddress, Handler: AdminOnly(api)},
+		}, nil, time.Second, time.Second)
+	}()
+
+	unixClient := &http.Client{Transport: &http.Transport{
+		DialContext: func(ctx context.Context, _, _ string) (net.Conn, error) {
+			return (&net.Dialer{}).DialContext(ctx, "unix", socket)
+		},
+	}}
+	get := func(client *http.Client, url string) int {
+		t.Helper()
+		var resp *http.Response
+		var err error
+		for attempt := 0; attempt < 50; attempt++ {
+			if resp, err = client.Get(url); err == nil {
+				break
+			}
+			time.Sleep(10 * time.Millisecond)
+		}
+		if err != nil {
+			t.Fatalf("GET %s failed: %v", url, err)
+		}
+		resp.Body.Close()
+		return resp.StatusCode
+	}
+
+	if code := get(unixClient, "http://wallet/api/v1/wallets/me"); code != http.StatusOK {
+		t.Errorf("Expected the API on the unix socket, got %d", code)
+	}
+	if code := get(unixClient, "http://wallet/api/v1/admin/users"); code != http.StatusNotFound {
+		t.Errorf("Expected admin endpoints to be refused off the admin listener, got %d", code)
+	}
+	if code := get(http.DefaultClient, "http://"+adminAddress+"/api/v1/admin/users"); code != http.StatusOK {
+		t.Errorf("Expected admin endpoints on the admin listener, got %d", code)
+	}
+	if code := get(http.DefaultClient, "http://"+adminAddress+"/api/v1/wallets/me"); code != http.StatusNotFound {
+		t.Errorf("Expected the admin listener to refuse the rest of the API, got %d", code)
+	}
+	if info, err := os.Stat(socket); err != nil || info.Mode().Perm() != 0o660 {
+		t.Errorf("Expected the socket to be limited to the owner and group, got %v %v", info.Mode(), err)
+	}
+
+	select {
+	case err := <-errs:
+		t.Fatalf("Serve() returned early: %v", err)
+	default:
+	}
+}
+
+func TestIsAdminPath(t *testing.T) {
+	for path, want := range map[string]bool{
+		"/metrics":              true,
+		"/api/v1/admin":         true,
+		"/api/v1/admin/users/1": true,
+		"/api/v1//admin/users":  true,
+		"/api/v1/administrator": false,
+		"/api/v1/wallets/me":    false,
+	} {
+		if got := isAdminPath(path); got != want {
+			t.Errorf("isAdminPath(%q) = %v, want %v", path, got, want)
+		}
+	}
+
+	ok := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {})
+	rec := httptest.NewRecorder()
+	WithoutAdmin(ok).ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+	if rec.Code != http.StatusNotFound {
+		t.Errorf("Expected metrics to be refused, got %d", rec.Code)
+	}
+}
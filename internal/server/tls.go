@@ -0,0 +1,78 @@
+// Package server configures how the HTTP server listens: TLS termination, HTTP to HTTPS redirects
+package server
+
+import (
+	"crypto/tls"
+	"fmt"
+	"net"
+	"net/http"
+
+	"github.com/limistah/wallet-service/internal/config"
+	"golang.org/x/crypto/acme/autocert"
+)
+
+// modernTLSConfig accepts TLS 1.2 and 1.3 only, with forward secret AEAD cipher suites, and offers
+// HTTP/2 to clients
+func modernTLSConfig() *tls.Config {
+	return &tls.Config{
+		MinVersion:       tls.VersionTLS12,
+		CurvePreferences: []tls.CurveID{tls.X25519, tls.CurveP256},
+		// TLS 1.3 suites are not configurable; these apply to TLS 1.2
+		CipherSuites: []uint16{
+			tls.TLS_ECDHE_ECDSA_WITH_AES_128_GCM_SHA256,
+			tls.TLS_ECDHE_RSA_WITH_AES_128_GCM_SHA256,
+			tls.TLS_ECDHE_ECDSA_WITH_AES_256_GCM_SHA384,
+			tls.TLS_ECDHE_RSA_WITH_AES_256_GCM_SHA384,
+			tls.TLS_ECDHE_ECDSA_WITH_CHACHA20_POLY1305,
+			tls.TLS_ECDHE_RSA_WITH_CHACHA20_POLY1305,
+		},
+		NextProtos: []string{"h2", "http/1.1"},
+	}
+}
+
+// NewTLS returns the TLS configuration of cfg, or nil when the server speaks plain HTTP. The handler
+// is served on the HTTP redirect port: it redirects to HTTPS, after answering the challenges of
+// Let's Encrypt when certificates come from autocert
+func NewTLS(cfg config.ServerConfig) (*tls.Config, http.Handler, error) {
+	redirect := RedirectToHTTPS(cfg.Port)
+	switch {
+	case cfg.TLSCertFile != "":
+		certificate, err := tls.LoadX509KeyPair(cfg.TLSCertFile, cfg.TLSKeyFile)
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to load TLS certificate: %w", err)
+		}
+		tlsConfig := modernTLSConfig()
+		tlsConfig.Certificates = []tls.Certificate{certificate}
+		return tlsConfig, redirect, nil
+	case len(cfg.TLSAutocertDomains) > 0:
+		manager := &autocert.Manager{
+			Prompt:     autocert.AcceptTOS,
+			HostPolicy: autocert.HostWhitelist(cfg.TLSAutocertDomains...),
+			Cache:      autocert.DirCache(cfg.TLSAutocertCacheDir),
+			Email:      cfg.TLSAutocertEmail,
+		}
+		tlsConfig := modernTLSConfig()
+		tlsConfig.GetCertificate = manager.GetCertificate
+		// TLS-ALPN challenges are answered on the HTTPS port itself
+		tlsConfig.NextProtos = append(tlsConfig.NextProtos, "acme-tls/1")
+		return tlsConfig, manager.HTTPHandler(redirect), nil
+	default:
+		return nil, nil, nil
+	}
+}
+
+// RedirectToHTTPS permanently redirects requests to the same URL over HTTPS on httpsPort, keeping
+// their method
+func RedirectToHTTPS(httpsPort string) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		host := r.Host
+		if h, _, err := net.SplitHostPort(host); err == nil {
+			host = h
+		}
+		if httpsPort != "" && httpsPort != "443" {
+			host = net.JoinHostPort(host, httpsPort)
+		}
+		target := "https://" + host + r.URL.RequestURI()
+		http.Redirect(w, r, target, http.StatusPermanentRedirect)
+	})
+}
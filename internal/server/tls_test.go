@@ -0,0 +1,106 @@
+package server
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/limistah/wallet-service/internal/config"
+)
+
+// writeCertificate writes a self-signed certificate for the loopback address and its key to dir
+func writeCertificate(t *testing.T, dir string) (certFile, keyFile string) {
+	t.Helper()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatalf("Failed to generate key: %v", err)
+	}
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: "localhost"},
+		DNSNames:     []string{"localhost"},
+		IPAddresses:  []net.IP{net.IPv4(127, 0, 0, 1)},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		t.Fatalf("Failed to create certificate: %v", err)
+	}
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	if err != nil {
+		t.Fatalf("Failed to encode key: %v", err)
+	}
+
+	certFile, keyFile = filepath.Join(dir, "cert.pem"), filepath.Join(dir, "key.pem")
+	os.WriteFile(certFile, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}), 0o600)
+	os.WriteFile(keyFile, pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER}), 0o600)
+	return certFile, keyFile
+}
+
+func TestNewTLS_ServesHTTP2WithCertificateFiles(t *testing.T) {
+	certFile, keyFile := writeCertificate(t, t.TempDir())
+	tlsConfig, _, err := NewTLS(config.ServerConfig{Port: "8443", TLSCertFile: certFile, TLSKeyFile: keyFile})
+	if err != nil {
+		t.Fatalf("NewTLS() error = %v", err)
+	}
+	if tlsConfig.MinVersion != tls.VersionTLS12 {
+		t.Errorf("Expected TLS 1.2 as the minimum version, got %x", tlsConfig.MinVersion)
+	}
+
+	server := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(r.Proto))
+	}))
+	server.TLS = tlsConfig
+	server.EnableHTTP2 = true
+	server.StartTLS()
+	defer server.Close()
+
+	client := server.Client()
+	resp, err := client.Get(server.URL)
+	if err != nil {
+		t.Fatalf("Request failed: %v", err)
+	}
+	defer resp.Body.Close()
+	if resp.ProtoMajor != 2 {
+		t.Errorf("Expected HTTP/2 to be negotiated, got %s", resp.Proto)
+	}
+
+	if _, _, err := NewTLS(config.ServerConfig{TLSCertFile: certFile, TLSKeyFile: filepath.Join(t.TempDir(), "missing.pem")}); err == nil {
+		t.Error("Expected a missing key to fail")
+	}
+	if tlsConfig, _, err := NewTLS(config.ServerConfig{}); tlsConfig != nil || err != nil {
+		t.Errorf("Expected plain HTTP without certificates, got %v, %v", tlsConfig, err)
+	}
+}
+
+func TestRedirectToHTTPS(t *testing.T) {
+	tests := []struct {
+		port, host, want string
+	}{
+		{"443", "wallet.example.com", "https://wallet.example.com/api/v1/wallets?page=2"},
+		{"443", "wallet.example.com:80", "https://wallet.example.com/api/v1/wallets?page=2"},
+		{"8443", "wallet.example.com:8080", "https://wallet.example.com:8443/api/v1/wallets?page=2"},
+	}
+	for _, tt := range tests {
+		req := httptest.NewRequest(http.MethodPost, "http://"+tt.host+"/api/v1/wallets?page=2", nil)
+		rec := httptest.NewRecorder()
+		RedirectToHTTPS(tt.port).ServeHTTP(rec, req)
+
+		if rec.Code != http.StatusPermanentRedirect || rec.Header().Get("Location") != tt.want {
+			t.Errorf("Redirect of %s = %d %q, want 308 %q", tt.host, rec.Code, rec.Header().Get("Location"), tt.want)
+		}
+	}
+}
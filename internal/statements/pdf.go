@@ -0,0 +1,141 @@
+package statements
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// The PDF is laid out as monospaced text on A4 pages
+const (
+	pdfPageWidth    = 595
+	pdfPageHeight   = 842
+	pdfMargin       = 40
+	pdfFontSize     = 8
+	pdfLeading      = 11
+	pdfLinesPerPage = (pdfPageHeight - 2*pdfMargin) / pdfLeading
+)
+
+// RenderPDF writes the statement as a printable document with a header, a summary of the period and a
+// table of transactions spread over as many pages as needed
+func RenderPDF(doc *Document) ([]byte, error) {
+	summary := doc.Summarize()
+	lines := []string{
+		"ACCOUNT STATEMENT",
+		"",
+		fmt.Sprintf("Account holder: %s <%s>", doc.AccountName, doc.AccountEmail),
+		fmt.Sprintf("Wallet:         %d (%s)", doc.WalletID, doc.Currency),
+		fmt.Sprintf("Period:         %s to %s", doc.PeriodStart.Format("2006-01-02"), doc.PeriodEnd.AddDate(0, 0, -1).Format("2006-01-02")),
+		fmt.Sprintf("Generated:      %s", doc.GeneratedAt.UTC().Format("2006-01-02 15:04 MST")),
+		"",
+	}
+	if summary.HasBalances {
+		lines = append(lines, fmt.Sprintf("Opening balance: %15s", summary.OpeningBalance.StringFixed(2)))
+	}
+	lines = append(lines,
+		fmt.Sprintf("Total credits:   %15s", summary.TotalCredits.StringFixed(2)),
+		fmt.Sprintf("Total debits:    %15s", summary.TotalDebits.StringFixed(2)),
+	)
+	if summary.HasBalances {
+		lines = append(lines, fmt.Sprintf("Closing balance: %15s", summary.ClosingBalance.StringFixed(2)))
+	}
+	lines = append(lines, "")
+
+	header := fmt.Sprintf("%-16s  %-24s  %-24s  %-6s  %13s  %13s  %-9s", "Date", "Reference", "Description", "Type", "Amount", "Balance", "Status")
+	rule := strings.Repeat("-", len(header))
+	lines = append(lines, header, rule)
+	if len(doc.Transactions) == 0 {
+		lines = append(lines, "No transactions in this period.")
+	}
+	for i := range doc.Transactions {
+		transaction := &doc.Transactions[i]
+		lines = append(lines, fmt.Sprintf("%-16s  %-24s  %-24s  %-6s  %13s  %13s  %-9s",
+			transaction.CreatedAt.UTC().Format("2006-01-02 15:04"),
+			truncate(transaction.Reference, 24),
+			truncate(transaction.Description, 24),
+			transaction.TransactionType,
+			transaction.Amount.StringFixed(2),
+			transaction.BalanceAfter.StringFixed(2),
+			truncate(string(transaction.Status), 9),
+		))
+	}
+
+	var pages [][]string
+	for len(lines) > pdfLinesPerPage {
+		pages = append(pages, lines[:pdfLinesPerPage])
+		lines = append([]string{header, rule}, lines[pdfLinesPerPage:]...)
+	}
+	pages = append(pages, lines)
+	return writePDF(pages, doc.GeneratedAt), nil
+}
+
+// writePDF lays each page's lines out top to bottom in Courier. Objects are numbered as: 1 catalog,
+// 2 page tree, 3 font, then a page and its content stream for each page
+func writePDF(pages [][]string, created time.Time) []byte {
+	var buf bytes.Buffer
+	offsets := []int{0}
+	object := func(body string) {
+		offsets = append(offsets, buf.Len())
+		fmt.Fprintf(&buf, "%d 0 obj\n%s\nendobj\n", len(offsets)-1, body)
+	}
+
+	buf.WriteString("%PDF-1.4\n")
+	kids := make([]string, len(pages))
+	for i := range pages {
+		kids[i] = fmt.Sprintf("%d 0 R", 4+2*i)
+	}
+	object("<< /Type /Catalog /Pages 2 0 R >>")
+	object(fmt.Sprintf("<< /Type /Pages /Kids [%s] /Count %d >>", strings.Join(kids, " "), len(pages)))
+	object("<< /Type /Font /Subtype /Type1 /BaseFont /Courier /Encoding /WinAnsiEncoding >>")
+
+	for i, lines := range pages {
+		var content bytes.Buffer
+		fmt.Fprintf(&content, "BT\n/F1 %d Tf\n%d TL\n%d %d Td\n", pdfFontSize, pdfLeading, pdfMargin, pdfPageHeight-pdfMargin)
+		for _, line := range lines {
+			fmt.Fprintf(&content, "(%s) '\n", escapePDFText(line))
+		}
+		fmt.Fprintf(&content, "ET\n")
+
+		object(fmt.Sprintf("<< /Type /Page /Parent 2 0 R /MediaBox [0 0 %d %d] /Resources << /Font << /F1 3 0 R >> >> /Contents %d 0 R >>",
+			pdfPageWidth, pdfPageHeight, 5+2*i))
+		object(fmt.Sprintf("<< /Length %d >>\nstream\n%sendstream", content.Len(), content.String()))
+	}
+
+	infoID := len(offsets)
+	object(fmt.Sprintf("<< /Producer (wallet-service) /CreationDate (D:%s) >>", created.UTC().Format("20060102150405Z")))
+
+	xref := buf.Len()
+	fmt.Fprintf(&buf, "xref\n0 %d\n0000000000 65535 f \n", len(offsets))
+	for _, offset := range offsets[1:] {
+		fmt.Fprintf(&buf, "%010d 00000 n \n", offset)
+	}
+	fmt.Fprintf(&buf, "trailer\n<< /Size %d /Root 1 0 R /Info %d 0 R >>\nstartxref\n%d\n%%%%EOF\n", len(offsets), infoID, xref)
+	return buf.Bytes()
+}
+
+// escapePDFText escapes a line for a PDF string literal. The standard fonts only cover Latin
+// characters, so anything outside printable ASCII is shown as a question mark
+func escapePDFText(text string) string {
+	var b strings.Builder
+	for _, r := range text {
+		switch {
+		case r == '(' || r == ')' || r == '\\':
+			b.WriteByte('\\')
+			b.WriteRune(r)
+		case r < 0x20 || r > 0x7e:
+			b.WriteByte('?')
+		default:
+			b.WriteRune(r)
+		}
+	}
+	return b.String()
+}
+
+func truncate(text string, width int) string {
+	runes := []rune(text)
+	if len(runes) <= width {
+		return text
+	}
+	return string(runes[:width-1]) + "~"
+}
@@ -0,0 +1,101 @@
+// Package statements renders account statements for download
+package statements
+
+import (
+	"bytes"
+	"encoding/csv"
+	"fmt"
+	"time"
+
+	"github.com/limistah/wallet-service/internal/models"
+	"github.com/shopspring/decimal"
+)
+
+// Document is an account statement ready to render: a wallet's transactions over a period, oldest
+// first
+type Document struct {
+	AccountName  string
+	AccountEmail string
+	WalletID     uint
+	Currency     string
+	PeriodStart  time.Time
+	PeriodEnd    time.Time // Exclusive
+	GeneratedAt  time.Time
+	Transactions []models.Transaction
+}
+
+// Summary totals what completed in the period. The opening and closing balances are only known when
+// the period has transactions
+type Summary struct {
+	TotalCredits   decimal.Decimal
+	TotalDebits    decimal.Decimal
+	OpeningBalance decimal.Decimal
+	ClosingBalance decimal.Decimal
+	HasBalances    bool
+}
+
+// Summarize totals the completed credits and debits of the statement and reads the balances before
+// its first transaction and after its last
+func (d *Document) Summarize() Summary {
+	summary := Summary{TotalCredits: decimal.Zero, TotalDebits: decimal.Zero}
+	for i := range d.Transactions {
+		transaction := &d.Transactions[i]
+		if !transaction.IsCompleted() {
+			continue
+		}
+		if transaction.TransactionType == models.TransactionTypeCredit {
+			summary.TotalCredits = summary.TotalCredits.Add(transaction.Amount)
+		} else {
+			summary.TotalDebits = summary.TotalDebits.Add(transaction.Amount)
+		}
+	}
+	if len(d.Transactions) > 0 {
+		summary.OpeningBalance = d.Transactions[0].BalanceBefore
+		summary.ClosingBalance = d.Transactions[len(d.Transactions)-1].BalanceAfter
+		summary.HasBalances = true
+	}
+	return summary
+}
+
+// Render writes the statement in the given format
+func Render(doc *Document, format models.AccountStatementFormat) ([]byte, error) {
+	switch format {
+	case models.AccountStatementFormatCSV:
+		return RenderCSV(doc)
+	case models.AccountStatementFormatPDF:
+		return RenderPDF(doc)
+	default:
+		return nil, fmt.Errorf("unsupported statement format %q", format)
+	}
+}
+
+// RenderCSV writes the statement as one row per transaction, for spreadsheets and accounting tools
+func RenderCSV(doc *Document) ([]byte, error) {
+	var buf bytes.Buffer
+	writer := csv.NewWriter(&buf)
+	if err := writer.Write([]string{"date", "reference", "description", "purpose", "type", "amount", "balance_after", "status", "currency"}); err != nil {
+		return nil, err
+	}
+	for i := range doc.Transactions {
+		transaction := &doc.Transactions[i]
+		err := writer.Write([]string{
+			transaction.CreatedAt.UTC().Format(time.RFC3339),
+			transaction.Reference,
+			transaction.Description,
+			string(transaction.TransactionPurpose),
+			string(transaction.TransactionType),
+			transaction.Amount.StringFixed(2),
+			transaction.BalanceAfter.StringFixed(2),
+			string(transaction.Status),
+			doc.Currency,
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+	writer.Flush()
+	if err := writer.Error(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
@@ -0,0 +1,126 @@
+package statements
+
+import (
+	"bytes"
+	"encoding/csv"
+	"fmt"
+	"regexp"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/limistah/wallet-service/internal/models"
+	"github.com/shopspring/decimal"
+)
+
+func testDocument(count int) *Document {
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	doc := &Document{
+		AccountName:  "Ada Lovelace",
+		AccountEmail: "ada@example.com",
+		WalletID:     7,
+		Currency:     "NGN",
+		PeriodStart:  start,
+		PeriodEnd:    start.AddDate(0, 1, 0),
+		GeneratedAt:  start.AddDate(0, 1, 1),
+	}
+	balance := decimal.NewFromInt(1000)
+	for i := 0; i < count; i++ {
+		transaction := models.Transaction{
+			ID:                 uint(i + 1),
+			CreatedAt:          start.Add(time.Duration(i) * time.Hour),
+			Reference:          fmt.Sprintf("TXN-%04d", i+1),
+			Description:        "Transfer (to Bob)",
+			TransactionPurpose: models.TransactionPurposeTransfer,
+			TransactionType:    models.TransactionTypeCredit,
+			Amount:             decimal.NewFromInt(100),
+			BalanceBefore:      balance,
+			Status:             models.TransactionStatusCompleted,
+		}
+		if i%2 == 1 {
+			transaction.TransactionType = models.TransactionTypeDebit
+			transaction.BalanceAfter = balance.Sub(transaction.Amount)
+		} else {
+			transaction.BalanceAfter = balance.Add(transaction.Amount)
+		}
+		balance = transaction.BalanceAfter
+		doc.Transactions = append(doc.Transactions, transaction)
+	}
+	return doc
+}
+
+func TestDocument_Summarize(t *testing.T) {
+	doc := testDocument(3)
+	doc.Transactions[2].Status = models.TransactionStatusPending
+
+	summary := doc.Summarize()
+	if !summary.TotalCredits.Equal(decimal.NewFromInt(100)) || !summary.TotalDebits.Equal(decimal.NewFromInt(100)) {
+		t.Errorf("Expected 100 of completed credits and debits, got %v and %v", summary.TotalCredits, summary.TotalDebits)
+	}
+	if !summary.HasBalances || !summary.OpeningBalance.Equal(decimal.NewFromInt(1000)) || !summary.ClosingBalance.Equal(decimal.NewFromInt(1100)) {
+		t.Errorf("Expected balances from 1000 to 1100, got: %+v", summary)
+	}
+
+	if summary := testDocument(0).Summarize(); summary.HasBalances {
+		t.Error("Expected no balances for a period without transactions")
+	}
+}
+
+func TestRenderCSV(t *testing.T) {
+	content, err := Render(testDocument(2), models.AccountStatementFormatCSV)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	records, err := csv.NewReader(bytes.NewReader(content)).ReadAll()
+	if err != nil {
+		t.Fatalf("Expected valid CSV, got: %v", err)
+	}
+	if len(records) != 3 {
+		t.Fatalf("Expected a header and 2 rows, got %d records", len(records))
+	}
+	if records[2][1] != "TXN-0002" || records[2][4] != "DEBIT" || records[2][5] != "100.00" || records[2][6] != "1000.00" {
+		t.Errorf("Unexpected second row: %v", records[2])
+	}
+}
+
+func TestRenderPDF(t *testing.T) {
+	content, err := Render(testDocument(150), models.AccountStatementFormatPDF)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if !bytes.HasPrefix(content, []byte("%PDF-1.4\n")) || !bytes.HasSuffix(content, []byte("%%EOF\n")) {
+		t.Fatal("Expected a PDF header and trailer")
+	}
+	if !bytes.Contains(content, []byte("TXN-0150")) {
+		t.Error("Expected the last transaction to be rendered")
+	}
+	if !bytes.Contains(content, []byte(`Transfer \(to Bob\)`)) {
+		t.Error("Expected parentheses in text to be escaped")
+	}
+	if pages := regexp.MustCompile(`/Count (\d+)`).FindSubmatch(content); pages == nil || string(pages[1]) != "3" {
+		t.Errorf("Expected 150 transactions to fill 3 pages, got: %s", pages)
+	}
+
+	// Every offset in the cross-reference table must point at the object it lists
+	startxref := regexp.MustCompile(`startxref\n(\d+)\n`).FindSubmatch(content)
+	if startxref == nil {
+		t.Fatal("Expected a startxref")
+	}
+	xref, _ := strconv.Atoi(string(startxref[1]))
+	if !bytes.HasPrefix(content[xref:], []byte("xref\n")) {
+		t.Fatalf("Expected startxref to point at the xref table")
+	}
+	entries := regexp.MustCompile(`(\d{10}) 00000 n `).FindAllSubmatch(content[xref:], -1)
+	for i, entry := range entries {
+		offset, _ := strconv.Atoi(string(entry[1]))
+		if want := fmt.Sprintf("%d 0 obj\n", i+1); !bytes.HasPrefix(content[offset:], []byte(want)) {
+			t.Errorf("Expected object %d at offset %d", i+1, offset)
+		}
+	}
+}
+
+func TestRender_UnsupportedFormat(t *testing.T) {
+	if _, err := Render(testDocument(1), "XLSX"); err == nil {
+		t.Error("Expected an error for an unsupported format")
+	}
+}
@@ -0,0 +1,77 @@
+package storage
+
+import (
+	"errors"
+	"net/url"
+	"strconv"
+	"testing"
+	"time"
+)
+
+func TestLocalStore(t *testing.T) {
+	store := NewLocalStore(t.TempDir())
+
+	if err := store.Put("statements/1/2.csv", []byte("a,b\n")); err != nil {
+		t.Fatalf("Put() error = %v", err)
+	}
+	content, err := store.Get("statements/1/2.csv")
+	if err != nil || string(content) != "a,b\n" {
+		t.Errorf("Get() = %q, %v; want the stored content", content, err)
+	}
+	if _, err := store.Get("statements/1/3.csv"); !errors.Is(err, ErrNotFound) {
+		t.Errorf("Get() of a missing key error = %v, want ErrNotFound", err)
+	}
+	if err := store.Put("../outside.csv", []byte("x")); err == nil {
+		t.Error("Put() with a key leaving the directory should fail")
+	}
+
+	if err := store.Delete("statements/1/2.csv"); err != nil {
+		t.Fatalf("Delete() error = %v", err)
+	}
+	if _, err := store.Get("statements/1/2.csv"); !errors.Is(err, ErrNotFound) {
+		t.Errorf("Get() of a deleted key error = %v, want ErrNotFound", err)
+	}
+	if err := store.Delete("statements/1/2.csv"); err != nil {
+		t.Errorf("Delete() of a missing key error = %v, want nil", err)
+	}
+}
+
+func TestURLSigner(t *testing.T) {
+	now := time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)
+	signer := NewURLSigner("secret", "https://wallet.example.com/")
+	signer.now = func() time.Time { return now }
+
+	link, expires := signer.SignedURL("statements/1/2.csv", 15*time.Minute)
+	if !expires.Equal(now.Add(15 * time.Minute)) {
+		t.Errorf("SignedURL() expiry = %v, want %v", expires, now.Add(15*time.Minute))
+	}
+	parsed, err := url.Parse(link)
+	if err != nil || parsed.Path != DownloadPath || parsed.Host != "wallet.example.com" {
+		t.Fatalf("SignedURL() = %q, want a link to %s", link, DownloadPath)
+	}
+	query := parsed.Query()
+	expiresAt, _ := strconv.ParseInt(query.Get("expires"), 10, 64)
+
+	tests := []struct {
+		name      string
+		key       string
+		expires   int64
+		signature string
+		after     time.Duration
+		want      error
+	}{
+		{"valid", query.Get("key"), expiresAt, query.Get("signature"), 0, nil},
+		{"other key", "statements/1/3.csv", expiresAt, query.Get("signature"), 0, ErrInvalidSignature},
+		{"extended expiry", query.Get("key"), expiresAt + 3600, query.Get("signature"), 0, ErrInvalidSignature},
+		{"malformed signature", query.Get("key"), expiresAt, "zz", 0, ErrInvalidSignature},
+		{"expired", query.Get("key"), expiresAt, query.Get("signature"), 16 * time.Minute, ErrLinkExpired},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			signer.now = func() time.Time { return now.Add(tt.after) }
+			if err := signer.Verify(tt.key, tt.expires, tt.signature); !errors.Is(err, tt.want) {
+				t.Errorf("Verify() error = %v, want %v", err, tt.want)
+			}
+		})
+	}
+}
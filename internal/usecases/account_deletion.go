@@ -0,0 +1,105 @@
+package usecases
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/limistah/wallet-service/internal/apperrors"
+	"github.com/limistah/wallet-service/internal/models"
+)
+
+// accountDeletionBatchSize is how many accounts are anonymized per run of the deletion job
+const accountDeletionBatchSize = 100
+
+// RequestDeletion schedules the account of a user for erasure once they confirm it with their
+// password. Every session is signed out, logins are refused and the wallet is suspended right away;
+// the personal data is removed by AnonymizeDeletedAccounts after the grace period. A wallet still
+// holding money has to be emptied first
+func (uc *userUseCase) RequestDeletion(ctx context.Context, id uint, password string) (*models.User, error) {
+	user, err := uc.repos.User.GetByID(ctx, id)
+	if err != nil {
+		return nil, apperrors.ErrUserNotFound
+	}
+	if user.IsDeletionRequested() {
+		return user, nil
+	}
+	if err := user.CheckPassword(password); err != nil {
+		return nil, apperrors.ErrIncorrectPassword
+	}
+	if user.IsSystemAccount() {
+		return nil, apperrors.ErrValidation.Withf("system accounts cannot be deleted")
+	}
+
+	wallet, err := uc.repos.Wallet.GetByUserID(ctx, user.ID)
+	if err == nil && wallet.Balance.IsPositive() {
+		return nil, apperrors.ErrAccountHasBalance
+	}
+
+	now := time.Now()
+	user.DeletionRequestedAt = &now
+	user.TokensRevokedAt = &now
+	if err := uc.repos.User.Update(ctx, user); err != nil {
+		return nil, err
+	}
+
+	// Nothing may be paid into a wallet nobody can log in to anymore
+	if wallet != nil && wallet.Status == models.WalletStatusActive {
+		wallet.Status = models.WalletStatusSuspended
+		if err := uc.repos.Wallet.Update(ctx, wallet); err != nil {
+			log.Printf("Failed to suspend wallet %d of deleted user %d: %v", wallet.ID, user.ID, err)
+		}
+	}
+	return user, nil
+}
+
+// AnonymizeDeletedAccounts removes the personal data of users who asked for deletion before the
+// cutoff: their profile, avatar, linked sign in providers and login history. Their wallets are
+// closed, and wallets and transactions are kept for regulatory retention. Returns how many accounts
+// were anonymized
+func (uc *userUseCase) AnonymizeDeletedAccounts(ctx context.Context, before time.Time) (int, error) {
+	users, err := uc.repos.User.ListDeletionsDue(ctx, before, accountDeletionBatchSize)
+	if err != nil {
+		return 0, fmt.Errorf("failed to load accounts due for deletion: %w", err)
+	}
+
+	anonymized := 0
+	for i := range users {
+		if err := uc.anonymize(ctx, &users[i]); err != nil {
+			log.Printf("Failed to anonymize user %d: %v", users[i].ID, err)
+			continue
+		}
+		anonymized++
+	}
+	return anonymized, nil
+}
+
+// anonymize erases the data kept besides the user record before the record itself, so an account
+// that fails halfway is picked up again on the next run
+func (uc *userUseCase) anonymize(ctx context.Context, user *models.User) error {
+	if user.HasAvatar() && uc.avatarStore != nil {
+		if err := uc.avatarStore.Delete(user.AvatarKey); err != nil {
+			return fmt.Errorf("failed to delete avatar: %w", err)
+		}
+	}
+	if err := uc.repos.UserIdentity.DeleteByUser(ctx, user.ID); err != nil {
+		return fmt.Errorf("failed to unlink sign in providers: %w", err)
+	}
+	if err := uc.repos.LoginAttempt.DeleteByUser(ctx, user.ID); err != nil {
+		return fmt.Errorf("failed to delete login history: %w", err)
+	}
+	if err := uc.repos.PasswordHistory.Prune(ctx, user.ID, 0); err != nil {
+		return fmt.Errorf("failed to delete password history: %w", err)
+	}
+
+	if wallet, err := uc.repos.Wallet.GetByUserID(ctx, user.ID); err == nil && wallet.Status != models.WalletStatusClosed {
+		wallet.Status = models.WalletStatusClosed
+		if err := uc.repos.Wallet.Update(ctx, wallet); err != nil {
+			return fmt.Errorf("failed to close wallet: %w", err)
+		}
+	}
+
+	user.Anonymize(time.Now())
+	return uc.repos.User.Update(ctx, user)
+}
@@ -0,0 +1,90 @@
+package usecases
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/limistah/wallet-service/internal/apperrors"
+	"github.com/limistah/wallet-service/internal/models"
+	"github.com/limistah/wallet-service/internal/repositories"
+	"github.com/limistah/wallet-service/internal/repositories/memory"
+	"github.com/limistah/wallet-service/internal/storage"
+	"github.com/shopspring/decimal"
+)
+
+func TestUserUseCase_AccountDeletion(t *testing.T) {
+	userRepo := memory.NewUserRepository()
+	walletRepo := memory.NewWalletRepository()
+	identityRepo := memory.NewUserIdentityRepository()
+	now := time.Now()
+	attemptRepo := memory.NewLoginAttemptRepository(&now)
+	repos := &repositories.Repositories{
+		User:            userRepo,
+		Wallet:          walletRepo,
+		UserIdentity:    identityRepo,
+		LoginAttempt:    attemptRepo,
+		PasswordHistory: memory.NewPasswordHistoryRepository(),
+	}
+	avatars := storage.NewMemoryStore()
+	uc := NewUserUseCase(repos, WithAvatarStorage(avatars, 1024))
+
+	user := &models.User{ID: 2, Name: "Ada Lovelace", Email: "ada@example.com", PhoneNumber: "+2348012345678", City: "Lagos", AvatarKey: avatarKey(2)}
+	if err := user.HashPassword("password123"); err != nil {
+		t.Fatalf("Failed to hash password: %v", err)
+	}
+	userRepo.Create(context.Background(), user)
+	wallet := &models.Wallet{ID: 2, UserID: 2, Balance: decimal.NewFromInt(5), Currency: "USD", Status: models.WalletStatusActive}
+	walletRepo.Create(context.Background(), wallet)
+	identityRepo.Create(context.Background(), &models.UserIdentity{UserID: 2, Provider: "google", Subject: "123", Email: "ada@example.com"})
+	attemptRepo.Create(context.Background(), &models.LoginAttempt{UserID: &user.ID, Email: user.Email, IPAddress: "10.0.0.1", Succeeded: true})
+	avatars.Put(avatarKey(2), []byte("\x89PNG\r\n\x1a\n"))
+
+	if _, err := uc.RequestDeletion(context.Background(), 2, "wrong-password"); !errors.Is(err, apperrors.ErrIncorrectPassword) {
+		t.Errorf("Expected ErrIncorrectPassword, got %v", err)
+	}
+	if _, err := uc.RequestDeletion(context.Background(), 2, "password123"); !errors.Is(err, apperrors.ErrAccountHasBalance) {
+		t.Errorf("Expected ErrAccountHasBalance while the wallet holds money, got %v", err)
+	}
+
+	wallet.Balance = decimal.Zero
+	walletRepo.Update(context.Background(), wallet)
+	user, err := uc.RequestDeletion(context.Background(), 2, "password123")
+	if err != nil {
+		t.Fatalf("RequestDeletion() error = %v", err)
+	}
+	if !user.IsDeletionRequested() || user.TokensRevokedAt == nil {
+		t.Errorf("Expected the deletion to be recorded and every token revoked, got %+v", user)
+	}
+	if wallet, _ := walletRepo.GetByID(context.Background(), 2); wallet.Status != models.WalletStatusSuspended {
+		t.Errorf("Expected the wallet to be suspended, got %s", wallet.Status)
+	}
+
+	// Nothing is erased before the grace period ends
+	if anonymized, err := uc.AnonymizeDeletedAccounts(context.Background(), now.Add(-time.Hour)); err != nil || anonymized != 0 {
+		t.Fatalf("AnonymizeDeletedAccounts() = %d, %v; want nothing due yet", anonymized, err)
+	}
+
+	anonymized, err := uc.AnonymizeDeletedAccounts(context.Background(), time.Now())
+	if err != nil || anonymized != 1 {
+		t.Fatalf("AnonymizeDeletedAccounts() = %d, %v; want 1", anonymized, err)
+	}
+	user, _ = userRepo.GetByID(context.Background(), 2)
+	if user.Name != models.DeletedUserName || user.Email == "ada@example.com" || user.PhoneNumber != "" || user.City != "" || user.HasAvatar() || user.AnonymizedAt == nil {
+		t.Errorf("Expected the personal data to be erased, got %+v", user)
+	}
+	if _, err := avatars.Get(avatarKey(2)); !errors.Is(err, storage.ErrNotFound) {
+		t.Errorf("Expected the avatar to be deleted, got %v", err)
+	}
+	if len(identityRepo.Identities()) != 0 || len(attemptRepo.Attempts()) != 0 {
+		t.Errorf("Expected linked providers and login history to be deleted, got %d and %d", len(identityRepo.Identities()), len(attemptRepo.Attempts()))
+	}
+	if wallet, _ := walletRepo.GetByUserID(context.Background(), 2); wallet == nil || wallet.Status != models.WalletStatusClosed {
+		t.Errorf("Expected the wallet to be kept and closed, got %+v", wallet)
+	}
+
+	if anonymized, _ := uc.AnonymizeDeletedAccounts(context.Background(), time.Now()); anonymized != 0 {
+		t.Errorf("Expected anonymized accounts to be left alone, got %d", anonymized)
+	}
+}
@@ -0,0 +1,240 @@
+package usecases
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"sort"
+	"time"
+
+	"github.com/limistah/wallet-service/internal/apperrors"
+	"github.com/limistah/wallet-service/internal/models"
+	"github.com/limistah/wallet-service/internal/queue"
+	"github.com/limistah/wallet-service/internal/repositories"
+	"github.com/limistah/wallet-service/internal/statements"
+	"github.com/limistah/wallet-service/internal/storage"
+	"gorm.io/gorm"
+)
+
+// maxStatementPeriod caps how far a single account statement reaches
+const maxStatementPeriod = 366 * 24 * time.Hour
+
+// StatementDownload is a signed link to a rendered account statement
+type StatementDownload struct {
+	URL       string    `json:"url"`
+	ExpiresAt time.Time `json:"expires_at"`
+}
+
+type accountStatementUseCase struct {
+	repos  *repositories.Repositories
+	jobs   queue.Enqueuer
+	store  storage.BlobStore
+	signer *storage.URLSigner
+	urlTTL time.Duration
+}
+
+// NewAccountStatementUseCase creates a new account statement use case
+func NewAccountStatementUseCase(repos *repositories.Repositories, opts ...Option) AccountStatementUseCase {
+	o := newOptions(opts)
+	return &accountStatementUseCase{
+		repos:  repos,
+		jobs:   o.jobs,
+		store:  o.statementStore,
+		signer: o.statementSigner,
+		urlTTL: o.statementURLTTL,
+	}
+}
+
+// RequestStatement records a statement of the user's wallet from the first day through the last and
+// queues it to be rendered in the background
+func (uc *accountStatementUseCase) RequestStatement(ctx context.Context, userID uint, from, to time.Time, format models.AccountStatementFormat) (*models.AccountStatement, error) {
+	if uc.store == nil || uc.signer == nil {
+		return nil, apperrors.ErrStatementsNotConfigured
+	}
+	if format != models.AccountStatementFormatCSV && format != models.AccountStatementFormatPDF {
+		return nil, apperrors.ErrValidation.Withf("format must be CSV or PDF")
+	}
+	periodStart := startOfDay(from)
+	periodEnd := startOfDay(to).AddDate(0, 0, 1)
+	if !periodEnd.After(periodStart) {
+		return nil, apperrors.ErrValidation.Withf("statement period must end on or after its start")
+	}
+	if periodStart.After(time.Now()) {
+		return nil, apperrors.ErrValidation.Withf("statement period cannot start in the future")
+	}
+	if periodEnd.Sub(periodStart) > maxStatementPeriod {
+		return nil, apperrors.ErrValidation.Withf("statement period cannot be longer than %d days", int(maxStatementPeriod.Hours()/24))
+	}
+
+	wallet, err := uc.repos.Wallet.GetByUserID(ctx, userID)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, apperrors.ErrWalletNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	statement := &models.AccountStatement{
+		UserID:      userID,
+		WalletID:    wallet.ID,
+		PeriodStart: periodStart,
+		PeriodEnd:   periodEnd,
+		Format:      format,
+		Status:      models.AccountStatementStatusPending,
+	}
+	if err := uc.repos.AccountStatement.Create(ctx, statement); err != nil {
+		return nil, fmt.Errorf("failed to save statement: %w", err)
+	}
+	if err := uc.jobs.Enqueue(JobGenerateStatement, generateStatementJob{StatementID: statement.ID}); err != nil {
+		return nil, fmt.Errorf("failed to queue statement: %w", err)
+	}
+	return statement, nil
+}
+
+// GetStatement returns one of the user's statements, with a link to download it once it is ready
+func (uc *accountStatementUseCase) GetStatement(ctx context.Context, userID, statementID uint) (*models.AccountStatement, *StatementDownload, error) {
+	statement, err := uc.repos.AccountStatement.GetByID(ctx, statementID)
+	if err != nil || statement.UserID != userID {
+		return nil, nil, apperrors.ErrAccountStatementNotFound
+	}
+	if statement.Status != models.AccountStatementStatusReady || uc.signer == nil {
+		return statement, nil, nil
+	}
+	url, expiresAt := uc.signer.SignedURL(statement.BlobKey, uc.urlTTL)
+	return statement, &StatementDownload{URL: url, ExpiresAt: expiresAt}, nil
+}
+
+func (uc *accountStatementUseCase) ListStatements(ctx context.Context, userID uint, page, pageSize int) ([]models.AccountStatement, error) {
+	if page < 1 {
+		page = 1
+	}
+	if pageSize < 1 || pageSize > 100 {
+		pageSize = 20
+	}
+	return uc.repos.AccountStatement.ListByUserID(ctx, userID, (page-1)*pageSize, pageSize)
+}
+
+// GenerateStatement renders a pending statement from the wallet's live and archived transactions and
+// stores the file. Storage failures are returned so the job is retried; a statement that cannot be
+// rendered at all is marked failed
+func (uc *accountStatementUseCase) GenerateStatement(ctx context.Context, statementID uint) error {
+	statement, err := uc.repos.AccountStatement.GetByID(ctx, statementID)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return queue.Permanent(apperrors.ErrAccountStatementNotFound)
+	}
+	if err != nil {
+		return err
+	}
+	if statement.Status != models.AccountStatementStatusPending {
+		return nil
+	}
+	if uc.store == nil {
+		return uc.failStatement(ctx, statement, apperrors.ErrStatementsNotConfigured)
+	}
+
+	user, err := uc.repos.User.GetByID(ctx, statement.UserID)
+	if err != nil {
+		return uc.failStatement(ctx, statement, fmt.Errorf("failed to load account holder: %w", err))
+	}
+	wallet, err := uc.repos.Wallet.GetByID(ctx, statement.WalletID)
+	if err != nil {
+		return uc.failStatement(ctx, statement, fmt.Errorf("failed to load wallet: %w", err))
+	}
+	transactions, err := uc.statementTransactions(ctx, statement)
+	if err != nil {
+		return err
+	}
+
+	doc := &statements.Document{
+		AccountName:  user.Name,
+		AccountEmail: user.Email,
+		WalletID:     wallet.ID,
+		Currency:     wallet.Currency,
+		PeriodStart:  statement.PeriodStart,
+		PeriodEnd:    statement.PeriodEnd,
+		GeneratedAt:  time.Now(),
+		Transactions: transactions,
+	}
+	content, err := statements.Render(doc, statement.Format)
+	if err != nil {
+		return uc.failStatement(ctx, statement, err)
+	}
+
+	key := fmt.Sprintf("statements/%d/%d/%s", statement.UserID, statement.ID, statement.FileName())
+	if err := uc.store.Put(key, content); err != nil {
+		return fmt.Errorf("failed to store statement: %w", err)
+	}
+
+	completedAt := time.Now()
+	statement.Status = models.AccountStatementStatusReady
+	statement.BlobKey = key
+	statement.FileSize = int64(len(content))
+	statement.TransactionCount = len(transactions)
+	statement.CompletedAt = &completedAt
+	if err := uc.repos.AccountStatement.Update(ctx, statement); err != nil {
+		return fmt.Errorf("failed to update statement: %w", err)
+	}
+	return nil
+}
+
+// OpenDownload checks a signed download link and returns the file it points at
+func (uc *accountStatementUseCase) OpenDownload(ctx context.Context, key string, expires int64, signature string) ([]byte, error) {
+	if uc.store == nil || uc.signer == nil {
+		return nil, apperrors.ErrStatementsNotConfigured
+	}
+	if err := uc.signer.Verify(key, expires, signature); err != nil {
+		return nil, apperrors.ErrInvalidDownloadLink
+	}
+	content, err := uc.store.Get(key)
+	if errors.Is(err, storage.ErrNotFound) {
+		return nil, apperrors.ErrAccountStatementNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read statement: %w", err)
+	}
+	return content, nil
+}
+
+// statementTransactions returns the wallet's transactions over the statement period oldest first,
+// including the ones already moved to the archive
+func (uc *accountStatementUseCase) statementTransactions(ctx context.Context, statement *models.AccountStatement) ([]models.Transaction, error) {
+	transactions, err := uc.repos.Transaction.GetByWalletIDBetween(ctx, statement.WalletID, statement.PeriodStart, statement.PeriodEnd)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load transactions: %w", err)
+	}
+	archived, err := uc.repos.ArchivedTransaction.GetByWalletIDBetween(ctx, statement.WalletID, statement.PeriodStart, statement.PeriodEnd)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load archived transactions: %w", err)
+	}
+	if len(archived) == 0 {
+		return transactions, nil
+	}
+	for i := range archived {
+		transactions = append(transactions, archived[i].ToTransaction())
+	}
+	sort.SliceStable(transactions, func(i, j int) bool {
+		if !transactions[i].CreatedAt.Equal(transactions[j].CreatedAt) {
+			return transactions[i].CreatedAt.Before(transactions[j].CreatedAt)
+		}
+		return transactions[i].ID < transactions[j].ID
+	})
+	return transactions, nil
+}
+
+// failStatement marks a statement that cannot be rendered as failed and stops its job from retrying
+func (uc *accountStatementUseCase) failStatement(ctx context.Context, statement *models.AccountStatement, cause error) error {
+	log.Printf("Account statement %d failed: %v", statement.ID, cause)
+	statement.Status = models.AccountStatementStatusFailed
+	statement.FailureReason = cause.Error()
+	if err := uc.repos.AccountStatement.Update(ctx, statement); err != nil {
+		return fmt.Errorf("failed to update statement: %w", err)
+	}
+	return queue.Permanent(cause)
+}
+
+// startOfDay returns midnight UTC of t's date
+func startOfDay(t time.Time) time.Time {
+	year, month, day := t.Date()
+	return time.Date(year, month, day, 0, 0, 0, 0, time.UTC)
+}
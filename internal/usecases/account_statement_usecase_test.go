@@ -0,0 +1,151 @@
+package usecases
+
+import (
+	"context"
+	"errors"
+	"net/url"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/limistah/wallet-service/internal/apperrors"
+	"github.com/limistah/wallet-service/internal/models"
+	"github.com/limistah/wallet-service/internal/repositories/memory"
+	"github.com/limistah/wallet-service/internal/storage"
+	"github.com/shopspring/decimal"
+)
+
+// recordingEnqueuer keeps the jobs it was asked to enqueue
+type recordingEnqueuer struct {
+	jobTypes []string
+	payloads []interface{}
+}
+
+func (e *recordingEnqueuer) Enqueue(jobType string, payload interface{}) error {
+	e.jobTypes = append(e.jobTypes, jobType)
+	e.payloads = append(e.payloads, payload)
+	return nil
+}
+
+func TestAccountStatementUseCase_RequestStatement(t *testing.T) {
+	repos, _ := setupTestEnvironment()
+	repos.AccountStatement = memory.NewAccountStatementRepository()
+	repos.User.Create(context.Background(), &models.User{ID: 2, Name: "Ada Lovelace", Email: "ada@example.com"})
+	repos.Wallet.Create(context.Background(), &models.Wallet{ID: 2, UserID: 2, Currency: "NGN", Status: models.WalletStatusActive})
+	jan1 := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	unconfigured := NewAccountStatementUseCase(repos)
+	if _, err := unconfigured.RequestStatement(context.Background(), 2, jan1, jan1, models.AccountStatementFormatCSV); !errors.Is(err, apperrors.ErrStatementsNotConfigured) {
+		t.Errorf("Expected statements not configured, got: %v", err)
+	}
+
+	jobs := &recordingEnqueuer{}
+	statementUC := NewAccountStatementUseCase(repos, WithJobQueue(jobs),
+		WithStatementStorage(storage.NewMemoryStore(), storage.NewURLSigner("secret", ""), time.Minute))
+
+	tests := []struct {
+		name     string
+		from, to time.Time
+		format   models.AccountStatementFormat
+	}{
+		{"end before start", jan1.AddDate(0, 1, 0), jan1, models.AccountStatementFormatCSV},
+		{"longer than a year", jan1, jan1.AddDate(1, 1, 0), models.AccountStatementFormatCSV},
+		{"future", time.Now().AddDate(0, 0, 2), time.Now().AddDate(0, 0, 3), models.AccountStatementFormatCSV},
+		{"unknown format", jan1, jan1, "XLSX"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := statementUC.RequestStatement(context.Background(), 2, tt.from, tt.to, tt.format); !errors.Is(err, apperrors.ErrValidation) {
+				t.Errorf("Expected a validation error, got: %v", err)
+			}
+		})
+	}
+
+	statement, err := statementUC.RequestStatement(context.Background(), 2, jan1.Add(15*time.Hour), jan1.AddDate(0, 0, 30), models.AccountStatementFormatPDF)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if statement.Status != models.AccountStatementStatusPending || statement.WalletID != 2 {
+		t.Errorf("Expected a pending statement of wallet 2, got: %+v", statement)
+	}
+	if !statement.PeriodStart.Equal(jan1) || !statement.PeriodEnd.Equal(jan1.AddDate(0, 1, 0)) {
+		t.Errorf("Expected the period to cover January, got %v to %v", statement.PeriodStart, statement.PeriodEnd)
+	}
+	if len(jobs.jobTypes) != 1 || jobs.jobTypes[0] != JobGenerateStatement || jobs.payloads[0] != (generateStatementJob{StatementID: statement.ID}) {
+		t.Errorf("Expected one statement job for statement %d, got %v %v", statement.ID, jobs.jobTypes, jobs.payloads)
+	}
+}
+
+func TestAccountStatementUseCase_GenerateStatement(t *testing.T) {
+	repos, _ := setupTestEnvironment()
+	statements := memory.NewAccountStatementRepository()
+	repos.AccountStatement = statements
+	repos.User.Create(context.Background(), &models.User{ID: 2, Name: "Ada Lovelace", Email: "ada@example.com"})
+	repos.Wallet.Create(context.Background(), &models.Wallet{ID: 2, UserID: 2, Currency: "NGN", Status: models.WalletStatusActive})
+	jan1 := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	record := func(reference string, at time.Time) {
+		repos.Transaction.Create(context.Background(), &models.Transaction{
+			CreatedAt: at, Reference: reference, WalletID: 2, Amount: decimal.NewFromInt(100),
+			TransactionType: models.TransactionTypeCredit, TransactionPurpose: models.TransactionPurposeWalletTopUp,
+			Status: models.TransactionStatusCompleted,
+		})
+	}
+	record("TXN-JAN", jan1.AddDate(0, 0, 9))
+	record("TXN-FEB", jan1.AddDate(0, 1, 1))
+	repos.ArchivedTransaction.(*memory.ArchivedTransactionRepository).Add(models.ArchivedTransaction{
+		ID: 100, CreatedAt: jan1.AddDate(0, 0, 4), Reference: "TXN-ARCHIVED", WalletID: 2, Amount: decimal.NewFromInt(50),
+		TransactionType: models.TransactionTypeCredit, Status: models.TransactionStatusCompleted,
+	})
+
+	signer := storage.NewURLSigner("secret", "https://wallet.example.com")
+	statementUC := NewAccountStatementUseCase(repos, WithStatementStorage(storage.NewMemoryStore(), signer, time.Minute))
+	statement, err := statementUC.RequestStatement(context.Background(), 2, jan1, jan1.AddDate(0, 0, 30), models.AccountStatementFormatCSV)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if _, download, err := statementUC.GetStatement(context.Background(), 2, statement.ID); err != nil || download != nil {
+		t.Errorf("Expected no download link before the statement is ready, got %+v, %v", download, err)
+	}
+	if _, _, err := statementUC.GetStatement(context.Background(), 3, statement.ID); !errors.Is(err, apperrors.ErrAccountStatementNotFound) {
+		t.Errorf("Expected another user's statement to be not found, got: %v", err)
+	}
+
+	if err := statementUC.GenerateStatement(context.Background(), statement.ID); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	generated, download, err := statementUC.GetStatement(context.Background(), 2, statement.ID)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if generated.Status != models.AccountStatementStatusReady || generated.TransactionCount != 2 || generated.CompletedAt == nil {
+		t.Errorf("Expected a ready statement of 2 transactions, got: %+v", generated)
+	}
+	if download == nil || !strings.HasPrefix(download.URL, "https://wallet.example.com/api/v1/downloads?") {
+		t.Fatalf("Expected a signed download link, got: %+v", download)
+	}
+	if err := statementUC.GenerateStatement(context.Background(), statement.ID); err != nil {
+		t.Errorf("Expected generating a ready statement again to do nothing, got: %v", err)
+	}
+
+	link, _ := url.Parse(download.URL)
+	query := link.Query()
+	expires, _ := strconv.ParseInt(query.Get("expires"), 10, 64)
+	content, err := statementUC.OpenDownload(context.Background(), query.Get("key"), expires, query.Get("signature"))
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	archived, live := strings.Index(string(content), "TXN-ARCHIVED"), strings.Index(string(content), "TXN-JAN")
+	if archived < 0 || live < archived || strings.Contains(string(content), "TXN-FEB") {
+		t.Errorf("Expected the archived and live January transactions in order, got:\n%s", content)
+	}
+
+	if _, err := statementUC.OpenDownload(context.Background(), query.Get("key"), expires+60, query.Get("signature")); !errors.Is(err, apperrors.ErrInvalidDownloadLink) {
+		t.Errorf("Expected a tampered link to be refused, got: %v", err)
+	}
+	if err := statementUC.GenerateStatement(context.Background(), 99); err == nil {
+		t.Error("Expected an error for an unknown statement")
+	}
+}
@@ -0,0 +1,179 @@
+package usecases
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/limistah/wallet-service/internal/apperrors"
+	"github.com/limistah/wallet-service/internal/models"
+	"github.com/limistah/wallet-service/internal/repositories"
+	"github.com/limistah/wallet-service/internal/utils"
+	"github.com/shopspring/decimal"
+	"gorm.io/gorm"
+)
+
+// BalanceAdjustmentInput is a manual credit or debit of a wallet requested by an admin
+type BalanceAdjustmentInput struct {
+	WalletID    uint
+	Type        models.TransactionType
+	Amount      decimal.Decimal
+	Reason      string
+	RequestedBy uint
+}
+
+type adjustmentUseCase struct {
+	repos *repositories.Repositories
+}
+
+// NewAdjustmentUseCase creates a new balance adjustment use case
+func NewAdjustmentUseCase(repos *repositories.Repositories) AdjustmentUseCase {
+	return &adjustmentUseCase{repos: repos}
+}
+
+// RequestAdjustment records a manual adjustment of a wallet balance awaiting the approval of a second
+// admin; nothing is posted yet
+func (uc *adjustmentUseCase) RequestAdjustment(ctx context.Context, input BalanceAdjustmentInput) (*models.BalanceAdjustment, error) {
+	if input.Type != models.TransactionTypeCredit && input.Type != models.TransactionTypeDebit {
+		return nil, apperrors.ErrValidation.Withf("invalid adjustment type %q", input.Type)
+	}
+	if !input.Amount.IsPositive() {
+		return nil, apperrors.ErrInvalidAmount
+	}
+	input.Reason = strings.TrimSpace(input.Reason)
+	if input.Reason == "" {
+		return nil, apperrors.ErrValidation.Withf("reason is required")
+	}
+
+	wallet, err := uc.repos.Wallet.GetByID(ctx, input.WalletID)
+	if err != nil {
+		return nil, apperrors.ErrWalletNotFound
+	}
+	if !wallet.IsActive() {
+		return nil, apperrors.ErrWalletInactive
+	}
+	owner, err := uc.repos.User.GetByID(ctx, wallet.UserID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load wallet owner: %w", err)
+	}
+	if owner.IsSystemAccount() {
+		return nil, apperrors.ErrValidation.Withf("ledger account wallets are adjusted with journal entries")
+	}
+
+	adjustment := &models.BalanceAdjustment{
+		WalletID:    wallet.ID,
+		Type:        input.Type,
+		Amount:      input.Amount,
+		Currency:    wallet.Currency,
+		Reason:      input.Reason,
+		RequestedBy: input.RequestedBy,
+		Status:      models.BalanceAdjustmentStatusPending,
+	}
+	if err := uc.repos.BalanceAdjustment.Create(ctx, adjustment); err != nil {
+		return nil, fmt.Errorf("failed to save balance adjustment: %w", err)
+	}
+	return adjustment, nil
+}
+
+func (uc *adjustmentUseCase) ListAdjustments(ctx context.Context, status models.BalanceAdjustmentStatus, page, pageSize int) ([]models.BalanceAdjustment, error) {
+	if page < 1 {
+		page = 1
+	}
+	if pageSize < 1 || pageSize > 100 {
+		pageSize = 20
+	}
+	return uc.repos.BalanceAdjustment.List(ctx, status, (page-1)*pageSize, pageSize)
+}
+
+func (uc *adjustmentUseCase) GetAdjustment(ctx context.Context, adjustmentID uint) (*models.BalanceAdjustment, error) {
+	adjustment, err := uc.repos.BalanceAdjustment.GetByID(ctx, adjustmentID)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, apperrors.ErrAdjustmentNotFound
+	}
+	return adjustment, err
+}
+
+// ApproveAdjustment posts a pending adjustment as a journal entry between the wallet and the
+// adjustments account. Tier limits do not apply: the adjustment corrects the balance. A debit the
+// wallet cannot cover, or a credit the adjustments account has not been funded for, fails and leaves
+// the adjustment pending
+func (uc *adjustmentUseCase) ApproveAdjustment(ctx context.Context, adjustmentID, approverID uint, note string) (*models.BalanceAdjustment, error) {
+	adjustment, err := uc.pendingAdjustment(ctx, adjustmentID, approverID)
+	if err != nil {
+		return nil, err
+	}
+	wallet, err := uc.repos.Wallet.GetByID(ctx, adjustment.WalletID)
+	if err != nil {
+		return nil, apperrors.ErrWalletNotFound
+	}
+	account, err := uc.repos.LedgerAccount.GetByCode(ctx, models.LedgerAccountAdjustments, wallet.Sandbox)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, apperrors.ErrLedgerAccountNotFound.Withf("ledger account %s not found", models.LedgerAccountAdjustments)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to load adjustments account: %w", err)
+	}
+
+	accountType := models.TransactionTypeDebit
+	if adjustment.Type == models.TransactionTypeDebit {
+		accountType = models.TransactionTypeCredit
+	}
+	description := "Manual adjustment: " + adjustment.Reason
+	entry := &models.JournalEntry{Reference: "ADJ-" + utils.GenerateTransactionReference(), Description: description, PostedBy: &approverID}
+	legs := []postingLeg{
+		{WalletID: wallet.ID, Type: adjustment.Type, Amount: adjustment.Amount, Purpose: models.TransactionPurposeAdjustment, Description: description},
+		{WalletID: account.WalletID, Type: accountType, Amount: adjustment.Amount, Purpose: models.TransactionPurposeAdjustment, Description: description},
+	}
+	now := time.Now()
+	err = uc.repos.UnitOfWork.Do(ctx, func(tx *repositories.Repositories) error {
+		if err := postJournalEntry(ctx, tx, entry, legs); err != nil {
+			return err
+		}
+		approved, err := tx.BalanceAdjustment.Approve(ctx, adjustment.ID, approverID, note, entry.ID, now)
+		if err != nil {
+			return fmt.Errorf("failed to update balance adjustment: %w", err)
+		}
+		if !approved {
+			return apperrors.ErrAdjustmentDecided
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return uc.repos.BalanceAdjustment.GetByID(ctx, adjustment.ID)
+}
+
+// RejectAdjustment declines a pending adjustment; nothing was posted for it
+func (uc *adjustmentUseCase) RejectAdjustment(ctx context.Context, adjustmentID, approverID uint, note string) (*models.BalanceAdjustment, error) {
+	adjustment, err := uc.pendingAdjustment(ctx, adjustmentID, approverID)
+	if err != nil {
+		return nil, err
+	}
+	rejected, err := uc.repos.BalanceAdjustment.Reject(ctx, adjustment.ID, approverID, note, time.Now())
+	if err != nil {
+		return nil, fmt.Errorf("failed to update balance adjustment: %w", err)
+	}
+	if !rejected {
+		return nil, apperrors.ErrAdjustmentDecided
+	}
+	return uc.repos.BalanceAdjustment.GetByID(ctx, adjustment.ID)
+}
+
+// pendingAdjustment loads a pending adjustment the admin may decide on: admins decide on the
+// adjustments of other admins, never on their own
+func (uc *adjustmentUseCase) pendingAdjustment(ctx context.Context, adjustmentID, approverID uint) (*models.BalanceAdjustment, error) {
+	adjustment, err := uc.GetAdjustment(ctx, adjustmentID)
+	if err != nil {
+		return nil, err
+	}
+	if adjustment.RequestedBy == approverID {
+		return nil, apperrors.ErrAdjustmentSelfApproval
+	}
+	if !adjustment.IsPending() {
+		return nil, apperrors.ErrAdjustmentDecided
+	}
+	return adjustment, nil
+}
@@ -0,0 +1,120 @@
+package usecases
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/limistah/wallet-service/internal/apperrors"
+	"github.com/limistah/wallet-service/internal/models"
+	"github.com/limistah/wallet-service/internal/repositories/memory"
+	"github.com/shopspring/decimal"
+)
+
+func TestAdjustmentUseCase_RequestAdjustment(t *testing.T) {
+	repos, _ := setupTestEnvironment()
+	repos.BalanceAdjustment = memory.NewBalanceAdjustmentRepository()
+	adjustmentUC := NewAdjustmentUseCase(repos)
+	ctx := context.Background()
+
+	repos.User.Create(ctx, &models.User{ID: 2, Email: "customer@example.com"})
+	repos.Wallet.Create(ctx, &models.Wallet{ID: 2, UserID: 2, Currency: "NGN", Status: models.WalletStatusActive})
+	repos.Wallet.Create(ctx, &models.Wallet{ID: 3, UserID: 2, Currency: "NGN", Status: models.WalletStatusClosed})
+	repos.User.Create(ctx, &models.User{ID: 3, Email: "ledger@example.com", IsSystem: true})
+	repos.Wallet.Create(ctx, &models.Wallet{ID: 4, UserID: 3, Currency: "USD", Status: models.WalletStatusActive})
+
+	input := func(walletID uint, transactionType models.TransactionType, amount int64, reason string) BalanceAdjustmentInput {
+		return BalanceAdjustmentInput{WalletID: walletID, Type: transactionType, Amount: decimal.NewFromInt(amount), Reason: reason, RequestedBy: 9}
+	}
+	cases := []struct {
+		name     string
+		input    BalanceAdjustmentInput
+		expected error
+	}{
+		{"invalid type", input(2, "REFUND", 25, "Duplicate fee"), apperrors.ErrValidation},
+		{"zero amount", input(2, models.TransactionTypeCredit, 0, "Duplicate fee"), apperrors.ErrInvalidAmount},
+		{"missing reason", input(2, models.TransactionTypeCredit, 25, "  "), apperrors.ErrValidation},
+		{"unknown wallet", input(99, models.TransactionTypeCredit, 25, "Duplicate fee"), apperrors.ErrWalletNotFound},
+		{"closed wallet", input(3, models.TransactionTypeCredit, 25, "Duplicate fee"), apperrors.ErrWalletInactive},
+		{"ledger account wallet", input(4, models.TransactionTypeDebit, 25, "Duplicate fee"), apperrors.ErrValidation},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			if _, err := adjustmentUC.RequestAdjustment(ctx, tc.input); !errors.Is(err, tc.expected) {
+				t.Errorf("Expected %v, got: %v", tc.expected, err)
+			}
+		})
+	}
+
+	adjustment, err := adjustmentUC.RequestAdjustment(ctx, input(2, models.TransactionTypeCredit, 25, " Duplicate fee "))
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if !adjustment.IsPending() || adjustment.Currency != "NGN" || adjustment.Reason != "Duplicate fee" || adjustment.JournalEntryID != nil {
+		t.Errorf("Expected a pending NGN adjustment with nothing posted, got %+v", adjustment)
+	}
+	wallet, _ := repos.Wallet.GetByID(ctx, 2)
+	if !wallet.Balance.IsZero() {
+		t.Errorf("Expected the balance untouched until approval, got %s", wallet.Balance)
+	}
+}
+
+func TestAdjustmentUseCase_Decide(t *testing.T) {
+	repos, _ := setupTestEnvironment()
+	adjustments := memory.NewBalanceAdjustmentRepository()
+	repos.BalanceAdjustment = adjustments
+	accounts := memory.NewLedgerAccountRepository(nil)
+	accounts.Create(context.Background(), &models.LedgerAccount{Code: models.LedgerAccountAdjustments, Name: "Manual adjustments", Type: models.LedgerAccountTypeExpense, WalletID: 1})
+	repos.LedgerAccount = accounts
+	adjustmentUC := NewAdjustmentUseCase(repos)
+	ctx := context.Background()
+
+	repos.Wallet.Create(ctx, &models.Wallet{ID: 2, UserID: 2, Currency: "USD", Status: models.WalletStatusActive})
+	pending := func() *models.BalanceAdjustment {
+		adjustment := &models.BalanceAdjustment{WalletID: 2, Type: models.TransactionTypeCredit, Amount: decimal.NewFromInt(25), Reason: "Duplicate fee", RequestedBy: 9}
+		adjustments.Create(ctx, adjustment)
+		return adjustment
+	}
+
+	adjustment := pending()
+	if _, err := adjustmentUC.ApproveAdjustment(ctx, adjustment.ID, 9, ""); !errors.Is(err, apperrors.ErrAdjustmentSelfApproval) {
+		t.Errorf("Expected admins to be refused their own adjustments, got: %v", err)
+	}
+	if _, err := adjustmentUC.RejectAdjustment(ctx, adjustment.ID, 9, ""); !errors.Is(err, apperrors.ErrAdjustmentSelfApproval) {
+		t.Errorf("Expected admins to be refused their own adjustments, got: %v", err)
+	}
+	approved, err := adjustmentUC.ApproveAdjustment(ctx, adjustment.ID, 10, "")
+	if err != nil {
+		t.Fatalf("Expected a second admin to approve, got: %v", err)
+	}
+	if approved.Status != models.BalanceAdjustmentStatusApproved || approved.JournalEntryID == nil {
+		t.Errorf("Expected the adjustment approved with its journal entry, got %+v", approved)
+	}
+	if wallet, _ := repos.Wallet.GetByID(ctx, 2); !wallet.Balance.Equal(decimal.NewFromInt(25)) {
+		t.Errorf("Expected the wallet credited 25, got %s", wallet.Balance)
+	}
+	if _, err := adjustmentUC.RejectAdjustment(ctx, adjustment.ID, 10, ""); !errors.Is(err, apperrors.ErrAdjustmentDecided) {
+		t.Errorf("Expected ErrAdjustmentDecided, got: %v", err)
+	}
+
+	adjustment = pending()
+	rejected, err := adjustmentUC.RejectAdjustment(ctx, adjustment.ID, 10, "Fee was refunded by the provider")
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if rejected.Status != models.BalanceAdjustmentStatusRejected || rejected.ApproverID == nil || *rejected.ApproverID != 10 {
+		t.Errorf("Expected the adjustment rejected by admin 10, got %+v", rejected)
+	}
+	if _, err := adjustmentUC.ApproveAdjustment(ctx, adjustment.ID, 10, ""); !errors.Is(err, apperrors.ErrAdjustmentDecided) {
+		t.Errorf("Expected ErrAdjustmentDecided, got: %v", err)
+	}
+	if _, err := adjustmentUC.GetAdjustment(ctx, 99); !errors.Is(err, apperrors.ErrAdjustmentNotFound) {
+		t.Errorf("Expected ErrAdjustmentNotFound, got: %v", err)
+	}
+
+	pending()
+	listed, err := adjustmentUC.ListAdjustments(ctx, models.BalanceAdjustmentStatusPending, 1, 20)
+	if err != nil || len(listed) != 1 {
+		t.Errorf("Expected one pending adjustment, got %d (%v)", len(listed), err)
+	}
+}
@@ -0,0 +1,254 @@
+package usecases
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"sort"
+	"time"
+
+	"github.com/limistah/wallet-service/internal/apperrors"
+	"github.com/limistah/wallet-service/internal/models"
+	"github.com/limistah/wallet-service/internal/repositories"
+	"gorm.io/gorm"
+)
+
+const (
+	// MinTransactionRetention is the youngest a transaction can be archived at, leaving time for
+	// disputes and provider reconciliation
+	MinTransactionRetention = 90 * 24 * time.Hour
+	// archiveBatchSize caps how many candidate transactions one archive pass loads
+	archiveBatchSize = 500
+	// archiveLinkDepth caps how far an archive pass follows links between transactions; anything
+	// still linked further out stays live
+	archiveLinkDepth = 4
+)
+
+// ArchiveResult reports what an archive run did
+type ArchiveResult struct {
+	Checkpointed int `json:"checkpointed"` // Wallets whose balance checkpoint moved forward
+	Archived     int `json:"archived"`     // Transactions moved to the archive
+}
+
+type archiveUseCase struct {
+	repos *repositories.Repositories
+}
+
+// NewArchiveUseCase creates a new transaction archive use case
+func NewArchiveUseCase(repos *repositories.Repositories) ArchiveUseCase {
+	return &archiveUseCase{repos: repos}
+}
+
+// ArchiveTransactions moves settled transactions created before the cutoff into the archive table.
+// Each wallet's balance checkpoint is moved forward first, and only transactions it covers are
+// archived, so the checkpoint keeps reconciliation correct without them. Transactions that are tied
+// together - transfer legs, fee legs and journal legs - are archived together or not at all, and
+// those a fraud review or AML case points at stay live
+func (uc *archiveUseCase) ArchiveTransactions(ctx context.Context, before time.Time) (*ArchiveResult, error) {
+	if before.After(time.Now().Add(-MinTransactionRetention)) {
+		return nil, apperrors.ErrValidation.Withf("transactions must be kept for at least %d days", int(MinTransactionRetention.Hours()/24))
+	}
+
+	wallets, err := uc.repos.Wallet.GetAllForReconciliation(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load wallets: %w", err)
+	}
+
+	result := &ArchiveResult{}
+	for _, wallet := range wallets {
+		advanced, err := advanceBalanceCheckpoint(ctx, uc.repos, wallet.ID, before)
+		if err != nil {
+			log.Printf("Failed to checkpoint wallet %d: %v", wallet.ID, err)
+			continue
+		}
+		if advanced {
+			result.Checkpointed++
+		}
+	}
+
+	var afterID uint
+	for {
+		candidates, err := uc.repos.Transaction.FindArchivable(ctx, before, afterID, archiveBatchSize)
+		if err != nil {
+			return result, fmt.Errorf("failed to load archivable transactions: %w", err)
+		}
+		if len(candidates) == 0 {
+			return result, nil
+		}
+		afterID = candidates[len(candidates)-1].ID
+
+		archivable, err := uc.collectArchivable(ctx, candidates, before)
+		if err != nil {
+			return result, err
+		}
+		if len(archivable) == 0 {
+			continue
+		}
+		if err := uc.moveToArchive(ctx, archivable); err != nil {
+			return result, err
+		}
+		result.Archived += len(archivable)
+	}
+}
+
+// collectArchivable follows the links out of the candidates and returns the transactions that can be
+// archived: whole linked groups in which every transaction is covered by its wallet's checkpoint,
+// was created before the cutoff and is not under a fraud review or AML case
+func (uc *archiveUseCase) collectArchivable(ctx context.Context, candidates []models.Transaction, before time.Time) ([]models.Transaction, error) {
+	rows := append([]models.Transaction{}, candidates...)
+	blocked := make(map[uint]bool)
+
+	checkpoints := make(map[uint]*models.BalanceCheckpoint)
+	covered := func(t *models.Transaction) (bool, error) {
+		checkpoint, ok := checkpoints[t.WalletID]
+		if !ok {
+			var err error
+			checkpoint, err = uc.repos.BalanceCheckpoint.GetByWalletID(ctx, t.WalletID)
+			if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+				return false, fmt.Errorf("failed to load balance checkpoint: %w", err)
+			}
+			checkpoints[t.WalletID] = checkpoint
+		}
+		return checkpoint != nil && t.ID <= checkpoint.ThroughTransactionID && t.CreatedAt.Before(before), nil
+	}
+
+	for depth := 0; ; depth++ {
+		linked, err := uc.repos.Transaction.FindLinked(ctx, rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load linked transactions: %w", err)
+		}
+		if len(linked) == 0 {
+			break
+		}
+		for i := range linked {
+			ok, err := covered(&linked[i])
+			if err != nil {
+				return nil, err
+			}
+			// Links past the depth limit are not followed, so their groups stay live
+			if !ok || depth == archiveLinkDepth {
+				blocked[linked[i].ID] = true
+			}
+		}
+		rows = append(rows, linked...)
+		if depth == archiveLinkDepth {
+			break
+		}
+	}
+
+	ids := make([]uint, len(rows))
+	for i := range rows {
+		ids[i] = rows[i].ID
+	}
+	referenced, err := uc.repos.Transaction.GetCaseReferencedIDs(ctx, ids)
+	if err != nil {
+		return nil, fmt.Errorf("failed to check case references: %w", err)
+	}
+	for _, id := range referenced {
+		blocked[id] = true
+	}
+
+	return selectArchivable(rows, blocked), nil
+}
+
+// moveToArchive copies the transactions into the archive and deletes them from the live table in one
+// database transaction, recording the move on each wallet's checkpoint
+func (uc *archiveUseCase) moveToArchive(ctx context.Context, transactions []models.Transaction) error {
+	now := time.Now()
+	archived := make([]models.ArchivedTransaction, len(transactions))
+	ids := make([]uint, len(transactions))
+	counts := make(map[uint]int64)
+	newest := make(map[uint]time.Time)
+	for i := range transactions {
+		t := &transactions[i]
+		archived[i] = models.NewArchivedTransaction(t, now)
+		ids[i] = t.ID
+		counts[t.WalletID]++
+		if t.CreatedAt.After(newest[t.WalletID]) {
+			newest[t.WalletID] = t.CreatedAt
+		}
+	}
+
+	return uc.repos.UnitOfWork.Do(ctx, func(tx *repositories.Repositories) error {
+		if err := tx.ArchivedTransaction.Create(ctx, archived); err != nil {
+			return fmt.Errorf("failed to archive transactions: %w", err)
+		}
+		if err := tx.Transaction.DeleteArchived(ctx, ids); err != nil {
+			return fmt.Errorf("failed to delete archived transactions: %w", err)
+		}
+
+		for walletID, count := range counts {
+			checkpoint, err := tx.BalanceCheckpoint.GetByWalletID(ctx, walletID)
+			if err != nil {
+				return fmt.Errorf("failed to load balance checkpoint: %w", err)
+			}
+			checkpoint.ArchivedCount += count
+			if through := newest[walletID]; checkpoint.ArchivedThrough == nil || through.After(*checkpoint.ArchivedThrough) {
+				checkpoint.ArchivedThrough = &through
+			}
+			if err := tx.BalanceCheckpoint.RecordArchived(ctx, checkpoint); err != nil {
+				return fmt.Errorf("failed to update balance checkpoint: %w", err)
+			}
+		}
+		return nil
+	})
+}
+
+// selectArchivable splits the transactions into groups tied together by related transactions and
+// journal entries and returns those of the groups with no blocked member, in ID order. A transaction
+// whose related transaction is not among them is treated as blocked
+func selectArchivable(transactions []models.Transaction, blocked map[uint]bool) []models.Transaction {
+	parent := make(map[uint]uint, len(transactions))
+	var find func(id uint) uint
+	find = func(id uint) uint {
+		if parent[id] != id {
+			parent[id] = find(parent[id])
+		}
+		return parent[id]
+	}
+	union := func(a, b uint) {
+		parent[find(a)] = find(b)
+	}
+
+	byID := make(map[uint]models.Transaction, len(transactions))
+	for _, t := range transactions {
+		byID[t.ID] = t
+		parent[t.ID] = t.ID
+	}
+
+	journals := make(map[uint]uint)
+	orphans := make(map[uint]bool)
+	for _, t := range byID {
+		if t.RelatedTransactionID != nil {
+			if _, ok := byID[*t.RelatedTransactionID]; ok {
+				union(t.ID, *t.RelatedTransactionID)
+			} else {
+				orphans[t.ID] = true
+			}
+		}
+		if t.JournalEntryID != nil {
+			if first, ok := journals[*t.JournalEntryID]; ok {
+				union(t.ID, first)
+			} else {
+				journals[*t.JournalEntryID] = t.ID
+			}
+		}
+	}
+
+	blockedGroups := make(map[uint]bool)
+	for id := range byID {
+		if blocked[id] || orphans[id] {
+			blockedGroups[find(id)] = true
+		}
+	}
+
+	var archivable []models.Transaction
+	for id, t := range byID {
+		if !blockedGroups[find(id)] {
+			archivable = append(archivable, t)
+		}
+	}
+	sort.Slice(archivable, func(i, j int) bool { return archivable[i].ID < archivable[j].ID })
+	return archivable
+}
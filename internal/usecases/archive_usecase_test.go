@@ -0,0 +1,157 @@
+package usecases
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/limistah/wallet-service/internal/apperrors"
+	"github.com/limistah/wallet-service/internal/models"
+	"github.com/limistah/wallet-service/internal/repositories/memory"
+	"github.com/shopspring/decimal"
+	"gorm.io/gorm"
+)
+
+func TestSelectArchivable(t *testing.T) {
+	related := func(id uint) *uint { return &id }
+	transactions := []models.Transaction{
+		// Transfer pair with a fee leg on the debit
+		{ID: 1, RelatedTransactionID: related(2)},
+		{ID: 2, RelatedTransactionID: related(1)},
+		{ID: 3, RelatedTransactionID: related(1)},
+		// Journal legs, one under review
+		{ID: 4, JournalEntryID: related(7)},
+		{ID: 5, JournalEntryID: related(7)},
+		// Deposit on its own
+		{ID: 6},
+		// Leg whose pair was not loaded
+		{ID: 8, RelatedTransactionID: related(20)},
+	}
+
+	archivable := selectArchivable(transactions, map[uint]bool{5: true})
+	var ids []uint
+	for _, transaction := range archivable {
+		ids = append(ids, transaction.ID)
+	}
+	expected := []uint{1, 2, 3, 6}
+	if len(ids) != len(expected) {
+		t.Fatalf("Expected transactions %v to be archivable, got %v", expected, ids)
+	}
+	for i := range expected {
+		if ids[i] != expected[i] {
+			t.Errorf("Expected transactions %v to be archivable, got %v", expected, ids)
+			break
+		}
+	}
+}
+
+func TestArchiveUseCase_ArchiveTransactions(t *testing.T) {
+	repos, _ := setupTestEnvironment()
+	archiveUC := NewArchiveUseCase(repos)
+
+	if _, err := archiveUC.ArchiveTransactions(context.Background(), time.Now().AddDate(0, 0, -30)); !errors.Is(err, apperrors.ErrValidation) {
+		t.Errorf("Expected a cutoff inside the minimum retention to be rejected, got: %v", err)
+	}
+
+	transactionRepo := repos.Transaction.(*memory.TransactionRepository)
+	repos.Wallet.Create(context.Background(), &models.Wallet{ID: 2, UserID: 2, Currency: "USD", Status: models.WalletStatusActive})
+	old := time.Now().AddDate(-2, 0, 0)
+	for _, transaction := range []*models.Transaction{
+		{WalletID: 2, TransactionType: models.TransactionTypeCredit, Amount: decimal.NewFromInt(500), Status: models.TransactionStatusCompleted, CreatedAt: old},
+		{WalletID: 2, TransactionType: models.TransactionTypeDebit, Amount: decimal.NewFromInt(80), Status: models.TransactionStatusCompleted, CreatedAt: old},
+		{WalletID: 2, TransactionType: models.TransactionTypeDebit, Amount: decimal.NewFromInt(40), Status: models.TransactionStatusFailed, CreatedAt: old},
+		{WalletID: 2, TransactionType: models.TransactionTypeDebit, Amount: decimal.NewFromInt(30), Status: models.TransactionStatusPending, CreatedAt: old},
+		{WalletID: 2, TransactionType: models.TransactionTypeCredit, Amount: decimal.NewFromInt(25), Status: models.TransactionStatusCompleted, CreatedAt: time.Now()},
+	} {
+		transactionRepo.Create(context.Background(), transaction)
+	}
+
+	result, err := archiveUC.ArchiveTransactions(context.Background(), time.Now().AddDate(-1, 0, 0))
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if result.Checkpointed != 1 {
+		t.Errorf("Expected 1 wallet to be checkpointed, got %d", result.Checkpointed)
+	}
+
+	// The pending debit stops the checkpoint, so it only covers the first three transactions
+	checkpoint, err := repos.BalanceCheckpoint.GetByWalletID(context.Background(), 2)
+	if err != nil {
+		t.Fatalf("Expected a checkpoint for wallet 2, got: %v", err)
+	}
+	if checkpoint.ThroughTransactionID != 3 || !checkpoint.Balance.Equal(decimal.NewFromInt(420)) {
+		t.Errorf("Expected a checkpoint of 420 through transaction 3, got %v through %d", checkpoint.Balance, checkpoint.ThroughTransactionID)
+	}
+
+	// A second run with nothing newly settled leaves the checkpoint where it is
+	result, err = archiveUC.ArchiveTransactions(context.Background(), time.Now().AddDate(-1, 0, 0))
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if result.Checkpointed != 0 {
+		t.Errorf("Expected no checkpoint to move, got %d", result.Checkpointed)
+	}
+}
+
+func TestReconciliationUseCase_UsesBalanceCheckpoint(t *testing.T) {
+	repos := setupReconciliationTestEnvironment()
+	reconciliationUC := NewReconciliationUseCase(repos)
+	transactionRepo := repos.Transaction.(*memory.TransactionRepository)
+
+	repos.Wallet.Create(context.Background(), &models.Wallet{ID: 3, UserID: 3, Balance: decimal.NewFromInt(1000), Currency: "USD", Status: models.WalletStatusActive})
+	transactionRepo.SetLastID(10) // Transactions up to 10 have been archived
+	transactionRepo.Create(context.Background(), &models.Transaction{WalletID: 3, TransactionType: models.TransactionTypeDebit, Amount: decimal.NewFromInt(250), Status: models.TransactionStatusPending})
+	repos.BalanceCheckpoint.Create(context.Background(), &models.BalanceCheckpoint{WalletID: 3, ThroughTransactionID: 10, Balance: decimal.NewFromInt(1250)})
+
+	report, err := reconciliationUC.PerformWalletReconciliation(context.Background(), 3)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if !report.CalculatedBalance.Equal(decimal.NewFromInt(1000)) || report.Status != models.ReconciliationStatusMatch {
+		t.Errorf("Expected the checkpoint and live transactions to add up to 1000, got %v (%s)", report.CalculatedBalance, report.Status)
+	}
+}
+
+func TestWalletUseCase_HistoryFallsBackToArchive(t *testing.T) {
+	repos, reconciliationUC := setupTestEnvironment()
+	walletUC := NewWalletUseCase(repos, reconciliationUC)
+	transactionRepo := repos.Transaction.(*memory.TransactionRepository)
+	archive := repos.ArchivedTransaction.(*memory.ArchivedTransactionRepository)
+
+	repos.Wallet.Create(context.Background(), &models.Wallet{ID: 4, UserID: 4, Currency: "USD", Status: models.WalletStatusActive})
+	base := time.Now().AddDate(-2, 0, 0)
+	for i := 1; i <= 3; i++ {
+		archive.Add(models.ArchivedTransaction{ID: uint(i), WalletID: 4, Amount: decimal.NewFromInt(10), Status: models.TransactionStatusCompleted, CreatedAt: base.Add(time.Duration(i) * time.Hour)})
+	}
+	archivedThrough := base.Add(3 * time.Hour)
+	repos.BalanceCheckpoint.Create(context.Background(), &models.BalanceCheckpoint{WalletID: 4, ThroughTransactionID: 3, ArchivedCount: 3, ArchivedThrough: &archivedThrough})
+	transactionRepo.SetLastID(3)
+	for i := 0; i < 2; i++ {
+		transactionRepo.Create(context.Background(), &models.Transaction{WalletID: 4, Amount: decimal.NewFromInt(10), Status: models.TransactionStatusCompleted, CreatedAt: time.Now().Add(time.Duration(i) * time.Minute)})
+	}
+
+	page, cursor, err := walletUC.GetTransactionHistory(context.Background(), 4, nil, 3)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(page) != 3 || page[0].ID != 5 || page[1].ID != 4 || page[2].ID != 3 || cursor == nil {
+		t.Fatalf("Expected live transactions 5 and 4 then archived 3 with a next page, got %d transactions", len(page))
+	}
+
+	page, cursor, err = walletUC.GetTransactionHistory(context.Background(), 4, cursor, 3)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(page) != 2 || page[0].ID != 2 || page[1].ID != 1 || cursor != nil {
+		t.Errorf("Expected archived transactions 2 and 1 on the last page, got %d transactions", len(page))
+	}
+
+	transaction, err := walletUC.GetTransaction(context.Background(), 2)
+	if err != nil || transaction.ID != 2 {
+		t.Errorf("Expected archived transaction 2 to be found, got: %v", err)
+	}
+	if _, err := walletUC.GetTransaction(context.Background(), 99); !errors.Is(err, gorm.ErrRecordNotFound) {
+		t.Errorf("Expected a missing transaction to be not found, got: %v", err)
+	}
+}
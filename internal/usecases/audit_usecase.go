@@ -0,0 +1,31 @@
+package usecases
+
+import (
+	"context"
+
+	"github.com/limistah/wallet-service/internal/models"
+	"github.com/limistah/wallet-service/internal/repositories"
+)
+
+type auditUseCase struct {
+	repos *repositories.Repositories
+}
+
+// NewAuditUseCase creates a new audit use case
+func NewAuditUseCase(repos *repositories.Repositories) AuditUseCase {
+	return &auditUseCase{repos: repos}
+}
+
+func (uc *auditUseCase) Record(ctx context.Context, entry *models.AuditLog) error {
+	return uc.repos.AuditLog.Create(ctx, entry)
+}
+
+func (uc *auditUseCase) ListAuditLogs(ctx context.Context, page, pageSize int) ([]models.AuditLog, error) {
+	offset := (page - 1) * pageSize
+	return uc.repos.AuditLog.List(ctx, offset, pageSize)
+}
+
+func (uc *auditUseCase) ListAuditLogsByActor(ctx context.Context, actorID uint, page, pageSize int) ([]models.AuditLog, error) {
+	offset := (page - 1) * pageSize
+	return uc.repos.AuditLog.GetByActorID(ctx, actorID, offset, pageSize)
+}
@@ -0,0 +1,79 @@
+package usecases
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/limistah/wallet-service/internal/apperrors"
+	"github.com/limistah/wallet-service/internal/models"
+	"github.com/limistah/wallet-service/internal/repositories"
+	"gorm.io/gorm"
+)
+
+// MinBalanceCheckpointLag is how old a transaction must be before a checkpoint can cover it, so
+// transactions still being written are never counted
+const MinBalanceCheckpointLag = time.Hour
+
+// CheckpointBalances moves every wallet's balance checkpoint up to its transactions settled before
+// the cutoff, so reconciliation only sums what came after. Returns how many checkpoints moved
+func (uc *reconciliationUseCase) CheckpointBalances(ctx context.Context, before time.Time) (int, error) {
+	if before.After(time.Now().Add(-MinBalanceCheckpointLag)) {
+		return 0, apperrors.ErrValidation.Withf("checkpoints must lag at least %s behind", MinBalanceCheckpointLag)
+	}
+
+	wallets, err := uc.repos.Wallet.GetAllForReconciliation(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("failed to load wallets: %w", err)
+	}
+
+	advanced := 0
+	for _, wallet := range wallets {
+		ok, err := advanceBalanceCheckpoint(ctx, uc.repos, wallet.ID, before)
+		if err != nil {
+			log.Printf("Failed to checkpoint wallet %d: %v", wallet.ID, err)
+			continue
+		}
+		if ok {
+			advanced++
+		}
+	}
+	return advanced, nil
+}
+
+// advanceBalanceCheckpoint moves a wallet's balance checkpoint up to the last of its transactions
+// that had settled before the cutoff, adding what the newly covered transactions bring to the balance
+func advanceBalanceCheckpoint(ctx context.Context, repos *repositories.Repositories, walletID uint, before time.Time) (bool, error) {
+	through, err := repos.Transaction.GetSettledThrough(ctx, walletID, before)
+	if err != nil {
+		return false, fmt.Errorf("failed to find settled transactions: %w", err)
+	}
+
+	checkpoint, err := repos.BalanceCheckpoint.GetByWalletID(ctx, walletID)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		checkpoint = &models.BalanceCheckpoint{WalletID: walletID}
+	} else if err != nil {
+		return false, fmt.Errorf("failed to load balance checkpoint: %w", err)
+	}
+	if through <= checkpoint.ThroughTransactionID {
+		return false, nil
+	}
+
+	delta, err := repos.Transaction.CalculateBalanceBetween(ctx, walletID, checkpoint.ThroughTransactionID, through)
+	if err != nil {
+		return false, fmt.Errorf("failed to calculate checkpoint balance: %w", err)
+	}
+	previous := checkpoint.ThroughTransactionID
+	checkpoint.Balance = checkpoint.Balance.Add(delta)
+	checkpoint.ThroughTransactionID = through
+
+	if checkpoint.ID == 0 {
+		if err := repos.BalanceCheckpoint.Create(ctx, checkpoint); err != nil {
+			return false, fmt.Errorf("failed to save balance checkpoint: %w", err)
+		}
+		return true, nil
+	}
+	return repos.BalanceCheckpoint.Advance(ctx, checkpoint, previous)
+}
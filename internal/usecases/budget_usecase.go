@@ -0,0 +1,236 @@
+package usecases
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/limistah/wallet-service/internal/apperrors"
+	"github.com/limistah/wallet-service/internal/events"
+	"github.com/limistah/wallet-service/internal/fx"
+	"github.com/limistah/wallet-service/internal/models"
+	"github.com/limistah/wallet-service/internal/repositories"
+	"github.com/shopspring/decimal"
+)
+
+// budgetBatchSize bounds how many budgets the evaluator loads at once
+const budgetBatchSize = 100
+
+// maxCategoryLength is the longest spending category a transaction or budget may use
+const maxCategoryLength = 50
+
+// budgetAlertThresholds are the percentages of a budget users are alerted at, lowest first
+var budgetAlertThresholds = []int{80, 100}
+
+// BudgetProgress is a budget with what was spent in its category this month
+type BudgetProgress struct {
+	Budget models.Budget
+	Spent  decimal.Decimal
+	// Display converts the amounts to the display currency of the user for indicative values
+	Display *fx.Conversion
+}
+
+type budgetUseCase struct {
+	repos         *repositories.Repositories
+	publisher     events.EventPublisher
+	exchangeRates fx.RateProvider
+}
+
+// NewBudgetUseCase creates a new budget use case
+func NewBudgetUseCase(repos *repositories.Repositories, opts ...Option) BudgetUseCase {
+	o := newOptions(opts)
+	return &budgetUseCase{
+		repos:         repos,
+		publisher:     o.publisher,
+		exchangeRates: o.exchangeRates,
+	}
+}
+
+func (uc *budgetUseCase) CreateBudget(ctx context.Context, userID uint, category string, amount decimal.Decimal) (*models.Budget, error) {
+	category, err := normalizeCategory(category, false)
+	if err != nil {
+		return nil, err
+	}
+	if amount.LessThanOrEqual(decimal.Zero) {
+		return nil, apperrors.ErrInvalidAmount
+	}
+
+	wallet, err := uc.repos.Wallet.GetByUserID(ctx, userID)
+	if err != nil {
+		return nil, apperrors.ErrWalletNotFound
+	}
+	budgets, err := uc.repos.Budget.ListByUserID(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("error loading budgets: %w", err)
+	}
+	for _, budget := range budgets {
+		if budget.Category == category {
+			return nil, apperrors.ErrBudgetExists
+		}
+	}
+
+	budget := &models.Budget{
+		UserID:   userID,
+		WalletID: wallet.ID,
+		Category: category,
+		Amount:   amount,
+		Currency: wallet.Currency,
+	}
+	if err := uc.repos.Budget.Create(ctx, budget); err != nil {
+		return nil, fmt.Errorf("failed to create budget: %w", err)
+	}
+	return budget, nil
+}
+
+// ListBudgets returns the user's budgets with their spending this month
+func (uc *budgetUseCase) ListBudgets(ctx context.Context, userID uint) ([]BudgetProgress, error) {
+	budgets, err := uc.repos.Budget.ListByUserID(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("error loading budgets: %w", err)
+	}
+
+	monthStart := startOfDebitMonth(time.Now())
+	progress := make([]BudgetProgress, len(budgets))
+	displays := make(map[string]*fx.Conversion)
+	for i, budget := range budgets {
+		spent, err := uc.repos.Transaction.SumCategoryDebitsSince(ctx, budget.WalletID, budget.Category, monthStart)
+		if err != nil {
+			return nil, fmt.Errorf("failed to sum spending: %w", err)
+		}
+		display, ok := displays[budget.Currency]
+		if !ok {
+			display = displayConversion(ctx, uc.repos, uc.exchangeRates, userID, budget.Currency)
+			displays[budget.Currency] = display
+		}
+		progress[i] = BudgetProgress{Budget: budget, Spent: spent, Display: display}
+	}
+	return progress, nil
+}
+
+// UpdateBudget changes the amount of a budget. Alerts already sent this month are sent again if
+// spending crosses a threshold of the new amount
+func (uc *budgetUseCase) UpdateBudget(ctx context.Context, userID, budgetID uint, amount decimal.Decimal) (*models.Budget, error) {
+	if amount.LessThanOrEqual(decimal.Zero) {
+		return nil, apperrors.ErrInvalidAmount
+	}
+	budget, err := uc.userBudget(ctx, userID, budgetID)
+	if err != nil {
+		return nil, err
+	}
+
+	budget.Amount = amount
+	budget.AlertedPercent = 0
+	budget.AlertedPeriod = ""
+	if err := uc.repos.Budget.Update(ctx, budget); err != nil {
+		return nil, fmt.Errorf("failed to update budget: %w", err)
+	}
+	return budget, nil
+}
+
+func (uc *budgetUseCase) DeleteBudget(ctx context.Context, userID, budgetID uint) error {
+	budget, err := uc.userBudget(ctx, userID, budgetID)
+	if err != nil {
+		return err
+	}
+	if err := uc.repos.Budget.Delete(ctx, budget.ID); err != nil {
+		return fmt.Errorf("failed to delete budget: %w", err)
+	}
+	return nil
+}
+
+func (uc *budgetUseCase) EvaluateBudgets(ctx context.Context, now time.Time) (int, error) {
+	period := models.BudgetPeriod(now)
+	monthStart := startOfDebitMonth(now)
+	alerts := 0
+	var afterID uint
+	for {
+		budgets, err := uc.repos.Budget.ListAfter(ctx, afterID, budgetBatchSize)
+		if err != nil {
+			return alerts, fmt.Errorf("error loading budgets: %w", err)
+		}
+		for i := range budgets {
+			alerted, err := uc.evaluate(ctx, &budgets[i], period, monthStart, now)
+			if err != nil {
+				// One budget failing should not hold up the alerts of the others
+				log.Printf("Failed to evaluate budget %d: %v", budgets[i].ID, err)
+				continue
+			}
+			if alerted {
+				alerts++
+			}
+		}
+		if len(budgets) < budgetBatchSize {
+			return alerts, nil
+		}
+		afterID = budgets[len(budgets)-1].ID
+	}
+}
+
+// evaluate alerts the owner of a budget about the highest threshold their spending crossed this month,
+// unless they were alerted about it already
+func (uc *budgetUseCase) evaluate(ctx context.Context, budget *models.Budget, period string, monthStart, now time.Time) (bool, error) {
+	spent, err := uc.repos.Transaction.SumCategoryDebitsSince(ctx, budget.WalletID, budget.Category, monthStart)
+	if err != nil {
+		return false, fmt.Errorf("failed to sum spending: %w", err)
+	}
+
+	alreadyAlerted := 0
+	if budget.AlertedPeriod == period {
+		alreadyAlerted = budget.AlertedPercent
+	}
+	threshold := 0
+	for _, percent := range budgetAlertThresholds {
+		if spent.GreaterThanOrEqual(budget.Amount.Mul(decimal.NewFromInt(int64(percent))).Div(decimal.NewFromInt(100))) {
+			threshold = percent
+		}
+	}
+	if threshold <= alreadyAlerted {
+		return false, nil
+	}
+
+	budget.AlertedPeriod = period
+	budget.AlertedPercent = threshold
+	if err := uc.repos.Budget.Update(ctx, budget); err != nil {
+		return false, fmt.Errorf("failed to record budget alert: %w", err)
+	}
+
+	uc.publisher.Publish(events.Event{
+		Type:     events.EventBudgetThreshold,
+		UserID:   budget.UserID,
+		WalletID: budget.WalletID,
+		Amount:   budget.Amount,
+		Currency: budget.Currency,
+		Data: map[string]string{
+			"category": budget.Category,
+			"percent":  strconv.Itoa(threshold),
+			"spent":    spent.StringFixed(2),
+		},
+		OccurredAt: now,
+	})
+	return true, nil
+}
+
+// userBudget loads a budget of the user; budgets of other users are not found
+func (uc *budgetUseCase) userBudget(ctx context.Context, userID, budgetID uint) (*models.Budget, error) {
+	budget, err := uc.repos.Budget.GetByID(ctx, budgetID)
+	if err != nil || budget.UserID != userID {
+		return nil, apperrors.ErrBudgetNotFound
+	}
+	return budget, nil
+}
+
+// normalizeCategory trims and lower-cases a spending category so "Groceries" and "groceries " are
+// the same; empty categories are only accepted when allowEmpty is set
+func normalizeCategory(category string, allowEmpty bool) (string, error) {
+	category = strings.ToLower(strings.TrimSpace(category))
+	if category == "" && !allowEmpty {
+		return "", apperrors.ErrValidation.Withf("category is required")
+	}
+	if len(category) > maxCategoryLength {
+		return "", apperrors.ErrValidation.Withf("category must be at most %d characters", maxCategoryLength)
+	}
+	return category, nil
+}
@@ -0,0 +1,113 @@
+package usecases
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/limistah/wallet-service/internal/apperrors"
+	"github.com/limistah/wallet-service/internal/events"
+	"github.com/limistah/wallet-service/internal/models"
+	"github.com/limistah/wallet-service/internal/repositories/memory"
+	"github.com/shopspring/decimal"
+)
+
+func TestBudgetUseCase_AlertsOncePerThreshold(t *testing.T) {
+	repos, reconciliationUC := setupTestEnvironment()
+	repos.Budget = memory.NewBudgetRepository()
+	repos.Wallet.Create(context.Background(), &models.Wallet{ID: 2, UserID: 2, Balance: decimal.NewFromInt(1000), Currency: "USD", Status: models.WalletStatusActive})
+	publisher := &recordingPublisher{}
+	budgetUC := NewBudgetUseCase(repos, WithEventPublisher(publisher))
+	walletUC := NewWalletUseCase(repos, reconciliationUC)
+
+	budget, err := budgetUC.CreateBudget(context.Background(), 2, " Groceries ", decimal.NewFromInt(100))
+	if err != nil {
+		t.Fatalf("Unexpected error creating budget: %v", err)
+	}
+	if budget.Category != "groceries" || budget.Currency != "USD" {
+		t.Errorf("Expected a USD budget for groceries, got: %+v", budget)
+	}
+	if _, err := budgetUC.CreateBudget(context.Background(), 2, "GROCERIES", decimal.NewFromInt(50)); !errors.Is(err, apperrors.ErrBudgetExists) {
+		t.Errorf("Expected a second groceries budget to be refused, got: %v", err)
+	}
+
+	now := time.Now().UTC()
+	spend := func(reference string, amount int64) {
+		debit := &models.Transaction{WalletID: 2, Reference: reference, TransactionType: models.TransactionTypeDebit,
+			Amount: decimal.NewFromInt(amount), Status: models.TransactionStatusCompleted, CreatedAt: now}
+		repos.Transaction.Create(context.Background(), debit)
+		if _, err := walletUC.CategorizeTransaction(context.Background(), 2, debit.ID, "groceries"); err != nil {
+			t.Fatalf("Unexpected error categorizing transaction: %v", err)
+		}
+	}
+
+	spend("SHOP-1", 50)
+	if alerts, _ := budgetUC.EvaluateBudgets(context.Background(), now); alerts != 0 {
+		t.Errorf("Expected no alert at 50%%, got %d", alerts)
+	}
+
+	spend("SHOP-2", 35)
+	if alerts, _ := budgetUC.EvaluateBudgets(context.Background(), now); alerts != 1 {
+		t.Fatalf("Expected an alert at 85%%, got %d", alerts)
+	}
+	if alerts, _ := budgetUC.EvaluateBudgets(context.Background(), now); alerts != 0 {
+		t.Errorf("Expected the 80%% alert to be sent once, got %d more", alerts)
+	}
+
+	spend("SHOP-3", 20)
+	if alerts, _ := budgetUC.EvaluateBudgets(context.Background(), now); alerts != 1 {
+		t.Fatalf("Expected an alert at 105%%, got %d", alerts)
+	}
+	if len(publisher.events) != 2 {
+		t.Fatalf("Expected 2 budget alerts, got %d", len(publisher.events))
+	}
+	event := publisher.events[1]
+	if event.Type != events.EventBudgetThreshold || event.UserID != 2 || event.Data["percent"] != "100" || event.Data["spent"] != "105.00" {
+		t.Errorf("Expected a 100%% alert for 105.00 spent, got: %+v", event)
+	}
+
+	progress, err := budgetUC.ListBudgets(context.Background(), 2)
+	if err != nil || len(progress) != 1 || !progress[0].Spent.Equal(decimal.NewFromInt(105)) {
+		t.Errorf("Expected 105 spent on groceries, got %+v, %v", progress, err)
+	}
+
+	// Raising the budget starts alerting again against the new amount
+	if _, err := budgetUC.UpdateBudget(context.Background(), 2, budget.ID, decimal.NewFromInt(130)); err != nil {
+		t.Fatalf("Unexpected error updating budget: %v", err)
+	}
+	if alerts, _ := budgetUC.EvaluateBudgets(context.Background(), now); alerts != 1 || publisher.events[2].Data["percent"] != "80" {
+		t.Errorf("Expected an 80%% alert against the raised budget, got %d alerts", alerts)
+	}
+
+	if err := budgetUC.DeleteBudget(context.Background(), 1, budget.ID); !errors.Is(err, apperrors.ErrBudgetNotFound) {
+		t.Errorf("Expected budgets of other users to be not found, got: %v", err)
+	}
+	if err := budgetUC.DeleteBudget(context.Background(), 2, budget.ID); err != nil {
+		t.Errorf("Unexpected error deleting budget: %v", err)
+	}
+}
+
+func TestWalletUseCase_CategorizeTransaction(t *testing.T) {
+	repos, reconciliationUC := setupTestEnvironment()
+	walletUC := NewWalletUseCase(repos, reconciliationUC)
+
+	debit := &models.Transaction{WalletID: 1, Reference: "SHOP-1", TransactionType: models.TransactionTypeDebit,
+		Amount: decimal.NewFromInt(10), Status: models.TransactionStatusCompleted}
+	repos.Transaction.Create(context.Background(), debit)
+
+	if _, err := walletUC.CategorizeTransaction(context.Background(), 2, debit.ID, "travel"); !errors.Is(err, apperrors.ErrTransactionNotFound) {
+		t.Errorf("Expected transactions of other wallets to be not found, got: %v", err)
+	}
+	if _, err := walletUC.CategorizeTransaction(context.Background(), 1, debit.ID, "this category name is far too long to be a spending category"); !errors.Is(err, apperrors.ErrValidation) {
+		t.Errorf("Expected a long category to be rejected, got: %v", err)
+	}
+
+	transaction, err := walletUC.CategorizeTransaction(context.Background(), 1, debit.ID, "Travel")
+	if err != nil || transaction.Category != "travel" {
+		t.Fatalf("Expected the transaction to be tagged travel, got %+v, %v", transaction, err)
+	}
+	if transaction, err = walletUC.CategorizeTransaction(context.Background(), 1, debit.ID, ""); err != nil || transaction.Category != "" {
+		t.Errorf("Expected an empty category to clear it, got %+v, %v", transaction, err)
+	}
+}
@@ -0,0 +1,368 @@
+package usecases
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/limistah/wallet-service/internal/apperrors"
+	"github.com/limistah/wallet-service/internal/fraud"
+	"github.com/limistah/wallet-service/internal/models"
+	"github.com/limistah/wallet-service/internal/repositories"
+	"github.com/limistah/wallet-service/internal/utils"
+	"github.com/shopspring/decimal"
+	"gorm.io/gorm"
+)
+
+// BusinessPayment is a transfer or, with a bank account, a withdrawal from a business wallet
+type BusinessPayment struct {
+	ToWalletID  uint
+	BankAccount *models.BankAccount
+	Amount      decimal.Decimal
+	Reference   string
+	Description string
+	// PIN is the transaction PIN of the member making the payment, checked when it is made at once
+	PIN string
+}
+
+type businessUseCase struct {
+	repos         *repositories.Repositories
+	walletUseCase WalletUseCase
+	authorizer    debitAuthorizer
+}
+
+// NewBusinessUseCase creates a new business account use case
+func NewBusinessUseCase(repos *repositories.Repositories, walletUseCase WalletUseCase, opts ...Option) BusinessUseCase {
+	o := newOptions(opts)
+	return &businessUseCase{
+		repos:         repos,
+		walletUseCase: walletUseCase,
+		authorizer:    newDebitAuthorizer(repos, o),
+	}
+}
+
+// CreateBusinessAccount opens a business wallet in the currency of the owner's wallet, with the owner
+// as its first approver
+func (uc *businessUseCase) CreateBusinessAccount(ctx context.Context, ownerID uint, name string) (*models.BusinessAccount, error) {
+	name = strings.TrimSpace(name)
+	if name == "" {
+		return nil, apperrors.ErrValidation.Withf("business name is required")
+	}
+	ownerWallet, err := uc.repos.Wallet.GetByUserID(ctx, ownerID)
+	if err != nil {
+		return nil, apperrors.ErrWalletNotFound
+	}
+
+	wallet := &models.Wallet{
+		UserID:   ownerID,
+		Balance:  decimal.Zero,
+		Currency: ownerWallet.Currency,
+		Status:   models.WalletStatusActive,
+	}
+	if err := uc.repos.Wallet.Create(ctx, wallet); err != nil {
+		return nil, fmt.Errorf("failed to create business wallet: %w", err)
+	}
+
+	account := &models.BusinessAccount{Name: name, OwnerID: ownerID, WalletID: wallet.ID}
+	if err := uc.repos.BusinessAccount.Create(ctx, account); err != nil {
+		return nil, fmt.Errorf("failed to create business account: %w", err)
+	}
+	if err := uc.repos.BusinessAccount.AddMember(ctx, &models.BusinessMember{
+		BusinessAccountID: account.ID,
+		UserID:            ownerID,
+		Role:              models.BusinessRoleApprover,
+	}); err != nil {
+		return nil, fmt.Errorf("failed to add business account owner: %w", err)
+	}
+
+	return uc.repos.BusinessAccount.GetByID(ctx, account.ID)
+}
+
+func (uc *businessUseCase) ListBusinessAccounts(ctx context.Context, userID uint) ([]models.BusinessAccount, error) {
+	accounts, err := uc.repos.BusinessAccount.ListByMemberUserID(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list business accounts: %w", err)
+	}
+	return accounts, nil
+}
+
+// GetBusinessAccount returns an account of which the user is a member, with the user's role
+func (uc *businessUseCase) GetBusinessAccount(ctx context.Context, userID, accountID uint) (*models.BusinessAccount, models.BusinessRole, error) {
+	account, member, err := uc.membership(ctx, userID, accountID)
+	if err != nil {
+		return nil, "", err
+	}
+	return account, member.Role, nil
+}
+
+// AddMember gives the user with the email a role on the account; only the owner manages members
+func (uc *businessUseCase) AddMember(ctx context.Context, ownerID, accountID uint, email string, role models.BusinessRole) (*models.BusinessMember, error) {
+	if !role.IsValid() {
+		return nil, apperrors.ErrValidation.Withf("role must be VIEWER, INITIATOR or APPROVER")
+	}
+	account, err := uc.ownedAccount(ctx, ownerID, accountID)
+	if err != nil {
+		return nil, err
+	}
+	user, err := uc.repos.User.GetByEmail(ctx, utils.NormalizeEmail(email))
+	if err != nil || user.IsSystemAccount() {
+		return nil, apperrors.ErrUserNotFound
+	}
+	if _, err := uc.repos.BusinessAccount.GetMember(ctx, account.ID, user.ID); err == nil {
+		return nil, apperrors.ErrBusinessMemberExists
+	}
+
+	member := &models.BusinessMember{BusinessAccountID: account.ID, UserID: user.ID, Role: role}
+	if err := uc.repos.BusinessAccount.AddMember(ctx, member); err != nil {
+		return nil, fmt.Errorf("failed to add business account member: %w", err)
+	}
+	member.User = *user
+	return member, nil
+}
+
+// UpdateMemberRole changes the role of a member other than the owner
+func (uc *businessUseCase) UpdateMemberRole(ctx context.Context, ownerID, accountID, memberUserID uint, role models.BusinessRole) (*models.BusinessMember, error) {
+	if !role.IsValid() {
+		return nil, apperrors.ErrValidation.Withf("role must be VIEWER, INITIATOR or APPROVER")
+	}
+	member, err := uc.otherMember(ctx, ownerID, accountID, memberUserID)
+	if err != nil {
+		return nil, err
+	}
+	member.Role = role
+	if err := uc.repos.BusinessAccount.UpdateMember(ctx, member); err != nil {
+		return nil, fmt.Errorf("failed to update business account member: %w", err)
+	}
+	return member, nil
+}
+
+// RemoveMember takes away the access of a member other than the owner
+func (uc *businessUseCase) RemoveMember(ctx context.Context, ownerID, accountID, memberUserID uint) error {
+	member, err := uc.otherMember(ctx, ownerID, accountID, memberUserID)
+	if err != nil {
+		return err
+	}
+	if err := uc.repos.BusinessAccount.RemoveMember(ctx, member.ID); err != nil {
+		return fmt.Errorf("failed to remove business account member: %w", err)
+	}
+	return nil
+}
+
+// RequestPayment records a payment from the business wallet. A payment of an approver is recorded as
+// approved by them and made at once; a payment of an initiator waits for an approver
+func (uc *businessUseCase) RequestPayment(ctx context.Context, userID, accountID uint, payment BusinessPayment, origin *fraud.Origin) (*models.PaymentApproval, error) {
+	account, member, err := uc.membership(ctx, userID, accountID)
+	if err != nil {
+		return nil, err
+	}
+	if member.Role == models.BusinessRoleViewer {
+		return nil, apperrors.ErrBusinessRoleForbidden
+	}
+	if payment.Amount.LessThanOrEqual(decimal.Zero) {
+		return nil, apperrors.ErrInvalidAmount
+	}
+	payment.Reference = strings.TrimSpace(payment.Reference)
+	if payment.Reference == "" {
+		return nil, apperrors.ErrValidation.Withf("reference is required")
+	}
+	if _, err := uc.repos.PaymentApproval.GetByReference(ctx, payment.Reference); err == nil {
+		return nil, apperrors.ErrDuplicateReference
+	} else if !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, fmt.Errorf("error checking reference: %w", err)
+	}
+
+	approval := &models.PaymentApproval{
+		BusinessAccountID: account.ID,
+		WalletID:          account.WalletID,
+		Purpose:           models.TransactionPurposeTransfer,
+		Amount:            payment.Amount,
+		Currency:          account.Wallet.Currency,
+		Reference:         payment.Reference,
+		Description:       payment.Description,
+		InitiatorID:       userID,
+		Status:            models.PaymentApprovalStatusPending,
+	}
+	if payment.BankAccount != nil {
+		approval.Purpose = models.TransactionPurposeWithdrawal
+		approval.BankAccount = *payment.BankAccount
+	} else {
+		if payment.ToWalletID == 0 {
+			return nil, apperrors.ErrValidation.Withf("a recipient wallet or bank account is required")
+		}
+		approval.CounterpartyWalletID = &payment.ToWalletID
+	}
+
+	if member.Role != models.BusinessRoleApprover {
+		if err := uc.repos.PaymentApproval.Create(ctx, approval); err != nil {
+			return nil, fmt.Errorf("failed to create payment approval: %w", err)
+		}
+		return approval, nil
+	}
+
+	if err := uc.authorizer.authorize(ctx, userID, payment.Amount, payment.PIN); err != nil {
+		return nil, err
+	}
+	// The payment is recorded before it is made so no debit exists without its approval; a refused
+	// payment is recorded as failed like an approved request
+	now := time.Now()
+	approval.Status = models.PaymentApprovalStatusApproved
+	approval.ApproverID = &userID
+	approval.DecidedAt = &now
+	if err := uc.repos.PaymentApproval.Create(ctx, approval); err != nil {
+		return nil, fmt.Errorf("failed to record payment: %w", err)
+	}
+	return uc.execute(ctx, approval, origin)
+}
+
+func (uc *businessUseCase) ListApprovals(ctx context.Context, userID, accountID uint, status models.PaymentApprovalStatus, page, pageSize int) ([]models.PaymentApproval, error) {
+	if _, _, err := uc.membership(ctx, userID, accountID); err != nil {
+		return nil, err
+	}
+	if page < 1 {
+		page = 1
+	}
+	if pageSize < 1 || pageSize > 100 {
+		pageSize = 20
+	}
+	return uc.repos.PaymentApproval.ListByBusinessAccount(ctx, accountID, status, (page-1)*pageSize, pageSize)
+}
+
+// ApprovePayment makes a pending payment. An approval that cannot be paid, such as for insufficient
+// funds, is recorded as failed with the reason and the error is returned. pin is the transaction PIN
+// of the approver, who authorizes the debit
+func (uc *businessUseCase) ApprovePayment(ctx context.Context, userID, accountID, approvalID uint, note, pin string, origin *fraud.Origin) (*models.PaymentApproval, error) {
+	approval, err := uc.pendingApproval(ctx, userID, accountID, approvalID)
+	if err != nil {
+		return nil, err
+	}
+	if err := uc.authorizer.authorize(ctx, userID, approval.Amount, pin); err != nil {
+		return nil, err
+	}
+	if err := uc.decide(ctx, approval, models.PaymentApprovalStatusApproved, userID, note); err != nil {
+		return nil, err
+	}
+	return uc.execute(ctx, approval, origin)
+}
+
+// execute makes an approved payment and records its debit, or records it as failed with the reason
+// and returns the error
+func (uc *businessUseCase) execute(ctx context.Context, approval *models.PaymentApproval, origin *fraud.Origin) (*models.PaymentApproval, error) {
+	transaction, payErr := uc.pay(ctx, approval, origin)
+	if payErr != nil {
+		approval.Status = models.PaymentApprovalStatusFailed
+		approval.FailureReason = payErr.Error()
+	} else {
+		approval.TransactionID = &transaction.ID
+	}
+	if err := uc.repos.PaymentApproval.Update(ctx, approval); err != nil {
+		return nil, fmt.Errorf("failed to update payment approval: %w", err)
+	}
+	if payErr != nil {
+		return approval, payErr
+	}
+	return approval, nil
+}
+
+// RejectPayment declines a pending payment; nothing was debited for it
+func (uc *businessUseCase) RejectPayment(ctx context.Context, userID, accountID, approvalID uint, note string) (*models.PaymentApproval, error) {
+	approval, err := uc.pendingApproval(ctx, userID, accountID, approvalID)
+	if err != nil {
+		return nil, err
+	}
+	if err := uc.decide(ctx, approval, models.PaymentApprovalStatusRejected, userID, note); err != nil {
+		return nil, err
+	}
+	return approval, nil
+}
+
+// pay debits the business wallet for a payment and returns the debit
+func (uc *businessUseCase) pay(ctx context.Context, approval *models.PaymentApproval, origin *fraud.Origin) (*models.Transaction, error) {
+	if approval.Purpose == models.TransactionPurposeWithdrawal {
+		bankAccount := approval.BankAccount
+		debit, _, err := uc.walletUseCase.WithdrawFunds(ctx, approval.WalletID, approval.Amount, approval.Reference, approval.Description, &bankAccount, origin)
+		return debit, err
+	}
+	debit, _, err := uc.walletUseCase.TransferFunds(ctx, approval.WalletID, *approval.CounterpartyWalletID, approval.Amount, approval.Reference, approval.Description, origin)
+	return debit, err
+}
+
+// decide records the decision of an approver, failing when another approver decided first
+func (uc *businessUseCase) decide(ctx context.Context, approval *models.PaymentApproval, status models.PaymentApprovalStatus, approverID uint, note string) error {
+	now := time.Now()
+	decided, err := uc.repos.PaymentApproval.Decide(ctx, approval.ID, status, approverID, note, now)
+	if err != nil {
+		return fmt.Errorf("failed to update payment approval: %w", err)
+	}
+	if !decided {
+		return apperrors.ErrApprovalResolved
+	}
+	approval.Status = status
+	approval.ApproverID = &approverID
+	approval.DecisionNote = note
+	approval.DecidedAt = &now
+	return nil
+}
+
+// pendingApproval loads a pending approval of the account that the user may decide on: approvers
+// decide on the requests of other members, never on their own
+func (uc *businessUseCase) pendingApproval(ctx context.Context, userID, accountID, approvalID uint) (*models.PaymentApproval, error) {
+	_, member, err := uc.membership(ctx, userID, accountID)
+	if err != nil {
+		return nil, err
+	}
+	approval, err := uc.repos.PaymentApproval.GetByID(ctx, approvalID)
+	if err != nil || approval.BusinessAccountID != accountID {
+		return nil, apperrors.ErrApprovalNotFound
+	}
+	if member.Role != models.BusinessRoleApprover || approval.InitiatorID == userID {
+		return nil, apperrors.ErrBusinessRoleForbidden
+	}
+	if !approval.IsPending() {
+		return nil, apperrors.ErrApprovalResolved
+	}
+	return approval, nil
+}
+
+// membership loads an account with the user's membership; accounts the user is not a member of are
+// not found
+func (uc *businessUseCase) membership(ctx context.Context, userID, accountID uint) (*models.BusinessAccount, *models.BusinessMember, error) {
+	member, err := uc.repos.BusinessAccount.GetMember(ctx, accountID, userID)
+	if err != nil {
+		return nil, nil, apperrors.ErrBusinessAccountNotFound
+	}
+	account, err := uc.repos.BusinessAccount.GetByID(ctx, accountID)
+	if err != nil {
+		return nil, nil, apperrors.ErrBusinessAccountNotFound
+	}
+	return account, member, nil
+}
+
+// ownedAccount loads an account the user owns
+func (uc *businessUseCase) ownedAccount(ctx context.Context, ownerID, accountID uint) (*models.BusinessAccount, error) {
+	account, _, err := uc.membership(ctx, ownerID, accountID)
+	if err != nil {
+		return nil, err
+	}
+	if account.OwnerID != ownerID {
+		return nil, apperrors.ErrBusinessRoleForbidden
+	}
+	return account, nil
+}
+
+// otherMember loads a member of an account the user owns, other than the owner
+func (uc *businessUseCase) otherMember(ctx context.Context, ownerID, accountID, memberUserID uint) (*models.BusinessMember, error) {
+	account, err := uc.ownedAccount(ctx, ownerID, accountID)
+	if err != nil {
+		return nil, err
+	}
+	if memberUserID == account.OwnerID {
+		return nil, apperrors.ErrValidation.Withf("the owner's membership cannot be changed")
+	}
+	member, err := uc.repos.BusinessAccount.GetMember(ctx, account.ID, memberUserID)
+	if err != nil {
+		return nil, apperrors.ErrBusinessMemberNotFound
+	}
+	return member, nil
+}
@@ -0,0 +1,147 @@
+package usecases
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/limistah/wallet-service/internal/apperrors"
+	"github.com/limistah/wallet-service/internal/models"
+	"github.com/limistah/wallet-service/internal/repositories/memory"
+	"github.com/shopspring/decimal"
+)
+
+func setupBusinessTest(t *testing.T) (BusinessUseCase, *transferringWalletUseCase, *models.BusinessAccount) {
+	t.Helper()
+	repos, _ := setupTestEnvironment()
+	wallets := repos.Wallet.(*memory.WalletRepository)
+	repos.BusinessAccount = memory.NewBusinessAccountRepository(wallets)
+	repos.PaymentApproval = memory.NewPaymentApprovalRepository()
+
+	for id, email := range map[uint]string{2: "owner@example.com", 3: "viewer@example.com", 4: "initiator@example.com", 5: "approver@example.com"} {
+		repos.User.Create(context.Background(), &models.User{ID: id, Email: email, Name: email})
+		repos.Wallet.Create(context.Background(), &models.Wallet{ID: id, UserID: id, Currency: "NGN", Status: models.WalletStatusActive})
+	}
+
+	walletUC := &transferringWalletUseCase{wallets: wallets}
+	businessUC := NewBusinessUseCase(repos, walletUC)
+	account, err := businessUC.CreateBusinessAccount(context.Background(), 2, " Acme Ltd ")
+	if err != nil {
+		t.Fatalf("Unexpected error creating business account: %v", err)
+	}
+	for email, role := range map[string]models.BusinessRole{
+		"viewer@example.com":    models.BusinessRoleViewer,
+		"initiator@example.com": models.BusinessRoleInitiator,
+		"approver@example.com":  models.BusinessRoleApprover,
+	} {
+		if _, err := businessUC.AddMember(context.Background(), 2, account.ID, email, role); err != nil {
+			t.Fatalf("Unexpected error adding %s: %v", email, err)
+		}
+	}
+	return businessUC, walletUC, account
+}
+
+func TestBusinessUseCase_CreateBusinessAccount(t *testing.T) {
+	businessUC, _, account := setupBusinessTest(t)
+
+	if account.Name != "Acme Ltd" || account.OwnerID != 2 || account.WalletID == 2 || account.Wallet.Currency != "NGN" {
+		t.Errorf("Expected a new NGN wallet owned by the owner, got: %+v", account)
+	}
+	if _, role, err := businessUC.GetBusinessAccount(context.Background(), 2, account.ID); err != nil || role != models.BusinessRoleApprover {
+		t.Errorf("Expected the owner to be an approver, got %q, %v", role, err)
+	}
+	if _, _, err := businessUC.GetBusinessAccount(context.Background(), 1, account.ID); !errors.Is(err, apperrors.ErrBusinessAccountNotFound) {
+		t.Errorf("Expected non-members not to find the account, got: %v", err)
+	}
+
+	// Only the owner manages members, and the owner's own membership is fixed
+	if _, err := businessUC.AddMember(context.Background(), 5, account.ID, "system@wallet.internal", models.BusinessRoleViewer); !errors.Is(err, apperrors.ErrBusinessRoleForbidden) {
+		t.Errorf("Expected members other than the owner to be refused, got: %v", err)
+	}
+	if _, err := businessUC.AddMember(context.Background(), 2, account.ID, "viewer@example.com", models.BusinessRoleApprover); !errors.Is(err, apperrors.ErrBusinessMemberExists) {
+		t.Errorf("Expected an existing member to be refused, got: %v", err)
+	}
+	if _, err := businessUC.UpdateMemberRole(context.Background(), 2, account.ID, 2, models.BusinessRoleViewer); !errors.Is(err, apperrors.ErrValidation) {
+		t.Errorf("Expected the owner's role to be fixed, got: %v", err)
+	}
+	if member, err := businessUC.UpdateMemberRole(context.Background(), 2, account.ID, 3, models.BusinessRoleInitiator); err != nil || member.Role != models.BusinessRoleInitiator {
+		t.Errorf("Expected the viewer to become an initiator, got %+v, %v", member, err)
+	}
+	if err := businessUC.RemoveMember(context.Background(), 2, account.ID, 3); err != nil {
+		t.Fatalf("Unexpected error removing member: %v", err)
+	}
+	if _, _, err := businessUC.GetBusinessAccount(context.Background(), 3, account.ID); !errors.Is(err, apperrors.ErrBusinessAccountNotFound) {
+		t.Errorf("Expected a removed member to lose access, got: %v", err)
+	}
+}
+
+func TestBusinessUseCase_PaymentApprovals(t *testing.T) {
+	businessUC, walletUC, account := setupBusinessTest(t)
+	payment := BusinessPayment{ToWalletID: 3, Amount: decimal.NewFromInt(500), Reference: "INV-1"}
+
+	if _, err := businessUC.RequestPayment(context.Background(), 3, account.ID, payment, nil); !errors.Is(err, apperrors.ErrBusinessRoleForbidden) {
+		t.Errorf("Expected viewers not to pay, got: %v", err)
+	}
+
+	// An initiator's payment waits for approval and debits nothing
+	approval, err := businessUC.RequestPayment(context.Background(), 4, account.ID, payment, nil)
+	if err != nil {
+		t.Fatalf("Unexpected error requesting payment: %v", err)
+	}
+	if !approval.IsPending() || approval.Currency != "NGN" || len(walletUC.transfers) != 0 {
+		t.Fatalf("Expected a pending NGN payment without a transfer, got %+v, transfers %v", approval, walletUC.transfers)
+	}
+	if _, err := businessUC.RequestPayment(context.Background(), 4, account.ID, payment, nil); !errors.Is(err, apperrors.ErrDuplicateReference) {
+		t.Errorf("Expected a reused reference to be refused, got: %v", err)
+	}
+	if _, err := businessUC.ApprovePayment(context.Background(), 4, account.ID, approval.ID, "", "", nil); !errors.Is(err, apperrors.ErrBusinessRoleForbidden) {
+		t.Errorf("Expected initiators not to approve, got: %v", err)
+	}
+
+	approved, err := businessUC.ApprovePayment(context.Background(), 5, account.ID, approval.ID, "ok", "", nil)
+	if err != nil {
+		t.Fatalf("Unexpected error approving payment: %v", err)
+	}
+	if approved.Status != models.PaymentApprovalStatusApproved || *approved.ApproverID != 5 || approved.TransactionID == nil || len(walletUC.transfers) != 1 {
+		t.Errorf("Expected the approval to make the transfer, got %+v, transfers %v", approved, walletUC.transfers)
+	}
+	if _, err := businessUC.RejectPayment(context.Background(), 2, account.ID, approval.ID, ""); !errors.Is(err, apperrors.ErrApprovalResolved) {
+		t.Errorf("Expected a decided payment to stay decided, got: %v", err)
+	}
+
+	// Approvers do not approve their own requests, but their payments are made at once
+	payment.Reference = "INV-2"
+	direct, err := businessUC.RequestPayment(context.Background(), 5, account.ID, payment, nil)
+	if err != nil || direct.Status != models.PaymentApprovalStatusApproved || len(walletUC.transfers) != 2 {
+		t.Errorf("Expected an approver's payment to be made at once, got %+v, %v", direct, err)
+	}
+
+	// A rejected request is never paid, and a failed payment records why
+	payment.Reference = "INV-3"
+	rejected, _ := businessUC.RequestPayment(context.Background(), 4, account.ID, payment, nil)
+	if rejected, err := businessUC.RejectPayment(context.Background(), 2, account.ID, rejected.ID, "not ours"); err != nil || rejected.Status != models.PaymentApprovalStatusRejected {
+		t.Errorf("Expected the payment to be rejected, got %+v, %v", rejected, err)
+	}
+	payment.Reference = "INV-4"
+	failing, _ := businessUC.RequestPayment(context.Background(), 4, account.ID, payment, nil)
+	walletUC.transferErr = apperrors.ErrInsufficientFunds
+	failed, err := businessUC.ApprovePayment(context.Background(), 2, account.ID, failing.ID, "", "", nil)
+	if !errors.Is(err, apperrors.ErrInsufficientFunds) || failed.Status != models.PaymentApprovalStatusFailed || failed.FailureReason == "" {
+		t.Errorf("Expected the payment to fail with the reason, got %+v, %v", failed, err)
+	}
+	if len(walletUC.transfers) != 2 {
+		t.Errorf("Expected only the approved payments to be transferred, got %v", walletUC.transfers)
+	}
+
+	// An approver's payment is recorded before it is made, so a refused one stays as failed
+	payment.Reference = "INV-5"
+	refused, err := businessUC.RequestPayment(context.Background(), 5, account.ID, payment, nil)
+	if !errors.Is(err, apperrors.ErrInsufficientFunds) || refused.ID == 0 || refused.Status != models.PaymentApprovalStatusFailed {
+		t.Errorf("Expected the refused payment to be recorded as failed, got %+v, %v", refused, err)
+	}
+
+	pending, _ := businessUC.ListApprovals(context.Background(), 3, account.ID, models.PaymentApprovalStatusPending, 1, 20)
+	if len(pending) != 0 {
+		t.Errorf("Expected no pending approvals, got %d", len(pending))
+	}
+}
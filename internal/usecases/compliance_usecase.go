@@ -0,0 +1,187 @@
+package usecases
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strconv"
+	"strings"
+
+	"github.com/limistah/wallet-service/internal/apperrors"
+	"github.com/limistah/wallet-service/internal/compliance"
+	"github.com/limistah/wallet-service/internal/models"
+	"github.com/limistah/wallet-service/internal/repositories"
+	"github.com/shopspring/decimal"
+	"gorm.io/gorm"
+)
+
+type complianceUseCase struct {
+	repos *repositories.Repositories
+}
+
+// NewComplianceUseCase creates a new compliance use case
+func NewComplianceUseCase(repos *repositories.Repositories) ComplianceUseCase {
+	return &complianceUseCase{repos: repos}
+}
+
+func (uc *complianceUseCase) CreateBlocklistEntry(ctx context.Context, entry *models.BlocklistEntry) (*models.BlocklistEntry, error) {
+	entry.Value = models.BlocklistValue(entry.Type, entry.Value)
+	if entry.Value == "" {
+		return nil, apperrors.ErrValidation.Withf("value is required")
+	}
+
+	switch entry.Type {
+	case models.BlocklistEntryEmail:
+		if !strings.Contains(entry.Value, "@") {
+			return nil, apperrors.ErrValidation.Withf("invalid email address")
+		}
+	case models.BlocklistEntryWallet:
+		if id, err := strconv.ParseUint(entry.Value, 10, 64); err != nil || id == 0 {
+			return nil, apperrors.ErrValidation.Withf("invalid wallet ID")
+		}
+	case models.BlocklistEntryBankAccount:
+		bankCode, accountNumber, found := strings.Cut(entry.Value, ":")
+		if !found || bankCode == "" || accountNumber == "" {
+			return nil, apperrors.ErrValidation.Withf("bank accounts must be written as BANKCODE:ACCOUNTNUMBER")
+		}
+	default:
+		return nil, apperrors.ErrValidation.Withf("invalid blocklist entry type")
+	}
+
+	if _, err := uc.repos.Blocklist.GetByValue(ctx, entry.Type, entry.Value); err == nil {
+		return nil, apperrors.ErrAlreadyBlocklisted
+	} else if err != gorm.ErrRecordNotFound {
+		return nil, fmt.Errorf("failed to check blocklist: %w", err)
+	}
+
+	entry.ID = 0
+	if err := uc.repos.Blocklist.Create(ctx, entry); err != nil {
+		return nil, fmt.Errorf("failed to create blocklist entry: %w", err)
+	}
+	return entry, nil
+}
+
+func (uc *complianceUseCase) ListBlocklistEntries(ctx context.Context, entryType models.BlocklistEntryType, page, pageSize int) ([]models.BlocklistEntry, error) {
+	if page < 1 {
+		page = 1
+	}
+	if pageSize < 1 || pageSize > 100 {
+		pageSize = 20
+	}
+	return uc.repos.Blocklist.List(ctx, entryType, (page-1)*pageSize, pageSize)
+}
+
+func (uc *complianceUseCase) DeleteBlocklistEntry(ctx context.Context, id uint) error {
+	if err := uc.repos.Blocklist.Delete(ctx, id); err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return apperrors.ErrBlocklistEntryNotFound
+		}
+		return fmt.Errorf("failed to delete blocklist entry: %w", err)
+	}
+	return nil
+}
+
+func (uc *complianceUseCase) ListComplianceLogs(ctx context.Context, check models.ComplianceCheck, page, pageSize int) ([]models.ComplianceLog, error) {
+	if page < 1 {
+		page = 1
+	}
+	if pageSize < 1 || pageSize > 100 {
+		pageSize = 20
+	}
+	return uc.repos.ComplianceLog.List(ctx, check, (page-1)*pageSize, pageSize)
+}
+
+// matchBlocklist returns the first blocklist entry matching one of the wallets, the email of its
+// owner or the bank account, or nil when none matches
+func matchBlocklist(ctx context.Context, repos *repositories.Repositories, wallets []*models.Wallet, bankAccount *models.BankAccount) (*models.BlocklistEntry, error) {
+	type candidate struct {
+		entryType models.BlocklistEntryType
+		value     string
+	}
+
+	var candidates []candidate
+	for _, wallet := range wallets {
+		candidates = append(candidates, candidate{models.BlocklistEntryWallet, strconv.FormatUint(uint64(wallet.ID), 10)})
+		if wallet.User.Email != "" {
+			candidates = append(candidates, candidate{models.BlocklistEntryEmail, models.BlocklistValue(models.BlocklistEntryEmail, wallet.User.Email)})
+		}
+	}
+	if bankAccount != nil && bankAccount.AccountNumber != "" {
+		candidates = append(candidates, candidate{models.BlocklistEntryBankAccount, bankAccount.BlocklistValue()})
+	}
+
+	for _, c := range candidates {
+		entry, err := repos.Blocklist.GetByValue(ctx, c.entryType, c.value)
+		if err == nil {
+			return entry, nil
+		}
+		if err != gorm.ErrRecordNotFound {
+			return nil, fmt.Errorf("failed to check blocklist: %w", err)
+		}
+	}
+	return nil, nil
+}
+
+// enforceBlocklist refuses a debit when the wallet, the counterparty wallet or the bank account is
+// blocklisted, and records the refusal in the compliance log for review
+func enforceBlocklist(ctx context.Context, repos *repositories.Repositories, purpose models.TransactionPurpose, reference string, amount decimal.Decimal, wallet, counterparty *models.Wallet, bankAccount *models.BankAccount) error {
+	wallets := []*models.Wallet{wallet}
+	if counterparty != nil {
+		wallets = append(wallets, counterparty)
+	}
+
+	entry, err := matchBlocklist(ctx, repos, wallets, bankAccount)
+	if err != nil {
+		return err
+	}
+	if entry == nil {
+		return nil
+	}
+
+	record := &models.ComplianceLog{
+		Check:     models.ComplianceCheckBlocklist,
+		UserID:    &wallet.UserID,
+		WalletID:  &wallet.ID,
+		Purpose:   purpose,
+		Amount:    amount,
+		Reference: reference,
+		Subject:   fmt.Sprintf("%s %s", entry.Type, entry.Value),
+		Reason:    entry.Reason,
+	}
+	if counterparty != nil {
+		record.CounterpartyWalletID = &counterparty.ID
+	}
+	if err := repos.ComplianceLog.Create(ctx, record); err != nil {
+		log.Printf("Failed to record blocklist match for %s: %v", reference, err)
+	}
+
+	return apperrors.ErrComplianceBlocked
+}
+
+// screenSanctions checks a person against the sanctions watchlist and records a match in the
+// compliance log on top of the details already in record. A nil checker disables screening
+func screenSanctions(ctx context.Context, repos *repositories.Repositories, checker compliance.SanctionsChecker, subject compliance.SanctionsSubject, record models.ComplianceLog) error {
+	if checker == nil {
+		return nil
+	}
+
+	match, err := checker.Check(subject)
+	if err != nil {
+		return fmt.Errorf("sanctions screening failed: %w", err)
+	}
+	if match == nil {
+		return nil
+	}
+
+	record.Check = models.ComplianceCheckSanctions
+	record.Subject = subject.Name
+	if subject.Email != "" {
+		record.Subject = fmt.Sprintf("%s <%s>", subject.Name, subject.Email)
+	}
+	record.Reason = fmt.Sprintf("%s matched %s entry %s: %s", checker.Name(), match.List, match.EntryID, match.Name)
+	if err := repos.ComplianceLog.Create(ctx, &record); err != nil {
+		log.Printf("Failed to record sanctions match for %s: %v", record.Subject, err)
+	}
+
+	return apperrors.ErrSanctionsMatch
+}
@@ -0,0 +1,143 @@
+package usecases
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/limistah/wallet-service/internal/apperrors"
+	"github.com/limistah/wallet-service/internal/compliance"
+	"github.com/limistah/wallet-service/internal/models"
+	"github.com/shopspring/decimal"
+)
+
+func TestComplianceUseCase_CreateBlocklistEntry(t *testing.T) {
+	repos, _ := setupTestEnvironment()
+	complianceUC := NewComplianceUseCase(repos)
+
+	entry, err := complianceUC.CreateBlocklistEntry(context.Background(), &models.BlocklistEntry{Type: models.BlocklistEntryEmail, Value: " Mule@Example.com "})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if entry.Value != "mule@example.com" {
+		t.Errorf("Expected the email to be normalized, got %q", entry.Value)
+	}
+
+	entry, err = complianceUC.CreateBlocklistEntry(context.Background(), &models.BlocklistEntry{Type: models.BlocklistEntryBankAccount, Value: "044 : 0123456789"})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if entry.Value != "044:0123456789" {
+		t.Errorf("Expected the bank account to be normalized, got %q", entry.Value)
+	}
+
+	tests := []struct {
+		name    string
+		entry   models.BlocklistEntry
+		wantErr string
+	}{
+		{"duplicate email", models.BlocklistEntry{Type: models.BlocklistEntryEmail, Value: "MULE@example.com"}, "value is already blocklisted"},
+		{"empty value", models.BlocklistEntry{Type: models.BlocklistEntryEmail, Value: "  "}, "value is required"},
+		{"invalid email", models.BlocklistEntry{Type: models.BlocklistEntryEmail, Value: "mule"}, "invalid email address"},
+		{"invalid wallet", models.BlocklistEntry{Type: models.BlocklistEntryWallet, Value: "abc"}, "invalid wallet ID"},
+		{"bank account without bank code", models.BlocklistEntry{Type: models.BlocklistEntryBankAccount, Value: "0123456789"}, "bank accounts must be written as BANKCODE:ACCOUNTNUMBER"},
+		{"unknown type", models.BlocklistEntry{Type: "PHONE", Value: "123"}, "invalid blocklist entry type"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			entry := tt.entry
+			_, err := complianceUC.CreateBlocklistEntry(context.Background(), &entry)
+			if err == nil || err.Error() != tt.wantErr {
+				t.Errorf("Expected error %q, got %v", tt.wantErr, err)
+			}
+		})
+	}
+
+	if err := complianceUC.DeleteBlocklistEntry(context.Background(), 99); err == nil || err.Error() != "blocklist entry not found" {
+		t.Errorf("Expected blocklist entry not found, got %v", err)
+	}
+}
+
+func TestWalletUseCase_BlocklistRefusesDebits(t *testing.T) {
+	repos, reconciliationUC := setupTestEnvironment()
+	repos.Wallet.Create(context.Background(), &models.Wallet{ID: 2, UserID: 2, Balance: decimal.NewFromInt(500), Currency: "USD", Status: models.WalletStatusActive})
+	repos.Wallet.Create(context.Background(), &models.Wallet{ID: 3, UserID: 3, Currency: "USD", Status: models.WalletStatusActive,
+		User: models.User{ID: 3, Email: "mule@example.com"}})
+
+	complianceUC := NewComplianceUseCase(repos)
+	walletUC := NewWalletUseCase(repos, reconciliationUC)
+
+	if _, err := complianceUC.CreateBlocklistEntry(context.Background(), &models.BlocklistEntry{Type: models.BlocklistEntryEmail, Value: "MULE@example.com", Reason: "Reported by partner bank"}); err != nil {
+		t.Fatalf("Failed to blocklist email: %v", err)
+	}
+	if _, err := complianceUC.CreateBlocklistEntry(context.Background(), &models.BlocklistEntry{Type: models.BlocklistEntryBankAccount, Value: "044:0123456789"}); err != nil {
+		t.Fatalf("Failed to blocklist bank account: %v", err)
+	}
+
+	_, _, err := walletUC.TransferFunds(context.Background(), 2, 3, decimal.NewFromInt(100), "BL001", "Transfer", nil)
+	if !errors.Is(err, apperrors.ErrComplianceBlocked) {
+		t.Fatalf("Expected the transfer to a blocklisted user to be refused, got %v", err)
+	}
+
+	_, _, err = walletUC.WithdrawFunds(context.Background(), 2, decimal.NewFromInt(100), "BL002", "Withdrawal",
+		&models.BankAccount{BankCode: "044", AccountNumber: "0123456789"}, nil)
+	if !errors.Is(err, apperrors.ErrComplianceBlocked) {
+		t.Fatalf("Expected the withdrawal to a blocklisted bank account to be refused, got %v", err)
+	}
+
+	if wallet, _ := repos.Wallet.GetByID(context.Background(), 2); !wallet.Balance.Equal(decimal.NewFromInt(500)) {
+		t.Errorf("Expected refused debits to leave the balance untouched, got %s", wallet.Balance)
+	}
+
+	logs, _ := NewComplianceUseCase(repos).ListComplianceLogs(context.Background(), models.ComplianceCheckBlocklist, 1, 20)
+	if len(logs) != 2 {
+		t.Fatalf("Expected 2 compliance log entries, got %d", len(logs))
+	}
+	// Newest first
+	if logs[1].Reference != "BL001" || logs[1].Subject != "EMAIL mule@example.com" || logs[1].CounterpartyWalletID == nil || *logs[1].CounterpartyWalletID != 3 {
+		t.Errorf("Unexpected compliance log entry for the transfer: %+v", logs[1])
+	}
+	if logs[0].Reference != "BL002" || logs[0].Subject != "BANK_ACCOUNT 044:0123456789" {
+		t.Errorf("Unexpected compliance log entry for the withdrawal: %+v", logs[0])
+	}
+}
+
+func TestUserUseCase_SanctionsScreeningRefusesRegistration(t *testing.T) {
+	repos, _ := setupTestEnvironment()
+	userUC := NewUserUseCase(repos, WithSanctionsChecker(compliance.NewWatchlistChecker("test", []string{"DOE, John"})))
+
+	_, err := userUC.CreateUser(context.Background(), &models.User{Name: "John Doe", Email: "john@example.com", Password: "password123", Age: 30})
+	if !errors.Is(err, apperrors.ErrSanctionsMatch) {
+		t.Fatalf("Expected registration to be refused by sanctions screening, got %v", err)
+	}
+	if _, err := repos.User.GetByEmail(context.Background(), "john@example.com"); err == nil {
+		t.Error("Expected no user to be created")
+	}
+
+	logs, _ := repos.ComplianceLog.List(context.Background(), models.ComplianceCheckSanctions, 0, 20)
+	if len(logs) != 1 || logs[0].Subject != "John Doe <john@example.com>" || logs[0].UserID != nil {
+		t.Errorf("Expected the match to be recorded in the compliance log, got %+v", logs)
+	}
+}
+
+func TestWalletUseCase_SanctionsScreeningRefusesNewRecipients(t *testing.T) {
+	repos, reconciliationUC := setupTestEnvironment()
+	repos.Wallet.Create(context.Background(), &models.Wallet{ID: 2, UserID: 2, Balance: decimal.NewFromInt(500), Currency: "USD", Status: models.WalletStatusActive})
+	repos.Wallet.Create(context.Background(), &models.Wallet{ID: 3, UserID: 3, Currency: "USD", Status: models.WalletStatusActive,
+		User: models.User{ID: 3, Name: "Jane Roe", Email: "jane@example.com"}})
+
+	walletUC := NewWalletUseCase(repos, reconciliationUC, WithSanctionsChecker(compliance.NewWatchlistChecker("test", []string{"Jane Roe"})))
+
+	_, _, err := walletUC.TransferFunds(context.Background(), 2, 3, decimal.NewFromInt(100), "SN001", "Transfer", nil)
+	if !errors.Is(err, apperrors.ErrSanctionsMatch) {
+		t.Fatalf("Expected the transfer to be refused by sanctions screening, got %v", err)
+	}
+
+	logs, _ := repos.ComplianceLog.List(context.Background(), models.ComplianceCheckSanctions, 0, 20)
+	if len(logs) != 1 || logs[0].Reference != "SN001" || logs[0].CounterpartyWalletID == nil || *logs[0].CounterpartyWalletID != 3 {
+		t.Errorf("Expected the match to be recorded in the compliance log, got %+v", logs)
+	}
+	if wallet, _ := repos.Wallet.GetByID(context.Background(), 2); !wallet.Balance.Equal(decimal.NewFromInt(500)) {
+		t.Errorf("Expected the refused transfer to leave the balance untouched, got %s", wallet.Balance)
+	}
+}
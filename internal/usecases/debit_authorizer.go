@@ -0,0 +1,53 @@
+package usecases
+
+import (
+	"context"
+	"time"
+
+	"github.com/limistah/wallet-service/internal/apperrors"
+	"github.com/limistah/wallet-service/internal/repositories"
+	"github.com/shopspring/decimal"
+)
+
+// debitAuthorizer checks the transaction PIN of payments that debit a wallet outside the transfer
+// and withdrawal endpoints, where the amount is only known once the payment is resolved, and refuses
+// the amounts a transfer would hold for a one-time code
+type debitAuthorizer struct {
+	pin      PINUseCase
+	settings *LiveSettings
+}
+
+func newDebitAuthorizer(repos *repositories.Repositories, o *options) debitAuthorizer {
+	return debitAuthorizer{
+		pin:      &pinUseCase{repos: repos, settings: o.settings, now: time.Now},
+		settings: o.settings,
+	}
+}
+
+// authorize checks the PIN the user sent for a debit of amount from their wallet
+func (a debitAuthorizer) authorize(ctx context.Context, userID uint, amount decimal.Decimal, pin string) error {
+	return a.pin.AuthorizeDebit(ctx, userID, amount, pin)
+}
+
+// authorizeUnchallenged refuses an amount above the step-up threshold, since the payment cannot be held
+// for a one-time code, then checks the PIN
+func (a debitAuthorizer) authorizeUnchallenged(ctx context.Context, userID uint, amount decimal.Decimal, pin string) error {
+	if err := a.checkUnchallenged(amount); err != nil {
+		return err
+	}
+	return a.authorize(ctx, userID, amount, pin)
+}
+
+// checkUnchallenged refuses an amount above the step-up threshold
+func (a debitAuthorizer) checkUnchallenged(amount decimal.Decimal) error {
+	return checkUnchallenged(a.settings.Load().TransferChallenge, amount)
+}
+
+// checkUnchallenged refuses an amount above the threshold of policy; without a policy every amount
+// goes through
+func checkUnchallenged(policy *TransferChallengePolicy, amount decimal.Decimal) error {
+	if policy == nil || !amount.GreaterThan(policy.Threshold) {
+		return nil
+	}
+	return apperrors.ErrValidation.Withf("payments above %s must be made as transfers confirmed with a one-time code", policy.Threshold.StringFixed(2))
+}
@@ -0,0 +1,243 @@
+package usecases
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/limistah/wallet-service/internal/apperrors"
+	"github.com/limistah/wallet-service/internal/models"
+	"github.com/limistah/wallet-service/internal/payments"
+	"github.com/limistah/wallet-service/internal/repositories"
+	"github.com/limistah/wallet-service/internal/utils"
+	"github.com/shopspring/decimal"
+	"gorm.io/gorm"
+)
+
+type depositUseCase struct {
+	repos         *repositories.Repositories
+	walletUseCase WalletUseCase
+	cardProvider  payments.CardPaymentProvider
+
+	paymentProvider     payments.PaymentProvider
+	checkoutCallbackURL string
+}
+
+// NewDepositUseCase creates a new deposit use case
+func NewDepositUseCase(repos *repositories.Repositories, walletUseCase WalletUseCase, opts ...Option) DepositUseCase {
+	o := newOptions(opts)
+	return &depositUseCase{
+		repos:         repos,
+		walletUseCase: walletUseCase,
+		cardProvider:  o.cardProvider,
+
+		paymentProvider:     o.paymentProvider,
+		checkoutCallbackURL: o.checkoutCallbackURL,
+	}
+}
+
+// getFundableWallet loads a wallet that can receive deposits
+func (uc *depositUseCase) getFundableWallet(ctx context.Context, walletID uint, amount decimal.Decimal) (*models.Wallet, error) {
+	if amount.LessThanOrEqual(decimal.Zero) {
+		return nil, apperrors.ErrInvalidAmount
+	}
+
+	wallet, err := uc.repos.Wallet.GetByID(ctx, walletID)
+	if err != nil {
+		return nil, apperrors.ErrWalletNotFound
+	}
+	if !wallet.IsActive() {
+		return nil, apperrors.ErrWalletInactive
+	}
+	return wallet, nil
+}
+
+func (uc *depositUseCase) CreatePendingDeposit(ctx context.Context, walletID uint, provider, externalReference string, amount decimal.Decimal) (*models.Deposit, error) {
+	if provider == "" || externalReference == "" {
+		return nil, apperrors.ErrValidation.Withf("provider and external reference are required")
+	}
+
+	wallet, err := uc.getFundableWallet(ctx, walletID, amount)
+	if err != nil {
+		return nil, err
+	}
+
+	deposit := &models.Deposit{
+		WalletID:          walletID,
+		Provider:          strings.ToLower(provider),
+		ExternalReference: externalReference,
+		Reference:         utils.GenerateTransactionReference(),
+		Amount:            amount,
+		Currency:          wallet.Currency,
+		Status:            models.DepositStatusPending,
+	}
+	if err := uc.repos.Deposit.Create(ctx, deposit); err != nil {
+		return nil, err
+	}
+	return deposit, nil
+}
+
+func (uc *depositUseCase) FundWithCard(ctx context.Context, walletID uint, amount decimal.Decimal) (*models.Deposit, string, error) {
+	if uc.cardProvider == nil {
+		return nil, "", apperrors.ErrFundingNotConfigured.Withf("card funding is not configured")
+	}
+
+	wallet, err := uc.getFundableWallet(ctx, walletID, amount)
+	if err != nil {
+		return nil, "", err
+	}
+
+	reference := utils.GenerateTransactionReference()
+	intent, err := uc.cardProvider.CreatePaymentIntent(amount, wallet.Currency, reference)
+	if err != nil {
+		return nil, "", apperrors.ErrPaymentProviderFailed.Withf("failed to create payment intent: %w", err)
+	}
+
+	deposit := &models.Deposit{
+		WalletID:          walletID,
+		Provider:          uc.cardProvider.Name(),
+		ExternalReference: intent.ID,
+		Reference:         reference,
+		Amount:            amount,
+		Currency:          wallet.Currency,
+		Status:            models.DepositStatusPending,
+	}
+	if err := uc.repos.Deposit.Create(ctx, deposit); err != nil {
+		return nil, "", err
+	}
+	return deposit, intent.ClientSecret, nil
+}
+
+func (uc *depositUseCase) InitializeCheckout(ctx context.Context, walletID uint, amount decimal.Decimal) (*models.Deposit, *payments.Checkout, error) {
+	if uc.paymentProvider == nil {
+		return nil, nil, apperrors.ErrFundingNotConfigured.Withf("checkout funding is not configured")
+	}
+
+	wallet, err := uc.getFundableWallet(ctx, walletID, amount)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	user, err := uc.repos.User.GetByID(ctx, wallet.UserID)
+	if err != nil {
+		return nil, nil, apperrors.ErrUserNotFound
+	}
+
+	// The intent is persisted before contacting the provider so a webhook can never arrive for an unknown reference
+	reference := utils.GenerateTransactionReference()
+	deposit := &models.Deposit{
+		WalletID:          walletID,
+		Provider:          uc.paymentProvider.Name(),
+		ExternalReference: reference,
+		Reference:         reference,
+		Amount:            amount,
+		Currency:          wallet.Currency,
+		Status:            models.DepositStatusPending,
+	}
+	if err := uc.repos.Deposit.Create(ctx, deposit); err != nil {
+		return nil, nil, err
+	}
+
+	checkout, err := uc.paymentProvider.InitializeCheckout(payments.CheckoutRequest{
+		Reference:   reference,
+		Amount:      amount,
+		Currency:    wallet.Currency,
+		Email:       user.Email,
+		CallbackURL: uc.checkoutCallbackURL,
+	})
+	if err != nil {
+		deposit.Status = models.DepositStatusFailed
+		deposit.FailureReason = err.Error()
+		if updateErr := uc.repos.Deposit.Update(ctx, deposit); updateErr != nil {
+			log.Printf("Failed to mark deposit %d as failed: %v", deposit.ID, updateErr)
+		}
+		return nil, nil, apperrors.ErrPaymentProviderFailed.Withf("failed to initialize checkout: %w", err)
+	}
+
+	return deposit, checkout, nil
+}
+
+func (uc *depositUseCase) ConfirmDeposit(ctx context.Context, provider string, event payments.DepositEvent) (*models.Deposit, error) {
+	deposit, err := uc.repos.Deposit.GetByExternalReference(ctx, strings.ToLower(provider), event.ExternalReference)
+	if err == gorm.ErrRecordNotFound {
+		return nil, apperrors.ErrDepositNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	// Providers retry deliveries until acknowledged; anything already settled is acknowledged as is
+	if !deposit.IsPending() {
+		if event.Status == payments.DepositEventSucceeded && deposit.Status != models.DepositStatusCompleted {
+			log.Printf("Deposit %d is %s but %s reported success for %s", deposit.ID, deposit.Status, provider, event.ExternalReference)
+		}
+		return deposit, nil
+	}
+
+	if event.Status == payments.DepositEventFailed || event.Status == payments.DepositEventCancelled {
+		deposit.Status = models.DepositStatusFailed
+		if event.Status == payments.DepositEventCancelled {
+			deposit.Status = models.DepositStatusCancelled
+		}
+		deposit.FailureReason = event.FailureReason
+		if err := uc.repos.Deposit.Update(ctx, deposit); err != nil {
+			return nil, err
+		}
+		return deposit, nil
+	}
+
+	if !event.Amount.Equal(deposit.Amount) {
+		return nil, apperrors.ErrDepositMismatch.Withf("deposit amount mismatch: expected %s, got %s", deposit.Amount.StringFixed(2), event.Amount.StringFixed(2))
+	}
+	if event.Currency != "" && !strings.EqualFold(event.Currency, deposit.Currency) {
+		return nil, apperrors.ErrDepositMismatch.Withf("deposit currency mismatch: expected %s, got %s", deposit.Currency, event.Currency)
+	}
+
+	description := fmt.Sprintf("Deposit via %s (%s)", deposit.Provider, deposit.ExternalReference)
+	transaction, _, err := uc.walletUseCase.FundWallet(ctx, deposit.WalletID, deposit.Amount, deposit.Reference, description)
+	if errors.Is(err, apperrors.ErrWalletInactive) || errors.Is(err, apperrors.ErrWalletNotFound) {
+		return uc.parkDeposit(ctx, deposit)
+	}
+	if err != nil && errors.Is(err, apperrors.ErrDuplicateReference) {
+		// A concurrent delivery of the same webhook already credited the wallet
+		transaction, err = uc.repos.Transaction.GetByReference(ctx, deposit.Reference)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	deposit.Status = models.DepositStatusCompleted
+	deposit.TransactionID = &transaction.ID
+	deposit.CompletedAt = &now
+	if err := uc.repos.Deposit.Update(ctx, deposit); err != nil {
+		return nil, err
+	}
+	return deposit, nil
+}
+
+// parkDeposit moves a settled deposit whose wallet can no longer be credited to the suspense account
+func (uc *depositUseCase) parkDeposit(ctx context.Context, deposit *models.Deposit) (*models.Deposit, error) {
+	item, err := parkInSuspense(ctx, uc.repos, &models.SuspenseItem{
+		Provider:          deposit.Provider,
+		ExternalReference: deposit.ExternalReference,
+		Amount:            deposit.Amount,
+		Currency:          deposit.Currency,
+		Reason:            models.SuspenseReasonWalletInactive,
+		DepositID:         &deposit.ID,
+		WalletID:          &deposit.WalletID,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	deposit.Status = models.DepositStatusParked
+	deposit.FailureReason = fmt.Sprintf("wallet is not active; funds parked in suspense item %d", item.ID)
+	if err := uc.repos.Deposit.Update(ctx, deposit); err != nil {
+		return nil, err
+	}
+	return deposit, nil
+}
@@ -0,0 +1,95 @@
+package usecases
+
+import (
+	"context"
+	"testing"
+
+	"github.com/limistah/wallet-service/internal/models"
+	"github.com/limistah/wallet-service/internal/payments"
+	"github.com/limistah/wallet-service/internal/repositories/memory"
+	"github.com/shopspring/decimal"
+)
+
+// fundingWalletUseCase records FundWallet calls; other methods are not used by deposits
+type fundingWalletUseCase struct {
+	WalletUseCase
+	fundCalls int
+}
+
+func (w *fundingWalletUseCase) FundWallet(ctx context.Context, walletID uint, amount decimal.Decimal, reference, description string) (*models.Transaction, *models.Transaction, error) {
+	w.fundCalls++
+	return &models.Transaction{ID: 42, WalletID: walletID, Amount: amount, Reference: reference}, &models.Transaction{ID: 41}, nil
+}
+
+func TestDepositUseCase_ConfirmDeposit(t *testing.T) {
+	repos, _ := setupTestEnvironment()
+	repos.Deposit = memory.NewDepositRepository()
+	repos.Wallet.Create(context.Background(), &models.Wallet{ID: 2, UserID: 2, Currency: "USD", Status: models.WalletStatusActive})
+
+	walletUC := &fundingWalletUseCase{}
+	depositUC := NewDepositUseCase(repos, walletUC)
+
+	deposit, err := depositUC.CreatePendingDeposit(context.Background(), 2, "Bank", "EXT-1", decimal.NewFromInt(100))
+	if err != nil {
+		t.Fatalf("Unexpected error creating deposit: %v", err)
+	}
+	if deposit.Provider != "bank" || deposit.Currency != "USD" || !deposit.IsPending() {
+		t.Fatalf("Unexpected deposit: %+v", deposit)
+	}
+
+	mismatch := payments.DepositEvent{ExternalReference: "EXT-1", Amount: decimal.NewFromInt(99), Status: payments.DepositEventSucceeded}
+	if _, err := depositUC.ConfirmDeposit(context.Background(), "bank", mismatch); err == nil {
+		t.Error("Expected amount mismatch to be rejected")
+	}
+
+	event := payments.DepositEvent{ExternalReference: "EXT-1", Amount: decimal.NewFromInt(100), Currency: "USD", Status: payments.DepositEventSucceeded}
+	for i := 0; i < 3; i++ {
+		confirmed, err := depositUC.ConfirmDeposit(context.Background(), "bank", event)
+		if err != nil {
+			t.Fatalf("Unexpected error on delivery %d: %v", i+1, err)
+		}
+		if confirmed.Status != models.DepositStatusCompleted || confirmed.TransactionID == nil || *confirmed.TransactionID != 42 {
+			t.Errorf("Unexpected deposit after delivery %d: %+v", i+1, confirmed)
+		}
+	}
+	if walletUC.fundCalls != 1 {
+		t.Errorf("Expected the wallet to be credited once, got %d", walletUC.fundCalls)
+	}
+
+	if _, err := depositUC.ConfirmDeposit(context.Background(), "bank", payments.DepositEvent{ExternalReference: "missing"}); err == nil || err.Error() != "deposit not found" {
+		t.Errorf("Expected deposit not found, got %v", err)
+	}
+}
+
+func TestDepositUseCase_ConfirmDepositFailure(t *testing.T) {
+	repos, _ := setupTestEnvironment()
+	repos.Deposit = memory.NewDepositRepository()
+	repos.Wallet.Create(context.Background(), &models.Wallet{ID: 2, UserID: 2, Currency: "USD", Status: models.WalletStatusActive})
+
+	walletUC := &fundingWalletUseCase{}
+	depositUC := NewDepositUseCase(repos, walletUC)
+
+	if _, err := depositUC.CreatePendingDeposit(context.Background(), 2, "bank", "EXT-2", decimal.NewFromInt(50)); err != nil {
+		t.Fatalf("Unexpected error creating deposit: %v", err)
+	}
+
+	failed, err := depositUC.ConfirmDeposit(context.Background(), "bank", payments.DepositEvent{
+		ExternalReference: "EXT-2",
+		Status:            payments.DepositEventFailed,
+		FailureReason:     "card declined",
+	})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if failed.Status != models.DepositStatusFailed || failed.FailureReason != "card declined" {
+		t.Errorf("Unexpected deposit: %+v", failed)
+	}
+
+	// A late success for a failed deposit must not credit the wallet
+	if _, err := depositUC.ConfirmDeposit(context.Background(), "bank", payments.DepositEvent{ExternalReference: "EXT-2", Amount: decimal.NewFromInt(50), Status: payments.DepositEventSucceeded}); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if walletUC.fundCalls != 0 {
+		t.Errorf("Expected no credit, got %d", walletUC.fundCalls)
+	}
+}
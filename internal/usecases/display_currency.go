@@ -0,0 +1,38 @@
+package usecases
+
+import (
+	"context"
+	"log"
+	"strings"
+
+	"github.com/limistah/wallet-service/internal/fx"
+	"github.com/limistah/wallet-service/internal/repositories"
+)
+
+// displayConversion returns how amounts in currency are shown in the display currency the user
+// prefers, or nil when they have none, it is the same currency or no rate is known. Converted
+// values are indicative: money always moves at the rate quoted when it moves, so a missing rate
+// leaves the value out rather than failing the read
+func displayConversion(ctx context.Context, repos *repositories.Repositories, rates fx.RateProvider, userID uint, currency string) *fx.Conversion {
+	if rates == nil {
+		return nil
+	}
+	user, err := repos.User.GetByID(ctx, userID)
+	if err != nil {
+		log.Printf("Failed to load display currency of user %d: %v", userID, err)
+		return nil
+	}
+	if user.DisplayCurrency == "" || strings.EqualFold(user.DisplayCurrency, currency) {
+		return nil
+	}
+	rate, err := rates.Rate(currency, user.DisplayCurrency)
+	if err != nil {
+		return nil
+	}
+	return &fx.Conversion{Currency: user.DisplayCurrency, Rate: rate}
+}
+
+// DisplayConversion converts the amounts of a wallet of the user in currency to their display currency
+func (uc *walletUseCase) DisplayConversion(ctx context.Context, userID uint, currency string) *fx.Conversion {
+	return displayConversion(ctx, uc.repos, uc.exchangeRates, userID, currency)
+}
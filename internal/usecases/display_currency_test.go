@@ -0,0 +1,58 @@
+package usecases
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/limistah/wallet-service/internal/apperrors"
+	"github.com/limistah/wallet-service/internal/fx"
+	"github.com/limistah/wallet-service/internal/models"
+	"github.com/shopspring/decimal"
+)
+
+func TestDisplayCurrency(t *testing.T) {
+	currency := func(code string) ProfileUpdate { return ProfileUpdate{DisplayCurrency: &code} }
+	repos, reconciliationUC := setupTestEnvironment()
+	repos.User.Create(context.Background(), &models.User{ID: 2, Email: "ada@example.com"})
+	repos.Wallet.Create(context.Background(), &models.Wallet{ID: 2, UserID: 2, Balance: decimal.RequireFromString("10.50"), Currency: "USD", Status: models.WalletStatusActive})
+
+	if _, err := NewUserUseCase(repos).UpdateProfile(context.Background(), 2, currency("NGN")); !errors.Is(err, apperrors.ErrValidation) {
+		t.Errorf("Expected ErrValidation without exchange rates, got %v", err)
+	}
+
+	rates, err := fx.ParseRates([]string{"USD/NGN=1550"})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	userUC := NewUserUseCase(repos, WithExchangeRates(rates))
+	walletUC := NewWalletUseCase(repos, reconciliationUC, WithExchangeRates(rates))
+
+	if conversion := walletUC.DisplayConversion(context.Background(), 2, "USD"); conversion != nil {
+		t.Errorf("Expected no conversion without a display currency, got %+v", conversion)
+	}
+	for _, code := range []string{"naira", "EUR"} {
+		if _, err := userUC.UpdateProfile(context.Background(), 2, currency(code)); !errors.Is(err, apperrors.ErrValidation) {
+			t.Errorf("%s: expected ErrValidation, got %v", code, err)
+		}
+	}
+
+	user, err := userUC.UpdateProfile(context.Background(), 2, currency("ngn"))
+	if err != nil || user.DisplayCurrency != "NGN" {
+		t.Fatalf("Expected the display currency to be NGN, got %+v, %v", user, err)
+	}
+	conversion := walletUC.DisplayConversion(context.Background(), 2, "USD")
+	if conversion == nil || conversion.Currency != "NGN" || !conversion.Convert(decimal.RequireFromString("10.50")).Equal(decimal.NewFromInt(16275)) {
+		t.Errorf("Expected 10.50 USD to show as 16275 NGN, got %+v", conversion)
+	}
+	if conversion := walletUC.DisplayConversion(context.Background(), 2, "NGN"); conversion != nil {
+		t.Errorf("Expected no conversion to the same currency, got %+v", conversion)
+	}
+
+	if _, err := userUC.UpdateProfile(context.Background(), 2, currency("")); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if conversion := walletUC.DisplayConversion(context.Background(), 2, "USD"); conversion != nil {
+		t.Errorf("Expected an empty display currency to turn it off, got %+v", conversion)
+	}
+}
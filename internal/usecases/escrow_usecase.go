@@ -0,0 +1,262 @@
+package usecases
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/limistah/wallet-service/internal/apperrors"
+	"github.com/limistah/wallet-service/internal/fraud"
+	"github.com/limistah/wallet-service/internal/models"
+	"github.com/limistah/wallet-service/internal/repositories"
+	"github.com/limistah/wallet-service/internal/utils"
+	"github.com/shopspring/decimal"
+)
+
+// escrowExpiryBatchSize bounds the escrows returned to payers in one run of the expiry job
+const escrowExpiryBatchSize = 100
+
+type escrowUseCase struct {
+	repos         *repositories.Repositories
+	walletUseCase WalletUseCase
+	authorizer    debitAuthorizer
+}
+
+// NewEscrowUseCase creates a new escrow use case
+func NewEscrowUseCase(repos *repositories.Repositories, walletUseCase WalletUseCase, opts ...Option) EscrowUseCase {
+	o := newOptions(opts)
+	return &escrowUseCase{
+		repos:         repos,
+		walletUseCase: walletUseCase,
+		authorizer:    newDebitAuthorizer(repos, o),
+	}
+}
+
+// CreateEscrow moves the amount from the payer's wallet to the escrow account, to be released to the
+// payee or returned to the payer; pin authorizes the debit. The payment is screened like a transfer to
+// the payee
+func (uc *escrowUseCase) CreateEscrow(ctx context.Context, payerID uint, payeeEmail string, amount decimal.Decimal, condition string, expiresAt time.Time, pin string, origin *fraud.Origin) (*models.Escrow, error) {
+	if amount.LessThanOrEqual(decimal.Zero) {
+		return nil, apperrors.ErrInvalidAmount
+	}
+	if !expiresAt.After(time.Now()) {
+		return nil, apperrors.ErrValidation.Withf("expiry must be in the future")
+	}
+
+	payee, err := uc.repos.User.GetByEmail(ctx, utils.NormalizeEmail(payeeEmail))
+	if err != nil || payee.IsSystemAccount() {
+		return nil, apperrors.ErrUserNotFound.Withf("payee not found")
+	}
+	if payee.ID == payerID {
+		return nil, apperrors.ErrSelfPayment.Withf("cannot hold funds in escrow for yourself")
+	}
+
+	payerWallet, err := uc.repos.Wallet.GetByUserID(ctx, payerID)
+	if err != nil {
+		return nil, apperrors.ErrWalletNotFound
+	}
+	if !payerWallet.IsActive() {
+		return nil, apperrors.ErrWalletInactive
+	}
+	payeeWallet, err := uc.repos.Wallet.GetByUserID(ctx, payee.ID)
+	if err != nil {
+		return nil, apperrors.ErrCounterpartyWalletNotFound.Withf("payee wallet not found")
+	}
+	if payeeWallet.Currency != payerWallet.Currency {
+		return nil, apperrors.ErrCurrencyMismatch.Withf("payee wallet currency does not match")
+	}
+	if payerWallet.Balance.LessThan(amount) {
+		return nil, apperrors.ErrInsufficientFunds
+	}
+	if err := uc.authorizer.authorizeUnchallenged(ctx, payerID, amount, pin); err != nil {
+		return nil, err
+	}
+
+	account, err := uc.escrowAccount(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if account.Wallet.Currency != payerWallet.Currency {
+		return nil, apperrors.ErrCurrencyMismatch.Withf("escrow is only available in %s", account.Wallet.Currency)
+	}
+
+	reference := "ESCROW-" + utils.GenerateTransactionReference()
+	if err := uc.walletUseCase.ScreenPayment(ctx, payerWallet, payeeWallet, amount, reference, origin); err != nil {
+		return nil, err
+	}
+
+	escrow := &models.Escrow{
+		Reference:     reference,
+		PayerID:       payerID,
+		PayerWalletID: payerWallet.ID,
+		PayeeID:       payee.ID,
+		PayeeWalletID: payeeWallet.ID,
+		Amount:        amount,
+		Currency:      payerWallet.Currency,
+		Condition:     strings.TrimSpace(condition),
+		ExpiresAt:     expiresAt,
+		Status:        models.EscrowStatusHeld,
+	}
+	description := fmt.Sprintf("Held in escrow for %s", payee.Name)
+	entry := &models.JournalEntry{Reference: escrow.Reference, Description: description}
+	legs := []postingLeg{
+		{WalletID: payerWallet.ID, Type: models.TransactionTypeDebit, Amount: amount, Purpose: models.TransactionPurposeTransfer, Description: description},
+		{WalletID: account.WalletID, Type: models.TransactionTypeCredit, Amount: amount, Purpose: models.TransactionPurposeTransfer, Description: description},
+	}
+	err = uc.repos.UnitOfWork.Do(ctx, func(tx *repositories.Repositories) error {
+		if err := postJournalEntry(ctx, tx, entry, legs); err != nil {
+			return err
+		}
+		escrow.FundingJournalEntryID = entry.ID
+		if err := tx.Escrow.Create(ctx, escrow); err != nil {
+			return fmt.Errorf("failed to create escrow: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return uc.repos.Escrow.GetByID(ctx, escrow.ID)
+}
+
+func (uc *escrowUseCase) ListEscrows(ctx context.Context, userID uint, status models.EscrowStatus, page, pageSize int) ([]models.Escrow, error) {
+	if page < 1 {
+		page = 1
+	}
+	if pageSize < 1 || pageSize > 100 {
+		pageSize = 20
+	}
+	return uc.repos.Escrow.ListByUser(ctx, userID, status, (page-1)*pageSize, pageSize)
+}
+
+// GetEscrow returns an escrow of which the user is the payer or the payee
+func (uc *escrowUseCase) GetEscrow(ctx context.Context, userID, escrowID uint) (*models.Escrow, error) {
+	escrow, err := uc.repos.Escrow.GetByID(ctx, escrowID)
+	if err != nil || (escrow.PayerID != userID && escrow.PayeeID != userID) {
+		return nil, apperrors.ErrEscrowNotFound
+	}
+	return escrow, nil
+}
+
+// ReleaseEscrow pays the held funds to the payee; only the payer releases, and only before expiry
+func (uc *escrowUseCase) ReleaseEscrow(ctx context.Context, payerID, escrowID uint) (*models.Escrow, error) {
+	escrow, err := uc.heldEscrow(ctx, payerID, escrowID)
+	if err != nil {
+		return nil, err
+	}
+	if escrow.PayerID != payerID {
+		return nil, apperrors.ErrEscrowPayerOnly
+	}
+	if escrow.IsExpired(time.Now()) {
+		return nil, apperrors.ErrEscrowSettled.Withf("escrow expired on %s", escrow.ExpiresAt.UTC().Format(time.RFC3339))
+	}
+	// The release cannot be held for a one-time code either, in case the threshold was lowered since
+	if err := uc.authorizer.checkUnchallenged(escrow.Amount); err != nil {
+		return nil, err
+	}
+
+	payeeWallet, err := uc.repos.Wallet.GetByID(ctx, escrow.PayeeWalletID)
+	if err != nil {
+		return nil, apperrors.ErrCounterpartyWalletNotFound.Withf("payee wallet not found")
+	}
+	if !payeeWallet.IsActive() {
+		return nil, apperrors.ErrWalletInactive.Withf("payee wallet is not active")
+	}
+	tier, err := resolveWalletTier(ctx, uc.repos, payeeWallet)
+	if err != nil {
+		return nil, err
+	}
+	if !withinMaxBalance(tier, payeeWallet, escrow.Amount) {
+		return nil, apperrors.ErrTierLimitExceeded.Withf("payee wallet balance would exceed the maximum of %s", tier.MaxBalance.StringFixed(2))
+	}
+
+	description := fmt.Sprintf("Released from escrow by %s", escrow.Payer.Name)
+	return uc.settle(ctx, escrow, models.EscrowStatusReleased, escrow.PayeeWalletID, description)
+}
+
+// CancelEscrow returns the held funds to the payer; the payer may call the deal off and the payee
+// may decline it
+func (uc *escrowUseCase) CancelEscrow(ctx context.Context, userID, escrowID uint) (*models.Escrow, error) {
+	escrow, err := uc.heldEscrow(ctx, userID, escrowID)
+	if err != nil {
+		return nil, err
+	}
+	description := "Escrow cancelled by the payer"
+	if userID == escrow.PayeeID {
+		description = "Escrow declined by the payee"
+	}
+	return uc.settle(ctx, escrow, models.EscrowStatusCancelled, escrow.PayerWalletID, description)
+}
+
+// ExpireEscrows returns the funds of held escrows past their expiry to their payers
+func (uc *escrowUseCase) ExpireEscrows(ctx context.Context, now time.Time) (int, error) {
+	escrows, err := uc.repos.Escrow.ListExpired(ctx, now, escrowExpiryBatchSize)
+	if err != nil {
+		return 0, fmt.Errorf("failed to list expired escrows: %w", err)
+	}
+
+	expired := 0
+	for i := range escrows {
+		if _, err := uc.settle(ctx, &escrows[i], models.EscrowStatusExpired, escrows[i].PayerWalletID, "Escrow expired"); err != nil {
+			log.Printf("Failed to expire escrow %s: %v", escrows[i].Reference, err)
+			continue
+		}
+		expired++
+	}
+	return expired, nil
+}
+
+// settle moves the funds of a held escrow out of the escrow account to a wallet, failing when the
+// escrow was settled first
+func (uc *escrowUseCase) settle(ctx context.Context, escrow *models.Escrow, status models.EscrowStatus, walletID uint, description string) (*models.Escrow, error) {
+	account, err := uc.escrowAccount(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	entry := &models.JournalEntry{Reference: fmt.Sprintf("%s-%s", escrow.Reference, status), Description: description}
+	legs := []postingLeg{
+		{WalletID: account.WalletID, Type: models.TransactionTypeDebit, Amount: escrow.Amount, Purpose: models.TransactionPurposeTransfer, Description: description},
+		{WalletID: walletID, Type: models.TransactionTypeCredit, Amount: escrow.Amount, Purpose: models.TransactionPurposeTransfer, Description: description},
+	}
+	err = uc.repos.UnitOfWork.Do(ctx, func(tx *repositories.Repositories) error {
+		settled, err := tx.Escrow.Settle(ctx, escrow.ID, status, now)
+		if err != nil {
+			return fmt.Errorf("failed to update escrow: %w", err)
+		}
+		if !settled {
+			return apperrors.ErrEscrowSettled
+		}
+		if err := postJournalEntry(ctx, tx, entry, legs); err != nil {
+			return err
+		}
+		return tx.Escrow.SetSettlementJournalEntry(ctx, escrow.ID, entry.ID)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return uc.repos.Escrow.GetByID(ctx, escrow.ID)
+}
+
+// heldEscrow loads a held escrow of which the user is the payer or the payee
+func (uc *escrowUseCase) heldEscrow(ctx context.Context, userID, escrowID uint) (*models.Escrow, error) {
+	escrow, err := uc.GetEscrow(ctx, userID, escrowID)
+	if err != nil {
+		return nil, err
+	}
+	if !escrow.IsHeld() {
+		return nil, apperrors.ErrEscrowSettled
+	}
+	return escrow, nil
+}
+
+func (uc *escrowUseCase) escrowAccount(ctx context.Context) (*models.LedgerAccount, error) {
+	account, err := uc.repos.LedgerAccount.GetByCode(ctx, models.LedgerAccountEscrow, false)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load escrow account: %w", err)
+	}
+	return account, nil
+}
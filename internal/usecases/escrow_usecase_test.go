@@ -0,0 +1,115 @@
+package usecases
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/limistah/wallet-service/internal/apperrors"
+	"github.com/limistah/wallet-service/internal/fraud"
+	"github.com/limistah/wallet-service/internal/models"
+	"github.com/limistah/wallet-service/internal/repositories/memory"
+	"github.com/shopspring/decimal"
+)
+
+func setupEscrowTest() (*memory.EscrowRepository, EscrowUseCase) {
+	repos, _ := setupTestEnvironment()
+	escrows := memory.NewEscrowRepository()
+	repos.Escrow = escrows
+
+	ledgerAccounts := memory.NewLedgerAccountRepository(nil)
+	ledgerAccounts.Create(context.Background(), &models.LedgerAccount{Code: models.LedgerAccountEscrow, Name: "Escrow", WalletID: 10, Wallet: models.Wallet{ID: 10, Currency: "USD"}})
+	repos.LedgerAccount = ledgerAccounts
+
+	repos.User.Create(context.Background(), &models.User{ID: 2, Email: "payer@example.com", Name: "Payer"})
+	repos.User.Create(context.Background(), &models.User{ID: 3, Email: "payee@example.com", Name: "Payee"})
+	repos.User.Create(context.Background(), &models.User{ID: 4, Email: "euro@example.com", Name: "Euro"})
+	repos.Wallet.Create(context.Background(), &models.Wallet{ID: 2, UserID: 2, Balance: decimal.NewFromInt(100), Currency: "USD", Status: models.WalletStatusActive})
+	repos.Wallet.Create(context.Background(), &models.Wallet{ID: 3, UserID: 3, Currency: "USD", Status: models.WalletStatusActive})
+	repos.Wallet.Create(context.Background(), &models.Wallet{ID: 4, UserID: 4, Currency: "EUR", Status: models.WalletStatusActive})
+	return escrows, NewEscrowUseCase(repos, &screeningWalletUseCase{})
+}
+
+// screeningWalletUseCase screens payments with a fixed result
+type screeningWalletUseCase struct {
+	WalletUseCase
+	screenErr error
+	screened  []string
+}
+
+func (w *screeningWalletUseCase) ScreenPayment(ctx context.Context, fromWallet, toWallet *models.Wallet, amount decimal.Decimal, reference string, origin *fraud.Origin) error {
+	w.screened = append(w.screened, reference)
+	return w.screenErr
+}
+
+func TestEscrowUseCase_CreateEscrowValidation(t *testing.T) {
+	_, escrowUC := setupEscrowTest()
+	expiresAt := time.Now().Add(24 * time.Hour)
+
+	tests := []struct {
+		name      string
+		payee     string
+		amount    decimal.Decimal
+		expiresAt time.Time
+		want      error
+	}{
+		{"zero amount", "payee@example.com", decimal.Zero, expiresAt, apperrors.ErrInvalidAmount},
+		{"past expiry", "payee@example.com", decimal.NewFromInt(10), time.Now().Add(-time.Minute), apperrors.ErrValidation},
+		{"unknown payee", "nobody@example.com", decimal.NewFromInt(10), expiresAt, apperrors.ErrUserNotFound},
+		{"self", " Payer@Example.com ", decimal.NewFromInt(10), expiresAt, apperrors.ErrSelfPayment},
+		{"other currency", "euro@example.com", decimal.NewFromInt(10), expiresAt, apperrors.ErrCurrencyMismatch},
+		{"insufficient funds", "payee@example.com", decimal.NewFromInt(101), expiresAt, apperrors.ErrInsufficientFunds},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := escrowUC.CreateEscrow(context.Background(), 2, tt.payee, tt.amount, "", tt.expiresAt, "", nil); !errors.Is(err, tt.want) {
+				t.Errorf("CreateEscrow() error = %v, want %v", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestEscrowUseCase_CreateEscrowScreensThePayment(t *testing.T) {
+	_, escrowUC := setupEscrowTest()
+	screener := escrowUC.(*escrowUseCase).walletUseCase.(*screeningWalletUseCase)
+	screener.screenErr = apperrors.ErrComplianceBlocked
+
+	if _, err := escrowUC.CreateEscrow(context.Background(), 2, "payee@example.com", decimal.NewFromInt(10), "", time.Now().Add(time.Hour), "", nil); !errors.Is(err, apperrors.ErrComplianceBlocked) {
+		t.Errorf("Expected a refused screening to refuse the escrow, got: %v", err)
+	}
+	if len(screener.screened) != 1 {
+		t.Errorf("Expected the escrow to be screened once, got %v", screener.screened)
+	}
+}
+
+func TestEscrowUseCase_Access(t *testing.T) {
+	escrows, escrowUC := setupEscrowTest()
+	held := &models.Escrow{PayerID: 2, PayeeID: 3, Amount: decimal.NewFromInt(50), Currency: "USD", ExpiresAt: time.Now().Add(time.Hour), Status: models.EscrowStatusHeld}
+	escrows.Create(context.Background(), held)
+	expired := &models.Escrow{PayerID: 2, PayeeID: 3, Amount: decimal.NewFromInt(5), Currency: "USD", ExpiresAt: time.Now().Add(-time.Hour), Status: models.EscrowStatusHeld}
+	escrows.Create(context.Background(), expired)
+	released := &models.Escrow{PayerID: 2, PayeeID: 3, Amount: decimal.NewFromInt(5), Currency: "USD", ExpiresAt: time.Now().Add(time.Hour), Status: models.EscrowStatusReleased}
+	escrows.Create(context.Background(), released)
+
+	if _, err := escrowUC.GetEscrow(context.Background(), 4, held.ID); !errors.Is(err, apperrors.ErrEscrowNotFound) {
+		t.Errorf("Expected escrows of others to be not found, got: %v", err)
+	}
+	if escrow, err := escrowUC.GetEscrow(context.Background(), 3, held.ID); err != nil || escrow.ID != held.ID {
+		t.Errorf("Expected the payee to see the escrow, got %+v, %v", escrow, err)
+	}
+	if _, err := escrowUC.ReleaseEscrow(context.Background(), 3, held.ID); !errors.Is(err, apperrors.ErrEscrowPayerOnly) {
+		t.Errorf("Expected the payee not to release, got: %v", err)
+	}
+	if _, err := escrowUC.ReleaseEscrow(context.Background(), 2, expired.ID); !errors.Is(err, apperrors.ErrEscrowSettled) {
+		t.Errorf("Expected an expired escrow not to be released, got: %v", err)
+	}
+	if _, err := escrowUC.CancelEscrow(context.Background(), 3, released.ID); !errors.Is(err, apperrors.ErrEscrowSettled) {
+		t.Errorf("Expected a released escrow not to be cancelled, got: %v", err)
+	}
+
+	list, _ := escrowUC.ListEscrows(context.Background(), 3, models.EscrowStatusHeld, 1, 20)
+	if len(list) != 2 {
+		t.Errorf("Expected 2 held escrows, got %d", len(list))
+	}
+}
@@ -0,0 +1,67 @@
+package usecases
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/limistah/wallet-service/internal/apperrors"
+	"github.com/limistah/wallet-service/internal/models"
+	"github.com/limistah/wallet-service/internal/repositories"
+	"gorm.io/gorm"
+)
+
+// ImpersonationGrant is what a staff member may do as another user
+type ImpersonationGrant struct {
+	Staff    *models.User
+	User     *models.User
+	ReadOnly bool
+	TTL      time.Duration
+}
+
+type impersonationUseCase struct {
+	repos *repositories.Repositories
+	ttl   time.Duration
+}
+
+// NewImpersonationUseCase creates a new impersonation use case
+func NewImpersonationUseCase(repos *repositories.Repositories, opts ...Option) ImpersonationUseCase {
+	o := newOptions(opts)
+	return &impersonationUseCase{
+		repos: repos,
+		ttl:   o.impersonationTTL,
+	}
+}
+
+// StartImpersonation checks that a staff member may act as a user. Impersonations are read-only unless
+// write access is asked for, which only admins get; staff and system accounts cannot be impersonated
+func (uc *impersonationUseCase) StartImpersonation(ctx context.Context, staffID, userID uint, writeAccess bool) (*ImpersonationGrant, error) {
+	staff, err := uc.repos.User.GetByID(ctx, staffID)
+	if err != nil {
+		return nil, err
+	}
+	if staff.Role != models.UserRoleAdmin && staff.Role != models.UserRoleSupport {
+		return nil, apperrors.ErrImpersonationForbidden
+	}
+	if writeAccess && !staff.IsAdmin() {
+		return nil, apperrors.ErrImpersonationForbidden.Withf("only admins can impersonate with write access")
+	}
+
+	user, err := uc.repos.User.GetByID(ctx, userID)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, apperrors.ErrUserNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	if user.IsSystemAccount() || user.Role != models.UserRoleUser {
+		return nil, apperrors.ErrImpersonationForbidden.Withf("staff and system accounts cannot be impersonated")
+	}
+
+	return &ImpersonationGrant{
+		Staff:    staff,
+		User:     user,
+		ReadOnly: !writeAccess,
+		TTL:      uc.ttl,
+	}, nil
+}
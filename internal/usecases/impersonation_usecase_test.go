@@ -0,0 +1,79 @@
+package usecases
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/limistah/wallet-service/internal/apperrors"
+	"github.com/limistah/wallet-service/internal/models"
+	"github.com/limistah/wallet-service/internal/repositories"
+	"github.com/limistah/wallet-service/internal/repositories/memory"
+)
+
+func newTestImpersonation() ImpersonationUseCase {
+	userRepo := memory.NewUserRepository()
+	userRepo.Create(context.Background(), &models.User{ID: 1, Email: "admin@example.com", Role: models.UserRoleAdmin})
+	userRepo.Create(context.Background(), &models.User{ID: 2, Email: "support@example.com", Role: models.UserRoleSupport})
+	userRepo.Create(context.Background(), &models.User{ID: 3, Email: "user@example.com", Role: models.UserRoleUser})
+	userRepo.Create(context.Background(), &models.User{ID: 4, Email: "system@example.com", Role: models.UserRoleUser, IsSystem: true})
+
+	repos := &repositories.Repositories{User: userRepo}
+	return NewImpersonationUseCase(repos, WithImpersonationTTL(10*time.Minute))
+}
+
+func TestImpersonationUseCase_SupportGetsReadOnlyAccess(t *testing.T) {
+	uc := newTestImpersonation()
+
+	grant, err := uc.StartImpersonation(context.Background(), 2, 3, false)
+	if err != nil {
+		t.Fatalf("Expected support staff to impersonate a user, got %v", err)
+	}
+	if !grant.ReadOnly {
+		t.Errorf("Expected a read-only grant")
+	}
+	if grant.User.ID != 3 || grant.Staff.ID != 2 {
+		t.Errorf("Expected staff 2 acting as user 3, got staff %d as user %d", grant.Staff.ID, grant.User.ID)
+	}
+	if grant.TTL != 10*time.Minute {
+		t.Errorf("Expected the configured TTL, got %s", grant.TTL)
+	}
+
+	if _, err := uc.StartImpersonation(context.Background(), 2, 3, true); !errors.Is(err, apperrors.ErrImpersonationForbidden) {
+		t.Errorf("Expected support staff to be refused write access, got %v", err)
+	}
+}
+
+func TestImpersonationUseCase_AdminGetsWriteAccess(t *testing.T) {
+	uc := newTestImpersonation()
+
+	grant, err := uc.StartImpersonation(context.Background(), 1, 3, true)
+	if err != nil {
+		t.Fatalf("Expected an admin to get write access, got %v", err)
+	}
+	if grant.ReadOnly {
+		t.Errorf("Expected a grant with write access")
+	}
+}
+
+func TestImpersonationUseCase_RefusesStaffAndSystemTargets(t *testing.T) {
+	uc := newTestImpersonation()
+
+	for _, target := range []uint{1, 2, 4} {
+		if _, err := uc.StartImpersonation(context.Background(), 1, target, false); !errors.Is(err, apperrors.ErrImpersonationForbidden) {
+			t.Errorf("Expected impersonating user %d to be forbidden, got %v", target, err)
+		}
+	}
+}
+
+func TestImpersonationUseCase_RefusesRegularUsersAndUnknownTargets(t *testing.T) {
+	uc := newTestImpersonation()
+
+	if _, err := uc.StartImpersonation(context.Background(), 3, 3, false); !errors.Is(err, apperrors.ErrImpersonationForbidden) {
+		t.Errorf("Expected a regular user to be refused, got %v", err)
+	}
+	if _, err := uc.StartImpersonation(context.Background(), 1, 99, false); !errors.Is(err, apperrors.ErrUserNotFound) {
+		t.Errorf("Expected user not found, got %v", err)
+	}
+}
@@ -1,56 +1,477 @@
 package usecases
 
 import (
+	"context"
+	"time"
+
+	"github.com/limistah/wallet-service/internal/events"
+	"github.com/limistah/wallet-service/internal/fraud"
+	"github.com/limistah/wallet-service/internal/fx"
 	"github.com/limistah/wallet-service/internal/models"
+	"github.com/limistah/wallet-service/internal/oauth"
+	"github.com/limistah/wallet-service/internal/payments"
 	"github.com/limistah/wallet-service/internal/repositories"
 	"github.com/shopspring/decimal"
 )
 
 // UserUseCase defines the interface for user business logic
 type UserUseCase interface {
-	CreateUser(user *models.User) (*models.User, error)
-	GetUser(id uint) (*models.User, error)
-	GetUserByID(id uint) (*models.User, error)
-	GetUserByEmail(email string) (*models.User, error)
-	UpdateUser(id uint, user *models.User) (*models.User, error)
-	DeleteUser(id uint) error
-	ListUsers(page, pageSize int) ([]models.User, error)
+	CreateUser(ctx context.Context, user *models.User) (*models.User, error)
+	GetUser(ctx context.Context, id uint) (*models.User, error)
+	GetUserByID(ctx context.Context, id uint) (*models.User, error)
+	GetUserByEmail(ctx context.Context, email string) (*models.User, error)
+	UpdateUser(ctx context.Context, id uint, user *models.User) (*models.User, error)
+	UpdateProfile(ctx context.Context, id uint, update ProfileUpdate) (*models.User, error)
+	SetAvatar(ctx context.Context, id uint, content []byte) (*models.User, error)
+	SetHandle(ctx context.Context, id uint, handle string) (*models.User, error)
+	GetAvatar(ctx context.Context, id uint) ([]byte, string, error)
+	RequestDeletion(ctx context.Context, id uint, password string) (*models.User, error)
+	AnonymizeDeletedAccounts(ctx context.Context, before time.Time) (int, error)
+	DeleteUser(ctx context.Context, id uint) error
+	ListUsers(ctx context.Context, page, pageSize int) ([]models.User, error)
+	ChangePassword(ctx context.Context, id uint, currentPassword, newPassword string) error
+	ValidatePassword(ctx context.Context, password string) error
 }
 
 // WalletUseCase defines the interface for wallet business logic
 type WalletUseCase interface {
-	CreateWallet(userID uint, currency string) (*models.Wallet, error)
-	GetWallet(id uint) (*models.Wallet, error)
-	GetWalletByUserID(userID uint) (*models.Wallet, error)
-	FundWallet(walletID uint, amount decimal.Decimal, reference, description string) (*models.Transaction, *models.Transaction, error)
-	WithdrawFunds(walletID uint, amount decimal.Decimal, reference, description string) (*models.Transaction, *models.Transaction, error)
-	TransferFunds(fromWalletID, toWalletID uint, amount decimal.Decimal, reference, description string) (*models.Transaction, *models.Transaction, error)
-	GetWalletBalance(walletID uint) (decimal.Decimal, error)
-	GetTransactionHistory(walletID uint, cursor *string, limit int) ([]models.Transaction, *string, error)
+	CreateWallet(ctx context.Context, userID uint, currency, nickname string) (*models.Wallet, error)
+	GetWallet(ctx context.Context, id uint) (*models.Wallet, error)
+	GetWalletByUserID(ctx context.Context, userID uint) (*models.Wallet, error)
+	ListUserWallets(ctx context.Context, userID uint, sandbox bool, nickname string) ([]models.Wallet, error)
+	SetWalletNickname(ctx context.Context, userID, walletID uint, nickname string) (*models.Wallet, error)
+	SetDefaultWallet(ctx context.Context, userID, walletID uint) (*models.Wallet, error)
+	ResolveHandle(ctx context.Context, handle string) (*models.Wallet, error)
+	FundWallet(ctx context.Context, walletID uint, amount decimal.Decimal, reference, description string) (*models.Transaction, *models.Transaction, error)
+	WithdrawFunds(ctx context.Context, walletID uint, amount decimal.Decimal, reference, description string, destination *models.BankAccount, origin *fraud.Origin) (*models.Transaction, *models.Transaction, error)
+	TransferFunds(ctx context.Context, fromWalletID, toWalletID uint, amount decimal.Decimal, reference, description string, origin *fraud.Origin) (*models.Transaction, *models.Transaction, error)
+	QuoteTransfer(ctx context.Context, userID, fromWalletID, toWalletID uint, amount decimal.Decimal) (*models.TransferQuote, error)
+	TransferFundsWithQuote(ctx context.Context, quoteID, fromWalletID, toWalletID uint, amount decimal.Decimal, reference, description string, origin *fraud.Origin) (*models.Transaction, *models.Transaction, error)
+	SplitFunds(ctx context.Context, fromWalletID uint, amount decimal.Decimal, recipients []SplitRecipient, reference, description string, origin *fraud.Origin) (*SplitPayment, error)
+	ScreenPayment(ctx context.Context, fromWallet, toWallet *models.Wallet, amount decimal.Decimal, reference string, origin *fraud.Origin) error
+	GetWalletBalance(ctx context.Context, walletID uint) (decimal.Decimal, error)
+	GetTransactionHistory(ctx context.Context, walletID uint, cursor *string, limit int) ([]models.Transaction, *string, error)
+	ListWallets(ctx context.Context, filter repositories.WalletFilter, page, pageSize int) ([]models.Wallet, int64, error)
+	GetTransaction(ctx context.Context, id uint) (*models.Transaction, error)
+	GetTransactionByReference(ctx context.Context, reference string) (*models.Transaction, *models.Transaction, error)
+	GetWalletTransactionByReference(ctx context.Context, walletID uint, reference string) (*models.Transaction, *models.Transaction, error)
+	GetWalletTransaction(ctx context.Context, walletID, id uint) (*models.Transaction, *models.Transaction, []models.TransactionStatusChange, error)
+	GetWalletLimits(ctx context.Context, walletID uint) (*WalletLimits, error)
+	// DisplayConversion converts amounts in currency to the display currency of the user for an
+	// indicative value, or is nil when there is nothing to convert
+	DisplayConversion(ctx context.Context, userID uint, currency string) *fx.Conversion
+	CategorizeTransaction(ctx context.Context, walletID, id uint, category string) (*models.Transaction, error)
+	ListTransactionTypes(ctx context.Context) ([]models.TransactionTypeDefinition, error)
+	SettlePayout(ctx context.Context, provider string, event payments.PayoutEvent) (*models.Payout, error)
+	GetSandboxWallet(ctx context.Context, userID uint) (*models.Wallet, error)
+	MintSandboxFunds(ctx context.Context, walletID uint, amount decimal.Decimal) (*models.Transaction, error)
+	ListFraudReviews(ctx context.Context, status models.FraudReviewStatus, page, pageSize int) ([]models.FraudReview, error)
+	ApproveFraudReview(ctx context.Context, reviewID, reviewerID uint, note string) (*models.FraudReview, error)
+	RejectFraudReview(ctx context.Context, reviewID, reviewerID uint, note string) (*models.FraudReview, error)
+	ListAMLCases(ctx context.Context, status models.AMLCaseStatus, page, pageSize int) ([]models.AMLCase, error)
+	ApproveAMLCase(ctx context.Context, caseID, reviewerID uint, note string) (*models.AMLCase, error)
+	RejectAMLCase(ctx context.Context, caseID, reviewerID uint, note string) (*models.AMLCase, error)
+	ExpirePendingTransactions(ctx context.Context, before time.Time) (int, error)
+	AuditWallet(ctx context.Context, walletID uint) error
+	DispatchQueuedPayout(ctx context.Context, reference, narration string) error
 }
 
 // ReconciliationUseCase defines the interface for reconciliation business logic
 type ReconciliationUseCase interface {
-	PerformReconciliation() ([]models.ReconciliationReport, error)
-	PerformWalletReconciliation(walletID uint) (*models.ReconciliationReport, error)
-	GetReconciliationReports(page, pageSize int) ([]models.ReconciliationReport, error)
-	GetMismatchReports(page, pageSize int) ([]models.ReconciliationReport, error)
+	PerformReconciliation(ctx context.Context) ([]models.ReconciliationReport, error)
+	StreamReconciliation(ctx context.Context, scope models.ReconciliationScope, fn func(ReconciliationProgress) error) error
+	StartRun(ctx context.Context, requestedBy uint, scope models.ReconciliationScope) (*models.ReconciliationRun, error)
+	GetRun(ctx context.Context, runID uint) (*models.ReconciliationRun, error)
+	ProcessRunBatch(ctx context.Context, runID uint) error
+	PerformWalletReconciliation(ctx context.Context, walletID uint) (*models.ReconciliationReport, error)
+	GetReconciliationReports(ctx context.Context, page, pageSize int) ([]models.ReconciliationReport, error)
+	GetMismatchReports(ctx context.Context, page, pageSize int) ([]models.ReconciliationReport, error)
+	GetWalletReconciliationReports(ctx context.Context, walletID uint) ([]models.ReconciliationReport, error)
+	AcknowledgeReport(ctx context.Context, reportID, operatorID uint, note string) (*models.ReconciliationReport, error)
+	AssignReport(ctx context.Context, reportID, assigneeID, operatorID uint) (*models.ReconciliationReport, error)
+	AddReportNote(ctx context.Context, reportID, operatorID uint, note string) (*models.ReconciliationReport, error)
+	ResolveReport(ctx context.Context, reportID, operatorID uint, resolution models.ReconciliationResolutionType, note string) (*models.ReconciliationReport, error)
+	ExportReports(ctx context.Context, filter repositories.ReconciliationReportFilter, fn func([]models.ReconciliationReport) error) error
+	SummarizeDay(ctx context.Context, day time.Time) (*models.ReconciliationSummary, error)
+	MarkSummaryDelivered(ctx context.Context, summaryID uint) error
+	GetSummaries(ctx context.Context, page, pageSize int) ([]models.ReconciliationSummary, error)
+	CheckpointBalances(ctx context.Context, before time.Time) (int, error)
+}
+
+// NotificationUseCase defines the interface for notification preference business logic
+type NotificationUseCase interface {
+	GetPreferences(ctx context.Context, userID uint) (*models.NotificationPreference, error)
+	UpdatePreferences(ctx context.Context, userID uint, preference *models.NotificationPreference) (*models.NotificationPreference, error)
+	RequestPhoneVerification(ctx context.Context, userID uint, phoneNumber string) (*models.PhoneVerification, error)
+	VerifyPhone(ctx context.Context, userID uint, code string) (*models.User, error)
+	RegisterDevice(ctx context.Context, userID uint, token string, platform models.DevicePlatform, deviceName string) (*models.DeviceToken, error)
+	ListDevices(ctx context.Context, userID uint) ([]models.DeviceToken, error)
+	RemoveDevice(ctx context.Context, userID, deviceID uint) error
+}
+
+// AuditUseCase defines the interface for audit trail business logic
+type AuditUseCase interface {
+	Record(ctx context.Context, entry *models.AuditLog) error
+	ListAuditLogs(ctx context.Context, page, pageSize int) ([]models.AuditLog, error)
+	ListAuditLogsByActor(ctx context.Context, actorID uint, page, pageSize int) ([]models.AuditLog, error)
+}
+
+// DepositUseCase defines the interface for provider funded deposit business logic
+type DepositUseCase interface {
+	CreatePendingDeposit(ctx context.Context, walletID uint, provider, externalReference string, amount decimal.Decimal) (*models.Deposit, error)
+	FundWithCard(ctx context.Context, walletID uint, amount decimal.Decimal) (*models.Deposit, string, error)
+	InitializeCheckout(ctx context.Context, walletID uint, amount decimal.Decimal) (*models.Deposit, *payments.Checkout, error)
+	ConfirmDeposit(ctx context.Context, provider string, event payments.DepositEvent) (*models.Deposit, error)
+}
+
+// MoneyRequestUseCase defines the interface for requesting money between users
+type MoneyRequestUseCase interface {
+	CreateRequest(ctx context.Context, requesterID uint, payerEmail string, amount decimal.Decimal, note string) (*models.MoneyRequest, error)
+	AcceptRequest(ctx context.Context, payerID, requestID uint, pin string, origin *fraud.Origin) (*models.MoneyRequest, *models.Transaction, error)
+	DeclineRequest(ctx context.Context, payerID, requestID uint) (*models.MoneyRequest, error)
+	CancelRequest(ctx context.Context, requesterID, requestID uint) (*models.MoneyRequest, error)
+	ListRequests(ctx context.Context, userID uint, direction models.MoneyRequestDirection, status models.MoneyRequestStatus, page, pageSize int) ([]models.MoneyRequest, error)
+}
+
+// PaymentLinkUseCase defines the interface for shareable payment link business logic
+type PaymentLinkUseCase interface {
+	CreateLink(ctx context.Context, userID uint, amount *decimal.Decimal, description string, expiresAt *time.Time, singleUse bool) (*models.PaymentLink, error)
+	ResolveLink(ctx context.Context, token string) (*models.PaymentLink, error)
+	PayLink(ctx context.Context, payerID uint, token string, amount *decimal.Decimal, pin string, origin *fraud.Origin) (*models.PaymentLink, *models.Transaction, error)
+	ListLinks(ctx context.Context, userID uint, page, pageSize int) ([]models.PaymentLink, error)
+	DisableLink(ctx context.Context, userID, linkID uint) (*models.PaymentLink, error)
+}
+
+// QRPaymentUseCase defines the interface for in-person QR code payments
+type QRPaymentUseCase interface {
+	GenerateCode(ctx context.Context, userID uint, amount *decimal.Decimal, note string) (*payments.QRPayload, error)
+	PayCode(ctx context.Context, payerID uint, rawPayload string, amount *decimal.Decimal, pin string, origin *fraud.Origin) (*models.Transaction, error)
+}
+
+// StandingOrderUseCase defines the interface for recurring transfer business logic
+type StandingOrderUseCase interface {
+	CreateStandingOrder(ctx context.Context, userID, destinationWalletID uint, amount decimal.Decimal, frequency models.StandingOrderFrequency, startAt time.Time, endsAt *time.Time, description, pin string) (*models.StandingOrder, error)
+	ListStandingOrders(ctx context.Context, userID uint) ([]models.StandingOrder, error)
+	CancelStandingOrder(ctx context.Context, userID, orderID uint) (*models.StandingOrder, error)
+	ListRuns(ctx context.Context, userID, orderID uint, page, pageSize int) ([]models.StandingOrderRun, error)
+	ProcessDue(ctx context.Context, now time.Time) (int, error)
+}
+
+// WalletTierUseCase defines the interface for wallet tier management
+type WalletTierUseCase interface {
+	CreateTier(ctx context.Context, tier *models.WalletTier) (*models.WalletTier, error)
+	UpdateTier(ctx context.Context, id uint, tier *models.WalletTier) (*models.WalletTier, error)
+	ListTiers(ctx context.Context) ([]models.WalletTier, error)
+	AssignTier(ctx context.Context, walletID uint, tierID *uint) (*models.Wallet, error)
+	GetWalletTier(ctx context.Context, walletID uint) (*models.WalletTier, error)
+}
+
+// ComplianceUseCase defines the interface for blocklist management and the compliance log
+type ComplianceUseCase interface {
+	CreateBlocklistEntry(ctx context.Context, entry *models.BlocklistEntry) (*models.BlocklistEntry, error)
+	ListBlocklistEntries(ctx context.Context, entryType models.BlocklistEntryType, page, pageSize int) ([]models.BlocklistEntry, error)
+	DeleteBlocklistEntry(ctx context.Context, id uint) error
+	ListComplianceLogs(ctx context.Context, check models.ComplianceCheck, page, pageSize int) ([]models.ComplianceLog, error)
+}
+
+// JobUseCase defines the interface for inspecting the background job queue and its dead letters
+type JobUseCase interface {
+	ListJobs(ctx context.Context, status models.JobStatus, page, pageSize int) ([]models.Job, error)
+	RetryJob(ctx context.Context, id uint) (*models.Job, error)
+}
+
+// IPAllowlistUseCase defines the interface for the CIDR ranges allowed to reach the admin endpoints
+type IPAllowlistUseCase interface {
+	Allows(ctx context.Context, ip string) (bool, error)
+	StaticCIDRs(ctx context.Context) []string
+	ListEntries(ctx context.Context) ([]models.IPAllowlistEntry, error)
+	CreateEntry(ctx context.Context, entry *models.IPAllowlistEntry, callerIP string) (*models.IPAllowlistEntry, error)
+	DeleteEntry(ctx context.Context, id uint, callerIP string) error
+}
+
+// TokenRevocationUseCase defines the interface for signing out JWTs before they expire
+type TokenRevocationUseCase interface {
+	RevokeToken(ctx context.Context, tokenID string, userID uint, expiresAt time.Time) error
+	RevokeAllTokens(ctx context.Context, userID uint) error
+	IsRevoked(ctx context.Context, tokenID string, userID uint, issuedAt time.Time) (bool, error)
+}
+
+// SessionUseCase defines the interface for the devices signed in to an account
+type SessionUseCase interface {
+	StartSession(ctx context.Context, userID uint, token TokenInfo, client ClientInfo) (*models.Session, error)
+	RefreshSession(ctx context.Context, userID uint, oldTokenID string, token TokenInfo, client ClientInfo) (*models.Session, error)
+	ListSessions(ctx context.Context, userID uint) ([]models.Session, error)
+	RevokeSession(ctx context.Context, userID, sessionID uint) error
+}
+
+// ImpersonationUseCase defines the interface for staff acting as a user to debug support cases
+type ImpersonationUseCase interface {
+	StartImpersonation(ctx context.Context, staffID, userID uint, writeAccess bool) (*ImpersonationGrant, error)
+}
+
+// OAuthUseCase defines the interface for signing in with external identity providers
+type OAuthUseCase interface {
+	SignIn(ctx context.Context, identity oauth.Identity) (*models.User, error)
+}
+
+// LoginUseCase defines the interface for password logins with account lockout and IP throttling, and
+// for the login history of users
+type LoginUseCase interface {
+	Authenticate(ctx context.Context, email, password string, client ClientInfo) (*models.User, error)
+	RecordLogin(ctx context.Context, user *models.User, method string, client ClientInfo)
+	LoginHistory(ctx context.Context, userID uint, limit int) ([]models.LoginAttempt, error)
+	UnlockUser(ctx context.Context, userID uint) (*models.User, error)
+}
+
+// PINUseCase manages the transaction PIN confirming debits
+type PINUseCase interface {
+	SetPIN(ctx context.Context, userID uint, pin string) error
+	ChangePIN(ctx context.Context, userID uint, currentPIN, newPIN string) error
+	ResetPIN(ctx context.Context, userID uint, password, newPIN string) error
+	AuthorizeDebit(ctx context.Context, userID uint, amount decimal.Decimal, pin string) error
+}
+
+// TransferChallengeUseCase holds large transfers until the user confirms them with a one-time code
+type TransferChallengeUseCase interface {
+	StartTransfer(ctx context.Context, userID, fromWalletID, toWalletID uint, amount decimal.Decimal, reference, description string, quoteID *uint) (*models.TransferChallenge, error)
+	ConfirmTransfer(ctx context.Context, userID, challengeID uint, code string, origin *fraud.Origin) (*models.Transaction, *models.Transaction, error)
+	CheckUnchallenged(ctx context.Context, amount decimal.Decimal) error
+}
+
+// LedgerUseCase manages the chart of ledger accounts and posts balanced journal entries between them
+type LedgerUseCase interface {
+	ListAccounts(ctx context.Context, sandbox bool) ([]models.LedgerAccount, error)
+	PostJournalEntry(ctx context.Context, input JournalEntryInput) (*models.JournalEntry, error)
+	GetJournalEntry(ctx context.Context, id uint) (*models.JournalEntry, error)
+}
+
+// SuspenseUseCase manages inbound credits parked in the suspense account because they matched no
+// wallet, and their reallocation or return
+type SuspenseUseCase interface {
+	ParkUnmatchedCredit(ctx context.Context, provider string, event payments.DepositEvent) (*models.SuspenseItem, error)
+	ListItems(ctx context.Context, status models.SuspenseItemStatus, page, pageSize int) ([]models.SuspenseItem, error)
+	GetItem(ctx context.Context, itemID uint) (*models.SuspenseItem, error)
+	Investigate(ctx context.Context, itemID, adminID uint, note string) (*models.SuspenseItem, error)
+	Reallocate(ctx context.Context, itemID, walletID, adminID uint, note string) (*models.SuspenseItem, error)
+	Return(ctx context.Context, itemID, adminID uint, note string) (*models.SuspenseItem, error)
+}
+
+// AdjustmentUseCase manages manual credits and debits of wallet balances, which one admin requests
+// and another approves before they are posted against the adjustments account
+type AdjustmentUseCase interface {
+	RequestAdjustment(ctx context.Context, input BalanceAdjustmentInput) (*models.BalanceAdjustment, error)
+	ListAdjustments(ctx context.Context, status models.BalanceAdjustmentStatus, page, pageSize int) ([]models.BalanceAdjustment, error)
+	GetAdjustment(ctx context.Context, adjustmentID uint) (*models.BalanceAdjustment, error)
+	ApproveAdjustment(ctx context.Context, adjustmentID, approverID uint, note string) (*models.BalanceAdjustment, error)
+	RejectAdjustment(ctx context.Context, adjustmentID, approverID uint, note string) (*models.BalanceAdjustment, error)
+}
+
+// TransactionPurposeUseCase manages the purposes transactions can be recorded with and the hooks that
+// tune their fees, limits and notifications
+type TransactionPurposeUseCase interface {
+	ListPurposes(ctx context.Context) ([]models.TransactionPurposeDefinition, error)
+	GetPurpose(ctx context.Context, name models.TransactionPurpose) (*models.TransactionPurposeDefinition, error)
+	CreatePurpose(ctx context.Context, input TransactionPurposeInput) (*models.TransactionPurposeDefinition, error)
+	UpdatePurpose(ctx context.Context, name models.TransactionPurpose, input TransactionPurposeInput) (*models.TransactionPurposeDefinition, error)
+	DeletePurpose(ctx context.Context, name models.TransactionPurpose) error
+}
+
+// SettlementUseCase groups each day's provider-facing deposits and payouts into settlement batches and
+// records what the providers actually settled against them
+type SettlementUseCase interface {
+	CloseDay(ctx context.Context, day time.Time) ([]models.SettlementBatch, error)
+	ListBatches(ctx context.Context, filter repositories.SettlementBatchFilter, page, pageSize int) ([]models.SettlementBatch, error)
+	GetBatch(ctx context.Context, batchID uint) (*models.SettlementBatch, error)
+	RecordReceived(ctx context.Context, batchID, adminID uint, received decimal.Decimal, note string) (*models.SettlementBatch, error)
+}
+
+// StatementUseCase imports provider settlement files, matches their lines to deposits and payouts and
+// queues the lines that do not match for review
+type StatementUseCase interface {
+	Import(ctx context.Context, provider, fileName string, uploadedBy uint, content []byte) (*models.ProviderStatement, error)
+	ListStatements(ctx context.Context, provider string, page, pageSize int) ([]models.ProviderStatement, error)
+	GetStatement(ctx context.Context, statementID uint) (*models.ProviderStatement, error)
+	ListLines(ctx context.Context, filter repositories.StatementLineFilter, page, pageSize int) ([]models.StatementLine, error)
+	ResolveException(ctx context.Context, lineID, adminID uint, note string) (*models.StatementLine, error)
+}
+
+// ArchiveUseCase moves old settled transactions out of the live ledger behind wallet balance checkpoints
+type ArchiveUseCase interface {
+	ArchiveTransactions(ctx context.Context, before time.Time) (*ArchiveResult, error)
+}
+
+// AccountStatementUseCase generates statements of a user's wallet in the background and hands out
+// signed links to download them
+type AccountStatementUseCase interface {
+	RequestStatement(ctx context.Context, userID uint, from, to time.Time, format models.AccountStatementFormat) (*models.AccountStatement, error)
+	GetStatement(ctx context.Context, userID, statementID uint) (*models.AccountStatement, *StatementDownload, error)
+	ListStatements(ctx context.Context, userID uint, page, pageSize int) ([]models.AccountStatement, error)
+	GenerateStatement(ctx context.Context, statementID uint) error
+	OpenDownload(ctx context.Context, key string, expires int64, signature string) ([]byte, error)
+}
+
+// BudgetUseCase defines the interface for monthly spending budgets
+type BudgetUseCase interface {
+	CreateBudget(ctx context.Context, userID uint, category string, amount decimal.Decimal) (*models.Budget, error)
+	ListBudgets(ctx context.Context, userID uint) ([]BudgetProgress, error)
+	UpdateBudget(ctx context.Context, userID, budgetID uint, amount decimal.Decimal) (*models.Budget, error)
+	DeleteBudget(ctx context.Context, userID, budgetID uint) error
+	// EvaluateBudgets compares this month's spending with every budget and alerts users who crossed
+	// a threshold; it returns how many alerts were sent
+	EvaluateBudgets(ctx context.Context, now time.Time) (int, error)
+}
+
+// TenantUseCase manages the businesses sharing the deployment
+type TenantUseCase interface {
+	CreateTenant(ctx context.Context, name, slug string) (*models.Tenant, error)
+	ListTenants(ctx context.Context) ([]models.Tenant, error)
+	ResolveTenant(ctx context.Context, slug string) (*models.Tenant, error)
+}
+
+// BusinessUseCase manages business wallets shared by members with roles, whose payments requested by
+// initiators wait for an approver
+type BusinessUseCase interface {
+	CreateBusinessAccount(ctx context.Context, ownerID uint, name string) (*models.BusinessAccount, error)
+	ListBusinessAccounts(ctx context.Context, userID uint) ([]models.BusinessAccount, error)
+	GetBusinessAccount(ctx context.Context, userID, accountID uint) (*models.BusinessAccount, models.BusinessRole, error)
+	AddMember(ctx context.Context, ownerID, accountID uint, email string, role models.BusinessRole) (*models.BusinessMember, error)
+	UpdateMemberRole(ctx context.Context, ownerID, accountID, memberUserID uint, role models.BusinessRole) (*models.BusinessMember, error)
+	RemoveMember(ctx context.Context, ownerID, accountID, memberUserID uint) error
+	RequestPayment(ctx context.Context, userID, accountID uint, payment BusinessPayment, origin *fraud.Origin) (*models.PaymentApproval, error)
+	ListApprovals(ctx context.Context, userID, accountID uint, status models.PaymentApprovalStatus, page, pageSize int) ([]models.PaymentApproval, error)
+	ApprovePayment(ctx context.Context, userID, accountID, approvalID uint, note, pin string, origin *fraud.Origin) (*models.PaymentApproval, error)
+	RejectPayment(ctx context.Context, userID, accountID, approvalID uint, note string) (*models.PaymentApproval, error)
+}
+
+// EscrowUseCase defines the interface for funds held between a payer and a payee until the payer
+// releases them or they return to the payer
+type EscrowUseCase interface {
+	CreateEscrow(ctx context.Context, payerID uint, payeeEmail string, amount decimal.Decimal, condition string, expiresAt time.Time, pin string, origin *fraud.Origin) (*models.Escrow, error)
+	ListEscrows(ctx context.Context, userID uint, status models.EscrowStatus, page, pageSize int) ([]models.Escrow, error)
+	GetEscrow(ctx context.Context, userID, escrowID uint) (*models.Escrow, error)
+	ReleaseEscrow(ctx context.Context, payerID, escrowID uint) (*models.Escrow, error)
+	CancelEscrow(ctx context.Context, userID, escrowID uint) (*models.Escrow, error)
+	ExpireEscrows(ctx context.Context, now time.Time) (int, error)
+}
+
+// SubscriptionUseCase defines the interface for plans merchants bill their subscribers' wallets for
+// every period
+type SubscriptionUseCase interface {
+	CreatePlan(ctx context.Context, merchantID uint, name, description string, amount decimal.Decimal, interval models.BillingInterval) (*models.SubscriptionPlan, error)
+	ListPlans(ctx context.Context, page, pageSize int) ([]models.SubscriptionPlan, error)
+	ListMerchantPlans(ctx context.Context, merchantID uint) ([]models.SubscriptionPlan, error)
+	GetPlan(ctx context.Context, planID uint) (*models.SubscriptionPlan, error)
+	ArchivePlan(ctx context.Context, merchantID, planID uint) (*models.SubscriptionPlan, error)
+	ListPlanSubscriptions(ctx context.Context, merchantID, planID uint, page, pageSize int) ([]models.Subscription, error)
+	Subscribe(ctx context.Context, userID, planID uint) (*models.Subscription, error)
+	ListSubscriptions(ctx context.Context, userID uint) ([]models.Subscription, error)
+	GetSubscription(ctx context.Context, userID, subscriptionID uint) (*models.Subscription, error)
+	ListCharges(ctx context.Context, userID, subscriptionID uint, page, pageSize int) ([]models.SubscriptionCharge, error)
+	CancelSubscription(ctx context.Context, userID, subscriptionID uint) (*models.Subscription, error)
+	ResumeSubscription(ctx context.Context, userID, subscriptionID uint) (*models.Subscription, error)
+	BillDue(ctx context.Context, now time.Time) (int, error)
+}
+
+// WebhookUseCase defines the interface for endpoints integrators register to receive the events of
+// their wallets, and for the deliveries of those events
+type WebhookUseCase interface {
+	CreateSubscription(ctx context.Context, userID uint, endpoint string, eventTypes []string) (*models.WebhookSubscription, error)
+	ListSubscriptions(ctx context.Context, userID uint) ([]models.WebhookSubscription, error)
+	DisableSubscription(ctx context.Context, userID, subscriptionID uint) (*models.WebhookSubscription, error)
+	ListDeliveries(ctx context.Context, userID, subscriptionID uint, page, pageSize int) ([]models.WebhookDelivery, error)
+	GetSubscriptionDelivery(ctx context.Context, userID, subscriptionID, deliveryID uint) (*models.WebhookDelivery, []models.WebhookDeliveryAttempt, error)
+	ListDeadDeliveries(ctx context.Context, page, pageSize int) ([]models.WebhookDelivery, error)
+	SearchDeliveries(ctx context.Context, filter repositories.WebhookDeliveryFilter, page, pageSize int) ([]models.WebhookDelivery, error)
+	GetDelivery(ctx context.Context, deliveryID uint) (*models.WebhookDelivery, []models.WebhookDeliveryAttempt, error)
+	Redeliver(ctx context.Context, deliveryID uint) (*models.WebhookDelivery, error)
+	EnqueueEvent(ctx context.Context, event events.Event)
+	ReplayEvents(ctx context.Context, subscriptionID uint, replay EventReplay) (int, error)
+	ReplaySubscriptionEvents(ctx context.Context, userID, subscriptionID uint, replay EventReplay) (int, error)
+	DeliverWebhook(ctx context.Context, deliveryID uint) error
+	RetryPolicy(ctx context.Context) WebhookRetryPolicy
 }
 
 // UseCases holds all use case interfaces
 type UseCases struct {
-	User           UserUseCase
-	Wallet         WalletUseCase
-	Reconciliation ReconciliationUseCase
+	User             UserUseCase
+	Wallet           WalletUseCase
+	Reconciliation   ReconciliationUseCase
+	Audit            AuditUseCase
+	Notification     NotificationUseCase
+	Deposit          DepositUseCase
+	MoneyRequest     MoneyRequestUseCase
+	PaymentLink      PaymentLinkUseCase
+	QRPayment        QRPaymentUseCase
+	StandingOrder    StandingOrderUseCase
+	WalletTier       WalletTierUseCase
+	Compliance       ComplianceUseCase
+	Job              JobUseCase
+	IPAllowlist      IPAllowlistUseCase
+	Revocation       TokenRevocationUseCase
+	OAuth            OAuthUseCase
+	Login            LoginUseCase
+	Session          SessionUseCase
+	Impersonation    ImpersonationUseCase
+	PIN              PINUseCase
+	StepUp           TransferChallengeUseCase
+	Ledger           LedgerUseCase
+	Suspense         SuspenseUseCase
+	Adjustment       AdjustmentUseCase
+	Purpose          TransactionPurposeUseCase
+	Settlement       SettlementUseCase
+	Statement        StatementUseCase
+	Archive          ArchiveUseCase
+	AccountStatement AccountStatementUseCase
+	Budget           BudgetUseCase
+	Tenant           TenantUseCase
+	Business         BusinessUseCase
+	Escrow           EscrowUseCase
+	Subscription     SubscriptionUseCase
+	Webhook          WebhookUseCase
 }
 
 // NewUseCases creates a new instance of all use cases
-func NewUseCases(repos *repositories.Repositories) *UseCases {
-	reconciliationUC := NewReconciliationUseCase(repos)
+func NewUseCases(repos *repositories.Repositories, opts ...Option) *UseCases {
+	reconciliationUC := NewReconciliationUseCase(repos, opts...)
+	walletUC := NewWalletUseCase(repos, reconciliationUC, opts...)
+	userUC := NewUserUseCase(repos, opts...)
+	revocationUC := NewTokenRevocationUseCase(repos)
 
 	return &UseCases{
-		User:           NewUserUseCase(repos),
-		Wallet:         NewWalletUseCase(repos, reconciliationUC),
-		Reconciliation: reconciliationUC,
+		User:             userUC,
+		Wallet:           walletUC,
+		Reconciliation:   reconciliationUC,
+		Audit:            NewAuditUseCase(repos),
+		Notification:     NewNotificationUseCase(repos, opts...),
+		Deposit:          NewDepositUseCase(repos, walletUC, opts...),
+		MoneyRequest:     NewMoneyRequestUseCase(repos, walletUC, opts...),
+		PaymentLink:      NewPaymentLinkUseCase(repos, walletUC, opts...),
+		QRPayment:        NewQRPaymentUseCase(repos, walletUC, opts...),
+		StandingOrder:    NewStandingOrderUseCase(repos, walletUC, opts...),
+		WalletTier:       NewWalletTierUseCase(repos),
+		Compliance:       NewComplianceUseCase(repos),
+		Job:              NewJobUseCase(repos),
+		IPAllowlist:      NewIPAllowlistUseCase(repos, opts...),
+		Revocation:       revocationUC,
+		OAuth:            NewOAuthUseCase(repos, userUC),
+		Login:            NewLoginUseCase(repos, opts...),
+		Session:          NewSessionUseCase(repos, revocationUC),
+		Impersonation:    NewImpersonationUseCase(repos, opts...),
+		PIN:              NewPINUseCase(repos, opts...),
+		StepUp:           NewTransferChallengeUseCase(repos, walletUC, opts...),
+		Ledger:           NewLedgerUseCase(repos),
+		Suspense:         NewSuspenseUseCase(repos),
+		Adjustment:       NewAdjustmentUseCase(repos),
+		Purpose:          NewTransactionPurposeUseCase(repos),
+		Settlement:       NewSettlementUseCase(repos),
+		Statement:        NewStatementUseCase(repos),
+		Archive:          NewArchiveUseCase(repos),
+		AccountStatement: NewAccountStatementUseCase(repos, opts...),
+		Budget:           NewBudgetUseCase(repos, opts...),
+		Tenant:           NewTenantUseCase(repos),
+		Business:         NewBusinessUseCase(repos, walletUC, opts...),
+		Escrow:           NewEscrowUseCase(repos, walletUC, opts...),
+		Subscription:     NewSubscriptionUseCase(repos, walletUC, opts...),
+		Webhook:          NewWebhookUseCase(repos, opts...),
 	}
 }
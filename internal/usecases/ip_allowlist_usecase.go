@@ -0,0 +1,199 @@
+package usecases
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"net"
+	"sync"
+	"time"
+
+	"github.com/limistah/wallet-service/internal/apperrors"
+	"github.com/limistah/wallet-service/internal/models"
+	"github.com/limistah/wallet-service/internal/repositories"
+	"gorm.io/gorm"
+)
+
+type ipAllowlistUseCase struct {
+	repos *repositories.Repositories
+	// static holds the ranges from configuration, which cannot be removed at runtime
+	static          []*net.IPNet
+	refreshInterval time.Duration
+	now             func() time.Time
+
+	mu       sync.Mutex
+	managed  []*net.IPNet // Ranges stored in the database as of loadedAt
+	loadedAt time.Time
+}
+
+// NewIPAllowlistUseCase creates a new IP allowlist use case
+func NewIPAllowlistUseCase(repos *repositories.Repositories, opts ...Option) IPAllowlistUseCase {
+	o := newOptions(opts)
+	uc := &ipAllowlistUseCase{
+		repos:           repos,
+		refreshInterval: o.ipAllowlistRefresh,
+		now:             time.Now,
+	}
+	for _, cidr := range o.ipAllowlist {
+		if _, network, err := net.ParseCIDR(cidr); err == nil {
+			uc.static = append(uc.static, network)
+		}
+	}
+	return uc
+}
+
+// Allows reports whether a client address may reach the admin endpoints. Every address is allowed
+// while neither configuration nor the database lists a range
+func (uc *ipAllowlistUseCase) Allows(ctx context.Context, ip string) (bool, error) {
+	managed, err := uc.managedNetworks(ctx)
+	if err != nil {
+		return false, err
+	}
+	if len(uc.static) == 0 && len(managed) == 0 {
+		return true, nil
+	}
+	return containsIP(uc.static, ip) || containsIP(managed, ip), nil
+}
+
+// StaticCIDRs returns the ranges allowed by configuration
+func (uc *ipAllowlistUseCase) StaticCIDRs(ctx context.Context) []string {
+	cidrs := make([]string, len(uc.static))
+	for i, network := range uc.static {
+		cidrs[i] = network.String()
+	}
+	return cidrs
+}
+
+func (uc *ipAllowlistUseCase) ListEntries(ctx context.Context) ([]models.IPAllowlistEntry, error) {
+	return uc.repos.IPAllowlist.List(ctx)
+}
+
+// CreateEntry allows a CIDR range. The first range added while the allowlist is empty must include
+// callerIP, so the admin adding it is not shut out
+func (uc *ipAllowlistUseCase) CreateEntry(ctx context.Context, entry *models.IPAllowlistEntry, callerIP string) (*models.IPAllowlistEntry, error) {
+	cidr, err := models.NormalizeCIDR(entry.CIDR)
+	if err != nil {
+		return nil, apperrors.ErrValidation.Withf("%s", err.Error())
+	}
+	entry.CIDR = cidr
+
+	if _, err := uc.repos.IPAllowlist.GetByCIDR(ctx, entry.CIDR); err == nil {
+		return nil, apperrors.ErrAlreadyAllowlisted
+	} else if err != gorm.ErrRecordNotFound {
+		return nil, fmt.Errorf("failed to check IP allowlist: %w", err)
+	}
+
+	managed, err := uc.reload(ctx)
+	if err != nil {
+		return nil, err
+	}
+	_, network, _ := net.ParseCIDR(entry.CIDR)
+	if len(uc.static) == 0 && len(managed) == 0 && !network.Contains(net.ParseIP(callerIP)) {
+		return nil, apperrors.ErrIPAllowlistLockout
+	}
+
+	entry.ID = 0
+	if err := uc.repos.IPAllowlist.Create(ctx, entry); err != nil {
+		return nil, fmt.Errorf("failed to create IP allowlist entry: %w", err)
+	}
+	uc.invalidate()
+	return entry, nil
+}
+
+// DeleteEntry removes an allowed range, unless the remaining ranges would no longer include callerIP
+func (uc *ipAllowlistUseCase) DeleteEntry(ctx context.Context, id uint, callerIP string) error {
+	entries, err := uc.repos.IPAllowlist.List(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to load IP allowlist: %w", err)
+	}
+
+	found := false
+	var remaining []*net.IPNet
+	for _, entry := range entries {
+		if entry.ID == id {
+			found = true
+			continue
+		}
+		if _, network, err := net.ParseCIDR(entry.CIDR); err == nil {
+			remaining = append(remaining, network)
+		}
+	}
+	if !found {
+		return apperrors.ErrIPAllowlistEntryNotFound
+	}
+	if len(uc.static)+len(remaining) > 0 && !containsIP(uc.static, callerIP) && !containsIP(remaining, callerIP) {
+		return apperrors.ErrIPAllowlistLockout
+	}
+
+	if err := uc.repos.IPAllowlist.Delete(ctx, id); err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return apperrors.ErrIPAllowlistEntryNotFound
+		}
+		return fmt.Errorf("failed to delete IP allowlist entry: %w", err)
+	}
+	uc.invalidate()
+	return nil
+}
+
+// managedNetworks returns the ranges stored in the database, reloading them once refreshInterval has
+// passed so changes made through other instances are picked up. When a reload fails the ranges
+// loaded last keep applying
+func (uc *ipAllowlistUseCase) managedNetworks(ctx context.Context) ([]*net.IPNet, error) {
+	uc.mu.Lock()
+	fresh := !uc.loadedAt.IsZero() && uc.now().Sub(uc.loadedAt) < uc.refreshInterval
+	managed, loaded := uc.managed, !uc.loadedAt.IsZero()
+	uc.mu.Unlock()
+	if fresh {
+		return managed, nil
+	}
+
+	reloaded, err := uc.reload(ctx)
+	if err != nil {
+		if loaded {
+			log.Printf("Failed to refresh IP allowlist, keeping the previous ranges: %v", err)
+			return managed, nil
+		}
+		return nil, err
+	}
+	return reloaded, nil
+}
+
+func (uc *ipAllowlistUseCase) reload(ctx context.Context) ([]*net.IPNet, error) {
+	entries, err := uc.repos.IPAllowlist.List(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load IP allowlist: %w", err)
+	}
+
+	managed := make([]*net.IPNet, 0, len(entries))
+	for _, entry := range entries {
+		if _, network, err := net.ParseCIDR(entry.CIDR); err == nil {
+			managed = append(managed, network)
+		}
+	}
+
+	uc.mu.Lock()
+	uc.managed = managed
+	uc.loadedAt = uc.now()
+	uc.mu.Unlock()
+	return managed, nil
+}
+
+// invalidate makes the next check reload the ranges so changes apply right away
+func (uc *ipAllowlistUseCase) invalidate() {
+	uc.mu.Lock()
+	uc.loadedAt = time.Time{}
+	uc.mu.Unlock()
+}
+
+func containsIP(networks []*net.IPNet, ip string) bool {
+	parsed := net.ParseIP(ip)
+	if parsed == nil {
+		return false
+	}
+	for _, network := range networks {
+		if network.Contains(parsed) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,138 @@
+package usecases
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/limistah/wallet-service/internal/apperrors"
+	"github.com/limistah/wallet-service/internal/models"
+	"github.com/limistah/wallet-service/internal/repositories"
+	"github.com/limistah/wallet-service/internal/repositories/memory"
+)
+
+// failingIPAllowlistRepository returns ListErr from List when it is set
+type failingIPAllowlistRepository struct {
+	*memory.IPAllowlistRepository
+	ListErr error
+}
+
+func (r *failingIPAllowlistRepository) List(ctx context.Context) ([]models.IPAllowlistEntry, error) {
+	if r.ListErr != nil {
+		return nil, r.ListErr
+	}
+	return r.IPAllowlistRepository.List(ctx)
+}
+
+func newTestIPAllowlist(static []string) (*ipAllowlistUseCase, *failingIPAllowlistRepository) {
+	repo := &failingIPAllowlistRepository{IPAllowlistRepository: memory.NewIPAllowlistRepository()}
+	uc := NewIPAllowlistUseCase(&repositories.Repositories{IPAllowlist: repo}, WithIPAllowlist(static, time.Minute)).(*ipAllowlistUseCase)
+	return uc, repo
+}
+
+func TestIPAllowlistUseCase_AllowsEveryoneWithoutRanges(t *testing.T) {
+	uc, _ := newTestIPAllowlist(nil)
+
+	allowed, err := uc.Allows(context.Background(), "198.51.100.7")
+	if err != nil || !allowed {
+		t.Fatalf("Expected every address to be allowed without ranges, got %v, %v", allowed, err)
+	}
+}
+
+func TestIPAllowlistUseCase_ConfiguredAndManagedRanges(t *testing.T) {
+	uc, _ := newTestIPAllowlist([]string{"10.0.0.0/8"})
+
+	entry, err := uc.CreateEntry(context.Background(), &models.IPAllowlistEntry{CIDR: " 203.0.113.9/24 ", CreatedByID: 3}, "10.1.2.3")
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if entry.CIDR != "203.0.113.0/24" {
+		t.Errorf("Expected the range to be normalized, got %s", entry.CIDR)
+	}
+
+	tests := []struct {
+		ip      string
+		allowed bool
+	}{
+		{"10.20.30.40", true},
+		{"203.0.113.200", true},
+		{"198.51.100.7", false},
+		{"not-an-ip", false},
+	}
+	for _, tt := range tests {
+		allowed, err := uc.Allows(context.Background(), tt.ip)
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		if allowed != tt.allowed {
+			t.Errorf("Expected %s allowed=%v, got %v", tt.ip, tt.allowed, allowed)
+		}
+	}
+
+	if _, err := uc.CreateEntry(context.Background(), &models.IPAllowlistEntry{CIDR: "203.0.113.0/24"}, "10.1.2.3"); !errors.Is(err, apperrors.ErrAlreadyAllowlisted) {
+		t.Errorf("Expected ErrAlreadyAllowlisted, got %v", err)
+	}
+	if _, err := uc.CreateEntry(context.Background(), &models.IPAllowlistEntry{CIDR: "203.0.113.0/33"}, "10.1.2.3"); !errors.Is(err, apperrors.ErrValidation) {
+		t.Errorf("Expected ErrValidation for an invalid range, got %v", err)
+	}
+}
+
+func TestIPAllowlistUseCase_PreventsLockout(t *testing.T) {
+	uc, _ := newTestIPAllowlist(nil)
+
+	if _, err := uc.CreateEntry(context.Background(), &models.IPAllowlistEntry{CIDR: "203.0.113.0/24"}, "198.51.100.7"); !errors.Is(err, apperrors.ErrIPAllowlistLockout) {
+		t.Fatalf("Expected the first range to be refused when it excludes the caller, got %v", err)
+	}
+
+	office, err := uc.CreateEntry(context.Background(), &models.IPAllowlistEntry{CIDR: "198.51.100.7"}, "198.51.100.7")
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	vpn, err := uc.CreateEntry(context.Background(), &models.IPAllowlistEntry{CIDR: "203.0.113.0/24"}, "198.51.100.7")
+	if err != nil {
+		t.Fatalf("Expected further ranges to be accepted, got %v", err)
+	}
+
+	if err := uc.DeleteEntry(context.Background(), office.ID, "198.51.100.7"); !errors.Is(err, apperrors.ErrIPAllowlistLockout) {
+		t.Errorf("Expected removing the caller's own range to be refused, got %v", err)
+	}
+	if err := uc.DeleteEntry(context.Background(), vpn.ID, "198.51.100.7"); err != nil {
+		t.Errorf("Expected no error, got %v", err)
+	}
+	if err := uc.DeleteEntry(context.Background(), vpn.ID, "198.51.100.7"); !errors.Is(err, apperrors.ErrIPAllowlistEntryNotFound) {
+		t.Errorf("Expected ErrIPAllowlistEntryNotFound, got %v", err)
+	}
+	// Removing the last range opens the admin endpoints to every address again
+	if err := uc.DeleteEntry(context.Background(), office.ID, "192.0.2.1"); err != nil {
+		t.Errorf("Expected the last range to be removable, got %v", err)
+	}
+}
+
+func TestIPAllowlistUseCase_RefreshesManagedRanges(t *testing.T) {
+	uc, repo := newTestIPAllowlist([]string{"10.0.0.0/8"})
+	now := time.Now()
+	uc.now = func() time.Time { return now }
+
+	if allowed, _ := uc.Allows(context.Background(), "203.0.113.5"); allowed {
+		t.Fatal("Expected the address to be refused")
+	}
+
+	// Another instance allows the range
+	repo.Create(context.Background(), &models.IPAllowlistEntry{CIDR: "203.0.113.0/24"})
+	if allowed, _ := uc.Allows(context.Background(), "203.0.113.5"); allowed {
+		t.Error("Expected the cached ranges to apply until the refresh interval passes")
+	}
+
+	now = now.Add(2 * time.Minute)
+	if allowed, _ := uc.Allows(context.Background(), "203.0.113.5"); !allowed {
+		t.Error("Expected the range added elsewhere to apply after a refresh")
+	}
+
+	// A failed refresh keeps the ranges loaded last
+	repo.ListErr = errors.New("connection refused")
+	now = now.Add(2 * time.Minute)
+	if allowed, err := uc.Allows(context.Background(), "203.0.113.5"); err != nil || !allowed {
+		t.Errorf("Expected the previous ranges to keep applying, got %v, %v", allowed, err)
+	}
+}
@@ -0,0 +1,128 @@
+package usecases
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/limistah/wallet-service/internal/apperrors"
+	"github.com/limistah/wallet-service/internal/models"
+	"github.com/limistah/wallet-service/internal/queue"
+	"github.com/limistah/wallet-service/internal/repositories"
+)
+
+const (
+	// JobWalletAudit reconciles a wallet after a transaction for the audit trail
+	JobWalletAudit = "wallet.audit"
+	// JobDispatchPayout submits a payout held back while the payout provider's circuit was open
+	JobDispatchPayout = "payments.dispatch_payout"
+	// JobGenerateStatement renders a requested account statement to blob storage
+	JobGenerateStatement = "statements.generate"
+	// JobDeliverWebhook sends one attempt of a webhook delivery
+	JobDeliverWebhook = "webhooks.deliver"
+	// JobReconciliationRunBatch reconciles the next batch of wallets of a bulk reconciliation run
+	JobReconciliationRunBatch = "reconciliation.run_batch"
+)
+
+// walletAuditJob is the payload of a JobWalletAudit job
+type walletAuditJob struct {
+	WalletID uint `json:"wallet_id"`
+}
+
+// dispatchPayoutJob is the payload of a JobDispatchPayout job
+type dispatchPayoutJob struct {
+	Reference string `json:"reference"`
+	Narration string `json:"narration"`
+}
+
+// generateStatementJob is the payload of a JobGenerateStatement job
+type generateStatementJob struct {
+	StatementID uint `json:"statement_id"`
+}
+
+// deliverWebhookJob is the payload of a JobDeliverWebhook job
+type deliverWebhookJob struct {
+	DeliveryID uint `json:"delivery_id"`
+}
+
+// reconciliationRunBatchJob is the payload of a JobReconciliationRunBatch job
+type reconciliationRunBatchJob struct {
+	RunID uint `json:"run_id"`
+}
+
+// RegisterJobs registers the handlers of the jobs the use cases enqueue. Jobs run outside any request,
+// and the queue lets those in flight finish when it stops, so they run with a background context
+func RegisterJobs(q *queue.Queue, useCases *UseCases) {
+	q.Register(JobWalletAudit, func(payload []byte) error {
+		var job walletAuditJob
+		if err := queue.Decode(payload, &job); err != nil {
+			return err
+		}
+		return useCases.Wallet.AuditWallet(context.Background(), job.WalletID)
+	})
+	q.Register(JobDispatchPayout, func(payload []byte) error {
+		var job dispatchPayoutJob
+		if err := queue.Decode(payload, &job); err != nil {
+			return err
+		}
+		return useCases.Wallet.DispatchQueuedPayout(context.Background(), job.Reference, job.Narration)
+	})
+	q.Register(JobGenerateStatement, func(payload []byte) error {
+		var job generateStatementJob
+		if err := queue.Decode(payload, &job); err != nil {
+			return err
+		}
+		return useCases.AccountStatement.GenerateStatement(context.Background(), job.StatementID)
+	})
+	q.Register(JobReconciliationRunBatch, func(payload []byte) error {
+		var job reconciliationRunBatchJob
+		if err := queue.Decode(payload, &job); err != nil {
+			return err
+		}
+		return useCases.Reconciliation.ProcessRunBatch(context.Background(), job.RunID)
+	})
+	// Webhook deliveries follow their own retry policy, and their attempts stay in the delivery history
+	q.RegisterWithRetry(JobDeliverWebhook, func(payload []byte) error {
+		var job deliverWebhookJob
+		if err := queue.Decode(payload, &job); err != nil {
+			return err
+		}
+		return useCases.Webhook.DeliverWebhook(context.Background(), job.DeliveryID)
+	}, useCases.Webhook.RetryPolicy(context.Background()).queueRetry())
+}
+
+type jobUseCase struct {
+	repos *repositories.Repositories
+}
+
+// NewJobUseCase creates a new job use case
+func NewJobUseCase(repos *repositories.Repositories) JobUseCase {
+	return &jobUseCase{repos: repos}
+}
+
+func (uc *jobUseCase) ListJobs(ctx context.Context, status models.JobStatus, page, pageSize int) ([]models.Job, error) {
+	if page < 1 {
+		page = 1
+	}
+	if pageSize < 1 || pageSize > 100 {
+		pageSize = 20
+	}
+	return uc.repos.Job.List(ctx, status, (page-1)*pageSize, pageSize)
+}
+
+// RetryJob takes a job out of the dead letter queue and runs it again with a fresh set of attempts
+func (uc *jobUseCase) RetryJob(ctx context.Context, id uint) (*models.Job, error) {
+	job, err := uc.repos.Job.GetByID(ctx, id)
+	if err != nil {
+		return nil, apperrors.ErrJobNotFound
+	}
+	if !job.IsDead() {
+		return nil, apperrors.ErrJobNotDead
+	}
+
+	if err := uc.repos.Job.Requeue(ctx, job.ID, time.Now()); err != nil {
+		return nil, fmt.Errorf("failed to retry job: %w", err)
+	}
+
+	return uc.repos.Job.GetByID(ctx, job.ID)
+}
@@ -0,0 +1,197 @@
+package usecases
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/limistah/wallet-service/internal/apperrors"
+	"github.com/limistah/wallet-service/internal/models"
+	"github.com/limistah/wallet-service/internal/repositories"
+	"github.com/shopspring/decimal"
+	"gorm.io/gorm"
+)
+
+// JournalLeg is one side of a journal entry posted to a ledger account
+type JournalLeg struct {
+	Account models.LedgerAccountCode
+	Type    models.TransactionType
+	Amount  decimal.Decimal
+}
+
+// JournalEntryInput is a manual journal entry between ledger accounts, such as funding the interest
+// account from cash
+type JournalEntryInput struct {
+	Reference   string
+	Description string
+	Sandbox     bool // Posts to the ledger accounts of the sandbox system account
+	PostedBy    uint
+	Purpose     models.TransactionPurpose // Recorded on every leg; ADJUSTMENT when empty
+	Legs        []JournalLeg
+}
+
+// postingLeg is a leg of a journal entry resolved to the wallet whose balance it moves
+type postingLeg struct {
+	WalletID    uint
+	Type        models.TransactionType
+	Amount      decimal.Decimal
+	Purpose     models.TransactionPurpose
+	Description string
+}
+
+type ledgerUseCase struct {
+	repos *repositories.Repositories
+}
+
+// NewLedgerUseCase creates a new ledger use case
+func NewLedgerUseCase(repos *repositories.Repositories) LedgerUseCase {
+	return &ledgerUseCase{repos: repos}
+}
+
+func (uc *ledgerUseCase) ListAccounts(ctx context.Context, sandbox bool) ([]models.LedgerAccount, error) {
+	return uc.repos.LedgerAccount.List(ctx, sandbox)
+}
+
+// PostJournalEntry writes a balanced multi-leg journal entry between ledger accounts
+func (uc *ledgerUseCase) PostJournalEntry(ctx context.Context, input JournalEntryInput) (*models.JournalEntry, error) {
+	input.Reference = strings.TrimSpace(input.Reference)
+	if input.Reference == "" {
+		return nil, apperrors.ErrValidation.Withf("reference is required")
+	}
+	if _, err := uc.repos.JournalEntry.GetByReference(ctx, input.Reference); err == nil {
+		return nil, apperrors.ErrDuplicateReference
+	} else if !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, fmt.Errorf("error checking reference: %w", err)
+	}
+
+	if input.Purpose == "" {
+		input.Purpose = models.TransactionPurposeAdjustment
+	}
+	if _, err := purposeDefinition(ctx, uc.repos, input.Purpose); err != nil {
+		return nil, err
+	}
+
+	legs := make([]postingLeg, len(input.Legs))
+	for i, leg := range input.Legs {
+		account, err := uc.repos.LedgerAccount.GetByCode(ctx, leg.Account, input.Sandbox)
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, apperrors.ErrLedgerAccountNotFound.Withf("ledger account %s not found", leg.Account)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to load ledger account %s: %w", leg.Account, err)
+		}
+		legs[i] = postingLeg{
+			WalletID:    account.WalletID,
+			Type:        leg.Type,
+			Amount:      leg.Amount,
+			Purpose:     input.Purpose,
+			Description: fmt.Sprintf("%s: %s", account.Name, input.Description),
+		}
+	}
+
+	entry := &models.JournalEntry{
+		Reference:   input.Reference,
+		Description: input.Description,
+	}
+	if input.PostedBy != 0 {
+		entry.PostedBy = &input.PostedBy
+	}
+
+	err := uc.repos.UnitOfWork.Do(ctx, func(tx *repositories.Repositories) error {
+		return postJournalEntry(ctx, tx, entry, legs)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return uc.repos.JournalEntry.GetByID(ctx, entry.ID)
+}
+
+func (uc *ledgerUseCase) GetJournalEntry(ctx context.Context, id uint) (*models.JournalEntry, error) {
+	entry, err := uc.repos.JournalEntry.GetByID(ctx, id)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, apperrors.ErrJournalEntryNotFound
+	}
+	return entry, err
+}
+
+// validateJournalLegs checks that a journal entry has at least two legs of positive amounts whose
+// debits and credits add up to the same total
+func validateJournalLegs(legs []postingLeg) error {
+	if len(legs) < 2 {
+		return apperrors.ErrUnbalancedJournalEntry.Withf("a journal entry needs at least two legs")
+	}
+
+	debits, credits := decimal.Zero, decimal.Zero
+	for _, leg := range legs {
+		if !leg.Amount.IsPositive() {
+			return apperrors.ErrInvalidAmount
+		}
+		switch leg.Type {
+		case models.TransactionTypeDebit:
+			debits = debits.Add(leg.Amount)
+		case models.TransactionTypeCredit:
+			credits = credits.Add(leg.Amount)
+		default:
+			return apperrors.ErrValidation.Withf("invalid leg type %q", leg.Type)
+		}
+	}
+	if !debits.Equal(credits) {
+		return apperrors.ErrUnbalancedJournalEntry.Withf("debits %s do not match credits %s", debits.String(), credits.String())
+	}
+	return nil
+}
+
+// postJournalEntry is the posting engine: it writes a balanced journal entry with one completed
+// transaction per leg and applies each leg to its wallet balance, all in the unit of work of tx
+func postJournalEntry(ctx context.Context, tx *repositories.Repositories, entry *models.JournalEntry, legs []postingLeg) error {
+	if err := validateJournalLegs(legs); err != nil {
+		return err
+	}
+
+	if err := tx.JournalEntry.Create(ctx, entry); err != nil {
+		return fmt.Errorf("failed to create journal entry: %w", err)
+	}
+
+	for i, leg := range legs {
+		wallet, err := tx.Wallet.GetByID(ctx, leg.WalletID)
+		if err != nil {
+			return fmt.Errorf("failed to load wallet %d: %w", leg.WalletID, err)
+		}
+		if !wallet.IsActive() {
+			return apperrors.ErrWalletInactive
+		}
+
+		change := leg.Amount
+		if leg.Type == models.TransactionTypeDebit {
+			if wallet.Balance.LessThan(leg.Amount) {
+				return apperrors.ErrInsufficientFunds.Withf("insufficient funds in wallet %d for journal entry %s", wallet.ID, entry.Reference)
+			}
+			change = leg.Amount.Neg()
+		}
+
+		balanceBefore, err := adjustBalance(ctx, tx.Wallet, wallet.ID, change)
+		if err != nil {
+			return err
+		}
+
+		transaction := &models.Transaction{
+			Reference:          fmt.Sprintf("%s-%d", entry.Reference, i+1),
+			WalletID:           wallet.ID,
+			TransactionType:    leg.Type,
+			TransactionPurpose: leg.Purpose,
+			Amount:             leg.Amount,
+			Metadata:           `{"source": "journal"}`,
+			BalanceBefore:      balanceBefore,
+			BalanceAfter:       balanceBefore.Add(change),
+			Description:        leg.Description,
+			Status:             models.TransactionStatusCompleted,
+			JournalEntryID:     &entry.ID,
+		}
+		if err := tx.Transaction.Create(ctx, transaction); err != nil {
+			return fmt.Errorf("failed to create journal leg: %w", err)
+		}
+	}
+	return nil
+}
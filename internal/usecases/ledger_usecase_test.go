@@ -0,0 +1,110 @@
+package usecases
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/limistah/wallet-service/internal/apperrors"
+	"github.com/limistah/wallet-service/internal/models"
+	"github.com/limistah/wallet-service/internal/repositories/memory"
+	"github.com/shopspring/decimal"
+)
+
+func TestValidateJournalLegs(t *testing.T) {
+	leg := func(transactionType models.TransactionType, amount float64) postingLeg {
+		return postingLeg{WalletID: 1, Type: transactionType, Amount: decimal.NewFromFloat(amount)}
+	}
+
+	cases := []struct {
+		name     string
+		legs     []postingLeg
+		expected error
+	}{
+		{"balanced pair", []postingLeg{leg(models.TransactionTypeDebit, 50), leg(models.TransactionTypeCredit, 50)}, nil},
+		{"balanced multi-leg", []postingLeg{
+			leg(models.TransactionTypeDebit, 100),
+			leg(models.TransactionTypeCredit, 70),
+			leg(models.TransactionTypeCredit, 30),
+		}, nil},
+		{"single leg", []postingLeg{leg(models.TransactionTypeDebit, 50)}, apperrors.ErrUnbalancedJournalEntry},
+		{"unbalanced", []postingLeg{leg(models.TransactionTypeDebit, 50), leg(models.TransactionTypeCredit, 40)}, apperrors.ErrUnbalancedJournalEntry},
+		{"zero amount", []postingLeg{leg(models.TransactionTypeDebit, 0), leg(models.TransactionTypeCredit, 0)}, apperrors.ErrInvalidAmount},
+		{"invalid type", []postingLeg{leg(models.TransactionTypeDebit, 50), leg("REFUND", 50)}, apperrors.ErrValidation},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			err := validateJournalLegs(tc.legs)
+			if tc.expected == nil && err != nil {
+				t.Errorf("Expected no error, got: %v", err)
+			}
+			if tc.expected != nil && !errors.Is(err, tc.expected) {
+				t.Errorf("Expected %v, got: %v", tc.expected, err)
+			}
+		})
+	}
+}
+
+func TestLedgerUseCase_PostJournalEntry(t *testing.T) {
+	setup := func() (*memory.LedgerAccountRepository, *memory.JournalEntryRepository, LedgerUseCase) {
+		repos := setupReconciliationTestEnvironment()
+		accounts := memory.NewLedgerAccountRepository(nil)
+		accounts.Create(context.Background(), &models.LedgerAccount{Code: models.LedgerAccountCash, Name: "Cash", Type: models.LedgerAccountTypeAsset, WalletID: 1})
+		accounts.Create(context.Background(), &models.LedgerAccount{Code: models.LedgerAccountInterest, Name: "Interest expense", Type: models.LedgerAccountTypeExpense, WalletID: 2})
+		entries := memory.NewJournalEntryRepository(repos.Transaction.(*memory.TransactionRepository))
+		repos.LedgerAccount = accounts
+		repos.JournalEntry = entries
+		return accounts, entries, NewLedgerUseCase(repos)
+	}
+	legs := []JournalLeg{
+		{Account: models.LedgerAccountCash, Type: models.TransactionTypeDebit, Amount: decimal.NewFromFloat(500)},
+		{Account: models.LedgerAccountInterest, Type: models.TransactionTypeCredit, Amount: decimal.NewFromFloat(500)},
+	}
+
+	t.Run("should require a reference", func(t *testing.T) {
+		_, _, ledgerUC := setup()
+		_, err := ledgerUC.PostJournalEntry(context.Background(), JournalEntryInput{Reference: "  ", Legs: legs})
+		if !errors.Is(err, apperrors.ErrValidation) {
+			t.Errorf("Expected validation error, got: %v", err)
+		}
+	})
+
+	t.Run("should reject a duplicate reference", func(t *testing.T) {
+		_, entries, ledgerUC := setup()
+		entries.Create(context.Background(), &models.JournalEntry{Reference: "JE-1"})
+		_, err := ledgerUC.PostJournalEntry(context.Background(), JournalEntryInput{Reference: "JE-1", Legs: legs})
+		if !errors.Is(err, apperrors.ErrDuplicateReference) {
+			t.Errorf("Expected duplicate reference error, got: %v", err)
+		}
+	})
+
+	t.Run("should reject an unknown ledger account", func(t *testing.T) {
+		_, _, ledgerUC := setup()
+		_, err := ledgerUC.PostJournalEntry(context.Background(), JournalEntryInput{
+			Reference: "JE-2",
+			Legs: []JournalLeg{
+				{Account: models.LedgerAccountCash, Type: models.TransactionTypeDebit, Amount: decimal.NewFromFloat(10)},
+				{Account: models.LedgerAccountSuspense, Type: models.TransactionTypeCredit, Amount: decimal.NewFromFloat(10)},
+			},
+		})
+		if !errors.Is(err, apperrors.ErrLedgerAccountNotFound) {
+			t.Errorf("Expected ledger account not found, got: %v", err)
+		}
+	})
+
+	t.Run("should reject an unknown purpose", func(t *testing.T) {
+		_, _, ledgerUC := setup()
+		_, err := ledgerUC.PostJournalEntry(context.Background(), JournalEntryInput{Reference: "JE-3", Purpose: "CASHBACK", Legs: legs})
+		if !errors.Is(err, apperrors.ErrTransactionPurposeNotFound) {
+			t.Errorf("Expected transaction purpose not found, got: %v", err)
+		}
+	})
+
+	t.Run("should report a missing journal entry", func(t *testing.T) {
+		_, _, ledgerUC := setup()
+		if _, err := ledgerUC.GetJournalEntry(context.Background(), 42); !errors.Is(err, apperrors.ErrJournalEntryNotFound) {
+			t.Errorf("Expected journal entry not found, got: %v", err)
+		}
+	})
+}
@@ -0,0 +1,88 @@
+package usecases
+
+import (
+	"strconv"
+	"sync/atomic"
+
+	"github.com/shopspring/decimal"
+)
+
+// RuntimeSettings are the settings an operator can change while the service runs, without a restart
+type RuntimeSettings struct {
+	Login LoginPolicy
+	PIN   PINPolicy
+	// TransferChallenge is nil when transfers are not held for a one-time code
+	TransferChallenge *TransferChallengePolicy
+	AMLThreshold      decimal.Decimal
+}
+
+// LiveSettings holds the current RuntimeSettings, shared by the use cases of every tenant. The use
+// cases read it on every operation, so a reload applies to the next login or debit
+type LiveSettings struct {
+	current atomic.Pointer[RuntimeSettings]
+}
+
+// NewLiveSettings creates a holder starting with settings
+func NewLiveSettings(settings RuntimeSettings) *LiveSettings {
+	l := &LiveSettings{}
+	l.Store(settings)
+	return l
+}
+
+// Load returns the current settings
+func (l *LiveSettings) Load() RuntimeSettings {
+	return *l.current.Load()
+}
+
+// Store replaces the settings and returns the ones they replace
+func (l *LiveSettings) Store(settings RuntimeSettings) RuntimeSettings {
+	if settings.TransferChallenge != nil {
+		policy := *settings.TransferChallenge
+		settings.TransferChallenge = &policy
+	}
+	previous := l.current.Swap(&settings)
+	if previous == nil {
+		return RuntimeSettings{}
+	}
+	return *previous
+}
+
+// SettingChange is the old and new value of a reloaded setting
+type SettingChange struct {
+	From string `json:"from"`
+	To   string `json:"to"`
+}
+
+// Changes lists the settings that differ from previous by name, for the audit entry of a reload
+func (s RuntimeSettings) Changes(previous RuntimeSettings) map[string]SettingChange {
+	before, after := previous.values(), s.values()
+	changes := make(map[string]SettingChange)
+	for name, value := range after {
+		if before[name] != value {
+			changes[name] = SettingChange{From: before[name], To: value}
+		}
+	}
+	return changes
+}
+
+// values renders the settings by name; the transfer challenge settings are empty when transfers are
+// not challenged
+func (s RuntimeSettings) values() map[string]string {
+	values := map[string]string{
+		"login.max_failures":           strconv.Itoa(s.Login.MaxFailures),
+		"login.cooldown":               s.Login.Cooldown.String(),
+		"login.ip_max_failures":        strconv.Itoa(s.Login.IPMaxFailures),
+		"login.ip_window":              s.Login.IPWindow.String(),
+		"pin.threshold":                s.PIN.Threshold.String(),
+		"pin.max_attempts":             strconv.Itoa(s.PIN.MaxAttempts),
+		"pin.cooldown":                 s.PIN.Cooldown.String(),
+		"transfer_challenge.threshold": "",
+		"transfer_challenge.ttl":       "",
+		"aml.threshold":                s.AMLThreshold.String(),
+	}
+	if s.TransferChallenge != nil {
+		values["transfer_challenge.threshold"] = s.TransferChallenge.Threshold.String()
+		values["transfer_challenge.ttl"] = s.TransferChallenge.TTL.String()
+	}
+	return values
+}
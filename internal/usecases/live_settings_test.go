@@ -0,0 +1,55 @@
+package usecases
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+func TestLiveSettings_ReloadAppliesToNextOperation(t *testing.T) {
+	settings := NewLiveSettings(RuntimeSettings{})
+	uc := NewTransferChallengeUseCase(nil, nil, WithLiveSettings(settings))
+
+	if err := uc.CheckUnchallenged(context.Background(), decimal.NewFromInt(5000)); err != nil {
+		t.Fatalf("Expected payments to go through without a step-up policy, got %v", err)
+	}
+
+	policy := TransferChallengePolicy{Threshold: decimal.NewFromInt(1000), TTL: 5 * time.Minute}
+	settings.Store(RuntimeSettings{TransferChallenge: &policy})
+	policy.Threshold = decimal.NewFromInt(10000) // The stored settings must not follow the caller's copy
+
+	if err := uc.CheckUnchallenged(context.Background(), decimal.NewFromInt(5000)); err == nil {
+		t.Error("Expected payments above the reloaded threshold to be refused")
+	}
+}
+
+func TestRuntimeSettings_Changes(t *testing.T) {
+	previous := RuntimeSettings{
+		Login:        LoginPolicy{MaxFailures: 5, Cooldown: 15 * time.Minute},
+		PIN:          PINPolicy{Threshold: decimal.Zero, MaxAttempts: 5},
+		AMLThreshold: decimal.NewFromInt(10000),
+	}
+	next := previous
+	next.Login.MaxFailures = 3
+	next.TransferChallenge = &TransferChallengePolicy{Threshold: decimal.NewFromInt(1000), TTL: 5 * time.Minute}
+
+	changes := next.Changes(previous)
+	want := map[string]SettingChange{
+		"login.max_failures":           {From: "5", To: "3"},
+		"transfer_challenge.threshold": {From: "", To: "1000"},
+		"transfer_challenge.ttl":       {From: "", To: "5m0s"},
+	}
+	if len(changes) != len(want) {
+		t.Fatalf("Changes() = %+v, want %+v", changes, want)
+	}
+	for name, change := range want {
+		if changes[name] != change {
+			t.Errorf("Changes()[%q] = %+v, want %+v", name, changes[name], change)
+		}
+	}
+	if changes := previous.Changes(previous); len(changes) != 0 {
+		t.Errorf("Expected no changes between equal settings, got %+v", changes)
+	}
+}
@@ -0,0 +1,232 @@
+package usecases
+
+import (
+	"context"
+	"errors"
+	"log"
+	"time"
+
+	"github.com/limistah/wallet-service/internal/apperrors"
+	"github.com/limistah/wallet-service/internal/events"
+	"github.com/limistah/wallet-service/internal/models"
+	"github.com/limistah/wallet-service/internal/repositories"
+	"gorm.io/gorm"
+)
+
+// LoginPolicy controls account lockout and the throttling of IP addresses on password logins
+type LoginPolicy struct {
+	// MaxFailures wrong passwords in a row lock the account for Cooldown; 0 disables lockout
+	MaxFailures int
+	Cooldown    time.Duration
+	// IPMaxFailures failed logins from an IP address within IPWindow refuse its further attempts until
+	// older failures leave the window; 0 disables throttling
+	IPMaxFailures int
+	IPWindow      time.Duration
+}
+
+// DefaultLoginPolicy locks an account for 15 minutes after 5 wrong passwords, and throttles IP
+// addresses after 20 failed logins within 15 minutes
+func DefaultLoginPolicy() LoginPolicy {
+	return LoginPolicy{
+		MaxFailures:   5,
+		Cooldown:      15 * time.Minute,
+		IPMaxFailures: 20,
+		IPWindow:      15 * time.Minute,
+	}
+}
+
+// ClientInfo describes the client a login comes from
+type ClientInfo struct {
+	IPAddress string
+	UserAgent string
+}
+
+// maxUserAgentLength is the size of the user agent column of login attempts
+const maxUserAgentLength = 512
+
+type loginUseCase struct {
+	repos     *repositories.Repositories
+	settings  *LiveSettings
+	publisher events.EventPublisher
+	now       func() time.Time
+}
+
+// NewLoginUseCase creates a new login use case
+func NewLoginUseCase(repos *repositories.Repositories, opts ...Option) LoginUseCase {
+	o := newOptions(opts)
+	return &loginUseCase{
+		repos:     repos,
+		settings:  o.settings,
+		publisher: o.publisher,
+		now:       time.Now,
+	}
+}
+
+// Authenticate checks the password of the account with the given email. Every attempt is recorded
+// with the client's IP address and user agent; wrong passwords count towards locking the account
+func (uc *loginUseCase) Authenticate(ctx context.Context, email, password string, client ClientInfo) (*models.User, error) {
+	now := uc.now()
+	ipAddress := client.IPAddress
+
+	policy := uc.settings.Load().Login
+	if policy.IPMaxFailures > 0 {
+		failures, err := uc.repos.LoginAttempt.CountFailuresByIP(ctx, ipAddress, now.Add(-policy.IPWindow))
+		if err != nil {
+			return nil, err
+		}
+		if failures >= int64(policy.IPMaxFailures) {
+			return nil, apperrors.ErrTooManyLoginAttempts
+		}
+	}
+
+	user, err := uc.repos.User.GetByEmail(ctx, email)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		uc.recordAttempt(ctx, nil, email, models.LoginMethodPassword, client, false)
+		return nil, apperrors.ErrInvalidCredentials
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if user.IsLocked(now) {
+		uc.recordAttempt(ctx, &user.ID, email, models.LoginMethodPassword, client, false)
+		return nil, lockedError(*user.LockedUntil)
+	}
+
+	if err := user.CheckPassword(password); err != nil {
+		uc.recordAttempt(ctx, &user.ID, email, models.LoginMethodPassword, client, false)
+		return nil, uc.countFailure(ctx, user, policy, ipAddress, now)
+	}
+
+	// Only the owner of the account learns that it is being deleted
+	if user.IsDeletionRequested() {
+		uc.recordAttempt(ctx, &user.ID, email, models.LoginMethodPassword, client, false)
+		return nil, apperrors.ErrAccountDeleted
+	}
+
+	uc.RecordLogin(ctx, user, models.LoginMethodPassword, client)
+	if user.FailedLoginAttempts > 0 || user.LockedUntil != nil {
+		if err := uc.repos.User.SetLoginLock(ctx, user.ID, nil); err != nil {
+			return nil, err
+		}
+		user.FailedLoginAttempts = 0
+		user.LockedUntil = nil
+	}
+	return user, nil
+}
+
+// RecordLogin records a successful login and tells the user when it comes from a device never used
+// to log in before. The first login of an account is not reported, as every device is new then
+func (uc *loginUseCase) RecordLogin(ctx context.Context, user *models.User, method string, client ClientInfo) {
+	client.UserAgent = truncateUserAgent(client.UserAgent)
+
+	newDevice, err := uc.isNewDevice(ctx, user.ID, client.UserAgent)
+	if err != nil {
+		log.Printf("Failed to check the login device of user %d: %v", user.ID, err)
+	}
+	uc.recordAttempt(ctx, &user.ID, user.Email, method, client, true)
+
+	if newDevice {
+		uc.publisher.Publish(events.Event{
+			Type:   events.EventNewDeviceLogin,
+			UserID: user.ID,
+			Data: map[string]string{
+				"ip_address": client.IPAddress,
+				"user_agent": client.UserAgent,
+				"method":     method,
+			},
+			OccurredAt: uc.now(),
+		})
+	}
+}
+
+// LoginHistory returns the latest login attempts on an account, newest first
+func (uc *loginUseCase) LoginHistory(ctx context.Context, userID uint, limit int) ([]models.LoginAttempt, error) {
+	return uc.repos.LoginAttempt.ListByUser(ctx, userID, limit)
+}
+
+// isNewDevice tells whether a user who logged in before never did so with the user agent
+func (uc *loginUseCase) isNewDevice(ctx context.Context, userID uint, userAgent string) (bool, error) {
+	known, err := uc.repos.LoginAttempt.HasSucceededWithUserAgent(ctx, userID, userAgent)
+	if err != nil || known {
+		return false, err
+	}
+	return uc.repos.LoginAttempt.HasSucceeded(ctx, userID)
+}
+
+// UnlockUser lifts the lock of an account and clears its failed login count
+func (uc *loginUseCase) UnlockUser(ctx context.Context, userID uint) (*models.User, error) {
+	user, err := uc.repos.User.GetByID(ctx, userID)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, apperrors.ErrUserNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if err := uc.repos.User.SetLoginLock(ctx, user.ID, nil); err != nil {
+		return nil, err
+	}
+	user.FailedLoginAttempts = 0
+	user.LockedUntil = nil
+	return user, nil
+}
+
+// countFailure counts a wrong password and locks the account once it reaches the limit, telling the
+// user about it
+func (uc *loginUseCase) countFailure(ctx context.Context, user *models.User, policy LoginPolicy, ipAddress string, now time.Time) error {
+	if policy.MaxFailures <= 0 {
+		return apperrors.ErrInvalidCredentials
+	}
+
+	failures, err := uc.repos.User.IncrementFailedLogins(ctx, user.ID)
+	if err != nil {
+		return err
+	}
+	if failures < policy.MaxFailures {
+		return apperrors.ErrInvalidCredentials
+	}
+
+	lockedUntil := now.Add(policy.Cooldown)
+	if err := uc.repos.User.SetLoginLock(ctx, user.ID, &lockedUntil); err != nil {
+		return err
+	}
+	uc.publisher.Publish(events.Event{
+		Type:   events.EventAccountLocked,
+		UserID: user.ID,
+		Data: map[string]string{
+			"ip_address":   ipAddress,
+			"locked_until": lockedUntil.UTC().Format("2006-01-02 15:04:05 MST"),
+		},
+		OccurredAt: now,
+	})
+	return lockedError(lockedUntil)
+}
+
+// recordAttempt stores a login attempt; failing to store it does not fail the login
+func (uc *loginUseCase) recordAttempt(ctx context.Context, userID *uint, email, method string, client ClientInfo, succeeded bool) {
+	err := uc.repos.LoginAttempt.Create(ctx, &models.LoginAttempt{
+		UserID:    userID,
+		Email:     email,
+		IPAddress: client.IPAddress,
+		UserAgent: truncateUserAgent(client.UserAgent),
+		Method:    method,
+		Succeeded: succeeded,
+	})
+	if err != nil {
+		log.Printf("Failed to record login attempt for %s from %s: %v", email, client.IPAddress, err)
+	}
+}
+
+// truncateUserAgent cuts user agents to the size of their column
+func truncateUserAgent(userAgent string) string {
+	if len(userAgent) > maxUserAgentLength {
+		return userAgent[:maxUserAgentLength]
+	}
+	return userAgent
+}
+
+// lockedError tells the user until when the account is locked
+func lockedError(lockedUntil time.Time) error {
+	return apperrors.ErrAccountLocked.Withf("account is locked until %s after too many failed logins", lockedUntil.UTC().Format(time.RFC3339))
+}
@@ -0,0 +1,208 @@
+package usecases
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/limistah/wallet-service/internal/apperrors"
+	"github.com/limistah/wallet-service/internal/events"
+	"github.com/limistah/wallet-service/internal/models"
+	"github.com/limistah/wallet-service/internal/repositories"
+	"github.com/limistah/wallet-service/internal/repositories/memory"
+)
+
+func newTestLoginUseCase(t *testing.T, now *time.Time, policy LoginPolicy) (*loginUseCase, *recordingPublisher) {
+	t.Helper()
+	user := &models.User{ID: 1, Email: "user@example.com"}
+	if err := user.HashPassword("correct-password"); err != nil {
+		t.Fatalf("Failed to hash password: %v", err)
+	}
+	userRepo := memory.NewUserRepository()
+	userRepo.Create(context.Background(), user)
+
+	repos := &repositories.Repositories{
+		User:         userRepo,
+		LoginAttempt: memory.NewLoginAttemptRepository(now),
+	}
+	publisher := &recordingPublisher{}
+	uc := NewLoginUseCase(repos, WithEventPublisher(publisher), WithLoginPolicy(policy)).(*loginUseCase)
+	uc.now = func() time.Time { return *now }
+	return uc, publisher
+}
+
+func TestLoginUseCase_LocksAccountAfterMaxFailures(t *testing.T) {
+	now := time.Date(2025, 3, 1, 12, 0, 0, 0, time.UTC)
+	uc, publisher := newTestLoginUseCase(t, &now, LoginPolicy{MaxFailures: 3, Cooldown: 15 * time.Minute})
+
+	for i := 0; i < 2; i++ {
+		if _, err := uc.Authenticate(context.Background(), "user@example.com", "wrong", ClientInfo{IPAddress: "10.0.0.1"}); !errors.Is(err, apperrors.ErrInvalidCredentials) {
+			t.Fatalf("Expected invalid credentials on failure %d, got %v", i+1, err)
+		}
+	}
+	if _, err := uc.Authenticate(context.Background(), "user@example.com", "wrong", ClientInfo{IPAddress: "10.0.0.1"}); !errors.Is(err, apperrors.ErrAccountLocked) {
+		t.Fatalf("Expected the account to be locked on the third failure, got %v", err)
+	}
+
+	if len(publisher.events) != 1 || publisher.events[0].Type != events.EventAccountLocked {
+		t.Fatalf("Expected one account locked event, got %+v", publisher.events)
+	}
+	if publisher.events[0].Data["ip_address"] != "10.0.0.1" {
+		t.Errorf("Expected the event to name the IP address, got %+v", publisher.events[0].Data)
+	}
+
+	// The right password is refused until the cooldown is over
+	if _, err := uc.Authenticate(context.Background(), "user@example.com", "correct-password", ClientInfo{IPAddress: "10.0.0.1"}); !errors.Is(err, apperrors.ErrAccountLocked) {
+		t.Errorf("Expected the locked account to refuse the right password, got %v", err)
+	}
+
+	now = now.Add(16 * time.Minute)
+	user, err := uc.Authenticate(context.Background(), "user@example.com", "correct-password", ClientInfo{IPAddress: "10.0.0.1"})
+	if err != nil {
+		t.Fatalf("Expected login after the cooldown, got %v", err)
+	}
+	if user.FailedLoginAttempts != 0 || user.LockedUntil != nil {
+		t.Errorf("Expected the lock to be cleared, got %d failures locked until %v", user.FailedLoginAttempts, user.LockedUntil)
+	}
+}
+
+func TestLoginUseCase_SuccessResetsFailures(t *testing.T) {
+	now := time.Date(2025, 3, 1, 12, 0, 0, 0, time.UTC)
+	uc, _ := newTestLoginUseCase(t, &now, LoginPolicy{MaxFailures: 3, Cooldown: 15 * time.Minute})
+
+	uc.Authenticate(context.Background(), "user@example.com", "wrong", ClientInfo{IPAddress: "10.0.0.1"})
+	uc.Authenticate(context.Background(), "user@example.com", "wrong", ClientInfo{IPAddress: "10.0.0.1"})
+	if _, err := uc.Authenticate(context.Background(), "user@example.com", "correct-password", ClientInfo{IPAddress: "10.0.0.1"}); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	uc.Authenticate(context.Background(), "user@example.com", "wrong", ClientInfo{IPAddress: "10.0.0.1"})
+	if _, err := uc.Authenticate(context.Background(), "user@example.com", "wrong", ClientInfo{IPAddress: "10.0.0.1"}); !errors.Is(err, apperrors.ErrInvalidCredentials) {
+		t.Errorf("Expected failures before a successful login not to count, got %v", err)
+	}
+}
+
+func TestLoginUseCase_UnknownEmail(t *testing.T) {
+	now := time.Date(2025, 3, 1, 12, 0, 0, 0, time.UTC)
+	uc, _ := newTestLoginUseCase(t, &now, DefaultLoginPolicy())
+
+	if _, err := uc.Authenticate(context.Background(), "nobody@example.com", "password", ClientInfo{IPAddress: "10.0.0.1"}); !errors.Is(err, apperrors.ErrInvalidCredentials) {
+		t.Errorf("Expected invalid credentials for an unknown email, got %v", err)
+	}
+}
+
+func TestLoginUseCase_DeletedAccount(t *testing.T) {
+	now := time.Date(2025, 3, 1, 12, 0, 0, 0, time.UTC)
+	uc, _ := newTestLoginUseCase(t, &now, DefaultLoginPolicy())
+	user, _ := uc.repos.User.GetByID(context.Background(), 1)
+	user.DeletionRequestedAt = &now
+	uc.repos.User.Update(context.Background(), user)
+
+	if _, err := uc.Authenticate(context.Background(), "user@example.com", "wrong", ClientInfo{IPAddress: "10.0.0.1"}); !errors.Is(err, apperrors.ErrInvalidCredentials) {
+		t.Errorf("Expected a wrong password not to reveal the deletion, got %v", err)
+	}
+	if _, err := uc.Authenticate(context.Background(), "user@example.com", "correct-password", ClientInfo{IPAddress: "10.0.0.1"}); !errors.Is(err, apperrors.ErrAccountDeleted) {
+		t.Errorf("Expected ErrAccountDeleted for an account scheduled for deletion, got %v", err)
+	}
+}
+
+func TestLoginUseCase_ThrottlesIPAddress(t *testing.T) {
+	now := time.Date(2025, 3, 1, 12, 0, 0, 0, time.UTC)
+	uc, _ := newTestLoginUseCase(t, &now, LoginPolicy{IPMaxFailures: 3, IPWindow: 10 * time.Minute})
+
+	for i := 0; i < 3; i++ {
+		uc.Authenticate(context.Background(), "nobody@example.com", "password", ClientInfo{IPAddress: "10.0.0.1"})
+	}
+	if _, err := uc.Authenticate(context.Background(), "user@example.com", "correct-password", ClientInfo{IPAddress: "10.0.0.1"}); !errors.Is(err, apperrors.ErrTooManyLoginAttempts) {
+		t.Errorf("Expected the IP address to be throttled, got %v", err)
+	}
+	if _, err := uc.Authenticate(context.Background(), "user@example.com", "correct-password", ClientInfo{IPAddress: "10.0.0.2"}); err != nil {
+		t.Errorf("Expected other IP addresses to log in, got %v", err)
+	}
+
+	now = now.Add(11 * time.Minute)
+	if _, err := uc.Authenticate(context.Background(), "user@example.com", "correct-password", ClientInfo{IPAddress: "10.0.0.1"}); err != nil {
+		t.Errorf("Expected the IP address to log in once the failures left the window, got %v", err)
+	}
+}
+
+func TestLoginUseCase_UnlockUser(t *testing.T) {
+	now := time.Date(2025, 3, 1, 12, 0, 0, 0, time.UTC)
+	uc, _ := newTestLoginUseCase(t, &now, LoginPolicy{MaxFailures: 1, Cooldown: time.Hour})
+
+	if _, err := uc.Authenticate(context.Background(), "user@example.com", "wrong", ClientInfo{IPAddress: "10.0.0.1"}); !errors.Is(err, apperrors.ErrAccountLocked) {
+		t.Fatalf("Expected the account to be locked, got %v", err)
+	}
+	if _, err := uc.UnlockUser(context.Background(), 1); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if _, err := uc.Authenticate(context.Background(), "user@example.com", "correct-password", ClientInfo{IPAddress: "10.0.0.1"}); err != nil {
+		t.Errorf("Expected login after unlocking, got %v", err)
+	}
+
+	if _, err := uc.UnlockUser(context.Background(), 99); !errors.Is(err, apperrors.ErrUserNotFound) {
+		t.Errorf("Expected user not found, got %v", err)
+	}
+}
+
+func TestLoginUseCase_NotifiesNewDevice(t *testing.T) {
+	now := time.Date(2025, 3, 1, 12, 0, 0, 0, time.UTC)
+	uc, publisher := newTestLoginUseCase(t, &now, DefaultLoginPolicy())
+	laptop := ClientInfo{IPAddress: "10.0.0.1", UserAgent: "Mozilla/5.0 (Macintosh)"}
+	phone := ClientInfo{IPAddress: "10.0.0.2", UserAgent: "WalletApp/2.1 (iPhone)"}
+
+	for _, client := range []ClientInfo{laptop, laptop} {
+		if _, err := uc.Authenticate(context.Background(), "user@example.com", "correct-password", client); err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+	}
+	if len(publisher.events) != 0 {
+		t.Fatalf("Expected the first login and known devices not to be reported, got %+v", publisher.events)
+	}
+
+	// A failed attempt does not make the device known
+	uc.Authenticate(context.Background(), "user@example.com", "wrong", phone)
+	if _, err := uc.Authenticate(context.Background(), "user@example.com", "correct-password", phone); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if len(publisher.events) != 1 || publisher.events[0].Type != events.EventNewDeviceLogin {
+		t.Fatalf("Expected one new device event, got %+v", publisher.events)
+	}
+	if publisher.events[0].Data["user_agent"] != phone.UserAgent || publisher.events[0].Data["ip_address"] != phone.IPAddress {
+		t.Errorf("Expected the event to describe the device, got %+v", publisher.events[0].Data)
+	}
+
+	user, _ := uc.repos.User.GetByID(context.Background(), 1)
+	uc.RecordLogin(context.Background(), user, "google", phone)
+	if len(publisher.events) != 1 {
+		t.Errorf("Expected a provider sign in from a known device not to be reported, got %+v", publisher.events)
+	}
+}
+
+func TestLoginUseCase_LoginHistory(t *testing.T) {
+	now := time.Date(2025, 3, 1, 12, 0, 0, 0, time.UTC)
+	uc, _ := newTestLoginUseCase(t, &now, DefaultLoginPolicy())
+	client := ClientInfo{IPAddress: "10.0.0.1", UserAgent: "Mozilla/5.0"}
+
+	uc.Authenticate(context.Background(), "user@example.com", "wrong", client)
+	uc.Authenticate(context.Background(), "user@example.com", "correct-password", client)
+	uc.Authenticate(context.Background(), "nobody@example.com", "password", client)
+
+	history, err := uc.LoginHistory(context.Background(), 1, 10)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if len(history) != 2 {
+		t.Fatalf("Expected the 2 attempts on the account, got %d", len(history))
+	}
+	if !history[0].Succeeded || history[1].Succeeded {
+		t.Errorf("Expected the newest attempt first, got %+v", history)
+	}
+	if history[0].UserAgent != client.UserAgent || history[0].Method != models.LoginMethodPassword {
+		t.Errorf("Expected the device and method to be recorded, got %+v", history[0])
+	}
+
+	if history, _ := uc.LoginHistory(context.Background(), 1, 1); len(history) != 1 {
+		t.Errorf("Expected the limit to apply, got %d entries", len(history))
+	}
+}
@@ -0,0 +1,224 @@
+package usecases
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/limistah/wallet-service/internal/apperrors"
+	"github.com/limistah/wallet-service/internal/config"
+	"github.com/limistah/wallet-service/internal/models"
+	"github.com/limistah/wallet-service/internal/payments"
+	"github.com/limistah/wallet-service/internal/repositories"
+	"github.com/limistah/wallet-service/internal/repositories/memory"
+	"github.com/shopspring/decimal"
+)
+
+// setupMemoryDriver opens the repositories used with DB_DRIVER=memory and adds a payer and a payee
+// with USD wallets
+func setupMemoryDriver(t *testing.T) (*repositories.Repositories, *models.Wallet, *models.Wallet) {
+	t.Helper()
+	ctx := context.Background()
+	repos, err := memory.Open(ctx, config.AppConfig{})
+	if err != nil {
+		t.Fatalf("Open() error = %v", err)
+	}
+	payer := &models.User{Email: "payer@example.com", Name: "Payer"}
+	payee := &models.User{Email: "payee@example.com", Name: "Payee"}
+	repos.User.Create(ctx, payer)
+	repos.User.Create(ctx, payee)
+	payerWallet := &models.Wallet{UserID: payer.ID, Balance: decimal.NewFromInt(500), Currency: "USD", Status: models.WalletStatusActive}
+	payeeWallet := &models.Wallet{UserID: payee.ID, Currency: "USD", Status: models.WalletStatusActive}
+	repos.Wallet.Create(ctx, payerWallet)
+	repos.Wallet.Create(ctx, payeeWallet)
+	return repos, payerWallet, payeeWallet
+}
+
+// holdTransfer records a transfer of amount from one wallet to another that is held for a fraud
+// review, with the amount already taken from the sender
+func holdTransfer(t *testing.T, repos *repositories.Repositories, from, to uint, amount decimal.Decimal) *models.FraudReview {
+	t.Helper()
+	ctx := context.Background()
+	debit := &models.Transaction{WalletID: from, Reference: "TXN-HELD-" + decimal.NewFromInt(int64(to)).String(), Amount: amount,
+		TransactionType: models.TransactionTypeDebit, TransactionPurpose: models.TransactionPurposeTransfer, Status: models.TransactionStatusPending}
+	repos.Transaction.Create(ctx, debit)
+	repos.Transaction.Create(ctx, &models.Transaction{WalletID: to, Reference: debit.Reference + "-CR", Amount: amount, RelatedTransactionID: &debit.ID,
+		TransactionType: models.TransactionTypeCredit, TransactionPurpose: models.TransactionPurposeTransfer, Status: models.TransactionStatusPending})
+	repos.Wallet.AdjustBalance(ctx, from, amount.Neg())
+	review := &models.FraudReview{TransactionID: debit.ID, WalletID: from, Purpose: models.TransactionPurposeTransfer, CounterpartyWalletID: &to,
+		Amount: amount, Currency: "USD", Rule: "velocity", Status: models.FraudReviewStatusPending}
+	repos.FraudReview.Create(ctx, review)
+	return review
+}
+
+func expectBalance(t *testing.T, repos *repositories.Repositories, walletID uint, expected int64) {
+	t.Helper()
+	wallet, err := repos.Wallet.GetByID(context.Background(), walletID)
+	if err != nil {
+		t.Fatalf("Expected wallet %d, got: %v", walletID, err)
+	}
+	if !wallet.Balance.Equal(decimal.NewFromInt(expected)) {
+		t.Errorf("Expected wallet %d to hold %d, got %s", walletID, expected, wallet.Balance)
+	}
+}
+
+func TestMemoryDriver_RunsUnitsOfWork(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("holds", func(t *testing.T) {
+		repos, payer, payee := setupMemoryDriver(t)
+		walletUC := NewWalletUseCase(repos, NewReconciliationUseCase(repos))
+
+		approved := holdTransfer(t, repos, payer.ID, payee.ID, decimal.NewFromInt(100))
+		if _, err := walletUC.ApproveFraudReview(ctx, approved.ID, 9, ""); err != nil {
+			t.Fatalf("ApproveFraudReview() error = %v", err)
+		}
+		expectBalance(t, repos, payer.ID, 400)
+		expectBalance(t, repos, payee.ID, 100)
+
+		rejected := holdTransfer(t, repos, payer.ID, payer.ID, decimal.NewFromInt(50))
+		if _, err := walletUC.RejectFraudReview(ctx, rejected.ID, 9, ""); err != nil {
+			t.Fatalf("RejectFraudReview() error = %v", err)
+		}
+		expectBalance(t, repos, payer.ID, 400)
+		if transaction, _ := repos.Transaction.GetByID(ctx, rejected.TransactionID); transaction.Status != models.TransactionStatusFailed {
+			t.Errorf("Expected the rejected debit to fail, got %s", transaction.Status)
+		}
+	})
+
+	t.Run("failed hold decisions roll back", func(t *testing.T) {
+		repos, payer, _ := setupMemoryDriver(t)
+		walletUC := NewWalletUseCase(repos, NewReconciliationUseCase(repos))
+
+		// The recipient wallet is gone, so crediting it fails after the review was resolved
+		review := holdTransfer(t, repos, payer.ID, 404, decimal.NewFromInt(100))
+		if _, err := walletUC.ApproveFraudReview(ctx, review.ID, 9, ""); err == nil {
+			t.Fatal("Expected the approval to fail")
+		}
+		if stored, _ := repos.FraudReview.GetByID(ctx, review.ID); !stored.IsPending() {
+			t.Errorf("Expected the review to stay pending, got %s", stored.Status)
+		}
+		if transaction, _ := repos.Transaction.GetByID(ctx, review.TransactionID); transaction.Status != models.TransactionStatusPending {
+			t.Errorf("Expected the debit to stay pending, got %s", transaction.Status)
+		}
+		if changes, _ := repos.TransactionStatusChange.ListByTransactionID(ctx, review.TransactionID); len(changes) != 0 {
+			t.Errorf("Expected no status history, got %d entries", len(changes))
+		}
+		expectBalance(t, repos, payer.ID, 400)
+	})
+
+	t.Run("payouts and expiry", func(t *testing.T) {
+		repos, payer, _ := setupMemoryDriver(t)
+		walletUC := NewWalletUseCase(repos, NewReconciliationUseCase(repos))
+		for _, reference := range []string{"TXN-PAYOUT", "TXN-EXPIRED"} {
+			repos.Transaction.Create(ctx, &models.Transaction{WalletID: payer.ID, Reference: reference, Amount: decimal.NewFromInt(100),
+				TransactionType: models.TransactionTypeDebit, TransactionPurpose: models.TransactionPurposeWithdrawal,
+				Status: models.TransactionStatusPending, CreatedAt: time.Now().Add(-96 * time.Hour)})
+			repos.Wallet.AdjustBalance(ctx, payer.ID, decimal.NewFromInt(-100))
+		}
+		debit, _ := repos.Transaction.GetByReference(ctx, "TXN-PAYOUT")
+		repos.Payout.Create(ctx, &models.Payout{WalletID: payer.ID, TransactionID: debit.ID, Reference: debit.Reference, Provider: "paystack",
+			Amount: debit.Amount, Currency: "USD", ProviderReference: "PSK-1", Status: models.PayoutStatusPending})
+
+		payout, err := walletUC.SettlePayout(ctx, "paystack", payments.PayoutEvent{Reference: "TXN-PAYOUT", Status: payments.PayoutEventFailed, FailureReason: "account closed"})
+		if err != nil {
+			t.Fatalf("SettlePayout() error = %v", err)
+		}
+		if payout.Status != models.PayoutStatusFailed {
+			t.Errorf("Expected the payout to fail, got %s", payout.Status)
+		}
+		expectBalance(t, repos, payer.ID, 400)
+
+		expired, err := walletUC.ExpirePendingTransactions(ctx, time.Now().Add(-72*time.Hour))
+		if err != nil || expired != 1 {
+			t.Fatalf("Expected 1 transaction to expire, got %d, %v", expired, err)
+		}
+		expectBalance(t, repos, payer.ID, 500)
+	})
+
+	t.Run("escrow", func(t *testing.T) {
+		repos, payer, payee := setupMemoryDriver(t)
+		escrowUC := NewEscrowUseCase(repos, &screeningWalletUseCase{})
+
+		escrow, err := escrowUC.CreateEscrow(ctx, payer.UserID, "payee@example.com", decimal.NewFromInt(120), "delivery", time.Now().Add(time.Hour), "", nil)
+		if err != nil {
+			t.Fatalf("CreateEscrow() error = %v", err)
+		}
+		expectBalance(t, repos, payer.ID, 380)
+		if _, err := escrowUC.ReleaseEscrow(ctx, payer.UserID, escrow.ID); err != nil {
+			t.Fatalf("ReleaseEscrow() error = %v", err)
+		}
+		expectBalance(t, repos, payee.ID, 120)
+		if _, err := escrowUC.ReleaseEscrow(ctx, payer.UserID, escrow.ID); !errors.Is(err, apperrors.ErrEscrowSettled) {
+			t.Errorf("Expected the escrow to be settled once, got: %v", err)
+		}
+	})
+
+	t.Run("ledger, suspense and adjustments", func(t *testing.T) {
+		repos, payer, _ := setupMemoryDriver(t)
+
+		entry, err := NewLedgerUseCase(repos).PostJournalEntry(ctx, JournalEntryInput{Reference: "JE-1", Legs: []JournalLeg{
+			{Account: models.LedgerAccountCash, Type: models.TransactionTypeDebit, Amount: decimal.NewFromInt(30)},
+			{Account: models.LedgerAccountInterest, Type: models.TransactionTypeCredit, Amount: decimal.NewFromInt(30)},
+		}})
+		if err != nil {
+			t.Fatalf("PostJournalEntry() error = %v", err)
+		}
+		if len(entry.Legs) != 2 {
+			t.Errorf("Expected 2 journal legs, got %d", len(entry.Legs))
+		}
+
+		suspenseUC := NewSuspenseUseCase(repos)
+		item, err := suspenseUC.ParkUnmatchedCredit(ctx, "paystack", payments.DepositEvent{ExternalReference: "PSK-2", Amount: decimal.NewFromInt(75), Currency: "USD", Status: payments.DepositEventSucceeded})
+		if err != nil {
+			t.Fatalf("ParkUnmatchedCredit() error = %v", err)
+		}
+		if _, err := suspenseUC.Reallocate(ctx, item.ID, payer.ID, 9, "matched by hand"); err != nil {
+			t.Fatalf("Reallocate() error = %v", err)
+		}
+		expectBalance(t, repos, payer.ID, 575)
+
+		adjustmentUC := NewAdjustmentUseCase(repos)
+		adjustment, err := adjustmentUC.RequestAdjustment(ctx, BalanceAdjustmentInput{WalletID: payer.ID, Type: models.TransactionTypeDebit, Amount: decimal.NewFromInt(25), Reason: "duplicate credit", RequestedBy: 9})
+		if err != nil {
+			t.Fatalf("RequestAdjustment() error = %v", err)
+		}
+		if _, err := adjustmentUC.ApproveAdjustment(ctx, adjustment.ID, 10, ""); err != nil {
+			t.Fatalf("ApproveAdjustment() error = %v", err)
+		}
+		expectBalance(t, repos, payer.ID, 550)
+	})
+
+	t.Run("settlement and archive", func(t *testing.T) {
+		repos, payer, _ := setupMemoryDriver(t)
+		now := time.Now().UTC()
+		repos.Deposit.Create(ctx, &models.Deposit{WalletID: payer.ID, Reference: "DEP-1", Provider: "paystack", Amount: decimal.NewFromInt(200), Currency: "USD", Status: models.DepositStatusCompleted, CompletedAt: &now})
+		repos.Payout.Create(ctx, &models.Payout{WalletID: payer.ID, Reference: "PAY-1", Provider: "paystack", Amount: decimal.NewFromInt(50), Currency: "USD", Status: models.PayoutStatusCompleted, CompletedAt: &now})
+
+		batches, err := NewSettlementUseCase(repos).CloseDay(ctx, now)
+		if err != nil {
+			t.Fatalf("CloseDay() error = %v", err)
+		}
+		if len(batches) != 1 || !batches[0].ExpectedNet.Equal(decimal.NewFromInt(150)) {
+			t.Fatalf("Expected one batch expecting 150, got %+v", batches)
+		}
+		if batch, _ := repos.SettlementBatch.GetByID(ctx, batches[0].ID); len(batch.Deposits) != 1 || len(batch.Payouts) != 1 {
+			t.Errorf("Expected the batch to hold the deposit and the payout, got %+v", batch)
+		}
+
+		old := time.Now().AddDate(-2, 0, 0)
+		repos.Transaction.Create(ctx, &models.Transaction{WalletID: payer.ID, Reference: "TXN-OLD", Amount: decimal.NewFromInt(500),
+			TransactionType: models.TransactionTypeCredit, Status: models.TransactionStatusCompleted, CreatedAt: old})
+		result, err := NewArchiveUseCase(repos).ArchiveTransactions(ctx, time.Now().AddDate(-1, 0, 0))
+		if err != nil {
+			t.Fatalf("ArchiveTransactions() error = %v", err)
+		}
+		if result.Archived != 1 {
+			t.Errorf("Expected the old transaction to be archived, got %+v", result)
+		}
+		if _, err := repos.Transaction.GetByReference(ctx, "TXN-OLD"); err == nil {
+			t.Error("Expected the archived transaction to leave the live table")
+		}
+	})
+}
@@ -0,0 +1,206 @@
+package usecases
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strconv"
+	"time"
+
+	"github.com/limistah/wallet-service/internal/apperrors"
+	"github.com/limistah/wallet-service/internal/events"
+	"github.com/limistah/wallet-service/internal/fraud"
+	"github.com/limistah/wallet-service/internal/models"
+	"github.com/limistah/wallet-service/internal/repositories"
+	"github.com/limistah/wallet-service/internal/utils"
+	"github.com/shopspring/decimal"
+)
+
+type moneyRequestUseCase struct {
+	repos         *repositories.Repositories
+	walletUseCase WalletUseCase
+	publisher     events.EventPublisher
+	authorizer    debitAuthorizer
+}
+
+// NewMoneyRequestUseCase creates a new money request use case
+func NewMoneyRequestUseCase(repos *repositories.Repositories, walletUseCase WalletUseCase, opts ...Option) MoneyRequestUseCase {
+	o := newOptions(opts)
+	return &moneyRequestUseCase{
+		repos:         repos,
+		walletUseCase: walletUseCase,
+		publisher:     o.publisher,
+		authorizer:    newDebitAuthorizer(repos, o),
+	}
+}
+
+func (uc *moneyRequestUseCase) CreateRequest(ctx context.Context, requesterID uint, payerEmail string, amount decimal.Decimal, note string) (*models.MoneyRequest, error) {
+	if amount.LessThanOrEqual(decimal.Zero) {
+		return nil, apperrors.ErrInvalidAmount
+	}
+
+	requester, err := uc.repos.User.GetByID(ctx, requesterID)
+	if err != nil {
+		return nil, apperrors.ErrUserNotFound
+	}
+
+	payer, err := uc.repos.User.GetByEmail(ctx, utils.NormalizeEmail(payerEmail))
+	if err != nil || payer.IsSystemAccount() {
+		return nil, apperrors.ErrUserNotFound.Withf("payer not found")
+	}
+	if payer.ID == requester.ID {
+		return nil, apperrors.ErrSelfPayment.Withf("cannot request money from yourself")
+	}
+
+	requesterWallet, err := uc.walletUseCase.GetWalletByUserID(ctx, requester.ID)
+	if err != nil {
+		return nil, apperrors.ErrWalletNotFound
+	}
+	payerWallet, err := uc.walletUseCase.GetWalletByUserID(ctx, payer.ID)
+	if err != nil {
+		return nil, apperrors.ErrCounterpartyWalletNotFound.Withf("payer wallet not found")
+	}
+	if payerWallet.Currency != requesterWallet.Currency {
+		return nil, apperrors.ErrCurrencyMismatch.Withf("payer wallet currency does not match")
+	}
+
+	request := &models.MoneyRequest{
+		RequesterID: requester.ID,
+		PayerID:     payer.ID,
+		Amount:      amount,
+		Currency:    requesterWallet.Currency,
+		Note:        note,
+		Status:      models.MoneyRequestStatusPending,
+		Requester:   *requester,
+		Payer:       *payer,
+	}
+	if err := uc.repos.MoneyRequest.Create(ctx, request); err != nil {
+		return nil, fmt.Errorf("failed to create money request: %w", err)
+	}
+
+	uc.publisher.Publish(events.Event{
+		Type:      events.EventMoneyRequested,
+		UserID:    payer.ID,
+		WalletID:  payerWallet.ID,
+		Reference: request.Reference(),
+		Amount:    amount,
+		Currency:  request.Currency,
+		Reason:    note,
+		Data: map[string]string{
+			"request_id":      strconv.FormatUint(uint64(request.ID), 10),
+			"requester_name":  requester.Name,
+			"requester_email": requester.Email,
+		},
+		OccurredAt: time.Now(),
+	})
+
+	return request, nil
+}
+
+// AcceptRequest pays a pending request from the payer's wallet into the requester's wallet; the
+// request is claimed before the transfer so concurrent accepts cannot pay it twice. pin authorizes the
+// payment
+func (uc *moneyRequestUseCase) AcceptRequest(ctx context.Context, payerID, requestID uint, pin string, origin *fraud.Origin) (*models.MoneyRequest, *models.Transaction, error) {
+	request, err := uc.getRequest(ctx, requestID, func(r *models.MoneyRequest) bool { return r.PayerID == payerID })
+	if err != nil {
+		return nil, nil, err
+	}
+
+	payerWallet, err := uc.walletUseCase.GetWalletByUserID(ctx, request.PayerID)
+	if err != nil {
+		return nil, nil, apperrors.ErrWalletNotFound
+	}
+	requesterWallet, err := uc.walletUseCase.GetWalletByUserID(ctx, request.RequesterID)
+	if err != nil {
+		return nil, nil, apperrors.ErrCounterpartyWalletNotFound.Withf("requester wallet not found")
+	}
+	if err := uc.authorizer.authorizeUnchallenged(ctx, payerID, request.Amount, pin); err != nil {
+		return nil, nil, err
+	}
+
+	if err := uc.transition(ctx, request, models.MoneyRequestStatusAccepted); err != nil {
+		return nil, nil, err
+	}
+
+	description := fmt.Sprintf("Payment for money request #%d", request.ID)
+	if request.Note != "" {
+		description = fmt.Sprintf("%s: %s", description, request.Note)
+	}
+
+	outTx, _, err := uc.walletUseCase.TransferFunds(ctx, payerWallet.ID, requesterWallet.ID, request.Amount, request.Reference(), description, origin)
+	if err != nil {
+		// Reopen the request so the payer can retry once the problem is fixed
+		if _, revertErr := uc.repos.MoneyRequest.UpdateStatus(ctx, request.ID, models.MoneyRequestStatusAccepted, models.MoneyRequestStatusPending); revertErr != nil {
+			log.Printf("Failed to reopen money request %d after transfer error: %v", request.ID, revertErr)
+		}
+		return nil, nil, err
+	}
+
+	if err := uc.repos.MoneyRequest.SetTransaction(ctx, request.ID, outTx.ID); err != nil {
+		log.Printf("Failed to link transaction %d to money request %d: %v", outTx.ID, request.ID, err)
+	}
+	request.TransactionID = &outTx.ID
+
+	return request, outTx, nil
+}
+
+func (uc *moneyRequestUseCase) DeclineRequest(ctx context.Context, payerID, requestID uint) (*models.MoneyRequest, error) {
+	request, err := uc.getRequest(ctx, requestID, func(r *models.MoneyRequest) bool { return r.PayerID == payerID })
+	if err != nil {
+		return nil, err
+	}
+	if err := uc.transition(ctx, request, models.MoneyRequestStatusDeclined); err != nil {
+		return nil, err
+	}
+	return request, nil
+}
+
+func (uc *moneyRequestUseCase) CancelRequest(ctx context.Context, requesterID, requestID uint) (*models.MoneyRequest, error) {
+	request, err := uc.getRequest(ctx, requestID, func(r *models.MoneyRequest) bool { return r.RequesterID == requesterID })
+	if err != nil {
+		return nil, err
+	}
+	if err := uc.transition(ctx, request, models.MoneyRequestStatusCancelled); err != nil {
+		return nil, err
+	}
+	return request, nil
+}
+
+func (uc *moneyRequestUseCase) ListRequests(ctx context.Context, userID uint, direction models.MoneyRequestDirection, status models.MoneyRequestStatus, page, pageSize int) ([]models.MoneyRequest, error) {
+	if page < 1 {
+		page = 1
+	}
+	if pageSize < 1 || pageSize > 100 {
+		pageSize = 20
+	}
+	return uc.repos.MoneyRequest.ListByUser(ctx, userID, direction, status, (page-1)*pageSize, pageSize)
+}
+
+// getRequest loads a pending request visible to the caller; requests belonging to other users
+// are reported as not found
+func (uc *moneyRequestUseCase) getRequest(ctx context.Context, requestID uint, owns func(*models.MoneyRequest) bool) (*models.MoneyRequest, error) {
+	request, err := uc.repos.MoneyRequest.GetByID(ctx, requestID)
+	if err != nil || !owns(request) {
+		return nil, apperrors.ErrMoneyRequestNotFound
+	}
+	if !request.IsPending() {
+		return nil, apperrors.ErrMoneyRequestNotPending
+	}
+	return request, nil
+}
+
+// transition moves a pending request to its final status
+func (uc *moneyRequestUseCase) transition(ctx context.Context, request *models.MoneyRequest, status models.MoneyRequestStatus) error {
+	updated, err := uc.repos.MoneyRequest.UpdateStatus(ctx, request.ID, models.MoneyRequestStatusPending, status)
+	if err != nil {
+		return fmt.Errorf("failed to update money request: %w", err)
+	}
+	if !updated {
+		return apperrors.ErrMoneyRequestNotPending
+	}
+
+	now := time.Now()
+	request.Status = status
+	request.RespondedAt = &now
+	return nil
+}
@@ -0,0 +1,100 @@
+package usecases
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/limistah/wallet-service/internal/fraud"
+	"github.com/limistah/wallet-service/internal/models"
+	"github.com/limistah/wallet-service/internal/repositories/memory"
+	"github.com/shopspring/decimal"
+)
+
+// transferringWalletUseCase serves wallets from the mock repository and records transfers
+type transferringWalletUseCase struct {
+	WalletUseCase
+	wallets     *memory.WalletRepository
+	transfers   []string
+	origins     []*fraud.Origin
+	transferErr error
+}
+
+func (w *transferringWalletUseCase) GetWalletByUserID(ctx context.Context, userID uint) (*models.Wallet, error) {
+	return w.wallets.GetByUserID(ctx, userID)
+}
+
+func (w *transferringWalletUseCase) GetWallet(ctx context.Context, id uint) (*models.Wallet, error) {
+	return w.wallets.GetByID(ctx, id)
+}
+
+func (w *transferringWalletUseCase) TransferFunds(ctx context.Context, fromWalletID, toWalletID uint, amount decimal.Decimal, reference, description string, origin *fraud.Origin) (*models.Transaction, *models.Transaction, error) {
+	if w.transferErr != nil {
+		return nil, nil, w.transferErr
+	}
+	w.transfers = append(w.transfers, reference)
+	w.origins = append(w.origins, origin)
+	return &models.Transaction{ID: 77, WalletID: fromWalletID, Amount: amount, Reference: reference}, &models.Transaction{ID: 78}, nil
+}
+
+func TestMoneyRequestUseCase_Lifecycle(t *testing.T) {
+	repos, _ := setupTestEnvironment()
+	repos.MoneyRequest = memory.NewMoneyRequestRepository()
+
+	repos.User.Create(context.Background(), &models.User{ID: 2, Email: "requester@example.com", Name: "Requester"})
+	repos.User.Create(context.Background(), &models.User{ID: 3, Email: "payer@example.com", Name: "Payer"})
+	repos.Wallet.Create(context.Background(), &models.Wallet{ID: 2, UserID: 2, Currency: "USD", Status: models.WalletStatusActive})
+	repos.Wallet.Create(context.Background(), &models.Wallet{ID: 3, UserID: 3, Currency: "USD", Status: models.WalletStatusActive})
+
+	walletUC := &transferringWalletUseCase{wallets: repos.Wallet.(*memory.WalletRepository)}
+	requestUC := NewMoneyRequestUseCase(repos, walletUC)
+
+	if _, err := requestUC.CreateRequest(context.Background(), 2, "requester@example.com", decimal.NewFromInt(10), ""); err == nil || err.Error() != "cannot request money from yourself" {
+		t.Errorf("Expected self requests to be rejected, got %v", err)
+	}
+
+	request, err := requestUC.CreateRequest(context.Background(), 2, " Payer@Example.com ", decimal.NewFromInt(25), "Dinner")
+	if err != nil {
+		t.Fatalf("Unexpected error creating request: %v", err)
+	}
+	if request.PayerID != 3 || request.Currency != "USD" || !request.IsPending() {
+		t.Fatalf("Unexpected request: %+v", request)
+	}
+
+	if _, _, err := requestUC.AcceptRequest(context.Background(), 2, request.ID, "", nil); err == nil || err.Error() != "money request not found" {
+		t.Errorf("Expected the requester to be unable to accept, got %v", err)
+	}
+
+	walletUC.transferErr = errors.New("insufficient funds in source wallet")
+	if _, _, err := requestUC.AcceptRequest(context.Background(), 3, request.ID, "", nil); err == nil {
+		t.Fatal("Expected the failed transfer to surface")
+	}
+	if stored, _ := repos.MoneyRequest.GetByID(context.Background(), request.ID); !stored.IsPending() {
+		t.Errorf("Expected the request to reopen after a failed transfer, got %s", stored.Status)
+	}
+
+	walletUC.transferErr = nil
+	origin := &fraud.Origin{IPAddress: "203.0.113.7", Country: "NG"}
+	accepted, transaction, err := requestUC.AcceptRequest(context.Background(), 3, request.ID, "", origin)
+	if err != nil {
+		t.Fatalf("Unexpected error accepting request: %v", err)
+	}
+	if accepted.Status != models.MoneyRequestStatusAccepted || transaction.Reference != request.Reference() {
+		t.Errorf("Unexpected accepted request: %+v / %+v", accepted, transaction)
+	}
+
+	if _, _, err := requestUC.AcceptRequest(context.Background(), 3, request.ID, "", nil); err == nil || err.Error() != "money request is no longer pending" {
+		t.Errorf("Expected a second accept to be rejected, got %v", err)
+	}
+	if len(walletUC.transfers) != 1 {
+		t.Errorf("Expected exactly one transfer, got %d", len(walletUC.transfers))
+	} else if walletUC.origins[0] != origin {
+		t.Errorf("Expected the transfer to carry the payer's origin, got %+v", walletUC.origins[0])
+	}
+
+	incoming, _ := requestUC.ListRequests(context.Background(), 3, models.MoneyRequestIncoming, "", 1, 20)
+	outgoing, _ := requestUC.ListRequests(context.Background(), 3, models.MoneyRequestOutgoing, "", 1, 20)
+	if len(incoming) != 1 || len(outgoing) != 0 {
+		t.Errorf("Expected one incoming and no outgoing requests, got %d and %d", len(incoming), len(outgoing))
+	}
+}
@@ -0,0 +1,178 @@
+package usecases
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/limistah/wallet-service/internal/apperrors"
+	"github.com/limistah/wallet-service/internal/models"
+	"github.com/limistah/wallet-service/internal/notifications"
+	"github.com/limistah/wallet-service/internal/repositories"
+	"github.com/limistah/wallet-service/internal/utils"
+	"github.com/shopspring/decimal"
+	"gorm.io/gorm"
+)
+
+// phoneVerificationTTL is how long a phone verification code stays valid
+const phoneVerificationTTL = 10 * time.Minute
+
+type notificationUseCase struct {
+	repos     *repositories.Repositories
+	smsSender notifications.SMSSender
+}
+
+// NewNotificationUseCase creates a new notification use case
+func NewNotificationUseCase(repos *repositories.Repositories, opts ...Option) NotificationUseCase {
+	o := newOptions(opts)
+	return &notificationUseCase{
+		repos:     repos,
+		smsSender: o.smsSender,
+	}
+}
+
+func (uc *notificationUseCase) GetPreferences(ctx context.Context, userID uint) (*models.NotificationPreference, error) {
+	preference, err := uc.repos.NotificationPreference.GetByUserID(ctx, userID)
+	if err == gorm.ErrRecordNotFound {
+		return models.DefaultNotificationPreference(userID), nil
+	}
+	return preference, err
+}
+
+func (uc *notificationUseCase) UpdatePreferences(ctx context.Context, userID uint, updated *models.NotificationPreference) (*models.NotificationPreference, error) {
+	if updated.SMSDebitThreshold.LessThan(decimal.Zero) {
+		return nil, apperrors.ErrValidation.Withf("sms debit threshold cannot be negative")
+	}
+
+	preference, err := uc.GetPreferences(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	if updated.SMSEnabled && !preference.SMSEnabled {
+		user, err := uc.repos.User.GetByID(ctx, userID)
+		if err != nil {
+			return nil, err
+		}
+		if !user.HasVerifiedPhone() {
+			return nil, apperrors.ErrValidation.Withf("phone number must be verified before enabling sms alerts")
+		}
+	}
+
+	preference.EmailEnabled = updated.EmailEnabled
+	preference.CreditReceived = updated.CreditReceived
+	preference.WithdrawalCompleted = updated.WithdrawalCompleted
+	preference.TransactionFailed = updated.TransactionFailed
+	preference.SuspiciousActivity = updated.SuspiciousActivity
+	preference.SMSEnabled = updated.SMSEnabled
+	preference.SMSDebitThreshold = updated.SMSDebitThreshold
+	preference.PushEnabled = updated.PushEnabled
+
+	if err := uc.repos.NotificationPreference.Save(ctx, preference); err != nil {
+		return nil, err
+	}
+	return preference, nil
+}
+
+func (uc *notificationUseCase) RequestPhoneVerification(ctx context.Context, userID uint, phoneNumber string) (*models.PhoneVerification, error) {
+	if !utils.ValidatePhoneNumber(phoneNumber) {
+		return nil, apperrors.ErrInvalidPhoneNumber
+	}
+
+	if _, err := uc.repos.User.GetByID(ctx, userID); err != nil {
+		return nil, apperrors.ErrUserNotFound
+	}
+
+	code := utils.GenerateNumericCode(6)
+	verification := &models.PhoneVerification{
+		UserID:      userID,
+		PhoneNumber: phoneNumber,
+		ExpiresAt:   time.Now().Add(phoneVerificationTTL),
+	}
+	if err := verification.SetCode(code); err != nil {
+		return nil, fmt.Errorf("failed to secure verification code: %w", err)
+	}
+
+	if err := uc.repos.PhoneVerification.Create(ctx, verification); err != nil {
+		return nil, err
+	}
+
+	message := fmt.Sprintf("Your wallet verification code is %s. It expires in %d minutes.", code, int(phoneVerificationTTL.Minutes()))
+	if err := uc.smsSender.Send(phoneNumber, message); err != nil {
+		return nil, apperrors.ErrDeliveryFailed.Withf("failed to send verification code: %w", err)
+	}
+
+	return verification, nil
+}
+
+func (uc *notificationUseCase) VerifyPhone(ctx context.Context, userID uint, code string) (*models.User, error) {
+	verification, err := uc.repos.PhoneVerification.GetLatestByUserID(ctx, userID)
+	if err == gorm.ErrRecordNotFound {
+		return nil, apperrors.ErrVerificationNotPending
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if !verification.IsUsable() {
+		return nil, apperrors.ErrVerificationExpired
+	}
+
+	if !verification.CheckCode(code) {
+		verification.Attempts++
+		if err := uc.repos.PhoneVerification.Update(ctx, verification); err != nil {
+			return nil, err
+		}
+		return nil, apperrors.ErrInvalidVerificationCode
+	}
+
+	now := time.Now()
+	verification.VerifiedAt = &now
+	if err := uc.repos.PhoneVerification.Update(ctx, verification); err != nil {
+		return nil, err
+	}
+
+	user, err := uc.repos.User.GetByID(ctx, userID)
+	if err != nil {
+		return nil, apperrors.ErrUserNotFound
+	}
+	user.PhoneNumber = verification.PhoneNumber
+	user.PhoneVerifiedAt = &now
+	if err := uc.repos.User.Update(ctx, user); err != nil {
+		return nil, err
+	}
+
+	return user, nil
+}
+
+func (uc *notificationUseCase) RegisterDevice(ctx context.Context, userID uint, token string, platform models.DevicePlatform, deviceName string) (*models.DeviceToken, error) {
+	if token == "" {
+		return nil, apperrors.ErrValidation.Withf("device token is required")
+	}
+	if !models.IsValidDevicePlatform(platform) {
+		return nil, apperrors.ErrValidation.Withf("invalid device platform")
+	}
+
+	device := &models.DeviceToken{
+		UserID:     userID,
+		Token:      token,
+		Platform:   platform,
+		DeviceName: deviceName,
+	}
+	if err := uc.repos.DeviceToken.Save(ctx, device); err != nil {
+		return nil, err
+	}
+	return device, nil
+}
+
+func (uc *notificationUseCase) ListDevices(ctx context.Context, userID uint) ([]models.DeviceToken, error) {
+	return uc.repos.DeviceToken.GetByUserID(ctx, userID)
+}
+
+func (uc *notificationUseCase) RemoveDevice(ctx context.Context, userID, deviceID uint) error {
+	err := uc.repos.DeviceToken.Delete(ctx, userID, deviceID)
+	if err == gorm.ErrRecordNotFound {
+		return apperrors.ErrDeviceNotFound
+	}
+	return err
+}
@@ -0,0 +1,110 @@
+package usecases
+
+import (
+	"context"
+	"errors"
+	"log"
+	"strings"
+
+	"github.com/limistah/wallet-service/internal/apperrors"
+	"github.com/limistah/wallet-service/internal/models"
+	"github.com/limistah/wallet-service/internal/oauth"
+	"github.com/limistah/wallet-service/internal/repositories"
+	"github.com/limistah/wallet-service/internal/utils"
+	"gorm.io/gorm"
+)
+
+type oauthUseCase struct {
+	repos       *repositories.Repositories
+	userUseCase UserUseCase
+}
+
+// NewOAuthUseCase creates a new use case for signing in with external identity providers
+func NewOAuthUseCase(repos *repositories.Repositories, userUseCase UserUseCase) OAuthUseCase {
+	return &oauthUseCase{
+		repos:       repos,
+		userUseCase: userUseCase,
+	}
+}
+
+// SignIn returns the user of a provider account. An account seen for the first time is linked to the
+// user with the same email, or provisions a new user with a default wallet like registration does.
+// Only emails verified by the provider are trusted for either
+func (uc *oauthUseCase) SignIn(ctx context.Context, identity oauth.Identity) (*models.User, error) {
+	linked, err := uc.repos.UserIdentity.GetByProviderSubject(ctx, identity.Provider, identity.Subject)
+	if err == nil {
+		user, err := uc.repos.User.GetByID(ctx, linked.UserID)
+		if err == nil && user.IsDeletionRequested() {
+			return nil, apperrors.ErrAccountDeleted
+		}
+		return user, err
+	}
+	if !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, err
+	}
+
+	if identity.Email == "" || !identity.EmailVerified {
+		return nil, apperrors.ErrOAuthEmailUnverified
+	}
+
+	user, err := uc.repos.User.GetByEmail(ctx, identity.Email)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		user, err = uc.createUser(ctx, identity)
+	}
+	if err != nil {
+		return nil, err
+	}
+	if user.IsSystemAccount() {
+		return nil, apperrors.ErrOAuthEmailUnverified
+	}
+	if user.IsDeletionRequested() {
+		return nil, apperrors.ErrAccountDeleted
+	}
+
+	// A failed link is retried on the next sign in, which finds the user by email again
+	err = uc.repos.UserIdentity.Create(ctx, &models.UserIdentity{
+		UserID:   user.ID,
+		Provider: identity.Provider,
+		Subject:  identity.Subject,
+		Email:    identity.Email,
+	})
+	if err != nil {
+		log.Printf("Failed to link %s account %s to user %d: %v", identity.Provider, identity.Subject, user.ID, err)
+	}
+	return user, nil
+}
+
+// createUser registers a user for a provider account. The password is random: the user signs in
+// with the provider
+func (uc *oauthUseCase) createUser(ctx context.Context, identity oauth.Identity) (*models.User, error) {
+	user := &models.User{
+		Name:  displayName(identity),
+		Email: identity.Email,
+		Role:  models.UserRoleUser,
+	}
+	if err := user.HashPassword(utils.GenerateToken(32)); err != nil {
+		return nil, err
+	}
+	return uc.userUseCase.CreateUser(ctx, user)
+}
+
+// displayName returns the name reported by the provider, or the local part of the email when it is
+// missing or too short for a user name
+func displayName(identity oauth.Identity) string {
+	name := strings.TrimSpace(identity.Name)
+	if len([]rune(name)) > 100 {
+		name = string([]rune(name)[:100])
+	}
+	if len([]rune(name)) >= 2 {
+		return name
+	}
+
+	local, _, _ := strings.Cut(identity.Email, "@")
+	if len([]rune(local)) > 100 {
+		local = string([]rune(local)[:100])
+	}
+	if len([]rune(local)) >= 2 {
+		return local
+	}
+	return "User"
+}
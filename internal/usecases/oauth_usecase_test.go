@@ -0,0 +1,102 @@
+package usecases
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/limistah/wallet-service/internal/apperrors"
+	"github.com/limistah/wallet-service/internal/models"
+	"github.com/limistah/wallet-service/internal/oauth"
+	"github.com/limistah/wallet-service/internal/repositories"
+	"github.com/limistah/wallet-service/internal/repositories/memory"
+)
+
+// stubRegistration creates users in the mock repository instead of a database transaction
+type stubRegistration struct {
+	UserUseCase
+	users *memory.UserRepository
+}
+
+func (s *stubRegistration) CreateUser(ctx context.Context, user *models.User) (*models.User, error) {
+	return user, s.users.Create(ctx, user)
+}
+
+func newTestOAuth() (*oauthUseCase, *memory.UserRepository, *memory.UserIdentityRepository) {
+	userRepo := memory.NewUserRepository()
+	identityRepo := memory.NewUserIdentityRepository()
+	repos := &repositories.Repositories{User: userRepo, UserIdentity: identityRepo}
+	uc := NewOAuthUseCase(repos, &stubRegistration{users: userRepo}).(*oauthUseCase)
+	return uc, userRepo, identityRepo
+}
+
+func TestOAuthUseCase_SignIn(t *testing.T) {
+	t.Run("links an existing account by verified email", func(t *testing.T) {
+		uc, userRepo, identityRepo := newTestOAuth()
+		userRepo.Create(context.Background(), &models.User{ID: 7, Name: "Ada", Email: "ada@example.com"})
+
+		user, err := uc.SignIn(context.Background(), oauth.Identity{Provider: "google", Subject: "g-1", Email: "ada@example.com", EmailVerified: true})
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		if user.ID != 7 {
+			t.Errorf("Expected the existing user, got %d", user.ID)
+		}
+		if len(identityRepo.Identities()) != 1 || identityRepo.Identities()[0].UserID != 7 {
+			t.Errorf("Expected the provider account to be linked, got %+v", identityRepo.Identities())
+		}
+
+		// Linked accounts sign in even after the email changed at the provider
+		user, err = uc.SignIn(context.Background(), oauth.Identity{Provider: "google", Subject: "g-1", Email: "new@example.com"})
+		if err != nil || user.ID != 7 {
+			t.Errorf("Expected the linked user, got %v, %v", user, err)
+		}
+	})
+
+	t.Run("provisions a new user", func(t *testing.T) {
+		uc, userRepo, identityRepo := newTestOAuth()
+
+		user, err := uc.SignIn(context.Background(), oauth.Identity{Provider: "oidc", Subject: "o-1", Email: "grace@example.com", EmailVerified: true})
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		if user.Name != "grace" || user.Role != models.UserRoleUser || user.Password == "" {
+			t.Errorf("Unexpected user: %+v", user)
+		}
+		if _, err := userRepo.GetByEmail(context.Background(), "grace@example.com"); err != nil {
+			t.Errorf("Expected the user to be created, got %v", err)
+		}
+		if len(identityRepo.Identities()) != 1 {
+			t.Errorf("Expected the provider account to be linked, got %+v", identityRepo.Identities())
+		}
+	})
+
+	t.Run("refuses unverified emails", func(t *testing.T) {
+		uc, userRepo, identityRepo := newTestOAuth()
+		userRepo.Create(context.Background(), &models.User{ID: 7, Name: "Ada", Email: "ada@example.com"})
+
+		_, err := uc.SignIn(context.Background(), oauth.Identity{Provider: "oidc", Subject: "o-1", Email: "ada@example.com"})
+		if !errors.Is(err, apperrors.ErrOAuthEmailUnverified) {
+			t.Errorf("Expected ErrOAuthEmailUnverified, got %v", err)
+		}
+		if len(identityRepo.Identities()) != 0 {
+			t.Error("Expected no account to be linked")
+		}
+	})
+}
+
+func TestDisplayName(t *testing.T) {
+	tests := []struct {
+		identity oauth.Identity
+		expected string
+	}{
+		{oauth.Identity{Name: " Ada Lovelace ", Email: "ada@example.com"}, "Ada Lovelace"},
+		{oauth.Identity{Name: "A", Email: "ada@example.com"}, "ada"},
+		{oauth.Identity{Email: "a@example.com"}, "User"},
+	}
+	for _, tt := range tests {
+		if name := displayName(tt.identity); name != tt.expected {
+			t.Errorf("Expected %q, got %q", tt.expected, name)
+		}
+	}
+}
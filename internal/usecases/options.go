@@ -0,0 +1,349 @@
+package usecases
+
+import (
+	"time"
+
+	"github.com/limistah/wallet-service/internal/compliance"
+	"github.com/limistah/wallet-service/internal/events"
+	"github.com/limistah/wallet-service/internal/fraud"
+	"github.com/limistah/wallet-service/internal/fx"
+	"github.com/limistah/wallet-service/internal/notifications"
+	"github.com/limistah/wallet-service/internal/passwords"
+	"github.com/limistah/wallet-service/internal/payments"
+	"github.com/limistah/wallet-service/internal/queue"
+	"github.com/limistah/wallet-service/internal/storage"
+	"github.com/limistah/wallet-service/internal/utils"
+	"github.com/limistah/wallet-service/internal/webhooks"
+	"github.com/shopspring/decimal"
+)
+
+// Option configures optional dependencies shared by the use cases
+type Option func(*options)
+
+type options struct {
+	publisher    events.EventPublisher
+	smsSender    notifications.SMSSender
+	cardProvider payments.CardPaymentProvider
+
+	paymentProvider     payments.PaymentProvider
+	checkoutCallbackURL string
+
+	payoutProvider payments.PayoutProvider
+
+	sandboxEnabled bool
+
+	standingOrderRetry RetryPolicy
+
+	fraudEngine *fraud.Engine
+
+	amlScreener  compliance.AMLScreener
+	amlThreshold decimal.Decimal
+
+	sanctionsChecker compliance.SanctionsChecker
+
+	jobs queue.Enqueuer
+
+	ipAllowlist        []string
+	ipAllowlistRefresh time.Duration
+
+	loginPolicy LoginPolicy
+
+	passwordPolicy passwords.Policy
+	breachChecker  passwords.BreachChecker
+
+	impersonationTTL time.Duration
+
+	pinPolicy PINPolicy
+
+	transferChallenge *TransferChallengePolicy
+
+	statementStore  storage.BlobStore
+	statementSigner *storage.URLSigner
+	statementURLTTL time.Duration
+
+	avatarStore    storage.BlobStore
+	avatarMaxBytes int
+
+	exchangeRates    fx.RateProvider
+	transferQuoteTTL time.Duration
+
+	subscriptionDunning DunningPolicy
+
+	webhookSender webhooks.Sender
+	webhookRetry  WebhookRetryPolicy
+
+	reconciliationMetrics *ReconciliationMetrics
+
+	// settings holds the reloadable settings; without WithLiveSettings it is made from the options above
+	settings *LiveSettings
+}
+
+// RetryPolicy controls how standing order occurrences are retried when the wallet cannot cover them
+type RetryPolicy struct {
+	// InitialBackoff is the wait before the first retry; each further retry doubles it
+	InitialBackoff time.Duration
+	// MaxBackoff caps the wait between retries
+	MaxBackoff time.Duration
+	// GracePeriod is how long after the scheduled time an occurrence keeps being retried
+	GracePeriod time.Duration
+}
+
+// DefaultRetryPolicy retries from 30 minutes up to every 6 hours for 3 days
+func DefaultRetryPolicy() RetryPolicy {
+	return RetryPolicy{
+		InitialBackoff: 30 * time.Minute,
+		MaxBackoff:     6 * time.Hour,
+		GracePeriod:    72 * time.Hour,
+	}
+}
+
+// Backoff returns the wait before the given retry attempt, starting at 1
+func (p RetryPolicy) Backoff(attempt int) time.Duration {
+	return utils.Backoff(p.InitialBackoff, p.MaxBackoff, attempt)
+}
+
+// WithEventPublisher sets the publisher that receives wallet domain events
+func WithEventPublisher(publisher events.EventPublisher) Option {
+	return func(o *options) {
+		o.publisher = publisher
+	}
+}
+
+// WithSMSSender sets the sender used for phone verification codes
+func WithSMSSender(sender notifications.SMSSender) Option {
+	return func(o *options) {
+		o.smsSender = sender
+	}
+}
+
+// WithCardPaymentProvider enables card funding through the given provider
+func WithCardPaymentProvider(provider payments.CardPaymentProvider) Option {
+	return func(o *options) {
+		o.cardProvider = provider
+	}
+}
+
+// WithPaymentProvider enables hosted checkout funding; customers return to callbackURL after paying
+func WithPaymentProvider(provider payments.PaymentProvider, callbackURL string) Option {
+	return func(o *options) {
+		o.paymentProvider = provider
+		o.checkoutCallbackURL = callbackURL
+	}
+}
+
+// WithPayoutProvider makes withdrawals asynchronous bank payouts through the given provider
+func WithPayoutProvider(provider payments.PayoutProvider) Option {
+	return func(o *options) {
+		o.payoutProvider = provider
+	}
+}
+
+// WithSandbox allows sandbox tokens to operate sandbox wallets and mint test money
+func WithSandbox(enabled bool) Option {
+	return func(o *options) {
+		o.sandboxEnabled = enabled
+	}
+}
+
+// WithStandingOrderRetry sets the retry policy for standing orders that hit insufficient funds
+func WithStandingOrderRetry(policy RetryPolicy) Option {
+	return func(o *options) {
+		o.standingOrderRetry = policy
+	}
+}
+
+// WithFraudEngine sets the rules engine that screens withdrawals and transfers before they are debited
+func WithFraudEngine(engine *fraud.Engine) Option {
+	return func(o *options) {
+		o.fraudEngine = engine
+	}
+}
+
+// WithAMLScreener submits withdrawals and transfers above threshold to the screener before they are debited
+func WithAMLScreener(screener compliance.AMLScreener, threshold decimal.Decimal) Option {
+	return func(o *options) {
+		o.amlScreener = screener
+		o.amlThreshold = threshold
+	}
+}
+
+// WithSanctionsChecker screens new users and new transfer recipients against a sanctions watchlist
+func WithSanctionsChecker(checker compliance.SanctionsChecker) Option {
+	return func(o *options) {
+		o.sanctionsChecker = checker
+	}
+}
+
+// WithJobQueue sets the durable queue that runs post-transaction audits
+func WithJobQueue(jobs queue.Enqueuer) Option {
+	return func(o *options) {
+		o.jobs = jobs
+	}
+}
+
+// WithIPAllowlist restricts the admin endpoints to the given CIDR ranges on top of the ranges managed
+// at runtime, which are reloaded every refresh
+func WithIPAllowlist(cidrs []string, refresh time.Duration) Option {
+	return func(o *options) {
+		o.ipAllowlist = cidrs
+		o.ipAllowlistRefresh = refresh
+	}
+}
+
+// WithLoginPolicy sets the account lockout and IP throttling of password logins
+func WithLoginPolicy(policy LoginPolicy) Option {
+	return func(o *options) {
+		o.loginPolicy = policy
+	}
+}
+
+// WithPasswordPolicy sets the rules new passwords must follow on registration and password changes
+func WithPasswordPolicy(policy passwords.Policy) Option {
+	return func(o *options) {
+		o.passwordPolicy = policy
+	}
+}
+
+// WithBreachChecker refuses new passwords the checker finds in known data breaches
+func WithBreachChecker(checker passwords.BreachChecker) Option {
+	return func(o *options) {
+		o.breachChecker = checker
+	}
+}
+
+// WithImpersonationTTL sets how long the tokens staff get to act as a user stay valid
+func WithImpersonationTTL(ttl time.Duration) Option {
+	return func(o *options) {
+		o.impersonationTTL = ttl
+	}
+}
+
+// WithPINPolicy sets which debits need the transaction PIN and how wrong PINs lock it
+func WithPINPolicy(policy PINPolicy) Option {
+	return func(o *options) {
+		o.pinPolicy = policy
+	}
+}
+
+// WithTransferChallenge holds transfers above the policy's threshold until the user confirms them with
+// a one-time code sent to their verified phone
+func WithTransferChallenge(policy TransferChallengePolicy) Option {
+	return func(o *options) {
+		o.transferChallenge = &policy
+	}
+}
+
+// WithStatementStorage enables account statements, rendered into store and downloaded through links
+// from signer that stay valid for urlTTL
+func WithStatementStorage(store storage.BlobStore, signer *storage.URLSigner, urlTTL time.Duration) Option {
+	return func(o *options) {
+		o.statementStore = store
+		o.statementSigner = signer
+		if urlTTL > 0 {
+			o.statementURLTTL = urlTTL
+		}
+	}
+}
+
+// WithAvatarStorage keeps uploaded profile pictures in store, refusing images larger than maxBytes
+func WithAvatarStorage(store storage.BlobStore, maxBytes int) Option {
+	return func(o *options) {
+		o.avatarStore = store
+		if maxBytes > 0 {
+			o.avatarMaxBytes = maxBytes
+		}
+	}
+}
+
+// WithExchangeRates lets transfers between wallets of different currencies convert the amount at the
+// rates of provider, and shows balances in the display currency users choose; without it such
+// transfers are refused
+func WithExchangeRates(provider fx.RateProvider) Option {
+	return func(o *options) {
+		o.exchangeRates = provider
+	}
+}
+
+// WithTransferQuoteTTL sets how long a transfer quote keeps its terms
+func WithTransferQuoteTTL(ttl time.Duration) Option {
+	return func(o *options) {
+		if ttl > 0 {
+			o.transferQuoteTTL = ttl
+		}
+	}
+}
+
+// WithSubscriptionDunning sets how failed subscription charges are retried before the subscription
+// is suspended
+func WithSubscriptionDunning(policy DunningPolicy) Option {
+	return func(o *options) {
+		o.subscriptionDunning = policy
+	}
+}
+
+// WithWebhookSender sets the sender posting events to webhook endpoints
+func WithWebhookSender(sender webhooks.Sender) Option {
+	return func(o *options) {
+		o.webhookSender = sender
+	}
+}
+
+// WithWebhookRetry sets how failed webhook deliveries are retried before they move to the dead letter
+// queue
+func WithWebhookRetry(policy WebhookRetryPolicy) Option {
+	return func(o *options) {
+		o.webhookRetry = policy
+	}
+}
+
+// WithLiveSettings shares settings that can be reloaded at runtime between the use cases; they take
+// the place of the login, PIN, transfer challenge and AML threshold options
+func WithLiveSettings(settings *LiveSettings) Option {
+	return func(o *options) {
+		o.settings = settings
+	}
+}
+
+// WithReconciliationMetrics records the duration and outcome of reconciliation runs
+func WithReconciliationMetrics(m *ReconciliationMetrics) Option {
+	return func(o *options) {
+		o.reconciliationMetrics = m
+	}
+}
+
+// newOptions applies opts on top of the defaults
+func newOptions(opts []Option) *options {
+	o := &options{
+		publisher: events.NoopPublisher{},
+		smsSender: notifications.LogSMSSender{},
+		jobs:      queue.NoopEnqueuer{},
+
+		standingOrderRetry: DefaultRetryPolicy(),
+		fraudEngine:        fraud.NewEngine(),
+		ipAllowlistRefresh: time.Minute,
+		loginPolicy:        DefaultLoginPolicy(),
+		passwordPolicy:     passwords.DefaultPolicy(),
+		impersonationTTL:   15 * time.Minute,
+		pinPolicy:          DefaultPINPolicy(),
+		statementURLTTL:    15 * time.Minute,
+		avatarMaxBytes:     512 << 10,
+		transferQuoteTTL:   time.Minute,
+
+		subscriptionDunning: DefaultDunningPolicy(),
+
+		webhookSender: webhooks.NewHTTPSender(10 * time.Second),
+		webhookRetry:  DefaultWebhookRetryPolicy(),
+	}
+	for _, opt := range opts {
+		opt(o)
+	}
+	if o.settings == nil {
+		o.settings = NewLiveSettings(RuntimeSettings{
+			Login:             o.loginPolicy,
+			PIN:               o.pinPolicy,
+			TransferChallenge: o.transferChallenge,
+			AMLThreshold:      o.amlThreshold,
+		})
+	}
+	return o
+}
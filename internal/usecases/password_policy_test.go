@@ -0,0 +1,91 @@
+package usecases
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/limistah/wallet-service/internal/apperrors"
+	"github.com/limistah/wallet-service/internal/models"
+	"github.com/limistah/wallet-service/internal/passwords"
+	"github.com/limistah/wallet-service/internal/repositories"
+	"github.com/limistah/wallet-service/internal/repositories/memory"
+)
+
+// stubBreachChecker reports the passwords it holds as breached
+type stubBreachChecker struct {
+	breached map[string]bool
+	err      error
+}
+
+func (c *stubBreachChecker) Breached(password string) (bool, error) {
+	return c.breached[password], c.err
+}
+
+func TestUserUseCase_ValidatePassword(t *testing.T) {
+	checker := &stubBreachChecker{breached: map[string]bool{"Password123": true}}
+	uc := NewUserUseCase(&repositories.Repositories{}, WithBreachChecker(checker))
+
+	if err := uc.ValidatePassword(context.Background(), "Tr0ub4dor-horse"); err != nil {
+		t.Errorf("Expected a strong password to be accepted, got %v", err)
+	}
+
+	err := uc.ValidatePassword(context.Background(), "short")
+	if !errors.Is(err, apperrors.ErrWeakPassword) {
+		t.Fatalf("Expected ErrWeakPassword, got %v", err)
+	}
+	if err.Error() != "password must be at least 8 characters long, contain an upper case letter, contain a digit" {
+		t.Errorf("Expected the message to list the broken rules, got %q", err.Error())
+	}
+
+	if err := uc.ValidatePassword(context.Background(), "Password123"); !errors.Is(err, apperrors.ErrPasswordBreached) {
+		t.Errorf("Expected ErrPasswordBreached, got %v", err)
+	}
+
+	// An unavailable breach check does not block the password
+	checker.err = errors.New("connection refused")
+	if err := uc.ValidatePassword(context.Background(), "Password123"); err != nil {
+		t.Errorf("Expected the password to be accepted while the breach check is down, got %v", err)
+	}
+}
+
+func TestUserUseCase_ChangePasswordRefusesRecentPasswords(t *testing.T) {
+	userRepo := memory.NewUserRepository()
+	user := &models.User{ID: 1, Email: "user@example.com"}
+	user.HashPassword("First-password1")
+	userRepo.Create(context.Background(), user)
+	history := memory.NewPasswordHistoryRepository()
+	policy := passwords.DefaultPolicy()
+	policy.HistorySize = 3
+	uc := NewUserUseCase(&repositories.Repositories{User: userRepo, PasswordHistory: history}, WithPasswordPolicy(policy))
+
+	if err := uc.ChangePassword(context.Background(), 1, "First-password1", "First-password1"); !errors.Is(err, apperrors.ErrPasswordReused) {
+		t.Fatalf("Expected the current password to be refused, got %v", err)
+	}
+	if err := uc.ChangePassword(context.Background(), 1, "First-password1", "weak"); !errors.Is(err, apperrors.ErrWeakPassword) {
+		t.Fatalf("Expected a weak password to be refused, got %v", err)
+	}
+
+	for _, change := range [][2]string{
+		{"First-password1", "Second-password2"},
+		{"Second-password2", "Third-password3"},
+	} {
+		if err := uc.ChangePassword(context.Background(), 1, change[0], change[1]); err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+	}
+	if err := uc.ChangePassword(context.Background(), 1, "Third-password3", "First-password1"); !errors.Is(err, apperrors.ErrPasswordReused) {
+		t.Errorf("Expected one of the last 3 passwords to be refused, got %v", err)
+	}
+
+	if err := uc.ChangePassword(context.Background(), 1, "Third-password3", "Fourth-password4"); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if len(history.Entries()) != 2 {
+		t.Errorf("Expected the history to keep 2 replaced passwords, got %d", len(history.Entries()))
+	}
+	// First-password1 is now older than the last 3 passwords
+	if err := uc.ChangePassword(context.Background(), 1, "Fourth-password4", "First-password1"); err != nil {
+		t.Errorf("Expected an old password to be allowed again, got %v", err)
+	}
+}
@@ -0,0 +1,203 @@
+package usecases
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/limistah/wallet-service/internal/apperrors"
+	"github.com/limistah/wallet-service/internal/fraud"
+	"github.com/limistah/wallet-service/internal/models"
+	"github.com/limistah/wallet-service/internal/repositories"
+	"github.com/limistah/wallet-service/internal/utils"
+	"github.com/shopspring/decimal"
+)
+
+// paymentLinkTokenLength is long enough that link tokens cannot be guessed
+const paymentLinkTokenLength = 24
+
+type paymentLinkUseCase struct {
+	repos         *repositories.Repositories
+	walletUseCase WalletUseCase
+	authorizer    debitAuthorizer
+}
+
+// NewPaymentLinkUseCase creates a new payment link use case
+func NewPaymentLinkUseCase(repos *repositories.Repositories, walletUseCase WalletUseCase, opts ...Option) PaymentLinkUseCase {
+	o := newOptions(opts)
+	return &paymentLinkUseCase{
+		repos:         repos,
+		walletUseCase: walletUseCase,
+		authorizer:    newDebitAuthorizer(repos, o),
+	}
+}
+
+func (uc *paymentLinkUseCase) CreateLink(ctx context.Context, userID uint, amount *decimal.Decimal, description string, expiresAt *time.Time, singleUse bool) (*models.PaymentLink, error) {
+	if amount != nil && amount.LessThanOrEqual(decimal.Zero) {
+		return nil, apperrors.ErrInvalidAmount
+	}
+	if expiresAt != nil && !expiresAt.After(time.Now()) {
+		return nil, apperrors.ErrValidation.Withf("expiry must be in the future")
+	}
+
+	wallet, err := uc.walletUseCase.GetWalletByUserID(ctx, userID)
+	if err != nil {
+		return nil, apperrors.ErrWalletNotFound
+	}
+	if !wallet.IsActive() {
+		return nil, apperrors.ErrWalletInactive
+	}
+	if err := requireTierFeature(ctx, uc.repos, wallet, func(t *models.WalletTier) bool { return t.PaymentLinksEnabled }, "payment links"); err != nil {
+		return nil, err
+	}
+
+	link := &models.PaymentLink{
+		UserID:         userID,
+		WalletID:       wallet.ID,
+		Token:          utils.GenerateToken(paymentLinkTokenLength),
+		Amount:         amount,
+		Currency:       wallet.Currency,
+		Description:    description,
+		SingleUse:      singleUse,
+		ExpiresAt:      expiresAt,
+		Status:         models.PaymentLinkStatusActive,
+		TotalCollected: decimal.Zero,
+	}
+	if err := uc.repos.PaymentLink.Create(ctx, link); err != nil {
+		return nil, fmt.Errorf("failed to create payment link: %w", err)
+	}
+
+	return link, nil
+}
+
+// ResolveLink returns the public details of a link and counts the view
+func (uc *paymentLinkUseCase) ResolveLink(ctx context.Context, token string) (*models.PaymentLink, error) {
+	link, err := uc.repos.PaymentLink.GetByToken(ctx, token)
+	if err != nil {
+		return nil, apperrors.ErrPaymentLinkNotFound
+	}
+
+	if err := uc.repos.PaymentLink.IncrementViews(ctx, link.ID); err != nil {
+		log.Printf("Failed to record view of payment link %d: %v", link.ID, err)
+	} else {
+		link.ViewCount++
+	}
+
+	return link, nil
+}
+
+// PayLink transfers from the payer's wallet into the link creator's wallet. Single-use links are
+// claimed before the transfer so two payers cannot both pay them; pin authorizes the payment
+func (uc *paymentLinkUseCase) PayLink(ctx context.Context, payerID uint, token string, amount *decimal.Decimal, pin string, origin *fraud.Origin) (*models.PaymentLink, *models.Transaction, error) {
+	link, err := uc.repos.PaymentLink.GetByToken(ctx, token)
+	if err != nil {
+		return nil, nil, apperrors.ErrPaymentLinkNotFound
+	}
+	if !link.IsPayable() {
+		return nil, nil, apperrors.ErrPaymentLinkInactive
+	}
+	if link.UserID == payerID {
+		return nil, nil, apperrors.ErrSelfPayment.Withf("cannot pay your own payment link")
+	}
+
+	payAmount, err := resolvePaymentAmount(link.Amount, amount, "payment link")
+	if err != nil {
+		return nil, nil, err
+	}
+
+	payerWallet, err := uc.walletUseCase.GetWalletByUserID(ctx, payerID)
+	if err != nil {
+		return nil, nil, apperrors.ErrWalletNotFound
+	}
+	if payerWallet.Currency != link.Currency {
+		return nil, nil, apperrors.ErrCurrencyMismatch.Withf("wallet currency does not match payment link")
+	}
+	if err := uc.authorizer.authorizeUnchallenged(ctx, payerID, payAmount, pin); err != nil {
+		return nil, nil, err
+	}
+
+	if link.SingleUse {
+		claimed, err := uc.repos.PaymentLink.UpdateStatus(ctx, link.ID, models.PaymentLinkStatusActive, models.PaymentLinkStatusUsed)
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to update payment link: %w", err)
+		}
+		if !claimed {
+			return nil, nil, apperrors.ErrPaymentLinkInactive
+		}
+	}
+
+	reference := fmt.Sprintf("PLK-%d-%s", link.ID, utils.GenerateUniqueID())
+	description := fmt.Sprintf("Payment link #%d", link.ID)
+	if link.Description != "" {
+		description = fmt.Sprintf("%s: %s", description, link.Description)
+	}
+
+	outTx, _, err := uc.walletUseCase.TransferFunds(ctx, payerWallet.ID, link.WalletID, payAmount, reference, description, origin)
+	if err != nil {
+		if link.SingleUse {
+			if _, revertErr := uc.repos.PaymentLink.UpdateStatus(ctx, link.ID, models.PaymentLinkStatusUsed, models.PaymentLinkStatusActive); revertErr != nil {
+				log.Printf("Failed to reopen payment link %d after transfer error: %v", link.ID, revertErr)
+			}
+		}
+		return nil, nil, err
+	}
+
+	if err := uc.repos.PaymentLink.RecordPayment(ctx, link.ID, payAmount); err != nil {
+		log.Printf("Failed to record payment on payment link %d: %v", link.ID, err)
+	}
+
+	now := time.Now()
+	link.PaymentCount++
+	link.TotalCollected = link.TotalCollected.Add(payAmount)
+	link.LastPaidAt = &now
+	if link.SingleUse {
+		link.Status = models.PaymentLinkStatusUsed
+	}
+
+	return link, outTx, nil
+}
+
+func (uc *paymentLinkUseCase) ListLinks(ctx context.Context, userID uint, page, pageSize int) ([]models.PaymentLink, error) {
+	if page < 1 {
+		page = 1
+	}
+	if pageSize < 1 || pageSize > 100 {
+		pageSize = 20
+	}
+	return uc.repos.PaymentLink.ListByUserID(ctx, userID, (page-1)*pageSize, pageSize)
+}
+
+func (uc *paymentLinkUseCase) DisableLink(ctx context.Context, userID, linkID uint) (*models.PaymentLink, error) {
+	link, err := uc.repos.PaymentLink.GetByID(ctx, linkID)
+	if err != nil || link.UserID != userID {
+		return nil, apperrors.ErrPaymentLinkNotFound
+	}
+
+	disabled, err := uc.repos.PaymentLink.UpdateStatus(ctx, link.ID, models.PaymentLinkStatusActive, models.PaymentLinkStatusDisabled)
+	if err != nil {
+		return nil, fmt.Errorf("failed to update payment link: %w", err)
+	}
+	if !disabled {
+		return nil, apperrors.ErrPaymentLinkInactive
+	}
+
+	link.Status = models.PaymentLinkStatusDisabled
+	return link, nil
+}
+
+// resolvePaymentAmount picks the fixed amount of a link or code, or validates the amount chosen by
+// the payer when none is fixed; subject names the link or code in error messages
+func resolvePaymentAmount(fixed, chosen *decimal.Decimal, subject string) (decimal.Decimal, error) {
+	if fixed != nil {
+		if chosen != nil && !chosen.Equal(*fixed) {
+			return decimal.Zero, apperrors.ErrAmountMismatch.Withf("amount does not match %s", subject)
+		}
+		return *fixed, nil
+	}
+
+	if chosen == nil || chosen.LessThanOrEqual(decimal.Zero) {
+		return decimal.Zero, apperrors.ErrAmountRequired.Withf("amount is required for this %s", subject)
+	}
+	return *chosen, nil
+}
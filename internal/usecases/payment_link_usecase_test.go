@@ -0,0 +1,100 @@
+package usecases
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/limistah/wallet-service/internal/apperrors"
+	"github.com/limistah/wallet-service/internal/models"
+	"github.com/limistah/wallet-service/internal/repositories/memory"
+	"github.com/shopspring/decimal"
+)
+
+func TestPaymentLinkUseCase_PayLink(t *testing.T) {
+	repos, _ := setupTestEnvironment()
+	linkRepo := memory.NewPaymentLinkRepository()
+	repos.PaymentLink = linkRepo
+
+	repos.Wallet.Create(context.Background(), &models.Wallet{ID: 2, UserID: 2, Currency: "USD", Status: models.WalletStatusActive})
+	repos.Wallet.Create(context.Background(), &models.Wallet{ID: 3, UserID: 3, Currency: "USD", Status: models.WalletStatusActive})
+	repos.User.Create(context.Background(), &models.User{ID: 3, Email: "payer@example.com"})
+
+	walletUC := &transferringWalletUseCase{wallets: repos.Wallet.(*memory.WalletRepository)}
+	linkUC := NewPaymentLinkUseCase(repos, walletUC)
+
+	past := time.Now().Add(-time.Hour)
+	if _, err := linkUC.CreateLink(context.Background(), 2, nil, "", &past, false); err == nil || err.Error() != "expiry must be in the future" {
+		t.Errorf("Expected past expiry to be rejected, got %v", err)
+	}
+
+	price := decimal.NewFromInt(30)
+	ticket, err := linkUC.CreateLink(context.Background(), 2, &price, "Ticket", nil, true)
+	if err != nil {
+		t.Fatalf("Unexpected error creating link: %v", err)
+	}
+	if len(ticket.Token) != paymentLinkTokenLength || ticket.WalletID != 2 {
+		t.Fatalf("Unexpected link: %+v", ticket)
+	}
+
+	if _, _, err := linkUC.PayLink(context.Background(), 2, ticket.Token, nil, "", nil); err == nil || err.Error() != "cannot pay your own payment link" {
+		t.Errorf("Expected creator payments to be rejected, got %v", err)
+	}
+
+	wrong := decimal.NewFromInt(10)
+	if _, _, err := linkUC.PayLink(context.Background(), 3, ticket.Token, &wrong, "", nil); err == nil || err.Error() != "amount does not match payment link" {
+		t.Errorf("Expected a different amount to be rejected, got %v", err)
+	}
+
+	paid, transaction, err := linkUC.PayLink(context.Background(), 3, ticket.Token, nil, "", nil)
+	if err != nil {
+		t.Fatalf("Unexpected error paying link: %v", err)
+	}
+	if !transaction.Amount.Equal(price) || paid.Status != models.PaymentLinkStatusUsed || paid.PaymentCount != 1 {
+		t.Errorf("Unexpected payment: %+v / %+v", paid, transaction)
+	}
+
+	if _, _, err := linkUC.PayLink(context.Background(), 3, ticket.Token, nil, "", nil); err == nil || err.Error() != "payment link is no longer active" {
+		t.Errorf("Expected a single-use link to be paid only once, got %v", err)
+	}
+
+	tips, _ := linkUC.CreateLink(context.Background(), 2, nil, "Tips", nil, false)
+	if _, _, err := linkUC.PayLink(context.Background(), 3, tips.Token, nil, "", nil); err == nil || err.Error() != "amount is required for this payment link" {
+		t.Errorf("Expected open links to require an amount, got %v", err)
+	}
+	for i := 0; i < 2; i++ {
+		if _, _, err := linkUC.PayLink(context.Background(), 3, tips.Token, &wrong, "", nil); err != nil {
+			t.Fatalf("Unexpected error paying open link: %v", err)
+		}
+	}
+
+	resolved, err := linkUC.ResolveLink(context.Background(), tips.Token)
+	if err != nil {
+		t.Fatalf("Unexpected error resolving link: %v", err)
+	}
+	if resolved.ViewCount != 1 || resolved.PaymentCount != 2 || !resolved.TotalCollected.Equal(decimal.NewFromInt(20)) {
+		t.Errorf("Unexpected analytics: views=%d payments=%d total=%s", resolved.ViewCount, resolved.PaymentCount, resolved.TotalCollected)
+	}
+}
+
+func TestPaymentLinkUseCase_PayLinkAboveStepUpThreshold(t *testing.T) {
+	repos, _ := setupTestEnvironment()
+	repos.PaymentLink = memory.NewPaymentLinkRepository()
+	repos.Wallet.Create(context.Background(), &models.Wallet{ID: 2, UserID: 2, Currency: "USD", Status: models.WalletStatusActive})
+	repos.Wallet.Create(context.Background(), &models.Wallet{ID: 3, UserID: 3, Currency: "USD", Status: models.WalletStatusActive})
+	repos.User.Create(context.Background(), &models.User{ID: 3, Email: "payer@example.com"})
+
+	walletUC := &transferringWalletUseCase{wallets: repos.Wallet.(*memory.WalletRepository)}
+	linkUC := NewPaymentLinkUseCase(repos, walletUC, WithTransferChallenge(TransferChallengePolicy{Threshold: decimal.NewFromInt(100), TTL: time.Minute}))
+
+	tips, _ := linkUC.CreateLink(context.Background(), 2, nil, "Tips", nil, false)
+	large := decimal.NewFromInt(150)
+	if _, _, err := linkUC.PayLink(context.Background(), 3, tips.Token, &large, "", nil); !errors.Is(err, apperrors.ErrValidation) {
+		t.Errorf("Expected a payment above the step-up threshold to be refused, got %v", err)
+	}
+	small := decimal.NewFromInt(100)
+	if _, _, err := linkUC.PayLink(context.Background(), 3, tips.Token, &small, "", nil); err != nil {
+		t.Errorf("Expected a payment at the threshold to go through, got %v", err)
+	}
+}
@@ -0,0 +1,182 @@
+package usecases
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/limistah/wallet-service/internal/apperrors"
+	"github.com/limistah/wallet-service/internal/models"
+	"github.com/limistah/wallet-service/internal/repositories"
+	"github.com/shopspring/decimal"
+	"gorm.io/gorm"
+)
+
+// PINPolicy controls which debits need the transaction PIN and how wrong PINs lock it
+type PINPolicy struct {
+	// Threshold is the amount above which withdrawals and transfers need the PIN of users who set one;
+	// zero asks for it on every debit
+	Threshold decimal.Decimal
+	// MaxAttempts wrong PINs in a row lock the PIN for Cooldown; 0 disables the lock
+	MaxAttempts int
+	Cooldown    time.Duration
+}
+
+// DefaultPINPolicy asks for the PIN on every debit and locks it for 30 minutes after 5 wrong PINs
+func DefaultPINPolicy() PINPolicy {
+	return PINPolicy{
+		Threshold:   decimal.Zero,
+		MaxAttempts: 5,
+		Cooldown:    30 * time.Minute,
+	}
+}
+
+const (
+	minPINLength = 4
+	maxPINLength = 6
+)
+
+type pinUseCase struct {
+	repos    *repositories.Repositories
+	settings *LiveSettings
+	now      func() time.Time
+}
+
+// NewPINUseCase creates a new transaction PIN use case
+func NewPINUseCase(repos *repositories.Repositories, opts ...Option) PINUseCase {
+	o := newOptions(opts)
+	return &pinUseCase{
+		repos:    repos,
+		settings: o.settings,
+		now:      time.Now,
+	}
+}
+
+// SetPIN sets the first transaction PIN of a user
+func (uc *pinUseCase) SetPIN(ctx context.Context, userID uint, pin string) error {
+	user, err := uc.getUser(ctx, userID)
+	if err != nil {
+		return err
+	}
+	if user.HasTransactionPIN() {
+		return apperrors.ErrPINAlreadySet
+	}
+	return uc.storePIN(ctx, user, pin)
+}
+
+// ChangePIN replaces the transaction PIN; a wrong current PIN counts towards locking it
+func (uc *pinUseCase) ChangePIN(ctx context.Context, userID uint, currentPIN, newPIN string) error {
+	user, err := uc.getUser(ctx, userID)
+	if err != nil {
+		return err
+	}
+	if !user.HasTransactionPIN() {
+		return apperrors.ErrPINNotSet
+	}
+	if err := uc.checkPIN(ctx, user, currentPIN); err != nil {
+		return err
+	}
+	return uc.storePIN(ctx, user, newPIN)
+}
+
+// ResetPIN replaces a forgotten or locked transaction PIN after checking the account password
+func (uc *pinUseCase) ResetPIN(ctx context.Context, userID uint, password, newPIN string) error {
+	user, err := uc.getUser(ctx, userID)
+	if err != nil {
+		return err
+	}
+	if err := user.CheckPassword(password); err != nil {
+		return apperrors.ErrIncorrectPassword
+	}
+	return uc.storePIN(ctx, user, newPIN)
+}
+
+// AuthorizeDebit checks the PIN sent with a withdrawal or transfer. Users without a PIN and debits
+// up to the threshold need none
+func (uc *pinUseCase) AuthorizeDebit(ctx context.Context, userID uint, amount decimal.Decimal, pin string) error {
+	policy := uc.settings.Load().PIN
+	if !amount.GreaterThan(policy.Threshold) {
+		return nil
+	}
+	user, err := uc.getUser(ctx, userID)
+	if err != nil {
+		return err
+	}
+	if !user.HasTransactionPIN() {
+		return nil
+	}
+	if pin == "" {
+		return apperrors.ErrPINRequired.Withf("transaction PIN is required for debits above %s", policy.Threshold.StringFixed(2))
+	}
+	return uc.checkPIN(ctx, user, pin)
+}
+
+// checkPIN verifies a PIN, locking it once too many wrong PINs were sent in a row
+func (uc *pinUseCase) checkPIN(ctx context.Context, user *models.User, pin string) error {
+	now := uc.now()
+	policy := uc.settings.Load().PIN
+	if user.IsPINLocked(now) {
+		return pinLockedError(*user.PINLockedUntil)
+	}
+
+	if err := user.CheckTransactionPIN(pin); err != nil {
+		if policy.MaxAttempts <= 0 {
+			return apperrors.ErrInvalidPIN
+		}
+		attempts, err := uc.repos.User.IncrementFailedPINAttempts(ctx, user.ID)
+		if err != nil {
+			return err
+		}
+		if attempts < policy.MaxAttempts {
+			return apperrors.ErrInvalidPIN.Withf("transaction PIN is incorrect, %d attempts left", policy.MaxAttempts-attempts)
+		}
+		lockedUntil := now.Add(policy.Cooldown)
+		if err := uc.repos.User.SetPINLock(ctx, user.ID, &lockedUntil); err != nil {
+			return err
+		}
+		return pinLockedError(lockedUntil)
+	}
+
+	if user.FailedPINAttempts > 0 || user.PINLockedUntil != nil {
+		if err := uc.repos.User.SetPINLock(ctx, user.ID, nil); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// storePIN hashes and saves a new PIN, which also lifts any lock
+func (uc *pinUseCase) storePIN(ctx context.Context, user *models.User, pin string) error {
+	if err := validatePIN(pin); err != nil {
+		return err
+	}
+	if err := user.SetTransactionPIN(pin); err != nil {
+		return err
+	}
+	return uc.repos.User.UpdateTransactionPIN(ctx, user.ID, user.TransactionPIN)
+}
+
+func (uc *pinUseCase) getUser(ctx context.Context, userID uint) (*models.User, error) {
+	user, err := uc.repos.User.GetByID(ctx, userID)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, apperrors.ErrUserNotFound
+	}
+	return user, err
+}
+
+// validatePIN checks that a PIN is 4 to 6 digits
+func validatePIN(pin string) error {
+	if len(pin) < minPINLength || len(pin) > maxPINLength {
+		return apperrors.ErrValidation.Withf("transaction PIN must be %d to %d digits", minPINLength, maxPINLength)
+	}
+	for _, r := range pin {
+		if r < '0' || r > '9' {
+			return apperrors.ErrValidation.Withf("transaction PIN must be %d to %d digits", minPINLength, maxPINLength)
+		}
+	}
+	return nil
+}
+
+func pinLockedError(lockedUntil time.Time) error {
+	return apperrors.ErrPINLocked.Withf("transaction PIN is locked until %s after too many wrong attempts", lockedUntil.UTC().Format(time.RFC3339))
+}
@@ -0,0 +1,158 @@
+package usecases
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/limistah/wallet-service/internal/apperrors"
+	"github.com/limistah/wallet-service/internal/models"
+	"github.com/limistah/wallet-service/internal/repositories"
+	"github.com/limistah/wallet-service/internal/repositories/memory"
+	"github.com/shopspring/decimal"
+)
+
+func newTestPINUseCase(t *testing.T, now *time.Time) (*pinUseCase, *models.User) {
+	t.Helper()
+	user := &models.User{ID: 1, Email: "user@example.com"}
+	if err := user.HashPassword("Password123"); err != nil {
+		t.Fatalf("Failed to hash password: %v", err)
+	}
+	userRepo := memory.NewUserRepository()
+	userRepo.Create(context.Background(), user)
+
+	repos := &repositories.Repositories{User: userRepo}
+	uc := NewPINUseCase(repos, WithPINPolicy(PINPolicy{
+		Threshold:   decimal.NewFromInt(100),
+		MaxAttempts: 3,
+		Cooldown:    30 * time.Minute,
+	})).(*pinUseCase)
+	uc.now = func() time.Time { return *now }
+	return uc, user
+}
+
+func TestPINUseCase_SetPIN(t *testing.T) {
+	now := time.Date(2025, 3, 1, 12, 0, 0, 0, time.UTC)
+	uc, user := newTestPINUseCase(t, &now)
+
+	for _, pin := range []string{"123", "1234567", "12a4"} {
+		if err := uc.SetPIN(context.Background(), 1, pin); !errors.Is(err, apperrors.ErrValidation) {
+			t.Errorf("Expected PIN %q to be refused, got %v", pin, err)
+		}
+	}
+
+	if err := uc.SetPIN(context.Background(), 1, "4821"); err != nil {
+		t.Fatalf("Expected the PIN to be set, got %v", err)
+	}
+	user, _ = uc.repos.User.GetByID(context.Background(), 1)
+	if user.TransactionPIN == "4821" || user.CheckTransactionPIN("4821") != nil {
+		t.Errorf("Expected the PIN to be stored hashed")
+	}
+
+	if err := uc.SetPIN(context.Background(), 1, "7305"); !errors.Is(err, apperrors.ErrPINAlreadySet) {
+		t.Errorf("Expected a second PIN to be refused, got %v", err)
+	}
+}
+
+func TestPINUseCase_AuthorizeDebit(t *testing.T) {
+	now := time.Date(2025, 3, 1, 12, 0, 0, 0, time.UTC)
+	uc, _ := newTestPINUseCase(t, &now)
+
+	// Users without a PIN are not asked for one
+	if err := uc.AuthorizeDebit(context.Background(), 1, decimal.NewFromInt(500), ""); err != nil {
+		t.Errorf("Expected debits to pass without a PIN set, got %v", err)
+	}
+
+	if err := uc.SetPIN(context.Background(), 1, "4821"); err != nil {
+		t.Fatalf("Failed to set PIN: %v", err)
+	}
+
+	if err := uc.AuthorizeDebit(context.Background(), 1, decimal.NewFromInt(100), ""); err != nil {
+		t.Errorf("Expected debits up to the threshold to pass without the PIN, got %v", err)
+	}
+	if err := uc.AuthorizeDebit(context.Background(), 1, decimal.NewFromInt(500), ""); !errors.Is(err, apperrors.ErrPINRequired) {
+		t.Errorf("Expected the PIN to be required above the threshold, got %v", err)
+	}
+	if err := uc.AuthorizeDebit(context.Background(), 1, decimal.NewFromInt(500), "0000"); !errors.Is(err, apperrors.ErrInvalidPIN) {
+		t.Errorf("Expected a wrong PIN to be refused, got %v", err)
+	}
+	if err := uc.AuthorizeDebit(context.Background(), 1, decimal.NewFromInt(500), "4821"); err != nil {
+		t.Errorf("Expected the right PIN to authorize the debit, got %v", err)
+	}
+}
+
+func TestPINUseCase_LocksAfterMaxAttempts(t *testing.T) {
+	now := time.Date(2025, 3, 1, 12, 0, 0, 0, time.UTC)
+	uc, user := newTestPINUseCase(t, &now)
+	if err := uc.SetPIN(context.Background(), 1, "4821"); err != nil {
+		t.Fatalf("Failed to set PIN: %v", err)
+	}
+
+	amount := decimal.NewFromInt(500)
+	for i := 0; i < 2; i++ {
+		if err := uc.AuthorizeDebit(context.Background(), 1, amount, "0000"); !errors.Is(err, apperrors.ErrInvalidPIN) {
+			t.Fatalf("Expected invalid PIN on attempt %d, got %v", i+1, err)
+		}
+	}
+	if err := uc.AuthorizeDebit(context.Background(), 1, amount, "0000"); !errors.Is(err, apperrors.ErrPINLocked) {
+		t.Fatalf("Expected the PIN to be locked on the third wrong attempt, got %v", err)
+	}
+
+	// The right PIN is refused until the cooldown is over
+	if err := uc.AuthorizeDebit(context.Background(), 1, amount, "4821"); !errors.Is(err, apperrors.ErrPINLocked) {
+		t.Errorf("Expected the locked PIN to refuse the right PIN, got %v", err)
+	}
+	if err := uc.ChangePIN(context.Background(), 1, "4821", "7305"); !errors.Is(err, apperrors.ErrPINLocked) {
+		t.Errorf("Expected the locked PIN not to be changeable, got %v", err)
+	}
+
+	now = now.Add(31 * time.Minute)
+	if err := uc.AuthorizeDebit(context.Background(), 1, amount, "4821"); err != nil {
+		t.Errorf("Expected the right PIN to pass after the cooldown, got %v", err)
+	}
+	user, _ = uc.repos.User.GetByID(context.Background(), 1)
+	if user.PINLockedUntil != nil || user.FailedPINAttempts != 0 {
+		t.Errorf("Expected the lock to be cleared, got %v and %d attempts", user.PINLockedUntil, user.FailedPINAttempts)
+	}
+}
+
+func TestPINUseCase_ChangeAndResetPIN(t *testing.T) {
+	now := time.Date(2025, 3, 1, 12, 0, 0, 0, time.UTC)
+	uc, user := newTestPINUseCase(t, &now)
+
+	if err := uc.ChangePIN(context.Background(), 1, "4821", "7305"); !errors.Is(err, apperrors.ErrPINNotSet) {
+		t.Errorf("Expected changing a missing PIN to fail, got %v", err)
+	}
+	if err := uc.SetPIN(context.Background(), 1, "4821"); err != nil {
+		t.Fatalf("Failed to set PIN: %v", err)
+	}
+	if err := uc.ChangePIN(context.Background(), 1, "0000", "7305"); !errors.Is(err, apperrors.ErrInvalidPIN) {
+		t.Errorf("Expected a wrong current PIN to be refused, got %v", err)
+	}
+	if err := uc.ChangePIN(context.Background(), 1, "4821", "7305"); err != nil {
+		t.Fatalf("Expected the PIN to change, got %v", err)
+	}
+	user, _ = uc.repos.User.GetByID(context.Background(), 1)
+	if user.CheckTransactionPIN("7305") != nil {
+		t.Errorf("Expected the new PIN to be stored")
+	}
+
+	// Lock the PIN, then reset it with the password
+	for i := 0; i < 3; i++ {
+		uc.AuthorizeDebit(context.Background(), 1, decimal.NewFromInt(500), "0000")
+	}
+	user, _ = uc.repos.User.GetByID(context.Background(), 1)
+	if !user.IsPINLocked(now) {
+		t.Fatalf("Expected the PIN to be locked")
+	}
+	if err := uc.ResetPIN(context.Background(), 1, "wrong-password", "1357"); !errors.Is(err, apperrors.ErrIncorrectPassword) {
+		t.Errorf("Expected a wrong password to be refused, got %v", err)
+	}
+	if err := uc.ResetPIN(context.Background(), 1, "Password123", "1357"); err != nil {
+		t.Fatalf("Expected the PIN to be reset, got %v", err)
+	}
+	if err := uc.AuthorizeDebit(context.Background(), 1, decimal.NewFromInt(500), "1357"); err != nil {
+		t.Errorf("Expected the reset PIN to be unlocked and accepted, got %v", err)
+	}
+}
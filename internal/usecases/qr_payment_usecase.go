@@ -0,0 +1,109 @@
+package usecases
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/limistah/wallet-service/internal/apperrors"
+	"github.com/limistah/wallet-service/internal/fraud"
+	"github.com/limistah/wallet-service/internal/models"
+	"github.com/limistah/wallet-service/internal/payments"
+	"github.com/limistah/wallet-service/internal/repositories"
+	"github.com/limistah/wallet-service/internal/utils"
+	"github.com/shopspring/decimal"
+)
+
+// qrMaxNoteLength mirrors the limit enforced when parsing scanned payloads
+const qrMaxNoteLength = 100
+
+type qrPaymentUseCase struct {
+	repos         *repositories.Repositories
+	walletUseCase WalletUseCase
+	authorizer    debitAuthorizer
+}
+
+// NewQRPaymentUseCase creates a new QR payment use case
+func NewQRPaymentUseCase(repos *repositories.Repositories, walletUseCase WalletUseCase, opts ...Option) QRPaymentUseCase {
+	o := newOptions(opts)
+	return &qrPaymentUseCase{
+		repos:         repos,
+		walletUseCase: walletUseCase,
+		authorizer:    newDebitAuthorizer(repos, o),
+	}
+}
+
+// GenerateCode builds the payload a user shows to get paid into their wallet
+func (uc *qrPaymentUseCase) GenerateCode(ctx context.Context, userID uint, amount *decimal.Decimal, note string) (*payments.QRPayload, error) {
+	if amount != nil && amount.LessThanOrEqual(decimal.Zero) {
+		return nil, apperrors.ErrInvalidAmount
+	}
+	if len(note) > qrMaxNoteLength {
+		return nil, apperrors.ErrValidation.Withf("note is too long")
+	}
+
+	wallet, err := uc.walletUseCase.GetWalletByUserID(ctx, userID)
+	if err != nil {
+		return nil, apperrors.ErrWalletNotFound
+	}
+	if !wallet.IsActive() {
+		return nil, apperrors.ErrWalletInactive
+	}
+	if err := requireTierFeature(ctx, uc.repos, wallet, func(t *models.WalletTier) bool { return t.QRPaymentsEnabled }, "QR payments"); err != nil {
+		return nil, err
+	}
+
+	return &payments.QRPayload{
+		WalletID: wallet.ID,
+		Currency: wallet.Currency,
+		Amount:   amount,
+		Note:     note,
+	}, nil
+}
+
+// PayCode decodes a scanned payload and transfers from the payer's wallet to the wallet it names; pin
+// authorizes the payment once its amount is known
+func (uc *qrPaymentUseCase) PayCode(ctx context.Context, payerID uint, rawPayload string, amount *decimal.Decimal, pin string, origin *fraud.Origin) (*models.Transaction, error) {
+	payload, err := payments.ParseQRPayload(rawPayload)
+	if err != nil {
+		return nil, err
+	}
+
+	payAmount, err := resolvePaymentAmount(payload.Amount, amount, "QR code")
+	if err != nil {
+		return nil, err
+	}
+
+	recipientWallet, err := uc.walletUseCase.GetWallet(ctx, payload.WalletID)
+	if err != nil || recipientWallet.Sandbox {
+		return nil, apperrors.ErrCounterpartyWalletNotFound.Withf("recipient wallet not found")
+	}
+	if recipientWallet.Currency != payload.Currency {
+		return nil, apperrors.ErrCurrencyMismatch.Withf("QR code currency does not match recipient wallet")
+	}
+
+	payerWallet, err := uc.walletUseCase.GetWalletByUserID(ctx, payerID)
+	if err != nil {
+		return nil, apperrors.ErrWalletNotFound
+	}
+	if payerWallet.ID == recipientWallet.ID {
+		return nil, apperrors.ErrSelfPayment.Withf("cannot pay your own QR code")
+	}
+	if payerWallet.Currency != recipientWallet.Currency {
+		return nil, apperrors.ErrCurrencyMismatch.Withf("wallet currency does not match QR code")
+	}
+	if err := uc.authorizer.authorizeUnchallenged(ctx, payerID, payAmount, pin); err != nil {
+		return nil, err
+	}
+
+	description := "QR payment"
+	if payload.Note != "" {
+		description = fmt.Sprintf("%s: %s", description, payload.Note)
+	}
+
+	outTx, _, err := uc.walletUseCase.TransferFunds(ctx, payerWallet.ID, recipientWallet.ID, payAmount, "QR-"+utils.GenerateUniqueID(), description, origin)
+	if err != nil {
+		return nil, err
+	}
+
+	return outTx, nil
+}
@@ -0,0 +1,67 @@
+package usecases
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/limistah/wallet-service/internal/apperrors"
+	"github.com/limistah/wallet-service/internal/models"
+	"github.com/limistah/wallet-service/internal/payments"
+	"github.com/limistah/wallet-service/internal/repositories/memory"
+	"github.com/shopspring/decimal"
+)
+
+func TestQRPaymentUseCase_PayCode(t *testing.T) {
+	repos, _ := setupTestEnvironment()
+	repos.Wallet.Create(context.Background(), &models.Wallet{ID: 2, UserID: 2, Currency: "USD", Status: models.WalletStatusActive})
+	repos.Wallet.Create(context.Background(), &models.Wallet{ID: 3, UserID: 3, Currency: "USD", Status: models.WalletStatusActive})
+	repos.Wallet.Create(context.Background(), &models.Wallet{ID: 4, UserID: 4, Currency: "EUR", Status: models.WalletStatusActive})
+	payer := &models.User{ID: 3, Email: "payer@example.com"}
+	if err := payer.SetTransactionPIN("4821"); err != nil {
+		t.Fatalf("Failed to set PIN: %v", err)
+	}
+	repos.User.Create(context.Background(), payer)
+
+	walletUC := &transferringWalletUseCase{wallets: repos.Wallet.(*memory.WalletRepository)}
+	qrUC := NewQRPaymentUseCase(repos, walletUC)
+
+	price := decimal.NewFromFloat(4.5)
+	payload, err := qrUC.GenerateCode(context.Background(), 2, &price, "Coffee")
+	if err != nil {
+		t.Fatalf("Unexpected error generating code: %v", err)
+	}
+	if payload.WalletID != 2 || payload.Currency != "USD" {
+		t.Fatalf("Unexpected payload: %+v", payload)
+	}
+
+	if _, err := qrUC.PayCode(context.Background(), 2, payload.Encode(), nil, "", nil); err == nil || err.Error() != "cannot pay your own QR code" {
+		t.Errorf("Expected self payments to be rejected, got %v", err)
+	}
+	if _, err := qrUC.PayCode(context.Background(), 4, payload.Encode(), nil, "", nil); err == nil || err.Error() != "wallet currency does not match QR code" {
+		t.Errorf("Expected a currency mismatch to be rejected, got %v", err)
+	}
+	if _, err := qrUC.PayCode(context.Background(), 3, "not a code", nil, "", nil); err != payments.ErrInvalidQRPayload {
+		t.Errorf("Expected an invalid payload error, got %v", err)
+	}
+
+	if _, err := qrUC.PayCode(context.Background(), 3, payload.Encode(), nil, "", nil); !errors.Is(err, apperrors.ErrPINRequired) {
+		t.Errorf("Expected the payer's PIN to be required, got %v", err)
+	}
+	if _, err := qrUC.PayCode(context.Background(), 3, payload.Encode(), nil, "0000", nil); !errors.Is(err, apperrors.ErrInvalidPIN) {
+		t.Errorf("Expected a wrong PIN to be rejected, got %v", err)
+	}
+
+	transaction, err := qrUC.PayCode(context.Background(), 3, payload.Encode(), nil, "4821", nil)
+	if err != nil {
+		t.Fatalf("Unexpected error paying code: %v", err)
+	}
+	if transaction.WalletID != 3 || !transaction.Amount.Equal(price) {
+		t.Errorf("Unexpected transaction: %+v", transaction)
+	}
+
+	open, _ := qrUC.GenerateCode(context.Background(), 2, nil, "")
+	if _, err := qrUC.PayCode(context.Background(), 3, open.Encode(), nil, "4821", nil); err == nil || err.Error() != "amount is required for this QR code" {
+		t.Errorf("Expected open codes to require an amount, got %v", err)
+	}
+}
@@ -0,0 +1,185 @@
+package usecases
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/limistah/wallet-service/internal/models"
+	"github.com/shopspring/decimal"
+	"gorm.io/gorm"
+)
+
+const (
+	// doubleEntryBatchSize bounds how many transactions of a wallet the double-entry check loads at once
+	doubleEntryBatchSize = 500
+	// doubleEntryNotesLimit bounds how many broken pairs are described in the notes of a report
+	doubleEntryNotesLimit = 5
+)
+
+// checkDoubleEntries verifies that every completed transaction of a wallet has a completed counterpart
+// of equal amount and opposite type, and returns a description of each broken pair. Main legs link to
+// their counterpart through RelatedTransactionID; both fee legs link to the transaction the fee was
+// charged for, so a fee leg's counterpart is the other fee leg linked to it. Legs of a multi-leg
+// journal entry are checked together: the entry's debits and credits must balance
+func (uc *reconciliationUseCase) checkDoubleEntries(ctx context.Context, walletID uint) ([]string, error) {
+	var issues []string
+	feeLegs := make(map[uint][]models.Transaction)
+	journalEntries := make(map[uint]bool)
+
+	err := uc.repos.Transaction.FindCompletedByWalletID(ctx, walletID, doubleEntryBatchSize, func(transactions []models.Transaction) error {
+		for i := range transactions {
+			transaction := &transactions[i]
+			if transaction.JournalEntryID != nil {
+				entryID := *transaction.JournalEntryID
+				if journalEntries[entryID] {
+					continue
+				}
+				journalEntries[entryID] = true
+
+				entry, err := uc.repos.JournalEntry.GetByID(ctx, entryID)
+				if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+					return err
+				}
+				if issue := journalEntryIssue(transaction, entry); issue != "" {
+					issues = append(issues, issue)
+				}
+				continue
+			}
+
+			if transaction.RelatedTransactionID == nil {
+				issues = append(issues, fmt.Sprintf("transaction %d has no linked counterpart", transaction.ID))
+				continue
+			}
+
+			if transaction.TransactionPurpose == models.TransactionPurposeFee {
+				chargedID := *transaction.RelatedTransactionID
+				legs, ok := feeLegs[chargedID]
+				if !ok {
+					var err error
+					legs, err = uc.repos.Transaction.GetByRelatedTransactionID(ctx, chargedID)
+					if err != nil {
+						return err
+					}
+					feeLegs[chargedID] = legs
+				}
+				if issue := feeCounterpartIssue(transaction, legs); issue != "" {
+					issues = append(issues, issue)
+				}
+				continue
+			}
+
+			if issue := counterpartIssue(transaction, transaction.RelatedTransaction); issue != "" {
+				issues = append(issues, issue)
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return issues, nil
+}
+
+// counterpartIssue describes why counterpart does not balance transaction, or returns "" when it does
+func counterpartIssue(transaction, counterpart *models.Transaction) string {
+	switch {
+	case counterpart == nil:
+		return fmt.Sprintf("transaction %d is linked to missing transaction %d", transaction.ID, *transaction.RelatedTransactionID)
+	case !convertedAmountsMatch(transaction, counterpart):
+		return fmt.Sprintf("transaction %d of %s is linked to transaction %d of %s",
+			transaction.ID, transaction.Amount.String(), counterpart.ID, counterpart.Amount.String())
+	case counterpart.TransactionType == transaction.TransactionType:
+		return fmt.Sprintf("transaction %d and its counterpart %d are both %s", transaction.ID, counterpart.ID, transaction.TransactionType)
+	case !counterpart.IsCompleted():
+		return fmt.Sprintf("transaction %d is completed but its counterpart %d is %s", transaction.ID, counterpart.ID, counterpart.Status)
+	default:
+		return ""
+	}
+}
+
+// convertedAmountsMatch reports whether two linked legs carry the same value. The legs of a transfer
+// between currencies keep the exchange rate it was made at in their metadata; the credited leg must
+// then hold the debited amount converted at that rate, rounded as it was when the transfer was priced
+func convertedAmountsMatch(transaction, counterpart *models.Transaction) bool {
+	rate := legExchangeRate(transaction)
+	if rate.Equal(decimal.NewFromInt(1)) {
+		return counterpart.Amount.Equal(transaction.Amount)
+	}
+	debit, credit := transaction, counterpart
+	if transaction.TransactionType == models.TransactionTypeCredit {
+		debit, credit = counterpart, transaction
+	}
+	return debit.Amount.Mul(rate).Round(2).Equal(credit.Amount)
+}
+
+// legExchangeRate returns the exchange rate recorded in the metadata of a transfer leg, or 1 for legs
+// that moved money within one currency
+func legExchangeRate(transaction *models.Transaction) decimal.Decimal {
+	var metadata struct {
+		ExchangeRate decimal.NullDecimal `json:"exchange_rate"`
+	}
+	if transaction.Metadata == "" || json.Unmarshal([]byte(transaction.Metadata), &metadata) != nil ||
+		!metadata.ExchangeRate.Valid || !metadata.ExchangeRate.Decimal.IsPositive() {
+		return decimal.NewFromInt(1)
+	}
+	return metadata.ExchangeRate.Decimal
+}
+
+// feeCounterpartIssue looks for the counter leg of a fee leg among the transactions linked to the same
+// charged transaction
+func feeCounterpartIssue(fee *models.Transaction, linked []models.Transaction) string {
+	var candidate *models.Transaction
+	for i := range linked {
+		leg := &linked[i]
+		if leg.ID == fee.ID || leg.TransactionPurpose != models.TransactionPurposeFee || leg.TransactionType == fee.TransactionType {
+			continue
+		}
+		if counterpartIssue(fee, leg) == "" {
+			return ""
+		}
+		candidate = leg
+	}
+	if candidate == nil {
+		return fmt.Sprintf("fee transaction %d has no counter leg", fee.ID)
+	}
+	return counterpartIssue(fee, candidate)
+}
+
+// journalEntryIssue describes why the journal entry of a leg does not balance, or returns "" when it does
+func journalEntryIssue(leg *models.Transaction, entry *models.JournalEntry) string {
+	if entry == nil {
+		return fmt.Sprintf("transaction %d belongs to missing journal entry %d", leg.ID, *leg.JournalEntryID)
+	}
+
+	debits, credits := decimal.Zero, decimal.Zero
+	for _, entryLeg := range entry.Legs {
+		if !entryLeg.IsCompleted() {
+			return fmt.Sprintf("journal entry %d has leg %d %s", entry.ID, entryLeg.ID, entryLeg.Status)
+		}
+		if entryLeg.TransactionType == models.TransactionTypeDebit {
+			debits = debits.Add(entryLeg.Amount)
+		} else {
+			credits = credits.Add(entryLeg.Amount)
+		}
+	}
+	if len(entry.Legs) < 2 || !debits.Equal(credits) {
+		return fmt.Sprintf("journal entry %d is unbalanced: debits %s, credits %s", entry.ID, debits.String(), credits.String())
+	}
+	return ""
+}
+
+// describeDoubleEntryIssues summarises broken pairs for the notes of a report
+func describeDoubleEntryIssues(issues []string) string {
+	described := issues
+	if len(described) > doubleEntryNotesLimit {
+		described = described[:doubleEntryNotesLimit]
+	}
+	notes := fmt.Sprintf("Broken double-entry pairs (%d): %s", len(issues), strings.Join(described, "; "))
+	if len(issues) > len(described) {
+		notes += fmt.Sprintf("; and %d more", len(issues)-len(described))
+	}
+	return notes
+}
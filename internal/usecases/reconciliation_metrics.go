@@ -0,0 +1,79 @@
+package usecases
+
+import (
+	"context"
+	"time"
+
+	"github.com/limistah/wallet-service/internal/metrics"
+	"github.com/limistah/wallet-service/internal/repositories"
+)
+
+// Scopes of a reconciliation run in the metrics
+const (
+	reconciliationScopeAll     = "all"
+	reconciliationScopeWallet  = "wallet"
+	reconciliationScopePartial = "partial" // Runs over a cohort of wallets
+)
+
+// reconciliationDurationBuckets cover single wallet checks in milliseconds up to full runs over
+// every wallet in minutes
+var reconciliationDurationBuckets = []float64{0.005, 0.01, 0.05, 0.1, 0.5, 1, 5, 30, 60, 300, 900}
+
+// ReconciliationMetrics exports how reconciliation runs go and how far the ledger has drifted, so
+// SRE can alert on it. The drift is read from the stored reports when scraped, so every instance
+// reports the same figures
+type ReconciliationMetrics struct {
+	lastSuccess *metrics.Gauge
+	duration    *metrics.Histogram
+}
+
+// NewReconciliationMetrics registers the reconciliation metrics; repos must not be scoped to a tenant
+// so the drift covers the whole deployment
+func NewReconciliationMetrics(registry *metrics.Registry, repos *repositories.Repositories) *ReconciliationMetrics {
+	m := &ReconciliationMetrics{
+		lastSuccess: registry.NewGauge("wallet_reconciliation_last_success_timestamp_seconds",
+			"Unix time of the last reconciliation run that completed on this instance", "scope"),
+		duration: registry.NewHistogram("wallet_reconciliation_run_duration_seconds",
+			"How long reconciliation runs take", reconciliationDurationBuckets, "scope"),
+	}
+
+	registry.NewGaugeFunc("wallet_reconciliation_open_mismatch_wallets",
+		"Wallets whose latest reconciliation found a mismatch or a broken double entry", []string{"currency"},
+		func() ([]metrics.Sample, error) {
+			return openMismatchSamples(context.Background(), repos, func(total repositories.OpenMismatchTotal) float64 {
+				return float64(total.Wallets)
+			})
+		})
+	registry.NewGaugeFunc("wallet_reconciliation_open_mismatch_amount",
+		"Sum of the absolute differences of the wallets with an open mismatch", []string{"currency"},
+		func() ([]metrics.Sample, error) {
+			return openMismatchSamples(context.Background(), repos, func(total repositories.OpenMismatchTotal) float64 {
+				return total.Amount.InexactFloat64()
+			})
+		})
+	return m
+}
+
+func openMismatchSamples(ctx context.Context, repos *repositories.Repositories, value func(repositories.OpenMismatchTotal) float64) ([]metrics.Sample, error) {
+	totals, err := repos.Reconciliation.OpenMismatches(ctx)
+	if err != nil {
+		return nil, err
+	}
+	samples := make([]metrics.Sample, 0, len(totals))
+	for _, total := range totals {
+		samples = append(samples, metrics.Sample{Labels: []string{total.Currency}, Value: value(total)})
+	}
+	return samples, nil
+}
+
+// observeRun records a run started at start; only runs without an error move the last success
+func (m *ReconciliationMetrics) observeRun(scope string, start time.Time, err error) {
+	if m == nil {
+		return
+	}
+	now := time.Now()
+	m.duration.Observe(now.Sub(start).Seconds(), scope)
+	if err == nil {
+		m.lastSuccess.Set(float64(now.Unix()), scope)
+	}
+}
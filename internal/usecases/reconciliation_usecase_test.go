@@ -1,21 +1,28 @@
 package usecases
 
 import (
+	"context"
+	"errors"
 	"fmt"
+	"strings"
 	"testing"
+	"time"
 
+	"github.com/limistah/wallet-service/internal/apperrors"
+	"github.com/limistah/wallet-service/internal/metrics"
 	"github.com/limistah/wallet-service/internal/models"
 	"github.com/limistah/wallet-service/internal/repositories"
+	"github.com/limistah/wallet-service/internal/repositories/memory"
 	"github.com/shopspring/decimal"
 )
 
 // Helper function to set up test environment for reconciliation tests
 func setupReconciliationTestEnvironment() *repositories.Repositories {
-	userRepo := NewMockUserRepository()
-	walletRepo := NewMockWalletRepository()
-	transactionRepo := NewMockTransactionRepository()
-	transactionTypeRepo := NewMockTransactionTypeRepository()
-	reconciliationRepo := NewMockReconciliationRepository()
+	userRepo := memory.NewUserRepository()
+	walletRepo := memory.NewWalletRepository()
+	transactionRepo := memory.NewTransactionRepository()
+	transactionTypeRepo := memory.NewTransactionTypeRepository()
+	reconciliationRepo := memory.NewReconciliationRepository()
 
 	// Create system user and wallet
 	systemUser := &models.User{
@@ -23,7 +30,7 @@ func setupReconciliationTestEnvironment() *repositories.Repositories {
 		Email: models.SystemAccountEmail,
 		Name:  "System Account",
 	}
-	userRepo.Create(systemUser)
+	userRepo.Create(context.Background(), systemUser)
 
 	systemWallet := &models.Wallet{
 		ID:       1,
@@ -33,20 +40,50 @@ func setupReconciliationTestEnvironment() *repositories.Repositories {
 		Status:   models.WalletStatusActive,
 		Version:  0,
 	}
-	walletRepo.Create(systemWallet)
+	walletRepo.Create(context.Background(), systemWallet)
 
 	repos := &repositories.Repositories{
-		User:            userRepo,
-		Wallet:          walletRepo,
-		Transaction:     transactionRepo,
-		TransactionType: transactionTypeRepo,
-		Reconciliation:  reconciliationRepo,
-		DB:              nil, // Skip DB for unit tests
+		User:               userRepo,
+		Wallet:             walletRepo,
+		Transaction:        transactionRepo,
+		TransactionType:    transactionTypeRepo,
+		TransactionPurpose: memory.NewTransactionPurposeRepository(),
+		Reconciliation:     reconciliationRepo,
+		BalanceCheckpoint:  memory.NewBalanceCheckpointRepository(),
+		DB:                 nil, // Skip DB for unit tests
 	}
 
 	return repos
 }
 
+// counterpartyWalletID holds the counter legs written by createBalancedTransaction; it is never reconciled
+const counterpartyWalletID = 9999
+
+// createBalancedTransaction records a transaction together with its counter leg on the counterparty
+// wallet, linked both ways, so it passes the double-entry check
+func createBalancedTransaction(repo *memory.TransactionRepository, transaction *models.Transaction) {
+	if transaction.TransactionType == "" {
+		transaction.TransactionType = models.TransactionTypeCredit
+	}
+	counterType := models.TransactionTypeDebit
+	if transaction.TransactionType == models.TransactionTypeDebit {
+		counterType = models.TransactionTypeCredit
+	}
+
+	repo.Create(context.Background(), transaction)
+	counterpart := &models.Transaction{
+		WalletID:             counterpartyWalletID,
+		TransactionType:      counterType,
+		TransactionPurpose:   transaction.TransactionPurpose,
+		Amount:               transaction.Amount,
+		Status:               transaction.Status,
+		RelatedTransactionID: &transaction.ID,
+	}
+	repo.Create(context.Background(), counterpart)
+	repo.LinkRelated(context.Background(), transaction.ID, counterpart.ID)
+	transaction.RelatedTransactionID = &counterpart.ID
+}
+
 // Helper function to check if a string contains a substring
 func containsString(str, substr string) bool {
 	for i := 0; i <= len(str)-len(substr); i++ {
@@ -63,16 +100,16 @@ func TestReconciliationUseCase_PerformWalletReconciliation(t *testing.T) {
 	reconciliationUC := NewReconciliationUseCase(repos)
 
 	// Create test user and wallet
-	userRepo := repos.User.(*MockUserRepository)
-	walletRepo := repos.Wallet.(*MockWalletRepository)
-	transactionRepo := repos.Transaction.(*MockTransactionRepository)
+	userRepo := repos.User.(*memory.UserRepository)
+	walletRepo := repos.Wallet.(*memory.WalletRepository)
+	transactionRepo := repos.Transaction.(*memory.TransactionRepository)
 
 	user := &models.User{
 		ID:    20,
 		Email: "reconcile@example.com",
 		Name:  "Reconcile User",
 	}
-	userRepo.Create(user)
+	userRepo.Create(context.Background(), user)
 
 	wallet := &models.Wallet{
 		ID:       20,
@@ -82,7 +119,7 @@ func TestReconciliationUseCase_PerformWalletReconciliation(t *testing.T) {
 		Status:   models.WalletStatusActive,
 		Version:  0,
 	}
-	walletRepo.Create(wallet)
+	walletRepo.Create(context.Background(), wallet)
 
 	t.Run("should create reconciliation report for matching balance", func(t *testing.T) {
 		// Create transactions that match the wallet balance
@@ -91,9 +128,9 @@ func TestReconciliationUseCase_PerformWalletReconciliation(t *testing.T) {
 			Amount:   decimal.NewFromFloat(100.00),
 			Status:   models.TransactionStatusCompleted,
 		}
-		transactionRepo.Create(tx1)
+		createBalancedTransaction(transactionRepo, tx1)
 
-		report, err := reconciliationUC.PerformWalletReconciliation(20)
+		report, err := reconciliationUC.PerformWalletReconciliation(context.Background(), 20)
 		if err != nil {
 			t.Errorf("Expected no error, got: %v", err)
 		}
@@ -131,7 +168,7 @@ func TestReconciliationUseCase_PerformWalletReconciliation(t *testing.T) {
 			Email: "mismatch@example.com",
 			Name:  "Mismatch User",
 		}
-		userRepo.Create(mismatchUser)
+		userRepo.Create(context.Background(), mismatchUser)
 
 		mismatchWallet := &models.Wallet{
 			ID:       21,
@@ -141,7 +178,7 @@ func TestReconciliationUseCase_PerformWalletReconciliation(t *testing.T) {
 			Status:   models.WalletStatusActive,
 			Version:  0,
 		}
-		walletRepo.Create(mismatchWallet)
+		walletRepo.Create(context.Background(), mismatchWallet)
 
 		// Create transactions that don't match the stored balance
 		tx2 := &models.Transaction{
@@ -149,9 +186,9 @@ func TestReconciliationUseCase_PerformWalletReconciliation(t *testing.T) {
 			Amount:   decimal.NewFromFloat(150.00), // Different from stored balance
 			Status:   models.TransactionStatusCompleted,
 		}
-		transactionRepo.Create(tx2)
+		createBalancedTransaction(transactionRepo, tx2)
 
-		report, err := reconciliationUC.PerformWalletReconciliation(21)
+		report, err := reconciliationUC.PerformWalletReconciliation(context.Background(), 21)
 		if err != nil {
 			t.Errorf("Expected no error, got: %v", err)
 		}
@@ -184,7 +221,7 @@ func TestReconciliationUseCase_PerformWalletReconciliation(t *testing.T) {
 	})
 
 	t.Run("should handle nonexistent wallet", func(t *testing.T) {
-		_, err := reconciliationUC.PerformWalletReconciliation(999)
+		_, err := reconciliationUC.PerformWalletReconciliation(context.Background(), 999)
 		if err == nil {
 			t.Error("Expected error for nonexistent wallet")
 		}
@@ -196,9 +233,9 @@ func TestReconciliationUseCase_PerformReconciliation(t *testing.T) {
 	reconciliationUC := NewReconciliationUseCase(repos)
 
 	// Create test users and wallets
-	userRepo := repos.User.(*MockUserRepository)
-	walletRepo := repos.Wallet.(*MockWalletRepository)
-	transactionRepo := repos.Transaction.(*MockTransactionRepository)
+	userRepo := repos.User.(*memory.UserRepository)
+	walletRepo := repos.Wallet.(*memory.WalletRepository)
+	transactionRepo := repos.Transaction.(*memory.TransactionRepository)
 
 	// Create first wallet with matching balance
 	user1 := &models.User{
@@ -206,7 +243,7 @@ func TestReconciliationUseCase_PerformReconciliation(t *testing.T) {
 		Email: "bulk1@example.com",
 		Name:  "Bulk User 1",
 	}
-	userRepo.Create(user1)
+	userRepo.Create(context.Background(), user1)
 
 	wallet1 := &models.Wallet{
 		ID:       22,
@@ -216,7 +253,7 @@ func TestReconciliationUseCase_PerformReconciliation(t *testing.T) {
 		Status:   models.WalletStatusActive,
 		Version:  0,
 	}
-	walletRepo.Create(wallet1)
+	walletRepo.Create(context.Background(), wallet1)
 
 	// Create matching transaction
 	tx1 := &models.Transaction{
@@ -224,7 +261,7 @@ func TestReconciliationUseCase_PerformReconciliation(t *testing.T) {
 		Amount:   decimal.NewFromFloat(300.00),
 		Status:   models.TransactionStatusCompleted,
 	}
-	transactionRepo.Create(tx1)
+	createBalancedTransaction(transactionRepo, tx1)
 
 	// Create second wallet with mismatched balance
 	user2 := &models.User{
@@ -232,7 +269,7 @@ func TestReconciliationUseCase_PerformReconciliation(t *testing.T) {
 		Email: "bulk2@example.com",
 		Name:  "Bulk User 2",
 	}
-	userRepo.Create(user2)
+	userRepo.Create(context.Background(), user2)
 
 	wallet2 := &models.Wallet{
 		ID:       23,
@@ -242,7 +279,7 @@ func TestReconciliationUseCase_PerformReconciliation(t *testing.T) {
 		Status:   models.WalletStatusActive,
 		Version:  0,
 	}
-	walletRepo.Create(wallet2)
+	walletRepo.Create(context.Background(), wallet2)
 
 	// Create mismatched transaction
 	tx2 := &models.Transaction{
@@ -250,10 +287,10 @@ func TestReconciliationUseCase_PerformReconciliation(t *testing.T) {
 		Amount:   decimal.NewFromFloat(350.00), // Different from wallet balance
 		Status:   models.TransactionStatusCompleted,
 	}
-	transactionRepo.Create(tx2)
+	createBalancedTransaction(transactionRepo, tx2)
 
 	t.Run("should perform bulk reconciliation for all wallets", func(t *testing.T) {
-		reports, err := reconciliationUC.PerformReconciliation()
+		reports, err := reconciliationUC.PerformReconciliation(context.Background())
 		if err != nil {
 			t.Errorf("Expected no error, got: %v", err)
 		}
@@ -283,12 +320,43 @@ func TestReconciliationUseCase_PerformReconciliation(t *testing.T) {
 			t.Error("Expected at least one mismatch report")
 		}
 	})
+
+	t.Run("should stream each wallet's outcome as it is reconciled", func(t *testing.T) {
+		var progress []ReconciliationProgress
+		err := reconciliationUC.StreamReconciliation(context.Background(), models.ReconciliationScope{}, func(p ReconciliationProgress) error {
+			progress = append(progress, p)
+			return nil
+		})
+		if err != nil {
+			t.Fatalf("Expected no error, got: %v", err)
+		}
+		if len(progress) == 0 || progress[len(progress)-1].Done != progress[0].Total {
+			t.Fatalf("Expected every wallet to be streamed, got %+v", progress)
+		}
+		for i, p := range progress {
+			if p.Done != i+1 || p.Report == nil || p.Report.WalletID != p.WalletID {
+				t.Errorf("Unexpected progress %d: %+v", i, p)
+			}
+		}
+	})
+
+	t.Run("should stop streaming when the receiver fails", func(t *testing.T) {
+		stop := errors.New("client went away")
+		calls := 0
+		err := reconciliationUC.StreamReconciliation(context.Background(), models.ReconciliationScope{}, func(ReconciliationProgress) error {
+			calls++
+			return stop
+		})
+		if !errors.Is(err, stop) || calls != 1 {
+			t.Errorf("Expected the run to stop after the first wallet, got %v after %d calls", err, calls)
+		}
+	})
 }
 
 func TestReconciliationUseCase_GetReconciliationReports(t *testing.T) {
 	repos := setupReconciliationTestEnvironment()
 	reconciliationUC := NewReconciliationUseCase(repos)
-	reconciliationRepo := repos.Reconciliation.(*MockReconciliationRepository)
+	reconciliationRepo := repos.Reconciliation.(*memory.ReconciliationRepository)
 
 	// Create test reconciliation reports
 	report1 := &models.ReconciliationReport{
@@ -299,7 +367,7 @@ func TestReconciliationUseCase_GetReconciliationReports(t *testing.T) {
 		Status:            models.ReconciliationStatusMatch,
 		Notes:             "Balance matches",
 	}
-	reconciliationRepo.Create(report1)
+	reconciliationRepo.Create(context.Background(), report1)
 
 	report2 := &models.ReconciliationReport{
 		WalletID:          25,
@@ -309,10 +377,10 @@ func TestReconciliationUseCase_GetReconciliationReports(t *testing.T) {
 		Status:            models.ReconciliationStatusMismatch,
 		Notes:             "Balance mismatch detected",
 	}
-	reconciliationRepo.Create(report2)
+	reconciliationRepo.Create(context.Background(), report2)
 
 	t.Run("should get reconciliation reports with pagination", func(t *testing.T) {
-		reports, err := reconciliationUC.GetReconciliationReports(1, 10)
+		reports, err := reconciliationUC.GetReconciliationReports(context.Background(), 1, 10)
 		if err != nil {
 			t.Errorf("Expected no error, got: %v", err)
 		}
@@ -346,7 +414,7 @@ func TestReconciliationUseCase_GetReconciliationReports(t *testing.T) {
 func TestReconciliationUseCase_GetMismatchReports(t *testing.T) {
 	repos := setupReconciliationTestEnvironment()
 	reconciliationUC := NewReconciliationUseCase(repos)
-	reconciliationRepo := repos.Reconciliation.(*MockReconciliationRepository)
+	reconciliationRepo := repos.Reconciliation.(*memory.ReconciliationRepository)
 
 	// Create test reconciliation reports - mix of match and mismatch
 	matchReport := &models.ReconciliationReport{
@@ -357,7 +425,7 @@ func TestReconciliationUseCase_GetMismatchReports(t *testing.T) {
 		Status:            models.ReconciliationStatusMatch,
 		Notes:             "Balance matches",
 	}
-	reconciliationRepo.Create(matchReport)
+	reconciliationRepo.Create(context.Background(), matchReport)
 
 	mismatchReport1 := &models.ReconciliationReport{
 		WalletID:          27,
@@ -367,7 +435,7 @@ func TestReconciliationUseCase_GetMismatchReports(t *testing.T) {
 		Status:            models.ReconciliationStatusMismatch,
 		Notes:             "Balance mismatch detected",
 	}
-	reconciliationRepo.Create(mismatchReport1)
+	reconciliationRepo.Create(context.Background(), mismatchReport1)
 
 	mismatchReport2 := &models.ReconciliationReport{
 		WalletID:          28,
@@ -377,10 +445,10 @@ func TestReconciliationUseCase_GetMismatchReports(t *testing.T) {
 		Status:            models.ReconciliationStatusMismatch,
 		Notes:             "Balance mismatch detected",
 	}
-	reconciliationRepo.Create(mismatchReport2)
+	reconciliationRepo.Create(context.Background(), mismatchReport2)
 
 	t.Run("should get only mismatch reports", func(t *testing.T) {
-		reports, err := reconciliationUC.GetMismatchReports(1, 10)
+		reports, err := reconciliationUC.GetMismatchReports(context.Background(), 1, 10)
 		if err != nil {
 			t.Errorf("Expected no error, got: %v", err)
 		}
@@ -489,15 +557,15 @@ func TestReconciliationUseCase_EdgeCases(t *testing.T) {
 
 	t.Run("should handle wallet with no transactions", func(t *testing.T) {
 		// Create test user and wallet with no transactions
-		userRepo := repos.User.(*MockUserRepository)
-		walletRepo := repos.Wallet.(*MockWalletRepository)
+		userRepo := repos.User.(*memory.UserRepository)
+		walletRepo := repos.Wallet.(*memory.WalletRepository)
 
 		user := &models.User{
 			ID:    29,
 			Email: "notx@example.com",
 			Name:  "No Transactions User",
 		}
-		userRepo.Create(user)
+		userRepo.Create(context.Background(), user)
 
 		wallet := &models.Wallet{
 			ID:       29,
@@ -507,9 +575,9 @@ func TestReconciliationUseCase_EdgeCases(t *testing.T) {
 			Status:   models.WalletStatusActive,
 			Version:  0,
 		}
-		walletRepo.Create(wallet)
+		walletRepo.Create(context.Background(), wallet)
 
-		report, err := reconciliationUC.PerformWalletReconciliation(29)
+		report, err := reconciliationUC.PerformWalletReconciliation(context.Background(), 29)
 		if err != nil {
 			t.Errorf("Expected no error, got: %v", err)
 		}
@@ -531,16 +599,16 @@ func TestReconciliationUseCase_EdgeCases(t *testing.T) {
 
 	t.Run("should handle wallet with pending transactions", func(t *testing.T) {
 		// Create test user and wallet
-		userRepo := repos.User.(*MockUserRepository)
-		walletRepo := repos.Wallet.(*MockWalletRepository)
-		transactionRepo := repos.Transaction.(*MockTransactionRepository)
+		userRepo := repos.User.(*memory.UserRepository)
+		walletRepo := repos.Wallet.(*memory.WalletRepository)
+		transactionRepo := repos.Transaction.(*memory.TransactionRepository)
 
 		user := &models.User{
 			ID:    30,
 			Email: "pending@example.com",
 			Name:  "Pending Transactions User",
 		}
-		userRepo.Create(user)
+		userRepo.Create(context.Background(), user)
 
 		wallet := &models.Wallet{
 			ID:       30,
@@ -550,7 +618,7 @@ func TestReconciliationUseCase_EdgeCases(t *testing.T) {
 			Status:   models.WalletStatusActive,
 			Version:  0,
 		}
-		walletRepo.Create(wallet)
+		walletRepo.Create(context.Background(), wallet)
 
 		// Create pending transaction (should not be included in calculation)
 		pendingTx := &models.Transaction{
@@ -558,7 +626,7 @@ func TestReconciliationUseCase_EdgeCases(t *testing.T) {
 			Amount:   decimal.NewFromFloat(50.00),
 			Status:   models.TransactionStatusPending, // Not completed
 		}
-		transactionRepo.Create(pendingTx)
+		createBalancedTransaction(transactionRepo, pendingTx)
 
 		// Create completed transaction
 		completedTx := &models.Transaction{
@@ -566,9 +634,9 @@ func TestReconciliationUseCase_EdgeCases(t *testing.T) {
 			Amount:   decimal.NewFromFloat(100.00),
 			Status:   models.TransactionStatusCompleted,
 		}
-		transactionRepo.Create(completedTx)
+		createBalancedTransaction(transactionRepo, completedTx)
 
-		report, err := reconciliationUC.PerformWalletReconciliation(30)
+		report, err := reconciliationUC.PerformWalletReconciliation(context.Background(), 30)
 		if err != nil {
 			t.Errorf("Expected no error, got: %v", err)
 		}
@@ -585,16 +653,16 @@ func TestReconciliationUseCase_EdgeCases(t *testing.T) {
 	})
 
 	t.Run("should handle wallet with failed transactions", func(t *testing.T) {
-		userRepo := repos.User.(*MockUserRepository)
-		walletRepo := repos.Wallet.(*MockWalletRepository)
-		transactionRepo := repos.Transaction.(*MockTransactionRepository)
+		userRepo := repos.User.(*memory.UserRepository)
+		walletRepo := repos.Wallet.(*memory.WalletRepository)
+		transactionRepo := repos.Transaction.(*memory.TransactionRepository)
 
 		user := &models.User{
 			ID:    31,
 			Email: "failed@example.com",
 			Name:  "Failed Transactions User",
 		}
-		userRepo.Create(user)
+		userRepo.Create(context.Background(), user)
 
 		wallet := &models.Wallet{
 			ID:       31,
@@ -604,7 +672,7 @@ func TestReconciliationUseCase_EdgeCases(t *testing.T) {
 			Status:   models.WalletStatusActive,
 			Version:  0,
 		}
-		walletRepo.Create(wallet)
+		walletRepo.Create(context.Background(), wallet)
 
 		// Create failed transaction (should not be included in calculation)
 		failedTx := &models.Transaction{
@@ -612,7 +680,7 @@ func TestReconciliationUseCase_EdgeCases(t *testing.T) {
 			Amount:   decimal.NewFromFloat(100.00),
 			Status:   models.TransactionStatusFailed,
 		}
-		transactionRepo.Create(failedTx)
+		createBalancedTransaction(transactionRepo, failedTx)
 
 		// Create successful transaction
 		successTx := &models.Transaction{
@@ -620,9 +688,9 @@ func TestReconciliationUseCase_EdgeCases(t *testing.T) {
 			Amount:   decimal.NewFromFloat(150.00),
 			Status:   models.TransactionStatusCompleted,
 		}
-		transactionRepo.Create(successTx)
+		createBalancedTransaction(transactionRepo, successTx)
 
-		report, err := reconciliationUC.PerformWalletReconciliation(31)
+		report, err := reconciliationUC.PerformWalletReconciliation(context.Background(), 31)
 		if err != nil {
 			t.Errorf("Expected no error, got: %v", err)
 		}
@@ -639,16 +707,16 @@ func TestReconciliationUseCase_EdgeCases(t *testing.T) {
 	})
 
 	t.Run("should handle mixed debit and credit transactions", func(t *testing.T) {
-		userRepo := repos.User.(*MockUserRepository)
-		walletRepo := repos.Wallet.(*MockWalletRepository)
-		transactionRepo := repos.Transaction.(*MockTransactionRepository)
+		userRepo := repos.User.(*memory.UserRepository)
+		walletRepo := repos.Wallet.(*memory.WalletRepository)
+		transactionRepo := repos.Transaction.(*memory.TransactionRepository)
 
 		user := &models.User{
 			ID:    32,
 			Email: "mixed@example.com",
 			Name:  "Mixed Transactions User",
 		}
-		userRepo.Create(user)
+		userRepo.Create(context.Background(), user)
 
 		wallet := &models.Wallet{
 			ID:       32,
@@ -658,7 +726,7 @@ func TestReconciliationUseCase_EdgeCases(t *testing.T) {
 			Status:   models.WalletStatusActive,
 			Version:  0,
 		}
-		walletRepo.Create(wallet)
+		walletRepo.Create(context.Background(), wallet)
 
 		// Create credit transaction (+200)
 		creditTx := &models.Transaction{
@@ -667,18 +735,18 @@ func TestReconciliationUseCase_EdgeCases(t *testing.T) {
 			TransactionType: models.TransactionTypeCredit,
 			Status:          models.TransactionStatusCompleted,
 		}
-		transactionRepo.Create(creditTx)
+		createBalancedTransaction(transactionRepo, creditTx)
 
 		// Create debit transaction (-150)
 		debitTx := &models.Transaction{
 			WalletID:        32,
-			Amount:          decimal.NewFromFloat(-150.00),
+			Amount:          decimal.NewFromFloat(150.00),
 			TransactionType: models.TransactionTypeDebit,
 			Status:          models.TransactionStatusCompleted,
 		}
-		transactionRepo.Create(debitTx)
+		createBalancedTransaction(transactionRepo, debitTx)
 
-		report, err := reconciliationUC.PerformWalletReconciliation(32)
+		report, err := reconciliationUC.PerformWalletReconciliation(context.Background(), 32)
 		if err != nil {
 			t.Errorf("Expected no error, got: %v", err)
 		}
@@ -696,15 +764,15 @@ func TestReconciliationUseCase_EdgeCases(t *testing.T) {
 	})
 
 	t.Run("should handle suspended wallet", func(t *testing.T) {
-		userRepo := repos.User.(*MockUserRepository)
-		walletRepo := repos.Wallet.(*MockWalletRepository)
+		userRepo := repos.User.(*memory.UserRepository)
+		walletRepo := repos.Wallet.(*memory.WalletRepository)
 
 		user := &models.User{
 			ID:    33,
 			Email: "suspended@example.com",
 			Name:  "Suspended User",
 		}
-		userRepo.Create(user)
+		userRepo.Create(context.Background(), user)
 
 		wallet := &models.Wallet{
 			ID:       33,
@@ -714,10 +782,10 @@ func TestReconciliationUseCase_EdgeCases(t *testing.T) {
 			Status:   models.WalletStatusSuspended, // Suspended wallet
 			Version:  0,
 		}
-		walletRepo.Create(wallet)
+		walletRepo.Create(context.Background(), wallet)
 
 		// Should still perform reconciliation even for suspended wallets
-		report, err := reconciliationUC.PerformWalletReconciliation(33)
+		report, err := reconciliationUC.PerformWalletReconciliation(context.Background(), 33)
 		if err != nil {
 			t.Errorf("Expected no error, got: %v", err)
 		}
@@ -728,15 +796,15 @@ func TestReconciliationUseCase_EdgeCases(t *testing.T) {
 	})
 
 	t.Run("should handle zero balances", func(t *testing.T) {
-		userRepo := repos.User.(*MockUserRepository)
-		walletRepo := repos.Wallet.(*MockWalletRepository)
+		userRepo := repos.User.(*memory.UserRepository)
+		walletRepo := repos.Wallet.(*memory.WalletRepository)
 
 		user := &models.User{
 			ID:    34,
 			Email: "zero@example.com",
 			Name:  "Zero Balance User",
 		}
-		userRepo.Create(user)
+		userRepo.Create(context.Background(), user)
 
 		wallet := &models.Wallet{
 			ID:       34,
@@ -746,9 +814,9 @@ func TestReconciliationUseCase_EdgeCases(t *testing.T) {
 			Status:   models.WalletStatusActive,
 			Version:  0,
 		}
-		walletRepo.Create(wallet)
+		walletRepo.Create(context.Background(), wallet)
 
-		report, err := reconciliationUC.PerformWalletReconciliation(34)
+		report, err := reconciliationUC.PerformWalletReconciliation(context.Background(), 34)
 		if err != nil {
 			t.Errorf("Expected no error, got: %v", err)
 		}
@@ -777,7 +845,7 @@ func TestReconciliationUseCase_SystemAccountScenarios(t *testing.T) {
 
 	t.Run("should reconcile system account", func(t *testing.T) {
 		// System wallet should be ID 1 from setup
-		report, err := reconciliationUC.PerformWalletReconciliation(1)
+		report, err := reconciliationUC.PerformWalletReconciliation(context.Background(), 1)
 		if err != nil {
 			t.Errorf("Expected no error reconciling system account, got: %v", err)
 		}
@@ -804,16 +872,16 @@ func TestReconciliationUseCase_BoundaryConditions(t *testing.T) {
 	reconciliationUC := NewReconciliationUseCase(repos)
 
 	t.Run("should handle large decimal values", func(t *testing.T) {
-		userRepo := repos.User.(*MockUserRepository)
-		walletRepo := repos.Wallet.(*MockWalletRepository)
-		transactionRepo := repos.Transaction.(*MockTransactionRepository)
+		userRepo := repos.User.(*memory.UserRepository)
+		walletRepo := repos.Wallet.(*memory.WalletRepository)
+		transactionRepo := repos.Transaction.(*memory.TransactionRepository)
 
 		user := &models.User{
 			ID:    35,
 			Email: "large@example.com",
 			Name:  "Large Values User",
 		}
-		userRepo.Create(user)
+		userRepo.Create(context.Background(), user)
 
 		// Large balance
 		largeBalance := decimal.NewFromFloat(999999999.99)
@@ -825,7 +893,7 @@ func TestReconciliationUseCase_BoundaryConditions(t *testing.T) {
 			Status:   models.WalletStatusActive,
 			Version:  0,
 		}
-		walletRepo.Create(wallet)
+		walletRepo.Create(context.Background(), wallet)
 
 		// Matching large transaction
 		largeTx := &models.Transaction{
@@ -833,9 +901,9 @@ func TestReconciliationUseCase_BoundaryConditions(t *testing.T) {
 			Amount:   largeBalance,
 			Status:   models.TransactionStatusCompleted,
 		}
-		transactionRepo.Create(largeTx)
+		createBalancedTransaction(transactionRepo, largeTx)
 
-		report, err := reconciliationUC.PerformWalletReconciliation(35)
+		report, err := reconciliationUC.PerformWalletReconciliation(context.Background(), 35)
 		if err != nil {
 			t.Errorf("Expected no error with large values, got: %v", err)
 		}
@@ -851,16 +919,16 @@ func TestReconciliationUseCase_BoundaryConditions(t *testing.T) {
 	})
 
 	t.Run("should handle very small decimal differences", func(t *testing.T) {
-		userRepo := repos.User.(*MockUserRepository)
-		walletRepo := repos.Wallet.(*MockWalletRepository)
-		transactionRepo := repos.Transaction.(*MockTransactionRepository)
+		userRepo := repos.User.(*memory.UserRepository)
+		walletRepo := repos.Wallet.(*memory.WalletRepository)
+		transactionRepo := repos.Transaction.(*memory.TransactionRepository)
 
 		user := &models.User{
 			ID:    36,
 			Email: "small@example.com",
 			Name:  "Small Difference User",
 		}
-		userRepo.Create(user)
+		userRepo.Create(context.Background(), user)
 
 		wallet := &models.Wallet{
 			ID:       36,
@@ -870,7 +938,7 @@ func TestReconciliationUseCase_BoundaryConditions(t *testing.T) {
 			Status:   models.WalletStatusActive,
 			Version:  0,
 		}
-		walletRepo.Create(wallet)
+		walletRepo.Create(context.Background(), wallet)
 
 		// Transaction with tiny difference (100.01 vs 100.00)
 		smallDiffTx := &models.Transaction{
@@ -878,9 +946,9 @@ func TestReconciliationUseCase_BoundaryConditions(t *testing.T) {
 			Amount:   decimal.NewFromFloat(100.01),
 			Status:   models.TransactionStatusCompleted,
 		}
-		transactionRepo.Create(smallDiffTx)
+		createBalancedTransaction(transactionRepo, smallDiffTx)
 
-		report, err := reconciliationUC.PerformWalletReconciliation(36)
+		report, err := reconciliationUC.PerformWalletReconciliation(context.Background(), 36)
 		if err != nil {
 			t.Errorf("Expected no error with small differences, got: %v", err)
 		}
@@ -897,16 +965,16 @@ func TestReconciliationUseCase_BoundaryConditions(t *testing.T) {
 	})
 
 	t.Run("should handle negative balances", func(t *testing.T) {
-		userRepo := repos.User.(*MockUserRepository)
-		walletRepo := repos.Wallet.(*MockWalletRepository)
-		transactionRepo := repos.Transaction.(*MockTransactionRepository)
+		userRepo := repos.User.(*memory.UserRepository)
+		walletRepo := repos.Wallet.(*memory.WalletRepository)
+		transactionRepo := repos.Transaction.(*memory.TransactionRepository)
 
 		user := &models.User{
 			ID:    37,
 			Email: "negative@example.com",
 			Name:  "Negative Balance User",
 		}
-		userRepo.Create(user)
+		userRepo.Create(context.Background(), user)
 
 		// Negative stored balance
 		negativeBalance := decimal.NewFromFloat(-50.00)
@@ -918,7 +986,7 @@ func TestReconciliationUseCase_BoundaryConditions(t *testing.T) {
 			Status:   models.WalletStatusActive,
 			Version:  0,
 		}
-		walletRepo.Create(wallet)
+		walletRepo.Create(context.Background(), wallet)
 
 		// Matching negative transaction
 		negativeTx := &models.Transaction{
@@ -926,9 +994,9 @@ func TestReconciliationUseCase_BoundaryConditions(t *testing.T) {
 			Amount:   negativeBalance,
 			Status:   models.TransactionStatusCompleted,
 		}
-		transactionRepo.Create(negativeTx)
+		createBalancedTransaction(transactionRepo, negativeTx)
 
-		report, err := reconciliationUC.PerformWalletReconciliation(37)
+		report, err := reconciliationUC.PerformWalletReconciliation(context.Background(), 37)
 		if err != nil {
 			t.Errorf("Expected no error with negative balances, got: %v", err)
 		}
@@ -984,7 +1052,7 @@ func TestReconciliationUseCase_ErrorHandling(t *testing.T) {
 
 	t.Run("should handle repository errors gracefully", func(t *testing.T) {
 		// Test with invalid wallet ID that doesn't exist
-		_, err := reconciliationUC.PerformWalletReconciliation(99999)
+		_, err := reconciliationUC.PerformWalletReconciliation(context.Background(), 99999)
 		if err == nil {
 			t.Error("Expected error for non-existent wallet")
 		}
@@ -992,7 +1060,7 @@ func TestReconciliationUseCase_ErrorHandling(t *testing.T) {
 
 	t.Run("should handle bulk reconciliation with some failures", func(t *testing.T) {
 		// Bulk reconciliation should continue even if some wallets fail
-		reports, err := reconciliationUC.PerformReconciliation()
+		reports, err := reconciliationUC.PerformReconciliation(context.Background())
 
 		// Should not return error even if some individual reconciliations fail
 		if err != nil {
@@ -1007,13 +1075,13 @@ func TestReconciliationUseCase_ErrorHandling(t *testing.T) {
 
 	t.Run("should validate pagination parameters", func(t *testing.T) {
 		// Test with valid pagination
-		_, err := reconciliationUC.GetReconciliationReports(1, 10)
+		_, err := reconciliationUC.GetReconciliationReports(context.Background(), 1, 10)
 		if err != nil {
 			t.Errorf("Expected no error with valid pagination, got: %v", err)
 		}
 
 		// Test with edge case pagination (page 0 should be handled by repository)
-		_, err = reconciliationUC.GetReconciliationReports(0, 10)
+		_, err = reconciliationUC.GetReconciliationReports(context.Background(), 0, 10)
 		if err != nil {
 			t.Errorf("Expected repository to handle page 0, got: %v", err)
 		}
@@ -1026,9 +1094,9 @@ func TestReconciliationUseCase_Performance(t *testing.T) {
 	reconciliationUC := NewReconciliationUseCase(repos)
 
 	t.Run("should handle multiple wallets efficiently", func(t *testing.T) {
-		userRepo := repos.User.(*MockUserRepository)
-		walletRepo := repos.Wallet.(*MockWalletRepository)
-		transactionRepo := repos.Transaction.(*MockTransactionRepository)
+		userRepo := repos.User.(*memory.UserRepository)
+		walletRepo := repos.Wallet.(*memory.WalletRepository)
+		transactionRepo := repos.Transaction.(*memory.TransactionRepository)
 
 		// Create multiple test wallets
 		numWallets := 10
@@ -1038,7 +1106,7 @@ func TestReconciliationUseCase_Performance(t *testing.T) {
 				Email: fmt.Sprintf("perf%d@example.com", i),
 				Name:  fmt.Sprintf("Performance User %d", i),
 			}
-			userRepo.Create(user)
+			userRepo.Create(context.Background(), user)
 
 			wallet := &models.Wallet{
 				ID:       uint(i),
@@ -1048,7 +1116,7 @@ func TestReconciliationUseCase_Performance(t *testing.T) {
 				Status:   models.WalletStatusActive,
 				Version:  0,
 			}
-			walletRepo.Create(wallet)
+			walletRepo.Create(context.Background(), wallet)
 
 			// Create matching transaction
 			tx := &models.Transaction{
@@ -1056,11 +1124,11 @@ func TestReconciliationUseCase_Performance(t *testing.T) {
 				Amount:   decimal.NewFromFloat(float64(i * 100)),
 				Status:   models.TransactionStatusCompleted,
 			}
-			transactionRepo.Create(tx)
+			createBalancedTransaction(transactionRepo, tx)
 		}
 
 		// Perform bulk reconciliation
-		reports, err := reconciliationUC.PerformReconciliation()
+		reports, err := reconciliationUC.PerformReconciliation(context.Background())
 		if err != nil {
 			t.Errorf("Expected no error with multiple wallets, got: %v", err)
 		}
@@ -1085,16 +1153,16 @@ func TestReconciliationUseCase_Performance(t *testing.T) {
 	})
 
 	t.Run("should handle wallets with many transactions", func(t *testing.T) {
-		userRepo := repos.User.(*MockUserRepository)
-		walletRepo := repos.Wallet.(*MockWalletRepository)
-		transactionRepo := repos.Transaction.(*MockTransactionRepository)
+		userRepo := repos.User.(*memory.UserRepository)
+		walletRepo := repos.Wallet.(*memory.WalletRepository)
+		transactionRepo := repos.Transaction.(*memory.TransactionRepository)
 
 		user := &models.User{
 			ID:    70,
 			Email: "manytx@example.com",
 			Name:  "Many Transactions User",
 		}
-		userRepo.Create(user)
+		userRepo.Create(context.Background(), user)
 
 		wallet := &models.Wallet{
 			ID:       70,
@@ -1104,7 +1172,7 @@ func TestReconciliationUseCase_Performance(t *testing.T) {
 			Status:   models.WalletStatusActive,
 			Version:  0,
 		}
-		walletRepo.Create(wallet)
+		walletRepo.Create(context.Background(), wallet)
 
 		// Create many transactions that sum to 1000
 		totalAmount := decimal.Zero
@@ -1117,11 +1185,11 @@ func TestReconciliationUseCase_Performance(t *testing.T) {
 				Amount:   amountPerTx,
 				Status:   models.TransactionStatusCompleted,
 			}
-			transactionRepo.Create(tx)
+			createBalancedTransaction(transactionRepo, tx)
 			totalAmount = totalAmount.Add(amountPerTx)
 		}
 
-		report, err := reconciliationUC.PerformWalletReconciliation(70)
+		report, err := reconciliationUC.PerformWalletReconciliation(context.Background(), 70)
 		if err != nil {
 			t.Errorf("Expected no error with many transactions, got: %v", err)
 		}
@@ -1143,15 +1211,15 @@ func TestReconciliationUseCase_Concurrency(t *testing.T) {
 	reconciliationUC := NewReconciliationUseCase(repos)
 
 	t.Run("should handle sequential reconciliation requests", func(t *testing.T) {
-		userRepo := repos.User.(*MockUserRepository)
-		walletRepo := repos.Wallet.(*MockWalletRepository)
+		userRepo := repos.User.(*memory.UserRepository)
+		walletRepo := repos.Wallet.(*memory.WalletRepository)
 
 		user := &models.User{
 			ID:    80,
 			Email: "concurrent@example.com",
 			Name:  "Concurrent User",
 		}
-		userRepo.Create(user)
+		userRepo.Create(context.Background(), user)
 
 		wallet := &models.Wallet{
 			ID:       80,
@@ -1161,12 +1229,12 @@ func TestReconciliationUseCase_Concurrency(t *testing.T) {
 			Status:   models.WalletStatusActive,
 			Version:  0,
 		}
-		walletRepo.Create(wallet)
+		walletRepo.Create(context.Background(), wallet)
 
 		// Perform multiple reconciliations of the same wallet
 		numRuns := 5
 		for i := 0; i < numRuns; i++ {
-			report, err := reconciliationUC.PerformWalletReconciliation(80)
+			report, err := reconciliationUC.PerformWalletReconciliation(context.Background(), 80)
 			if err != nil {
 				t.Errorf("Run %d: Expected no error, got: %v", i+1, err)
 			}
@@ -1177,16 +1245,16 @@ func TestReconciliationUseCase_Concurrency(t *testing.T) {
 	})
 
 	t.Run("should maintain data consistency across operations", func(t *testing.T) {
-		userRepo := repos.User.(*MockUserRepository)
-		walletRepo := repos.Wallet.(*MockWalletRepository)
-		transactionRepo := repos.Transaction.(*MockTransactionRepository)
+		userRepo := repos.User.(*memory.UserRepository)
+		walletRepo := repos.Wallet.(*memory.WalletRepository)
+		transactionRepo := repos.Transaction.(*memory.TransactionRepository)
 
 		user := &models.User{
 			ID:    81,
 			Email: "consistency@example.com",
 			Name:  "Consistency User",
 		}
-		userRepo.Create(user)
+		userRepo.Create(context.Background(), user)
 
 		wallet := &models.Wallet{
 			ID:       81,
@@ -1196,10 +1264,10 @@ func TestReconciliationUseCase_Concurrency(t *testing.T) {
 			Status:   models.WalletStatusActive,
 			Version:  0,
 		}
-		walletRepo.Create(wallet)
+		walletRepo.Create(context.Background(), wallet)
 
 		// First reconciliation (no transactions)
-		report1, err := reconciliationUC.PerformWalletReconciliation(81)
+		report1, err := reconciliationUC.PerformWalletReconciliation(context.Background(), 81)
 		if err != nil {
 			t.Errorf("First reconciliation failed: %v", err)
 		}
@@ -1210,10 +1278,10 @@ func TestReconciliationUseCase_Concurrency(t *testing.T) {
 			Amount:   decimal.NewFromFloat(300.00),
 			Status:   models.TransactionStatusCompleted,
 		}
-		transactionRepo.Create(tx)
+		createBalancedTransaction(transactionRepo, tx)
 
 		// Second reconciliation (with transaction)
-		report2, err := reconciliationUC.PerformWalletReconciliation(81)
+		report2, err := reconciliationUC.PerformWalletReconciliation(context.Background(), 81)
 		if err != nil {
 			t.Errorf("Second reconciliation failed: %v", err)
 		}
@@ -1237,16 +1305,16 @@ func TestReconciliationUseCase_AdvancedScenarios(t *testing.T) {
 	reconciliationUC := NewReconciliationUseCase(repos)
 
 	t.Run("should detect complex balance mismatches", func(t *testing.T) {
-		userRepo := repos.User.(*MockUserRepository)
-		walletRepo := repos.Wallet.(*MockWalletRepository)
-		transactionRepo := repos.Transaction.(*MockTransactionRepository)
+		userRepo := repos.User.(*memory.UserRepository)
+		walletRepo := repos.Wallet.(*memory.WalletRepository)
+		transactionRepo := repos.Transaction.(*memory.TransactionRepository)
 
 		user := &models.User{
 			ID:    90,
 			Email: "complex@example.com",
 			Name:  "Complex Mismatch User",
 		}
-		userRepo.Create(user)
+		userRepo.Create(context.Background(), user)
 
 		wallet := &models.Wallet{
 			ID:       90,
@@ -1256,7 +1324,7 @@ func TestReconciliationUseCase_AdvancedScenarios(t *testing.T) {
 			Status:   models.WalletStatusActive,
 			Version:  0,
 		}
-		walletRepo.Create(wallet)
+		walletRepo.Create(context.Background(), wallet)
 
 		// Create transactions that don't match stored balance
 		transactions := []decimal.Decimal{
@@ -1273,10 +1341,10 @@ func TestReconciliationUseCase_AdvancedScenarios(t *testing.T) {
 				Amount:   amount,
 				Status:   models.TransactionStatusCompleted,
 			}
-			transactionRepo.Create(tx)
+			createBalancedTransaction(transactionRepo, tx)
 		}
 
-		report, err := reconciliationUC.PerformWalletReconciliation(90)
+		report, err := reconciliationUC.PerformWalletReconciliation(context.Background(), 90)
 		if err != nil {
 			t.Errorf("Expected no error, got: %v", err)
 		}
@@ -1304,16 +1372,16 @@ func TestReconciliationUseCase_AdvancedScenarios(t *testing.T) {
 	})
 
 	t.Run("should handle precision edge cases", func(t *testing.T) {
-		userRepo := repos.User.(*MockUserRepository)
-		walletRepo := repos.Wallet.(*MockWalletRepository)
-		transactionRepo := repos.Transaction.(*MockTransactionRepository)
+		userRepo := repos.User.(*memory.UserRepository)
+		walletRepo := repos.Wallet.(*memory.WalletRepository)
+		transactionRepo := repos.Transaction.(*memory.TransactionRepository)
 
 		user := &models.User{
 			ID:    91,
 			Email: "precision@example.com",
 			Name:  "Precision User",
 		}
-		userRepo.Create(user)
+		userRepo.Create(context.Background(), user)
 
 		// Use precise decimal values
 		preciseBalance := decimal.NewFromFloat(123.456789)
@@ -1325,7 +1393,7 @@ func TestReconciliationUseCase_AdvancedScenarios(t *testing.T) {
 			Status:   models.WalletStatusActive,
 			Version:  0,
 		}
-		walletRepo.Create(wallet)
+		walletRepo.Create(context.Background(), wallet)
 
 		// Create matching precise transaction
 		preciseTx := &models.Transaction{
@@ -1333,9 +1401,9 @@ func TestReconciliationUseCase_AdvancedScenarios(t *testing.T) {
 			Amount:   preciseBalance,
 			Status:   models.TransactionStatusCompleted,
 		}
-		transactionRepo.Create(preciseTx)
+		createBalancedTransaction(transactionRepo, preciseTx)
 
-		report, err := reconciliationUC.PerformWalletReconciliation(91)
+		report, err := reconciliationUC.PerformWalletReconciliation(context.Background(), 91)
 		if err != nil {
 			t.Errorf("Expected no error with precise values, got: %v", err)
 		}
@@ -1351,16 +1419,16 @@ func TestReconciliationUseCase_AdvancedScenarios(t *testing.T) {
 	})
 
 	t.Run("should handle currency-specific scenarios", func(t *testing.T) {
-		userRepo := repos.User.(*MockUserRepository)
-		walletRepo := repos.Wallet.(*MockWalletRepository)
-		transactionRepo := repos.Transaction.(*MockTransactionRepository)
+		userRepo := repos.User.(*memory.UserRepository)
+		walletRepo := repos.Wallet.(*memory.WalletRepository)
+		transactionRepo := repos.Transaction.(*memory.TransactionRepository)
 
 		user := &models.User{
 			ID:    92,
 			Email: "currency@example.com",
 			Name:  "Currency User",
 		}
-		userRepo.Create(user)
+		userRepo.Create(context.Background(), user)
 
 		// Different currency wallet
 		wallet := &models.Wallet{
@@ -1371,16 +1439,16 @@ func TestReconciliationUseCase_AdvancedScenarios(t *testing.T) {
 			Status:   models.WalletStatusActive,
 			Version:  0,
 		}
-		walletRepo.Create(wallet)
+		walletRepo.Create(context.Background(), wallet)
 
 		tx := &models.Transaction{
 			WalletID: 92,
 			Amount:   decimal.NewFromFloat(100.00),
 			Status:   models.TransactionStatusCompleted,
 		}
-		transactionRepo.Create(tx)
+		createBalancedTransaction(transactionRepo, tx)
 
-		report, err := reconciliationUC.PerformWalletReconciliation(92)
+		report, err := reconciliationUC.PerformWalletReconciliation(context.Background(), 92)
 		if err != nil {
 			t.Errorf("Expected no error with different currency, got: %v", err)
 		}
@@ -1393,3 +1461,509 @@ func TestReconciliationUseCase_AdvancedScenarios(t *testing.T) {
 		}
 	})
 }
+
+func TestReconciliationUseCase_SummarizeDay(t *testing.T) {
+	repos := setupReconciliationTestEnvironment()
+	summaryRepo := memory.NewReconciliationSummaryRepository()
+	repos.ReconciliationSummary = summaryRepo
+	reconciliationRepo := repos.Reconciliation.(*memory.ReconciliationRepository)
+	reconciliationUC := NewReconciliationUseCase(repos)
+
+	day := time.Date(2024, 3, 10, 0, 0, 0, 0, time.UTC)
+	reports := []models.ReconciliationReport{
+		{WalletID: 1, CreatedAt: day.Add(time.Hour), Status: models.ReconciliationStatusMatch},
+		{WalletID: 2, CreatedAt: day.Add(2 * time.Hour), Status: models.ReconciliationStatusMismatch, Difference: decimal.NewFromInt(-30)},
+		{WalletID: 2, CreatedAt: day.Add(3 * time.Hour), Status: models.ReconciliationStatusMismatch, Difference: decimal.NewFromInt(10)},
+		{WalletID: 3, CreatedAt: day.Add(4 * time.Hour), Status: models.ReconciliationStatusMismatch, Difference: decimal.NewFromInt(20)},
+		// Reports of the previous and next days are left out
+		{WalletID: 4, CreatedAt: day.Add(-time.Minute), Status: models.ReconciliationStatusMismatch, Difference: decimal.NewFromInt(500)},
+		{WalletID: 4, CreatedAt: day.AddDate(0, 0, 1), Status: models.ReconciliationStatusMismatch, Difference: decimal.NewFromInt(500)},
+	}
+	for i := range reports {
+		reconciliationRepo.Create(context.Background(), &reports[i])
+	}
+
+	summary, err := reconciliationUC.SummarizeDay(context.Background(), day.Add(15*time.Hour))
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if summary.Checks != 4 || summary.WalletsChecked != 3 {
+		t.Errorf("Expected 4 checks of 3 wallets, got %d checks of %d wallets", summary.Checks, summary.WalletsChecked)
+	}
+	if summary.Matches != 1 || summary.Mismatches != 3 || summary.DoubleEntryErrors != 0 {
+		t.Errorf("Unexpected counts: %d matches, %d mismatches, %d double-entry errors",
+			summary.Matches, summary.Mismatches, summary.DoubleEntryErrors)
+	}
+
+	differences, err := summary.Differences()
+	if err != nil {
+		t.Fatalf("Expected no error decoding differences, got: %v", err)
+	}
+	if len(differences) != 2 {
+		t.Fatalf("Expected one difference per wallet, got %d", len(differences))
+	}
+	if differences[0].WalletID != 2 || !differences[0].Difference.Equal(decimal.NewFromInt(-30)) {
+		t.Errorf("Expected wallet 2 with -30 first, got wallet %d with %s", differences[0].WalletID, differences[0].Difference)
+	}
+	if differences[1].WalletID != 3 {
+		t.Errorf("Expected wallet 3 second, got wallet %d", differences[1].WalletID)
+	}
+
+	if err := reconciliationUC.MarkSummaryDelivered(context.Background(), summary.ID); err != nil {
+		t.Fatalf("Expected no error marking delivered, got: %v", err)
+	}
+	again, err := reconciliationUC.SummarizeDay(context.Background(), day)
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if again.ID != summary.ID || again.DeliveredAt == nil {
+		t.Error("Expected the stored, delivered summary to be returned for the same day")
+	}
+}
+
+func TestReconciliationUseCase_DoubleEntryErrors(t *testing.T) {
+	newWallet := func(repos *repositories.Repositories, id uint, balance float64) {
+		repos.Wallet.(*memory.WalletRepository).Create(context.Background(), &models.Wallet{
+			ID:       id,
+			UserID:   id,
+			Balance:  decimal.NewFromFloat(balance),
+			Currency: "USD",
+			Status:   models.WalletStatusActive,
+		})
+	}
+
+	t.Run("should flag a completed transaction without counterpart", func(t *testing.T) {
+		repos := setupReconciliationTestEnvironment()
+		transactionRepo := repos.Transaction.(*memory.TransactionRepository)
+		newWallet(repos, 40, 100)
+		transactionRepo.Create(context.Background(), &models.Transaction{
+			WalletID:        40,
+			TransactionType: models.TransactionTypeCredit,
+			Amount:          decimal.NewFromFloat(100),
+			Status:          models.TransactionStatusCompleted,
+		})
+
+		report, err := NewReconciliationUseCase(repos).PerformWalletReconciliation(context.Background(), 40)
+		if err != nil {
+			t.Fatalf("Expected no error, got: %v", err)
+		}
+		if report.Status != models.ReconciliationStatusDoubleEntryError {
+			t.Errorf("Expected status DOUBLE_ENTRY_ERROR, got: %v", report.Status)
+		}
+		if !containsString(report.Notes, "has no linked counterpart") || !containsString(report.Notes, "Balance matches") {
+			t.Errorf("Expected notes to describe the missing counterpart and the balance, got: %s", report.Notes)
+		}
+	})
+
+	t.Run("should flag broken counterparts", func(t *testing.T) {
+		cases := []struct {
+			name     string
+			mutate   func(counterpart *models.Transaction)
+			expected string
+		}{
+			{"different amount", func(c *models.Transaction) { c.Amount = decimal.NewFromFloat(90) }, "is linked to transaction"},
+			{"same type", func(c *models.Transaction) { c.TransactionType = models.TransactionTypeCredit }, "are both CREDIT"},
+			{"not completed", func(c *models.Transaction) { c.Status = models.TransactionStatusPending }, "is PENDING"},
+		}
+		for _, tc := range cases {
+			t.Run(tc.name, func(t *testing.T) {
+				repos := setupReconciliationTestEnvironment()
+				transactionRepo := repos.Transaction.(*memory.TransactionRepository)
+				newWallet(repos, 41, 100)
+
+				transaction := &models.Transaction{
+					WalletID: 41,
+					Amount:   decimal.NewFromFloat(100),
+					Status:   models.TransactionStatusCompleted,
+				}
+				createBalancedTransaction(transactionRepo, transaction)
+				counterpart, _ := transactionRepo.GetByID(context.Background(), *transaction.RelatedTransactionID)
+				tc.mutate(counterpart)
+				transactionRepo.Update(context.Background(), counterpart)
+
+				report, err := NewReconciliationUseCase(repos).PerformWalletReconciliation(context.Background(), 41)
+				if err != nil {
+					t.Fatalf("Expected no error, got: %v", err)
+				}
+				if report.Status != models.ReconciliationStatusDoubleEntryError {
+					t.Errorf("Expected status DOUBLE_ENTRY_ERROR, got: %v", report.Status)
+				}
+				if !containsString(report.Notes, tc.expected) {
+					t.Errorf("Expected notes to contain %q, got: %s", tc.expected, report.Notes)
+				}
+			})
+		}
+	})
+
+	t.Run("should pair fee legs with each other", func(t *testing.T) {
+		repos := setupReconciliationTestEnvironment()
+		transactionRepo := repos.Transaction.(*memory.TransactionRepository)
+		newWallet(repos, 42, 98)
+
+		charged := &models.Transaction{
+			WalletID: 42,
+			Amount:   decimal.NewFromFloat(100),
+			Status:   models.TransactionStatusCompleted,
+		}
+		createBalancedTransaction(transactionRepo, charged)
+		feeDebit := &models.Transaction{
+			WalletID:             42,
+			TransactionType:      models.TransactionTypeDebit,
+			TransactionPurpose:   models.TransactionPurposeFee,
+			Amount:               decimal.NewFromFloat(2),
+			Status:               models.TransactionStatusCompleted,
+			RelatedTransactionID: &charged.ID,
+		}
+		transactionRepo.Create(context.Background(), feeDebit)
+		feeCredit := &models.Transaction{
+			WalletID:             counterpartyWalletID,
+			TransactionType:      models.TransactionTypeCredit,
+			TransactionPurpose:   models.TransactionPurposeFee,
+			Amount:               decimal.NewFromFloat(2),
+			Status:               models.TransactionStatusCompleted,
+			RelatedTransactionID: &charged.ID,
+		}
+		transactionRepo.Create(context.Background(), feeCredit)
+
+		reconciliationUC := NewReconciliationUseCase(repos)
+		report, err := reconciliationUC.PerformWalletReconciliation(context.Background(), 42)
+		if err != nil {
+			t.Fatalf("Expected no error, got: %v", err)
+		}
+		if report.Status != models.ReconciliationStatusMatch {
+			t.Errorf("Expected status MATCH, got: %v (%s)", report.Status, report.Notes)
+		}
+
+		feeCredit.Amount = decimal.NewFromFloat(1)
+		transactionRepo.Update(context.Background(), feeCredit)
+		report, err = reconciliationUC.PerformWalletReconciliation(context.Background(), 42)
+		if err != nil {
+			t.Fatalf("Expected no error, got: %v", err)
+		}
+		if report.Status != models.ReconciliationStatusDoubleEntryError {
+			t.Errorf("Expected status DOUBLE_ENTRY_ERROR for unbalanced fee legs, got: %v", report.Status)
+		}
+	})
+
+	t.Run("should balance the legs of a journal entry together", func(t *testing.T) {
+		repos := setupReconciliationTestEnvironment()
+		transactionRepo := repos.Transaction.(*memory.TransactionRepository)
+		entries := memory.NewJournalEntryRepository(transactionRepo)
+		repos.JournalEntry = entries
+		newWallet(repos, 43, 100)
+
+		entry := &models.JournalEntry{Reference: "JE-1"}
+		entries.Create(context.Background(), entry)
+		journalLeg := func(walletID uint, transactionType models.TransactionType, amount float64) *models.Transaction {
+			leg := &models.Transaction{
+				WalletID:        walletID,
+				TransactionType: transactionType,
+				Amount:          decimal.NewFromFloat(amount),
+				Status:          models.TransactionStatusCompleted,
+				JournalEntryID:  &entry.ID,
+			}
+			transactionRepo.Create(context.Background(), leg)
+			return leg
+		}
+		journalLeg(43, models.TransactionTypeCredit, 100)
+		journalLeg(counterpartyWalletID, models.TransactionTypeDebit, 60)
+		lastLeg := journalLeg(counterpartyWalletID, models.TransactionTypeDebit, 40)
+
+		reconciliationUC := NewReconciliationUseCase(repos)
+		report, err := reconciliationUC.PerformWalletReconciliation(context.Background(), 43)
+		if err != nil {
+			t.Fatalf("Expected no error, got: %v", err)
+		}
+		if report.Status != models.ReconciliationStatusMatch {
+			t.Errorf("Expected status MATCH, got: %v (%s)", report.Status, report.Notes)
+		}
+
+		lastLeg.Amount = decimal.NewFromFloat(30)
+		transactionRepo.Update(context.Background(), lastLeg)
+		report, err = reconciliationUC.PerformWalletReconciliation(context.Background(), 43)
+		if err != nil {
+			t.Fatalf("Expected no error, got: %v", err)
+		}
+		if report.Status != models.ReconciliationStatusDoubleEntryError || !containsString(report.Notes, "is unbalanced") {
+			t.Errorf("Expected an unbalanced journal entry, got: %v (%s)", report.Status, report.Notes)
+		}
+	})
+}
+
+func TestReconciliationUseCase_CheckpointBalances(t *testing.T) {
+	repos := setupReconciliationTestEnvironment()
+	transactionRepo := repos.Transaction.(*memory.TransactionRepository)
+	reconciliationUC := NewReconciliationUseCase(repos)
+	repos.Wallet.Create(context.Background(), &models.Wallet{ID: 50, UserID: 50, Balance: decimal.NewFromInt(350), Currency: "USD", Status: models.WalletStatusActive})
+
+	if _, err := reconciliationUC.CheckpointBalances(context.Background(), time.Now()); !errors.Is(err, apperrors.ErrValidation) {
+		t.Errorf("Expected a cutoff inside the minimum lag to be rejected, got: %v", err)
+	}
+
+	old := time.Now().AddDate(0, 0, -3)
+	createBalancedTransaction(transactionRepo, &models.Transaction{
+		WalletID: 50, TransactionType: models.TransactionTypeCredit, Amount: decimal.NewFromInt(500), Status: models.TransactionStatusCompleted, CreatedAt: old,
+	})
+	createBalancedTransaction(transactionRepo, &models.Transaction{
+		WalletID: 50, TransactionType: models.TransactionTypeDebit, Amount: decimal.NewFromInt(200), Status: models.TransactionStatusCompleted, CreatedAt: old,
+	})
+	createBalancedTransaction(transactionRepo, &models.Transaction{
+		WalletID: 50, TransactionType: models.TransactionTypeCredit, Amount: decimal.NewFromInt(50), Status: models.TransactionStatusCompleted, CreatedAt: time.Now(),
+	})
+
+	advanced, err := reconciliationUC.CheckpointBalances(context.Background(), time.Now().Add(-24*time.Hour))
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if advanced != 1 {
+		t.Errorf("Expected 1 checkpoint to move, got %d", advanced)
+	}
+	checkpoint, err := repos.BalanceCheckpoint.GetByWalletID(context.Background(), 50)
+	if err != nil {
+		t.Fatalf("Expected a checkpoint for wallet 50, got: %v", err)
+	}
+	if checkpoint.ThroughTransactionID != 3 || !checkpoint.Balance.Equal(decimal.NewFromInt(300)) {
+		t.Errorf("Expected a checkpoint of 300 through transaction 3, got %v through %d", checkpoint.Balance, checkpoint.ThroughTransactionID)
+	}
+
+	report, err := reconciliationUC.PerformWalletReconciliation(context.Background(), 50)
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if report.Status != models.ReconciliationStatusMatch || !report.CalculatedBalance.Equal(decimal.NewFromInt(350)) {
+		t.Errorf("Expected the checkpoint and the later credit to match 350, got: %v (%s)", report.CalculatedBalance, report.Notes)
+	}
+}
+
+func TestReconciliationUseCase_RecordsRunMetrics(t *testing.T) {
+	repos := setupReconciliationTestEnvironment()
+	repos.Wallet.Create(context.Background(), &models.Wallet{ID: 50, UserID: 50, Currency: "USD", Status: models.WalletStatusActive})
+	registry := metrics.NewRegistry()
+	reconciliationUC := NewReconciliationUseCase(repos, WithReconciliationMetrics(NewReconciliationMetrics(registry, repos)))
+
+	if _, err := reconciliationUC.PerformWalletReconciliation(context.Background(), 50); err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if _, err := reconciliationUC.PerformWalletReconciliation(context.Background(), 999); err == nil {
+		t.Fatal("Expected an unknown wallet to fail")
+	}
+
+	var out strings.Builder
+	registry.Write(&out)
+	scrape := out.String()
+	if !strings.Contains(scrape, `wallet_reconciliation_run_duration_seconds_count{scope="wallet"} 2`) {
+		t.Errorf("Expected both runs to be timed, got:\n%s", scrape)
+	}
+	if !strings.Contains(scrape, `wallet_reconciliation_last_success_timestamp_seconds{scope="wallet"}`) {
+		t.Errorf("Expected the successful run to be recorded, got:\n%s", scrape)
+	}
+	if strings.Contains(scrape, `last_success_timestamp_seconds{scope="all"}`) {
+		t.Errorf("Expected no full run to be recorded, got:\n%s", scrape)
+	}
+}
+
+func TestReconciliationUseCase_Run(t *testing.T) {
+	repos := setupReconciliationTestEnvironment()
+	repos.ReconciliationRun = memory.NewReconciliationRunRepository()
+	jobs := &recordingEnqueuer{}
+	reconciliationUC := NewReconciliationUseCase(repos, WithJobQueue(jobs))
+	ctx := context.Background()
+
+	// Together with the system wallet, two full batches and a partial one
+	for id := uint(100); id < 100+2*reconciliationRunBatchSize; id++ {
+		repos.Wallet.Create(ctx, &models.Wallet{ID: id, UserID: id, Currency: "USD", Status: models.WalletStatusActive})
+	}
+	repos.Wallet.Create(ctx, &models.Wallet{ID: 5000, UserID: 5000, Balance: decimal.NewFromInt(10), Currency: "USD", Status: models.WalletStatusActive})
+
+	run, err := reconciliationUC.StartRun(ctx, 7, models.ReconciliationScope{})
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if run.Status != models.ReconciliationRunStatusPending || len(jobs.jobTypes) != 1 || jobs.jobTypes[0] != JobReconciliationRunBatch {
+		t.Fatalf("Expected a pending run with its first batch queued, got %+v and jobs %v", run, jobs.jobTypes)
+	}
+
+	// Each batch queues the next until no wallet is left
+	for batch := 1; batch <= 3; batch++ {
+		if err := reconciliationUC.ProcessRunBatch(ctx, run.ID); err != nil {
+			t.Fatalf("Batch %d: expected no error, got: %v", batch, err)
+		}
+		if run, _ = reconciliationUC.GetRun(ctx, run.ID); batch < 3 && run.Status != models.ReconciliationRunStatusRunning {
+			t.Fatalf("Batch %d: expected the run to still be running, got %s", batch, run.Status)
+		}
+	}
+	if len(jobs.jobTypes) != 3 {
+		t.Errorf("Expected the last batch to queue nothing, got %d jobs", len(jobs.jobTypes))
+	}
+
+	total := 2*reconciliationRunBatchSize + 2
+	if run.Status != models.ReconciliationRunStatusCompleted || run.CompletedAt == nil || run.Progress() != 100 {
+		t.Fatalf("Expected the run to be completed, got %+v", run)
+	}
+	// The system wallet's balance has no transactions behind it either
+	if run.TotalWallets != total || run.ProcessedWallets != total || run.Matches != total-2 || run.Mismatches != 2 || run.FailedWallets != 0 {
+		t.Errorf("Expected %d wallets with two mismatches, got %+v", total, run)
+	}
+
+	if err := reconciliationUC.ProcessRunBatch(ctx, run.ID); err != nil || len(jobs.jobTypes) != 3 {
+		t.Errorf("Expected a completed run to be left alone, got %v", err)
+	}
+	if _, err := reconciliationUC.GetRun(ctx, 999); !errors.Is(err, apperrors.ErrReconciliationRunNotFound) {
+		t.Errorf("Expected reconciliation run not found, got: %v", err)
+	}
+}
+
+func TestReconciliationUseCase_ScopedRun(t *testing.T) {
+	repos := setupReconciliationTestEnvironment()
+	repos.ReconciliationRun = memory.NewReconciliationRunRepository()
+	reconciliationUC := NewReconciliationUseCase(repos, WithJobQueue(&recordingEnqueuer{}))
+	ctx := context.Background()
+
+	incident := time.Now().Add(-time.Hour)
+	repos.Wallet.Create(ctx, &models.Wallet{ID: 60, UserID: 60, Currency: "NGN", Status: models.WalletStatusActive})
+	repos.Wallet.Create(ctx, &models.Wallet{ID: 61, UserID: 61, Currency: "NGN", Status: models.WalletStatusSuspended})
+	repos.Wallet.Create(ctx, &models.Wallet{ID: 62, UserID: 62, Currency: "NGN", Status: models.WalletStatusActive, UpdatedAt: incident.Add(-time.Hour)})
+	repos.Wallet.Create(ctx, &models.Wallet{ID: 63, UserID: 63, Currency: "USD", Status: models.WalletStatusActive})
+
+	tests := []struct {
+		name  string
+		scope models.ReconciliationScope
+		want  []uint
+	}{
+		{name: "currency and status", scope: models.ReconciliationScope{Currency: "ngn", Status: models.WalletStatusActive}, want: []uint{60, 62}},
+		{name: "activity window", scope: models.ReconciliationScope{Currency: "NGN", ActiveFrom: &incident}, want: []uint{60, 61}},
+		{name: "wallet list", scope: models.ReconciliationScope{WalletIDs: []uint{63, 61, 63}}, want: []uint{61, 63}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var reconciled []uint
+			err := reconciliationUC.StreamReconciliation(ctx, tt.scope, func(p ReconciliationProgress) error {
+				reconciled = append(reconciled, p.WalletID)
+				return nil
+			})
+			if err != nil {
+				t.Fatalf("Expected no error, got: %v", err)
+			}
+			if fmt.Sprint(reconciled) != fmt.Sprint(tt.want) {
+				t.Errorf("Expected wallets %v, got %v", tt.want, reconciled)
+			}
+
+			run, err := reconciliationUC.StartRun(ctx, 7, tt.scope)
+			if err != nil {
+				t.Fatalf("Expected no error, got: %v", err)
+			}
+			if err := reconciliationUC.ProcessRunBatch(ctx, run.ID); err != nil {
+				t.Fatalf("Expected no error, got: %v", err)
+			}
+			run, _ = reconciliationUC.GetRun(ctx, run.ID)
+			if run.Status != models.ReconciliationRunStatusCompleted || run.TotalWallets != len(tt.want) || run.ProcessedWallets != len(tt.want) {
+				t.Errorf("Expected the run to cover %d wallets, got %+v", len(tt.want), run)
+			}
+		})
+	}
+
+	invalid := []models.ReconciliationScope{
+		{Currency: "XYZ"},
+		{Status: "FROZEN"},
+		{ActiveFrom: &incident, ActiveTo: &incident},
+		{WalletIDs: make([]uint, maxReconciliationScopeWallets+1)},
+	}
+	for _, scope := range invalid {
+		if _, err := reconciliationUC.StartRun(ctx, 7, scope); !errors.Is(err, apperrors.ErrValidation) {
+			t.Errorf("Expected a validation error for %+v, got: %v", scope, err)
+		}
+	}
+}
+
+func TestReconciliationUseCase_ResolveReport(t *testing.T) {
+	repos := setupReconciliationTestEnvironment()
+	reconciliationUC := NewReconciliationUseCase(repos)
+	ctx := context.Background()
+
+	repos.User.Create(ctx, &models.User{ID: 70, Email: "support@example.com", Role: models.UserRoleSupport})
+	repos.User.Create(ctx, &models.User{ID: 71, Email: "customer@example.com", Role: models.UserRoleUser})
+	mismatch := &models.ReconciliationReport{WalletID: 1, Difference: decimal.NewFromInt(25), Status: models.ReconciliationStatusMismatch}
+	match := &models.ReconciliationReport{WalletID: 1, Status: models.ReconciliationStatusMatch}
+	repos.Reconciliation.Create(ctx, mismatch)
+	repos.Reconciliation.Create(ctx, match)
+
+	if _, err := reconciliationUC.AcknowledgeReport(ctx, match.ID, 1, ""); !errors.Is(err, apperrors.ErrReconciliationReportNoIssue) {
+		t.Errorf("Expected matching reports to have nothing to acknowledge, got: %v", err)
+	}
+	if _, err := reconciliationUC.AcknowledgeReport(ctx, 999, 1, ""); !errors.Is(err, apperrors.ErrReconciliationReportNotFound) {
+		t.Errorf("Expected ErrReconciliationReportNotFound, got: %v", err)
+	}
+
+	report, err := reconciliationUC.AcknowledgeReport(ctx, mismatch.ID, 1, "Looking into it")
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if report.ResolutionStatus != models.ReconciliationResolutionAcknowledged || report.AcknowledgedBy == nil || *report.AcknowledgedBy != 1 {
+		t.Errorf("Expected the report acknowledged by operator 1, got %+v", report)
+	}
+
+	if _, err := reconciliationUC.AssignReport(ctx, mismatch.ID, 71, 1); !errors.Is(err, apperrors.ErrValidation) {
+		t.Errorf("Expected customers to be refused as assignees, got: %v", err)
+	}
+	if _, err := reconciliationUC.AssignReport(ctx, mismatch.ID, 70, 1); err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if _, err := reconciliationUC.AddReportNote(ctx, mismatch.ID, 70, ""); !errors.Is(err, apperrors.ErrValidation) {
+		t.Errorf("Expected an empty note to be refused, got: %v", err)
+	}
+	if _, err := reconciliationUC.AddReportNote(ctx, mismatch.ID, 70, "Fee leg missing from the journal"); err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+
+	if _, err := reconciliationUC.ResolveReport(ctx, mismatch.ID, 1, "IGNORED", ""); !errors.Is(err, apperrors.ErrValidation) {
+		t.Errorf("Expected an unknown resolution type to be refused, got: %v", err)
+	}
+	if _, err := reconciliationUC.ResolveReport(ctx, mismatch.ID, 1, models.ReconciliationResolutionDataFix, "Replayed the fee leg"); err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+
+	stored, _ := repos.Reconciliation.GetByID(ctx, mismatch.ID)
+	if !stored.IsResolved() || stored.ResolutionType == nil || *stored.ResolutionType != models.ReconciliationResolutionDataFix || stored.ResolvedBy == nil {
+		t.Errorf("Expected the report resolved as a data fix, got %+v", stored)
+	}
+	if stored.AssigneeID == nil || *stored.AssigneeID != 70 || *stored.AcknowledgedBy != 1 {
+		t.Errorf("Expected the assignee and first acknowledgement kept, got %+v", stored)
+	}
+	if lines := strings.Split(stored.InvestigationNotes, "\n"); len(lines) != 4 || !strings.HasSuffix(lines[2], "admin 70: Fee leg missing from the journal") {
+		t.Errorf("Expected every note in the investigation log, got %q", stored.InvestigationNotes)
+	}
+
+	if _, err := reconciliationUC.AddReportNote(ctx, mismatch.ID, 1, "Too late"); !errors.Is(err, apperrors.ErrReconciliationReportResolved) {
+		t.Errorf("Expected ErrReconciliationReportResolved, got: %v", err)
+	}
+}
+
+func TestReconciliationUseCase_MismatchWithBrokenPairs(t *testing.T) {
+	repos := setupReconciliationTestEnvironment()
+	transactionRepo := repos.Transaction.(*memory.TransactionRepository)
+	repos.Wallet.Create(context.Background(), &models.Wallet{ID: 44, UserID: 44, Balance: decimal.NewFromFloat(1500), Currency: "USD", Status: models.WalletStatusActive})
+
+	transaction := &models.Transaction{
+		WalletID: 44,
+		Amount:   decimal.NewFromFloat(500),
+		Status:   models.TransactionStatusCompleted,
+	}
+	createBalancedTransaction(transactionRepo, transaction)
+	counterpart, _ := transactionRepo.GetByID(context.Background(), *transaction.RelatedTransactionID)
+	counterpart.Amount = decimal.NewFromFloat(400)
+	transactionRepo.Update(context.Background(), counterpart)
+
+	report, err := NewReconciliationUseCase(repos).PerformWalletReconciliation(context.Background(), 44)
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if report.Status != models.ReconciliationStatusMismatch {
+		t.Errorf("Expected the mismatch to stay visible, got: %v", report.Status)
+	}
+	if !report.HasMismatch() || !report.HasDoubleEntryError() || report.BrokenPairs != 1 {
+		t.Errorf("Expected both a mismatch and one broken pair, got: %+v", report)
+	}
+	if report.GetSeverity() != "CRITICAL" {
+		t.Errorf("Expected CRITICAL severity, got: %s", report.GetSeverity())
+	}
+}
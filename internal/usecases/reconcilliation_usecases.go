@@ -1,14 +1,34 @@
 package usecases
 
 import (
+	"context"
+	"errors"
 	"fmt"
+	"sort"
+	"strings"
 	"time"
 
+	"github.com/limistah/wallet-service/internal/apperrors"
 	"github.com/limistah/wallet-service/internal/models"
+	"github.com/limistah/wallet-service/internal/queue"
 	"github.com/limistah/wallet-service/internal/repositories"
+	"github.com/limistah/wallet-service/internal/utils"
 	"github.com/shopspring/decimal"
+	"gorm.io/gorm"
 )
 
+// reconciliationExportBatchSize bounds how many reports an export loads at once
+const reconciliationExportBatchSize = 500
+
+// reconciliationSummaryTopDifferences is how many of the largest differences a daily summary keeps
+const reconciliationSummaryTopDifferences = 5
+
+// reconciliationRunBatchSize is how many wallets one job of a bulk reconciliation run reconciles
+const reconciliationRunBatchSize = 200
+
+// maxReconciliationScopeWallets caps the wallets a scope can list explicitly
+const maxReconciliationScopeWallets = 1000
+
 // SystemReconciliationReport represents system-wide reconciliation results
 type SystemReconciliationReport struct {
 	TotalWallets       int             `json:"total_wallets"`
@@ -31,52 +51,263 @@ type SystemAccountValidation struct {
 }
 
 type reconciliationUseCase struct {
-	repos *repositories.Repositories
+	repos   *repositories.Repositories
+	metrics *ReconciliationMetrics
+	jobs    queue.Enqueuer
 }
 
 // NewReconciliationUseCase creates a new reconciliation use case
-func NewReconciliationUseCase(repos *repositories.Repositories) ReconciliationUseCase {
-	return &reconciliationUseCase{repos: repos}
+func NewReconciliationUseCase(repos *repositories.Repositories, opts ...Option) ReconciliationUseCase {
+	o := newOptions(opts)
+	return &reconciliationUseCase{repos: repos, metrics: o.reconciliationMetrics, jobs: o.jobs}
+}
+
+// ReconciliationProgress is the outcome of one wallet of a bulk reconciliation run: its report, or the
+// error that kept it from being reconciled, and how far the run has got
+type ReconciliationProgress struct {
+	WalletID uint
+	Report   *models.ReconciliationReport
+	Err      error
+	Done     int
+	Total    int
 }
 
-func (uc *reconciliationUseCase) PerformReconciliation() ([]models.ReconciliationReport, error) {
-	// Get all wallets for reconciliation
-	wallets, err := uc.repos.Wallet.GetAllForReconciliation()
+func (uc *reconciliationUseCase) PerformReconciliation(ctx context.Context) (reports []models.ReconciliationReport, err error) {
+	err = uc.StreamReconciliation(ctx, models.ReconciliationScope{}, func(progress ReconciliationProgress) error {
+		// A wallet that fails is skipped so the others are still reconciled
+		if progress.Report != nil {
+			reports = append(reports, *progress.Report)
+		}
+		return nil
+	})
 	if err != nil {
 		return nil, err
 	}
+	return reports, nil
+}
+
+// StreamReconciliation reconciles the wallets of a scope and hands each wallet's outcome to fn as soon
+// as it is known, so callers can show the progress of a long run. The run stops when fn returns an
+// error or ctx is done
+func (uc *reconciliationUseCase) StreamReconciliation(ctx context.Context, scope models.ReconciliationScope, fn func(ReconciliationProgress) error) (err error) {
+	start := time.Now()
+	defer func() { uc.metrics.observeRun(reconciliationMetricsScope(scope), start, err) }()
+
+	if err := validateReconciliationScope(&scope); err != nil {
+		return err
+	}
+	total, err := uc.repos.Wallet.CountForReconciliation(ctx, scope)
+	if err != nil {
+		return err
+	}
+
+	done := 0
+	for afterID := uint(0); ; {
+		wallets, err := uc.repos.Wallet.ListForReconciliation(ctx, scope, afterID, reconciliationRunBatchSize)
+		if err != nil {
+			return err
+		}
+		for _, wallet := range wallets {
+			if err := ctx.Err(); err != nil {
+				return err
+			}
+			done++
+			progress := ReconciliationProgress{WalletID: wallet.ID, Done: done, Total: max(int(total), done)}
+			progress.Report, progress.Err = uc.performWalletReconciliation(ctx, wallet.ID)
+			if err := fn(progress); err != nil {
+				return err
+			}
+			afterID = wallet.ID
+		}
+		if len(wallets) < reconciliationRunBatchSize {
+			return nil
+		}
+	}
+}
+
+// validateReconciliationScope checks the criteria of a scope and normalizes them
+func validateReconciliationScope(scope *models.ReconciliationScope) error {
+	scope.Currency = strings.ToUpper(strings.TrimSpace(scope.Currency))
+	if scope.Currency != "" && !utils.IsValidCurrency(scope.Currency) {
+		return apperrors.ErrValidation.Withf("unsupported currency %s", scope.Currency)
+	}
+	switch scope.Status {
+	case "", models.WalletStatusActive, models.WalletStatusSuspended, models.WalletStatusClosed:
+	default:
+		return apperrors.ErrValidation.Withf("invalid wallet status %s", scope.Status)
+	}
+	if scope.ActiveFrom != nil && scope.ActiveTo != nil && !scope.ActiveFrom.Before(*scope.ActiveTo) {
+		return apperrors.ErrValidation.Withf("active_from must be before active_to")
+	}
+	if len(scope.WalletIDs) > maxReconciliationScopeWallets {
+		return apperrors.ErrValidation.Withf("at most %d wallets can be listed", maxReconciliationScopeWallets)
+	}
+	// Wallets listed twice are reconciled once
+	seen := make(map[uint]bool, len(scope.WalletIDs))
+	walletIDs := scope.WalletIDs[:0]
+	for _, id := range scope.WalletIDs {
+		if !seen[id] {
+			seen[id] = true
+			walletIDs = append(walletIDs, id)
+		}
+	}
+	scope.WalletIDs = walletIDs
+	return nil
+}
+
+// reconciliationMetricsScope labels the metrics of runs over a cohort apart from full runs, so the
+// last full run stays visible
+func reconciliationMetricsScope(scope models.ReconciliationScope) string {
+	if scope.IsEmpty() {
+		return reconciliationScopeAll
+	}
+	return reconciliationScopePartial
+}
+
+// StartRun records a reconciliation of the wallets of a scope requested by an admin and queues its
+// first batch, so the request returns before the wallets are reconciled; GetRun reports how far the
+// run has got
+func (uc *reconciliationUseCase) StartRun(ctx context.Context, requestedBy uint, scope models.ReconciliationScope) (*models.ReconciliationRun, error) {
+	if err := validateReconciliationScope(&scope); err != nil {
+		return nil, err
+	}
+	run := &models.ReconciliationRun{
+		RequestedBy: requestedBy,
+		Status:      models.ReconciliationRunStatusPending,
+	}
+	if err := run.SetScope(scope); err != nil {
+		return nil, err
+	}
+	if err := uc.repos.ReconciliationRun.Create(ctx, run); err != nil {
+		return nil, fmt.Errorf("failed to save reconciliation run: %w", err)
+	}
+	if err := uc.jobs.Enqueue(JobReconciliationRunBatch, reconciliationRunBatchJob{RunID: run.ID}); err != nil {
+		return nil, fmt.Errorf("failed to queue reconciliation run: %w", err)
+	}
+	return run, nil
+}
+
+func (uc *reconciliationUseCase) GetRun(ctx context.Context, runID uint) (*models.ReconciliationRun, error) {
+	run, err := uc.repos.ReconciliationRun.GetByID(ctx, runID)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, apperrors.ErrReconciliationRunNotFound
+	}
+	return run, err
+}
 
-	var reports []models.ReconciliationReport
+// ProcessRunBatch reconciles the next batch of wallets of a run and queues the batch after it, or
+// completes the run once no wallet is left. Wallets that cannot be reconciled are recorded on the run
+// and skipped
+func (uc *reconciliationUseCase) ProcessRunBatch(ctx context.Context, runID uint) error {
+	run, err := uc.repos.ReconciliationRun.GetByID(ctx, runID)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return queue.Permanent(apperrors.ErrReconciliationRunNotFound)
+	}
+	if err != nil {
+		return err
+	}
+	if run.Status == models.ReconciliationRunStatusCompleted {
+		return nil
+	}
+	scope, err := run.DecodeScope()
+	if err != nil {
+		return queue.Permanent(err)
+	}
+	if run.Status == models.ReconciliationRunStatusPending {
+		total, err := uc.repos.Wallet.CountForReconciliation(ctx, scope)
+		if err != nil {
+			return err
+		}
+		now := time.Now()
+		run.Status = models.ReconciliationRunStatusRunning
+		run.TotalWallets = int(total)
+		run.StartedAt = &now
+	}
 
+	wallets, err := uc.repos.Wallet.ListForReconciliation(ctx, scope, run.LastWalletID, reconciliationRunBatchSize)
+	if err != nil {
+		return err
+	}
 	for _, wallet := range wallets {
-		report, err := uc.performWalletReconciliation(wallet.ID)
+		report, err := uc.performWalletReconciliation(ctx, wallet.ID)
+		run.ProcessedWallets++
+		run.LastWalletID = wallet.ID
 		if err != nil {
-			// Log error but continue with other wallets
+			if err := run.AddFailure(wallet.ID, err); err != nil {
+				return err
+			}
 			continue
 		}
-		reports = append(reports, *report)
+		// A mismatch with broken pairs counts as both
+		switch {
+		case !report.HasAnyIssue():
+			run.Matches++
+		case report.HasMismatch():
+			run.Mismatches++
+		}
+		if report.HasDoubleEntryError() {
+			run.DoubleEntryErrors++
+		}
 	}
 
-	return reports, nil
+	if len(wallets) < reconciliationRunBatchSize {
+		now := time.Now()
+		run.Status = models.ReconciliationRunStatusCompleted
+		run.CompletedAt = &now
+		// Wallets opened while the run was going are reconciled too
+		if run.ProcessedWallets > run.TotalWallets {
+			run.TotalWallets = run.ProcessedWallets
+		}
+		uc.metrics.observeRun(reconciliationMetricsScope(scope), *run.StartedAt, nil)
+	}
+	if err := uc.repos.ReconciliationRun.Update(ctx, run); err != nil {
+		return fmt.Errorf("failed to save reconciliation run: %w", err)
+	}
+	if run.Status == models.ReconciliationRunStatusCompleted {
+		return nil
+	}
+	// The next batch starts after the last wallet saved above, so a retry of this job carries on from there
+	if err := uc.jobs.Enqueue(JobReconciliationRunBatch, reconciliationRunBatchJob{RunID: run.ID}); err != nil {
+		return fmt.Errorf("failed to queue reconciliation run: %w", err)
+	}
+	return nil
 }
 
-func (uc *reconciliationUseCase) PerformWalletReconciliation(walletID uint) (*models.ReconciliationReport, error) {
-	return uc.performWalletReconciliation(walletID)
+func (uc *reconciliationUseCase) PerformWalletReconciliation(ctx context.Context, walletID uint) (report *models.ReconciliationReport, err error) {
+	start := time.Now()
+	defer func() { uc.metrics.observeRun(reconciliationScopeWallet, start, err) }()
+	return uc.performWalletReconciliation(ctx, walletID)
 }
 
-func (uc *reconciliationUseCase) performWalletReconciliation(walletID uint) (*models.ReconciliationReport, error) {
+// calculateBalance adds the wallet's transactions after its balance checkpoint to the checkpointed
+// balance, so transactions moved to the archive are still counted
+func (uc *reconciliationUseCase) calculateBalance(ctx context.Context, walletID uint) (decimal.Decimal, error) {
+	checkpoint, err := uc.repos.BalanceCheckpoint.GetByWalletID(ctx, walletID)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return uc.repos.Transaction.CalculateBalance(ctx, walletID)
+	}
+	if err != nil {
+		return decimal.Zero, err
+	}
+	live, err := uc.repos.Transaction.CalculateBalanceAfter(ctx, walletID, checkpoint.ThroughTransactionID)
+	if err != nil {
+		return decimal.Zero, err
+	}
+	return checkpoint.Balance.Add(live), nil
+}
+
+func (uc *reconciliationUseCase) performWalletReconciliation(ctx context.Context, walletID uint) (*models.ReconciliationReport, error) {
 	// Get wallet
-	wallet, err := uc.repos.Wallet.GetByID(walletID)
+	wallet, err := uc.repos.Wallet.GetByID(ctx, walletID)
 	if err != nil {
 		return nil, err
 	}
 
 	// Calculate balance from transactions
-	calculatedBalance, err := uc.repos.Transaction.CalculateBalance(walletID)
+	calculatedBalance, err := uc.calculateBalance(ctx, walletID)
 	if err != nil {
 		return nil, err
 	}
-	fmt.Println("Calculated Balance:", calculatedBalance.String())
 
 	// Compare balances
 	storedBalance := wallet.Balance
@@ -91,6 +322,19 @@ func (uc *reconciliationUseCase) performWalletReconciliation(walletID uint) (*mo
 		notes = fmt.Sprintf("Balance mismatch detected. Difference: %s", difference.String())
 	}
 
+	// A broken double-entry pair is reported even when the balances agree, since both legs may be wrong.
+	// A mismatch keeps its status so the drift stays visible, and the broken pairs are counted alongside
+	issues, err := uc.checkDoubleEntries(ctx, walletID)
+	if err != nil {
+		return nil, err
+	}
+	if len(issues) > 0 {
+		if status == models.ReconciliationStatusMatch {
+			status = models.ReconciliationStatusDoubleEntryError
+		}
+		notes = describeDoubleEntryIssues(issues) + ". " + notes
+	}
+
 	// Create reconciliation report
 	report := &models.ReconciliationReport{
 		WalletID:          walletID,
@@ -98,11 +342,12 @@ func (uc *reconciliationUseCase) performWalletReconciliation(walletID uint) (*mo
 		CalculatedBalance: calculatedBalance,
 		Difference:        difference,
 		Status:            status,
+		BrokenPairs:       len(issues),
 		Notes:             notes,
 	}
 
 	// Save the report
-	err = uc.repos.Reconciliation.Create(report)
+	err = uc.repos.Reconciliation.Create(ctx, report)
 	if err != nil {
 		return nil, err
 	}
@@ -110,12 +355,225 @@ func (uc *reconciliationUseCase) performWalletReconciliation(walletID uint) (*mo
 	return report, nil
 }
 
-func (uc *reconciliationUseCase) GetReconciliationReports(page, pageSize int) ([]models.ReconciliationReport, error) {
+func (uc *reconciliationUseCase) GetReconciliationReports(ctx context.Context, page, pageSize int) ([]models.ReconciliationReport, error) {
+	offset := (page - 1) * pageSize
+	return uc.repos.Reconciliation.List(ctx, offset, pageSize)
+}
+
+func (uc *reconciliationUseCase) GetMismatchReports(ctx context.Context, page, pageSize int) ([]models.ReconciliationReport, error) {
 	offset := (page - 1) * pageSize
-	return uc.repos.Reconciliation.List(offset, pageSize)
+	return uc.repos.Reconciliation.GetMismatches(ctx, offset, pageSize)
+}
+
+// ExportReports hands the reports matching filter to fn in batches, oldest first
+func (uc *reconciliationUseCase) ExportReports(ctx context.Context, filter repositories.ReconciliationReportFilter, fn func([]models.ReconciliationReport) error) error {
+	if filter.From != nil && filter.To != nil && !filter.From.Before(*filter.To) {
+		return apperrors.ErrValidation.Withf("from must be before to")
+	}
+	return uc.repos.Reconciliation.ExportReports(ctx, filter, reconciliationExportBatchSize, fn)
+}
+
+func (uc *reconciliationUseCase) GetWalletReconciliationReports(ctx context.Context, walletID uint) ([]models.ReconciliationReport, error) {
+	if _, err := uc.repos.Wallet.GetByID(ctx, walletID); err != nil {
+		return nil, err
+	}
+	return uc.repos.Reconciliation.GetByWalletID(ctx, walletID)
+}
+
+// AcknowledgeReport records that an operator has picked up the issue of a report, with an optional
+// first note
+func (uc *reconciliationUseCase) AcknowledgeReport(ctx context.Context, reportID, operatorID uint, note string) (*models.ReconciliationReport, error) {
+	report, err := uc.getUnresolvedReport(ctx, reportID)
+	if err != nil {
+		return nil, err
+	}
+	acknowledgeReport(report, operatorID)
+	if note != "" {
+		report.InvestigationNotes = appendSuspenseNote(report.InvestigationNotes, operatorID, note)
+	}
+	return report, uc.saveReport(ctx, report)
+}
+
+// AssignReport hands the issue of a report to an admin or support operator, acknowledging it if
+// nobody has yet
+func (uc *reconciliationUseCase) AssignReport(ctx context.Context, reportID, assigneeID, operatorID uint) (*models.ReconciliationReport, error) {
+	report, err := uc.getUnresolvedReport(ctx, reportID)
+	if err != nil {
+		return nil, err
+	}
+	assignee, err := uc.repos.User.GetByID(ctx, assigneeID)
+	if errors.Is(err, gorm.ErrRecordNotFound) || (err == nil && assignee.Role != models.UserRoleAdmin && assignee.Role != models.UserRoleSupport) {
+		return nil, apperrors.ErrValidation.Withf("assignee must be an admin or support operator")
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	acknowledgeReport(report, operatorID)
+	report.AssigneeID = &assignee.ID
+	report.InvestigationNotes = appendSuspenseNote(report.InvestigationNotes, operatorID, fmt.Sprintf("assigned to %d", assignee.ID))
+	return report, uc.saveReport(ctx, report)
+}
+
+// AddReportNote adds a finding to the investigation log of an unresolved report
+func (uc *reconciliationUseCase) AddReportNote(ctx context.Context, reportID, operatorID uint, note string) (*models.ReconciliationReport, error) {
+	if note == "" {
+		return nil, apperrors.ErrValidation.Withf("note is required")
+	}
+	report, err := uc.getUnresolvedReport(ctx, reportID)
+	if err != nil {
+		return nil, err
+	}
+	report.InvestigationNotes = appendSuspenseNote(report.InvestigationNotes, operatorID, note)
+	return report, uc.saveReport(ctx, report)
+}
+
+// ResolveReport closes the issue of a report, recording how it was settled
+func (uc *reconciliationUseCase) ResolveReport(ctx context.Context, reportID, operatorID uint, resolution models.ReconciliationResolutionType, note string) (*models.ReconciliationReport, error) {
+	switch resolution {
+	case models.ReconciliationResolutionAdjusted, models.ReconciliationResolutionFalsePositive, models.ReconciliationResolutionDataFix:
+	default:
+		return nil, apperrors.ErrValidation.Withf("invalid resolution type %s", resolution)
+	}
+	report, err := uc.getUnresolvedReport(ctx, reportID)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	acknowledgeReport(report, operatorID)
+	report.ResolutionStatus = models.ReconciliationResolutionResolved
+	report.ResolutionType = &resolution
+	report.ResolvedBy = &operatorID
+	report.ResolvedAt = &now
+	if note != "" {
+		report.InvestigationNotes = appendSuspenseNote(report.InvestigationNotes, operatorID, note)
+	}
+	return report, uc.saveReport(ctx, report)
+}
+
+func (uc *reconciliationUseCase) getUnresolvedReport(ctx context.Context, reportID uint) (*models.ReconciliationReport, error) {
+	report, err := uc.repos.Reconciliation.GetByID(ctx, reportID)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, apperrors.ErrReconciliationReportNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	if !report.HasAnyIssue() {
+		return nil, apperrors.ErrReconciliationReportNoIssue
+	}
+	if report.IsResolved() {
+		return nil, apperrors.ErrReconciliationReportResolved
+	}
+	return report, nil
+}
+
+func (uc *reconciliationUseCase) saveReport(ctx context.Context, report *models.ReconciliationReport) error {
+	if err := uc.repos.Reconciliation.Update(ctx, report); err != nil {
+		return fmt.Errorf("failed to update reconciliation report: %w", err)
+	}
+	return nil
+}
+
+// acknowledgeReport marks an open report as picked up by the operator; later acknowledgements keep
+// the first one
+func acknowledgeReport(report *models.ReconciliationReport, operatorID uint) {
+	if report.ResolutionStatus != models.ReconciliationResolutionOpen {
+		return
+	}
+	now := time.Now()
+	report.ResolutionStatus = models.ReconciliationResolutionAcknowledged
+	report.AcknowledgedBy = &operatorID
+	report.AcknowledgedAt = &now
+}
+
+// SummarizeDay aggregates the reconciliation reports created on the UTC day of the given time. The
+// summary is stored once per day; later calls return the stored one
+func (uc *reconciliationUseCase) SummarizeDay(ctx context.Context, day time.Time) (*models.ReconciliationSummary, error) {
+	from := time.Date(day.Year(), day.Month(), day.Day(), 0, 0, 0, 0, time.UTC)
+	to := from.AddDate(0, 0, 1)
+
+	existing, err := uc.repos.ReconciliationSummary.GetByDate(ctx, from)
+	if err == nil {
+		return existing, nil
+	}
+	if !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, err
+	}
+
+	summary := &models.ReconciliationSummary{Date: from}
+	wallets := make(map[uint]bool)
+	largest := make(map[uint]models.ReconciliationDifference)
+
+	filter := repositories.ReconciliationReportFilter{From: &from, To: &to}
+	err = uc.repos.Reconciliation.ExportReports(ctx, filter, reconciliationExportBatchSize, func(reports []models.ReconciliationReport) error {
+		for _, report := range reports {
+			summary.Checks++
+			wallets[report.WalletID] = true
+			// A mismatch with broken pairs counts as both
+			switch {
+			case !report.HasAnyIssue():
+				summary.Matches++
+			case report.HasMismatch():
+				summary.Mismatches++
+			}
+			if report.HasDoubleEntryError() {
+				summary.DoubleEntryErrors++
+			}
+
+			// A wallet checked several times is listed once, with its largest difference
+			if report.Difference.IsZero() {
+				continue
+			}
+			if current, ok := largest[report.WalletID]; !ok || report.Difference.Abs().GreaterThan(current.Difference.Abs()) {
+				largest[report.WalletID] = models.ReconciliationDifference{
+					WalletID:   report.WalletID,
+					ReportID:   report.ID,
+					Difference: report.Difference,
+				}
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	summary.WalletsChecked = len(wallets)
+
+	differences := make([]models.ReconciliationDifference, 0, len(largest))
+	for _, difference := range largest {
+		differences = append(differences, difference)
+	}
+	sort.Slice(differences, func(i, j int) bool {
+		if cmp := differences[i].Difference.Abs().Cmp(differences[j].Difference.Abs()); cmp != 0 {
+			return cmp > 0
+		}
+		return differences[i].WalletID < differences[j].WalletID
+	})
+	if len(differences) > reconciliationSummaryTopDifferences {
+		differences = differences[:reconciliationSummaryTopDifferences]
+	}
+	if err := summary.SetDifferences(differences); err != nil {
+		return nil, err
+	}
+
+	if err := uc.repos.ReconciliationSummary.Create(ctx, summary); err != nil {
+		// Another instance may have stored the day's summary first
+		if existing, getErr := uc.repos.ReconciliationSummary.GetByDate(ctx, from); getErr == nil {
+			return existing, nil
+		}
+		return nil, err
+	}
+	return summary, nil
+}
+
+// MarkSummaryDelivered records that the digest of a summary was sent
+func (uc *reconciliationUseCase) MarkSummaryDelivered(ctx context.Context, summaryID uint) error {
+	return uc.repos.ReconciliationSummary.MarkDelivered(ctx, summaryID, time.Now())
 }
 
-func (uc *reconciliationUseCase) GetMismatchReports(page, pageSize int) ([]models.ReconciliationReport, error) {
+func (uc *reconciliationUseCase) GetSummaries(ctx context.Context, page, pageSize int) ([]models.ReconciliationSummary, error) {
 	offset := (page - 1) * pageSize
-	return uc.repos.Reconciliation.GetMismatches(offset, pageSize)
+	return uc.repos.ReconciliationSummary.List(ctx, offset, pageSize)
 }
@@ -0,0 +1,120 @@
+package usecases
+
+import (
+	"context"
+	"errors"
+	"log"
+	"time"
+
+	"github.com/limistah/wallet-service/internal/apperrors"
+	"github.com/limistah/wallet-service/internal/models"
+	"github.com/limistah/wallet-service/internal/repositories"
+	"gorm.io/gorm"
+)
+
+// TokenInfo identifies an issued token
+type TokenInfo struct {
+	ID        string
+	IssuedAt  time.Time
+	ExpiresAt time.Time
+}
+
+type sessionUseCase struct {
+	repos       *repositories.Repositories
+	revocations TokenRevocationUseCase
+	now         func() time.Time
+}
+
+// NewSessionUseCase creates a new session use case
+func NewSessionUseCase(repos *repositories.Repositories, revocations TokenRevocationUseCase) SessionUseCase {
+	return &sessionUseCase{
+		repos:       repos,
+		revocations: revocations,
+		now:         time.Now,
+	}
+}
+
+// StartSession records the device a token was issued to at login
+func (uc *sessionUseCase) StartSession(ctx context.Context, userID uint, token TokenInfo, client ClientInfo) (*models.Session, error) {
+	session := &models.Session{
+		UserID:    userID,
+		TokenID:   token.ID,
+		IPAddress: client.IPAddress,
+		UserAgent: truncateUserAgent(client.UserAgent),
+		IssuedAt:  token.IssuedAt,
+		ExpiresAt: token.ExpiresAt,
+	}
+	if err := uc.repos.Session.Create(ctx, session); err != nil {
+		return nil, err
+	}
+
+	// Sessions whose token expired are never listed again
+	if _, err := uc.repos.Session.DeleteExpired(ctx, uc.now()); err != nil {
+		log.Printf("Failed to delete expired sessions: %v", err)
+	}
+	return session, nil
+}
+
+// RefreshSession moves the session of a refreshed token to its replacement. Tokens issued before
+// sessions were tracked start a new session
+func (uc *sessionUseCase) RefreshSession(ctx context.Context, userID uint, oldTokenID string, token TokenInfo, client ClientInfo) (*models.Session, error) {
+	session, err := uc.repos.Session.GetByTokenID(ctx, oldTokenID)
+	if errors.Is(err, gorm.ErrRecordNotFound) || (err == nil && (session.UserID != userID || session.RevokedAt != nil)) {
+		return uc.StartSession(ctx, userID, token, client)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	session.TokenID = token.ID
+	session.IssuedAt = token.IssuedAt
+	session.ExpiresAt = token.ExpiresAt
+	session.IPAddress = client.IPAddress
+	session.UserAgent = truncateUserAgent(client.UserAgent)
+	if err := uc.repos.Session.Update(ctx, session); err != nil {
+		return nil, err
+	}
+	return session, nil
+}
+
+// ListSessions returns the devices of a user holding a valid token, latest first. Sessions signed out
+// along with every other one, by logging out everywhere or changing the password, are left out
+func (uc *sessionUseCase) ListSessions(ctx context.Context, userID uint) ([]models.Session, error) {
+	sessions, err := uc.repos.Session.ListActive(ctx, userID, uc.now())
+	if err != nil {
+		return nil, err
+	}
+
+	revokedAt, err := uc.repos.User.GetTokensRevokedAt(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if revokedAt == nil {
+		return sessions, nil
+	}
+
+	active := make([]models.Session, 0, len(sessions))
+	for _, session := range sessions {
+		// Same rule as TokenRevocationUseCase.IsRevoked
+		if session.IssuedAt.After(*revokedAt) {
+			active = append(active, session)
+		}
+	}
+	return active, nil
+}
+
+// RevokeSession signs a device of the user out by revoking its token
+func (uc *sessionUseCase) RevokeSession(ctx context.Context, userID, sessionID uint) error {
+	session, err := uc.repos.Session.GetByID(ctx, sessionID)
+	if errors.Is(err, gorm.ErrRecordNotFound) || (err == nil && session.UserID != userID) {
+		return apperrors.ErrSessionNotFound
+	}
+	if err != nil {
+		return err
+	}
+	if session.RevokedAt != nil {
+		return nil
+	}
+
+	return uc.revocations.RevokeToken(ctx, session.TokenID, userID, session.ExpiresAt)
+}
@@ -0,0 +1,106 @@
+package usecases
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/limistah/wallet-service/internal/apperrors"
+	"github.com/limistah/wallet-service/internal/models"
+)
+
+func newTestSessionUseCase(now time.Time) (*sessionUseCase, *tokenRevocationUseCase) {
+	revocations, repos := newTestTokenRevocation(now)
+	uc := NewSessionUseCase(repos, revocations).(*sessionUseCase)
+	uc.now = func() time.Time { return now }
+	return uc, revocations
+}
+
+func testToken(id string, issuedAt time.Time) TokenInfo {
+	return TokenInfo{ID: id, IssuedAt: issuedAt, ExpiresAt: issuedAt.Add(24 * time.Hour)}
+}
+
+func TestSessionUseCase_ListAndRevoke(t *testing.T) {
+	now := time.Date(2025, 3, 1, 12, 0, 0, 0, time.UTC)
+	uc, revocations := newTestSessionUseCase(now)
+	laptop := ClientInfo{IPAddress: "10.0.0.1", UserAgent: "Mozilla/5.0 (Macintosh)"}
+	phone := ClientInfo{IPAddress: "10.0.0.2", UserAgent: "WalletApp/2.1 (iPhone)"}
+
+	uc.StartSession(context.Background(), 1, testToken("laptop-token", now.Add(-2*time.Hour)), laptop)
+	phoneSession, err := uc.StartSession(context.Background(), 1, testToken("phone-token", now.Add(-time.Hour)), phone)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	// Expired tokens no longer have a session
+	uc.repos.Session.Create(context.Background(), &models.Session{UserID: 1, TokenID: "old-token", IssuedAt: now.Add(-48 * time.Hour), ExpiresAt: now.Add(-24 * time.Hour)})
+
+	sessions, err := uc.ListSessions(context.Background(), 1)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if len(sessions) != 2 || sessions[0].TokenID != "phone-token" {
+		t.Fatalf("Expected the 2 active sessions, latest first, got %+v", sessions)
+	}
+
+	if err := uc.RevokeSession(context.Background(), 2, phoneSession.ID); !errors.Is(err, apperrors.ErrSessionNotFound) {
+		t.Errorf("Expected sessions of other users to be hidden, got %v", err)
+	}
+	if err := uc.RevokeSession(context.Background(), 1, phoneSession.ID); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if revoked, _ := revocations.IsRevoked(context.Background(), "phone-token", 1, now.Add(-time.Hour)); !revoked {
+		t.Error("Expected the token of the revoked session to be refused")
+	}
+	if revoked, _ := revocations.IsRevoked(context.Background(), "laptop-token", 1, now.Add(-2*time.Hour)); revoked {
+		t.Error("Expected the other sessions to stay signed in")
+	}
+	sessions, _ = uc.ListSessions(context.Background(), 1)
+	if len(sessions) != 1 || sessions[0].TokenID != "laptop-token" {
+		t.Errorf("Expected only the laptop session to be left, got %+v", sessions)
+	}
+}
+
+func TestSessionUseCase_RefreshKeepsSession(t *testing.T) {
+	now := time.Date(2025, 3, 1, 12, 0, 0, 0, time.UTC)
+	uc, _ := newTestSessionUseCase(now)
+	client := ClientInfo{IPAddress: "10.0.0.1", UserAgent: "WalletApp/2.1 (iPhone)"}
+
+	session, _ := uc.StartSession(context.Background(), 1, testToken("first-token", now.Add(-time.Hour)), client)
+	refreshed, err := uc.RefreshSession(context.Background(), 1, "first-token", testToken("second-token", now), client)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if refreshed.ID != session.ID || refreshed.TokenID != "second-token" || !refreshed.IssuedAt.Equal(now) {
+		t.Errorf("Expected the session to move to the new token, got %+v", refreshed)
+	}
+
+	// Tokens issued before sessions were tracked start one
+	started, err := uc.RefreshSession(context.Background(), 1, "untracked-token", testToken("third-token", now), client)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if started.ID == session.ID {
+		t.Error("Expected a new session for an untracked token")
+	}
+}
+
+func TestSessionUseCase_LogoutEverywhereHidesSessions(t *testing.T) {
+	now := time.Date(2025, 3, 1, 12, 0, 0, 0, time.UTC)
+	uc, revocations := newTestSessionUseCase(now)
+
+	uc.StartSession(context.Background(), 1, testToken("old-token", now.Add(-time.Hour)), ClientInfo{})
+	if err := revocations.RevokeAllTokens(context.Background(), 1); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	uc.StartSession(context.Background(), 1, testToken("new-token", now.Add(time.Second)), ClientInfo{})
+
+	sessions, err := uc.ListSessions(context.Background(), 1)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if len(sessions) != 1 || sessions[0].TokenID != "new-token" {
+		t.Errorf("Expected only the session started after logging out everywhere, got %+v", sessions)
+	}
+}
@@ -0,0 +1,202 @@
+package usecases
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/limistah/wallet-service/internal/apperrors"
+	"github.com/limistah/wallet-service/internal/models"
+	"github.com/limistah/wallet-service/internal/repositories"
+	"github.com/shopspring/decimal"
+	"gorm.io/gorm"
+)
+
+type settlementUseCase struct {
+	repos *repositories.Repositories
+}
+
+// NewSettlementUseCase creates a new settlement use case
+func NewSettlementUseCase(repos *repositories.Repositories) SettlementUseCase {
+	return &settlementUseCase{repos: repos}
+}
+
+// settlementKey identifies the batch a provider-facing item belongs to on a day
+type settlementKey struct {
+	Provider string
+	Currency string
+}
+
+// settlementGroup holds the items of one batch that are not batched yet
+type settlementGroup struct {
+	Deposits []models.Deposit
+	Payouts  []models.Payout
+}
+
+// CloseDay batches the deposits and payouts completed on the UTC day of the given time, one batch per
+// provider and currency. Items that complete late are added to the day's existing batch, so the day
+// can be closed again safely
+func (uc *settlementUseCase) CloseDay(ctx context.Context, day time.Time) ([]models.SettlementBatch, error) {
+	from := time.Date(day.Year(), day.Month(), day.Day(), 0, 0, 0, 0, time.UTC)
+	to := from.AddDate(0, 0, 1)
+
+	deposits, err := uc.repos.Deposit.ListUnbatched(ctx, from, to)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load deposits: %w", err)
+	}
+	payouts, err := uc.repos.Payout.ListUnbatched(ctx, from, to)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load payouts: %w", err)
+	}
+
+	groups := groupSettlementItems(deposits, payouts)
+	keys := make([]settlementKey, 0, len(groups))
+	for key := range groups {
+		keys = append(keys, key)
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		if keys[i].Provider != keys[j].Provider {
+			return keys[i].Provider < keys[j].Provider
+		}
+		return keys[i].Currency < keys[j].Currency
+	})
+
+	batches := make([]models.SettlementBatch, 0, len(keys))
+	for _, key := range keys {
+		batch, err := uc.addToBatch(ctx, key, from, groups[key])
+		if err != nil {
+			return nil, err
+		}
+		batches = append(batches, *batch)
+	}
+	return batches, nil
+}
+
+// addToBatch adds a group of items to the day's batch for their provider and currency, creating the
+// batch on first use
+func (uc *settlementUseCase) addToBatch(ctx context.Context, key settlementKey, date time.Time, group *settlementGroup) (*models.SettlementBatch, error) {
+	batch, err := uc.repos.SettlementBatch.GetByKey(ctx, key.Provider, key.Currency, date)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		batch = &models.SettlementBatch{
+			Provider: key.Provider,
+			Currency: key.Currency,
+			Date:     date,
+			Status:   models.SettlementBatchStatusPending,
+		}
+	} else if err != nil {
+		return nil, fmt.Errorf("error checking settlement batch: %w", err)
+	}
+
+	depositIDs := make([]uint, len(group.Deposits))
+	for i, deposit := range group.Deposits {
+		batch.AddDeposit(deposit.Amount)
+		depositIDs[i] = deposit.ID
+	}
+	payoutIDs := make([]uint, len(group.Payouts))
+	for i, payout := range group.Payouts {
+		batch.AddPayout(payout.Amount)
+		payoutIDs[i] = payout.ID
+	}
+	// Late items change what the provider owes, so a recorded settlement is compared again
+	if batch.ReceivedNet != nil {
+		batch.SetReceived(*batch.ReceivedNet)
+	}
+
+	err = uc.repos.UnitOfWork.Do(ctx, func(tx *repositories.Repositories) error {
+		if err := tx.SettlementBatch.Update(ctx, batch); err != nil {
+			return fmt.Errorf("failed to save settlement batch: %w", err)
+		}
+		if err := claimForBatch(ctx, tx.Deposit.AssignToBatch, depositIDs, batch.ID); err != nil {
+			return err
+		}
+		return claimForBatch(ctx, tx.Payout.AssignToBatch, payoutIDs, batch.ID)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return batch, nil
+}
+
+// claimForBatch assigns unbatched items to a batch, failing when a concurrent run took any of them
+// first so that the batch totals are not counted twice
+func claimForBatch(ctx context.Context, assign func(ctx context.Context, ids []uint, batchID uint) (int, error), ids []uint, batchID uint) error {
+	if len(ids) == 0 {
+		return nil
+	}
+	assigned, err := assign(ctx, ids, batchID)
+	if err != nil {
+		return fmt.Errorf("failed to assign settlement batch: %w", err)
+	}
+	if assigned != len(ids) {
+		return apperrors.ErrConcurrentModification.Withf("settlement items were batched concurrently")
+	}
+	return nil
+}
+
+func (uc *settlementUseCase) ListBatches(ctx context.Context, filter repositories.SettlementBatchFilter, page, pageSize int) ([]models.SettlementBatch, error) {
+	if page < 1 {
+		page = 1
+	}
+	if pageSize < 1 || pageSize > 100 {
+		pageSize = 20
+	}
+	filter.Provider = strings.ToLower(filter.Provider)
+	return uc.repos.SettlementBatch.List(ctx, filter, (page-1)*pageSize, pageSize)
+}
+
+// GetBatch returns a batch with the deposits and payouts it holds
+func (uc *settlementUseCase) GetBatch(ctx context.Context, batchID uint) (*models.SettlementBatch, error) {
+	batch, err := uc.repos.SettlementBatch.GetByID(ctx, batchID)
+	if err != nil {
+		return nil, apperrors.ErrSettlementBatchNotFound
+	}
+	return batch, nil
+}
+
+// RecordReceived records the net settled by the provider for a batch from its statement, marking the
+// batch matched or mismatched. Recording again replaces the earlier figure
+func (uc *settlementUseCase) RecordReceived(ctx context.Context, batchID, adminID uint, received decimal.Decimal, note string) (*models.SettlementBatch, error) {
+	batch, err := uc.repos.SettlementBatch.GetByID(ctx, batchID)
+	if err != nil {
+		return nil, apperrors.ErrSettlementBatchNotFound
+	}
+
+	now := time.Now()
+	batch.SetReceived(received)
+	batch.ReconciledBy = &adminID
+	batch.ReconciledAt = &now
+	if note != "" {
+		batch.Notes = note
+	}
+	if err := uc.repos.SettlementBatch.Update(ctx, batch); err != nil {
+		return nil, fmt.Errorf("failed to update settlement batch: %w", err)
+	}
+	return batch, nil
+}
+
+// groupSettlementItems groups deposits and payouts by the provider and currency they settle in
+func groupSettlementItems(deposits []models.Deposit, payouts []models.Payout) map[settlementKey]*settlementGroup {
+	groups := make(map[settlementKey]*settlementGroup)
+	groupFor := func(provider, currency string) *settlementGroup {
+		key := settlementKey{Provider: strings.ToLower(provider), Currency: strings.ToUpper(currency)}
+		group, ok := groups[key]
+		if !ok {
+			group = &settlementGroup{}
+			groups[key] = group
+		}
+		return group
+	}
+
+	for _, deposit := range deposits {
+		group := groupFor(deposit.Provider, deposit.Currency)
+		group.Deposits = append(group.Deposits, deposit)
+	}
+	for _, payout := range payouts {
+		group := groupFor(payout.Provider, payout.Currency)
+		group.Payouts = append(group.Payouts, payout)
+	}
+	return groups
+}
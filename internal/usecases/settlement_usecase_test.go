@@ -0,0 +1,82 @@
+package usecases
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/limistah/wallet-service/internal/apperrors"
+	"github.com/limistah/wallet-service/internal/models"
+	"github.com/limistah/wallet-service/internal/repositories/memory"
+	"github.com/shopspring/decimal"
+)
+
+func TestGroupSettlementItems(t *testing.T) {
+	deposits := []models.Deposit{
+		{ID: 1, Provider: "paystack", Currency: "NGN", Amount: decimal.NewFromInt(100)},
+		{ID: 2, Provider: "flutterwave", Currency: "NGN", Amount: decimal.NewFromInt(40)},
+		{ID: 3, Provider: "paystack", Currency: "ngn", Amount: decimal.NewFromInt(60)},
+		{ID: 4, Provider: "paystack", Currency: "USD", Amount: decimal.NewFromInt(10)},
+	}
+	payouts := []models.Payout{
+		{ID: 1, Provider: "Paystack", Currency: "NGN", Amount: decimal.NewFromInt(30)},
+	}
+
+	groups := groupSettlementItems(deposits, payouts)
+	if len(groups) != 3 {
+		t.Fatalf("Expected 3 provider and currency groups, got %d", len(groups))
+	}
+	paystack := groups[settlementKey{Provider: "paystack", Currency: "NGN"}]
+	if paystack == nil || len(paystack.Deposits) != 2 || len(paystack.Payouts) != 1 {
+		t.Fatalf("Expected 2 deposits and 1 payout for paystack NGN, got: %+v", paystack)
+	}
+
+	batch := &models.SettlementBatch{}
+	for _, deposit := range paystack.Deposits {
+		batch.AddDeposit(deposit.Amount)
+	}
+	for _, payout := range paystack.Payouts {
+		batch.AddPayout(payout.Amount)
+	}
+	if !batch.ExpectedNet.Equal(decimal.NewFromInt(130)) || batch.DepositCount != 2 || batch.PayoutCount != 1 {
+		t.Errorf("Expected an expected net of 130 from 2 deposits and 1 payout, got: %+v", batch)
+	}
+}
+
+func TestSettlementUseCase_RecordReceived(t *testing.T) {
+	repos, _ := setupTestEnvironment()
+	batches := memory.NewSettlementBatchRepository()
+	repos.SettlementBatch = batches
+	settlementUC := NewSettlementUseCase(repos)
+
+	batch := &models.SettlementBatch{Provider: "paystack", Currency: "NGN", Status: models.SettlementBatchStatusPending}
+	batch.AddDeposit(decimal.NewFromInt(500))
+	batch.AddPayout(decimal.NewFromInt(200))
+	batches.Create(batch)
+
+	if _, err := settlementUC.RecordReceived(context.Background(), 99, 3, decimal.NewFromInt(300), ""); !errors.Is(err, apperrors.ErrSettlementBatchNotFound) {
+		t.Errorf("Expected settlement batch not found, got: %v", err)
+	}
+
+	recorded, err := settlementUC.RecordReceived(context.Background(), batch.ID, 3, decimal.NewFromInt(295), "statement short by provider fee")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if recorded.Status != models.SettlementBatchStatusMismatched || !recorded.Difference.Equal(decimal.NewFromInt(-5)) {
+		t.Errorf("Expected a mismatch of -5, got status %s and difference %v", recorded.Status, recorded.Difference)
+	}
+	if recorded.ReconciledBy == nil || *recorded.ReconciledBy != 3 || recorded.Notes != "statement short by provider fee" {
+		t.Errorf("Expected admin 3 and the note to be recorded, got: %+v", recorded)
+	}
+
+	recorded, err = settlementUC.RecordReceived(context.Background(), batch.ID, 4, decimal.NewFromInt(300), "")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if recorded.Status != models.SettlementBatchStatusMatched || !recorded.Difference.IsZero() {
+		t.Errorf("Expected the corrected figure to match, got status %s and difference %v", recorded.Status, recorded.Difference)
+	}
+	if recorded.Notes != "statement short by provider fee" {
+		t.Errorf("Expected an empty note to keep the earlier one, got: %q", recorded.Notes)
+	}
+}
@@ -0,0 +1,289 @@
+package usecases
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"strconv"
+	"time"
+
+	"github.com/limistah/wallet-service/internal/apperrors"
+	"github.com/limistah/wallet-service/internal/events"
+	"github.com/limistah/wallet-service/internal/models"
+	"github.com/limistah/wallet-service/internal/repositories"
+	"github.com/shopspring/decimal"
+)
+
+// standingOrderBatchSize bounds how many orders and occurrences one scheduler tick handles
+const standingOrderBatchSize = 100
+
+type standingOrderUseCase struct {
+	repos         *repositories.Repositories
+	walletUseCase WalletUseCase
+	publisher     events.EventPublisher
+	retry         RetryPolicy
+	authorizer    debitAuthorizer
+}
+
+// NewStandingOrderUseCase creates a new standing order use case
+func NewStandingOrderUseCase(repos *repositories.Repositories, walletUseCase WalletUseCase, opts ...Option) StandingOrderUseCase {
+	o := newOptions(opts)
+	return &standingOrderUseCase{
+		repos:         repos,
+		walletUseCase: walletUseCase,
+		publisher:     o.publisher,
+		retry:         o.standingOrderRetry,
+		authorizer:    newDebitAuthorizer(repos, o),
+	}
+}
+
+// CreateStandingOrder schedules a recurring transfer. pin authorizes the order once for the amount
+// of every occurrence, since the scheduler pays them without the user
+func (uc *standingOrderUseCase) CreateStandingOrder(ctx context.Context, userID, destinationWalletID uint, amount decimal.Decimal, frequency models.StandingOrderFrequency, startAt time.Time, endsAt *time.Time, description, pin string) (*models.StandingOrder, error) {
+	if amount.LessThanOrEqual(decimal.Zero) {
+		return nil, apperrors.ErrInvalidAmount
+	}
+	if !models.IsValidStandingOrderFrequency(frequency) {
+		return nil, apperrors.ErrValidation.Withf("invalid frequency")
+	}
+	if startAt.IsZero() {
+		startAt = time.Now()
+	}
+	if startAt.Before(time.Now().Add(-time.Minute)) {
+		return nil, apperrors.ErrValidation.Withf("start date must not be in the past")
+	}
+	if endsAt != nil && endsAt.Before(startAt) {
+		return nil, apperrors.ErrValidation.Withf("end date must be after the start date")
+	}
+
+	wallet, err := uc.walletUseCase.GetWalletByUserID(ctx, userID)
+	if err != nil {
+		return nil, apperrors.ErrWalletNotFound
+	}
+	if !wallet.IsActive() {
+		return nil, apperrors.ErrWalletInactive
+	}
+	if err := requireTierFeature(ctx, uc.repos, wallet, func(t *models.WalletTier) bool { return t.StandingOrdersEnabled }, "standing orders"); err != nil {
+		return nil, err
+	}
+
+	destination, err := uc.walletUseCase.GetWallet(ctx, destinationWalletID)
+	if err != nil || destination.Sandbox {
+		return nil, apperrors.ErrCounterpartyWalletNotFound
+	}
+	if destination.ID == wallet.ID {
+		return nil, apperrors.ErrSelfPayment
+	}
+	if destination.Currency != wallet.Currency {
+		return nil, apperrors.ErrCurrencyMismatch.Withf("destination wallet currency does not match")
+	}
+	if err := uc.authorizer.authorize(ctx, userID, amount, pin); err != nil {
+		return nil, err
+	}
+
+	order := &models.StandingOrder{
+		UserID:              userID,
+		WalletID:            wallet.ID,
+		DestinationWalletID: destination.ID,
+		Amount:              amount,
+		Currency:            wallet.Currency,
+		Description:         description,
+		Frequency:           frequency,
+		NextRunAt:           startAt,
+		EndsAt:              endsAt,
+		Status:              models.StandingOrderStatusActive,
+	}
+	if err := uc.repos.StandingOrder.Create(ctx, order); err != nil {
+		return nil, fmt.Errorf("failed to create standing order: %w", err)
+	}
+
+	return order, nil
+}
+
+func (uc *standingOrderUseCase) ListStandingOrders(ctx context.Context, userID uint) ([]models.StandingOrder, error) {
+	return uc.repos.StandingOrder.ListByUserID(ctx, userID)
+}
+
+func (uc *standingOrderUseCase) CancelStandingOrder(ctx context.Context, userID, orderID uint) (*models.StandingOrder, error) {
+	order, err := uc.getOwnedOrder(ctx, userID, orderID)
+	if err != nil {
+		return nil, err
+	}
+	if !order.IsActive() {
+		return nil, apperrors.ErrStandingOrderInactive
+	}
+
+	if err := uc.repos.StandingOrder.UpdateStatus(ctx, order.ID, models.StandingOrderStatusCancelled); err != nil {
+		return nil, fmt.Errorf("failed to cancel standing order: %w", err)
+	}
+	order.Status = models.StandingOrderStatusCancelled
+	return order, nil
+}
+
+func (uc *standingOrderUseCase) ListRuns(ctx context.Context, userID, orderID uint, page, pageSize int) ([]models.StandingOrderRun, error) {
+	if _, err := uc.getOwnedOrder(ctx, userID, orderID); err != nil {
+		return nil, err
+	}
+	if page < 1 {
+		page = 1
+	}
+	if pageSize < 1 || pageSize > 100 {
+		pageSize = 20
+	}
+	return uc.repos.StandingOrder.ListRuns(ctx, orderID, (page-1)*pageSize, pageSize)
+}
+
+// ProcessDue creates occurrences for orders that have reached their next run and attempts every
+// occurrence whose retry time has come; it returns the number of attempts made
+func (uc *standingOrderUseCase) ProcessDue(ctx context.Context, now time.Time) (int, error) {
+	if err := uc.scheduleDue(ctx, now); err != nil {
+		return 0, err
+	}
+
+	runs, err := uc.repos.StandingOrder.GetDueRuns(ctx, now, standingOrderBatchSize)
+	if err != nil {
+		return 0, fmt.Errorf("failed to load due standing order runs: %w", err)
+	}
+
+	for i := range runs {
+		uc.attempt(ctx, &runs[i], now)
+	}
+	return len(runs), nil
+}
+
+// scheduleDue records the next occurrence of each due order and moves the order forward
+func (uc *standingOrderUseCase) scheduleDue(ctx context.Context, now time.Time) error {
+	orders, err := uc.repos.StandingOrder.GetDue(ctx, now, standingOrderBatchSize)
+	if err != nil {
+		return fmt.Errorf("failed to load due standing orders: %w", err)
+	}
+
+	for _, order := range orders {
+		scheduled := order.NextRunAt
+		next := order.NextOccurrence(scheduled)
+		status := models.StandingOrderStatusActive
+		if order.EndsAt != nil && next.After(*order.EndsAt) {
+			status = models.StandingOrderStatusCompleted
+		}
+
+		advanced, err := uc.repos.StandingOrder.Advance(ctx, order.ID, scheduled, next, status)
+		if err != nil {
+			log.Printf("Failed to advance standing order %d: %v", order.ID, err)
+			continue
+		}
+		if !advanced {
+			continue
+		}
+
+		run := &models.StandingOrderRun{
+			StandingOrderID: order.ID,
+			ScheduledFor:    scheduled,
+			Status:          models.StandingOrderRunPending,
+			NextAttemptAt:   &now,
+		}
+		if err := uc.repos.StandingOrder.CreateRun(ctx, run); err != nil {
+			log.Printf("Failed to schedule standing order %d for %s: %v", order.ID, scheduled.Format(time.RFC3339), err)
+		}
+	}
+	return nil
+}
+
+// attempt tries to pay one occurrence. A wallet that cannot cover it is retried with backoff until
+// the grace period runs out; any other failure is final
+func (uc *standingOrderUseCase) attempt(ctx context.Context, run *models.StandingOrderRun, now time.Time) {
+	order := run.StandingOrder
+	run.Attempts++
+
+	if order.Status == models.StandingOrderStatusCancelled {
+		uc.finish(ctx, run, now, models.StandingOrderRunFailed, "standing order cancelled")
+		return
+	}
+
+	// Checking the balance first keeps retries from raising a failed-transaction alert every attempt
+	wallet, err := uc.walletUseCase.GetWallet(ctx, order.WalletID)
+	if err != nil {
+		uc.fail(ctx, run, &order, now, apperrors.ErrWalletNotFound)
+		return
+	}
+	tier, err := resolveWalletTier(ctx, uc.repos, wallet)
+	if err != nil {
+		uc.retryOrFail(ctx, run, &order, now, err)
+		return
+	}
+	required := order.Amount.Add(tier.TransferFee(order.Amount))
+	if !wallet.CanDebit(required) {
+		uc.retryOrFail(ctx, run, &order, now, apperrors.ErrInsufficientFunds.Withf("insufficient funds: available=%s, required=%s",
+			wallet.Balance.StringFixed(2), required.StringFixed(2)))
+		return
+	}
+
+	reference := "SO-" + strconv.FormatUint(uint64(run.ID), 10)
+	description := fmt.Sprintf("Standing order #%d", order.ID)
+	if order.Description != "" {
+		description = fmt.Sprintf("%s: %s", description, order.Description)
+	}
+
+	outTx, _, err := uc.walletUseCase.TransferFunds(ctx, order.WalletID, order.DestinationWalletID, order.Amount, reference, description, nil)
+	if err != nil && errors.Is(err, apperrors.ErrDuplicateReference) {
+		// A previous attempt transferred but did not record its outcome
+		outTx, err = uc.repos.Transaction.GetByReference(ctx, reference)
+	}
+	if err != nil {
+		uc.fail(ctx, run, &order, now, err)
+		return
+	}
+
+	run.TransactionID = &outTx.ID
+	uc.finish(ctx, run, now, models.StandingOrderRunSucceeded, "")
+}
+
+func (uc *standingOrderUseCase) retryOrFail(ctx context.Context, run *models.StandingOrderRun, order *models.StandingOrder, now time.Time, cause error) {
+	next := now.Add(uc.retry.Backoff(run.Attempts))
+	if next.After(run.ScheduledFor.Add(uc.retry.GracePeriod)) {
+		uc.fail(ctx, run, order, now, cause)
+		return
+	}
+
+	run.Status = models.StandingOrderRunRetrying
+	run.NextAttemptAt = &next
+	run.LastError = cause.Error()
+	if err := uc.repos.StandingOrder.UpdateRun(ctx, run); err != nil {
+		log.Printf("Failed to schedule retry of standing order run %d: %v", run.ID, err)
+	}
+}
+
+// fail marks the occurrence failed and tells the user it will not be paid
+func (uc *standingOrderUseCase) fail(ctx context.Context, run *models.StandingOrderRun, order *models.StandingOrder, now time.Time, cause error) {
+	uc.finish(ctx, run, now, models.StandingOrderRunFailed, cause.Error())
+
+	uc.publisher.Publish(events.Event{
+		Type:       events.EventStandingOrderFailed,
+		UserID:     order.UserID,
+		WalletID:   order.WalletID,
+		Reference:  "SO-" + strconv.FormatUint(uint64(run.ID), 10),
+		Amount:     order.Amount,
+		Currency:   order.Currency,
+		Reason:     cause.Error(),
+		OccurredAt: now,
+	})
+}
+
+func (uc *standingOrderUseCase) finish(ctx context.Context, run *models.StandingOrderRun, now time.Time, status models.StandingOrderRunStatus, lastError string) {
+	run.Status = status
+	run.NextAttemptAt = nil
+	run.LastError = lastError
+	run.CompletedAt = &now
+	if err := uc.repos.StandingOrder.UpdateRun(ctx, run); err != nil {
+		log.Printf("Failed to update standing order run %d: %v", run.ID, err)
+	}
+}
+
+// getOwnedOrder loads a standing order of the user; orders of other users are reported as not found
+func (uc *standingOrderUseCase) getOwnedOrder(ctx context.Context, userID, orderID uint) (*models.StandingOrder, error) {
+	order, err := uc.repos.StandingOrder.GetByID(ctx, orderID)
+	if err != nil || order.UserID != userID {
+		return nil, apperrors.ErrStandingOrderNotFound
+	}
+	return order, nil
+}
@@ -0,0 +1,91 @@
+package usecases
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/limistah/wallet-service/internal/events"
+	"github.com/limistah/wallet-service/internal/models"
+	"github.com/limistah/wallet-service/internal/repositories/memory"
+	"github.com/shopspring/decimal"
+)
+
+// recordingPublisher keeps every published event
+type recordingPublisher struct {
+	events []events.Event
+}
+
+func (p *recordingPublisher) Publish(event events.Event) {
+	p.events = append(p.events, event)
+}
+
+func TestStandingOrderUseCase_RetriesUntilGracePeriod(t *testing.T) {
+	repos, _ := setupTestEnvironment()
+	orderRepo := memory.NewStandingOrderRepository()
+	repos.StandingOrder = orderRepo
+
+	repos.Wallet.Create(context.Background(), &models.Wallet{ID: 2, UserID: 2, Balance: decimal.NewFromInt(20), Currency: "USD", Status: models.WalletStatusActive})
+	repos.Wallet.Create(context.Background(), &models.Wallet{ID: 3, UserID: 3, Currency: "USD", Status: models.WalletStatusActive})
+	repos.User.Create(context.Background(), &models.User{ID: 2, Email: "payer@example.com"})
+
+	walletUC := &transferringWalletUseCase{wallets: repos.Wallet.(*memory.WalletRepository)}
+	publisher := &recordingPublisher{}
+	policy := RetryPolicy{InitialBackoff: time.Hour, MaxBackoff: 2 * time.Hour, GracePeriod: 4 * time.Hour}
+	orderUC := NewStandingOrderUseCase(repos, walletUC, WithEventPublisher(publisher), WithStandingOrderRetry(policy))
+
+	if _, err := orderUC.CreateStandingOrder(context.Background(), 2, 2, decimal.NewFromInt(50), models.StandingOrderMonthly, time.Time{}, nil, "", ""); err == nil || err.Error() != "cannot transfer to the same wallet" {
+		t.Errorf("Expected transfers to the same wallet to be rejected, got %v", err)
+	}
+
+	start := time.Now()
+	order, err := orderUC.CreateStandingOrder(context.Background(), 2, 3, decimal.NewFromInt(50), models.StandingOrderMonthly, start, nil, "Rent", "")
+	if err != nil {
+		t.Fatalf("Unexpected error creating standing order: %v", err)
+	}
+
+	// The wallet holds 20 of the 50 due: the occurrence is retried after 1h, then 2h, and fails
+	// once the next retry would fall outside the 4h grace period
+	for _, offset := range []time.Duration{0, time.Hour, 3 * time.Hour} {
+		if _, err := orderUC.ProcessDue(context.Background(), start.Add(offset)); err != nil {
+			t.Fatalf("Unexpected error processing standing orders: %v", err)
+		}
+	}
+
+	runs, _ := orderUC.ListRuns(context.Background(), 2, order.ID, 1, 20)
+	if len(runs) != 1 {
+		t.Fatalf("Expected one occurrence, got %d", len(runs))
+	}
+	if runs[0].Status != models.StandingOrderRunFailed || runs[0].Attempts != 3 {
+		t.Errorf("Expected the occurrence to fail after 3 attempts, got %s after %d", runs[0].Status, runs[0].Attempts)
+	}
+	if len(walletUC.transfers) != 0 {
+		t.Errorf("Expected no transfers, got %d", len(walletUC.transfers))
+	}
+	if len(publisher.events) != 1 || publisher.events[0].Type != events.EventStandingOrderFailed {
+		t.Errorf("Expected one standing order failure event, got %+v", publisher.events)
+	}
+
+	stored, _ := repos.StandingOrder.GetByID(context.Background(), order.ID)
+	if !stored.IsActive() || !stored.NextRunAt.Equal(start.AddDate(0, 1, 0)) {
+		t.Errorf("Expected the order to move on to next month, got %+v", stored)
+	}
+
+	// Next month the wallet has been topped up and the occurrence is paid on the first attempt
+	wallet, _ := repos.Wallet.GetByID(context.Background(), 2)
+	wallet.Balance = decimal.NewFromInt(100)
+	repos.Wallet.Update(context.Background(), wallet)
+	if _, err := orderUC.ProcessDue(context.Background(), stored.NextRunAt); err != nil {
+		t.Fatalf("Unexpected error processing standing orders: %v", err)
+	}
+	if len(walletUC.transfers) != 1 {
+		t.Fatalf("Expected one transfer, got %d", len(walletUC.transfers))
+	}
+
+	if _, err := orderUC.CancelStandingOrder(context.Background(), 3, order.ID); err == nil || err.Error() != "standing order not found" {
+		t.Errorf("Expected other users to be unable to cancel, got %v", err)
+	}
+	if _, err := orderUC.CancelStandingOrder(context.Background(), 2, order.ID); err != nil {
+		t.Errorf("Unexpected error cancelling standing order: %v", err)
+	}
+}
@@ -0,0 +1,293 @@
+package usecases
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/csv"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/limistah/wallet-service/internal/apperrors"
+	"github.com/limistah/wallet-service/internal/models"
+	"github.com/limistah/wallet-service/internal/repositories"
+	"github.com/shopspring/decimal"
+	"gorm.io/gorm"
+)
+
+type statementUseCase struct {
+	repos *repositories.Repositories
+}
+
+// NewStatementUseCase creates a new provider statement use case
+func NewStatementUseCase(repos *repositories.Repositories) StatementUseCase {
+	return &statementUseCase{repos: repos}
+}
+
+// Import reads a provider settlement file and matches each line to a deposit or payout of the
+// provider by reference, then by amount, currency and status. Lines that do not match are kept as
+// exceptions for review
+func (uc *statementUseCase) Import(ctx context.Context, provider, fileName string, uploadedBy uint, content []byte) (*models.ProviderStatement, error) {
+	provider = strings.ToLower(strings.TrimSpace(provider))
+	if provider == "" {
+		return nil, apperrors.ErrValidation.Withf("provider is required")
+	}
+
+	sum := sha256.Sum256(content)
+	checksum := hex.EncodeToString(sum[:])
+	if _, err := uc.repos.ProviderStatement.GetByChecksum(ctx, provider, checksum); err == nil {
+		return nil, apperrors.ErrDuplicateStatement
+	} else if !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, fmt.Errorf("error checking statements: %w", err)
+	}
+
+	lines, err := parseStatementCSV(bytes.NewReader(content))
+	if err != nil {
+		return nil, err
+	}
+
+	references := make([]string, len(lines))
+	for i := range lines {
+		references[i] = lines[i].Reference
+	}
+	matched, err := uc.repos.StatementLine.MatchedReferences(ctx, provider, references)
+	if err != nil {
+		return nil, fmt.Errorf("error checking matched statement lines: %w", err)
+	}
+	seen := make(map[string]bool, len(lines)+len(matched))
+	for _, reference := range matched {
+		seen[reference] = true
+	}
+
+	statement := &models.ProviderStatement{
+		Provider:   provider,
+		FileName:   fileName,
+		Checksum:   checksum,
+		UploadedBy: uploadedBy,
+		LineCount:  len(lines),
+	}
+	for i := range lines {
+		line := &lines[i]
+		line.Provider = provider
+		if seen[line.Reference] {
+			line.Flag(models.StatementExceptionDuplicate)
+		} else {
+			seen[line.Reference] = true
+			if err := uc.matchLine(ctx, provider, line); err != nil {
+				return nil, err
+			}
+		}
+		if line.Status == models.StatementLineStatusMatched {
+			statement.MatchedCount++
+		} else {
+			statement.ExceptionCount++
+		}
+	}
+	statement.Lines = lines
+
+	if err := uc.repos.ProviderStatement.Create(ctx, statement); err != nil {
+		return nil, fmt.Errorf("failed to save statement: %w", err)
+	}
+	return statement, nil
+}
+
+// matchLine looks the line's reference up among the provider's deposits for credits and payouts for
+// debits, and compares the line with the item found
+func (uc *statementUseCase) matchLine(ctx context.Context, provider string, line *models.StatementLine) error {
+	if line.Type != models.TransactionTypeDebit {
+		deposit, err := uc.repos.Deposit.GetByExternalReference(ctx, provider, line.Reference)
+		if err == nil {
+			line.DepositID = &deposit.ID
+			compareStatementLine(line, deposit.Amount, deposit.Currency, deposit.Status == models.DepositStatusCompleted)
+			return nil
+		}
+		if !errors.Is(err, gorm.ErrRecordNotFound) {
+			return fmt.Errorf("error looking up deposit: %w", err)
+		}
+	}
+
+	if line.Type != models.TransactionTypeCredit {
+		payout, err := uc.findPayout(ctx, provider, line.Reference)
+		if err == nil {
+			line.PayoutID = &payout.ID
+			compareStatementLine(line, payout.Amount, payout.Currency, payout.Status == models.PayoutStatusCompleted)
+			return nil
+		}
+		if !errors.Is(err, gorm.ErrRecordNotFound) {
+			return fmt.Errorf("error looking up payout: %w", err)
+		}
+	}
+
+	line.Flag(models.StatementExceptionNotFound)
+	return nil
+}
+
+// findPayout finds a payout by the provider's reference, or by the reference sent to the provider
+func (uc *statementUseCase) findPayout(ctx context.Context, provider, reference string) (*models.Payout, error) {
+	payout, err := uc.repos.Payout.GetByProviderReference(ctx, provider, reference)
+	if !errors.Is(err, gorm.ErrRecordNotFound) {
+		return payout, err
+	}
+	payout, err = uc.repos.Payout.GetByReference(ctx, reference)
+	if err != nil {
+		return nil, err
+	}
+	if payout.Provider != provider {
+		return nil, gorm.ErrRecordNotFound
+	}
+	return payout, nil
+}
+
+func (uc *statementUseCase) ListStatements(ctx context.Context, provider string, page, pageSize int) ([]models.ProviderStatement, error) {
+	if page < 1 {
+		page = 1
+	}
+	if pageSize < 1 || pageSize > 100 {
+		pageSize = 20
+	}
+	return uc.repos.ProviderStatement.List(ctx, strings.ToLower(provider), (page-1)*pageSize, pageSize)
+}
+
+// GetStatement returns a statement with its lines
+func (uc *statementUseCase) GetStatement(ctx context.Context, statementID uint) (*models.ProviderStatement, error) {
+	statement, err := uc.repos.ProviderStatement.GetByID(ctx, statementID)
+	if err != nil {
+		return nil, apperrors.ErrStatementNotFound
+	}
+	return statement, nil
+}
+
+func (uc *statementUseCase) ListLines(ctx context.Context, filter repositories.StatementLineFilter, page, pageSize int) ([]models.StatementLine, error) {
+	if page < 1 {
+		page = 1
+	}
+	if pageSize < 1 || pageSize > 100 {
+		pageSize = 20
+	}
+	filter.Provider = strings.ToLower(filter.Provider)
+	return uc.repos.StatementLine.List(ctx, filter, (page-1)*pageSize, pageSize)
+}
+
+// ResolveException closes a reviewed exception with a note explaining the outcome
+func (uc *statementUseCase) ResolveException(ctx context.Context, lineID, adminID uint, note string) (*models.StatementLine, error) {
+	if note == "" {
+		return nil, apperrors.ErrValidation.Withf("note is required")
+	}
+	line, err := uc.repos.StatementLine.GetByID(ctx, lineID)
+	if err != nil {
+		return nil, apperrors.ErrStatementLineNotFound
+	}
+	if line.Status != models.StatementLineStatusException {
+		return nil, apperrors.ErrStatementLineNotPending
+	}
+
+	now := time.Now()
+	line.Status = models.StatementLineStatusResolved
+	line.Notes = note
+	line.ResolvedBy = &adminID
+	line.ResolvedAt = &now
+	if err := uc.repos.StatementLine.Update(ctx, line); err != nil {
+		return nil, fmt.Errorf("failed to update statement line: %w", err)
+	}
+	return line, nil
+}
+
+// compareStatementLine marks a line matched when it agrees with the internal item it references
+func compareStatementLine(line *models.StatementLine, amount decimal.Decimal, currency string, completed bool) {
+	switch {
+	case !line.Amount.Equal(amount) || (line.Currency != "" && !strings.EqualFold(line.Currency, currency)):
+		line.Flag(models.StatementExceptionAmountMismatch)
+	case !completed:
+		line.Flag(models.StatementExceptionStatusMismatch)
+	default:
+		line.Status = models.StatementLineStatusMatched
+		line.Reason = ""
+	}
+}
+
+// parseStatementCSV reads statement lines from a CSV file with a header row. The reference and
+// amount columns are required; currency, type (CREDIT or DEBIT) and date are optional. A negative
+// amount without a type is read as a debit
+func parseStatementCSV(r io.Reader) ([]models.StatementLine, error) {
+	reader := csv.NewReader(r)
+	reader.TrimLeadingSpace = true
+	reader.FieldsPerRecord = -1
+
+	header, err := reader.Read()
+	if err == io.EOF {
+		return nil, apperrors.ErrValidation.Withf("statement file is empty")
+	}
+	if err != nil {
+		return nil, apperrors.ErrValidation.Withf("invalid statement file: %v", err)
+	}
+	columns := make(map[string]int, len(header))
+	for i, name := range header {
+		columns[strings.ToLower(strings.TrimSpace(strings.TrimPrefix(name, "\ufeff")))] = i
+	}
+	for _, required := range []string{"reference", "amount"} {
+		if _, ok := columns[required]; !ok {
+			return nil, apperrors.ErrValidation.Withf("statement file has no %s column", required)
+		}
+	}
+	field := func(record []string, name string) string {
+		if i, ok := columns[name]; ok && i < len(record) {
+			return strings.TrimSpace(record[i])
+		}
+		return ""
+	}
+
+	var lines []models.StatementLine
+	for lineNumber := 2; ; lineNumber++ {
+		record, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, apperrors.ErrValidation.Withf("invalid statement file: %v", err)
+		}
+
+		line := models.StatementLine{
+			LineNumber: lineNumber,
+			Reference:  field(record, "reference"),
+			Currency:   strings.ToUpper(field(record, "currency")),
+			Type:       models.TransactionType(strings.ToUpper(field(record, "type"))),
+		}
+		if line.Reference == "" {
+			return nil, apperrors.ErrValidation.Withf("line %d has no reference", lineNumber)
+		}
+		amount, err := decimal.NewFromString(field(record, "amount"))
+		if err != nil || amount.IsZero() {
+			return nil, apperrors.ErrValidation.Withf("line %d has an invalid amount", lineNumber)
+		}
+		switch line.Type {
+		case "":
+			if amount.IsNegative() {
+				line.Type = models.TransactionTypeDebit
+			}
+		case models.TransactionTypeCredit, models.TransactionTypeDebit:
+		default:
+			return nil, apperrors.ErrValidation.Withf("line %d has an invalid type; use CREDIT or DEBIT", lineNumber)
+		}
+		line.Amount = amount.Abs()
+
+		if value := field(record, "date"); value != "" {
+			date, err := time.Parse("2006-01-02", value)
+			if err != nil {
+				if date, err = time.Parse(time.RFC3339, value); err != nil {
+					return nil, apperrors.ErrValidation.Withf("line %d has an invalid date; use YYYY-MM-DD", lineNumber)
+				}
+			}
+			line.ValueDate = &date
+		}
+		lines = append(lines, line)
+	}
+
+	if len(lines) == 0 {
+		return nil, apperrors.ErrValidation.Withf("statement file has no lines")
+	}
+	return lines, nil
+}
@@ -0,0 +1,141 @@
+package usecases
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/limistah/wallet-service/internal/apperrors"
+	"github.com/limistah/wallet-service/internal/models"
+	"github.com/limistah/wallet-service/internal/repositories/memory"
+	"github.com/shopspring/decimal"
+)
+
+func setupStatementTest() (*memory.DepositRepository, *memory.PayoutRepository, *memory.StatementLineRepository, StatementUseCase) {
+	repos, _ := setupTestEnvironment()
+	deposits := memory.NewDepositRepository()
+	payouts := memory.NewPayoutRepository()
+	lines := memory.NewStatementLineRepository()
+	repos.Deposit = deposits
+	repos.Payout = payouts
+	repos.StatementLine = lines
+	repos.ProviderStatement = memory.NewProviderStatementRepository(lines)
+	return deposits, payouts, lines, NewStatementUseCase(repos)
+}
+
+func TestParseStatementCSV(t *testing.T) {
+	lines, err := parseStatementCSV(strings.NewReader("Reference,Amount,Currency,Type,Date\nPSK-1,100.50,ngn,,2023-01-01\nTRF-9,-40,NGN,,\nPSK-2, 12 ,NGN,credit,\n"))
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(lines) != 3 {
+		t.Fatalf("Expected 3 lines, got %d", len(lines))
+	}
+	if lines[0].LineNumber != 2 || lines[0].Currency != "NGN" || lines[0].ValueDate == nil || lines[0].Type != "" {
+		t.Errorf("Unexpected first line: %+v", lines[0])
+	}
+	if lines[1].Type != models.TransactionTypeDebit || !lines[1].Amount.Equal(decimal.NewFromInt(40)) {
+		t.Errorf("Expected a negative amount to be read as a debit of 40, got: %+v", lines[1])
+	}
+	if lines[2].Type != models.TransactionTypeCredit || !lines[2].Amount.Equal(decimal.NewFromInt(12)) {
+		t.Errorf("Expected a credit of 12, got: %+v", lines[2])
+	}
+
+	invalid := []struct {
+		name    string
+		content string
+	}{
+		{"empty file", ""},
+		{"missing amount column", "reference,currency\nPSK-1,NGN\n"},
+		{"header only", "reference,amount\n"},
+		{"missing reference", "reference,amount\n,10\n"},
+		{"invalid amount", "reference,amount\nPSK-1,ten\n"},
+		{"invalid type", "reference,amount,type\nPSK-1,10,REFUND\n"},
+		{"invalid date", "reference,amount,date\nPSK-1,10,01/01/2023\n"},
+	}
+	for _, tc := range invalid {
+		t.Run(tc.name, func(t *testing.T) {
+			if _, err := parseStatementCSV(strings.NewReader(tc.content)); !errors.Is(err, apperrors.ErrValidation) {
+				t.Errorf("Expected validation error, got: %v", err)
+			}
+		})
+	}
+}
+
+func TestStatementUseCase_Import(t *testing.T) {
+	deposits, payouts, _, statementUC := setupStatementTest()
+	deposits.Create(context.Background(), &models.Deposit{Provider: "paystack", ExternalReference: "PSK-1", Amount: decimal.NewFromInt(100), Currency: "NGN", Status: models.DepositStatusCompleted})
+	deposits.Create(context.Background(), &models.Deposit{Provider: "paystack", ExternalReference: "PSK-2", Amount: decimal.NewFromInt(50), Currency: "NGN", Status: models.DepositStatusCompleted})
+	deposits.Create(context.Background(), &models.Deposit{Provider: "paystack", ExternalReference: "PSK-3", Amount: decimal.NewFromInt(25), Currency: "NGN", Status: models.DepositStatusPending})
+	deposits.Create(context.Background(), &models.Deposit{Provider: "flutterwave", ExternalReference: "PSK-4", Amount: decimal.NewFromInt(10), Currency: "NGN", Status: models.DepositStatusCompleted})
+	payouts.Create(context.Background(), &models.Payout{Provider: "paystack", Reference: "WTH-1", ProviderReference: "TRF-1", Amount: decimal.NewFromInt(30), Currency: "NGN", Status: models.PayoutStatusCompleted})
+
+	content := "reference,amount,currency\nPSK-1,100,NGN\nPSK-2,49.50,NGN\nPSK-3,25,NGN\nPSK-4,10,NGN\nTRF-1,-30,NGN\nPSK-1,100,NGN\n"
+	statement, err := statementUC.Import(context.Background(), "Paystack", "paystack.csv", 3, []byte(content))
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if statement.LineCount != 6 || statement.MatchedCount != 2 || statement.ExceptionCount != 4 {
+		t.Errorf("Expected 2 of 6 lines matched, got %d of %d with %d exceptions", statement.MatchedCount, statement.LineCount, statement.ExceptionCount)
+	}
+
+	expected := []struct {
+		status models.StatementLineStatus
+		reason models.StatementExceptionReason
+	}{
+		{models.StatementLineStatusMatched, ""},
+		{models.StatementLineStatusException, models.StatementExceptionAmountMismatch},
+		{models.StatementLineStatusException, models.StatementExceptionStatusMismatch},
+		{models.StatementLineStatusException, models.StatementExceptionNotFound}, // Another provider's deposit
+		{models.StatementLineStatusMatched, ""},
+		{models.StatementLineStatusException, models.StatementExceptionDuplicate},
+	}
+	for i, want := range expected {
+		line := statement.Lines[i]
+		if line.Status != want.status || line.Reason != want.reason {
+			t.Errorf("Line %d: expected %s %s, got %s %s", line.LineNumber, want.status, want.reason, line.Status, line.Reason)
+		}
+	}
+	if statement.Lines[4].PayoutID == nil {
+		t.Errorf("Expected the debit to reference the payout")
+	}
+
+	if _, err := statementUC.Import(context.Background(), "paystack", "again.csv", 3, []byte(content)); !errors.Is(err, apperrors.ErrDuplicateStatement) {
+		t.Errorf("Expected the same file to be refused, got: %v", err)
+	}
+
+	next, err := statementUC.Import(context.Background(), "paystack", "next.csv", 3, []byte("reference,amount\nPSK-1,100\n"))
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if next.Lines[0].Reason != models.StatementExceptionDuplicate {
+		t.Errorf("Expected a reference matched by an earlier statement to be a duplicate, got: %+v", next.Lines[0])
+	}
+}
+
+func TestStatementUseCase_ResolveException(t *testing.T) {
+	_, _, lines, statementUC := setupStatementTest()
+	exception := &models.StatementLine{Provider: "paystack", Reference: "PSK-9", Amount: decimal.NewFromInt(5), Status: models.StatementLineStatusException, Reason: models.StatementExceptionNotFound}
+	lines.Create(exception)
+	matched := &models.StatementLine{Provider: "paystack", Reference: "PSK-1", Amount: decimal.NewFromInt(100), Status: models.StatementLineStatusMatched}
+	lines.Create(matched)
+
+	if _, err := statementUC.ResolveException(context.Background(), exception.ID, 3, ""); !errors.Is(err, apperrors.ErrValidation) {
+		t.Errorf("Expected a note to be required, got: %v", err)
+	}
+	if _, err := statementUC.ResolveException(context.Background(), 99, 3, "checked"); !errors.Is(err, apperrors.ErrStatementLineNotFound) {
+		t.Errorf("Expected statement line not found, got: %v", err)
+	}
+	if _, err := statementUC.ResolveException(context.Background(), matched.ID, 3, "checked"); !errors.Is(err, apperrors.ErrStatementLineNotPending) {
+		t.Errorf("Expected matched lines to be refused, got: %v", err)
+	}
+
+	resolved, err := statementUC.ResolveException(context.Background(), exception.ID, 3, "credit parked in suspense")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if resolved.Status != models.StatementLineStatusResolved || resolved.ResolvedBy == nil || *resolved.ResolvedBy != 3 {
+		t.Errorf("Expected the line to be resolved by admin 3, got: %+v", resolved)
+	}
+}
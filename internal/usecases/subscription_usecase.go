@@ -0,0 +1,444 @@
+package usecases
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/limistah/wallet-service/internal/apperrors"
+	"github.com/limistah/wallet-service/internal/events"
+	"github.com/limistah/wallet-service/internal/models"
+	"github.com/limistah/wallet-service/internal/repositories"
+	"github.com/limistah/wallet-service/internal/utils"
+	"github.com/shopspring/decimal"
+	"gorm.io/gorm"
+)
+
+// subscriptionBillingBatchSize bounds the subscriptions charged in one run of the billing job
+const subscriptionBillingBatchSize = 100
+
+// DunningPolicy controls how a subscription whose charge failed is retried
+type DunningPolicy struct {
+	// RetryInterval is the wait between attempts to charge a period that failed
+	RetryInterval time.Duration
+	// MaxAttempts failed attempts in a row at charging a period suspend the subscription
+	MaxAttempts int
+}
+
+// DefaultDunningPolicy retries a failed charge daily and suspends the subscription after 4 attempts
+func DefaultDunningPolicy() DunningPolicy {
+	return DunningPolicy{
+		RetryInterval: 24 * time.Hour,
+		MaxAttempts:   4,
+	}
+}
+
+type subscriptionUseCase struct {
+	repos         *repositories.Repositories
+	walletUseCase WalletUseCase
+	publisher     events.EventPublisher
+	dunning       DunningPolicy
+	now           func() time.Time
+}
+
+// NewSubscriptionUseCase creates a new subscription use case
+func NewSubscriptionUseCase(repos *repositories.Repositories, walletUseCase WalletUseCase, opts ...Option) SubscriptionUseCase {
+	o := newOptions(opts)
+	return &subscriptionUseCase{
+		repos:         repos,
+		walletUseCase: walletUseCase,
+		publisher:     o.publisher,
+		dunning:       o.subscriptionDunning,
+		now:           time.Now,
+	}
+}
+
+// CreatePlan lets a merchant charge subscribers amount every interval, paid into the merchant's wallet
+func (uc *subscriptionUseCase) CreatePlan(ctx context.Context, merchantID uint, name, description string, amount decimal.Decimal, interval models.BillingInterval) (*models.SubscriptionPlan, error) {
+	name = strings.TrimSpace(name)
+	if name == "" {
+		return nil, apperrors.ErrValidation.Withf("plan name is required")
+	}
+	if !amount.IsPositive() || !amount.Equal(amount.Round(2)) {
+		return nil, apperrors.ErrInvalidAmount.Withf("amount must be positive with at most 2 decimals")
+	}
+	if !models.IsValidBillingInterval(interval) {
+		return nil, apperrors.ErrValidation.Withf("invalid billing interval")
+	}
+
+	wallet, err := uc.walletUseCase.GetWalletByUserID(ctx, merchantID)
+	if err != nil {
+		return nil, apperrors.ErrWalletNotFound
+	}
+	if !wallet.IsActive() {
+		return nil, apperrors.ErrWalletInactive
+	}
+
+	plan := &models.SubscriptionPlan{
+		MerchantID:  merchantID,
+		WalletID:    wallet.ID,
+		Name:        name,
+		Description: strings.TrimSpace(description),
+		Amount:      amount,
+		Currency:    wallet.Currency,
+		Interval:    interval,
+		Active:      true,
+	}
+	if err := uc.repos.Subscription.CreatePlan(ctx, plan); err != nil {
+		return nil, fmt.Errorf("failed to create subscription plan: %w", err)
+	}
+	return plan, nil
+}
+
+func (uc *subscriptionUseCase) ListPlans(ctx context.Context, page, pageSize int) ([]models.SubscriptionPlan, error) {
+	if page < 1 {
+		page = 1
+	}
+	if pageSize < 1 || pageSize > 100 {
+		pageSize = 20
+	}
+	return uc.repos.Subscription.ListActivePlans(ctx, (page-1)*pageSize, pageSize)
+}
+
+func (uc *subscriptionUseCase) ListMerchantPlans(ctx context.Context, merchantID uint) ([]models.SubscriptionPlan, error) {
+	return uc.repos.Subscription.ListPlansByMerchant(ctx, merchantID)
+}
+
+func (uc *subscriptionUseCase) GetPlan(ctx context.Context, planID uint) (*models.SubscriptionPlan, error) {
+	plan, err := uc.repos.Subscription.GetPlan(ctx, planID)
+	if err != nil {
+		return nil, apperrors.ErrSubscriptionPlanNotFound
+	}
+	return plan, nil
+}
+
+// ArchivePlan stops a plan from taking new subscribers; its subscriptions keep being billed
+func (uc *subscriptionUseCase) ArchivePlan(ctx context.Context, merchantID, planID uint) (*models.SubscriptionPlan, error) {
+	plan, err := uc.merchantPlan(ctx, merchantID, planID)
+	if err != nil {
+		return nil, err
+	}
+	if err := uc.repos.Subscription.ArchivePlan(ctx, plan.ID); err != nil {
+		return nil, fmt.Errorf("failed to archive subscription plan: %w", err)
+	}
+	plan.Active = false
+	return plan, nil
+}
+
+func (uc *subscriptionUseCase) ListPlanSubscriptions(ctx context.Context, merchantID, planID uint, page, pageSize int) ([]models.Subscription, error) {
+	if _, err := uc.merchantPlan(ctx, merchantID, planID); err != nil {
+		return nil, err
+	}
+	if page < 1 {
+		page = 1
+	}
+	if pageSize < 1 || pageSize > 100 {
+		pageSize = 20
+	}
+	return uc.repos.Subscription.ListByPlan(ctx, planID, (page-1)*pageSize, pageSize)
+}
+
+// Subscribe charges the user's wallet for the first period of the plan and subscribes the user to
+// it; nothing is created when the first charge fails
+func (uc *subscriptionUseCase) Subscribe(ctx context.Context, userID, planID uint) (*models.Subscription, error) {
+	plan, err := uc.GetPlan(ctx, planID)
+	if err != nil {
+		return nil, err
+	}
+	if !plan.Active {
+		return nil, apperrors.ErrSubscriptionPlanArchived
+	}
+	if plan.MerchantID == userID {
+		return nil, apperrors.ErrSelfPayment.Withf("cannot subscribe to your own plan")
+	}
+	if _, err := uc.repos.Subscription.GetOpenByUserAndPlan(ctx, userID, planID); err == nil {
+		return nil, apperrors.ErrSubscriptionExists
+	} else if !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, fmt.Errorf("failed to check subscriptions: %w", err)
+	}
+
+	wallet, err := uc.walletUseCase.GetWalletByUserID(ctx, userID)
+	if err != nil {
+		return nil, apperrors.ErrWalletNotFound
+	}
+	if !wallet.IsActive() {
+		return nil, apperrors.ErrWalletInactive
+	}
+	if wallet.Currency != plan.Currency {
+		return nil, apperrors.ErrCurrencyMismatch.Withf("plan is billed in %s", plan.Currency)
+	}
+
+	now := uc.now()
+	subscription := &models.Subscription{
+		Reference: "SUB-" + utils.GenerateTransactionReference(),
+		PlanID:    plan.ID,
+		UserID:    userID,
+		WalletID:  wallet.ID,
+		Status:    models.SubscriptionStatusActive,
+		PaidUntil: now,
+		Plan:      *plan,
+	}
+	transaction, err := uc.pay(ctx, subscription)
+	if err != nil {
+		return nil, err
+	}
+
+	charge := uc.newCharge(subscription, 1)
+	uc.paid(subscription, charge, transaction)
+	if err := uc.repos.Subscription.Create(ctx, subscription); err != nil {
+		return nil, fmt.Errorf("failed to create subscription after charging %s: %w", charge.Reference, err)
+	}
+	uc.recordCharge(ctx, subscription, charge)
+	return subscription, nil
+}
+
+func (uc *subscriptionUseCase) ListSubscriptions(ctx context.Context, userID uint) ([]models.Subscription, error) {
+	return uc.repos.Subscription.ListByUser(ctx, userID)
+}
+
+// GetSubscription returns a subscription of which the user is the subscriber or the merchant
+func (uc *subscriptionUseCase) GetSubscription(ctx context.Context, userID, subscriptionID uint) (*models.Subscription, error) {
+	subscription, err := uc.repos.Subscription.GetByID(ctx, subscriptionID)
+	if err != nil || (subscription.UserID != userID && subscription.Plan.MerchantID != userID) {
+		return nil, apperrors.ErrSubscriptionNotFound
+	}
+	return subscription, nil
+}
+
+func (uc *subscriptionUseCase) ListCharges(ctx context.Context, userID, subscriptionID uint, page, pageSize int) ([]models.SubscriptionCharge, error) {
+	if _, err := uc.GetSubscription(ctx, userID, subscriptionID); err != nil {
+		return nil, err
+	}
+	if page < 1 {
+		page = 1
+	}
+	if pageSize < 1 || pageSize > 100 {
+		pageSize = 20
+	}
+	return uc.repos.Subscription.ListCharges(ctx, subscriptionID, (page-1)*pageSize, pageSize)
+}
+
+// CancelSubscription stops the billing of a subscription; the period already paid is not refunded
+func (uc *subscriptionUseCase) CancelSubscription(ctx context.Context, userID, subscriptionID uint) (*models.Subscription, error) {
+	subscription, err := uc.ownedSubscription(ctx, userID, subscriptionID)
+	if err != nil {
+		return nil, err
+	}
+	if subscription.Status == models.SubscriptionStatusCancelled {
+		return nil, apperrors.ErrSubscriptionCancelled
+	}
+
+	from := subscription.Status
+	now := uc.now()
+	subscription.Status = models.SubscriptionStatusCancelled
+	subscription.CancelledAt = &now
+	if err := uc.update(ctx, subscription, from); err != nil {
+		return nil, err
+	}
+	return subscription, nil
+}
+
+// ResumeSubscription charges a suspended subscription for a new period starting now and bills it
+// again from there; it stays suspended when the charge fails
+func (uc *subscriptionUseCase) ResumeSubscription(ctx context.Context, userID, subscriptionID uint) (*models.Subscription, error) {
+	subscription, err := uc.ownedSubscription(ctx, userID, subscriptionID)
+	if err != nil {
+		return nil, err
+	}
+	if subscription.Status != models.SubscriptionStatusSuspended {
+		return nil, apperrors.ErrSubscriptionNotSuspended
+	}
+
+	subscription.PaidUntil = uc.now()
+	charge := uc.newCharge(subscription, 1)
+	transaction, err := uc.pay(ctx, subscription)
+	if err != nil {
+		charge.Status = models.SubscriptionChargeFailed
+		charge.Error = err.Error()
+		uc.recordCharge(ctx, subscription, charge)
+		return nil, err
+	}
+
+	uc.paid(subscription, charge, transaction)
+	subscription.SuspendedAt = nil
+	if err := uc.update(ctx, subscription, models.SubscriptionStatusSuspended); err != nil {
+		return nil, err
+	}
+	uc.recordCharge(ctx, subscription, charge)
+	return subscription, nil
+}
+
+// BillDue charges every subscription whose next charge is due, and returns the number of charges
+// attempted. A failed charge is retried after the dunning interval; the subscription is suspended
+// once the attempts run out
+func (uc *subscriptionUseCase) BillDue(ctx context.Context, now time.Time) (int, error) {
+	subscriptions, err := uc.repos.Subscription.ListDue(ctx, now, subscriptionBillingBatchSize)
+	if err != nil {
+		return 0, fmt.Errorf("failed to load due subscriptions: %w", err)
+	}
+
+	for i := range subscriptions {
+		uc.bill(ctx, &subscriptions[i], now)
+	}
+	return len(subscriptions), nil
+}
+
+func (uc *subscriptionUseCase) bill(ctx context.Context, subscription *models.Subscription, now time.Time) {
+	from := subscription.Status
+	charge := uc.newCharge(subscription, subscription.FailedAttempts+1)
+	transaction, err := uc.pay(ctx, subscription)
+	if err == nil {
+		uc.paid(subscription, charge, transaction)
+	} else {
+		charge.Status = models.SubscriptionChargeFailed
+		charge.Error = err.Error()
+		subscription.FailedAttempts++
+		subscription.LastError = err.Error()
+		if subscription.FailedAttempts >= uc.dunning.MaxAttempts {
+			subscription.Status = models.SubscriptionStatusSuspended
+			subscription.SuspendedAt = &now
+		} else {
+			subscription.Status = models.SubscriptionStatusPastDue
+			subscription.NextBillingAt = now.Add(uc.dunning.RetryInterval)
+		}
+	}
+
+	if err := uc.update(ctx, subscription, from); err != nil {
+		log.Printf("Failed to update subscription %s after charge %s: %v", subscription.Reference, charge.Reference, err)
+	}
+	uc.recordCharge(ctx, subscription, charge)
+
+	if charge.Status == models.SubscriptionChargeFailed {
+		uc.publishFailure(subscription, charge, now)
+	}
+}
+
+// pay transfers the plan's amount for the period starting at PaidUntil from the subscriber's wallet
+// to the merchant's. Each period is charged with its own reference, so a period is never paid twice
+func (uc *subscriptionUseCase) pay(ctx context.Context, subscription *models.Subscription) (*models.Transaction, error) {
+	plan := subscription.Plan
+
+	// Checking the balance first keeps retries from raising a failed-transaction alert every attempt
+	wallet, err := uc.walletUseCase.GetWallet(ctx, subscription.WalletID)
+	if err != nil {
+		return nil, apperrors.ErrWalletNotFound
+	}
+	tier, err := resolveWalletTier(ctx, uc.repos, wallet)
+	if err != nil {
+		return nil, err
+	}
+	required := plan.Amount.Add(tier.TransferFee(plan.Amount))
+	if !wallet.CanDebit(required) {
+		return nil, apperrors.ErrInsufficientFunds.Withf("insufficient funds: available=%s, required=%s",
+			wallet.Balance.StringFixed(2), required.StringFixed(2))
+	}
+
+	reference := chargeReference(subscription)
+	description := fmt.Sprintf("Subscription to %s", plan.Name)
+	outTx, _, err := uc.walletUseCase.TransferFunds(ctx, subscription.WalletID, plan.WalletID, plan.Amount, reference, description, nil)
+	if err != nil && errors.Is(err, apperrors.ErrDuplicateReference) {
+		// A previous attempt transferred but did not record its outcome
+		outTx, err = uc.repos.Transaction.GetByReference(ctx, reference)
+		if err == nil && (outTx.Status == models.TransactionStatusFailed || outTx.Status == models.TransactionStatusCancelled) {
+			return nil, apperrors.ErrDuplicateReference.Withf("charge %s was already attempted and did not complete", reference)
+		}
+	}
+	return outTx, err
+}
+
+// paid moves a subscription on to the period after the one charged
+func (uc *subscriptionUseCase) paid(subscription *models.Subscription, charge *models.SubscriptionCharge, transaction *models.Transaction) {
+	charge.Status = models.SubscriptionChargePaid
+	charge.TransactionID = &transaction.ID
+
+	subscription.Status = models.SubscriptionStatusActive
+	subscription.PaidPeriods++
+	subscription.PaidUntil = charge.PeriodEnd
+	subscription.NextBillingAt = charge.PeriodEnd
+	subscription.FailedAttempts = 0
+	subscription.LastError = ""
+}
+
+func (uc *subscriptionUseCase) newCharge(subscription *models.Subscription, attempt int) *models.SubscriptionCharge {
+	return &models.SubscriptionCharge{
+		Reference:   chargeReference(subscription),
+		PeriodStart: subscription.PaidUntil,
+		PeriodEnd:   subscription.Plan.Interval.Next(subscription.PaidUntil),
+		Amount:      subscription.Plan.Amount,
+		Currency:    subscription.Plan.Currency,
+		Attempt:     attempt,
+	}
+}
+
+func (uc *subscriptionUseCase) recordCharge(ctx context.Context, subscription *models.Subscription, charge *models.SubscriptionCharge) {
+	charge.SubscriptionID = subscription.ID
+	if err := uc.repos.Subscription.CreateCharge(ctx, charge); err != nil {
+		log.Printf("Failed to record charge %s of subscription %s: %v", charge.Reference, subscription.Reference, err)
+	}
+}
+
+func (uc *subscriptionUseCase) update(ctx context.Context, subscription *models.Subscription, from models.SubscriptionStatus) error {
+	updated, err := uc.repos.Subscription.Update(ctx, subscription, from)
+	if err != nil {
+		return fmt.Errorf("failed to update subscription: %w", err)
+	}
+	if !updated {
+		return apperrors.ErrConcurrentModification.Withf("subscription changed while it was being updated")
+	}
+	return nil
+}
+
+// publishFailure tells the subscriber that a charge failed, and whether it will be retried
+func (uc *subscriptionUseCase) publishFailure(subscription *models.Subscription, charge *models.SubscriptionCharge, now time.Time) {
+	event := events.Event{
+		Type:      events.EventSubscriptionPaymentFailed,
+		UserID:    subscription.UserID,
+		WalletID:  subscription.WalletID,
+		Reference: charge.Reference,
+		Amount:    charge.Amount,
+		Currency:  charge.Currency,
+		Reason:    charge.Error,
+		Data: map[string]string{
+			"plan":    subscription.Plan.Name,
+			"attempt": strconv.Itoa(charge.Attempt),
+		},
+		OccurredAt: now,
+	}
+	if subscription.Status == models.SubscriptionStatusSuspended {
+		event.Type = events.EventSubscriptionSuspended
+	} else {
+		event.Data["next_attempt_at"] = subscription.NextBillingAt.UTC().Format(time.RFC3339)
+	}
+	uc.publisher.Publish(event)
+}
+
+// merchantPlan loads a plan the merchant owns
+func (uc *subscriptionUseCase) merchantPlan(ctx context.Context, merchantID, planID uint) (*models.SubscriptionPlan, error) {
+	plan, err := uc.GetPlan(ctx, planID)
+	if err != nil {
+		return nil, err
+	}
+	if plan.MerchantID != merchantID {
+		return nil, apperrors.ErrSubscriptionMerchantOnly
+	}
+	return plan, nil
+}
+
+// ownedSubscription loads a subscription of the subscriber; those of other users are reported as not
+// found
+func (uc *subscriptionUseCase) ownedSubscription(ctx context.Context, userID, subscriptionID uint) (*models.Subscription, error) {
+	subscription, err := uc.repos.Subscription.GetByID(ctx, subscriptionID)
+	if err != nil || subscription.UserID != userID {
+		return nil, apperrors.ErrSubscriptionNotFound
+	}
+	return subscription, nil
+}
+
+// chargeReference numbers the charge of a subscription's next period after the periods it paid
+func chargeReference(subscription *models.Subscription) string {
+	return fmt.Sprintf("%s-%d", subscription.Reference, subscription.PaidPeriods+1)
+}
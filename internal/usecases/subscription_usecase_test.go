@@ -0,0 +1,138 @@
+package usecases
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/limistah/wallet-service/internal/apperrors"
+	"github.com/limistah/wallet-service/internal/events"
+	"github.com/limistah/wallet-service/internal/models"
+	"github.com/limistah/wallet-service/internal/repositories/memory"
+	"github.com/shopspring/decimal"
+)
+
+func TestSubscriptionUseCase_BillingAndDunning(t *testing.T) {
+	repos, _ := setupTestEnvironment()
+	repos.Subscription = memory.NewSubscriptionRepository()
+	repos.Wallet.Create(context.Background(), &models.Wallet{ID: 2, UserID: 2, Currency: "USD", Status: models.WalletStatusActive})
+	repos.Wallet.Create(context.Background(), &models.Wallet{ID: 3, UserID: 3, Balance: decimal.NewFromInt(15), Currency: "USD", Status: models.WalletStatusActive})
+	repos.Wallet.Create(context.Background(), &models.Wallet{ID: 4, UserID: 4, Balance: decimal.NewFromInt(15), Currency: "EUR", Status: models.WalletStatusActive})
+
+	walletUC := &transferringWalletUseCase{wallets: repos.Wallet.(*memory.WalletRepository)}
+	publisher := &recordingPublisher{}
+	subscriptionUC := NewSubscriptionUseCase(repos, walletUC, WithEventPublisher(publisher),
+		WithSubscriptionDunning(DunningPolicy{RetryInterval: 24 * time.Hour, MaxAttempts: 2}))
+
+	if _, err := subscriptionUC.CreatePlan(context.Background(), 2, "Pro", "", decimal.NewFromInt(10), "DAILY"); !errors.Is(err, apperrors.ErrValidation) {
+		t.Errorf("Expected unsupported intervals to be rejected, got %v", err)
+	}
+	plan, err := subscriptionUC.CreatePlan(context.Background(), 2, " Pro ", "", decimal.NewFromInt(10), models.BillingIntervalMonthly)
+	if err != nil {
+		t.Fatalf("Unexpected error creating plan: %v", err)
+	}
+	if plan.Name != "Pro" || plan.WalletID != 2 || plan.Currency != "USD" {
+		t.Fatalf("Unexpected plan: %+v", plan)
+	}
+
+	if _, err := subscriptionUC.Subscribe(context.Background(), 2, plan.ID); !errors.Is(err, apperrors.ErrSelfPayment) {
+		t.Errorf("Expected merchants not to subscribe to their own plans, got %v", err)
+	}
+	if _, err := subscriptionUC.Subscribe(context.Background(), 4, plan.ID); !errors.Is(err, apperrors.ErrCurrencyMismatch) {
+		t.Errorf("Expected wallets in another currency to be rejected, got %v", err)
+	}
+
+	subscription, err := subscriptionUC.Subscribe(context.Background(), 3, plan.ID)
+	if err != nil {
+		t.Fatalf("Unexpected error subscribing: %v", err)
+	}
+	if subscription.PaidPeriods != 1 || !subscription.NextBillingAt.Equal(subscription.PaidUntil) || len(walletUC.transfers) != 1 {
+		t.Fatalf("Expected the first period to be paid, got %+v after %v", subscription, walletUC.transfers)
+	}
+	if _, err := subscriptionUC.Subscribe(context.Background(), 3, plan.ID); !errors.Is(err, apperrors.ErrSubscriptionExists) {
+		t.Errorf("Expected a second subscription to be rejected, got %v", err)
+	}
+
+	// The transfers are recorded without moving money, so the balance is lowered by hand
+	wallet, _ := repos.Wallet.GetByID(context.Background(), 3)
+	wallet.Balance = decimal.NewFromInt(5)
+	repos.Wallet.Update(context.Background(), wallet)
+
+	// The second period fails, is retried a day later and fails again, which suspends the subscription
+	due := subscription.NextBillingAt
+	for _, at := range []time.Time{due, due.Add(24 * time.Hour)} {
+		if charges, err := subscriptionUC.BillDue(context.Background(), at); err != nil || charges != 1 {
+			t.Fatalf("Expected one charge at %s, got %d, %v", at, charges, err)
+		}
+	}
+	stored, _ := subscriptionUC.GetSubscription(context.Background(), 3, subscription.ID)
+	if stored.Status != models.SubscriptionStatusSuspended || stored.FailedAttempts != 2 || stored.PaidPeriods != 1 {
+		t.Fatalf("Expected the subscription to be suspended after 2 failures, got %+v", stored)
+	}
+	if len(publisher.events) != 2 || publisher.events[0].Type != events.EventSubscriptionPaymentFailed || publisher.events[1].Type != events.EventSubscriptionSuspended {
+		t.Errorf("Expected a failure then a suspension event, got %+v", publisher.events)
+	}
+	if charges, _ := subscriptionUC.BillDue(context.Background(), due.Add(72*time.Hour)); charges != 0 {
+		t.Errorf("Expected suspended subscriptions not to be billed, got %d charges", charges)
+	}
+
+	wallet.Balance = decimal.NewFromInt(50)
+	repos.Wallet.Update(context.Background(), wallet)
+	resumed, err := subscriptionUC.ResumeSubscription(context.Background(), 3, subscription.ID)
+	if err != nil {
+		t.Fatalf("Unexpected error resuming: %v", err)
+	}
+	if resumed.Status != models.SubscriptionStatusActive || resumed.PaidPeriods != 2 || resumed.FailedAttempts != 0 {
+		t.Errorf("Expected the resumed subscription to pay a new period, got %+v", resumed)
+	}
+	if walletUC.transfers[1] != subscription.Reference+"-2" {
+		t.Errorf("Expected the period to be charged with its own reference, got %v", walletUC.transfers)
+	}
+
+	charges, _ := subscriptionUC.ListCharges(context.Background(), 2, subscription.ID, 1, 20)
+	if len(charges) != 4 || charges[0].Status != models.SubscriptionChargePaid || charges[1].Status != models.SubscriptionChargeFailed {
+		t.Errorf("Expected the merchant to see 4 charges, got %+v", charges)
+	}
+
+	if _, err := subscriptionUC.CancelSubscription(context.Background(), 2, subscription.ID); !errors.Is(err, apperrors.ErrSubscriptionNotFound) {
+		t.Errorf("Expected only the subscriber to cancel, got %v", err)
+	}
+	if _, err := subscriptionUC.CancelSubscription(context.Background(), 3, subscription.ID); err != nil {
+		t.Fatalf("Unexpected error cancelling: %v", err)
+	}
+	if _, err := subscriptionUC.CancelSubscription(context.Background(), 3, subscription.ID); !errors.Is(err, apperrors.ErrSubscriptionCancelled) {
+		t.Errorf("Expected a cancelled subscription not to be cancelled again, got %v", err)
+	}
+	if charges, _ := subscriptionUC.BillDue(context.Background(), resumed.NextBillingAt); charges != 0 {
+		t.Errorf("Expected cancelled subscriptions not to be billed, got %d charges", charges)
+	}
+}
+
+func TestSubscriptionUseCase_PlanAccess(t *testing.T) {
+	repos, _ := setupTestEnvironment()
+	repos.Subscription = memory.NewSubscriptionRepository()
+	repos.Wallet.Create(context.Background(), &models.Wallet{ID: 2, UserID: 2, Currency: "USD", Status: models.WalletStatusActive})
+	walletUC := &transferringWalletUseCase{wallets: repos.Wallet.(*memory.WalletRepository)}
+	subscriptionUC := NewSubscriptionUseCase(repos, walletUC)
+
+	plan, err := subscriptionUC.CreatePlan(context.Background(), 2, "Basic", "", decimal.NewFromInt(5), models.BillingIntervalWeekly)
+	if err != nil {
+		t.Fatalf("Unexpected error creating plan: %v", err)
+	}
+	if _, err := subscriptionUC.ArchivePlan(context.Background(), 3, plan.ID); !errors.Is(err, apperrors.ErrSubscriptionMerchantOnly) {
+		t.Errorf("Expected only the merchant to archive the plan, got %v", err)
+	}
+	if _, err := subscriptionUC.ListPlanSubscriptions(context.Background(), 3, plan.ID, 1, 20); !errors.Is(err, apperrors.ErrSubscriptionMerchantOnly) {
+		t.Errorf("Expected only the merchant to list subscriptions, got %v", err)
+	}
+	if _, err := subscriptionUC.ArchivePlan(context.Background(), 2, plan.ID); err != nil {
+		t.Fatalf("Unexpected error archiving plan: %v", err)
+	}
+	if _, err := subscriptionUC.Subscribe(context.Background(), 3, plan.ID); !errors.Is(err, apperrors.ErrSubscriptionPlanArchived) {
+		t.Errorf("Expected archived plans to refuse subscribers, got %v", err)
+	}
+	if plans, _ := subscriptionUC.ListPlans(context.Background(), 1, 20); len(plans) != 0 {
+		t.Errorf("Expected archived plans to be left out of the list, got %d", len(plans))
+	}
+}
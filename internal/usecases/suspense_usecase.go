@@ -0,0 +1,254 @@
+package usecases
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/limistah/wallet-service/internal/apperrors"
+	"github.com/limistah/wallet-service/internal/models"
+	"github.com/limistah/wallet-service/internal/payments"
+	"github.com/limistah/wallet-service/internal/repositories"
+	"github.com/limistah/wallet-service/internal/utils"
+	"gorm.io/gorm"
+)
+
+type suspenseUseCase struct {
+	repos *repositories.Repositories
+}
+
+// NewSuspenseUseCase creates a new suspense use case
+func NewSuspenseUseCase(repos *repositories.Repositories) SuspenseUseCase {
+	return &suspenseUseCase{repos: repos}
+}
+
+// ParkUnmatchedCredit parks a provider credit whose reference matches no deposit
+func (uc *suspenseUseCase) ParkUnmatchedCredit(ctx context.Context, provider string, event payments.DepositEvent) (*models.SuspenseItem, error) {
+	if event.Status != payments.DepositEventSucceeded {
+		return nil, apperrors.ErrValidation.Withf("only settled credits are parked in suspense")
+	}
+	return parkInSuspense(ctx, uc.repos, &models.SuspenseItem{
+		Provider:          strings.ToLower(provider),
+		ExternalReference: event.ExternalReference,
+		Amount:            event.Amount,
+		Currency:          strings.ToUpper(event.Currency),
+		Reason:            models.SuspenseReasonUnknownReference,
+	})
+}
+
+func (uc *suspenseUseCase) ListItems(ctx context.Context, status models.SuspenseItemStatus, page, pageSize int) ([]models.SuspenseItem, error) {
+	if page < 1 {
+		page = 1
+	}
+	if pageSize < 1 || pageSize > 100 {
+		pageSize = 20
+	}
+	return uc.repos.SuspenseItem.List(ctx, status, (page-1)*pageSize, pageSize)
+}
+
+func (uc *suspenseUseCase) GetItem(ctx context.Context, itemID uint) (*models.SuspenseItem, error) {
+	item, err := uc.repos.SuspenseItem.GetByID(ctx, itemID)
+	if err != nil {
+		return nil, apperrors.ErrSuspenseItemNotFound
+	}
+	return item, nil
+}
+
+// Investigate records a finding on an unresolved item and marks it as under investigation
+func (uc *suspenseUseCase) Investigate(ctx context.Context, itemID, adminID uint, note string) (*models.SuspenseItem, error) {
+	if note == "" {
+		return nil, apperrors.ErrValidation.Withf("note is required")
+	}
+	item, err := uc.getUnresolvedItem(ctx, itemID)
+	if err != nil {
+		return nil, err
+	}
+
+	item.Status = models.SuspenseItemStatusInvestigating
+	item.InvestigatorID = &adminID
+	item.Notes = appendSuspenseNote(item.Notes, adminID, note)
+	if err := uc.repos.SuspenseItem.Update(ctx, item); err != nil {
+		return nil, err
+	}
+	return item, nil
+}
+
+// Reallocate credits the parked funds to the wallet they belong to
+func (uc *suspenseUseCase) Reallocate(ctx context.Context, itemID, walletID, adminID uint, note string) (*models.SuspenseItem, error) {
+	item, err := uc.getUnresolvedItem(ctx, itemID)
+	if err != nil {
+		return nil, err
+	}
+
+	wallet, err := uc.repos.Wallet.GetByID(ctx, walletID)
+	if err != nil {
+		return nil, apperrors.ErrWalletNotFound
+	}
+	if !wallet.IsActive() {
+		return nil, apperrors.ErrWalletInactive
+	}
+	if wallet.Sandbox {
+		return nil, apperrors.ErrValidation.Withf("suspense funds cannot be reallocated to a sandbox wallet")
+	}
+	if !strings.EqualFold(wallet.Currency, item.Currency) {
+		return nil, apperrors.ErrValidation.Withf("wallet currency %s does not match the %s credit", wallet.Currency, item.Currency)
+	}
+	tier, err := resolveWalletTier(ctx, uc.repos, wallet)
+	if err != nil {
+		return nil, err
+	}
+	if !withinMaxBalance(tier, wallet, item.Amount) {
+		return nil, apperrors.ErrTierLimitExceeded.Withf("wallet balance would exceed the maximum of %s", tier.MaxBalance.StringFixed(2))
+	}
+
+	suspense, err := uc.repos.LedgerAccount.GetByCode(ctx, models.LedgerAccountSuspense, false)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load suspense account: %w", err)
+	}
+
+	description := fmt.Sprintf("Reallocated from suspense: %s via %s", item.ExternalReference, item.Provider)
+	entry := &models.JournalEntry{Reference: "SUSPENSE-" + utils.GenerateTransactionReference(), Description: description, PostedBy: &adminID}
+	legs := []postingLeg{
+		{WalletID: suspense.WalletID, Type: models.TransactionTypeDebit, Amount: item.Amount, Purpose: models.TransactionPurposeWalletTopUp, Description: description},
+		{WalletID: wallet.ID, Type: models.TransactionTypeCredit, Amount: item.Amount, Purpose: models.TransactionPurposeWalletTopUp, Description: description},
+	}
+	item.ReallocatedWalletID = &wallet.ID
+	err = uc.repos.UnitOfWork.Do(ctx, func(tx *repositories.Repositories) error {
+		if err := postJournalEntry(ctx, tx, entry, legs); err != nil {
+			return err
+		}
+		return resolveSuspenseItem(ctx, tx, item, models.SuspenseItemStatusReallocated, entry.ID, adminID, note)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return uc.repos.SuspenseItem.GetByID(ctx, item.ID)
+}
+
+// Return moves the parked funds back to cash once they have been refunded to the payer outside the
+// service
+func (uc *suspenseUseCase) Return(ctx context.Context, itemID, adminID uint, note string) (*models.SuspenseItem, error) {
+	item, err := uc.getUnresolvedItem(ctx, itemID)
+	if err != nil {
+		return nil, err
+	}
+
+	suspense, err := uc.repos.LedgerAccount.GetByCode(ctx, models.LedgerAccountSuspense, false)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load suspense account: %w", err)
+	}
+	cash, err := uc.repos.LedgerAccount.GetByCode(ctx, models.LedgerAccountCash, false)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load cash account: %w", err)
+	}
+
+	description := fmt.Sprintf("Returned to payer: %s via %s", item.ExternalReference, item.Provider)
+	entry := &models.JournalEntry{Reference: "SUSPENSE-" + utils.GenerateTransactionReference(), Description: description, PostedBy: &adminID}
+	legs := []postingLeg{
+		{WalletID: suspense.WalletID, Type: models.TransactionTypeDebit, Amount: item.Amount, Purpose: models.TransactionPurposeAdjustment, Description: description},
+		{WalletID: cash.WalletID, Type: models.TransactionTypeCredit, Amount: item.Amount, Purpose: models.TransactionPurposeAdjustment, Description: description},
+	}
+	err = uc.repos.UnitOfWork.Do(ctx, func(tx *repositories.Repositories) error {
+		if err := postJournalEntry(ctx, tx, entry, legs); err != nil {
+			return err
+		}
+		return resolveSuspenseItem(ctx, tx, item, models.SuspenseItemStatusReturned, entry.ID, adminID, note)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return uc.repos.SuspenseItem.GetByID(ctx, item.ID)
+}
+
+func (uc *suspenseUseCase) getUnresolvedItem(ctx context.Context, itemID uint) (*models.SuspenseItem, error) {
+	item, err := uc.repos.SuspenseItem.GetByID(ctx, itemID)
+	if err != nil {
+		return nil, apperrors.ErrSuspenseItemNotFound
+	}
+	if item.IsResolved() {
+		return nil, apperrors.ErrSuspenseItemResolved
+	}
+	return item, nil
+}
+
+// resolveSuspenseItem records where the funds went, failing when another admin resolved the item first
+func resolveSuspenseItem(ctx context.Context, tx *repositories.Repositories, item *models.SuspenseItem, status models.SuspenseItemStatus, entryID, adminID uint, note string) error {
+	now := time.Now()
+	item.Status = status
+	item.ResolutionJournalEntryID = &entryID
+	item.ResolvedBy = &adminID
+	item.ResolvedAt = &now
+	if note != "" {
+		item.Notes = appendSuspenseNote(item.Notes, adminID, note)
+	}
+
+	resolved, err := tx.SuspenseItem.Resolve(ctx, item)
+	if err != nil {
+		return fmt.Errorf("failed to update suspense item: %w", err)
+	}
+	if !resolved {
+		return apperrors.ErrSuspenseItemResolved
+	}
+	return nil
+}
+
+// appendSuspenseNote adds a dated note by an admin to the investigation log of an item
+func appendSuspenseNote(notes string, adminID uint, note string) string {
+	line := fmt.Sprintf("[%s] admin %d: %s", time.Now().UTC().Format("2006-01-02 15:04"), adminID, note)
+	if notes == "" {
+		return line
+	}
+	return notes + "\n" + line
+}
+
+// parkInSuspense posts an unmatched credit from cash to the suspense account and queues it for
+// investigation. A credit that is already parked is returned as is, so provider retries are safe
+func parkInSuspense(ctx context.Context, repos *repositories.Repositories, item *models.SuspenseItem) (*models.SuspenseItem, error) {
+	existing, err := repos.SuspenseItem.GetByExternalReference(ctx, item.Provider, item.ExternalReference)
+	if err == nil {
+		return existing, nil
+	}
+	if !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, fmt.Errorf("error checking suspense items: %w", err)
+	}
+
+	cash, err := repos.LedgerAccount.GetByCode(ctx, models.LedgerAccountCash, false)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load cash account: %w", err)
+	}
+	suspense, err := repos.LedgerAccount.GetByCode(ctx, models.LedgerAccountSuspense, false)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load suspense account: %w", err)
+	}
+	if item.Currency == "" {
+		item.Currency = suspense.Wallet.Currency
+	}
+	item.Status = models.SuspenseItemStatusOpen
+
+	description := fmt.Sprintf("Unmatched credit %s via %s", item.ExternalReference, item.Provider)
+	entry := &models.JournalEntry{Reference: "SUSPENSE-" + utils.GenerateTransactionReference(), Description: description}
+	legs := []postingLeg{
+		{WalletID: cash.WalletID, Type: models.TransactionTypeDebit, Amount: item.Amount, Purpose: models.TransactionPurposeWalletTopUp, Description: description},
+		{WalletID: suspense.WalletID, Type: models.TransactionTypeCredit, Amount: item.Amount, Purpose: models.TransactionPurposeWalletTopUp, Description: description},
+	}
+	err = repos.UnitOfWork.Do(ctx, func(tx *repositories.Repositories) error {
+		if err := postJournalEntry(ctx, tx, entry, legs); err != nil {
+			return err
+		}
+		item.JournalEntryID = entry.ID
+		if err := tx.SuspenseItem.Create(ctx, item); err != nil {
+			return fmt.Errorf("failed to create suspense item: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		// A concurrent delivery of the same credit may have parked it first
+		if existing, getErr := repos.SuspenseItem.GetByExternalReference(ctx, item.Provider, item.ExternalReference); getErr == nil {
+			return existing, nil
+		}
+		return nil, err
+	}
+	return item, nil
+}
@@ -0,0 +1,114 @@
+package usecases
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/limistah/wallet-service/internal/apperrors"
+	"github.com/limistah/wallet-service/internal/models"
+	"github.com/limistah/wallet-service/internal/payments"
+	"github.com/limistah/wallet-service/internal/repositories/memory"
+	"github.com/shopspring/decimal"
+)
+
+func setupSuspenseTest() (*memory.SuspenseItemRepository, *memory.WalletRepository, SuspenseUseCase) {
+	repos, _ := setupTestEnvironment()
+	items := memory.NewSuspenseItemRepository()
+	repos.SuspenseItem = items
+	return items, repos.Wallet.(*memory.WalletRepository), NewSuspenseUseCase(repos)
+}
+
+func TestSuspenseUseCase_ParkUnmatchedCredit(t *testing.T) {
+	items, _, suspenseUC := setupSuspenseTest()
+
+	_, err := suspenseUC.ParkUnmatchedCredit(context.Background(), "paystack", payments.DepositEvent{ExternalReference: "PSK-1", Status: payments.DepositEventFailed})
+	if !errors.Is(err, apperrors.ErrValidation) {
+		t.Errorf("Expected failed credits to be refused, got: %v", err)
+	}
+
+	parked := &models.SuspenseItem{Provider: "paystack", ExternalReference: "PSK-2", Amount: decimal.NewFromInt(250), Status: models.SuspenseItemStatusOpen}
+	items.Create(context.Background(), parked)
+	item, err := suspenseUC.ParkUnmatchedCredit(context.Background(), "Paystack", payments.DepositEvent{
+		ExternalReference: "PSK-2",
+		Amount:            decimal.NewFromInt(250),
+		Status:            payments.DepositEventSucceeded,
+	})
+	if err != nil {
+		t.Fatalf("Expected a redelivered credit to be acknowledged, got: %v", err)
+	}
+	if item.ID != parked.ID || items.Count() != 1 {
+		t.Errorf("Expected the credit to be parked once, got item %d and %d items", item.ID, items.Count())
+	}
+}
+
+func TestSuspenseUseCase_Investigate(t *testing.T) {
+	items, _, suspenseUC := setupSuspenseTest()
+	item := &models.SuspenseItem{Provider: "paystack", ExternalReference: "PSK-3", Amount: decimal.NewFromInt(40), Status: models.SuspenseItemStatusOpen}
+	items.Create(context.Background(), item)
+
+	if _, err := suspenseUC.Investigate(context.Background(), item.ID, 3, ""); !errors.Is(err, apperrors.ErrValidation) {
+		t.Errorf("Expected a note to be required, got: %v", err)
+	}
+	if _, err := suspenseUC.Investigate(context.Background(), 99, 3, "checking"); !errors.Is(err, apperrors.ErrSuspenseItemNotFound) {
+		t.Errorf("Expected suspense item not found, got: %v", err)
+	}
+
+	if _, err := suspenseUC.Investigate(context.Background(), item.ID, 3, "payer named in the narration"); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	investigated, err := suspenseUC.Investigate(context.Background(), item.ID, 4, "customer contacted")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if investigated.Status != models.SuspenseItemStatusInvestigating || investigated.InvestigatorID == nil || *investigated.InvestigatorID != 4 {
+		t.Errorf("Expected the item to be under investigation by admin 4, got: %+v", investigated)
+	}
+	notes := strings.Split(investigated.Notes, "\n")
+	if len(notes) != 2 || !strings.HasSuffix(notes[0], "admin 3: payer named in the narration") || !strings.HasSuffix(notes[1], "admin 4: customer contacted") {
+		t.Errorf("Expected both notes in order, got: %q", investigated.Notes)
+	}
+
+	item.Status = models.SuspenseItemStatusReturned
+	items.Update(context.Background(), item)
+	if _, err := suspenseUC.Investigate(context.Background(), item.ID, 3, "too late"); !errors.Is(err, apperrors.ErrSuspenseItemResolved) {
+		t.Errorf("Expected resolved items to be refused, got: %v", err)
+	}
+}
+
+func TestSuspenseUseCase_Reallocate(t *testing.T) {
+	items, walletRepo, suspenseUC := setupSuspenseTest()
+	item := &models.SuspenseItem{Provider: "paystack", ExternalReference: "PSK-4", Amount: decimal.NewFromInt(75), Currency: "USD", Status: models.SuspenseItemStatusOpen}
+	items.Create(context.Background(), item)
+	walletRepo.Create(context.Background(), &models.Wallet{ID: 20, UserID: 20, Currency: "USD", Status: models.WalletStatusClosed})
+	walletRepo.Create(context.Background(), &models.Wallet{ID: 21, UserID: 21, Currency: "EUR", Status: models.WalletStatusActive})
+	walletRepo.Create(context.Background(), &models.Wallet{ID: 22, UserID: 22, Currency: "USD", Status: models.WalletStatusActive, Sandbox: true})
+
+	cases := []struct {
+		name     string
+		walletID uint
+		expected error
+	}{
+		{"missing wallet", 404, apperrors.ErrWalletNotFound},
+		{"closed wallet", 20, apperrors.ErrWalletInactive},
+		{"other currency", 21, apperrors.ErrValidation},
+		{"sandbox wallet", 22, apperrors.ErrValidation},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			if _, err := suspenseUC.Reallocate(context.Background(), item.ID, tc.walletID, 3, ""); !errors.Is(err, tc.expected) {
+				t.Errorf("Expected %v, got: %v", tc.expected, err)
+			}
+		})
+	}
+
+	item.Status = models.SuspenseItemStatusReallocated
+	items.Update(context.Background(), item)
+	if _, err := suspenseUC.Reallocate(context.Background(), item.ID, 2, 3, ""); !errors.Is(err, apperrors.ErrSuspenseItemResolved) {
+		t.Errorf("Expected resolved items to be refused, got: %v", err)
+	}
+	if _, err := suspenseUC.Return(context.Background(), item.ID, 3, ""); !errors.Is(err, apperrors.ErrSuspenseItemResolved) {
+		t.Errorf("Expected resolved items to be refused, got: %v", err)
+	}
+}
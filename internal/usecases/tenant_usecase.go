@@ -0,0 +1,129 @@
+package usecases
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/limistah/wallet-service/internal/apperrors"
+	"github.com/limistah/wallet-service/internal/models"
+	"github.com/limistah/wallet-service/internal/repositories"
+	"github.com/shopspring/decimal"
+	"gorm.io/gorm"
+)
+
+// tenantSlugPattern is what tenant slugs may look like: lower-case letters, digits and inner dashes
+var tenantSlugPattern = regexp.MustCompile(`^[a-z0-9]([a-z0-9-]{0,48}[a-z0-9])?$`)
+
+type tenantUseCase struct {
+	repos *repositories.Repositories
+}
+
+// NewTenantUseCase creates a new tenant use case
+func NewTenantUseCase(repos *repositories.Repositories) TenantUseCase {
+	return &tenantUseCase{repos: repos}
+}
+
+// CreateTenant adds a tenant with live and sandbox system accounts and charts of ledger accounts of its
+// own, so its transfers, fees and escrows never balance against the wallets of another tenant
+func (uc *tenantUseCase) CreateTenant(ctx context.Context, name, slug string) (*models.Tenant, error) {
+	name = strings.TrimSpace(name)
+	slug = strings.ToLower(strings.TrimSpace(slug))
+	if name == "" {
+		return nil, apperrors.ErrValidation.Withf("tenant name is required")
+	}
+	if !tenantSlugPattern.MatchString(slug) {
+		return nil, apperrors.ErrValidation.Withf("tenant slug must be 1-50 lower-case letters, digits or dashes")
+	}
+
+	if _, err := uc.repos.Tenant.GetBySlug(ctx, slug); err == nil {
+		return nil, apperrors.ErrTenantExists
+	} else if !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, fmt.Errorf("failed to check tenant slug: %w", err)
+	}
+
+	tenant := &models.Tenant{Name: name, Slug: slug, Active: true}
+	if err := uc.repos.Tenant.Create(ctx, tenant); err != nil {
+		return nil, fmt.Errorf("failed to create tenant: %w", err)
+	}
+
+	for _, systemUser := range []*models.User{models.CreateSystemUser(), models.CreateSandboxSystemUser()} {
+		if err := uc.createSystemAccount(ctx, tenant.ID, systemUser); err != nil {
+			return nil, err
+		}
+	}
+	return tenant, nil
+}
+
+func (uc *tenantUseCase) ListTenants(ctx context.Context) ([]models.Tenant, error) {
+	tenants, err := uc.repos.Tenant.List(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list tenants: %w", err)
+	}
+	return tenants, nil
+}
+
+// ResolveTenant finds an active tenant by slug
+func (uc *tenantUseCase) ResolveTenant(ctx context.Context, slug string) (*models.Tenant, error) {
+	tenant, err := uc.repos.Tenant.GetBySlug(ctx, strings.ToLower(strings.TrimSpace(slug)))
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, apperrors.ErrTenantNotFound
+		}
+		return nil, fmt.Errorf("failed to load tenant: %w", err)
+	}
+	if !tenant.Active {
+		return nil, apperrors.ErrTenantNotFound
+	}
+	return tenant, nil
+}
+
+// createSystemAccount creates a system user of the tenant with its wallet and the tenant's chart of
+// ledger accounts. Cash is the system wallet; every other account gets a wallet of its own, starting empty
+func (uc *tenantUseCase) createSystemAccount(ctx context.Context, tenantID uint, systemUser *models.User) error {
+	systemUser.TenantID = tenantID
+	if err := systemUser.HashPassword(systemUser.Password); err != nil {
+		return fmt.Errorf("failed to hash system account password: %w", err)
+	}
+	if err := uc.repos.User.Create(ctx, systemUser); err != nil {
+		return fmt.Errorf("failed to create system user: %w", err)
+	}
+
+	systemWallet := &models.Wallet{
+		UserID:   systemUser.ID,
+		TenantID: tenantID,
+		Balance:  models.SystemWalletOpeningBalance,
+		Currency: "USD",
+		Status:   models.WalletStatusActive,
+		Sandbox:  systemUser.Email == models.SandboxSystemAccountEmail,
+	}
+	if err := uc.repos.Wallet.Create(ctx, systemWallet); err != nil {
+		return fmt.Errorf("failed to create system wallet: %w", err)
+	}
+
+	for _, account := range models.DefaultLedgerAccounts() {
+		wallet := systemWallet
+		if account.Code != models.LedgerAccountCash {
+			wallet = &models.Wallet{
+				UserID:   systemUser.ID,
+				TenantID: tenantID,
+				Balance:  decimal.Zero,
+				Currency: "USD",
+				Status:   models.WalletStatusActive,
+				Sandbox:  systemWallet.Sandbox,
+			}
+			if err := uc.repos.Wallet.Create(ctx, wallet); err != nil {
+				return fmt.Errorf("failed to create wallet of ledger account %s: %w", account.Code, err)
+			}
+		}
+		account.TenantID = tenantID
+		account.Sandbox = systemWallet.Sandbox
+		account.WalletID = wallet.ID
+		if err := uc.repos.LedgerAccount.Create(ctx, &account); err != nil {
+			return fmt.Errorf("failed to create ledger account %s: %w", account.Code, err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,115 @@
+package usecases
+
+import (
+	"context"
+	"errors"
+	"path/filepath"
+	"testing"
+
+	"github.com/limistah/wallet-service/internal/apperrors"
+	"github.com/limistah/wallet-service/internal/database"
+	"github.com/limistah/wallet-service/internal/models"
+	"github.com/limistah/wallet-service/internal/repositories"
+	"github.com/limistah/wallet-service/internal/repositories/memory"
+	"github.com/shopspring/decimal"
+)
+
+func TestTenantUseCase_CreateTenant(t *testing.T) {
+	repos, reconciliationUC := setupTestEnvironment()
+	repos.Tenant = memory.NewTenantRepository()
+	repos.LedgerAccount = memory.NewLedgerAccountRepository(repos.Wallet.(*memory.WalletRepository))
+	repos.Tenant.Create(context.Background(), &models.Tenant{ID: models.DefaultTenantID, Name: "Default", Slug: models.DefaultTenantSlug, Active: true})
+	tenantUC := NewTenantUseCase(repos)
+
+	if _, err := tenantUC.CreateTenant(context.Background(), "Acme", "not a slug"); !errors.Is(err, apperrors.ErrValidation) {
+		t.Errorf("Expected an invalid slug to be rejected, got: %v", err)
+	}
+
+	tenant, err := tenantUC.CreateTenant(context.Background(), " Acme Payments ", "Acme")
+	if err != nil {
+		t.Fatalf("Unexpected error creating tenant: %v", err)
+	}
+	if tenant.Name != "Acme Payments" || tenant.Slug != "acme" || !tenant.Active {
+		t.Errorf("Expected an active tenant acme, got: %+v", tenant)
+	}
+	if _, err := tenantUC.CreateTenant(context.Background(), "Other", "acme"); !errors.Is(err, apperrors.ErrTenantExists) {
+		t.Errorf("Expected a taken slug to be refused, got: %v", err)
+	}
+	if _, err := tenantUC.ResolveTenant(context.Background(), "ACME"); err != nil {
+		t.Errorf("Expected the tenant to resolve by slug, got: %v", err)
+	}
+	if _, err := tenantUC.ResolveTenant(context.Background(), "unknown"); !errors.Is(err, apperrors.ErrTenantNotFound) {
+		t.Errorf("Expected an unknown slug to be not found, got: %v", err)
+	}
+
+	// Wallets of the tenant balance against the tenant's system wallets, not the default ones
+	walletUC := NewWalletUseCase(repos, reconciliationUC).(*walletUseCase)
+	for _, sandbox := range []bool{false, true} {
+		systemWallet, err := walletUC.getSystemWallet(context.Background(), &models.Wallet{TenantID: tenant.ID, Sandbox: sandbox})
+		if err != nil {
+			t.Fatalf("Expected the tenant to have a system wallet (sandbox=%v), got: %v", sandbox, err)
+		}
+		owner, _ := repos.User.GetByID(context.Background(), systemWallet.UserID)
+		if owner.TenantID != tenant.ID || systemWallet.Sandbox != sandbox || !systemWallet.Balance.Equal(models.SystemWalletOpeningBalance) {
+			t.Errorf("Expected a funded system wallet of the tenant (sandbox=%v), got: %+v", sandbox, systemWallet)
+		}
+	}
+	if systemWallet, err := walletUC.getSystemWallet(context.Background(), &models.Wallet{TenantID: models.DefaultTenantID}); err != nil || systemWallet.ID != 1 {
+		t.Errorf("Expected wallets of the default tenant to keep the default system wallet, got %+v, %v", systemWallet, err)
+	}
+}
+
+func TestTenantUseCase_ChargesFeesToTheTenantLedger(t *testing.T) {
+	t.Setenv("DB_DRIVER", "sqlite")
+	t.Setenv("DB_PATH", filepath.Join(t.TempDir(), "wallet.db"))
+	t.Setenv("APP_ENV", "production")
+	db, err := database.Initialize()
+	if err != nil {
+		t.Fatalf("Failed to open database: %v", err)
+	}
+	repos := repositories.NewRepositories(db)
+	ctx := context.Background()
+
+	tenant, err := NewTenantUseCase(repos.ForTenant(models.DefaultTenantID)).CreateTenant(ctx, "Acme", "acme")
+	if err != nil {
+		t.Fatalf("Unexpected error creating tenant: %v", err)
+	}
+	acme := repos.ForTenant(tenant.ID)
+
+	tier := &models.WalletTier{Name: "fee-charging", TransferFeeFlat: decimal.NewFromInt(1)}
+	if err := acme.WalletTier.Create(ctx, tier); err != nil {
+		t.Fatalf("Failed to create tier: %v", err)
+	}
+	var wallets []*models.Wallet
+	for _, email := range []string{"payer@acme.test", "payee@acme.test"} {
+		user := &models.User{Name: "Acme user", Email: email, Password: "secret"}
+		if err := acme.User.Create(ctx, user); err != nil {
+			t.Fatalf("Failed to create user: %v", err)
+		}
+		wallet := &models.Wallet{UserID: user.ID, Currency: "USD", Status: models.WalletStatusActive, TierID: &tier.ID, IsDefault: true}
+		if err := acme.Wallet.Create(ctx, wallet); err != nil {
+			t.Fatalf("Failed to create wallet: %v", err)
+		}
+		wallets = append(wallets, wallet)
+	}
+
+	walletUC := NewWalletUseCase(acme, NewReconciliationUseCase(acme))
+	if _, _, err := walletUC.FundWallet(ctx, wallets[0].ID, decimal.NewFromInt(100), "ACME-FUND", "Funding"); err != nil {
+		t.Fatalf("Unexpected error funding: %v", err)
+	}
+	if _, _, err := walletUC.TransferFunds(ctx, wallets[0].ID, wallets[1].ID, decimal.NewFromInt(10), "ACME-TRF", "Fee-charging", nil); err != nil {
+		t.Fatalf("Unexpected error transferring: %v", err)
+	}
+
+	fees, err := acme.LedgerAccount.GetByCode(ctx, models.LedgerAccountFees, false)
+	if err != nil {
+		t.Fatalf("Expected the tenant to have a fee account, got: %v", err)
+	}
+	if fees.TenantID != tenant.ID || fees.Wallet.TenantID != tenant.ID || !fees.Wallet.Balance.Equal(decimal.NewFromInt(1)) {
+		t.Errorf("Expected the fee to land in the tenant's fee wallet, got: %+v", fees)
+	}
+	defaultFees, err := repos.ForTenant(models.DefaultTenantID).LedgerAccount.GetByCode(ctx, models.LedgerAccountFees, false)
+	if err != nil || defaultFees.WalletID == fees.WalletID || !defaultFees.Wallet.Balance.IsZero() {
+		t.Errorf("Expected the default tenant's fee wallet to be left alone, got: %+v, %v", defaultFees, err)
+	}
+}
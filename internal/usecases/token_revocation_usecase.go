@@ -0,0 +1,79 @@
+package usecases
+
+import (
+	"context"
+	"errors"
+	"log"
+	"time"
+
+	"github.com/limistah/wallet-service/internal/models"
+	"github.com/limistah/wallet-service/internal/repositories"
+	"gorm.io/gorm"
+)
+
+type tokenRevocationUseCase struct {
+	repos *repositories.Repositories
+	now   func() time.Time
+}
+
+// NewTokenRevocationUseCase creates a new token revocation use case
+func NewTokenRevocationUseCase(repos *repositories.Repositories) TokenRevocationUseCase {
+	return &tokenRevocationUseCase{
+		repos: repos,
+		now:   time.Now,
+	}
+}
+
+// RevokeToken signs out a single token until it expires, ending the session of its device. Tokens
+// issued before token IDs existed cannot be told apart, so revoking one of them signs out every session
+// of the user
+func (uc *tokenRevocationUseCase) RevokeToken(ctx context.Context, tokenID string, userID uint, expiresAt time.Time) error {
+	if tokenID == "" {
+		return uc.RevokeAllTokens(ctx, userID)
+	}
+
+	err := uc.repos.RevokedToken.Create(ctx, &models.RevokedToken{
+		TokenID:   tokenID,
+		UserID:    userID,
+		ExpiresAt: expiresAt,
+	})
+	if err != nil {
+		return err
+	}
+	if err := uc.repos.Session.RevokeByTokenID(ctx, tokenID, uc.now()); err != nil {
+		return err
+	}
+
+	// Revocations of expired tokens are never looked up again
+	if _, err := uc.repos.RevokedToken.DeleteExpired(ctx, uc.now()); err != nil {
+		log.Printf("Failed to delete expired token revocations: %v", err)
+	}
+	return nil
+}
+
+// RevokeAllTokens signs out every session of the user; tokens issued afterwards are accepted
+func (uc *tokenRevocationUseCase) RevokeAllTokens(ctx context.Context, userID uint) error {
+	return uc.repos.User.RevokeTokens(ctx, userID, uc.now())
+}
+
+// IsRevoked reports whether a token was signed out, one by one or along with every session of its
+// user. Tokens of deleted users are reported as revoked
+func (uc *tokenRevocationUseCase) IsRevoked(ctx context.Context, tokenID string, userID uint, issuedAt time.Time) (bool, error) {
+	if tokenID != "" {
+		revoked, err := uc.repos.RevokedToken.Exists(ctx, tokenID)
+		if err != nil || revoked {
+			return revoked, err
+		}
+	}
+
+	revokedAt, err := uc.repos.User.GetTokensRevokedAt(ctx, userID)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return true, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	// Token times have a precision of one second, so tokens issued in the second of the revocation are
+	// revoked as well
+	return revokedAt != nil && !issuedAt.After(*revokedAt), nil
+}
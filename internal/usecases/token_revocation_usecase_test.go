@@ -0,0 +1,128 @@
+package usecases
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/limistah/wallet-service/internal/apperrors"
+	"github.com/limistah/wallet-service/internal/models"
+	"github.com/limistah/wallet-service/internal/repositories"
+	"github.com/limistah/wallet-service/internal/repositories/memory"
+)
+
+func newTestTokenRevocation(now time.Time) (*tokenRevocationUseCase, *repositories.Repositories) {
+	userRepo := memory.NewUserRepository()
+	userRepo.Create(context.Background(), &models.User{ID: 1, Email: "user@example.com"})
+	repos := &repositories.Repositories{
+		User:         userRepo,
+		RevokedToken: memory.NewRevokedTokenRepository(),
+		Session:      memory.NewSessionRepository(),
+	}
+	uc := NewTokenRevocationUseCase(repos).(*tokenRevocationUseCase)
+	uc.now = func() time.Time { return now }
+	return uc, repos
+}
+
+func TestTokenRevocationUseCase_RevokeToken(t *testing.T) {
+	now := time.Date(2025, 3, 1, 12, 0, 0, 0, time.UTC)
+	uc, repos := newTestTokenRevocation(now)
+	repos.RevokedToken.Create(context.Background(), &models.RevokedToken{TokenID: "expired", UserID: 1, ExpiresAt: now.Add(-time.Minute)})
+
+	if err := uc.RevokeToken(context.Background(), "abc", 1, now.Add(time.Hour)); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	revoked, err := uc.IsRevoked(context.Background(), "abc", 1, now.Add(-time.Hour))
+	if err != nil || !revoked {
+		t.Errorf("Expected the token to be revoked, got %v, %v", revoked, err)
+	}
+	revoked, err = uc.IsRevoked(context.Background(), "other", 1, now.Add(-time.Hour))
+	if err != nil || revoked {
+		t.Errorf("Expected other tokens of the user to stay valid, got %v, %v", revoked, err)
+	}
+	if exists, _ := repos.RevokedToken.Exists(context.Background(), "expired"); exists {
+		t.Error("Expected revocations of expired tokens to be deleted")
+	}
+}
+
+func TestTokenRevocationUseCase_RevokeAllTokens(t *testing.T) {
+	now := time.Date(2025, 3, 1, 12, 0, 0, 500, time.UTC)
+	uc, _ := newTestTokenRevocation(now)
+
+	if err := uc.RevokeAllTokens(context.Background(), 1); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	tests := []struct {
+		name     string
+		issuedAt time.Time
+		revoked  bool
+	}{
+		{"issued before", now.Add(-time.Hour), true},
+		{"issued in the same second", now.Truncate(time.Second), true},
+		{"issued after", now.Add(time.Second).Truncate(time.Second), false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			revoked, err := uc.IsRevoked(context.Background(), "abc", 1, tt.issuedAt)
+			if err != nil {
+				t.Fatalf("Expected no error, got %v", err)
+			}
+			if revoked != tt.revoked {
+				t.Errorf("Expected revoked to be %v, got %v", tt.revoked, revoked)
+			}
+		})
+	}
+}
+
+func TestTokenRevocationUseCase_TokenWithoutID(t *testing.T) {
+	now := time.Date(2025, 3, 1, 12, 0, 0, 0, time.UTC)
+	uc, _ := newTestTokenRevocation(now)
+
+	if err := uc.RevokeToken(context.Background(), "", 1, now.Add(time.Hour)); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	revoked, err := uc.IsRevoked(context.Background(), "", 1, now.Add(-time.Hour))
+	if err != nil || !revoked {
+		t.Errorf("Expected every session to be revoked, got %v, %v", revoked, err)
+	}
+}
+
+func TestTokenRevocationUseCase_DeletedUser(t *testing.T) {
+	uc, _ := newTestTokenRevocation(time.Now())
+
+	revoked, err := uc.IsRevoked(context.Background(), "abc", 42, time.Now())
+	if err != nil || !revoked {
+		t.Errorf("Expected tokens of unknown users to be revoked, got %v, %v", revoked, err)
+	}
+}
+
+func TestUserUseCase_ChangePassword(t *testing.T) {
+	userRepo := memory.NewUserRepository()
+	user := &models.User{ID: 1, Email: "user@example.com"}
+	user.HashPassword("Old-password1")
+	userRepo.Create(context.Background(), user)
+	uc := NewUserUseCase(&repositories.Repositories{User: userRepo, PasswordHistory: memory.NewPasswordHistoryRepository()})
+
+	err := uc.ChangePassword(context.Background(), 1, "wrong-password", "New-password1")
+	if !errors.Is(err, apperrors.ErrIncorrectPassword) {
+		t.Fatalf("Expected ErrIncorrectPassword, got %v", err)
+	}
+	if user.TokensRevokedAt != nil {
+		t.Error("Expected sessions to stay valid after a failed change")
+	}
+
+	if err := uc.ChangePassword(context.Background(), 1, "Old-password1", "New-password1"); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	user, _ = userRepo.GetByID(context.Background(), 1)
+	if user.CheckPassword("New-password1") != nil {
+		t.Error("Expected the new password to be set")
+	}
+	if user.TokensRevokedAt == nil {
+		t.Error("Expected every session to be revoked")
+	}
+}
@@ -0,0 +1,138 @@
+package usecases
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/limistah/wallet-service/internal/apperrors"
+	"github.com/limistah/wallet-service/internal/models"
+	"github.com/limistah/wallet-service/internal/notifications"
+	"github.com/limistah/wallet-service/internal/repositories"
+	"gorm.io/gorm"
+)
+
+// purposeNamePattern is the form of purpose names: upper case words joined by underscores, fitting
+// the transaction_purpose column
+var purposeNamePattern = regexp.MustCompile(`^[A-Z][A-Z0-9]*(_[A-Z0-9]+)*$`)
+
+// maxPurposeNameLength is the size of the transaction_purpose column
+const maxPurposeNameLength = 20
+
+// TransactionPurposeInput is a transaction purpose an admin defines or changes, with the hooks that
+// tune how its transactions are handled
+type TransactionPurposeInput struct {
+	Name                 models.TransactionPurpose
+	Description          string
+	ChargesFees          bool
+	CountsTowardLimits   bool
+	NotificationTemplate string
+}
+
+type transactionPurposeUseCase struct {
+	repos *repositories.Repositories
+}
+
+// NewTransactionPurposeUseCase creates a new transaction purpose use case
+func NewTransactionPurposeUseCase(repos *repositories.Repositories) TransactionPurposeUseCase {
+	return &transactionPurposeUseCase{repos: repos}
+}
+
+func (uc *transactionPurposeUseCase) ListPurposes(ctx context.Context) ([]models.TransactionPurposeDefinition, error) {
+	return uc.repos.TransactionPurpose.List(ctx)
+}
+
+func (uc *transactionPurposeUseCase) GetPurpose(ctx context.Context, name models.TransactionPurpose) (*models.TransactionPurposeDefinition, error) {
+	return purposeDefinition(ctx, uc.repos, name)
+}
+
+// CreatePurpose adds a purpose transactions can be recorded with
+func (uc *transactionPurposeUseCase) CreatePurpose(ctx context.Context, input TransactionPurposeInput) (*models.TransactionPurposeDefinition, error) {
+	input.Name = models.TransactionPurpose(strings.ToUpper(strings.TrimSpace(string(input.Name))))
+	if len(input.Name) > maxPurposeNameLength || !purposeNamePattern.MatchString(string(input.Name)) {
+		return nil, apperrors.ErrValidation.Withf("purpose name must be upper case letters, digits and underscores, at most %d characters", maxPurposeNameLength)
+	}
+	definition := &models.TransactionPurposeDefinition{Name: input.Name}
+	if err := applyPurposeInput(definition, input); err != nil {
+		return nil, err
+	}
+
+	if _, err := uc.repos.TransactionPurpose.GetByName(ctx, input.Name); err == nil {
+		return nil, apperrors.ErrTransactionPurposeExists
+	} else if !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, fmt.Errorf("failed to check transaction purpose: %w", err)
+	}
+	if err := uc.repos.TransactionPurpose.Create(ctx, definition); err != nil {
+		return nil, fmt.Errorf("failed to create transaction purpose: %w", err)
+	}
+	return definition, nil
+}
+
+// UpdatePurpose changes the description and hooks of a purpose; its name is what transactions
+// reference, so it stays
+func (uc *transactionPurposeUseCase) UpdatePurpose(ctx context.Context, name models.TransactionPurpose, input TransactionPurposeInput) (*models.TransactionPurposeDefinition, error) {
+	definition, err := purposeDefinition(ctx, uc.repos, name)
+	if err != nil {
+		return nil, err
+	}
+	if err := applyPurposeInput(definition, input); err != nil {
+		return nil, err
+	}
+	if err := uc.repos.TransactionPurpose.Update(ctx, definition); err != nil {
+		return nil, fmt.Errorf("failed to update transaction purpose: %w", err)
+	}
+	return definition, nil
+}
+
+// DeletePurpose removes a purpose no transaction was recorded with. The default purposes are posted
+// by the service itself and always stay
+func (uc *transactionPurposeUseCase) DeletePurpose(ctx context.Context, name models.TransactionPurpose) error {
+	if _, err := purposeDefinition(ctx, uc.repos, name); err != nil {
+		return err
+	}
+	if models.IsDefaultTransactionPurpose(name) {
+		return apperrors.ErrTransactionPurposeInUse.Withf("transaction purpose %s is posted by the service", name)
+	}
+	inUse, err := uc.repos.TransactionPurpose.InUse(ctx, name)
+	if err != nil {
+		return fmt.Errorf("failed to check transaction purpose usage: %w", err)
+	}
+	if inUse {
+		return apperrors.ErrTransactionPurposeInUse.Withf("transactions were recorded with purpose %s", name)
+	}
+	return uc.repos.TransactionPurpose.Delete(ctx, name)
+}
+
+// applyPurposeInput validates the description and hooks of a purpose and copies them onto its definition
+func applyPurposeInput(definition *models.TransactionPurposeDefinition, input TransactionPurposeInput) error {
+	description := strings.TrimSpace(input.Description)
+	if description == "" {
+		return apperrors.ErrValidation.Withf("description is required")
+	}
+	template := strings.TrimSpace(input.NotificationTemplate)
+	if template != "" {
+		if err := notifications.ValidatePurposeTemplate(template); err != nil {
+			return apperrors.ErrValidation.Withf("%v", err)
+		}
+	}
+	definition.Description = description
+	definition.ChargesFees = input.ChargesFees
+	definition.CountsTowardLimits = input.CountsTowardLimits
+	definition.NotificationTemplate = template
+	return nil
+}
+
+// purposeDefinition loads a transaction purpose with the hooks that tune how its transactions are
+// handled
+func purposeDefinition(ctx context.Context, repos *repositories.Repositories, name models.TransactionPurpose) (*models.TransactionPurposeDefinition, error) {
+	definition, err := repos.TransactionPurpose.GetByName(ctx, name)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, apperrors.ErrTransactionPurposeNotFound.Withf("transaction purpose %s not found", name)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to load transaction purpose %s: %w", name, err)
+	}
+	return definition, nil
+}
@@ -0,0 +1,112 @@
+package usecases
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/limistah/wallet-service/internal/apperrors"
+	"github.com/limistah/wallet-service/internal/models"
+	"github.com/limistah/wallet-service/internal/repositories/memory"
+	"github.com/shopspring/decimal"
+)
+
+func TestTransactionPurposeUseCase_CRUD(t *testing.T) {
+	repos := memory.NewRepositories()
+	purposeUC := NewTransactionPurposeUseCase(repos)
+	ctx := context.Background()
+
+	purposes, err := purposeUC.ListPurposes(ctx)
+	if err != nil || len(purposes) != len(models.DefaultTransactionPurposes()) {
+		t.Fatalf("Expected the default purposes, got %d, %v", len(purposes), err)
+	}
+
+	cases := []struct {
+		name     string
+		input    TransactionPurposeInput
+		expected error
+	}{
+		{"invalid name", TransactionPurposeInput{Name: "cash back!", Description: "Cashback"}, apperrors.ErrValidation},
+		{"name too long", TransactionPurposeInput{Name: "LOYALTY_POINTS_REDEMPTION", Description: "Points"}, apperrors.ErrValidation},
+		{"missing description", TransactionPurposeInput{Name: "CASHBACK", Description: " "}, apperrors.ErrValidation},
+		{"unknown template field", TransactionPurposeInput{Name: "CASHBACK", Description: "Cashback", NotificationTemplate: "{{.Event.Total}}"}, apperrors.ErrValidation},
+		{"unparsable template", TransactionPurposeInput{Name: "CASHBACK", Description: "Cashback", NotificationTemplate: "{{.Event.Amount"}, apperrors.ErrValidation},
+		{"existing purpose", TransactionPurposeInput{Name: "refund", Description: "Refund"}, apperrors.ErrTransactionPurposeExists},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			if _, err := purposeUC.CreatePurpose(ctx, tc.input); !errors.Is(err, tc.expected) {
+				t.Errorf("Expected %v, got: %v", tc.expected, err)
+			}
+		})
+	}
+
+	cashback, err := purposeUC.CreatePurpose(ctx, TransactionPurposeInput{Name: " cashback ", Description: "Cashback on card spend",
+		NotificationTemplate: "{{.Name}}, you earned {{.Event.Amount.StringFixed 2}} {{.Event.Currency}} cashback"})
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if cashback.Name != "CASHBACK" || cashback.ChargesFees || cashback.CountsTowardLimits {
+		t.Errorf("Expected an upper case purpose without fees or limits, got %+v", cashback)
+	}
+
+	updated, err := purposeUC.UpdatePurpose(ctx, "CASHBACK", TransactionPurposeInput{Description: "Cashback", CountsTowardLimits: true})
+	if err != nil || !updated.CountsTowardLimits || updated.NotificationTemplate != "" {
+		t.Errorf("Expected the hooks replaced, got %+v, %v", updated, err)
+	}
+	if _, err := purposeUC.UpdatePurpose(ctx, "LOYALTY", TransactionPurposeInput{Description: "Loyalty"}); !errors.Is(err, apperrors.ErrTransactionPurposeNotFound) {
+		t.Errorf("Expected an unknown purpose to be reported, got: %v", err)
+	}
+
+	if err := purposeUC.DeletePurpose(ctx, models.TransactionPurposeInterest); !errors.Is(err, apperrors.ErrTransactionPurposeInUse) {
+		t.Errorf("Expected a default purpose to stay, got: %v", err)
+	}
+	repos.Transaction.Create(ctx, &models.Transaction{WalletID: 1, Reference: "CB-1", TransactionPurpose: "CASHBACK",
+		TransactionType: models.TransactionTypeCredit, Amount: decimal.NewFromInt(5), Status: models.TransactionStatusCompleted})
+	if err := purposeUC.DeletePurpose(ctx, "CASHBACK"); !errors.Is(err, apperrors.ErrTransactionPurposeInUse) {
+		t.Errorf("Expected a purpose with transactions to stay, got: %v", err)
+	}
+
+	if _, err := purposeUC.CreatePurpose(ctx, TransactionPurposeInput{Name: "PROMO", Description: "Promotional credit"}); err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if err := purposeUC.DeletePurpose(ctx, "PROMO"); err != nil {
+		t.Fatalf("Expected an unused purpose to be deleted, got: %v", err)
+	}
+	if _, err := purposeUC.GetPurpose(ctx, "PROMO"); !errors.Is(err, apperrors.ErrTransactionPurposeNotFound) {
+		t.Errorf("Expected the purpose to be gone, got: %v", err)
+	}
+}
+
+func TestTransactionPurpose_Hooks(t *testing.T) {
+	repos := memory.NewRepositories()
+	ctx := context.Background()
+	tier := &models.WalletTier{Name: "standard", TransferFeeFlat: decimal.NewFromInt(1), DailyDebitLimit: decimalPtr(100)}
+	repos.WalletTier.Create(ctx, tier)
+	repos.Wallet.Create(ctx, &models.Wallet{ID: 2, UserID: 2, Balance: decimal.NewFromInt(500), Currency: "USD", Status: models.WalletStatusActive, TierID: &tier.ID})
+	repos.Wallet.Create(ctx, &models.Wallet{ID: 3, UserID: 3, Currency: "USD", Status: models.WalletStatusActive})
+	repos.Transaction.Create(ctx, &models.Transaction{WalletID: 2, Reference: "FEE-1", TransactionPurpose: models.TransactionPurposeFee,
+		TransactionType: models.TransactionTypeDebit, Amount: decimal.NewFromInt(30), Status: models.TransactionStatusCompleted})
+	repos.Transaction.Create(ctx, &models.Transaction{WalletID: 2, Reference: "OUT-1", TransactionPurpose: models.TransactionPurposeTransfer,
+		TransactionType: models.TransactionTypeDebit, Amount: decimal.NewFromInt(60), Status: models.TransactionStatusCompleted})
+	walletUC := NewWalletUseCase(repos, nil).(*walletUseCase)
+	from, _ := repos.Wallet.GetByID(ctx, 2)
+	to, _ := repos.Wallet.GetByID(ctx, 3)
+
+	price, err := walletUC.priceTransfer(ctx, from, to, decimal.NewFromInt(40), nil)
+	if err != nil || !price.Fee.Equal(decimal.NewFromInt(1)) {
+		t.Errorf("Expected fees to be left out of the used limit and the tier fee charged, got %+v, %v", price, err)
+	}
+	if _, err := walletUC.priceTransfer(ctx, from, to, decimal.NewFromInt(41), nil); !errors.Is(err, apperrors.ErrTierLimitExceeded) {
+		t.Errorf("Expected the daily limit to apply, got: %v", err)
+	}
+
+	purposeUC := NewTransactionPurposeUseCase(repos)
+	if _, err := purposeUC.UpdatePurpose(ctx, models.TransactionPurposeTransfer, TransactionPurposeInput{Description: "Transfer between wallets"}); err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	price, err = walletUC.priceTransfer(ctx, from, to, decimal.NewFromInt(200), nil)
+	if err != nil || !price.Fee.IsZero() {
+		t.Errorf("Expected transfers free and outside the limits, got %+v, %v", price, err)
+	}
+}
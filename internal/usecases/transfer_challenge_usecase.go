@@ -0,0 +1,138 @@
+package usecases
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/limistah/wallet-service/internal/apperrors"
+	"github.com/limistah/wallet-service/internal/fraud"
+	"github.com/limistah/wallet-service/internal/models"
+	"github.com/limistah/wallet-service/internal/notifications"
+	"github.com/limistah/wallet-service/internal/repositories"
+	"github.com/limistah/wallet-service/internal/utils"
+	"github.com/shopspring/decimal"
+	"gorm.io/gorm"
+)
+
+// TransferChallengePolicy controls which transfers need a step-up confirmation
+type TransferChallengePolicy struct {
+	// Threshold is the amount above which transfers are held until confirmed
+	Threshold decimal.Decimal
+	// TTL is how long the confirmation code stays valid
+	TTL time.Duration
+}
+
+type transferChallengeUseCase struct {
+	repos         *repositories.Repositories
+	walletUseCase WalletUseCase
+	smsSender     notifications.SMSSender
+	settings      *LiveSettings
+	now           func() time.Time
+}
+
+// NewTransferChallengeUseCase creates a new transfer challenge use case; without a policy no transfer
+// is challenged
+func NewTransferChallengeUseCase(repos *repositories.Repositories, walletUseCase WalletUseCase, opts ...Option) TransferChallengeUseCase {
+	o := newOptions(opts)
+	return &transferChallengeUseCase{
+		repos:         repos,
+		walletUseCase: walletUseCase,
+		smsSender:     o.smsSender,
+		settings:      o.settings,
+		now:           time.Now,
+	}
+}
+
+// StartTransfer holds a transfer above the threshold and sends a one-time code to the user's verified
+// phone; a transfer made with a quote is confirmed with the quote's terms. It returns no challenge for
+// transfers that can go through straight away
+func (uc *transferChallengeUseCase) StartTransfer(ctx context.Context, userID, fromWalletID, toWalletID uint, amount decimal.Decimal, reference, description string, quoteID *uint) (*models.TransferChallenge, error) {
+	policy := uc.settings.Load().TransferChallenge
+	if policy == nil || !amount.GreaterThan(policy.Threshold) {
+		return nil, nil
+	}
+
+	user, err := uc.repos.User.GetByID(ctx, userID)
+	if err != nil {
+		return nil, apperrors.ErrUserNotFound
+	}
+	if !user.HasVerifiedPhone() {
+		return nil, apperrors.ErrStepUpPhoneRequired.Withf("verify a phone number to confirm transfers above %s", policy.Threshold.StringFixed(2))
+	}
+
+	now := uc.now()
+	if err := uc.repos.TransferChallenge.DeleteExpired(ctx, now); err != nil {
+		log.Printf("Failed to purge expired transfer challenges: %v", err)
+	}
+
+	code := utils.GenerateNumericCode(6)
+	challenge := &models.TransferChallenge{
+		UserID:       userID,
+		FromWalletID: fromWalletID,
+		ToWalletID:   toWalletID,
+		Amount:       amount,
+		Reference:    reference,
+		Description:  description,
+		QuoteID:      quoteID,
+		ExpiresAt:    now.Add(policy.TTL),
+	}
+	if err := challenge.SetCode(code); err != nil {
+		return nil, fmt.Errorf("failed to secure confirmation code: %w", err)
+	}
+	if err := uc.repos.TransferChallenge.Create(ctx, challenge); err != nil {
+		return nil, err
+	}
+
+	message := fmt.Sprintf("Your code to confirm the transfer of %s is %s. It expires in %d minutes. Never share it.",
+		amount.StringFixed(2), code, int(policy.TTL.Minutes()))
+	if err := uc.smsSender.Send(user.PhoneNumber, message); err != nil {
+		return nil, apperrors.ErrDeliveryFailed.Withf("failed to send confirmation code: %w", err)
+	}
+
+	return challenge, nil
+}
+
+// CheckUnchallenged refuses an amount above the threshold for payments that cannot be held for a
+// one-time code, such as split payments
+func (uc *transferChallengeUseCase) CheckUnchallenged(ctx context.Context, amount decimal.Decimal) error {
+	return checkUnchallenged(uc.settings.Load().TransferChallenge, amount)
+}
+
+// ConfirmTransfer checks the code of a challenge and makes the transfer it holds. A challenge is
+// consumed by its first successful confirmation, even when the transfer then fails
+func (uc *transferChallengeUseCase) ConfirmTransfer(ctx context.Context, userID, challengeID uint, code string, origin *fraud.Origin) (*models.Transaction, *models.Transaction, error) {
+	challenge, err := uc.repos.TransferChallenge.GetByID(ctx, challengeID)
+	if errors.Is(err, gorm.ErrRecordNotFound) || (err == nil && challenge.UserID != userID) {
+		return nil, nil, apperrors.ErrChallengeNotFound
+	}
+	if err != nil {
+		return nil, nil, err
+	}
+
+	now := uc.now()
+	if !challenge.IsUsable(now) {
+		return nil, nil, apperrors.ErrChallengeExpired
+	}
+	if !challenge.CheckCode(code) {
+		if err := uc.repos.TransferChallenge.IncrementAttempts(ctx, challenge.ID); err != nil {
+			return nil, nil, err
+		}
+		return nil, nil, apperrors.ErrInvalidChallengeCode
+	}
+
+	confirmed, err := uc.repos.TransferChallenge.MarkConfirmed(ctx, challenge.ID, now)
+	if err != nil {
+		return nil, nil, err
+	}
+	if !confirmed {
+		return nil, nil, apperrors.ErrChallengeExpired
+	}
+
+	if challenge.QuoteID != nil {
+		return uc.walletUseCase.TransferFundsWithQuote(ctx, *challenge.QuoteID, challenge.FromWalletID, challenge.ToWalletID, challenge.Amount, challenge.Reference, challenge.Description, origin)
+	}
+	return uc.walletUseCase.TransferFunds(ctx, challenge.FromWalletID, challenge.ToWalletID, challenge.Amount, challenge.Reference, challenge.Description, origin)
+}
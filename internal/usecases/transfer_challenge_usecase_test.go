@@ -0,0 +1,140 @@
+package usecases
+
+import (
+	"context"
+	"errors"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/limistah/wallet-service/internal/apperrors"
+	"github.com/limistah/wallet-service/internal/models"
+	"github.com/limistah/wallet-service/internal/repositories/memory"
+	"github.com/shopspring/decimal"
+)
+
+// recordingSMSSender keeps the messages it is asked to send
+type recordingSMSSender struct {
+	messages []string
+}
+
+func (s *recordingSMSSender) Send(to, message string) error {
+	s.messages = append(s.messages, message)
+	return nil
+}
+
+func newTestTransferChallenge(t *testing.T, now *time.Time) (*transferChallengeUseCase, *transferringWalletUseCase, *recordingSMSSender) {
+	t.Helper()
+	repos, _ := setupTestEnvironment()
+	repos.TransferChallenge = memory.NewTransferChallengeRepository()
+
+	verifiedAt := *now
+	repos.User.Create(context.Background(), &models.User{ID: 2, Email: "verified@example.com", PhoneNumber: "+2348012345678", PhoneVerifiedAt: &verifiedAt})
+	repos.User.Create(context.Background(), &models.User{ID: 3, Email: "unverified@example.com"})
+	repos.Wallet.Create(context.Background(), &models.Wallet{ID: 2, UserID: 2, Currency: "USD", Status: models.WalletStatusActive})
+
+	walletUC := &transferringWalletUseCase{wallets: repos.Wallet.(*memory.WalletRepository)}
+	sender := &recordingSMSSender{}
+	uc := NewTransferChallengeUseCase(repos, walletUC, WithSMSSender(sender), WithTransferChallenge(TransferChallengePolicy{
+		Threshold: decimal.NewFromInt(1000),
+		TTL:       5 * time.Minute,
+	})).(*transferChallengeUseCase)
+	uc.now = func() time.Time { return *now }
+	return uc, walletUC, sender
+}
+
+// sentCode extracts the code from the last message
+func sentCode(t *testing.T, sender *recordingSMSSender) string {
+	t.Helper()
+	if len(sender.messages) == 0 {
+		t.Fatal("Expected a confirmation code to be sent")
+	}
+	code := regexp.MustCompile(`\b\d{6}\b`).FindString(sender.messages[len(sender.messages)-1])
+	if code == "" {
+		t.Fatalf("Expected a 6 digit code in %q", sender.messages[len(sender.messages)-1])
+	}
+	return code
+}
+
+func TestTransferChallengeUseCase_OnlyChallengesLargeTransfers(t *testing.T) {
+	now := time.Date(2025, 3, 1, 12, 0, 0, 0, time.UTC)
+	uc, _, sender := newTestTransferChallenge(t, &now)
+
+	challenge, err := uc.StartTransfer(context.Background(), 2, 2, 5, decimal.NewFromInt(1000), "TRF001", "", nil)
+	if err != nil || challenge != nil {
+		t.Errorf("Expected transfers up to the threshold to go through, got %v, %v", challenge, err)
+	}
+	if len(sender.messages) != 0 {
+		t.Errorf("Expected no code to be sent, got %v", sender.messages)
+	}
+
+	if _, err := uc.StartTransfer(context.Background(), 3, 3, 5, decimal.NewFromInt(5000), "TRF002", "", nil); !errors.Is(err, apperrors.ErrStepUpPhoneRequired) {
+		t.Errorf("Expected users without a verified phone to be refused, got %v", err)
+	}
+
+	uc.settings.Store(RuntimeSettings{})
+	if challenge, err := uc.StartTransfer(context.Background(), 2, 2, 5, decimal.NewFromInt(5000), "TRF003", "", nil); err != nil || challenge != nil {
+		t.Errorf("Expected no challenge without a policy, got %v, %v", challenge, err)
+	}
+}
+
+func TestTransferChallengeUseCase_ConfirmTransfer(t *testing.T) {
+	now := time.Date(2025, 3, 1, 12, 0, 0, 0, time.UTC)
+	uc, walletUC, sender := newTestTransferChallenge(t, &now)
+
+	challenge, err := uc.StartTransfer(context.Background(), 2, 2, 5, decimal.NewFromInt(5000), "TRF001", "Rent", nil)
+	if err != nil || challenge == nil {
+		t.Fatalf("Expected the transfer to be challenged, got %v, %v", challenge, err)
+	}
+	if !challenge.ExpiresAt.Equal(now.Add(5 * time.Minute)) {
+		t.Errorf("Expected the challenge to expire after the TTL, got %s", challenge.ExpiresAt)
+	}
+	code := sentCode(t, sender)
+
+	if _, _, err := uc.ConfirmTransfer(context.Background(), 3, challenge.ID, code, nil); !errors.Is(err, apperrors.ErrChallengeNotFound) {
+		t.Errorf("Expected another user's challenge to be hidden, got %v", err)
+	}
+	if _, _, err := uc.ConfirmTransfer(context.Background(), 2, challenge.ID, "000000", nil); !errors.Is(err, apperrors.ErrInvalidChallengeCode) {
+		t.Errorf("Expected a wrong code to be refused, got %v", err)
+	}
+	if len(walletUC.transfers) != 0 {
+		t.Fatalf("Expected no transfer before confirmation, got %v", walletUC.transfers)
+	}
+
+	outTx, _, err := uc.ConfirmTransfer(context.Background(), 2, challenge.ID, code, nil)
+	if err != nil {
+		t.Fatalf("Expected the transfer to be confirmed, got %v", err)
+	}
+	if outTx.Reference != "TRF001" || !outTx.Amount.Equal(decimal.NewFromInt(5000)) {
+		t.Errorf("Expected the held transfer to be made, got %+v", outTx)
+	}
+
+	if _, _, err := uc.ConfirmTransfer(context.Background(), 2, challenge.ID, code, nil); !errors.Is(err, apperrors.ErrChallengeExpired) {
+		t.Errorf("Expected a confirmed challenge not to be reusable, got %v", err)
+	}
+	if len(walletUC.transfers) != 1 {
+		t.Errorf("Expected exactly one transfer, got %v", walletUC.transfers)
+	}
+}
+
+func TestTransferChallengeUseCase_ExpiresAndLimitsAttempts(t *testing.T) {
+	now := time.Date(2025, 3, 1, 12, 0, 0, 0, time.UTC)
+	uc, _, sender := newTestTransferChallenge(t, &now)
+
+	expiring, _ := uc.StartTransfer(context.Background(), 2, 2, 5, decimal.NewFromInt(5000), "TRF001", "", nil)
+	expiringCode := sentCode(t, sender)
+	guessed, _ := uc.StartTransfer(context.Background(), 2, 2, 5, decimal.NewFromInt(5000), "TRF002", "", nil)
+	guessedCode := sentCode(t, sender)
+
+	for i := 0; i < models.MaxTransferChallengeAttempts; i++ {
+		uc.ConfirmTransfer(context.Background(), 2, guessed.ID, "000000", nil)
+	}
+	if _, _, err := uc.ConfirmTransfer(context.Background(), 2, guessed.ID, guessedCode, nil); !errors.Is(err, apperrors.ErrChallengeExpired) {
+		t.Errorf("Expected the challenge to be invalidated after too many wrong codes, got %v", err)
+	}
+
+	now = now.Add(6 * time.Minute)
+	if _, _, err := uc.ConfirmTransfer(context.Background(), 2, expiring.ID, expiringCode, nil); !errors.Is(err, apperrors.ErrChallengeExpired) {
+		t.Errorf("Expected the challenge to expire, got %v", err)
+	}
+}
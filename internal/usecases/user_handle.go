@@ -0,0 +1,55 @@
+package usecases
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/limistah/wallet-service/internal/apperrors"
+	"github.com/limistah/wallet-service/internal/models"
+	"gorm.io/gorm"
+)
+
+// handlePattern is the form of handles: 3 to 30 lower case letters, digits and underscores, starting
+// with a letter
+var handlePattern = regexp.MustCompile(`^[a-z][a-z0-9_]{2,29}$`)
+
+// normalizeHandle drops the @ a handle is written with and lower-cases it, so @Alice and alice are the
+// same handle
+func normalizeHandle(handle string) string {
+	return strings.ToLower(strings.TrimPrefix(strings.TrimSpace(handle), "@"))
+}
+
+// SetHandle gives the user the handle other users pay them by; an empty handle removes it, freeing it
+// for others
+func (uc *userUseCase) SetHandle(ctx context.Context, id uint, handle string) (*models.User, error) {
+	user, err := uc.repos.User.GetByID(ctx, id)
+	if err != nil {
+		return nil, apperrors.ErrUserNotFound
+	}
+
+	var value *string
+	if handle = normalizeHandle(handle); handle != "" {
+		if !handlePattern.MatchString(handle) {
+			return nil, apperrors.ErrValidation.Withf("handle must be 3 to 30 letters, digits and underscores, starting with a letter")
+		}
+		owner, err := uc.repos.User.GetByHandle(ctx, handle)
+		if err == nil && owner.ID != user.ID {
+			return nil, apperrors.ErrHandleTaken
+		}
+		if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, fmt.Errorf("failed to check handle: %w", err)
+		}
+		value = &handle
+	}
+
+	if err := uc.repos.User.SetHandle(ctx, user.ID, value); errors.Is(err, gorm.ErrDuplicatedKey) {
+		return nil, apperrors.ErrHandleTaken
+	} else if err != nil {
+		return nil, fmt.Errorf("failed to set handle: %w", err)
+	}
+	user.Handle = value
+	return user, nil
+}
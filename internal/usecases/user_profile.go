@@ -0,0 +1,234 @@
+package usecases
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/limistah/wallet-service/internal/apperrors"
+	"github.com/limistah/wallet-service/internal/models"
+	"github.com/limistah/wallet-service/internal/storage"
+	"github.com/limistah/wallet-service/internal/utils"
+)
+
+// avatarTypes are the image types accepted as profile pictures, as sniffed from their content
+var avatarTypes = map[string]bool{
+	"image/png":  true,
+	"image/jpeg": true,
+	"image/gif":  true,
+	"image/webp": true,
+}
+
+// countryCodePattern matches ISO 3166-1 alpha-2 country codes
+var countryCodePattern = regexp.MustCompile(`^[A-Z]{2}$`)
+
+// currencyCodePattern matches ISO 4217 currency codes
+var currencyCodePattern = regexp.MustCompile(`^[A-Z]{3}$`)
+
+// ProfileUpdate changes the profile fields that are set and leaves the others as they are
+type ProfileUpdate struct {
+	Name *string
+	// PhoneNumber is stored unverified when it changes; an empty string removes it
+	PhoneNumber *string
+	DateOfBirth *time.Time
+	// Address replaces the whole postal address; an empty address removes it
+	Address *ProfileAddress
+	// DisplayCurrency is the currency balances are also shown in; an empty string removes it
+	DisplayCurrency *string
+}
+
+// ProfileAddress is the postal address of a user; Country is an ISO 3166-1 alpha-2 code
+type ProfileAddress struct {
+	Line1      string
+	Line2      string
+	City       string
+	State      string
+	PostalCode string
+	Country    string
+}
+
+// UpdateProfile validates and saves the changed profile fields of a user
+func (uc *userUseCase) UpdateProfile(ctx context.Context, id uint, update ProfileUpdate) (*models.User, error) {
+	user, err := uc.repos.User.GetByID(ctx, id)
+	if err != nil {
+		return nil, apperrors.ErrUserNotFound
+	}
+
+	if update.Name != nil {
+		name := strings.TrimSpace(*update.Name)
+		if len(name) < 2 || len(name) > 100 {
+			return nil, apperrors.ErrValidation.Withf("name must be between 2 and 100 characters")
+		}
+		user.Name = name
+	}
+
+	if update.PhoneNumber != nil && *update.PhoneNumber != user.PhoneNumber {
+		phone := strings.TrimSpace(*update.PhoneNumber)
+		if phone != "" && !utils.ValidatePhoneNumber(phone) {
+			return nil, apperrors.ErrInvalidPhoneNumber
+		}
+		// Only a confirmed code proves the user owns the new number
+		user.PhoneNumber = phone
+		user.PhoneVerifiedAt = nil
+	}
+
+	if update.DateOfBirth != nil {
+		birth := startOfDay(*update.DateOfBirth)
+		if birth.After(time.Now()) {
+			return nil, apperrors.ErrValidation.Withf("date of birth cannot be in the future")
+		}
+		if birth.Before(time.Now().AddDate(-150, 0, 0)) {
+			return nil, apperrors.ErrValidation.Withf("date of birth is more than 150 years ago")
+		}
+		user.DateOfBirth = &birth
+	}
+
+	if update.Address != nil {
+		address, err := validateAddress(*update.Address)
+		if err != nil {
+			return nil, err
+		}
+		user.AddressLine1 = address.Line1
+		user.AddressLine2 = address.Line2
+		user.City = address.City
+		user.State = address.State
+		user.PostalCode = address.PostalCode
+		user.Country = address.Country
+	}
+
+	if update.DisplayCurrency != nil {
+		currency := strings.ToUpper(strings.TrimSpace(*update.DisplayCurrency))
+		if err := uc.validateDisplayCurrency(ctx, user.ID, currency); err != nil {
+			return nil, err
+		}
+		user.DisplayCurrency = currency
+	}
+
+	if err := uc.repos.User.Update(ctx, user); err != nil {
+		return nil, err
+	}
+	return user, nil
+}
+
+// validateDisplayCurrency checks that the wallet of a user can be shown in currency; an empty
+// currency turns the display currency off
+func (uc *userUseCase) validateDisplayCurrency(ctx context.Context, userID uint, currency string) error {
+	if currency == "" {
+		return nil
+	}
+	if !currencyCodePattern.MatchString(currency) {
+		return apperrors.ErrValidation.Withf("display currency must be an ISO 4217 code")
+	}
+	if uc.exchangeRates == nil {
+		return apperrors.ErrValidation.Withf("display currencies are not available")
+	}
+	wallet, err := uc.repos.Wallet.GetByUserID(ctx, userID)
+	if err != nil {
+		return apperrors.ErrWalletNotFound
+	}
+	if _, err := uc.exchangeRates.Rate(wallet.Currency, currency); err != nil {
+		return apperrors.ErrValidation.Withf("no exchange rate from %s to %s", wallet.Currency, currency)
+	}
+	return nil
+}
+
+// validateAddress trims an address and checks it is empty or has at least a first line, a city and a
+// valid country
+func validateAddress(address ProfileAddress) (ProfileAddress, error) {
+	address = ProfileAddress{
+		Line1:      strings.TrimSpace(address.Line1),
+		Line2:      strings.TrimSpace(address.Line2),
+		City:       strings.TrimSpace(address.City),
+		State:      strings.TrimSpace(address.State),
+		PostalCode: strings.TrimSpace(address.PostalCode),
+		Country:    strings.ToUpper(strings.TrimSpace(address.Country)),
+	}
+	if address == (ProfileAddress{}) {
+		return address, nil
+	}
+
+	if address.Line1 == "" || address.City == "" || address.Country == "" {
+		return address, apperrors.ErrValidation.Withf("address needs line1, city and country")
+	}
+	if !countryCodePattern.MatchString(address.Country) {
+		return address, apperrors.ErrValidation.Withf("country must be an ISO 3166-1 alpha-2 code")
+	}
+	for field, limit := range map[string]struct {
+		value string
+		max   int
+	}{
+		"line1":       {address.Line1, 255},
+		"line2":       {address.Line2, 255},
+		"city":        {address.City, 100},
+		"state":       {address.State, 100},
+		"postal_code": {address.PostalCode, 20},
+	} {
+		if len(limit.value) > limit.max {
+			return address, apperrors.ErrValidation.Withf("%s must be at most %d characters", field, limit.max)
+		}
+	}
+	return address, nil
+}
+
+// SetAvatar stores a PNG, JPEG, GIF or WebP image as the profile picture of a user, replacing the
+// previous one
+func (uc *userUseCase) SetAvatar(ctx context.Context, id uint, content []byte) (*models.User, error) {
+	if uc.avatarStore == nil {
+		return nil, apperrors.ErrAvatarsNotConfigured
+	}
+	if len(content) > uc.avatarMaxBytes {
+		return nil, apperrors.ErrRequestTooLarge.Withf("avatar must be at most %d bytes", uc.avatarMaxBytes)
+	}
+	if !avatarTypes[http.DetectContentType(content)] {
+		return nil, apperrors.ErrInvalidAvatar
+	}
+
+	user, err := uc.repos.User.GetByID(ctx, id)
+	if err != nil {
+		return nil, apperrors.ErrUserNotFound
+	}
+
+	key := avatarKey(user.ID)
+	if err := uc.avatarStore.Put(key, content); err != nil {
+		return nil, fmt.Errorf("failed to store avatar: %w", err)
+	}
+	now := time.Now()
+	user.AvatarKey = key
+	user.AvatarUpdatedAt = &now
+	if err := uc.repos.User.Update(ctx, user); err != nil {
+		return nil, err
+	}
+	return user, nil
+}
+
+// GetAvatar returns the profile picture of a user and its content type
+func (uc *userUseCase) GetAvatar(ctx context.Context, id uint) ([]byte, string, error) {
+	if uc.avatarStore == nil {
+		return nil, "", apperrors.ErrAvatarsNotConfigured
+	}
+	user, err := uc.repos.User.GetByID(ctx, id)
+	if err != nil {
+		return nil, "", apperrors.ErrUserNotFound
+	}
+	if !user.HasAvatar() {
+		return nil, "", apperrors.ErrAvatarNotFound
+	}
+
+	content, err := uc.avatarStore.Get(user.AvatarKey)
+	if errors.Is(err, storage.ErrNotFound) {
+		return nil, "", apperrors.ErrAvatarNotFound
+	}
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to read avatar: %w", err)
+	}
+	return content, http.DetectContentType(content), nil
+}
+
+// avatarKey is where the profile picture of a user is stored; a new upload overwrites the old one
+func avatarKey(userID uint) string {
+	return fmt.Sprintf("avatars/%d", userID)
+}
@@ -0,0 +1,100 @@
+package usecases
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/limistah/wallet-service/internal/apperrors"
+	"github.com/limistah/wallet-service/internal/models"
+	"github.com/limistah/wallet-service/internal/repositories"
+	"github.com/limistah/wallet-service/internal/repositories/memory"
+	"github.com/limistah/wallet-service/internal/storage"
+)
+
+func TestUserUseCase_UpdateProfile(t *testing.T) {
+	userRepo := memory.NewUserRepository()
+	verifiedAt := time.Now()
+	userRepo.Create(context.Background(), &models.User{ID: 1, Name: "Ada", Email: "ada@example.com", PhoneNumber: "+2348012345678", PhoneVerifiedAt: &verifiedAt})
+	uc := NewUserUseCase(&repositories.Repositories{User: userRepo})
+
+	name, phone := "Ada Lovelace", "+2348098765432"
+	birth := time.Date(1990, 4, 21, 0, 0, 0, 0, time.UTC)
+	user, err := uc.UpdateProfile(context.Background(), 1, ProfileUpdate{
+		Name:        &name,
+		PhoneNumber: &phone,
+		DateOfBirth: &birth,
+		Address:     &ProfileAddress{Line1: " 12 Marina Road ", City: "Lagos", Country: "ng"},
+	})
+	if err != nil {
+		t.Fatalf("UpdateProfile() error = %v", err)
+	}
+	if user.Name != name || user.AddressLine1 != "12 Marina Road" || user.Country != "NG" || !user.DateOfBirth.Equal(birth) {
+		t.Errorf("Expected the profile to be updated, got %+v", user)
+	}
+	if user.PhoneNumber != phone || user.HasVerifiedPhone() {
+		t.Errorf("Expected a changed phone number to need verification again, got %q verified %v", user.PhoneNumber, user.PhoneVerifiedAt)
+	}
+
+	// Fields left out are kept
+	user, err = uc.UpdateProfile(context.Background(), 1, ProfileUpdate{})
+	if err != nil || user.City != "Lagos" || user.Name != name {
+		t.Errorf("Expected an empty update to keep the profile, got %+v, %v", user, err)
+	}
+
+	future := time.Now().AddDate(1, 0, 0)
+	badPhone := "08012345678"
+	for name, update := range map[string]ProfileUpdate{
+		"future birth date":    {DateOfBirth: &future},
+		"address without city": {Address: &ProfileAddress{Line1: "12 Marina Road", Country: "NG"}},
+		"unknown country":      {Address: &ProfileAddress{Line1: "12 Marina Road", City: "Lagos", Country: "Nigeria"}},
+	} {
+		if _, err := uc.UpdateProfile(context.Background(), 1, update); !errors.Is(err, apperrors.ErrValidation) {
+			t.Errorf("%s: expected ErrValidation, got %v", name, err)
+		}
+	}
+	if _, err := uc.UpdateProfile(context.Background(), 1, ProfileUpdate{PhoneNumber: &badPhone}); !errors.Is(err, apperrors.ErrInvalidPhoneNumber) {
+		t.Errorf("Expected ErrInvalidPhoneNumber, got %v", err)
+	}
+
+	user, err = uc.UpdateProfile(context.Background(), 1, ProfileUpdate{Address: &ProfileAddress{}})
+	if err != nil || user.AddressLine1 != "" || user.Country != "" {
+		t.Errorf("Expected an empty address to remove it, got %+v, %v", user, err)
+	}
+}
+
+func TestUserUseCase_Avatar(t *testing.T) {
+	userRepo := memory.NewUserRepository()
+	userRepo.Create(context.Background(), &models.User{ID: 1, Email: "ada@example.com"})
+	repos := &repositories.Repositories{User: userRepo}
+
+	if _, err := NewUserUseCase(repos).SetAvatar(context.Background(), 1, nil); !errors.Is(err, apperrors.ErrAvatarsNotConfigured) {
+		t.Fatalf("Expected ErrAvatarsNotConfigured without storage, got %v", err)
+	}
+
+	uc := NewUserUseCase(repos, WithAvatarStorage(storage.NewMemoryStore(), 1024))
+	if _, _, err := uc.GetAvatar(context.Background(), 1); !errors.Is(err, apperrors.ErrAvatarNotFound) {
+		t.Errorf("Expected ErrAvatarNotFound before an upload, got %v", err)
+	}
+	if _, err := uc.SetAvatar(context.Background(), 1, []byte("<svg onload=alert(1)>")); !errors.Is(err, apperrors.ErrInvalidAvatar) {
+		t.Errorf("Expected ErrInvalidAvatar for a non image, got %v", err)
+	}
+	png := append([]byte("\x89PNG\r\n\x1a\n"), make([]byte, 64)...)
+	if _, err := uc.SetAvatar(context.Background(), 1, append(png, make([]byte, 1024)...)); !errors.Is(err, apperrors.ErrRequestTooLarge) {
+		t.Errorf("Expected ErrRequestTooLarge for a large image, got %v", err)
+	}
+
+	user, err := uc.SetAvatar(context.Background(), 1, png)
+	if err != nil {
+		t.Fatalf("SetAvatar() error = %v", err)
+	}
+	if !user.HasAvatar() || user.AvatarUpdatedAt == nil {
+		t.Errorf("Expected the avatar to be recorded, got %+v", user)
+	}
+	content, contentType, err := uc.GetAvatar(context.Background(), 1)
+	if err != nil || contentType != "image/png" || !bytes.Equal(content, png) {
+		t.Errorf("GetAvatar() = %d bytes, %q, %v", len(content), contentType, err)
+	}
+}
@@ -1,54 +1,85 @@
 package usecases
 
 import (
+	"context"
 	"errors"
+	"log"
+	"strings"
+	"time"
 
+	"github.com/limistah/wallet-service/internal/apperrors"
+	"github.com/limistah/wallet-service/internal/compliance"
+	"github.com/limistah/wallet-service/internal/fx"
 	"github.com/limistah/wallet-service/internal/models"
+	"github.com/limistah/wallet-service/internal/passwords"
 	"github.com/limistah/wallet-service/internal/repositories"
+	"github.com/limistah/wallet-service/internal/storage"
 	"github.com/limistah/wallet-service/internal/utils"
+	"golang.org/x/crypto/bcrypt"
 	"gorm.io/gorm"
 )
 
 type userUseCase struct {
-	repos *repositories.Repositories
+	repos            *repositories.Repositories
+	sanctionsChecker compliance.SanctionsChecker
+	passwordPolicy   passwords.Policy
+	breachChecker    passwords.BreachChecker
+	avatarStore      storage.BlobStore
+	avatarMaxBytes   int
+	exchangeRates    fx.RateProvider
 }
 
 // NewUserUseCase creates a new user use case
-func NewUserUseCase(repos *repositories.Repositories) UserUseCase {
-	return &userUseCase{repos: repos}
+func NewUserUseCase(repos *repositories.Repositories, opts ...Option) UserUseCase {
+	o := newOptions(opts)
+	return &userUseCase{
+		repos:            repos,
+		sanctionsChecker: o.sanctionsChecker,
+		passwordPolicy:   o.passwordPolicy,
+		breachChecker:    o.breachChecker,
+		avatarStore:      o.avatarStore,
+		avatarMaxBytes:   o.avatarMaxBytes,
+		exchangeRates:    o.exchangeRates,
+	}
 }
 
-func (uc *userUseCase) CreateUser(user *models.User) (*models.User, error) {
+func (uc *userUseCase) CreateUser(ctx context.Context, user *models.User) (*models.User, error) {
 	if err := utils.ValidateStruct(user); err != nil {
 		return nil, err
 	}
 
 	// Check if user already exists
-	existingUser, err := uc.repos.User.GetByEmail(user.Email)
+	existingUser, err := uc.repos.User.GetByEmail(ctx, user.Email)
 	if err == nil && existingUser != nil {
-		return nil, errors.New("user with this email already exists")
+		return nil, apperrors.ErrEmailTaken
 	}
 	if err != nil && err != gorm.ErrRecordNotFound {
 		return nil, err
 	}
 
+	subject := compliance.SanctionsSubject{Name: user.Name, Email: user.Email}
+	if err := screenSanctions(ctx, uc.repos, uc.sanctionsChecker, subject, models.ComplianceLog{}); err != nil {
+		return nil, err
+	}
+
 	// Use transaction to ensure data consistency
 	var createdUser *models.User
-	err = uc.repos.DB.Transaction(func(tx *gorm.DB) error {
+	err = uc.repos.UnitOfWork.Do(ctx, func(tx *repositories.Repositories) error {
 		// Create user within transaction
-		if err := tx.Create(user).Error; err != nil {
+		if err := tx.User.Create(ctx, user); err != nil {
 			return err
 		}
 		createdUser = user
 
 		// Create default wallet for the user within the same transaction
 		wallet := &models.Wallet{
-			UserID:   user.ID,
-			Currency: "USD",
-			Status:   models.WalletStatusActive,
+			UserID:    user.ID,
+			Currency:  "USD",
+			Status:    models.WalletStatusActive,
+			IsDefault: true,
 		}
 
-		if err := tx.Create(wallet).Error; err != nil {
+		if err := tx.Wallet.Create(ctx, wallet); err != nil {
 			return errors.New("failed to create user wallet")
 		}
 
@@ -62,20 +93,20 @@ func (uc *userUseCase) CreateUser(user *models.User) (*models.User, error) {
 	return createdUser, nil
 }
 
-func (uc *userUseCase) GetUser(id uint) (*models.User, error) {
-	return uc.repos.User.GetByID(id)
+func (uc *userUseCase) GetUser(ctx context.Context, id uint) (*models.User, error) {
+	return uc.repos.User.GetByID(ctx, id)
 }
 
-func (uc *userUseCase) GetUserByID(id uint) (*models.User, error) {
-	return uc.repos.User.GetByID(id)
+func (uc *userUseCase) GetUserByID(ctx context.Context, id uint) (*models.User, error) {
+	return uc.repos.User.GetByID(ctx, id)
 }
 
-func (uc *userUseCase) GetUserByEmail(email string) (*models.User, error) {
-	return uc.repos.User.GetByEmail(email)
+func (uc *userUseCase) GetUserByEmail(ctx context.Context, email string) (*models.User, error) {
+	return uc.repos.User.GetByEmail(ctx, email)
 }
 
-func (uc *userUseCase) UpdateUser(id uint, updatedUser *models.User) (*models.User, error) {
-	user, err := uc.repos.User.GetByID(id)
+func (uc *userUseCase) UpdateUser(ctx context.Context, id uint, updatedUser *models.User) (*models.User, error) {
+	user, err := uc.repos.User.GetByID(ctx, id)
 	if err != nil {
 		return nil, err
 	}
@@ -93,7 +124,7 @@ func (uc *userUseCase) UpdateUser(id uint, updatedUser *models.User) (*models.Us
 		user.Password = updatedUser.Password
 	}
 
-	err = uc.repos.User.Update(user)
+	err = uc.repos.User.Update(ctx, user)
 	if err != nil {
 		return nil, err
 	}
@@ -101,11 +132,98 @@ func (uc *userUseCase) UpdateUser(id uint, updatedUser *models.User) (*models.Us
 	return user, nil
 }
 
-func (uc *userUseCase) DeleteUser(id uint) error {
-	return uc.repos.User.Delete(id)
+// ChangePassword replaces the password of a user after checking the current one, and signs out every
+// session so stolen tokens stop working. The new password must follow the password policy and differ
+// from the recent passwords of the user
+func (uc *userUseCase) ChangePassword(ctx context.Context, id uint, currentPassword, newPassword string) error {
+	user, err := uc.repos.User.GetByID(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	if err := user.CheckPassword(currentPassword); err != nil {
+		return apperrors.ErrIncorrectPassword
+	}
+	if err := uc.ValidatePassword(ctx, newPassword); err != nil {
+		return err
+	}
+	if err := uc.checkPasswordReuse(ctx, user, newPassword); err != nil {
+		return err
+	}
+
+	replacedHash := user.Password
+	if err := user.HashPassword(newPassword); err != nil {
+		return err
+	}
+	if uc.passwordPolicy.HistorySize > 0 {
+		if err := uc.repos.PasswordHistory.Create(ctx, &models.PasswordHistory{UserID: user.ID, PasswordHash: replacedHash}); err != nil {
+			return err
+		}
+	}
+
+	now := time.Now()
+	user.TokensRevokedAt = &now
+	if err := uc.repos.User.Update(ctx, user); err != nil {
+		return err
+	}
+
+	if uc.passwordPolicy.HistorySize > 0 {
+		// The current password is checked from the user itself, so one entry less is needed
+		if err := uc.repos.PasswordHistory.Prune(ctx, user.ID, uc.passwordPolicy.HistorySize-1); err != nil {
+			log.Printf("Failed to prune password history of user %d: %v", user.ID, err)
+		}
+	}
+	return nil
+}
+
+// ValidatePassword checks a new password against the password policy and, when a breach checker is
+// set, the known data breaches. Passwords cannot be checked for breaches while the checker is down;
+// they are accepted rather than blocking registrations and password changes
+func (uc *userUseCase) ValidatePassword(ctx context.Context, password string) error {
+	if violations := uc.passwordPolicy.Violations(password); len(violations) > 0 {
+		return apperrors.ErrWeakPassword.Withf("password must %s", strings.Join(violations, ", "))
+	}
+
+	if uc.breachChecker != nil {
+		breached, err := uc.breachChecker.Breached(password)
+		if err != nil {
+			log.Printf("Failed to check password against known breaches: %v", err)
+		} else if breached {
+			return apperrors.ErrPasswordBreached
+		}
+	}
+	return nil
+}
+
+// checkPasswordReuse refuses the current password and the replaced passwords the policy remembers
+func (uc *userUseCase) checkPasswordReuse(ctx context.Context, user *models.User, password string) error {
+	if uc.passwordPolicy.HistorySize <= 0 {
+		return nil
+	}
+	if user.CheckPassword(password) == nil {
+		return apperrors.ErrPasswordReused
+	}
+	if uc.passwordPolicy.HistorySize == 1 {
+		return nil
+	}
+
+	history, err := uc.repos.PasswordHistory.ListRecent(ctx, user.ID, uc.passwordPolicy.HistorySize-1)
+	if err != nil {
+		return err
+	}
+	for _, entry := range history {
+		if bcrypt.CompareHashAndPassword([]byte(entry.PasswordHash), []byte(password)) == nil {
+			return apperrors.ErrPasswordReused
+		}
+	}
+	return nil
+}
+
+func (uc *userUseCase) DeleteUser(ctx context.Context, id uint) error {
+	return uc.repos.User.Delete(ctx, id)
 }
 
-func (uc *userUseCase) ListUsers(page, pageSize int) ([]models.User, error) {
+func (uc *userUseCase) ListUsers(ctx context.Context, page, pageSize int) ([]models.User, error) {
 	offset := (page - 1) * pageSize
-	return uc.repos.User.List(offset, pageSize)
+	return uc.repos.User.List(ctx, offset, pageSize)
 }
@@ -0,0 +1,219 @@
+package usecases
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/limistah/wallet-service/internal/apperrors"
+	"github.com/limistah/wallet-service/internal/compliance"
+	"github.com/limistah/wallet-service/internal/fraud"
+	"github.com/limistah/wallet-service/internal/models"
+	"github.com/limistah/wallet-service/internal/repositories"
+	"github.com/shopspring/decimal"
+)
+
+// screenAML submits a debit above the screening threshold to the AML screener. Denied debits are
+// recorded in the compliance log and return an error; the wallet owner is not notified of either
+// outcome. Debits at or below the threshold and sandbox debits are allowed without screening
+func (uc *walletUseCase) screenAML(ctx context.Context, wallet, counterparty *models.Wallet, purpose models.TransactionPurpose, amount decimal.Decimal, reference string, bankAccount *models.BankAccount) (compliance.Result, error) {
+	if uc.amlScreener == nil || wallet.Sandbox || !amount.GreaterThan(uc.settings.Load().AMLThreshold) {
+		return compliance.Result{Decision: compliance.DecisionAllow}, nil
+	}
+
+	screening := compliance.Screening{
+		Reference:   reference,
+		Purpose:     purpose,
+		WalletID:    wallet.ID,
+		UserID:      wallet.UserID,
+		Name:        wallet.User.Name,
+		Email:       wallet.User.Email,
+		BankAccount: bankAccount,
+		Amount:      amount,
+		Currency:    wallet.Currency,
+	}
+	if counterparty != nil {
+		screening.CounterpartyWalletID = counterparty.ID
+		screening.CounterpartyName = counterparty.User.Name
+	}
+
+	result, err := uc.amlScreener.Screen(screening)
+	if err != nil {
+		return result, fmt.Errorf("AML screening failed: %w", err)
+	}
+	if result.Decision != compliance.DecisionDeny {
+		return result, nil
+	}
+
+	record := &models.ComplianceLog{
+		Check:     models.ComplianceCheckAML,
+		UserID:    &wallet.UserID,
+		WalletID:  &wallet.ID,
+		Purpose:   purpose,
+		Amount:    amount,
+		Reference: reference,
+		Subject:   strings.TrimSpace(uc.amlScreener.Name() + " " + result.CaseReference),
+		Reason:    result.Reason,
+	}
+	if counterparty != nil {
+		record.CounterpartyWalletID = &counterparty.ID
+	}
+	if err := uc.repos.ComplianceLog.Create(ctx, record); err != nil {
+		log.Printf("Failed to record AML denial for %s: %v", reference, err)
+	}
+
+	return result, apperrors.ErrAMLDenied
+}
+
+// screenNewRecipient checks the owner of a wallet the sender has not paid before against the
+// sanctions watchlist. Recipients already paid were screened on their first transfer
+func (uc *walletUseCase) screenNewRecipient(ctx context.Context, fromWallet, toWallet *models.Wallet, amount decimal.Decimal, reference string) error {
+	if uc.sanctionsChecker == nil || fromWallet.Sandbox {
+		return nil
+	}
+
+	known, err := uc.repos.Transaction.HasTransferBetween(ctx, fromWallet.ID, toWallet.ID)
+	if err != nil {
+		return fmt.Errorf("failed to check transfer history: %w", err)
+	}
+	if known {
+		return nil
+	}
+
+	subject := compliance.SanctionsSubject{Name: toWallet.User.Name, Email: toWallet.User.Email}
+	return screenSanctions(ctx, uc.repos, uc.sanctionsChecker, subject, models.ComplianceLog{
+		UserID:               &fromWallet.UserID,
+		WalletID:             &fromWallet.ID,
+		CounterpartyWalletID: &toWallet.ID,
+		Purpose:              models.TransactionPurposeTransfer,
+		Amount:               amount,
+		Reference:            reference,
+	})
+}
+
+// holdStatus is the status of the legs of a debit held for review. AML review parks the debit as
+// PENDING; otherwise it is held as PENDING_REVIEW by a fraud rule
+func holdStatus(screening compliance.Result) models.TransactionStatus {
+	if screening.Decision == compliance.DecisionReview {
+		return models.TransactionStatusPending
+	}
+	return models.TransactionStatusPendingReview
+}
+
+// createHoldReview queues a held debit for a decision. An AML review takes the place of a fraud flag
+// on the same debit, since the compliance officer decides on the whole transaction
+func (uc *walletUseCase) createHoldReview(ctx context.Context, tx *repositories.Repositories, verdict fraud.Verdict, screening compliance.Result, held *models.Transaction, currency string, fee decimal.Decimal, counterpartyWalletID *uint, destination *models.BankAccount) error {
+	if screening.Decision != compliance.DecisionReview {
+		return createFraudReview(ctx, tx, verdict, held, currency, fee, counterpartyWalletID, destination)
+	}
+
+	amlCase := &models.AMLCase{
+		TransactionID:        held.ID,
+		WalletID:             held.WalletID,
+		Purpose:              held.TransactionPurpose,
+		CounterpartyWalletID: counterpartyWalletID,
+		Amount:               held.Amount,
+		Fee:                  fee,
+		Currency:             currency,
+		Screener:             uc.amlScreener.Name(),
+		CaseReference:        screening.CaseReference,
+		Reason:               screening.Reason,
+		Status:               models.AMLCaseStatusPending,
+	}
+	if destination != nil {
+		amlCase.BankAccount = *destination
+	}
+	if err := tx.AMLCase.Create(ctx, amlCase); err != nil {
+		return fmt.Errorf("failed to create AML case: %w", err)
+	}
+	return nil
+}
+
+func (uc *walletUseCase) ListAMLCases(ctx context.Context, status models.AMLCaseStatus, page, pageSize int) ([]models.AMLCase, error) {
+	if page < 1 {
+		page = 1
+	}
+	if pageSize < 1 || pageSize > 100 {
+		pageSize = 20
+	}
+	return uc.repos.AMLCase.List(ctx, status, (page-1)*pageSize, pageSize)
+}
+
+// ApproveAMLCase clears a debit parked by AML screening
+func (uc *walletUseCase) ApproveAMLCase(ctx context.Context, caseID, reviewerID uint, note string) (*models.AMLCase, error) {
+	amlCase, wallet, err := uc.getPendingAMLCase(ctx, caseID)
+	if err != nil {
+		return nil, err
+	}
+
+	err = uc.releaseHeldDebit(ctx, amlCaseDebit(amlCase), wallet, func(tx *repositories.Repositories) error {
+		return resolveAMLCase(ctx, tx, amlCase, models.AMLCaseStatusApproved, reviewerID, note)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return uc.repos.AMLCase.GetByID(ctx, amlCase.ID)
+}
+
+// RejectAMLCase fails a debit parked by AML screening and returns the held amount and fee to the wallet
+func (uc *walletUseCase) RejectAMLCase(ctx context.Context, caseID, reviewerID uint, note string) (*models.AMLCase, error) {
+	amlCase, wallet, err := uc.getPendingAMLCase(ctx, caseID)
+	if err != nil {
+		return nil, err
+	}
+
+	err = uc.refundHeldDebit(ctx, amlCaseDebit(amlCase), wallet, "rejected after compliance review", func(tx *repositories.Repositories) error {
+		return resolveAMLCase(ctx, tx, amlCase, models.AMLCaseStatusRejected, reviewerID, note)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return uc.repos.AMLCase.GetByID(ctx, amlCase.ID)
+}
+
+func (uc *walletUseCase) getPendingAMLCase(ctx context.Context, caseID uint) (*models.AMLCase, *models.Wallet, error) {
+	amlCase, err := uc.repos.AMLCase.GetByID(ctx, caseID)
+	if err != nil {
+		return nil, nil, apperrors.ErrAMLCaseNotFound
+	}
+	if !amlCase.IsPending() {
+		return nil, nil, apperrors.ErrAMLCaseResolved
+	}
+	wallet, err := uc.repos.Wallet.GetByID(ctx, amlCase.WalletID)
+	if err != nil {
+		return nil, nil, apperrors.ErrWalletNotFound
+	}
+	return amlCase, wallet, nil
+}
+
+// resolveAMLCase records the decision, failing when another reviewer decided first
+func resolveAMLCase(ctx context.Context, tx *repositories.Repositories, amlCase *models.AMLCase, status models.AMLCaseStatus, reviewerID uint, note string) error {
+	resolved, err := tx.AMLCase.Resolve(ctx, amlCase.ID, status, reviewerID, note, time.Now())
+	if err != nil {
+		return fmt.Errorf("failed to update AML case: %w", err)
+	}
+	if !resolved {
+		return apperrors.ErrAMLCaseResolved
+	}
+	return nil
+}
+
+// amlCaseDebit describes the debit held by an AML case
+func amlCaseDebit(amlCase *models.AMLCase) heldDebit {
+	return heldDebit{
+		TransactionID:        amlCase.TransactionID,
+		WalletID:             amlCase.WalletID,
+		Purpose:              amlCase.Purpose,
+		CounterpartyWalletID: amlCase.CounterpartyWalletID,
+		Amount:               amlCase.Amount,
+		Fee:                  amlCase.Fee,
+		Currency:             amlCase.Currency,
+		BankAccount:          amlCase.BankAccount,
+		Reference:            amlCase.Transaction.Reference,
+		Description:          amlCase.Transaction.Description,
+	}
+}
@@ -0,0 +1,66 @@
+package usecases
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/limistah/wallet-service/internal/apperrors"
+	"github.com/limistah/wallet-service/internal/compliance"
+	"github.com/limistah/wallet-service/internal/models"
+	"github.com/shopspring/decimal"
+)
+
+// stubScreener returns a fixed result and records the screenings it receives
+type stubScreener struct {
+	result     compliance.Result
+	err        error
+	screenings []compliance.Screening
+}
+
+func (s *stubScreener) Name() string { return "stub" }
+
+func (s *stubScreener) Screen(screening compliance.Screening) (compliance.Result, error) {
+	s.screenings = append(s.screenings, screening)
+	return s.result, s.err
+}
+
+func TestWalletUseCase_AMLScreeningDeniesDebits(t *testing.T) {
+	repos, reconciliationUC := setupTestEnvironment()
+	repos.Wallet.Create(context.Background(), &models.Wallet{ID: 2, UserID: 2, Balance: decimal.NewFromInt(50000), Currency: "USD", Status: models.WalletStatusActive,
+		User: models.User{ID: 2, Name: "Ada Sender", Email: "ada@example.com"}})
+	repos.Wallet.Create(context.Background(), &models.Wallet{ID: 3, UserID: 3, Currency: "USD", Status: models.WalletStatusActive,
+		User: models.User{ID: 3, Name: "Bola Recipient"}})
+
+	screener := &stubScreener{result: compliance.Result{Decision: compliance.DecisionDeny, Reason: "counterparty risk", CaseReference: "CASE-1"}}
+	walletUC := NewWalletUseCase(repos, reconciliationUC, WithAMLScreener(screener, decimal.NewFromInt(10000)))
+
+	_, _, err := walletUC.TransferFunds(context.Background(), 2, 3, decimal.NewFromInt(15000), "AML001", "Large transfer", nil)
+	if !errors.Is(err, apperrors.ErrAMLDenied) {
+		t.Fatalf("Expected the transfer to be denied by AML screening, got %v", err)
+	}
+	if len(screener.screenings) != 1 {
+		t.Fatalf("Expected one screening, got %d", len(screener.screenings))
+	}
+	screening := screener.screenings[0]
+	if screening.Email != "ada@example.com" || screening.CounterpartyWalletID != 3 || screening.CounterpartyName != "Bola Recipient" ||
+		screening.Purpose != models.TransactionPurposeTransfer || !screening.Amount.Equal(decimal.NewFromInt(15000)) {
+		t.Errorf("Unexpected screening: %+v", screening)
+	}
+
+	logs, _ := repos.ComplianceLog.List(context.Background(), models.ComplianceCheckAML, 0, 20)
+	if len(logs) != 1 || logs[0].Reference != "AML001" || logs[0].Subject != "stub CASE-1" || logs[0].Reason != "counterparty risk" {
+		t.Errorf("Expected the denial to be recorded in the compliance log, got %+v", logs)
+	}
+
+	screener.err = errors.New("provider unavailable")
+	_, _, err = walletUC.WithdrawFunds(context.Background(), 2, decimal.NewFromInt(12000), "AML002", "Withdrawal", nil, nil)
+	if err == nil || !strings.HasPrefix(err.Error(), "AML screening failed") {
+		t.Fatalf("Expected screening failures to refuse the withdrawal, got %v", err)
+	}
+
+	if wallet, _ := repos.Wallet.GetByID(context.Background(), 2); !wallet.Balance.Equal(decimal.NewFromInt(50000)) {
+		t.Errorf("Expected refused debits to leave the balance untouched, got %s", wallet.Balance)
+	}
+}
@@ -0,0 +1,30 @@
+package usecases
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/limistah/wallet-service/internal/apperrors"
+	"github.com/limistah/wallet-service/internal/models"
+)
+
+// SetDefaultWallet makes a live wallet of the user the one payments addressed to them and fundings
+// without a wallet land in, in place of their previous default
+func (uc *walletUseCase) SetDefaultWallet(ctx context.Context, userID, walletID uint) (*models.Wallet, error) {
+	wallet, err := uc.repos.Wallet.GetByID(ctx, walletID)
+	if err != nil || wallet.UserID != userID {
+		return nil, apperrors.ErrWalletNotFound
+	}
+	if wallet.Sandbox {
+		return nil, apperrors.ErrValidation.Withf("a sandbox wallet cannot be the default wallet")
+	}
+	if !wallet.IsActive() {
+		return nil, apperrors.ErrWalletInactive
+	}
+
+	if err := uc.repos.Wallet.SetDefault(ctx, userID, wallet.ID); err != nil {
+		return nil, fmt.Errorf("failed to set default wallet: %w", err)
+	}
+	wallet.IsDefault = true
+	return wallet, nil
+}
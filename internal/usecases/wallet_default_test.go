@@ -0,0 +1,57 @@
+package usecases
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/limistah/wallet-service/internal/apperrors"
+	"github.com/limistah/wallet-service/internal/models"
+	"github.com/limistah/wallet-service/internal/repositories/memory"
+)
+
+func TestWalletUseCase_SetDefaultWallet(t *testing.T) {
+	repos := memory.NewRepositories()
+	ctx := context.Background()
+	for _, wallet := range []*models.Wallet{
+		{ID: 2, UserID: 2, Currency: "USD", Status: models.WalletStatusActive, IsDefault: true},
+		{ID: 3, UserID: 2, Currency: "USD", Status: models.WalletStatusActive},
+		{ID: 4, UserID: 2, Currency: "USD", Status: models.WalletStatusActive, Sandbox: true},
+		{ID: 5, UserID: 2, Currency: "USD", Status: models.WalletStatusSuspended},
+		{ID: 6, UserID: 3, Currency: "USD", Status: models.WalletStatusActive},
+	} {
+		repos.Wallet.Create(ctx, wallet)
+	}
+	walletUC := NewWalletUseCase(repos, nil)
+
+	cases := []struct {
+		name     string
+		walletID uint
+		expected error
+	}{
+		{"another user's wallet", 6, apperrors.ErrWalletNotFound},
+		{"unknown wallet", 9, apperrors.ErrWalletNotFound},
+		{"sandbox wallet", 4, apperrors.ErrValidation},
+		{"suspended wallet", 5, apperrors.ErrWalletInactive},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			if _, err := walletUC.SetDefaultWallet(ctx, 2, tc.walletID); !errors.Is(err, tc.expected) {
+				t.Errorf("Expected %v, got: %v", tc.expected, err)
+			}
+		})
+	}
+
+	wallet, err := walletUC.SetDefaultWallet(ctx, 2, 3)
+	if err != nil || !wallet.IsDefault {
+		t.Fatalf("Expected the wallet to be the default, got %+v, %v", wallet, err)
+	}
+	// Payments addressed to the user and fundings without a wallet now land in it
+	if wallet, err := walletUC.GetWalletByUserID(ctx, 2); err != nil || wallet.ID != 3 {
+		t.Errorf("Expected the default wallet, got %+v, %v", wallet, err)
+	}
+	previous, _ := repos.Wallet.GetByID(ctx, 2)
+	if previous.IsDefault {
+		t.Errorf("Expected the previous default to be cleared")
+	}
+}
@@ -0,0 +1,120 @@
+package usecases
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/limistah/wallet-service/internal/events"
+	"github.com/limistah/wallet-service/internal/models"
+	"github.com/limistah/wallet-service/internal/repositories"
+	"github.com/shopspring/decimal"
+	"gorm.io/gorm"
+)
+
+// expiryBatchSize caps how many pending debits one sweep cancels
+const expiryBatchSize = 100
+
+// expiredReason is reported to the wallet owner and kept in the status history of expired debits
+const expiredReason = "pending transaction expired"
+
+// errNotExpirable signals that a pending debit was settled concurrently or is waiting on a payout
+// the provider has accepted, and must be left alone
+var errNotExpirable = errors.New("pending transaction cannot be expired")
+
+// ExpirePendingTransactions cancels withdrawals and transfers still PENDING that were created before
+// the cutoff and returns the held amount and fee to the wallet. Payouts the provider has accepted are
+// left to its webhook since the money may already be on its way. Returns how many debits expired
+func (uc *walletUseCase) ExpirePendingTransactions(ctx context.Context, before time.Time) (int, error) {
+	pending, err := uc.repos.Transaction.GetPendingDebitsBefore(ctx, before, expiryBatchSize)
+	if err != nil {
+		return 0, fmt.Errorf("failed to load pending transactions: %w", err)
+	}
+
+	expired := 0
+	for i := range pending {
+		refund, err := uc.expirePendingDebit(ctx, &pending[i])
+		if err == errNotExpirable {
+			continue
+		}
+		if err != nil {
+			log.Printf("Failed to expire pending transaction %s: %v", pending[i].Reference, err)
+			continue
+		}
+		expired++
+
+		if wallet, err := uc.repos.Wallet.GetByID(ctx, pending[i].WalletID); err == nil {
+			uc.publisher.Publish(events.Event{
+				Type:          events.EventTransactionExpired,
+				UserID:        wallet.UserID,
+				WalletID:      wallet.ID,
+				TransactionID: pending[i].ID,
+				Reference:     pending[i].Reference,
+				Amount:        refund,
+				Currency:      wallet.Currency,
+				Reason:        expiredReason,
+				OccurredAt:    time.Now(),
+			})
+		}
+
+		uc.schedulePostTransactionAudit(pending[i].WalletID)
+	}
+
+	return expired, nil
+}
+
+// expirePendingDebit cancels a pending debit with its counter legs and fee legs, closes its payout or
+// AML case and refunds the wallet; it returns the refunded amount
+func (uc *walletUseCase) expirePendingDebit(ctx context.Context, debit *models.Transaction) (decimal.Decimal, error) {
+	refund := debit.Amount
+	err := uc.repos.UnitOfWork.Do(ctx, func(tx *repositories.Repositories) error {
+		payout, err := tx.Payout.GetByTransactionID(ctx, debit.ID)
+		if err == nil && (!payout.IsPending() || payout.ProviderReference != "") {
+			return errNotExpirable
+		}
+		if err != nil && err != gorm.ErrRecordNotFound {
+			return fmt.Errorf("failed to load payout: %w", err)
+		}
+
+		// Claim the debit first so a concurrent settlement or review decision wins or loses cleanly
+		claimed, err := tx.Transaction.UpdateStatus(ctx, debit.ID, models.TransactionStatusPending, models.TransactionStatusCancelled)
+		if err != nil {
+			return fmt.Errorf("failed to cancel transaction: %w", err)
+		}
+		if !claimed {
+			return errNotExpirable
+		}
+		if err := recordStatusChanges(ctx, tx, []models.Transaction{*debit}, models.TransactionStatusCancelled, expiredReason); err != nil {
+			return err
+		}
+
+		legs, err := heldLegs(ctx, tx, debit.ID)
+		if err != nil {
+			return err
+		}
+		for _, leg := range legs {
+			if leg.WalletID == debit.WalletID && leg.TransactionType == models.TransactionTypeDebit &&
+				leg.TransactionPurpose == models.TransactionPurposeFee {
+				refund = refund.Add(leg.Amount)
+			}
+		}
+		if err := updateLegStatus(ctx, tx, debit.ID, models.TransactionStatusCancelled, expiredReason); err != nil {
+			return err
+		}
+
+		if payout != nil {
+			if _, err := tx.Payout.Settle(ctx, payout.ID, models.PayoutStatusCancelled, expiredReason, time.Now()); err != nil {
+				return fmt.Errorf("failed to cancel payout: %w", err)
+			}
+		}
+		if err := tx.AMLCase.ExpireByTransactionID(ctx, debit.ID, time.Now()); err != nil {
+			return fmt.Errorf("failed to expire AML case: %w", err)
+		}
+
+		_, err = adjustBalance(ctx, tx.Wallet, debit.WalletID, refund)
+		return err
+	})
+	return refund, err
+}
@@ -0,0 +1,36 @@
+package usecases
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/limistah/wallet-service/internal/models"
+	"github.com/shopspring/decimal"
+)
+
+func TestWalletUseCase_ExpirePendingTransactionsSkipsRecentDebits(t *testing.T) {
+	repos, reconciliationUC := setupTestEnvironment()
+	walletUC := NewWalletUseCase(repos, reconciliationUC)
+
+	now := time.Now()
+	repos.Transaction.Create(context.Background(), &models.Transaction{WalletID: 1, Reference: "EXP001", Amount: decimal.NewFromInt(100),
+		TransactionType: models.TransactionTypeDebit, TransactionPurpose: models.TransactionPurposeWithdrawal,
+		Status: models.TransactionStatusPending, CreatedAt: now.Add(-time.Hour)})
+	repos.Transaction.Create(context.Background(), &models.Transaction{WalletID: 1, Reference: "EXP002", Amount: decimal.NewFromInt(100),
+		TransactionType: models.TransactionTypeDebit, TransactionPurpose: models.TransactionPurposeTransfer,
+		Status: models.TransactionStatusCompleted, CreatedAt: now.Add(-96 * time.Hour)})
+
+	expired, err := walletUC.ExpirePendingTransactions(context.Background(), now.Add(-72*time.Hour))
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if expired != 0 {
+		t.Errorf("Expected no transactions to expire, got %d", expired)
+	}
+
+	transaction, _ := repos.Transaction.GetByReference(context.Background(), "EXP001")
+	if transaction.Status != models.TransactionStatusPending {
+		t.Errorf("Expected the recent debit to stay pending, got %s", transaction.Status)
+	}
+}
@@ -0,0 +1,175 @@
+package usecases
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/limistah/wallet-service/internal/apperrors"
+	"github.com/limistah/wallet-service/internal/events"
+	"github.com/limistah/wallet-service/internal/fraud"
+	"github.com/limistah/wallet-service/internal/models"
+	"github.com/limistah/wallet-service/internal/repositories"
+	"github.com/shopspring/decimal"
+)
+
+// fraudHistory exposes the transaction history of wallets to fraud rules
+type fraudHistory struct {
+	ctx   context.Context
+	repos *repositories.Repositories
+}
+
+func (h fraudHistory) RecentDebits(walletID uint, since time.Time) ([]models.Transaction, error) {
+	return h.repos.Transaction.GetDebitsSince(h.ctx, walletID, since)
+}
+
+func (h fraudHistory) HasTransferredTo(walletID, counterpartyWalletID uint) (bool, error) {
+	return h.repos.Transaction.HasTransferBetween(h.ctx, walletID, counterpartyWalletID)
+}
+
+// screenDebit runs the fraud rules against a debit; blocked debits return an error, and both blocked
+// and flagged debits notify the wallet owner
+func (uc *walletUseCase) screenDebit(ctx context.Context, wallet *models.Wallet, check fraud.Check, reference string) (fraud.Verdict, error) {
+	verdict, err := uc.fraudEngine.Evaluate(check, fraudHistory{ctx: ctx, repos: uc.repos})
+	if err != nil {
+		return verdict, fmt.Errorf("fraud screening failed: %w", err)
+	}
+	if verdict.Action == fraud.ActionAllow {
+		return verdict, nil
+	}
+
+	uc.publisher.Publish(events.Event{
+		Type:       events.EventSuspiciousActivity,
+		UserID:     wallet.UserID,
+		WalletID:   wallet.ID,
+		Reference:  reference,
+		Amount:     check.Amount,
+		Currency:   wallet.Currency,
+		Reason:     verdict.Reason,
+		OccurredAt: time.Now(),
+	})
+
+	if verdict.Action == fraud.ActionBlock {
+		return verdict, apperrors.ErrFraudBlocked.Withf("transaction blocked by fraud rule %s: %s", verdict.Rule, verdict.Reason)
+	}
+	return verdict, nil
+}
+
+// setOrigin records where a user-initiated debit was requested from
+func setOrigin(transaction *models.Transaction, origin *fraud.Origin) {
+	if origin == nil {
+		return
+	}
+	transaction.OriginIP = origin.IPAddress
+	transaction.OriginCountry = origin.Country
+}
+
+// createFraudReview queues a held debit for an admin decision
+func createFraudReview(ctx context.Context, tx *repositories.Repositories, verdict fraud.Verdict, held *models.Transaction, currency string, fee decimal.Decimal, counterpartyWalletID *uint, destination *models.BankAccount) error {
+	review := &models.FraudReview{
+		TransactionID:        held.ID,
+		WalletID:             held.WalletID,
+		Purpose:              held.TransactionPurpose,
+		CounterpartyWalletID: counterpartyWalletID,
+		Amount:               held.Amount,
+		Fee:                  fee,
+		Currency:             currency,
+		Rule:                 verdict.Rule,
+		Reason:               verdict.Reason,
+		Status:               models.FraudReviewStatusPending,
+	}
+	if destination != nil {
+		review.BankAccount = *destination
+	}
+	if err := tx.FraudReview.Create(ctx, review); err != nil {
+		return fmt.Errorf("failed to create fraud review: %w", err)
+	}
+	return nil
+}
+
+func (uc *walletUseCase) ListFraudReviews(ctx context.Context, status models.FraudReviewStatus, page, pageSize int) ([]models.FraudReview, error) {
+	if page < 1 {
+		page = 1
+	}
+	if pageSize < 1 || pageSize > 100 {
+		pageSize = 20
+	}
+	return uc.repos.FraudReview.List(ctx, status, (page-1)*pageSize, pageSize)
+}
+
+// ApproveFraudReview releases a held debit
+func (uc *walletUseCase) ApproveFraudReview(ctx context.Context, reviewID, reviewerID uint, note string) (*models.FraudReview, error) {
+	review, wallet, err := uc.getPendingFraudReview(ctx, reviewID)
+	if err != nil {
+		return nil, err
+	}
+
+	err = uc.releaseHeldDebit(ctx, fraudReviewDebit(review), wallet, func(tx *repositories.Repositories) error {
+		return resolveFraudReview(ctx, tx, review, models.FraudReviewStatusApproved, reviewerID, note)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return uc.repos.FraudReview.GetByID(ctx, review.ID)
+}
+
+// RejectFraudReview fails a held debit and returns the held amount and fee to the wallet
+func (uc *walletUseCase) RejectFraudReview(ctx context.Context, reviewID, reviewerID uint, note string) (*models.FraudReview, error) {
+	review, wallet, err := uc.getPendingFraudReview(ctx, reviewID)
+	if err != nil {
+		return nil, err
+	}
+
+	err = uc.refundHeldDebit(ctx, fraudReviewDebit(review), wallet, "rejected after fraud review", func(tx *repositories.Repositories) error {
+		return resolveFraudReview(ctx, tx, review, models.FraudReviewStatusRejected, reviewerID, note)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return uc.repos.FraudReview.GetByID(ctx, review.ID)
+}
+
+func (uc *walletUseCase) getPendingFraudReview(ctx context.Context, reviewID uint) (*models.FraudReview, *models.Wallet, error) {
+	review, err := uc.repos.FraudReview.GetByID(ctx, reviewID)
+	if err != nil {
+		return nil, nil, apperrors.ErrFraudReviewNotFound
+	}
+	if !review.IsPending() {
+		return nil, nil, apperrors.ErrFraudReviewResolved
+	}
+	wallet, err := uc.repos.Wallet.GetByID(ctx, review.WalletID)
+	if err != nil {
+		return nil, nil, apperrors.ErrWalletNotFound
+	}
+	return review, wallet, nil
+}
+
+// resolveFraudReview records the decision, failing when another reviewer decided first
+func resolveFraudReview(ctx context.Context, tx *repositories.Repositories, review *models.FraudReview, status models.FraudReviewStatus, reviewerID uint, note string) error {
+	resolved, err := tx.FraudReview.Resolve(ctx, review.ID, status, reviewerID, note, time.Now())
+	if err != nil {
+		return fmt.Errorf("failed to update fraud review: %w", err)
+	}
+	if !resolved {
+		return apperrors.ErrFraudReviewResolved
+	}
+	return nil
+}
+
+// fraudReviewDebit describes the debit held by a fraud review
+func fraudReviewDebit(review *models.FraudReview) heldDebit {
+	return heldDebit{
+		TransactionID:        review.TransactionID,
+		WalletID:             review.WalletID,
+		Purpose:              review.Purpose,
+		CounterpartyWalletID: review.CounterpartyWalletID,
+		Amount:               review.Amount,
+		Fee:                  review.Fee,
+		Currency:             review.Currency,
+		BankAccount:          review.BankAccount,
+		Reference:            review.Transaction.Reference,
+		Description:          review.Transaction.Description,
+	}
+}
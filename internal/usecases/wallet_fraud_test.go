@@ -0,0 +1,53 @@
+package usecases
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/limistah/wallet-service/internal/events"
+	"github.com/limistah/wallet-service/internal/fraud"
+	"github.com/limistah/wallet-service/internal/models"
+	"github.com/shopspring/decimal"
+)
+
+func TestWalletUseCase_FraudRulesBlockDebits(t *testing.T) {
+	repos, reconciliationUC := setupTestEnvironment()
+	repos.Wallet.Create(context.Background(), &models.Wallet{ID: 2, UserID: 2, Balance: decimal.NewFromInt(5000), Currency: "USD", Status: models.WalletStatusActive})
+	repos.Wallet.Create(context.Background(), &models.Wallet{ID: 3, UserID: 3, Currency: "USD", Status: models.WalletStatusActive})
+
+	publisher := &recordingPublisher{}
+	engine := fraud.NewEngine(
+		fraud.NewRecipientRule{Threshold: decimal.NewFromInt(1000), Action: fraud.ActionBlock},
+		fraud.OriginAnomalyRule{Threshold: decimal.NewFromInt(100), Action: fraud.ActionBlock},
+	)
+	walletUC := NewWalletUseCase(repos, reconciliationUC, WithEventPublisher(publisher), WithFraudEngine(engine))
+
+	_, _, err := walletUC.TransferFunds(context.Background(), 2, 3, decimal.NewFromInt(1500), "FR001", "Large transfer", nil)
+	if err == nil || !strings.HasPrefix(err.Error(), "transaction blocked by fraud rule new_recipient") {
+		t.Fatalf("Expected the transfer to be blocked by the new recipient rule, got %v", err)
+	}
+	if len(publisher.events) != 1 || publisher.events[0].Type != events.EventSuspiciousActivity || publisher.events[0].Reference != "FR001" {
+		t.Errorf("Expected a suspicious activity event for the blocked transfer, got %+v", publisher.events)
+	}
+
+	repos.Transaction.Create(context.Background(), &models.Transaction{
+		ID:                 50,
+		WalletID:           2,
+		TransactionType:    models.TransactionTypeDebit,
+		TransactionPurpose: models.TransactionPurposeWithdrawal,
+		Amount:             decimal.NewFromInt(20),
+		Status:             models.TransactionStatusCompleted,
+		OriginIP:           "203.0.113.7",
+		OriginCountry:      "NG",
+		CreatedAt:          time.Now().Add(-time.Hour),
+	})
+	_, _, err = walletUC.WithdrawFunds(context.Background(), 2, decimal.NewFromInt(200), "FR002", "Withdrawal", nil, &fraud.Origin{IPAddress: "198.51.100.9", Country: "RU"})
+	if err == nil || !strings.HasPrefix(err.Error(), "transaction blocked by fraud rule origin_anomaly") {
+		t.Fatalf("Expected the withdrawal to be blocked by the origin rule, got %v", err)
+	}
+	if wallet, _ := repos.Wallet.GetByID(context.Background(), 2); !wallet.Balance.Equal(decimal.NewFromInt(5000)) {
+		t.Errorf("Expected blocked debits to leave the balance untouched, got %s", wallet.Balance)
+	}
+}
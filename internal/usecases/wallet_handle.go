@@ -0,0 +1,28 @@
+package usecases
+
+import (
+	"context"
+
+	"github.com/limistah/wallet-service/internal/apperrors"
+	"github.com/limistah/wallet-service/internal/models"
+)
+
+// ResolveHandle finds the default wallet of the user going by a handle, with the user, so they can be
+// paid without knowing a wallet ID. Accounts being deleted and system accounts cannot be paid and
+// are not found
+func (uc *walletUseCase) ResolveHandle(ctx context.Context, handle string) (*models.Wallet, error) {
+	handle = normalizeHandle(handle)
+	if handle == "" {
+		return nil, apperrors.ErrHandleNotFound
+	}
+	user, err := uc.repos.User.GetByHandle(ctx, handle)
+	if err != nil || user.IsSystemAccount() || user.DeletionRequestedAt != nil {
+		return nil, apperrors.ErrHandleNotFound.Withf("no user goes by @%s", handle)
+	}
+	wallet, err := uc.repos.Wallet.GetByUserID(ctx, user.ID)
+	if err != nil {
+		return nil, apperrors.ErrHandleNotFound.Withf("@%s has no wallet", handle)
+	}
+	wallet.User = *user
+	return wallet, nil
+}
@@ -0,0 +1,76 @@
+package usecases
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/limistah/wallet-service/internal/apperrors"
+	"github.com/limistah/wallet-service/internal/models"
+	"github.com/limistah/wallet-service/internal/repositories/memory"
+)
+
+func TestHandles(t *testing.T) {
+	repos := memory.NewRepositories()
+	ctx := context.Background()
+	deletionRequestedAt := time.Now()
+	for _, user := range []*models.User{
+		{ID: 2, Name: "Alice", Email: "alice@example.com"},
+		{ID: 3, Name: "Bob", Email: "bob@example.com"},
+		{ID: 4, Name: "Carol", Email: "carol@example.com", DeletionRequestedAt: &deletionRequestedAt},
+	} {
+		repos.User.Create(ctx, user)
+	}
+	repos.Wallet.Create(ctx, &models.Wallet{ID: 2, UserID: 2, Currency: "USD", Status: models.WalletStatusActive})
+	repos.Wallet.Create(ctx, &models.Wallet{ID: 3, UserID: 2, Currency: "EUR", Status: models.WalletStatusActive, IsDefault: true})
+	repos.Wallet.Create(ctx, &models.Wallet{ID: 4, UserID: 4, Currency: "USD", Status: models.WalletStatusActive})
+	userUC := NewUserUseCase(repos)
+	walletUC := NewWalletUseCase(repos, nil)
+
+	user, err := userUC.SetHandle(ctx, 2, " @Alice ")
+	if err != nil || user.Handle == nil || *user.Handle != "alice" {
+		t.Fatalf("Expected the handle stored without its @ in lower case, got %+v, %v", user, err)
+	}
+	cases := []struct {
+		name     string
+		handle   string
+		expected error
+	}{
+		{"taken", "@ALICE", apperrors.ErrHandleTaken},
+		{"too short", "bo", apperrors.ErrValidation},
+		{"starting with a digit", "2bob", apperrors.ErrValidation},
+		{"with a dash", "bob-smith", apperrors.ErrValidation},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			if _, err := userUC.SetHandle(ctx, 3, tc.handle); !errors.Is(err, tc.expected) {
+				t.Errorf("Expected %v, got: %v", tc.expected, err)
+			}
+		})
+	}
+	if _, err := userUC.SetHandle(ctx, 2, "alice"); err != nil {
+		t.Errorf("Expected the user to keep their own handle, got: %v", err)
+	}
+	if _, err := userUC.SetHandle(ctx, 4, "carol"); err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+
+	wallet, err := walletUC.ResolveHandle(ctx, "@Alice")
+	if err != nil || wallet.ID != 3 || wallet.User.Name != "Alice" {
+		t.Errorf("Expected the default wallet of the user, got %+v, %v", wallet, err)
+	}
+	for _, handle := range []string{"bob", "carol", "@"} {
+		if _, err := walletUC.ResolveHandle(ctx, handle); !errors.Is(err, apperrors.ErrHandleNotFound) {
+			t.Errorf("Expected %q not to be found, got: %v", handle, err)
+		}
+	}
+
+	// Removing a handle frees it for others
+	if user, err := userUC.SetHandle(ctx, 2, ""); err != nil || user.Handle != nil {
+		t.Fatalf("Expected the handle removed, got %+v, %v", user, err)
+	}
+	if _, err := userUC.SetHandle(ctx, 3, "alice"); err != nil {
+		t.Errorf("Expected the freed handle to be available, got: %v", err)
+	}
+}
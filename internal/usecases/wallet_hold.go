@@ -0,0 +1,197 @@
+package usecases
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/limistah/wallet-service/internal/apperrors"
+	"github.com/limistah/wallet-service/internal/events"
+	"github.com/limistah/wallet-service/internal/models"
+	"github.com/limistah/wallet-service/internal/repositories"
+	"github.com/shopspring/decimal"
+)
+
+// heldDebit is a withdrawal or transfer whose amount and fee stay debited from the wallet while a
+// fraud review or an AML case decides on it
+type heldDebit struct {
+	TransactionID        uint
+	WalletID             uint
+	Purpose              models.TransactionPurpose
+	CounterpartyWalletID *uint
+	Amount               decimal.Decimal
+	Fee                  decimal.Decimal
+	Currency             string
+	BankAccount          models.BankAccount
+	Reference            string
+	Description          string
+}
+
+// releaseHeldDebit completes a held debit once decide has recorded the approval in the same unit of
+// work. Transfers credit the recipient, instant withdrawals credit the system wallet, and
+// withdrawals through a payout provider are sent to the bank
+func (uc *walletUseCase) releaseHeldDebit(ctx context.Context, debit heldDebit, wallet *models.Wallet, decide func(tx *repositories.Repositories) error) error {
+	isPayout := debit.Purpose == models.TransactionPurposeWithdrawal && uc.payoutProvider != nil && !wallet.Sandbox
+	if isPayout && (debit.BankAccount.BankCode == "" || debit.BankAccount.AccountNumber == "") {
+		return apperrors.ErrBankAccountRequired
+	}
+
+	var payout *models.Payout
+	err := uc.repos.UnitOfWork.Do(ctx, func(tx *repositories.Repositories) error {
+		if err := decide(tx); err != nil {
+			return err
+		}
+
+		if isPayout {
+			// The system wallet is credited when the payout settles
+			if err := updateLegStatus(ctx, tx, debit.TransactionID, models.TransactionStatusPending, "approved after review; sent for payout"); err != nil {
+				return err
+			}
+			payout = &models.Payout{
+				WalletID:      debit.WalletID,
+				TransactionID: debit.TransactionID,
+				Reference:     debit.Reference,
+				Provider:      uc.payoutProvider.Name(),
+				Amount:        debit.Amount,
+				Fee:           debit.Fee,
+				Currency:      debit.Currency,
+				BankAccount:   debit.BankAccount,
+				Status:        models.PayoutStatusPending,
+			}
+			if err := tx.Payout.Create(ctx, payout); err != nil {
+				return fmt.Errorf("failed to create payout: %w", err)
+			}
+			return nil
+		}
+
+		legs, err := heldLegs(ctx, tx, debit.TransactionID)
+		if err != nil {
+			return err
+		}
+		for i := range legs {
+			if legs[i].TransactionType == models.TransactionTypeCredit {
+				if err := creditHeldLeg(ctx, tx, &legs[i]); err != nil {
+					return err
+				}
+			}
+		}
+		return updateLegStatus(ctx, tx, debit.TransactionID, models.TransactionStatusCompleted, "approved after review")
+	})
+	if err != nil {
+		return err
+	}
+
+	if isPayout {
+		if err := uc.dispatchPayout(ctx, payout, debit.Description); err != nil {
+			return apperrors.ErrPayoutFailed.Wrap(err)
+		}
+	} else if transaction, err := uc.repos.Transaction.GetByID(ctx, debit.TransactionID); err == nil {
+		if debit.Purpose == models.TransactionPurposeWithdrawal {
+			uc.publishTransactionEvent(events.EventWithdrawalCompleted, wallet, transaction)
+		} else {
+			uc.publishTransactionEvent(events.EventTransferSent, wallet, transaction)
+			if recipient, err := uc.repos.Wallet.GetByID(ctx, *debit.CounterpartyWalletID); err == nil && transaction.RelatedTransaction != nil {
+				uc.publishTransactionEvent(events.EventCreditReceived, recipient, transaction.RelatedTransaction)
+			}
+		}
+	}
+
+	uc.schedulePostTransactionAudit(debit.WalletID)
+
+	return nil
+}
+
+// refundHeldDebit fails a held debit and returns the held amount and fee to the wallet once decide
+// has recorded the rejection in the same unit of work; reason is reported to the wallet owner
+func (uc *walletUseCase) refundHeldDebit(ctx context.Context, debit heldDebit, wallet *models.Wallet, reason string, decide func(tx *repositories.Repositories) error) error {
+	err := uc.repos.UnitOfWork.Do(ctx, func(tx *repositories.Repositories) error {
+		if err := decide(tx); err != nil {
+			return err
+		}
+		if err := updateLegStatus(ctx, tx, debit.TransactionID, models.TransactionStatusFailed, reason); err != nil {
+			return err
+		}
+		_, err := adjustBalance(ctx, tx.Wallet, debit.WalletID, debit.Amount.Add(debit.Fee))
+		return err
+	})
+	if err != nil {
+		return err
+	}
+
+	uc.publishFailureEvent(wallet, debit.Amount, debit.Reference, errors.New(reason))
+
+	uc.schedulePostTransactionAudit(debit.WalletID)
+
+	return nil
+}
+
+// heldLegs loads the held debit together with its counter legs and fee legs
+func heldLegs(ctx context.Context, tx *repositories.Repositories, transactionID uint) ([]models.Transaction, error) {
+	legs, err := tx.Transaction.GetLegs(ctx, transactionID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load held transactions: %w", err)
+	}
+	return legs, nil
+}
+
+// updateLegStatus moves the held debit, its counter legs and fee legs to the same status and records
+// the change in their status history
+func updateLegStatus(ctx context.Context, tx *repositories.Repositories, transactionID uint, status models.TransactionStatus, reason string) error {
+	legs, err := heldLegs(ctx, tx, transactionID)
+	if err != nil {
+		return err
+	}
+	if err := tx.Transaction.UpdateLegsStatus(ctx, transactionID, status); err != nil {
+		return fmt.Errorf("failed to update held transactions: %w", err)
+	}
+	return recordStatusChanges(ctx, tx, legs, status, reason)
+}
+
+// recordStatusChanges adds a status history entry for each transaction not already in status
+func recordStatusChanges(ctx context.Context, tx *repositories.Repositories, transactions []models.Transaction, status models.TransactionStatus, reason string) error {
+	var changes []models.TransactionStatusChange
+	for _, transaction := range transactions {
+		if transaction.Status == status {
+			continue
+		}
+		changes = append(changes, models.TransactionStatusChange{
+			TransactionID: transaction.ID,
+			FromStatus:    transaction.Status,
+			ToStatus:      status,
+			Reason:        reason,
+		})
+	}
+	if len(changes) == 0 {
+		return nil
+	}
+	if err := tx.TransactionStatusChange.Create(ctx, changes); err != nil {
+		return fmt.Errorf("failed to record transaction status history: %w", err)
+	}
+	return nil
+}
+
+// creditHeldLeg applies a credit leg that was recorded while its debit was held, rewriting its
+// balances to those of the wallet at release time
+func creditHeldLeg(ctx context.Context, tx *repositories.Repositories, leg *models.Transaction) error {
+	balanceBefore, err := adjustBalance(ctx, tx.Wallet, leg.WalletID, leg.Amount)
+	if err != nil {
+		return err
+	}
+	if err := tx.Transaction.SetBalances(ctx, leg.ID, balanceBefore, balanceBefore.Add(leg.Amount)); err != nil {
+		return fmt.Errorf("failed to update held transaction: %w", err)
+	}
+	return nil
+}
+
+// adjustBalance adds amount to the balance of a wallet through wallets and returns the balance
+// before the change
+func adjustBalance(ctx context.Context, wallets repositories.WalletRepository, walletID uint, amount decimal.Decimal) (decimal.Decimal, error) {
+	balanceBefore, applied, err := wallets.AdjustBalance(ctx, walletID, amount)
+	if err != nil {
+		return decimal.Zero, fmt.Errorf("failed to update wallet balance: %w", err)
+	}
+	if !applied {
+		return decimal.Zero, apperrors.ErrConcurrentModification
+	}
+	return balanceBefore, nil
+}
@@ -0,0 +1,87 @@
+package usecases
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/limistah/wallet-service/internal/apperrors"
+	"github.com/limistah/wallet-service/internal/models"
+	"github.com/shopspring/decimal"
+)
+
+// DebitAllowance is a periodic debit limit of a wallet and how much of it is left
+type DebitAllowance struct {
+	Limit     decimal.Decimal
+	Used      decimal.Decimal
+	Remaining decimal.Decimal
+	ResetsAt  time.Time
+}
+
+// WalletLimits are the limits of a wallet's tier and the largest amounts they allow right now. Nil
+// limits and amounts are unlimited
+type WalletLimits struct {
+	Tier    *models.WalletTier
+	Daily   *DebitAllowance
+	Monthly *DebitAllowance
+	// MaxDebit is the largest transfer or withdrawal the limits allow, before fees
+	MaxDebit *decimal.Decimal
+	// MaxCredit is the largest funding or incoming transfer the maximum balance allows
+	MaxCredit *decimal.Decimal
+}
+
+// GetWalletLimits reports the limits of the wallet's tier with what is left of them today, so
+// clients can check an amount before submitting it
+func (uc *walletUseCase) GetWalletLimits(ctx context.Context, walletID uint) (*WalletLimits, error) {
+	wallet, err := uc.repos.Wallet.GetByID(ctx, walletID)
+	if err != nil {
+		return nil, apperrors.ErrWalletNotFound
+	}
+	tier, err := resolveWalletTier(ctx, uc.repos, wallet)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now().UTC()
+	limits := &WalletLimits{Tier: tier, MaxDebit: tier.MaxTransactionAmount}
+	if tier.DailyDebitLimit != nil {
+		dayStart := startOfDebitDay(now)
+		if limits.Daily, err = uc.debitAllowance(ctx, wallet, *tier.DailyDebitLimit, dayStart, dayStart.AddDate(0, 0, 1)); err != nil {
+			return nil, err
+		}
+		limits.MaxDebit = minLimit(limits.MaxDebit, limits.Daily.Remaining)
+	}
+	if tier.MonthlyDebitLimit != nil {
+		monthStart := startOfDebitMonth(now)
+		if limits.Monthly, err = uc.debitAllowance(ctx, wallet, *tier.MonthlyDebitLimit, monthStart, monthStart.AddDate(0, 1, 0)); err != nil {
+			return nil, err
+		}
+		limits.MaxDebit = minLimit(limits.MaxDebit, limits.Monthly.Remaining)
+	}
+	if tier.MaxBalance != nil {
+		headroom := decimal.Max(tier.MaxBalance.Sub(wallet.Balance), decimal.Zero)
+		limits.MaxCredit = &headroom
+	}
+	return limits, nil
+}
+
+func (uc *walletUseCase) debitAllowance(ctx context.Context, wallet *models.Wallet, limit decimal.Decimal, since, resetsAt time.Time) (*DebitAllowance, error) {
+	used, err := uc.repos.Transaction.SumDebitsSince(ctx, wallet.ID, since)
+	if err != nil {
+		return nil, fmt.Errorf("failed to sum debits: %w", err)
+	}
+	return &DebitAllowance{
+		Limit:     limit,
+		Used:      used,
+		Remaining: decimal.Max(limit.Sub(used), decimal.Zero),
+		ResetsAt:  resetsAt,
+	}, nil
+}
+
+// minLimit returns the lower of a limit that may be unlimited and an amount
+func minLimit(limit *decimal.Decimal, amount decimal.Decimal) *decimal.Decimal {
+	if limit != nil && limit.LessThan(amount) {
+		return limit
+	}
+	return &amount
+}
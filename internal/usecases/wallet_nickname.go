@@ -0,0 +1,43 @@
+package usecases
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/limistah/wallet-service/internal/apperrors"
+	"github.com/limistah/wallet-service/internal/models"
+)
+
+// maxNicknameLength is the size of the nickname column of wallets
+const maxNicknameLength = 50
+
+// ListUserWallets returns the wallets a user owns, their business wallets included, in the order they
+// were opened. Given a nickname, only the wallets labelled with it are returned, whatever its case
+func (uc *walletUseCase) ListUserWallets(ctx context.Context, userID uint, sandbox bool, nickname string) ([]models.Wallet, error) {
+	wallets, err := uc.repos.Wallet.ListByUserID(ctx, userID, sandbox, strings.TrimSpace(nickname))
+	if err != nil {
+		return nil, fmt.Errorf("failed to list wallets: %w", err)
+	}
+	return wallets, nil
+}
+
+// SetWalletNickname labels a wallet of the user, such as "Rent" or "Travel"; an empty nickname clears
+// it. Nicknames are for the owner only, so wallets of other users are not found
+func (uc *walletUseCase) SetWalletNickname(ctx context.Context, userID, walletID uint, nickname string) (*models.Wallet, error) {
+	nickname = strings.TrimSpace(nickname)
+	if utf8.RuneCountInString(nickname) > maxNicknameLength {
+		return nil, apperrors.ErrValidation.Withf("nickname must be at most %d characters", maxNicknameLength)
+	}
+	wallet, err := uc.repos.Wallet.GetByID(ctx, walletID)
+	if err != nil || wallet.UserID != userID {
+		return nil, apperrors.ErrWalletNotFound
+	}
+
+	if err := uc.repos.Wallet.SetNickname(ctx, wallet.ID, nickname); err != nil {
+		return nil, fmt.Errorf("failed to set wallet nickname: %w", err)
+	}
+	wallet.Nickname = nickname
+	return wallet, nil
+}
@@ -0,0 +1,56 @@
+package usecases
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/limistah/wallet-service/internal/apperrors"
+	"github.com/limistah/wallet-service/internal/models"
+	"github.com/limistah/wallet-service/internal/repositories/memory"
+)
+
+func TestWalletUseCase_Nicknames(t *testing.T) {
+	repos := memory.NewRepositories()
+	ctx := context.Background()
+	for _, wallet := range []*models.Wallet{
+		{ID: 2, UserID: 2, Currency: "USD", Status: models.WalletStatusActive},
+		{ID: 3, UserID: 2, Currency: "USD", Status: models.WalletStatusActive},
+		{ID: 4, UserID: 2, Currency: "USD", Status: models.WalletStatusActive, Sandbox: true},
+		{ID: 5, UserID: 3, Currency: "USD", Status: models.WalletStatusActive},
+	} {
+		repos.Wallet.Create(ctx, wallet)
+	}
+	walletUC := NewWalletUseCase(repos, nil)
+
+	wallet, err := walletUC.SetWalletNickname(ctx, 2, 3, "  Rent ")
+	if err != nil || wallet.Nickname != "Rent" {
+		t.Fatalf("Expected the trimmed nickname, got %+v, %v", wallet, err)
+	}
+	if _, err := walletUC.SetWalletNickname(ctx, 2, 4, "Rent"); err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if _, err := walletUC.SetWalletNickname(ctx, 2, 5, "Travel"); !errors.Is(err, apperrors.ErrWalletNotFound) {
+		t.Errorf("Expected another user's wallet not to be found, got: %v", err)
+	}
+	if _, err := walletUC.SetWalletNickname(ctx, 2, 2, strings.Repeat("é", maxNicknameLength+1)); !errors.Is(err, apperrors.ErrValidation) {
+		t.Errorf("Expected a long nickname to be refused, got: %v", err)
+	}
+
+	wallets, err := walletUC.ListUserWallets(ctx, 2, false, "")
+	if err != nil || len(wallets) != 2 {
+		t.Fatalf("Expected the user's two live wallets, got %d, %v", len(wallets), err)
+	}
+	wallets, err = walletUC.ListUserWallets(ctx, 2, false, "rent")
+	if err != nil || len(wallets) != 1 || wallets[0].ID != 3 {
+		t.Errorf("Expected the wallet labelled Rent, got %+v, %v", wallets, err)
+	}
+
+	if _, err := walletUC.SetWalletNickname(ctx, 2, 3, ""); err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if wallets, _ := walletUC.ListUserWallets(ctx, 2, false, "Rent"); len(wallets) != 0 {
+		t.Errorf("Expected the nickname cleared, got %+v", wallets)
+	}
+}
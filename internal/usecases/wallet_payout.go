@@ -0,0 +1,186 @@
+package usecases
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/limistah/wallet-service/internal/apperrors"
+	"github.com/limistah/wallet-service/internal/breaker"
+	"github.com/limistah/wallet-service/internal/events"
+	"github.com/limistah/wallet-service/internal/models"
+	"github.com/limistah/wallet-service/internal/payments"
+	"github.com/limistah/wallet-service/internal/repositories"
+	"gorm.io/gorm"
+)
+
+// errPayoutAlreadySettled signals that a concurrent delivery settled the payout first
+var errPayoutAlreadySettled = errors.New("payout already settled")
+
+// dispatchPayout submits a pending payout to the provider and refunds it when the provider rejects it
+// outright. While the provider's circuit is open the payout stays pending and is queued for later
+func (uc *walletUseCase) dispatchPayout(ctx context.Context, payout *models.Payout, narration string) error {
+	providerReference, err := uc.payoutProvider.InitiatePayout(payoutRequest(payout, narration))
+	if errors.Is(err, breaker.ErrOpen) {
+		// The provider was never called, so submitting the payout later cannot pay it twice
+		queueErr := uc.jobs.Enqueue(JobDispatchPayout, dispatchPayoutJob{Reference: payout.Reference, Narration: narration})
+		if queueErr == nil {
+			return nil
+		}
+		log.Printf("Failed to queue payout %s: %v", payout.Reference, queueErr)
+	}
+	if err != nil {
+		uc.refundRejectedPayout(ctx, payout, err)
+		return err
+	}
+
+	if err := uc.repos.Payout.UpdateProviderReference(ctx, payout.ID, providerReference); err != nil {
+		log.Printf("Failed to store provider reference for payout %s: %v", payout.Reference, err)
+	}
+	return nil
+}
+
+// DispatchQueuedPayout submits a payout that was queued while the payout provider's circuit was open.
+// It returns breaker.ErrOpen while the circuit stays open so the job is retried; a payout the provider
+// rejects is refunded like any other
+func (uc *walletUseCase) DispatchQueuedPayout(ctx context.Context, reference, narration string) error {
+	payout, err := uc.repos.Payout.GetByReference(ctx, reference)
+	if err != nil {
+		return fmt.Errorf("failed to load payout: %w", err)
+	}
+	// Settled, expired or already submitted by an earlier attempt
+	if uc.payoutProvider == nil || !payout.IsPending() || payout.ProviderReference != "" {
+		return nil
+	}
+
+	providerReference, err := uc.payoutProvider.InitiatePayout(payoutRequest(payout, narration))
+	if errors.Is(err, breaker.ErrOpen) {
+		return err
+	}
+	if err != nil {
+		uc.refundRejectedPayout(ctx, payout, err)
+		return nil
+	}
+
+	if err := uc.repos.Payout.UpdateProviderReference(ctx, payout.ID, providerReference); err != nil {
+		log.Printf("Failed to store provider reference for payout %s: %v", payout.Reference, err)
+	}
+	return nil
+}
+
+// refundRejectedPayout fails a payout the provider refused and returns the held funds
+func (uc *walletUseCase) refundRejectedPayout(ctx context.Context, payout *models.Payout, cause error) {
+	if _, err := uc.settlePayout(ctx, payout, payments.PayoutEvent{
+		Reference:     payout.Reference,
+		Status:        payments.PayoutEventFailed,
+		FailureReason: cause.Error(),
+	}); err != nil {
+		log.Printf("Failed to refund rejected payout %s: %v", payout.Reference, err)
+	}
+}
+
+func payoutRequest(payout *models.Payout, narration string) payments.PayoutRequest {
+	return payments.PayoutRequest{
+		Reference:     payout.Reference,
+		Amount:        payout.Amount,
+		Currency:      payout.Currency,
+		BankCode:      payout.BankAccount.BankCode,
+		AccountNumber: payout.BankAccount.AccountNumber,
+		AccountName:   payout.BankAccount.AccountName,
+		Narration:     narration,
+	}
+}
+
+func (uc *walletUseCase) SettlePayout(ctx context.Context, provider string, event payments.PayoutEvent) (*models.Payout, error) {
+	payout, err := uc.repos.Payout.GetByReference(ctx, event.Reference)
+	if err == gorm.ErrRecordNotFound || (err == nil && payout.Provider != provider) {
+		return nil, apperrors.ErrPayoutNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return uc.settlePayout(ctx, payout, event)
+}
+
+// settlePayout moves a pending payout to its final state: on success the held debit completes and the
+// system wallet and fee account receive the funds, on failure both legs fail and the held amount returns to the wallet
+func (uc *walletUseCase) settlePayout(ctx context.Context, payout *models.Payout, event payments.PayoutEvent) (*models.Payout, error) {
+	if !payout.IsPending() {
+		return payout, nil
+	}
+
+	succeeded := event.Status == payments.PayoutEventSucceeded
+	payoutStatus := models.PayoutStatusFailed
+	transactionStatus := models.TransactionStatusFailed
+	if succeeded {
+		payoutStatus = models.PayoutStatusCompleted
+		transactionStatus = models.TransactionStatusCompleted
+	}
+
+	now := time.Now()
+	err := uc.repos.UnitOfWork.Do(ctx, func(tx *repositories.Repositories) error {
+		settled, err := tx.Payout.Settle(ctx, payout.ID, payoutStatus, event.FailureReason, now)
+		if err != nil {
+			return fmt.Errorf("failed to update payout: %w", err)
+		}
+		if !settled {
+			return errPayoutAlreadySettled
+		}
+
+		// The user debit, its system credit leg and any fee legs share the outcome
+		reason := "payout settled by provider"
+		if !succeeded {
+			reason = "payout failed: " + event.FailureReason
+		}
+		if err := updateLegStatus(ctx, tx, payout.TransactionID, transactionStatus, reason); err != nil {
+			return err
+		}
+
+		// Completed payouts credit the system wallet and the fee account through their legs; failed
+		// ones refund the user
+		if !succeeded {
+			_, err := adjustBalance(ctx, tx.Wallet, payout.WalletID, payout.Amount.Add(payout.Fee))
+			return err
+		}
+		legs, err := heldLegs(ctx, tx, payout.TransactionID)
+		if err != nil {
+			return err
+		}
+		for i := range legs {
+			if legs[i].TransactionType == models.TransactionTypeCredit {
+				if err := creditHeldLeg(ctx, tx, &legs[i]); err != nil {
+					return err
+				}
+			}
+		}
+		return nil
+	})
+	if err != nil && err != errPayoutAlreadySettled {
+		return nil, err
+	}
+
+	settled, loadErr := uc.repos.Payout.GetByReference(ctx, payout.Reference)
+	if loadErr != nil {
+		return nil, fmt.Errorf("failed to load payout: %w", loadErr)
+	}
+	if err == errPayoutAlreadySettled {
+		return settled, nil
+	}
+
+	if wallet, walletErr := uc.repos.Wallet.GetByID(ctx, payout.WalletID); walletErr == nil {
+		if transaction, txErr := uc.repos.Transaction.GetByID(ctx, payout.TransactionID); txErr == nil {
+			if succeeded {
+				uc.publishTransactionEvent(events.EventWithdrawalCompleted, wallet, transaction)
+			} else {
+				uc.publishFailureEvent(wallet, payout.Amount, payout.Reference, errors.New(event.FailureReason))
+			}
+		}
+	}
+
+	uc.schedulePostTransactionAudit(payout.WalletID)
+
+	return settled, nil
+}
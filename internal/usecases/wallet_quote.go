@@ -0,0 +1,183 @@
+package usecases
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/limistah/wallet-service/internal/apperrors"
+	"github.com/limistah/wallet-service/internal/fraud"
+	"github.com/limistah/wallet-service/internal/models"
+	"github.com/limistah/wallet-service/internal/repositories"
+	"github.com/shopspring/decimal"
+	"gorm.io/gorm"
+)
+
+// transferPrice is what a transfer costs the sender and brings the recipient
+type transferPrice struct {
+	Fee          decimal.Decimal
+	ExchangeRate decimal.Decimal
+	CreditAmount decimal.Decimal // In the recipient's currency
+}
+
+// priceTransfer checks the sender's tier limits and the recipient's maximum balance and works out the
+// fee, exchange rate and credited amount of a transfer. The transfer purpose decides whether the limits
+// and fee apply. A quote's fee and rate are kept
+func (uc *walletUseCase) priceTransfer(ctx context.Context, fromWallet, toWallet *models.Wallet, amount decimal.Decimal, quote *models.TransferQuote) (transferPrice, error) {
+	fromTier, err := resolveWalletTier(ctx, uc.repos, fromWallet)
+	if err != nil {
+		return transferPrice{}, err
+	}
+	purpose, err := purposeDefinition(ctx, uc.repos, models.TransactionPurposeTransfer)
+	if err != nil {
+		return transferPrice{}, err
+	}
+	if purpose.CountsTowardLimits {
+		if err := checkDebitLimits(ctx, uc.repos, fromTier, fromWallet, amount); err != nil {
+			return transferPrice{}, err
+		}
+	}
+
+	price := transferPrice{Fee: decimal.Zero}
+	if purpose.ChargesFees {
+		price.Fee = fromTier.TransferFee(amount)
+	}
+	if quote != nil {
+		price.Fee = quote.Fee
+		price.ExchangeRate = quote.ExchangeRate
+	} else if price.ExchangeRate, err = uc.exchangeRate(fromWallet.Currency, toWallet.Currency); err != nil {
+		return transferPrice{}, err
+	}
+	price.CreditAmount = amount.Mul(price.ExchangeRate).Round(2)
+	if amount.IsPositive() && !price.CreditAmount.IsPositive() {
+		return transferPrice{}, apperrors.ErrInvalidAmount.Withf("amount is too small to convert to %s", toWallet.Currency)
+	}
+
+	toTier, err := resolveWalletTier(ctx, uc.repos, toWallet)
+	if err != nil {
+		return transferPrice{}, err
+	}
+	if !withinMaxBalance(toTier, toWallet, price.CreditAmount) {
+		return transferPrice{}, apperrors.ErrTierLimitExceeded.Withf("destination wallet would exceed its maximum balance")
+	}
+	return price, nil
+}
+
+// exchangeRate returns the rate from one wallet currency to another; wallets of the same currency
+// trade at 1
+func (uc *walletUseCase) exchangeRate(from, to string) (decimal.Decimal, error) {
+	if strings.EqualFold(from, to) {
+		return decimal.NewFromInt(1), nil
+	}
+	if uc.exchangeRates == nil {
+		return decimal.Zero, apperrors.ErrCurrencyMismatch.Withf("transfers from %s to %s are not supported", from, to)
+	}
+	rate, err := uc.exchangeRates.Rate(from, to)
+	if err != nil {
+		return decimal.Zero, apperrors.ErrCurrencyMismatch.Withf("no exchange rate from %s to %s: %w", from, to, err)
+	}
+	return rate, nil
+}
+
+// transferMetadata records the exchange rate on both legs of a converted transfer
+func transferMetadata(price transferPrice) string {
+	if price.ExchangeRate.Equal(decimal.NewFromInt(1)) {
+		return `{"source": "transfer"}`
+	}
+	return fmt.Sprintf(`{"source": "transfer", "exchange_rate": "%s"}`, price.ExchangeRate.String())
+}
+
+// QuoteTransfer checks a transfer without making it and returns its fee, exchange rate and credited
+// amount as a quote that keeps them for the quote TTL
+func (uc *walletUseCase) QuoteTransfer(ctx context.Context, userID, fromWalletID, toWalletID uint, amount decimal.Decimal) (*models.TransferQuote, error) {
+	if !amount.IsPositive() {
+		return nil, apperrors.ErrInvalidAmount
+	}
+	if fromWalletID == toWalletID {
+		return nil, apperrors.ErrSelfPayment
+	}
+
+	fromWallet, err := uc.repos.Wallet.GetByID(ctx, fromWalletID)
+	if err != nil {
+		return nil, apperrors.ErrWalletNotFound.Withf("source wallet not found")
+	}
+	toWallet, err := uc.repos.Wallet.GetByID(ctx, toWalletID)
+	if err != nil {
+		return nil, apperrors.ErrCounterpartyWalletNotFound
+	}
+	if fromWallet.Sandbox != toWallet.Sandbox {
+		return nil, apperrors.ErrValidation.Withf("cannot transfer between sandbox and live wallets")
+	}
+	if !toWallet.IsActive() {
+		return nil, apperrors.ErrCounterpartyWalletInactive
+	}
+	if systemWallet, _ := uc.getSystemWallet(ctx, fromWallet); (systemWallet != nil && toWalletID == systemWallet.ID) || toWallet.User.IsSystemAccount() {
+		return nil, apperrors.ErrValidation.Withf("direct transfers to system account are not allowed")
+	}
+
+	price, err := uc.priceTransfer(ctx, fromWallet, toWallet, amount, nil)
+	if err != nil {
+		return nil, err
+	}
+	if !fromWallet.CanDebit(amount.Add(price.Fee)) {
+		return nil, apperrors.ErrInsufficientFunds.Withf("insufficient funds in source wallet: available=%.2f, requested=%.2f",
+			fromWallet.Balance.InexactFloat64(), amount.Add(price.Fee).InexactFloat64())
+	}
+
+	now := time.Now()
+	if err := uc.repos.TransferQuote.DeleteExpired(ctx, now); err != nil {
+		log.Printf("Failed to purge expired transfer quotes: %v", err)
+	}
+
+	quote := &models.TransferQuote{
+		UserID:         userID,
+		FromWalletID:   fromWalletID,
+		ToWalletID:     toWalletID,
+		Amount:         amount,
+		Fee:            price.Fee,
+		SourceCurrency: fromWallet.Currency,
+		TargetCurrency: toWallet.Currency,
+		ExchangeRate:   price.ExchangeRate,
+		CreditAmount:   price.CreditAmount,
+		ExpiresAt:      now.Add(uc.quoteTTL),
+	}
+	if err := uc.repos.TransferQuote.Create(ctx, quote); err != nil {
+		return nil, fmt.Errorf("failed to save transfer quote: %w", err)
+	}
+	return quote, nil
+}
+
+// TransferFundsWithQuote makes a transfer with the fee and exchange rate of a quote of the sender's
+// wallet. The recipient and amount must be those quoted; a quote can be used once
+func (uc *walletUseCase) TransferFundsWithQuote(ctx context.Context, quoteID, fromWalletID, toWalletID uint, amount decimal.Decimal, reference, description string, origin *fraud.Origin) (*models.Transaction, *models.Transaction, error) {
+	quote, err := uc.repos.TransferQuote.GetByID(ctx, quoteID)
+	if errors.Is(err, gorm.ErrRecordNotFound) || (err == nil && quote.FromWalletID != fromWalletID) {
+		return nil, nil, apperrors.ErrQuoteNotFound
+	}
+	if err != nil {
+		return nil, nil, err
+	}
+	if !quote.IsUsable(time.Now()) {
+		return nil, nil, apperrors.ErrQuoteExpired
+	}
+	if quote.ToWalletID != toWalletID || !quote.Amount.Equal(amount) {
+		return nil, nil, apperrors.ErrQuoteMismatch
+	}
+
+	return uc.transferFunds(ctx, fromWalletID, toWalletID, amount, reference, description, origin, quote)
+}
+
+// useQuote marks a quote used, failing when it expired or a concurrent transfer used it first
+func useQuote(ctx context.Context, tx *repositories.Repositories, quoteID uint, now time.Time) error {
+	used, err := tx.TransferQuote.Use(ctx, quoteID, now)
+	if err != nil {
+		return fmt.Errorf("failed to use transfer quote: %w", err)
+	}
+	if !used {
+		return apperrors.ErrQuoteExpired
+	}
+	return nil
+}
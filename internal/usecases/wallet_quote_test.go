@@ -0,0 +1,144 @@
+package usecases
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/limistah/wallet-service/internal/apperrors"
+	"github.com/limistah/wallet-service/internal/fx"
+	"github.com/limistah/wallet-service/internal/models"
+	"github.com/limistah/wallet-service/internal/repositories/memory"
+	"github.com/shopspring/decimal"
+)
+
+func TestWalletUseCase_QuoteTransfer(t *testing.T) {
+	repos, reconciliationUC := setupTestEnvironment()
+	repos.TransferQuote = memory.NewTransferQuoteRepository()
+	tiers := repos.WalletTier.(*memory.WalletTierRepository)
+	tier := &models.WalletTier{Name: "standard", TransferFeeFlat: decimal.NewFromInt(1), TransferFeePercent: decimal.NewFromInt(1)}
+	tiers.Create(context.Background(), tier)
+	repos.Wallet.Create(context.Background(), &models.Wallet{ID: 2, UserID: 2, Balance: decimal.NewFromInt(500), Currency: "USD", Status: models.WalletStatusActive, TierID: &tier.ID})
+	repos.Wallet.Create(context.Background(), &models.Wallet{ID: 3, UserID: 3, Currency: "USD", Status: models.WalletStatusActive})
+	repos.Wallet.Create(context.Background(), &models.Wallet{ID: 4, UserID: 4, Currency: "NGN", Status: models.WalletStatusActive})
+	repos.Wallet.Create(context.Background(), &models.Wallet{ID: 5, UserID: 5, Currency: "EUR", Status: models.WalletStatusActive})
+
+	rates, err := fx.ParseRates([]string{"USD/NGN=1550"})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	walletUC := NewWalletUseCase(repos, reconciliationUC, WithExchangeRates(rates), WithTransferQuoteTTL(2*time.Minute))
+
+	before := time.Now()
+	quote, err := walletUC.QuoteTransfer(context.Background(), 2, 2, 3, decimal.NewFromInt(100))
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if !quote.Fee.Equal(decimal.NewFromInt(2)) || !quote.ExchangeRate.Equal(decimal.NewFromInt(1)) || !quote.CreditAmount.Equal(decimal.NewFromInt(100)) {
+		t.Errorf("Expected a fee of 2 and 100 credited at par, got: %+v", quote)
+	}
+	if quote.ExpiresAt.Before(before.Add(2*time.Minute)) || quote.UserID != 2 {
+		t.Errorf("Expected a quote of user 2 valid for 2 minutes, got: %+v", quote)
+	}
+
+	converted, err := walletUC.QuoteTransfer(context.Background(), 2, 2, 4, decimal.NewFromInt(100))
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if converted.TargetCurrency != "NGN" || !converted.ExchangeRate.Equal(decimal.NewFromInt(1550)) || !converted.CreditAmount.Equal(decimal.NewFromInt(155000)) {
+		t.Errorf("Expected 155000 NGN credited at 1550, got: %+v", converted)
+	}
+
+	tests := []struct {
+		name       string
+		toWalletID uint
+		amount     decimal.Decimal
+		want       error
+	}{
+		{"no exchange rate", 5, decimal.NewFromInt(100), apperrors.ErrCurrencyMismatch},
+		{"insufficient funds", 3, decimal.NewFromInt(499), apperrors.ErrInsufficientFunds},
+		{"own wallet", 2, decimal.NewFromInt(100), apperrors.ErrSelfPayment},
+		{"unknown recipient", 99, decimal.NewFromInt(100), apperrors.ErrCounterpartyWalletNotFound},
+		{"zero amount", 3, decimal.Zero, apperrors.ErrInvalidAmount},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := walletUC.QuoteTransfer(context.Background(), 2, 2, tt.toWalletID, tt.amount); !errors.Is(err, tt.want) {
+				t.Errorf("Expected %v, got: %v", tt.want, err)
+			}
+		})
+	}
+}
+
+func TestWalletUseCase_TransferFundsWithQuoteChecksTheQuote(t *testing.T) {
+	repos, reconciliationUC := setupTestEnvironment()
+	quotes := memory.NewTransferQuoteRepository()
+	repos.TransferQuote = quotes
+	walletUC := NewWalletUseCase(repos, reconciliationUC)
+
+	amount := decimal.NewFromInt(100)
+	quotes.Create(context.Background(), &models.TransferQuote{UserID: 2, FromWalletID: 2, ToWalletID: 3, Amount: amount, ExpiresAt: time.Now().Add(time.Minute)})
+	usedAt := time.Now()
+	quotes.Create(context.Background(), &models.TransferQuote{UserID: 2, FromWalletID: 2, ToWalletID: 3, Amount: amount, ExpiresAt: time.Now().Add(time.Minute), UsedAt: &usedAt})
+	quotes.Create(context.Background(), &models.TransferQuote{UserID: 2, FromWalletID: 2, ToWalletID: 3, Amount: amount, ExpiresAt: time.Now().Add(-time.Second)})
+
+	tests := []struct {
+		name         string
+		quoteID      uint
+		fromWalletID uint
+		toWalletID   uint
+		amount       decimal.Decimal
+		want         error
+	}{
+		{"unknown quote", 9, 2, 3, amount, apperrors.ErrQuoteNotFound},
+		{"another wallet's quote", 1, 4, 3, amount, apperrors.ErrQuoteNotFound},
+		{"used quote", 2, 2, 3, amount, apperrors.ErrQuoteExpired},
+		{"expired quote", 3, 2, 3, amount, apperrors.ErrQuoteExpired},
+		{"different recipient", 1, 2, 4, amount, apperrors.ErrQuoteMismatch},
+		{"different amount", 1, 2, 3, decimal.NewFromInt(101), apperrors.ErrQuoteMismatch},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, _, err := walletUC.TransferFundsWithQuote(context.Background(), tt.quoteID, tt.fromWalletID, tt.toWalletID, tt.amount, "QT001", "", nil)
+			if !errors.Is(err, tt.want) {
+				t.Errorf("Expected %v, got: %v", tt.want, err)
+			}
+		})
+	}
+}
+
+func TestWalletUseCase_ConvertedTransferReconciles(t *testing.T) {
+	repos, _ := setupTestEnvironment()
+	repos.Wallet.Create(context.Background(), &models.Wallet{ID: 10, UserID: 10, Currency: "USD", Status: models.WalletStatusActive})
+	repos.Wallet.Create(context.Background(), &models.Wallet{ID: 15, UserID: 15, Currency: "NGN", Status: models.WalletStatusActive})
+
+	rates, err := fx.ParseRates([]string{"USD/NGN=1550"})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	reconciliationUC := NewReconciliationUseCase(repos)
+	walletUC := NewWalletUseCase(repos, reconciliationUC, WithExchangeRates(rates))
+
+	if _, _, err := walletUC.FundWallet(context.Background(), 10, decimal.NewFromInt(100), "FX-FUND", "Funding"); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if _, _, err := walletUC.TransferFunds(context.Background(), 10, 15, decimal.NewFromInt(10), "FX-TRF", "Converted", nil); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	for _, walletID := range []uint{10, 15} {
+		report, err := reconciliationUC.PerformWalletReconciliation(context.Background(), walletID)
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if report.HasAnyIssue() {
+			t.Errorf("Expected wallet %d to reconcile after a converted transfer, got: %v (%s)", walletID, report.Status, report.Notes)
+		}
+	}
+
+	// A second transfer out of the USD wallet passes the pre-transaction check
+	if _, _, err := walletUC.TransferFunds(context.Background(), 10, 15, decimal.NewFromInt(10), "FX-TRF-2", "Converted again", nil); err != nil {
+		t.Errorf("Expected the next transfer to go through, got: %v", err)
+	}
+}
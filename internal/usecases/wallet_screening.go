@@ -0,0 +1,60 @@
+package usecases
+
+import (
+	"context"
+	"time"
+
+	"github.com/limistah/wallet-service/internal/apperrors"
+	"github.com/limistah/wallet-service/internal/compliance"
+	"github.com/limistah/wallet-service/internal/fraud"
+	"github.com/limistah/wallet-service/internal/models"
+	"github.com/shopspring/decimal"
+)
+
+// ScreenPayment runs the checks of a transfer against a payment between two wallets that is posted
+// outside TransferFunds, such as an escrow: the tier debit limits of the payer, the blocklist, the
+// new recipient and fraud rules, and AML screening. A payment that would be held for review is refused,
+// since it cannot be held
+func (uc *walletUseCase) ScreenPayment(ctx context.Context, fromWallet, toWallet *models.Wallet, amount decimal.Decimal, reference string, origin *fraud.Origin) error {
+	tier, err := resolveWalletTier(ctx, uc.repos, fromWallet)
+	if err != nil {
+		return err
+	}
+	if err := checkDebitLimits(ctx, uc.repos, tier, fromWallet, amount); err != nil {
+		return err
+	}
+	return uc.screenUnheldPayment(ctx, fromWallet, toWallet, amount, reference, origin, "payment")
+}
+
+// screenUnheldPayment screens a transfer that cannot be held for review, refusing it when a fraud rule
+// flags it or AML screening refers it; subject names the payment in errors
+func (uc *walletUseCase) screenUnheldPayment(ctx context.Context, fromWallet, toWallet *models.Wallet, amount decimal.Decimal, reference string, origin *fraud.Origin, subject string) error {
+	if err := enforceBlocklist(ctx, uc.repos, models.TransactionPurposeTransfer, reference, amount, fromWallet, toWallet, nil); err != nil {
+		return err
+	}
+	if err := uc.screenNewRecipient(ctx, fromWallet, toWallet, amount, reference); err != nil {
+		return err
+	}
+	verdict, err := uc.screenDebit(ctx, fromWallet, fraud.Check{
+		Purpose:              models.TransactionPurposeTransfer,
+		WalletID:             fromWallet.ID,
+		CounterpartyWalletID: toWallet.ID,
+		Amount:               amount,
+		Origin:               origin,
+		At:                   time.Now(),
+	}, reference)
+	if err != nil {
+		return err
+	}
+	if verdict.Action == fraud.ActionFlag {
+		return apperrors.ErrFraudBlocked.Withf("%s flagged by fraud rule %s: %s", subject, verdict.Rule, verdict.Reason)
+	}
+	screening, err := uc.screenAML(ctx, fromWallet, toWallet, models.TransactionPurposeTransfer, amount, reference, nil)
+	if err != nil {
+		return err
+	}
+	if screening.Decision == compliance.DecisionReview {
+		return apperrors.ErrAMLDenied.Withf("%s referred for AML review", subject)
+	}
+	return nil
+}
@@ -0,0 +1,260 @@
+package usecases
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/limistah/wallet-service/internal/apperrors"
+	"github.com/limistah/wallet-service/internal/events"
+	"github.com/limistah/wallet-service/internal/fraud"
+	"github.com/limistah/wallet-service/internal/models"
+	"github.com/limistah/wallet-service/internal/repositories"
+	"github.com/shopspring/decimal"
+	"gorm.io/gorm"
+)
+
+// maxSplitRecipients bounds the credits of one split payment
+const maxSplitRecipients = 50
+
+// SplitRecipient is a wallet credited by a split payment with either a fixed amount or a percentage
+// of what the fixed amounts leave of the payment
+type SplitRecipient struct {
+	WalletID   uint
+	Amount     decimal.Decimal
+	Percentage decimal.Decimal
+}
+
+// SplitPayment is a payment debited from one wallet once and credited to several. Its legs share the
+// journal entry named by the split reference
+type SplitPayment struct {
+	Reference string
+	Debit     *models.Transaction
+	Credits   []models.Transaction
+}
+
+// allocateSplit works out the amount credited to each recipient. Fixed amounts are taken from the
+// payment first and percentages, which must add up to 100, share the rest; the cent left over by
+// rounding the percentages goes to the last of them. Without percentages the fixed amounts must add
+// up to the payment
+func allocateSplit(amount decimal.Decimal, recipients []SplitRecipient) ([]decimal.Decimal, error) {
+	if !amount.IsPositive() {
+		return nil, apperrors.ErrInvalidAmount
+	}
+	if len(recipients) < 2 || len(recipients) > maxSplitRecipients {
+		return nil, apperrors.ErrValidation.Withf("a split payment needs between 2 and %d recipients", maxSplitRecipients)
+	}
+
+	hundred := decimal.NewFromInt(100)
+	fixed, percentages := decimal.Zero, decimal.Zero
+	last := -1
+	for i, recipient := range recipients {
+		hasAmount, hasPercentage := !recipient.Amount.IsZero(), !recipient.Percentage.IsZero()
+		if hasAmount == hasPercentage {
+			return nil, apperrors.ErrValidation.Withf("recipient %d needs either an amount or a percentage", i+1)
+		}
+		if hasAmount {
+			if !recipient.Amount.IsPositive() || !recipient.Amount.Equal(recipient.Amount.Round(2)) {
+				return nil, apperrors.ErrInvalidAmount.Withf("amount of recipient %d must be positive with at most 2 decimals", i+1)
+			}
+			fixed = fixed.Add(recipient.Amount)
+			continue
+		}
+		if !recipient.Percentage.IsPositive() || recipient.Percentage.GreaterThan(hundred) {
+			return nil, apperrors.ErrValidation.Withf("percentage of recipient %d must be between 0 and 100", i+1)
+		}
+		percentages = percentages.Add(recipient.Percentage)
+		last = i
+	}
+
+	rest := amount.Sub(fixed)
+	if last < 0 {
+		if !rest.IsZero() {
+			return nil, apperrors.ErrValidation.Withf("recipient amounts add up to %s, not %s", fixed.StringFixed(2), amount.StringFixed(2))
+		}
+	} else if !percentages.Equal(hundred) {
+		return nil, apperrors.ErrValidation.Withf("percentages add up to %s, not 100", percentages.String())
+	} else if !rest.IsPositive() {
+		return nil, apperrors.ErrValidation.Withf("fixed amounts leave nothing of %s to share by percentage", amount.StringFixed(2))
+	}
+
+	amounts := make([]decimal.Decimal, len(recipients))
+	shared := decimal.Zero
+	for i, recipient := range recipients {
+		switch {
+		case !recipient.Amount.IsZero():
+			amounts[i] = recipient.Amount
+		case i == last:
+			amounts[i] = rest.Sub(shared)
+		default:
+			amounts[i] = rest.Mul(recipient.Percentage).Div(hundred).RoundFloor(2)
+			shared = shared.Add(amounts[i])
+		}
+		if !amounts[i].IsPositive() {
+			return nil, apperrors.ErrInvalidAmount.Withf("share of recipient %d rounds to zero", i+1)
+		}
+	}
+	return amounts, nil
+}
+
+// SplitFunds debits a wallet once for amount and credits each recipient its share, all in one
+// database transaction. The tier transfer fee is charged once on the whole amount. Recipients must
+// hold the currency of the paying wallet. Splits are not held for review: one a fraud rule flags or
+// AML screening sends to review is refused
+func (uc *walletUseCase) SplitFunds(ctx context.Context, fromWalletID uint, amount decimal.Decimal, recipients []SplitRecipient, reference, description string, origin *fraud.Origin) (*SplitPayment, error) {
+	amounts, err := allocateSplit(amount, recipients)
+	if err != nil {
+		return nil, err
+	}
+
+	fromWallet, err := uc.repos.Wallet.GetByID(ctx, fromWalletID)
+	if err != nil {
+		return nil, apperrors.ErrWalletNotFound.Withf("source wallet not found")
+	}
+	fromTier, err := resolveWalletTier(ctx, uc.repos, fromWallet)
+	if err != nil {
+		return nil, err
+	}
+	if err := checkDebitLimits(ctx, uc.repos, fromTier, fromWallet, amount); err != nil {
+		return nil, err
+	}
+	fee := fromTier.TransferFee(amount)
+
+	if !fromWallet.CanDebit(amount.Add(fee)) {
+		err := apperrors.ErrInsufficientFunds.Withf("insufficient funds in source wallet: available=%.2f, requested=%.2f",
+			fromWallet.Balance.InexactFloat64(), amount.Add(fee).InexactFloat64())
+		uc.publishFailureEvent(fromWallet, amount, reference, err)
+		return nil, err
+	}
+
+	if _, err := uc.repos.JournalEntry.GetByReference(ctx, reference); err == nil {
+		return nil, apperrors.ErrDuplicateReference
+	} else if !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, fmt.Errorf("error checking reference: %w", err)
+	}
+
+	systemWallet, _ := uc.getSystemWallet(ctx, fromWallet)
+	toWallets := make([]*models.Wallet, len(recipients))
+	seen := make(map[uint]bool, len(recipients))
+	for i, recipient := range recipients {
+		if recipient.WalletID == fromWalletID {
+			return nil, apperrors.ErrSelfPayment
+		}
+		if seen[recipient.WalletID] {
+			return nil, apperrors.ErrValidation.Withf("wallet %d is a recipient more than once", recipient.WalletID)
+		}
+		seen[recipient.WalletID] = true
+
+		toWallet, err := uc.repos.Wallet.GetByID(ctx, recipient.WalletID)
+		if err != nil {
+			return nil, apperrors.ErrCounterpartyWalletNotFound.Withf("wallet %d not found", recipient.WalletID)
+		}
+		if (systemWallet != nil && toWallet.ID == systemWallet.ID) || toWallet.User.IsSystemAccount() {
+			return nil, apperrors.ErrValidation.Withf("direct transfers to system account are not allowed")
+		}
+		if toWallet.Sandbox != fromWallet.Sandbox {
+			return nil, apperrors.ErrValidation.Withf("cannot transfer between sandbox and live wallets")
+		}
+		if toWallet.Currency != fromWallet.Currency {
+			return nil, apperrors.ErrCurrencyMismatch.Withf("wallet %d holds %s, not %s", toWallet.ID, toWallet.Currency, fromWallet.Currency)
+		}
+		if !toWallet.IsActive() {
+			return nil, apperrors.ErrCounterpartyWalletInactive.Withf("wallet %d is not active", toWallet.ID)
+		}
+		toTier, err := resolveWalletTier(ctx, uc.repos, toWallet)
+		if err != nil {
+			return nil, err
+		}
+		if !withinMaxBalance(toTier, toWallet, amounts[i]) {
+			return nil, apperrors.ErrTierLimitExceeded.Withf("wallet %d would exceed its maximum balance", toWallet.ID)
+		}
+
+		if err := uc.screenUnheldPayment(ctx, fromWallet, toWallet, amounts[i], reference, origin, "split payment"); err != nil {
+			return nil, err
+		}
+		toWallets[i] = toWallet
+	}
+
+	if err := uc.performPreTransactionReconciliation(ctx, fromWalletID); err != nil {
+		return nil, fmt.Errorf("source wallet reconciliation failed: %w", err)
+	}
+	var feeWallet *models.Wallet
+	if fee.IsPositive() {
+		if feeWallet, err = uc.getLedgerWallet(ctx, models.LedgerAccountFees, fromWallet.Sandbox); err != nil {
+			return nil, fmt.Errorf("failed to get fee wallet: %w", err)
+		}
+	}
+
+	split := &SplitPayment{Reference: reference}
+	metadata := fmt.Sprintf(`{"source": "split", "split_reference": %q}`, reference)
+	err = uc.repos.UnitOfWork.Do(ctx, func(tx *repositories.Repositories) error {
+		entry := &models.JournalEntry{Reference: reference, Description: description}
+		if err := tx.JournalEntry.Create(ctx, entry); err != nil {
+			return fmt.Errorf("failed to create split journal entry: %w", err)
+		}
+
+		fromBalanceAfter := fromWallet.Balance.Sub(amount)
+		split.Debit = &models.Transaction{
+			Reference:          reference + "-OUT",
+			WalletID:           fromWalletID,
+			TransactionType:    models.TransactionTypeDebit,
+			TransactionPurpose: models.TransactionPurposeTransfer,
+			Amount:             amount,
+			Metadata:           metadata,
+			BalanceBefore:      fromWallet.Balance,
+			BalanceAfter:       fromBalanceAfter,
+			Description:        fmt.Sprintf("Split payment to %d wallets: %s", len(recipients), description),
+			Status:             models.TransactionStatusCompleted,
+			JournalEntryID:     &entry.ID,
+		}
+		setOrigin(split.Debit, origin)
+		if err := tx.Transaction.Create(ctx, split.Debit); err != nil {
+			return fmt.Errorf("failed to create split debit: %w", err)
+		}
+
+		for i, toWallet := range toWallets {
+			balanceBefore, err := adjustBalance(ctx, tx.Wallet, toWallet.ID, amounts[i])
+			if err != nil {
+				return err
+			}
+			credit := models.Transaction{
+				Reference:            fmt.Sprintf("%s-IN-%d", reference, i+1),
+				WalletID:             toWallet.ID,
+				TransactionType:      models.TransactionTypeCredit,
+				TransactionPurpose:   models.TransactionPurposeTransfer,
+				Amount:               amounts[i],
+				Metadata:             metadata,
+				BalanceBefore:        balanceBefore,
+				BalanceAfter:         balanceBefore.Add(amounts[i]),
+				Description:          fmt.Sprintf("Split payment from wallet %d: %s", fromWalletID, description),
+				Status:               models.TransactionStatusCompleted,
+				RelatedTransactionID: &split.Debit.ID,
+				JournalEntryID:       &entry.ID,
+			}
+			if err := tx.Transaction.Create(ctx, &credit); err != nil {
+				return fmt.Errorf("failed to create split credit: %w", err)
+			}
+			split.Credits = append(split.Credits, credit)
+		}
+
+		if err := uc.recordFee(ctx, tx, fromWallet, fromBalanceAfter, feeWallet, fee, reference, models.TransactionStatusCompleted, split.Debit.ID); err != nil {
+			return err
+		}
+
+		return updateWalletBalance(ctx, tx, "source", fromWallet, fromBalanceAfter.Sub(fee))
+	})
+	if err != nil {
+		uc.publishFailureEvent(fromWallet, amount, reference, err)
+		return nil, err
+	}
+
+	uc.publishTransactionEvent(events.EventTransferSent, fromWallet, split.Debit)
+	walletIDs := []uint{fromWalletID}
+	for i, toWallet := range toWallets {
+		uc.publishTransactionEvent(events.EventCreditReceived, toWallet, &split.Credits[i])
+		walletIDs = append(walletIDs, toWallet.ID)
+	}
+	uc.schedulePostTransactionAudit(walletIDs...)
+
+	return split, nil
+}
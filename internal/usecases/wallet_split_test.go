@@ -0,0 +1,105 @@
+package usecases
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/limistah/wallet-service/internal/apperrors"
+	"github.com/limistah/wallet-service/internal/models"
+	"github.com/limistah/wallet-service/internal/repositories/memory"
+	"github.com/shopspring/decimal"
+)
+
+func TestAllocateSplit(t *testing.T) {
+	d := decimal.RequireFromString
+	fixed := func(walletID uint, amount string) SplitRecipient {
+		return SplitRecipient{WalletID: walletID, Amount: d(amount)}
+	}
+	share := func(walletID uint, percentage string) SplitRecipient {
+		return SplitRecipient{WalletID: walletID, Percentage: d(percentage)}
+	}
+
+	tests := []struct {
+		name       string
+		amount     string
+		recipients []SplitRecipient
+		want       []string
+		wantErr    error
+	}{
+		{"fixed amounts", "100", []SplitRecipient{fixed(2, "60"), fixed(3, "40")}, []string{"60", "40"}, nil},
+		{"percentages", "100", []SplitRecipient{share(2, "90"), share(3, "10")}, []string{"90", "10"}, nil},
+		{"remainder to last share", "10", []SplitRecipient{share(2, "33.33"), share(3, "33.33"), share(4, "33.34")}, []string{"3.33", "3.33", "3.34"}, nil},
+		{"fee then shares", "100", []SplitRecipient{fixed(2, "5"), share(3, "50"), share(4, "50")}, []string{"5", "47.5", "47.5"}, nil},
+		{"one recipient", "100", []SplitRecipient{fixed(2, "100")}, nil, apperrors.ErrValidation},
+		{"amount and percentage", "100", []SplitRecipient{{WalletID: 2, Amount: d("50"), Percentage: d("50")}, fixed(3, "50")}, nil, apperrors.ErrValidation},
+		{"amounts short of total", "100", []SplitRecipient{fixed(2, "60"), fixed(3, "30")}, nil, apperrors.ErrValidation},
+		{"percentages short of 100", "100", []SplitRecipient{share(2, "60"), share(3, "30")}, nil, apperrors.ErrValidation},
+		{"fixed amounts take everything", "100", []SplitRecipient{fixed(2, "100"), share(3, "100")}, nil, apperrors.ErrValidation},
+		{"sub-cent amount", "100", []SplitRecipient{fixed(2, "99.995"), fixed(3, "0.005")}, nil, apperrors.ErrInvalidAmount},
+		{"share rounds to zero", "0.02", []SplitRecipient{share(2, "1"), share(3, "99")}, nil, apperrors.ErrInvalidAmount},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			amounts, err := allocateSplit(d(tt.amount), tt.recipients)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("allocateSplit() error = %v, want %v", err, tt.wantErr)
+			}
+			if len(amounts) != len(tt.want) {
+				t.Fatalf("allocateSplit() = %v, want %v", amounts, tt.want)
+			}
+			for i := range amounts {
+				if !amounts[i].Equal(d(tt.want[i])) {
+					t.Errorf("amount %d = %s, want %s", i, amounts[i], tt.want[i])
+				}
+			}
+		})
+	}
+}
+
+func TestWalletUseCase_SplitFundsValidation(t *testing.T) {
+	repos, reconciliationUC := setupTestEnvironment()
+	journal := memory.NewJournalEntryRepository(repos.Transaction.(*memory.TransactionRepository))
+	journal.Create(context.Background(), &models.JournalEntry{Reference: "ORDER-1"})
+	repos.JournalEntry = journal
+	repos.Wallet.Create(context.Background(), &models.Wallet{ID: 2, UserID: 2, Balance: decimal.NewFromInt(100), Currency: "USD", Status: models.WalletStatusActive})
+	repos.Wallet.Create(context.Background(), &models.Wallet{ID: 3, UserID: 3, Currency: "USD", Status: models.WalletStatusActive})
+	repos.Wallet.Create(context.Background(), &models.Wallet{ID: 4, UserID: 4, Currency: "USD", Status: models.WalletStatusActive})
+	repos.Wallet.Create(context.Background(), &models.Wallet{ID: 5, UserID: 5, Currency: "EUR", Status: models.WalletStatusActive})
+	repos.Wallet.Create(context.Background(), &models.Wallet{ID: 6, UserID: 6, Currency: "USD", Status: models.WalletStatusSuspended})
+	walletUC := NewWalletUseCase(repos, reconciliationUC)
+
+	half := decimal.NewFromInt(50)
+	pay := func(walletIDs ...uint) []SplitRecipient {
+		recipients := make([]SplitRecipient, len(walletIDs))
+		for i, walletID := range walletIDs {
+			recipients[i] = SplitRecipient{WalletID: walletID, Percentage: half}
+		}
+		return recipients
+	}
+
+	tests := []struct {
+		name       string
+		amount     int64
+		reference  string
+		recipients []SplitRecipient
+		want       error
+	}{
+		{"insufficient funds", 101, "ORDER-2", pay(3, 4), apperrors.ErrInsufficientFunds},
+		{"duplicate reference", 10, "ORDER-1", pay(3, 4), apperrors.ErrDuplicateReference},
+		{"paying itself", 10, "ORDER-2", pay(2, 3), apperrors.ErrSelfPayment},
+		{"recipient twice", 10, "ORDER-2", pay(3, 3), apperrors.ErrValidation},
+		{"unknown recipient", 10, "ORDER-2", pay(3, 99), apperrors.ErrCounterpartyWalletNotFound},
+		{"system wallet", 10, "ORDER-2", pay(3, 1), apperrors.ErrValidation},
+		{"other currency", 10, "ORDER-2", pay(3, 5), apperrors.ErrCurrencyMismatch},
+		{"inactive recipient", 10, "ORDER-2", pay(3, 6), apperrors.ErrCounterpartyWalletInactive},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := walletUC.SplitFunds(context.Background(), 2, decimal.NewFromInt(tt.amount), tt.recipients, tt.reference, "", nil)
+			if !errors.Is(err, tt.want) {
+				t.Errorf("SplitFunds() error = %v, want %v", err, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,214 @@
+package usecases
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/limistah/wallet-service/internal/apperrors"
+	"github.com/limistah/wallet-service/internal/models"
+	"github.com/limistah/wallet-service/internal/repositories"
+	"github.com/shopspring/decimal"
+	"gorm.io/gorm"
+)
+
+type walletTierUseCase struct {
+	repos *repositories.Repositories
+}
+
+// NewWalletTierUseCase creates a new wallet tier use case
+func NewWalletTierUseCase(repos *repositories.Repositories) WalletTierUseCase {
+	return &walletTierUseCase{repos: repos}
+}
+
+func (uc *walletTierUseCase) CreateTier(ctx context.Context, tier *models.WalletTier) (*models.WalletTier, error) {
+	if err := validateWalletTier(tier); err != nil {
+		return nil, err
+	}
+
+	tier.ID = 0
+	if err := uc.repos.WalletTier.Create(ctx, tier); err != nil {
+		return nil, fmt.Errorf("failed to create wallet tier: %w", err)
+	}
+	if tier.IsDefault {
+		// Update clears the default flag on the previous default tier
+		if err := uc.repos.WalletTier.Update(ctx, tier); err != nil {
+			return nil, fmt.Errorf("failed to make wallet tier the default: %w", err)
+		}
+	}
+
+	return tier, nil
+}
+
+func (uc *walletTierUseCase) UpdateTier(ctx context.Context, id uint, tier *models.WalletTier) (*models.WalletTier, error) {
+	existing, err := uc.repos.WalletTier.GetByID(ctx, id)
+	if err != nil {
+		return nil, apperrors.ErrWalletTierNotFound
+	}
+	if err := validateWalletTier(tier); err != nil {
+		return nil, err
+	}
+	if existing.IsDefault && !tier.IsDefault {
+		return nil, apperrors.ErrValidation.Withf("make another tier the default instead")
+	}
+
+	tier.ID = existing.ID
+	tier.CreatedAt = existing.CreatedAt
+	if err := uc.repos.WalletTier.Update(ctx, tier); err != nil {
+		return nil, fmt.Errorf("failed to update wallet tier: %w", err)
+	}
+
+	return tier, nil
+}
+
+func (uc *walletTierUseCase) ListTiers(ctx context.Context) ([]models.WalletTier, error) {
+	return uc.repos.WalletTier.List(ctx)
+}
+
+// AssignTier attaches a tier to the wallet; a nil tier returns the wallet to the default tier
+func (uc *walletTierUseCase) AssignTier(ctx context.Context, walletID uint, tierID *uint) (*models.Wallet, error) {
+	wallet, err := uc.repos.Wallet.GetByID(ctx, walletID)
+	if err != nil {
+		return nil, apperrors.ErrWalletNotFound
+	}
+	if tierID != nil {
+		if _, err := uc.repos.WalletTier.GetByID(ctx, *tierID); err != nil {
+			return nil, apperrors.ErrWalletTierNotFound
+		}
+	}
+
+	if err := uc.repos.Wallet.SetTier(ctx, wallet.ID, tierID); err != nil {
+		return nil, fmt.Errorf("failed to assign wallet tier: %w", err)
+	}
+	wallet.TierID = tierID
+
+	return wallet, nil
+}
+
+func (uc *walletTierUseCase) GetWalletTier(ctx context.Context, walletID uint) (*models.WalletTier, error) {
+	wallet, err := uc.repos.Wallet.GetByID(ctx, walletID)
+	if err != nil {
+		return nil, apperrors.ErrWalletNotFound
+	}
+	return resolveWalletTier(ctx, uc.repos, wallet)
+}
+
+func validateWalletTier(tier *models.WalletTier) error {
+	tier.Name = strings.TrimSpace(tier.Name)
+	if tier.Name == "" {
+		return apperrors.ErrValidation.Withf("tier name is required")
+	}
+
+	hundred := decimal.NewFromInt(100)
+	if tier.TransferFeeFlat.IsNegative() || tier.WithdrawalFeeFlat.IsNegative() ||
+		tier.TransferFeePercent.IsNegative() || tier.WithdrawalFeePercent.IsNegative() ||
+		tier.TransferFeePercent.GreaterThan(hundred) || tier.WithdrawalFeePercent.GreaterThan(hundred) {
+		return apperrors.ErrValidation.Withf("fees must be non-negative and percentages at most 100")
+	}
+
+	for _, limit := range []*decimal.Decimal{tier.MaxTransactionAmount, tier.DailyDebitLimit, tier.MonthlyDebitLimit, tier.MaxBalance} {
+		if limit != nil && limit.LessThanOrEqual(decimal.Zero) {
+			return apperrors.ErrValidation.Withf("limits must be greater than zero")
+		}
+	}
+	if tier.DailyDebitLimit != nil && tier.MonthlyDebitLimit != nil && tier.MonthlyDebitLimit.LessThan(*tier.DailyDebitLimit) {
+		return apperrors.ErrValidation.Withf("monthly debit limit must be at least the daily debit limit")
+	}
+
+	return nil
+}
+
+// resolveWalletTier returns the tier assigned to the wallet, or the default tier when none is
+// assigned. Without a default tier wallets have no fees, no limits and every feature
+func resolveWalletTier(ctx context.Context, repos *repositories.Repositories, wallet *models.Wallet) (*models.WalletTier, error) {
+	if wallet.TierID != nil {
+		tier, err := repos.WalletTier.GetByID(ctx, *wallet.TierID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load wallet tier: %w", err)
+		}
+		return tier, nil
+	}
+
+	tier, err := repos.WalletTier.GetDefault(ctx)
+	if err == gorm.ErrRecordNotFound {
+		return &models.WalletTier{
+			Name:                  models.DefaultWalletTierName,
+			PaymentLinksEnabled:   true,
+			QRPaymentsEnabled:     true,
+			StandingOrdersEnabled: true,
+		}, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to load default wallet tier: %w", err)
+	}
+	return tier, nil
+}
+
+// requireTierFeature fails when the tier of the wallet does not include a feature; feature names it
+// in the error message
+func requireTierFeature(ctx context.Context, repos *repositories.Repositories, wallet *models.Wallet, enabled func(*models.WalletTier) bool, feature string) error {
+	tier, err := resolveWalletTier(ctx, repos, wallet)
+	if err != nil {
+		return err
+	}
+	if !enabled(tier) {
+		return apperrors.ErrTierFeatureUnavailable.Withf("%s are not available on this wallet tier", feature)
+	}
+	return nil
+}
+
+// checkDebitLimits enforces the per-transaction, daily and monthly debit limits of the tier; days
+// start at midnight UTC and months on their first day
+func checkDebitLimits(ctx context.Context, repos *repositories.Repositories, tier *models.WalletTier, wallet *models.Wallet, amount decimal.Decimal) error {
+	if tier.MaxTransactionAmount != nil && amount.GreaterThan(*tier.MaxTransactionAmount) {
+		return apperrors.ErrTierLimitExceeded.Withf("amount exceeds the per-transaction limit of %s", tier.MaxTransactionAmount.StringFixed(2))
+	}
+
+	now := time.Now().UTC()
+	if tier.DailyDebitLimit != nil {
+		remaining, err := remainingDebitLimit(ctx, repos, wallet, *tier.DailyDebitLimit, startOfDebitDay(now))
+		if err != nil {
+			return fmt.Errorf("failed to check daily limit: %w", err)
+		}
+		if amount.GreaterThan(remaining) {
+			return apperrors.ErrTierLimitExceeded.Withf("amount exceeds the remaining daily limit of %s", remaining.StringFixed(2))
+		}
+	}
+	if tier.MonthlyDebitLimit != nil {
+		remaining, err := remainingDebitLimit(ctx, repos, wallet, *tier.MonthlyDebitLimit, startOfDebitMonth(now))
+		if err != nil {
+			return fmt.Errorf("failed to check monthly limit: %w", err)
+		}
+		if amount.GreaterThan(remaining) {
+			return apperrors.ErrTierLimitExceeded.Withf("amount exceeds the remaining monthly limit of %s", remaining.StringFixed(2))
+		}
+	}
+
+	return nil
+}
+
+// remainingDebitLimit returns how much of a debit limit the wallet has left since the given time
+func remainingDebitLimit(ctx context.Context, repos *repositories.Repositories, wallet *models.Wallet, limit decimal.Decimal, since time.Time) (decimal.Decimal, error) {
+	spent, err := repos.Transaction.SumDebitsSince(ctx, wallet.ID, since)
+	if err != nil {
+		return decimal.Zero, err
+	}
+	return decimal.Max(limit.Sub(spent), decimal.Zero), nil
+}
+
+// startOfDebitDay is midnight UTC of the day of t
+func startOfDebitDay(t time.Time) time.Time {
+	return t.UTC().Truncate(24 * time.Hour)
+}
+
+// startOfDebitMonth is midnight UTC of the first day of the month of t
+func startOfDebitMonth(t time.Time) time.Time {
+	t = t.UTC()
+	return time.Date(t.Year(), t.Month(), 1, 0, 0, 0, 0, time.UTC)
+}
+
+// withinMaxBalance reports whether crediting the amount keeps the wallet within the maximum balance of its tier
+func withinMaxBalance(tier *models.WalletTier, wallet *models.Wallet, amount decimal.Decimal) bool {
+	return tier.MaxBalance == nil || !wallet.Balance.Add(amount).GreaterThan(*tier.MaxBalance)
+}
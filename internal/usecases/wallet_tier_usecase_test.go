@@ -0,0 +1,126 @@
+package usecases
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/limistah/wallet-service/internal/models"
+	"github.com/limistah/wallet-service/internal/repositories/memory"
+	"github.com/shopspring/decimal"
+)
+
+func TestWalletTierUseCase_AssignAndEnforce(t *testing.T) {
+	repos, _ := setupTestEnvironment()
+	repos.PaymentLink = memory.NewPaymentLinkRepository()
+	repos.Wallet.Create(context.Background(), &models.Wallet{ID: 2, UserID: 2, Balance: decimal.NewFromInt(500), Currency: "USD", Status: models.WalletStatusActive})
+
+	tierUC := NewWalletTierUseCase(repos)
+
+	if _, err := tierUC.CreateTier(context.Background(), &models.WalletTier{Name: "bad", TransferFeePercent: decimal.NewFromInt(120)}); err == nil {
+		t.Error("Expected percentages above 100 to be rejected")
+	}
+
+	standard, err := tierUC.CreateTier(context.Background(), &models.WalletTier{Name: "standard", IsDefault: true, PaymentLinksEnabled: true})
+	if err != nil {
+		t.Fatalf("Unexpected error creating tier: %v", err)
+	}
+	maxAmount := decimal.NewFromInt(100)
+	basic, err := tierUC.CreateTier(context.Background(), &models.WalletTier{
+		Name:                 "basic",
+		TransferFeeFlat:      decimal.NewFromInt(1),
+		TransferFeePercent:   decimal.NewFromFloat(1.5),
+		MaxTransactionAmount: &maxAmount,
+	})
+	if err != nil {
+		t.Fatalf("Unexpected error creating tier: %v", err)
+	}
+
+	if tier, _ := tierUC.GetWalletTier(context.Background(), 2); tier.ID != standard.ID {
+		t.Errorf("Expected wallets without a tier to use the default tier, got %s", tier.Name)
+	}
+
+	if _, err := tierUC.AssignTier(context.Background(), 2, &basic.ID); err != nil {
+		t.Fatalf("Unexpected error assigning tier: %v", err)
+	}
+	wallet, _ := repos.Wallet.GetByID(context.Background(), 2)
+	tier, _ := resolveWalletTier(context.Background(), repos, wallet)
+	if tier.ID != basic.ID {
+		t.Fatalf("Expected the assigned tier, got %s", tier.Name)
+	}
+
+	if fee := tier.TransferFee(decimal.NewFromInt(50)); !fee.Equal(decimal.NewFromFloat(1.75)) {
+		t.Errorf("Expected a fee of 1.75, got %s", fee)
+	}
+	if err := checkDebitLimits(context.Background(), repos, tier, wallet, decimal.NewFromInt(150)); err == nil || err.Error() != "amount exceeds the per-transaction limit of 100.00" {
+		t.Errorf("Expected the per-transaction limit to apply, got %v", err)
+	}
+
+	linkUC := NewPaymentLinkUseCase(repos, &transferringWalletUseCase{wallets: repos.Wallet.(*memory.WalletRepository)})
+	if _, err := linkUC.CreateLink(context.Background(), 2, nil, "", nil, false); err == nil || err.Error() != "payment links are not available on this wallet tier" {
+		t.Errorf("Expected payment links to be unavailable on the basic tier, got %v", err)
+	}
+}
+
+func TestWalletUseCase_GetWalletLimits(t *testing.T) {
+	repos, reconciliationUC := setupTestEnvironment()
+	repos.Wallet.Create(context.Background(), &models.Wallet{ID: 2, UserID: 2, Balance: decimal.NewFromInt(500), Currency: "USD", Status: models.WalletStatusActive})
+	walletUC := NewWalletUseCase(repos, reconciliationUC)
+
+	if _, err := NewWalletTierUseCase(repos).CreateTier(context.Background(), &models.WalletTier{Name: "bad", DailyDebitLimit: decimalPtr(500), MonthlyDebitLimit: decimalPtr(100)}); err == nil {
+		t.Error("Expected a monthly limit below the daily limit to be rejected")
+	}
+	tier, err := NewWalletTierUseCase(repos).CreateTier(context.Background(), &models.WalletTier{
+		Name:                 "standard",
+		IsDefault:            true,
+		MaxTransactionAmount: decimalPtr(300),
+		DailyDebitLimit:      decimalPtr(400),
+		MonthlyDebitLimit:    decimalPtr(1000),
+		MaxBalance:           decimalPtr(2000),
+	})
+	if err != nil {
+		t.Fatalf("Unexpected error creating tier: %v", err)
+	}
+
+	now := time.Now().UTC()
+	repos.Transaction.Create(context.Background(), &models.Transaction{WalletID: 2, Reference: "OUT-1", TransactionType: models.TransactionTypeDebit,
+		Amount: decimal.NewFromInt(150), CreatedAt: now})
+	// An earlier debit this month counts against the monthly limit only; there is none on the 1st
+	if startOfDebitDay(now).After(startOfDebitMonth(now)) {
+		repos.Transaction.Create(context.Background(), &models.Transaction{WalletID: 2, Reference: "OUT-2", TransactionType: models.TransactionTypeDebit,
+			Amount: decimal.NewFromInt(700), CreatedAt: startOfDebitDay(now).Add(-time.Minute)})
+	}
+
+	limits, err := walletUC.GetWalletLimits(context.Background(), 2)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if limits.Tier.ID != tier.ID || limits.Daily == nil || limits.Monthly == nil {
+		t.Fatalf("Expected daily and monthly allowances of the standard tier, got: %+v", limits)
+	}
+	if !limits.Daily.Used.Equal(decimal.NewFromInt(150)) || !limits.Daily.Remaining.Equal(decimal.NewFromInt(250)) {
+		t.Errorf("Expected 150 used and 250 left today, got %s and %s", limits.Daily.Used, limits.Daily.Remaining)
+	}
+	if !limits.Daily.ResetsAt.Equal(startOfDebitDay(now).AddDate(0, 0, 1)) {
+		t.Errorf("Expected the daily allowance to reset at midnight UTC, got %v", limits.Daily.ResetsAt)
+	}
+	expectedMaxDebit := decimal.NewFromInt(250)
+	if !limits.Monthly.Used.Equal(decimal.NewFromInt(150)) {
+		expectedMaxDebit = decimal.NewFromInt(150)
+	}
+	if limits.MaxDebit == nil || !limits.MaxDebit.Equal(expectedMaxDebit) {
+		t.Errorf("Expected the largest debit to be %s, got %v", expectedMaxDebit, limits.MaxDebit)
+	}
+	if limits.MaxCredit == nil || !limits.MaxCredit.Equal(decimal.NewFromInt(1500)) {
+		t.Errorf("Expected 1500 of headroom under the maximum balance, got %v", limits.MaxCredit)
+	}
+
+	if _, err := walletUC.GetWalletLimits(context.Background(), 99); err == nil {
+		t.Error("Expected an unknown wallet to be rejected")
+	}
+}
+
+func decimalPtr(value int64) *decimal.Decimal {
+	amount := decimal.NewFromInt(value)
+	return &amount
+}
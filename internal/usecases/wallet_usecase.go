@@ -1,13 +1,25 @@
 package usecases
 
 import (
+	"context"
 	"encoding/base64"
 	"encoding/json"
 	"errors"
 	"fmt"
+	"log"
+	"sort"
+	"strings"
 	"time"
+	"unicode/utf8"
 
+	"github.com/limistah/wallet-service/internal/apperrors"
+	"github.com/limistah/wallet-service/internal/compliance"
+	"github.com/limistah/wallet-service/internal/events"
+	"github.com/limistah/wallet-service/internal/fraud"
+	"github.com/limistah/wallet-service/internal/fx"
 	"github.com/limistah/wallet-service/internal/models"
+	"github.com/limistah/wallet-service/internal/payments"
+	"github.com/limistah/wallet-service/internal/queue"
 	"github.com/limistah/wallet-service/internal/repositories"
 	"github.com/shopspring/decimal"
 	"gorm.io/gorm"
@@ -16,6 +28,16 @@ import (
 type walletUseCase struct {
 	repos            *repositories.Repositories
 	reconciliationUC ReconciliationUseCase
+	publisher        events.EventPublisher
+	payoutProvider   payments.PayoutProvider
+	sandboxEnabled   bool
+	fraudEngine      *fraud.Engine
+	amlScreener      compliance.AMLScreener
+	settings         *LiveSettings
+	sanctionsChecker compliance.SanctionsChecker
+	jobs             queue.Enqueuer
+	exchangeRates    fx.RateProvider
+	quoteTTL         time.Duration
 }
 
 // TransactionCursor represents a cursor for pagination
@@ -25,47 +47,137 @@ type TransactionCursor struct {
 }
 
 // NewWalletUseCase creates a new wallet use case
-func NewWalletUseCase(repos *repositories.Repositories, reconciliationUC ReconciliationUseCase) WalletUseCase {
+func NewWalletUseCase(repos *repositories.Repositories, reconciliationUC ReconciliationUseCase, opts ...Option) WalletUseCase {
+	o := newOptions(opts)
 	return &walletUseCase{
 		repos:            repos,
 		reconciliationUC: reconciliationUC,
+		publisher:        o.publisher,
+		payoutProvider:   o.payoutProvider,
+		sandboxEnabled:   o.sandboxEnabled,
+		fraudEngine:      o.fraudEngine,
+		amlScreener:      o.amlScreener,
+		settings:         o.settings,
+		sanctionsChecker: o.sanctionsChecker,
+		jobs:             o.jobs,
+		exchangeRates:    o.exchangeRates,
+		quoteTTL:         o.transferQuoteTTL,
 	}
 }
 
+// publishTransactionEvent emits a wallet event for a completed transaction
+func (uc *walletUseCase) publishTransactionEvent(eventType events.EventType, wallet *models.Wallet, transaction *models.Transaction) {
+	uc.publisher.Publish(events.Event{
+		Type:          eventType,
+		UserID:        wallet.UserID,
+		WalletID:      wallet.ID,
+		TransactionID: transaction.ID,
+		Reference:     transaction.Reference,
+		Amount:        transaction.Amount,
+		Currency:      wallet.Currency,
+		Data:          map[string]string{"purpose": string(transaction.TransactionPurpose)},
+		OccurredAt:    time.Now(),
+	})
+}
+
+// publishFailureEvent emits a wallet event for an operation that could not be completed
+func (uc *walletUseCase) publishFailureEvent(wallet *models.Wallet, amount decimal.Decimal, reference string, cause error) {
+	uc.publisher.Publish(events.Event{
+		Type:       events.EventTransactionFailed,
+		UserID:     wallet.UserID,
+		WalletID:   wallet.ID,
+		Reference:  reference,
+		Amount:     amount,
+		Currency:   wallet.Currency,
+		Reason:     cause.Error(),
+		OccurredAt: time.Now(),
+	})
+}
+
 // performPreTransactionReconciliation performs reconciliation check before withdrawal/transfer
 // This ensures the wallet balance is accurate before any debiting operation
-func (uc *walletUseCase) performPreTransactionReconciliation(walletID uint) error {
-	report, err := uc.reconciliationUC.PerformWalletReconciliation(walletID)
+func (uc *walletUseCase) performPreTransactionReconciliation(ctx context.Context, walletID uint) error {
+	report, err := uc.reconciliationUC.PerformWalletReconciliation(ctx, walletID)
 	if err != nil {
-		return fmt.Errorf("reconciliation check failed: %w", err)
+		return apperrors.ErrReconciliationFailed.Wrap(err)
 	}
 
-	if report.Status == models.ReconciliationStatusMismatch {
-		return fmt.Errorf("wallet balance mismatch detected: stored=%s, calculated=%s, difference=%s. Transaction cannot proceed until reconciliation is resolved",
+	if report.HasMismatch() {
+		uc.reportBalanceMismatch(ctx, report)
+		return apperrors.ErrBalanceMismatch.Withf("wallet balance mismatch detected: stored=%s, calculated=%s, difference=%s. Transaction cannot proceed until reconciliation is resolved",
 			report.StoredBalance.String(), report.CalculatedBalance.String(), report.Difference.String())
 	}
+	if report.HasDoubleEntryError() {
+		uc.reportBalanceMismatch(ctx, report)
+		return apperrors.ErrBalanceMismatch.Withf("wallet ledger has %d broken double-entry pairs. Transaction cannot proceed until reconciliation is resolved",
+			report.BrokenPairs)
+	}
 
 	return nil
 }
 
-// performPostTransactionReconciliation performs reconciliation after transaction for audit
-// This is optional and won't block transactions, but provides audit trail
-func (uc *walletUseCase) performPostTransactionReconciliation(walletID uint) {
-	// This is for audit purposes only
-	err := uc.performPreTransactionReconciliation(walletID)
+// reportBalanceMismatch warns the wallet owner that the stored balance does not match the ledger
+func (uc *walletUseCase) reportBalanceMismatch(ctx context.Context, report *models.ReconciliationReport) {
+	wallet, err := uc.repos.Wallet.GetByID(ctx, report.WalletID)
+	if err != nil {
+		return
+	}
+	uc.publisher.Publish(events.Event{
+		Type:       events.EventSuspiciousActivity,
+		UserID:     wallet.UserID,
+		WalletID:   wallet.ID,
+		Amount:     report.Difference.Abs(),
+		Currency:   wallet.Currency,
+		Reason:     "wallet balance does not match its transaction history",
+		OccurredAt: time.Now(),
+	})
+}
+
+// schedulePostTransactionAudit queues a reconciliation of each wallet after a transaction for the
+// audit trail. It never blocks or fails the transaction; the job queue retries failed audits
+func (uc *walletUseCase) schedulePostTransactionAudit(walletIDs ...uint) {
+	for _, walletID := range walletIDs {
+		if err := uc.jobs.Enqueue(JobWalletAudit, walletAuditJob{WalletID: walletID}); err != nil {
+			log.Printf("Failed to schedule post-transaction audit for wallet %d: %v", walletID, err)
+		}
+	}
+}
+
+// AuditWallet reconciles a wallet for the audit trail. A mismatch is reported to the wallet owner
+// and logged rather than returned, since retrying cannot fix it; only a failed check is returned
+func (uc *walletUseCase) AuditWallet(ctx context.Context, walletID uint) error {
+	report, err := uc.reconciliationUC.PerformWalletReconciliation(ctx, walletID)
 	if err != nil {
-		fmt.Printf("Post-transaction reconciliation warning for wallet %d: %v\n", walletID, err)
+		return fmt.Errorf("reconciliation check failed: %w", err)
+	}
+	if report.HasAnyIssue() {
+		uc.reportBalanceMismatch(ctx, report)
+		log.Printf("Post-transaction reconciliation found an issue on wallet %d: status=%s, stored=%s, calculated=%s, broken pairs=%d",
+			walletID, report.Status, report.StoredBalance.String(), report.CalculatedBalance.String(), report.BrokenPairs)
 	}
+	return nil
 }
 
-// getSystemWallet retrieves the system wallet for double-entry bookkeeping
-func (uc *walletUseCase) getSystemWallet() (*models.Wallet, error) {
-	systemUser, err := uc.repos.User.GetByEmail(models.SystemAccountEmail)
+// getSystemWallet retrieves the system wallet the given wallet balances against for double-entry
+// bookkeeping. Each tenant has system accounts of its own, and sandbox wallets balance against a
+// separate sandbox system account so test money never touches live ledgers
+func (uc *walletUseCase) getSystemWallet(ctx context.Context, wallet *models.Wallet) (*models.Wallet, error) {
+	email := models.SystemAccountEmail
+	if wallet.Sandbox {
+		email = models.SandboxSystemAccountEmail
+	}
+
+	systemUser, err := uc.repos.User.GetByEmailInTenant(ctx, wallet.TenantID, email)
 	if err != nil {
 		return nil, fmt.Errorf("system user not found: %w", err)
 	}
 
-	systemWallet, err := uc.repos.Wallet.GetByUserID(systemUser.ID)
+	getWallet := uc.repos.Wallet.GetByUserID
+	if wallet.Sandbox {
+		getWallet = uc.repos.Wallet.GetSandboxByUserID
+	}
+
+	systemWallet, err := getWallet(ctx, systemUser.ID)
 	if err != nil {
 		return nil, fmt.Errorf("system wallet not found: %w", err)
 	}
@@ -73,25 +185,53 @@ func (uc *walletUseCase) getSystemWallet() (*models.Wallet, error) {
 	return systemWallet, nil
 }
 
-func (uc *walletUseCase) CreateWallet(userID uint, currency string) (*models.Wallet, error) {
-	_, err := uc.repos.User.GetByID(userID)
+// getLedgerWallet retrieves the wallet backing an account of the chart of ledger accounts
+func (uc *walletUseCase) getLedgerWallet(ctx context.Context, code models.LedgerAccountCode, sandbox bool) (*models.Wallet, error) {
+	account, err := uc.repos.LedgerAccount.GetByCode(ctx, code, sandbox)
 	if err != nil {
-		return nil, errors.New("user not found")
+		return nil, fmt.Errorf("ledger account %s not found: %w", code, err)
 	}
+	return &account.Wallet, nil
+}
 
-	existingWallet, err := uc.repos.Wallet.GetByUserID(userID)
-	if err == nil && existingWallet != nil {
-		return nil, errors.New("user already has a wallet")
+// CreateWallet opens another live wallet for a user, such as a wallet in a second currency or one set
+// aside for rent. Wallets of a user must differ by currency or nickname, so a user cannot open the same
+// wallet twice. The first wallet of a user becomes their default one
+func (uc *walletUseCase) CreateWallet(ctx context.Context, userID uint, currency, nickname string) (*models.Wallet, error) {
+	currency = strings.ToUpper(strings.TrimSpace(currency))
+	nickname = strings.TrimSpace(nickname)
+	if !currencyCodePattern.MatchString(currency) {
+		return nil, apperrors.ErrValidation.Withf("currency must be an ISO 4217 code")
+	}
+	if utf8.RuneCountInString(nickname) > maxNicknameLength {
+		return nil, apperrors.ErrValidation.Withf("nickname must be at most %d characters", maxNicknameLength)
+	}
+
+	_, err := uc.repos.User.GetByID(ctx, userID)
+	if err != nil {
+		return nil, apperrors.ErrUserNotFound
+	}
+
+	existing, err := uc.repos.Wallet.ListByUserID(ctx, userID, false, "")
+	if err != nil {
+		return nil, fmt.Errorf("failed to list wallets: %w", err)
+	}
+	for _, wallet := range existing {
+		if wallet.Currency == currency && strings.EqualFold(wallet.Nickname, nickname) {
+			return nil, apperrors.ErrWalletExists
+		}
 	}
 
 	wallet := &models.Wallet{
-		UserID:   userID,
-		Balance:  decimal.Zero,
-		Currency: currency,
-		Status:   models.WalletStatusActive,
+		UserID:    userID,
+		Balance:   decimal.Zero,
+		Currency:  currency,
+		Nickname:  nickname,
+		Status:    models.WalletStatusActive,
+		IsDefault: len(existing) == 0,
 	}
 
-	err = uc.repos.Wallet.Create(wallet)
+	err = uc.repos.Wallet.Create(ctx, wallet)
 	if err != nil {
 		return nil, err
 	}
@@ -99,41 +239,105 @@ func (uc *walletUseCase) CreateWallet(userID uint, currency string) (*models.Wal
 	return wallet, nil
 }
 
-func (uc *walletUseCase) GetWallet(id uint) (*models.Wallet, error) {
-	return uc.repos.Wallet.GetByID(id)
+func (uc *walletUseCase) GetWallet(ctx context.Context, id uint) (*models.Wallet, error) {
+	return uc.repos.Wallet.GetByID(ctx, id)
 }
 
-func (uc *walletUseCase) GetWalletByUserID(userID uint) (*models.Wallet, error) {
-	return uc.repos.Wallet.GetByUserID(userID)
+func (uc *walletUseCase) GetWalletByUserID(ctx context.Context, userID uint) (*models.Wallet, error) {
+	wallet, err := uc.repos.Wallet.GetByUserID(ctx, userID)
+	if err != nil {
+		return nil, apperrors.ErrWalletNotFound
+	}
+	return wallet, nil
+}
+
+// GetSandboxWallet returns the user's sandbox wallet, creating it in the live wallet's currency on first use
+func (uc *walletUseCase) GetSandboxWallet(ctx context.Context, userID uint) (*models.Wallet, error) {
+	if !uc.sandboxEnabled {
+		return nil, apperrors.ErrSandboxDisabled
+	}
+
+	if wallet, err := uc.repos.Wallet.GetSandboxByUserID(ctx, userID); err == nil {
+		return wallet, nil
+	}
+
+	liveWallet, err := uc.repos.Wallet.GetByUserID(ctx, userID)
+	if err != nil {
+		return nil, apperrors.ErrWalletNotFound
+	}
+
+	wallet := &models.Wallet{
+		UserID:   userID,
+		Balance:  decimal.Zero,
+		Currency: liveWallet.Currency,
+		Status:   models.WalletStatusActive,
+		Sandbox:  true,
+	}
+	if err := uc.repos.Wallet.Create(ctx, wallet); err != nil {
+		return nil, err
+	}
+
+	return wallet, nil
 }
 
-func (uc *walletUseCase) FundWallet(walletID uint, amount decimal.Decimal, reference, description string) (*models.Transaction, *models.Transaction, error) {
+// MintSandboxFunds credits test money to a sandbox wallet from the sandbox system wallet
+func (uc *walletUseCase) MintSandboxFunds(ctx context.Context, walletID uint, amount decimal.Decimal) (*models.Transaction, error) {
+	if !uc.sandboxEnabled {
+		return nil, apperrors.ErrSandboxDisabled
+	}
+
+	wallet, err := uc.repos.Wallet.GetByID(ctx, walletID)
+	if err != nil {
+		return nil, apperrors.ErrWalletNotFound
+	}
+	if !wallet.Sandbox {
+		return nil, apperrors.ErrValidation.Withf("funds can only be minted into sandbox wallets")
+	}
+
+	reference := fmt.Sprintf("SBX-%d-%d", wallet.ID, time.Now().UnixNano())
+	transaction, _, err := uc.FundWallet(ctx, wallet.ID, amount, reference, "Sandbox test funds")
+	if err != nil {
+		return nil, err
+	}
+
+	return transaction, nil
+}
+
+func (uc *walletUseCase) FundWallet(ctx context.Context, walletID uint, amount decimal.Decimal, reference, description string) (*models.Transaction, *models.Transaction, error) {
 	if amount.LessThanOrEqual(decimal.Zero) {
-		return nil, nil, errors.New("amount must be greater than zero")
+		return nil, nil, apperrors.ErrInvalidAmount
 	}
 
-	if err := uc.performPreTransactionReconciliation(walletID); err != nil {
+	if err := uc.performPreTransactionReconciliation(ctx, walletID); err != nil {
 		return nil, nil, fmt.Errorf("pre-transaction reconciliation failed: %w", err)
 	}
 
-	_, err := uc.repos.Transaction.GetByReference(reference)
+	_, err := uc.repos.Transaction.GetByReference(ctx, reference)
 	if err == nil {
-		return nil, nil, errors.New("duplicate reference")
+		return nil, nil, apperrors.ErrDuplicateReference
 	}
 	if err != gorm.ErrRecordNotFound {
 		return nil, nil, fmt.Errorf("error checking reference: %w", err)
 	}
 
-	userWallet, err := uc.repos.Wallet.GetByID(walletID)
+	userWallet, err := uc.repos.Wallet.GetByID(ctx, walletID)
 	if err != nil {
-		return nil, nil, errors.New("wallet not found")
+		return nil, nil, apperrors.ErrWalletNotFound
 	}
 
 	if !userWallet.IsActive() {
-		return nil, nil, errors.New("wallet is not active")
+		return nil, nil, apperrors.ErrWalletInactive
 	}
 
-	systemWallet, err := uc.getSystemWallet()
+	tier, err := resolveWalletTier(ctx, uc.repos, userWallet)
+	if err != nil {
+		return nil, nil, err
+	}
+	if !withinMaxBalance(tier, userWallet, amount) {
+		return nil, nil, apperrors.ErrTierLimitExceeded.Withf("wallet balance would exceed the maximum of %s", tier.MaxBalance.StringFixed(2))
+	}
+
+	systemWallet, err := uc.getSystemWallet(ctx, userWallet)
 	if err != nil {
 		return nil, nil, fmt.Errorf("failed to get system wallet: %w", err)
 	}
@@ -144,7 +348,7 @@ func (uc *walletUseCase) FundWallet(walletID uint, amount decimal.Decimal, refer
 
 	var systemTransaction, userTransaction *models.Transaction
 
-	err = uc.repos.DB.Transaction(func(tx *gorm.DB) error {
+	err = uc.repos.UnitOfWork.Do(ctx, func(tx *repositories.Repositories) error {
 		systemBalanceBefore := systemWallet.Balance
 		systemBalanceAfter := systemBalanceBefore.Sub(amount)
 
@@ -161,22 +365,12 @@ func (uc *walletUseCase) FundWallet(walletID uint, amount decimal.Decimal, refer
 			Status:             models.TransactionStatusCompleted,
 		}
 
-		if err := tx.Create(systemTransaction).Error; err != nil {
+		if err := tx.Transaction.Create(ctx, systemTransaction); err != nil {
 			return fmt.Errorf("failed to create system transaction: %w", err)
 		}
 
-		result := tx.Model(&models.Wallet{}).Where("id = ? AND version = ?", systemWallet.ID, systemWallet.Version).
-			Updates(map[string]interface{}{
-				"balance": systemBalanceAfter,
-				"version": gorm.Expr("version + 1"),
-			})
-
-		if result.Error != nil {
-			return fmt.Errorf("failed to update system wallet balance: %w", result.Error)
-		}
-
-		if result.RowsAffected == 0 {
-			return errors.New("system wallet version mismatch - concurrent modification detected")
+		if err := updateWalletBalance(ctx, tx, "system", systemWallet, systemBalanceAfter); err != nil {
+			return err
 		}
 
 		userBalanceBefore := userWallet.Balance
@@ -196,39 +390,32 @@ func (uc *walletUseCase) FundWallet(walletID uint, amount decimal.Decimal, refer
 			RelatedTransactionID: &systemTransaction.ID,
 		}
 
-		if err := tx.Create(userTransaction).Error; err != nil {
+		if err := tx.Transaction.Create(ctx, userTransaction); err != nil {
 			return fmt.Errorf("failed to create user transaction: %w", err)
 		}
 
-		result = tx.Model(&models.Wallet{}).Where("id = ? AND version = ?", walletID, userWallet.Version).
-			Updates(map[string]interface{}{
-				"balance": userBalanceAfter,
-				"version": gorm.Expr("version + 1"),
-			})
-
-		if result.Error != nil {
-			return fmt.Errorf("failed to update user wallet balance: %w", result.Error)
-		}
-
-		if result.RowsAffected == 0 {
-			return errors.New("user wallet version mismatch - concurrent modification detected")
+		if err := updateWalletBalance(ctx, tx, "user", userWallet, userBalanceAfter); err != nil {
+			return err
 		}
 
-		return tx.Model(systemTransaction).Update("related_transaction_id", userTransaction.ID).Error
+		return linkRelated(ctx, tx, systemTransaction, userTransaction)
 	})
 
 	if err != nil {
+		uc.publishFailureEvent(userWallet, amount, reference, err)
 		return nil, nil, err
 	}
 
-	go uc.performPostTransactionReconciliation(walletID)
+	uc.publishTransactionEvent(events.EventCreditReceived, userWallet, userTransaction)
 
-	userTx, err := uc.repos.Transaction.GetByID(userTransaction.ID)
+	uc.schedulePostTransactionAudit(walletID)
+
+	userTx, err := uc.repos.Transaction.GetByID(ctx, userTransaction.ID)
 	if err != nil {
 		return nil, nil, fmt.Errorf("failed to load user transaction: %w", err)
 	}
 
-	systemTx, err := uc.repos.Transaction.GetByID(systemTransaction.ID)
+	systemTx, err := uc.repos.Transaction.GetByID(ctx, systemTransaction.ID)
 	if err != nil {
 		return nil, nil, fmt.Errorf("failed to load system transaction: %w", err)
 	}
@@ -236,50 +423,114 @@ func (uc *walletUseCase) FundWallet(walletID uint, amount decimal.Decimal, refer
 	return userTx, systemTx, nil
 }
 
-func (uc *walletUseCase) WithdrawFunds(walletID uint, amount decimal.Decimal, reference, description string) (*models.Transaction, *models.Transaction, error) {
+func (uc *walletUseCase) WithdrawFunds(ctx context.Context, walletID uint, amount decimal.Decimal, reference, description string, destination *models.BankAccount, origin *fraud.Origin) (*models.Transaction, *models.Transaction, error) {
 	if amount.LessThanOrEqual(decimal.Zero) {
-		return nil, nil, errors.New("amount must be greater than zero")
+		return nil, nil, apperrors.ErrInvalidAmount
 	}
 
-	if err := uc.performPreTransactionReconciliation(walletID); err != nil {
+	if err := uc.performPreTransactionReconciliation(ctx, walletID); err != nil {
 		return nil, nil, fmt.Errorf("pre-transaction reconciliation failed: %w", err)
 	}
 
-	_, err := uc.repos.Transaction.GetByReference(reference)
+	_, err := uc.repos.Transaction.GetByReference(ctx, reference)
 	if err == nil {
-		return nil, nil, errors.New("duplicate reference")
+		return nil, nil, apperrors.ErrDuplicateReference
 	}
 	if err != gorm.ErrRecordNotFound {
 		return nil, nil, fmt.Errorf("error checking reference: %w", err)
 	}
 
-	userWallet, err := uc.repos.Wallet.GetByID(walletID)
+	userWallet, err := uc.repos.Wallet.GetByID(ctx, walletID)
 	if err != nil {
-		return nil, nil, errors.New("wallet not found")
+		return nil, nil, apperrors.ErrWalletNotFound
 	}
 
 	if !userWallet.IsActive() {
-		return nil, nil, errors.New("wallet is not active")
+		return nil, nil, apperrors.ErrWalletInactive
+	}
+
+	if err := enforceBlocklist(ctx, uc.repos, models.TransactionPurposeWithdrawal, reference, amount, userWallet, nil, destination); err != nil {
+		return nil, nil, err
 	}
 
-	if !userWallet.CanDebit(amount) {
-		return nil, nil, fmt.Errorf("insufficient funds: available=%.2f, requested=%.2f",
-			userWallet.Balance.InexactFloat64(), amount.InexactFloat64())
+	// With a payout provider the debit is held as PENDING until the bank transfer settles;
+	// sandbox withdrawals never leave the platform and complete instantly
+	isPayout := uc.payoutProvider != nil && !userWallet.Sandbox
+	if isPayout && (destination == nil || destination.BankCode == "" || destination.AccountNumber == "") {
+		return nil, nil, apperrors.ErrBankAccountRequired
 	}
 
-	systemWallet, err := uc.getSystemWallet()
+	status := models.TransactionStatusCompleted
+	if isPayout {
+		status = models.TransactionStatusPending
+	}
+
+	tier, err := resolveWalletTier(ctx, uc.repos, userWallet)
+	if err != nil {
+		return nil, nil, err
+	}
+	purpose, err := purposeDefinition(ctx, uc.repos, models.TransactionPurposeWithdrawal)
+	if err != nil {
+		return nil, nil, err
+	}
+	if purpose.CountsTowardLimits {
+		if err := checkDebitLimits(ctx, uc.repos, tier, userWallet, amount); err != nil {
+			return nil, nil, err
+		}
+	}
+	fee := decimal.Zero
+	if purpose.ChargesFees {
+		fee = tier.WithdrawalFee(amount)
+	}
+
+	if !userWallet.CanDebit(amount.Add(fee)) {
+		err := apperrors.ErrInsufficientFunds.Withf("insufficient funds: available=%.2f, requested=%.2f",
+			userWallet.Balance.InexactFloat64(), amount.Add(fee).InexactFloat64())
+		uc.publishFailureEvent(userWallet, amount, reference, err)
+		return nil, nil, err
+	}
+
+	verdict, err := uc.screenDebit(ctx, userWallet, fraud.Check{
+		Purpose:  models.TransactionPurposeWithdrawal,
+		WalletID: walletID,
+		Amount:   amount,
+		Origin:   origin,
+		At:       time.Now(),
+	}, reference)
+	if err != nil {
+		return nil, nil, err
+	}
+	screening, err := uc.screenAML(ctx, userWallet, nil, models.TransactionPurposeWithdrawal, amount, reference, destination)
+	if err != nil {
+		return nil, nil, err
+	}
+	// Flagged withdrawals and withdrawals under AML review hold the debit and fee until they are
+	// reviewed; nothing is paid out yet
+	held := verdict.Action == fraud.ActionFlag || screening.Decision == compliance.DecisionReview
+	if held {
+		status = holdStatus(screening)
+	}
+
+	systemWallet, err := uc.getSystemWallet(ctx, userWallet)
 	if err != nil {
 		return nil, nil, fmt.Errorf("failed to get system wallet: %w", err)
 	}
+	var feeWallet *models.Wallet
+	if fee.IsPositive() {
+		if feeWallet, err = uc.getLedgerWallet(ctx, models.LedgerAccountFees, userWallet.Sandbox); err != nil {
+			return nil, nil, fmt.Errorf("failed to get fee wallet: %w", err)
+		}
+	}
 
 	var userTransaction, systemTransaction *models.Transaction
+	var payout *models.Payout
 
-	err = uc.repos.DB.Transaction(func(tx *gorm.DB) error {
+	err = uc.repos.UnitOfWork.Do(ctx, func(tx *repositories.Repositories) error {
 		userBalanceBefore := userWallet.Balance
 		userBalanceAfter := userBalanceBefore.Sub(amount)
 
-		if userBalanceAfter.LessThan(decimal.Zero) {
-			return errors.New("insufficient funds for withdrawal")
+		if userBalanceAfter.Sub(fee).LessThan(decimal.Zero) {
+			return apperrors.ErrInsufficientFunds.Withf("insufficient funds for withdrawal")
 		}
 
 		userTransaction = &models.Transaction{
@@ -292,25 +543,16 @@ func (uc *walletUseCase) WithdrawFunds(walletID uint, amount decimal.Decimal, re
 			BalanceAfter:       userBalanceAfter,
 			TransactionPurpose: "WITHDRAWAL",
 			Description:        description,
-			Status:             models.TransactionStatusCompleted,
+			Status:             status,
 		}
+		setOrigin(userTransaction, origin)
 
-		if err := tx.Create(userTransaction).Error; err != nil {
+		if err := tx.Transaction.Create(ctx, userTransaction); err != nil {
 			return fmt.Errorf("failed to create user transaction: %w", err)
 		}
 
-		result := tx.Model(&models.Wallet{}).Where("id = ? AND version = ?", walletID, userWallet.Version).
-			Updates(map[string]interface{}{
-				"balance": userBalanceAfter,
-				"version": gorm.Expr("version + 1"),
-			})
-
-		if result.Error != nil {
-			return fmt.Errorf("failed to update user wallet balance: %w", result.Error)
-		}
-
-		if result.RowsAffected == 0 {
-			return errors.New("user wallet version mismatch - concurrent modification detected")
+		if err := updateWalletBalance(ctx, tx, "user", userWallet, userBalanceAfter.Sub(fee)); err != nil {
+			return err
 		}
 
 		systemBalanceBefore := systemWallet.Balance
@@ -326,43 +568,75 @@ func (uc *walletUseCase) WithdrawFunds(walletID uint, amount decimal.Decimal, re
 			BalanceAfter:         systemBalanceAfter,
 			TransactionPurpose:   "WITHDRAWAL",
 			Description:          fmt.Sprintf("System credit for withdrawal: %s", description),
-			Status:               models.TransactionStatusCompleted,
+			Status:               status,
 			RelatedTransactionID: &userTransaction.ID,
 		}
 
-		if err := tx.Create(systemTransaction).Error; err != nil {
+		if err := tx.Transaction.Create(ctx, systemTransaction); err != nil {
 			return fmt.Errorf("failed to create system transaction: %w", err)
 		}
 
-		result = tx.Model(&models.Wallet{}).Where("id = ? AND version = ?", systemWallet.ID, systemWallet.Version).
-			Updates(map[string]interface{}{
-				"balance": systemBalanceAfter,
-				"version": gorm.Expr("version + 1"),
-			})
+		if err := uc.recordFee(ctx, tx, userWallet, userBalanceAfter, feeWallet, fee, reference, status, userTransaction.ID); err != nil {
+			return err
+		}
 
-		if result.Error != nil {
-			return fmt.Errorf("failed to update system wallet balance: %w", result.Error)
+		if held {
+			if err := uc.createHoldReview(ctx, tx, verdict, screening, userTransaction, userWallet.Currency, fee, nil, destination); err != nil {
+				return err
+			}
+			return linkRelated(ctx, tx, userTransaction, systemTransaction)
 		}
 
-		if result.RowsAffected == 0 {
-			return errors.New("system wallet version mismatch - concurrent modification detected")
+		if isPayout {
+			payout = &models.Payout{
+				WalletID:      walletID,
+				TransactionID: userTransaction.ID,
+				Reference:     reference,
+				Provider:      uc.payoutProvider.Name(),
+				Amount:        amount,
+				Fee:           fee,
+				Currency:      userWallet.Currency,
+				BankAccount:   *destination,
+				Status:        models.PayoutStatusPending,
+			}
+			if err := tx.Payout.Create(ctx, payout); err != nil {
+				return fmt.Errorf("failed to create payout: %w", err)
+			}
+
+			// The system wallet is credited when the payout settles
+			return linkRelated(ctx, tx, userTransaction, systemTransaction)
+		}
+
+		if err := updateWalletBalance(ctx, tx, "system", systemWallet, systemBalanceAfter); err != nil {
+			return err
 		}
 
-		return tx.Model(userTransaction).Update("related_transaction_id", systemTransaction.ID).Error
+		return linkRelated(ctx, tx, userTransaction, systemTransaction)
 	})
 
 	if err != nil {
+		uc.publishFailureEvent(userWallet, amount, reference, err)
 		return nil, nil, err
 	}
 
-	go uc.performPostTransactionReconciliation(walletID)
+	switch {
+	case held:
+	case isPayout:
+		if err := uc.dispatchPayout(ctx, payout, description); err != nil {
+			return nil, nil, apperrors.ErrPayoutFailed.Wrap(err)
+		}
+	default:
+		uc.publishTransactionEvent(events.EventWithdrawalCompleted, userWallet, userTransaction)
+	}
+
+	uc.schedulePostTransactionAudit(walletID)
 
-	userTx, err := uc.repos.Transaction.GetByID(userTransaction.ID)
+	userTx, err := uc.repos.Transaction.GetByID(ctx, userTransaction.ID)
 	if err != nil {
 		return nil, nil, fmt.Errorf("failed to load user transaction: %w", err)
 	}
 
-	systemTx, err := uc.repos.Transaction.GetByID(systemTransaction.ID)
+	systemTx, err := uc.repos.Transaction.GetByID(ctx, systemTransaction.ID)
 	if err != nil {
 		return nil, nil, fmt.Errorf("failed to load system transaction: %w", err)
 	}
@@ -370,75 +644,138 @@ func (uc *walletUseCase) WithdrawFunds(walletID uint, amount decimal.Decimal, re
 	return userTx, systemTx, nil
 }
 
-func (uc *walletUseCase) TransferFunds(fromWalletID, toWalletID uint, amount decimal.Decimal, reference, description string) (*models.Transaction, *models.Transaction, error) {
+func (uc *walletUseCase) TransferFunds(ctx context.Context, fromWalletID, toWalletID uint, amount decimal.Decimal, reference, description string, origin *fraud.Origin) (*models.Transaction, *models.Transaction, error) {
+	return uc.transferFunds(ctx, fromWalletID, toWalletID, amount, reference, description, origin, nil)
+}
+
+// transferFunds moves amount between two wallets, converting it when their currencies differ. A
+// transfer made with a quote keeps the quote's fee and exchange rate and uses the quote up in the
+// same database transaction
+func (uc *walletUseCase) transferFunds(ctx context.Context, fromWalletID, toWalletID uint, amount decimal.Decimal, reference, description string, origin *fraud.Origin, quote *models.TransferQuote) (*models.Transaction, *models.Transaction, error) {
 	// Validate different wallets
 	if fromWalletID == toWalletID {
-		return nil, nil, errors.New("cannot transfer to the same wallet")
+		return nil, nil, apperrors.ErrSelfPayment
 	}
 	// Get both wallets
-	fromWallet, err := uc.repos.Wallet.GetByID(fromWalletID)
+	fromWallet, err := uc.repos.Wallet.GetByID(ctx, fromWalletID)
 	if err != nil {
-		return nil, nil, errors.New("source wallet not found")
+		return nil, nil, apperrors.ErrWalletNotFound.Withf("source wallet not found")
+	}
+
+	toWallet, err := uc.repos.Wallet.GetByID(ctx, toWalletID)
+	if err != nil {
+		return nil, nil, apperrors.ErrCounterpartyWalletNotFound
+	}
+
+	if fromWallet.Sandbox != toWallet.Sandbox {
+		return nil, nil, apperrors.ErrValidation.Withf("cannot transfer between sandbox and live wallets")
+	}
+
+	if err := enforceBlocklist(ctx, uc.repos, models.TransactionPurposeTransfer, reference, amount, fromWallet, toWallet, nil); err != nil {
+		return nil, nil, err
 	}
 
-	toWallet, err := uc.repos.Wallet.GetByID(toWalletID)
+	if err := uc.screenNewRecipient(ctx, fromWallet, toWallet, amount, reference); err != nil {
+		return nil, nil, err
+	}
+
+	price, err := uc.priceTransfer(ctx, fromWallet, toWallet, amount, quote)
 	if err != nil {
-		return nil, nil, errors.New("destination wallet not found")
+		return nil, nil, err
 	}
+	fee := price.Fee
 
-	if !fromWallet.CanDebit(amount) {
-		return nil, nil, fmt.Errorf("insufficient funds in source wallet: available=%.2f, requested=%.2f",
-			fromWallet.Balance.InexactFloat64(), amount.InexactFloat64())
+	if !fromWallet.CanDebit(amount.Add(fee)) {
+		err := apperrors.ErrInsufficientFunds.Withf("insufficient funds in source wallet: available=%.2f, requested=%.2f",
+			fromWallet.Balance.InexactFloat64(), amount.Add(fee).InexactFloat64())
+		uc.publishFailureEvent(fromWallet, amount, reference, err)
+		return nil, nil, err
 	}
 
 	if !toWallet.IsActive() {
-		return nil, nil, errors.New("destination wallet is not active")
+		return nil, nil, apperrors.ErrCounterpartyWalletInactive
 	}
 
 	// Validate amount
 	if amount.LessThanOrEqual(decimal.Zero) {
-		return nil, nil, errors.New("amount must be greater than zero")
+		return nil, nil, apperrors.ErrInvalidAmount
 	}
 
-	if err := uc.performPreTransactionReconciliation(fromWalletID); err != nil {
+	if err := uc.performPreTransactionReconciliation(ctx, fromWalletID); err != nil {
 		return nil, nil, fmt.Errorf("source wallet reconciliation failed: %w", err)
 	}
-	if err := uc.performPreTransactionReconciliation(toWalletID); err != nil {
+	if err := uc.performPreTransactionReconciliation(ctx, toWalletID); err != nil {
 		return nil, nil, fmt.Errorf("destination wallet reconciliation failed: %w", err)
 	}
 
 	// Check for duplicate reference
-	_, err = uc.repos.Transaction.GetByReference(reference)
+	_, err = uc.repos.Transaction.GetByReference(ctx, reference)
 	if err == nil {
-		return nil, nil, errors.New("duplicate reference")
+		return nil, nil, apperrors.ErrDuplicateReference
 	}
 	if err != gorm.ErrRecordNotFound {
 		return nil, nil, fmt.Errorf("error checking reference: %w", err)
 	}
 
 	// Prevent transfers to system accounts (unless explicitly allowed)
-	systemWallet, _ := uc.getSystemWallet()
-	if systemWallet != nil && toWalletID == systemWallet.ID {
-		return nil, nil, errors.New("direct transfers to system account are not allowed")
+	systemWallet, _ := uc.getSystemWallet(ctx, fromWallet)
+	if (systemWallet != nil && toWalletID == systemWallet.ID) || toWallet.User.IsSystemAccount() {
+		return nil, nil, apperrors.ErrValidation.Withf("direct transfers to system account are not allowed")
+	}
+	var feeWallet *models.Wallet
+	if fee.IsPositive() {
+		if feeWallet, err = uc.getLedgerWallet(ctx, models.LedgerAccountFees, fromWallet.Sandbox); err != nil {
+			return nil, nil, fmt.Errorf("failed to get fee wallet: %w", err)
+		}
 	}
 
 	fromBalanceBefore := fromWallet.Balance
-	fromBalanceAfter := fromBalanceBefore.Sub(amount)
+	fromBalanceAfter := fromBalanceBefore.Sub(amount).Sub(fee)
 
 	// Double-check sufficient funds within transaction
 	if fromBalanceAfter.LessThan(decimal.Zero) {
-		return nil, nil, errors.New("insufficient funds for transfer")
+		return nil, nil, apperrors.ErrInsufficientFunds.Withf("insufficient funds for transfer")
+	}
+
+	verdict, err := uc.screenDebit(ctx, fromWallet, fraud.Check{
+		Purpose:              models.TransactionPurposeTransfer,
+		WalletID:             fromWalletID,
+		CounterpartyWalletID: toWalletID,
+		Amount:               amount,
+		Origin:               origin,
+		At:                   time.Now(),
+	}, reference)
+	if err != nil {
+		return nil, nil, err
+	}
+	screening, err := uc.screenAML(ctx, fromWallet, toWallet, models.TransactionPurposeTransfer, amount, reference, nil)
+	if err != nil {
+		return nil, nil, err
+	}
+	// Flagged transfers and transfers under AML review hold the debit and fee until they are
+	// reviewed; the recipient and the system wallet are only credited on approval
+	held := verdict.Action == fraud.ActionFlag || screening.Decision == compliance.DecisionReview
+	status := models.TransactionStatusCompleted
+	if held {
+		status = holdStatus(screening)
 	}
 
 	var outTransaction, inTransaction *models.Transaction
 
-	err = uc.repos.DB.Transaction(func(tx *gorm.DB) error {
+	metadata := transferMetadata(price)
+	err = uc.repos.UnitOfWork.Do(ctx, func(tx *repositories.Repositories) error {
+		if quote != nil {
+			if err := useQuote(ctx, tx, quote.ID, time.Now()); err != nil {
+				return err
+			}
+		}
+
 		outReference := fmt.Sprintf("%s-OUT", reference)
 		fromBalanceBefore := fromWallet.Balance
 		fromBalanceAfter := fromBalanceBefore.Sub(amount)
 
-		if fromBalanceAfter.LessThan(decimal.Zero) {
-			return errors.New("insufficient funds for transfer")
+		if fromBalanceAfter.Sub(fee).LessThan(decimal.Zero) {
+			return apperrors.ErrInsufficientFunds.Withf("insufficient funds for transfer")
 		}
 
 		outTransaction = &models.Transaction{
@@ -446,91 +783,79 @@ func (uc *walletUseCase) TransferFunds(fromWalletID, toWalletID uint, amount dec
 			WalletID:           fromWalletID,
 			TransactionType:    models.TransactionTypeDebit,
 			Amount:             amount,
-			Metadata:           `{"source": "transfer"}`,
+			Metadata:           metadata,
 			BalanceBefore:      fromBalanceBefore,
 			TransactionPurpose: "TRANSFER",
 			BalanceAfter:       fromBalanceAfter,
 			Description:        fmt.Sprintf("Transfer to wallet %d: %s", toWalletID, description),
-			Status:             models.TransactionStatusCompleted,
+			Status:             status,
 		}
+		setOrigin(outTransaction, origin)
 
-		if err := tx.Create(outTransaction).Error; err != nil {
+		if err := tx.Transaction.Create(ctx, outTransaction); err != nil {
 			return fmt.Errorf("failed to create outgoing transaction: %w", err)
 		}
 
 		inReference := fmt.Sprintf("%s-IN", reference)
 		toBalanceBefore := toWallet.Balance
-		toBalanceAfter := toBalanceBefore.Add(amount)
+		toBalanceAfter := toBalanceBefore.Add(price.CreditAmount)
 
 		inTransaction = &models.Transaction{
 			Reference:            inReference,
 			WalletID:             toWalletID,
 			TransactionType:      models.TransactionTypeCredit,
 			TransactionPurpose:   "TRANSFER",
-			Amount:               amount,
+			Amount:               price.CreditAmount,
 			BalanceBefore:        toBalanceBefore,
-			Metadata:             `{"source": "transfer"}`,
+			Metadata:             metadata,
 			BalanceAfter:         toBalanceAfter,
 			Description:          fmt.Sprintf("Transfer from wallet %d: %s", fromWalletID, description),
-			Status:               models.TransactionStatusCompleted,
+			Status:               status,
 			RelatedTransactionID: &outTransaction.ID,
 		}
 
-		if err := tx.Create(inTransaction).Error; err != nil {
+		if err := tx.Transaction.Create(ctx, inTransaction); err != nil {
 			return fmt.Errorf("failed to create incoming transaction: %w", err)
 		}
 
-		result := tx.Model(&models.Wallet{}).Where("id = ? AND version = ?", fromWalletID, fromWallet.Version).
-			Updates(map[string]interface{}{
-				"balance": fromBalanceAfter,
-				"version": gorm.Expr("version + 1"),
-			})
-
-		if result.Error != nil {
-			return fmt.Errorf("failed to update source wallet balance: %w", result.Error)
+		if err := uc.recordFee(ctx, tx, fromWallet, fromBalanceAfter, feeWallet, fee, reference, status, outTransaction.ID); err != nil {
+			return err
 		}
 
-		if result.RowsAffected == 0 {
-			return errors.New("source wallet version mismatch - concurrent modification detected")
+		if err := updateWalletBalance(ctx, tx, "source", fromWallet, fromBalanceAfter.Sub(fee)); err != nil {
+			return err
 		}
 
-		result = tx.Model(&models.Wallet{}).Where("id = ? AND version = ?", toWalletID, toWallet.Version).
-			Updates(map[string]interface{}{
-				"balance": toBalanceAfter,
-				"version": gorm.Expr("version + 1"),
-			})
-
-		if result.Error != nil {
-			return fmt.Errorf("failed to update destination wallet balance: %w", result.Error)
-		}
-
-		if result.RowsAffected == 0 {
-			return errors.New("destination wallet version mismatch - concurrent modification detected")
+		if err := linkRelated(ctx, tx, outTransaction, inTransaction); err != nil {
+			return err
 		}
 
-		if err := tx.Model(outTransaction).Update("related_transaction_id", inTransaction.ID).Error; err != nil {
-			return fmt.Errorf("failed to link outgoing transaction: %w", err)
+		if held {
+			return uc.createHoldReview(ctx, tx, verdict, screening, outTransaction, fromWallet.Currency, fee, &toWalletID, nil)
 		}
 
-		return nil
+		return updateWalletBalance(ctx, tx, "destination", toWallet, toBalanceAfter)
 	})
 
 	if err != nil {
+		uc.publishFailureEvent(fromWallet, amount, reference, err)
 		return nil, nil, err
 	}
 
+	if !held {
+		uc.publishTransactionEvent(events.EventTransferSent, fromWallet, outTransaction)
+		uc.publishTransactionEvent(events.EventCreditReceived, toWallet, inTransaction)
+	}
+
 	// POST-TRANSACTION RECONCILIATION: Audit checks for both wallets
-	go func() {
-		uc.performPostTransactionReconciliation(fromWalletID)
-		uc.performPostTransactionReconciliation(toWalletID)
-	}()
+	uc.schedulePostTransactionAudit(fromWalletID, toWalletID)
 
-	outTx, err := uc.repos.Transaction.GetByID(outTransaction.ID)
+	outTx, err := uc.repos.Transaction.GetByID(ctx, outTransaction.ID)
 	if err != nil {
 		return nil, nil, fmt.Errorf("failed to load outgoing transaction: %w", err)
 	}
 
-	inTx, err := uc.repos.Transaction.GetByID(inTransaction.ID)
+	inTx, err := uc.repos.Transaction.GetByID(ctx, inTransaction.ID)
 	if err != nil {
 		return nil, nil, fmt.Errorf("failed to load incoming transaction: %w", err)
 	}
@@ -538,18 +863,94 @@ func (uc *walletUseCase) TransferFunds(fromWalletID, toWalletID uint, amount dec
 	return outTx, inTx, nil
 }
 
-func (uc *walletUseCase) GetWalletBalance(walletID uint) (decimal.Decimal, error) {
-	wallet, err := uc.repos.Wallet.GetByID(walletID)
+// recordFee writes the two legs of a tier fee: a debit from the charged wallet and a credit to the
+// fee ledger account, both pointing at the transaction the fee was charged for. The charged wallet's
+// balance is that after the transaction and updating it is left to the caller; a completed fee is
+// credited to the fee account here, while a held or pending one is credited when it is released
+func (uc *walletUseCase) recordFee(ctx context.Context, tx *repositories.Repositories, wallet *models.Wallet, balanceBefore decimal.Decimal, feeWallet *models.Wallet, fee decimal.Decimal, reference string, status models.TransactionStatus, chargedTransactionID uint) error {
+	if !fee.IsPositive() {
+		return nil
+	}
+
+	feeBalanceBefore := feeWallet.Balance
+	if status == models.TransactionStatusCompleted {
+		var err error
+		if feeBalanceBefore, err = adjustBalance(ctx, tx.Wallet, feeWallet.ID, fee); err != nil {
+			return err
+		}
+	}
+
+	feeTransaction := &models.Transaction{
+		Reference:            reference + "-FEE",
+		WalletID:             wallet.ID,
+		TransactionType:      models.TransactionTypeDebit,
+		Amount:               fee,
+		Metadata:             `{"source": "fee"}`,
+		BalanceBefore:        balanceBefore,
+		BalanceAfter:         balanceBefore.Sub(fee),
+		TransactionPurpose:   models.TransactionPurposeFee,
+		Description:          fmt.Sprintf("Fee for %s", reference),
+		Status:               status,
+		RelatedTransactionID: &chargedTransactionID,
+	}
+	if err := tx.Transaction.Create(ctx, feeTransaction); err != nil {
+		return fmt.Errorf("failed to create fee transaction: %w", err)
+	}
+
+	systemFeeTransaction := &models.Transaction{
+		Reference:            reference + "-FEE_system_credit",
+		WalletID:             feeWallet.ID,
+		TransactionType:      models.TransactionTypeCredit,
+		Amount:               fee,
+		Metadata:             `{"source": "fee"}`,
+		BalanceBefore:        feeBalanceBefore,
+		BalanceAfter:         feeBalanceBefore.Add(fee),
+		TransactionPurpose:   models.TransactionPurposeFee,
+		Description:          fmt.Sprintf("Fee income: %s", reference),
+		Status:               status,
+		RelatedTransactionID: &chargedTransactionID,
+	}
+	if err := tx.Transaction.Create(ctx, systemFeeTransaction); err != nil {
+		return fmt.Errorf("failed to create system fee transaction: %w", err)
+	}
+
+	return nil
+}
+
+// updateWalletBalance sets the balance of a wallet as read at its current version, failing when a
+// concurrent change got in first. label names the wallet in errors
+func updateWalletBalance(ctx context.Context, tx *repositories.Repositories, label string, wallet *models.Wallet, balance decimal.Decimal) error {
+	err := tx.Wallet.UpdateBalance(ctx, wallet.ID, balance, wallet.Version)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return apperrors.ErrConcurrentModification.Withf("%s wallet version mismatch - concurrent modification detected", label)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to update %s wallet balance: %w", label, err)
+	}
+	return nil
+}
+
+// linkRelated points a transaction at its counterpart leg
+func linkRelated(ctx context.Context, tx *repositories.Repositories, transaction, related *models.Transaction) error {
+	transaction.RelatedTransactionID = &related.ID
+	if err := tx.Transaction.LinkRelated(ctx, transaction.ID, related.ID); err != nil {
+		return fmt.Errorf("failed to link transaction %s: %w", transaction.Reference, err)
+	}
+	return nil
+}
+
+func (uc *walletUseCase) GetWalletBalance(ctx context.Context, walletID uint) (decimal.Decimal, error) {
+	wallet, err := uc.repos.Wallet.GetByID(ctx, walletID)
 	if err != nil {
 		return decimal.Zero, err
 	}
 	return wallet.Balance, nil
 }
 
-func (uc *walletUseCase) GetTransactionHistory(walletID uint, cursor *string, limit int) ([]models.Transaction, *string, error) {
-	_, err := uc.repos.Wallet.GetByID(walletID)
+func (uc *walletUseCase) GetTransactionHistory(ctx context.Context, walletID uint, cursor *string, limit int) ([]models.Transaction, *string, error) {
+	_, err := uc.repos.Wallet.GetByID(ctx, walletID)
 	if err != nil {
-		return nil, nil, errors.New("wallet not found")
+		return nil, nil, apperrors.ErrWalletNotFound
 	}
 
 	var cursorTime *time.Time
@@ -558,12 +959,16 @@ func (uc *walletUseCase) GetTransactionHistory(walletID uint, cursor *string, li
 	if cursor != nil && *cursor != "" {
 		decodedCursor, err := uc.decodeCursor(*cursor)
 		if err != nil {
-			return nil, nil, fmt.Errorf("invalid cursor: %w", err)
+			return nil, nil, apperrors.ErrInvalidCursor.Wrap(err)
 		}
 		cursorTime = &decodedCursor.CreatedAt
 		cursorID = &decodedCursor.ID
 	}
-	transactions, err := uc.repos.Transaction.GetByWalletIDWithCursor(walletID, cursorTime, cursorID, limit)
+	transactions, err := uc.repos.Transaction.GetByWalletIDWithCursor(ctx, walletID, cursorTime, cursorID, limit)
+	if err != nil {
+		return nil, nil, err
+	}
+	transactions, err = uc.withArchivedHistory(ctx, walletID, transactions, cursorTime, cursorID, limit)
 	if err != nil {
 		return nil, nil, err
 	}
@@ -588,6 +993,197 @@ func (uc *walletUseCase) GetTransactionHistory(walletID uint, cursor *string, li
 	return transactions, nextCursor, nil
 }
 
+func (uc *walletUseCase) ListWallets(ctx context.Context, filter repositories.WalletFilter, page, pageSize int) ([]models.Wallet, int64, error) {
+	offset := (page - 1) * pageSize
+	return uc.repos.Wallet.ListWithFilter(ctx, filter, offset, pageSize)
+}
+
+// GetTransaction returns a transaction, looking in the archive when it is no longer live
+func (uc *walletUseCase) GetTransaction(ctx context.Context, id uint) (*models.Transaction, error) {
+	transaction, err := uc.repos.Transaction.GetByID(ctx, id)
+	if !errors.Is(err, gorm.ErrRecordNotFound) {
+		return transaction, err
+	}
+	archived, archiveErr := uc.repos.ArchivedTransaction.GetByID(ctx, id)
+	if archiveErr != nil {
+		return nil, err
+	}
+	restored := archived.ToTransaction()
+	return &restored, nil
+}
+
+// GetTransactionByReference finds the operation made with a client reference and returns the leg
+// carrying it, or the outgoing leg of a transfer, with its double-entry counter leg. Fee legs are left
+// out; the counter leg is nil for a transaction without one
+func (uc *walletUseCase) GetTransactionByReference(ctx context.Context, reference string) (*models.Transaction, *models.Transaction, error) {
+	reference = strings.TrimSpace(reference)
+	if reference == "" {
+		return nil, nil, apperrors.ErrTransactionNotFound
+	}
+	for _, candidate := range []string{reference, reference + "-OUT"} {
+		transaction, err := uc.findByReference(ctx, candidate)
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			continue
+		}
+		if err != nil {
+			return nil, nil, err
+		}
+		counter, err := uc.counterLeg(ctx, transaction)
+		if err != nil {
+			return nil, nil, err
+		}
+		return transaction, counter, nil
+	}
+	return nil, nil, apperrors.ErrTransactionNotFound
+}
+
+// GetWalletTransactionByReference is GetTransactionByReference for a wallet owner: it returns the
+// wallet's own leg first, and nothing for operations the wallet took no part in
+func (uc *walletUseCase) GetWalletTransactionByReference(ctx context.Context, walletID uint, reference string) (*models.Transaction, *models.Transaction, error) {
+	transaction, counter, err := uc.GetTransactionByReference(ctx, reference)
+	if err != nil {
+		return nil, nil, err
+	}
+	if transaction.WalletID == walletID {
+		return transaction, counter, nil
+	}
+	if counter != nil && counter.WalletID == walletID {
+		return counter, transaction, nil
+	}
+	return nil, nil, apperrors.ErrTransactionNotFound
+}
+
+// GetWalletTransaction returns a transaction of the wallet with its double-entry counter leg and
+// status history. Transactions of other wallets are reported as not found
+func (uc *walletUseCase) GetWalletTransaction(ctx context.Context, walletID, id uint) (*models.Transaction, *models.Transaction, []models.TransactionStatusChange, error) {
+	transaction, err := uc.GetTransaction(ctx, id)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil, nil, apperrors.ErrTransactionNotFound
+	}
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	if transaction.WalletID != walletID {
+		return nil, nil, nil, apperrors.ErrTransactionNotFound
+	}
+
+	counter, err := uc.counterLeg(ctx, transaction)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	history, err := uc.repos.TransactionStatusChange.ListByTransactionID(ctx, transaction.ID)
+	if err != nil {
+		return nil, nil, nil, fmt.Errorf("error loading transaction status history: %w", err)
+	}
+	return transaction, counter, history, nil
+}
+
+// CategorizeTransaction tags a live transaction of the wallet with a spending category for budgets;
+// an empty category clears it
+func (uc *walletUseCase) CategorizeTransaction(ctx context.Context, walletID, id uint, category string) (*models.Transaction, error) {
+	category, err := normalizeCategory(category, true)
+	if err != nil {
+		return nil, err
+	}
+	transaction, err := uc.repos.Transaction.GetByID(ctx, id)
+	if err != nil || transaction.WalletID != walletID {
+		return nil, apperrors.ErrTransactionNotFound
+	}
+
+	if err := uc.repos.Transaction.SetCategory(ctx, transaction.ID, category); err != nil {
+		return nil, fmt.Errorf("failed to categorize transaction: %w", err)
+	}
+	transaction.Category = category
+	return transaction, nil
+}
+
+// findByReference looks a transaction up by reference, in the archive when it is no longer live
+func (uc *walletUseCase) findByReference(ctx context.Context, reference string) (*models.Transaction, error) {
+	transaction, err := uc.repos.Transaction.GetByReference(ctx, reference)
+	if !errors.Is(err, gorm.ErrRecordNotFound) {
+		return transaction, err
+	}
+	archived, archiveErr := uc.repos.ArchivedTransaction.GetByReference(ctx, reference)
+	if archiveErr != nil {
+		return nil, err
+	}
+	restored := archived.ToTransaction()
+	return &restored, nil
+}
+
+// counterLeg returns the other side of a double entry: the transaction the leg points at, or the
+// one of the same purpose and opposite type pointing back at it
+func (uc *walletUseCase) counterLeg(ctx context.Context, transaction *models.Transaction) (*models.Transaction, error) {
+	if transaction.RelatedTransactionID != nil {
+		counter, err := uc.GetTransaction(ctx, *transaction.RelatedTransactionID)
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return counter, err
+	}
+
+	legs, err := uc.repos.Transaction.GetByRelatedTransactionID(ctx, transaction.ID)
+	if err != nil {
+		return nil, err
+	}
+	archived, err := uc.repos.ArchivedTransaction.GetByRelatedTransactionID(ctx, transaction.ID)
+	if err != nil {
+		return nil, err
+	}
+	for i := range archived {
+		legs = append(legs, archived[i].ToTransaction())
+	}
+	for i := range legs {
+		if legs[i].TransactionPurpose == transaction.TransactionPurpose && legs[i].TransactionType != transaction.TransactionType {
+			return &legs[i], nil
+		}
+	}
+	return nil, nil
+}
+
+// ListTransactionTypes returns the transaction types transactions can be recorded with
+func (uc *walletUseCase) ListTransactionTypes(ctx context.Context) ([]models.TransactionTypeDefinition, error) {
+	return uc.repos.TransactionType.List(ctx)
+}
+
+// withArchivedHistory merges the wallet's archived transactions into a page of live ones once the
+// page reaches back to when the archived transactions were made. Both sources are read with the same
+// cursor and one extra row, so the merged page pages on exactly as a live-only one
+func (uc *walletUseCase) withArchivedHistory(ctx context.Context, walletID uint, live []models.Transaction, cursor *time.Time, cursorID *uint, limit int) ([]models.Transaction, error) {
+	checkpoint, err := uc.repos.BalanceCheckpoint.GetByWalletID(ctx, walletID)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return live, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if checkpoint.ArchivedThrough == nil {
+		return live, nil
+	}
+	if len(live) > limit && live[len(live)-1].CreatedAt.After(*checkpoint.ArchivedThrough) {
+		return live, nil
+	}
+
+	archived, err := uc.repos.ArchivedTransaction.GetByWalletIDWithCursor(ctx, walletID, cursor, cursorID, limit)
+	if err != nil {
+		return nil, err
+	}
+	merged := live
+	for i := range archived {
+		merged = append(merged, archived[i].ToTransaction())
+	}
+	sort.SliceStable(merged, func(i, j int) bool {
+		if !merged[i].CreatedAt.Equal(merged[j].CreatedAt) {
+			return merged[i].CreatedAt.After(merged[j].CreatedAt)
+		}
+		return merged[i].ID > merged[j].ID
+	})
+	if len(merged) > limit+1 {
+		merged = merged[:limit+1]
+	}
+	return merged, nil
+}
+
 // encodeCursor encodes a cursor to a base64 string
 func (uc *walletUseCase) encodeCursor(cursor TransactionCursor) (*string, error) {
 	cursorJSON, err := json.Marshal(cursor)
@@ -1,364 +1,108 @@
 package usecases
 
 import (
+	"context"
 	"errors"
 	"testing"
 	"time"
 
+	"github.com/limistah/wallet-service/internal/apperrors"
+	"github.com/limistah/wallet-service/internal/config"
 	"github.com/limistah/wallet-service/internal/models"
 	"github.com/limistah/wallet-service/internal/repositories"
+	"github.com/limistah/wallet-service/internal/repositories/memory"
 	"github.com/shopspring/decimal"
-	"gorm.io/gorm"
 )
 
-// MockUserRepository implements UserRepository interface for testing
-type MockUserRepository struct {
-	users  map[uint]*models.User
-	emails map[string]*models.User
-}
-
-func NewMockUserRepository() *MockUserRepository {
-	return &MockUserRepository{
-		users:  make(map[uint]*models.User),
-		emails: make(map[string]*models.User),
-	}
-}
-
-func (m *MockUserRepository) Create(user *models.User) error {
-	if user.ID == 0 {
-		user.ID = uint(len(m.users) + 1)
-	}
-	m.users[user.ID] = user
-	m.emails[user.Email] = user
-	return nil
-}
-
-func (m *MockUserRepository) GetByID(id uint) (*models.User, error) {
-	if user, ok := m.users[id]; ok {
-		return user, nil
-	}
-	return nil, gorm.ErrRecordNotFound
-}
+// MockReconciliationUseCase implements ReconciliationUseCase interface for testing
+type MockReconciliationUseCase struct{}
 
-func (m *MockUserRepository) GetByEmail(email string) (*models.User, error) {
-	if user, ok := m.emails[email]; ok {
-		return user, nil
-	}
-	return nil, gorm.ErrRecordNotFound
+func (m *MockReconciliationUseCase) PerformReconciliation(ctx context.Context) ([]models.ReconciliationReport, error) {
+	return []models.ReconciliationReport{}, nil
 }
 
-func (m *MockUserRepository) Update(user *models.User) error {
-	m.users[user.ID] = user
-	m.emails[user.Email] = user
+func (m *MockReconciliationUseCase) StreamReconciliation(ctx context.Context, scope models.ReconciliationScope, fn func(ReconciliationProgress) error) error {
 	return nil
 }
 
-func (m *MockUserRepository) Delete(id uint) error {
-	if user, ok := m.users[id]; ok {
-		delete(m.emails, user.Email)
-		delete(m.users, id)
-		return nil
-	}
-	return gorm.ErrRecordNotFound
-}
-
-func (m *MockUserRepository) List(offset, limit int) ([]models.User, error) {
-	users := make([]models.User, 0, len(m.users))
-	for _, user := range m.users {
-		users = append(users, *user)
-	}
-	return users, nil
+func (m *MockReconciliationUseCase) StartRun(ctx context.Context, requestedBy uint, scope models.ReconciliationScope) (*models.ReconciliationRun, error) {
+	return &models.ReconciliationRun{RequestedBy: requestedBy, Status: models.ReconciliationRunStatusPending}, nil
 }
 
-// MockWalletRepository implements WalletRepository interface for testing
-type MockWalletRepository struct {
-	wallets     map[uint]*models.Wallet
-	userWallets map[uint]*models.Wallet
+func (m *MockReconciliationUseCase) GetRun(ctx context.Context, runID uint) (*models.ReconciliationRun, error) {
+	return &models.ReconciliationRun{ID: runID}, nil
 }
 
-func NewMockWalletRepository() *MockWalletRepository {
-	return &MockWalletRepository{
-		wallets:     make(map[uint]*models.Wallet),
-		userWallets: make(map[uint]*models.Wallet),
-	}
-}
-
-func (m *MockWalletRepository) Create(wallet *models.Wallet) error {
-	if wallet.ID == 0 {
-		wallet.ID = uint(len(m.wallets) + 1)
-	}
-	m.wallets[wallet.ID] = wallet
-	m.userWallets[wallet.UserID] = wallet
+func (m *MockReconciliationUseCase) ProcessRunBatch(ctx context.Context, runID uint) error {
 	return nil
 }
 
-func (m *MockWalletRepository) GetByID(id uint) (*models.Wallet, error) {
-	if wallet, ok := m.wallets[id]; ok {
-		return wallet, nil
-	}
-	return nil, gorm.ErrRecordNotFound
+func (m *MockReconciliationUseCase) PerformWalletReconciliation(ctx context.Context, walletID uint) (*models.ReconciliationReport, error) {
+	// Return a successful reconciliation report
+	return &models.ReconciliationReport{
+		WalletID:          walletID,
+		Status:            models.ReconciliationStatusMatch,
+		StoredBalance:     decimal.NewFromFloat(100.00),
+		CalculatedBalance: decimal.NewFromFloat(100.00),
+		Difference:        decimal.Zero,
+	}, nil
 }
 
-func (m *MockWalletRepository) GetByUserID(userID uint) (*models.Wallet, error) {
-	if wallet, ok := m.userWallets[userID]; ok {
-		return wallet, nil
-	}
-	return nil, gorm.ErrRecordNotFound
+func (m *MockReconciliationUseCase) GetReconciliationReports(ctx context.Context, page, pageSize int) ([]models.ReconciliationReport, error) {
+	return []models.ReconciliationReport{}, nil
 }
 
-func (m *MockWalletRepository) Update(wallet *models.Wallet) error {
-	m.wallets[wallet.ID] = wallet
-	m.userWallets[wallet.UserID] = wallet
-	return nil
+func (m *MockReconciliationUseCase) GetMismatchReports(ctx context.Context, page, pageSize int) ([]models.ReconciliationReport, error) {
+	return []models.ReconciliationReport{}, nil
 }
 
-func (m *MockWalletRepository) UpdateBalance(walletID uint, newBalance decimal.Decimal, version uint) error {
-	if wallet, ok := m.wallets[walletID]; ok {
-		if wallet.Version != version {
-			return errors.New("version mismatch")
-		}
-		wallet.Balance = newBalance
-		wallet.Version++
-		return nil
-	}
-	return gorm.ErrRecordNotFound
+func (m *MockReconciliationUseCase) GetWalletReconciliationReports(ctx context.Context, walletID uint) ([]models.ReconciliationReport, error) {
+	return []models.ReconciliationReport{}, nil
 }
 
-func (m *MockWalletRepository) List(offset, limit int) ([]models.Wallet, error) {
-	wallets := make([]models.Wallet, 0, len(m.wallets))
-	for _, wallet := range m.wallets {
-		wallets = append(wallets, *wallet)
-	}
-	return wallets, nil
+func (m *MockReconciliationUseCase) AcknowledgeReport(ctx context.Context, reportID, operatorID uint, note string) (*models.ReconciliationReport, error) {
+	return &models.ReconciliationReport{ID: reportID, ResolutionStatus: models.ReconciliationResolutionAcknowledged}, nil
 }
 
-func (m *MockWalletRepository) GetAllForReconciliation() ([]models.Wallet, error) {
-	return m.List(0, 100)
+func (m *MockReconciliationUseCase) AssignReport(ctx context.Context, reportID, assigneeID, operatorID uint) (*models.ReconciliationReport, error) {
+	return &models.ReconciliationReport{ID: reportID, AssigneeID: &assigneeID}, nil
 }
 
-// MockTransactionRepository implements TransactionRepository interface for testing
-type MockTransactionRepository struct {
-	transactions map[uint]*models.Transaction
-	references   map[string]*models.Transaction
-	idCounter    uint
+func (m *MockReconciliationUseCase) AddReportNote(ctx context.Context, reportID, operatorID uint, note string) (*models.ReconciliationReport, error) {
+	return &models.ReconciliationReport{ID: reportID, InvestigationNotes: note}, nil
 }
 
-func NewMockTransactionRepository() *MockTransactionRepository {
-	return &MockTransactionRepository{
-		transactions: make(map[uint]*models.Transaction),
-		references:   make(map[string]*models.Transaction),
-		idCounter:    0,
-	}
+func (m *MockReconciliationUseCase) ResolveReport(ctx context.Context, reportID, operatorID uint, resolution models.ReconciliationResolutionType, note string) (*models.ReconciliationReport, error) {
+	return &models.ReconciliationReport{ID: reportID, ResolutionStatus: models.ReconciliationResolutionResolved, ResolutionType: &resolution}, nil
 }
 
-func (m *MockTransactionRepository) Create(transaction *models.Transaction) error {
-	m.idCounter++
-	transaction.ID = m.idCounter
-	m.transactions[transaction.ID] = transaction
-	m.references[transaction.Reference] = transaction
+func (m *MockReconciliationUseCase) ExportReports(ctx context.Context, filter repositories.ReconciliationReportFilter, fn func([]models.ReconciliationReport) error) error {
 	return nil
 }
 
-func (m *MockTransactionRepository) GetByID(id uint) (*models.Transaction, error) {
-	if transaction, ok := m.transactions[id]; ok {
-		return transaction, nil
-	}
-	return nil, gorm.ErrRecordNotFound
-}
-
-func (m *MockTransactionRepository) GetByReference(reference string) (*models.Transaction, error) {
-	if transaction, ok := m.references[reference]; ok {
-		return transaction, nil
-	}
-	return nil, gorm.ErrRecordNotFound
-}
-
-func (m *MockTransactionRepository) GetByWalletID(walletID uint, offset, limit int) ([]models.Transaction, error) {
-	transactions := make([]models.Transaction, 0)
-	for _, transaction := range m.transactions {
-		if transaction.WalletID == walletID {
-			transactions = append(transactions, *transaction)
-		}
-	}
-	return transactions, nil
+func (m *MockReconciliationUseCase) SummarizeDay(ctx context.Context, day time.Time) (*models.ReconciliationSummary, error) {
+	return &models.ReconciliationSummary{Date: day}, nil
 }
 
-func (m *MockTransactionRepository) GetByWalletIDWithCursor(walletID uint, cursor *time.Time, cursorID *uint, limit int) ([]models.Transaction, error) {
-	transactions := make([]models.Transaction, 0)
-	for _, transaction := range m.transactions {
-		if transaction.WalletID == walletID {
-			// If cursor is provided, filter based on cursor
-			if cursor != nil && cursorID != nil {
-				if transaction.CreatedAt.Before(*cursor) ||
-					(transaction.CreatedAt.Equal(*cursor) && transaction.ID < *cursorID) {
-					transactions = append(transactions, *transaction)
-				}
-			} else {
-				// No cursor, include all transactions
-				transactions = append(transactions, *transaction)
-			}
-		}
-	}
-
-	// Sort by created_at DESC, id DESC (simulating ORDER BY)
-	for i := 0; i < len(transactions)-1; i++ {
-		for j := i + 1; j < len(transactions); j++ {
-			if transactions[i].CreatedAt.Before(transactions[j].CreatedAt) ||
-				(transactions[i].CreatedAt.Equal(transactions[j].CreatedAt) && transactions[i].ID < transactions[j].ID) {
-				transactions[i], transactions[j] = transactions[j], transactions[i]
-			}
-		}
-	}
-
-	// Apply limit (add 1 to check if there's a next page)
-	if len(transactions) > limit {
-		transactions = transactions[:limit+1]
-	}
-
-	return transactions, nil
-}
-
-func (m *MockTransactionRepository) Update(transaction *models.Transaction) error {
-	m.transactions[transaction.ID] = transaction
-	return nil
-}
-
-func (m *MockTransactionRepository) CalculateBalance(walletID uint) (decimal.Decimal, error) {
-	balance := decimal.Zero
-	for _, transaction := range m.transactions {
-		if transaction.WalletID == walletID && transaction.Status == models.TransactionStatusCompleted {
-			balance = balance.Add(transaction.Amount)
-		}
-	}
-	return balance, nil
-}
-
-func (m *MockTransactionRepository) List(offset, limit int) ([]models.Transaction, error) {
-	transactions := make([]models.Transaction, 0, len(m.transactions))
-	for _, transaction := range m.transactions {
-		transactions = append(transactions, *transaction)
-	}
-	return transactions, nil
-}
-
-// MockTransactionTypeRepository implements TransactionTypeRepository interface for testing
-// Note: TransactionType is now a simple string, but we maintain the interface for compatibility
-type MockTransactionTypeRepository struct {
-	types map[string]string
-}
-
-func NewMockTransactionTypeRepository() *MockTransactionTypeRepository {
-	repo := &MockTransactionTypeRepository{
-		types: make(map[string]string),
-	}
-	// Pre-populate with default types
-	repo.types[string(models.TransactionTypeCredit)] = string(models.TransactionTypeCredit)
-	repo.types[string(models.TransactionTypeDebit)] = string(models.TransactionTypeDebit)
-	return repo
-}
-
-// MockReconciliationRepository implements ReconciliationRepository interface for testing
-type MockReconciliationRepository struct {
-	reports map[uint]*models.ReconciliationReport
-}
-
-func NewMockReconciliationRepository() *MockReconciliationRepository {
-	return &MockReconciliationRepository{
-		reports: make(map[uint]*models.ReconciliationReport),
-	}
-}
-
-func (m *MockReconciliationRepository) Create(report *models.ReconciliationReport) error {
-	if report.ID == 0 {
-		report.ID = uint(len(m.reports) + 1)
-	}
-	m.reports[report.ID] = report
+func (m *MockReconciliationUseCase) MarkSummaryDelivered(ctx context.Context, summaryID uint) error {
 	return nil
 }
 
-func (m *MockReconciliationRepository) GetByID(id uint) (*models.ReconciliationReport, error) {
-	if report, ok := m.reports[id]; ok {
-		return report, nil
-	}
-	return nil, gorm.ErrRecordNotFound
+func (m *MockReconciliationUseCase) GetSummaries(ctx context.Context, page, pageSize int) ([]models.ReconciliationSummary, error) {
+	return []models.ReconciliationSummary{}, nil
 }
 
-func (m *MockReconciliationRepository) GetByWalletID(walletID uint) ([]models.ReconciliationReport, error) {
-	reports := make([]models.ReconciliationReport, 0)
-	for _, report := range m.reports {
-		if report.WalletID == walletID {
-			reports = append(reports, *report)
-		}
-	}
-	return reports, nil
-}
-
-func (m *MockReconciliationRepository) List(offset, limit int) ([]models.ReconciliationReport, error) {
-	reports := make([]models.ReconciliationReport, 0, len(m.reports))
-	for _, report := range m.reports {
-		reports = append(reports, *report)
-	}
-	return reports, nil
-}
-
-func (m *MockReconciliationRepository) GetMismatches(offset, limit int) ([]models.ReconciliationReport, error) {
-	reports := make([]models.ReconciliationReport, 0)
-	for _, report := range m.reports {
-		if report.Status == models.ReconciliationStatusMismatch {
-			reports = append(reports, *report)
-		}
-	}
-	return reports, nil
-}
-
-// MockReconciliationUseCase implements ReconciliationUseCase interface for testing
-type MockReconciliationUseCase struct{}
-
-func (m *MockReconciliationUseCase) PerformReconciliation() ([]models.ReconciliationReport, error) {
-	return []models.ReconciliationReport{}, nil
-}
-
-func (m *MockReconciliationUseCase) PerformWalletReconciliation(walletID uint) (*models.ReconciliationReport, error) {
-	// Return a successful reconciliation report
-	return &models.ReconciliationReport{
-		WalletID:          walletID,
-		Status:            models.ReconciliationStatusMatch,
-		StoredBalance:     decimal.NewFromFloat(100.00),
-		CalculatedBalance: decimal.NewFromFloat(100.00),
-		Difference:        decimal.Zero,
-	}, nil
-}
-
-func (m *MockReconciliationUseCase) GetReconciliationReports(page, pageSize int) ([]models.ReconciliationReport, error) {
-	return []models.ReconciliationReport{}, nil
-}
-
-func (m *MockReconciliationUseCase) GetMismatchReports(page, pageSize int) ([]models.ReconciliationReport, error) {
-	return []models.ReconciliationReport{}, nil
-}
-
-func (m *MockTransactionTypeRepository) GetByName(name string) (*models.TransactionType, error) {
-	// Since TransactionType is now a simple string, return a dummy struct for compatibility
-	return nil, gorm.ErrRecordNotFound
-}
-
-func (m *MockTransactionTypeRepository) List() ([]models.TransactionType, error) {
-	// Return empty list since TransactionType is now a simple string
-	return []models.TransactionType{}, nil
-}
-
-func (m *MockTransactionTypeRepository) Create(transactionType *models.TransactionType) error {
-	// No-op since TransactionType is now a simple string
-	return nil
+func (m *MockReconciliationUseCase) CheckpointBalances(ctx context.Context, before time.Time) (int, error) {
+	return 0, nil
 }
 
 // Helper function to create test repositories and data
 func setupTestEnvironment() (*repositories.Repositories, *MockReconciliationUseCase) {
-	userRepo := NewMockUserRepository()
-	walletRepo := NewMockWalletRepository()
-	transactionRepo := NewMockTransactionRepository()
-	transactionTypeRepo := NewMockTransactionTypeRepository()
-	reconciliationRepo := NewMockReconciliationRepository()
+	userRepo := memory.NewUserRepository()
+	walletRepo := memory.NewWalletRepository()
+	transactionRepo := memory.NewTransactionRepository()
+	transactionTypeRepo := memory.NewTransactionTypeRepository()
+	reconciliationRepo := memory.NewReconciliationRepository()
 
 	// Create system user and wallet
 	systemUser := &models.User{
@@ -366,7 +110,7 @@ func setupTestEnvironment() (*repositories.Repositories, *MockReconciliationUseC
 		Email: models.SystemAccountEmail,
 		Name:  "System Account",
 	}
-	userRepo.Create(systemUser)
+	userRepo.Create(context.Background(), systemUser)
 
 	systemWallet := &models.Wallet{
 		ID:       1,
@@ -376,16 +120,25 @@ func setupTestEnvironment() (*repositories.Repositories, *MockReconciliationUseC
 		Status:   models.WalletStatusActive,
 		Version:  0,
 	}
-	walletRepo.Create(systemWallet)
+	walletRepo.Create(context.Background(), systemWallet)
 
 	repos := &repositories.Repositories{
-		User:            userRepo,
-		Wallet:          walletRepo,
-		Transaction:     transactionRepo,
-		TransactionType: transactionTypeRepo,
-		Reconciliation:  reconciliationRepo,
-		DB:              nil, // Skip DB for unit tests
+		User:                    userRepo,
+		Wallet:                  walletRepo,
+		Transaction:             transactionRepo,
+		TransactionType:         transactionTypeRepo,
+		TransactionPurpose:      memory.NewTransactionPurposeRepository(),
+		Reconciliation:          reconciliationRepo,
+		WalletTier:              memory.NewWalletTierRepository(),
+		Blocklist:               memory.NewBlocklistRepository(),
+		ComplianceLog:           memory.NewComplianceLogRepository(),
+		ArchivedTransaction:     memory.NewArchivedTransactionRepository(),
+		BalanceCheckpoint:       memory.NewBalanceCheckpointRepository(),
+		TransactionStatusChange: memory.NewTransactionStatusChangeRepository(),
+		JournalEntry:            memory.NewJournalEntryRepository(transactionRepo),
+		DB:                      nil, // Skip DB for unit tests
 	}
+	repositories.NewInMemoryUnitOfWork(repos)
 
 	reconciliationUC := &MockReconciliationUseCase{}
 	return repos, reconciliationUC
@@ -397,15 +150,15 @@ func TestWalletUseCase_FundWallet(t *testing.T) {
 	walletUC := NewWalletUseCase(repos, reconciliationUC)
 
 	// Create test user and wallet
-	userRepo := repos.User.(*MockUserRepository)
-	walletRepo := repos.Wallet.(*MockWalletRepository)
+	userRepo := repos.User.(*memory.UserRepository)
+	walletRepo := repos.Wallet.(*memory.WalletRepository)
 
 	user := &models.User{
 		ID:    2,
 		Email: "testuser@example.com",
 		Name:  "Test User",
 	}
-	userRepo.Create(user)
+	userRepo.Create(context.Background(), user)
 
 	wallet := &models.Wallet{
 		ID:       2,
@@ -415,10 +168,10 @@ func TestWalletUseCase_FundWallet(t *testing.T) {
 		Status:   models.WalletStatusActive,
 		Version:  0,
 	}
-	walletRepo.Create(wallet)
+	walletRepo.Create(context.Background(), wallet)
 
 	t.Run("should reject zero amount", func(t *testing.T) {
-		_, _, err := walletUC.FundWallet(2, decimal.Zero, "REF001", "Test funding")
+		_, _, err := walletUC.FundWallet(context.Background(), 2, decimal.Zero, "REF001", "Test funding")
 		if err == nil {
 			t.Error("Expected error for zero amount")
 		}
@@ -428,7 +181,7 @@ func TestWalletUseCase_FundWallet(t *testing.T) {
 	})
 
 	t.Run("should reject negative amount", func(t *testing.T) {
-		_, _, err := walletUC.FundWallet(2, decimal.NewFromFloat(-50.00), "REF002", "Test funding")
+		_, _, err := walletUC.FundWallet(context.Background(), 2, decimal.NewFromFloat(-50.00), "REF002", "Test funding")
 		if err == nil {
 			t.Error("Expected error for negative amount")
 		}
@@ -438,7 +191,7 @@ func TestWalletUseCase_FundWallet(t *testing.T) {
 	})
 
 	t.Run("should reject funding nonexistent wallet", func(t *testing.T) {
-		_, _, err := walletUC.FundWallet(999, decimal.NewFromFloat(50.00), "REF003", "Test funding")
+		_, _, err := walletUC.FundWallet(context.Background(), 999, decimal.NewFromFloat(50.00), "REF003", "Test funding")
 		if err == nil {
 			t.Error("Expected error for nonexistent wallet")
 		}
@@ -457,7 +210,7 @@ func TestWalletUseCase_FundWallet(t *testing.T) {
 	})
 
 	t.Run("should reject duplicate reference", func(t *testing.T) {
-		transactionRepo := repos.Transaction.(*MockTransactionRepository)
+		transactionRepo := repos.Transaction.(*memory.TransactionRepository)
 
 		// Create existing transaction with same reference
 		existingTx := &models.Transaction{
@@ -465,9 +218,9 @@ func TestWalletUseCase_FundWallet(t *testing.T) {
 			WalletID:  2,
 			Amount:    decimal.NewFromFloat(25.00),
 		}
-		transactionRepo.Create(existingTx)
+		transactionRepo.Create(context.Background(), existingTx)
 
-		_, _, err := walletUC.FundWallet(2, decimal.NewFromFloat(50.00), "DUPLICATE_REF", "Test funding")
+		_, _, err := walletUC.FundWallet(context.Background(), 2, decimal.NewFromFloat(50.00), "DUPLICATE_REF", "Test funding")
 		if err == nil {
 			t.Error("Expected error for duplicate reference")
 		}
@@ -483,7 +236,7 @@ func TestWalletUseCase_FundWallet(t *testing.T) {
 			Email: "inactive@example.com",
 			Name:  "Inactive User",
 		}
-		userRepo.Create(inactiveUser)
+		userRepo.Create(context.Background(), inactiveUser)
 
 		inactiveWallet := &models.Wallet{
 			ID:       3,
@@ -493,9 +246,9 @@ func TestWalletUseCase_FundWallet(t *testing.T) {
 			Status:   models.WalletStatusSuspended, // Inactive status
 			Version:  0,
 		}
-		walletRepo.Create(inactiveWallet)
+		walletRepo.Create(context.Background(), inactiveWallet)
 
-		_, _, err := walletUC.FundWallet(3, decimal.NewFromFloat(50.00), "REF004", "Test funding")
+		_, _, err := walletUC.FundWallet(context.Background(), 3, decimal.NewFromFloat(50.00), "REF004", "Test funding")
 		if err == nil {
 			t.Error("Expected error for inactive wallet")
 		}
@@ -503,6 +256,27 @@ func TestWalletUseCase_FundWallet(t *testing.T) {
 			t.Errorf("Expected 'wallet is not active', got: %v", err)
 		}
 	})
+
+	t.Run("should credit the wallet and debit the system wallet", func(t *testing.T) {
+		userTx, systemTx, err := walletUC.FundWallet(context.Background(), 2, decimal.NewFromFloat(50.00), "REF005", "Test funding")
+		if err != nil {
+			t.Fatalf("FundWallet() error = %v", err)
+		}
+		wallet, _ := walletRepo.GetByID(context.Background(), 2)
+		if !wallet.Balance.Equal(decimal.NewFromFloat(150.00)) || wallet.Version != 1 {
+			t.Errorf("Expected balance 150 at version 1, got %s at version %d", wallet.Balance, wallet.Version)
+		}
+		systemWallet, _ := walletRepo.GetByID(context.Background(), 1)
+		if !systemWallet.Balance.Equal(decimal.NewFromFloat(999950.00)) {
+			t.Errorf("Expected the system wallet to be debited, got %s", systemWallet.Balance)
+		}
+		if userTx.TransactionType != models.TransactionTypeCredit || !userTx.BalanceAfter.Equal(decimal.NewFromFloat(150.00)) {
+			t.Errorf("Unexpected user transaction %+v", userTx)
+		}
+		if systemTx.RelatedTransactionID == nil || *systemTx.RelatedTransactionID != userTx.ID {
+			t.Errorf("Expected the system leg to point at the user leg, got %v", systemTx.RelatedTransactionID)
+		}
+	})
 }
 
 // Test Withdraw Funds functionality
@@ -511,15 +285,15 @@ func TestWalletUseCase_WithdrawFunds(t *testing.T) {
 	walletUC := NewWalletUseCase(repos, reconciliationUC)
 
 	// Create test user and wallet
-	userRepo := repos.User.(*MockUserRepository)
-	walletRepo := repos.Wallet.(*MockWalletRepository)
+	userRepo := repos.User.(*memory.UserRepository)
+	walletRepo := repos.Wallet.(*memory.WalletRepository)
 
 	user := &models.User{
 		ID:    4,
 		Email: "withdraw@example.com",
 		Name:  "Withdraw User",
 	}
-	userRepo.Create(user)
+	userRepo.Create(context.Background(), user)
 
 	wallet := &models.Wallet{
 		ID:       4,
@@ -529,10 +303,10 @@ func TestWalletUseCase_WithdrawFunds(t *testing.T) {
 		Status:   models.WalletStatusActive,
 		Version:  0,
 	}
-	walletRepo.Create(wallet)
+	walletRepo.Create(context.Background(), wallet)
 
 	t.Run("should reject zero amount", func(t *testing.T) {
-		_, _, err := walletUC.WithdrawFunds(4, decimal.Zero, "WD001", "Test withdrawal")
+		_, _, err := walletUC.WithdrawFunds(context.Background(), 4, decimal.Zero, "WD001", "Test withdrawal", nil, nil)
 		if err == nil {
 			t.Error("Expected error for zero amount")
 		}
@@ -542,7 +316,7 @@ func TestWalletUseCase_WithdrawFunds(t *testing.T) {
 	})
 
 	t.Run("should reject negative amount", func(t *testing.T) {
-		_, _, err := walletUC.WithdrawFunds(4, decimal.NewFromFloat(-50.00), "WD002", "Test withdrawal")
+		_, _, err := walletUC.WithdrawFunds(context.Background(), 4, decimal.NewFromFloat(-50.00), "WD002", "Test withdrawal", nil, nil)
 		if err == nil {
 			t.Error("Expected error for negative amount")
 		}
@@ -552,7 +326,7 @@ func TestWalletUseCase_WithdrawFunds(t *testing.T) {
 	})
 
 	t.Run("should reject withdrawal from nonexistent wallet", func(t *testing.T) {
-		_, _, err := walletUC.WithdrawFunds(999, decimal.NewFromFloat(50.00), "WD003", "Test withdrawal")
+		_, _, err := walletUC.WithdrawFunds(context.Background(), 999, decimal.NewFromFloat(50.00), "WD003", "Test withdrawal", nil, nil)
 		if err == nil {
 			t.Error("Expected error for nonexistent wallet")
 		}
@@ -571,7 +345,7 @@ func TestWalletUseCase_WithdrawFunds(t *testing.T) {
 	})
 
 	t.Run("should reject withdrawal exceeding balance", func(t *testing.T) {
-		_, _, err := walletUC.WithdrawFunds(4, decimal.NewFromFloat(200.00), "WD004", "Test withdrawal")
+		_, _, err := walletUC.WithdrawFunds(context.Background(), 4, decimal.NewFromFloat(200.00), "WD004", "Test withdrawal", nil, nil)
 		if err == nil {
 			t.Error("Expected error for insufficient funds")
 		}
@@ -588,7 +362,7 @@ func TestWalletUseCase_WithdrawFunds(t *testing.T) {
 			Email: "inactive_wd@example.com",
 			Name:  "Inactive Withdraw User",
 		}
-		userRepo.Create(inactiveUser)
+		userRepo.Create(context.Background(), inactiveUser)
 
 		inactiveWallet := &models.Wallet{
 			ID:       5,
@@ -598,9 +372,9 @@ func TestWalletUseCase_WithdrawFunds(t *testing.T) {
 			Status:   models.WalletStatusSuspended,
 			Version:  0,
 		}
-		walletRepo.Create(inactiveWallet)
+		walletRepo.Create(context.Background(), inactiveWallet)
 
-		_, _, err := walletUC.WithdrawFunds(5, decimal.NewFromFloat(50.00), "WD005", "Test withdrawal")
+		_, _, err := walletUC.WithdrawFunds(context.Background(), 5, decimal.NewFromFloat(50.00), "WD005", "Test withdrawal", nil, nil)
 		if err == nil {
 			t.Error("Expected error for inactive wallet")
 		}
@@ -608,6 +382,27 @@ func TestWalletUseCase_WithdrawFunds(t *testing.T) {
 			t.Errorf("Expected 'wallet is not active', got: %v", err)
 		}
 	})
+
+	t.Run("should debit the wallet and credit the system wallet", func(t *testing.T) {
+		userTx, systemTx, err := walletUC.WithdrawFunds(context.Background(), 4, decimal.NewFromFloat(30.00), "WD006", "Test withdrawal", nil, nil)
+		if err != nil {
+			t.Fatalf("WithdrawFunds() error = %v", err)
+		}
+		wallet, _ := walletRepo.GetByID(context.Background(), 4)
+		if !wallet.Balance.Equal(decimal.NewFromFloat(70.00)) {
+			t.Errorf("Expected balance 70, got %s", wallet.Balance)
+		}
+		systemWallet, _ := walletRepo.GetByID(context.Background(), 1)
+		if !systemWallet.Balance.Equal(decimal.NewFromFloat(1000030.00)) {
+			t.Errorf("Expected the system wallet to be credited, got %s", systemWallet.Balance)
+		}
+		if userTx.Status != models.TransactionStatusCompleted || !userTx.BalanceAfter.Equal(decimal.NewFromFloat(70.00)) {
+			t.Errorf("Unexpected user transaction %+v", userTx)
+		}
+		if userTx.RelatedTransactionID == nil || *userTx.RelatedTransactionID != systemTx.ID {
+			t.Errorf("Expected the user leg to point at the system leg, got %v", userTx.RelatedTransactionID)
+		}
+	})
 }
 
 // Test Transfer Funds functionality
@@ -616,8 +411,8 @@ func TestWalletUseCase_TransferFunds(t *testing.T) {
 	walletUC := NewWalletUseCase(repos, reconciliationUC)
 
 	// Create test users and wallets
-	userRepo := repos.User.(*MockUserRepository)
-	walletRepo := repos.Wallet.(*MockWalletRepository)
+	userRepo := repos.User.(*memory.UserRepository)
+	walletRepo := repos.Wallet.(*memory.WalletRepository)
 
 	// Source user and wallet
 	sourceUser := &models.User{
@@ -625,7 +420,7 @@ func TestWalletUseCase_TransferFunds(t *testing.T) {
 		Email: "source@example.com",
 		Name:  "Source User",
 	}
-	userRepo.Create(sourceUser)
+	userRepo.Create(context.Background(), sourceUser)
 
 	sourceWallet := &models.Wallet{
 		ID:       6,
@@ -635,7 +430,7 @@ func TestWalletUseCase_TransferFunds(t *testing.T) {
 		Status:   models.WalletStatusActive,
 		Version:  0,
 	}
-	walletRepo.Create(sourceWallet)
+	walletRepo.Create(context.Background(), sourceWallet)
 
 	// Destination user and wallet
 	destUser := &models.User{
@@ -643,7 +438,7 @@ func TestWalletUseCase_TransferFunds(t *testing.T) {
 		Email: "dest@example.com",
 		Name:  "Destination User",
 	}
-	userRepo.Create(destUser)
+	userRepo.Create(context.Background(), destUser)
 
 	destWallet := &models.Wallet{
 		ID:       7,
@@ -653,10 +448,10 @@ func TestWalletUseCase_TransferFunds(t *testing.T) {
 		Status:   models.WalletStatusActive,
 		Version:  0,
 	}
-	walletRepo.Create(destWallet)
+	walletRepo.Create(context.Background(), destWallet)
 
 	t.Run("should reject transfer to same wallet", func(t *testing.T) {
-		_, _, err := walletUC.TransferFunds(6, 6, decimal.NewFromFloat(50.00), "TR001", "Self transfer")
+		_, _, err := walletUC.TransferFunds(context.Background(), 6, 6, decimal.NewFromFloat(50.00), "TR001", "Self transfer", nil)
 		if err == nil {
 			t.Error("Expected error for transfer to same wallet")
 		}
@@ -666,7 +461,7 @@ func TestWalletUseCase_TransferFunds(t *testing.T) {
 	})
 
 	t.Run("should reject zero amount", func(t *testing.T) {
-		_, _, err := walletUC.TransferFunds(6, 7, decimal.Zero, "TR002", "Zero transfer")
+		_, _, err := walletUC.TransferFunds(context.Background(), 6, 7, decimal.Zero, "TR002", "Zero transfer", nil)
 		if err == nil {
 			t.Error("Expected error for zero amount")
 		}
@@ -676,7 +471,7 @@ func TestWalletUseCase_TransferFunds(t *testing.T) {
 	})
 
 	t.Run("should reject negative amount", func(t *testing.T) {
-		_, _, err := walletUC.TransferFunds(6, 7, decimal.NewFromFloat(-50.00), "TR003", "Negative transfer")
+		_, _, err := walletUC.TransferFunds(context.Background(), 6, 7, decimal.NewFromFloat(-50.00), "TR003", "Negative transfer", nil)
 		if err == nil {
 			t.Error("Expected error for negative amount")
 		}
@@ -686,7 +481,7 @@ func TestWalletUseCase_TransferFunds(t *testing.T) {
 	})
 
 	t.Run("should reject transfer to nonexistent destination", func(t *testing.T) {
-		_, _, err := walletUC.TransferFunds(6, 999, decimal.NewFromFloat(50.00), "TR004", "Transfer to nowhere")
+		_, _, err := walletUC.TransferFunds(context.Background(), 6, 999, decimal.NewFromFloat(50.00), "TR004", "Transfer to nowhere", nil)
 		if err == nil {
 			t.Error("Expected error for nonexistent destination")
 		}
@@ -696,7 +491,7 @@ func TestWalletUseCase_TransferFunds(t *testing.T) {
 	})
 
 	t.Run("should reject transfer from nonexistent source", func(t *testing.T) {
-		_, _, err := walletUC.TransferFunds(999, 7, decimal.NewFromFloat(50.00), "TR005", "Transfer from nowhere")
+		_, _, err := walletUC.TransferFunds(context.Background(), 999, 7, decimal.NewFromFloat(50.00), "TR005", "Transfer from nowhere", nil)
 		if err == nil {
 			t.Error("Expected error for nonexistent source")
 		}
@@ -707,7 +502,7 @@ func TestWalletUseCase_TransferFunds(t *testing.T) {
 	})
 
 	t.Run("should reject transfer exceeding source balance", func(t *testing.T) {
-		_, _, err := walletUC.TransferFunds(6, 7, decimal.NewFromFloat(500.00), "TR006", "Excessive transfer")
+		_, _, err := walletUC.TransferFunds(context.Background(), 6, 7, decimal.NewFromFloat(500.00), "TR006", "Excessive transfer", nil)
 		if err == nil {
 			t.Error("Expected error for insufficient funds")
 		}
@@ -724,7 +519,7 @@ func TestWalletUseCase_TransferFunds(t *testing.T) {
 			Email: "inactive_dest@example.com",
 			Name:  "Inactive Dest User",
 		}
-		userRepo.Create(inactiveDestUser)
+		userRepo.Create(context.Background(), inactiveDestUser)
 
 		inactiveDestWallet := &models.Wallet{
 			ID:       8,
@@ -734,9 +529,9 @@ func TestWalletUseCase_TransferFunds(t *testing.T) {
 			Status:   models.WalletStatusSuspended,
 			Version:  0,
 		}
-		walletRepo.Create(inactiveDestWallet)
+		walletRepo.Create(context.Background(), inactiveDestWallet)
 
-		_, _, err := walletUC.TransferFunds(6, 8, decimal.NewFromFloat(50.00), "TR007", "Transfer to inactive")
+		_, _, err := walletUC.TransferFunds(context.Background(), 6, 8, decimal.NewFromFloat(50.00), "TR007", "Transfer to inactive", nil)
 		if err == nil {
 			t.Error("Expected error for inactive destination wallet")
 		}
@@ -746,7 +541,7 @@ func TestWalletUseCase_TransferFunds(t *testing.T) {
 	})
 
 	t.Run("should prevent transfer to system wallet", func(t *testing.T) {
-		_, _, err := walletUC.TransferFunds(6, 1, decimal.NewFromFloat(50.00), "TR008", "Transfer to system")
+		_, _, err := walletUC.TransferFunds(context.Background(), 6, 1, decimal.NewFromFloat(50.00), "TR008", "Transfer to system", nil)
 		if err == nil {
 			t.Error("Expected error for transfer to system wallet")
 		}
@@ -754,6 +549,24 @@ func TestWalletUseCase_TransferFunds(t *testing.T) {
 			t.Errorf("Expected 'direct transfers to system account are not allowed', got: %v", err)
 		}
 	})
+
+	t.Run("should move funds between the wallets", func(t *testing.T) {
+		outTx, inTx, err := walletUC.TransferFunds(context.Background(), 6, 7, decimal.NewFromFloat(75.00), "TR009", "Rent", nil)
+		if err != nil {
+			t.Fatalf("TransferFunds() error = %v", err)
+		}
+		sourceWallet, _ := walletRepo.GetByID(context.Background(), 6)
+		destWallet, _ := walletRepo.GetByID(context.Background(), 7)
+		if !sourceWallet.Balance.Equal(decimal.NewFromFloat(125.00)) || !destWallet.Balance.Equal(decimal.NewFromFloat(125.00)) {
+			t.Errorf("Expected both wallets to hold 125, got %s and %s", sourceWallet.Balance, destWallet.Balance)
+		}
+		if outTx.Reference != "TR009-OUT" || inTx.Reference != "TR009-IN" {
+			t.Errorf("Unexpected references %q and %q", outTx.Reference, inTx.Reference)
+		}
+		if outTx.RelatedTransactionID == nil || *outTx.RelatedTransactionID != inTx.ID {
+			t.Errorf("Expected the outgoing leg to point at the incoming leg, got %v", outTx.RelatedTransactionID)
+		}
+	})
 }
 
 // Test additional business logic methods
@@ -761,8 +574,8 @@ func TestWalletUseCase_BusinessLogic(t *testing.T) {
 	repos, reconciliationUC := setupTestEnvironment()
 	walletUC := NewWalletUseCase(repos, reconciliationUC)
 
-	userRepo := repos.User.(*MockUserRepository)
-	walletRepo := repos.Wallet.(*MockWalletRepository)
+	userRepo := repos.User.(*memory.UserRepository)
+	walletRepo := repos.Wallet.(*memory.WalletRepository)
 
 	t.Run("should create wallet successfully", func(t *testing.T) {
 		user := &models.User{
@@ -770,9 +583,9 @@ func TestWalletUseCase_BusinessLogic(t *testing.T) {
 			Email: "newwallet@example.com",
 			Name:  "New Wallet User",
 		}
-		userRepo.Create(user)
+		userRepo.Create(context.Background(), user)
 
-		wallet, err := walletUC.CreateWallet(10, "USD")
+		wallet, err := walletUC.CreateWallet(context.Background(), 10, "USD", "")
 		if err != nil {
 			t.Errorf("Expected no error, got: %v", err)
 		}
@@ -803,7 +616,7 @@ func TestWalletUseCase_BusinessLogic(t *testing.T) {
 			Email: "duplicate@example.com",
 			Name:  "Duplicate User",
 		}
-		userRepo.Create(user)
+		userRepo.Create(context.Background(), user)
 
 		// Create first wallet
 		wallet := &models.Wallet{
@@ -814,10 +627,10 @@ func TestWalletUseCase_BusinessLogic(t *testing.T) {
 			Status:   models.WalletStatusActive,
 			Version:  0,
 		}
-		walletRepo.Create(wallet)
+		walletRepo.Create(context.Background(), wallet)
 
 		// Try to create second wallet
-		_, err := walletUC.CreateWallet(11, "USD")
+		_, err := walletUC.CreateWallet(context.Background(), 11, "USD", "")
 		if err == nil {
 			t.Error("Expected error for duplicate wallet creation")
 		}
@@ -832,7 +645,7 @@ func TestWalletUseCase_BusinessLogic(t *testing.T) {
 			Email: "getwallet@example.com",
 			Name:  "Get Wallet User",
 		}
-		userRepo.Create(user)
+		userRepo.Create(context.Background(), user)
 
 		wallet := &models.Wallet{
 			ID:       12,
@@ -842,9 +655,9 @@ func TestWalletUseCase_BusinessLogic(t *testing.T) {
 			Status:   models.WalletStatusActive,
 			Version:  0,
 		}
-		walletRepo.Create(wallet)
+		walletRepo.Create(context.Background(), wallet)
 
-		retrievedWallet, err := walletUC.GetWallet(12)
+		retrievedWallet, err := walletUC.GetWallet(context.Background(), 12)
 		if err != nil {
 			t.Errorf("Expected no error, got: %v", err)
 		}
@@ -864,7 +677,7 @@ func TestWalletUseCase_BusinessLogic(t *testing.T) {
 	})
 
 	t.Run("should get wallet balance", func(t *testing.T) {
-		balance, err := walletUC.GetWalletBalance(12)
+		balance, err := walletUC.GetWalletBalance(context.Background(), 12)
 		if err != nil {
 			t.Errorf("Expected no error, got: %v", err)
 		}
@@ -876,7 +689,7 @@ func TestWalletUseCase_BusinessLogic(t *testing.T) {
 	})
 
 	t.Run("should get wallet by user ID", func(t *testing.T) {
-		retrievedWallet, err := walletUC.GetWalletByUserID(12)
+		retrievedWallet, err := walletUC.GetWalletByUserID(context.Background(), 12)
 		if err != nil {
 			t.Errorf("Expected no error, got: %v", err)
 		}
@@ -893,7 +706,7 @@ func TestWalletUseCase_BusinessLogic(t *testing.T) {
 	})
 
 	t.Run("should handle nonexistent wallet", func(t *testing.T) {
-		_, err := walletUC.GetWallet(999)
+		_, err := walletUC.GetWallet(context.Background(), 999)
 		if err == nil {
 			t.Error("Expected error for nonexistent wallet")
 		}
@@ -910,7 +723,7 @@ func TestWalletUseCase_GetTransactionHistory(t *testing.T) {
 		Email: "test@example.com",
 		Name:  "Test User",
 	}
-	repos.User.Create(user)
+	repos.User.Create(context.Background(), user)
 
 	wallet := &models.Wallet{
 		ID:       100,
@@ -920,7 +733,7 @@ func TestWalletUseCase_GetTransactionHistory(t *testing.T) {
 		Status:   models.WalletStatusActive,
 		Version:  1,
 	}
-	repos.Wallet.Create(wallet)
+	repos.Wallet.Create(context.Background(), wallet)
 
 	// Create some test transactions
 	baseTime := time.Now()
@@ -963,12 +776,12 @@ func TestWalletUseCase_GetTransactionHistory(t *testing.T) {
 		Status:             models.TransactionStatusCompleted,
 	}
 
-	repos.Transaction.Create(tx1) // Will get ID 1
-	repos.Transaction.Create(tx2) // Will get ID 2
-	repos.Transaction.Create(tx3) // Will get ID 3
+	repos.Transaction.Create(context.Background(), tx1) // Will get ID 1
+	repos.Transaction.Create(context.Background(), tx2) // Will get ID 2
+	repos.Transaction.Create(context.Background(), tx3) // Will get ID 3
 
 	t.Run("should get transaction history without cursor (first page)", func(t *testing.T) {
-		transactions, nextCursor, err := walletUC.GetTransactionHistory(100, nil, 2)
+		transactions, nextCursor, err := walletUC.GetTransactionHistory(context.Background(), 100, nil, 2)
 
 		if err != nil {
 			t.Errorf("Expected no error, got: %v", err)
@@ -996,7 +809,7 @@ func TestWalletUseCase_GetTransactionHistory(t *testing.T) {
 
 	t.Run("should get transaction history with cursor (next page)", func(t *testing.T) {
 		// First get the first page to get a cursor
-		_, cursor, err := walletUC.GetTransactionHistory(100, nil, 1)
+		_, cursor, err := walletUC.GetTransactionHistory(context.Background(), 100, nil, 1)
 		if err != nil {
 			t.Errorf("Expected no error getting first page, got: %v", err)
 		}
@@ -1006,7 +819,7 @@ func TestWalletUseCase_GetTransactionHistory(t *testing.T) {
 		}
 
 		// Use the cursor to get the next page
-		transactions, nextCursor, err := walletUC.GetTransactionHistory(100, cursor, 2)
+		transactions, nextCursor, err := walletUC.GetTransactionHistory(context.Background(), 100, cursor, 2)
 
 		if err != nil {
 			t.Errorf("Expected no error, got: %v", err)
@@ -1024,7 +837,7 @@ func TestWalletUseCase_GetTransactionHistory(t *testing.T) {
 	})
 
 	t.Run("should handle nonexistent wallet", func(t *testing.T) {
-		_, _, err := walletUC.GetTransactionHistory(999, nil, 10)
+		_, _, err := walletUC.GetTransactionHistory(context.Background(), 999, nil, 10)
 		if err == nil {
 			t.Error("Expected error for nonexistent wallet")
 		}
@@ -1032,7 +845,7 @@ func TestWalletUseCase_GetTransactionHistory(t *testing.T) {
 
 	t.Run("should handle invalid cursor", func(t *testing.T) {
 		invalidCursor := "invalid-cursor"
-		_, _, err := walletUC.GetTransactionHistory(100, &invalidCursor, 10)
+		_, _, err := walletUC.GetTransactionHistory(context.Background(), 100, &invalidCursor, 10)
 		if err == nil {
 			t.Error("Expected error for invalid cursor")
 		}
@@ -1048,3 +861,290 @@ func contains(str, substr string) bool {
 	}
 	return false
 }
+
+func TestWalletUseCase_SandboxWallets(t *testing.T) {
+	repos, reconciliationUC := setupTestEnvironment()
+
+	userRepo := repos.User.(*memory.UserRepository)
+	walletRepo := repos.Wallet.(*memory.WalletRepository)
+
+	user := &models.User{
+		ID:    20,
+		Email: "integrator@example.com",
+		Name:  "Integrator",
+	}
+	userRepo.Create(context.Background(), user)
+
+	liveWallet := &models.Wallet{
+		ID:       20,
+		UserID:   user.ID,
+		Balance:  decimal.NewFromFloat(75.00),
+		Currency: "NGN",
+		Status:   models.WalletStatusActive,
+	}
+	walletRepo.Create(context.Background(), liveWallet)
+
+	t.Run("should refuse sandbox wallets when sandbox mode is disabled", func(t *testing.T) {
+		walletUC := NewWalletUseCase(repos, reconciliationUC)
+
+		_, err := walletUC.GetSandboxWallet(context.Background(), user.ID)
+		if err == nil || err.Error() != "sandbox mode is disabled" {
+			t.Errorf("Expected 'sandbox mode is disabled', got: %v", err)
+		}
+	})
+
+	walletUC := NewWalletUseCase(repos, reconciliationUC, WithSandbox(true))
+
+	t.Run("should create a sandbox wallet alongside the live wallet", func(t *testing.T) {
+		sandboxWallet, err := walletUC.GetSandboxWallet(context.Background(), user.ID)
+		if err != nil {
+			t.Fatalf("Expected no error, got: %v", err)
+		}
+		if !sandboxWallet.Sandbox || sandboxWallet.ID == liveWallet.ID {
+			t.Errorf("Expected a separate sandbox wallet, got: %+v", sandboxWallet)
+		}
+		if sandboxWallet.Currency != "NGN" {
+			t.Errorf("Expected sandbox wallet in live currency NGN, got: %s", sandboxWallet.Currency)
+		}
+
+		again, _ := walletUC.GetSandboxWallet(context.Background(), user.ID)
+		if again.ID != sandboxWallet.ID {
+			t.Errorf("Expected the existing sandbox wallet to be reused")
+		}
+
+		live, _ := walletUC.GetWalletByUserID(context.Background(), user.ID)
+		if live.ID != liveWallet.ID {
+			t.Errorf("Expected the live wallet to stay the user's default wallet")
+		}
+	})
+
+	t.Run("should reject transfers between sandbox and live wallets", func(t *testing.T) {
+		sandboxWallet, _ := walletUC.GetSandboxWallet(context.Background(), user.ID)
+
+		_, _, err := walletUC.TransferFunds(context.Background(), sandboxWallet.ID, liveWallet.ID, decimal.NewFromFloat(10.00), "SBX-TR001", "Leak", nil)
+		if err == nil || err.Error() != "cannot transfer between sandbox and live wallets" {
+			t.Errorf("Expected 'cannot transfer between sandbox and live wallets', got: %v", err)
+		}
+	})
+
+	t.Run("should only mint into sandbox wallets", func(t *testing.T) {
+		_, err := walletUC.MintSandboxFunds(context.Background(), liveWallet.ID, decimal.NewFromFloat(10.00))
+		if err == nil || err.Error() != "funds can only be minted into sandbox wallets" {
+			t.Errorf("Expected 'funds can only be minted into sandbox wallets', got: %v", err)
+		}
+	})
+}
+
+func TestWalletUseCase_GetTransactionByReference(t *testing.T) {
+	repos, reconciliationUC := setupTestEnvironment()
+	walletUC := NewWalletUseCase(repos, reconciliationUC)
+
+	out := &models.Transaction{Reference: "TRF1-OUT", WalletID: 2, TransactionType: models.TransactionTypeDebit,
+		TransactionPurpose: models.TransactionPurposeTransfer, Amount: decimal.NewFromInt(75), Status: models.TransactionStatusCompleted}
+	repos.Transaction.Create(context.Background(), out)
+	fee := &models.Transaction{Reference: "TRF1-FEE", WalletID: 2, TransactionType: models.TransactionTypeDebit,
+		TransactionPurpose: models.TransactionPurposeFee, Amount: decimal.NewFromInt(1), Status: models.TransactionStatusCompleted,
+		RelatedTransactionID: &out.ID}
+	repos.Transaction.Create(context.Background(), fee)
+	in := &models.Transaction{Reference: "TRF1-IN", WalletID: 3, TransactionType: models.TransactionTypeCredit,
+		TransactionPurpose: models.TransactionPurposeTransfer, Amount: decimal.NewFromInt(75), Status: models.TransactionStatusCompleted,
+		RelatedTransactionID: &out.ID}
+	repos.Transaction.Create(context.Background(), in)
+
+	systemLegID := uint(101)
+	archive := repos.ArchivedTransaction.(*memory.ArchivedTransactionRepository)
+	archive.Add(models.ArchivedTransaction{ID: systemLegID, Reference: "FND1_system_debit", WalletID: 1,
+		TransactionType: models.TransactionTypeDebit, TransactionPurpose: models.TransactionPurposeWalletTopUp, Status: models.TransactionStatusCompleted})
+	archive.Add(models.ArchivedTransaction{ID: 102, Reference: "FND1", WalletID: 2, TransactionType: models.TransactionTypeCredit,
+		TransactionPurpose: models.TransactionPurposeWalletTopUp, Status: models.TransactionStatusCompleted, RelatedTransactionID: &systemLegID})
+
+	tests := []struct {
+		name        string
+		reference   string
+		wantLeg     uint
+		wantCounter uint
+	}{
+		{"transfer by client reference", "TRF1", out.ID, in.ID},
+		{"incoming transfer leg", "TRF1-IN", in.ID, out.ID},
+		{"archived funding", " FND1 ", 102, systemLegID},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			leg, counter, err := walletUC.GetTransactionByReference(context.Background(), tt.reference)
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if leg.ID != tt.wantLeg || counter == nil || counter.ID != tt.wantCounter {
+				t.Errorf("Expected legs %d and %d, got %+v and %+v", tt.wantLeg, tt.wantCounter, leg, counter)
+			}
+		})
+	}
+
+	if _, _, err := walletUC.GetTransactionByReference(context.Background(), "MISSING"); !errors.Is(err, apperrors.ErrTransactionNotFound) {
+		t.Errorf("Expected transaction not found, got: %v", err)
+	}
+
+	leg, counter, err := walletUC.GetWalletTransactionByReference(context.Background(), 3, "TRF1")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if leg.ID != in.ID || counter.ID != out.ID {
+		t.Errorf("Expected the recipient's leg first, got %d and %d", leg.ID, counter.ID)
+	}
+	if _, _, err := walletUC.GetWalletTransactionByReference(context.Background(), 4, "TRF1"); !errors.Is(err, apperrors.ErrTransactionNotFound) {
+		t.Errorf("Expected another wallet's transfer to be not found, got: %v", err)
+	}
+}
+
+func TestWalletUseCase_GetWalletTransaction(t *testing.T) {
+	repos, reconciliationUC := setupTestEnvironment()
+	walletUC := NewWalletUseCase(repos, reconciliationUC)
+
+	out := &models.Transaction{Reference: "TRF1-OUT", WalletID: 2, TransactionType: models.TransactionTypeDebit,
+		TransactionPurpose: models.TransactionPurposeTransfer, Amount: decimal.NewFromInt(75), Status: models.TransactionStatusCompleted,
+		Metadata: `{"channel": "app"}`}
+	repos.Transaction.Create(context.Background(), out)
+	in := &models.Transaction{Reference: "TRF1-IN", WalletID: 3, TransactionType: models.TransactionTypeCredit,
+		TransactionPurpose: models.TransactionPurposeTransfer, Amount: decimal.NewFromInt(75), Status: models.TransactionStatusCompleted,
+		RelatedTransactionID: &out.ID}
+	repos.Transaction.Create(context.Background(), in)
+	history := repos.TransactionStatusChange.(*memory.TransactionStatusChangeRepository)
+	history.Add(models.TransactionStatusChange{TransactionID: out.ID, FromStatus: models.TransactionStatusPendingReview,
+		ToStatus: models.TransactionStatusCompleted, Reason: "approved after review"})
+
+	transaction, related, changes, err := walletUC.GetWalletTransaction(context.Background(), 2, out.ID)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if transaction.ID != out.ID || transaction.Metadata != out.Metadata {
+		t.Errorf("Expected transaction %d with its metadata, got: %+v", out.ID, transaction)
+	}
+	if related == nil || related.ID != in.ID {
+		t.Errorf("Expected related transaction %d, got: %+v", in.ID, related)
+	}
+	if len(changes) != 1 || changes[0].FromStatus != models.TransactionStatusPendingReview {
+		t.Errorf("Expected the review approval in the status history, got: %+v", changes)
+	}
+
+	if _, _, _, err := walletUC.GetWalletTransaction(context.Background(), 3, out.ID); !errors.Is(err, apperrors.ErrTransactionNotFound) {
+		t.Errorf("Expected another wallet's transaction to be not found, got: %v", err)
+	}
+	if _, _, _, err := walletUC.GetWalletTransaction(context.Background(), 2, 999); !errors.Is(err, apperrors.ErrTransactionNotFound) {
+		t.Errorf("Expected transaction not found, got: %v", err)
+	}
+}
+
+func TestWalletUseCase_PreTransactionReconciliation(t *testing.T) {
+	ctx := context.Background()
+	setup := func(storedBalance float64) WalletUseCase {
+		repos := memory.NewRepositories()
+		repos.Wallet.Create(ctx, &models.Wallet{ID: 60, UserID: 60, Balance: decimal.NewFromFloat(storedBalance), Currency: "USD", Status: models.WalletStatusActive})
+		repos.Wallet.Create(ctx, &models.Wallet{ID: 61, UserID: 61, Currency: "USD", Status: models.WalletStatusActive})
+
+		// A funding of 500 whose system leg was posted as 400
+		transactionRepo := repos.Transaction.(*memory.TransactionRepository)
+		funding := &models.Transaction{
+			WalletID:        60,
+			TransactionType: models.TransactionTypeCredit,
+			Amount:          decimal.NewFromFloat(500),
+			Status:          models.TransactionStatusCompleted,
+		}
+		createBalancedTransaction(transactionRepo, funding)
+		counterpart, _ := transactionRepo.GetByID(ctx, *funding.RelatedTransactionID)
+		counterpart.Amount = decimal.NewFromFloat(400)
+		transactionRepo.Update(ctx, counterpart)
+
+		return NewWalletUseCase(repos, NewReconciliationUseCase(repos))
+	}
+
+	t.Run("should refuse a debit from a wallet with drift and a broken pair", func(t *testing.T) {
+		walletUC := setup(1500)
+
+		_, _, err := walletUC.TransferFunds(ctx, 60, 61, decimal.NewFromFloat(500), "TRF-DRIFT", "Drifted", nil)
+		if !errors.Is(err, apperrors.ErrBalanceMismatch) {
+			t.Fatalf("Expected ErrBalanceMismatch, got: %v", err)
+		}
+		wallet, _ := walletUC.GetWallet(ctx, 60)
+		if !wallet.Balance.Equal(decimal.NewFromFloat(1500)) {
+			t.Errorf("Expected the balance to be left at 1500, got: %s", wallet.Balance)
+		}
+	})
+
+	t.Run("should refuse a debit from a wallet with a broken pair only", func(t *testing.T) {
+		walletUC := setup(500)
+
+		_, _, err := walletUC.WithdrawFunds(ctx, 60, decimal.NewFromFloat(100), "WD-PAIR", "Broken pair", nil, nil)
+		if !errors.Is(err, apperrors.ErrBalanceMismatch) {
+			t.Fatalf("Expected ErrBalanceMismatch, got: %v", err)
+		}
+	})
+}
+
+func TestWalletUseCase_SeveralWalletsOfAUser(t *testing.T) {
+	ctx := context.Background()
+	repos, err := memory.Open(ctx, config.AppConfig{})
+	if err != nil {
+		t.Fatalf("Failed to open repositories: %v", err)
+	}
+	user := &models.User{Email: "several@example.com", Name: "Several Wallets"}
+	repos.User.Create(ctx, user)
+	walletUC := NewWalletUseCase(repos, NewReconciliationUseCase(repos))
+
+	primary, err := walletUC.CreateWallet(ctx, user.ID, "usd", "")
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	rent, err := walletUC.CreateWallet(ctx, user.ID, "USD", " Rent ")
+	if err != nil {
+		t.Fatalf("Expected a second USD wallet with a nickname, got: %v", err)
+	}
+	euros, err := walletUC.CreateWallet(ctx, user.ID, "EUR", "")
+	if err != nil {
+		t.Fatalf("Expected a wallet in another currency, got: %v", err)
+	}
+	if primary.Currency != "USD" || !primary.IsDefault || rent.IsDefault || euros.IsDefault || rent.Nickname != "Rent" {
+		t.Errorf("Expected only the first wallet to be the default, got: %+v, %+v, %+v", primary, rent, euros)
+	}
+
+	tests := []struct {
+		name     string
+		currency string
+		nickname string
+		want     error
+	}{
+		{"same currency and nickname", "USD", "rent", apperrors.ErrWalletExists},
+		{"same currency without nickname", "USD", "", apperrors.ErrWalletExists},
+		{"invalid currency", "US", "", apperrors.ErrValidation},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := walletUC.CreateWallet(ctx, user.ID, tt.currency, tt.nickname); !errors.Is(err, tt.want) {
+				t.Errorf("Expected %v, got: %v", tt.want, err)
+			}
+		})
+	}
+
+	// Money moves between wallets of the same user like between any two wallets
+	if _, _, err := walletUC.FundWallet(ctx, primary.ID, decimal.NewFromInt(100), "SEVERAL-FUND", "Funding"); err != nil {
+		t.Fatalf("Unexpected error funding: %v", err)
+	}
+	if _, _, err := walletUC.TransferFunds(ctx, primary.ID, rent.ID, decimal.NewFromInt(30), "SEVERAL-TRF", "Set aside", nil); err != nil {
+		t.Fatalf("Unexpected error transferring: %v", err)
+	}
+	for walletID, balance := range map[uint]int64{primary.ID: 70, rent.ID: 30} {
+		wallet, _ := walletUC.GetWallet(ctx, walletID)
+		if !wallet.Balance.Equal(decimal.NewFromInt(balance)) {
+			t.Errorf("Expected wallet %d to hold %d, got: %s", walletID, balance, wallet.Balance)
+		}
+	}
+
+	if _, err := walletUC.SetDefaultWallet(ctx, user.ID, rent.ID); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if wallet, err := walletUC.GetWalletByUserID(ctx, user.ID); err != nil || wallet.ID != rent.ID {
+		t.Errorf("Expected the rent wallet to become the default, got %+v, %v", wallet, err)
+	}
+	if wallets, err := walletUC.ListUserWallets(ctx, user.ID, false, ""); err != nil || len(wallets) != 3 {
+		t.Errorf("Expected the user's three wallets, got %d, %v", len(wallets), err)
+	}
+}
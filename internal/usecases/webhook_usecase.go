@@ -0,0 +1,431 @@
+package usecases
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/limistah/wallet-service/internal/apperrors"
+	"github.com/limistah/wallet-service/internal/events"
+	"github.com/limistah/wallet-service/internal/models"
+	"github.com/limistah/wallet-service/internal/queue"
+	"github.com/limistah/wallet-service/internal/repositories"
+	"github.com/limistah/wallet-service/internal/utils"
+	"github.com/limistah/wallet-service/internal/webhooks"
+)
+
+const (
+	webhookSecretLength = 32
+	// maxReplayEvents bounds the events one replay queues; larger replays are split into shorter ranges
+	maxReplayEvents = 1000
+)
+
+// EventReplay selects the stored events to deliver again: those of a wallet, those in a time range, or
+// those of a wallet in a time range. A zero To is open-ended
+type EventReplay struct {
+	WalletID uint
+	From     time.Time
+	To       time.Time
+}
+
+// WebhookRetryPolicy controls how failed webhook deliveries are retried before they are dead
+type WebhookRetryPolicy struct {
+	// MaxAttempts is how many times a delivery is sent before it moves to the dead letter queue
+	MaxAttempts int
+	// InitialBackoff is the wait before the first retry; each further retry doubles it up to MaxBackoff
+	InitialBackoff time.Duration
+	MaxBackoff     time.Duration
+}
+
+// DefaultWebhookRetryPolicy sends a delivery 8 times, retrying from a minute up to every 6 hours
+func DefaultWebhookRetryPolicy() WebhookRetryPolicy {
+	return WebhookRetryPolicy{
+		MaxAttempts:    8,
+		InitialBackoff: time.Minute,
+		MaxBackoff:     6 * time.Hour,
+	}
+}
+
+// Backoff returns the wait before the retry that follows the given attempt, starting at 1
+func (p WebhookRetryPolicy) Backoff(attempt int) time.Duration {
+	return utils.Backoff(p.InitialBackoff, p.MaxBackoff, attempt)
+}
+
+// queueRetry is the policy as the job queue applies it to delivery jobs
+func (p WebhookRetryPolicy) queueRetry() queue.Retry {
+	return queue.Retry{
+		MaxAttempts:    p.MaxAttempts,
+		InitialBackoff: p.InitialBackoff,
+		MaxBackoff:     p.MaxBackoff,
+	}
+}
+
+type webhookUseCase struct {
+	repos  *repositories.Repositories
+	sender webhooks.Sender
+	retry  WebhookRetryPolicy
+	jobs   queue.Enqueuer
+}
+
+// NewWebhookUseCase creates a new webhook use case
+func NewWebhookUseCase(repos *repositories.Repositories, opts ...Option) WebhookUseCase {
+	o := newOptions(opts)
+	return &webhookUseCase{
+		repos:  repos,
+		sender: o.webhookSender,
+		retry:  o.webhookRetry,
+		jobs:   o.jobs,
+	}
+}
+
+// CreateSubscription registers an endpoint receiving the events of the given types, or every event
+// when none is given. The secret signing the deliveries is only returned here
+func (uc *webhookUseCase) CreateSubscription(ctx context.Context, userID uint, endpoint string, eventTypes []string) (*models.WebhookSubscription, error) {
+	if err := webhooks.ValidateEndpoint(endpoint); err != nil {
+		return nil, apperrors.ErrValidation.Withf("%w", err)
+	}
+
+	types := make([]string, 0, len(eventTypes))
+	seen := make(map[string]bool, len(eventTypes))
+	for _, eventType := range eventTypes {
+		eventType = strings.TrimSpace(eventType)
+		if !events.IsKnownEventType(events.EventType(eventType)) {
+			return nil, apperrors.ErrValidation.Withf("unknown event type %q", eventType)
+		}
+		if !seen[eventType] {
+			seen[eventType] = true
+			types = append(types, eventType)
+		}
+	}
+
+	subscription := &models.WebhookSubscription{
+		UserID:     userID,
+		URL:        endpoint,
+		Secret:     "whsec_" + utils.GenerateToken(webhookSecretLength),
+		EventTypes: strings.Join(types, ","),
+		Active:     true,
+	}
+	if err := uc.repos.Webhook.CreateSubscription(ctx, subscription); err != nil {
+		return nil, fmt.Errorf("failed to create webhook subscription: %w", err)
+	}
+	return subscription, nil
+}
+
+func (uc *webhookUseCase) ListSubscriptions(ctx context.Context, userID uint) ([]models.WebhookSubscription, error) {
+	return uc.repos.Webhook.ListSubscriptionsByUser(ctx, userID)
+}
+
+// DisableSubscription stops deliveries to an endpoint; deliveries still pending move to the dead letter
+// queue when they come due
+func (uc *webhookUseCase) DisableSubscription(ctx context.Context, userID, subscriptionID uint) (*models.WebhookSubscription, error) {
+	subscription, err := uc.ownedSubscription(ctx, userID, subscriptionID)
+	if err != nil {
+		return nil, err
+	}
+	if !subscription.Active {
+		return subscription, nil
+	}
+
+	if err := uc.repos.Webhook.DisableSubscription(ctx, subscription.ID); err != nil {
+		return nil, fmt.Errorf("failed to disable webhook subscription: %w", err)
+	}
+	subscription.Active = false
+	return subscription, nil
+}
+
+func (uc *webhookUseCase) ListDeliveries(ctx context.Context, userID, subscriptionID uint, page, pageSize int) ([]models.WebhookDelivery, error) {
+	if _, err := uc.ownedSubscription(ctx, userID, subscriptionID); err != nil {
+		return nil, err
+	}
+	if page < 1 {
+		page = 1
+	}
+	if pageSize < 1 || pageSize > 100 {
+		pageSize = 20
+	}
+	return uc.repos.Webhook.ListDeliveries(ctx, subscriptionID, (page-1)*pageSize, pageSize)
+}
+
+// GetSubscriptionDelivery returns a delivery to an endpoint of the user with the history of its attempts
+func (uc *webhookUseCase) GetSubscriptionDelivery(ctx context.Context, userID, subscriptionID, deliveryID uint) (*models.WebhookDelivery, []models.WebhookDeliveryAttempt, error) {
+	delivery, err := uc.repos.Webhook.GetDelivery(ctx, deliveryID)
+	if err != nil || delivery.SubscriptionID != subscriptionID || delivery.Subscription.UserID != userID {
+		return nil, nil, apperrors.ErrWebhookDeliveryNotFound
+	}
+	return uc.withAttempts(ctx, delivery)
+}
+
+// ListDeadDeliveries returns the dead letter queue of webhooks with redacted payloads
+func (uc *webhookUseCase) ListDeadDeliveries(ctx context.Context, page, pageSize int) ([]models.WebhookDelivery, error) {
+	return uc.SearchDeliveries(ctx, repositories.WebhookDeliveryFilter{Status: models.WebhookDeliveryDead}, page, pageSize)
+}
+
+// SearchDeliveries returns the deliveries of every endpoint matching the filter for operators; payloads
+// are redacted snapshots of what was sent
+func (uc *webhookUseCase) SearchDeliveries(ctx context.Context, filter repositories.WebhookDeliveryFilter, page, pageSize int) ([]models.WebhookDelivery, error) {
+	if page < 1 {
+		page = 1
+	}
+	if pageSize < 1 || pageSize > 100 {
+		pageSize = 20
+	}
+	filter.EventType = strings.TrimSpace(filter.EventType)
+	deliveries, err := uc.repos.Webhook.SearchDeliveries(ctx, filter, (page-1)*pageSize, pageSize)
+	if err != nil {
+		return nil, err
+	}
+	for i := range deliveries {
+		deliveries[i].Payload = webhooks.Redact(deliveries[i].Payload)
+	}
+	return deliveries, nil
+}
+
+// GetDelivery returns any delivery with the history of its attempts
+func (uc *webhookUseCase) GetDelivery(ctx context.Context, deliveryID uint) (*models.WebhookDelivery, []models.WebhookDeliveryAttempt, error) {
+	delivery, err := uc.repos.Webhook.GetDelivery(ctx, deliveryID)
+	if err != nil {
+		return nil, nil, apperrors.ErrWebhookDeliveryNotFound
+	}
+	return uc.withAttempts(ctx, delivery)
+}
+
+// Redeliver takes a delivery out of the dead letter queue and queues it again with a fresh set of
+// attempts
+func (uc *webhookUseCase) Redeliver(ctx context.Context, deliveryID uint) (*models.WebhookDelivery, error) {
+	delivery, err := uc.repos.Webhook.GetDelivery(ctx, deliveryID)
+	if err != nil {
+		return nil, apperrors.ErrWebhookDeliveryNotFound
+	}
+	if !delivery.IsDead() {
+		return nil, apperrors.ErrWebhookDeliveryNotDead
+	}
+	if !delivery.Subscription.Active {
+		return nil, apperrors.ErrWebhookSubscriptionDisabled
+	}
+
+	requeued, err := uc.repos.Webhook.Requeue(ctx, delivery.ID, time.Now())
+	if err != nil {
+		return nil, fmt.Errorf("failed to redeliver webhook: %w", err)
+	}
+	if !requeued {
+		return nil, apperrors.ErrWebhookDeliveryNotDead
+	}
+	if err := uc.jobs.Enqueue(JobDeliverWebhook, deliverWebhookJob{DeliveryID: delivery.ID}); err != nil {
+		return nil, fmt.Errorf("failed to redeliver webhook: %w", err)
+	}
+
+	return uc.repos.Webhook.GetDelivery(ctx, delivery.ID)
+}
+
+// EnqueueEvent stores the event and records a delivery of it to every active endpoint of its user that
+// wants it; it is an events.Bus handler
+func (uc *webhookUseCase) EnqueueEvent(ctx context.Context, event events.Event) {
+	payload, err := json.Marshal(event)
+	if err != nil {
+		log.Printf("Webhooks: failed to encode %s event: %v", event.Type, err)
+		return
+	}
+
+	stored := &models.DomainEvent{
+		Type:       string(event.Type),
+		UserID:     event.UserID,
+		WalletID:   event.WalletID,
+		OccurredAt: event.OccurredAt,
+		Payload:    string(payload),
+	}
+	if err := uc.repos.DomainEvent.Create(ctx, stored); err != nil {
+		log.Printf("Webhooks: failed to store %s event: %v", event.Type, err)
+		return
+	}
+	if event.UserID == 0 {
+		return
+	}
+
+	subscriptions, err := uc.repos.Webhook.ListActiveSubscriptionsByUser(ctx, event.UserID)
+	if err != nil {
+		log.Printf("Webhooks: failed to load subscriptions of user %d: %v", event.UserID, err)
+		return
+	}
+	for i := range subscriptions {
+		if !subscriptions[i].Wants(stored.Type) {
+			continue
+		}
+		if err := uc.queueDelivery(ctx, &subscriptions[i], stored, false); err != nil {
+			log.Printf("Webhooks: failed to queue %s event for subscription %d: %v", event.Type, subscriptions[i].ID, err)
+		}
+	}
+}
+
+// ReplayEvents delivers again to a subscription the stored events of its user for a wallet or a time
+// range, so a consumer can recover after an outage; it returns how many events were queued
+func (uc *webhookUseCase) ReplayEvents(ctx context.Context, subscriptionID uint, replay EventReplay) (int, error) {
+	subscription, err := uc.repos.Webhook.GetSubscription(ctx, subscriptionID)
+	if err != nil {
+		return 0, apperrors.ErrWebhookSubscriptionNotFound
+	}
+	return uc.replay(ctx, subscription, replay)
+}
+
+// ReplaySubscriptionEvents is ReplayEvents for a subscription of the user
+func (uc *webhookUseCase) ReplaySubscriptionEvents(ctx context.Context, userID, subscriptionID uint, replay EventReplay) (int, error) {
+	subscription, err := uc.ownedSubscription(ctx, userID, subscriptionID)
+	if err != nil {
+		return 0, err
+	}
+	return uc.replay(ctx, subscription, replay)
+}
+
+func (uc *webhookUseCase) replay(ctx context.Context, subscription *models.WebhookSubscription, replay EventReplay) (int, error) {
+	if !subscription.Active {
+		return 0, apperrors.ErrWebhookSubscriptionDisabled
+	}
+	if replay.WalletID == 0 && replay.From.IsZero() {
+		return 0, apperrors.ErrValidation.Withf("choose a wallet or the start of a time range to replay")
+	}
+	if !replay.To.IsZero() && !replay.To.After(replay.From) {
+		return 0, apperrors.ErrValidation.Withf("the end of the time range must be after its start")
+	}
+
+	stored, err := uc.repos.DomainEvent.List(ctx, repositories.DomainEventFilter{
+		UserID:   subscription.UserID,
+		WalletID: replay.WalletID,
+		From:     replay.From,
+		To:       replay.To,
+	}, maxReplayEvents+1)
+	if err != nil {
+		return 0, fmt.Errorf("failed to load events to replay: %w", err)
+	}
+	if len(stored) > maxReplayEvents {
+		return 0, apperrors.ErrValidation.Withf("more than %d events match; replay a shorter time range", maxReplayEvents)
+	}
+
+	queued := 0
+	for i := range stored {
+		if !subscription.Wants(stored[i].Type) {
+			continue
+		}
+		if err := uc.queueDelivery(ctx, subscription, &stored[i], true); err != nil {
+			return queued, fmt.Errorf("failed to queue replayed event: %w", err)
+		}
+		queued++
+	}
+	return queued, nil
+}
+
+// queueDelivery records a delivery of a stored event to a subscription and queues its first attempt.
+// A delivery that cannot be queued is moved to the dead letter queue so it can be redelivered
+func (uc *webhookUseCase) queueDelivery(ctx context.Context, subscription *models.WebhookSubscription, event *models.DomainEvent, replay bool) error {
+	now := time.Now()
+	delivery := &models.WebhookDelivery{
+		TenantID:       subscription.TenantID,
+		SubscriptionID: subscription.ID,
+		EventID:        event.ID,
+		EventType:      event.Type,
+		Replay:         replay,
+		Payload:        event.Payload,
+		Status:         models.WebhookDeliveryPending,
+		NextAttemptAt:  now,
+	}
+	if err := uc.repos.Webhook.CreateDelivery(ctx, delivery); err != nil {
+		return err
+	}
+
+	if err := uc.jobs.Enqueue(JobDeliverWebhook, deliverWebhookJob{DeliveryID: delivery.ID}); err != nil {
+		delivery.Status = models.WebhookDeliveryDead
+		delivery.DeadAt = &now
+		delivery.LastError = err.Error()
+		if updateErr := uc.repos.Webhook.UpdateDelivery(ctx, delivery); updateErr != nil {
+			log.Printf("Webhooks: failed to move unqueued delivery %d to the dead letter queue: %v", delivery.ID, updateErr)
+		}
+		return err
+	}
+	return nil
+}
+
+// DeliverWebhook sends a pending delivery once and records the attempt; it runs as a job, so a failed
+// attempt is returned for the queue to retry with the backoff of the retry policy, until the last
+// attempt moves the delivery to the dead letter queue
+func (uc *webhookUseCase) DeliverWebhook(ctx context.Context, deliveryID uint) error {
+	delivery, err := uc.repos.Webhook.GetDelivery(ctx, deliveryID)
+	if err != nil {
+		return fmt.Errorf("failed to load webhook delivery %d: %w", deliveryID, err)
+	}
+	if delivery.Status != models.WebhookDeliveryPending {
+		return nil
+	}
+
+	now := time.Now()
+	if !delivery.Subscription.Active {
+		delivery.Status = models.WebhookDeliveryDead
+		delivery.DeadAt = &now
+		delivery.LastError = apperrors.ErrWebhookSubscriptionDisabled.Error()
+		return uc.repos.Webhook.UpdateDelivery(ctx, delivery)
+	}
+
+	delivery.Attempts++
+	statusCode, sendErr := uc.sender.Send(webhooks.Request{
+		URL:        delivery.Subscription.URL,
+		Secret:     delivery.Subscription.Secret,
+		EventType:  delivery.EventType,
+		DeliveryID: delivery.ID,
+		Payload:    []byte(delivery.Payload),
+	})
+	attempt := &models.WebhookDeliveryAttempt{
+		DeliveryID: delivery.ID,
+		Attempt:    delivery.Attempts,
+		StatusCode: statusCode,
+		DurationMS: time.Since(now).Milliseconds(),
+	}
+	if sendErr != nil {
+		attempt.Error = sendErr.Error()
+	}
+	if err := uc.repos.Webhook.CreateAttempt(ctx, attempt); err != nil {
+		log.Printf("Webhooks: failed to record attempt %d of delivery %d: %v", attempt.Attempt, delivery.ID, err)
+	}
+
+	delivery.LastStatusCode = statusCode
+	delivery.LastDurationMS = attempt.DurationMS
+	switch {
+	case sendErr == nil:
+		delivery.Status = models.WebhookDeliveryDelivered
+		delivery.DeliveredAt = &now
+		delivery.LastError = ""
+	case delivery.Attempts >= uc.retry.MaxAttempts:
+		delivery.Status = models.WebhookDeliveryDead
+		delivery.DeadAt = &now
+		delivery.LastError = sendErr.Error()
+		sendErr = queue.Permanent(sendErr)
+	default:
+		delivery.NextAttemptAt = now.Add(uc.retry.Backoff(delivery.Attempts))
+		delivery.LastError = sendErr.Error()
+	}
+	if err := uc.repos.Webhook.UpdateDelivery(ctx, delivery); err != nil {
+		return fmt.Errorf("failed to record webhook delivery %d: %w", delivery.ID, err)
+	}
+	return sendErr
+}
+
+// RetryPolicy returns how failed deliveries are retried, for the job queue running them
+func (uc *webhookUseCase) RetryPolicy(ctx context.Context) WebhookRetryPolicy {
+	return uc.retry
+}
+
+func (uc *webhookUseCase) withAttempts(ctx context.Context, delivery *models.WebhookDelivery) (*models.WebhookDelivery, []models.WebhookDeliveryAttempt, error) {
+	attempts, err := uc.repos.Webhook.ListAttempts(ctx, delivery.ID)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to load webhook delivery attempts: %w", err)
+	}
+	return delivery, attempts, nil
+}
+
+// ownedSubscription loads a subscription of the user; other users' subscriptions are not found
+func (uc *webhookUseCase) ownedSubscription(ctx context.Context, userID, subscriptionID uint) (*models.WebhookSubscription, error) {
+	subscription, err := uc.repos.Webhook.GetSubscription(ctx, subscriptionID)
+	if err != nil || subscription.UserID != userID {
+		return nil, apperrors.ErrWebhookSubscriptionNotFound
+	}
+	return subscription, nil
+}
@@ -0,0 +1,276 @@
+package usecases
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/limistah/wallet-service/internal/apperrors"
+	"github.com/limistah/wallet-service/internal/events"
+	"github.com/limistah/wallet-service/internal/models"
+	"github.com/limistah/wallet-service/internal/repositories"
+	"github.com/limistah/wallet-service/internal/repositories/memory"
+	"github.com/limistah/wallet-service/internal/webhooks"
+	"github.com/shopspring/decimal"
+)
+
+// scriptedWebhookSender answers each request with the next status code of its script
+type scriptedWebhookSender struct {
+	statuses []int
+	requests []webhooks.Request
+}
+
+func (s *scriptedWebhookSender) Send(req webhooks.Request) (int, error) {
+	s.requests = append(s.requests, req)
+	status := s.statuses[0]
+	if len(s.statuses) > 1 {
+		s.statuses = s.statuses[1:]
+	}
+	if status < 200 || status >= 300 {
+		return status, fmt.Errorf("webhook endpoint returned status %d", status)
+	}
+	return status, nil
+}
+
+func TestWebhookUseCase_RetriesAndDeadLetters(t *testing.T) {
+	repos, _ := setupTestEnvironment()
+	webhookRepo := memory.NewWebhookRepository()
+	repos.Webhook = webhookRepo
+	repos.DomainEvent = memory.NewDomainEventRepository()
+	sender := &scriptedWebhookSender{statuses: []int{http.StatusInternalServerError}}
+	jobs := &recordingEnqueuer{}
+	webhookUC := NewWebhookUseCase(repos, WithWebhookSender(sender), WithJobQueue(jobs), WithWebhookRetry(WebhookRetryPolicy{
+		MaxAttempts:    3,
+		InitialBackoff: time.Minute,
+		MaxBackoff:     90 * time.Second,
+	}))
+
+	if _, err := webhookUC.CreateSubscription(context.Background(), 2, "ftp://example.com/hooks", nil); !errors.Is(err, apperrors.ErrValidation) {
+		t.Errorf("Expected non-HTTP URLs to be rejected, got %v", err)
+	}
+	if _, err := webhookUC.CreateSubscription(context.Background(), 2, "http://169.254.169.254/latest/meta-data", nil); !errors.Is(err, apperrors.ErrValidation) {
+		t.Errorf("Expected internal endpoints to be refused, got: %v", err)
+	}
+	if _, err := webhookUC.CreateSubscription(context.Background(), 2, "https://example.com/hooks", []string{"wallet.unknown"}); !errors.Is(err, apperrors.ErrValidation) {
+		t.Errorf("Expected unknown event types to be rejected, got %v", err)
+	}
+	subscription, err := webhookUC.CreateSubscription(context.Background(), 2, "https://example.com/hooks", []string{string(events.EventCreditReceived)})
+	if err != nil {
+		t.Fatalf("Unexpected error creating subscription: %v", err)
+	}
+	if subscription.Secret == "" {
+		t.Fatal("Expected the subscription to get a signing secret")
+	}
+
+	webhookUC.EnqueueEvent(context.Background(), events.Event{Type: events.EventTransferSent, UserID: 2, WalletID: 2})
+	webhookUC.EnqueueEvent(context.Background(), events.Event{Type: events.EventCreditReceived, UserID: 3, WalletID: 3})
+	webhookUC.EnqueueEvent(context.Background(), events.Event{Type: events.EventCreditReceived, UserID: 2, WalletID: 2, Amount: decimal.NewFromInt(40), Currency: "USD"})
+	if webhookRepo.DeliveryCount() != 1 {
+		t.Fatalf("Expected only the subscribed event of the user to be queued, got %d deliveries", webhookRepo.DeliveryCount())
+	}
+	if len(jobs.jobTypes) != 1 || jobs.jobTypes[0] != JobDeliverWebhook || jobs.payloads[0] != (deliverWebhookJob{DeliveryID: 1}) {
+		t.Fatalf("Expected a delivery job for the delivery, got %v %v", jobs.jobTypes, jobs.payloads)
+	}
+
+	// Every attempt fails and is handed back to the queue: retried after 1 minute, then after the 90
+	// second cap, then dead
+	for i, wait := range []time.Duration{time.Minute, 90 * time.Second} {
+		before := time.Now()
+		if err := webhookUC.DeliverWebhook(context.Background(), 1); err == nil {
+			t.Fatalf("Expected attempt %d to fail for the queue to retry it", i+1)
+		}
+		if d := webhookRepo.Delivery(1); d.Status != models.WebhookDeliveryPending || d.NextAttemptAt.Before(before.Add(wait)) {
+			t.Fatalf("Expected attempt %d to be retried after %s, got %+v", i+1, wait, d)
+		}
+	}
+	if err := webhookUC.DeliverWebhook(context.Background(), 1); err == nil {
+		t.Fatal("Expected the last attempt to fail")
+	}
+	if retry := webhookUC.RetryPolicy(context.Background()).queueRetry(); retry.MaxAttempts != 3 || retry.Backoff(2) != 90*time.Second {
+		t.Errorf("Expected the queue to retry deliveries with the webhook policy, got %+v", retry)
+	}
+
+	delivery, attempts, err := webhookUC.GetSubscriptionDelivery(context.Background(), 2, subscription.ID, 1)
+	if err != nil {
+		t.Fatalf("Unexpected error getting delivery: %v", err)
+	}
+	if !delivery.IsDead() || delivery.Attempts != 3 || delivery.LastStatusCode != http.StatusInternalServerError {
+		t.Fatalf("Expected the delivery to be dead after 3 attempts, got %+v", delivery)
+	}
+	if len(attempts) != 3 || attempts[2].Attempt != 3 || attempts[2].StatusCode != http.StatusInternalServerError {
+		t.Errorf("Expected the history of 3 attempts, got %+v", attempts)
+	}
+	if req := sender.requests[0]; req.Secret != subscription.Secret || req.EventType != string(events.EventCreditReceived) {
+		t.Errorf("Expected the request to be signed for the subscription, got %+v", req)
+	}
+	if _, _, err := webhookUC.GetSubscriptionDelivery(context.Background(), 3, subscription.ID, 1); !errors.Is(err, apperrors.ErrWebhookDeliveryNotFound) {
+		t.Errorf("Expected other users not to see the delivery, got %v", err)
+	}
+	if dead, _ := webhookUC.ListDeadDeliveries(context.Background(), 1, 20); len(dead) != 1 {
+		t.Errorf("Expected the delivery in the dead letter queue, got %d", len(dead))
+	}
+
+	// An admin redelivers it and the endpoint is back
+	sender.statuses = []int{http.StatusOK}
+	redelivered, err := webhookUC.Redeliver(context.Background(), 1)
+	if err != nil {
+		t.Fatalf("Unexpected error redelivering: %v", err)
+	}
+	if redelivered.Status != models.WebhookDeliveryPending || redelivered.Attempts != 0 {
+		t.Fatalf("Expected the delivery to be pending with fresh attempts, got %+v", redelivered)
+	}
+	if _, err := webhookUC.Redeliver(context.Background(), 1); !errors.Is(err, apperrors.ErrWebhookDeliveryNotDead) {
+		t.Errorf("Expected pending deliveries not to be redelivered, got %v", err)
+	}
+	if len(jobs.jobTypes) != 2 {
+		t.Fatalf("Expected the redelivery to be queued, got %d jobs", len(jobs.jobTypes))
+	}
+	if err := webhookUC.DeliverWebhook(context.Background(), 1); err != nil {
+		t.Fatalf("Unexpected error sending the redelivery: %v", err)
+	}
+	delivery, attempts, _ = webhookUC.GetDelivery(context.Background(), 1)
+	if delivery.Status != models.WebhookDeliveryDelivered || delivery.DeliveredAt == nil || len(attempts) != 4 {
+		t.Errorf("Expected the delivery to succeed on its fourth request, got %+v with %d attempts", delivery, len(attempts))
+	}
+}
+
+func TestWebhookUseCase_DisabledSubscription(t *testing.T) {
+	repos, _ := setupTestEnvironment()
+	webhookRepo := memory.NewWebhookRepository()
+	repos.Webhook = webhookRepo
+	repos.DomainEvent = memory.NewDomainEventRepository()
+	sender := &scriptedWebhookSender{statuses: []int{http.StatusOK}}
+	webhookUC := NewWebhookUseCase(repos, WithWebhookSender(sender))
+
+	subscription, err := webhookUC.CreateSubscription(context.Background(), 2, "https://example.com/hooks", nil)
+	if err != nil {
+		t.Fatalf("Unexpected error creating subscription: %v", err)
+	}
+	webhookUC.EnqueueEvent(context.Background(), events.Event{Type: events.EventTransferSent, UserID: 2, WalletID: 2})
+
+	if _, err := webhookUC.DisableSubscription(context.Background(), 3, subscription.ID); !errors.Is(err, apperrors.ErrWebhookSubscriptionNotFound) {
+		t.Errorf("Expected only the owner to disable the subscription, got %v", err)
+	}
+	if _, err := webhookUC.DisableSubscription(context.Background(), 2, subscription.ID); err != nil {
+		t.Fatalf("Unexpected error disabling subscription: %v", err)
+	}
+
+	if err := webhookUC.DeliverWebhook(context.Background(), 1); err != nil || len(sender.requests) != 0 {
+		t.Fatalf("Expected nothing to be sent to a disabled endpoint, got %d requests, %v", len(sender.requests), err)
+	}
+	if !webhookRepo.Delivery(1).IsDead() {
+		t.Errorf("Expected the pending delivery to be dead, got %+v", webhookRepo.Delivery(1))
+	}
+	if _, err := webhookUC.Redeliver(context.Background(), 1); !errors.Is(err, apperrors.ErrWebhookSubscriptionDisabled) {
+		t.Errorf("Expected deliveries of disabled subscriptions not to be redelivered, got %v", err)
+	}
+
+	webhookUC.EnqueueEvent(context.Background(), events.Event{Type: events.EventTransferSent, UserID: 2, WalletID: 2})
+	if webhookRepo.DeliveryCount() != 1 {
+		t.Errorf("Expected no delivery to be queued for a disabled endpoint, got %d", webhookRepo.DeliveryCount())
+	}
+}
+
+func TestWebhookUseCase_ReplayEvents(t *testing.T) {
+	repos, _ := setupTestEnvironment()
+	webhookRepo := memory.NewWebhookRepository()
+	repos.Webhook = webhookRepo
+	eventRepo := memory.NewDomainEventRepository()
+	repos.DomainEvent = eventRepo
+	webhookUC := NewWebhookUseCase(repos, WithWebhookSender(&scriptedWebhookSender{statuses: []int{http.StatusOK}}))
+
+	// Events happen before the endpoint is registered, so none of them is delivered
+	start := time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)
+	webhookUC.EnqueueEvent(context.Background(), events.Event{Type: events.EventCreditReceived, UserID: 2, WalletID: 2, OccurredAt: start})
+	webhookUC.EnqueueEvent(context.Background(), events.Event{Type: events.EventTransferSent, UserID: 2, WalletID: 2, OccurredAt: start.Add(time.Hour)})
+	webhookUC.EnqueueEvent(context.Background(), events.Event{Type: events.EventCreditReceived, UserID: 2, WalletID: 5, OccurredAt: start.Add(2 * time.Hour)})
+	webhookUC.EnqueueEvent(context.Background(), events.Event{Type: events.EventCreditReceived, UserID: 3, WalletID: 3, OccurredAt: start.Add(time.Hour)})
+	if len(eventRepo.Events()) != 4 || webhookRepo.DeliveryCount() != 0 {
+		t.Fatalf("Expected 4 stored events and no delivery, got %d and %d", len(eventRepo.Events()), webhookRepo.DeliveryCount())
+	}
+
+	subscription, err := webhookUC.CreateSubscription(context.Background(), 2, "https://example.com/hooks", []string{string(events.EventCreditReceived)})
+	if err != nil {
+		t.Fatalf("Unexpected error creating subscription: %v", err)
+	}
+
+	if _, err := webhookUC.ReplaySubscriptionEvents(context.Background(), 2, subscription.ID, EventReplay{}); !errors.Is(err, apperrors.ErrValidation) {
+		t.Errorf("Expected a wallet or time range to be required, got %v", err)
+	}
+	if _, err := webhookUC.ReplaySubscriptionEvents(context.Background(), 2, subscription.ID, EventReplay{From: start, To: start}); !errors.Is(err, apperrors.ErrValidation) {
+		t.Errorf("Expected empty time ranges to be rejected, got %v", err)
+	}
+	if _, err := webhookUC.ReplaySubscriptionEvents(context.Background(), 3, subscription.ID, EventReplay{WalletID: 3}); !errors.Is(err, apperrors.ErrWebhookSubscriptionNotFound) {
+		t.Errorf("Expected other users not to replay to the subscription, got %v", err)
+	}
+
+	// Wallet 2 had a credit and a transfer; only the subscribed credit is replayed
+	queued, err := webhookUC.ReplaySubscriptionEvents(context.Background(), 2, subscription.ID, EventReplay{WalletID: 2})
+	if err != nil || queued != 1 {
+		t.Fatalf("Expected 1 event of wallet 2 to be replayed, got %d, %v", queued, err)
+	}
+	if d := webhookRepo.Delivery(1); !d.Replay || d.EventID != 1 || d.Status != models.WebhookDeliveryPending {
+		t.Errorf("Expected a pending replay of the first event, got %+v", d)
+	}
+
+	// The time range covers the credits of both wallets of the user but not the other user's
+	queued, err = webhookUC.ReplayEvents(context.Background(), subscription.ID, EventReplay{From: start.Add(time.Minute), To: start.Add(3 * time.Hour)})
+	if err != nil || queued != 1 {
+		t.Fatalf("Expected 1 event of the time range to be replayed, got %d, %v", queued, err)
+	}
+	if d := webhookRepo.Delivery(2); d.EventID != 3 {
+		t.Errorf("Expected the credit of wallet 5 to be replayed, got event %d", d.EventID)
+	}
+
+	webhookUC.DisableSubscription(context.Background(), 2, subscription.ID)
+	if _, err := webhookUC.ReplayEvents(context.Background(), subscription.ID, EventReplay{WalletID: 2}); !errors.Is(err, apperrors.ErrWebhookSubscriptionDisabled) {
+		t.Errorf("Expected disabled subscriptions not to get replays, got %v", err)
+	}
+}
+
+func TestWebhookUseCase_SearchDeliveries(t *testing.T) {
+	repos, _ := setupTestEnvironment()
+	webhookRepo := memory.NewWebhookRepository()
+	repos.Webhook = webhookRepo
+	repos.DomainEvent = memory.NewDomainEventRepository()
+	sender := &scriptedWebhookSender{statuses: []int{http.StatusOK, http.StatusBadGateway}}
+	webhookUC := NewWebhookUseCase(repos, WithWebhookSender(sender))
+
+	first, _ := webhookUC.CreateSubscription(context.Background(), 2, "https://example.com/hooks", nil)
+	second, _ := webhookUC.CreateSubscription(context.Background(), 2, "https://example.com/other", nil)
+	webhookUC.EnqueueEvent(context.Background(), events.Event{Type: events.EventTransferSent, UserID: 2, WalletID: 2, Reason: "rent for flat 4B"})
+	webhookUC.DeliverWebhook(context.Background(), 1)
+	webhookUC.DeliverWebhook(context.Background(), 2)
+	if len(sender.requests) != 2 {
+		t.Fatalf("Expected both deliveries to be attempted, got %d", len(sender.requests))
+	}
+
+	delivered, err := webhookUC.SearchDeliveries(context.Background(), repositories.WebhookDeliveryFilter{Status: models.WebhookDeliveryDelivered}, 1, 20)
+	if err != nil || len(delivered) != 1 || delivered[0].SubscriptionID != first.ID {
+		t.Fatalf("Expected the delivery to the first endpoint, got %+v, %v", delivered, err)
+	}
+	if delivered[0].LastStatusCode != http.StatusOK {
+		t.Errorf("Expected the response code of the last attempt, got %d", delivered[0].LastStatusCode)
+	}
+	if strings.Contains(delivered[0].Payload, "rent") || !strings.Contains(delivered[0].Payload, webhooks.Redacted) {
+		t.Errorf("Expected the reason to be redacted from the payload, got %s", delivered[0].Payload)
+	}
+	if stored := webhookRepo.Delivery(delivered[0].ID).Payload; !strings.Contains(stored, "rent") {
+		t.Errorf("Expected the stored payload to be left alone, got %s", stored)
+	}
+
+	failing, _ := webhookUC.SearchDeliveries(context.Background(), repositories.WebhookDeliveryFilter{
+		EventType:      string(events.EventTransferSent),
+		SubscriptionID: second.ID,
+	}, 1, 20)
+	if len(failing) != 1 || failing[0].Status != models.WebhookDeliveryPending || failing[0].LastStatusCode != http.StatusBadGateway {
+		t.Errorf("Expected the failing delivery to the second endpoint, got %+v", failing)
+	}
+	if none, _ := webhookUC.SearchDeliveries(context.Background(), repositories.WebhookDeliveryFilter{EventType: string(events.EventCreditReceived)}, 1, 20); len(none) != 0 {
+		t.Errorf("Expected no delivery of other event types, got %d", len(none))
+	}
+}
@@ -155,3 +155,48 @@ func GenerateUniqueID() string {
 	randomPart := generateRandomString(4)
 	return fmt.Sprintf("%d%s", timestamp, randomPart)
 }
+
+// ValidatePhoneNumber validates an E.164 formatted phone number (e.g. +2348012345678)
+func ValidatePhoneNumber(phone string) bool {
+	phoneRegex := regexp.MustCompile(`^\+[1-9][0-9]{7,14}$`)
+	return phoneRegex.MatchString(phone)
+}
+
+// GenerateNumericCode generates a random numeric code of the given length
+func GenerateNumericCode(length int) string {
+	const digits = "0123456789"
+	result := make([]byte, length)
+
+	for i := range result {
+		num, _ := rand.Int(rand.Reader, big.NewInt(int64(len(digits))))
+		result[i] = digits[num.Int64()]
+	}
+
+	return string(result)
+}
+
+// GenerateToken generates a random URL-safe token of the given length
+func GenerateToken(length int) string {
+	const charset = "abcdefghijklmnopqrstuvwxyzABCDEFGHIJKLMNOPQRSTUVWXYZ0123456789"
+	result := make([]byte, length)
+
+	for i := range result {
+		num, _ := rand.Int(rand.Reader, big.NewInt(int64(len(charset))))
+		result[i] = charset[num.Int64()]
+	}
+
+	return string(result)
+}
+
+// Backoff returns the wait before the retry that follows the given attempt, starting at 1: initial
+// doubled for every earlier retry, capped at max
+func Backoff(initial, max time.Duration, attempt int) time.Duration {
+	backoff := initial
+	for i := 1; i < attempt && backoff < max; i++ {
+		backoff *= 2
+	}
+	if backoff > max {
+		backoff = max
+	}
+	return backoff
+}
@@ -0,0 +1,68 @@
+package webhooks
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"net/url"
+	"strings"
+	"syscall"
+)
+
+// ErrPrivateDestination is returned for webhook endpoints on loopback, private or otherwise internal
+// addresses, which subscribers must not be able to reach through the service
+var ErrPrivateDestination = errors.New("webhook endpoint must be on a public address")
+
+// ValidateEndpoint checks that a webhook URL is an absolute http or https URL whose host is not an
+// internal address or name. Names are resolved again when each request is made, see HTTPSender
+func ValidateEndpoint(endpoint string) error {
+	parsed, err := url.Parse(endpoint)
+	if err != nil || (parsed.Scheme != "https" && parsed.Scheme != "http") || parsed.Host == "" {
+		return errors.New("webhook URL must be an absolute http or https URL")
+	}
+	if parsed.User != nil {
+		return errors.New("webhook URL must not carry credentials")
+	}
+
+	host := strings.ToLower(strings.TrimSuffix(parsed.Hostname(), "."))
+	if host == "localhost" || strings.HasSuffix(host, ".localhost") || strings.HasSuffix(host, ".internal") {
+		return ErrPrivateDestination
+	}
+	if ip := net.ParseIP(host); ip != nil && !IsPublicIP(ip) {
+		return ErrPrivateDestination
+	}
+	return nil
+}
+
+// IsPublicIP reports whether an address is reachable on the internet, as opposed to loopback, private,
+// link-local (including cloud metadata endpoints), multicast or unspecified addresses
+func IsPublicIP(ip net.IP) bool {
+	if ip4 := ip.To4(); ip4 != nil {
+		ip = ip4
+		// 100.64.0.0/10 is shared address space of carrier-grade NAT
+		if ip[0] == 100 && ip[1]&0xc0 == 64 {
+			return false
+		}
+		// 0.0.0.0/8 only addresses this host
+		if ip[0] == 0 {
+			return false
+		}
+	}
+	return !(ip.IsLoopback() || ip.IsPrivate() || ip.IsLinkLocalUnicast() || ip.IsLinkLocalMulticast() ||
+		ip.IsInterfaceLocalMulticast() || ip.IsMulticast() || ip.IsUnspecified())
+}
+
+// refusePrivateDestinations is a net.Dialer control function refusing connections to internal
+// addresses. It runs on the address a name resolved to, so names that resolve to internal addresses,
+// or are made to later, are refused as well
+func refusePrivateDestinations(network, address string, _ syscall.RawConn) error {
+	host, _, err := net.SplitHostPort(address)
+	if err != nil {
+		return fmt.Errorf("invalid webhook destination %q: %w", address, err)
+	}
+	ip := net.ParseIP(host)
+	if ip == nil || !IsPublicIP(ip) {
+		return ErrPrivateDestination
+	}
+	return nil
+}
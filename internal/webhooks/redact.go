@@ -0,0 +1,53 @@
+package webhooks
+
+import "encoding/json"
+
+// Redacted replaces the values hidden from payload snapshots
+const Redacted = "[REDACTED]"
+
+// redactedFields are free-form event fields that may carry personal or account details
+var redactedFields = map[string]bool{
+	"reason": true,
+	"data":   true,
+}
+
+// Redact returns a snapshot of a delivery payload that is safe to show to operators: the values of
+// free-form fields are hidden, nested ones included, and payloads that are not JSON objects are hidden
+// whole. Amounts, identifiers and event types stay so deliveries can still be traced
+func Redact(payload string) string {
+	var fields map[string]interface{}
+	if err := json.Unmarshal([]byte(payload), &fields); err != nil {
+		return Redacted
+	}
+	for key, value := range fields {
+		if redactedFields[key] {
+			fields[key] = redactValue(value)
+		}
+	}
+	redacted, err := json.Marshal(fields)
+	if err != nil {
+		return Redacted
+	}
+	return string(redacted)
+}
+
+// redactValue hides every scalar of a value while keeping the keys of objects, so operators can tell
+// what a payload carried without seeing it
+func redactValue(value interface{}) interface{} {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		for key := range v {
+			v[key] = redactValue(v[key])
+		}
+		return v
+	case []interface{}:
+		for i := range v {
+			v[i] = redactValue(v[i])
+		}
+		return v
+	case nil:
+		return nil
+	default:
+		return Redacted
+	}
+}
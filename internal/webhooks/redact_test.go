@@ -0,0 +1,17 @@
+package webhooks
+
+import "testing"
+
+func TestRedact(t *testing.T) {
+	payload := `{"type":"wallet.transfer_sent","wallet_id":2,"amount":"40","reason":"rent for flat 4B","data":{"recipient_email":"jane@example.com","tags":["a","b"]}}`
+
+	got := Redact(payload)
+	want := `{"amount":"40","data":{"recipient_email":"[REDACTED]","tags":["[REDACTED]","[REDACTED]"]},"reason":"[REDACTED]","type":"wallet.transfer_sent","wallet_id":2}`
+	if got != want {
+		t.Errorf("Redact() = %s, want %s", got, want)
+	}
+
+	if got := Redact("not json"); got != Redacted {
+		t.Errorf("Expected payloads that are not JSON objects to be hidden whole, got %s", got)
+	}
+}
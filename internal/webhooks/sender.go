@@ -0,0 +1,105 @@
+package webhooks
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"strconv"
+	"syscall"
+	"time"
+)
+
+// Headers sent with every delivery. The signature is the hex encoded HMAC-SHA256 of the body with the
+// secret of the subscription
+const (
+	SignatureHeader = "X-Wallet-Signature"
+	EventHeader     = "X-Wallet-Event"
+	DeliveryHeader  = "X-Wallet-Delivery"
+)
+
+// Request is one attempt to deliver an event to a webhook endpoint
+type Request struct {
+	URL        string
+	Secret     string
+	EventType  string
+	DeliveryID uint
+	Payload    []byte
+}
+
+// Sender posts events to webhook endpoints
+type Sender interface {
+	// Send posts the request and returns the status code the endpoint answered with, zero when no
+	// response was received; statuses other than 2xx are returned as errors as well
+	Send(req Request) (int, error)
+}
+
+// HTTPSender posts signed JSON requests. It only connects to public addresses and does not follow
+// redirects, so subscribers cannot point it at services inside the network
+type HTTPSender struct {
+	client *http.Client
+}
+
+// NewHTTPSender creates a new sender giving up on endpoints that do not answer within timeout
+func NewHTTPSender(timeout time.Duration) *HTTPSender {
+	return newHTTPSender(timeout, refusePrivateDestinations)
+}
+
+// newHTTPSender creates a sender whose connections are vetted by control; tests pass nil to reach
+// local servers
+func newHTTPSender(timeout time.Duration, control func(network, address string, conn syscall.RawConn) error) *HTTPSender {
+	dialer := &net.Dialer{Timeout: timeout, Control: control}
+	transport := &http.Transport{
+		Proxy:               nil,
+		DialContext:         dialer.DialContext,
+		TLSHandshakeTimeout: timeout,
+		MaxIdleConnsPerHost: 2,
+	}
+	return &HTTPSender{
+		client: &http.Client{
+			Timeout:   timeout,
+			Transport: transport,
+			// A redirect is answered as a failed delivery rather than followed to wherever it points
+			CheckRedirect: func(*http.Request, []*http.Request) error {
+				return http.ErrUseLastResponse
+			},
+		},
+	}
+}
+
+// Send implements Sender
+func (s *HTTPSender) Send(req Request) (int, error) {
+	httpReq, err := http.NewRequest(http.MethodPost, req.URL, bytes.NewReader(req.Payload))
+	if err != nil {
+		return 0, fmt.Errorf("failed to build webhook request: %w", err)
+	}
+	httpReq.Header.Set("Content-Type", "application/json")
+	httpReq.Header.Set(SignatureHeader, Sign(req.Secret, req.Payload))
+	httpReq.Header.Set(EventHeader, req.EventType)
+	httpReq.Header.Set(DeliveryHeader, strconv.FormatUint(uint64(req.DeliveryID), 10))
+
+	resp, err := s.client.Do(httpReq)
+	if err != nil {
+		return 0, fmt.Errorf("webhook request failed: %w", err)
+	}
+	defer resp.Body.Close()
+	// The body is drained so the connection can be reused, but never kept: it is whatever the endpoint
+	// chose to answer and would be shown to the subscriber in the delivery history
+	io.Copy(io.Discard, io.LimitReader(resp.Body, 64*1024))
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return resp.StatusCode, fmt.Errorf("webhook endpoint returned status %d", resp.StatusCode)
+	}
+	return resp.StatusCode, nil
+}
+
+// Sign returns the signature of a payload sent with the given secret
+func Sign(secret string, payload []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(payload)
+	return hex.EncodeToString(mac.Sum(nil))
+}
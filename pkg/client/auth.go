@@ -0,0 +1,86 @@
+package client
+
+import (
+	"context"
+	"net/http"
+	"time"
+)
+
+// User is an account of the service
+type User struct {
+	ID            uint      `json:"id"`
+	CreatedAt     time.Time `json:"created_at"`
+	UpdatedAt     time.Time `json:"updated_at"`
+	Name          string    `json:"name"`
+	Email         string    `json:"email"`
+	Age           int       `json:"age"`
+	PhoneNumber   string    `json:"phone_number,omitempty"`
+	PhoneVerified bool      `json:"phone_verified"`
+}
+
+// RegisterRequest is a new account; the password must follow the password policy of the service
+type RegisterRequest struct {
+	Name     string `json:"name"`
+	Email    string `json:"email"`
+	Password string `json:"password"`
+	Age      int    `json:"age,omitempty"`
+}
+
+// Register creates an account with a wallet. It does not log in
+func (c *Client) Register(ctx context.Context, req RegisterRequest) (*User, error) {
+	var user User
+	if _, err := c.do(ctx, http.MethodPost, "/auth/register", nil, req, &user); err != nil {
+		return nil, err
+	}
+	return &user, nil
+}
+
+// Login authenticates the client as the user, whose token later requests are sent with
+func (c *Client) Login(ctx context.Context, email, password string) (*User, error) {
+	return c.login(ctx, email, password, false)
+}
+
+// LoginSandbox authenticates the client as the user with a sandbox token, whose wallet operations
+// move test money
+func (c *Client) LoginSandbox(ctx context.Context, email, password string) (*User, error) {
+	return c.login(ctx, email, password, true)
+}
+
+func (c *Client) login(ctx context.Context, email, password string, sandbox bool) (*User, error) {
+	req := struct {
+		Email    string `json:"email"`
+		Password string `json:"password"`
+		Sandbox  bool   `json:"sandbox,omitempty"`
+	}{email, password, sandbox}
+
+	var resp struct {
+		User  User   `json:"user"`
+		Token string `json:"token"`
+	}
+	if _, err := c.do(ctx, http.MethodPost, "/auth/login", nil, req, &resp); err != nil {
+		return nil, err
+	}
+	c.SetToken(resp.Token)
+	return &resp.User, nil
+}
+
+// Refresh replaces the token of the client with one expiring later; the replaced token is revoked
+func (c *Client) Refresh(ctx context.Context) error {
+	var resp struct {
+		Token string `json:"token"`
+	}
+	if _, err := c.do(ctx, http.MethodPost, "/auth/refresh", nil, nil, &resp); err != nil {
+		return err
+	}
+	c.SetToken(resp.Token)
+	return nil
+}
+
+// Logout revokes the token of the client and forgets it
+func (c *Client) Logout(ctx context.Context) error {
+	if _, err := c.do(ctx, http.MethodPost, "/auth/logout", nil, nil, nil); err != nil {
+		return err
+	}
+	c.SetToken("")
+	return nil
+}
@@ -0,0 +1,259 @@
+package client
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/limistah/wallet-service/internal/utils"
+)
+
+// CodeDuplicateReference is the error code of a money movement whose reference was already used
+const CodeDuplicateReference = "DUPLICATE_REFERENCE"
+
+// RetryPolicy says how often and how patiently failed requests are retried
+type RetryPolicy struct {
+	// MaxRetries is how many times a request is retried after the first attempt; 0 turns retries off
+	MaxRetries int
+	// InitialBackoff is the wait before the first retry, doubled for every later one
+	InitialBackoff time.Duration
+	// MaxBackoff caps the wait between retries, and how long a Retry-After header is honoured for
+	MaxBackoff time.Duration
+}
+
+// DefaultRetryPolicy retries 3 times, from 200ms up to every 5 seconds
+func DefaultRetryPolicy() RetryPolicy {
+	return RetryPolicy{
+		MaxRetries:     3,
+		InitialBackoff: 200 * time.Millisecond,
+		MaxBackoff:     5 * time.Second,
+	}
+}
+
+// Client calls the wallet service REST API
+type Client struct {
+	baseURL    string
+	httpClient *http.Client
+	retry      RetryPolicy
+	userAgent  string
+	tenant     string
+
+	mu    sync.RWMutex
+	token string
+}
+
+// Option configures a Client
+type Option func(*Client)
+
+// WithHTTPClient sets the HTTP client requests are sent with
+func WithHTTPClient(httpClient *http.Client) Option {
+	return func(c *Client) {
+		c.httpClient = httpClient
+	}
+}
+
+// WithToken authenticates requests with a token issued earlier, instead of logging in
+func WithToken(token string) Option {
+	return func(c *Client) {
+		c.token = token
+	}
+}
+
+// WithRetryPolicy sets how failed requests are retried
+func WithRetryPolicy(policy RetryPolicy) Option {
+	return func(c *Client) {
+		c.retry = policy
+	}
+}
+
+// WithTenant sends the slug of a tenant in the X-Tenant-ID header, for deployments shared by several
+func WithTenant(slug string) Option {
+	return func(c *Client) {
+		c.tenant = slug
+	}
+}
+
+// WithUserAgent sets the User-Agent requests are sent with, so the service can tell callers apart
+func WithUserAgent(userAgent string) Option {
+	return func(c *Client) {
+		c.userAgent = userAgent
+	}
+}
+
+// New creates a client for the service at baseURL, such as https://wallet.internal
+func New(baseURL string, opts ...Option) *Client {
+	c := &Client{
+		baseURL:    strings.TrimRight(baseURL, "/") + "/api/v1",
+		httpClient: &http.Client{Timeout: 30 * time.Second},
+		retry:      DefaultRetryPolicy(),
+		userAgent:  "wallet-service-go-client",
+	}
+	for _, opt := range opts {
+		opt(c)
+	}
+	return c
+}
+
+// Token returns the token requests are authenticated with, empty when logged out
+func (c *Client) Token() string {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.token
+}
+
+// SetToken replaces the token requests are authenticated with
+func (c *Client) SetToken(token string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.token = token
+}
+
+// APIError is a request the service refused or failed
+type APIError struct {
+	// StatusCode is the HTTP status of the response
+	StatusCode int
+	// Code is the machine-readable code of the error, empty for unexpected failures
+	Code string
+	// Message is the localized message of the response
+	Message string
+	// Detail is the error text; branch on Code rather than on it
+	Detail string
+}
+
+func (e *APIError) Error() string {
+	detail := e.Detail
+	if detail == "" {
+		detail = e.Message
+	}
+	if e.Code != "" {
+		return fmt.Sprintf("wallet service returned status %d (%s): %s", e.StatusCode, e.Code, detail)
+	}
+	return fmt.Sprintf("wallet service returned status %d: %s", e.StatusCode, detail)
+}
+
+// HasCode reports whether err is an *APIError with the given code
+func HasCode(err error, code string) bool {
+	var apiErr *APIError
+	return errors.As(err, &apiErr) && apiErr.Code == code
+}
+
+// envelope is the body of every response of the API
+type envelope struct {
+	Success bool            `json:"success"`
+	Message string          `json:"message"`
+	Data    json.RawMessage `json:"data"`
+	Error   string          `json:"error"`
+	Code    string          `json:"code"`
+}
+
+// do sends a request, retrying it as the policy allows, and decodes the data of a successful response
+// into out, when given. It returns the status of the response
+func (c *Client) do(ctx context.Context, method, path string, query url.Values, body, out any) (int, error) {
+	var payload []byte
+	if body != nil {
+		var err error
+		if payload, err = json.Marshal(body); err != nil {
+			return 0, fmt.Errorf("failed to encode request: %w", err)
+		}
+	}
+	endpoint := c.baseURL + path
+	if len(query) > 0 {
+		endpoint += "?" + query.Encode()
+	}
+
+	for attempt := 1; ; attempt++ {
+		status, retryAfter, transient, err := c.send(ctx, method, endpoint, payload, out)
+		if err == nil || attempt > c.retry.MaxRetries || !transient {
+			return status, err
+		}
+
+		wait := utils.Backoff(c.retry.InitialBackoff, c.retry.MaxBackoff, attempt)
+		if retryAfter > wait {
+			wait = min(retryAfter, c.retry.MaxBackoff)
+		}
+		timer := time.NewTimer(wait)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return status, err
+		case <-timer.C:
+		}
+	}
+}
+
+// send makes one attempt at a request, returning the status of the response, how long its Retry-After
+// header asks to wait and whether a failure may not happen again: the service could not be reached,
+// was overloaded or sat behind a gateway that gave up on it
+func (c *Client) send(ctx context.Context, method, endpoint string, payload []byte, out any) (int, time.Duration, bool, error) {
+	var body io.Reader
+	if payload != nil {
+		body = bytes.NewReader(payload)
+	}
+	req, err := http.NewRequestWithContext(ctx, method, endpoint, body)
+	if err != nil {
+		return 0, 0, false, fmt.Errorf("failed to build request: %w", err)
+	}
+	req.Header.Set("Accept", "application/json")
+	req.Header.Set("User-Agent", c.userAgent)
+	if payload != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	if token := c.Token(); token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+	if c.tenant != "" {
+		req.Header.Set("X-Tenant-ID", c.tenant)
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return 0, 0, ctx.Err() == nil, fmt.Errorf("wallet service request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	raw, err := io.ReadAll(io.LimitReader(resp.Body, 10<<20))
+	if err != nil {
+		return resp.StatusCode, 0, ctx.Err() == nil, fmt.Errorf("failed to read response: %w", err)
+	}
+
+	var decoded envelope
+	decodeErr := json.Unmarshal(raw, &decoded)
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		apiErr := &APIError{StatusCode: resp.StatusCode, Code: decoded.Code, Message: decoded.Message, Detail: decoded.Error}
+		if decodeErr != nil {
+			apiErr.Detail = strings.TrimSpace(string(raw))
+		}
+		switch resp.StatusCode {
+		case http.StatusTooManyRequests, http.StatusBadGateway, http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+			return resp.StatusCode, retryAfter(resp.Header), true, apiErr
+		}
+		return resp.StatusCode, 0, false, apiErr
+	}
+	if decodeErr != nil {
+		return resp.StatusCode, 0, false, fmt.Errorf("failed to decode response: %w", decodeErr)
+	}
+	if out != nil && len(decoded.Data) > 0 {
+		if err := json.Unmarshal(decoded.Data, out); err != nil {
+			return resp.StatusCode, 0, false, fmt.Errorf("failed to decode response: %w", err)
+		}
+	}
+	return resp.StatusCode, 0, false, nil
+}
+
+// retryAfter returns the wait a Retry-After header in seconds asks for
+func retryAfter(header http.Header) time.Duration {
+	seconds, err := strconv.Atoi(header.Get("Retry-After"))
+	if err != nil || seconds < 0 {
+		return 0
+	}
+	return time.Duration(seconds) * time.Second
+}
@@ -0,0 +1,233 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+// fakeAPI answers the routes the tests register with the JSON envelope of the service
+type fakeAPI struct {
+	mu     sync.Mutex
+	routes map[string]func(w http.ResponseWriter, r *http.Request) (int, any)
+	calls  map[string]int
+}
+
+func newFakeAPI(t *testing.T) (*fakeAPI, *Client) {
+	api := &fakeAPI{routes: map[string]func(http.ResponseWriter, *http.Request) (int, any){}, calls: map[string]int{}}
+	server := httptest.NewServer(api)
+	t.Cleanup(server.Close)
+	retry := RetryPolicy{MaxRetries: 2, InitialBackoff: time.Millisecond, MaxBackoff: 5 * time.Millisecond}
+	return api, New(server.URL, WithRetryPolicy(retry))
+}
+
+func (a *fakeAPI) handle(route string, handler func(w http.ResponseWriter, r *http.Request) (int, any)) {
+	a.routes[route] = handler
+}
+
+func (a *fakeAPI) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	route := r.Method + " " + r.URL.Path
+	a.mu.Lock()
+	a.calls[route]++
+	handler, ok := a.routes[route]
+	a.mu.Unlock()
+	if !ok {
+		http.NotFound(w, r)
+		return
+	}
+
+	status, data := handler(w, r)
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	if status >= 400 {
+		json.NewEncoder(w).Encode(data)
+		return
+	}
+	json.NewEncoder(w).Encode(map[string]any{"success": true, "message": "ok", "data": data})
+}
+
+func (a *fakeAPI) count(route string) int {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	return a.calls[route]
+}
+
+func errorBody(code, message string) map[string]any {
+	return map[string]any{"success": false, "message": "Operation failed", "error": message, "code": code}
+}
+
+func TestClient_LoginAuthenticatesLaterRequests(t *testing.T) {
+	api, c := newFakeAPI(t)
+	api.handle("POST /api/v1/auth/login", func(w http.ResponseWriter, r *http.Request) (int, any) {
+		var req map[string]any
+		json.NewDecoder(r.Body).Decode(&req)
+		if req["email"] != "ada@example.com" || req["password"] != "s3cret" {
+			return http.StatusUnauthorized, errorBody("INVALID_CREDENTIALS", "invalid credentials")
+		}
+		return http.StatusOK, map[string]any{"user": map[string]any{"id": 7, "email": "ada@example.com"}, "token": "token-1"}
+	})
+	api.handle("GET /api/v1/wallets/me", func(w http.ResponseWriter, r *http.Request) (int, any) {
+		if r.Header.Get("Authorization") != "Bearer token-1" {
+			return http.StatusUnauthorized, errorBody("UNAUTHENTICATED", "authentication required")
+		}
+		return http.StatusOK, map[string]any{"id": 3, "user_id": 7, "balance": "120.50", "currency": "USD", "status": "ACTIVE"}
+	})
+	api.handle("POST /api/v1/auth/logout", func(w http.ResponseWriter, r *http.Request) (int, any) {
+		return http.StatusOK, nil
+	})
+
+	_, err := c.Login(context.Background(), "ada@example.com", "wrong")
+	var apiErr *APIError
+	if !errors.As(err, &apiErr) || apiErr.StatusCode != http.StatusUnauthorized || apiErr.Code != "INVALID_CREDENTIALS" {
+		t.Fatalf("Expected an APIError with the code of the response, got %v", err)
+	}
+
+	user, err := c.Login(context.Background(), "ada@example.com", "s3cret")
+	if err != nil || user.ID != 7 || c.Token() != "token-1" {
+		t.Fatalf("Expected the token to be kept, got %+v, %q, %v", user, c.Token(), err)
+	}
+	wallet, err := c.GetWallet(context.Background())
+	if err != nil || wallet.ID != 3 || !wallet.Balance.Equal(decimal.RequireFromString("120.50")) {
+		t.Fatalf("Expected the wallet of the user, got %+v, %v", wallet, err)
+	}
+	if err := c.Logout(context.Background()); err != nil || c.Token() != "" {
+		t.Errorf("Expected the token to be forgotten, got %q, %v", c.Token(), err)
+	}
+}
+
+func TestClient_TransferRetriesWithTheSameReference(t *testing.T) {
+	api, c := newFakeAPI(t)
+	var mu sync.Mutex
+	var references []string
+	api.handle("POST /api/v1/wallets/me/transfer", func(w http.ResponseWriter, r *http.Request) (int, any) {
+		var req TransferRequest
+		json.NewDecoder(r.Body).Decode(&req)
+		mu.Lock()
+		references = append(references, req.Reference)
+		attempt := len(references)
+		mu.Unlock()
+		if attempt == 1 {
+			w.Header().Set("Retry-After", "0")
+			return http.StatusServiceUnavailable, errorBody("", "service unavailable")
+		}
+		return http.StatusOK, []map[string]any{
+			{"id": 10, "reference": req.Reference + "-OUT", "amount": "25", "status": StatusCompleted},
+			{"id": 11, "reference": req.Reference + "-IN", "amount": "25", "status": StatusCompleted},
+		}
+	})
+
+	result, err := c.Transfer(context.Background(), TransferRequest{ToWalletID: 4, Amount: decimal.NewFromInt(25)})
+	if err != nil {
+		t.Fatalf("Transfer() error = %v", err)
+	}
+	if len(references) != 2 || references[0] == "" || references[0] != references[1] || result.Reference != references[0] {
+		t.Fatalf("Expected the retry to resend the generated reference, got %v and %q", references, result.Reference)
+	}
+	if result.Debit.ID != 10 || result.Credit.ID != 11 || result.Challenge != nil || result.Pending {
+		t.Errorf("Expected the legs of a completed transfer, got %+v", result)
+	}
+}
+
+func TestClient_DoesNotRetryRefusedRequests(t *testing.T) {
+	api, c := newFakeAPI(t)
+	api.handle("POST /api/v1/wallets/me/fund", func(w http.ResponseWriter, r *http.Request) (int, any) {
+		return http.StatusConflict, errorBody(CodeDuplicateReference, "duplicate reference")
+	})
+
+	_, err := c.Fund(context.Background(), FundRequest{Amount: decimal.NewFromInt(10), Reference: "FUND-1"})
+	if !HasCode(err, CodeDuplicateReference) {
+		t.Fatalf("Expected a duplicate reference error, got %v", err)
+	}
+	if calls := api.count("POST /api/v1/wallets/me/fund"); calls != 1 {
+		t.Errorf("Expected a refused request not to be retried, got %d calls", calls)
+	}
+}
+
+func TestClient_GivesUpAfterMaxRetries(t *testing.T) {
+	api, c := newFakeAPI(t)
+	api.handle("GET /api/v1/wallets/me/balance", func(w http.ResponseWriter, r *http.Request) (int, any) {
+		return http.StatusBadGateway, errorBody("", "bad gateway")
+	})
+
+	_, err := c.GetBalance(context.Background())
+	var apiErr *APIError
+	if !errors.As(err, &apiErr) || apiErr.StatusCode != http.StatusBadGateway {
+		t.Fatalf("Expected the last failure, got %v", err)
+	}
+	if calls := api.count("GET /api/v1/wallets/me/balance"); calls != 3 {
+		t.Errorf("Expected the first attempt and 2 retries, got %d calls", calls)
+	}
+}
+
+func TestClient_TransferChallenge(t *testing.T) {
+	api, c := newFakeAPI(t)
+	api.handle("POST /api/v1/wallets/me/transfer", func(w http.ResponseWriter, r *http.Request) (int, any) {
+		return http.StatusAccepted, map[string]any{"challenge_id": 12, "expires_at": time.Now().Add(5 * time.Minute), "channel": "sms"}
+	})
+	api.handle("POST /api/v1/wallets/me/transfer/confirm", func(w http.ResponseWriter, r *http.Request) (int, any) {
+		var req map[string]any
+		json.NewDecoder(r.Body).Decode(&req)
+		if req["challenge_id"] != float64(12) || req["code"] != "482913" {
+			return http.StatusBadRequest, errorBody("INVALID_CHALLENGE_CODE", "invalid code")
+		}
+		return http.StatusAccepted, []map[string]any{
+			{"id": 20, "status": StatusPendingReview},
+			{"id": 21, "status": StatusPendingReview},
+		}
+	})
+
+	result, err := c.Transfer(context.Background(), TransferRequest{ToWalletID: 4, Amount: decimal.NewFromInt(5000), Reference: "TRF-1"})
+	if err != nil || result.Challenge == nil || result.Challenge.ChallengeID != 12 || result.Debit != nil {
+		t.Fatalf("Expected the transfer to be held by a challenge, got %+v, %v", result, err)
+	}
+	result, err = c.ConfirmTransfer(context.Background(), result.Challenge.ChallengeID, "482913")
+	if err != nil || result.Debit.ID != 20 || !result.Pending {
+		t.Errorf("Expected the confirmed transfer to be held for review, got %+v, %v", result, err)
+	}
+}
+
+func TestClient_TransactionsIteratesEveryPage(t *testing.T) {
+	api, c := newFakeAPI(t)
+	pages := map[string]map[string]any{
+		"": {
+			"transactions": []map[string]any{{"id": 5}, {"id": 4}},
+			"pagination":   map[string]any{"page_size": 2, "next_cursor": "c1", "has_next_page": true},
+		},
+		"c1": {
+			"transactions": []map[string]any{{"id": 3}, {"id": 2}},
+			"pagination":   map[string]any{"page_size": 2, "next_cursor": "c2", "has_next_page": true},
+		},
+		"c2": {
+			"transactions": []map[string]any{{"id": 1}},
+			"pagination":   map[string]any{"page_size": 2, "has_next_page": false},
+		},
+	}
+	api.handle("GET /api/v1/wallets/me/transactions", func(w http.ResponseWriter, r *http.Request) (int, any) {
+		if r.URL.Query().Get("limit") != "2" {
+			return http.StatusBadRequest, errorBody("", "unexpected limit")
+		}
+		return http.StatusOK, pages[r.URL.Query().Get("cursor")]
+	})
+
+	var ids []uint
+	it := c.Transactions(context.Background(), 2)
+	for it.Next() {
+		ids = append(ids, it.Transaction().ID)
+	}
+	if err := it.Err(); err != nil {
+		t.Fatalf("Err() = %v", err)
+	}
+	if len(ids) != 5 || ids[0] != 5 || ids[4] != 1 {
+		t.Errorf("Expected the 5 transactions newest first, got %v", ids)
+	}
+	if calls := api.count("GET /api/v1/wallets/me/transactions"); calls != 3 {
+		t.Errorf("Expected one request per page, got %d", calls)
+	}
+}
@@ -0,0 +1,17 @@
+// Package client is a typed Go client for the wallet service REST API (/api/v1), for services that
+// would otherwise hand-roll the HTTP calls:
+//
+//	c := client.New("https://wallet.internal")
+//	if _, err := c.Login(ctx, "ops@example.com", password); err != nil {
+//		return err
+//	}
+//	result, err := c.Transfer(ctx, client.TransferRequest{ToWalletID: 42, Amount: decimal.NewFromInt(25)})
+//
+// Money movements are idempotent by reference: a request sent without one gets a generated reference,
+// kept across the retries the client makes on network errors, 429 and 502 to 504 responses, so a
+// retry can never move money twice. A retry of a request that went through before its response was
+// lost fails with the code DUPLICATE_REFERENCE; GetTransactionByReference returns its outcome.
+//
+// Failed requests return an *APIError carrying the machine-readable code of the error response.
+// A Client is safe for concurrent use
+package client
@@ -0,0 +1,339 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+
+	"github.com/limistah/wallet-service/internal/utils"
+	"github.com/shopspring/decimal"
+)
+
+// Statuses of a transaction
+const (
+	StatusPending       = "PENDING"
+	StatusPendingReview = "PENDING_REVIEW"
+	StatusCompleted     = "COMPLETED"
+	StatusFailed        = "FAILED"
+	StatusCancelled     = "CANCELLED"
+)
+
+// Wallet is the wallet of the logged in user
+type Wallet struct {
+	ID       uint            `json:"id"`
+	UserID   uint            `json:"user_id"`
+	Balance  decimal.Decimal `json:"balance"`
+	Currency string          `json:"currency"`
+	Status   string          `json:"status"`
+	Version  uint            `json:"version"`
+	Sandbox  bool            `json:"sandbox,omitempty"`
+	TierID   *uint           `json:"tier_id,omitempty"`
+}
+
+// Balance is the balance of a wallet
+type Balance struct {
+	WalletID uint            `json:"wallet_id"`
+	Balance  decimal.Decimal `json:"balance"`
+	Currency string          `json:"currency"`
+}
+
+// Transaction is an entry of a wallet's history
+type Transaction struct {
+	ID                 uint            `json:"id"`
+	CreatedAt          time.Time       `json:"created_at"`
+	Reference          string          `json:"reference"`
+	WalletID           uint            `json:"wallet_id"`
+	TransactionType    string          `json:"transaction_type"`
+	TransactionPurpose string          `json:"transaction_purpose"`
+	Amount             decimal.Decimal `json:"amount"`
+	BalanceBefore      decimal.Decimal `json:"balance_before"`
+	BalanceAfter       decimal.Decimal `json:"balance_after"`
+	Description        string          `json:"description"`
+	Category           string          `json:"category,omitempty"`
+	Status             string          `json:"status"`
+}
+
+// TransactionLeg is the other side of a transaction, without the counterparty's balances
+type TransactionLeg struct {
+	ID                 uint            `json:"id"`
+	CreatedAt          time.Time       `json:"created_at"`
+	Reference          string          `json:"reference"`
+	WalletID           uint            `json:"wallet_id"`
+	TransactionType    string          `json:"transaction_type"`
+	TransactionPurpose string          `json:"transaction_purpose"`
+	Amount             decimal.Decimal `json:"amount"`
+	Status             string          `json:"status"`
+}
+
+// TransactionLookup is a transaction of the user's wallet found by reference, with its double-entry
+// counter leg
+type TransactionLookup struct {
+	Transaction Transaction     `json:"transaction"`
+	CounterLeg  *TransactionLeg `json:"counter_leg,omitempty"`
+}
+
+// FundRequest credits the wallet. Reference is the idempotency key of the funding, generated when empty
+type FundRequest struct {
+	Amount      decimal.Decimal `json:"amount"`
+	Reference   string          `json:"reference"`
+	Description string          `json:"description,omitempty"`
+}
+
+// BankAccount is the bank account a withdrawal is paid out to
+type BankAccount struct {
+	BankCode      string `json:"bank_code"`
+	AccountNumber string `json:"account_number"`
+	AccountName   string `json:"account_name"`
+}
+
+// WithdrawRequest debits the wallet. Reference is the idempotency key of the withdrawal, generated
+// when empty. BankAccount is required when the service pays withdrawals out, and PIN when the user
+// set one and the amount is above the PIN threshold
+type WithdrawRequest struct {
+	Amount      decimal.Decimal `json:"amount"`
+	Reference   string          `json:"reference"`
+	Description string          `json:"description,omitempty"`
+	BankAccount *BankAccount    `json:"bank_account,omitempty"`
+	PIN         string          `json:"pin,omitempty"`
+}
+
+// TransferRequest moves money to another wallet. Reference is the idempotency key of the transfer,
+// generated when empty. QuoteID makes the transfer with the fee and exchange rate of a quote
+type TransferRequest struct {
+	ToWalletID  uint            `json:"to_wallet_id"`
+	Amount      decimal.Decimal `json:"amount"`
+	Reference   string          `json:"reference"`
+	Description string          `json:"description,omitempty"`
+	PIN         string          `json:"pin,omitempty"`
+	QuoteID     *uint           `json:"quote_id,omitempty"`
+}
+
+// MovementResult is the outcome of a funding or withdrawal: the user's transaction and the system's
+// double-entry counterpart. Pending is set while a payout settles or the withdrawal is held for review
+type MovementResult struct {
+	Reference         string      `json:"-"`
+	Transaction       Transaction `json:"user_transaction"`
+	SystemTransaction Transaction `json:"system_transaction"`
+	Pending           bool        `json:"-"`
+}
+
+// TransferChallenge holds a transfer above the step-up threshold until the one-time code sent to the
+// user is confirmed with ConfirmTransfer
+type TransferChallenge struct {
+	ChallengeID uint      `json:"challenge_id"`
+	ExpiresAt   time.Time `json:"expires_at"`
+	Channel     string    `json:"channel"`
+}
+
+// TransferResult is the outcome of a transfer: its debit and credit legs once made, or the challenge
+// holding it. Pending is set while the transfer is held for review
+type TransferResult struct {
+	Reference string
+	Debit     *Transaction
+	Credit    *Transaction
+	Challenge *TransferChallenge
+	Pending   bool
+}
+
+// NewReference returns a fresh reference for a money movement. Generate one up front to be able to
+// look up the outcome of a request that failed without an answer from the service
+func NewReference() string {
+	return utils.GenerateTransactionReference()
+}
+
+// GetWallet returns the wallet of the logged in user
+func (c *Client) GetWallet(ctx context.Context) (*Wallet, error) {
+	var wallet Wallet
+	if _, err := c.do(ctx, http.MethodGet, "/wallets/me", nil, nil, &wallet); err != nil {
+		return nil, err
+	}
+	return &wallet, nil
+}
+
+// GetBalance returns the balance of the logged in user's wallet
+func (c *Client) GetBalance(ctx context.Context) (*Balance, error) {
+	var balance Balance
+	if _, err := c.do(ctx, http.MethodGet, "/wallets/me/balance", nil, nil, &balance); err != nil {
+		return nil, err
+	}
+	return &balance, nil
+}
+
+// Fund credits the logged in user's wallet
+func (c *Client) Fund(ctx context.Context, req FundRequest) (*MovementResult, error) {
+	if req.Reference == "" {
+		req.Reference = NewReference()
+	}
+	result := MovementResult{Reference: req.Reference}
+	if _, err := c.do(ctx, http.MethodPost, "/wallets/me/fund", nil, req, &result); err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
+
+// Withdraw debits the logged in user's wallet
+func (c *Client) Withdraw(ctx context.Context, req WithdrawRequest) (*MovementResult, error) {
+	if req.Reference == "" {
+		req.Reference = NewReference()
+	}
+	result := MovementResult{Reference: req.Reference}
+	status, err := c.do(ctx, http.MethodPost, "/wallets/me/withdraw", nil, req, &result)
+	if err != nil {
+		return nil, err
+	}
+	result.Pending = status == http.StatusAccepted
+	return &result, nil
+}
+
+// Transfer moves money from the logged in user's wallet. A transfer above the step-up threshold is
+// not made until its challenge is confirmed
+func (c *Client) Transfer(ctx context.Context, req TransferRequest) (*TransferResult, error) {
+	if req.Reference == "" {
+		req.Reference = NewReference()
+	}
+	return c.transfer(ctx, "/wallets/me/transfer", req, req.Reference)
+}
+
+// ConfirmTransfer makes a transfer held by a challenge with the one-time code sent to the user
+func (c *Client) ConfirmTransfer(ctx context.Context, challengeID uint, code string) (*TransferResult, error) {
+	req := struct {
+		ChallengeID uint   `json:"challenge_id"`
+		Code        string `json:"code"`
+	}{challengeID, code}
+	return c.transfer(ctx, "/wallets/me/transfer/confirm", req, "")
+}
+
+// transfer sends a transfer, which is answered with its legs or, with 202 Accepted, with its legs held
+// for review or the challenge holding it
+func (c *Client) transfer(ctx context.Context, path string, req any, reference string) (*TransferResult, error) {
+	var data json.RawMessage
+	status, err := c.do(ctx, http.MethodPost, path, nil, req, &data)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &TransferResult{Reference: reference}
+	if len(data) > 0 && data[0] == '{' {
+		var challenge TransferChallenge
+		if err := json.Unmarshal(data, &challenge); err != nil {
+			return nil, fmt.Errorf("failed to decode response: %w", err)
+		}
+		result.Challenge = &challenge
+		return result, nil
+	}
+	var legs []Transaction
+	if err := json.Unmarshal(data, &legs); err != nil {
+		return nil, fmt.Errorf("failed to decode response: %w", err)
+	}
+	if len(legs) != 2 {
+		return nil, fmt.Errorf("failed to decode response: expected 2 transfer legs, got %d", len(legs))
+	}
+	result.Debit, result.Credit = &legs[0], &legs[1]
+	result.Pending = status == http.StatusAccepted
+	return result, nil
+}
+
+// GetTransactionByReference returns the outcome of an operation by the reference it was made with
+func (c *Client) GetTransactionByReference(ctx context.Context, reference string) (*TransactionLookup, error) {
+	var lookup TransactionLookup
+	path := "/wallets/me/transactions/by-reference/" + url.PathEscape(reference)
+	if _, err := c.do(ctx, http.MethodGet, path, nil, nil, &lookup); err != nil {
+		return nil, err
+	}
+	return &lookup, nil
+}
+
+// TransactionPage is a page of the logged in user's transaction history, newest first
+type TransactionPage struct {
+	Transactions []Transaction
+	// NextCursor fetches the next page; empty on the last one
+	NextCursor string
+}
+
+// ListTransactions returns the page of the history starting at cursor, or the first page when it is
+// empty. The service returns 20 transactions per page when limit is 0, and at most 100
+func (c *Client) ListTransactions(ctx context.Context, cursor string, limit int) (*TransactionPage, error) {
+	query := url.Values{}
+	if cursor != "" {
+		query.Set("cursor", cursor)
+	}
+	if limit > 0 {
+		query.Set("limit", strconv.Itoa(limit))
+	}
+
+	var resp struct {
+		Transactions []Transaction `json:"transactions"`
+		Pagination   struct {
+			NextCursor  *string `json:"next_cursor"`
+			HasNextPage bool    `json:"has_next_page"`
+		} `json:"pagination"`
+	}
+	if _, err := c.do(ctx, http.MethodGet, "/wallets/me/transactions", query, nil, &resp); err != nil {
+		return nil, err
+	}
+
+	page := &TransactionPage{Transactions: resp.Transactions}
+	if resp.Pagination.HasNextPage && resp.Pagination.NextCursor != nil {
+		page.NextCursor = *resp.Pagination.NextCursor
+	}
+	return page, nil
+}
+
+// Transactions returns an iterator over the whole transaction history, fetching pages of limit
+// transactions as it goes:
+//
+//	it := c.Transactions(ctx, 100)
+//	for it.Next() {
+//		process(it.Transaction())
+//	}
+//	if err := it.Err(); err != nil {
+//		return err
+//	}
+func (c *Client) Transactions(ctx context.Context, limit int) *TransactionIterator {
+	return &TransactionIterator{client: c, ctx: ctx, limit: limit}
+}
+
+// TransactionIterator walks the transaction history page by page; it is not safe for concurrent use
+type TransactionIterator struct {
+	client  *Client
+	ctx     context.Context
+	limit   int
+	page    []Transaction
+	current Transaction
+	cursor  string
+	started bool
+	err     error
+}
+
+// Next advances to the next transaction, fetching the next page when needed. It returns false once
+// the history is exhausted or a page failed to load
+func (it *TransactionIterator) Next() bool {
+	for len(it.page) == 0 {
+		if it.err != nil || (it.started && it.cursor == "") {
+			return false
+		}
+		page, err := it.client.ListTransactions(it.ctx, it.cursor, it.limit)
+		if err != nil {
+			it.err = err
+			return false
+		}
+		it.started = true
+		it.page, it.cursor = page.Transactions, page.NextCursor
+	}
+	it.current, it.page = it.page[0], it.page[1:]
+	return true
+}
+
+// Transaction returns the transaction Next advanced to
+func (it *TransactionIterator) Transaction() Transaction {
+	return it.current
+}
+
+// Err returns the error that stopped the iteration, if any
+func (it *TransactionIterator) Err() error {
+	return it.err
+}
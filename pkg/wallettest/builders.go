@@ -0,0 +1,202 @@
+package wallettest
+
+import (
+	"context"
+	"fmt"
+	"sync/atomic"
+	"testing"
+
+	"github.com/limistah/wallet-service/internal/models"
+	"github.com/limistah/wallet-service/internal/repositories"
+	"github.com/shopspring/decimal"
+)
+
+// sequence numbers the fixtures of a test binary so their emails and references never collide
+var sequence atomic.Uint64
+
+func next() uint64 {
+	return sequence.Add(1)
+}
+
+// UserBuilder builds a user and, with WithWallet, their wallet. The user defaults to an active
+// member of the default tenant with a unique email
+type UserBuilder struct {
+	user     models.User
+	password string
+	wallet   *models.Wallet
+}
+
+// NewUser starts building a user
+func NewUser() *UserBuilder {
+	return &UserBuilder{user: models.User{
+		Name:     "Test User",
+		Email:    fmt.Sprintf("user%d@example.com", next()),
+		TenantID: models.DefaultTenantID,
+		Role:     models.UserRoleUser,
+	}}
+}
+
+// WithID sets the ID of the user instead of letting the repository assign one
+func (b *UserBuilder) WithID(id uint) *UserBuilder {
+	b.user.ID = id
+	return b
+}
+
+func (b *UserBuilder) WithName(name string) *UserBuilder {
+	b.user.Name = name
+	return b
+}
+
+func (b *UserBuilder) WithEmail(email string) *UserBuilder {
+	b.user.Email = email
+	return b
+}
+
+// WithPassword sets the password the user logs in with; it is hashed when the user is built
+func (b *UserBuilder) WithPassword(password string) *UserBuilder {
+	b.password = password
+	return b
+}
+
+func (b *UserBuilder) WithRole(role models.UserRole) *UserBuilder {
+	b.user.Role = role
+	return b
+}
+
+func (b *UserBuilder) WithTenant(tenantID uint) *UserBuilder {
+	b.user.TenantID = tenantID
+	if b.wallet != nil {
+		b.wallet.TenantID = tenantID
+	}
+	return b
+}
+
+// WithWallet gives the user an active wallet holding balance in currency
+func (b *UserBuilder) WithWallet(balance float64, currency string) *UserBuilder {
+	b.wallet = &models.Wallet{
+		TenantID: b.user.TenantID,
+		Balance:  decimal.NewFromFloat(balance),
+		Currency: currency,
+		Status:   models.WalletStatusActive,
+	}
+	return b
+}
+
+// WithWalletStatus sets the status of the wallet added by WithWallet
+func (b *UserBuilder) WithWalletStatus(status models.WalletStatus) *UserBuilder {
+	if b.wallet == nil {
+		b.WithWallet(0, "USD")
+	}
+	b.wallet.Status = status
+	return b
+}
+
+// Build returns the user and their wallet, or a nil wallet without WithWallet, without storing them
+func (b *UserBuilder) Build() (*models.User, *models.Wallet, error) {
+	user := b.user
+	if b.password != "" {
+		if err := user.HashPassword(b.password); err != nil {
+			return nil, nil, fmt.Errorf("failed to hash password: %w", err)
+		}
+	}
+	if b.wallet == nil {
+		return &user, nil, nil
+	}
+	wallet := *b.wallet
+	wallet.UserID = user.ID
+	return &user, &wallet, nil
+}
+
+// Create stores the user and their wallet in repos, failing the test on an error
+func (b *UserBuilder) Create(t testing.TB, repos *repositories.Repositories) (*models.User, *models.Wallet) {
+	t.Helper()
+	user, wallet, err := b.Build()
+	if err != nil {
+		t.Fatalf("Failed to build user: %v", err)
+	}
+	if err := repos.User.Create(context.Background(), user); err != nil {
+		t.Fatalf("Failed to create user: %v", err)
+	}
+	if wallet == nil {
+		return user, nil
+	}
+	wallet.UserID = user.ID
+	if err := repos.Wallet.Create(context.Background(), wallet); err != nil {
+		t.Fatalf("Failed to create wallet: %v", err)
+	}
+	return user, wallet
+}
+
+// TransactionBuilder builds a transaction of a wallet. The transaction defaults to a completed top-up
+// with a unique reference
+type TransactionBuilder struct {
+	transaction models.Transaction
+}
+
+// NewTransaction starts building a transaction of the wallet
+func NewTransaction(walletID uint) *TransactionBuilder {
+	return &TransactionBuilder{transaction: models.Transaction{
+		Reference:          fmt.Sprintf("TXN-TEST-%d", next()),
+		WalletID:           walletID,
+		TransactionPurpose: models.TransactionPurposeWalletTopUp,
+		TransactionType:    models.TransactionTypeCredit,
+		Status:             models.TransactionStatusCompleted,
+	}}
+}
+
+// Credit makes the transaction add amount to the wallet
+func (b *TransactionBuilder) Credit(amount float64) *TransactionBuilder {
+	b.transaction.TransactionType = models.TransactionTypeCredit
+	b.transaction.Amount = decimal.NewFromFloat(amount)
+	return b
+}
+
+// Debit makes the transaction take amount from the wallet
+func (b *TransactionBuilder) Debit(amount float64) *TransactionBuilder {
+	b.transaction.TransactionType = models.TransactionTypeDebit
+	b.transaction.Amount = decimal.NewFromFloat(amount)
+	return b
+}
+
+func (b *TransactionBuilder) WithPurpose(purpose models.TransactionPurpose) *TransactionBuilder {
+	b.transaction.TransactionPurpose = purpose
+	return b
+}
+
+func (b *TransactionBuilder) WithStatus(status models.TransactionStatus) *TransactionBuilder {
+	b.transaction.Status = status
+	return b
+}
+
+func (b *TransactionBuilder) WithReference(reference string) *TransactionBuilder {
+	b.transaction.Reference = reference
+	return b
+}
+
+func (b *TransactionBuilder) WithDescription(description string) *TransactionBuilder {
+	b.transaction.Description = description
+	return b
+}
+
+// WithBalances sets the wallet balance before and after the transaction
+func (b *TransactionBuilder) WithBalances(before, after float64) *TransactionBuilder {
+	b.transaction.BalanceBefore = decimal.NewFromFloat(before)
+	b.transaction.BalanceAfter = decimal.NewFromFloat(after)
+	return b
+}
+
+// Build returns the transaction without storing it
+func (b *TransactionBuilder) Build() *models.Transaction {
+	transaction := b.transaction
+	return &transaction
+}
+
+// Create stores the transaction in repos, failing the test on an error
+func (b *TransactionBuilder) Create(t testing.TB, repos *repositories.Repositories) *models.Transaction {
+	t.Helper()
+	transaction := b.Build()
+	if err := repos.Transaction.Create(context.Background(), transaction); err != nil {
+		t.Fatalf("Failed to create transaction: %v", err)
+	}
+	return transaction
+}
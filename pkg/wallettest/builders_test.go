@@ -0,0 +1,43 @@
+package wallettest
+
+import (
+	"context"
+	"testing"
+
+	"github.com/limistah/wallet-service/internal/models"
+)
+
+func TestUserBuilder_Create(t *testing.T) {
+	repos := NewRepositories()
+	user, wallet := NewUser().WithName("Ada").WithPassword("s3cret-Passw0rd").WithWallet(100, "USD").Create(t, repos)
+
+	if user.ID == 0 || user.Name != "Ada" || user.CheckPassword("s3cret-Passw0rd") != nil {
+		t.Errorf("Expected a stored user with a hashed password, got %+v", user)
+	}
+	stored, err := repos.Wallet.GetByUserID(context.Background(), user.ID)
+	if err != nil || stored.ID != wallet.ID || stored.Currency != "USD" || stored.Status != models.WalletStatusActive {
+		t.Fatalf("Expected the wallet to be stored for the user, got %+v, %v", stored, err)
+	}
+	if !stored.Balance.Equal(wallet.Balance) || wallet.Balance.String() != "100" {
+		t.Errorf("Expected a balance of 100, got %s", stored.Balance)
+	}
+
+	other, _ := NewUser().Create(t, repos)
+	if other.Email == user.Email {
+		t.Errorf("Expected users to get unique emails, both got %s", user.Email)
+	}
+}
+
+func TestTransactionBuilder_Create(t *testing.T) {
+	repos := NewRepositories()
+	_, wallet := NewUser().WithWallet(50, "USD").Create(t, repos)
+
+	transaction := NewTransaction(wallet.ID).Debit(20).WithStatus(models.TransactionStatusPending).Create(t, repos)
+	stored, err := repos.Transaction.GetByReference(context.Background(), transaction.Reference)
+	if err != nil {
+		t.Fatalf("GetByReference() error = %v", err)
+	}
+	if stored.TransactionType != models.TransactionTypeDebit || stored.Status != models.TransactionStatusPending || stored.Amount.String() != "20" {
+		t.Errorf("Expected a pending debit of 20, got %+v", stored)
+	}
+}
@@ -0,0 +1,10 @@
+// Package wallettest provides in-memory repositories and fluent fixture builders for testing code
+// built on the wallet use cases without a database:
+//
+//	repos := wallettest.NewRepositories()
+//	user, wallet := wallettest.NewUser().WithWallet(100, "USD").Create(t, repos)
+//	uc := usecases.NewWalletUseCase(repos, reconciliation)
+//
+// The repositories are those of the memory driver (internal/repositories/memory) and are safe for
+// concurrent use
+package wallettest
@@ -0,0 +1,12 @@
+package wallettest
+
+import (
+	"github.com/limistah/wallet-service/internal/repositories"
+	"github.com/limistah/wallet-service/internal/repositories/memory"
+)
+
+// NewRepositories returns empty in-memory repositories, with a unit of work that runs changes
+// directly against them
+func NewRepositories() *repositories.Repositories {
+	return memory.NewRepositories()
+}